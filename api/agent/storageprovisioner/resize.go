@@ -0,0 +1,100 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/rpc/params"
+)
+
+// WatchVolumeResizes watches for changes to volumes scoped to the
+// entity with the specified tag, so that pending resizes may be
+// carried out. An error satisfying errors.IsNotSupported is returned
+// if the controller does not support resizing volumes.
+func (st *State) WatchVolumeResizes(scope names.Tag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("resizing volumes on this version of Juju")
+	}
+	return st.watchStorageEntities("WatchVolumeResizes", scope)
+}
+
+// WatchFilesystemResizes watches for changes to filesystems scoped to
+// the machine with the specified tag, so that filesystems whose backing
+// volumes have been resized may be grown. An error satisfying
+// errors.IsNotSupported is returned if the controller does not support
+// resizing volumes.
+func (st *State) WatchFilesystemResizes(scope names.MachineTag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("resizing filesystems on this version of Juju")
+	}
+	return st.watchStorageEntities("WatchFilesystemResizes", scope)
+}
+
+// VolumeResizeParams returns the parameters for resizing the volumes
+// with the specified tags. A volume with no pending resize will have
+// a size of zero.
+func (st *State) VolumeResizeParams(tags []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	var results params.VolumeResizeParamsResults
+	args := params.Entities{Entities: make([]params.Entity, len(tags))}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	err := st.facade.FacadeCall("VolumeResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// FinishVolumeResizes records that volumes have been resized by the
+// storage provider.
+func (st *State) FinishVolumeResizes(volumes []params.Volume) ([]params.ErrorResult, error) {
+	var results params.ErrorResults
+	args := params.Volumes{Volumes: volumes}
+	err := st.facade.FacadeCall("FinishVolumeResizes", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(volumes) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(volumes), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// FilesystemResizeParams returns the parameters for growing the
+// filesystems attached to machines with the specified IDs. A filesystem
+// with no pending resize will have a size of zero.
+func (st *State) FilesystemResizeParams(ids []params.MachineStorageId) ([]params.FilesystemResizeParamsResult, error) {
+	var results params.FilesystemResizeParamsResults
+	args := params.MachineStorageIds{Ids: ids}
+	err := st.facade.FacadeCall("FilesystemResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// FinishFilesystemResizes records that filesystems have been grown to
+// fill their resized volumes.
+func (st *State) FinishFilesystemResizes(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
+	var results params.ErrorResults
+	args := params.Filesystems{Filesystems: filesystems}
+	err := st.facade.FacadeCall("FinishFilesystemResizes", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(filesystems) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(filesystems), len(results.Results))
+	}
+	return results.Results, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/agent/storageprovisioner"
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&resizeSuite{})
+
+type resizeSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *resizeSuite) TestWatchVolumeResizes(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "StorageProvisioner")
+			c.Check(version, gc.Equals, 6)
+			c.Check(request, gc.Equals, "WatchVolumeResizes")
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-123"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+			*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+				Results: []params.StringsWatchResult{{
+					Error: &params.Error{Message: "FAIL"},
+				}},
+			}
+			callCount++
+			return nil
+		},
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeResizes(names.NewMachineTag("123"))
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *resizeSuite) TestWatchResizesNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected API call %q", request)
+			return nil
+		},
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeResizes(names.NewMachineTag("123"))
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = st.WatchFilesystemResizes(names.NewMachineTag("123"))
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *resizeSuite) TestVolumeResizeParams(c *gc.C) {
+	expected := params.VolumeResizeParams{
+		VolumeTag: "volume-0-1",
+		VolumeId:  "vol-123",
+		Provider:  "lvm",
+		Size:      2048,
+	}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "VolumeResizeParams")
+		c.Check(arg, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "volume-0-1"}}})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeResizeParamsResults{})
+		*(result.(*params.VolumeResizeParamsResults)) = params.VolumeResizeParamsResults{
+			Results: []params.VolumeResizeParamsResult{{Result: expected}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.VolumeResizeParams([]names.VolumeTag{names.NewVolumeTag("0/1")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.VolumeResizeParamsResult{{Result: expected}})
+}
+
+func (s *resizeSuite) TestFinishVolumeResizes(c *gc.C) {
+	volumes := []params.Volume{{
+		VolumeTag: "volume-0-1",
+		Info:      params.VolumeInfo{VolumeId: "vol-123", Size: 2048},
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "FinishVolumeResizes")
+		c.Check(arg, jc.DeepEquals, params.Volumes{Volumes: volumes})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "FAIL"}}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.FinishVolumeResizes(volumes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, "FAIL")
+}
+
+func (s *resizeSuite) TestFilesystemResizeParams(c *gc.C) {
+	ids := []params.MachineStorageId{{
+		MachineTag:    "machine-0",
+		AttachmentTag: "filesystem-0-1",
+	}}
+	expected := params.FilesystemResizeParams{
+		FilesystemTag: "filesystem-0-1",
+		VolumeTag:     "volume-0-1",
+		MountPoint:    "/srv",
+		Size:          2048,
+	}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "FilesystemResizeParams")
+		c.Check(arg, jc.DeepEquals, params.MachineStorageIds{Ids: ids})
+		c.Assert(result, gc.FitsTypeOf, &params.FilesystemResizeParamsResults{})
+		*(result.(*params.FilesystemResizeParamsResults)) = params.FilesystemResizeParamsResults{
+			Results: []params.FilesystemResizeParamsResult{{Result: expected}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.FilesystemResizeParams(ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.FilesystemResizeParamsResult{{Result: expected}})
+}
+
+func (s *resizeSuite) TestFinishFilesystemResizes(c *gc.C) {
+	filesystems := []params.Filesystem{{
+		FilesystemTag: "filesystem-0-1",
+		Info:          params.FilesystemInfo{Size: 2048},
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "FinishFilesystemResizes")
+		c.Check(arg, jc.DeepEquals, params.Filesystems{Filesystems: filesystems})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.FinishFilesystemResizes(filesystems)
+	c.Assert(err, gc.ErrorMatches, `expected 1 result\(s\), got 2`)
+}
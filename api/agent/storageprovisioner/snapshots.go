@@ -0,0 +1,77 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/rpc/params"
+)
+
+// WatchVolumeSnapshots watches for changes to snapshots of volumes
+// scoped to the entity with the specified tag. An error satisfying
+// errors.IsNotSupported is returned if the controller does not
+// support volume snapshots.
+func (st *State) WatchVolumeSnapshots(scope names.Tag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("volume snapshots on this version of Juju")
+	}
+	return st.watchStorageEntities("WatchVolumeSnapshots", scope)
+}
+
+// VolumeSnapshotParams returns the parameters for taking or destroying
+// the volume snapshots with the specified IDs.
+func (st *State) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	var results params.VolumeSnapshotParamsResults
+	args := params.VolumeSnapshotIds{Ids: ids}
+	err := st.facade.FacadeCall("VolumeSnapshotParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// SetVolumeSnapshotInfo records the details of newly taken volume snapshots.
+func (st *State) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+	var results params.ErrorResults
+	args := params.VolumeSnapshots{Snapshots: snapshots}
+	err := st.facade.FacadeCall("SetVolumeSnapshotInfo", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(snapshots) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(snapshots), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// SetVolumeSnapshotStatus sets the status of volume snapshots.
+func (st *State) SetVolumeSnapshotStatus(args []params.VolumeSnapshotStatusArg) error {
+	var result params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeSnapshotStatus", params.SetVolumeSnapshotStatus{Args: args}, &result)
+	if err != nil {
+		return err
+	}
+	return result.Combine()
+}
+
+// RemoveVolumeSnapshots removes the volume snapshots with the specified
+// IDs from state.
+func (st *State) RemoveVolumeSnapshots(ids []string) ([]params.ErrorResult, error) {
+	var results params.ErrorResults
+	args := params.VolumeSnapshotIds{Ids: ids}
+	err := st.facade.FacadeCall("RemoveVolumeSnapshots", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/agent/storageprovisioner"
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&snapshotsSuite{})
+
+type snapshotsSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *snapshotsSuite) TestWatchVolumeSnapshots(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "StorageProvisioner")
+			c.Check(version, gc.Equals, 5)
+			c.Check(request, gc.Equals, "WatchVolumeSnapshots")
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-123"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+			*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+				Results: []params.StringsWatchResult{{
+					Error: &params.Error{Message: "FAIL"},
+				}},
+			}
+			callCount++
+			return nil
+		},
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeSnapshots(names.NewMachineTag("123"))
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *snapshotsSuite) TestWatchVolumeSnapshotsNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		BestVersion: 4,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected API call %q", request)
+			return nil
+		},
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeSnapshots(names.NewMachineTag("123"))
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *snapshotsSuite) TestVolumeSnapshotParams(c *gc.C) {
+	expected := params.VolumeSnapshotParams{
+		Id:        "0/1@2",
+		Life:      life.Alive,
+		VolumeTag: "volume-0-1",
+		VolumeId:  "vol-123",
+		Provider:  "lvm",
+	}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "VolumeSnapshotParams")
+		c.Check(arg, jc.DeepEquals, params.VolumeSnapshotIds{Ids: []string{"0/1@2"}})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeSnapshotParamsResults{})
+		*(result.(*params.VolumeSnapshotParamsResults)) = params.VolumeSnapshotParamsResults{
+			Results: []params.VolumeSnapshotParamsResult{{Result: expected}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.VolumeSnapshotParams([]string{"0/1@2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.VolumeSnapshotParamsResult{{Result: expected}})
+}
+
+func (s *snapshotsSuite) TestSetVolumeSnapshotInfo(c *gc.C) {
+	snapshots := []params.VolumeSnapshot{{
+		Id:   "0/1@2",
+		Info: params.VolumeSnapshotInfo{SnapshotId: "snap-123", Size: 1024},
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "SetVolumeSnapshotInfo")
+		c.Check(arg, jc.DeepEquals, params.VolumeSnapshots{Snapshots: snapshots})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "FAIL"}}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.SetVolumeSnapshotInfo(snapshots)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, "FAIL")
+}
+
+func (s *snapshotsSuite) TestSetVolumeSnapshotStatus(c *gc.C) {
+	args := []params.VolumeSnapshotStatusArg{{
+		Id:     "0/1@2",
+		Status: status.Error.String(),
+		Info:   "out of quota",
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "SetVolumeSnapshotStatus")
+		c.Check(arg, jc.DeepEquals, params.SetVolumeSnapshotStatus{Args: args})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.SetVolumeSnapshotStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *snapshotsSuite) TestRemoveVolumeSnapshots(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "RemoveVolumeSnapshots")
+		c.Check(arg, jc.DeepEquals, params.VolumeSnapshotIds{Ids: []string{"0/1@2"}})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {}},
+		}
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.RemoveVolumeSnapshots([]string{"0/1@2"})
+	c.Assert(err, gc.ErrorMatches, `expected 1 result\(s\), got 2`)
+}
@@ -176,3 +176,31 @@ func (sa *StorageAccessor) RemoveStorageAttachment(storageTag names.StorageTag,
 	}
 	return nil
 }
+
+// ClearStorageAttachmentResized clears the resized flag of the storage
+// attachment with the specified unit and storage tags, once the unit
+// has run its storage-resized hook.
+func (sa *StorageAccessor) ClearStorageAttachmentResized(storageTag names.StorageTag, unitTag names.UnitTag) error {
+	if sa.facade.BestAPIVersion() < 20 {
+		return errors.NotSupportedf("resized storage on this version of Juju")
+	}
+	var results params.ErrorResults
+	args := params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: storageTag.String(),
+			UnitTag:    unitTag.String(),
+		}},
+	}
+	err := sa.facade.FacadeCall("ClearStorageAttachmentsResized", args, &results)
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
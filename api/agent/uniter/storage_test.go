@@ -256,3 +256,41 @@ func (s *storageSuite) TestRemoveStorageAttachment(c *gc.C) {
 	err := st.RemoveStorageAttachment(names.NewStorageTag("data/0"), names.NewUnitTag("mysql/0"))
 	c.Check(err, gc.ErrorMatches, "yoink")
 }
+
+func (s *storageSuite) TestClearStorageAttachmentResized(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Uniter")
+		c.Check(version, gc.Equals, 20)
+		c.Check(request, gc.Equals, "ClearStorageAttachmentsResized")
+		c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{
+			Ids: []params.StorageAttachmentId{{
+				StorageTag: "storage-data-0",
+				UnitTag:    "unit-mysql-0",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "yoink"},
+			}},
+		}
+		return nil
+	})
+
+	caller := testing.BestVersionCaller{apiCaller, 20}
+	st := uniter.NewState(caller, names.NewUnitTag("mysql/0"))
+	err := st.ClearStorageAttachmentResized(names.NewStorageTag("data/0"), names.NewUnitTag("mysql/0"))
+	c.Check(err, gc.ErrorMatches, "yoink")
+}
+
+func (s *storageSuite) TestClearStorageAttachmentResizedNotSupported(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+
+	caller := testing.BestVersionCaller{apiCaller, 19}
+	st := uniter.NewState(caller, names.NewUnitTag("mysql/0"))
+	err := st.ClearStorageAttachmentResized(names.NewStorageTag("data/0"), names.NewUnitTag("mysql/0"))
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/juju/juju/core/facades"
 	coremacaroon "github.com/juju/juju/core/macaroon"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/trace"
 	jujuproxy "github.com/juju/juju/proxy"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
@@ -60,7 +61,7 @@ const modelRoot = "/model/"
 var logger = loggo.GetLogger("juju.api")
 
 type rpcConnection interface {
-	Call(req rpc.Request, params, response interface{}) error
+	CallContext(ctx context.Context, req rpc.Request, params, response interface{}) error
 	Dead() <-chan struct{}
 	Close() error
 }
@@ -133,10 +134,11 @@ type state struct {
 	// access it safely.
 	loggedIn int32
 
-	// tag, password, macaroons and nonce hold the cached login
-	// credentials. These are only valid if loggedIn is 1.
+	// tag, password, idToken, macaroons and nonce hold the cached
+	// login credentials. These are only valid if loggedIn is 1.
 	tag       string
 	password  string
+	idToken   string
 	macaroons []macaroon.Slice
 	nonce     string
 
@@ -254,7 +256,7 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 	}
 
 	st := &state{
-		ctx:                 context.Background(),
+		ctx:                 tracingContext(opts.TraceContext),
 		client:              client,
 		conn:                dialResult.conn,
 		clock:               opts.Clock,
@@ -270,6 +272,7 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 		// those. If login fails, we discard the connection.
 		tag:          tagToString(info.Tag),
 		password:     info.Password,
+		idToken:      info.IDToken,
 		macaroons:    info.Macaroons,
 		nonce:        info.Nonce,
 		tlsConfig:    dialResult.tlsConfig,
@@ -359,6 +362,20 @@ func (t *hostSwitchingTransport) RoundTrip(req *http.Request) (*http.Response, e
 	return t.fallback.RoundTrip(req)
 }
 
+// tracingContext returns a context holding only the tracer and span of
+// the given context, so that API calls made over the connection are
+// recorded in the caller's trace without being cancelled with the
+// caller's context.
+func tracingContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return trace.WithSpan(
+		trace.WithTracer(context.Background(), trace.TracerFromContext(ctx)),
+		trace.SpanFromContext(ctx),
+	)
+}
+
 // Context returns the context associated with this state.
 func (st *state) Context() context.Context {
 	return st.ctx
@@ -449,6 +466,9 @@ func (st *state) connectStream(path string, attrs url.Values, extraHeaders http.
 		requestHeader = jujuhttp.BasicAuthHeader(st.tag, st.password)
 	} else {
 		requestHeader = make(http.Header)
+		if st.idToken != "" {
+			requestHeader.Set("Authorization", "Bearer "+st.idToken)
+		}
 	}
 	requestHeader.Set(params.JujuClientVersion, jujuversion.Current.String())
 	requestHeader.Set("Origin", "http://localhost/")
@@ -639,6 +659,9 @@ func dialAPI(ctx context.Context, info *Info, opts0 DialOpts) (*dialResult, erro
 	addrs := info.Addrs[:]
 
 	if info.Proxier != nil {
+		if p, ok := info.Proxier.(jujuproxy.ForwardingProxier); ok {
+			p.SetRemoteAddresses(addrs)
+		}
 		if err := info.Proxier.Start(); err != nil {
 			return nil, errors.Annotate(err, "starting proxy for api connection")
 		}
@@ -1248,7 +1271,7 @@ func isX509Error(err error) bool {
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
 func (s *state) APICall(facade string, vers int, id, method string, args, response interface{}) error {
-	err := s.client.Call(rpc.Request{
+	err := s.client.CallContext(s.ctx, rpc.Request{
 		Type:    facade,
 		Version: vers,
 		Id:      id,
@@ -1294,33 +1294,39 @@ func (s *apiclientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 	s.PatchValue(&api.WebsocketDial, catcher.RecordLocation)
 
 	params := common.DebugLogParams{
-		IncludeEntity: []string{"a", "b"},
-		IncludeModule: []string{"c", "d"},
-		IncludeLabel:  []string{"e", "f"},
-		ExcludeEntity: []string{"g", "h"},
-		ExcludeModule: []string{"i", "j"},
-		ExcludeLabel:  []string{"k", "l"},
-		Limit:         100,
-		Backlog:       200,
-		Level:         loggo.ERROR,
-		Replay:        true,
-		NoTail:        true,
-		StartTime:     time.Date(2016, 11, 30, 11, 48, 0, 100, time.UTC),
+		IncludeEntity:  []string{"a", "b"},
+		IncludeModule:  []string{"c", "d"},
+		IncludeLabel:   []string{"e", "f"},
+		ExcludeEntity:  []string{"g", "h"},
+		ExcludeModule:  []string{"i", "j"},
+		ExcludeLabel:   []string{"k", "l"},
+		Limit:          100,
+		Backlog:        200,
+		Level:          loggo.ERROR,
+		Replay:         true,
+		NoTail:         true,
+		StartTime:      time.Date(2016, 11, 30, 11, 48, 0, 100, time.UTC),
+		EndTime:        time.Date(2016, 11, 30, 13, 48, 0, 0, time.UTC),
+		IncludeMessage: "hook failed",
+		ExcludeMessage: "^DEBUG",
 	}
 
 	urlValues := url.Values{
-		"includeEntity": params.IncludeEntity,
-		"includeModule": params.IncludeModule,
-		"includeLabel":  params.IncludeLabel,
-		"excludeEntity": params.ExcludeEntity,
-		"excludeModule": params.ExcludeModule,
-		"excludeLabel":  params.ExcludeLabel,
-		"maxLines":      {"100"},
-		"backlog":       {"200"},
-		"level":         {"ERROR"},
-		"replay":        {"true"},
-		"noTail":        {"true"},
-		"startTime":     {"2016-11-30T11:48:00.0000001Z"},
+		"includeEntity":  params.IncludeEntity,
+		"includeModule":  params.IncludeModule,
+		"includeLabel":   params.IncludeLabel,
+		"excludeEntity":  params.ExcludeEntity,
+		"excludeModule":  params.ExcludeModule,
+		"excludeLabel":   params.ExcludeLabel,
+		"maxLines":       {"100"},
+		"backlog":        {"200"},
+		"level":          {"ERROR"},
+		"replay":         {"true"},
+		"noTail":         {"true"},
+		"startTime":      {"2016-11-30T11:48:00.0000001Z"},
+		"endTime":        {"2016-11-30T13:48:00Z"},
+		"includeMessage": {"hook failed"},
+		"excludeMessage": {"^DEBUG"},
 	}
 
 	client := apiclient.NewClient(s.APIState, jtesting.NoopLogger{})
@@ -1455,7 +1461,7 @@ func (f *fakeRPCConnection) Close() error {
 	return nil
 }
 
-func (f *fakeRPCConnection) Call(req rpc.Request, params, response interface{}) error {
+func (f *fakeRPCConnection) CallContext(_ context.Context, req rpc.Request, params, response interface{}) error {
 	f.stub.AddCall(req.Type+"."+req.Action, req.Version, params)
 	if f.response != nil {
 		rv := reflect.ValueOf(response)
@@ -0,0 +1,179 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/juju/juju/rpc/params"
+)
+
+// OIDCScopes are the scopes requested when logging in with an OpenID
+// Connect provider. The offline_access scope asks for a refresh token,
+// so that the user need not log in again when the ID token expires.
+var OIDCScopes = []string{"openid", "email", "profile", "offline_access"}
+
+// OIDCToken holds the tokens issued to a user by an OpenID Connect
+// provider.
+type OIDCToken struct {
+	// IDToken is presented to the controller to log in.
+	IDToken string
+
+	// RefreshToken is used to obtain a new ID token when the ID
+	// token expires.
+	RefreshToken string
+
+	// Expiry is when the access token issued with the ID token
+	// expires.
+	Expiry time.Time
+}
+
+// OIDCProvider is an OpenID Connect provider that users may log in
+// to the controller with, using the device authorization grant.
+type OIDCProvider struct {
+	config oauth2.Config
+	client *http.Client
+}
+
+// FetchOIDCLoginConfig returns the details of the OpenID Connect
+// provider that the controller at the given address accepts logins
+// from. It returns a NotFound error if the controller does not accept
+// OpenID Connect logins.
+func FetchOIDCLoginConfig(ctx context.Context, client *http.Client, addr string) (params.OIDCLoginConfig, error) {
+	var config params.OIDCLoginConfig
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+addr+"/oidc", nil)
+	if err != nil {
+		return config, errors.Trace(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return config, errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp params.ErrorResult
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error == nil {
+			return config, errors.Errorf("cannot get OIDC login config: %s", resp.Status)
+		}
+		if errResp.Error.Code == params.CodeNotFound {
+			return config, errors.NotFoundf("OIDC login on controller")
+		}
+		return config, errResp.Error
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return config, errors.Annotate(err, "cannot decode OIDC login config")
+	}
+	return config, nil
+}
+
+// NewOIDCProvider returns the OpenID Connect provider at the given
+// issuer URL, discovering its endpoints. The client is used to talk
+// to the provider.
+func NewOIDCProvider(ctx context.Context, client *http.Client, issuerURL, clientID string) (*OIDCProvider, error) {
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Annotatef(err, "discovering OIDC provider %q", issuerURL)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("discovering OIDC provider %q: %s", issuerURL, resp.Status)
+	}
+
+	var doc struct {
+		TokenEndpoint               string `json:"token_endpoint"`
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, errors.Annotatef(err, "decoding discovery document of OIDC provider %q", issuerURL)
+	}
+	if doc.DeviceAuthorizationEndpoint == "" {
+		return nil, errors.NotSupportedf("device login with OIDC provider %q", issuerURL)
+	}
+	if doc.TokenEndpoint == "" {
+		return nil, errors.Errorf("OIDC provider %q has no token endpoint", issuerURL)
+	}
+	return &OIDCProvider{
+		config: oauth2.Config{
+			ClientID: clientID,
+			Endpoint: oauth2.Endpoint{
+				DeviceAuthURL: doc.DeviceAuthorizationEndpoint,
+				TokenURL:      doc.TokenEndpoint,
+				// The controller is a public client, without a
+				// secret, so the client ID is sent as a parameter.
+				AuthStyle: oauth2.AuthStyleInParams,
+			},
+			Scopes: OIDCScopes,
+		},
+		client: client,
+	}, nil
+}
+
+// DeviceLogin logs the user in with the device authorization grant.
+// The prompt function is called with the URL that the user must visit,
+// and the code they must enter there, to approve the login; the
+// provider is then polled until the user has done so, or the context
+// is done.
+func (p *OIDCProvider) DeviceLogin(ctx context.Context, prompt func(verificationURL, userCode string) error) (*OIDCToken, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	auth, err := p.config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, errors.Annotate(err, "starting device login")
+	}
+	verificationURL := auth.VerificationURIComplete
+	if verificationURL == "" {
+		verificationURL = auth.VerificationURI
+	}
+	if err := prompt(verificationURL, auth.UserCode); err != nil {
+		return nil, errors.Trace(err)
+	}
+	token, err := p.config.DeviceAccessToken(ctx, auth)
+	if err != nil {
+		return nil, errors.Annotate(err, "waiting for device login")
+	}
+	return oidcToken(token)
+}
+
+// Refresh returns new tokens for the user, using a refresh token
+// issued to them previously.
+func (p *OIDCProvider) Refresh(ctx context.Context, refreshToken string) (*OIDCToken, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	token, err := p.config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, errors.Annotate(err, "refreshing OIDC token")
+	}
+	result, err := oidcToken(token)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.RefreshToken == "" {
+		// Providers need not issue a new refresh token.
+		result.RefreshToken = refreshToken
+	}
+	return result, nil
+}
+
+func oidcToken(token *oauth2.Token) (*OIDCToken, error) {
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return nil, errors.New("OIDC provider did not issue an ID token")
+	}
+	return &OIDCToken{
+		IDToken:      idToken,
+		RefreshToken: token.RefreshToken,
+		Expiry:       token.Expiry,
+	}, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/authentication"
+	apitesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/rpc/params"
+)
+
+type OIDCSuite struct {
+	testing.IsolationSuite
+
+	issuer *apitesting.FakeOIDCIssuer
+}
+
+var _ = gc.Suite(&OIDCSuite{})
+
+func (s *OIDCSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	issuer, err := apitesting.NewFakeOIDCIssuer("juju")
+	c.Assert(err, jc.ErrorIsNil)
+	s.issuer = issuer
+	s.AddCleanup(func(c *gc.C) { s.issuer.Close() })
+}
+
+func (s *OIDCSuite) newProvider(c *gc.C) *authentication.OIDCProvider {
+	provider, err := authentication.NewOIDCProvider(context.Background(), s.issuer.Client(), s.issuer.URL(), "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	return provider
+}
+
+func (s *OIDCSuite) assertIDToken(c *gc.C, idToken, email string) {
+	token, err := jwt.ParseInsecure([]byte(idToken))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Issuer(), gc.Equals, s.issuer.URL())
+	c.Assert(token.Audience(), jc.DeepEquals, []string{"juju"})
+	value, _ := token.Get("email")
+	c.Assert(value, gc.Equals, email)
+}
+
+func (s *OIDCSuite) TestDeviceLogin(c *gc.C) {
+	s.issuer.ApproveDevice(map[string]interface{}{"email": "alice@example.com"}, 1)
+
+	var prompted []string
+	token, err := s.newProvider(c).DeviceLogin(context.Background(), func(verificationURL, userCode string) error {
+		prompted = append(prompted, verificationURL, userCode)
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(prompted, jc.DeepEquals, []string{
+		s.issuer.URL() + "/activate?user_code=" + apitesting.FakeOIDCUserCode,
+		apitesting.FakeOIDCUserCode,
+	})
+	c.Assert(s.issuer.DevicePolls(), gc.Equals, 2)
+	s.assertIDToken(c, token.IDToken, "alice@example.com")
+	c.Assert(token.RefreshToken, gc.Not(gc.Equals), "")
+}
+
+func (s *OIDCSuite) TestDeviceLoginPromptError(c *gc.C) {
+	_, err := s.newProvider(c).DeviceLogin(context.Background(), func(string, string) error {
+		return errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(s.issuer.DevicePolls(), gc.Equals, 0)
+}
+
+func (s *OIDCSuite) TestDeviceLoginCancelled(c *gc.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := s.newProvider(c).DeviceLogin(ctx, func(string, string) error {
+		cancel()
+		return nil
+	})
+	c.Assert(err, gc.ErrorMatches, "waiting for device login: .*context canceled")
+}
+
+func (s *OIDCSuite) TestRefresh(c *gc.C) {
+	s.issuer.ApproveDevice(map[string]interface{}{"email": "alice@example.com"}, 0)
+	provider := s.newProvider(c)
+	token, err := provider.DeviceLogin(context.Background(), func(string, string) error { return nil })
+	c.Assert(err, jc.ErrorIsNil)
+
+	refreshed, err := provider.Refresh(context.Background(), token.RefreshToken)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIDToken(c, refreshed.IDToken, "alice@example.com")
+	c.Assert(refreshed.RefreshToken, gc.Not(gc.Equals), "")
+	c.Assert(refreshed.RefreshToken, gc.Not(gc.Equals), token.RefreshToken)
+}
+
+func (s *OIDCSuite) TestRefreshInvalidToken(c *gc.C) {
+	_, err := s.newProvider(c).Refresh(context.Background(), "bogus")
+	c.Assert(err, gc.ErrorMatches, `refreshing OIDC token: .*invalid_grant.*`)
+}
+
+func (s *OIDCSuite) TestNewOIDCProviderWithoutDeviceLogin(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": "https://example.com/token"})
+	}))
+	defer server.Close()
+	_, err := authentication.NewOIDCProvider(context.Background(), server.Client(), server.URL, "juju")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *OIDCSuite) TestFetchOIDCLoginConfig(c *gc.C) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, gc.Equals, "/oidc")
+		_ = json.NewEncoder(w).Encode(params.OIDCLoginConfig{
+			IssuerURL: "https://login.example.com",
+			ClientID:  "juju",
+		})
+	}))
+	defer server.Close()
+	config, err := authentication.FetchOIDCLoginConfig(context.Background(), server.Client(), server.Listener.Addr().String())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, jc.DeepEquals, params.OIDCLoginConfig{
+		IssuerURL: "https://login.example.com",
+		ClientID:  "juju",
+	})
+}
+
+func (s *OIDCSuite) TestFetchOIDCLoginConfigNotFound(c *gc.C) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(params.ErrorResult{Error: &params.Error{
+			Message: "OIDC login not found",
+			Code:    params.CodeNotFound,
+		}})
+	}))
+	defer server.Close()
+	_, err := authentication.FetchOIDCLoginConfig(context.Background(), server.Client(), server.Listener.Addr().String())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package changestream
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/go-querystring/query"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/core/changestream"
+	"github.com/juju/juju/rpc/params"
+)
+
+const changeStreamPath = "/changestream"
+
+// jsonReadCloser provides the functionality to read JSON-serialized
+// values from a streaming connection.
+type jsonReadCloser interface {
+	io.Closer
+
+	// ReadJSON decodes the next JSON value from the connection and
+	// sets the value at the provided pointer to that newly decoded one.
+	ReadJSON(interface{}) error
+}
+
+// Event describes a change to an entity in the controller database.
+type Event struct {
+	// ID is the position of the change in the stream.
+	ID int64
+
+	// Type is the type of change.
+	Type changestream.ChangeType
+
+	// Namespace is the namespace in which the change was made.
+	Namespace string
+
+	// ChangedUUID is the UUID of the entity that was changed.
+	ChangedUUID string
+
+	// Timestamp is when the change was made.
+	Timestamp time.Time
+}
+
+// ChangeStream streams change events from the /changestream API
+// endpoint over a websocket connection.
+type ChangeStream struct {
+	stream jsonReadCloser
+
+	mu       sync.Mutex
+	position int64
+	closed   bool
+}
+
+// Open opens a websocket to the API's /changestream endpoint and
+// returns a stream of the changes made in the controller database.
+// Only controller admins may open the stream.
+//
+// To resume a stream which was closed, or whose connection failed,
+// set cfg.After to the stream's last Position. If cfg.After is nil
+// the stream starts with the next change made.
+func Open(conn base.ControllerStreamConnector, cfg params.ChangeStreamConfig) (*ChangeStream, error) {
+	attrs, err := query.Values(cfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to generate URL query from config")
+	}
+	wsStream, err := conn.ConnectControllerStream(changeStreamPath, attrs, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot connect to %s", changeStreamPath)
+	}
+	cs := &ChangeStream{
+		stream: wsStream,
+	}
+	if cfg.After != nil {
+		cs.position = *cfg.After
+	}
+	return cs, nil
+}
+
+// Next blocks until the next batch of changes arrives from the server
+// and returns them, oldest first.
+//
+// An error indicates either the streaming connection is closed, the
+// connection failed, or the data read from the connection is
+// corrupted. In each of these cases the stream should be re-opened
+// from its Position, so that no changes are missed.
+func (cs *ChangeStream) Next() ([]Event, error) {
+	for {
+		var batch params.ChangeStreamEvents
+		if err := cs.stream.ReadJSON(&batch); err != nil {
+			return nil, errors.Trace(err)
+		}
+		events := make([]Event, len(batch.Events))
+		for i, apiEvent := range batch.Events {
+			event, err := eventFromAPI(apiEvent)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			events[i] = event
+		}
+
+		cs.mu.Lock()
+		cs.position = batch.Position
+		cs.mu.Unlock()
+
+		// Batches without any changes just tell us where the
+		// stream is.
+		if len(events) > 0 {
+			return events, nil
+		}
+	}
+}
+
+// Position returns the position of the stream after the changes
+// returned by the last call to Next.
+func (cs *ChangeStream) Position() int64 {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.position
+}
+
+// Close closes the stream. Any blocked call to Next returns an error.
+func (cs *ChangeStream) Close() error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.closed {
+		return nil
+	}
+	cs.closed = true
+	return errors.Trace(cs.stream.Close())
+}
+
+// See the counterpart in apiserver/changestream/changestream.go.
+func eventFromAPI(apiEvent params.ChangeStreamEvent) (Event, error) {
+	var changeType changestream.ChangeType
+	switch apiEvent.Type {
+	case "create":
+		changeType = changestream.Create
+	case "update":
+		changeType = changestream.Update
+	case "delete":
+		changeType = changestream.Delete
+	default:
+		return Event{}, errors.NotValidf("change type %q", apiEvent.Type)
+	}
+	return Event{
+		ID:          apiEvent.ID,
+		Type:        changeType,
+		Namespace:   apiEvent.Namespace,
+		ChangedUUID: apiEvent.ChangedUUID,
+		Timestamp:   apiEvent.Timestamp,
+	}, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package changestream_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/changestream"
+	corechangestream "github.com/juju/juju/core/changestream"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ChangeStreamSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ChangeStreamSuite{})
+
+func (s *ChangeStreamSuite) TestOpen(c *gc.C) {
+	stub := &testing.Stub{}
+	conn := &mockConnector{stub: stub, stream: &mockStream{stub: stub}}
+	after := int64(42)
+
+	cs, err := changestream.Open(conn, params.ChangeStreamConfig{
+		Namespaces: []string{"external_controller"},
+		Types:      []string{"create", "delete"},
+		After:      &after,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cs.Position(), gc.Equals, int64(42))
+
+	stub.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ConnectControllerStream",
+		Args: []interface{}{"/changestream", url.Values{
+			"namespace": {"external_controller"},
+			"type":      {"create", "delete"},
+			"after":     {"42"},
+		}, http.Header(nil)},
+	}})
+}
+
+func (s *ChangeStreamSuite) TestOpenFromHead(c *gc.C) {
+	stub := &testing.Stub{}
+	conn := &mockConnector{stub: stub, stream: &mockStream{stub: stub}}
+
+	_, err := changestream.Open(conn, params.ChangeStreamConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCall(c, 0, "ConnectControllerStream", "/changestream", url.Values{}, http.Header(nil))
+}
+
+func (s *ChangeStreamSuite) TestOpenError(c *gc.C) {
+	stub := &testing.Stub{}
+	conn := &mockConnector{stub: stub}
+	stub.SetErrors(errors.New("foo"))
+
+	_, err := changestream.Open(conn, params.ChangeStreamConfig{})
+	c.Assert(err, gc.ErrorMatches, "cannot connect to /changestream: foo")
+}
+
+func (s *ChangeStreamSuite) TestNext(c *gc.C) {
+	ts := time.Date(2023, 5, 2, 10, 0, 0, 0, time.UTC)
+	stub := &testing.Stub{}
+	stream := &mockStream{stub: stub, batches: []params.ChangeStreamEvents{{
+		Position: 10,
+	}, {
+		Events: []params.ChangeStreamEvent{{
+			ID:          11,
+			Type:        "create",
+			Namespace:   "external_controller",
+			ChangedUUID: "deadbeef",
+			Timestamp:   ts,
+		}, {
+			ID:          13,
+			Type:        "delete",
+			Namespace:   "external_controller",
+			ChangedUUID: "deadbeef",
+			Timestamp:   ts,
+		}},
+		Position: 14,
+	}}}
+	cs, err := changestream.Open(&mockConnector{stub: stub, stream: stream}, params.ChangeStreamConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The batch which only carries the position is skipped.
+	events, err := cs.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []changestream.Event{{
+		ID:          11,
+		Type:        corechangestream.Create,
+		Namespace:   "external_controller",
+		ChangedUUID: "deadbeef",
+		Timestamp:   ts,
+	}, {
+		ID:          13,
+		Type:        corechangestream.Delete,
+		Namespace:   "external_controller",
+		ChangedUUID: "deadbeef",
+		Timestamp:   ts,
+	}})
+	c.Assert(cs.Position(), gc.Equals, int64(14))
+}
+
+func (s *ChangeStreamSuite) TestNextInvalidType(c *gc.C) {
+	stub := &testing.Stub{}
+	stream := &mockStream{stub: stub, batches: []params.ChangeStreamEvents{{
+		Events:   []params.ChangeStreamEvent{{ID: 1, Type: "rename"}},
+		Position: 1,
+	}}}
+	cs, err := changestream.Open(&mockConnector{stub: stub, stream: stream}, params.ChangeStreamConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cs.Next()
+	c.Assert(err, gc.ErrorMatches, `change type "rename" not valid`)
+	// The position isn't moved past changes that weren't returned.
+	c.Assert(cs.Position(), gc.Equals, int64(0))
+}
+
+func (s *ChangeStreamSuite) TestNextError(c *gc.C) {
+	stub := &testing.Stub{}
+	stream := &mockStream{stub: stub}
+	cs, err := changestream.Open(&mockConnector{stub: stub, stream: stream}, params.ChangeStreamConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	stub.SetErrors(errors.New("connection lost"))
+	_, err = cs.Next()
+	c.Assert(err, gc.ErrorMatches, "connection lost")
+}
+
+func (s *ChangeStreamSuite) TestClose(c *gc.C) {
+	stub := &testing.Stub{}
+	cs, err := changestream.Open(&mockConnector{stub: stub, stream: &mockStream{stub: stub}}, params.ChangeStreamConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(cs.Close(), jc.ErrorIsNil)
+	c.Assert(cs.Close(), jc.ErrorIsNil)
+	stub.CheckCallNames(c, "ConnectControllerStream", "Close")
+}
+
+type mockConnector struct {
+	stub   *testing.Stub
+	stream base.Stream
+}
+
+func (c *mockConnector) ConnectControllerStream(path string, attrs url.Values, headers http.Header) (base.Stream, error) {
+	c.stub.AddCall("ConnectControllerStream", path, attrs, headers)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c.stream, nil
+}
+
+type mockStream struct {
+	base.Stream
+	stub    *testing.Stub
+	batches []params.ChangeStreamEvents
+}
+
+func (s *mockStream) ReadJSON(v interface{}) error {
+	s.stub.AddCall("ReadJSON")
+	if err := s.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	batch, ok := v.(*params.ChangeStreamEvents)
+	if !ok {
+		return errors.Errorf("unexpected output type: %T", v)
+	}
+	if len(s.batches) == 0 {
+		return errors.New("no more batches")
+	}
+	*batch = s.batches[0]
+	s.batches = s.batches[1:]
+	return nil
+}
+
+func (s *mockStream) Close() error {
+	s.stub.AddCall("Close")
+	return s.stub.NextErr()
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/rpc/params"
+)
+
+// Client is the api client for the AuditLog facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates an audit log api client.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, "AuditLog")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Records returns the audit log records matching the filter, oldest
+// first.
+func (c *Client) Records(filter params.AuditLogFilter) ([]params.AuditLogRecord, error) {
+	if c.BestAPIVersion() < 1 {
+		return nil, errors.NotSupportedf("querying the audit log on this juju version")
+	}
+	var result params.AuditLogRecords
+	if err := c.facade.FacadeCall("Records", filter, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Records, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/client/auditlog"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+type AuditLogSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *AuditLogSuite) TestRecords(c *gc.C) {
+	since := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	filter := params.AuditLogFilter{
+		User:   "bob",
+		Method: "Client.FullStatus",
+		Since:  &since,
+		Limit:  10,
+	}
+	records := []params.AuditLogRecord{{
+		ConversationID: "0123456789abcdef",
+		Who:            "bob",
+		What:           "juju status",
+		RequestID:      1,
+		When:           since.Add(time.Minute),
+		Facade:         "Client",
+		Method:         "FullStatus",
+		Version:        6,
+	}}
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "AuditLog")
+			c.Check(version, gc.Equals, 1)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Records")
+			c.Check(arg, jc.DeepEquals, filter)
+			c.Assert(result, gc.FitsTypeOf, &params.AuditLogRecords{})
+			*(result.(*params.AuditLogRecords)) = params.AuditLogRecords{Records: records}
+			return nil
+		}), BestVersion: 1,
+	}
+	client := auditlog.NewClient(apiCaller)
+	result, err := client.Records(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, records)
+}
+
+func (s *AuditLogSuite) TestRecordsNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call")
+			return nil
+		}), BestVersion: 0,
+	}
+	client := auditlog.NewClient(apiCaller)
+	_, err := client.Records(params.AuditLogFilter{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
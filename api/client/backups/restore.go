@@ -0,0 +1,48 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/rpc/params"
+)
+
+// Upload sends the backup archive to the controller, ready to be
+// restored. It returns the filename of the archive on the controller.
+func (c *Client) Upload(archive io.ReadSeeker) (string, error) {
+	req, err := http.NewRequest(http.MethodPut, "/backups", archive)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot create upload request")
+	}
+	req.Header.Set("Content-Type", params.ContentTypeRaw)
+
+	httpClient, err := c.st.HTTPClient()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	var result params.BackupsUploadResult
+	if err := httpClient.Do(c.st.Context(), req, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	return result.ID, nil
+}
+
+// Restore asks the controller to replace its state with the contents
+// of the named backup archive, which must already be on the controller.
+// The controller agents restart once the restore completes, which
+// will drop the connection used to make the request.
+func (c *Client) Restore(filename string) error {
+	if c.facade.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("restoring backups on this version of Juju")
+	}
+	args := params.RestoreArgs{
+		FileName: filename,
+	}
+	return errors.Trace(c.facade.FacadeCall("Restore", args, nil))
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/httprequest.v1"
+
+	"github.com/juju/juju/rpc/params"
+)
+
+type restoreSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+func (s *restoreSuite) TestUpload(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, gc.Equals, http.MethodPut)
+		c.Check(r.URL.String(), gc.Equals, "/backups")
+		c.Check(r.Header.Get("Content-Type"), gc.Equals, params.ContentTypeRaw)
+		data, err := io.ReadAll(r.Body)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, "<archive>")
+		w.Header().Set("Content-Type", params.ContentTypeJSON)
+		_, err = w.Write([]byte(`{"id":"/tmp/juju-backup-uploaded.tar.gz"}`))
+		c.Check(err, jc.ErrorIsNil)
+	}))
+	defer srv.Close()
+	httpClient := &httprequest.Client{BaseURL: srv.URL}
+
+	s.apiCaller.EXPECT().HTTPClient().Return(httpClient, nil)
+	s.apiCaller.EXPECT().Context().Return(context.TODO())
+
+	client := s.newClient()
+	id, err := client.Upload(strings.NewReader("<archive>"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(id, gc.Equals, "/tmp/juju-backup-uploaded.tar.gz")
+}
+
+func (s *restoreSuite) TestRestore(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	args := params.RestoreArgs{
+		FileName: "/tmp/juju-backup-uploaded.tar.gz",
+	}
+	s.facade.EXPECT().BestAPIVersion().Return(4)
+	s.facade.EXPECT().FacadeCall("Restore", args, nil).Return(nil)
+
+	client := s.newClient()
+	err := client.Restore("/tmp/juju-backup-uploaded.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreSuite) TestRestoreNotSupported(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	s.facade.EXPECT().BestAPIVersion().Return(3)
+
+	client := s.newClient()
+	err := client.Restore("/tmp/juju-backup-uploaded.tar.gz")
+	c.Assert(err, gc.ErrorMatches, `restoring backups on this version of Juju not supported`)
+}
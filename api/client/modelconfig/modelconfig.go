@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/rpc/params"
 )
@@ -85,6 +86,26 @@ func (c *Client) SetModelConstraints(constraints constraints.Value) error {
 	return c.facade.FacadeCall("SetModelConstraints", params, nil)
 }
 
+// GetModelQuotas returns the resource quotas of the model, and the
+// resources the model currently consumes. A zero quota means that the
+// resource is not limited.
+func (c *Client) GetModelQuotas() (quotas, usage quota.ModelResources, _ error) {
+	var result params.ModelQuotasResult
+	err := c.facade.FacadeCall("GetModelQuotas", nil, &result)
+	if err != nil {
+		return quotas, usage, errors.Trace(err)
+	}
+	return quota.ModelResources(result.Quotas), quota.ModelResources(result.Usage), nil
+}
+
+// SetModelQuotas replaces the resource quotas of the model.
+func (c *Client) SetModelQuotas(quotas quota.ModelResources) error {
+	args := params.SetModelQuotas{
+		Quotas: params.ModelResources(quotas),
+	}
+	return c.facade.FacadeCall("SetModelQuotas", args, nil)
+}
+
 // SetSLALevel sets the support level for the given model.
 func (c *Client) SetSLALevel(level, owner string, creds []byte) error {
 	args := params.ModelSLA{
@@ -11,6 +11,7 @@ import (
 	basemocks "github.com/juju/juju/api/base/mocks"
 	"github.com/juju/juju/api/client/modelconfig"
 	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/rpc/params"
 )
@@ -181,3 +182,37 @@ func (s *modelconfigSuite) TestSetModelConstraints(c *gc.C) {
 	err := client.SetModelConstraints(constraints.MustParse("arch=amd64"))
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *modelconfigSuite) TestGetModelQuotas(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	var args interface{}
+	res := new(params.ModelQuotasResult)
+	results := params.ModelQuotasResult{
+		Quotas: params.ModelResources{Machines: 10, Memory: 8192},
+		Usage:  params.ModelResources{Machines: 2, Memory: 2048},
+	}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().FacadeCall("GetModelQuotas", args, res).SetArg(2, results).Return(nil)
+	client := modelconfig.NewClientFromCaller(mockFacadeCaller)
+	quotas, usage, err := client.GetModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, jc.DeepEquals, quota.ModelResources{Machines: 10, Memory: 8192})
+	c.Assert(usage, jc.DeepEquals, quota.ModelResources{Machines: 2, Memory: 2048})
+}
+
+func (s *modelconfigSuite) TestSetModelQuotas(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	var res interface{}
+	args := params.SetModelQuotas{
+		Quotas: params.ModelResources{Units: 5},
+	}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().FacadeCall("SetModelQuotas", args, res).Return(nil)
+	client := modelconfig.NewClientFromCaller(mockFacadeCaller)
+	err := client.SetModelQuotas(quota.ModelResources{Units: 5})
+	c.Assert(err, jc.ErrorIsNil)
+}
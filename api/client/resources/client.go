@@ -4,6 +4,7 @@
 package resources
 
 import (
+	"fmt"
 	"io"
 	"strings"
 
@@ -122,6 +123,17 @@ func (c Client) Upload(application, name, filename, pendingID string, reader io.
 	return nil
 }
 
+// OpenResource downloads the blob for the current revision of the
+// named application resource.
+func (c Client) OpenResource(application, name string) (io.ReadCloser, error) {
+	uri := fmt.Sprintf(HTTPEndpointPath, application, name)
+	reader, err := http.OpenURI(c.facade.RawAPICaller().Context(), c.httpClient, uri, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening resource %q of application %q", name, application)
+	}
+	return reader, nil
+}
+
 // CharmID represents the underlying charm for a given application. This
 // includes both the URL and the origin.
 type CharmID struct {
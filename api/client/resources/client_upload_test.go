@@ -77,6 +77,42 @@ func (s *UploadSuite) TestUpload(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *UploadSuite) TestOpenResource(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	ctx := context.TODO()
+	s.mockAPICaller.EXPECT().Context().Return(ctx)
+
+	s.mockHTTPClient.EXPECT().Do(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *http.Request, resp interface{}) error {
+			c.Check(req.Method, gc.Equals, "GET")
+			c.Check(req.URL.Path, gc.Equals, "/applications/a-application/resources/spam")
+			*(resp.(**http.Response)) = &http.Response{
+				Body: io.NopCloser(strings.NewReader("<data>")),
+			}
+			return nil
+		},
+	)
+
+	reader, err := s.client.OpenResource("a-application", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<data>")
+}
+
+func (s *UploadSuite) TestOpenResourceError(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	ctx := context.TODO()
+	s.mockAPICaller.EXPECT().Context().Return(ctx)
+	s.mockHTTPClient.EXPECT().Do(ctx, gomock.Any(), gomock.Any()).Return(errors.New("boom"))
+
+	_, err := s.client.OpenResource("a-application", "spam")
+	c.Assert(err, gc.ErrorMatches, `opening resource "spam" of application "a-application": boom`)
+}
+
 type reqMatcher struct {
 	c   *gc.C
 	req *http.Request
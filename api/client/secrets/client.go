@@ -80,6 +80,7 @@ func (api *Client) ListSecrets(reveal bool, filter secrets.Filter) ([]SecretDeta
 				CreateTime:  r.CreateTime,
 				UpdateTime:  r.UpdateTime,
 				ExpireTime:  r.ExpireTime,
+				RollbackTo:  r.RollbackTo,
 			}
 		}
 		if reveal && r.Value != nil {
@@ -174,6 +175,42 @@ func (c *Client) UpdateSecret(
 	return nil
 }
 
+// RollbackSecret creates a new revision of an existing secret
+// with the content of the specified older revision.
+func (c *Client) RollbackSecret(uri *secrets.URI, name string, revision int) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("secret rollback on this version of Juju")
+	}
+	if uri == nil && name == "" {
+		return errors.New("must specify either URI or name")
+	}
+	if uri != nil && name != "" {
+		return errors.New("must specify either URI or name but not both")
+	}
+	arg := params.UpdateUserSecretArg{
+		RollbackTo: &revision,
+	}
+	if uri != nil {
+		arg.URI = uri.String()
+	}
+	if name != "" {
+		arg.ExistingLabel = name
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("UpdateSecrets", params.UpdateUserSecretArgs{Args: []params.UpdateUserSecretArg{arg}}, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.TranslateWellKnownError(result.Error)
+	}
+	return nil
+}
+
 func (c *Client) RemoveSecret(uri *secrets.URI, name string, revision *int) error {
 	if c.BestAPIVersion() < 2 {
 		return errors.NotSupportedf("user secrets")
@@ -264,6 +264,38 @@ func (s *SecretsSuite) TestUpdateSecret(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *SecretsSuite) TestRollbackSecret(c *gc.C) {
+	uri := secrets.NewURI()
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Assert(objType, gc.Equals, "Secrets")
+		c.Assert(request, gc.Equals, "UpdateSecrets")
+		c.Assert(arg, gc.DeepEquals, params.UpdateUserSecretArgs{
+			Args: []params.UpdateUserSecretArg{
+				{
+					URI:        uri.String(),
+					RollbackTo: ptr(2),
+				},
+			},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{}}}
+		return nil
+	})
+	caller := testing.BestVersionCaller{apiCaller, 3}
+	client := apisecrets.NewClient(caller)
+	err := client.RollbackSecret(uri, "", 2)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SecretsSuite) TestRollbackSecretNotSupported(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return nil
+	})
+	caller := testing.BestVersionCaller{apiCaller, 2}
+	client := apisecrets.NewClient(caller)
+	err := client.RollbackSecret(secrets.NewURI(), "", 2)
+	c.Assert(err, gc.ErrorMatches, "secret rollback on this version of Juju not supported")
+}
+
 func (s *SecretsSuite) TestRemoveSecretError(c *gc.C) {
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		return nil
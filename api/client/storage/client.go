@@ -314,3 +314,121 @@ func (c *Client) Import(
 	}
 	return names.ParseStorageTag(results.Results[0].Result.StorageTag)
 }
+
+// SnapshotStorage requests snapshots of the volumes backing the
+// specified storage instances.
+func (c *Client) SnapshotStorage(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error) {
+	if c.facade.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("snapshotting storage on this version of Juju")
+	}
+	args := params.Entities{Entities: make([]params.Entity, len(storageIds))}
+	for i, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		args.Entities[i].Tag = names.NewStorageTag(id).String()
+	}
+	var results params.VolumeSnapshotDetailsResults
+	if err := c.facade.FacadeCall("SnapshotStorage", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(storageIds) {
+		return nil, errors.Errorf(
+			"expected %d result(s), got %d",
+			len(storageIds), len(results.Results),
+		)
+	}
+	return results.Results, nil
+}
+
+// ListStorageSnapshots lists the volume snapshots taken of the specified
+// storage instances, or all volume snapshots in the model if none are
+// specified.
+func (c *Client) ListStorageSnapshots(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error) {
+	if c.facade.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("listing storage snapshots on this version of Juju")
+	}
+	var args params.StorageSnapshotFilter
+	for _, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		args.StorageTags = append(args.StorageTags, names.NewStorageTag(id).String())
+	}
+	var results params.VolumeSnapshotDetailsResults
+	if err := c.facade.FacadeCall("ListStorageSnapshots", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// RestoreStorageSnapshot creates a new, detached storage instance from
+// the specified volume snapshot. If storageName is empty, the storage
+// name of the snapshotted storage instance is used.
+func (c *Client) RestoreStorageSnapshot(snapshotId, storageName string) (names.StorageTag, error) {
+	if c.facade.BestAPIVersion() < 7 {
+		return names.StorageTag{}, errors.NotSupportedf("restoring storage snapshots on this version of Juju")
+	}
+	args := params.RestoreStorageSnapshotArgs{
+		Args: []params.RestoreStorageSnapshotArg{{
+			Id:          snapshotId,
+			StorageName: storageName,
+		}},
+	}
+	var results params.ImportStorageResults
+	if err := c.facade.FacadeCall("RestoreStorageSnapshots", args, &results); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return names.StorageTag{}, errors.Errorf(
+			"expected 1 result, got %d",
+			len(results.Results),
+		)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return names.StorageTag{}, err
+	}
+	return names.ParseStorageTag(results.Results[0].Result.StorageTag)
+}
+
+// RemoveStorageSnapshots removes the specified volume snapshots.
+func (c *Client) RemoveStorageSnapshots(snapshotIds []string) ([]params.ErrorResult, error) {
+	if c.facade.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("removing storage snapshots on this version of Juju")
+	}
+	args := params.VolumeSnapshotIds{Ids: snapshotIds}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveStorageSnapshots", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(snapshotIds) {
+		return nil, errors.Errorf(
+			"expected %d result(s), got %d",
+			len(snapshotIds), len(results.Results),
+		)
+	}
+	return results.Results, nil
+}
+
+// ResizeStorage requests that the volume backing the specified storage
+// instance be grown to the specified size in MiB. Any filesystem on the
+// volume is grown once the volume has been resized.
+func (c *Client) ResizeStorage(storageId string, size uint64) error {
+	if c.facade.BestAPIVersion() < 8 {
+		return errors.NotSupportedf("resizing storage on this version of Juju")
+	}
+	if !names.IsValidStorage(storageId) {
+		return errors.NotValidf("storage ID %q", storageId)
+	}
+	args := params.ResizeStorageArgs{
+		Args: []params.ResizeStorageArg{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Size:       size,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("ResizeStorage", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
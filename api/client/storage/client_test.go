@@ -793,3 +793,132 @@ func (s *storageMockSuite) TestUpdatePoolFacadeCallError(c *gc.C) {
 	err := storageClient.UpdatePool("", "", nil)
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 }
+
+func (s *storageMockSuite) TestSnapshotStorage(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	expectedArgs := params.Entities{Entities: []params.Entity{{Tag: "storage-data-0"}}}
+	result := new(params.VolumeSnapshotDetailsResults)
+	results := params.VolumeSnapshotDetailsResults{
+		Results: []params.VolumeSnapshotDetailsResult{{
+			Result: &params.VolumeSnapshotDetails{Id: "0/1@2", StorageTag: "storage-data-0"},
+		}},
+	}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("SnapshotStorage", expectedArgs, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	found, err := storageClient.SnapshotStorage([]string{"data/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, results.Results)
+}
+
+func (s *storageMockSuite) TestSnapshotStorageNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(6)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	_, err := storageClient.SnapshotStorage([]string{"data/0"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageMockSuite) TestListStorageSnapshots(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	expectedArgs := params.StorageSnapshotFilter{StorageTags: []string{"storage-data-0"}}
+	result := new(params.VolumeSnapshotDetailsResults)
+	results := params.VolumeSnapshotDetailsResults{
+		Results: []params.VolumeSnapshotDetailsResult{{
+			Result: &params.VolumeSnapshotDetails{Id: "0/1@2", StorageTag: "storage-data-0"},
+		}},
+	}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("ListStorageSnapshots", expectedArgs, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	found, err := storageClient.ListStorageSnapshots([]string{"data/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, results.Results)
+}
+
+func (s *storageMockSuite) TestRestoreStorageSnapshot(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	expectedArgs := params.RestoreStorageSnapshotArgs{
+		Args: []params.RestoreStorageSnapshotArg{{Id: "0/1@2", StorageName: "logs"}},
+	}
+	result := new(params.ImportStorageResults)
+	results := params.ImportStorageResults{
+		Results: []params.ImportStorageResult{{
+			Result: &params.ImportStorageDetails{StorageTag: "storage-logs-3"},
+		}},
+	}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("RestoreStorageSnapshots", expectedArgs, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	storageTag, err := storageClient.RestoreStorageSnapshot("0/1@2", "logs")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageTag, gc.Equals, names.NewStorageTag("logs/3"))
+}
+
+func (s *storageMockSuite) TestRemoveStorageSnapshots(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	expectedArgs := params.VolumeSnapshotIds{Ids: []string{"0/1@2", "0/1@3"}}
+	result := new(params.ErrorResults)
+	results := params.ErrorResults{
+		Results: []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}},
+	}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("RemoveStorageSnapshots", expectedArgs, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	found, err := storageClient.RemoveStorageSnapshots([]string{"0/1@2", "0/1@3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, results.Results)
+}
+
+func (s *storageMockSuite) TestResizeStorage(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	expectedArgs := params.ResizeStorageArgs{Args: []params.ResizeStorageArg{{
+		StorageTag: "storage-data-0",
+		Size:       2048,
+	}}}
+	result := new(params.ErrorResults)
+	results := params.ErrorResults{Results: []params.ErrorResult{{
+		Error: &params.Error{Message: "volumes can only be grown"},
+	}}}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(8)
+	mockFacadeCaller.EXPECT().FacadeCall("ResizeStorage", expectedArgs, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	err := storageClient.ResizeStorage("data/0", 2048)
+	c.Assert(err, gc.ErrorMatches, "volumes can only be grown")
+}
+
+func (s *storageMockSuite) TestResizeStorageNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	err := storageClient.ResizeStorage("data/0", 2048)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
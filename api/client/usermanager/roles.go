@@ -0,0 +1,115 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+)
+
+func (c *Client) checkRolesSupported() error {
+	if c.facade.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("roles on this version of Juju")
+	}
+	return nil
+}
+
+// AddRole adds a new custom role to the controller. Users granted the
+// role on a model are given its access level, and may only call the
+// API methods allowed by its capabilities.
+func (c *Client) AddRole(name string, access permission.Access, capabilities ...permission.Capability) error {
+	if err := c.checkRolesSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	role := params.AddRole{
+		Name:         name,
+		Access:       string(access),
+		Capabilities: make([]string, len(capabilities)),
+	}
+	for i, capability := range capabilities {
+		role.Capabilities[i] = string(capability)
+	}
+	args := params.AddRoles{Roles: []params.AddRole{role}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddRoles", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return oneError(results)
+}
+
+// RemoveRole removes a custom role from the controller.
+func (c *Client) RemoveRole(name string) error {
+	if err := c.checkRolesSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	args := params.RoleNames{Names: []string{name}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveRoles", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return oneError(results)
+}
+
+// RoleInfo returns information about the named roles. If no roles are
+// named, all the roles are returned.
+func (c *Client) RoleInfo(roles ...string) ([]params.RoleInfo, error) {
+	if err := c.checkRolesSupported(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	args := params.RoleNames{Names: roles}
+	var results params.RoleInfoResults
+	if err := c.facade.FacadeCall("RoleInfo", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(roles) > 0 && len(results.Results) != len(roles) {
+		return nil, errors.Errorf("expected %d results, got %d", len(roles), len(results.Results))
+	}
+	info := make([]params.RoleInfo, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil && len(roles) > 0 {
+			return nil, errors.Annotate(result.Error, roles[i])
+		} else if result.Error != nil {
+			return nil, errors.Trace(result.Error)
+		}
+		if result.Result == nil {
+			return nil, errors.Errorf("unexpected nil result at position %d", i)
+		}
+		info[i] = *result.Result
+	}
+	return info, nil
+}
+
+// GrantRole grants the role to the user on the models with the given
+// UUIDs, replacing the role and access level they had.
+func (c *Client) GrantRole(user, role string, modelUUIDs ...string) error {
+	if err := c.checkRolesSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	if !names.IsValidUser(user) {
+		return errors.NotValidf("user name %q", user)
+	}
+	userTag := names.NewUserTag(user).String()
+	args := params.GrantRoles{Grants: make([]params.GrantRole, len(modelUUIDs))}
+	for i, modelUUID := range modelUUIDs {
+		if !names.IsValidModel(modelUUID) {
+			return errors.NotValidf("model UUID %q", modelUUID)
+		}
+		args.Grants[i] = params.GrantRole{
+			UserTag:  userTag,
+			ModelTag: names.NewModelTag(modelUUID).String(),
+			Role:     role,
+		}
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("GrantRoles", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != len(modelUUIDs) {
+		return errors.Errorf("expected %d results, got %d", len(modelUUIDs), len(results.Results))
+	}
+	return results.Combine()
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/mocks"
+	"github.com/juju/juju/api/client/usermanager"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+)
+
+type rolesSuite struct{}
+
+var _ = gc.Suite(&rolesSuite{})
+
+func (s *rolesSuite) TestAddRole(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.AddRoles{
+		Roles: []params.AddRole{{
+			Name:         "operator",
+			Access:       "write",
+			Capabilities: []string{"Action.*", "Client.FullStatus"},
+		}},
+	}
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(5)
+	mockFacadeCaller.EXPECT().FacadeCall("AddRoles", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.AddRole("operator", permission.WriteAccess, "Action.*", "Client.FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *rolesSuite) TestAddRoleNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(4)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.AddRole("operator", permission.WriteAccess, "*.*")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *rolesSuite) TestRemoveRoleError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.RoleNames{Names: []string{"operator"}}
+	results := params.ErrorResults{Results: []params.ErrorResult{{
+		Error: &params.Error{Message: `role "operator" is still granted to 1 user(s)`},
+	}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(5)
+	mockFacadeCaller.EXPECT().FacadeCall("RemoveRoles", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.RemoveRole("operator")
+	c.Assert(err, gc.ErrorMatches, `role "operator" is still granted to 1 user\(s\)`)
+}
+
+func (s *rolesSuite) TestRoleInfo(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	info := params.RoleInfo{
+		Name:         "operator",
+		Access:       "write",
+		Capabilities: []string{"Action.*"},
+		CreatedBy:    "admin",
+	}
+	args := params.RoleNames{Names: []string{"operator"}}
+	results := params.RoleInfoResults{Results: []params.RoleInfoResult{{Result: &info}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(5)
+	mockFacadeCaller.EXPECT().FacadeCall("RoleInfo", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	roles, err := client.RoleInfo("operator")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(roles, jc.DeepEquals, []params.RoleInfo{info})
+}
+
+func (s *rolesSuite) TestGrantRole(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.GrantRoles{
+		Grants: []params.GrantRole{{
+			UserTag:  "user-bob",
+			ModelTag: "model-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+			Role:     "operator",
+		}, {
+			UserTag:  "user-bob",
+			ModelTag: "model-deadbeef-0bad-400d-8000-4b1d0d06f00e",
+			Role:     "operator",
+		}},
+	}
+	results := params.ErrorResults{Results: []params.ErrorResult{
+		{}, {Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(5)
+	mockFacadeCaller.EXPECT().FacadeCall("GrantRoles", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.GrantRole("bob", "operator",
+		"deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		"deadbeef-0bad-400d-8000-4b1d0d06f00e",
+	)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *rolesSuite) TestGrantRoleInvalidUser(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(5)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.GrantRole("not/valid", "operator", "deadbeef-0bad-400d-8000-4b1d0d06f00d")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -0,0 +1,181 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+)
+
+func (c *Client) checkUserGroupsSupported() error {
+	if c.facade.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("user groups on this version of Juju")
+	}
+	return nil
+}
+
+// oneError returns the error of the single result, or an error if
+// there is not exactly one result.
+func oneError(results params.ErrorResults) error {
+	if count := len(results.Results); count != 1 {
+		return errors.Errorf("expected 1 result, got %d", count)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// AddUserGroup adds a new, empty, user group to the controller. The
+// members of an external group are asserted by the identity provider
+// that users log in with, rather than added with AddUserGroupMembers.
+func (c *Client) AddUserGroup(name string, external bool) error {
+	if err := c.checkUserGroupsSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	args := params.AddUserGroups{
+		Groups: []params.AddUserGroup{{Name: name, External: external}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddUserGroups", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return oneError(results)
+}
+
+// RemoveUserGroup removes a user group from the controller, along with
+// all the access granted to it.
+func (c *Client) RemoveUserGroup(name string) error {
+	if err := c.checkUserGroupsSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	args := params.UserGroupNames{Names: []string{name}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveUserGroups", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return oneError(results)
+}
+
+// UserGroupInfo returns information about the named user groups. If no
+// groups are named, all the groups visible to the user are returned.
+func (c *Client) UserGroupInfo(groups ...string) ([]params.UserGroupInfo, error) {
+	if err := c.checkUserGroupsSupported(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	args := params.UserGroupNames{Names: groups}
+	var results params.UserGroupInfoResults
+	if err := c.facade.FacadeCall("UserGroupInfo", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(groups) > 0 && len(results.Results) != len(groups) {
+		return nil, errors.Errorf("expected %d results, got %d", len(groups), len(results.Results))
+	}
+	info := make([]params.UserGroupInfo, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil && len(groups) > 0 {
+			return nil, errors.Annotate(result.Error, groups[i])
+		} else if result.Error != nil {
+			return nil, errors.Trace(result.Error)
+		}
+		if result.Result == nil {
+			return nil, errors.Errorf("unexpected nil result at position %d", i)
+		}
+		info[i] = *result.Result
+	}
+	return info, nil
+}
+
+// AddUserGroupMembers adds the users to the user group.
+func (c *Client) AddUserGroupMembers(group string, users ...string) error {
+	return c.modifyUserGroupMembers("AddUserGroupMembers", group, users)
+}
+
+// RemoveUserGroupMembers removes the users from the user group.
+func (c *Client) RemoveUserGroupMembers(group string, users ...string) error {
+	return c.modifyUserGroupMembers("RemoveUserGroupMembers", group, users)
+}
+
+func (c *Client) modifyUserGroupMembers(method, group string, users []string) error {
+	if err := c.checkUserGroupsSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	change := params.UserGroupMembers{
+		Group:    group,
+		UserTags: make([]string, len(users)),
+	}
+	for i, user := range users {
+		if !names.IsValidUser(user) {
+			return errors.NotValidf("user name %q", user)
+		}
+		change.UserTags[i] = names.NewUserTag(user).String()
+	}
+	args := params.ModifyUserGroupMembers{Changes: []params.UserGroupMembers{change}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return oneError(results)
+}
+
+// GrantUserGroup grants the user group access to the model, cloud or
+// controller with the given tag.
+func (c *Client) GrantUserGroup(group string, access permission.Access, target names.Tag) error {
+	return c.modifyUserGroupAccess(params.ModifyUserGroupAccess{
+		Group:     group,
+		Action:    params.GrantUserGroupAccess,
+		Access:    string(access),
+		TargetTag: target.String(),
+	})
+}
+
+// RevokeUserGroup revokes the access of the user group to the model,
+// cloud or controller with the given tag.
+func (c *Client) RevokeUserGroup(group string, access permission.Access, target names.Tag) error {
+	return c.modifyUserGroupAccess(params.ModifyUserGroupAccess{
+		Group:     group,
+		Action:    params.RevokeUserGroupAccess,
+		Access:    string(access),
+		TargetTag: target.String(),
+	})
+}
+
+// GrantUserGroupOffer grants the user group access to the offer with
+// the given URL.
+func (c *Client) GrantUserGroupOffer(group string, access permission.Access, offerURL string) error {
+	return c.modifyUserGroupAccess(params.ModifyUserGroupAccess{
+		Group:    group,
+		Action:   params.GrantUserGroupAccess,
+		Access:   string(access),
+		OfferURL: offerURL,
+	})
+}
+
+// RevokeUserGroupOffer revokes the access of the user group to the
+// offer with the given URL.
+func (c *Client) RevokeUserGroupOffer(group string, access permission.Access, offerURL string) error {
+	return c.modifyUserGroupAccess(params.ModifyUserGroupAccess{
+		Group:    group,
+		Action:   params.RevokeUserGroupAccess,
+		Access:   string(access),
+		OfferURL: offerURL,
+	})
+}
+
+func (c *Client) modifyUserGroupAccess(change params.ModifyUserGroupAccess) error {
+	if err := c.checkUserGroupsSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	args := params.ModifyUserGroupAccessRequest{
+		Changes: []params.ModifyUserGroupAccess{change},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("ModifyUserGroupAccess", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return oneError(results)
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/mocks"
+	"github.com/juju/juju/api/client/usermanager"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+)
+
+type userGroupsSuite struct{}
+
+var _ = gc.Suite(&userGroupsSuite{})
+
+func (s *userGroupsSuite) TestAddUserGroup(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.AddUserGroups{
+		Groups: []params.AddUserGroup{{Name: "developers", External: true}},
+	}
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(4)
+	mockFacadeCaller.EXPECT().FacadeCall("AddUserGroups", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.AddUserGroup("developers", true)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *userGroupsSuite) TestAddUserGroupNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(3)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.AddUserGroup("developers", false)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *userGroupsSuite) TestRemoveUserGroupError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.UserGroupNames{Names: []string{"developers"}}
+	results := params.ErrorResults{Results: []params.ErrorResult{{
+		Error: &params.Error{Message: `user group "developers" not found`, Code: params.CodeNotFound},
+	}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(4)
+	mockFacadeCaller.EXPECT().FacadeCall("RemoveUserGroups", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.RemoveUserGroup("developers")
+	c.Assert(err, gc.ErrorMatches, `user group "developers" not found`)
+}
+
+func (s *userGroupsSuite) TestUserGroupInfo(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	info := params.UserGroupInfo{Name: "developers", Members: []string{"bob"}, CreatedBy: "admin"}
+	results := params.UserGroupInfoResults{Results: []params.UserGroupInfoResult{{Result: &info}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(4)
+	mockFacadeCaller.EXPECT().FacadeCall("UserGroupInfo", params.UserGroupNames{}, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	groups, err := client.UserGroupInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []params.UserGroupInfo{info})
+}
+
+func (s *userGroupsSuite) TestAddUserGroupMembers(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.ModifyUserGroupMembers{Changes: []params.UserGroupMembers{{
+		Group:    "developers",
+		UserTags: []string{"user-bob", "user-mary@external"},
+	}}}
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(4)
+	mockFacadeCaller.EXPECT().FacadeCall("AddUserGroupMembers", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.AddUserGroupMembers("developers", "bob", "mary@external")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *userGroupsSuite) TestRemoveUserGroupMembersInvalidUser(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(4)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.RemoveUserGroupMembers("developers", "not/valid")
+	c.Assert(err, gc.ErrorMatches, `user name "not/valid" not valid`)
+}
+
+func (s *userGroupsSuite) TestGrantUserGroup(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	modelTag := names.NewModelTag("0701e916-3274-46e4-bd12-c31aff89cee3")
+	args := params.ModifyUserGroupAccessRequest{Changes: []params.ModifyUserGroupAccess{{
+		Group:     "developers",
+		Action:    params.GrantUserGroupAccess,
+		Access:    "write",
+		TargetTag: modelTag.String(),
+	}}}
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(4)
+	mockFacadeCaller.EXPECT().FacadeCall("ModifyUserGroupAccess", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.GrantUserGroup("developers", permission.WriteAccess, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *userGroupsSuite) TestRevokeUserGroupOffer(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.ModifyUserGroupAccessRequest{Changes: []params.ModifyUserGroupAccess{{
+		Group:    "developers",
+		Action:   params.RevokeUserGroupAccess,
+		Access:   "consume",
+		OfferURL: "fred/prod.hosted-mysql",
+	}}}
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(4)
+	mockFacadeCaller.EXPECT().FacadeCall("ModifyUserGroupAccess", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := usermanager.NewClientFromCaller(mockFacadeCaller)
+	err := client.RevokeUserGroupOffer("developers", permission.ConsumeAccess, "fred/prod.hosted-mysql")
+	c.Assert(err, jc.ErrorIsNil)
+}
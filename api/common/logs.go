@@ -40,6 +40,12 @@ type DebugLogParams struct {
 	ExcludeModule []string
 	// ExcludeLabel lists logging labels to exclude from the response.
 	ExcludeLabel []string
+	// IncludeMessage is a regular expression; if set, only log messages
+	// matching it are included in the response.
+	IncludeMessage string
+	// ExcludeMessage is a regular expression; log messages matching it
+	// are excluded from the response.
+	ExcludeMessage string
 
 	// Limit defines the maximum number of lines to return. Once this many
 	// have been sent, the socket is closed.  If zero, all filtered lines are
@@ -60,6 +66,10 @@ type DebugLogParams struct {
 	// StartTime should be a time in the past - only records with a
 	// log time on or after StartTime will be returned.
 	StartTime time.Time
+	// EndTime, if set, means only records with a log time on or before
+	// EndTime will be returned. Once EndTime has passed, no more records
+	// are sent and the connection is closed.
+	EndTime time.Time
 }
 
 func (args DebugLogParams) URLQuery() url.Values {
@@ -89,6 +99,15 @@ func (args DebugLogParams) URLQuery() url.Values {
 	if !args.StartTime.IsZero() {
 		attrs.Set("startTime", args.StartTime.Format(time.RFC3339Nano))
 	}
+	if !args.EndTime.IsZero() {
+		attrs.Set("endTime", args.EndTime.Format(time.RFC3339Nano))
+	}
+	if args.IncludeMessage != "" {
+		attrs.Set("includeMessage", args.IncludeMessage)
+	}
+	if args.ExcludeMessage != "" {
+		attrs.Set("excludeMessage", args.ExcludeMessage)
+	}
 	return attrs
 }
 
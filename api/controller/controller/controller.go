@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/common/cloudspec"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/permission"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
@@ -327,16 +328,108 @@ func (s *MigrationSpec) Validate() error {
 // but we don't need that at the client side yet (and may never) so
 // this call just supports starting one migration at a time.
 func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
+	args, err := migrationArgs(spec)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	response := params.InitiateMigrationResults{}
+	if err := c.facade.FacadeCall("InitiateMigration", args, &response); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return "", errors.New("unexpected number of results returned")
+	}
+	result := response.Results[0]
+	if result.Error != nil {
+		return "", errors.Trace(result.Error)
+	}
+	return result.MigrationId, nil
+}
+
+// MigrationPrecheckResult holds everything found when checking
+// whether a model could be migrated.
+type MigrationPrecheckResult struct {
+	// Blockers holds every problem which would prevent the
+	// migration from succeeding.
+	Blockers []coremigration.Blocker
+
+	// Warnings holds any caveats about the checks which were run.
+	Warnings []string
+}
+
+// PrecheckMigration runs the migration prechecks for the specified
+// model against the target controller without starting a migration,
+// reporting every problem found rather than just the first.
+func (c *Client) PrecheckMigration(spec MigrationSpec) (MigrationPrecheckResult, error) {
+	if c.facade.BestAPIVersion() < 12 {
+		return MigrationPrecheckResult{}, errors.NotSupportedf("migration dry run on this version of Juju")
+	}
+	args, err := migrationArgs(spec)
+	if err != nil {
+		return MigrationPrecheckResult{}, errors.Trace(err)
+	}
+	response := params.MigrationPrecheckResults{}
+	if err := c.facade.FacadeCall("PrecheckMigration", args, &response); err != nil {
+		return MigrationPrecheckResult{}, errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return MigrationPrecheckResult{}, errors.New("unexpected number of results returned")
+	}
+	result := response.Results[0]
+	if result.Error != nil {
+		return MigrationPrecheckResult{}, errors.Trace(result.Error)
+	}
+	out := MigrationPrecheckResult{
+		Warnings: result.Warnings,
+	}
+	for _, blocker := range result.Blockers {
+		out.Blockers = append(out.Blockers, coremigration.Blocker{
+			Check:   blocker.Check,
+			Entity:  blocker.Entity,
+			Message: blocker.Message,
+		})
+	}
+	return out, nil
+}
+
+// ExportModel serializes the specified model, and lists the charms,
+// agent binaries and resources it uses, so that it can be written to
+// a portable archive. The model itself is not changed.
+func (c *Client) ExportModel(modelUUID string) (coremigration.SerializedModel, error) {
+	if c.facade.BestAPIVersion() < 13 {
+		return coremigration.SerializedModel{}, errors.NotSupportedf("exporting models on this version of Juju")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewModelTag(modelUUID).String()}},
+	}
+	var response params.SerializedModelResults
+	if err := c.facade.FacadeCall("ExportModels", args, &response); err != nil {
+		return coremigration.SerializedModel{}, errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return coremigration.SerializedModel{}, errors.New("unexpected number of results returned")
+	}
+	result := response.Results[0]
+	if result.Error != nil {
+		return coremigration.SerializedModel{}, errors.Trace(result.Error)
+	}
+	if result.Result == nil {
+		return coremigration.SerializedModel{}, errors.New("missing exported model")
+	}
+	return common.ConvertSerializedModel(*result.Result)
+}
+
+func migrationArgs(spec MigrationSpec) (params.InitiateMigrationArgs, error) {
 	if err := spec.Validate(); err != nil {
-		return "", errors.Annotatef(err, "client-side validation failed")
+		return params.InitiateMigrationArgs{}, errors.Annotatef(err, "client-side validation failed")
 	}
 
 	macsJSON, err := macaroonsToJSON(spec.TargetMacaroons)
 	if err != nil {
-		return "", errors.Annotatef(err, "client-side validation failed")
+		return params.InitiateMigrationArgs{}, errors.Annotatef(err, "client-side validation failed")
 	}
 
-	args := params.InitiateMigrationArgs{
+	return params.InitiateMigrationArgs{
 		Specs: []params.MigrationSpec{{
 			ModelTag: names.NewModelTag(spec.ModelUUID).String(),
 			TargetInfo: params.MigrationTargetInfo{
@@ -349,19 +442,7 @@ func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
 				Macaroons:       macsJSON,
 			},
 		}},
-	}
-	response := params.InitiateMigrationResults{}
-	if err := c.facade.FacadeCall("InitiateMigration", args, &response); err != nil {
-		return "", errors.Trace(err)
-	}
-	if len(response.Results) != 1 {
-		return "", errors.New("unexpected number of results returned")
-	}
-	result := response.Results[0]
-	if result.Error != nil {
-		return "", errors.Trace(result.Error)
-	}
-	return result.MigrationId, nil
+	}, nil
 }
 
 func macaroonsToJSON(macs []macaroon.Slice) (string, error) {
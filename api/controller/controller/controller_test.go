@@ -12,6 +12,7 @@ import (
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/v3"
+	"github.com/juju/version/v2"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/macaroon.v2"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/juju/juju/api/controller/controller"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/core/life"
+	coremigration "github.com/juju/juju/core/migration"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
 	proxyfactory "github.com/juju/juju/proxy/factory"
 	"github.com/juju/juju/rpc/params"
@@ -171,6 +173,68 @@ func (s *Suite) TestInitiateMigrationValidationError(c *gc.C) {
 	c.Check(stub.Calls(), gc.HasLen, 0) // API call shouldn't have happened
 }
 
+func (s *Suite) TestPrecheckMigration(c *gc.C) {
+	spec := makeSpec()
+	client, stub := makePrecheckMigrationClient(12, params.MigrationPrecheckResults{
+		Results: []params.MigrationPrecheckResult{{
+			ModelTag: names.NewModelTag(spec.ModelUUID).String(),
+			Blockers: []params.MigrationBlocker{{
+				Check:   "source",
+				Entity:  "machine 0",
+				Message: "machine 0 not running (stopped)",
+			}},
+			Warnings: []string{"trial import skipped"},
+		}},
+	})
+	result, err := client.PrecheckMigration(spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, controller.MigrationPrecheckResult{
+		Blockers: []coremigration.Blocker{{
+			Check:   "source",
+			Entity:  "machine 0",
+			Message: "machine 0 not running (stopped)",
+		}},
+		Warnings: []string{"trial import skipped"},
+	})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.PrecheckMigration", []interface{}{specToArgs(spec)}},
+	})
+}
+
+func (s *Suite) TestPrecheckMigrationError(c *gc.C) {
+	client, _ := makePrecheckMigrationClient(12, params.MigrationPrecheckResults{
+		Results: []params.MigrationPrecheckResult{{
+			Error: apiservererrors.ServerError(errors.New("boom")),
+		}},
+	})
+	_, err := client.PrecheckMigration(makeSpec())
+	c.Check(err, gc.ErrorMatches, "boom")
+}
+
+func (s *Suite) TestPrecheckMigrationResultMismatch(c *gc.C) {
+	client, _ := makePrecheckMigrationClient(12, params.MigrationPrecheckResults{
+		Results: []params.MigrationPrecheckResult{{}, {}},
+	})
+	_, err := client.PrecheckMigration(makeSpec())
+	c.Check(err, gc.ErrorMatches, "unexpected number of results returned")
+}
+
+func (s *Suite) TestPrecheckMigrationValidationError(c *gc.C) {
+	client, stub := makePrecheckMigrationClient(12, params.MigrationPrecheckResults{})
+	spec := makeSpec()
+	spec.ModelUUID = "not-a-uuid"
+	_, err := client.PrecheckMigration(spec)
+	c.Check(err, gc.ErrorMatches, "client-side validation failed: model UUID not valid")
+	c.Check(stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestPrecheckMigrationNotSupported(c *gc.C) {
+	client, stub := makePrecheckMigrationClient(11, params.MigrationPrecheckResults{})
+	_, err := client.PrecheckMigration(makeSpec())
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(stub.Calls(), gc.HasLen, 0)
+}
+
 func (s *Suite) TestHostedModelConfigs_CallError(c *gc.C) {
 	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
 		return errors.New("boom")
@@ -255,6 +319,94 @@ func makeInitiateMigrationClient(results params.InitiateMigrationResults) (
 	return client, &stub
 }
 
+func (s *Suite) TestExportModel(c *gc.C) {
+	modelUUID := randomUUID()
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 13,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			out := result.(*params.SerializedModelResults)
+			*out = params.SerializedModelResults{
+				Results: []params.SerializedModelResult{{
+					Result: &params.SerializedModel{
+						Bytes:  []byte("model"),
+						Charms: []string{"ch:foo-1"},
+						Tools: []params.SerializedModelTools{{
+							Version: "2.9.0-ubuntu-amd64",
+							URI:     "/tools/2.9.0-ubuntu-amd64",
+						}},
+					},
+				}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	serialized, err := client.ExportModel(modelUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(serialized, jc.DeepEquals, coremigration.SerializedModel{
+		Bytes:  []byte("model"),
+		Charms: []string{"ch:foo-1"},
+		Tools: map[version.Binary]string{
+			version.MustParseBinary("2.9.0-ubuntu-amd64"): "/tools/2.9.0-ubuntu-amd64",
+		},
+	})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.ExportModels", []interface{}{params.Entities{
+			Entities: []params.Entity{{Tag: names.NewModelTag(modelUUID).String()}},
+		}}},
+	})
+}
+
+func (s *Suite) TestExportModelError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 13,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			out := result.(*params.SerializedModelResults)
+			*out = params.SerializedModelResults{
+				Results: []params.SerializedModelResult{{
+					Error: apiservererrors.ServerError(errors.New("boom")),
+				}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.ExportModel(randomUUID())
+	c.Check(err, gc.ErrorMatches, "boom")
+}
+
+func (s *Suite) TestExportModelNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 12,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.ExportModel(randomUUID())
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func makePrecheckMigrationClient(bestVersion int, results params.MigrationPrecheckResults) (
+	*controller.Client, *jujutesting.Stub,
+) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: bestVersion,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			out := result.(*params.MigrationPrecheckResults)
+			*out = results
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	return client, &stub
+}
+
 func makeSpec() controller.MigrationSpec {
 	mac, err := macaroon.New([]byte("secret"), []byte("id"), "location", macaroon.LatestVersion)
 	if err != nil {
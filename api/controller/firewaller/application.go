@@ -10,6 +10,7 @@ import (
 	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/rpc/params"
 )
@@ -58,3 +59,46 @@ func (s *Application) ExposeInfo() (bool, map[string]params.ExposedEndpoint, err
 	}
 	return result.Exposed, result.ExposedEndpoints, nil
 }
+
+// EgressRules returns the egress rules configured for the application.
+// An application without egress rules may reach any destination. When
+// the controller is too old to support egress rules, a NotSupported
+// error is returned.
+func (s *Application) EgressRules() (firewall.EgressRules, error) {
+	if s.st.facade.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("egress rules")
+	}
+	var results params.EgressRulesResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetEgressRules", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return nil, errors.NewNotFound(result.Error, "")
+		}
+		return nil, result.Error
+	}
+	var rules firewall.EgressRules
+	for _, rule := range result.Rules {
+		rules = append(rules, firewall.NewEgressRule(rule.DestinationCIDR, rule.PortRange.NetworkPortRange()))
+	}
+	return rules, nil
+}
+
+// WatchEgressRules returns a watcher that notifies of potential changes
+// to the application's egress rules. When the controller is too old to
+// support egress rules, a NotSupported error is returned.
+func (s *Application) WatchEgressRules() (watcher.NotifyWatcher, error) {
+	if s.st.facade.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("egress rules")
+	}
+	return common.Watch(s.st.facade, "WatchEgressRules", s.tag)
+}
@@ -7,9 +7,12 @@ import (
 	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/api/controller/firewaller"
+	coreconfig "github.com/juju/juju/core/config"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/core/watcher/watchertest"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
@@ -93,3 +96,40 @@ func (s *applicationSuite) TestExposeInfo(c *gc.C) {
 	c.Assert(isExposed, jc.IsFalse)
 	c.Assert(exposedEndpoints, gc.HasLen, 0)
 }
+
+func (s *applicationSuite) TestEgressRules(c *gc.C) {
+	rules, err := s.apiApplication.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	err = s.application.UpdateApplicationConfig(coreconfig.ConfigAttributes{
+		"egress-allow": "10.0.0.0/8:5432/tcp",
+	}, nil, environschema.Fields{
+		"egress-allow": environschema.Attr{Type: environschema.Tstring},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rules, err = s.apiApplication.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+	})
+}
+
+func (s *applicationSuite) TestWatchEgressRules(c *gc.C) {
+	w, err := s.apiApplication.WatchEgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewNotifyWatcherC(c, w)
+	defer wc.AssertStops()
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	err = s.application.UpdateApplicationConfig(coreconfig.ConfigAttributes{
+		"egress-allow": "10.0.0.0/8:5432/tcp",
+	}, nil, environschema.Fields{
+		"egress-allow": environschema.Attr{Type: environschema.Tstring},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
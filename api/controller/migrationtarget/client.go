@@ -51,6 +51,37 @@ func (c *Client) BestFacadeVersion() int {
 // Prechecks checks that the target controller is able to accept the
 // model being migrated.
 func (c *Client) Prechecks(model coremigration.ModelInfo) error {
+	args := migrationModelInfo(model)
+	return errors.Trace(c.caller.FacadeCall("Prechecks", args, nil))
+}
+
+// PrecheckReport runs the same checks as Prechecks, but returns every
+// problem which would prevent the model being migrated to the target
+// controller rather than just the first.
+func (c *Client) PrecheckReport(model coremigration.ModelInfo) ([]coremigration.Blocker, error) {
+	if c.caller.BestAPIVersion() < 4 {
+		return nil, errors.NotSupportedf("precheck report on this version of Juju")
+	}
+	args := migrationModelInfo(model)
+	var result params.MigrationPrecheckResult
+	if err := c.caller.FacadeCall("PrecheckReport", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	blockers := make([]coremigration.Blocker, len(result.Blockers))
+	for i, blocker := range result.Blockers {
+		blockers[i] = coremigration.Blocker{
+			Check:   blocker.Check,
+			Entity:  blocker.Entity,
+			Message: blocker.Message,
+		}
+	}
+	return blockers, nil
+}
+
+func migrationModelInfo(model coremigration.ModelInfo) params.MigrationModelInfo {
 	// Pass all the known facade versions to the controller so that it
 	// can check that the target controller supports them. Passing all of them
 	// ensures that we don't have to update this code when new facades are
@@ -61,7 +92,7 @@ func (c *Client) Prechecks(model coremigration.ModelInfo) error {
 		versions[name] = version
 	}
 
-	args := params.MigrationModelInfo{
+	return params.MigrationModelInfo{
 		UUID:                   model.UUID,
 		Name:                   model.Name,
 		OwnerTag:               model.Owner.String(),
@@ -69,7 +100,6 @@ func (c *Client) Prechecks(model coremigration.ModelInfo) error {
 		ControllerAgentVersion: model.ControllerAgentVersion,
 		FacadeVersions:         versions,
 	}
-	return errors.Trace(c.caller.FacadeCall("Prechecks", args, nil))
 }
 
 // Import takes a serialized model and imports it into the target
@@ -79,6 +109,17 @@ func (c *Client) Import(bytes []byte) error {
 	return errors.Trace(c.caller.FacadeCall("Import", serialized, nil))
 }
 
+// TrialImport imports a serialized model into a throwaway model on the
+// target controller, to check that it could be migrated, and then
+// removes it again.
+func (c *Client) TrialImport(bytes []byte) error {
+	if c.caller.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("trial import on this version of Juju")
+	}
+	serialized := params.SerializedModel{Bytes: bytes}
+	return errors.Trace(c.caller.FacadeCall("TrialImport", serialized, nil))
+}
+
 // Abort removes all data relating to a previously imported model.
 func (c *Client) Abort(modelUUID string) error {
 	args := params.ModelArgs{ModelTag: names.NewModelTag(modelUUID).String()}
@@ -83,6 +83,7 @@ func NewTestingState(params TestingStateParams) Connection {
 		modelTag = t
 	}
 	st := &state{
+		ctx:               context.Background(),
 		client:            params.RPCConnection,
 		clock:             params.Clock,
 		addr:              params.Address,
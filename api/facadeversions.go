@@ -32,7 +32,8 @@ var facadeVersions = facades.FacadeVersions{
 	"Application":                  {15, 16, 17, 18, 19},
 	"ApplicationOffers":            {4},
 	"ApplicationScaler":            {1},
-	"Backups":                      {3},
+	"AuditLog":                     {1},
+	"Backups":                      {3, 4},
 	"Block":                        {2},
 	"Bundle":                       {6},
 	"CAASAgent":                    {2},
@@ -53,7 +54,7 @@ var facadeVersions = facades.FacadeVersions{
 	"Cleaner":                      {2},
 	"Client":                       {6, 7},
 	"Cloud":                        {7},
-	"Controller":                   {11},
+	"Controller":                   {11, 12, 13},
 	"CredentialManager":            {1},
 	"CredentialValidator":          {2},
 	"CrossController":              {1},
@@ -66,7 +67,7 @@ var facadeVersions = facades.FacadeVersions{
 	"ExternalControllerUpdater":    {1},
 	"FanConfigurer":                {1},
 	"FilesystemAttachmentsWatcher": {2},
-	"Firewaller":                   {7},
+	"Firewaller":                   {7, 8},
 	"HighAvailability":             {2},
 	"HostKeyReporter":              {1},
 	"ImageMetadata":                {3},
@@ -91,8 +92,8 @@ var facadeVersions = facades.FacadeVersions{
 	"MigrationMaster":              {3},
 	"MigrationMinion":              {1},
 	"MigrationStatusWatcher":       {1},
-	"MigrationTarget":              {1, 2, 3},
-	"ModelConfig":                  {3},
+	"MigrationTarget":              {1, 2, 3, 4},
+	"ModelConfig":                  {3, 4},
 	"ModelGeneration":              {4},
 	"ModelManager":                 {9},
 	"ModelSummaryWatcher":          {1},
@@ -117,7 +118,7 @@ var facadeVersions = facades.FacadeVersions{
 	"SecretBackendsManager":        {1},
 	"SecretBackendsRotateWatcher":  {1},
 	"SecretsRevisionWatcher":       {1},
-	"Secrets":                      {1, 2},
+	"Secrets":                      {1, 2, 3},
 	"SecretsManager":               {1, 2},
 	"SecretsDrain":                 {1},
 	"UserSecretsDrain":             {1},
@@ -126,17 +127,17 @@ var facadeVersions = facades.FacadeVersions{
 	"Spaces":                       {6},
 	"SSHClient":                    {4},
 	"StatusHistory":                {2},
-	"Storage":                      {6},
-	"StorageProvisioner":           {4},
+	"Storage":                      {6, 7, 8},
+	"StorageProvisioner":           {4, 5, 6},
 	"StringsWatcher":               {1},
 	"Subnets":                      {5},
 	"Undertaker":                   {1},
 	"UnitAssigner":                 {1},
-	"Uniter":                       {18, 19},
+	"Uniter":                       {18, 19, 20},
 	"Upgrader":                     {1},
 	"UpgradeSeries":                {3},
 	"UpgradeSteps":                 {2},
-	"UserManager":                  {3},
+	"UserManager":                  {3, 4, 5},
 	"VolumeAttachmentsWatcher":     {2},
 	"VolumeAttachmentPlansWatcher": {1},
 }
@@ -73,6 +73,9 @@ func (doer httpRequestDoer) Do(req *http.Request) (*http.Response, error) {
 	); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if doer.st.tag == "" && doer.st.idToken != "" {
+		req.Header.Set("Authorization", "Bearer "+doer.st.idToken)
+	}
 	return doer.st.bakeryClient.DoWithCustomError(req, func(resp *http.Response) error {
 		// At this point we are only interested in errors that
 		// the bakery cares about, and the CodeDischargeRequired
@@ -76,6 +76,11 @@ type Info struct {
 	// authenticate with the API server.
 	Macaroons []macaroon.Slice `yaml:",omitempty"`
 
+	// IDToken holds an ID token issued by the controller's OpenID
+	// Connect provider, which may be used to authenticate with the
+	// API server as an external user.
+	IDToken string `yaml:",omitempty"`
+
 	// Nonce holds the nonce used when provisioning the machine. Used
 	// only by the machine agent.
 	Nonce string `yaml:",omitempty"`
@@ -122,6 +127,9 @@ func (info *Info) Validate() error {
 		if len(info.Macaroons) > 0 {
 			return errors.NotValidf("specifying Macaroons and SkipLogin")
 		}
+		if info.IDToken != "" {
+			return errors.NotValidf("specifying IDToken and SkipLogin")
+		}
 	}
 	return nil
 }
@@ -188,6 +196,10 @@ type DialOpts struct {
 	// automatically verified. If the callback returns a non-nil error then
 	// the connection attempt will be aborted.
 	VerifyCA func(host, endpoint string, caCert *x509.Certificate) error
+
+	// TraceContext, if non-nil, holds the tracer and span that API
+	// calls made over the connection are recorded with.
+	TraceContext context.Context
 }
 
 // IPAddrResolver implements a resolved from host name to the
@@ -50,6 +50,10 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 	}
 
 	if password == "" {
+		// An ID token issued by the controller's OpenID Connect
+		// provider may be used in place of a password.
+		request.IDToken = st.idToken
+
 		// Add any macaroons from the cookie jar that might work for
 		// authenticating the login request.
 		request.Macaroons = append(request.Macaroons,
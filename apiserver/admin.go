@@ -154,6 +154,15 @@ func (a *admin) login(ctx context.Context, req params.LoginRequest, loginVersion
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
+	if authResult.userLogin {
+		// Users granted roles on a model may only call the methods
+		// the roles allow, whether the model is the one they logged
+		// in to or one named in a call to the controller.
+		if !authResult.controllerOnlyLogin {
+			apiRoot = restrictRoot(apiRoot, a.root.checkRoleCapabilities)
+		}
+		apiRoot = restrictModelRoles(apiRoot, a.root.checkModelRoleCapabilities)
+	}
 
 	var facadeFilters []facadeFilterFunc
 	var modelTag string
@@ -292,6 +301,7 @@ func (a *admin) authenticate(ctx context.Context, req params.LoginRequest) (*aut
 			Credentials:   req.Credentials,
 			Nonce:         req.Nonce,
 			Token:         req.Token,
+			IDToken:       req.IDToken,
 			Macaroons:     req.Macaroons,
 			BakeryVersion: req.BakeryVersion,
 		}
@@ -46,8 +46,9 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/annotations" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/apiserver/facades/client/applicationoffers" // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/backups"           // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/block"             // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/auditlog"
+	"github.com/juju/juju/apiserver/facades/client/backups" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/block"   // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/bundle"
 	"github.com/juju/juju/apiserver/facades/client/charms"     // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/client"     // ModelUser Write
@@ -190,6 +191,7 @@ func AllFacades() *facade.Registry {
 	application.Register(registry)
 	applicationoffers.Register(registry)
 	applicationscaler.Register(registry)
+	auditlog.Register(registry)
 	backups.Register(registry)
 	block.Register(registry)
 	bundle.Register(registry)
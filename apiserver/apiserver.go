@@ -32,6 +32,8 @@ import (
 	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/authentication/jwt"
 	"github.com/juju/juju/apiserver/authentication/macaroon"
+	"github.com/juju/juju/apiserver/authentication/oidc"
+	"github.com/juju/juju/apiserver/changestream"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/apihttp"
 	"github.com/juju/juju/apiserver/common/crossmodel"
@@ -50,11 +52,13 @@ import (
 	"github.com/juju/juju/core/multiwatcher"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/core/resources"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/pubsub/apiserver"
 	controllermsg "github.com/juju/juju/pubsub/controller"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
+	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/syslogger"
 )
@@ -80,6 +84,7 @@ type Server struct {
 
 	localMacaroonAuthenticator macaroon.LocalMacaroonAuthenticator
 	jwtAuthenticator           jwt.Authenticator
+	oidcAuthenticator          oidc.Authenticator
 
 	httpAuthenticators  []authentication.HTTPAuthenticator
 	loginAuthenticators []authentication.LoginAuthenticator
@@ -96,6 +101,7 @@ type Server struct {
 	mux                    *apiserverhttp.Mux
 	metricsCollector       *Collector
 	execEmbeddedCommand    ExecEmbeddedCommandFunc
+	tracer                 trace.Tracer
 
 	// mu guards the fields below it.
 	mu sync.Mutex
@@ -149,6 +155,11 @@ type ServerConfig struct {
 	// provider.
 	JWTAuthenticator jwt.Authenticator
 
+	// OIDCAuthenticator is the request authenticator used for validating
+	// OpenID Connect ID tokens when the controller has been configured
+	// with an OIDC provider.
+	OIDCAuthenticator oidc.Authenticator
+
 	// MultiwatcherFactory is used by the API server to create
 	// multiwatchers. The real factory is managed by the multiwatcher
 	// worker.
@@ -220,6 +231,10 @@ type ServerConfig struct {
 
 	// DBGetter supplies sql.DB references on request, for named databases.
 	DBGetter coredatabase.DBGetter
+
+	// Tracer records the spans of API requests. If it is nil, requests
+	// aren't traced.
+	Tracer trace.Tracer
 }
 
 // Validate validates the API server configuration.
@@ -278,6 +293,13 @@ func (c ServerConfig) pingClock() clock.Clock {
 	return c.PingClock
 }
 
+func (c ServerConfig) tracer() trace.Tracer {
+	if c.Tracer == nil {
+		return trace.NoopTracer{}
+	}
+	return c.Tracer
+}
+
 // NewServer serves API requests using the given configuration.
 func NewServer(cfg ServerConfig) (*Server, error) {
 	if cfg.LogSinkConfig == nil {
@@ -345,6 +367,10 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		httpAuthenticators = append([]authentication.HTTPAuthenticator{cfg.JWTAuthenticator}, httpAuthenticators...)
 		loginAuthenticators = append([]authentication.LoginAuthenticator{cfg.JWTAuthenticator}, loginAuthenticators...)
 	}
+	if cfg.OIDCAuthenticator != nil {
+		httpAuthenticators = append([]authentication.HTTPAuthenticator{cfg.OIDCAuthenticator}, httpAuthenticators...)
+		loginAuthenticators = append([]authentication.LoginAuthenticator{cfg.OIDCAuthenticator}, loginAuthenticators...)
+	}
 
 	srv := &Server{
 		clock:                         cfg.Clock,
@@ -359,6 +385,7 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		mux:                           cfg.Mux,
 		localMacaroonAuthenticator:    cfg.LocalMacaroonAuthenticator,
 		jwtAuthenticator:              cfg.JWTAuthenticator,
+		oidcAuthenticator:             cfg.OIDCAuthenticator,
 		httpAuthenticators:            httpAuthenticators,
 		loginAuthenticators:           loginAuthenticators,
 		allowModelAccess:              cfg.AllowModelAccess,
@@ -379,6 +406,7 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		},
 		metricsCollector:    cfg.MetricsCollector,
 		execEmbeddedCommand: cfg.ExecEmbeddedCommand,
+		tracer:              cfg.tracer(),
 
 		healthStatus: "starting",
 	}
@@ -652,6 +680,7 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		handler         http.Handler
 		unauthenticated bool
 		authorizer      authentication.Authorizer
+		capabilities    map[string]roleCapability
 		tracked         bool
 		noModelUUID     bool
 	}
@@ -663,6 +692,7 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 	controllerModelUUID := systemState.ModelUUID()
 
 	httpAuthenticator := authentication.HTTPStrategicAuthenticator(srv.httpAuthenticators)
+	httpCtxt := httpContext{srv: srv}
 
 	addHandler := func(handler handler) {
 		methods := handler.methods
@@ -673,6 +703,13 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		if handler.tracked {
 			h = srv.trackRequests(h)
 		}
+		if handler.capabilities != nil {
+			h = &roleCapabilityHandler{
+				ctxt:         httpCtxt,
+				next:         h,
+				capabilities: handler.capabilities,
+			}
+		}
 		if !handler.unauthenticated {
 			h = &httpcontext.AuthHandler{
 				NextHandler:   h,
@@ -707,9 +744,9 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		}
 	}
 
-	httpCtxt := httpContext{srv: srv}
 	mainAPIHandler := http.HandlerFunc(srv.apiHandler)
 	healthHandler := http.HandlerFunc(srv.healthHandler)
+	oidcHandler := http.HandlerFunc(srv.oidcHandler)
 	logStreamHandler := newLogStreamEndpointHandler(httpCtxt)
 	embeddedCLIHandler := newEmbeddedCLIHandler(httpCtxt)
 	debugLogHandler := newDebugLogDBHandler(
@@ -723,6 +760,13 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		},
 	)
 	pubsubHandler := newPubSubHandler(httpCtxt, srv.shared.centralHub)
+	changeStreamHandler := changestream.NewHTTPHandler(
+		func() (coredatabase.TrackedDB, error) {
+			return srv.shared.dbGetter.GetDB(coredatabase.ControllerNS)
+		},
+		httpCtxt.stop(),
+		srv.clock,
+	)
 	logSinkHandler := logsink.NewHTTPHandler(
 		newAgentLogWriteCloserFunc(httpCtxt, srv.logSinkWriter, &srv.apiServerLoggers),
 		httpCtxt.stop(),
@@ -814,6 +858,21 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		},
 	}
 
+	// Users granted roles on a model may only make the requests the
+	// roles allow, as with API calls. The logsink endpoint, which
+	// only agents may use, has no capabilities.
+	charmsDownloadCapabilities := map[string]roleCapability{"GET": charmsDownloadCapability}
+	charmsUploadCapabilities := map[string]roleCapability{"POST": charmsUploadCapability}
+	toolsUploadCapabilities := map[string]roleCapability{"POST": toolsUploadCapability}
+	resourcesCapabilities := map[string]roleCapability{
+		"GET": resourcesDownloadCapability,
+		"PUT": resourcesUploadCapability,
+	}
+	backupsCapabilities := map[string]roleCapability{
+		"GET": backupsDownloadCapability,
+		"PUT": backupsUploadCapability,
+	}
+
 	controllerAdminAuthorizer := controllerAdminAuthorizer{
 		controllerTag: systemState.ControllerTag(),
 	}
@@ -881,32 +940,38 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		handler: modelRestServer,
 	}, {
 		// GET /charms has no authorizer
-		pattern: modelRoutePrefix + "/charms",
-		methods: []string{"GET"},
-		handler: modelCharmsHTTPHandler,
+		pattern:      modelRoutePrefix + "/charms",
+		methods:      []string{"GET"},
+		handler:      modelCharmsHTTPHandler,
+		capabilities: charmsDownloadCapabilities,
 	}, {
-		pattern:    modelRoutePrefix + "/charms",
-		methods:    []string{"POST"},
-		handler:    modelCharmsHTTPHandler,
-		authorizer: modelCharmsUploadAuthorizer,
+		pattern:      modelRoutePrefix + "/charms",
+		methods:      []string{"POST"},
+		handler:      modelCharmsHTTPHandler,
+		authorizer:   modelCharmsUploadAuthorizer,
+		capabilities: charmsUploadCapabilities,
 	}, {
-		pattern:    modelRoutePrefix + "/tools",
-		handler:    modelToolsUploadHandler,
-		authorizer: modelToolsUploadAuthorizer,
+		pattern:      modelRoutePrefix + "/tools",
+		handler:      modelToolsUploadHandler,
+		authorizer:   modelToolsUploadAuthorizer,
+		capabilities: toolsUploadCapabilities,
 	}, {
 		pattern:         modelRoutePrefix + "/tools/:version",
 		handler:         modelToolsDownloadHandler,
 		unauthenticated: true,
 	}, {
-		pattern: modelRoutePrefix + "/applications/:application/resources/:resource",
-		handler: resourcesHandler,
+		pattern:      modelRoutePrefix + "/applications/:application/resources/:resource",
+		handler:      resourcesHandler,
+		capabilities: resourcesCapabilities,
 	}, {
-		pattern: modelRoutePrefix + "/units/:unit/resources/:resource",
-		handler: unitResourcesHandler,
+		pattern:      modelRoutePrefix + "/units/:unit/resources/:resource",
+		handler:      unitResourcesHandler,
+		capabilities: resourcesCapabilities,
 	}, {
-		pattern:    modelRoutePrefix + "/backups",
-		handler:    backupHandler,
-		authorizer: controllerAdminAuthorizer,
+		pattern:      modelRoutePrefix + "/backups",
+		handler:      backupHandler,
+		authorizer:   controllerAdminAuthorizer,
+		capabilities: backupsCapabilities,
 	}, {
 		pattern:    "/migrate/charms",
 		handler:    migrateCharmsHTTPHandler,
@@ -924,6 +989,13 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		handler:    logTransferHandler,
 		tracked:    true,
 		authorizer: controllerAdminAuthorizer,
+	}, {
+		// Changes are streamed from the controller database, so the
+		// endpoint is only available to controller admins.
+		pattern:    "/changestream",
+		handler:    changeStreamHandler,
+		tracked:    true,
+		authorizer: controllerAdminAuthorizer,
 	}, {
 		pattern:         "/api",
 		handler:         mainAPIHandler,
@@ -950,14 +1022,21 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		handler:         healthHandler,
 		unauthenticated: true,
 		noModelUUID:     true,
+	}, {
+		pattern:         "/oidc",
+		methods:         []string{"GET"},
+		handler:         oidcHandler,
+		unauthenticated: true,
+		noModelUUID:     true,
 	}, {
 		pattern:         "/register",
 		handler:         registerHandler,
 		unauthenticated: true,
 	}, {
-		pattern:    "/tools",
-		handler:    modelToolsUploadHandler,
-		authorizer: modelToolsUploadAuthorizer,
+		pattern:      "/tools",
+		handler:      modelToolsUploadHandler,
+		authorizer:   modelToolsUploadAuthorizer,
+		capabilities: toolsUploadCapabilities,
 	}, {
 		pattern:         "/tools/:version",
 		handler:         modelToolsDownloadHandler,
@@ -971,18 +1050,21 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 		unauthenticated: true,
 	}, {
 		// GET /charms has no authorizer
-		pattern: "/charms",
-		methods: []string{"GET"},
-		handler: modelCharmsHTTPHandler,
+		pattern:      "/charms",
+		methods:      []string{"GET"},
+		handler:      modelCharmsHTTPHandler,
+		capabilities: charmsDownloadCapabilities,
 	}, {
-		pattern:    "/charms",
-		methods:    []string{"POST"},
-		handler:    modelCharmsHTTPHandler,
-		authorizer: modelCharmsUploadAuthorizer,
+		pattern:      "/charms",
+		methods:      []string{"POST"},
+		handler:      modelCharmsHTTPHandler,
+		authorizer:   modelCharmsUploadAuthorizer,
+		capabilities: charmsUploadCapabilities,
 	}, {
-		pattern: charmsObjectsRoutePrefix,
-		methods: []string{"GET"},
-		handler: modelObjectsCharmsHTTPHandler,
+		pattern:      charmsObjectsRoutePrefix,
+		methods:      []string{"GET"},
+		handler:      modelObjectsCharmsHTTPHandler,
+		capabilities: charmsDownloadCapabilities,
 	}}
 	if srv.registerIntrospectionHandlers != nil {
 		add := func(subpath string, h http.Handler) {
@@ -1048,6 +1130,23 @@ func (srv *Server) healthHandler(w http.ResponseWriter, req *http.Request) {
 	fmt.Fprintf(w, "%s\n", status)
 }
 
+// oidcHandler serves the details of the OpenID Connect provider that
+// users may log in with, so that clients can start the device flow.
+func (srv *Server) oidcHandler(w http.ResponseWriter, req *http.Request) {
+	if srv.oidcAuthenticator == nil {
+		if err := sendError(w, errors.NotFoundf("OIDC login")); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	if err := sendStatusAndJSON(w, http.StatusOK, params.OIDCLoginConfig{
+		IssuerURL: srv.oidcAuthenticator.IssuerURL(),
+		ClientID:  srv.oidcAuthenticator.ClientID(),
+	}); err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
 func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	srv.metricsCollector.TotalConnections.Inc()
 
@@ -1129,7 +1228,7 @@ func (srv *Server) serveConn(
 		}
 		conn.ServeRoot(newAdminRoot(h, adminAPIs), recorderFactory, serverError)
 	}
-	conn.Start(ctx)
+	conn.Start(trace.WithTracer(ctx, srv.tracer))
 	select {
 	case <-conn.Dead():
 	case <-srv.tomb.Dying():
@@ -56,6 +56,9 @@ type AuthParams struct {
 	// Token is used for rebac based auth.
 	Token string
 
+	// IDToken is used for OpenID Connect auth.
+	IDToken string
+
 	// None is used for agent auth.
 	Nonce string
 
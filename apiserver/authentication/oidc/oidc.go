@@ -0,0 +1,427 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package oidc authenticates users logging in with ID tokens issued by
+// an OpenID Connect provider.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/core/permission"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.authentication.oidc")
+
+const (
+	// keyRefreshInterval is how often the provider's signing keys are
+	// fetched again.
+	keyRefreshInterval = time.Hour
+
+	// minKeyRefreshInterval is the least time between fetches of the
+	// provider's signing keys, when a token is signed by an unknown key.
+	minKeyRefreshInterval = time.Minute
+
+	// clockSkew is the clock skew allowed when validating the times
+	// in an ID token.
+	clockSkew = time.Minute
+)
+
+// Authenticator is an authenticator of users logging in with OpenID
+// Connect ID tokens.
+type Authenticator interface {
+	authentication.RequestAuthenticator
+
+	// IssuerURL returns the URL of the trusted OpenID Connect provider.
+	IssuerURL() string
+
+	// ClientID returns the client that ID tokens must be issued to.
+	ClientID() string
+}
+
+// GroupSyncer records the groups that users are members of, as asserted
+// by an identity provider.
+type GroupSyncer interface {
+	// SyncExternalUserGroups records that the user is a member of
+	// exactly the named external groups.
+	SyncExternalUserGroups(user names.UserTag, groups []string) error
+}
+
+// Config holds the configuration of an OIDCAuthenticator.
+type Config struct {
+	// IssuerURL is the URL of the trusted OpenID Connect provider.
+	IssuerURL string
+
+	// ClientID is the client registered for the controller with the
+	// provider; ID tokens must be issued to this client.
+	ClientID string
+
+	// UsernameClaim is the ID token claim holding the name of the
+	// user. If the name has no domain, the host name of the issuer
+	// is used.
+	UsernameClaim string
+
+	// GroupsClaim is the ID token claim holding the groups the user
+	// is a member of.
+	GroupsClaim string
+
+	// GroupAccess holds the controller access granted to the members
+	// of each group.
+	GroupAccess map[string]permission.Access
+
+	// Delegator answers questions about the permissions granted to
+	// users in the controller.
+	Delegator authentication.PermissionDelegator
+
+	// GroupSyncer, if not nil, records the groups named in the
+	// GroupsClaim of each authenticated user's ID token as the
+	// external user groups they are a member of.
+	GroupSyncer GroupSyncer
+
+	// HTTPClient is used to talk to the provider.
+	HTTPClient *http.Client
+
+	// Clock is used to validate the times in ID tokens.
+	Clock clock.Clock
+}
+
+// Validate validates the authenticator configuration.
+func (c Config) Validate() error {
+	if c.IssuerURL == "" {
+		return errors.NotValidf("empty IssuerURL")
+	}
+	if _, err := url.Parse(c.IssuerURL); err != nil {
+		return errors.NotValidf("IssuerURL %q", c.IssuerURL)
+	}
+	if c.ClientID == "" {
+		return errors.NotValidf("empty ClientID")
+	}
+	if c.UsernameClaim == "" {
+		return errors.NotValidf("empty UsernameClaim")
+	}
+	for group, access := range c.GroupAccess {
+		if err := permission.ValidateControllerAccess(access); err != nil {
+			return errors.Annotatef(err, "access for group %q", group)
+		}
+	}
+	if c.Delegator == nil {
+		return errors.NotValidf("nil Delegator")
+	}
+	if c.HTTPClient == nil {
+		return errors.NotValidf("nil HTTPClient")
+	}
+	if c.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// OIDCAuthenticator authenticates users logging in with ID tokens
+// issued by an OpenID Connect provider. The provider is discovered,
+// and its signing keys fetched, when the first token is validated, so
+// that the controller stays available to other users when the
+// provider is not.
+type OIDCAuthenticator struct {
+	config Config
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      jwk.Set
+	keysFetch time.Time
+}
+
+// NewAuthenticator returns an OIDCAuthenticator with the given config.
+func NewAuthenticator(config Config) (*OIDCAuthenticator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &OIDCAuthenticator{config: config}, nil
+}
+
+// IssuerURL is part of the Authenticator interface.
+func (a *OIDCAuthenticator) IssuerURL() string {
+	return a.config.IssuerURL
+}
+
+// ClientID is part of the Authenticator interface.
+func (a *OIDCAuthenticator) ClientID() string {
+	return a.config.ClientID
+}
+
+// Authenticate implements HTTPAuthenticator, authenticating requests
+// holding an ID token as a bearer token.
+func (a *OIDCAuthenticator) Authenticate(req *http.Request) (authentication.AuthInfo, error) {
+	scheme, token, _ := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return authentication.AuthInfo{}, fmt.Errorf("bearer token %w", errors.NotFound)
+	}
+	return a.authenticate(req.Context(), token)
+}
+
+// AuthenticateLoginRequest implements LoginAuthenticator.
+func (a *OIDCAuthenticator) AuthenticateLoginRequest(
+	ctx context.Context,
+	_, _ string,
+	authParams authentication.AuthParams,
+) (authentication.AuthInfo, error) {
+	if authParams.IDToken == "" {
+		return authentication.AuthInfo{}, fmt.Errorf("ID token %w", errors.NotSupported)
+	}
+	return a.authenticate(ctx, authParams.IDToken)
+}
+
+func (a *OIDCAuthenticator) authenticate(ctx context.Context, idToken string) (authentication.AuthInfo, error) {
+	token, err := a.Parse(ctx, idToken)
+	if err != nil {
+		return authentication.AuthInfo{}, errors.NewUnauthorized(err, "invalid ID token")
+	}
+	user, err := a.userFromToken(token)
+	if err != nil {
+		return authentication.AuthInfo{}, errors.NewUnauthorized(err, "")
+	}
+	logger.Debugf("authenticated OIDC user %q", user.Id())
+	if a.config.GroupSyncer != nil && a.config.GroupsClaim != "" {
+		groups := groupsFromToken(token, a.config.GroupsClaim)
+		if err := a.config.GroupSyncer.SyncExternalUserGroups(user, groups); err != nil {
+			return authentication.AuthInfo{}, errors.Trace(err)
+		}
+	}
+	return authentication.AuthInfo{
+		Entity: authentication.TagToEntity(user),
+		Delegator: &PermissionDelegator{
+			Delegator:        a.config.Delegator,
+			User:             user,
+			ControllerAccess: a.groupAccess(token),
+		},
+	}, nil
+}
+
+// Parse verifies the signature of the ID token, and that it was issued
+// by the trusted provider to the controller's client and is current,
+// and returns the token.
+func (a *OIDCAuthenticator) Parse(ctx context.Context, idToken string) (jwt.Token, error) {
+	keys, err := a.keySet(ctx, false)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	token, err := a.parse(idToken, keys)
+	var validationErr jwt.ValidationError
+	if err != nil && !errors.As(err, &validationErr) {
+		// The token may be signed by a key the provider has
+		// rotated in since the keys were fetched.
+		if keys, kerr := a.keySet(ctx, true); kerr == nil {
+			token, err = a.parse(idToken, keys)
+		}
+	}
+	return token, errors.Trace(err)
+}
+
+func (a *OIDCAuthenticator) parse(idToken string, keys jwk.Set) (jwt.Token, error) {
+	return jwt.Parse(
+		[]byte(idToken),
+		jwt.WithKeySet(keys, jws.WithInferAlgorithmFromKey(true)),
+		jwt.WithIssuer(a.config.IssuerURL),
+		jwt.WithAudience(a.config.ClientID),
+		jwt.WithClock(jwt.ClockFunc(a.config.Clock.Now)),
+		jwt.WithAcceptableSkew(clockSkew),
+	)
+}
+
+// keySet returns the provider's signing keys, discovering the provider
+// and fetching the keys if necessary. If refresh is true, the keys are
+// fetched again unless they were fetched very recently.
+func (a *OIDCAuthenticator) keySet(ctx context.Context, refresh bool) (jwk.Set, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.jwksURL == "" {
+		jwksURL, err := a.discover(ctx)
+		if err != nil {
+			return nil, errors.Annotatef(err, "discovering OIDC provider %q", a.config.IssuerURL)
+		}
+		a.jwksURL = jwksURL
+	}
+
+	age := a.config.Clock.Now().Sub(a.keysFetch)
+	if a.keys != nil && age < keyRefreshInterval && (!refresh || age < minKeyRefreshInterval) {
+		return a.keys, nil
+	}
+	keys, err := jwk.Fetch(ctx, a.jwksURL, jwk.WithHTTPClient(a.config.HTTPClient))
+	if err != nil {
+		if a.keys != nil {
+			logger.Warningf("cannot refresh OIDC provider keys: %v", err)
+			return a.keys, nil
+		}
+		return nil, errors.Annotatef(err, "fetching OIDC provider keys from %q", a.jwksURL)
+	}
+	a.keys = keys
+	a.keysFetch = a.config.Clock.Now()
+	return keys, nil
+}
+
+// discover returns the URL of the provider's signing keys, from its
+// discovery document.
+func (a *OIDCAuthenticator) discover(ctx context.Context) (string, error) {
+	discoveryURL := strings.TrimSuffix(a.config.IssuerURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	resp, err := a.config.HTTPClient.Do(req)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("%s: %s", discoveryURL, resp.Status)
+	}
+
+	var doc struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", errors.Annotatef(err, "decoding %s", discoveryURL)
+	}
+	if doc.Issuer != a.config.IssuerURL {
+		return "", errors.Errorf("provider claims to be issuer %q", doc.Issuer)
+	}
+	if doc.JWKSURI == "" {
+		return "", errors.Errorf("provider has no jwks_uri")
+	}
+	return doc.JWKSURI, nil
+}
+
+// userFromToken returns the user named in the ID token. OIDC users are
+// always external users; names without a domain are given the host
+// name of the issuer as their domain.
+//
+// An email address only identifies a user if the provider vouches for
+// it, so tokens named by their email claim must carry an
+// "email_verified" claim which is true. Providers which don't verify
+// email addresses should name users by their subject instead.
+func (a *OIDCAuthenticator) userFromToken(token jwt.Token) (names.UserTag, error) {
+	if token.Subject() == "" {
+		return names.UserTag{}, errors.Errorf("ID token has no subject")
+	}
+	claim := a.config.UsernameClaim
+	value, _ := token.Get(claim)
+	name, _ := value.(string)
+	if name == "" {
+		return names.UserTag{}, errors.Errorf("ID token has no %q claim", claim)
+	}
+	if claim == "email" {
+		if verified, _ := token.PrivateClaims()["email_verified"].(bool); !verified {
+			return names.UserTag{}, errors.Errorf("email address %q has not been verified", name)
+		}
+	}
+	if !strings.Contains(name, "@") {
+		issuer, err := url.Parse(a.config.IssuerURL)
+		if err != nil {
+			return names.UserTag{}, errors.Trace(err)
+		}
+		name += "@" + issuer.Hostname()
+	}
+	if !names.IsValidUser(name) {
+		return names.UserTag{}, errors.NotValidf("user name %q in ID token", name)
+	}
+	user := names.NewUserTag(name)
+	if user.IsLocal() {
+		return names.UserTag{}, errors.Errorf("OIDC provider has provided ostensibly local name %q", name)
+	}
+	return user, nil
+}
+
+// groupAccess returns the greatest controller access granted to the
+// groups named in the ID token.
+func (a *OIDCAuthenticator) groupAccess(token jwt.Token) permission.Access {
+	result := permission.NoAccess
+	for _, group := range groupsFromToken(token, a.config.GroupsClaim) {
+		if access, ok := a.config.GroupAccess[group]; ok && access.GreaterControllerAccessThan(result) {
+			result = access
+		}
+	}
+	return result
+}
+
+// groupsFromToken returns the groups in the given claim, which may
+// hold a list of groups or a single group.
+func groupsFromToken(token jwt.Token, claim string) []string {
+	if claim == "" {
+		return nil
+	}
+	value, ok := token.Get(claim)
+	if !ok {
+		return nil
+	}
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		groups := make([]string, 0, len(value))
+		for _, v := range value {
+			if group, ok := v.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
+// PermissionDelegator answers permission questions about a user
+// authenticated by an OIDCAuthenticator. It implements the
+// authentication.PermissionDelegator interface.
+type PermissionDelegator struct {
+	// Delegator answers questions about the permissions granted to
+	// users in the controller.
+	Delegator authentication.PermissionDelegator
+
+	// User is the authenticated user.
+	User names.UserTag
+
+	// ControllerAccess is the controller access granted to the user
+	// by the groups they are a member of.
+	ControllerAccess permission.Access
+}
+
+// SubjectPermissions implements PermissionDelegator. The controller
+// access of the authenticated user is the greater of that granted to
+// the user in the controller, and that granted to their groups.
+func (p *PermissionDelegator) SubjectPermissions(
+	e authentication.Entity,
+	subject names.Tag,
+) (permission.Access, error) {
+	access, err := p.Delegator.SubjectPermissions(e, subject)
+	if subject.Kind() != names.ControllerTagKind || e.Tag() != names.Tag(p.User) {
+		return access, err
+	}
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return access, err
+	}
+	if p.ControllerAccess.GreaterControllerAccessThan(access) {
+		return p.ControllerAccess, nil
+	}
+	return access, err
+}
+
+// PermissionError implements PermissionDelegator.
+func (p *PermissionDelegator) PermissionError(subject names.Tag, perm permission.Access) error {
+	return p.Delegator.PermissionError(subject, perm)
+}
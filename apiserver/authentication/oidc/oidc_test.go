@@ -0,0 +1,364 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/authentication/oidc"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	apitesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type oidcSuite struct {
+	testing.IsolationSuite
+
+	issuer    *apitesting.FakeOIDCIssuer
+	delegator *fakeDelegator
+}
+
+var _ = gc.Suite(&oidcSuite{})
+
+func (s *oidcSuite) SetUpSuite(c *gc.C) {
+	s.IsolationSuite.SetUpSuite(c)
+	issuer, err := apitesting.NewFakeOIDCIssuer("juju")
+	c.Assert(err, jc.ErrorIsNil)
+	s.issuer = issuer
+}
+
+func (s *oidcSuite) TearDownSuite(c *gc.C) {
+	s.issuer.Close()
+	s.IsolationSuite.TearDownSuite(c)
+}
+
+func (s *oidcSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.delegator = &fakeDelegator{access: make(map[names.Tag]permission.Access)}
+}
+
+func (s *oidcSuite) newAuthenticator(c *gc.C, configure ...func(*oidc.Config)) *oidc.OIDCAuthenticator {
+	config := oidc.Config{
+		IssuerURL:     s.issuer.URL(),
+		ClientID:      "juju",
+		UsernameClaim: "email",
+		GroupsClaim:   "groups",
+		GroupAccess: map[string]permission.Access{
+			"juju-admins": permission.SuperuserAccess,
+			"devs":        permission.LoginAccess,
+		},
+		Delegator:  s.delegator,
+		HTTPClient: s.issuer.Client(),
+		Clock:      clock.WallClock,
+	}
+	for _, f := range configure {
+		f(&config)
+	}
+	authenticator, err := oidc.NewAuthenticator(config)
+	c.Assert(err, jc.ErrorIsNil)
+	return authenticator
+}
+
+func (s *oidcSuite) login(c *gc.C, authenticator *oidc.OIDCAuthenticator, claims map[string]interface{}) (authentication.AuthInfo, error) {
+	idToken, err := s.issuer.IDToken(claims)
+	c.Assert(err, jc.ErrorIsNil)
+	return authenticator.AuthenticateLoginRequest(context.Background(), "", "", authentication.AuthParams{IDToken: idToken})
+}
+
+func (s *oidcSuite) TestValidateConfig(c *gc.C) {
+	_, err := oidc.NewAuthenticator(oidc.Config{IssuerURL: s.issuer.URL()})
+	c.Assert(err, gc.ErrorMatches, "empty ClientID not valid")
+
+	_, err = oidc.NewAuthenticator(oidc.Config{
+		IssuerURL:     s.issuer.URL(),
+		ClientID:      "juju",
+		UsernameClaim: "email",
+		GroupAccess:   map[string]permission.Access{"devs": permission.WriteAccess},
+	})
+	c.Assert(err, gc.ErrorMatches, `access for group "devs": "write" controller access not valid`)
+}
+
+func (s *oidcSuite) TestLoginNotSupportedWithoutIDToken(c *gc.C) {
+	_, err := s.newAuthenticator(c).AuthenticateLoginRequest(context.Background(), "", "", authentication.AuthParams{Token: "jwt"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *oidcSuite) TestLogin(c *gc.C) {
+	authInfo, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authInfo.Entity.Tag(), gc.Equals, names.NewUserTag("alice@example.com"))
+}
+
+func (s *oidcSuite) TestLoginUsernameWithoutDomain(c *gc.C) {
+	authenticator := s.newAuthenticator(c, func(config *oidc.Config) {
+		config.UsernameClaim = "preferred_username"
+	})
+	authInfo, err := s.login(c, authenticator, map[string]interface{}{
+		"sub":                "f6a1c5b2",
+		"preferred_username": "alice",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authInfo.Entity.Tag(), gc.Equals, names.NewUserTag("alice@127.0.0.1"))
+}
+
+func (s *oidcSuite) TestLoginSubject(c *gc.C) {
+	authenticator := s.newAuthenticator(c, func(config *oidc.Config) {
+		config.UsernameClaim = "sub"
+	})
+	authInfo, err := s.login(c, authenticator, map[string]interface{}{
+		"sub": "f6a1c5b2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authInfo.Entity.Tag(), gc.Equals, names.NewUserTag("f6a1c5b2@127.0.0.1"))
+}
+
+func (s *oidcSuite) TestLoginMissingUsernameClaim(c *gc.C) {
+	_, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{"sub": "f6a1c5b2"})
+	c.Assert(err, gc.ErrorMatches, `ID token has no "email" claim`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *oidcSuite) TestLoginUnverifiedEmail(c *gc.C) {
+	_, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": false,
+	})
+	c.Assert(err, gc.ErrorMatches, `email address "alice@example.com" has not been verified`)
+}
+
+func (s *oidcSuite) TestLoginEmailVerificationUnknown(c *gc.C) {
+	_, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":   "f6a1c5b2",
+		"email": "alice@example.com",
+	})
+	c.Assert(err, gc.ErrorMatches, `email address "alice@example.com" has not been verified`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *oidcSuite) TestLoginMissingSubject(c *gc.C) {
+	_, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"email":          "alice@example.com",
+		"email_verified": true,
+	})
+	c.Assert(err, gc.ErrorMatches, `ID token has no subject`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *oidcSuite) TestLoginLocalUser(c *gc.C) {
+	_, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "admin@local",
+		"email_verified": true,
+	})
+	c.Assert(err, gc.ErrorMatches, `OIDC provider has provided ostensibly local name "admin@local"`)
+}
+
+func (s *oidcSuite) TestLoginWrongAudience(c *gc.C) {
+	_, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"aud":            []string{"another-client"},
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid ID token: .*"aud" not satisfied.*`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *oidcSuite) TestLoginWrongIssuer(c *gc.C) {
+	_, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"iss":            "https://evil.example.com",
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid ID token: .*"iss" not satisfied.*`)
+}
+
+func (s *oidcSuite) TestLoginExpiredToken(c *gc.C) {
+	_, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"exp":            time.Now().Add(-time.Hour).Unix(),
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid ID token: .*"exp" not satisfied.*`)
+}
+
+func (s *oidcSuite) TestLoginBadSignature(c *gc.C) {
+	other, err := apitesting.NewFakeOIDCIssuer("juju")
+	c.Assert(err, jc.ErrorIsNil)
+	defer other.Close()
+	idToken, err := other.IDToken(map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"iss":            s.issuer.URL(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.newAuthenticator(c).AuthenticateLoginRequest(context.Background(), "", "", authentication.AuthParams{IDToken: idToken})
+	c.Assert(err, gc.ErrorMatches, `invalid ID token: .*`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *oidcSuite) TestLoginProviderUnavailable(c *gc.C) {
+	authenticator := s.newAuthenticator(c, func(config *oidc.Config) {
+		config.IssuerURL = "https://127.0.0.1:1"
+	})
+	_, err := s.login(c, authenticator, map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid ID token: discovering OIDC provider "https://127.0.0.1:1": .*`)
+}
+
+func (s *oidcSuite) TestGroupAccess(c *gc.C) {
+	authenticator := s.newAuthenticator(c)
+	alice := names.NewUserTag("alice@example.com")
+	model := names.NewModelTag(coretesting.ModelTag.Id())
+	s.delegator.access[model] = permission.WriteAccess
+
+	authInfo, err := s.login(c, authenticator, map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          alice.Id(),
+		"email_verified": true,
+		"groups":         []string{"devs", "juju-admins", "testers"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := authInfo.SubjectPermissions(coretesting.ControllerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.SuperuserAccess)
+
+	// Other subjects are answered by the controller.
+	access, err = authInfo.SubjectPermissions(model)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	// Group access only applies to the authenticated user.
+	access, err = authInfo.Delegator.SubjectPermissions(
+		authentication.TagToEntity(names.NewUserTag("bob@example.com")), coretesting.ControllerTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(access, gc.Equals, permission.NoAccess)
+}
+
+func (s *oidcSuite) TestGroupAccessDoesNotLowerControllerAccess(c *gc.C) {
+	s.delegator.access[coretesting.ControllerTag] = permission.SuperuserAccess
+	authInfo, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"groups":         "devs",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := authInfo.SubjectPermissions(coretesting.ControllerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.SuperuserAccess)
+}
+
+func (s *oidcSuite) TestNoGroupAccess(c *gc.C) {
+	authInfo, err := s.login(c, s.newAuthenticator(c), map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = authInfo.SubjectPermissions(coretesting.ControllerTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *oidcSuite) TestSyncGroups(c *gc.C) {
+	syncer := &fakeGroupSyncer{}
+	authenticator := s.newAuthenticator(c, func(config *oidc.Config) {
+		config.GroupSyncer = syncer
+	})
+	_, err := s.login(c, authenticator, map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"groups":         []string{"devs", "testers"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	syncer.CheckCalls(c, []testing.StubCall{{
+		FuncName: "SyncExternalUserGroups",
+		Args:     []interface{}{names.NewUserTag("alice@example.com"), []string{"devs", "testers"}},
+	}})
+}
+
+func (s *oidcSuite) TestSyncGroupsError(c *gc.C) {
+	syncer := &fakeGroupSyncer{}
+	syncer.SetErrors(errors.New("boom"))
+	authenticator := s.newAuthenticator(c, func(config *oidc.Config) {
+		config.GroupSyncer = syncer
+	})
+	_, err := s.login(c, authenticator, map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *oidcSuite) TestAuthenticateHTTPRequest(c *gc.C) {
+	authenticator := s.newAuthenticator(c)
+	req, err := http.NewRequest("GET", "/charms", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = authenticator.Authenticate(req)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	idToken, err := s.issuer.IDToken(map[string]interface{}{
+		"sub":            "f6a1c5b2",
+		"email":          "alice@example.com",
+		"email_verified": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("Authorization", "Bearer "+idToken)
+	authInfo, err := authenticator.Authenticate(req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authInfo.Entity.Tag(), gc.Equals, names.NewUserTag("alice@example.com"))
+}
+
+// fakeDelegator answers permission questions from a map of the access
+// granted to every user.
+type fakeDelegator struct {
+	access map[names.Tag]permission.Access
+}
+
+func (d *fakeDelegator) SubjectPermissions(_ authentication.Entity, subject names.Tag) (permission.Access, error) {
+	access, ok := d.access[subject]
+	if !ok {
+		return permission.NoAccess, errors.NotFoundf("access to %s", subject)
+	}
+	return access, nil
+}
+
+func (d *fakeDelegator) PermissionError(names.Tag, permission.Access) error {
+	return apiservererrors.ErrPerm
+}
+
+// fakeGroupSyncer records the external groups each user logs in with.
+type fakeGroupSyncer struct {
+	testing.Stub
+}
+
+func (f *fakeGroupSyncer) SyncExternalUserGroups(user names.UserTag, groups []string) error {
+	f.MethodCall(f, "SyncExternalUserGroups", user, groups)
+	return f.NextErr()
+}
@@ -0,0 +1,14 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package oidc_test
+
+import (
+	. "testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+func TestPackage(t *T) {
+	gc.TestingT(t)
+}
@@ -74,7 +74,7 @@ func (s *backupsSuite) checkInvalidMethod(c *gc.C, method, url string) {
 
 func (s *backupsSuite) TestInvalidHTTPMethods(c *gc.C) {
 	url := s.backupURL
-	for _, method := range []string{"PUT", "POST", "DELETE", "OPTIONS"} {
+	for _, method := range []string{"POST", "DELETE", "OPTIONS"} {
 		c.Log("testing HTTP method: " + method)
 		s.checkInvalidMethod(c, method, url)
 	}
@@ -164,3 +164,49 @@ func (s *backupsSuite) TestErrorWhenGetFails(c *gc.C) {
 
 	s.assertErrorResponse(c, resp, http.StatusInternalServerError, "failed!")
 }
+
+func (s *backupsSuite) sendValidPut(c *gc.C) *http.Response {
+	archive, err := backupstesting.NewArchiveBasic(backupstesting.NewMetadata())
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.Filename = "/tmp/juju-backup-uploaded-20231010-101010.tar.gz"
+
+	return s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:      "PUT",
+		URL:         s.backupURL,
+		ContentType: params.ContentTypeRaw,
+		Body:        archive,
+	})
+}
+
+func (s *backupsSuite) TestUpload(c *gc.C) {
+	resp := s.sendValidPut(c)
+	defer resp.Body.Close()
+
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	var result params.BackupsUploadResult
+	err := json.NewDecoder(resp.Body).Decode(&result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ID, gc.Equals, "/tmp/juju-backup-uploaded-20231010-101010.tar.gz")
+	c.Check(s.fake.Calls, gc.DeepEquals, []string{"Add"})
+}
+
+func (s *backupsSuite) TestUploadRequiresRawContent(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:      "PUT",
+		URL:         s.backupURL,
+		ContentType: params.ContentTypeJSON,
+		JSONBody:    params.BackupsDownloadArgs{},
+	})
+	defer resp.Body.Close()
+
+	s.assertErrorResponse(c, resp, http.StatusInternalServerError, `expected Content-Type "application/octet-stream", got "application/json"`)
+	c.Check(s.fake.Calls, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestErrorWhenUploadFails(c *gc.C) {
+	s.fake.Error = errors.New("failed!")
+	resp := s.sendValidPut(c)
+	defer resp.Body.Close()
+
+	s.assertErrorResponse(c, resp, http.StatusInternalServerError, "failed!")
+}
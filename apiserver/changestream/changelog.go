@@ -0,0 +1,97 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package changestream
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/changestream"
+	coredatabase "github.com/juju/juju/core/database"
+)
+
+const (
+	headQuery = `SELECT COALESCE(MAX(id), 0) FROM change_log;`
+
+	// Unlike the internal change stream, the changes are not coalesced
+	// so that every change has a position the client can resume from.
+	changesQuery = `
+SELECT c.id, c.edit_type_id, t.edit_type, n.namespace, c.changed_uuid, c.created_at
+	FROM change_log c
+		JOIN change_log_edit_type t ON c.edit_type_id = t.id
+		JOIN change_log_namespace n ON c.namespace_id = n.id
+	WHERE c.id > ?
+	ORDER BY c.id
+	LIMIT ?;
+`
+)
+
+// createdAtLayouts are the layouts in which change log times may be
+// returned, depending on the database driver.
+var createdAtLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+}
+
+type change struct {
+	id          int64
+	changeType  changestream.ChangeType
+	editType    string
+	namespace   string
+	changedUUID string
+	createdAt   time.Time
+}
+
+// readHead returns the ID of the most recent change in the change log.
+func readHead(ctx context.Context, db coredatabase.TrackedDB) (int64, error) {
+	var head int64
+	err := db.Txn(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return errors.Trace(tx.QueryRowContext(ctx, headQuery).Scan(&head))
+	})
+	return head, errors.Annotate(err, "reading change log head")
+}
+
+// readChanges returns up to limit changes with IDs greater than
+// after, in ID order.
+func readChanges(ctx context.Context, db coredatabase.TrackedDB, after int64, limit int) ([]change, error) {
+	var changes []change
+	err := db.Txn(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		// The transaction may be retried.
+		changes = nil
+
+		rows, err := tx.QueryContext(ctx, changesQuery, after, limit)
+		if err != nil {
+			return errors.Annotate(err, "querying for changes")
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				c         change
+				createdAt string
+			)
+			if err := rows.Scan(&c.id, &c.changeType, &c.editType, &c.namespace, &c.changedUUID, &createdAt); err != nil {
+				return errors.Annotate(err, "scanning change")
+			}
+			if c.createdAt, err = parseCreatedAt(createdAt); err != nil {
+				return errors.Trace(err)
+			}
+			changes = append(changes, c)
+		}
+		return errors.Trace(rows.Err())
+	})
+	return changes, errors.Trace(err)
+}
+
+func parseCreatedAt(value string) (time.Time, error) {
+	for _, layout := range createdAtLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.NotValidf("change time %q", value)
+}
@@ -0,0 +1,293 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package changestream
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/schema"
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/juju/clock"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/featureflag"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/websocket"
+	"github.com/juju/juju/core/changestream"
+	coredatabase "github.com/juju/juju/core/database"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/rpc/params"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.changestream")
+
+const (
+	// PollInterval is how long the handler waits between reads of the
+	// change log once it has caught up.
+	PollInterval = time.Second
+
+	// maxBatchSize is the maximum number of changes read from the
+	// change log at once.
+	maxBatchSize = 100
+)
+
+// permittedNamespaces holds the change log namespaces which may be
+// streamed to clients outside of the controller. Namespaces are only
+// added here once the changes recorded in them are safe to expose.
+var permittedNamespaces = set.NewStrings(
+	"external_controller",
+)
+
+// changeTypes maps the names of the types of change to their values.
+var changeTypes = map[string]changestream.ChangeType{
+	"create": changestream.Create,
+	"update": changestream.Update,
+	"delete": changestream.Delete,
+}
+
+// GetDBFunc returns the controller database.
+type GetDBFunc func() (coredatabase.TrackedDB, error)
+
+// NewHTTPHandler returns a new http.Handler which streams the changes
+// recorded in the controller database's change log over a websocket.
+func NewHTTPHandler(getDB GetDBFunc, abort <-chan struct{}, clock clock.Clock) http.Handler {
+	return &changeStreamHandler{
+		getDB:        getDB,
+		abort:        abort,
+		clock:        clock,
+		pollInterval: PollInterval,
+	}
+}
+
+type changeStreamHandler struct {
+	getDB        GetDBFunc
+	abort        <-chan struct{}
+	clock        clock.Clock
+	pollInterval time.Duration
+}
+
+// ServeHTTP will serve up connections as a websocket for the
+// changestream API.
+//
+// Args for the HTTP request are as follows:
+//
+//	namespace -> []string - the namespaces to stream changes from
+//	type -> []string - one or more of [create, update, delete]
+//	after -> int - only stream changes with a greater ID
+func (h *changeStreamHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	handler := func(socket *websocket.Conn) {
+		defer socket.Close()
+
+		stream, err := h.newStream(req)
+		if err != nil {
+			h.sendError(socket, req, err)
+			return
+		}
+
+		// If we get to here, no more errors to report, so we report a nil
+		// error.  This way the first line of the socket is always a json
+		// formatted simple error.
+		h.sendError(socket, req, nil)
+
+		if err := h.serve(socket, stream); err != nil {
+			logger.Errorf("change stream error: %v", err)
+		}
+	}
+	websocket.Serve(w, req, handler)
+}
+
+func (h *changeStreamHandler) newStream(req *http.Request) (*changeStream, error) {
+	var cfg params.ChangeStreamConfig
+	if err := schema.NewDecoder().Decode(&cfg, req.URL.Query()); err != nil {
+		return nil, errors.Annotate(err, "decoding schema")
+	}
+
+	namespaces := permittedNamespaces
+	if len(cfg.Namespaces) > 0 {
+		namespaces = set.NewStrings()
+		for _, ns := range cfg.Namespaces {
+			if !permittedNamespaces.Contains(ns) {
+				return nil, errors.Forbiddenf("streaming namespace %q not permitted", ns)
+			}
+			namespaces.Add(ns)
+		}
+	}
+
+	mask := changestream.Create | changestream.Update | changestream.Delete
+	if len(cfg.Types) > 0 {
+		mask = 0
+		for _, name := range cfg.Types {
+			changeType, ok := changeTypes[name]
+			if !ok {
+				return nil, errors.NotValidf("change type %q", name)
+			}
+			mask |= changeType
+		}
+	}
+
+	db, err := h.getDB()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting controller database")
+	}
+
+	stream := &changeStream{
+		db:         db,
+		namespaces: namespaces,
+		mask:       mask,
+	}
+	if cfg.After != nil {
+		if *cfg.After < 0 {
+			return nil, errors.NotValidf("negative position %d", *cfg.After)
+		}
+		stream.position = *cfg.After
+		return stream, nil
+	}
+	if stream.position, err = readHead(req.Context(), db); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The client doesn't know where the stream starts, so it is told
+	// before any changes are sent.
+	stream.sendPosition = true
+	return stream, nil
+}
+
+func (h *changeStreamHandler) serve(socket *websocket.Conn, stream *changeStream) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Here we configure the ping/pong handling for the websocket so
+	// the server can notice when the client goes away.
+	// See the long note in logsink.go for the rationale.
+	_ = socket.SetReadDeadline(time.Now().Add(websocket.PongDelay))
+	socket.SetPongHandler(func(string) error {
+		_ = socket.SetReadDeadline(time.Now().Add(websocket.PongDelay))
+		return nil
+	})
+	ticker := time.NewTicker(websocket.PingPeriod)
+	defer ticker.Stop()
+
+	closed := h.receiveClose(socket)
+
+	if stream.sendPosition {
+		if err := socket.WriteJSON(params.ChangeStreamEvents{
+			Position: stream.position,
+		}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	timer := h.clock.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-h.abort:
+			return nil
+		case <-closed:
+			return nil
+		case <-ticker.C:
+			deadline := time.Now().Add(websocket.WriteWait)
+			if err := socket.WriteControl(gorillaws.PingMessage, []byte{}, deadline); err != nil {
+				// This error is expected if the other end goes away. By
+				// returning we close the socket through the defer call.
+				logger.Debugf("failed to write ping: %s", err)
+				return nil
+			}
+			continue
+		case <-timer.Chan():
+		}
+
+		batch, more, err := stream.next(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(batch.Events) > 0 {
+			if err := socket.WriteJSON(batch); err != nil {
+				return errors.Trace(err)
+			}
+		}
+
+		// Keep reading straight away until we've caught up with the
+		// change log.
+		if more {
+			timer.Reset(0)
+		} else {
+			timer.Reset(h.pollInterval)
+		}
+	}
+}
+
+// receiveClose returns a channel which is closed when the client
+// closes the websocket. Clients are not expected to send anything
+// else.
+func (h *changeStreamHandler) receiveClose(socket *websocket.Conn) <-chan struct{} {
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			// NextReader blocks until data arrives but will also be
+			// unblocked when the handler calls socket.Close as it
+			// finishes.
+			if _, _, err := socket.NextReader(); err != nil {
+				logger.Tracef("websocket closed: %v", err)
+				return
+			}
+		}
+	}()
+	return closed
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *changeStreamHandler) sendError(ws *websocket.Conn, req *http.Request, err error) {
+	// There is no need to log the error for normal operators as there is nothing
+	// they can action. This is for developers.
+	if err != nil && featureflag.Enabled(feature.DeveloperMode) {
+		logger.Errorf("returning error from %s %s: %s", req.Method, req.URL.Path, errors.Details(err))
+	}
+	if sendErr := ws.SendInitialErrorV0(err); sendErr != nil {
+		logger.Errorf("closing websocket, %v", err)
+		ws.Close()
+	}
+}
+
+// changeStream tracks a client's position in the change log.
+type changeStream struct {
+	db         coredatabase.TrackedDB
+	namespaces set.Strings
+	mask       changestream.ChangeType
+
+	position     int64
+	sendPosition bool
+}
+
+// next reads the next batch of changes the client is interested in,
+// and reports whether more changes are waiting to be read.
+func (s *changeStream) next(ctx context.Context) (params.ChangeStreamEvents, bool, error) {
+	changes, err := readChanges(ctx, s.db, s.position, maxBatchSize)
+	if err != nil {
+		return params.ChangeStreamEvents{}, false, errors.Trace(err)
+	}
+	var events []params.ChangeStreamEvent
+	for _, change := range changes {
+		// The position moves past the changes that are filtered out
+		// too, so they aren't read again.
+		s.position = change.id
+		if !s.namespaces.Contains(change.namespace) || s.mask&change.changeType == 0 {
+			continue
+		}
+		events = append(events, params.ChangeStreamEvent{
+			ID:          change.id,
+			Type:        change.editType,
+			Namespace:   change.namespace,
+			ChangedUUID: change.changedUUID,
+			Timestamp:   change.createdAt,
+		})
+	}
+	return params.ChangeStreamEvents{
+		Events:   events,
+		Position: s.position,
+	}, len(changes) == maxBatchSize, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package changestream_test
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/changestream"
+	"github.com/juju/juju/apiserver/websocket/websockettest"
+	coredatabase "github.com/juju/juju/core/database"
+	databasetesting "github.com/juju/juju/database/testing"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type changeStreamSuite struct {
+	databasetesting.ControllerSuite
+
+	abort chan struct{}
+	srv   *httptest.Server
+}
+
+var _ = gc.Suite(&changeStreamSuite{})
+
+func (s *changeStreamSuite) SetUpTest(c *gc.C) {
+	s.ControllerSuite.SetUpTest(c)
+
+	_, err := s.DB().Exec(`INSERT INTO change_log_namespace VALUES (2, 'secret');`)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.abort = make(chan struct{})
+	handler := changestream.NewHTTPHandlerForTest(
+		func() (coredatabase.TrackedDB, error) { return s.TrackedDB(), nil },
+		s.abort,
+		clock.WallClock,
+		10*time.Millisecond,
+	)
+	s.srv = httptest.NewServer(handler)
+	s.AddCleanup(func(*gc.C) {
+		close(s.abort)
+		s.srv.Close()
+	})
+}
+
+func (s *changeStreamSuite) addChange(c *gc.C, editType, namespace int, uuid string) {
+	_, err := s.DB().Exec(`
+INSERT INTO change_log (edit_type_id, namespace_id, changed_uuid) VALUES (?, ?, ?);
+`[1:], editType, namespace, uuid)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *changeStreamSuite) dial(c *gc.C, values url.Values) *websocket.Conn {
+	u, err := url.Parse(s.srv.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	u.Scheme = "ws"
+	u.RawQuery = values.Encode()
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { conn.Close() })
+	return conn
+}
+
+func (s *changeStreamSuite) open(c *gc.C, values url.Values) *websocket.Conn {
+	conn := s.dial(c, values)
+	websockettest.AssertJSONInitialErrorNil(c, conn)
+	return conn
+}
+
+func (s *changeStreamSuite) read(c *gc.C, conn *websocket.Conn) params.ChangeStreamEvents {
+	err := conn.SetReadDeadline(time.Now().Add(coretesting.LongWait))
+	c.Assert(err, jc.ErrorIsNil)
+	var batch params.ChangeStreamEvents
+	err = conn.ReadJSON(&batch)
+	c.Assert(err, jc.ErrorIsNil)
+	for i, event := range batch.Events {
+		c.Check(event.Timestamp.IsZero(), jc.IsFalse)
+		batch.Events[i].Timestamp = time.Time{}
+	}
+	return batch
+}
+
+func (s *changeStreamSuite) assertNoBatch(c *gc.C, conn *websocket.Conn) {
+	err := conn.SetReadDeadline(time.Now().Add(coretesting.ShortWait))
+	c.Assert(err, jc.ErrorIsNil)
+	var batch params.ChangeStreamEvents
+	err = conn.ReadJSON(&batch)
+	c.Assert(err, gc.NotNil, gc.Commentf("unexpected batch %+v", batch))
+}
+
+func (s *changeStreamSuite) TestStreamAfterPosition(c *gc.C) {
+	s.addChange(c, 1, 1, "uuid-1")
+	s.addChange(c, 2, 1, "uuid-1")
+	s.addChange(c, 4, 1, "uuid-2")
+
+	conn := s.open(c, url.Values{"after": {"0"}})
+	c.Assert(s.read(c, conn), jc.DeepEquals, params.ChangeStreamEvents{
+		Events: []params.ChangeStreamEvent{
+			{ID: 1, Type: "create", Namespace: "external_controller", ChangedUUID: "uuid-1"},
+			{ID: 2, Type: "update", Namespace: "external_controller", ChangedUUID: "uuid-1"},
+			{ID: 3, Type: "delete", Namespace: "external_controller", ChangedUUID: "uuid-2"},
+		},
+		Position: 3,
+	})
+	s.assertNoBatch(c, conn)
+}
+
+func (s *changeStreamSuite) TestResume(c *gc.C) {
+	s.addChange(c, 1, 1, "uuid-1")
+	s.addChange(c, 2, 1, "uuid-1")
+	s.addChange(c, 4, 1, "uuid-1")
+
+	conn := s.open(c, url.Values{"after": {"2"}})
+	c.Assert(s.read(c, conn), jc.DeepEquals, params.ChangeStreamEvents{
+		Events: []params.ChangeStreamEvent{
+			{ID: 3, Type: "delete", Namespace: "external_controller", ChangedUUID: "uuid-1"},
+		},
+		Position: 3,
+	})
+}
+
+func (s *changeStreamSuite) TestStreamFromHead(c *gc.C) {
+	s.addChange(c, 1, 1, "uuid-1")
+
+	conn := s.open(c, nil)
+	c.Assert(s.read(c, conn), jc.DeepEquals, params.ChangeStreamEvents{Position: 1})
+
+	s.addChange(c, 2, 1, "uuid-1")
+	c.Assert(s.read(c, conn), jc.DeepEquals, params.ChangeStreamEvents{
+		Events: []params.ChangeStreamEvent{
+			{ID: 2, Type: "update", Namespace: "external_controller", ChangedUUID: "uuid-1"},
+		},
+		Position: 2,
+	})
+}
+
+func (s *changeStreamSuite) TestFiltersChanges(c *gc.C) {
+	s.addChange(c, 1, 1, "uuid-1")
+	s.addChange(c, 2, 2, "uuid-2")
+	s.addChange(c, 2, 1, "uuid-1")
+	s.addChange(c, 4, 1, "uuid-1")
+
+	// Changes in namespaces which aren't permitted are never streamed,
+	// but the position still moves past them.
+	conn := s.open(c, url.Values{"after": {"0"}, "type": {"update"}})
+	c.Assert(s.read(c, conn), jc.DeepEquals, params.ChangeStreamEvents{
+		Events: []params.ChangeStreamEvent{
+			{ID: 3, Type: "update", Namespace: "external_controller", ChangedUUID: "uuid-1"},
+		},
+		Position: 4,
+	})
+}
+
+func (s *changeStreamSuite) TestBatches(c *gc.C) {
+	for i := 0; i < 150; i++ {
+		s.addChange(c, 2, 1, "uuid-1")
+	}
+
+	conn := s.open(c, url.Values{"after": {"0"}, "namespace": {"external_controller"}})
+	batch := s.read(c, conn)
+	c.Assert(batch.Events, gc.HasLen, 100)
+	c.Assert(batch.Position, gc.Equals, int64(100))
+	batch = s.read(c, conn)
+	c.Assert(batch.Events, gc.HasLen, 50)
+	c.Assert(batch.Events[0].ID, gc.Equals, int64(101))
+	c.Assert(batch.Position, gc.Equals, int64(150))
+}
+
+func (s *changeStreamSuite) TestForbiddenNamespace(c *gc.C) {
+	conn := s.dial(c, url.Values{"namespace": {"secret"}})
+	websockettest.AssertJSONError(c, conn, `streaming namespace "secret" not permitted`)
+	websockettest.AssertWebsocketClosed(c, conn)
+}
+
+func (s *changeStreamSuite) TestInvalidType(c *gc.C) {
+	conn := s.dial(c, url.Values{"type": {"rename"}})
+	websockettest.AssertJSONError(c, conn, `change type "rename" not valid`)
+	websockettest.AssertWebsocketClosed(c, conn)
+}
+
+func (s *changeStreamSuite) TestNegativePosition(c *gc.C) {
+	conn := s.dial(c, url.Values{"after": {"-1"}})
+	websockettest.AssertJSONError(c, conn, `negative position -1 not valid`)
+	websockettest.AssertWebsocketClosed(c, conn)
+}
+
+func (s *changeStreamSuite) TestDBError(c *gc.C) {
+	handler := changestream.NewHTTPHandlerForTest(
+		func() (coredatabase.TrackedDB, error) { return nil, errors.New("boom") },
+		s.abort,
+		clock.WallClock,
+		10*time.Millisecond,
+	)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	s.srv = srv
+
+	conn := s.dial(c, nil)
+	websockettest.AssertJSONError(c, conn, `getting controller database: boom`)
+}
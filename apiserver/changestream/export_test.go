@@ -0,0 +1,20 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package changestream
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/clock"
+)
+
+func NewHTTPHandlerForTest(getDB GetDBFunc, abort <-chan struct{}, clock clock.Clock, pollInterval time.Duration) http.Handler {
+	return &changeStreamHandler{
+		getDB:        getDB,
+		abort:        abort,
+		clock:        clock,
+		pollInterval: pollInterval,
+	}
+}
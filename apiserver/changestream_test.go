@@ -0,0 +1,84 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	jujuhttp "github.com/juju/http/v2"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/websocket/websockettest"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/testing/factory"
+)
+
+type changeStreamSuite struct {
+	apiserverBaseSuite
+	userTag  names.UserTag
+	password string
+	url      string
+}
+
+var _ = gc.Suite(&changeStreamSuite{})
+
+func (s *changeStreamSuite) SetUpTest(c *gc.C) {
+	s.apiserverBaseSuite.SetUpTest(c)
+	s.password = "jabberwocky"
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: s.password})
+	s.userTag = u.Tag().(names.UserTag)
+
+	url := s.URL("/changestream", nil)
+	url.Scheme = "wss"
+	s.url = url.String()
+}
+
+func (s *changeStreamSuite) checkAuthFails(c *gc.C, header http.Header, code int, message string) {
+	_, resp, err := dialWebsocketFromURL(c, s.url, header)
+	c.Assert(err, gc.Equals, websocket.ErrBadHandshake)
+	defer resp.Body.Close()
+
+	c.Assert(resp.StatusCode, gc.Equals, code)
+	body, err := io.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(body), gc.Matches, message+"\n")
+}
+
+func (s *changeStreamSuite) TestRejectsNonControllerAdmins(c *gc.C) {
+	header := jujuhttp.BasicAuthHeader(s.userTag.String(), s.password)
+	s.checkAuthFails(c, header, http.StatusForbidden, "authorization failed: user .* is not a controller admin")
+}
+
+func (s *changeStreamSuite) TestRejectsMachineLogins(c *gc.C) {
+	m, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "nonce",
+	})
+	header := jujuhttp.BasicAuthHeader(m.Tag().String(), password)
+	header.Add(params.MachineNonceHeader, "nonce")
+	s.checkAuthFails(c, header, http.StatusForbidden, "authorization failed: machine 0 is not a user")
+}
+
+func (s *changeStreamSuite) TestRejectsBadPassword(c *gc.C) {
+	header := jujuhttp.BasicAuthHeader(s.userTag.String(), "wrong")
+	s.checkAuthFails(c, header, http.StatusUnauthorized, "authentication failed: invalid entity name or password")
+}
+
+func (s *changeStreamSuite) TestRejectsForbiddenNamespace(c *gc.C) {
+	_, err := s.State.SetUserAccess(s.userTag, s.State.ControllerTag(), permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	url := s.URL("/changestream", map[string][]string{"namespace": {"lease"}})
+	url.Scheme = "wss"
+	header := jujuhttp.BasicAuthHeader(s.userTag.String(), s.password)
+	conn, _, err := dialWebsocketFromURL(c, url.String(), header)
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	websockettest.AssertJSONError(c, conn, `streaming namespace "lease" not permitted`)
+	websockettest.AssertWebsocketClosed(c, conn)
+}
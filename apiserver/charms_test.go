@@ -149,6 +149,43 @@ func (s *charmsSuite) TestPOSTRequiresUserAuth(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, ".*expected Content-Type: application/zip.+")
 }
 
+func (s *charmsSuite) TestPOSTRequiresRoleCapability(c *gc.C) {
+	user, password := s.makeRoleUser(c, "Application.*", "Charms.Download")
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Tag:         user.String(),
+		Password:    password,
+		Method:      "POST",
+		URL:         s.charmsURI(""),
+		ContentType: "foo/bar",
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusForbidden, "text/plain; charset=utf-8")
+	c.Assert(string(body), gc.Equals, "authorization failed: Charms.Upload not allowed by role: permission denied\n")
+}
+
+func (s *charmsSuite) TestPOSTAllowedByRoleCapability(c *gc.C) {
+	user, password := s.makeRoleUser(c, "Charms.*")
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Tag:         user.String(),
+		Password:    password,
+		Method:      "POST",
+		URL:         s.charmsURI(""),
+		ContentType: "foo/bar",
+	})
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, ".*expected Content-Type: application/zip.+")
+}
+
+func (s *charmsSuite) TestGETRequiresRoleCapability(c *gc.C) {
+	user, password := s.makeRoleUser(c, "Charms.Upload")
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Tag:      user.String(),
+		Password: password,
+		Method:   "GET",
+		URL:      s.charmsURI("?url=local:quantal/dummy-1&file=revision"),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusForbidden, "text/plain; charset=utf-8")
+	c.Assert(string(body), gc.Equals, "authorization failed: Charms.Download not allowed by role: permission denied\n")
+}
+
 func (s *charmsSuite) TestUploadFailsWithInvalidZip(c *gc.C) {
 	var empty bytes.Buffer
 
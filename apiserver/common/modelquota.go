@@ -0,0 +1,44 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/quota"
+)
+
+// ModelQuotaBackend provides the state needed to enforce a model's
+// resource quotas.
+type ModelQuotaBackend interface {
+	// ModelQuotas returns the resource quotas of the model.
+	ModelQuotas() (quota.ModelResources, error)
+
+	// ModelResourceUsage returns the resources currently
+	// consumed by the model.
+	ModelResourceUsage() (quota.ModelResources, error)
+}
+
+// NewModelQuotaChecker returns a checker for the resources requested of
+// the model, preloaded with the model's quotas and current usage. If the
+// model has no quotas, NewModelQuotaChecker returns nil, in which case
+// no checks need to be made.
+//
+// The check is made before any change is made to the model, so that a
+// request is refused as a whole. Concurrent requests may each pass it;
+// state checks each change again when it is committed.
+func NewModelQuotaChecker(backend ModelQuotaBackend) (*quota.ModelQuotaChecker, error) {
+	quotas, err := backend.ModelQuotas()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if quotas.IsZero() {
+		return nil, nil
+	}
+	usage, err := backend.ModelResourceUsage()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return quota.NewModelQuotaChecker(quotas, usage), nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+
+	coresecrets "github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/secrets/provider"
+)
+
+// controllerContentBackend returns a backend client for the specified
+// backend if its secret content is only accessed by the controller,
+// or nil if clients access the backend themselves.
+func controllerContentBackend(cfgInfo *provider.ModelBackendConfigInfo, backendID string) (provider.SecretsBackend, error) {
+	cfg, ok := cfgInfo.Configs[backendID]
+	if !ok {
+		return nil, errors.NotFoundf("secret backend %q", backendID)
+	}
+	p, err := GetProvider(cfg.BackendType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !provider.HasControllerContent(p) {
+		return nil, nil
+	}
+	backend, err := p.NewBackend(&cfg)
+	return backend, errors.Trace(err)
+}
+
+// ControllerContent returns the content of a secret revision stored in
+// a backend whose content is only accessed by the controller, so that
+// it can be served to a client which may read the secret. It returns
+// nil if the revision is stored elsewhere, in which case the client
+// reads the content itself.
+func ControllerContent(adminConfigGetter BackendAdminConfigGetter, valueRef *coresecrets.ValueRef) (coresecrets.SecretValue, error) {
+	if valueRef == nil {
+		return nil, nil
+	}
+	cfgInfo, err := adminConfigGetter()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	backend, err := controllerContentBackend(cfgInfo, valueRef.BackendID)
+	if err != nil || backend == nil {
+		return nil, errors.Trace(err)
+	}
+	val, err := backend.GetContent(context.TODO(), valueRef.RevisionID)
+	return val, errors.Trace(err)
+}
+
+// SaveControllerContent saves secret content passed through the API by
+// a client to the model's active backend, if that backend's content is
+// only accessed by the controller. It returns nil if the content is to
+// be stored in the controller database as usual.
+func SaveControllerContent(
+	adminConfigGetter BackendAdminConfigGetter, uri *coresecrets.URI, revision int, data coresecrets.SecretData,
+) (*coresecrets.ValueRef, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	cfgInfo, err := adminConfigGetter()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	backend, err := controllerContentBackend(cfgInfo, cfgInfo.ActiveID)
+	if err != nil || backend == nil {
+		return nil, errors.Trace(err)
+	}
+	revisionID, err := backend.SaveContent(context.TODO(), uri, revision, coresecrets.NewSecretValue(data))
+	if err != nil {
+		return nil, errors.Annotatef(err, "saving content for secret %q", uri.ID)
+	}
+	return &coresecrets.ValueRef{
+		BackendID:  cfgInfo.ActiveID,
+		RevisionID: revisionID,
+	}, nil
+}
+
+// DeleteControllerContent deletes content saved by SaveControllerContent,
+// for when the secret could not be updated to refer to it.
+func DeleteControllerContent(adminConfigGetter BackendAdminConfigGetter, valueRef *coresecrets.ValueRef) {
+	if valueRef == nil {
+		return
+	}
+	cfgInfo, err := adminConfigGetter()
+	if err == nil {
+		var backend provider.SecretsBackend
+		backend, err = controllerContentBackend(cfgInfo, valueRef.BackendID)
+		if err == nil && backend != nil {
+			err = backend.DeleteContent(context.TODO(), valueRef.RevisionID)
+		}
+	}
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		logger.Warningf("cleaning up secret content %q: %v", valueRef.RevisionID, err)
+	}
+}
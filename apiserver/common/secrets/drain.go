@@ -26,6 +26,8 @@ type SecretsDrainAPI struct {
 	model           Model
 	secretsState    SecretsMetaState
 	secretsConsumer SecretsConsumer
+
+	adminConfigGetter BackendAdminConfigGetter
 }
 
 // NewSecretsDrainAPI returns a new SecretsDrainAPI.
@@ -48,6 +50,9 @@ func NewSecretsDrainAPI(
 		model:             model,
 		secretsState:      secretsState,
 		secretsConsumer:   secretsConsumer,
+		adminConfigGetter: func() (*secretsprovider.ModelBackendConfigInfo, error) {
+			return AdminBackendConfigInfo(model)
+		},
 	}, nil
 }
 
@@ -107,7 +112,25 @@ func (s *SecretsDrainAPI) changeSecretBackendForOne(arg params.ChangeSecretBacke
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return s.secretsState.ChangeSecretBackend(toChangeSecretBackendParams(token, uri, arg))
+	// Content drained to a backend which only the controller accesses
+	// is passed in by the drain worker, and saved here.
+	valueRef, err := SaveControllerContent(s.adminConfigGetter, uri, arg.Revision, arg.Content.Data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if valueRef != nil {
+		arg.Content = params.SecretContentParams{
+			ValueRef: &params.SecretValueRef{
+				BackendID:  valueRef.BackendID,
+				RevisionID: valueRef.RevisionID,
+			},
+		}
+	}
+	err = s.secretsState.ChangeSecretBackend(toChangeSecretBackendParams(token, uri, arg))
+	if err != nil {
+		DeleteControllerContent(s.adminConfigGetter, valueRef)
+	}
+	return errors.Trace(err)
 }
 
 func toChangeSecretBackendParams(token leadership.Token, uri *coresecrets.URI, arg params.ChangeSecretBackendArg) state.ChangeSecretBackendParams {
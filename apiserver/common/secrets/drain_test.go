@@ -4,6 +4,7 @@
 package secrets_test
 
 import (
+	"context"
 	"time"
 
 	"github.com/juju/errors"
@@ -20,6 +21,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/secrets/provider"
+	"github.com/juju/juju/secrets/provider/file"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -79,6 +81,19 @@ func (s *secretsDrainSuite) setup(c *gc.C) *gomock.Controller {
 		s.secretsConsumer,
 	)
 	c.Assert(err, jc.ErrorIsNil)
+	secrets.SetDrainAdminConfigGetter(s.facade, func() (*provider.ModelBackendConfigInfo, error) {
+		return &provider.ModelBackendConfigInfo{
+			ActiveID: "backend-id",
+			Configs: map[string]provider.ModelBackendConfig{
+				"backend-id": {
+					ControllerUUID: coretesting.ControllerTag.Id(),
+					ModelUUID:      coretesting.ModelTag.Id(),
+					ModelName:      "fred",
+					BackendConfig:  provider.BackendConfig{BackendType: "some-backend"},
+				},
+			},
+		}, nil
+	})
 	return ctrl
 }
 
@@ -328,6 +343,67 @@ func (s *secretsDrainSuite) TestChangeSecretBackend(c *gc.C) {
 	})
 }
 
+func (s *secretsDrainSuite) TestChangeSecretBackendControllerContent(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	// The content of secrets drained to the file backend is passed in
+	// by the drain worker, which has no access to the backend.
+	key, err := file.NewKey()
+	c.Assert(err, jc.ErrorIsNil)
+	fileConfig := provider.ModelBackendConfig{
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		ModelUUID:      coretesting.ModelTag.Id(),
+		ModelName:      "fred",
+		BackendConfig: provider.BackendConfig{
+			BackendType: file.BackendType,
+			Config:      map[string]interface{}{"path": c.MkDir(), "key": key},
+		},
+	}
+	secrets.SetDrainAdminConfigGetter(s.facade, func() (*provider.ModelBackendConfigInfo, error) {
+		return &provider.ModelBackendConfigInfo{
+			ActiveID: "file-backend-id",
+			Configs:  map[string]provider.ModelBackendConfig{"file-backend-id": fileConfig},
+		}, nil
+	})
+	s.PatchValue(&secrets.GetProvider, provider.Provider)
+
+	s.expectSecretAccessQuery(2)
+	uri := coresecrets.NewURI()
+	s.secretsMetaState.EXPECT().ChangeSecretBackend(
+		state.ChangeSecretBackendParams{
+			Token:    s.token,
+			URI:      uri,
+			Revision: 888,
+			ValueRef: &coresecrets.ValueRef{
+				BackendID:  "file-backend-id",
+				RevisionID: uri.ID + "-888",
+			},
+		},
+	).Return(nil)
+	s.leadership.EXPECT().LeadershipCheck("mariadb", "mariadb/0").Return(s.token)
+	s.token.EXPECT().Check().Return(nil)
+
+	result, err := s.facade.ChangeSecretBackend(params.ChangeSecretBackendArgs{
+		Args: []params.ChangeSecretBackendArg{{
+			URI:      uri.String(),
+			Revision: 888,
+			Content: params.SecretContentParams{
+				Data: map[string]string{"foo": "YmFy"},
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+
+	backend, err := file.NewProvider().NewBackend(&fileConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	val, err := backend.GetContent(context.Background(), uri.ID+"-888")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(val.EncodedValues(), jc.DeepEquals, map[string]string{"foo": "YmFy"})
+}
+
 func (s *secretsDrainSuite) TestWatchSecretBackendChanged(c *gc.C) {
 	defer s.setup(c).Finish()
 
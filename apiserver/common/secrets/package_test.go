@@ -23,3 +23,7 @@ func TestPackage(t *testing.T) {
 var (
 	NewSecretBackendModelConfigWatcher = newSecretBackendModelConfigWatcher
 )
+
+func SetDrainAdminConfigGetter(api *SecretsDrainAPI, getter BackendAdminConfigGetter) {
+	api.adminConfigGetter = getter
+}
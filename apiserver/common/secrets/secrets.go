@@ -544,6 +544,7 @@ func GetSecretMetadata(
 // RemoveSecretsForAgent removes the specified secrets for agent.
 // The secrets are only removed from the state and
 // the caller must have permission to manage the secret(secret owners remove secrets from the backend on uniter side).
+// Content in backends which only the controller accesses is removed here.
 func RemoveSecretsForAgent(
 	removeState SecretsRemoveState, adminConfigGetter BackendAdminConfigGetter,
 	args params.DeleteSecretArgs,
@@ -554,7 +555,20 @@ func RemoveSecretsForAgent(
 		removeState, adminConfigGetter, args,
 		modelUUID,
 		canDelete,
-		func(provider.SecretBackendProvider, provider.ModelBackendConfig, provider.SecretRevisions) error {
+		func(p provider.SecretBackendProvider, cfg provider.ModelBackendConfig, revs provider.SecretRevisions) error {
+			if !provider.HasControllerContent(p) {
+				return nil
+			}
+			backend, err := p.NewBackend(&cfg)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for _, revId := range revs.RevisionIDs() {
+				err = backend.DeleteContent(context.TODO(), revId)
+				if err != nil && !errors.Is(err, errors.NotFound) {
+					return errors.Trace(err)
+				}
+			}
 			return nil
 		},
 	)
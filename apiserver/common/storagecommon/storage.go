@@ -45,6 +45,14 @@ type VolumeAccess interface {
 	BlockDevices(names.MachineTag) ([]state.BlockDeviceInfo, error)
 }
 
+// VolumeSnapshotAccess is an interface for obtaining information
+// about volume snapshots.
+type VolumeSnapshotAccess interface {
+	// VolumeSnapshot returns the state.VolumeSnapshot with the
+	// specified ID.
+	VolumeSnapshot(string) (state.VolumeSnapshot, error)
+}
+
 // FilesystemAccess is an interface for obtaining information about
 // filesystem storage instances and related entities.
 type FilesystemAccess interface {
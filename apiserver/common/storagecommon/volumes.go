@@ -50,15 +50,37 @@ func VolumeParams(
 		return params.VolumeParams{}, errors.Trace(err)
 	}
 	return params.VolumeParams{
-		v.Tag().String(),
-		size,
-		string(providerType),
-		cfg.Attrs(),
-		volumeTags,
-		nil, // attachment params set by the caller
+		VolumeTag:  v.Tag().String(),
+		Size:       size,
+		Provider:   string(providerType),
+		Attributes: cfg.Attrs(),
+		Tags:       volumeTags,
+		// Attachment and SnapshotId are set by the caller.
 	}, nil
 }
 
+// VolumeSnapshotId returns the provider ID of the volume snapshot from
+// which the given volume should be created, or the empty string if the
+// volume is not to be created from a snapshot.
+func VolumeSnapshotId(v state.Volume, snapshots VolumeSnapshotAccess) (string, error) {
+	stateVolumeParams, ok := v.Params()
+	if !ok || stateVolumeParams.Snapshot == "" {
+		return "", nil
+	}
+	snapshot, err := snapshots.VolumeSnapshot(stateVolumeParams.Snapshot)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if snapshot.Life() != state.Alive {
+		return "", errors.Errorf("volume snapshot %q is not alive", snapshot.Id())
+	}
+	info, err := snapshot.Info()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return info.SnapshotId, nil
+}
+
 // StoragePoolConfig returns the storage provider type and
 // configuration for a named storage pool. If there is no
 // such pool with the specified name, but it identifies a
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"syscall"
 	"time"
@@ -102,6 +103,10 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			socket.sendError(errors.Annotate(err, "authorization failed"))
 			return
 		}
+		if err := h.ctxt.checkRoleCapability(req, authInfo, debugLogCapability); err != nil {
+			socket.sendError(errors.Annotate(err, "authorization failed"))
+			return
+		}
 
 		st, err := h.ctxt.stateForRequestUnauthenticated(req)
 		if err != nil {
@@ -181,18 +186,21 @@ func (s *debugLogSocketImpl) sendLogRecord(record *params.LogMessage) error {
 
 // debugLogParams contains the parsed debuglog API request parameters.
 type debugLogParams struct {
-	startTime     time.Time
-	maxLines      uint
-	fromTheStart  bool
-	noTail        bool
-	backlog       uint
-	filterLevel   loggo.Level
-	includeEntity []string
-	excludeEntity []string
-	includeModule []string
-	excludeModule []string
-	includeLabel  []string
-	excludeLabel  []string
+	startTime      time.Time
+	endTime        time.Time
+	maxLines       uint
+	fromTheStart   bool
+	noTail         bool
+	backlog        uint
+	filterLevel    loggo.Level
+	includeEntity  []string
+	excludeEntity  []string
+	includeModule  []string
+	excludeModule  []string
+	includeLabel   []string
+	excludeLabel   []string
+	includeMessage *regexp.Regexp
+	excludeMessage *regexp.Regexp
 }
 
 func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
@@ -248,6 +256,33 @@ func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
 		params.startTime = startTime
 	}
 
+	if value := queryMap.Get("endTime"); value != "" {
+		endTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return params, errors.Errorf("end time %q is not a valid time in RFC3339 format", value)
+		}
+		if !params.startTime.IsZero() && endTime.Before(params.startTime) {
+			return params, errors.Errorf("end time %q is before the start time", value)
+		}
+		params.endTime = endTime
+	}
+
+	if value := queryMap.Get("includeMessage"); value != "" {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return params, errors.Errorf("includeMessage value %q is not a valid regular expression: %v", value, err)
+		}
+		params.includeMessage = re
+	}
+
+	if value := queryMap.Get("excludeMessage"); value != "" {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return params, errors.Errorf("excludeMessage value %q is not a valid regular expression: %v", value, err)
+		}
+		params.excludeMessage = re
+	}
+
 	params.includeEntity = queryMap["includeEntity"]
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
@@ -33,6 +33,19 @@ func handleDebugLogDBRequest(
 	stop <-chan struct{},
 ) error {
 	tailerParams := makeLogTailerParams(reqParams)
+
+	// If the requested time window is still open, stop sending records
+	// once it closes. If it has already closed, there's no point in
+	// tailing for new records at all.
+	var windowClosed <-chan time.Time
+	if !reqParams.endTime.IsZero() {
+		if remaining := reqParams.endTime.Sub(clock.Now()); remaining > 0 {
+			windowClosed = clock.After(remaining)
+		} else {
+			tailerParams.NoTail = true
+		}
+	}
+
 	tailer, err := newLogTailer(st, tailerParams)
 	if err != nil {
 		return errors.Trace(err)
@@ -51,10 +64,15 @@ func handleDebugLogDBRequest(
 			return nil
 		case <-timeout:
 			return nil
+		case <-windowClosed:
+			return nil
 		case rec, ok := <-tailer.Logs():
 			if !ok {
 				return errors.Annotate(tailer.Err(), "tailer stopped")
 			}
+			if !includeLogRecord(reqParams, rec) {
+				continue
+			}
 
 			if err := socket.sendLogRecord(formatLogRecord(rec)); err != nil {
 				return errors.Annotate(err, "sending failed")
@@ -73,6 +91,7 @@ func makeLogTailerParams(reqParams debugLogParams) corelogger.LogTailerParams {
 		MinLevel:      reqParams.filterLevel,
 		NoTail:        reqParams.noTail,
 		StartTime:     reqParams.startTime,
+		EndTime:       reqParams.endTime,
 		InitialLines:  int(reqParams.backlog),
 		IncludeEntity: reqParams.includeEntity,
 		ExcludeEntity: reqParams.excludeEntity,
@@ -87,6 +106,21 @@ func makeLogTailerParams(reqParams debugLogParams) corelogger.LogTailerParams {
 	return tailerParams
 }
 
+// includeLogRecord reports whether rec passes the filters in reqParams
+// which the log tailer doesn't apply itself.
+func includeLogRecord(reqParams debugLogParams, rec *corelogger.LogRecord) bool {
+	if !reqParams.endTime.IsZero() && rec.Time.After(reqParams.endTime) {
+		return false
+	}
+	if reqParams.includeMessage != nil && !reqParams.includeMessage.MatchString(rec.Message) {
+		return false
+	}
+	if reqParams.excludeMessage != nil && reqParams.excludeMessage.MatchString(rec.Message) {
+		return false
+	}
+	return true
+}
+
 func formatLogRecord(r *corelogger.LogRecord) *params.LogMessage {
 	return &params.LogMessage{
 		Entity:    r.Entity,
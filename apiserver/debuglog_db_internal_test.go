@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/juju/clock/testclock"
@@ -37,11 +38,13 @@ func (s *debugLogDBIntSuite) SetUpTest(c *gc.C) {
 
 func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 	t1 := time.Date(2016, 11, 30, 10, 51, 0, 0, time.UTC)
+	t2 := time.Date(2016, 11, 30, 12, 51, 0, 0, time.UTC)
 	reqParams := debugLogParams{
 		fromTheStart:  false,
 		noTail:        true,
 		backlog:       11,
 		startTime:     t1,
+		endTime:       t2,
 		filterLevel:   loggo.INFO,
 		includeEntity: []string{"foo"},
 		includeModule: []string{"bar"},
@@ -58,6 +61,7 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		// Start time will be used once the client is extended to send
 		// time range arguments.
 		c.Assert(params.StartTime, gc.Equals, t1)
+		c.Assert(params.EndTime, gc.Equals, t2)
 		c.Assert(params.NoTail, jc.IsTrue)
 		c.Assert(params.MinLevel, gc.Equals, loggo.INFO)
 		c.Assert(params.InitialLines, gc.Equals, 11)
@@ -222,6 +226,93 @@ func (s *debugLogDBIntSuite) TestMaxLines(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestMessageFilters(c *gc.C) {
+	tailer := newFakeLogTailer()
+	for _, msg := range []string{"hook failed", "hook succeeded", "hook failed: debug", "stuff happened"} {
+		tailer.logsCh <- &corelogger.LogRecord{
+			Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+			Entity:   "machine-99",
+			Module:   "some.where",
+			Location: "code.go:42",
+			Level:    loggo.INFO,
+			Message:  msg,
+		}
+	}
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params corelogger.LogTailerParams) (corelogger.LogTailer, error) {
+		return tailer, nil
+	})
+
+	stop := make(chan struct{})
+	done := s.runRequest(debugLogParams{
+		includeMessage: regexp.MustCompile(`^hook`),
+		excludeMessage: regexp.MustCompile(`debug$`),
+	}, stop)
+
+	s.assertOutput(c, []string{
+		"ok",
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 hook failed\n",
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 hook succeeded\n",
+	})
+	s.assertNoOutput(c)
+
+	close(stop)
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestEndTimeInPast(c *gc.C) {
+	endTime := s.clock.Now().Add(-time.Hour)
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &corelogger.LogRecord{
+		Time:     endTime.Add(-time.Minute),
+		Entity:   "machine-99",
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "in window",
+	}
+	tailer.logsCh <- &corelogger.LogRecord{
+		Time:     endTime.Add(time.Minute),
+		Entity:   "machine-99",
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "too late",
+	}
+	close(tailer.logsCh)
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params corelogger.LogTailerParams) (corelogger.LogTailer, error) {
+		c.Check(params.EndTime, gc.Equals, endTime)
+		c.Check(params.NoTail, jc.IsTrue)
+		return tailer, nil
+	})
+
+	done := s.runRequest(debugLogParams{endTime: endTime}, nil)
+
+	s.assertOutput(c, []string{
+		"ok",
+		fmt.Sprintf("machine-99: %s INFO some.where code.go:42 in window\n", s.sock.formatTime(endTime.Add(-time.Minute))),
+	})
+	s.assertNoOutput(c)
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestEndTimeInFuture(c *gc.C) {
+	tailer := newFakeLogTailer()
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params corelogger.LogTailerParams) (corelogger.LogTailer, error) {
+		c.Check(params.NoTail, jc.IsFalse)
+		return tailer, nil
+	})
+
+	done := s.runRequest(debugLogParams{endTime: s.clock.Now().Add(time.Minute)}, nil)
+	s.assertOutput(c, []string{"ok"})
+
+	err := s.clock.WaitAdvance(30*time.Second, coretesting.LongWait, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRunning(c, done, tailer)
+
+	s.clock.Advance(30 * time.Second)
+	s.assertStops(c, done, tailer)
+}
+
 func (s *debugLogDBIntSuite) runRequest(params debugLogParams, stop chan struct{}) chan error {
 	done := make(chan error)
 	go func() {
@@ -242,6 +333,14 @@ func (s *debugLogDBIntSuite) assertOutput(c *gc.C, expectedWrites []string) {
 	}
 }
 
+func (s *debugLogDBIntSuite) assertNoOutput(c *gc.C) {
+	select {
+	case actualWrite := <-s.sock.writes:
+		c.Errorf("unexpected socket write: %q", actualWrite)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
 func (s *debugLogDBIntSuite) assertStops(c *gc.C, done chan error, tailer *fakeLogTailer) {
 	select {
 	case err := <-done:
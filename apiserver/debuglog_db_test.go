@@ -35,6 +35,25 @@ func (s *debugLogDBSuite) TestBadParams(c *gc.C) {
 	websockettest.AssertWebsocketClosed(c, conn)
 }
 
+func (s *debugLogDBSuite) TestBadMessageFilter(c *gc.C) {
+	conn := s.dialWebsocket(c, url.Values{"includeMessage": {"hook ("}})
+	defer conn.Close()
+
+	websockettest.AssertJSONError(c, conn, `includeMessage value "hook \(" is not a valid regular expression: .*`)
+	websockettest.AssertWebsocketClosed(c, conn)
+}
+
+func (s *debugLogDBSuite) TestBadTimeWindow(c *gc.C) {
+	conn := s.dialWebsocket(c, url.Values{
+		"startTime": {"2016-11-30T11:48:00Z"},
+		"endTime":   {"2016-11-30T10:48:00Z"},
+	})
+	defer conn.Close()
+
+	websockettest.AssertJSONError(c, conn, `end time "2016-11-30T10:48:00Z" is before the start time`)
+	websockettest.AssertWebsocketClosed(c, conn)
+}
+
 func (s *debugLogDBSuite) TestWithHTTP(c *gc.C) {
 	uri := s.logURL("http", nil).String()
 	apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
@@ -80,6 +99,28 @@ func (s *debugLogDBSuite) TestUserLoginsAccepted(c *gc.C) {
 	c.Assert(result.Error, gc.IsNil)
 }
 
+func (s *debugLogDBSuite) TestUserLoginsRestrictedByRole(c *gc.C) {
+	user, password := s.makeRoleUser(c, "Client.FullStatus")
+	header := jujuhttp.BasicAuthHeader(user.String(), password)
+	conn, _, err := s.dialWebsocketInternal(c, noResultsPlease, header)
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+
+	websockettest.AssertJSONError(c, conn, "authorization failed: DebugLog.Read not allowed by role: permission denied")
+	websockettest.AssertWebsocketClosed(c, conn)
+}
+
+func (s *debugLogDBSuite) TestUserLoginsAllowedByRole(c *gc.C) {
+	user, password := s.makeRoleUser(c, "DebugLog.Read")
+	header := jujuhttp.BasicAuthHeader(user.String(), password)
+	conn, _, err := s.dialWebsocketInternal(c, noResultsPlease, header)
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+
+	result := websockettest.ReadJSONErrorLine(c, conn)
+	c.Assert(result.Error, gc.IsNil)
+}
+
 func (s *debugLogDBSuite) TestMachineLoginsAccepted(c *gc.C) {
 	m, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
 		Nonce: "foo-nonce",
@@ -29,6 +29,7 @@ var (
 	MaxClientPingInterval = maxClientPingInterval
 	NewBackups            = &newBackups
 	SetResource           = setResource
+	RestrictModelRoles    = restrictModelRoles
 )
 
 func APIHandlerWithEntity(entity state.Entity) *apiHandler {
@@ -215,6 +216,18 @@ func AssertHasPermission(c *gc.C, handler *apiHandler, access permission.Access,
 	}
 }
 
+// CheckRoleCapabilities returns whether the handler's user may call the
+// method under the roles they have on the model.
+func CheckRoleCapabilities(handler *apiHandler, facadeName, methodName string) error {
+	return handler.checkRoleCapabilities(facadeName, methodName)
+}
+
+// CheckModelRoleCapabilities returns whether the handler's user may
+// call the method under the roles they have on the given model.
+func CheckModelRoleCapabilities(handler *apiHandler, modelTag names.ModelTag, facadeName, methodName string) error {
+	return handler.checkModelRoleCapabilities(modelTag, facadeName, methodName)
+}
+
 func CheckHasPermission(st *state.State, entity names.Tag, operation permission.Access, target names.Tag) (bool, error) {
 	if operation != permission.SuperuserAccess || entity.Kind() != names.UserTagKind {
 		return false, errors.Errorf("%s is not a user", names.ReadableString(entity))
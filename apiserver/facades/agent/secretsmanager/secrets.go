@@ -263,12 +263,17 @@ func (s *SecretsManagerAPI) createSecret(arg params.CreateSecretArg) (string, er
 	if arg.RotatePolicy.WillRotate() {
 		nextRotateTime = arg.RotatePolicy.NextRotateTime(s.clock.Now())
 	}
+	valueRef, err := s.saveControllerContent(&arg.UpsertSecretArg, uri, 1)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
 	md, err := s.secretsState.CreateSecret(uri, state.CreateSecretParams{
 		Version:            secrets.Version,
 		Owner:              secretOwner,
 		UpdateSecretParams: fromUpsertParams(arg.UpsertSecretArg, token, nextRotateTime),
 	})
 	if err != nil {
+		commonsecrets.DeleteControllerContent(s.adminConfigGetter, valueRef)
 		return "", errors.Trace(err)
 	}
 	err = s.secretsConsumer.GrantSecretAccess(uri, state.SecretAccessParams{
@@ -281,11 +286,29 @@ func (s *SecretsManagerAPI) createSecret(arg params.CreateSecretArg) (string, er
 		if _, err2 := s.secretsState.DeleteSecret(uri); err2 != nil {
 			logger.Warningf("cleaning up secret %q", uri)
 		}
+		commonsecrets.DeleteControllerContent(s.adminConfigGetter, valueRef)
 		return "", errors.Annotate(err, "granting secret owner permission to manage the secret")
 	}
 	return md.URI.String(), nil
 }
 
+// saveControllerContent saves the content of the secret revision to the
+// active backend if only the controller accesses the backend's content,
+// replacing the content in the args with a reference to it.
+func (s *SecretsManagerAPI) saveControllerContent(arg *params.UpsertSecretArg, uri *coresecrets.URI, revision int) (*coresecrets.ValueRef, error) {
+	valueRef, err := commonsecrets.SaveControllerContent(s.adminConfigGetter, uri, revision, arg.Content.Data)
+	if err != nil || valueRef == nil {
+		return nil, errors.Trace(err)
+	}
+	arg.Content = params.SecretContentParams{
+		ValueRef: &params.SecretValueRef{
+			BackendID:  valueRef.BackendID,
+			RevisionID: valueRef.RevisionID,
+		},
+	}
+	return valueRef, nil
+}
+
 func fromUpsertParams(p params.UpsertSecretArg, token leadership.Token, nextRotateTime *time.Time) state.UpdateSecretParams {
 	var valueRef *coresecrets.ValueRef
 	if p.Content.ValueRef != nil {
@@ -344,7 +367,14 @@ func (s *SecretsManagerAPI) updateSecret(arg params.UpdateSecretArg) error {
 	if !md.RotatePolicy.WillRotate() && arg.RotatePolicy.WillRotate() {
 		nextRotateTime = arg.RotatePolicy.NextRotateTime(s.clock.Now())
 	}
+	valueRef, err := s.saveControllerContent(&arg.UpsertSecretArg, uri, md.LatestRevision+1)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	_, err = s.secretsState.UpdateSecret(uri, fromUpsertParams(arg.UpsertSecretArg, token, nextRotateTime))
+	if err != nil {
+		commonsecrets.DeleteControllerContent(s.adminConfigGetter, valueRef)
+	}
 	return errors.Trace(err)
 }
 
@@ -552,6 +582,12 @@ func (s *SecretsManagerAPI) GetSecretRevisionContentInfo(arg params.SecretRevisi
 	for i, rev := range arg.Revisions {
 		// TODO(wallworld) - if pendingDelete is true, mark the revision for deletion
 		val, valueRef, err := s.secretsState.GetSecretValue(uri, rev)
+		if err == nil && valueRef != nil {
+			val, err = s.controllerContent(valueRef)
+			if val != nil {
+				valueRef = nil
+			}
+		}
 		if err != nil {
 			result.Results[i].Error = apiservererrors.ServerError(err)
 			continue
@@ -728,6 +764,12 @@ func (s *SecretsManagerAPI) getSecretContent(arg params.GetSecretContentArg) (
 	}
 
 	val, valueRef, err := s.secretsState.GetSecretValue(uri, consumedRevision)
+	if err == nil && valueRef != nil {
+		val, err = s.controllerContent(valueRef)
+		if val != nil {
+			valueRef = nil
+		}
+	}
 	content := &secrets.ContentParams{SecretValue: val, ValueRef: valueRef}
 	if err != nil || content.ValueRef == nil {
 		return content, nil, false, errors.Trace(err)
@@ -736,6 +778,14 @@ func (s *SecretsManagerAPI) getSecretContent(arg params.GetSecretContentArg) (
 	return content, backend, draining, errors.Trace(err)
 }
 
+// controllerContent returns the content of a secret revision stored in
+// a backend which only the controller accesses, or nil if the caller
+// reads the content from the backend itself. The caller must already
+// have checked the agent's access to the secret.
+func (s *SecretsManagerAPI) controllerContent(valueRef *coresecrets.ValueRef) (coresecrets.SecretValue, error) {
+	return commonsecrets.ControllerContent(s.adminConfigGetter, valueRef)
+}
+
 // UpdateTrackedRevisions updates the consumer info to track the latest
 // revisions for the specified secrets.
 func (s *SecretsManagerAPI) UpdateTrackedRevisions(uris []string) (params.ErrorResults, error) {
@@ -4,6 +4,7 @@
 package secretsmanager_test
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/secrets"
 	"github.com/juju/juju/secrets/provider"
+	"github.com/juju/juju/secrets/provider/file"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -51,6 +53,9 @@ type SecretsManagerSuite struct {
 	authTag               names.Tag
 	clock                 clock.Clock
 
+	fileBackendConfig provider.ModelBackendConfig
+	fileBackendActive bool
+
 	facade *secretsmanager.SecretsManagerAPI
 }
 
@@ -79,10 +84,29 @@ func (s *SecretsManagerSuite) setup(c *gc.C) *gomock.Controller {
 	s.secretsTriggerWatcher = mocks.NewMockSecretsTriggerWatcher(ctrl)
 	s.expectAuthUnitAgent()
 
-	s.PatchValue(&commonsecrets.GetProvider, func(string) (provider.SecretBackendProvider, error) { return s.provider, nil })
+	s.PatchValue(&commonsecrets.GetProvider, func(backendType string) (provider.SecretBackendProvider, error) {
+		if backendType == file.BackendType {
+			return file.NewProvider(), nil
+		}
+		return s.provider, nil
+	})
 
 	s.clock = testclock.NewClock(time.Now())
 
+	// The file backend's content is only accessed by the controller.
+	fileKey, err := file.NewKey()
+	c.Assert(err, jc.ErrorIsNil)
+	s.fileBackendConfig = provider.ModelBackendConfig{
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		ModelUUID:      coretesting.ModelTag.Id(),
+		ModelName:      "fred",
+		BackendConfig: provider.BackendConfig{
+			BackendType: file.BackendType,
+			Config:      map[string]interface{}{"path": c.MkDir(), "key": fileKey},
+		},
+	}
+	s.fileBackendActive = false
+
 	backendConfigGetter := func(backendIds []string, wantAll bool) (*provider.ModelBackendConfigInfo, error) {
 		// wantAll is for 3.1 compatibility only.
 		if wantAll {
@@ -104,8 +128,12 @@ func (s *SecretsManagerSuite) setup(c *gc.C) *gomock.Controller {
 		}, nil
 	}
 	adminConfigGetter := func() (*provider.ModelBackendConfigInfo, error) {
+		activeID := "backend-id"
+		if s.fileBackendActive {
+			activeID = "file-backend-id"
+		}
 		return &provider.ModelBackendConfigInfo{
-			ActiveID: "backend-id",
+			ActiveID: activeID,
 			Configs: map[string]provider.ModelBackendConfig{
 				"backend-id": {
 					ControllerUUID: coretesting.ControllerTag.Id(),
@@ -116,6 +144,7 @@ func (s *SecretsManagerSuite) setup(c *gc.C) *gomock.Controller {
 						Config:      map[string]interface{}{"foo": "admin"},
 					},
 				},
+				"file-backend-id": s.fileBackendConfig,
 			},
 		}, nil
 	}
@@ -139,7 +168,6 @@ func (s *SecretsManagerSuite) setup(c *gc.C) *gomock.Controller {
 		return s.remoteClient, nil
 	}
 
-	var err error
 	s.facade, err = secretsmanager.NewTestAPI(
 		s.authorizer, s.resources, s.leadership, s.secretsState, s.secretsConsumer,
 		s.secretTriggers, backendConfigGetter, adminConfigGetter,
@@ -426,6 +454,46 @@ func (s *SecretsManagerSuite) TestUpdateSecrets(c *gc.C) {
 	})
 }
 
+func (s *SecretsManagerSuite) TestUpdateSecretsControllerContent(c *gc.C) {
+	defer s.setup(c).Finish()
+	s.fileBackendActive = true
+
+	uri := coresecrets.NewURI()
+	expectURI := *uri
+	s.secretsState.EXPECT().GetSecret(&expectURI).Return(&coresecrets.SecretMetadata{LatestRevision: 2}, nil)
+	// The content is saved to the file backend by the controller, and
+	// only the reference to it is stored.
+	s.secretsState.EXPECT().UpdateSecret(&expectURI, state.UpdateSecretParams{
+		LeaderToken: s.token,
+		ValueRef: &coresecrets.ValueRef{
+			BackendID:  "file-backend-id",
+			RevisionID: uri.ID + "-3",
+		},
+	}).Return(&coresecrets.SecretMetadata{URI: uri, LatestRevision: 3}, nil)
+	s.leadership.EXPECT().LeadershipCheck("mariadb", "mariadb/0").Return(s.token)
+	s.token.EXPECT().Check().Return(nil)
+	s.expectSecretAccessQuery(2)
+
+	results, err := s.facade.UpdateSecrets(params.UpdateSecretArgs{
+		Args: []params.UpdateSecretArg{{
+			URI: uri.String(),
+			UpsertSecretArg: params.UpsertSecretArg{
+				Content: params.SecretContentParams{Data: map[string]string{"foo": "YmFy"}},
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+
+	backend, err := file.NewProvider().NewBackend(&s.fileBackendConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	val, err := backend.GetContent(context.Background(), uri.ID+"-3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(val.EncodedValues(), jc.DeepEquals, map[string]string{"foo": "YmFy"})
+}
+
 func (s *SecretsManagerSuite) TestUpdateSecretDuplicateLabel(c *gc.C) {
 	defer s.setup(c).Finish()
 
@@ -1618,6 +1686,35 @@ func (s *SecretsManagerSuite) TestGetSecretRevisionContentInfo(c *gc.C) {
 	})
 }
 
+func (s *SecretsManagerSuite) TestGetSecretRevisionContentInfoControllerContent(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	uri := coresecrets.NewURI()
+	backend, err := file.NewProvider().NewBackend(&s.fileBackendConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	revisionID, err := backend.SaveContent(context.Background(), uri, 666, coresecrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+	s.secretsConsumer.EXPECT().SecretAccess(uri, s.authTag).Return(coresecrets.RoleManage, nil)
+	s.secretsState.EXPECT().GetSecretValue(uri, 666).Return(
+		nil, &coresecrets.ValueRef{
+			BackendID:  "file-backend-id",
+			RevisionID: revisionID,
+		}, nil,
+	)
+
+	results, err := s.facade.GetSecretRevisionContentInfo(params.SecretRevisionArg{
+		URI:       uri.String(),
+		Revisions: []int{666},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// The content is served by the controller, without the backend config.
+	c.Assert(results, jc.DeepEquals, params.SecretContentResults{
+		Results: []params.SecretContentResult{{
+			Content: params.SecretContentParams{Data: map[string]string{"foo": "YmFy"}},
+		}},
+	})
+}
+
 func (s *SecretsManagerSuite) TestWatchObsolete(c *gc.C) {
 	defer s.setup(c).Finish()
 
@@ -0,0 +1,212 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common/storagecommon"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/rpc/params"
+)
+
+// StorageProvisionerAPIv6 provides the StorageProvisioner API v6 facade.
+// It adds methods for resizing volumes and growing their filesystems.
+type StorageProvisionerAPIv6 struct {
+	*StorageProvisionerAPIv5
+}
+
+// NewStorageProvisionerAPIv6 creates a new server-side StorageProvisioner v6 facade.
+func NewStorageProvisionerAPIv6(v5 *StorageProvisionerAPIv5) *StorageProvisionerAPIv6 {
+	return &StorageProvisionerAPIv6{v5}
+}
+
+// WatchVolumeResizes watches for changes to volumes scoped to the
+// entities with the tags passed in, so that pending resizes may be
+// carried out.
+func (s *StorageProvisionerAPIv6) WatchVolumeResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.sb.WatchModelVolumeResizes, s.sb.WatchMachineVolumeResizes, nil)
+}
+
+// WatchFilesystemResizes watches for changes to filesystems scoped to
+// the machines with the tags passed in, so that filesystems whose
+// backing volumes have been resized may be grown. Filesystems backed
+// by volumes are always machine-scoped, so only machine tags are
+// supported.
+func (s *StorageProvisionerAPIv6) WatchFilesystemResizes(args params.Entities) (params.StringsWatchResults, error) {
+	results := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		if _, err := names.ParseMachineTag(arg.Tag); err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(
+				errors.NotSupportedf("watching filesystem resizes for %q", arg.Tag),
+			)
+			continue
+		}
+		machineResults, err := s.watchStorageEntities(
+			params.Entities{Entities: []params.Entity{arg}},
+			nil, s.sb.WatchMachineFilesystemResizes, nil,
+		)
+		if err != nil {
+			return params.StringsWatchResults{}, err
+		}
+		results.Results[i] = machineResults.Results[0]
+	}
+	return results, nil
+}
+
+// VolumeResizeParams returns the parameters for resizing the volumes
+// with the specified tags. If a volume has no pending resize, the
+// returned size will be zero.
+func (s *StorageProvisionerAPIv6) VolumeResizeParams(args params.Entities) (params.VolumeResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeResizeParamsResults{}, err
+	}
+	one := func(arg params.Entity) (params.VolumeResizeParams, error) {
+		tag, err := names.ParseVolumeTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return params.VolumeResizeParams{}, apiservererrors.ErrPerm
+		}
+		volume, err := s.sb.Volume(tag)
+		if errors.IsNotFound(err) {
+			return params.VolumeResizeParams{}, apiservererrors.ErrPerm
+		} else if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		info, err := volume.Info()
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		providerType, cfg, err := storagecommon.StoragePoolConfig(info.Pool, s.poolManager, s.registry)
+		if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		size, _ := volume.PendingSize()
+		return params.VolumeResizeParams{
+			VolumeTag:  tag.String(),
+			VolumeId:   info.VolumeId,
+			Provider:   string(providerType),
+			Attributes: cfg.Attrs(),
+			Size:       size,
+		}, nil
+	}
+	results := params.VolumeResizeParamsResults{
+		Results: make([]params.VolumeResizeParamsResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		result, err := one(arg)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = result
+	}
+	return results, nil
+}
+
+// FinishVolumeResizes records that volumes have been resized by the
+// storage provider. The new size of each volume is taken from the
+// volume info.
+func (s *StorageProvisionerAPIv6) FinishVolumeResizes(args params.Volumes) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	one := func(arg params.Volume) error {
+		tag, err := names.ParseVolumeTag(arg.VolumeTag)
+		if err != nil || !canAccess(tag) {
+			return apiservererrors.ErrPerm
+		}
+		err = s.sb.FinishVolumeResize(tag, arg.Info.Size)
+		if errors.IsNotFound(err) {
+			return apiservererrors.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Volumes)),
+	}
+	for i, arg := range args.Volumes {
+		results.Results[i].Error = apiservererrors.ServerError(one(arg))
+	}
+	return results, nil
+}
+
+// FilesystemResizeParams returns the parameters for growing the
+// filesystems attached to machines with the specified IDs. If a
+// filesystem has no pending resize, the returned size will be zero.
+func (s *StorageProvisionerAPIv6) FilesystemResizeParams(args params.MachineStorageIds) (params.FilesystemResizeParamsResults, error) {
+	canAccess, err := s.getAttachmentAuthFunc()
+	if err != nil {
+		return params.FilesystemResizeParamsResults{}, apiservererrors.ServerError(apiservererrors.ErrPerm)
+	}
+	one := func(arg params.MachineStorageId) (params.FilesystemResizeParams, error) {
+		filesystemAttachment, err := s.oneFilesystemAttachment(arg, canAccess)
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		attachmentInfo, err := filesystemAttachment.Info()
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		filesystem, err := s.sb.Filesystem(filesystemAttachment.Filesystem())
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		volumeTag, err := filesystem.Volume()
+		if err != nil {
+			return params.FilesystemResizeParams{}, err
+		}
+		size, _ := filesystem.PendingSize()
+		return params.FilesystemResizeParams{
+			FilesystemTag: filesystem.FilesystemTag().String(),
+			VolumeTag:     volumeTag.String(),
+			MountPoint:    attachmentInfo.MountPoint,
+			Size:          size,
+		}, nil
+	}
+	results := params.FilesystemResizeParamsResults{
+		Results: make([]params.FilesystemResizeParamsResult, len(args.Ids)),
+	}
+	for i, arg := range args.Ids {
+		result, err := one(arg)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = result
+	}
+	return results, nil
+}
+
+// FinishFilesystemResizes records that filesystems have been grown
+// to fill their resized volumes. The new size of each filesystem is
+// taken from the filesystem info.
+func (s *StorageProvisionerAPIv6) FinishFilesystemResizes(args params.Filesystems) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	one := func(arg params.Filesystem) error {
+		tag, err := names.ParseFilesystemTag(arg.FilesystemTag)
+		if err != nil || !canAccess(tag) {
+			return apiservererrors.ErrPerm
+		}
+		err = s.sb.FinishFilesystemResize(tag, arg.Info.Size)
+		if errors.IsNotFound(err) {
+			return apiservererrors.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Filesystems)),
+	}
+	for i, arg := range args.Filesystems {
+		results.Results[i].Error = apiservererrors.ServerError(one(arg))
+	}
+	return results, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common/storagecommon"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+)
+
+// StorageProvisionerAPIv5 provides the StorageProvisioner API v5 facade.
+// It adds methods for taking and destroying volume snapshots.
+type StorageProvisionerAPIv5 struct {
+	*StorageProvisionerAPIv4
+}
+
+// NewStorageProvisionerAPIv5 creates a new server-side StorageProvisioner v5 facade.
+func NewStorageProvisionerAPIv5(v4 *StorageProvisionerAPIv4) *StorageProvisionerAPIv5 {
+	return &StorageProvisionerAPIv5{v4}
+}
+
+// WatchVolumeSnapshots watches for changes to snapshots of volumes
+// scoped to the entities with the tags passed in.
+func (s *StorageProvisionerAPIv5) WatchVolumeSnapshots(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.sb.WatchModelVolumeSnapshots, s.sb.WatchMachineVolumeSnapshots, nil)
+}
+
+// snapshotVolumeTag returns the tag of the volume that the snapshot
+// with the specified ID was taken from.
+func snapshotVolumeTag(id string) (names.VolumeTag, error) {
+	if !state.IsValidVolumeSnapshotId(id) {
+		return names.VolumeTag{}, errors.NotValidf("volume snapshot ID %q", id)
+	}
+	return names.NewVolumeTag(id[:strings.LastIndex(id, "@")]), nil
+}
+
+// volumeSnapshot returns the volume snapshot with the specified ID, if
+// the authenticated agent may access the snapshotted volume.
+func (s *StorageProvisionerAPIv5) volumeSnapshot(id string, canAccess func(names.Tag) bool) (state.VolumeSnapshot, error) {
+	volumeTag, err := snapshotVolumeTag(id)
+	if err != nil || !canAccess(volumeTag) {
+		return nil, apiservererrors.ErrPerm
+	}
+	snapshot, err := s.sb.VolumeSnapshot(id)
+	if errors.IsNotFound(err) {
+		return nil, apiservererrors.ErrPerm
+	}
+	return snapshot, errors.Trace(err)
+}
+
+// VolumeSnapshotParams returns the parameters for taking or destroying
+// the volume snapshots with the specified IDs.
+func (s *StorageProvisionerAPIv5) VolumeSnapshotParams(args params.VolumeSnapshotIds) (params.VolumeSnapshotParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	modelCfg, err := s.st.ModelConfig()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	controllerCfg, err := s.st.ControllerConfig()
+	if err != nil {
+		return params.VolumeSnapshotParamsResults{}, err
+	}
+	one := func(id string) (params.VolumeSnapshotParams, error) {
+		snapshot, err := s.volumeSnapshot(id, canAccess)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		providerType, cfg, err := storagecommon.StoragePoolConfig(snapshot.Pool(), s.poolManager, s.registry)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, err
+		}
+		var storageInstance state.StorageInstance
+		if storageTag, ok := snapshot.StorageInstance(); ok {
+			storageInstance, err = s.sb.StorageInstance(storageTag)
+			if err != nil && !errors.IsNotFound(err) {
+				return params.VolumeSnapshotParams{}, err
+			}
+		}
+		snapshotTags, err := storagecommon.StorageTags(
+			storageInstance, modelCfg.UUID(), controllerCfg.ControllerUUID(), modelCfg,
+		)
+		if err != nil {
+			return params.VolumeSnapshotParams{}, errors.Annotate(err, "computing snapshot tags")
+		}
+		result := params.VolumeSnapshotParams{
+			Id:         snapshot.Id(),
+			Life:       life.Value(snapshot.Life().String()),
+			VolumeTag:  snapshot.Volume().String(),
+			Provider:   string(providerType),
+			Attributes: cfg.Attrs(),
+			Tags:       snapshotTags,
+		}
+		if info, err := snapshot.Info(); err == nil {
+			result.SnapshotId = info.SnapshotId
+		} else if !errors.IsNotProvisioned(err) {
+			return params.VolumeSnapshotParams{}, err
+		}
+		volume, err := s.sb.Volume(snapshot.Volume())
+		if err == nil {
+			if info, err := volume.Info(); err == nil {
+				result.VolumeId = info.VolumeId
+			}
+		} else if !errors.IsNotFound(err) {
+			return params.VolumeSnapshotParams{}, err
+		}
+		return result, nil
+	}
+	results := params.VolumeSnapshotParamsResults{
+		Results: make([]params.VolumeSnapshotParamsResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		result, err := one(id)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = result
+	}
+	return results, nil
+}
+
+// SetVolumeSnapshotInfo records the details of newly taken volume snapshots.
+func (s *StorageProvisionerAPIv5) SetVolumeSnapshotInfo(args params.VolumeSnapshots) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	one := func(arg params.VolumeSnapshot) error {
+		if _, err := s.volumeSnapshot(arg.Id, canAccess); err != nil {
+			return err
+		}
+		return s.sb.SetVolumeSnapshotInfo(arg.Id, state.VolumeSnapshotInfo{
+			SnapshotId: arg.Info.SnapshotId,
+			Size:       arg.Info.Size,
+		})
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Snapshots)),
+	}
+	for i, arg := range args.Snapshots {
+		results.Results[i].Error = apiservererrors.ServerError(one(arg))
+	}
+	return results, nil
+}
+
+// SetVolumeSnapshotStatus sets the status of volume snapshots.
+func (s *StorageProvisionerAPIv5) SetVolumeSnapshotStatus(args params.SetVolumeSnapshotStatus) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	one := func(arg params.VolumeSnapshotStatusArg) error {
+		snapshot, err := s.volumeSnapshot(arg.Id, canAccess)
+		if err != nil {
+			return err
+		}
+		return snapshot.SetStatus(status.StatusInfo{
+			Status:  status.Status(arg.Status),
+			Message: arg.Info,
+		})
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		results.Results[i].Error = apiservererrors.ServerError(one(arg))
+	}
+	return results, nil
+}
+
+// RemoveVolumeSnapshots removes the volume snapshots with the specified
+// IDs from state. The snapshots must already have been destroyed in the
+// storage provider.
+func (s *StorageProvisionerAPIv5) RemoveVolumeSnapshots(args params.VolumeSnapshotIds) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	one := func(id string) error {
+		if _, err := s.volumeSnapshot(id, canAccess); err != nil {
+			return err
+		}
+		return s.sb.RemoveVolumeSnapshot(id)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		results.Results[i].Error = apiservererrors.ServerError(one(id))
+	}
+	return results, nil
+}
@@ -763,6 +763,10 @@ func (s *StorageProvisionerAPIv4) VolumeParams(args params.Entities) (params.Vol
 		if err != nil {
 			return params.VolumeParams{}, err
 		}
+		volumeParams.SnapshotId, err = storagecommon.VolumeSnapshotId(volume, s.sb)
+		if err != nil {
+			return params.VolumeParams{}, errors.Annotate(err, "getting volume snapshot")
+		}
 		if len(volumeAttachments) == 1 {
 			// There is exactly one attachment to be made, so make
 			// it immediately. Otherwise we will defer attachments
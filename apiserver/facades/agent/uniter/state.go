@@ -25,6 +25,7 @@ type storageInterface interface {
 	AddStorageForUnitOperation(names.UnitTag, string, state.StorageConstraints) (state.ModelOperation, error)
 	WatchStorageAttachments(names.UnitTag) state.StringsWatcher
 	WatchStorageAttachment(names.StorageTag, names.UnitTag) state.NotifyWatcher
+	ClearStorageAttachmentResized(names.StorageTag, names.UnitTag) error
 }
 
 type storageVolumeInterface interface {
@@ -207,6 +207,7 @@ func (s *StorageAPI) fromStateStorageAttachment(stateStorageAttachment state.Sto
 		params.StorageKind(stateStorageInstance.Kind()),
 		info.Location,
 		life.Value(stateStorageAttachment.Life().String()),
+		stateStorageAttachment.Resized(),
 	}, nil
 }
 
@@ -309,6 +310,38 @@ func (s *StorageAPI) watchOneStorageAttachment(id params.StorageAttachmentId, ca
 	return nothing, watcher.EnsureErr(watch)
 }
 
+// ClearStorageAttachmentsResized clears the resized flag of the
+// specified storage attachments, once the unit has run the
+// storage-resized hook for them.
+func (s *StorageAPI) ClearStorageAttachmentsResized(args params.StorageAttachmentIds) (params.ErrorResults, error) {
+	canAccess, err := s.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	one := func(id params.StorageAttachmentId) error {
+		unitTag, err := names.ParseUnitTag(id.UnitTag)
+		if err != nil || !canAccess(unitTag) {
+			return apiservererrors.ErrPerm
+		}
+		storageTag, err := names.ParseStorageTag(id.StorageTag)
+		if err != nil {
+			return err
+		}
+		err = s.storage.ClearStorageAttachmentResized(storageTag, unitTag)
+		if errors.IsNotFound(err) {
+			return apiservererrors.ErrPerm
+		}
+		return err
+	}
+	for i, id := range args.Ids {
+		results.Results[i].Error = apiservererrors.ServerError(one(id))
+	}
+	return results, nil
+}
+
 // RemoveStorageAttachments removes the specified storage
 // attachments from state.
 func (s *StorageAPI) RemoveStorageAttachments(args params.StorageAttachmentIds) (params.ErrorResults, error) {
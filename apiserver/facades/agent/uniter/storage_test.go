@@ -310,6 +310,56 @@ func (s *storageSuite) TestRemoveStorageAttachments(c *gc.C) {
 	})
 }
 
+func (s *storageSuite) TestClearStorageAttachmentsResized(c *gc.C) {
+	unitTag0 := names.NewUnitTag("mysql/0")
+	unitTag1 := names.NewUnitTag("mysql/1")
+	storageTag0 := names.NewStorageTag("data/0")
+	storageTag1 := names.NewStorageTag("data/1")
+
+	resources := common.NewResources()
+	getCanAccess := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			return tag == unitTag0
+		}, nil
+	}
+
+	var calls []names.StorageTag
+	st := &mockStorageState{
+		clearStorageAttachmentResized: func(s names.StorageTag, u names.UnitTag) error {
+			c.Assert(u, gc.DeepEquals, unitTag0)
+			calls = append(calls, s)
+			if s == storageTag1 {
+				return errors.NotFoundf("storage attachment")
+			}
+			return nil
+		},
+	}
+
+	storage, err := uniter.NewStorageAPI(st, st, resources, getCanAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := storage.ClearStorageAttachmentsResized(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: storageTag0.String(),
+			UnitTag:    unitTag0.String(),
+		}, {
+			StorageTag: storageTag1.String(),
+			UnitTag:    unitTag0.String(),
+		}, {
+			StorageTag: storageTag0.String(),
+			UnitTag:    unitTag1.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, jc.DeepEquals, []names.StorageTag{storageTag0, storageTag1})
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{&params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+			{&params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+		},
+	})
+}
+
 type mockUnit struct {
 	assignedMachine    string
 	storageConstraints map[string]state.StorageConstraints
@@ -349,6 +399,7 @@ type mockStorageState struct {
 	watchVolumeAttachment         func(names.Tag, names.VolumeTag) state.NotifyWatcher
 	watchBlockDevices             func(names.MachineTag) state.NotifyWatcher
 	addUnitStorageOperation       func(u names.UnitTag, name string, cons state.StorageConstraints) error
+	clearStorageAttachmentResized func(names.StorageTag, names.UnitTag) error
 }
 
 func (m *mockStorageState) VolumeAccess() uniter.StorageVolumeInterface {
@@ -373,6 +424,10 @@ func (m *mockStorageState) RemoveStorageAttachment(s names.StorageTag, u names.U
 	return m.remove(s, u, force)
 }
 
+func (m *mockStorageState) ClearStorageAttachmentResized(s names.StorageTag, u names.UnitTag) error {
+	return m.clearStorageAttachmentResized(s, u)
+}
+
 func (m *mockStorageState) StorageInstance(s names.StorageTag) (state.StorageInstance, error) {
 	return m.storageInstance(s)
 }
@@ -78,6 +78,12 @@ type UniterAPI struct {
 	cloudSpecer     cloudspec.CloudSpecer
 }
 
+// UniterAPIv19 implements version 19 of the uniter API, which does not
+// support clearing the resized flag of storage attachments.
+type UniterAPIv19 struct {
+	UniterAPI
+}
+
 // UniterAPIv18 Implements version 18 of the uniter API, which includes methods
 // ModelUUID and OpenedApplicationPortRangesByEndpoint that were removed from
 // later versions.
@@ -85,6 +91,12 @@ type UniterAPIv18 struct {
 	UniterAPI
 }
 
+// ClearStorageAttachmentsResized is not available on versions prior to v20.
+func (*UniterAPIv19) ClearStorageAttachmentsResized(_, _ struct{}) {}
+
+// ClearStorageAttachmentsResized is not available on versions prior to v20.
+func (*UniterAPIv18) ClearStorageAttachmentsResized(_, _ struct{}) {}
+
 // OpenedMachinePortRangesByEndpoint returns the port ranges opened by each
 // unit on the provided machines grouped by application endpoint.
 func (u *UniterAPI) OpenedMachinePortRangesByEndpoint(args params.Entities) (params.OpenPortRangesByEndpointResults, error) {
@@ -337,7 +337,7 @@ func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults,
 
 func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, schema.Defaults, error) {
 	if modelType != state.ModelTypeCAAS {
-		return trustFields, trustDefaults, nil
+		return iaasFields, trustDefaults, nil
 	}
 	// TODO(caas) - get the schema from the provider
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
@@ -579,6 +579,13 @@ func deployApplication(
 		attachStorage[i] = tag
 	}
 
+	err = checkUnitQuotas(backend, modelType, args.NumUnits, args.Placement, func() (constraints.Value, uint64, error) {
+		return args.Constraints, unitStorageSize(ch.Meta(), args.Storage), nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	bindings, err := state.NewBindings(backend, args.EndpointBindings)
 	if err != nil {
 		return errors.Trace(err)
@@ -735,6 +742,9 @@ func parseCharmSettings(
 	if err != nil {
 		return nil, nil, nil, nil, errors.Trace(err)
 	}
+	if _, err := EgressRules(appConfig.Attributes()); err != nil {
+		return nil, nil, nil, nil, errors.Trace(err)
+	}
 
 	// If there isn't a charm YAML, then we can just return the charmConfig as
 	// the settings and no need to attempt to parse an empty yaml.
@@ -1481,6 +1491,10 @@ func addApplicationUnits(backend Backend, modelType state.ModelType, args params
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	err = checkUnitQuotas(backend, modelType, args.NumUnits, args.Placement, applicationUnitResources(oneApplication))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return addUnits(
 		oneApplication,
 		args.ApplicationName,
@@ -1868,6 +1882,10 @@ func (api *APIBase) ScaleApplications(args params.ScaleApplicationsParams) (para
 			}
 		}
 
+		if err := checkScaleQuotas(api.backend, app, arg.Scale, arg.ScaleChange); err != nil {
+			return nil, errors.Trace(err)
+		}
+
 		var info params.ScaleApplicationInfo
 		if arg.ScaleChange != 0 {
 			newScale, err := app.ChangeScale(arg.ScaleChange)
@@ -1942,6 +1960,9 @@ func (api *APIBase) SetConstraints(args params.SetConstraints) error {
 	if err != nil {
 		return err
 	}
+	if err := checkConstraintsQuotas(api.backend, api.modelType, app, args.Constraints); err != nil {
+		return errors.Trace(err)
+	}
 	return app.SetConstraints(args.Constraints)
 }
 
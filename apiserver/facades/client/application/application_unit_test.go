@@ -13,7 +13,6 @@ import (
 	"github.com/juju/charm/v12/assumes"
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
-	"github.com/juju/schema"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/v3"
@@ -21,7 +20,6 @@ import (
 	"github.com/kr/pretty"
 	"go.uber.org/mock/gomock"
 	gc "gopkg.in/check.v1"
-	"gopkg.in/juju/environschema.v1"
 
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/apiserver/common"
@@ -42,6 +40,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/network/firewall"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -77,8 +76,9 @@ type ApplicationSuite struct {
 	changeAllowed error
 	removeAllowed error
 
-	authorizer apiservertesting.FakeAuthorizer
-	modelType  state.ModelType
+	authorizer  apiservertesting.FakeAuthorizer
+	modelType   state.ModelType
+	modelQuotas quota.ModelResources
 
 	allSpaceInfos network.SpaceInfos
 
@@ -116,6 +116,7 @@ func (s *ApplicationSuite) SetUpTest(c *gc.C) {
 		Tag: names.NewUserTag("admin"),
 	}
 	s.modelType = state.ModelTypeIAAS
+	s.modelQuotas = quota.ModelResources{}
 	s.PatchValue(&application.ClassifyDetachedStorage, fakeClassifyDetachedStorage)
 	s.PatchValue(&application.SupportedFeaturesGetter, fakeSupportedFeaturesGetter)
 	s.deployParams = make(map[string]application.DeployApplicationParams)
@@ -160,6 +161,7 @@ func (s *ApplicationSuite) setup(c *gc.C) *gomock.Controller {
 	).AnyTimes()
 	s.backend.EXPECT().ControllerTag().Return(coretesting.ControllerTag).AnyTimes()
 	s.backend.EXPECT().AllSpaceInfos().Return(s.allSpaceInfos, nil).AnyTimes()
+	s.backend.EXPECT().ModelQuotas().Return(s.modelQuotas, nil).AnyTimes()
 
 	s.storageAccess = mocks.NewMockStorageInterface(ctrl)
 	s.storageAccess.EXPECT().VolumeAccess().Return(nil).AnyTimes()
@@ -372,7 +374,7 @@ func (s *ApplicationSuite) TestSetCharmEverything(c *gc.C) {
 	}
 	app.EXPECT().SetCharm(setCharmConfigMatcher{c: c, expected: cfg})
 
-	schemaFields, defaults, err := application.AddTrustSchemaAndDefaults(environschema.Fields{}, schema.Defaults{})
+	schemaFields, defaults, err := application.ApplicationConfigSchema(state.ModelTypeIAAS)
 	c.Assert(err, jc.ErrorIsNil)
 	app.EXPECT().UpdateApplicationConfig(coreconfig.ConfigAttributes{"trust": true}, nil, schemaFields, defaults)
 	s.backend.EXPECT().Application("postgresql").Return(app, nil)
@@ -1501,6 +1503,64 @@ func (s *ApplicationSuite) TestApplicationDeployWithStorage(c *gc.C) {
 	c.Assert(s.deployParams["my-app"].Storage, gc.DeepEquals, storageConstraints)
 }
 
+func (s *ApplicationSuite) TestApplicationDeployWithinQuotas(c *gc.C) {
+	s.modelQuotas = quota.ModelResources{Units: 3, Storage: 4096}
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	ch := s.expectCharm(ctrl, &charm.Meta{Storage: map[string]charm.Storage{
+		"data": {Type: charm.StorageBlock, CountMin: 1, CountMax: 1, MinimumSize: 1024},
+	}}, nil, &charm.Config{})
+	curl := "ch:utopic/storage-block-10"
+	s.backend.EXPECT().Charm(curl).Return(ch, nil)
+	s.backend.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Units: 1}, nil)
+	s.backend.EXPECT().ResolveConstraints(constraints.Value{}).Return(constraints.Value{}, nil)
+
+	results, err := s.api.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "my-app",
+			CharmURL:        curl,
+			CharmOrigin:     createCharmOriginFromURL(curl),
+			NumUnits:        2,
+			Storage: map[string]storage.Constraints{
+				"data": {Count: 1, Size: 2048},
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(s.deployParams["my-app"].NumUnits, gc.Equals, 2)
+}
+
+func (s *ApplicationSuite) TestApplicationDeployQuotaExceeded(c *gc.C) {
+	s.modelQuotas = quota.ModelResources{Cores: 16}
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	ch := s.expectDefaultCharm(ctrl)
+	curl := "ch:precise/dummy-42"
+	s.backend.EXPECT().Charm(curl).Return(ch, nil)
+	s.backend.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Machines: 2, Cores: 8}, nil)
+	cons := constraints.MustParse("mem=8G")
+	s.backend.EXPECT().ResolveConstraints(cons).Return(constraints.MustParse("cores=4 mem=8G"), nil)
+
+	results, err := s.api.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "my-app",
+			CharmURL:        curl,
+			CharmOrigin:     createCharmOriginFromURL(curl),
+			NumUnits:        3,
+			Constraints:     cons,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeQuotaLimitExceeded)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `model cores quota of 16 exceeded \(8 in use, 12 requested\)`)
+	c.Assert(s.deployParams, gc.HasLen, 0)
+}
+
 func (s *ApplicationSuite) TestApplicationDeployDefaultFilesystemStorage(c *gc.C) {
 	ctrl := s.setup(c)
 	defer ctrl.Finish()
@@ -2118,6 +2178,28 @@ func (s *ApplicationSuite) TestAddUnits(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestAddUnitsQuotaExceeded(c *gc.C) {
+	s.modelQuotas = quota.ModelResources{Machines: 3, Memory: 8192}
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().StorageConstraints().Return(map[string]state.StorageConstraints{
+		"data": {Pool: "loop", Size: 1024, Count: 1},
+	}, nil)
+	s.backend.EXPECT().Application("postgresql").AnyTimes().Return(app, nil)
+	s.backend.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Machines: 1, Units: 1, Memory: 6144}, nil)
+	s.backend.EXPECT().ResolveConstraints(gomock.Any()).Return(constraints.MustParse("cores=2 mem=4G"), nil)
+
+	_, err := s.api.AddUnits(params.AddApplicationUnits{
+		ApplicationName: "postgresql",
+		NumUnits:        2,
+		Placement:       []*instance.Placement{{Scope: instance.MachineScope, Directive: "0"}},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `model memory quota of 8192MiB exceeded \(6144MiB in use, 4096MiB requested\)`)
+}
+
 func (s *ApplicationSuite) TestAddUnitsCAASModel(c *gc.C) {
 	s.modelType = state.ModelTypeCAAS
 	defer s.setup(c).Finish()
@@ -2221,6 +2303,108 @@ func (s *ApplicationSuite) TestScaleApplicationsCAASModel(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestScaleApplicationsWithinQuotas(c *gc.C) {
+	s.modelType = state.ModelTypeCAAS
+	s.modelQuotas = quota.ModelResources{Units: 5, Memory: 8192}
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().GetScale().Return(2).AnyTimes()
+	app.EXPECT().UnitCount().Return(1)
+	app.EXPECT().StorageConstraints().Return(nil, nil)
+	app.EXPECT().ChangeScale(3).Return(5, nil)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+	s.backend.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Units: 2, Memory: 4096}, nil)
+	s.backend.EXPECT().ResolveConstraints(gomock.Any()).Return(constraints.MustParse("mem=1G"), nil)
+
+	results, err := s.api.ScaleApplications(params.ScaleApplicationsParams{
+		Applications: []params.ScaleApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			ScaleChange:    3,
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ScaleApplicationResults{
+		Results: []params.ScaleApplicationResult{{
+			Info: &params.ScaleApplicationInfo{Scale: 5},
+		}},
+	})
+}
+
+func (s *ApplicationSuite) TestScaleApplicationsQuotaExceeded(c *gc.C) {
+	s.modelType = state.ModelTypeCAAS
+	s.modelQuotas = quota.ModelResources{Cores: 4}
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().GetScale().Return(1).AnyTimes()
+	app.EXPECT().UnitCount().Return(1)
+	app.EXPECT().StorageConstraints().Return(nil, nil)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+	s.backend.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Units: 1, Cores: 1}, nil)
+	s.backend.EXPECT().ResolveConstraints(gomock.Any()).Return(constraints.MustParse("cores=1"), nil)
+
+	results, err := s.api.ScaleApplications(params.ScaleApplicationsParams{
+		Applications: []params.ScaleApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			Scale:          5,
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeQuotaLimitExceeded)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `model cores quota of 4 exceeded \(1 in use, 4 requested\)`)
+}
+
+func (s *ApplicationSuite) TestSetConstraintsQuotaExceeded(c *gc.C) {
+	s.modelType = state.ModelTypeCAAS
+	s.modelQuotas = quota.ModelResources{Memory: 16384}
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	// Each of the 3 units grows from 4G of memory to 8G.
+	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().GetScale().Return(3)
+	app.EXPECT().UnitCount().Return(2)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+	s.backend.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Units: 3, Memory: 12288}, nil)
+	current := constraints.MustParse("arch=amd64 mem=4G cores=1 root-disk=8G")
+	s.backend.EXPECT().ResolveConstraints(current).Return(current, nil)
+	cons := constraints.MustParse("mem=8G")
+	s.backend.EXPECT().ResolveConstraints(cons).Return(cons, nil)
+
+	err := s.api.SetConstraints(params.SetConstraints{
+		ApplicationName: "postgresql",
+		Constraints:     cons,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `model memory quota of 16384MiB exceeded \(12288MiB in use, 12288MiB requested\)`)
+}
+
+func (s *ApplicationSuite) TestSetConstraintsWithinQuotas(c *gc.C) {
+	s.modelType = state.ModelTypeCAAS
+	s.modelQuotas = quota.ModelResources{Cores: 8}
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().GetScale().Return(2)
+	app.EXPECT().UnitCount().Return(2)
+	cons := constraints.MustParse("cores=4")
+	app.EXPECT().SetConstraints(cons).Return(nil)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+	s.backend.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Units: 2, Cores: 2}, nil)
+	current := constraints.MustParse("arch=amd64 mem=4G cores=1 root-disk=8G")
+	s.backend.EXPECT().ResolveConstraints(current).Return(current, nil)
+	s.backend.EXPECT().ResolveConstraints(cons).Return(cons, nil)
+
+	err := s.api.SetConstraints(params.SetConstraints{
+		ApplicationName: "postgresql",
+		Constraints:     cons,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *ApplicationSuite) TestScaleApplicationsNotAllowedForOperator(c *gc.C) {
 	s.modelType = state.ModelTypeCAAS
 	ctrl := s.setup(c)
@@ -2800,6 +2984,45 @@ func (s *ApplicationSuite) TestSetEmptyConfigMasterBranch(c *gc.C) {
 	c.Assert(result.OneError(), jc.ErrorIsNil)
 }
 
+func (s *ApplicationSuite) TestSetConfigEgressAllow(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	schemaFields, defaults, err := application.ApplicationConfigSchema(state.ModelTypeIAAS)
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().UpdateApplicationConfig(coreconfig.ConfigAttributes{
+		"egress-allow": "10.0.0.0/8:5432/tcp",
+	}, nil, schemaFields, defaults)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+
+	result, err := s.api.SetConfigs(params.ConfigSetArgs{
+		Args: []params.ConfigSet{{
+			ApplicationName: "postgresql",
+			Config:          map[string]string{"egress-allow": "10.0.0.0/8:5432/tcp"},
+			Generation:      "master",
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+}
+
+func (s *ApplicationSuite) TestSetConfigInvalidEgressAllow(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+
+	result, err := s.api.SetConfigs(params.ConfigSetArgs{
+		Args: []params.ConfigSet{{
+			ApplicationName: "postgresql",
+			Config:          map[string]string{"egress-allow": "10.0.0.0/8"},
+			Generation:      "master",
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `parsing settings for application: invalid "egress-allow" config: egress rule "10.0.0.0/8", expected <cidr>:<port-range> not valid`)
+}
+
 func (s *ApplicationSuite) TestUnsetApplicationConfig(c *gc.C) {
 	s.modelType = state.ModelTypeCAAS
 	ctrl := s.setup(c)
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/version/v2"
 	"gopkg.in/juju/environschema.v1"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/facades/client/charms/services"
 	"github.com/juju/juju/cloud"
@@ -61,6 +62,8 @@ type Backend interface {
 	Branch(string) (Generation, error)
 	state.EndpointBinding
 	ModelConstraints() (constraints.Value, error)
+	ResolveConstraints(constraints.Value) (constraints.Value, error)
+	common.ModelQuotaBackend
 	services.StateBackend
 }
 
@@ -99,6 +102,7 @@ type Application interface {
 	Life() state.Life
 	SetCharm(state.SetCharmConfig) error
 	SetConstraints(constraints.Value) error
+	StorageConstraints() (map[string]state.StorageConstraints, error)
 	MergeExposeSettings(map[string]state.ExposedEndpoint) error
 	UnsetExposeSettings([]string) error
 	SetMetricCredentials([]byte) error
@@ -108,6 +112,8 @@ type Application interface {
 	UpdateApplicationConfig(coreconfig.ConfigAttributes, []string, environschema.Fields, schema.Defaults) error
 	SetScale(int, int64, bool) error
 	ChangeScale(int) (int, error)
+	GetScale() int
+	UnitCount() int
 	AgentTools() (*tools.Tools, error)
 	MergeBindings(*state.Bindings, bool) error
 	Relations() ([]Relation, error)
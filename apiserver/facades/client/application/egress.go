@@ -0,0 +1,48 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/core/config"
+	"github.com/juju/juju/core/network/firewall"
+)
+
+// EgressAllowConfigOptionName is the option name used to hold the
+// destinations an application's machines may reach, as a comma separated
+// list of <cidr>:<port-range> rules. When unset, outbound traffic is not
+// restricted.
+const EgressAllowConfigOptionName = "egress-allow"
+
+var egressFields = environschema.Fields{
+	EgressAllowConfigOptionName: {
+		Description: "Comma separated list of <cidr>:<port-range> destinations the application may connect to; unrestricted when empty",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+}
+
+// iaasFields holds the application config fields supported by applications
+// in IAAS models.
+var iaasFields = func() environschema.Fields {
+	fields := make(environschema.Fields)
+	for name, field := range trustFields {
+		fields[name] = field
+	}
+	for name, field := range egressFields {
+		fields[name] = field
+	}
+	return fields
+}()
+
+// EgressRules returns the egress rules held in the application config.
+func EgressRules(appConfig config.ConfigAttributes) (firewall.EgressRules, error) {
+	rules, err := firewall.ParseEgressRules(appConfig.GetString(EgressAllowConfigOptionName, ""))
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid %q config", EgressAllowConfigOptionName)
+	}
+	return rules, nil
+}
@@ -11,6 +11,7 @@ var (
 	ParseSettingsCompatible = parseSettingsCompatible
 	GetStorageState         = getStorageState
 	ValidateSecretConfig    = validateSecretConfig
+	ApplicationConfigSchema = applicationConfigSchema
 )
 
 func GetState(st *state.State) Backend {
@@ -86,6 +86,11 @@ func (s *getSuite) TestClientApplicationGetIAASModelSmokeTest(c *gc.C) {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"egress-allow": map[string]interface{}{
+				"description": "Comma separated list of <cidr>:<port-range> destinations the application may connect to; unrestricted when empty",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
 			"trust": map[string]interface{}{
 				"default":     false,
 				"description": "Does this application have access to trusted credentials",
@@ -267,6 +272,11 @@ var getTests = []struct {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"egress-allow": map[string]interface{}{
+				"description": "Comma separated list of <cidr>:<port-range> destinations the application may connect to; unrestricted when empty",
+				"source":      "unset",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -333,6 +343,11 @@ var getTests = []struct {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"egress-allow": map[string]interface{}{
+				"description": "Comma separated list of <cidr>:<port-range> destinations the application may connect to; unrestricted when empty",
+				"source":      "unset",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -357,6 +372,11 @@ var getTests = []struct {
 		CharmConfig: map[string]interface{}{},
 		Base:        params.Base{Name: "ubuntu", Channel: "22.04/stable"},
 		ApplicationConfig: map[string]interface{}{
+			"egress-allow": map[string]interface{}{
+				"description": "Comma separated list of <cidr>:<port-range> destinations the application may connect to; unrestricted when empty",
+				"source":      "unset",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -387,6 +407,11 @@ var getTests = []struct {
 		CharmConfig: map[string]interface{}{},
 		Base:        params.Base{Name: "ubuntu", Channel: "22.04/stable"},
 		ApplicationConfig: map[string]interface{}{
+			"egress-allow": map[string]interface{}{
+				"description": "Comma separated list of <cidr>:<port-range> destinations the application may connect to; unrestricted when empty",
+				"source":      "unset",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -20,6 +20,7 @@ import (
 	crossmodel "github.com/juju/juju/core/crossmodel"
 	instance "github.com/juju/juju/core/instance"
 	network "github.com/juju/juju/core/network"
+	quota "github.com/juju/juju/core/quota"
 	status "github.com/juju/juju/core/status"
 	config0 "github.com/juju/juju/environs/config"
 	state "github.com/juju/juju/state"
@@ -364,6 +365,36 @@ func (mr *MockBackendMockRecorder) ModelConstraints() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelConstraints", reflect.TypeOf((*MockBackend)(nil).ModelConstraints))
 }
 
+// ModelQuotas mocks base method.
+func (m *MockBackend) ModelQuotas() (quota.ModelResources, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModelQuotas")
+	ret0, _ := ret[0].(quota.ModelResources)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModelQuotas indicates an expected call of ModelQuotas.
+func (mr *MockBackendMockRecorder) ModelQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelQuotas", reflect.TypeOf((*MockBackend)(nil).ModelQuotas))
+}
+
+// ModelResourceUsage mocks base method.
+func (m *MockBackend) ModelResourceUsage() (quota.ModelResources, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModelResourceUsage")
+	ret0, _ := ret[0].(quota.ModelResources)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModelResourceUsage indicates an expected call of ModelResourceUsage.
+func (mr *MockBackendMockRecorder) ModelResourceUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelResourceUsage", reflect.TypeOf((*MockBackend)(nil).ModelResourceUsage))
+}
+
 // ModelUUID mocks base method.
 func (m *MockBackend) ModelUUID() string {
 	m.ctrl.T.Helper()
@@ -452,6 +483,21 @@ func (mr *MockBackendMockRecorder) RemovePendingResources(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePendingResources", reflect.TypeOf((*MockBackend)(nil).RemovePendingResources), arg0, arg1)
 }
 
+// ResolveConstraints mocks base method.
+func (m *MockBackend) ResolveConstraints(arg0 constraints.Value) (constraints.Value, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveConstraints", arg0)
+	ret0, _ := ret[0].(constraints.Value)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveConstraints indicates an expected call of ResolveConstraints.
+func (mr *MockBackendMockRecorder) ResolveConstraints(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveConstraints", reflect.TypeOf((*MockBackend)(nil).ResolveConstraints), arg0)
+}
+
 // Resources mocks base method.
 func (m *MockBackend) Resources() application.Resources {
 	m.ctrl.T.Helper()
@@ -1253,6 +1299,20 @@ func (mr *MockApplicationMockRecorder) ExposedEndpoints() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExposedEndpoints", reflect.TypeOf((*MockApplication)(nil).ExposedEndpoints))
 }
 
+// GetScale mocks base method.
+func (m *MockApplication) GetScale() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScale")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetScale indicates an expected call of GetScale.
+func (mr *MockApplicationMockRecorder) GetScale() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScale", reflect.TypeOf((*MockApplication)(nil).GetScale))
+}
+
 // IsExposed mocks base method.
 func (m *MockApplication) IsExposed() bool {
 	m.ctrl.T.Helper()
@@ -1436,6 +1496,35 @@ func (mr *MockApplicationMockRecorder) SetScale(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScale", reflect.TypeOf((*MockApplication)(nil).SetScale), arg0, arg1, arg2)
 }
 
+// StorageConstraints mocks base method.
+func (m *MockApplication) StorageConstraints() (map[string]state.StorageConstraints, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageConstraints")
+	ret0, _ := ret[0].(map[string]state.StorageConstraints)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageConstraints indicates an expected call of StorageConstraints.
+func (mr *MockApplicationMockRecorder) StorageConstraints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageConstraints", reflect.TypeOf((*MockApplication)(nil).StorageConstraints))
+}
+
+// UnitCount mocks base method.
+func (m *MockApplication) UnitCount() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnitCount")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// UnitCount indicates an expected call of UnitCount.
+func (mr *MockApplicationMockRecorder) UnitCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnitCount", reflect.TypeOf((*MockApplication)(nil).UnitCount))
+}
+
 // UnsetExposeSettings mocks base method.
 func (m *MockApplication) UnsetExposeSettings(arg0 []string) error {
 	m.ctrl.T.Helper()
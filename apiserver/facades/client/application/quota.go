@@ -0,0 +1,219 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/charm/v12"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/quota"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+)
+
+// unitResourcesFunc returns the constraints of the units of an
+// application, and the size of the storage created for each unit.
+type unitResourcesFunc func() (constraints.Value, uint64, error)
+
+// checkUnitQuotas returns an error if adding numUnits units of an
+// application, with the given placement directives, would take the
+// model past any of its resource quotas.
+//
+// In IAAS models, each unit that is not placed on an existing machine
+// counts as a new machine, with the cores and memory of the unit
+// constraints combined with the model constraints. Units assigned by
+// policy may end up on an existing clean machine, in which case less
+// is consumed than was checked. In CAAS models, each unit consumes the
+// cores and memory of its constraints.
+//
+// All the units are checked before any is added; state checks each
+// unit, and each machine added for one, again when it is committed.
+func checkUnitQuotas(
+	backend Backend,
+	modelType state.ModelType,
+	numUnits int,
+	placement []*instance.Placement,
+	unitResources unitResourcesFunc,
+) error {
+	if numUnits < 1 {
+		return nil
+	}
+	checker, err := common.NewModelQuotaChecker(backend)
+	if err != nil || checker == nil {
+		return errors.Trace(err)
+	}
+
+	cons, storageSize, err := unitResources()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cons, err = backend.ResolveConstraints(cons); err != nil {
+		return errors.Trace(err)
+	}
+	compute := quota.ConstraintsResources(cons)
+	requested := quota.ModelResources{
+		Units:   numUnits,
+		Storage: storageSize * uint64(numUnits),
+	}
+	if modelType != state.ModelTypeIAAS {
+		requested = requested.Add(compute.Times(numUnits))
+	} else {
+		for i := 0; i < numUnits; i++ {
+			var p *instance.Placement
+			if i < len(placement) {
+				p = placement[i]
+			}
+			requested = requested.Add(placementResources(p, compute))
+		}
+	}
+	checker.Check(requested)
+	return checker.Outcome()
+}
+
+// checkConstraintsQuotas returns an error if setting the constraints of
+// an application in a CAAS model would take the model past its cores
+// or memory quotas, as each of the application's units consumes the
+// cores and memory of its constraints. In IAAS models, application
+// constraints only apply to machines added for new units, which are
+// checked when the units are added, so nothing is checked. State checks
+// the quotas again when the constraints are committed.
+func checkConstraintsQuotas(
+	backend Backend,
+	modelType state.ModelType,
+	app Application,
+	cons constraints.Value,
+) error {
+	if modelType == state.ModelTypeIAAS {
+		return nil
+	}
+	checker, err := common.NewModelQuotaChecker(backend)
+	if err != nil || checker == nil {
+		return errors.Trace(err)
+	}
+	numUnits := applicationScale(app)
+	if numUnits < 1 {
+		return nil
+	}
+
+	current, err := app.Constraints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if current, err = backend.ResolveConstraints(current); err != nil {
+		return errors.Trace(err)
+	}
+	if cons, err = backend.ResolveConstraints(cons); err != nil {
+		return errors.Trace(err)
+	}
+	var requested quota.ModelResources
+	from, to := quota.ConstraintsResources(current), quota.ConstraintsResources(cons)
+	if to.Cores > from.Cores {
+		requested.Cores = to.Cores - from.Cores
+	}
+	if to.Memory > from.Memory {
+		requested.Memory = to.Memory - from.Memory
+	}
+	checker.Check(requested.Times(numUnits))
+	return checker.Outcome()
+}
+
+// checkScaleQuotas returns an error if scaling an application in a
+// CAAS model, either to scale or by scaleChange, would take the model
+// past any of its resource quotas. Scaling up counts the units being
+// added as checkUnitQuotas does. State checks the quotas again when the
+// scale is committed.
+func checkScaleQuotas(backend Backend, app Application, scale, scaleChange int) error {
+	quotas, err := backend.ModelQuotas()
+	if err != nil || quotas.IsZero() {
+		return errors.Trace(err)
+	}
+	if scaleChange != 0 {
+		scale = app.GetScale() + scaleChange
+	}
+	return checkUnitQuotas(
+		backend, state.ModelTypeCAAS, scale-applicationScale(app), nil, applicationUnitResources(app),
+	)
+}
+
+// applicationScale returns the number of units of a CAAS application
+// counted towards the model's quotas: the greater of its scale and its
+// number of units.
+func applicationScale(app Application) int {
+	scale := app.GetScale()
+	if n := app.UnitCount(); n > scale {
+		scale = n
+	}
+	return scale
+}
+
+// applicationUnitResources returns the constraints of the units of an
+// existing application, and the size of the storage created for each.
+func applicationUnitResources(app Application) unitResourcesFunc {
+	return func() (constraints.Value, uint64, error) {
+		cons, err := app.Constraints()
+		if err != nil {
+			return constraints.Value{}, 0, errors.Trace(err)
+		}
+		storageCons, err := app.StorageConstraints()
+		if err != nil {
+			return constraints.Value{}, 0, errors.Trace(err)
+		}
+		var storageSize uint64
+		for _, sc := range storageCons {
+			storageSize += sc.Count * sc.Size
+		}
+		return cons, storageSize, nil
+	}
+}
+
+// placementResources returns the machines, cores and memory consumed by
+// placing a unit according to the given directive, where compute holds
+// the cores and memory of a new machine for the unit.
+func placementResources(p *instance.Placement, compute quota.ModelResources) quota.ModelResources {
+	newMachine := compute
+	newMachine.Machines = 1
+	if p == nil {
+		return newMachine
+	}
+	if p.Scope == instance.MachineScope {
+		// The unit is placed on an existing machine.
+		return quota.ModelResources{}
+	}
+	if _, err := instance.ParseContainerType(p.Scope); err == nil {
+		if p.Directive != "" {
+			// A new container on an existing machine.
+			return quota.ModelResources{Machines: 1}
+		}
+		// A new container on a new machine.
+		newMachine.Machines++
+	}
+	return newMachine
+}
+
+// unitStorageSize returns the size in MiB of the storage created for
+// each unit of a charm with the given metadata. Storage directives take
+// precedence over the charm's defaults. Shared storage is not created
+// per unit, and is not counted.
+func unitStorageSize(meta *charm.Meta, directives map[string]storage.Constraints) uint64 {
+	var size uint64
+	for name, store := range meta.Storage {
+		if store.Shared {
+			continue
+		}
+		count, each := uint64(store.CountMin), store.MinimumSize
+		if cons, ok := directives[name]; ok {
+			if cons.Count > 0 {
+				count = cons.Count
+			}
+			if cons.Size > 0 {
+				each = cons.Size
+			}
+		}
+		size += count * each
+	}
+	return size
+}
@@ -295,6 +295,20 @@ func (mr *MockApplicationMockRecorder) ExposedEndpoints() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExposedEndpoints", reflect.TypeOf((*MockApplication)(nil).ExposedEndpoints))
 }
 
+// GetScale mocks base method.
+func (m *MockApplication) GetScale() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScale")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetScale indicates an expected call of GetScale.
+func (mr *MockApplicationMockRecorder) GetScale() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScale", reflect.TypeOf((*MockApplication)(nil).GetScale))
+}
+
 // IsExposed mocks base method.
 func (m *MockApplication) IsExposed() bool {
 	m.ctrl.T.Helper()
@@ -478,6 +492,35 @@ func (mr *MockApplicationMockRecorder) SetScale(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScale", reflect.TypeOf((*MockApplication)(nil).SetScale), arg0, arg1, arg2)
 }
 
+// StorageConstraints mocks base method.
+func (m *MockApplication) StorageConstraints() (map[string]state.StorageConstraints, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageConstraints")
+	ret0, _ := ret[0].(map[string]state.StorageConstraints)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageConstraints indicates an expected call of StorageConstraints.
+func (mr *MockApplicationMockRecorder) StorageConstraints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageConstraints", reflect.TypeOf((*MockApplication)(nil).StorageConstraints))
+}
+
+// UnitCount mocks base method.
+func (m *MockApplication) UnitCount() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnitCount")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// UnitCount indicates an expected call of UnitCount.
+func (mr *MockApplicationMockRecorder) UnitCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnitCount", reflect.TypeOf((*MockApplication)(nil).UnitCount))
+}
+
 // UnsetExposeSettings mocks base method.
 func (m *MockApplication) UnsetExposeSettings(arg0 []string) error {
 	m.ctrl.T.Helper()
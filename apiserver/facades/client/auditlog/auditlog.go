@@ -0,0 +1,82 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+)
+
+// Backend provides access to the audit log records stored in the
+// controller database.
+type Backend interface {
+	AuditLogRecords(state.AuditLogFilter) ([]state.AuditLogRecord, error)
+}
+
+// AuditLogAPI is the server implementation for the AuditLog facade.
+type AuditLogAPI struct {
+	authorizer    facade.Authorizer
+	controllerTag names.ControllerTag
+	backend       Backend
+}
+
+// Records returns the audit log records matching the filter. Only
+// controller superusers may query the audit log.
+func (api *AuditLogAPI) Records(args params.AuditLogFilter) (params.AuditLogRecords, error) {
+	if err := api.authorizer.HasPermission(permission.SuperuserAccess, api.controllerTag); err != nil {
+		return params.AuditLogRecords{}, errors.Trace(err)
+	}
+	filter := state.AuditLogFilter{
+		ModelUUID: args.ModelUUID,
+		Method:    args.Method,
+		Limit:     args.Limit,
+	}
+	if args.User != "" {
+		if !names.IsValidUser(args.User) {
+			return params.AuditLogRecords{}, errors.NotValidf("user name %q", args.User)
+		}
+		// Records are stored with the id of the user's tag, which
+		// doesn't include the "@local" domain of local users.
+		filter.User = names.NewUserTag(args.User).Id()
+	}
+	if args.Since != nil {
+		filter.Since = *args.Since
+	}
+	records, err := api.backend.AuditLogRecords(filter)
+	if err != nil {
+		return params.AuditLogRecords{}, errors.Trace(err)
+	}
+	result := params.AuditLogRecords{
+		Records: make([]params.AuditLogRecord, len(records)),
+	}
+	for i, r := range records {
+		record := params.AuditLogRecord{
+			ConversationID: r.ConversationID,
+			ConnectionID:   r.ConnectionID,
+			Who:            r.Who,
+			What:           r.What,
+			ModelName:      r.ModelName,
+			ModelUUID:      r.ModelUUID,
+			RequestID:      r.RequestID,
+			When:           r.When,
+			Facade:         r.Facade,
+			Method:         r.Method,
+			Version:        r.Version,
+			Args:           r.Args,
+		}
+		for _, e := range r.Errors {
+			record.Errors = append(record.Errors, params.AuditLogError{
+				Message: e.Message,
+				Code:    e.Code,
+			})
+		}
+		result.Records[i] = record
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	facademocks "github.com/juju/juju/apiserver/facade/mocks"
+	"github.com/juju/juju/apiserver/facades/client/auditlog"
+	"github.com/juju/juju/apiserver/facades/client/auditlog/mocks"
+	coreauditlog "github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AuditLogSuite struct {
+	testing.IsolationSuite
+
+	authorizer *facademocks.MockAuthorizer
+	backend    *mocks.MockBackend
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) setup(c *gc.C) (*gomock.Controller, *auditlog.AuditLogAPI) {
+	ctrl := gomock.NewController(c)
+	s.authorizer = facademocks.NewMockAuthorizer(ctrl)
+	s.backend = mocks.NewMockBackend(ctrl)
+	return ctrl, auditlog.NewTestAPI(s.backend, s.authorizer, coretesting.ControllerTag)
+}
+
+func (s *AuditLogSuite) TestRecords(c *gc.C) {
+	ctrl, api := s.setup(c)
+	defer ctrl.Finish()
+
+	since := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	when := since.Add(time.Minute)
+	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(nil)
+	s.backend.EXPECT().AuditLogRecords(state.AuditLogFilter{
+		User:      "bob",
+		ModelUUID: coretesting.ModelTag.Id(),
+		Method:    "Client.FullStatus",
+		Since:     since,
+		Limit:     10,
+	}).Return([]state.AuditLogRecord{{
+		ConversationID: "0123456789abcdef",
+		ConnectionID:   "2AF",
+		Who:            "bob",
+		What:           "juju status",
+		ModelName:      "admin/default",
+		ModelUUID:      coretesting.ModelTag.Id(),
+		RequestID:      1,
+		When:           when,
+		Facade:         "Client",
+		Method:         "FullStatus",
+		Version:        6,
+		Errors:         []coreauditlog.Error{{Message: "boom", Code: "bad"}},
+	}}, nil)
+
+	result, err := api.Records(params.AuditLogFilter{
+		User:      "bob@local",
+		ModelUUID: coretesting.ModelTag.Id(),
+		Method:    "Client.FullStatus",
+		Since:     &since,
+		Limit:     10,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.AuditLogRecords{
+		Records: []params.AuditLogRecord{{
+			ConversationID: "0123456789abcdef",
+			ConnectionID:   "2AF",
+			Who:            "bob",
+			What:           "juju status",
+			ModelName:      "admin/default",
+			ModelUUID:      coretesting.ModelTag.Id(),
+			RequestID:      1,
+			When:           when,
+			Facade:         "Client",
+			Method:         "FullStatus",
+			Version:        6,
+			Errors:         []params.AuditLogError{{Message: "boom", Code: "bad"}},
+		}},
+	})
+}
+
+func (s *AuditLogSuite) TestRecordsInvalidUser(c *gc.C) {
+	ctrl, api := s.setup(c)
+	defer ctrl.Finish()
+
+	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(nil)
+
+	_, err := api.Records(params.AuditLogFilter{User: "not a user"})
+	c.Assert(err, gc.ErrorMatches, `user name "not a user" not valid`)
+}
+
+func (s *AuditLogSuite) TestRecordsPermissionDenied(c *gc.C) {
+	ctrl, api := s.setup(c)
+	defer ctrl.Finish()
+
+	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(
+		errors.WithType(apiservererrors.ErrPerm, authentication.ErrorEntityMissingPermission))
+
+	_, err := api.Records(params.AuditLogFilter{})
+	c.Assert(err, jc.ErrorIs, authentication.ErrorEntityMissingPermission)
+}
@@ -0,0 +1,7 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlog provides the server implementation
+// for the AuditLog facade, used to query the controller's
+// audit log.
+package auditlog
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/juju/juju/apiserver/facades/client/auditlog (interfaces: Backend)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	state "github.com/juju/juju/state"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBackend is a mock of Backend interface.
+type MockBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackendMockRecorder
+}
+
+// MockBackendMockRecorder is the mock recorder for MockBackend.
+type MockBackendMockRecorder struct {
+	mock *MockBackend
+}
+
+// NewMockBackend creates a new mock instance.
+func NewMockBackend(ctrl *gomock.Controller) *MockBackend {
+	mock := &MockBackend{ctrl: ctrl}
+	mock.recorder = &MockBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackend) EXPECT() *MockBackendMockRecorder {
+	return m.recorder
+}
+
+// AuditLogRecords mocks base method.
+func (m *MockBackend) AuditLogRecords(arg0 state.AuditLogFilter) ([]state.AuditLogRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditLogRecords", arg0)
+	ret0, _ := ret[0].([]state.AuditLogRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditLogRecords indicates an expected call of AuditLogRecords.
+func (mr *MockBackendMockRecorder) AuditLogRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditLogRecords", reflect.TypeOf((*MockBackend)(nil).AuditLogRecords), arg0)
+}
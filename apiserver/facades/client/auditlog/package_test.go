@@ -0,0 +1,27 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"testing"
+
+	"github.com/juju/names/v4"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facade"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/backend.go github.com/juju/juju/apiserver/facades/client/auditlog Backend
+func TestPackage(t *testing.T) {
+	gc.TestingT(t)
+}
+
+// NewTestAPI returns an AuditLogAPI for testing.
+func NewTestAPI(backend Backend, authorizer facade.Authorizer, controllerTag names.ControllerTag) *AuditLogAPI {
+	return &AuditLogAPI{
+		authorizer:    authorizer,
+		controllerTag: controllerTag,
+		backend:       backend,
+	}
+}
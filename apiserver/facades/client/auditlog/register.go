@@ -0,0 +1,31 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"reflect"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+)
+
+// Register is called to expose a package of facades onto a given registry.
+func Register(registry facade.FacadeRegistry) {
+	registry.MustRegister("AuditLog", 1, func(ctx facade.Context) (facade.Facade, error) {
+		return newAuditLogAPI(ctx)
+	}, reflect.TypeOf((*AuditLogAPI)(nil)))
+}
+
+// newAuditLogAPI creates an AuditLogAPI.
+func newAuditLogAPI(ctx facade.Context) (*AuditLogAPI, error) {
+	if !ctx.Auth().AuthClient() {
+		return nil, apiservererrors.ErrPerm
+	}
+	st := ctx.State()
+	return &AuditLogAPI{
+		authorizer:    ctx.Auth(),
+		controllerTag: st.ControllerTag(),
+		backend:       st,
+	}, nil
+}
@@ -5,6 +5,7 @@ package backups
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/mgo/v3"
 	"github.com/juju/names/v4"
 
@@ -21,6 +22,8 @@ import (
 	"github.com/juju/juju/state/backups"
 )
 
+var logger = loggo.GetLogger("juju.apiserver.backups")
+
 // Backend exposes state.State functionality needed by the backups Facade.
 type Backend interface {
 	IsController() bool
@@ -45,6 +48,11 @@ type API struct {
 	machineID string
 }
 
+// APIv3 provides the Backups API facade for version 3.
+type APIv3 struct {
+	*API
+}
+
 // NewAPI creates a new instance of the Backups API facade.
 func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
 	err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
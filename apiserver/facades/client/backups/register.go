@@ -14,10 +14,22 @@ import (
 // Register is called to expose a package of facades onto a given registry.
 func Register(registry facade.FacadeRegistry) {
 	registry.MustRegister("Backups", 3, func(ctx facade.Context) (facade.Facade, error) {
+		return newFacadeV3(ctx)
+	}, reflect.TypeOf((*APIv3)(nil)))
+	registry.MustRegister("Backups", 4, func(ctx facade.Context) (facade.Facade, error) {
 		return newFacade(ctx)
 	}, reflect.TypeOf((*API)(nil)))
 }
 
+// newFacadeV3 provides the required signature for version 3 facade registration.
+func newFacadeV3(ctx facade.Context) (*APIv3, error) {
+	api, err := newFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv3{API: api}, nil
+}
+
 // newFacade provides the required signature for facade registration.
 func newFacade(ctx facade.Context) (*API, error) {
 	st := ctx.State()
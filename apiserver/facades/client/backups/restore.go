@@ -0,0 +1,62 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state/backups"
+)
+
+// Restore is the API method that requests juju to replace the state
+// of the controller with the contents of the specified backup archive.
+// The controller agents are restarted once the restore completes, so
+// the connection used to make the request will be dropped.
+func (a *API) Restore(args params.RestoreArgs) error {
+	if args.FileName == "" {
+		return errors.NotValidf("missing backup filename")
+	}
+	backupsMethods := newBackups(a.paths)
+
+	session := a.backend.MongoSession().Copy()
+	defer session.Close()
+
+	// Don't go if HA isn't ready.
+	if err := waitUntilReady(session, 60); err != nil {
+		return errors.Annotatef(err, "HA not ready; try again later")
+	}
+
+	nodes, err := a.backend.ControllerNodes()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	modelConfig, err := a.backend.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	controllerVersion, ok := modelConfig.AgentVersion()
+	if !ok {
+		return errors.NotFoundf("controller agent version")
+	}
+
+	mgoInfo, err := mongoInfo(a.paths.DataDir, a.machineID)
+	if err != nil {
+		return errors.Annotatef(err, "getting mongo info")
+	}
+	dbInfo, err := backups.NewDBInfo(mgoInfo, sessionShim{session})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	logger.Infof("restoring backup %q onto controller machine %q", args.FileName, a.machineID)
+	err = backupsMethods.Restore(args.FileName, dbInfo, backups.RestoreArgs{
+		ControllerVersion: controllerVersion,
+		HANodes:           int64(len(nodes)),
+	})
+	return errors.Trace(err)
+}
+
+// Restore isn't on the v3 API.
+func (*APIv3) Restore(_ struct{}) {}
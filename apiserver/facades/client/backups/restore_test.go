@@ -0,0 +1,48 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"github.com/juju/mgo/v3"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/backups"
+	"github.com/juju/juju/rpc/params"
+	jujuversion "github.com/juju/juju/version"
+)
+
+func (s *backupsSuite) TestRestoreOkay(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	fake := s.setBackups(c, s.meta, "")
+
+	err := s.api.Restore(params.RestoreArgs{FileName: "/tmp/juju-backup-20231010-101010.tar.gz"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.Calls, jc.DeepEquals, []string{"Restore"})
+	c.Check(fake.IDArg, gc.Equals, "/tmp/juju-backup-20231010-101010.tar.gz")
+	c.Check(fake.DBInfoArg, gc.NotNil)
+	c.Check(fake.RestoreArgs.ControllerVersion, gc.Equals, jujuversion.Current)
+	c.Check(fake.RestoreArgs.HANodes, gc.Equals, int64(0))
+}
+
+func (s *backupsSuite) TestRestoreMissingFilename(c *gc.C) {
+	fake := s.setBackups(c, s.meta, "")
+
+	err := s.api.Restore(params.RestoreArgs{})
+	c.Assert(err, gc.ErrorMatches, "missing backup filename not valid")
+	c.Check(fake.Calls, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestRestoreError(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	s.setBackups(c, nil, "failed!")
+
+	err := s.api.Restore(params.RestoreArgs{FileName: "/tmp/juju-backup-20231010-101010.tar.gz"})
+	c.Assert(err, gc.ErrorMatches, "failed!")
+}
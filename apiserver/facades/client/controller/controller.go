@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/juju/collections/set"
+	"github.com/juju/description/v4"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names/v4"
@@ -28,6 +29,7 @@ import (
 	"github.com/juju/juju/caas"
 	corecontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/leadership"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/multiwatcher"
@@ -60,11 +62,22 @@ type ControllerAPI struct {
 	controller *cache.Controller
 
 	multiwatcherFactory multiwatcher.Factory
+	leadershipReader    func(modelUUID string) (leadership.Reader, error)
+}
+
+// ControllerAPIv12 provides the Controller API facade v12.
+type ControllerAPIv12 struct {
+	*ControllerAPI
+}
+
+// ControllerAPIv11 provides the Controller API facade v11.
+type ControllerAPIv11 struct {
+	*ControllerAPIv12
 }
 
 // LatestAPI is used for testing purposes to create the latest
 // controller API.
-var LatestAPI = newControllerAPIv11
+var LatestAPI = newControllerAPI
 
 // TestingAPI is an escape hatch for requesting a controller API that won't
 // allow auth to correctly happen for ModelStatus. I'm not convicned this
@@ -82,6 +95,7 @@ func NewControllerAPI(
 	hub facade.Hub,
 	factory multiwatcher.Factory,
 	controller *cache.Controller,
+	leadershipReader func(modelUUID string) (leadership.Reader, error),
 ) (*ControllerAPI, error) {
 	if !authorizer.AuthClient() {
 		return nil, errors.Trace(apiservererrors.ErrPerm)
@@ -123,6 +137,7 @@ func NewControllerAPI(
 		hub:                 hub,
 		multiwatcherFactory: factory,
 		controller:          controller,
+		leadershipReader:    leadershipReader,
 	}, nil
 }
 
@@ -616,38 +631,174 @@ func (c *ControllerAPI) InitiateMigration(reqArgs params.InitiateMigrationArgs)
 }
 
 func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string, error) {
-	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	hostedState, targetInfo, err := c.prepareMigration(spec)
 	if err != nil {
-		return "", errors.Annotate(err, "model tag")
+		return "", errors.Trace(err)
 	}
+	defer hostedState.Release()
 
-	// Ensure the model exists.
+	// Check if the migration is likely to succeed.
+	systemState, err := c.statePool.SystemState()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := runMigrationPrechecks(
+		hostedState.State, systemState,
+		&targetInfo, c.presence,
+	); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	// Trigger the migration.
+	mig, err := hostedState.CreateMigration(state.MigrationSpec{
+		InitiatedBy: c.apiUser,
+		TargetInfo:  targetInfo,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return mig.Id(), nil
+}
+
+// PrecheckMigration checks whether one or more models could be migrated
+// to other controllers, without starting the migrations. Rather than
+// stopping at the first problem, every problem found by the source
+// and target prechecks is reported. Each model is also exported and
+// imported into a throwaway model on the target controller.
+func (c *ControllerAPI) PrecheckMigration(reqArgs params.InitiateMigrationArgs) (
+	params.MigrationPrecheckResults, error,
+) {
+	out := params.MigrationPrecheckResults{
+		Results: make([]params.MigrationPrecheckResult, len(reqArgs.Specs)),
+	}
+	if err := c.checkIsSuperUser(); err != nil {
+		return out, errors.Trace(err)
+	}
+
+	for i, spec := range reqArgs.Specs {
+		result := &out.Results[i]
+		result.ModelTag = spec.ModelTag
+		report, err := c.precheckOneMigration(spec)
+		if err != nil {
+			result.Error = apiservererrors.ServerError(err)
+			continue
+		}
+		for _, blocker := range report.blockers {
+			result.Blockers = append(result.Blockers, params.MigrationBlocker{
+				Check:   blocker.Check,
+				Entity:  blocker.Entity,
+				Message: blocker.Message,
+			})
+		}
+		result.Warnings = report.warnings
+	}
+	return out, nil
+}
+
+func (c *ControllerAPI) precheckOneMigration(spec params.MigrationSpec) (migrationPrecheckReport, error) {
+	hostedState, targetInfo, err := c.prepareMigration(spec)
+	if err != nil {
+		return migrationPrecheckReport{}, errors.Trace(err)
+	}
+	defer hostedState.Release()
+
+	systemState, err := c.statePool.SystemState()
+	if err != nil {
+		return migrationPrecheckReport{}, errors.Trace(err)
+	}
+	return runMigrationPrecheckReport(hostedState.State, systemState, &targetInfo, c.presence)
+}
+
+// ExportModels serializes each of the specified models, along with
+// the charms, agent binaries and resources they use, so that they can
+// be written to a portable archive and later imported into another
+// controller. Exporting a model does not change it in any way.
+func (c *ControllerAPI) ExportModels(args params.Entities) (params.SerializedModelResults, error) {
+	out := params.SerializedModelResults{
+		Results: make([]params.SerializedModelResult, len(args.Entities)),
+	}
+	if err := c.checkIsSuperUser(); err != nil {
+		return out, errors.Trace(err)
+	}
+
+	for i, entity := range args.Entities {
+		serialized, err := c.exportOneModel(entity.Tag)
+		if err != nil {
+			out.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		out.Results[i].Result = &serialized
+	}
+	return out, nil
+}
+
+// exportOneModel serializes the model with the given tag. The
+// controller model can't be exported.
+func (c *ControllerAPI) exportOneModel(tag string) (params.SerializedModel, error) {
+	modelTag, err := names.ParseModelTag(tag)
+	if err != nil {
+		return params.SerializedModel{}, errors.Annotate(err, "model tag")
+	}
 	if modelExists, err := c.state.ModelExists(modelTag.Id()); err != nil {
-		return "", errors.Annotate(err, "reading model")
+		return params.SerializedModel{}, errors.Annotate(err, "reading model")
 	} else if !modelExists {
-		return "", errors.NotFoundf("model")
+		return params.SerializedModel{}, errors.NotFoundf("model")
 	}
 
 	hostedState, err := c.statePool.Get(modelTag.Id())
 	if err != nil {
-		return "", errors.Trace(err)
+		return params.SerializedModel{}, errors.Trace(err)
 	}
 	defer hostedState.Release()
+	if hostedState.IsController() {
+		return params.SerializedModel{}, errors.NotSupportedf("exporting the controller model")
+	}
+
+	reader, err := c.leadershipReader(modelTag.Id())
+	if err != nil {
+		return params.SerializedModel{}, errors.Trace(err)
+	}
+	leaders, err := reader.Leaders()
+	if err != nil {
+		return params.SerializedModel{}, errors.Trace(err)
+	}
+	model, err := hostedState.Export(leaders)
+	if err != nil {
+		return params.SerializedModel{}, errors.Annotate(err, "exporting model")
+	}
+	return common.SerializeModel(model)
+}
+
+// prepareMigration checks that the model to be migrated exists and
+// builds the target controller's details from the spec. The caller
+// must release the returned state.
+func (c *ControllerAPI) prepareMigration(spec params.MigrationSpec) (*state.PooledState, coremigration.TargetInfo, error) {
+	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	if err != nil {
+		return nil, coremigration.TargetInfo{}, errors.Annotate(err, "model tag")
+	}
+
+	// Ensure the model exists.
+	if modelExists, err := c.state.ModelExists(modelTag.Id()); err != nil {
+		return nil, coremigration.TargetInfo{}, errors.Annotate(err, "reading model")
+	} else if !modelExists {
+		return nil, coremigration.TargetInfo{}, errors.NotFoundf("model")
+	}
 
 	// Construct target info.
 	specTarget := spec.TargetInfo
 	controllerTag, err := names.ParseControllerTag(specTarget.ControllerTag)
 	if err != nil {
-		return "", errors.Annotate(err, "controller tag")
+		return nil, coremigration.TargetInfo{}, errors.Annotate(err, "controller tag")
 	}
 	authTag, err := names.ParseUserTag(specTarget.AuthTag)
 	if err != nil {
-		return "", errors.Annotate(err, "auth tag")
+		return nil, coremigration.TargetInfo{}, errors.Annotate(err, "auth tag")
 	}
 	var macs []macaroon.Slice
 	if specTarget.Macaroons != "" {
 		if err := json.Unmarshal([]byte(specTarget.Macaroons), &macs); err != nil {
-			return "", errors.Annotate(err, "invalid macaroons")
+			return nil, coremigration.TargetInfo{}, errors.Annotate(err, "invalid macaroons")
 		}
 	}
 	targetInfo := coremigration.TargetInfo{
@@ -660,27 +811,11 @@ func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string,
 		Macaroons:       macs,
 	}
 
-	// Check if the migration is likely to succeed.
-	systemState, err := c.statePool.SystemState()
-	if err != nil {
-		return "", errors.Trace(err)
-	}
-	if err := runMigrationPrechecks(
-		hostedState.State, systemState,
-		&targetInfo, c.presence,
-	); err != nil {
-		return "", errors.Trace(err)
-	}
-
-	// Trigger the migration.
-	mig, err := hostedState.CreateMigration(state.MigrationSpec{
-		InitiatedBy: c.apiUser,
-		TargetInfo:  targetInfo,
-	})
+	hostedState, err := c.statePool.Get(modelTag.Id())
 	if err != nil {
-		return "", errors.Trace(err)
+		return nil, coremigration.TargetInfo{}, errors.Trace(err)
 	}
-	return mig.Id(), nil
+	return hostedState, targetInfo, nil
 }
 
 // ModifyControllerAccess changes the model access granted to users.
@@ -846,6 +981,106 @@ var runMigrationPrechecks = func(
 	return errors.Annotate(err, "target prechecks failed")
 }
 
+// migrationPrecheckReport holds everything found when checking whether
+// a model could be migrated.
+type migrationPrecheckReport struct {
+	blockers []coremigration.Blocker
+	warnings []string
+}
+
+func (r *migrationPrecheckReport) add(check string, blockers ...coremigration.Blocker) {
+	for _, blocker := range blockers {
+		blocker.Check = check
+		r.blockers = append(r.blockers, blocker)
+	}
+}
+
+// runMigrationPrecheckReport runs the source and target prechecks
+// for a migration, collecting every problem rather than stopping at
+// the first, and then trial imports the model into the target
+// controller. No migration is created.
+var runMigrationPrecheckReport = func(
+	st, ctlrSt *state.State, targetInfo *coremigration.TargetInfo, presence facade.Presence,
+) (migrationPrecheckReport, error) {
+	var report migrationPrecheckReport
+
+	// Check model and source controller.
+	backend, err := migration.PrecheckShim(st, ctlrSt)
+	if err != nil {
+		return report, errors.Annotate(err, "creating backend")
+	}
+	modelPresence := presence.ModelPresence(st.ModelUUID())
+	controllerPresence := presence.ModelPresence(ctlrSt.ModelUUID())
+
+	blockers, err := migration.SourcePrecheckReport(
+		backend,
+		modelPresence, controllerPresence,
+		cloudspec.MakeCloudSpecGetterForModel(st),
+	)
+	if err != nil {
+		return report, errors.Annotate(err, "source prechecks failed")
+	}
+	report.add("source", blockers...)
+
+	// Check target controller.
+	modelInfo, srcUserList, err := makeModelInfo(st, ctlrSt)
+	if err != nil {
+		return report, errors.Trace(err)
+	}
+	targetConn, err := api.Open(targetToAPIInfo(targetInfo), migration.ControllerDialOpts())
+	if err != nil {
+		return report, errors.Annotate(err, "connect to target controller")
+	}
+	defer targetConn.Close()
+	dstUserList, err := getTargetControllerUsers(targetConn)
+	if err != nil {
+		return report, errors.Trace(err)
+	}
+	if err = srcUserList.checkCompatibilityWith(dstUserList); err != nil {
+		report.add("users", coremigration.Blocker{Entity: "model", Message: err.Error()})
+	}
+
+	client := migrationtarget.NewClient(targetConn)
+	blockers, err = client.PrecheckReport(modelInfo)
+	if errors.Is(err, errors.NotSupported) {
+		// Older controllers can only report the first problem.
+		report.warnings = append(report.warnings,
+			"target controller can only report the first problem found by its prechecks")
+		if err := client.Prechecks(modelInfo); err != nil {
+			blockers = []coremigration.Blocker{{Entity: "controller", Message: err.Error()}}
+		}
+	} else if err != nil {
+		return report, errors.Annotate(err, "target prechecks failed")
+	}
+	report.add("target", blockers...)
+
+	// Check that the model description can be imported by the target.
+	if err := trialImportModel(st, client); errors.Is(err, errors.NotSupported) {
+		report.warnings = append(report.warnings,
+			"target controller does not support trial imports, the model import was not checked")
+	} else if err != nil {
+		report.add("import", coremigration.Blocker{Entity: "model", Message: err.Error()})
+	}
+	return report, nil
+}
+
+// trialImportModel exports the model and imports it into a throwaway
+// model on the target controller.
+func trialImportModel(st *state.State, client *migrationtarget.Client) error {
+	if client.BestFacadeVersion() < 4 {
+		return errors.NotSupportedf("trial import")
+	}
+	model, err := st.Export(map[string]string{})
+	if err != nil {
+		return errors.Annotate(err, "exporting model")
+	}
+	bytes, err := description.Serialize(model)
+	if err != nil {
+		return errors.Annotate(err, "serializing model")
+	}
+	return errors.Annotate(client.TrialImport(bytes), "importing model")
+}
+
 // userList encapsulates information about the users who have been granted
 // access to a model or the users known to a particular controller.
 type userList struct {
@@ -1109,3 +1344,9 @@ func filterSet(s set.Strings, keep func(string) bool) set.Strings {
 
 	return out
 }
+
+// ExportModels isn't on the v12 API.
+func (*ControllerAPIv12) ExportModels(_, _ struct{}) {}
+
+// PrecheckMigration isn't on the v11 API.
+func (*ControllerAPIv11) PrecheckMigration(_, _ struct{}) {}
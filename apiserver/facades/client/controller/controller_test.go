@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/juju/clock"
+	"github.com/juju/description/v4"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names/v4"
@@ -31,6 +32,7 @@ import (
 	"github.com/juju/juju/cloud"
 	corecontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/cache"
+	coremigration "github.com/juju/juju/core/migration"
 	coremultiwatcher "github.com/juju/juju/core/multiwatcher"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/docker"
@@ -120,6 +122,7 @@ func (s *controllerSuite) SetUpTest(c *gc.C) {
 		Controller_:          cacheController,
 		Hub_:                 s.hub,
 		MultiwatcherFactory_: multiWatcherWorker,
+		LeadershipReader_:    fakeLeadershipReader{"wordpress": "wordpress/0"},
 	}
 	controller, err := controller.LatestAPI(s.context)
 	c.Assert(err, jc.ErrorIsNil)
@@ -314,7 +317,7 @@ func (s *controllerSuite) TestModelConfigFromNonController(c *gc.C) {
 		Tag:      s.Owner,
 		AdminTag: s.Owner,
 	}
-	controller, err := controller.NewControllerAPIv11(
+	controller, err := controller.LatestAPI(
 		facadetest.Context{
 			State_:     st,
 			StatePool_: s.StatePool,
@@ -344,7 +347,7 @@ func (s *controllerSuite) TestControllerConfigFromNonController(c *gc.C) {
 	defer st.Close()
 
 	authorizer := &apiservertesting.FakeAuthorizer{Tag: s.Owner}
-	controller, err := controller.NewControllerAPIv11(
+	controller, err := controller.LatestAPI(
 		facadetest.Context{
 			State_:     st,
 			StatePool_: s.StatePool,
@@ -676,6 +679,149 @@ func (s *controllerSuite) TestInitiateMigrationPrecheckFail(c *gc.C) {
 	c.Check(active, jc.IsFalse)
 }
 
+func (s *controllerSuite) TestPrecheckMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	controller.SetPrecheckReport(s, []coremigration.Blocker{{
+		Check:   "source",
+		Entity:  "machine 0",
+		Message: "machine 0 is dying",
+	}, {
+		Check:   "import",
+		Entity:  "model",
+		Message: "importing model: boom",
+	}}, []string{"careful"}, nil)
+
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: m.ModelTag().String(),
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag: randomControllerTag(),
+				Addrs:         []string{"1.1.1.1:1111"},
+				CACert:        "cert1",
+				AuthTag:       names.NewUserTag("admin1").String(),
+				Password:      "secret1",
+			},
+		}, {
+			ModelTag: randomModelTag(), // Doesn't exist.
+		}},
+	}
+	out, err := s.controller.PrecheckMigration(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 2)
+	c.Check(out.Results[0], jc.DeepEquals, params.MigrationPrecheckResult{
+		ModelTag: m.ModelTag().String(),
+		Blockers: []params.MigrationBlocker{{
+			Check:   "source",
+			Entity:  "machine 0",
+			Message: "machine 0 is dying",
+		}, {
+			Check:   "import",
+			Entity:  "model",
+			Message: "importing model: boom",
+		}},
+		Warnings: []string{"careful"},
+	})
+	c.Check(out.Results[1].ModelTag, gc.Equals, args.Specs[1].ModelTag)
+	c.Check(out.Results[1].Error, gc.ErrorMatches, "model not found")
+
+	// No migration is started.
+	active, err := st.IsMigrationActive()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsFalse)
+}
+
+func (s *controllerSuite) TestPrecheckMigrationFail(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	controller.SetPrecheckReport(s, nil, nil, errors.New("boom"))
+
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: m.ModelTag().String(),
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag: randomControllerTag(),
+				Addrs:         []string{"1.1.1.1:1111"},
+				CACert:        "cert1",
+				AuthTag:       names.NewUserTag("admin1").String(),
+				Password:      "secret1",
+			},
+		}},
+	}
+	out, err := s.controller.PrecheckMigration(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 1)
+	c.Check(out.Results[0].Error, gc.ErrorMatches, "boom")
+}
+
+func (s *controllerSuite) TestExportModels(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	f := factory.NewFactory(st, s.StatePool)
+	app := f.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	curl, _ := app.CharmURL()
+
+	out, err := s.controller.ExportModels(params.Entities{Entities: []params.Entity{
+		{Tag: model.ModelTag().String()},
+		{Tag: s.Model.ModelTag().String()},
+		{Tag: names.NewModelTag(utils.MustNewUUID().String()).String()},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 4)
+
+	c.Assert(out.Results[0].Error, gc.IsNil)
+	serialized := out.Results[0].Result
+	c.Assert(serialized, gc.NotNil)
+	c.Check(serialized.Charms, jc.DeepEquals, []string{*curl})
+	exported, err := description.Deserialize(serialized.Bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(exported.Tag(), gc.Equals, model.ModelTag())
+	c.Assert(exported.Applications(), gc.HasLen, 1)
+	c.Check(exported.Applications()[0].Leader(), gc.Equals, "wordpress/0")
+
+	c.Check(out.Results[1].Error, gc.ErrorMatches, "exporting the controller model not supported")
+	c.Check(out.Results[2].Error, gc.ErrorMatches, "model not found")
+	c.Check(out.Results[3].Error, gc.ErrorMatches, `model tag: "machine-0" is not a valid model tag`)
+
+	// The exported model is left untouched.
+	mode := model.MigrationMode()
+	c.Check(mode, gc.Equals, state.MigrationModeNone)
+}
+
+func (s *controllerSuite) TestExportModelsRequiresSuperuser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	endpoint, err := controller.LatestAPI(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.StatePool,
+			Resources_: s.resources,
+			Auth_:      apiservertesting.FakeAuthorizer{Tag: user.Tag()},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.ExportModels(params.Entities{Entities: []params.Entity{
+		{Tag: s.Model.ModelTag().String()},
+	}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type fakeLeadershipReader map[string]string
+
+func (r fakeLeadershipReader) Leaders() (map[string]string, error) {
+	return r, nil
+}
+
 func randomControllerTag() string {
 	uuid := utils.MustNewUUID().String()
 	return names.NewControllerTag(uuid).String()
@@ -895,7 +1041,7 @@ func (s *controllerSuite) TestGetControllerAccessPermissions(c *gc.C) {
 	anAuthoriser := apiservertesting.FakeAuthorizer{
 		Tag: user.Tag(),
 	}
-	endpoint, err := controller.NewControllerAPIv11(
+	endpoint, err := controller.LatestAPI(
 		facadetest.Context{
 			State_:     s.State,
 			StatePool_: s.StatePool,
@@ -982,7 +1128,7 @@ func (s *controllerSuite) TestConfigSetRequiresSuperUser(c *gc.C) {
 	anAuthoriser := apiservertesting.FakeAuthorizer{
 		Tag: user.Tag(),
 	}
-	endpoint, err := controller.NewControllerAPIv11(
+	endpoint, err := controller.LatestAPI(
 		facadetest.Context{
 			State_:     s.State,
 			StatePool_: s.StatePool,
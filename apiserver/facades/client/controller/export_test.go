@@ -19,10 +19,12 @@ func SetPrecheckResult(p patcher, err error) {
 	})
 }
 
+func SetPrecheckReport(p patcher, blockers []migration.Blocker, warnings []string, err error) {
+	p.PatchValue(&runMigrationPrecheckReport, func(*state.State, *state.State, *migration.TargetInfo, facade.Presence) (migrationPrecheckReport, error) {
+		return migrationPrecheckReport{blockers: blockers, warnings: warnings}, err
+	})
+}
+
 func NewControllerAPIForTest(backend Backend) *ControllerAPI {
 	return &ControllerAPI{state: backend}
 }
-
-var (
-	NewControllerAPIv11 = newControllerAPIv11
-)
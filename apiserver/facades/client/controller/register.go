@@ -6,6 +6,8 @@ package controller
 import (
 	"reflect"
 
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/facade"
 )
 
@@ -13,11 +15,35 @@ import (
 func Register(registry facade.FacadeRegistry) {
 	registry.MustRegister("Controller", 11, func(ctx facade.Context) (facade.Facade, error) {
 		return newControllerAPIv11(ctx)
+	}, reflect.TypeOf((*ControllerAPIv11)(nil)))
+	registry.MustRegister("Controller", 12, func(ctx facade.Context) (facade.Facade, error) {
+		return newControllerAPIv12(ctx)
+	}, reflect.TypeOf((*ControllerAPIv12)(nil)))
+	registry.MustRegister("Controller", 13, func(ctx facade.Context) (facade.Facade, error) {
+		return newControllerAPI(ctx)
 	}, reflect.TypeOf((*ControllerAPI)(nil)))
 }
 
 // newControllerAPIv11 creates a new ControllerAPIv11
-func newControllerAPIv11(ctx facade.Context) (*ControllerAPI, error) {
+func newControllerAPIv11(ctx facade.Context) (*ControllerAPIv11, error) {
+	api, err := newControllerAPIv12(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv11{ControllerAPIv12: api}, nil
+}
+
+// newControllerAPIv12 creates a new ControllerAPIv12
+func newControllerAPIv12(ctx facade.Context) (*ControllerAPIv12, error) {
+	api, err := newControllerAPI(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv12{ControllerAPI: api}, nil
+}
+
+// newControllerAPI creates a new ControllerAPI.
+func newControllerAPI(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	authorizer := ctx.Auth()
 	pool := ctx.StatePool()
@@ -36,5 +62,6 @@ func newControllerAPIv11(ctx facade.Context) (*ControllerAPI, error) {
 		hub,
 		factory,
 		controller,
+		ctx.LeadershipReader,
 	)
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/apiserver/common"
+	commonsecrets "github.com/juju/juju/apiserver/common/secrets"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/controller"
@@ -22,6 +23,8 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/secrets/provider"
+	_ "github.com/juju/juju/secrets/provider/all"
 	"github.com/juju/juju/state"
 )
 
@@ -82,6 +85,11 @@ func (api *HighAvailabilityAPI) enableHASingle(st *state.State, spec params.Cont
 	if err != nil {
 		return params.ControllersChanges{}, err
 	}
+	if spec.NumControllers != 1 {
+		if err := validateSecretBackends(st); err != nil {
+			return params.ControllersChanges{}, errors.Trace(err)
+		}
+	}
 
 	referenceMachine, err := getReferenceController(st, controllerIds)
 	if err != nil {
@@ -122,6 +130,26 @@ func (api *HighAvailabilityAPI) enableHASingle(st *state.State, spec params.Cont
 	return controllersChanges(changes), nil
 }
 
+// validateSecretBackends returns an error if any secret backend keeps
+// its content on the controller's local filesystem, since additional
+// controllers would not see that content.
+func validateSecretBackends(st *state.State) error {
+	backends, err := state.NewSecretBackends(st).ListSecretBackends()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, b := range backends {
+		p, err := commonsecrets.GetProvider(b.BackendType)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if provider.HasControllerContent(p) {
+			return errors.NotSupportedf("enabling HA with secret backend %q of type %q", b.Name, b.BackendType)
+		}
+	}
+	return nil
+}
+
 // getReferenceController looks up the ideal controller to use as a reference for Constraints and Release
 func getReferenceController(st *state.State, controllerIds []string) (*state.Machine, error) {
 	// Sort the controller IDs from low to high and take the first.
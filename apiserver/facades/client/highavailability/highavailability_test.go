@@ -288,6 +288,22 @@ func (s *clientSuite) TestBlockMakeHA(c *gc.C) {
 	c.Assert(machines, gc.HasLen, 1)
 }
 
+func (s *clientSuite) TestEnableHAFileSecretBackend(c *gc.C) {
+	_, err := state.NewSecretBackends(s.State).CreateSecretBackend(state.CreateSecretBackendParams{
+		Name:        "myfile",
+		BackendType: "file",
+		Config:      map[string]interface{}{"path": c.MkDir()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.enableHA(c, 3, emptyCons, nil)
+	c.Assert(err, gc.ErrorMatches, `enabling HA with secret backend "myfile" of type "file" not supported`)
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+}
+
 func (s *clientSuite) TestEnableHAPlacement(c *gc.C) {
 	placement := []string{"valid"}
 	enableHAResult, err := s.enableHA(c, 3, constraints.MustParse("mem=4G tags=foobar"), placement)
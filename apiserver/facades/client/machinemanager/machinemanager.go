@@ -23,6 +23,7 @@ import (
 	corebase "github.com/juju/juju/core/base"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	environscontext "github.com/juju/juju/environs/context"
@@ -303,6 +304,9 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 		Addresses:               sAddrs,
 		Placement:               placementDirective,
 	}
+	if err := mm.checkModelQuotas(template, p.ContainerType, p.ParentId); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if p.ContainerType == "" {
 		return mm.st.AddOneMachine(template)
 	}
@@ -312,6 +316,42 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	return mm.st.AddMachineInsideNewMachine(template, template, p.ContainerType)
 }
 
+// checkModelQuotas returns an error if adding a machine with the given
+// template would take the model past any of its resource quotas. The
+// cores and memory of a new top-level machine are counted from its
+// hardware characteristics if it is already provisioned, and otherwise
+// from its constraints combined with the model constraints. The quotas
+// are checked again when the machine is committed to state.
+func (mm *MachineManagerAPI) checkModelQuotas(
+	template state.MachineTemplate, containerType instance.ContainerType, parentId string,
+) error {
+	checker, err := common.NewModelQuotaChecker(mm.st)
+	if err != nil || checker == nil {
+		return errors.Trace(err)
+	}
+
+	requested := quota.ModelResources{Machines: 1}
+	for _, v := range template.Volumes {
+		requested.Storage += v.Volume.Size
+	}
+	switch {
+	case containerType == "" && template.InstanceId != "":
+		requested = requested.Add(quota.HardwareResources(template.HardwareCharacteristics))
+	case containerType == "" || parentId == "":
+		cons, err := mm.st.ResolveConstraints(template.Constraints)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		requested = requested.Add(quota.ConstraintsResources(cons))
+		if containerType != "" {
+			// The container is added to a new host machine.
+			requested.Machines++
+		}
+	}
+	checker.Check(requested)
+	return checker.Outcome()
+}
+
 // ProvisioningScript returns a shell script that, when run,
 // provisions a machine agent on the machine executing the script.
 func (mm *MachineManagerAPI) ProvisioningScript(args params.ProvisioningScriptParams) (params.ProvisioningScriptResult, error) {
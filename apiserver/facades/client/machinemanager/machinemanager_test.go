@@ -24,9 +24,11 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/machinemanager/mocks"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
@@ -79,6 +81,7 @@ type AddMachineManagerSuite struct {
 	pool          *mocks.MockPool
 	api           *machinemanager.MachineManagerAPI
 	model         *mocks.MockModel
+	quotas        quota.ModelResources
 
 	callContext context.ProviderCallContext
 }
@@ -86,6 +89,7 @@ type AddMachineManagerSuite struct {
 func (s *AddMachineManagerSuite) SetUpTest(c *gc.C) {
 	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
 	s.callContext = context.NewEmptyCloudCallContext()
+	s.quotas = quota.ModelResources{}
 }
 
 func (s *AddMachineManagerSuite) setup(c *gc.C) *gomock.Controller {
@@ -97,6 +101,7 @@ func (s *AddMachineManagerSuite) setup(c *gc.C) *gomock.Controller {
 	s.st = mocks.NewMockBackend(ctrl)
 	s.storageAccess = mocks.NewMockStorageInterface(ctrl)
 	s.st.EXPECT().GetBlockForType(state.ChangeBlock).Return(nil, false, nil).AnyTimes()
+	s.st.EXPECT().ModelQuotas().Return(s.quotas, nil).AnyTimes()
 
 	var err error
 	s.api, err = machinemanager.NewMachineManagerAPI(s.st,
@@ -184,6 +189,66 @@ func (s *AddMachineManagerSuite) TestAddMachinesStateError(c *gc.C) {
 	})
 }
 
+func (s *AddMachineManagerSuite) TestAddMachinesWithinQuotas(c *gc.C) {
+	s.quotas = quota.ModelResources{Machines: 4, Cores: 8, Storage: 4096}
+	defer s.setup(c).Finish()
+
+	s.st.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Machines: 1, Cores: 4, Storage: 1024}, nil)
+	cons := constraints.MustParse("mem=4G")
+	s.st.EXPECT().ResolveConstraints(cons).Return(constraints.MustParse("cores=4 mem=4G"), nil)
+	s.st.EXPECT().AddMachineInsideNewMachine(gomock.Any(), gomock.Any(), instance.LXD).Return(&state.Machine{}, nil)
+
+	results, err := s.api.AddMachines(params.AddMachines{
+		MachineParams: []params.AddMachineParams{{
+			Base:          &params.Base{Name: "ubuntu", Channel: "22.04"},
+			ContainerType: instance.LXD,
+			Constraints:   cons,
+			Disks:         []storage.Constraints{{Size: 1024, Count: 3}},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines, gc.HasLen, 1)
+	c.Assert(results.Machines[0].Error, gc.IsNil)
+}
+
+func (s *AddMachineManagerSuite) TestAddMachinesQuotaExceeded(c *gc.C) {
+	s.quotas = quota.ModelResources{Machines: 4, Cores: 8}
+	defer s.setup(c).Finish()
+
+	s.st.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Machines: 1, Cores: 4}, nil)
+	cons := constraints.MustParse("cores=8")
+	s.st.EXPECT().ResolveConstraints(cons).Return(cons, nil)
+
+	results, err := s.api.AddMachines(params.AddMachines{
+		MachineParams: []params.AddMachineParams{{
+			Base:        &params.Base{Name: "ubuntu", Channel: "22.04"},
+			Constraints: cons,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines, gc.HasLen, 1)
+	c.Assert(results.Machines[0].Error, jc.Satisfies, params.IsCodeQuotaLimitExceeded)
+	c.Assert(results.Machines[0].Error, gc.ErrorMatches, `model cores quota of 8 exceeded \(4 in use, 8 requested\)`)
+}
+
+func (s *AddMachineManagerSuite) TestAddContainerToExistingMachineCountsOnlyMachine(c *gc.C) {
+	s.quotas = quota.ModelResources{Machines: 2, Cores: 4}
+	defer s.setup(c).Finish()
+
+	s.st.EXPECT().ModelResourceUsage().Return(quota.ModelResources{Machines: 2, Cores: 4}, nil)
+
+	results, err := s.api.AddMachines(params.AddMachines{
+		MachineParams: []params.AddMachineParams{{
+			Base:          &params.Base{Name: "ubuntu", Channel: "22.04"},
+			ContainerType: instance.LXD,
+			ParentId:      "0",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Machines, gc.HasLen, 1)
+	c.Assert(results.Machines[0].Error, gc.ErrorMatches, `model machines quota of 2 exceeded \(2 in use, 1 requested\)`)
+}
+
 var _ = gc.Suite(&DestroyMachineManagerSuite{})
 
 type DestroyMachineManagerSuite struct {
@@ -50,6 +50,20 @@ func (mr *MockStorageAttachmentMockRecorder) Life() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Life", reflect.TypeOf((*MockStorageAttachment)(nil).Life))
 }
 
+// Resized mocks base method.
+func (m *MockStorageAttachment) Resized() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resized")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Resized indicates an expected call of Resized.
+func (mr *MockStorageAttachmentMockRecorder) Resized() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resized", reflect.TypeOf((*MockStorageAttachment)(nil).Resized))
+}
+
 // StorageInstance mocks base method.
 func (m *MockStorageAttachment) StorageInstance() names.StorageTag {
 	m.ctrl.T.Helper()
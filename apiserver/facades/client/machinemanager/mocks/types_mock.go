@@ -15,9 +15,11 @@ import (
 	charmhub "github.com/juju/juju/charmhub"
 	transport "github.com/juju/juju/charmhub/transport"
 	cloud "github.com/juju/juju/cloud"
+	constraints "github.com/juju/juju/core/constraints"
 	instance "github.com/juju/juju/core/instance"
 	model "github.com/juju/juju/core/model"
 	network "github.com/juju/juju/core/network"
+	quota "github.com/juju/juju/core/quota"
 	status "github.com/juju/juju/core/status"
 	config "github.com/juju/juju/environs/config"
 	state "github.com/juju/juju/state"
@@ -185,6 +187,51 @@ func (mr *MockBackendMockRecorder) Model() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Model", reflect.TypeOf((*MockBackend)(nil).Model))
 }
 
+// ModelQuotas mocks base method.
+func (m *MockBackend) ModelQuotas() (quota.ModelResources, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModelQuotas")
+	ret0, _ := ret[0].(quota.ModelResources)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModelQuotas indicates an expected call of ModelQuotas.
+func (mr *MockBackendMockRecorder) ModelQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelQuotas", reflect.TypeOf((*MockBackend)(nil).ModelQuotas))
+}
+
+// ModelResourceUsage mocks base method.
+func (m *MockBackend) ModelResourceUsage() (quota.ModelResources, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModelResourceUsage")
+	ret0, _ := ret[0].(quota.ModelResources)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModelResourceUsage indicates an expected call of ModelResourceUsage.
+func (mr *MockBackendMockRecorder) ModelResourceUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelResourceUsage", reflect.TypeOf((*MockBackend)(nil).ModelResourceUsage))
+}
+
+// ResolveConstraints mocks base method.
+func (m *MockBackend) ResolveConstraints(arg0 constraints.Value) (constraints.Value, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveConstraints", arg0)
+	ret0, _ := ret[0].(constraints.Value)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveConstraints indicates an expected call of ResolveConstraints.
+func (mr *MockBackendMockRecorder) ResolveConstraints(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveConstraints", reflect.TypeOf((*MockBackend)(nil).ResolveConstraints), arg0)
+}
+
 // ToolsStorage mocks base method.
 func (m *MockBackend) ToolsStorage() (binarystorage.StorageCloser, error) {
 	m.ctrl.T.Helper()
@@ -10,9 +10,11 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
@@ -32,6 +34,7 @@ func StateBackend(st *state.State) Backend {
 
 type Backend interface {
 	network.SpaceLookup
+	common.ModelQuotaBackend
 
 	// Application returns a application state by name.
 	Application(string) (Application, error)
@@ -44,6 +47,7 @@ type Backend interface {
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
 	ToolsStorage() (binarystorage.StorageCloser, error)
+	ResolveConstraints(cons constraints.Value) (constraints.Value, error)
 }
 
 type BackendState interface {
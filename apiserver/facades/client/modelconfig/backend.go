@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/quota"
 	coresecrets "github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
@@ -27,6 +28,9 @@ type Backend interface {
 	SpaceByName(string) error
 	SetModelConstraints(value constraints.Value) error
 	ModelConstraints() (constraints.Value, error)
+	ModelQuotas() (quota.ModelResources, error)
+	SetModelQuotas(quota.ModelResources) error
+	ModelResourceUsage() (quota.ModelResources, error)
 	GetSecretBackend(string) (*coresecrets.SecretBackend, error)
 }
 
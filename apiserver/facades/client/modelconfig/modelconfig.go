@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	corebase "github.com/juju/juju/core/base"
 	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
@@ -28,13 +29,19 @@ type ModelConfigAPI struct {
 	check   *common.BlockChecker
 }
 
-// ModelConfigAPIV3 is currently the latest.
-type ModelConfigAPIV3 struct {
+// ModelConfigAPIV4 is currently the latest.
+type ModelConfigAPIV4 struct {
 	*ModelConfigAPI
 }
 
+// ModelConfigAPIV3 is version 3 of the ModelConfig API, which lacks
+// model quotas.
+type ModelConfigAPIV3 struct {
+	*ModelConfigAPIV4
+}
+
 // NewModelConfigAPI creates a new instance of the ModelConfig Facade.
-func NewModelConfigAPI(backend Backend, authorizer facade.Authorizer) (*ModelConfigAPIV3, error) {
+func NewModelConfigAPI(backend Backend, authorizer facade.Authorizer) (*ModelConfigAPIV4, error) {
 	if !authorizer.AuthClient() {
 		return nil, apiservererrors.ErrPerm
 	}
@@ -43,7 +50,7 @@ func NewModelConfigAPI(backend Backend, authorizer facade.Authorizer) (*ModelCon
 		auth:    authorizer,
 		check:   common.NewBlockChecker(backend),
 	}
-	return &ModelConfigAPIV3{client}, nil
+	return &ModelConfigAPIV4{client}, nil
 }
 
 func (c *ModelConfigAPI) checkCanWrite() error {
@@ -418,6 +425,63 @@ func (c *ModelConfigAPI) SetModelConstraints(args params.SetConstraints) error {
 	return c.backend.SetModelConstraints(args.Constraints)
 }
 
+// GetModelQuotas returns the resource quotas of the model, along with
+// the resources the model currently consumes.
+func (c *ModelConfigAPI) GetModelQuotas() (params.ModelQuotasResult, error) {
+	if err := c.canReadModel(); err != nil {
+		return params.ModelQuotasResult{}, err
+	}
+
+	quotas, err := c.backend.ModelQuotas()
+	if err != nil {
+		return params.ModelQuotasResult{}, errors.Trace(err)
+	}
+	usage, err := c.backend.ModelResourceUsage()
+	if err != nil {
+		return params.ModelQuotasResult{}, errors.Trace(err)
+	}
+	return params.ModelQuotasResult{
+		Quotas: paramsModelResources(quotas),
+		Usage:  paramsModelResources(usage),
+	}, nil
+}
+
+// SetModelQuotas replaces the resource quotas of the model. Quotas
+// limit what the users of a model may consume, so only controller
+// administrators may set them.
+func (c *ModelConfigAPI) SetModelQuotas(args params.SetModelQuotas) error {
+	if err := c.isControllerAdmin(); err != nil {
+		return err
+	}
+
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.backend.SetModelQuotas(quota.ModelResources{
+		Machines: args.Quotas.Machines,
+		Units:    args.Quotas.Units,
+		Cores:    args.Quotas.Cores,
+		Memory:   args.Quotas.Memory,
+		Storage:  args.Quotas.Storage,
+	})
+}
+
+// GetModelQuotas isn't on the V3 API.
+func (*ModelConfigAPIV3) GetModelQuotas(_, _ struct{}) {}
+
+// SetModelQuotas isn't on the V3 API.
+func (*ModelConfigAPIV3) SetModelQuotas(_, _ struct{}) {}
+
+func paramsModelResources(r quota.ModelResources) params.ModelResources {
+	return params.ModelResources{
+		Machines: r.Machines,
+		Units:    r.Units,
+		Cores:    r.Cores,
+		Memory:   r.Memory,
+		Storage:  r.Storage,
+	}
+}
+
 // SetSLALevel sets the sla level on the model.
 func (c *ModelConfigAPI) SetSLALevel(args params.ModelSLA) error {
 	if err := c.checkCanWrite(); err != nil {
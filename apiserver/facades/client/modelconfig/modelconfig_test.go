@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/modelconfig/mocks"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/quota"
 	coresecrets "github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
@@ -31,7 +32,7 @@ type modelconfigSuite struct {
 	coretesting.JujuOSEnvSuite
 	backend    *mockBackend
 	authorizer apiservertesting.FakeAuthorizer
-	api        *modelconfig.ModelConfigAPIV3
+	api        *modelconfig.ModelConfigAPIV4
 }
 
 var _ = gc.Suite(&modelconfigSuite{})
@@ -452,15 +453,71 @@ func (s *modelconfigSuite) TestClientGetModelConstraints(c *gc.C) {
 	c.Assert(obtained.Constraints, gc.DeepEquals, cons)
 }
 
+func (s *modelconfigSuite) TestGetModelQuotas(c *gc.C) {
+	s.backend.quotas = quota.ModelResources{Machines: 10, Cores: 40}
+	s.backend.usage = quota.ModelResources{Machines: 2, Units: 3, Cores: 8, Memory: 4096}
+	result, err := s.api.GetModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ModelQuotasResult{
+		Quotas: params.ModelResources{Machines: 10, Cores: 40},
+		Usage:  params.ModelResources{Machines: 2, Units: 3, Cores: 8, Memory: 4096},
+	})
+}
+
+func (s *modelconfigSuite) TestSetModelQuotas(c *gc.C) {
+	err := s.api.SetModelQuotas(params.SetModelQuotas{
+		Quotas: params.ModelResources{Units: 5, Memory: 8192},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.backend.quotas, jc.DeepEquals, quota.ModelResources{Units: 5, Memory: 8192})
+}
+
+func (s *modelconfigSuite) TestSetModelQuotasNotControllerAdmin(c *gc.C) {
+	// Model administrators may not raise their own quotas.
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:         names.NewUserTag("bruce@local"),
+		HasWriteTag: names.NewUserTag("bruce@local"),
+		AdminTag:    names.NewUserTag("mary@local"),
+	}
+	err := s.api.SetModelQuotas(params.SetModelQuotas{
+		Quotas: params.ModelResources{Units: 5},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(s.backend.quotas.IsZero(), jc.IsTrue)
+}
+
+func (s *modelconfigSuite) TestBlockChangesSetModelQuotas(c *gc.C) {
+	s.blockAllChanges(c, "TestBlockChangesSetModelQuotas")
+	err := s.api.SetModelQuotas(params.SetModelQuotas{
+		Quotas: params.ModelResources{Units: 5},
+	})
+	s.assertBlocked(c, err, "TestBlockChangesSetModelQuotas")
+}
+
 type mockBackend struct {
 	cfg           config.ConfigValues
 	old           *config.Config
 	b             state.BlockType
 	msg           string
 	cons          constraints.Value
+	quotas        quota.ModelResources
+	usage         quota.ModelResources
 	secretBackend *coresecrets.SecretBackend
 }
 
+func (m *mockBackend) ModelQuotas() (quota.ModelResources, error) {
+	return m.quotas, nil
+}
+
+func (m *mockBackend) SetModelQuotas(quotas quota.ModelResources) error {
+	m.quotas = quotas
+	return nil
+}
+
+func (m *mockBackend) ModelResourceUsage() (quota.ModelResources, error) {
+	return m.usage, nil
+}
+
 func (m *mockBackend) SetModelConstraints(value constraints.Value) error {
 	m.cons = value
 	return nil
@@ -16,10 +16,22 @@ func Register(registry facade.FacadeRegistry) {
 	registry.MustRegister("ModelConfig", 3, func(ctx facade.Context) (facade.Facade, error) {
 		return newFacadeV3(ctx)
 	}, reflect.TypeOf((*ModelConfigAPIV3)(nil)))
+	registry.MustRegister("ModelConfig", 4, func(ctx facade.Context) (facade.Facade, error) {
+		return newFacadeV4(ctx) // add model quotas
+	}, reflect.TypeOf((*ModelConfigAPIV4)(nil)))
 }
 
 // newFacadeV3 is used for API registration.
 func newFacadeV3(ctx facade.Context) (*ModelConfigAPIV3, error) {
+	api, err := newFacadeV4(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ModelConfigAPIV3{api}, nil
+}
+
+// newFacadeV4 is used for API registration.
+func newFacadeV4(ctx facade.Context) (*ModelConfigAPIV4, error) {
 	auth := ctx.Auth()
 
 	model, err := ctx.State().Model()
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/juju/juju/apiserver/facades/client/secretbackends (interfaces: ControllerState)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockControllerState is a mock of ControllerState interface.
+type MockControllerState struct {
+	ctrl     *gomock.Controller
+	recorder *MockControllerStateMockRecorder
+}
+
+// MockControllerStateMockRecorder is the mock recorder for MockControllerState.
+type MockControllerStateMockRecorder struct {
+	mock *MockControllerState
+}
+
+// NewMockControllerState creates a new mock instance.
+func NewMockControllerState(ctrl *gomock.Controller) *MockControllerState {
+	mock := &MockControllerState{ctrl: ctrl}
+	mock.recorder = &MockControllerStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockControllerState) EXPECT() *MockControllerStateMockRecorder {
+	return m.recorder
+}
+
+// ControllerIds mocks base method.
+func (m *MockControllerState) ControllerIds() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControllerIds")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ControllerIds indicates an expected call of ControllerIds.
+func (mr *MockControllerStateMockRecorder) ControllerIds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControllerIds", reflect.TypeOf((*MockControllerState)(nil).ControllerIds))
+}
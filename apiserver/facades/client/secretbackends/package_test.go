@@ -15,6 +15,7 @@ import (
 )
 
 //go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/secretsbackendstate.go github.com/juju/juju/apiserver/facades/client/secretbackends SecretsBackendState
+//go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/controllerstate.go github.com/juju/juju/apiserver/facades/client/secretbackends ControllerState
 //go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/secretstate.go github.com/juju/juju/apiserver/facades/client/secretbackends SecretsState
 //go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/state.go github.com/juju/juju/apiserver/facades/client/secretbackends StatePool
 //go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/provider_mock.go github.com/juju/juju/secrets/provider SecretBackendProvider,SecretsBackend
@@ -24,6 +25,7 @@ func TestPackage(t *testing.T) {
 
 func NewTestAPI(
 	backendState SecretsBackendState,
+	controllerState ControllerState,
 	secretState SecretsState,
 	statePool StatePool,
 	authorizer facade.Authorizer,
@@ -34,11 +36,12 @@ func NewTestAPI(
 	}
 
 	return &SecretBackendsAPI{
-		clock:          clock,
-		authorizer:     authorizer,
-		controllerUUID: coretesting.ControllerTag.Id(),
-		statePool:      statePool,
-		backendState:   backendState,
-		secretState:    secretState,
+		clock:           clock,
+		authorizer:      authorizer,
+		controllerUUID:  coretesting.ControllerTag.Id(),
+		statePool:       statePool,
+		backendState:    backendState,
+		controllerState: controllerState,
+		secretState:     secretState,
 	}, nil
 }
@@ -27,16 +27,34 @@ type SecretBackendsAPI struct {
 	authorizer     facade.Authorizer
 	controllerUUID string
 
-	clock        clock.Clock
-	backendState SecretsBackendState
-	secretState  SecretsState
-	statePool    StatePool
+	clock           clock.Clock
+	backendState    SecretsBackendState
+	controllerState ControllerState
+	secretState     SecretsState
+	statePool       StatePool
 }
 
 func (s *SecretBackendsAPI) checkCanAdmin() error {
 	return s.authorizer.HasPermission(permission.SuperuserAccess, names.NewControllerTag(s.controllerUUID))
 }
 
+// checkControllerContent returns an error if the provider keeps secret
+// content on the controller's local filesystem and the controller has
+// more than one API server, since each would see different content.
+func (s *SecretBackendsAPI) checkControllerContent(p provider.SecretBackendProvider) error {
+	if !provider.HasControllerContent(p) {
+		return nil
+	}
+	controllerIds, err := s.controllerState.ControllerIds()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(controllerIds) > 1 {
+		return errors.NotSupportedf("secret backend of type %q on a controller with %d API servers", p.Type(), len(controllerIds))
+	}
+	return nil
+}
+
 // AddSecretBackends adds new secret backends.
 func (s *SecretBackendsAPI) AddSecretBackends(args params.AddSecretBackendArgs) (params.ErrorResults, error) {
 	result := params.ErrorResults{
@@ -63,6 +81,9 @@ func (s *SecretBackendsAPI) createBackend(id string, arg params.SecretBackend) e
 	if err != nil {
 		return errors.Annotatef(err, "creating backend provider type %q", arg.BackendType)
 	}
+	if err := s.checkControllerContent(p); err != nil {
+		return errors.Trace(err)
+	}
 	configValidator, ok := p.(provider.ProviderConfig)
 	if ok {
 		defaults := configValidator.ConfigDefaults()
@@ -137,6 +158,9 @@ func (s *SecretBackendsAPI) updateBackend(arg params.UpdateSecretBackendArg) err
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err := s.checkControllerContent(p); err != nil {
+		return errors.Trace(err)
+	}
 
 	cfg := make(map[string]interface{})
 	for k, v := range existing.Config {
@@ -35,11 +35,12 @@ import (
 type SecretsSuite struct {
 	testing.IsolationSuite
 
-	clock        clock.Clock
-	authorizer   *facademocks.MockAuthorizer
-	backendState *mocks.MockSecretsBackendState
-	secretsState *mocks.MockSecretsState
-	statePool    *mocks.MockStatePool
+	clock           clock.Clock
+	authorizer      *facademocks.MockAuthorizer
+	backendState    *mocks.MockSecretsBackendState
+	controllerState *mocks.MockControllerState
+	secretsState    *mocks.MockSecretsState
+	statePool       *mocks.MockStatePool
 }
 
 var _ = gc.Suite(&SecretsSuite{})
@@ -53,6 +54,7 @@ func (s *SecretsSuite) setup(c *gc.C) *gomock.Controller {
 
 	s.authorizer = facademocks.NewMockAuthorizer(ctrl)
 	s.backendState = mocks.NewMockSecretsBackendState(ctrl)
+	s.controllerState = mocks.NewMockControllerState(ctrl)
 	s.secretsState = mocks.NewMockSecretsState(ctrl)
 	s.statePool = mocks.NewMockStatePool(ctrl)
 
@@ -109,6 +111,12 @@ func (providerWithConfig) ValidateConfig(oldCfg, newCfg provider.ConfigAttrs) er
 	return nil
 }
 
+type controllerContentProvider struct {
+	providerWithConfig
+}
+
+func (controllerContentProvider) ControllerContent() {}
+
 type mockModel struct {
 	common.Model
 	modelType state.ModelType
@@ -141,7 +149,7 @@ func (s *SecretsSuite) assertListSecretBackends(c *gc.C, modelType state.ModelTy
 		}, nil
 	})
 
-	facade, err := secretbackends.NewTestAPI(s.backendState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 
 	uuid := coretesting.ModelTag.Id()
@@ -237,7 +245,7 @@ func (s *SecretsSuite) TestListSecretBackendsPermissionDeniedReveal(c *gc.C) {
 	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(
 		errors.WithType(apiservererrors.ErrPerm, authentication.ErrorEntityMissingPermission))
 
-	facade, err := secretbackends.NewTestAPI(s.backendState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 
 	_, err = facade.ListSecretBackends(params.ListSecretBackendsArgs{Reveal: true})
@@ -251,7 +259,7 @@ func (s *SecretsSuite) TestAddSecretBackends(c *gc.C) {
 	s.expectAuthClient()
 	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(nil)
 
-	facade, err := secretbackends.NewTestAPI(s.backendState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 
 	p := mocks.NewMockSecretBackendProvider(ctrl)
@@ -323,6 +331,40 @@ func (s *SecretsSuite) TestAddSecretBackends(c *gc.C) {
 	})
 }
 
+func (s *SecretsSuite) TestAddSecretBackendsControllerContentHA(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	s.expectAuthClient()
+	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(nil)
+
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	p := mocks.NewMockSecretBackendProvider(ctrl)
+	p.EXPECT().Type().Return("file")
+	s.PatchValue(&commonsecrets.GetProvider, func(string) (provider.SecretBackendProvider, error) {
+		return controllerContentProvider{providerWithConfig{SecretBackendProvider: p}}, nil
+	})
+	s.controllerState.EXPECT().ControllerIds().Return([]string{"0", "1", "2"}, nil)
+
+	results, err := facade.AddSecretBackends(params.AddSecretBackendArgs{
+		Args: []params.AddSecretBackendArg{{
+			SecretBackend: params.SecretBackend{
+				Name:        "myfile",
+				BackendType: "file",
+				Config:      map[string]interface{}{"path": "/var/lib/juju/secrets"},
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{
+		Error: &params.Error{
+			Code:    "not supported",
+			Message: `secret backend of type "file" on a controller with 3 API servers not supported`},
+	}})
+}
+
 func (s *SecretsSuite) TestAddSecretBackendsPermissionDenied(c *gc.C) {
 	defer s.setup(c).Finish()
 
@@ -330,7 +372,7 @@ func (s *SecretsSuite) TestAddSecretBackendsPermissionDenied(c *gc.C) {
 	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(
 		errors.WithType(apiservererrors.ErrPerm, authentication.ErrorEntityMissingPermission))
 
-	facade, err := secretbackends.NewTestAPI(s.backendState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 
 	_, err = facade.AddSecretBackends(params.AddSecretBackendArgs{})
@@ -343,7 +385,7 @@ func (s *SecretsSuite) TestRemoveSecretBackends(c *gc.C) {
 	s.expectAuthClient()
 	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(nil)
 
-	facade, err := secretbackends.NewTestAPI(s.backendState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.backendState.EXPECT().DeleteSecretBackend("myvault", true).Return(nil)
@@ -373,7 +415,7 @@ func (s *SecretsSuite) TestRemoveSecretBackendsPermissionDenied(c *gc.C) {
 	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(
 		errors.WithType(apiservererrors.ErrPerm, authentication.ErrorEntityMissingPermission))
 
-	facade, err := secretbackends.NewTestAPI(s.backendState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 
 	_, err = facade.RemoveSecretBackends(params.RemoveSecretBackendArgs{})
@@ -387,7 +429,7 @@ func (s *SecretsSuite) TestUpdateSecretBackends(c *gc.C) {
 	s.expectAuthClient()
 	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(nil)
 
-	facade, err := secretbackends.NewTestAPI(s.backendState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 
 	p := mocks.NewMockSecretBackendProvider(ctrl)
@@ -444,6 +486,42 @@ func (s *SecretsSuite) TestUpdateSecretBackends(c *gc.C) {
 	})
 }
 
+func (s *SecretsSuite) TestUpdateSecretBackendsControllerContentHA(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	s.expectAuthClient()
+	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(nil)
+
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	p := mocks.NewMockSecretBackendProvider(ctrl)
+	p.EXPECT().Type().Return("file")
+	s.PatchValue(&commonsecrets.GetProvider, func(string) (provider.SecretBackendProvider, error) {
+		return controllerContentProvider{providerWithConfig{SecretBackendProvider: p}}, nil
+	})
+	s.backendState.EXPECT().GetSecretBackend("myfile").Return(&secrets.SecretBackend{
+		ID:          "backend-id",
+		BackendType: "file",
+		Config:      map[string]interface{}{"path": "/var/lib/juju/secrets"},
+	}, nil)
+	s.controllerState.EXPECT().ControllerIds().Return([]string{"0", "1", "2"}, nil)
+
+	results, err := facade.UpdateSecretBackends(params.UpdateSecretBackendArgs{
+		Args: []params.UpdateSecretBackendArg{{
+			Name:   "myfile",
+			Config: map[string]interface{}{"key": "new-key"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{
+		Error: &params.Error{
+			Code:    "not supported",
+			Message: `secret backend of type "file" on a controller with 3 API servers not supported`},
+	}})
+}
+
 func (s *SecretsSuite) TestUpdateSecretBackendsPermissionDenied(c *gc.C) {
 	defer s.setup(c).Finish()
 
@@ -451,7 +529,7 @@ func (s *SecretsSuite) TestUpdateSecretBackendsPermissionDenied(c *gc.C) {
 	s.authorizer.EXPECT().HasPermission(permission.SuperuserAccess, coretesting.ControllerTag).Return(
 		errors.WithType(apiservererrors.ErrPerm, authentication.ErrorEntityMissingPermission))
 
-	facade, err := secretbackends.NewTestAPI(s.backendState, s.secretsState, s.statePool, s.authorizer, s.clock)
+	facade, err := secretbackends.NewTestAPI(s.backendState, s.controllerState, s.secretsState, s.statePool, s.authorizer, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 
 	_, err = facade.UpdateSecretBackends(params.UpdateSecretBackendArgs{})
@@ -22,6 +22,11 @@ type SecretsBackendState interface {
 	GetSecretBackendByID(ID string) (*secrets.SecretBackend, error)
 }
 
+// ControllerState is used to access the controller nodes.
+type ControllerState interface {
+	ControllerIds() ([]string, error)
+}
+
 type SecretsState interface {
 	ListModelSecrets(all bool) (map[string]set.Strings, error)
 }
@@ -21,16 +21,27 @@ func Register(registry facade.FacadeRegistry) {
 		return newSecretsAPIV1(ctx)
 	}, reflect.TypeOf((*SecretsAPI)(nil)))
 	registry.MustRegister("Secrets", 2, func(ctx facade.Context) (facade.Facade, error) {
+		return newSecretsAPIV2(ctx)
+	}, reflect.TypeOf((*SecretsAPIV2)(nil)))
+	registry.MustRegister("Secrets", 3, func(ctx facade.Context) (facade.Facade, error) {
 		return newSecretsAPI(ctx)
 	}, reflect.TypeOf((*SecretsAPI)(nil)))
 }
 
-func newSecretsAPIV1(context facade.Context) (*SecretsAPIV1, error) {
+func newSecretsAPIV2(context facade.Context) (*SecretsAPIV2, error) {
 	api, err := newSecretsAPI(context)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &SecretsAPIV1{SecretsAPI: api}, nil
+	return &SecretsAPIV2{SecretsAPI: api}, nil
+}
+
+func newSecretsAPIV1(context facade.Context) (*SecretsAPIV1, error) {
+	api, err := newSecretsAPIV2(context)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &SecretsAPIV1{SecretsAPIV2: api}, nil
 }
 
 // newSecretsAPI creates a SecretsAPI.
@@ -45,9 +45,14 @@ type SecretsAPI struct {
 	backendGetter                          func(*provider.ModelBackendConfig) (provider.SecretsBackend, error)
 }
 
+// SecretsAPIV2 is the backend for the Secrets facade v2.
+type SecretsAPIV2 struct {
+	*SecretsAPI
+}
+
 // SecretsAPIV1 is the backend for the Secrets facade v1.
 type SecretsAPIV1 struct {
-	*SecretsAPI
+	*SecretsAPIV2
 }
 
 func (s *SecretsAPI) checkCanRead() error {
@@ -148,6 +153,7 @@ func (s *SecretsAPI) ListSecrets(arg params.ListSecretsArgs) (params.ListSecretR
 				UpdateTime:  r.UpdateTime,
 				ExpireTime:  r.ExpireTime,
 				BackendName: backendName,
+				RollbackTo:  r.RollbackTo,
 			})
 		}
 		if arg.ShowSecrets {
@@ -413,6 +419,16 @@ func (s *SecretsAPI) updateSecret(backend provider.SecretsBackend, arg params.Up
 		// Check if the uri exists or not.
 		return errors.Trace(err)
 	}
+	if arg.RollbackTo != nil {
+		if *arg.RollbackTo >= md.LatestRevision {
+			return errors.NotValidf("rollback to revision %d of %d", *arg.RollbackTo, md.LatestRevision)
+		}
+		val, err := s.secretContentFromBackend(uri, *arg.RollbackTo)
+		if err != nil {
+			return errors.Annotatef(err, "reading content of revision %d", *arg.RollbackTo)
+		}
+		arg.Content.Data = val.EncodedValues()
+	}
 	if len(arg.Content.Data) > 0 {
 		revId, err := backend.SaveContent(context.TODO(), uri, md.LatestRevision+1, coresecrets.NewSecretValue(arg.Content.Data))
 		if err != nil && !errors.Is(err, errors.NotSupported) {
@@ -437,7 +453,9 @@ func (s *SecretsAPI) updateSecret(backend provider.SecretsBackend, arg params.Up
 			}
 		}
 	}
-	md, err = s.secretsState.UpdateSecret(uri, fromUpsertParams(arg.AutoPrune, arg.UpsertSecretArg))
+	updateParams := fromUpsertParams(arg.AutoPrune, arg.UpsertSecretArg)
+	updateParams.RollbackTo = arg.RollbackTo
+	md, err = s.secretsState.UpdateSecret(uri, updateParams)
 	if err != nil {
 		return errors.Trace(err)
 	}
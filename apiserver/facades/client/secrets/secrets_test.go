@@ -539,6 +539,74 @@ func (s *SecretsSuite) TestUpdateSecretsByName(c *gc.C) {
 	s.assertUpdateSecrets(c, nil, true, false)
 }
 
+func (s *SecretsSuite) TestUpdateSecretsRollback(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.expectAuthClient()
+	s.authorizer.EXPECT().HasPermission(permission.WriteAccess, coretesting.ModelTag).Return(nil)
+
+	uri := coresecrets.NewURI()
+	s.secretsState.EXPECT().GetSecret(uri).Return(&coresecrets.SecretMetadata{
+		URI:            uri,
+		LatestRevision: 2,
+	}, nil)
+	s.secretsState.EXPECT().GetSecretValue(uri, 1).Return(
+		coresecrets.NewSecretValue(map[string]string{"foo": "YmFy"}), nil, nil,
+	)
+	s.secretsBackend.EXPECT().SaveContent(gomock.Any(), uri, 3, coresecrets.NewSecretValue(map[string]string{"foo": "YmFy"})).
+		Return("", errors.NotSupportedf("not supported"))
+	s.secretsState.EXPECT().UpdateSecret(uri, gomock.Any()).DoAndReturn(func(_ *coresecrets.URI, params state.UpdateSecretParams) (*coresecrets.SecretMetadata, error) {
+		c.Assert(params.Data, gc.DeepEquals, coresecrets.SecretData(map[string]string{"foo": "YmFy"}))
+		c.Assert(params.RollbackTo, gc.DeepEquals, ptr(1))
+		return &coresecrets.SecretMetadata{URI: uri, LatestRevision: 3}, nil
+	})
+
+	facade, err := apisecrets.NewTestAPI(s.authTag, s.authorizer, s.secretsState, s.secretConsumer,
+		adminBackendConfigGetter, backendConfigGetterForUserSecretsWrite(c),
+		func(cfg *provider.ModelBackendConfig) (provider.SecretsBackend, error) {
+			return s.secretsBackend, nil
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := facade.UpdateSecrets(params.UpdateUserSecretArgs{
+		Args: []params.UpdateUserSecretArg{{
+			URI:        uri.String(),
+			RollbackTo: ptr(1),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+}
+
+func (s *SecretsSuite) TestUpdateSecretsRollbackInvalidRevision(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.expectAuthClient()
+	s.authorizer.EXPECT().HasPermission(permission.WriteAccess, coretesting.ModelTag).Return(nil)
+
+	uri := coresecrets.NewURI()
+	s.secretsState.EXPECT().GetSecret(uri).Return(&coresecrets.SecretMetadata{
+		URI:            uri,
+		LatestRevision: 2,
+	}, nil)
+
+	facade, err := apisecrets.NewTestAPI(s.authTag, s.authorizer, s.secretsState, s.secretConsumer,
+		adminBackendConfigGetter, backendConfigGetterForUserSecretsWrite(c),
+		func(cfg *provider.ModelBackendConfig) (provider.SecretsBackend, error) {
+			return s.secretsBackend, nil
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := facade.UpdateSecrets(params.UpdateUserSecretArgs{
+		Args: []params.UpdateUserSecretArg{{
+			URI:        uri.String(),
+			RollbackTo: ptr(2),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `rollback to revision 2 of 2 not valid`)
+}
+
 func (s *SecretsSuite) TestRemoveSecrets(c *gc.C) {
 	defer s.setup(c).Finish()
 	s.expectAuthClient()
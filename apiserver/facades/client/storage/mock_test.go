@@ -71,6 +71,66 @@ type mockStorageAccessor struct {
 	attachStorage                       func(names.StorageTag, names.UnitTag) error
 	detachStorage                       func(names.StorageTag, names.UnitTag, bool) error
 	addExistingFilesystem               func(state.FilesystemInfo, *state.VolumeInfo, string) (names.StorageTag, error)
+	createVolumeSnapshot                func(names.StorageTag) (state.VolumeSnapshot, error)
+	allVolumeSnapshots                  func() ([]state.VolumeSnapshot, error)
+	destroyVolumeSnapshot               func(string) error
+	restoreVolumeSnapshot               func(string, string) (names.StorageTag, error)
+	resizeStorageInstance               func(names.StorageTag, uint64) error
+}
+
+type mockVolumeSnapshot struct {
+	state.VolumeSnapshot
+	id      string
+	volume  names.VolumeTag
+	storage names.StorageTag
+	pool    string
+	created time.Time
+	info    *state.VolumeSnapshotInfo
+	status  status.StatusInfo
+}
+
+func (m *mockVolumeSnapshot) Id() string {
+	return m.id
+}
+
+func (m *mockVolumeSnapshot) Volume() names.VolumeTag {
+	return m.volume
+}
+
+func (m *mockVolumeSnapshot) StorageInstance() (names.StorageTag, bool) {
+	return m.storage, m.storage != names.StorageTag{}
+}
+
+func (m *mockVolumeSnapshot) StorageName() string {
+	name, _ := names.StorageName(m.storage.Id())
+	return name
+}
+
+func (m *mockVolumeSnapshot) Kind() state.StorageKind {
+	return state.StorageKindBlock
+}
+
+func (m *mockVolumeSnapshot) Pool() string {
+	return m.pool
+}
+
+func (m *mockVolumeSnapshot) Created() time.Time {
+	return m.created
+}
+
+func (m *mockVolumeSnapshot) Life() state.Life {
+	return state.Alive
+}
+
+func (m *mockVolumeSnapshot) Info() (state.VolumeSnapshotInfo, error) {
+	if m.info == nil {
+		return state.VolumeSnapshotInfo{}, errors.NotProvisionedf("volume snapshot %q", m.id)
+	}
+	return *m.info, nil
+}
+
+func (m *mockVolumeSnapshot) Status() (status.StatusInfo, error) {
+	return m.status, nil
 }
 
 func (st *mockStorageAccessor) VolumeAccess() storage.StorageVolume {
@@ -189,6 +249,26 @@ func (st *mockStorageAccessor) AddExistingFilesystem(f state.FilesystemInfo, v *
 	return st.addExistingFilesystem(f, v, s)
 }
 
+func (st *mockStorageAccessor) CreateVolumeSnapshot(tag names.StorageTag) (state.VolumeSnapshot, error) {
+	return st.createVolumeSnapshot(tag)
+}
+
+func (st *mockStorageAccessor) AllVolumeSnapshots() ([]state.VolumeSnapshot, error) {
+	return st.allVolumeSnapshots()
+}
+
+func (st *mockStorageAccessor) DestroyVolumeSnapshot(id string) error {
+	return st.destroyVolumeSnapshot(id)
+}
+
+func (st *mockStorageAccessor) RestoreVolumeSnapshot(id, storageName string) (names.StorageTag, error) {
+	return st.restoreVolumeSnapshot(id, storageName)
+}
+
+func (st *mockStorageAccessor) ResizeStorageInstance(tag names.StorageTag, size uint64) error {
+	return st.resizeStorageInstance(tag, size)
+}
+
 type mockVolume struct {
 	state.Volume
 	tag     names.VolumeTag
@@ -305,6 +385,11 @@ type mockStorageInstance struct {
 	owner      names.Tag
 	storageTag names.Tag
 	life       state.Life
+	pool       string
+}
+
+func (m *mockStorageInstance) Pool() string {
+	return m.pool
 }
 
 func (m *mockStorageInstance) Kind() state.StorageKind {
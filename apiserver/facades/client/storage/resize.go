@@ -0,0 +1,88 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/storage"
+)
+
+// ResizeStorage requests that the volumes assigned to the specified
+// storage instances be grown to the specified sizes. The volumes are
+// resized asynchronously by the storage provisioner, and any filesystems
+// on them are grown by the machine agent once the volume is resized.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) ResizeStorage(args params.ResizeStorageArgs) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	one := func(arg params.ResizeStorageArg) error {
+		storageTag, err := names.ParseStorageTag(arg.StorageTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if arg.Size == 0 {
+			return errors.NotValidf("size 0")
+		}
+		storageInstance, err := a.storageAccess.StorageInstance(storageTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := a.checkResizeSupported(storageInstance.Pool()); err != nil {
+			return errors.Trace(err)
+		}
+		return a.storageAccess.ResizeStorageInstance(storageTag, arg.Size)
+	}
+	results := make([]params.ErrorResult, len(args.Args))
+	for i, arg := range args.Args {
+		if err := one(arg); err != nil {
+			results[i].Error = apiservererrors.ServerError(err)
+		}
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// checkResizeSupported returns an error satisfying errors.NotSupported
+// if the storage provider for the specified pool cannot resize volumes.
+func (a *StorageAPI) checkResizeSupported(pool string) error {
+	pm, registry, err := a.storageMetadata()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg, err := pm.Get(pool)
+	if errors.IsNotFound(err) {
+		cfg, err = storage.NewConfig(pool, storage.ProviderType(pool), map[string]interface{}{})
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	provider, err := registry.StorageProvider(cfg.Provider())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !provider.Supports(storage.StorageKindBlock) {
+		return errors.NotSupportedf("resizing storage with storage provider %q", cfg.Provider())
+	}
+	volumeSource, err := provider.VolumeSource(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := volumeSource.(storage.VolumeResizer); !ok {
+		return errors.NotSupportedf("resizing storage with storage provider %q", cfg.Provider())
+	}
+	return nil
+}
+
+// ResizeStorage is not available on versions prior to v8.
+func (*StorageAPIv7) ResizeStorage(_, _ struct{}) {}
@@ -0,0 +1,110 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider/dummy"
+)
+
+type resizeSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&resizeSuite{})
+
+func (s *resizeSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.storageInstance.pool = "radiance"
+	s.storageAccessor.resizeStorageInstance = func(tag names.StorageTag, size uint64) error {
+		s.stub.AddCall("resizeStorageInstance", tag, size)
+		return s.stub.NextErr()
+	}
+}
+
+func (s *resizeSuite) registerProvider(source storage.VolumeSource) {
+	s.registry.Providers["radiance"] = &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+		IsDynamic:    true,
+		SupportsFunc: func(kind storage.StorageKind) bool {
+			return kind == storage.StorageKindBlock
+		},
+		VolumeSourceFunc: func(*storage.Config) (storage.VolumeSource, error) {
+			return source, nil
+		},
+	}
+}
+
+func (s *resizeSuite) TestResizeStorage(c *gc.C) {
+	s.registerProvider(volumeResizer{&dummy.VolumeSource{}})
+
+	results, err := s.api.ResizeStorage(params.ResizeStorageArgs{Args: []params.ResizeStorageArg{
+		{StorageTag: s.storageTag.String(), Size: 2048},
+		{StorageTag: s.storageTag.String()},
+		{StorageTag: "volume-0", Size: 2048},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "size 0 not valid", Code: params.CodeNotValid}},
+		{Error: &params.Error{Message: `"volume-0" is not a valid storage tag`}},
+	}})
+	s.stub.CheckCallNames(c, getBlockForTypeCall, storageInstanceCall, "resizeStorageInstance")
+	s.stub.CheckCall(c, 2, "resizeStorageInstance", s.storageTag, uint64(2048))
+}
+
+func (s *resizeSuite) TestResizeStorageError(c *gc.C) {
+	s.registerProvider(volumeResizer{&dummy.VolumeSource{}})
+	s.storageAccessor.resizeStorageInstance = func(tag names.StorageTag, size uint64) error {
+		return errors.NotValidf("new size %dMiB", size)
+	}
+
+	results, err := s.api.ResizeStorage(params.ResizeStorageArgs{Args: []params.ResizeStorageArg{
+		{StorageTag: s.storageTag.String(), Size: 512},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.DeepEquals, &params.Error{
+		Message: "new size 512MiB not valid",
+		Code:    params.CodeNotValid,
+	})
+}
+
+func (s *resizeSuite) TestResizeStorageNotSupported(c *gc.C) {
+	s.registerProvider(&dummy.VolumeSource{})
+
+	results, err := s.api.ResizeStorage(params.ResizeStorageArgs{Args: []params.ResizeStorageArg{
+		{StorageTag: s.storageTag.String(), Size: 2048},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches,
+		`resizing storage with storage provider "radiance" not supported`)
+	s.stub.CheckCallNames(c, getBlockForTypeCall, storageInstanceCall)
+}
+
+func (s *resizeSuite) TestResizeStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "resize")
+	_, err := s.api.ResizeStorage(params.ResizeStorageArgs{Args: []params.ResizeStorageArg{
+		{StorageTag: s.storageTag.String(), Size: 2048},
+	}})
+	s.assertBlocked(c, err, "resize")
+}
+
+type volumeResizer struct {
+	*dummy.VolumeSource
+}
+
+// ResizeVolumes is part of the storage.VolumeResizer interface.
+func (v volumeResizer) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	v.MethodCall(v, "ResizeVolumes", ctx, params)
+	return nil, v.NextErr()
+}
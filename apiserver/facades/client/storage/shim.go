@@ -21,6 +21,7 @@ type storageAccess interface {
 	storageInterface
 	storageVolume
 	storageFile
+	storageSnapshot
 }
 
 type storageInterface interface {
@@ -79,6 +80,28 @@ type storageVolume interface {
 	AddExistingFilesystem(f state.FilesystemInfo, v *state.VolumeInfo, storageName string) (names.StorageTag, error)
 }
 
+type storageSnapshot interface {
+	// CreateVolumeSnapshot requests a snapshot of the volume assigned
+	// to the storage instance with the specified tag.
+	CreateVolumeSnapshot(names.StorageTag) (state.VolumeSnapshot, error)
+
+	// AllVolumeSnapshots returns all volume snapshots in the model.
+	AllVolumeSnapshots() ([]state.VolumeSnapshot, error)
+
+	// DestroyVolumeSnapshot destroys the volume snapshot with the
+	// specified ID.
+	DestroyVolumeSnapshot(string) error
+
+	// RestoreVolumeSnapshot creates a new storage instance from the
+	// volume snapshot with the specified ID.
+	RestoreVolumeSnapshot(id, storageName string) (names.StorageTag, error)
+
+	// ResizeStorageInstance requests that the volume assigned to the
+	// storage instance with the specified tag be grown to the
+	// specified size in MiB.
+	ResizeStorageInstance(names.StorageTag, uint64) error
+}
+
 type storageFile interface {
 	storagecommon.FilesystemAccess
 
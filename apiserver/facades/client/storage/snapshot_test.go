@@ -0,0 +1,214 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider/dummy"
+)
+
+type snapshotSuite struct {
+	baseStorageSuite
+
+	snapshot *mockVolumeSnapshot
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+func (s *snapshotSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.storageInstance.pool = "radiance"
+	s.snapshot = &mockVolumeSnapshot{
+		id:      "22@3",
+		volume:  s.volumeTag,
+		storage: s.storageTag,
+		pool:    "radiance",
+		created: time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC),
+		status:  status.StatusInfo{Status: status.Pending},
+	}
+	s.storageAccessor.createVolumeSnapshot = func(tag names.StorageTag) (state.VolumeSnapshot, error) {
+		s.stub.AddCall("createVolumeSnapshot", tag)
+		return s.snapshot, nil
+	}
+	s.storageAccessor.allVolumeSnapshots = func() ([]state.VolumeSnapshot, error) {
+		s.stub.AddCall("allVolumeSnapshots")
+		other := &mockVolumeSnapshot{
+			id:      "0/1@4",
+			volume:  names.NewVolumeTag("0/1"),
+			storage: names.NewStorageTag("logs/1"),
+			pool:    "lvm",
+			info:    &state.VolumeSnapshotInfo{SnapshotId: "juju-snap-4", Size: 1024},
+			status:  status.StatusInfo{Status: status.Available},
+		}
+		return []state.VolumeSnapshot{s.snapshot, other}, nil
+	}
+	s.storageAccessor.destroyVolumeSnapshot = func(id string) error {
+		s.stub.AddCall("destroyVolumeSnapshot", id)
+		return s.stub.NextErr()
+	}
+	s.storageAccessor.restoreVolumeSnapshot = func(id, storageName string) (names.StorageTag, error) {
+		s.stub.AddCall("restoreVolumeSnapshot", id, storageName)
+		return names.NewStorageTag("data/7"), s.stub.NextErr()
+	}
+}
+
+func (s *snapshotSuite) registerProvider(source storage.VolumeSource) {
+	s.registry.Providers["radiance"] = &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+		IsDynamic:    true,
+		SupportsFunc: func(kind storage.StorageKind) bool {
+			return kind == storage.StorageKindBlock
+		},
+		VolumeSourceFunc: func(*storage.Config) (storage.VolumeSource, error) {
+			return source, nil
+		},
+	}
+}
+
+func (s *snapshotSuite) TestSnapshotStorage(c *gc.C) {
+	s.registerProvider(volumeSnapshotter{&dummy.VolumeSource{}})
+
+	results, err := s.api.SnapshotStorage(params.Entities{Entities: []params.Entity{
+		{Tag: s.storageTag.String()},
+		{Tag: "volume-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.VolumeSnapshotDetailsResult{{
+		Result: &params.VolumeSnapshotDetails{
+			Id:          "22@3",
+			VolumeTag:   "volume-22",
+			StorageTag:  "storage-data-0",
+			StorageName: "data",
+			Kind:        params.StorageKindBlock,
+			Pool:        "radiance",
+			Created:     s.snapshot.created,
+			Life:        life.Alive,
+			Status:      params.EntityStatus{Status: status.Pending},
+		},
+	}, {
+		Error: &params.Error{Message: `"volume-0" is not a valid storage tag`},
+	}})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{getBlockForTypeCall, []interface{}{state.ChangeBlock}},
+		{storageInstanceCall, []interface{}{s.storageTag}},
+		{"createVolumeSnapshot", []interface{}{s.storageTag}},
+	})
+}
+
+func (s *snapshotSuite) TestSnapshotStorageNotSupported(c *gc.C) {
+	s.registerProvider(&dummy.VolumeSource{})
+
+	results, err := s.api.SnapshotStorage(params.Entities{Entities: []params.Entity{
+		{Tag: s.storageTag.String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches,
+		`snapshotting storage with storage provider "radiance" not supported`)
+	s.stub.CheckCallNames(c, getBlockForTypeCall, storageInstanceCall)
+}
+
+func (s *snapshotSuite) TestSnapshotStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "snapshot")
+	_, err := s.api.SnapshotStorage(params.Entities{Entities: []params.Entity{
+		{Tag: s.storageTag.String()},
+	}})
+	s.assertBlocked(c, err, "snapshot")
+}
+
+func (s *snapshotSuite) TestListStorageSnapshots(c *gc.C) {
+	results, err := s.api.ListStorageSnapshots(params.StorageSnapshotFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[1].Result, jc.DeepEquals, &params.VolumeSnapshotDetails{
+		Id:          "0/1@4",
+		VolumeTag:   "volume-0-1",
+		StorageTag:  "storage-logs-1",
+		StorageName: "logs",
+		Kind:        params.StorageKindBlock,
+		Pool:        "lvm",
+		SnapshotId:  "juju-snap-4",
+		Size:        1024,
+		Life:        life.Alive,
+		Status:      params.EntityStatus{Status: status.Available},
+	})
+}
+
+func (s *snapshotSuite) TestListStorageSnapshotsFiltered(c *gc.C) {
+	results, err := s.api.ListStorageSnapshots(params.StorageSnapshotFilter{
+		StorageTags: []string{"storage-logs-1"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Result.Id, gc.Equals, "0/1@4")
+
+	_, err = s.api.ListStorageSnapshots(params.StorageSnapshotFilter{
+		StorageTags: []string{"logs/1"},
+	})
+	c.Assert(err, gc.ErrorMatches, `"logs/1" is not a valid tag`)
+}
+
+func (s *snapshotSuite) TestRestoreStorageSnapshots(c *gc.C) {
+	s.stub.SetErrors(nil, errors.NotProvisionedf(`volume snapshot "0/1@5"`))
+	results, err := s.api.RestoreStorageSnapshots(params.RestoreStorageSnapshotArgs{
+		Args: []params.RestoreStorageSnapshotArg{
+			{Id: "22@3", StorageName: "data"},
+			{Id: "0/1@5"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ImportStorageResult{{
+		Result: &params.ImportStorageDetails{StorageTag: "storage-data-7"},
+	}, {
+		Error: &params.Error{
+			Message: `volume snapshot "0/1@5" not provisioned`,
+			Code:    params.CodeNotProvisioned,
+		},
+	}})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{getBlockForTypeCall, []interface{}{state.ChangeBlock}},
+		{"restoreVolumeSnapshot", []interface{}{"22@3", "data"}},
+		{"restoreVolumeSnapshot", []interface{}{"0/1@5", ""}},
+	})
+}
+
+func (s *snapshotSuite) TestRemoveStorageSnapshots(c *gc.C) {
+	results, err := s.api.RemoveStorageSnapshots(params.VolumeSnapshotIds{Ids: []string{"22@3"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{getBlockForTypeCall, []interface{}{state.RemoveBlock}},
+		{getBlockForTypeCall, []interface{}{state.ChangeBlock}},
+		{"destroyVolumeSnapshot", []interface{}{"22@3"}},
+	})
+}
+
+type volumeSnapshotter struct {
+	*dummy.VolumeSource
+}
+
+// CreateVolumeSnapshots is part of the storage.VolumeSnapshotter interface.
+func (v volumeSnapshotter) CreateVolumeSnapshots(ctx context.ProviderCallContext, params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	v.MethodCall(v, "CreateVolumeSnapshots", ctx, params)
+	return nil, v.NextErr()
+}
+
+// DestroyVolumeSnapshots is part of the storage.VolumeSnapshotter interface.
+func (v volumeSnapshotter) DestroyVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	v.MethodCall(v, "DestroyVolumeSnapshots", ctx, snapshotIds)
+	return nil, v.NextErr()
+}
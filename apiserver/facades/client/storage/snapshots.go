@@ -0,0 +1,215 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+)
+
+// SnapshotStorage requests snapshots of the volumes assigned to the
+// specified storage instances. The snapshots are taken asynchronously
+// by the storage provisioner.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) SnapshotStorage(args params.Entities) (params.VolumeSnapshotDetailsResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.VolumeSnapshotDetailsResults{}, errors.Trace(err)
+	}
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.VolumeSnapshotDetailsResults{}, errors.Trace(err)
+	}
+
+	one := func(arg params.Entity) (*params.VolumeSnapshotDetails, error) {
+		storageTag, err := names.ParseStorageTag(arg.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		storageInstance, err := a.storageAccess.StorageInstance(storageTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := a.checkSnapshotsSupported(storageInstance.Pool()); err != nil {
+			return nil, errors.Trace(err)
+		}
+		snapshot, err := a.storageAccess.CreateVolumeSnapshot(storageTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return volumeSnapshotDetails(snapshot)
+	}
+	results := make([]params.VolumeSnapshotDetailsResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		details, err := one(arg)
+		if err != nil {
+			results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results[i].Result = details
+	}
+	return params.VolumeSnapshotDetailsResults{Results: results}, nil
+}
+
+// checkSnapshotsSupported returns an error satisfying errors.NotSupported
+// if the storage provider for the specified pool cannot snapshot volumes.
+func (a *StorageAPI) checkSnapshotsSupported(pool string) error {
+	pm, registry, err := a.storageMetadata()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg, err := pm.Get(pool)
+	if errors.IsNotFound(err) {
+		cfg, err = storage.NewConfig(pool, storage.ProviderType(pool), map[string]interface{}{})
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	provider, err := registry.StorageProvider(cfg.Provider())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !provider.Supports(storage.StorageKindBlock) {
+		return errors.NotSupportedf("snapshotting storage with storage provider %q", cfg.Provider())
+	}
+	volumeSource, err := provider.VolumeSource(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := volumeSource.(storage.VolumeSnapshotter); !ok {
+		return errors.NotSupportedf("snapshotting storage with storage provider %q", cfg.Provider())
+	}
+	return nil
+}
+
+// ListStorageSnapshots returns the volume snapshots taken of the
+// specified storage instances, or all volume snapshots in the model
+// if no storage instances are specified.
+func (a *StorageAPI) ListStorageSnapshots(filter params.StorageSnapshotFilter) (params.VolumeSnapshotDetailsResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.VolumeSnapshotDetailsResults{}, errors.Trace(err)
+	}
+	storageIds := make(map[string]bool)
+	for _, tagString := range filter.StorageTags {
+		storageTag, err := names.ParseStorageTag(tagString)
+		if err != nil {
+			return params.VolumeSnapshotDetailsResults{}, errors.Trace(err)
+		}
+		storageIds[storageTag.Id()] = true
+	}
+	snapshots, err := a.storageAccess.AllVolumeSnapshots()
+	if err != nil {
+		return params.VolumeSnapshotDetailsResults{}, errors.Trace(err)
+	}
+	var results []params.VolumeSnapshotDetailsResult
+	for _, snapshot := range snapshots {
+		if len(storageIds) > 0 {
+			storageTag, ok := snapshot.StorageInstance()
+			if !ok || !storageIds[storageTag.Id()] {
+				continue
+			}
+		}
+		details, err := volumeSnapshotDetails(snapshot)
+		if err != nil {
+			results = append(results, params.VolumeSnapshotDetailsResult{
+				Error: apiservererrors.ServerError(err),
+			})
+			continue
+		}
+		results = append(results, params.VolumeSnapshotDetailsResult{Result: details})
+	}
+	return params.VolumeSnapshotDetailsResults{Results: results}, nil
+}
+
+// RestoreStorageSnapshots creates new, detached storage instances from
+// volume snapshots. The storage instances may then be attached to units
+// with "attach-storage", or "add-unit --attach-storage", at which point
+// their volumes are created from the snapshots.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) RestoreStorageSnapshots(args params.RestoreStorageSnapshotArgs) (params.ImportStorageResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ImportStorageResults{}, errors.Trace(err)
+	}
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ImportStorageResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.ImportStorageResult, len(args.Args))
+	for i, arg := range args.Args {
+		storageTag, err := a.storageAccess.RestoreVolumeSnapshot(arg.Id, arg.StorageName)
+		if err != nil {
+			results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results[i].Result = &params.ImportStorageDetails{
+			StorageTag: storageTag.String(),
+		}
+	}
+	return params.ImportStorageResults{Results: results}, nil
+}
+
+// RemoveStorageSnapshots destroys the specified volume snapshots.
+// A "REMOVE" block can block this operation.
+func (a *StorageAPI) RemoveStorageSnapshots(args params.VolumeSnapshotIds) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Ids))
+	for i, id := range args.Ids {
+		err := a.storageAccess.DestroyVolumeSnapshot(id)
+		results[i].Error = apiservererrors.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// SnapshotStorage isn't on the v6 API.
+func (*StorageAPIv6) SnapshotStorage(_, _ struct{}) {}
+
+// ListStorageSnapshots isn't on the v6 API.
+func (*StorageAPIv6) ListStorageSnapshots(_, _ struct{}) {}
+
+// RestoreStorageSnapshots isn't on the v6 API.
+func (*StorageAPIv6) RestoreStorageSnapshots(_, _ struct{}) {}
+
+// RemoveStorageSnapshots isn't on the v6 API.
+func (*StorageAPIv6) RemoveStorageSnapshots(_, _ struct{}) {}
+
+func volumeSnapshotDetails(snapshot state.VolumeSnapshot) (*params.VolumeSnapshotDetails, error) {
+	snapshotStatus, err := snapshot.Status()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	details := &params.VolumeSnapshotDetails{
+		Id:          snapshot.Id(),
+		VolumeTag:   snapshot.Volume().String(),
+		StorageName: snapshot.StorageName(),
+		Kind:        params.StorageKind(snapshot.Kind()),
+		Pool:        snapshot.Pool(),
+		Created:     snapshot.Created(),
+		Life:        life.Value(snapshot.Life().String()),
+		Status:      common.EntityStatusFromState(snapshotStatus),
+	}
+	if storageTag, ok := snapshot.StorageInstance(); ok {
+		details.StorageTag = storageTag.String()
+	}
+	if info, err := snapshot.Info(); err == nil {
+		details.SnapshotId = info.SnapshotId
+		details.Size = info.Size
+	} else if !errors.IsNotProvisioned(err) {
+		return nil, errors.Trace(err)
+	}
+	return details, nil
+}
@@ -27,7 +27,7 @@ import (
 
 type storageMetadataFunc func() (poolmanager.PoolManager, storage.ProviderRegistry, error)
 
-// StorageAPI implements the latest version (v6) of the Storage API.
+// StorageAPI implements the latest version (v8) of the Storage API.
 type StorageAPI struct {
 	backend         backend
 	storageAccess   storageAccess
@@ -37,6 +37,18 @@ type StorageAPI struct {
 	modelType       state.ModelType
 }
 
+// StorageAPIv7 implements version 7 of the Storage API, which
+// does not support resizing storage.
+type StorageAPIv7 struct {
+	*StorageAPI
+}
+
+// StorageAPIv6 implements version 6 of the Storage API, which
+// does not support volume snapshots.
+type StorageAPIv6 struct {
+	*StorageAPIv7
+}
+
 func NewStorageAPI(
 	backend backend,
 	modelType state.ModelType,
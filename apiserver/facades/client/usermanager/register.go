@@ -19,10 +19,34 @@ import (
 // Register is called to expose a package of facades onto a given registry.
 func Register(registry facade.FacadeRegistry) {
 	registry.MustRegister("UserManager", 3, func(ctx facade.Context) (facade.Facade, error) {
-		return newUserManagerAPI(ctx) // Adds ModelUserInfo
+		return newUserManagerAPIV3(ctx) // Adds ModelUserInfo
+	}, reflect.TypeOf((*UserManagerAPIV3)(nil)))
+	registry.MustRegister("UserManager", 4, func(ctx facade.Context) (facade.Facade, error) {
+		return newUserManagerAPIV4(ctx) // Adds user groups
+	}, reflect.TypeOf((*UserManagerAPIV4)(nil)))
+	registry.MustRegister("UserManager", 5, func(ctx facade.Context) (facade.Facade, error) {
+		return newUserManagerAPI(ctx) // Adds roles
 	}, reflect.TypeOf((*UserManagerAPI)(nil)))
 }
 
+// newUserManagerAPIV3 provides the signature required for facade registration.
+func newUserManagerAPIV3(ctx facade.Context) (*UserManagerAPIV3, error) {
+	api, err := newUserManagerAPI(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIV3{api}, nil
+}
+
+// newUserManagerAPIV4 provides the signature required for facade registration.
+func newUserManagerAPIV4(ctx facade.Context) (*UserManagerAPIV4, error) {
+	api, err := newUserManagerAPI(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIV4{api}, nil
+}
+
 // newUserManagerAPI provides the signature required for facade registration.
 func newUserManagerAPI(ctx facade.Context) (*UserManagerAPI, error) {
 	authorizer := ctx.Auth()
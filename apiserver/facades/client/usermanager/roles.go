@@ -0,0 +1,170 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/authentication"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+)
+
+// AddRoles adds new custom roles to the controller. Only controller
+// superusers may manage roles.
+func (api *UserManagerAPI) AddRoles(args params.AddRoles) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := api.checkIsSuperuser(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Roles))
+	for i, arg := range args.Roles {
+		role := permission.Role{
+			Name:         arg.Name,
+			Access:       permission.Access(arg.Access),
+			Capabilities: make([]permission.Capability, len(arg.Capabilities)),
+		}
+		for j, capability := range arg.Capabilities {
+			role.Capabilities[j] = permission.Capability(capability)
+		}
+		_, err := api.state.AddRole(role, api.apiUser.Id())
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+// RemoveRoles removes custom roles from the controller. A role cannot
+// be removed while it is granted to any user.
+func (api *UserManagerAPI) RemoveRoles(args params.RoleNames) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.RemoveAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := api.checkIsSuperuser(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Names))
+	for i, name := range args.Names {
+		err := api.state.RemoveRole(name)
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+// RoleInfo returns information on roles. If no names are given, all
+// the roles are returned, built-in roles first. Any user may see the
+// roles defined on the controller.
+func (api *UserManagerAPI) RoleInfo(args params.RoleNames) (params.RoleInfoResults, error) {
+	var results params.RoleInfoResults
+	if len(args.Names) == 0 {
+		roles, err := api.state.AllRoles()
+		if err != nil {
+			return results, errors.Trace(err)
+		}
+		results.Results = make([]params.RoleInfoResult, len(roles))
+		for i, role := range roles {
+			results.Results[i].Result = roleInfo(role)
+		}
+		return results, nil
+	}
+
+	results.Results = make([]params.RoleInfoResult, len(args.Names))
+	for i, name := range args.Names {
+		role, err := api.state.Role(name)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = roleInfo(role)
+	}
+	return results, nil
+}
+
+func roleInfo(role *state.Role) *params.RoleInfo {
+	capabilities := role.Capabilities()
+	info := &params.RoleInfo{
+		Name:         role.Name(),
+		Access:       string(role.Access()),
+		Capabilities: make([]string, len(capabilities)),
+		BuiltIn:      role.BuiltIn(),
+		CreatedBy:    role.CreatedBy(),
+		DateCreated:  role.DateCreated(),
+	}
+	for i, capability := range capabilities {
+		info.Capabilities[i] = string(capability)
+	}
+	return info
+}
+
+// GrantRoles grants roles to users on models, replacing the role and
+// access level they had. Users without access to a model are given
+// it. Model admins may grant roles on their models.
+func (api *UserManagerAPI) GrantRoles(args params.GrantRoles) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Grants))
+	for i, arg := range args.Grants {
+		err := api.grantOneRole(arg)
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) grantOneRole(arg params.GrantRole) error {
+	user, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !api.isAdmin {
+		err := api.authorizer.HasPermission(permission.AdminAccess, modelTag)
+		if errors.Is(err, authentication.ErrorEntityMissingPermission) {
+			return apiservererrors.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	role, err := api.state.Role(arg.Role)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	st, err := api.pool.Get(modelTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+
+	if _, err := st.UserAccess(user, modelTag); errors.Is(err, errors.NotFound) {
+		model, err := st.Model()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, err = model.AddUser(state.UserAccessSpec{
+			User:      user,
+			CreatedBy: api.apiUser,
+			Access:    role.Access(),
+		})
+		if err != nil {
+			return errors.Annotate(err, "adding model user")
+		}
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(st.SetModelUserRole(user, role.Name()))
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade/facadetest"
+	"github.com/juju/juju/apiserver/facades/client/usermanager"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/testing/factory"
+)
+
+type rolesSuite struct {
+	jujutesting.JujuConnSuite
+
+	usermanager *usermanager.UserManagerAPI
+}
+
+var _ = gc.Suite(&rolesSuite{})
+
+func (s *rolesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.usermanager = s.newAPI(c, s.AdminUserTag(c))
+}
+
+func (s *rolesSuite) newAPI(c *gc.C, user names.UserTag) *usermanager.UserManagerAPI {
+	api, err := usermanager.NewUserManagerAPI(facadetest.Context{
+		StatePool_: s.StatePool,
+		State_:     s.State,
+		Resources_: common.NewResources(),
+		Auth_:      apiservertesting.FakeAuthorizer{Tag: user},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *rolesSuite) addOperatorRole(c *gc.C) {
+	results, err := s.usermanager.AddRoles(params.AddRoles{
+		Roles: []params.AddRole{{
+			Name:         "operator",
+			Access:       "write",
+			Capabilities: []string{"Action.*", "Client.FullStatus"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+}
+
+func (s *rolesSuite) TestAddRoles(c *gc.C) {
+	s.addOperatorRole(c)
+
+	results, err := s.usermanager.AddRoles(params.AddRoles{
+		Roles: []params.AddRole{
+			{Name: "operator", Access: "read", Capabilities: []string{"*.*"}},
+			{Name: "auditor", Access: "read", Capabilities: []string{"Client"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `role "operator" already exists`)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `role "auditor": capability "Client" not valid`)
+
+	role, err := s.State.Role("operator")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.Access(), gc.Equals, permission.WriteAccess)
+	c.Assert(role.CreatedBy(), gc.Equals, s.AdminUserTag(c).Id())
+}
+
+func (s *rolesSuite) TestAddRolesNotSuperuser(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	api := s.newAPI(c, bob.UserTag())
+	_, err := api.AddRoles(params.AddRoles{
+		Roles: []params.AddRole{{Name: "operator", Access: "read", Capabilities: []string{"*.*"}}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *rolesSuite) TestRemoveRoles(c *gc.C) {
+	s.addOperatorRole(c)
+
+	results, err := s.usermanager.RemoveRoles(params.RoleNames{
+		Names: []string{"operator", "admin", "unknown"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `removing built-in role "admin" not supported`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `role "unknown" not found`)
+}
+
+func (s *rolesSuite) TestRoleInfo(c *gc.C) {
+	s.addOperatorRole(c)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	api := s.newAPI(c, bob.UserTag())
+
+	results, err := api.RoleInfo(params.RoleNames{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Result, jc.DeepEquals, &params.RoleInfo{
+		Name:         "read",
+		Access:       "read",
+		Capabilities: []string{"*.*"},
+		BuiltIn:      true,
+	})
+	info := results.Results[3].Result
+	c.Assert(info.Name, gc.Equals, "operator")
+	c.Assert(info.Access, gc.Equals, "write")
+	c.Assert(info.Capabilities, jc.DeepEquals, []string{"Action.*", "Client.FullStatus"})
+	c.Assert(info.BuiltIn, jc.IsFalse)
+	c.Assert(info.CreatedBy, gc.Equals, s.AdminUserTag(c).Id())
+
+	results, err = api.RoleInfo(params.RoleNames{Names: []string{"operator", "unknown"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Result.Name, gc.Equals, "operator")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `role "unknown" not found`)
+}
+
+func (s *rolesSuite) TestGrantRoles(c *gc.C) {
+	s.addOperatorRole(c)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Access: permission.ReadAccess}).UserTag()
+	mary := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary", NoModelUser: true}).UserTag()
+	modelTag := s.Model.ModelTag()
+
+	results, err := s.usermanager.GrantRoles(params.GrantRoles{
+		Grants: []params.GrantRole{
+			{UserTag: bob.String(), ModelTag: modelTag.String(), Role: "operator"},
+			{UserTag: mary.String(), ModelTag: modelTag.String(), Role: "operator"},
+			{UserTag: bob.String(), ModelTag: modelTag.String(), Role: "unknown"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `role "unknown" not found`)
+
+	for _, user := range []names.UserTag{bob, mary} {
+		role, err := s.State.ModelUserRole(user)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(role, gc.Equals, "operator")
+		access, err := s.State.UserPermission(user, modelTag)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(access, gc.Equals, permission.WriteAccess)
+	}
+}
+
+func (s *rolesSuite) TestGrantRolesModelAdmin(c *gc.C) {
+	s.addOperatorRole(c)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	modelTag := s.Model.ModelTag()
+	grant := params.GrantRoles{
+		Grants: []params.GrantRole{{UserTag: bob.String(), ModelTag: modelTag.String(), Role: "operator"}},
+	}
+
+	reader := s.Factory.MakeUser(c, &factory.UserParams{Name: "readbob"})
+	results, err := s.newAPI(c, reader.UserTag()).GrantRoles(grant)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, "permission denied")
+
+	admin := s.Factory.MakeUser(c, &factory.UserParams{Name: "adminbob"})
+	results, err = s.newAPI(c, admin.UserTag()).GrantRoles(grant)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+}
@@ -0,0 +1,362 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/authentication"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+)
+
+// checkIsSuperuser returns an error unless the API user is a
+// controller superuser, who alone may manage user groups and roles.
+func (api *UserManagerAPI) checkIsSuperuser() error {
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil && !errors.Is(err, authentication.ErrorEntityMissingPermission) {
+		return errors.Trace(err)
+	}
+	if !api.isAdmin && !isSuperUser {
+		return apiservererrors.ErrPerm
+	}
+	return nil
+}
+
+// AddUserGroups adds new, empty, user groups to the controller.
+func (api *UserManagerAPI) AddUserGroups(args params.AddUserGroups) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := api.checkIsSuperuser(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Groups))
+	for i, arg := range args.Groups {
+		_, err := api.state.AddUserGroup(arg.Name, arg.External, api.apiUser.Id())
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+// RemoveUserGroups removes user groups from the controller, along with
+// all the access granted to them.
+func (api *UserManagerAPI) RemoveUserGroups(args params.UserGroupNames) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.RemoveAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := api.checkIsSuperuser(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Names))
+	for i, name := range args.Names {
+		err := api.state.RemoveUserGroup(name)
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+// UserGroupInfo returns information on user groups. If no names are
+// given, all the groups visible to the API user are returned: every
+// group for controller superusers, otherwise those that the user is a
+// member of.
+func (api *UserManagerAPI) UserGroupInfo(args params.UserGroupNames) (params.UserGroupInfoResults, error) {
+	var results params.UserGroupInfoResults
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil && !errors.Is(err, authentication.ErrorEntityMissingPermission) {
+		return results, errors.Trace(err)
+	}
+	isSuperUser = isSuperUser || api.isAdmin
+
+	memberOf, err := api.state.UserGroupsForUser(api.apiUser)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	memberOfNames := set.NewStrings()
+	for _, group := range memberOf {
+		memberOfNames.Add(group.Name())
+	}
+
+	if len(args.Names) == 0 {
+		groups := memberOf
+		if isSuperUser {
+			if groups, err = api.state.AllUserGroups(); err != nil {
+				return results, errors.Trace(err)
+			}
+		}
+		results.Results = make([]params.UserGroupInfoResult, len(groups))
+		for i, group := range groups {
+			results.Results[i].Result = userGroupInfo(group)
+		}
+		return results, nil
+	}
+
+	results.Results = make([]params.UserGroupInfoResult, len(args.Names))
+	for i, name := range args.Names {
+		group, err := api.state.UserGroup(name)
+		if err == nil && !isSuperUser && !memberOfNames.Contains(group.Name()) {
+			err = apiservererrors.ErrPerm
+		}
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = userGroupInfo(group)
+	}
+	return results, nil
+}
+
+func userGroupInfo(group *state.UserGroup) *params.UserGroupInfo {
+	members := group.Members()
+	info := &params.UserGroupInfo{
+		Name:        group.Name(),
+		External:    group.External(),
+		Members:     make([]string, len(members)),
+		CreatedBy:   group.CreatedBy(),
+		DateCreated: group.DateCreated(),
+	}
+	for i, member := range members {
+		info.Members[i] = member.Id()
+	}
+	return info
+}
+
+// AddUserGroupMembers adds users to user groups.
+func (api *UserManagerAPI) AddUserGroupMembers(args params.ModifyUserGroupMembers) (params.ErrorResults, error) {
+	return api.modifyUserGroupMembers(args, (*state.UserGroup).AddMembers)
+}
+
+// RemoveUserGroupMembers removes users from user groups.
+func (api *UserManagerAPI) RemoveUserGroupMembers(args params.ModifyUserGroupMembers) (params.ErrorResults, error) {
+	return api.modifyUserGroupMembers(args, (*state.UserGroup).RemoveMembers)
+}
+
+func (api *UserManagerAPI) modifyUserGroupMembers(
+	args params.ModifyUserGroupMembers,
+	modify func(*state.UserGroup, ...names.UserTag) error,
+) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := api.checkIsSuperuser(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Changes))
+	for i, arg := range args.Changes {
+		err := api.modifyOneUserGroupMembers(arg, modify)
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) modifyOneUserGroupMembers(
+	arg params.UserGroupMembers,
+	modify func(*state.UserGroup, ...names.UserTag) error,
+) error {
+	users := make([]names.UserTag, len(arg.UserTags))
+	for i, tag := range arg.UserTags {
+		user, err := names.ParseUserTag(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		users[i] = user
+	}
+	group, err := api.state.UserGroup(arg.Group)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(modify(group, users...))
+}
+
+// ModifyUserGroupAccess grants user groups access to, or revokes their
+// access from, models, clouds, offers and the controller.
+func (api *UserManagerAPI) ModifyUserGroupAccess(args params.ModifyUserGroupAccessRequest) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := api.checkIsSuperuser(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Changes))
+	for i, arg := range args.Changes {
+		err := api.modifyOneUserGroupAccess(arg)
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) modifyOneUserGroupAccess(arg params.ModifyUserGroupAccess) error {
+	access := permission.Access(arg.Access)
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+
+	st, target, release, err := api.userGroupAccessTarget(arg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+
+	current, err := st.UserGroupAccess(arg.Group, target)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return errors.Trace(err)
+	}
+
+	switch arg.Action {
+	case params.GrantUserGroupAccess:
+		if current != permission.NoAccess && equalOrGreaterAccess(target.Kind(), current, access) {
+			return errors.Errorf("user group already has %q access or greater", access)
+		}
+		return errors.Trace(st.SetUserGroupAccess(arg.Group, target, access))
+	case params.RevokeUserGroupAccess:
+		if current == permission.NoAccess {
+			return errors.NotFoundf("%s access for user group %q", target.Kind(), arg.Group)
+		}
+		remaining := revokedAccess(target.Kind(), access)
+		if remaining == permission.NoAccess {
+			return errors.Trace(st.RemoveUserGroupAccess(arg.Group, target))
+		}
+		if !equalOrGreaterAccess(target.Kind(), remaining, current) {
+			return errors.Trace(st.SetUserGroupAccess(arg.Group, target, remaining))
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown action %q", arg.Action)
+	}
+}
+
+// userGroupAccessTarget returns the state holding the object that the
+// access of a user group is to be modified on, and the object's tag.
+// The returned function must be called when the state is no longer
+// needed.
+func (api *UserManagerAPI) userGroupAccessTarget(arg params.ModifyUserGroupAccess) (*state.State, names.Tag, func(), error) {
+	noRelease := func() {}
+	if arg.OfferURL != "" {
+		url, err := crossmodel.ParseOfferURL(arg.OfferURL)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		if url.Source != "" {
+			return nil, nil, nil, errors.NotSupportedf("offer URL %q on another controller", arg.OfferURL)
+		}
+		ownerName := url.User
+		if ownerName == "" {
+			ownerName = api.apiUser.Id()
+		}
+		st, release, err := api.modelStateForName(url.ModelName, ownerName)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		return st, names.NewApplicationOfferTag(url.ApplicationName), release, nil
+	}
+
+	target, err := names.ParseTag(arg.TargetTag)
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	switch target.Kind() {
+	case names.ModelTagKind:
+		st, err := api.pool.Get(target.Id())
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		return st.State, target, func() { st.Release() }, nil
+	case names.CloudTagKind:
+		if _, err := api.state.Cloud(target.Id()); err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		return api.state, target, noRelease, nil
+	case names.ControllerTagKind:
+		if target != api.state.ControllerTag() {
+			return nil, nil, nil, errors.NotFoundf("controller %q", target.Id())
+		}
+		return api.state, target, noRelease, nil
+	default:
+		return nil, nil, nil, errors.NotValidf("%q as a target", target.Kind())
+	}
+}
+
+// modelStateForName returns the state of the named model.
+func (api *UserManagerAPI) modelStateForName(modelName, ownerName string) (*state.State, func(), error) {
+	uuids, err := api.state.AllModelUUIDs()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	for _, uuid := range uuids {
+		st, err := api.pool.Get(uuid)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		model, err := st.Model()
+		if err != nil {
+			st.Release()
+			return nil, nil, errors.Trace(err)
+		}
+		if model.Name() == modelName && model.Owner().Id() == ownerName {
+			return st.State, func() { st.Release() }, nil
+		}
+		st.Release()
+	}
+	return nil, nil, errors.NotFoundf("model %s/%s", ownerName, modelName)
+}
+
+// equalOrGreaterAccess returns whether access a is equal to or greater
+// than access b, as ordered for the given kind of target.
+func equalOrGreaterAccess(targetKind string, a, b permission.Access) bool {
+	switch targetKind {
+	case names.ModelTagKind:
+		return a.EqualOrGreaterModelAccessThan(b)
+	case names.CloudTagKind:
+		return a.EqualOrGreaterCloudAccessThan(b)
+	case names.ApplicationOfferTagKind:
+		return a.EqualOrGreaterOfferAccessThan(b)
+	case names.ControllerTagKind:
+		return a.EqualOrGreaterControllerAccessThan(b)
+	}
+	return false
+}
+
+// revokedAccess returns the access that remains after the given access
+// is revoked, for the given kind of target. As when revoking the access
+// of users, revoking one level of access leaves the level below it.
+func revokedAccess(targetKind string, access permission.Access) permission.Access {
+	switch targetKind {
+	case names.ModelTagKind:
+		switch access {
+		case permission.WriteAccess:
+			return permission.ReadAccess
+		case permission.AdminAccess:
+			return permission.WriteAccess
+		}
+	case names.CloudTagKind:
+		if access == permission.AdminAccess {
+			return permission.AddModelAccess
+		}
+	case names.ApplicationOfferTagKind:
+		switch access {
+		case permission.ConsumeAccess:
+			return permission.ReadAccess
+		case permission.AdminAccess:
+			return permission.ConsumeAccess
+		}
+	case names.ControllerTagKind:
+		if access == permission.SuperuserAccess {
+			return permission.LoginAccess
+		}
+	}
+	return permission.NoAccess
+}
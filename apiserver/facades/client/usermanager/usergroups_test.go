@@ -0,0 +1,208 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade/facadetest"
+	"github.com/juju/juju/apiserver/facades/client/usermanager"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/testing/factory"
+)
+
+type userGroupsSuite struct {
+	jujutesting.JujuConnSuite
+
+	usermanager *usermanager.UserManagerAPI
+}
+
+var _ = gc.Suite(&userGroupsSuite{})
+
+func (s *userGroupsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.usermanager = s.newAPI(c, s.AdminUserTag(c))
+}
+
+func (s *userGroupsSuite) newAPI(c *gc.C, user names.UserTag) *usermanager.UserManagerAPI {
+	api, err := usermanager.NewUserManagerAPI(facadetest.Context{
+		StatePool_: s.StatePool,
+		State_:     s.State,
+		Resources_: common.NewResources(),
+		Auth_:      apiservertesting.FakeAuthorizer{Tag: user},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *userGroupsSuite) TestAddUserGroups(c *gc.C) {
+	results, err := s.usermanager.AddUserGroups(params.AddUserGroups{
+		Groups: []params.AddUserGroup{
+			{Name: "developers"},
+			{Name: "platform", External: true},
+			{Name: "developers"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `user group "developers" already exists`)
+
+	group, err := s.State.UserGroup("platform")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.External(), jc.IsTrue)
+	c.Assert(group.CreatedBy(), gc.Equals, s.AdminUserTag(c).Id())
+}
+
+func (s *userGroupsSuite) TestAddUserGroupsNotSuperuser(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	api := s.newAPI(c, bob.UserTag())
+	_, err := api.AddUserGroups(params.AddUserGroups{
+		Groups: []params.AddUserGroup{{Name: "developers"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userGroupsSuite) TestRemoveUserGroups(c *gc.C) {
+	_, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.usermanager.RemoveUserGroups(params.UserGroupNames{
+		Names: []string{"developers", "unknown"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `user group "unknown" not found`)
+}
+
+func (s *userGroupsSuite) TestUserGroupMembers(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.usermanager.AddUserGroupMembers(params.ModifyUserGroupMembers{
+		Changes: []params.UserGroupMembers{{
+			Group:    "developers",
+			UserTags: []string{bob.Tag().String(), "user-mary@external"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	info, err := s.usermanager.UserGroupInfo(params.UserGroupNames{Names: []string{"developers"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results, gc.HasLen, 1)
+	c.Assert(info.Results[0].Error, gc.IsNil)
+	c.Assert(info.Results[0].Result.Members, jc.DeepEquals, []string{"bob", "mary@external"})
+
+	results, err = s.usermanager.RemoveUserGroupMembers(params.ModifyUserGroupMembers{
+		Changes: []params.UserGroupMembers{{
+			Group:    "developers",
+			UserTags: []string{"user-mary@external"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	group, err := s.State.UserGroup("developers")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{bob.UserTag()})
+}
+
+func (s *userGroupsSuite) TestUserGroupInfoNotSuperuser(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	developers, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = developers.AddMembers(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddUserGroup("testers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.newAPI(c, bob.UserTag())
+	info, err := api.UserGroupInfo(params.UserGroupNames{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results, gc.HasLen, 1)
+	c.Assert(info.Results[0].Result.Name, gc.Equals, "developers")
+
+	info, err = api.UserGroupInfo(params.UserGroupNames{Names: []string{"testers"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results, gc.HasLen, 1)
+	c.Assert(info.Results[0].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userGroupsSuite) modifyAccess(c *gc.C, action params.UserGroupAction, access permission.Access, target names.Tag) error {
+	results, err := s.usermanager.ModifyUserGroupAccess(params.ModifyUserGroupAccessRequest{
+		Changes: []params.ModifyUserGroupAccess{{
+			Group:     "developers",
+			Action:    action,
+			Access:    string(access),
+			TargetTag: target.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return results.OneError()
+}
+
+func (s *userGroupsSuite) TestModifyUserGroupModelAccess(c *gc.C) {
+	_, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	modelTag := s.Model.ModelTag()
+
+	err = s.modifyAccess(c, params.GrantUserGroupAccess, permission.AdminAccess, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.modifyAccess(c, params.GrantUserGroupAccess, permission.WriteAccess, modelTag)
+	c.Assert(err, gc.ErrorMatches, `user group already has "write" access or greater`)
+
+	// Revoking admin leaves write access.
+	err = s.modifyAccess(c, params.RevokeUserGroupAccess, permission.AdminAccess, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserGroupAccess("developers", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	// Revoking read removes all access.
+	err = s.modifyAccess(c, params.RevokeUserGroupAccess, permission.ReadAccess, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserGroupAccess("developers", modelTag)
+	c.Assert(err, gc.ErrorMatches, `.*not found`)
+
+	err = s.modifyAccess(c, params.RevokeUserGroupAccess, permission.ReadAccess, modelTag)
+	c.Assert(err, gc.ErrorMatches, `model access for user group "developers" not found`)
+}
+
+func (s *userGroupsSuite) TestModifyUserGroupControllerAccess(c *gc.C) {
+	_, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.modifyAccess(c, params.GrantUserGroupAccess, permission.SuperuserAccess, s.State.ControllerTag())
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserGroupAccess("developers", s.State.ControllerTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.SuperuserAccess)
+
+	err = s.modifyAccess(c, params.GrantUserGroupAccess, permission.SuperuserAccess, names.NewControllerTag("deadbeef-0bad-400d-8000-4b1d0d06f00d"))
+	c.Assert(err, gc.ErrorMatches, `controller "deadbeef-0bad-400d-8000-4b1d0d06f00d" not found`)
+}
+
+func (s *userGroupsSuite) TestModifyUserGroupAccessNotSuperuser(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	api := s.newAPI(c, bob.UserTag())
+	_, err := api.ModifyUserGroupAccess(params.ModifyUserGroupAccessRequest{
+		Changes: []params.ModifyUserGroupAccess{{
+			Group:     "developers",
+			Action:    params.GrantUserGroupAccess,
+			Access:    string(permission.ReadAccess),
+			TargetTag: s.Model.ModelTag().String(),
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
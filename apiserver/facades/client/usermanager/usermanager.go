@@ -33,6 +33,60 @@ type UserManagerAPI struct {
 	isAdmin    bool
 }
 
+// UserManagerAPIV3 is version 3 of the UserManager API, which lacks
+// user groups.
+type UserManagerAPIV3 struct {
+	*UserManagerAPI
+}
+
+// AddUserGroups isn't on the V3 API.
+func (*UserManagerAPIV3) AddUserGroups(_, _ struct{}) {}
+
+// RemoveUserGroups isn't on the V3 API.
+func (*UserManagerAPIV3) RemoveUserGroups(_, _ struct{}) {}
+
+// UserGroupInfo isn't on the V3 API.
+func (*UserManagerAPIV3) UserGroupInfo(_, _ struct{}) {}
+
+// AddUserGroupMembers isn't on the V3 API.
+func (*UserManagerAPIV3) AddUserGroupMembers(_, _ struct{}) {}
+
+// RemoveUserGroupMembers isn't on the V3 API.
+func (*UserManagerAPIV3) RemoveUserGroupMembers(_, _ struct{}) {}
+
+// ModifyUserGroupAccess isn't on the V3 API.
+func (*UserManagerAPIV3) ModifyUserGroupAccess(_, _ struct{}) {}
+
+// AddRoles isn't on the V3 API.
+func (*UserManagerAPIV3) AddRoles(_, _ struct{}) {}
+
+// RemoveRoles isn't on the V3 API.
+func (*UserManagerAPIV3) RemoveRoles(_, _ struct{}) {}
+
+// RoleInfo isn't on the V3 API.
+func (*UserManagerAPIV3) RoleInfo(_, _ struct{}) {}
+
+// GrantRoles isn't on the V3 API.
+func (*UserManagerAPIV3) GrantRoles(_, _ struct{}) {}
+
+// UserManagerAPIV4 is version 4 of the UserManager API, which lacks
+// roles.
+type UserManagerAPIV4 struct {
+	*UserManagerAPI
+}
+
+// AddRoles isn't on the V4 API.
+func (*UserManagerAPIV4) AddRoles(_, _ struct{}) {}
+
+// RemoveRoles isn't on the V4 API.
+func (*UserManagerAPIV4) RemoveRoles(_, _ struct{}) {}
+
+// RoleInfo isn't on the V4 API.
+func (*UserManagerAPIV4) RoleInfo(_, _ struct{}) {}
+
+// GrantRoles isn't on the V4 API.
+func (*UserManagerAPIV4) GrantRoles(_, _ struct{}) {}
+
 func (api *UserManagerAPI) hasControllerAdminAccess() (bool, error) {
 	err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
 	return err == nil, err
@@ -15,6 +15,7 @@ import (
 	"gopkg.in/macaroon.v2"
 
 	"github.com/juju/juju/apiserver/common/crossmodel"
+	commonsecrets "github.com/juju/juju/apiserver/common/secrets"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facade"
 	corelogger "github.com/juju/juju/core/logger"
@@ -221,6 +222,16 @@ func (s *CrossModelSecretsAPI) getSecretContent(arg params.GetRemoteSecretConten
 	}
 
 	val, valueRef, err := secretState.GetSecretValue(uri, wantRevision)
+	if err == nil && valueRef != nil {
+		// Content in backends which only the controller accesses is
+		// served here; the consumer is never given the backend config.
+		val, err = commonsecrets.ControllerContent(func() (*provider.ModelBackendConfigInfo, error) {
+			return s.backendConfigGetter(uri.SourceUUID)
+		}, valueRef)
+		if val != nil {
+			valueRef = nil
+		}
+	}
 	content := &secrets.ContentParams{SecretValue: val, ValueRef: valueRef}
 	if err != nil || content.ValueRef == nil {
 		return content, nil, latestRevision, errors.Trace(err)
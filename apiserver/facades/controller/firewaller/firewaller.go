@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/common/firewall"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facade"
+	appfacade "github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/rpc/params"
@@ -50,7 +51,18 @@ type FirewallerAPI struct {
 	appEndpointBindings map[string]map[string]string
 }
 
-// NewStateFirewallerAPI creates a new server-side FirewallerAPIV7 facade.
+// FirewallerAPIV7 provides access to the Firewaller API facade version 7.
+type FirewallerAPIV7 struct {
+	*FirewallerAPI
+}
+
+// GetEgressRules isn't on the v7 API.
+func (*FirewallerAPIV7) GetEgressRules(_, _ struct{}) {}
+
+// WatchEgressRules isn't on the v7 API.
+func (*FirewallerAPIV7) WatchEgressRules(_, _ struct{}) {}
+
+// NewStateFirewallerAPI creates a new server-side FirewallerAPI facade.
 func NewStateFirewallerAPI(
 	st State,
 	resources facade.Resources,
@@ -607,6 +619,82 @@ func (f *FirewallerAPI) GetExposeInfo(args params.Entities) (params.ExposeInfoRe
 	return result, nil
 }
 
+// GetEgressRules returns the egress rules configured for each of the
+// specified applications. Applications without an egress allow-list have
+// no rules, and their outbound traffic is not restricted.
+func (f *FirewallerAPI) GetEgressRules(args params.Entities) (params.EgressRulesResults, error) {
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.EgressRulesResults{}, err
+	}
+
+	result := params.EgressRulesResults{
+		Results: make([]params.EgressRulesResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		appConfig, err := application.ApplicationConfig()
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		rules, err := appfacade.EgressRules(appConfig)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		for _, rule := range rules {
+			result.Results[i].Rules = append(result.Results[i].Rules, params.EgressRule{
+				DestinationCIDR: rule.DestinationCIDR,
+				PortRange:       params.FromNetworkPortRange(rule.PortRange),
+			})
+		}
+	}
+	return result, nil
+}
+
+// WatchEgressRules returns a NotifyWatcher for each of the specified
+// applications, which notifies of potential changes to its egress rules.
+func (f *FirewallerAPI) WatchEgressRules(args params.Entities) (params.NotifyWatchResults, error) {
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		watch := application.WatchApplicationConfig()
+		// Consume the initial event.
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = f.resources.Register(watch)
+		} else {
+			result.Results[i].Error = apiservererrors.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return result, nil
+}
+
 // SpaceInfos returns a comprehensive representation of either all spaces or
 // a filtered subset of the known spaces and their associated subnet details.
 func (f *FirewallerAPI) SpaceInfos(args params.SpaceInfosParams) (params.SpaceInfos, error) {
@@ -10,6 +10,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"go.uber.org/mock/gomock"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/cloudspec"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/firewaller"
 	"github.com/juju/juju/apiserver/facades/controller/firewaller/mocks"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coreconfig "github.com/juju/juju/core/config"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
@@ -273,6 +275,86 @@ func (s *firewallerSuite) TestGetExposeInfo(c *gc.C) {
 	})
 }
 
+func (s *firewallerSuite) TestGetEgressRules(c *gc.C) {
+	defer s.ctrl.Finish()
+
+	err := s.application.UpdateApplicationConfig(coreconfig.ConfigAttributes{
+		"egress-allow": "10.0.0.0/8:5432/tcp,0.0.0.0/0:53/udp",
+	}, nil, egressSchema, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	result, err := s.firewaller.GetEgressRules(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.EgressRulesResults{
+		Results: []params.EgressRulesResult{
+			{Rules: []params.EgressRule{{
+				DestinationCIDR: "0.0.0.0/0",
+				PortRange:       params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+			}, {
+				DestinationCIDR: "10.0.0.0/8",
+				PortRange:       params.PortRange{FromPort: 5432, ToPort: 5432, Protocol: "tcp"},
+			}}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`application "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Without an allow-list there are no rules.
+	err = s.application.UpdateApplicationConfig(nil, []string{"egress-allow"}, egressSchema, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.firewaller.GetEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.EgressRulesResults{
+		Results: []params.EgressRulesResult{{}},
+	})
+}
+
+func (s *firewallerSuite) TestWatchEgressRules(c *gc.C) {
+	defer s.ctrl.Finish()
+
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	result, err := s.firewaller.WatchEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+		{Tag: s.machines[0].Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done.
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// The initial event has been consumed.
+	wc := statetesting.NewNotifyWatcherC(c, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.application.UpdateApplicationConfig(coreconfig.ConfigAttributes{
+		"egress-allow": "10.0.0.0/8:5432/tcp",
+	}, nil, egressSchema, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+var egressSchema = environschema.Fields{
+	"egress-allow": environschema.Attr{Type: environschema.Tstring},
+}
+
 func (s *firewallerSuite) TestWatchSubnets(c *gc.C) {
 	defer s.ctrl.Finish()
 
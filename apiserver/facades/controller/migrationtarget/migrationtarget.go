@@ -40,9 +40,14 @@ type API struct {
 	requiredMigrationFacadeVersions facades.FacadeVersions
 }
 
+// APIV3 implements the V3 version of the API facade.
+type APIV3 struct {
+	*API
+}
+
 // APIV1 implements the V1 version of the API facade.
 type APIV1 struct {
-	*API
+	*APIV3
 }
 
 // APIV2 implements the V2 version of the API facade.
@@ -87,43 +92,102 @@ func checkAuth(authorizer facade.Authorizer, st *state.State) error {
 // Prechecks ensure that the target controller is ready to accept a
 // model migration.
 func (api *API) Prechecks(model params.MigrationModelInfo) error {
+	if err := api.checkSourceFacadeVersions(model); err != nil {
+		return errors.Trace(err)
+	}
+	backend, modelInfo, presence, err := api.precheckArgs(model)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return migration.TargetPrecheck(
+		backend,
+		migration.PoolShim(api.pool),
+		modelInfo,
+		presence,
+	)
+}
+
+// PrecheckReport runs the same checks as Prechecks, but rather than
+// stopping at the first problem it reports every problem which would
+// prevent the model being migrated to this controller.
+func (api *API) PrecheckReport(model params.MigrationModelInfo) (params.MigrationPrecheckResult, error) {
+	result := params.MigrationPrecheckResult{
+		ModelTag: names.NewModelTag(model.UUID).String(),
+	}
+	if err := api.checkSourceFacadeVersions(model); err != nil {
+		result.Blockers = append(result.Blockers, params.MigrationBlocker{
+			Entity:  "controller",
+			Message: err.Error(),
+		})
+	}
+	backend, modelInfo, presence, err := api.precheckArgs(model)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	blockers, err := migration.TargetPrecheckReport(
+		backend,
+		migration.PoolShim(api.pool),
+		modelInfo,
+		presence,
+	)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, blocker := range blockers {
+		result.Blockers = append(result.Blockers, params.MigrationBlocker{
+			Entity:  blocker.Entity,
+			Message: blocker.Message,
+		})
+	}
+	return result, nil
+}
+
+// checkSourceFacadeVersions ensures that the source controller has the
+// facades required to perform the migration.
+func (api *API) checkSourceFacadeVersions(model params.MigrationModelInfo) error {
 	// If there are no required migration facade versions, then we
 	// don't need to check anything.
-	if len(api.requiredMigrationFacadeVersions) > 0 {
-		// Ensure that when attempting to migrate a model, the source
-		// controller has the required facades for the migration.
-		sourceFacadeVersions := facades.FacadeVersions{}
-		for name, versions := range model.FacadeVersions {
-			sourceFacadeVersions[name] = versions
-		}
-		if !facades.CompleteIntersection(api.requiredMigrationFacadeVersions, sourceFacadeVersions) {
-			majorMinor := fmt.Sprintf("%d.%d",
-				model.ControllerAgentVersion.Major,
-				model.ControllerAgentVersion.Minor,
-			)
-
-			// If the patch is zero, then we don't need to mention it.
-			var patchMessage string
-			if model.ControllerAgentVersion.Patch > 0 {
-				patchMessage = fmt.Sprintf(", that is greater than %s.%d", majorMinor, model.ControllerAgentVersion.Patch)
-			}
-
-			return errors.Errorf(`
+	if len(api.requiredMigrationFacadeVersions) == 0 {
+		return nil
+	}
+	sourceFacadeVersions := facades.FacadeVersions{}
+	for name, versions := range model.FacadeVersions {
+		sourceFacadeVersions[name] = versions
+	}
+	if facades.CompleteIntersection(api.requiredMigrationFacadeVersions, sourceFacadeVersions) {
+		return nil
+	}
+	majorMinor := fmt.Sprintf("%d.%d",
+		model.ControllerAgentVersion.Major,
+		model.ControllerAgentVersion.Minor,
+	)
+
+	// If the patch is zero, then we don't need to mention it.
+	var patchMessage string
+	if model.ControllerAgentVersion.Patch > 0 {
+		patchMessage = fmt.Sprintf(", that is greater than %s.%d", majorMinor, model.ControllerAgentVersion.Patch)
+	}
+
+	return errors.Errorf(`
 Source controller does not support required facades for performing migration.
 Upgrade the controller to a newer version of %s%s or migrate to a controller
 with an earlier version of the target controller and try again.
 
 `[1:], majorMinor, patchMessage)
-		}
-	}
+}
 
+// precheckArgs returns what is needed to run the target prechecks for
+// the model described.
+func (api *API) precheckArgs(model params.MigrationModelInfo) (
+	migration.PrecheckBackend, coremigration.ModelInfo, migration.ModelPresence, error,
+) {
 	ownerTag, err := names.ParseUserTag(model.OwnerTag)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, coremigration.ModelInfo{}, nil, errors.Trace(err)
 	}
 	controllerState, err := api.pool.SystemState()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, coremigration.ModelInfo{}, nil, errors.Trace(err)
 	}
 	// NOTE (thumper): it isn't clear to me why api.state would be different
 	// from the controllerState as I had thought that the Precheck call was
@@ -131,20 +195,16 @@ with an earlier version of the target controller and try again.
 	// controllerState.
 	backend, err := migration.PrecheckShim(api.state, controllerState)
 	if err != nil {
-		return errors.Annotate(err, "creating backend")
+		return nil, coremigration.ModelInfo{}, nil, errors.Annotate(err, "creating backend")
 	}
-	return migration.TargetPrecheck(
-		backend,
-		migration.PoolShim(api.pool),
-		coremigration.ModelInfo{
-			UUID:                   model.UUID,
-			Name:                   model.Name,
-			Owner:                  ownerTag,
-			AgentVersion:           model.AgentVersion,
-			ControllerAgentVersion: model.ControllerAgentVersion,
-		},
-		api.presence.ModelPresence(controllerState.ModelUUID()),
-	)
+	modelInfo := coremigration.ModelInfo{
+		UUID:                   model.UUID,
+		Name:                   model.Name,
+		Owner:                  ownerTag,
+		AgentVersion:           model.AgentVersion,
+		ControllerAgentVersion: model.ControllerAgentVersion,
+	}
+	return backend, modelInfo, api.presence.ModelPresence(controllerState.ModelUUID()), nil
 }
 
 // Import takes a serialized Juju model, deserializes it, and
@@ -162,6 +222,35 @@ func (api *API) Import(serialized params.SerializedModel) error {
 	return err
 }
 
+// TrialImport imports a serialized Juju model into a throwaway model,
+// to check that it could be migrated to this controller, and then
+// removes the throwaway model again.
+func (api *API) TrialImport(serialized params.SerializedModel) error {
+	controller := state.NewController(api.pool)
+	modelUUID, importErr := migration.TrialImportModel(controller, serialized.Bytes)
+	if modelUUID == "" {
+		return errors.Trace(importErr)
+	}
+	// Remove whatever was imported, even if the import failed
+	// part way through. The import error takes precedence.
+	removeErr := api.removeTrialModel(modelUUID)
+	if importErr != nil {
+		return errors.Trace(importErr)
+	}
+	return errors.Annotate(removeErr, "removing trial model")
+}
+
+func (api *API) removeTrialModel(modelUUID string) error {
+	st, err := api.pool.Get(modelUUID)
+	if errors.Is(err, errors.NotFound) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+	return errors.Trace(st.RemoveImportingModelDocs())
+}
+
 func (api *API) getModel(modelTag string) (*state.Model, func(), error) {
 	tag, err := names.ParseModelTag(modelTag)
 	if err != nil {
@@ -391,3 +480,9 @@ func (api *API) CACert() (params.BytesResult, error) {
 	caCert, _ := cfg.CACert()
 	return params.BytesResult{Result: []byte(caCert)}, nil
 }
+
+// PrecheckReport isn't on the V3 API.
+func (*APIV3) PrecheckReport(_, _ struct{}) {}
+
+// TrialImport isn't on the V3 API.
+func (*APIV3) TrialImport(_, _ struct{}) {}
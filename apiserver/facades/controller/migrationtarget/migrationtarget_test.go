@@ -81,7 +81,7 @@ func (s *Suite) SetUpTest(c *gc.C) {
 }
 
 func (s *Suite) TestFacadeRegistered(c *gc.C) {
-	aFactory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 3)
+	aFactory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 4)
 	c.Assert(err, jc.ErrorIsNil)
 
 	api, err := aFactory(&facadetest.Context{
@@ -93,6 +93,19 @@ func (s *Suite) TestFacadeRegistered(c *gc.C) {
 	c.Assert(api, gc.FitsTypeOf, new(migrationtarget.API))
 }
 
+func (s *Suite) TestFacadeRegisteredV3(c *gc.C) {
+	aFactory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 3)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api, err := aFactory(&facadetest.Context{
+		State_:     s.State,
+		Resources_: s.resources,
+		Auth_:      s.authorizer,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api, gc.FitsTypeOf, new(migrationtarget.APIV3))
+}
+
 func (s *Suite) TestFacadeRegisteredV2(c *gc.C) {
 	aFactory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 2)
 	c.Assert(err, jc.ErrorIsNil)
@@ -192,6 +205,70 @@ with an earlier version of the target controller and try again.
 `[1:])
 }
 
+func (s *Suite) TestPrecheckReport(c *gc.C) {
+	controllerVersion := s.controllerVersion(c)
+
+	// Set the model version ahead of the controller.
+	modelVersion := controllerVersion
+	modelVersion.Minor++
+
+	api := s.mustNewAPIWithFacadeVersions(c, facades.FacadeVersions{
+		"MigrationTarget": []int{1},
+	})
+	args := params.MigrationModelInfo{
+		UUID:                   "uuid",
+		Name:                   "some-model",
+		OwnerTag:               names.NewUserTag("someone").String(),
+		AgentVersion:           modelVersion,
+		ControllerAgentVersion: controllerVersion,
+	}
+	result, err := api.PrecheckReport(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.ModelTag, gc.Equals, "model-uuid")
+	c.Assert(result.Blockers, gc.HasLen, 2)
+	c.Check(result.Blockers[0].Entity, gc.Equals, "controller")
+	c.Check(result.Blockers[0].Message, gc.Matches, "Source controller does not support required facades(.|\n)*")
+	c.Check(result.Blockers[1], jc.DeepEquals, params.MigrationBlocker{
+		Entity:  "agent version",
+		Message: fmt.Sprintf("model has higher version than target controller (%s > %s)", modelVersion, controllerVersion),
+	})
+}
+
+func (s *Suite) TestPrecheckReportNoBlockers(c *gc.C) {
+	api := s.mustNewAPI(c)
+	args := params.MigrationModelInfo{
+		UUID:                   "uuid",
+		Name:                   "some-model",
+		OwnerTag:               names.NewUserTag("someone").String(),
+		AgentVersion:           s.controllerVersion(c),
+		ControllerAgentVersion: s.controllerVersion(c),
+	}
+	result, err := api.PrecheckReport(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Blockers, gc.HasLen, 0)
+}
+
+func (s *Suite) TestTrialImport(c *gc.C) {
+	before, err := s.State.AllModelUUIDs()
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.mustNewAPI(c)
+	_, bytes := s.makeExportedModel(c)
+	err = api.TrialImport(params.SerializedModel{Bytes: bytes})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The throwaway model should have been removed.
+	after, err := s.State.AllModelUUIDs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(after, jc.SameContents, before)
+}
+
+func (s *Suite) TestTrialImportBadBytes(c *gc.C) {
+	api := s.mustNewAPI(c)
+	err := api.TrialImport(params.SerializedModel{Bytes: []byte("not a model")})
+	c.Assert(err, gc.ErrorMatches, "yaml: unmarshal errors:\n.*")
+}
+
 func (s *Suite) TestImport(c *gc.C) {
 	api := s.mustNewAPI(c)
 	tag := s.importModel(c, api)
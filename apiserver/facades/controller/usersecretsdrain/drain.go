@@ -25,6 +25,7 @@ type SecretsDrainAPI struct {
 
 	drainConfigGetter   commonsecrets.BackendDrainConfigGetter
 	backendConfigGetter commonsecrets.BackendConfigGetter
+	adminConfigGetter   commonsecrets.BackendAdminConfigGetter
 }
 
 // GetSecretBackendConfigs gets the config needed to create a client to secret backends for the drain worker.
@@ -119,6 +120,12 @@ func (s *SecretsDrainAPI) getSecretContent(arg params.GetSecretContentArg) (
 	}
 
 	val, valueRef, err := s.secretsState.GetSecretValue(md.URI, md.LatestRevision)
+	if err == nil && valueRef != nil {
+		val, err = commonsecrets.ControllerContent(s.adminConfigGetter, valueRef)
+		if val != nil {
+			valueRef = nil
+		}
+	}
 	if err != nil {
 		return nil, nil, false, errors.Trace(err)
 	}
@@ -164,6 +171,12 @@ func (s *SecretsDrainAPI) GetSecretRevisionContentInfo(arg params.SecretRevision
 
 	for i, rev := range arg.Revisions {
 		val, valueRef, err := s.secretsState.GetSecretValue(uri, rev)
+		if err == nil && valueRef != nil {
+			val, err = commonsecrets.ControllerContent(s.adminConfigGetter, valueRef)
+			if val != nil {
+				valueRef = nil
+			}
+		}
 		if err != nil {
 			result.Results[i].Error = apiservererrors.ServerError(err)
 			continue
@@ -4,18 +4,23 @@
 package usersecretsdrain_test
 
 import (
+	"context"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"go.uber.org/mock/gomock"
 	gc "gopkg.in/check.v1"
 
+	commonsecrets "github.com/juju/juju/apiserver/common/secrets"
 	facademocks "github.com/juju/juju/apiserver/facade/mocks"
 	"github.com/juju/juju/apiserver/facades/controller/usersecretsdrain"
 	"github.com/juju/juju/apiserver/facades/controller/usersecretsdrain/mocks"
 	coresecrets "github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/secrets/provider"
+	"github.com/juju/juju/secrets/provider/file"
+	"github.com/juju/juju/secrets/provider/juju"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -25,6 +30,8 @@ type drainSuite struct {
 	authorizer   *facademocks.MockAuthorizer
 	secretsState *mocks.MockSecretsState
 	facade       *usersecretsdrain.SecretsDrainAPI
+
+	fileBackend provider.SecretsBackend
 }
 
 var _ = gc.Suite(&drainSuite{})
@@ -74,8 +81,35 @@ func (s *drainSuite) setup(c *gc.C) *gomock.Controller {
 		}, nil
 	}
 
-	var err error
-	s.facade, err = usersecretsdrain.NewTestAPI(s.authorizer, s.secretsState, backendConfigGetter, drainConfigGetter)
+	// The file backend's content is only accessed by the controller.
+	fileKey, err := file.NewKey()
+	c.Assert(err, jc.ErrorIsNil)
+	fileConfig := provider.ModelBackendConfig{
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		ModelUUID:      coretesting.ModelTag.Id(),
+		ModelName:      "fred",
+		BackendConfig: provider.BackendConfig{
+			BackendType: file.BackendType,
+			Config:      map[string]interface{}{"path": c.MkDir(), "key": fileKey},
+		},
+	}
+	s.fileBackend, err = file.NewProvider().NewBackend(&fileConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	adminConfigGetter := func() (*provider.ModelBackendConfigInfo, error) {
+		info, err := drainConfigGetter("")
+		if err == nil {
+			info.Configs["file-backend-id"] = fileConfig
+		}
+		return info, err
+	}
+	s.PatchValue(&commonsecrets.GetProvider, func(backendType string) (provider.SecretBackendProvider, error) {
+		if backendType == file.BackendType {
+			return file.NewProvider(), nil
+		}
+		return juju.NewProvider(), nil
+	})
+
+	s.facade, err = usersecretsdrain.NewTestAPI(s.authorizer, s.secretsState, backendConfigGetter, drainConfigGetter, adminConfigGetter)
 	c.Assert(err, jc.ErrorIsNil)
 
 	return ctrl
@@ -238,3 +272,30 @@ func (s *drainSuite) TestGetSecretRevisionContentInfoExternal(c *gc.C) {
 		}},
 	})
 }
+
+func (s *drainSuite) TestGetSecretRevisionContentInfoControllerContent(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	uri := coresecrets.NewURI()
+	data := map[string]string{"foo": "bar"}
+	revisionID, err := s.fileBackend.SaveContent(context.Background(), uri, 666, coresecrets.NewSecretValue(data))
+	c.Assert(err, jc.ErrorIsNil)
+	s.secretsState.EXPECT().GetSecretValue(uri, 666).Return(
+		nil, &coresecrets.ValueRef{
+			BackendID:  "file-backend-id",
+			RevisionID: revisionID,
+		}, nil,
+	)
+
+	results, err := s.facade.GetSecretRevisionContentInfo(params.SecretRevisionArg{
+		URI:       uri.String(),
+		Revisions: []int{666},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// The content is served by the controller, without the backend config.
+	c.Assert(results, jc.DeepEquals, params.SecretContentResults{
+		Results: []params.SecretContentResult{{
+			Content: params.SecretContentParams{Data: data},
+		}},
+	})
+}
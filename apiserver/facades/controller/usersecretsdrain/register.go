@@ -56,10 +56,15 @@ func newUserSecretsDrainAPI(context facade.Context) (*SecretsDrainAPI, error) {
 		return commonsecrets.DrainBackendConfigInfo(backendID, commonsecrets.SecretsModel(model), authTag, leadershipChecker)
 	}
 
+	secretBackendAdminConfigGetter := func() (*provider.ModelBackendConfigInfo, error) {
+		return commonsecrets.AdminBackendConfigInfo(commonsecrets.SecretsModel(model))
+	}
+
 	return &SecretsDrainAPI{
 		SecretsDrainAPI:     commonDrainAPI,
 		drainConfigGetter:   secretBackendDrainConfigGetter,
 		backendConfigGetter: secretBackendConfigGetter,
+		adminConfigGetter:   secretBackendAdminConfigGetter,
 		secretsState:        state.NewSecrets(context.State()),
 	}, nil
 }
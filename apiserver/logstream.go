@@ -11,6 +11,7 @@ import (
 	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/featureflag"
+	"github.com/juju/loggo"
 
 	"github.com/juju/juju/apiserver/websocket"
 	corelogger "github.com/juju/juju/core/logger"
@@ -54,6 +55,10 @@ func newLogStreamEndpointHandler(ctxt httpContext) *logStreamEndpointHandler {
 //
 //	all -> string - one of [true, false], if true, include records from all models
 //	sink -> string - the name of the log forwarding target
+//	format -> string - one of [compact, structured], the shape of the records sent
+//	includeEntity -> []string - lists entity tags to include in the response
+//	includeModule -> []string - lists logging modules to include in the response
+//	level -> string - the minimum level of the records sent
 func (h *logStreamEndpointHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	logger.Infof("log stream request handler starting")
 	handler := func(conn *websocket.Conn) {
@@ -95,6 +100,12 @@ func (h *logStreamEndpointHandler) newLogStreamRequestHandler(conn messageWriter
 		return nil, errors.Annotate(err, "decoding schema")
 	}
 
+	switch cfg.Format {
+	case "", params.LogStreamFormatCompact, params.LogStreamFormatStructured:
+	default:
+		return nil, errors.NotValidf("log stream format %q", cfg.Format)
+	}
+
 	tailer, err := h.newTailer(source, cfg, clock)
 	if err != nil {
 		return nil, errors.Annotate(err, "creating new tailer")
@@ -105,6 +116,7 @@ func (h *logStreamEndpointHandler) newLogStreamRequestHandler(conn messageWriter
 		req:        req,
 		tailer:     tailer,
 		poolHelper: ph,
+		structured: cfg.Format == params.LogStreamFormatStructured,
 	}
 	return reqHandler, nil
 }
@@ -128,8 +140,17 @@ func (h *logStreamEndpointHandler) newTailer(
 	}
 
 	tailerArgs := corelogger.LogTailerParams{
-		StartTime:    start,
-		InitialLines: cfg.MaxLookbackRecords,
+		StartTime:     start,
+		InitialLines:  cfg.MaxLookbackRecords,
+		IncludeEntity: cfg.IncludeEntity,
+		IncludeModule: cfg.IncludeModule,
+	}
+	if cfg.Level != "" {
+		level, ok := loggo.ParseLevel(cfg.Level)
+		if !ok || level == loggo.UNSPECIFIED {
+			return nil, errors.NotValidf("log stream level %q", cfg.Level)
+		}
+		tailerArgs.MinLevel = level
 	}
 	tailer, err := source.newTailer(tailerArgs)
 	if err != nil {
@@ -190,6 +211,10 @@ type logStreamRequestHandler struct {
 	req        *http.Request
 	tailer     corelogger.LogTailer
 	poolHelper state.PoolHelper
+
+	// structured means records are sent one at a time as
+	// params.StructuredLogMessage, rather than in batches.
+	structured bool
 }
 
 func (h *logStreamRequestHandler) serveWebsocket(stop <-chan struct{}) {
@@ -223,10 +248,33 @@ func (h *logStreamRequestHandler) close() {
 }
 
 func (h *logStreamRequestHandler) sendRecords(rec []*corelogger.LogRecord) error {
+	if h.structured {
+		for _, r := range rec {
+			if err := h.conn.WriteJSON(structuredFromRecord(r)); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return nil
+	}
 	apiRec := h.apiFromRecords(rec)
 	return errors.Trace(h.conn.WriteJSON(apiRec))
 }
 
+func structuredFromRecord(rec *corelogger.LogRecord) params.StructuredLogMessage {
+	return params.StructuredLogMessage{
+		ID:        rec.ID,
+		ModelUUID: rec.ModelUUID,
+		Version:   rec.Version.String(),
+		Entity:    rec.Entity,
+		Timestamp: rec.Time,
+		Severity:  rec.Level.String(),
+		Module:    rec.Module,
+		Location:  rec.Location,
+		Labels:    rec.Labels,
+		Message:   rec.Message,
+	}
+}
+
 func (h *logStreamRequestHandler) apiFromRecords(records []*corelogger.LogRecord) params.LogStreamRecords {
 	var result params.LogStreamRecords
 	result.Records = make([]params.LogStreamRecord, len(records))
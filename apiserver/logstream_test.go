@@ -97,6 +97,129 @@ func (s *LogStreamIntSuite) TestParamStartTruncate(c *gc.C) {
 	})
 }
 
+func (s *LogStreamIntSuite) TestParamFilters(c *gc.C) {
+	cfg := params.LogStreamConfig{
+		Sink:          "spam",
+		IncludeEntity: []string{"machine-0", "unit-mysql-*"},
+		IncludeModule: []string{"juju.worker"},
+		Level:         "ERROR",
+	}
+	req := s.newReq(c, cfg)
+
+	stub := &testing.Stub{}
+	source := &stubSource{stub: stub}
+	source.ReturnGetStart = 10
+	handler := logStreamEndpointHandler{
+		stopCh:    nil,
+		newSource: source.newSource,
+	}
+
+	_, err := handler.newLogStreamRequestHandler(nil, req, clock.WallClock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stub.CheckCallNames(c, "newSource", "getStart", "newTailer")
+	stub.CheckCall(c, 2, "newTailer", corelogger.LogTailerParams{
+		StartTime:     time.Unix(10, 0),
+		IncludeEntity: []string{"machine-0", "unit-mysql-*"},
+		IncludeModule: []string{"juju.worker"},
+		MinLevel:      loggo.ERROR,
+	})
+}
+
+func (s *LogStreamIntSuite) TestInvalidLevel(c *gc.C) {
+	req := s.newReq(c, params.LogStreamConfig{
+		Sink:  "spam",
+		Level: "LOUD",
+	})
+
+	stub := &testing.Stub{}
+	source := &stubSource{stub: stub}
+	handler := logStreamEndpointHandler{
+		stopCh:    nil,
+		newSource: source.newSource,
+	}
+
+	_, err := handler.newLogStreamRequestHandler(nil, req, clock.WallClock)
+	c.Assert(err, gc.ErrorMatches, `creating new tailer: log stream level "LOUD" not valid`)
+}
+
+func (s *LogStreamIntSuite) TestInvalidFormat(c *gc.C) {
+	req := s.newReq(c, params.LogStreamConfig{
+		Sink:   "spam",
+		Format: "yaml",
+	})
+
+	stub := &testing.Stub{}
+	source := &stubSource{stub: stub}
+	handler := logStreamEndpointHandler{
+		stopCh:    nil,
+		newSource: source.newSource,
+	}
+
+	_, err := handler.newLogStreamRequestHandler(nil, req, clock.WallClock)
+	c.Assert(err, gc.ErrorMatches, `log stream format "yaml" not valid`)
+	stub.CheckCallNames(c, "newSource", "close")
+}
+
+func (s *LogStreamIntSuite) TestSendStructuredRecords(c *gc.C) {
+	recs := []*corelogger.LogRecord{{
+		ID:        10,
+		ModelUUID: "deadbeef-...",
+		Version:   version.Current,
+		Time:      time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:    "machine-99",
+		Module:    "some.where",
+		Location:  "code.go:42",
+		Level:     loggo.INFO,
+		Message:   "stuff happened",
+		Labels:    []string{"http"},
+	}, {
+		ID:        20,
+		ModelUUID: "deadbeef-...",
+		Version:   version.Current,
+		Time:      time.Date(2015, 6, 19, 15, 36, 40, 0, time.UTC),
+		Entity:    "unit-foo-2",
+		Module:    "else.where",
+		Location:  "go.go:22",
+		Level:     loggo.ERROR,
+		Message:   "whoops",
+	}}
+
+	conn := &recordingWriter{}
+	handler := &logStreamRequestHandler{
+		conn:       conn,
+		structured: true,
+	}
+	err := handler.sendRecords(recs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(conn.written, jc.DeepEquals, []interface{}{
+		params.StructuredLogMessage{
+			ID:        10,
+			ModelUUID: "deadbeef-...",
+			Version:   version.Current.String(),
+			Entity:    "machine-99",
+			Timestamp: time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+			Severity:  "INFO",
+			Module:    "some.where",
+			Location:  "code.go:42",
+			Labels:    []string{"http"},
+			Message:   "stuff happened",
+		},
+		params.StructuredLogMessage{
+			ID:        20,
+			ModelUUID: "deadbeef-...",
+			Version:   version.Current.String(),
+			Entity:    "unit-foo-2",
+			Timestamp: time.Date(2015, 6, 19, 15, 36, 40, 0, time.UTC),
+			Severity:  "ERROR",
+			Module:    "else.where",
+			Location:  "go.go:22",
+			Message:   "whoops",
+		},
+	})
+}
+
 func (s *LogStreamIntSuite) TestFullRequest(c *gc.C) {
 
 	// Create test data: i.e. log records for tailing...
@@ -286,6 +409,15 @@ func (s *stubSource) newTailer(args corelogger.LogTailerParams) (corelogger.LogT
 	return s.ReturnNewTailer, nil
 }
 
+type recordingWriter struct {
+	written []interface{}
+}
+
+func (w *recordingWriter) WriteJSON(v interface{}) error {
+	w.written = append(w.written, v)
+	return nil
+}
+
 type stubLogTailer struct {
 	corelogger.LogTailer
 	stub *testing.Stub
@@ -0,0 +1,264 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/rpcreflect"
+
+	"github.com/juju/juju/apiserver/authentication"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/httpcontext"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// checkRoleCapabilities restricts a user logged in to a model to the
+// API methods allowed by the roles they have on the model.
+func (r *apiHandler) checkRoleCapabilities(facadeName, methodName string) error {
+	return r.checkModelRoleCapabilities(r.model.ModelTag(), facadeName, methodName)
+}
+
+// checkModelRoleCapabilities restricts a user to the API methods allowed
+// by the roles they have on the given model. Users without any role on
+// the model, and controller superusers, are left for the facades to
+// check as usual.
+func (r *apiHandler) checkModelRoleCapabilities(modelTag names.ModelTag, facadeName, methodName string) error {
+	if alwaysAllowedForRoles(facadeName) {
+		return nil
+	}
+	user, ok := r.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil
+	}
+	err := r.HasPermission(permission.SuperuserAccess, r.state.ControllerTag())
+	if err == nil {
+		return nil
+	}
+
+	st := r.state
+	if modelTag.Id() != st.ModelUUID() {
+		pooled, err := r.shared.statePool.Get(modelTag.Id())
+		if errors.Is(err, errors.NotFound) {
+			// The facade reports the missing model.
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		defer pooled.Release()
+		st = pooled.State
+	}
+	return checkUserRoles(st, user, facadeName, methodName)
+}
+
+// checkUserRoles returns an error unless the roles the user has on the
+// state's model allow calling the method. Users without any role on
+// the model are not restricted.
+func checkUserRoles(st *state.State, user names.UserTag, facadeName, methodName string) error {
+	roles, err := st.ModelUserRoles(user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+	for _, role := range roles {
+		if role.Allows(facadeName, methodName) {
+			return nil
+		}
+	}
+	return errors.Annotatef(apiservererrors.ErrPerm, "%s.%s not allowed by role", facadeName, methodName)
+}
+
+// roleCapability names the facade and method that the roles of a user
+// must allow for them to make a request to an HTTP endpoint. The
+// endpoints aren't served by facades, so they are given pseudo facade
+// and method names which roles grant like any others, as in
+// "Charms.Upload" or "Resources.*".
+type roleCapability struct {
+	facadeName string
+	methodName string
+}
+
+var (
+	charmsDownloadCapability    = roleCapability{"Charms", "Download"}
+	charmsUploadCapability      = roleCapability{"Charms", "Upload"}
+	resourcesDownloadCapability = roleCapability{"Resources", "Download"}
+	resourcesUploadCapability   = roleCapability{"Resources", "Upload"}
+	toolsUploadCapability       = roleCapability{"Tools", "Upload"}
+	backupsDownloadCapability   = roleCapability{"Backups", "Download"}
+	backupsUploadCapability     = roleCapability{"Backups", "Upload"}
+	debugLogCapability          = roleCapability{"DebugLog", "Read"}
+)
+
+// checkRoleCapability restricts a user making an HTTP request to the
+// capabilities allowed by the roles they have on the request's model.
+// As with API calls, other entities, users without any role on the
+// model and controller superusers are left for the handlers to check
+// as usual.
+func (ctxt *httpContext) checkRoleCapability(req *http.Request, authInfo authentication.AuthInfo, capability roleCapability) error {
+	user, ok := authInfo.Entity.Tag().(names.UserTag)
+	if !ok {
+		return nil
+	}
+	st, err := ctxt.stateForRequestUnauthenticated(req)
+	if errors.Is(err, errors.NotFound) {
+		// The handler reports the missing model.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+
+	superuser := controllerAdminAuthorizer{controllerTag: st.ControllerTag()}
+	if err := superuser.Authorize(authInfo); err == nil {
+		return nil
+	}
+	return checkUserRoles(st.State, user, capability.facadeName, capability.methodName)
+}
+
+// roleCapabilityHandler checks the roles of the authenticated user
+// against the capability of the request's HTTP method before passing
+// the request on. Requests with methods that have no capability are
+// passed on unchecked.
+type roleCapabilityHandler struct {
+	ctxt         httpContext
+	next         http.Handler
+	capabilities map[string]roleCapability
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *roleCapabilityHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	capability, ok := h.capabilities[req.Method]
+	if !ok {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+	authInfo, ok := httpcontext.RequestAuthInfo(req)
+	if !ok {
+		http.Error(w, "authorization failed: no authenticated entity", http.StatusForbidden)
+		return
+	}
+	if err := h.ctxt.checkRoleCapability(req, authInfo, capability); err != nil {
+		http.Error(w,
+			fmt.Sprintf("authorization failed: %s", err),
+			http.StatusForbidden,
+		)
+		return
+	}
+	h.next.ServeHTTP(w, req)
+}
+
+// alwaysAllowedForRoles returns whether the facade may be used whatever
+// the roles of the user: the pinger keeps the connection alive, and the
+// watcher facades only serve watchers created by other, checked, calls.
+func alwaysAllowedForRoles(facadeName string) bool {
+	return facadeName == "Pinger" || strings.HasSuffix(facadeName, "Watcher")
+}
+
+// restrictModelRoles wraps the root so that each call is checked
+// against the roles the user has on every model whose tag is in the
+// call's arguments. Calls to controller facades, such as those that
+// destroy models or change access to them, name the models they act
+// on in their arguments rather than in the login.
+func restrictModelRoles(root rpc.Root, check func(names.ModelTag, string, string) error) rpc.Root {
+	return &modelRolesRoot{
+		Root:  root,
+		check: check,
+	}
+}
+
+type modelRolesRoot struct {
+	rpc.Root
+	check func(modelTag names.ModelTag, facadeName, methodName string) error
+}
+
+// FindMethod implements rpc.Root.
+func (r *modelRolesRoot) FindMethod(facadeName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.Root.FindMethod(facadeName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	return &modelRolesCaller{
+		MethodCaller: caller,
+		facadeName:   facadeName,
+		methodName:   methodName,
+		check:        r.check,
+	}, nil
+}
+
+type modelRolesCaller struct {
+	rpcreflect.MethodCaller
+	facadeName string
+	methodName string
+	check      func(modelTag names.ModelTag, facadeName, methodName string) error
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *modelRolesCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
+	for _, modelTag := range argModelTags(arg) {
+		if err := c.check(modelTag, c.facadeName, c.methodName); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return c.MethodCaller.Call(ctx, objId, arg)
+}
+
+// argModelTags returns the distinct model tags found in the strings of
+// the API call arguments.
+func argModelTags(arg reflect.Value) []names.ModelTag {
+	var (
+		tags []names.ModelTag
+		seen = make(map[string]bool)
+	)
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if !v.IsNil() {
+				walk(v.Elem())
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).IsExported() {
+					walk(v.Field(i))
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				return
+			}
+			for i := 0; i < v.Len(); i++ {
+				walk(v.Index(i))
+			}
+		case reflect.Map:
+			iter := v.MapRange()
+			for iter.Next() {
+				walk(iter.Key())
+				walk(iter.Value())
+			}
+		case reflect.String:
+			s := v.String()
+			if !strings.HasPrefix(s, names.ModelTagKind+"-") || seen[s] {
+				return
+			}
+			if tag, err := names.ParseModelTag(s); err == nil {
+				seen[s] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if arg.IsValid() {
+		walk(arg)
+	}
+	return tags
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/rpcreflect"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	apitesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type restrictModelRolesSuite struct {
+	testing.BaseSuite
+	checked []string
+}
+
+var _ = gc.Suite(&restrictModelRolesSuite{})
+
+func (s *restrictModelRolesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.checked = nil
+}
+
+func (s *restrictModelRolesSuite) check(modelTag names.ModelTag, facadeName, methodName string) error {
+	s.checked = append(s.checked, modelTag.Id()+" "+facadeName+"."+methodName)
+	if modelTag.Id() == "deadbeef-0bad-400d-8000-4b1d0d06f00d" {
+		return errors.New("not allowed by role")
+	}
+	return nil
+}
+
+func (s *restrictModelRolesSuite) TestChecksModelsInArgs(c *gc.C) {
+	root := apiserver.RestrictModelRoles(&fakeRoot{}, s.check)
+	caller, err := root.FindMethod("ModelManager", 9, "ModifyModelAccess")
+	c.Assert(err, jc.ErrorIsNil)
+
+	arg := params.ModifyModelAccessRequest{Changes: []params.ModifyModelAccess{{
+		UserTag:  "user-bob",
+		Action:   params.GrantModelAccess,
+		Access:   params.ModelAdminAccess,
+		ModelTag: "model-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	}, {
+		UserTag:  "user-mary",
+		Action:   params.GrantModelAccess,
+		Access:   params.ModelReadAccess,
+		ModelTag: "model-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	}}}
+	result, err := caller.Call(context.Background(), "", reflect.ValueOf(arg))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Interface(), gc.Equals, "called")
+	c.Assert(s.checked, jc.DeepEquals, []string{
+		"f47ac10b-58cc-4372-a567-0e02b2c3d479 ModelManager.ModifyModelAccess",
+	})
+}
+
+func (s *restrictModelRolesSuite) TestDisallowedModel(c *gc.C) {
+	root := apiserver.RestrictModelRoles(&fakeRoot{}, s.check)
+	caller, err := root.FindMethod("ModelManager", 9, "DestroyModels")
+	c.Assert(err, jc.ErrorIsNil)
+
+	arg := params.DestroyModelsParams{Models: []params.DestroyModelParams{{
+		ModelTag: "model-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	}, {
+		ModelTag: "model-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+	}}}
+	_, err = caller.Call(context.Background(), "", reflect.ValueOf(arg))
+	c.Assert(err, gc.ErrorMatches, "not allowed by role")
+	c.Assert(s.checked, gc.HasLen, 2)
+}
+
+func (s *restrictModelRolesSuite) TestNoModelsInArgs(c *gc.C) {
+	root := apiserver.RestrictModelRoles(&fakeRoot{}, s.check)
+	caller, err := root.FindMethod("Client", 6, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+
+	arg := params.StatusParams{Patterns: []string{"model-foo", "mysql"}}
+	_, err = caller.Call(context.Background(), "", reflect.ValueOf(arg))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.checked, gc.HasLen, 0)
+}
+
+type fakeRoot struct{}
+
+func (*fakeRoot) FindMethod(string, int, string) (rpcreflect.MethodCaller, error) {
+	return fakeCaller{}, nil
+}
+
+func (*fakeRoot) Kill() {}
+
+type fakeCaller struct{}
+
+func (fakeCaller) ParamsType() reflect.Type { return nil }
+
+func (fakeCaller) ResultType() reflect.Type { return nil }
+
+func (fakeCaller) Call(context.Context, string, reflect.Value) (reflect.Value, error) {
+	return reflect.ValueOf("called"), nil
+}
+
+// makeRoleUser returns a user with write access to the model who has
+// been granted a role with the given capabilities, and their password.
+func (s *apiserverBaseSuite) makeRoleUser(c *gc.C, capabilities ...permission.Capability) (names.UserTag, string) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "oryx",
+		Password: "gardener",
+		Access:   permission.WriteAccess,
+	})
+	_, err := s.State.AddRole(permission.Role{
+		Name:         "restricted",
+		Access:       permission.WriteAccess,
+		Capabilities: capabilities,
+	}, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelUserRole(user.UserTag(), "restricted")
+	c.Assert(err, jc.ErrorIsNil)
+	return user.UserTag(), "gardener"
+}
+
+type httpRoleCapabilitiesSuite struct {
+	apiserverBaseSuite
+}
+
+var _ = gc.Suite(&httpRoleCapabilitiesSuite{})
+
+func (s *httpRoleCapabilitiesSuite) sendRequest(c *gc.C, user names.UserTag, password, method, path string) *http.Response {
+	return apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Tag:      user.String(),
+		Password: password,
+		Method:   method,
+		URL:      s.URL(fmt.Sprintf("/model/%s%s", s.State.ModelUUID(), path), nil).String(),
+	})
+}
+
+func (s *httpRoleCapabilitiesSuite) assertNotAllowed(c *gc.C, resp *http.Response, capability string) {
+	body := apitesting.AssertResponse(c, resp, http.StatusForbidden, "text/plain; charset=utf-8")
+	c.Assert(string(body), gc.Equals, "authorization failed: "+capability+" not allowed by role: permission denied\n")
+}
+
+func (s *httpRoleCapabilitiesSuite) TestResourcesRequireRoleCapability(c *gc.C) {
+	user, password := s.makeRoleUser(c, "Application.*")
+	resp := s.sendRequest(c, user, password, "GET", "/applications/mysql/resources/data")
+	s.assertNotAllowed(c, resp, "Resources.Download")
+	resp = s.sendRequest(c, user, password, "PUT", "/applications/mysql/resources/data")
+	s.assertNotAllowed(c, resp, "Resources.Upload")
+}
+
+func (s *httpRoleCapabilitiesSuite) TestResourcesAllowedByRoleCapability(c *gc.C) {
+	user, password := s.makeRoleUser(c, "Resources.Download")
+	resp := s.sendRequest(c, user, password, "GET", "/applications/mysql/resources/data")
+	c.Assert(resp.StatusCode, gc.Not(gc.Equals), http.StatusForbidden)
+	resp = s.sendRequest(c, user, password, "PUT", "/applications/mysql/resources/data")
+	s.assertNotAllowed(c, resp, "Resources.Upload")
+}
+
+func (s *httpRoleCapabilitiesSuite) TestToolsUploadRequiresRoleCapability(c *gc.C) {
+	user, password := s.makeRoleUser(c, "Application.*")
+	resp := s.sendRequest(c, user, password, "POST", "/tools")
+	s.assertNotAllowed(c, resp, "Tools.Upload")
+}
+
+func (s *httpRoleCapabilitiesSuite) TestSuperuserNotRestricted(c *gc.C) {
+	_, err := s.State.AddRole(permission.Role{
+		Name:         "restricted",
+		Access:       permission.AdminAccess,
+		Capabilities: []permission.Capability{"Application.*"},
+	}, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelUserRole(s.Owner, "restricted")
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.sendRequest(c, s.Owner, ownerPassword, "PUT", "/applications/mysql/resources/data")
+	c.Assert(resp.StatusCode, gc.Not(gc.Equals), http.StatusForbidden)
+}
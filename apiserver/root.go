@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/multiwatcher"
 	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
@@ -168,7 +169,7 @@ func (r *apiHandler) Kill() {
 // and place a call on its method.
 type srvCaller struct {
 	objMethod rpcreflect.ObjMethod
-	creator   func(id string) (reflect.Value, error)
+	creator   func(ctx context.Context, id string) (reflect.Value, error)
 }
 
 // ParamsType defines the parameters that should be supplied to this function.
@@ -186,7 +187,7 @@ func (s *srvCaller) ResultType() reflect.Type {
 // Call takes the object Id and an instance of ParamsType to create an object and place
 // a call on its method. It then returns an instance of ResultType.
 func (s *srvCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
-	objVal, err := s.creator(objId)
+	objVal, err := s.creator(ctx, objId)
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -345,8 +346,17 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 		return nil, err
 	}
 
-	creator := func(id string) (reflect.Value, error) {
+	creator := func(ctx context.Context, id string) (reflect.Value, error) {
 		objKey := objectKey{name: rootName, version: version, objId: id}
+
+		// A request which is being traced gets a facade of its own,
+		// whose State records the request's transactions under the
+		// request's span. Facades are shared by the connection's
+		// requests otherwise.
+		if trace.SpanFromContext(ctx).Scope().Sampled {
+			return r.newFacade(goType, r.requestFacadeContext(ctx, objKey))
+		}
+
 		r.objectMutex.RLock()
 		objValue, ok := r.objectCache[objKey]
 		r.objectMutex.RUnlock()
@@ -360,33 +370,10 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 		}
 		// Now that we have the write lock, check one more time in case
 		// someone got the write lock before us.
-		factory, err := r.facades.GetFactory(rootName, version)
-		if err != nil {
-			// We don't check for IsNotFound here, because it
-			// should have already been handled in the GetType
-			// check.
-			return reflect.Value{}, err
-		}
-		obj, err := factory(r.facadeContext(objKey))
+		objValue, err := r.newFacade(goType, r.facadeContext(objKey))
 		if err != nil {
 			return reflect.Value{}, err
 		}
-		objValue = reflect.ValueOf(obj)
-		if !objValue.Type().AssignableTo(goType) {
-			return reflect.Value{}, errors.Errorf(
-				"internal error, %s(%d) claimed to return %s but returned %T",
-				rootName, version, goType, obj)
-		}
-		if goType.Kind() == reflect.Interface {
-			// If the original function wanted to return an
-			// interface type, the indirection in the factory via
-			// an interface{} strips the original interface
-			// information off. So here we have to create the
-			// interface again, and assign it.
-			asInterface := reflect.New(goType).Elem()
-			asInterface.Set(objValue)
-			objValue = asInterface
-		}
 		r.objectCache[objKey] = objValue
 		return objValue, nil
 	}
@@ -396,6 +383,39 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 	}, nil
 }
 
+// newFacade returns a new instance of the facade identified by the
+// context's key, which must be assignable to goType.
+func (r *apiRoot) newFacade(goType reflect.Type, ctx *facadeContext) (reflect.Value, error) {
+	factory, err := r.facades.GetFactory(ctx.key.name, ctx.key.version)
+	if err != nil {
+		// We don't check for IsNotFound here, because it
+		// should have already been handled in the GetType
+		// check.
+		return reflect.Value{}, err
+	}
+	obj, err := factory(ctx)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	objValue := reflect.ValueOf(obj)
+	if !objValue.Type().AssignableTo(goType) {
+		return reflect.Value{}, errors.Errorf(
+			"internal error, %s(%d) claimed to return %s but returned %T",
+			ctx.key.name, ctx.key.version, goType, obj)
+	}
+	if goType.Kind() == reflect.Interface {
+		// If the original function wanted to return an
+		// interface type, the indirection in the factory via
+		// an interface{} strips the original interface
+		// information off. So here we have to create the
+		// interface again, and assign it.
+		asInterface := reflect.New(goType).Elem()
+		asInterface.Set(objValue)
+		objValue = asInterface
+	}
+	return objValue, nil
+}
+
 func (r *apiRoot) lookupMethod(rootName string, version int, methodName string) (reflect.Type, rpcreflect.ObjMethod, error) {
 	goType, err := r.facades.GetType(rootName, version)
 	if err != nil {
@@ -451,10 +471,24 @@ func (r *apiRoot) facadeContext(key objectKey) *facadeContext {
 	}
 }
 
+// requestFacadeContext returns the context for a facade which serves
+// a single request.
+func (r *apiRoot) requestFacadeContext(ctx context.Context, key objectKey) *facadeContext {
+	return &facadeContext{
+		r:          r,
+		key:        key,
+		requestCtx: ctx,
+	}
+}
+
 // facadeContext implements facade.Context
 type facadeContext struct {
 	r   *apiRoot
 	key objectKey
+
+	// requestCtx is the context of the request the facade serves, if
+	// the facade serves a single request.
+	requestCtx context.Context
 }
 
 // Cancel is part of the facade.Context interface.
@@ -504,6 +538,9 @@ func (ctx *facadeContext) CachedModel(uuid string) (*cache.Model, error) {
 
 // State is part of the facade.Context interface.
 func (ctx *facadeContext) State() *state.State {
+	if ctx.requestCtx != nil {
+		return ctx.r.state.WithContext(ctx.requestCtx)
+	}
 	return ctx.r.state
 }
 
@@ -275,6 +275,89 @@ func (s *serverSuite) TestAPIHandlerHasPermissionSuperUser(c *gc.C) {
 	apiserver.AssertHasPermission(c, handler, permission.SuperuserAccess, ctag, true)
 }
 
+func (s *serverSuite) TestAPIHandlerRoleCapabilities(c *gc.C) {
+	u, _ := s.bootstrapHasPermissionTest(c)
+	user := u.UserTag()
+	_, err := s.Model.AddUser(state.UserAccessSpec{
+		User:      user,
+		CreatedBy: s.AdminUserTag(c),
+		Access:    permission.WriteAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	handler, _ := apiserver.TestingAPIHandlerWithEntity(c, s.StatePool, s.State, u)
+	defer handler.Kill()
+
+	// The built-in role of the user's access allows everything.
+	err = apiserver.CheckRoleCapabilities(handler, "Application", "Deploy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddRole(permission.Role{
+		Name:         "operator",
+		Access:       permission.WriteAccess,
+		Capabilities: []permission.Capability{"Action.*", "Client.FullStatus"},
+	}, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelUserRole(user, "operator")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = apiserver.CheckRoleCapabilities(handler, "Action", "EnqueueOperation")
+	c.Assert(err, jc.ErrorIsNil)
+	err = apiserver.CheckRoleCapabilities(handler, "Client", "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	err = apiserver.CheckRoleCapabilities(handler, "Pinger", "Ping")
+	c.Assert(err, jc.ErrorIsNil)
+	err = apiserver.CheckRoleCapabilities(handler, "Application", "Deploy")
+	c.Assert(err, gc.ErrorMatches, `Application.Deploy not allowed by role: permission denied`)
+	c.Assert(jujuerrors.Is(err, errors.ErrPerm), jc.IsTrue)
+}
+
+func (s *serverSuite) TestAPIHandlerModelRoleCapabilities(c *gc.C) {
+	u, _ := s.bootstrapHasPermissionTest(c)
+	user := u.UserTag()
+	otherSt := s.Factory.MakeModel(c, nil)
+	defer otherSt.Close()
+	otherModel, err := otherSt.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = otherModel.AddUser(state.UserAccessSpec{
+		User:      user,
+		CreatedBy: s.AdminUserTag(c),
+		Access:    permission.AdminAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRole(permission.Role{
+		Name:         "operator",
+		Access:       permission.AdminAccess,
+		Capabilities: []permission.Capability{"Action.*"},
+	}, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = otherSt.SetModelUserRole(user, "operator")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Access granted to a group doesn't lift the restrictions of the
+	// user's role.
+	group, err := s.State.AddUserGroup("admins", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(user)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("admins", otherModel.ModelTag(), permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The handler is logged in to the controller, but the calls are
+	// checked against the roles on the model they name.
+	handler, _ := apiserver.TestingAPIHandlerWithEntity(c, s.StatePool, s.State, u)
+	defer handler.Kill()
+
+	err = apiserver.CheckModelRoleCapabilities(handler, otherModel.ModelTag(), "Action", "EnqueueOperation")
+	c.Assert(err, jc.ErrorIsNil)
+	err = apiserver.CheckModelRoleCapabilities(handler, otherModel.ModelTag(), "ModelManager", "ModifyModelAccess")
+	c.Assert(err, gc.ErrorMatches, `ModelManager.ModifyModelAccess not allowed by role: permission denied`)
+	err = apiserver.CheckModelRoleCapabilities(handler, otherModel.ModelTag(), "UserManager", "GrantRoles")
+	c.Assert(err, gc.ErrorMatches, `UserManager.GrantRoles not allowed by role: permission denied`)
+	err = apiserver.CheckModelRoleCapabilities(handler, s.Model.ModelTag(), "ModelManager", "ModifyModelAccess")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *serverSuite) TestAPIHandlerHasPermissionLoginToken(c *gc.C) {
 	user := names.NewUserTag("fred")
 	token, err := apitesting.NewJWT(apitesting.JWTParams{
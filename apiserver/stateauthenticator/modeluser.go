@@ -58,7 +58,15 @@ func (f modelUserEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
 			}
 		}
 		if permission.IsEmptyUserAccess(controllerAccess) {
-			return nil, errors.NotFoundf("model or controller user")
+			// The user may only have been granted access through the
+			// groups they are a member of.
+			hasGroupAccess, err := f.hasGroupAccess(utag, model.ModelTag())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !hasGroupAccess {
+				return nil, errors.NotFoundf("model or controller user")
+			}
 		}
 	}
 
@@ -77,6 +85,23 @@ func (f modelUserEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
 	return u, nil
 }
 
+// hasGroupAccess reports whether the user has been granted access to
+// the model or the controller through a group they are a member of.
+func (f modelUserEntityFinder) hasGroupAccess(user names.UserTag, modelTag names.ModelTag) (bool, error) {
+	for _, target := range []names.Tag{modelTag, f.st.ControllerTag()} {
+		access, err := f.st.UserPermission(user, target)
+		if errors.Is(err, errors.NotFound) {
+			continue
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		if access != permission.NoAccess {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // modelUserEntity encapsulates a model user
 // and, if the user is local, the local state user
 // as well. This enables us to implement FindEntity
@@ -0,0 +1,210 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+const (
+	// FakeOIDCDeviceCode and FakeOIDCUserCode are the codes returned by
+	// the device authorization endpoint of a FakeOIDCIssuer.
+	FakeOIDCDeviceCode = "fake-device-code"
+	FakeOIDCUserCode   = "ABCD-EFGH"
+
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// FakeOIDCIssuer is an OpenID Connect provider, served over TLS, which
+// issues ID tokens signed with a test key. It supports discovery, the
+// device authorization grant and the refresh token grant.
+type FakeOIDCIssuer struct {
+	// ClientID is the client that tokens are issued to.
+	ClientID string
+
+	server     *httptest.Server
+	keySet     jwk.Set
+	signingKey jwk.Key
+
+	mu            sync.Mutex
+	deviceClaims  map[string]interface{}
+	pendingPolls  int
+	polls         int
+	refreshTokens map[string]map[string]interface{}
+}
+
+// NewFakeOIDCIssuer starts a new FakeOIDCIssuer issuing tokens to the
+// given client. The issuer should be closed when it is no longer needed.
+func NewFakeOIDCIssuer(clientID string) (*FakeOIDCIssuer, error) {
+	keySet, signingKey, err := NewJWKSet()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pubKey, _ := keySet.Key(0)
+	if err := signingKey.Set(jwk.AlgorithmKey, jwa.RS256); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := signingKey.Set(jwk.KeyIDKey, pubKey.KeyID()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	issuer := &FakeOIDCIssuer{
+		ClientID:      clientID,
+		keySet:        keySet,
+		signingKey:    signingKey,
+		refreshTokens: make(map[string]map[string]interface{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.serveDiscovery)
+	mux.HandleFunc("/jwks", issuer.serveKeys)
+	mux.HandleFunc("/device", issuer.serveDeviceAuthorization)
+	mux.HandleFunc("/token", issuer.serveToken)
+	issuer.server = httptest.NewTLSServer(mux)
+	return issuer, nil
+}
+
+// URL returns the issuer URL.
+func (i *FakeOIDCIssuer) URL() string {
+	return i.server.URL
+}
+
+// Client returns an HTTP client that trusts the issuer's certificate.
+func (i *FakeOIDCIssuer) Client() *http.Client {
+	return i.server.Client()
+}
+
+// Close shuts down the issuer.
+func (i *FakeOIDCIssuer) Close() {
+	i.server.Close()
+}
+
+// IDToken returns an ID token, valid for an hour, holding the given
+// claims. The issuer, audience and time claims are set unless given.
+func (i *FakeOIDCIssuer) IDToken(claims map[string]interface{}) (string, error) {
+	now := time.Now()
+	builder := jwt.NewBuilder().
+		Issuer(i.URL()).
+		Audience([]string{i.ClientID}).
+		IssuedAt(now).
+		Expiration(now.Add(time.Hour))
+	for k, v := range claims {
+		builder = builder.Claim(k, v)
+	}
+	token, err := builder.Build()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, i.signingKey))
+	return string(signed), errors.Trace(err)
+}
+
+// ApproveDevice approves the device authorization, so that ID tokens
+// holding the given claims are issued to the device. Polls for the
+// device's tokens are answered with "authorization_pending" pendingPolls
+// times first.
+func (i *FakeOIDCIssuer) ApproveDevice(claims map[string]interface{}, pendingPolls int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.deviceClaims = claims
+	i.pendingPolls = pendingPolls
+}
+
+// DevicePolls returns the number of times the device has polled for
+// its tokens.
+func (i *FakeOIDCIssuer) DevicePolls() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.polls
+}
+
+func (i *FakeOIDCIssuer) serveDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                        i.URL(),
+		"jwks_uri":                      i.URL() + "/jwks",
+		"token_endpoint":                i.URL() + "/token",
+		"device_authorization_endpoint": i.URL() + "/device",
+	})
+}
+
+func (i *FakeOIDCIssuer) serveKeys(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, i.keySet)
+}
+
+func (i *FakeOIDCIssuer) serveDeviceAuthorization(w http.ResponseWriter, req *http.Request) {
+	if req.PostFormValue("client_id") != i.ClientID {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":               FakeOIDCDeviceCode,
+		"user_code":                 FakeOIDCUserCode,
+		"verification_uri":          i.URL() + "/activate",
+		"verification_uri_complete": i.URL() + "/activate?user_code=" + FakeOIDCUserCode,
+		"expires_in":                600,
+		"interval":                  1,
+	})
+}
+
+func (i *FakeOIDCIssuer) serveToken(w http.ResponseWriter, req *http.Request) {
+	if req.PostFormValue("client_id") != i.ClientID {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var claims map[string]interface{}
+	switch req.PostFormValue("grant_type") {
+	case deviceCodeGrantType:
+		if req.PostFormValue("device_code") != FakeOIDCDeviceCode {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		i.polls++
+		if i.deviceClaims == nil || i.polls <= i.pendingPolls {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+			return
+		}
+		claims = i.deviceClaims
+	case "refresh_token":
+		var ok bool
+		if claims, ok = i.refreshTokens[req.PostFormValue("refresh_token")]; !ok {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	idToken, err := i.IDToken(claims)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	refreshToken := fmt.Sprintf("refresh-%d", len(i.refreshTokens))
+	i.refreshTokens[refreshToken] = claims
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  "access-token",
+		"token_type":    "Bearer",
+		"id_token":      idToken,
+		"refresh_token": refreshToken,
+		"expires_in":    3600,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -78,6 +78,19 @@ func (s *storageSuite) TestScope(c *gc.C) {
 	c.Assert(p.Scope(), gc.Equals, storage.ScopeEnviron)
 }
 
+func (s *storageSuite) TestVolumeSourceNotSnapshotter(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	// Unit volumes are created by the application's statefulset, which
+	// cannot create them from snapshots, so none are taken.
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := vs.(storage.VolumeSnapshotter)
+	c.Assert(ok, jc.IsFalse)
+}
+
 func (s *storageSuite) TestDestroyVolumes(c *gc.C) {
 	ctrl := s.setupController(c)
 	defer ctrl.Finish()
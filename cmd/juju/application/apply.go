@@ -0,0 +1,411 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/client/application"
+	"github.com/juju/juju/api/client/applicationoffers"
+	"github.com/juju/juju/api/client/machinemanager"
+	"github.com/juju/juju/api/client/modelconfig"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/arch"
+	corebase "github.com/juju/juju/core/base"
+	bundlechanges "github.com/juju/juju/core/bundle/changes"
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/rpc/params"
+)
+
+const applyDoc = `
+Reconciles the model with the desired state described by a bundle.
+
+The bundle can be a local bundle file or the name of a bundle in Charmhub,
+combined with overlays in the same way as the deploy command.
+
+Applications, machines, relations, offers, options and units that are in the
+bundle but not in the model are deployed in the same way as the deploy
+command, reusing the existing machines in the model.
+
+Anything in the model which isn't described by the bundle is only removed
+when the --prune option is used. Pruning:
+  - removes applications, relations and offers missing from the bundle
+  - resets application options set in the model but not in the bundle
+  - removes surplus units, the most recently added first, or scales down
+    kubernetes applications
+  - removes machines missing from the bundle once none of their units
+    remain
+
+Without --prune the removals are shown but not applied. Use --dry-run to
+show the full plan, additions and removals, without changing the model.
+
+Confirmation of the removals is requested if the model "mode" is
+"requires-prompts", unless --no-prompt is used.
+`
+
+const applyExamples = `
+    juju apply bundle.yaml --dry-run
+    juju apply bundle.yaml
+    juju apply bundle.yaml --overlay prod.yaml --prune
+    juju apply -m othermodel bundle.yaml --map-machines 3=4 --prune --no-prompt
+`
+
+// PruneAPI defines the API methods used to remove everything from the
+// model that isn't described by a bundle.
+type PruneAPI interface {
+	ModelGet() (map[string]interface{}, error)
+	DestroyRelation(force *bool, maxWait *time.Duration, endpoints ...string) error
+	DestroyOffers(force bool, offerURLs ...string) error
+	UnsetApplicationConfig(branchName, application string, options []string) error
+	DestroyUnits(application.DestroyUnitsParams) ([]params.DestroyUnitResult, error)
+	ScaleApplication(application.ScaleApplicationParams) (params.ScaleApplicationResult, error)
+	DestroyApplications(application.DestroyApplicationsParams) ([]params.DestroyApplicationResult, error)
+	DestroyMachinesWithParams(force, keep, dryRun bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error)
+	Close() error
+}
+
+// NewApplyCommand returns a command which reconciles a model with a
+// bundle.
+func NewApplyCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(newApplyCommand())
+}
+
+func newApplyCommand() *applyCommand {
+	c := &applyCommand{
+		DeployCommand: newDeployCommand(),
+		differ: &diffBundleCommand{
+			arches: arch.AllArches(),
+		},
+	}
+	c.differ.charmAdaptorFn = c.differ.charmAdaptor
+	c.differ.newAPIRootFn = func() (base.APICallCloser, error) {
+		return c.NewAPIRoot()
+	}
+	c.differ.modelConfigClientFunc = func(api base.APICallCloser) ModelConfigClient {
+		return modelconfig.NewClient(api)
+	}
+	c.differ.modelConstraintsClientFunc = func() (ModelConstraintsClient, error) {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return modelconfig.NewClient(root), nil
+	}
+	c.newPruneAPIFunc = func() (PruneAPI, error) {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		controllerRoot, err := c.NewControllerAPIRoot()
+		if err != nil {
+			_ = root.Close()
+			return nil, errors.Trace(err)
+		}
+		return &pruneAPIAdapter{
+			applicationClient:    application.NewClient(root),
+			machineManagerClient: machinemanager.NewClient(root),
+			modelConfigClient:    modelconfig.NewClient(root),
+			offersClient:         applicationoffers.NewClient(controllerRoot),
+			roots:                []base.APICallCloser{root, controllerRoot},
+		}, nil
+	}
+	return c
+}
+
+// applyCommand deploys the additions from a bundle to a model and,
+// optionally, removes everything from the model not found in the bundle.
+type applyCommand struct {
+	*DeployCommand
+	modelcmd.RemoveConfirmationCommandBase
+
+	prune bool
+
+	differ          *diffBundleCommand
+	newPruneAPIFunc func() (PruneAPI, error)
+}
+
+// Info implements cmd.Command.
+func (c *applyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "apply",
+		Args:     "<bundle file or name>",
+		Purpose:  "Reconcile a model with a bundle.",
+		Doc:      applyDoc,
+		Examples: applyExamples,
+		SeeAlso: []string{
+			"deploy",
+			"diff-bundle",
+		},
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *applyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.RemoveConfirmationCommandBase.SetFlags(f)
+	f.StringVar(&c.channelStr, "channel", "", "Channel to use when getting the bundle from Charmhub")
+	f.Var(cmd.NewAppendStringsValue(&c.BundleOverlayFile), "overlay", "Bundles to overlay on the primary bundle, applied in order")
+	f.StringVar(&c.machineMap, "map-machines", "", "Indicates how existing machines correspond to bundle machines")
+	f.BoolVar(&c.Trust, "trust", false, "Allows charms to run hooks that require access credentials")
+	f.BoolVar(&c.DryRun, "dry-run", false, "Just show the changes required to reconcile the model")
+	f.BoolVar(&c.prune, "prune", false, "Remove everything from the model that isn't in the bundle")
+
+	c.flagSet = f
+}
+
+// Init implements cmd.Command.
+func (c *applyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle specified")
+	}
+	c.CharmOrBundle = args[0]
+	c.Revision = -1
+
+	// Existing machines are always reused, so that the model can be
+	// reconciled repeatedly.
+	_, mapping, err := parseMachineMap(c.machineMap)
+	if err != nil {
+		return errors.Annotate(err, "error in --map-machines")
+	}
+	c.UseExisting = true
+	c.BundleMachines = mapping
+	if c.channelStr != "" {
+		c.Channel, err = charm.ParseChannelNormalize(c.channelStr)
+		if err != nil {
+			return errors.Annotate(err, "error in --channel")
+		}
+	}
+
+	c.differ.bundle = c.CharmOrBundle
+	c.differ.bundleOverlays = c.BundleOverlayFile
+	c.differ.bundleMachines = c.BundleMachines
+	c.differ.channel = c.Channel
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *applyCommand) Run(ctx *cmd.Context) error {
+	// The removals are computed before anything is deployed, so that a
+	// bundle which can't be read doesn't partially change the model.
+	changes, err := c.pruneChanges(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := c.DeployCommand.Run(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if c.DryRun || !c.prune {
+		_, _ = fmt.Fprintf(ctx.Stdout, "Changes to prune model:\n")
+		for _, change := range changes {
+			_, _ = fmt.Fprintf(ctx.Stdout, "- %s\n", change.Description())
+		}
+		if !c.DryRun {
+			ctx.Infof("Run with --prune to apply the changes above.")
+		}
+		return nil
+	}
+
+	api, err := c.newPruneAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = api.Close() }()
+
+	if c.NeedsConfirmation(api) {
+		_, _ = fmt.Fprintf(ctx.Stderr, "This command will make the following removals:\n")
+		for _, change := range changes {
+			_, _ = fmt.Fprintf(ctx.Stderr, "- %s\n", change.Description())
+		}
+		if err := jujucmd.UserConfirmYes(ctx); err != nil {
+			return errors.Annotate(err, "model prune")
+		}
+	}
+
+	_, _ = fmt.Fprintf(ctx.Stdout, "Pruning model:\n")
+	for _, change := range changes {
+		_, _ = fmt.Fprintf(ctx.Stdout, "- %s\n", change.Description())
+		if err := c.applyPruneChange(api, change); err != nil {
+			return block.ProcessBlockedError(errors.Annotatef(err, "cannot %s", change.Description()), block.BlockRemove)
+		}
+	}
+	ctx.Infof("Prune of model completed.")
+	return nil
+}
+
+// pruneChanges returns the changes required to remove everything from the
+// model which isn't described by the bundle.
+func (c *applyCommand) pruneChanges(ctx *cmd.Context) ([]bundlechanges.PruneChange, error) {
+	apiRoot, err := c.differ.newAPIRootFn()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() { _ = apiRoot.Close() }()
+
+	bundle, model, err := c.differ.readBundleAndModel(ctx, apiRoot, corebase.Base{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	changes, err := bundlechanges.BuildPruneChanges(bundlechanges.DiffConfig{
+		Bundle: bundle,
+		Model:  model,
+		Logger: logger,
+	})
+	return changes, errors.Trace(err)
+}
+
+func (c *applyCommand) applyPruneChange(api PruneAPI, change bundlechanges.PruneChange) error {
+	switch change.Kind {
+	case bundlechanges.PruneRemoveRelation:
+		return api.DestroyRelation(nil, nil, change.Endpoints...)
+
+	case bundlechanges.PruneRemoveOffer:
+		offerURL, err := c.offerURL(change.Offer)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return api.DestroyOffers(false, offerURL)
+
+	case bundlechanges.PruneResetOptions:
+		return api.UnsetApplicationConfig(model.GenerationMaster, change.Application, change.Options)
+
+	case bundlechanges.PruneRemoveUnits:
+		results, err := api.DestroyUnits(application.DestroyUnitsParams{
+			Units: change.Units,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, result := range results {
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return nil
+
+	case bundlechanges.PruneScaleApplication:
+		result, err := api.ScaleApplication(application.ScaleApplicationParams{
+			ApplicationName: change.Application,
+			Scale:           change.Scale,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		return nil
+
+	case bundlechanges.PruneRemoveApplication:
+		results, err := api.DestroyApplications(application.DestroyApplicationsParams{
+			Applications: []string{change.Application},
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, result := range results {
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return nil
+
+	case bundlechanges.PruneRemoveMachine:
+		results, err := api.DestroyMachinesWithParams(false, false, false, nil, change.Machine)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, result := range results {
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return nil
+	}
+	return errors.NotSupportedf("prune change %q", change.Kind)
+}
+
+// offerURL returns the URL of an offer from the current model.
+func (c *applyCommand) offerURL(offer string) (string, error) {
+	modelName, _, err := c.ModelDetails()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var userName string
+	if jujuclient.IsQualifiedModelName(modelName) {
+		baseName, userTag, err := jujuclient.SplitModelName(modelName)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		modelName = baseName
+		userName = userTag.Id()
+	}
+	return crossmodel.MakeURL(userName, modelName, offer, ""), nil
+}
+
+// pruneAPIAdapter combines the clients needed to prune a model.
+type pruneAPIAdapter struct {
+	applicationClient    *application.Client
+	machineManagerClient *machinemanager.Client
+	modelConfigClient    *modelconfig.Client
+	offersClient         *applicationoffers.Client
+	roots                []base.APICallCloser
+}
+
+func (a *pruneAPIAdapter) ModelGet() (map[string]interface{}, error) {
+	return a.modelConfigClient.ModelGet()
+}
+
+func (a *pruneAPIAdapter) DestroyRelation(force *bool, maxWait *time.Duration, endpoints ...string) error {
+	return a.applicationClient.DestroyRelation(force, maxWait, endpoints...)
+}
+
+func (a *pruneAPIAdapter) DestroyOffers(force bool, offerURLs ...string) error {
+	return a.offersClient.DestroyOffers(force, offerURLs...)
+}
+
+func (a *pruneAPIAdapter) UnsetApplicationConfig(branchName, application string, options []string) error {
+	return a.applicationClient.UnsetApplicationConfig(branchName, application, options)
+}
+
+func (a *pruneAPIAdapter) DestroyUnits(in application.DestroyUnitsParams) ([]params.DestroyUnitResult, error) {
+	return a.applicationClient.DestroyUnits(in)
+}
+
+func (a *pruneAPIAdapter) ScaleApplication(in application.ScaleApplicationParams) (params.ScaleApplicationResult, error) {
+	return a.applicationClient.ScaleApplication(in)
+}
+
+func (a *pruneAPIAdapter) DestroyApplications(in application.DestroyApplicationsParams) ([]params.DestroyApplicationResult, error) {
+	return a.applicationClient.DestroyApplications(in)
+}
+
+func (a *pruneAPIAdapter) DestroyMachinesWithParams(force, keep, dryRun bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
+	return a.machineManagerClient.DestroyMachinesWithParams(force, keep, dryRun, maxWait, machines...)
+}
+
+func (a *pruneAPIAdapter) Close() error {
+	var err error
+	for _, root := range a.roots {
+		if closeErr := root.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/client/application"
+	"github.com/juju/juju/cmd/modelcmd"
+	bundlechanges "github.com/juju/juju/core/bundle/changes"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/rpc/params"
+)
+
+type ApplySuite struct {
+	testing.IsolationSuite
+	api *mockPruneAPI
+}
+
+var _ = gc.Suite(&ApplySuite{})
+
+func (s *ApplySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &mockPruneAPI{Stub: &testing.Stub{}}
+}
+
+func (s *ApplySuite) newCommand() *applyCommand {
+	cmd := newApplyCommand()
+	cmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return cmd
+}
+
+func (s *ApplySuite) TestInitNoBundle(c *gc.C) {
+	err := cmdtesting.InitCommand(modelcmd.Wrap(s.newCommand()), nil)
+	c.Assert(err, gc.ErrorMatches, "no bundle specified")
+}
+
+func (s *ApplySuite) TestInitTooManyArgs(c *gc.C) {
+	err := cmdtesting.InitCommand(modelcmd.Wrap(s.newCommand()), []string{"bundle.yaml", "other"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["other"\]`)
+}
+
+func (s *ApplySuite) TestInit(c *gc.C) {
+	cmd := s.newCommand()
+	err := cmdtesting.InitCommand(modelcmd.Wrap(cmd), []string{
+		"bundle.yaml", "--overlay", "overlay.yaml", "--map-machines", "1=3", "--prune",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmd.CharmOrBundle, gc.Equals, "bundle.yaml")
+	c.Assert(cmd.UseExisting, jc.IsTrue)
+	c.Assert(cmd.BundleMachines, jc.DeepEquals, map[string]string{"1": "3"})
+	c.Assert(cmd.prune, jc.IsTrue)
+	c.Assert(cmd.differ.bundle, gc.Equals, "bundle.yaml")
+	c.Assert(cmd.differ.bundleOverlays, jc.DeepEquals, []string{"overlay.yaml"})
+	c.Assert(cmd.differ.bundleMachines, jc.DeepEquals, map[string]string{"1": "3"})
+}
+
+func (s *ApplySuite) TestInitBadMachineMap(c *gc.C) {
+	err := cmdtesting.InitCommand(modelcmd.Wrap(s.newCommand()), []string{"bundle.yaml", "--map-machines", "foo"})
+	c.Assert(err, gc.ErrorMatches, `error in --map-machines: expected "existing" or "<bundle-id>=<machine-id>", got "foo"`)
+}
+
+func (s *ApplySuite) TestApplyPruneChanges(c *gc.C) {
+	cmd := s.newCommand()
+	for _, change := range []bundlechanges.PruneChange{{
+		Kind:      bundlechanges.PruneRemoveRelation,
+		Endpoints: []string{"mysql:db", "wordpress:db"},
+	}, {
+		Kind:        bundlechanges.PruneResetOptions,
+		Application: "mysql",
+		Options:     []string{"tuning"},
+	}, {
+		Kind:        bundlechanges.PruneRemoveUnits,
+		Application: "mysql",
+		Units:       []string{"mysql/1"},
+	}, {
+		Kind:        bundlechanges.PruneScaleApplication,
+		Application: "mariadb",
+		Scale:       2,
+	}, {
+		Kind:        bundlechanges.PruneRemoveApplication,
+		Application: "memcached",
+	}, {
+		Kind:    bundlechanges.PruneRemoveMachine,
+		Machine: "2",
+	}} {
+		err := cmd.applyPruneChange(s.api, change)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"DestroyRelation", []interface{}{(*bool)(nil), (*time.Duration)(nil), []string{"mysql:db", "wordpress:db"}}},
+		{"UnsetApplicationConfig", []interface{}{model.GenerationMaster, "mysql", []string{"tuning"}}},
+		{"DestroyUnits", []interface{}{application.DestroyUnitsParams{Units: []string{"mysql/1"}}}},
+		{"ScaleApplication", []interface{}{application.ScaleApplicationParams{ApplicationName: "mariadb", Scale: 2}}},
+		{"DestroyApplications", []interface{}{application.DestroyApplicationsParams{Applications: []string{"memcached"}}}},
+		{"DestroyMachinesWithParams", []interface{}{false, false, false, (*time.Duration)(nil), []string{"2"}}},
+	})
+}
+
+func (s *ApplySuite) TestApplyPruneChangeResultError(c *gc.C) {
+	s.api.destroyApplicationsResults = []params.DestroyApplicationResult{{
+		Error: &params.Error{Message: "application is offered"},
+	}}
+	err := s.newCommand().applyPruneChange(s.api, bundlechanges.PruneChange{
+		Kind:        bundlechanges.PruneRemoveApplication,
+		Application: "memcached",
+	})
+	c.Assert(err, gc.ErrorMatches, "application is offered")
+}
+
+type mockPruneAPI struct {
+	*testing.Stub
+
+	destroyApplicationsResults []params.DestroyApplicationResult
+}
+
+func (m *mockPruneAPI) ModelGet() (map[string]interface{}, error) {
+	m.MethodCall(m, "ModelGet")
+	return map[string]interface{}{}, m.NextErr()
+}
+
+func (m *mockPruneAPI) DestroyRelation(force *bool, maxWait *time.Duration, endpoints ...string) error {
+	m.MethodCall(m, "DestroyRelation", force, maxWait, endpoints)
+	return m.NextErr()
+}
+
+func (m *mockPruneAPI) DestroyOffers(force bool, offerURLs ...string) error {
+	m.MethodCall(m, "DestroyOffers", force, offerURLs)
+	return m.NextErr()
+}
+
+func (m *mockPruneAPI) UnsetApplicationConfig(branchName, application string, options []string) error {
+	m.MethodCall(m, "UnsetApplicationConfig", branchName, application, options)
+	return m.NextErr()
+}
+
+func (m *mockPruneAPI) DestroyUnits(in application.DestroyUnitsParams) ([]params.DestroyUnitResult, error) {
+	m.MethodCall(m, "DestroyUnits", in)
+	return make([]params.DestroyUnitResult, len(in.Units)), m.NextErr()
+}
+
+func (m *mockPruneAPI) ScaleApplication(in application.ScaleApplicationParams) (params.ScaleApplicationResult, error) {
+	m.MethodCall(m, "ScaleApplication", in)
+	return params.ScaleApplicationResult{}, m.NextErr()
+}
+
+func (m *mockPruneAPI) DestroyApplications(in application.DestroyApplicationsParams) ([]params.DestroyApplicationResult, error) {
+	m.MethodCall(m, "DestroyApplications", in)
+	if m.destroyApplicationsResults != nil {
+		return m.destroyApplicationsResults, m.NextErr()
+	}
+	return make([]params.DestroyApplicationResult, len(in.Applications)), m.NextErr()
+}
+
+func (m *mockPruneAPI) DestroyMachinesWithParams(force, keep, dryRun bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
+	m.MethodCall(m, "DestroyMachinesWithParams", force, keep, dryRun, maxWait, machines)
+	return make([]params.DestroyMachineResult, len(machines)), m.NextErr()
+}
+
+func (m *mockPruneAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
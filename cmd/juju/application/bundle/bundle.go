@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/juju/charm/v12"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 
@@ -184,6 +185,7 @@ func BuildModelRepresentation(
 	}
 	for i, cfg := range configValues {
 		options := make(map[string]interface{})
+		userOptions := set.NewStrings()
 		// The config map has values that looks like this:
 		//  map[string]interface {}{
 		//        "value":       "",
@@ -200,8 +202,12 @@ func BuildModelRepresentation(
 			if value != nil {
 				options[key] = value
 			}
+			if vm, ok := valueMap.(map[string]interface{}); ok && vm["source"] == "user" {
+				userOptions.Add(key)
+			}
 		}
 		mod.Applications[appNames[i]].Options = options
+		mod.Applications[appNames[i]].UserOptions = userOptions
 	}
 	// Lastly get all the application constraints.
 	sort.Strings(principalApps)
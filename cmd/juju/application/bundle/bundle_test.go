@@ -137,6 +137,7 @@ func (s *buildModelRepSuite) TestBuildModelRepresentationApplicationsWithSubordi
 	c.Assert(obtainedWordpress.Options, gc.HasLen, 1)
 	_, ok = obtainedWordpress.Options["skill-level"]
 	c.Assert(ok, jc.IsTrue)
+	c.Assert(obtainedWordpress.UserOptions.SortedValues(), jc.DeepEquals, []string{"skill-level"})
 	_, ok = obtainedModel.Applications["sub"]
 	c.Assert(ok, jc.IsTrue)
 
@@ -224,23 +224,7 @@ func (c *diffBundleCommand) Run(ctx *cmd.Context) error {
 	}
 	defer func() { _ = apiRoot.Close() }()
 
-	// Load up the bundle data, with includes and overlays.
-	baseSrc, err := c.bundleDataSource(ctx, apiRoot, base)
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	bundle, _, err := appbundle.ComposeAndVerifyBundle(baseSrc, c.bundleOverlays)
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	if err = c.warnForMissingRelationEndpoints(ctx, bundle); err != nil {
-		return errors.Trace(err)
-	}
-
-	// Extract the information from the current model.
-	model, err := c.readModel(apiRoot)
+	bundle, model, err := c.readBundleAndModel(ctx, apiRoot, base)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -265,6 +249,32 @@ func (c *diffBundleCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// readBundleAndModel returns the composed bundle, including any overlays,
+// and the representation of the current model to compare it with.
+func (c *diffBundleCommand) readBundleAndModel(ctx *cmd.Context, apiRoot base.APICallCloser, base corebase.Base) (*charm.BundleData, *bundlechanges.Model, error) {
+	// Load up the bundle data, with includes and overlays.
+	baseSrc, err := c.bundleDataSource(ctx, apiRoot, base)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	bundle, _, err := appbundle.ComposeAndVerifyBundle(baseSrc, c.bundleOverlays)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	if err = c.warnForMissingRelationEndpoints(ctx, bundle); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	// Extract the information from the current model.
+	model, err := c.readModel(apiRoot)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return bundle, model, nil
+}
+
 func (c *diffBundleCommand) warnForMissingRelationEndpoints(ctx *cmd.Context, bundle *charm.BundleData) error {
 	var missing []string
 	for _, relPair := range bundle.Relations {
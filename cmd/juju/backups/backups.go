@@ -27,6 +27,11 @@ type APIClient interface {
 	Create(notes string, noDownload bool) (*params.BackupsMetadataResult, error)
 	// Download pulls the backup archive file.
 	Download(filename string) (io.ReadCloser, error)
+	// Upload pushes a backup archive file to the controller.
+	Upload(archive io.ReadSeeker) (string, error)
+	// Restore sends an RPC request to restore the controller from
+	// the backup archive file on the controller.
+	Restore(filename string) error
 }
 
 // CommandBase is the base type for backups sub-commands.
@@ -206,6 +206,26 @@ func (c *fakeAPIClient) Download(id string) (io.ReadCloser, error) {
 	return c.archive, nil
 }
 
+func (c *fakeAPIClient) Upload(archive io.ReadSeeker) (string, error) {
+	c.calls = append(c.calls, "Upload")
+	if c.err != nil {
+		return "", c.err
+	}
+	data, err := io.ReadAll(archive)
+	if err != nil {
+		return "", err
+	}
+	c.args = append(c.args, string(data))
+	return c.metaresult.Filename, nil
+}
+
+func (c *fakeAPIClient) Restore(filename string) error {
+	c.calls = append(c.calls, "Restore")
+	c.args = append(c.args, filename)
+	c.idArg = filename
+	return c.err
+}
+
 func (c *fakeAPIClient) Close() error {
 	return nil
 }
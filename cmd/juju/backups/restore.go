@@ -0,0 +1,162 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/rpc"
+)
+
+const restoreDoc = `
+restore-backup replaces the state of the current controller with the
+contents of a backup archive created by 'juju create-backup'.
+
+By default the archive is read from the local filesystem and uploaded
+to the controller. Use --on-controller to restore an archive that is
+already stored on the controller, such as one created with
+'juju create-backup --no-download'.
+
+The backup must have been taken with the same major and minor version
+of Juju as the controller is running, and the controller must not be
+older than the backup. Restoring onto a controller with more than one
+node in HA is not supported; remove the extra nodes first and re-enable
+HA once the restore has completed.
+
+Restoring replaces the controller database, the dqlite data, the agent
+configuration and the controller certificates. Once that is done the
+agents on the controller machine are restarted, so the connection to
+the controller will be dropped.
+`
+
+const restoreExamples = `
+    juju restore-backup juju-backup-20230930-120000.tar.gz
+    juju restore-backup --on-controller /var/lib/juju/backups/juju-backup-20230930-120000.tar.gz
+    juju restore-backup --no-prompt juju-backup-20230930-120000.tar.gz
+`
+
+const restoreWarning = `
+WARNING! This command will replace the state of controller %q with
+the contents of the backup archive. Any changes made to the controller
+since the backup was taken will be lost.
+`
+
+// NewRestoreCommand returns a command used to restore a controller
+// from a backup archive.
+func NewRestoreCommand() cmd.Command {
+	return modelcmd.Wrap(&restoreCommand{})
+}
+
+// restoreCommand is the sub-command for restoring a backup archive.
+type restoreCommand struct {
+	CommandBase
+	modelcmd.DestroyConfirmationCommandBase
+
+	// Filename is the backup archive to restore.
+	Filename string
+	// OnController means Filename is already on the controller and
+	// doesn't need to be uploaded.
+	OnController bool
+}
+
+// Info implements Command.Info.
+func (c *restoreCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "restore-backup",
+		Args:     "<filename>",
+		Purpose:  "Restore a controller from a backup archive.",
+		Doc:      restoreDoc,
+		Examples: restoreExamples,
+		SeeAlso: []string{
+			"create-backup",
+			"download-backup",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *restoreCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.DestroyConfirmationCommandBase.SetFlags(f)
+	f.BoolVar(&c.OnController, "on-controller", false, "The archive is already stored on the controller")
+}
+
+// Init implements Command.Init.
+func (c *restoreCommand) Init(args []string) error {
+	if err := c.CommandBase.Init(args); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("missing filename")
+	}
+	filename, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.Filename = filename
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *restoreCommand) Run(ctx *cmd.Context) error {
+	if err := c.validateIaasController(c.Info().Name); err != nil {
+		return errors.Trace(err)
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.DestroyConfirmationCommandBase.NeedsConfirmation() {
+		fmt.Fprintf(ctx.Stderr, restoreWarning, controllerName)
+		if err := jujucmd.UserConfirmYes(ctx); err != nil {
+			return errors.Annotate(err, "restore")
+		}
+	}
+
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	remoteFilename := c.Filename
+	if !c.OnController {
+		if remoteFilename, err = c.upload(ctx, client); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	ctx.Infof("Restoring backup %v", remoteFilename)
+	err = client.Restore(remoteFilename)
+	// The controller agents are restarted as the final step of the
+	// restore, which may take the API connection down before we get
+	// the response.
+	if err != nil && !errors.Is(err, rpc.ErrShutdown) {
+		return errors.Annotate(err, "cannot restore backup")
+	}
+	ctx.Infof("Restore complete; controller %q is restarting", controllerName)
+	return nil
+}
+
+func (c *restoreCommand) upload(ctx *cmd.Context, client APIClient) (string, error) {
+	archive, err := c.Filesystem().Open(c.Filename)
+	if err != nil {
+		return "", errors.Annotatef(err, "while opening local archive file %v", c.Filename)
+	}
+	defer archive.Close()
+
+	ctx.Infof("Uploading %v to the controller", c.Filename)
+	remoteFilename, err := client.Upload(archive)
+	if err != nil {
+		return "", errors.Annotate(err, "while uploading archive")
+	}
+	return remoteFilename, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/rpc"
+)
+
+type restoreSuite struct {
+	BaseBackupsSuite
+	wrappedCommand cmd.Command
+	command        *backups.RestoreCommand
+	archive        string
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+func (s *restoreSuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+	s.wrappedCommand, s.command = backups.NewRestoreCommandForTest(s.store)
+	s.archive = filepath.Join(c.MkDir(), "juju-backup-20231010-101010.tar.gz")
+	err := os.WriteFile(s.archive, []byte(s.data), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreSuite) TestArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		args         []string
+		errMatch     string
+		filename     string
+		onController bool
+	}{{
+		errMatch: "missing filename",
+	}, {
+		args:     []string{"backup.tar.gz"},
+		filename: "backup.tar.gz",
+	}, {
+		args:         []string{"--on-controller", "/tmp/backup.tar.gz"},
+		filename:     "/tmp/backup.tar.gz",
+		onController: true,
+	}, {
+		args:     []string{"backup.tar.gz", "other.tar.gz"},
+		errMatch: `unrecognized args: \["other.tar.gz"\]`,
+	}} {
+		c.Logf("test %d", i)
+		wrapped, command := backups.NewRestoreCommandForTest(s.store)
+		err := cmdtesting.InitCommand(wrapped, test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(command.Filename, gc.Equals, test.filename)
+		c.Check(command.OnController, gc.Equals, test.onController)
+	}
+}
+
+func (s *restoreSuite) TestRestoreUploads(c *gc.C) {
+	client := s.setSuccess()
+	ctx, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--no-prompt", s.archive)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.CheckCalls(c, "Upload", "Restore")
+	client.CheckArgs(c, s.data, "backup-filename")
+	c.Check(cmdtesting.Stderr(ctx), jc.Contains, `Restore complete; controller "arthur" is restarting`)
+}
+
+func (s *restoreSuite) TestRestoreOnController(c *gc.C) {
+	client := s.setSuccess()
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--no-prompt", "--on-controller", "/tmp/juju-backup.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.CheckCalls(c, "Restore")
+	client.CheckArgs(c, "/tmp/juju-backup.tar.gz")
+}
+
+func (s *restoreSuite) TestRestorePrompt(c *gc.C) {
+	client := s.setSuccess()
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("y\n")
+	err := cmdtesting.InitCommand(s.wrappedCommand, []string{s.archive})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wrappedCommand.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(cmdtesting.Stderr(ctx), jc.Contains, `will replace the state of controller "arthur"`)
+	client.CheckCalls(c, "Upload", "Restore")
+}
+
+func (s *restoreSuite) TestRestorePromptAborted(c *gc.C) {
+	client := s.setSuccess()
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("n\n")
+	err := cmdtesting.InitCommand(s.wrappedCommand, []string{s.archive})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wrappedCommand.Run(ctx)
+	c.Assert(err, gc.ErrorMatches, "restore: aborted")
+
+	client.CheckCalls(c)
+}
+
+func (s *restoreSuite) TestRestoreConnectionDropped(c *gc.C) {
+	client := s.setSuccess()
+	client.err = nil
+	s.patchAPIClient(&shutdownClient{client})
+
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--no-prompt", "--on-controller", "/tmp/juju-backup.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreSuite) TestRestoreError(c *gc.C) {
+	s.setFailure("failed!")
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--no-prompt", "--on-controller", "/tmp/juju-backup.tar.gz")
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+}
+
+// shutdownClient simulates the controller restarting before
+// the response to the restore request is received.
+type shutdownClient struct {
+	*fakeAPIClient
+}
+
+func (c *shutdownClient) Restore(filename string) error {
+	_ = c.fakeAPIClient.Restore(filename)
+	return errors.Trace(rpc.ErrShutdown)
+}
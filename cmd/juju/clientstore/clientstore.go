@@ -0,0 +1,106 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clientstore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+const clientStoreDoc = `
+The client store holds the details of the controllers, models, accounts
+and cloud credentials this client knows about, under $JUJU_DATA.
+
+Account passwords and cloud credentials can be encrypted with a key
+derived from a passphrase, so that they aren't readable by anyone else
+with access to the files. While the store is encrypted, juju gets the
+passphrase from the $JUJU_CLIENT_STORE_PASSPHRASE environment variable,
+or by running a helper command given when the store was locked.
+
+See also:
+    client-store lock
+    client-store unlock
+`
+
+// NewClientStoreCommand creates the client-store supercommand and
+// registers the subcommands that it supports.
+func NewClientStoreCommand() cmd.Command {
+	clientStore := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "client-store",
+		UsagePrefix: "juju",
+		Doc:         clientStoreDoc,
+		Purpose:     "Manage encryption of the local client store.",
+	})
+
+	clientStore.Register(newLockCommand())
+	clientStore.Register(newUnlockCommand())
+	return clientStore
+}
+
+// readPassphrase gets the passphrase from the environment if it is set,
+// or else prompts for it. If confirm is true, the passphrase is read a
+// second time to check it was typed correctly.
+func readPassphrase(ctx *cmd.Context, confirm bool) (string, error) {
+	if passphrase := os.Getenv(osenv.JujuClientStorePassphraseEnvKey); passphrase != "" {
+		return passphrase, nil
+	}
+	fmt.Fprint(ctx.Stderr, "passphrase: ")
+	passphrase, err := readPassword(ctx.Stdin)
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if passphrase == "" {
+		return "", errors.New("you must enter a passphrase")
+	}
+	if !confirm {
+		return passphrase, nil
+	}
+	fmt.Fprint(ctx.Stderr, "type passphrase again: ")
+	verify, err := readPassword(ctx.Stdin)
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if passphrase != verify {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+func readPassword(stdin io.Reader) (string, error) {
+	if f, ok := stdin.(*os.File); ok && terminal.IsTerminal(int(f.Fd())) {
+		password, err := terminal.ReadPassword(int(f.Fd()))
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return string(password), nil
+	}
+	return readLine(stdin)
+}
+
+func readLine(stdin io.Reader) (string, error) {
+	// Read one byte at a time to avoid reading beyond the delimiter.
+	line, err := bufio.NewReader(byteAtATimeReader{stdin}).ReadString('\n')
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return line[:len(line)-1], nil
+}
+
+type byteAtATimeReader struct {
+	io.Reader
+}
+
+func (r byteAtATimeReader) Read(out []byte) (int, error) {
+	return r.Reader.Read(out[:1])
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clientstore_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/clientstore"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type clientStoreSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+}
+
+var _ = gc.Suite(&clientStoreSuite{})
+
+func (s *clientStoreSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	err := jujuclient.WriteAccountsFile(map[string]jujuclient.AccountDetails{
+		"ctrl": {User: "admin", Password: "hunter2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientStoreSuite) run(c *gc.C, stdin string, args ...string) (*cmd.Context, error) {
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader(stdin)
+	var command cmd.Command
+	switch args[0] {
+	case "lock":
+		command = clientstore.NewLockCommandForTest()
+	case "unlock":
+		command = clientstore.NewUnlockCommandForTest()
+	default:
+		c.Fatalf("unknown subcommand %q", args[0])
+	}
+	if err := cmdtesting.InitCommand(command, args[1:]); err != nil {
+		return ctx, err
+	}
+	return ctx, command.Run(ctx)
+}
+
+func (s *clientStoreSuite) accountsFile(c *gc.C) string {
+	data, err := os.ReadFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *clientStoreSuite) TestLockPrompts(c *gc.C) {
+	ctx, err := s.run(c, "sekrit\nsekrit\n", "lock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "passphrase: \ntype passphrase again: \n")
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Client store encrypted.
+Set JUJU_CLIENT_STORE_PASSPHRASE to the passphrase to use juju.
+`[1:])
+	c.Check(s.accountsFile(c), gc.Not(jc.Contains), "hunter2")
+
+	s.PatchEnvironment(osenv.JujuClientStorePassphraseEnvKey, "sekrit")
+	details, err := jujuclient.NewFileClientStore().AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.Password, gc.Equals, "hunter2")
+}
+
+func (s *clientStoreSuite) TestLockMismatch(c *gc.C) {
+	_, err := s.run(c, "sekrit\nsecret\n", "lock")
+	c.Assert(err, gc.ErrorMatches, "passphrases do not match")
+	c.Check(s.accountsFile(c), jc.Contains, "hunter2")
+}
+
+func (s *clientStoreSuite) TestLockFromEnvironment(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuClientStorePassphraseEnvKey, "sekrit")
+	ctx, err := s.run(c, "", "lock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "")
+	c.Check(s.accountsFile(c), gc.Not(jc.Contains), "hunter2")
+}
+
+func (s *clientStoreSuite) TestLockTwice(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuClientStorePassphraseEnvKey, "sekrit")
+	_, err := s.run(c, "", "lock")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.run(c, "", "lock")
+	c.Assert(err, gc.ErrorMatches, "client store is already encrypted")
+}
+
+func (s *clientStoreSuite) TestLockWithHelper(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("helper script is a shell script")
+	}
+	helper := filepath.Join(c.MkDir(), "helper")
+	err := os.WriteFile(helper, []byte("#!/bin/sh\necho sekrit\n"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := s.run(c, "", "lock", "--helper", helper)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "Client store encrypted.\n")
+	c.Check(s.accountsFile(c), gc.Not(jc.Contains), "hunter2")
+
+	// No passphrase is needed in the environment.
+	details, err := jujuclient.NewFileClientStore().AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.Password, gc.Equals, "hunter2")
+
+	ctx, err = s.run(c, "", "unlock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "Client store decrypted.\n")
+	c.Check(s.accountsFile(c), jc.Contains, "password: hunter2")
+}
+
+func (s *clientStoreSuite) TestUnlockPrompts(c *gc.C) {
+	_, err := s.run(c, "sekrit\nsekrit\n", "lock")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.run(c, "guess\n", "unlock")
+	c.Assert(err, gc.ErrorMatches, "incorrect client store passphrase")
+
+	ctx, err := s.run(c, "sekrit\n", "unlock")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "passphrase: \n")
+	c.Check(s.accountsFile(c), jc.Contains, "password: hunter2")
+
+	cfg, err := jujuclient.ReadEncryptionConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg, gc.IsNil)
+}
+
+func (s *clientStoreSuite) TestUnlockNotLocked(c *gc.C) {
+	_, err := s.run(c, "", "unlock")
+	c.Assert(err, gc.ErrorMatches, "client store is not encrypted")
+}
+
+func (s *clientStoreSuite) TestUnrecognizedArgs(c *gc.C) {
+	_, err := s.run(c, "", "lock", "now")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["now"\]`)
+	_, err = s.run(c, "", "unlock", "now")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["now"\]`)
+}
@@ -0,0 +1,16 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clientstore
+
+import "github.com/juju/cmd/v3"
+
+// NewLockCommandForTest returns a client-store lock command.
+func NewLockCommandForTest() cmd.Command {
+	return newLockCommand()
+}
+
+// NewUnlockCommandForTest returns a client-store unlock command.
+func NewUnlockCommandForTest() cmd.Command {
+	return newUnlockCommand()
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clientstore
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+)
+
+const lockDoc = `
+Encrypts the account passwords and cloud credentials in the client store.
+
+The encryption key is derived from a passphrase. By default the passphrase
+is read from $JUJU_CLIENT_STORE_PASSPHRASE, or prompted for if that isn't
+set, and juju must then be run with $JUJU_CLIENT_STORE_PASSPHRASE set for
+as long as the store stays encrypted.
+
+Alternatively --helper names a command which prints the passphrase when
+run with the extra argument "get", in the manner of git credential
+helpers. The helper is recorded and run whenever juju needs to read or
+write a secret, so it can fetch the passphrase from a keyring or password
+manager.
+
+Passwords and credentials already in the store are encrypted straight
+away; any later found in plain text are encrypted when next read.
+`
+
+const lockExamples = `
+    juju client-store lock
+    juju client-store lock --helper "pass show juju/client-store"
+`
+
+type lockCommand struct {
+	cmd.CommandBase
+
+	helper string
+}
+
+func newLockCommand() cmd.Command {
+	return &lockCommand{}
+}
+
+// Info implements Command.
+func (c *lockCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "lock",
+		Purpose:  "Encrypt the secrets in the client store.",
+		Doc:      lockDoc,
+		Examples: lockExamples,
+		SeeAlso: []string{
+			"client-store unlock",
+		},
+	})
+}
+
+// SetFlags implements Command.
+func (c *lockCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.helper, "helper", "", "Command run to get the passphrase")
+}
+
+// Init implements Command.
+func (c *lockCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *lockCommand) Run(ctx *cmd.Context) error {
+	cfg, err := jujuclient.ReadEncryptionConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cfg != nil {
+		return errors.New("client store is already encrypted")
+	}
+
+	var passphrase string
+	if c.helper != "" {
+		passphrase, err = jujuclient.HelperPassphrase(c.helper).Passphrase()
+	} else {
+		passphrase, err = readPassphrase(ctx, true)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := jujuclient.EnableEncryption(passphrase, c.helper); err != nil {
+		return errors.Trace(err)
+	}
+
+	fmt.Fprintln(ctx.Stdout, "Client store encrypted.")
+	if c.helper == "" {
+		fmt.Fprintf(ctx.Stdout, "Set %s to the passphrase to use juju.\n", osenv.JujuClientStorePassphraseEnvKey)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clientstore
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+const unlockDoc = `
+Decrypts the account passwords and cloud credentials in the client store,
+writing them back in plain text, and stops encrypting them.
+
+The passphrase is got from the helper given when the store was locked,
+or else from $JUJU_CLIENT_STORE_PASSPHRASE, or is prompted for.
+`
+
+const unlockExamples = `
+    juju client-store unlock
+`
+
+type unlockCommand struct {
+	cmd.CommandBase
+}
+
+func newUnlockCommand() cmd.Command {
+	return &unlockCommand{}
+}
+
+// Info implements Command.
+func (c *unlockCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "unlock",
+		Purpose:  "Decrypt the secrets in the client store.",
+		Doc:      unlockDoc,
+		Examples: unlockExamples,
+		SeeAlso: []string{
+			"client-store lock",
+		},
+	})
+}
+
+// Init implements Command.
+func (c *unlockCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *unlockCommand) Run(ctx *cmd.Context) error {
+	cfg, err := jujuclient.ReadEncryptionConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cfg == nil {
+		return errors.New("client store is not encrypted")
+	}
+
+	var passphrase string
+	if cfg.Helper != "" {
+		passphrase, err = cfg.PassphraseSource().Passphrase()
+	} else {
+		passphrase, err = readPassphrase(ctx, false)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := jujuclient.DisableEncryption(passphrase); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintln(ctx.Stdout, "Client store decrypted.")
+	return nil
+}
@@ -4,8 +4,11 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -25,6 +28,7 @@ import (
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/rpc/params"
 )
 
 // defaultLineCount is the default number of lines to
@@ -43,6 +47,11 @@ Each log line is emitted in this format:
 
   <entity> <timestamp> <log-level> <module>:<line-no> <message>
 
+With '--format json', each log message is instead emitted as a single line
+JSON object, with "entity", "timestamp", "severity", "module", "location",
+"labels" and "message" fields. Timestamps are in RFC3339 format, and the
+'--utc', '--date', '--ms', '--location' and '--color' options don't apply.
+
 The "entity" is the source of the message: a machine or unit. The names for
 machines and units can be seen in the output of `[1:] + "`juju status`" + `.
 
@@ -65,8 +74,25 @@ The filtering options combine as follows:
 * All --include-label options are logically ORed together.
 * All --exclude-label options are logically ORed together.
 * The combined --include, --exclude, --include-module, --exclude-module,
-  --include-label and --exclude-label selections are logically ANDed to form
-  the complete filter.
+  --include-label, --exclude-label, --grep and --exclude-grep selections
+  are logically ANDed to form the complete filter.
+
+The '--grep' and '--exclude-grep' options filter by log message, using a
+regular expression in Go's RE2 syntax. Only messages matching '--grep' are
+shown, and messages matching '--exclude-grep' are not.
+
+The '--since' and '--until' options restrict the output to messages logged
+within a time window. Each accepts either an absolute time, such as
+"2023-09-30T12:00:00Z" or "2023-09-30 12:00:00", or a duration, such as
+"2h" or "90m", meaning that long ago. Absolute times without a time zone are
+taken to be local time, or UTC if '--utc' is given. Using '--since' shows
+every matching message from the start of the window, so '--lines' is
+ignored. Once the end of the window given by '--until' has passed, no more
+messages are shown.
+
+All filtering is done by the controller, so only the matching messages are
+sent to the client. Note that the filters are applied after the controller
+has chosen which messages '--lines' refers to.
 
 `
 
@@ -101,10 +127,27 @@ machine-3 or machine-4, and then stop:
         --exclude machine-3 \
         --exclude machine-4
 
+Show all ERROR messages as JSON objects, for processing with jq:
+
+    juju debug-log --replay --no-tail --level ERROR --format json | jq .message
+
 To see all WARNING and ERROR messages and then continue showing any
 new WARNING and ERROR messages as they are logged:
 
     juju debug-log --replay --level WARNING
+
+Show all messages logged during a two hour window, and then exit:
+
+    juju debug-log --since "2023-09-30 10:00:00" --until "2023-09-30 12:00:00"
+
+Show the hook failures logged in the last 30 minutes, and then continue to
+append any new ones:
+
+    juju debug-log --since 30m --grep "hook.*failed"
+
+Show all messages from the last hour, except those about leadership:
+
+    juju debug-log --since 1h --exclude-grep "(?i)leader" --no-tail
 `
 
 func (c *debugLogCommand) Info() *cmd.Info {
@@ -148,8 +191,13 @@ type debugLogCommand struct {
 	retry      bool
 	retryDelay time.Duration
 
+	since string
+	until string
+
+	output string
 	format string
 	tz     *time.Location
+	clock  clock.Clock
 }
 
 func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
@@ -170,11 +218,16 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "Exit once this many of the most recent (possibly filtered) lines are shown")
 	f.BoolVar(&c.params.Replay, "replay", false, "Show the entire (possibly filtered) log and continue to append")
+	f.StringVar(&c.since, "since", "", "Only show log messages logged after this time or duration ago")
+	f.StringVar(&c.until, "until", "", "Only show log messages logged before this time or duration ago")
+	f.StringVar(&c.params.IncludeMessage, "grep", "", "Only show log messages matching this regular expression")
+	f.StringVar(&c.params.ExcludeMessage, "exclude-grep", "", "Do not show log messages matching this regular expression")
 
 	f.BoolVar(&c.noTail, "no-tail", false, "Stop after returning existing log messages")
 	f.BoolVar(&c.tail, "tail", false, "Wait for new logs")
 	f.BoolVar(&c.color, "color", false, "Force use of ANSI color codes")
 
+	f.StringVar(&c.output, "format", "text", "Specify output format (json|text)")
 	f.BoolVar(&c.utc, "utc", false, "Show times in UTC")
 	f.BoolVar(&c.location, "location", false, "Show filename and line numbers")
 	f.BoolVar(&c.date, "date", false, "Show dates as well as times")
@@ -202,6 +255,9 @@ func (c *debugLogCommand) Init(args []string) error {
 	if c.retryDelay < 0 {
 		return errors.NotValidf("negative retry delay")
 	}
+	if c.output != "text" && c.output != "json" {
+		return errors.Errorf("format value %q is not one of %q, %q", c.output, "text", "json")
+	}
 	if c.utc {
 		c.tz = time.UTC
 	}
@@ -213,11 +269,86 @@ func (c *debugLogCommand) Init(args []string) error {
 	if c.ms {
 		c.format = c.format + ".000"
 	}
+	if err := c.parseTimeWindow(); err != nil {
+		return errors.Trace(err)
+	}
+	if c.until != "" && c.retry {
+		return errors.NotValidf("setting --until and --retry")
+	}
+	for flag, pattern := range map[string]string{
+		"grep":         c.params.IncludeMessage,
+		"exclude-grep": c.params.ExcludeMessage,
+	} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return errors.Errorf("--%s value %q is not a valid regular expression: %v", flag, pattern, err)
+		}
+	}
 	c.params.IncludeEntity = transform.Slice(c.params.IncludeEntity, c.parseEntity)
 	c.params.ExcludeEntity = transform.Slice(c.params.ExcludeEntity, c.parseEntity)
 	return cmd.CheckEmpty(args)
 }
 
+// parseTimeWindow sets the start and end times of the log messages to
+// show from the --since and --until options.
+func (c *debugLogCommand) parseTimeWindow() error {
+	if c.clock == nil {
+		c.clock = clock.WallClock
+	}
+	now := c.clock.Now()
+	if c.since != "" {
+		since, err := c.parseTime(c.since, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --since value")
+		}
+		c.params.StartTime = since
+		// The whole window is wanted, not just its last few lines.
+		c.params.Replay = true
+	}
+	if c.until != "" {
+		until, err := c.parseTime(c.until, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --until value")
+		}
+		if !c.params.StartTime.IsZero() && until.Before(c.params.StartTime) {
+			return errors.NotValidf("--until time before --since time")
+		}
+		c.params.EndTime = until
+	}
+	return nil
+}
+
+// debugLogTimeLayouts holds the accepted layouts for absolute times
+// passed to --since and --until.
+var debugLogTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTime parses value as either a duration before now, or an
+// absolute time. Absolute times without a time zone are read in the
+// command's time zone.
+func (c *debugLogCommand) parseTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, errors.Errorf("negative duration %q", value)
+		}
+		return now.Add(-d), nil
+	}
+	loc := c.tz
+	if loc == nil {
+		loc = time.Local
+	}
+	for _, layout := range debugLogTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf(`%q is not a duration like "2h" or a time like "2006-01-02 15:04:05"`, value)
+}
+
 func (c *debugLogCommand) parseEntity(entity string) string {
 	tag, err := names.ParseTag(entity)
 	switch {
@@ -268,9 +399,16 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) error {
 		c.params.NoTail = !isTerminal(ctx.Stdout)
 	}
 
-	writer := ansiterm.NewWriter(ctx.Stdout)
-	if c.color {
-		writer.SetColorCapable(true)
+	writeRecord := c.writeJSONRecord(ctx.Stdout)
+	if c.output == "text" {
+		writer := ansiterm.NewWriter(ctx.Stdout)
+		if c.color {
+			writer.SetColorCapable(true)
+		}
+		writeRecord = func(r common.LogMessage) error {
+			c.writeLogRecord(writer, r)
+			return nil
+		}
 	}
 
 	err := retry.Call(retry.CallArgs{
@@ -291,7 +429,9 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) error {
 				if !ok {
 					return ErrConnectionClosed
 				}
-				c.writeLogRecord(writer, msg)
+				if err := writeRecord(msg); err != nil {
+					return errors.Trace(err)
+				}
 			}
 		},
 		IsFatalError: func(err error) bool {
@@ -353,3 +493,23 @@ func (c *debugLogCommand) writeLogRecord(w *ansiterm.Writer, r common.LogMessage
 	}
 	fmt.Fprintln(w, r.Message)
 }
+
+// writeJSONRecord returns a func which writes each log message to w as
+// a single line JSON object.
+func (c *debugLogCommand) writeJSONRecord(w io.Writer) func(common.LogMessage) error {
+	enc := json.NewEncoder(w)
+	// Log messages often hold "<", ">" and "&", and they're more use
+	// to log tooling unescaped.
+	enc.SetEscapeHTML(false)
+	return func(r common.LogMessage) error {
+		return enc.Encode(params.StructuredLogMessage{
+			Entity:    r.Entity,
+			Timestamp: r.Timestamp,
+			Severity:  r.Severity,
+			Module:    r.Module,
+			Location:  r.Location,
+			Labels:    r.Labels,
+			Message:   r.Message,
+		})
+	}
+}
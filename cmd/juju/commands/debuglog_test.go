@@ -6,6 +6,7 @@ package commands
 import (
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/cmd/v3/cmdtesting"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
@@ -122,6 +123,36 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 		}, {
 			args:     []string{"--retry-delay", "-1s"},
 			errMatch: `negative retry delay not valid`,
+		}, {
+			args: []string{"--grep", "hook.*failed", "--exclude-grep", "(?i)leader"},
+			expected: common.DebugLogParams{
+				Backlog:        10,
+				IncludeMessage: "hook.*failed",
+				ExcludeMessage: "(?i)leader",
+			},
+		}, {
+			args:     []string{"--grep", "hook ("},
+			errMatch: `--grep value "hook \(" is not a valid regular expression: .*`,
+		}, {
+			args: []string{"--utc", "--since", "2016-11-30 10:00:00", "--until", "2016-11-30T12:00:00Z"},
+			expected: common.DebugLogParams{
+				Backlog:   10,
+				Replay:    true,
+				StartTime: time.Date(2016, 11, 30, 10, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2016, 11, 30, 12, 0, 0, 0, time.UTC),
+			},
+		}, {
+			args:     []string{"--since", "yesterday"},
+			errMatch: `invalid --since value: "yesterday" is not a duration like "2h" or a time like "2006-01-02 15:04:05"`,
+		}, {
+			args:     []string{"--until", "-2h"},
+			errMatch: `invalid --until value: negative duration "-2h"`,
+		}, {
+			args:     []string{"--since", "2016-11-30T12:00:00Z", "--until", "2016-11-30T10:00:00Z"},
+			errMatch: `--until time before --since time not valid`,
+		}, {
+			args:     []string{"--until", "1h", "--retry"},
+			errMatch: `setting --until and --retry not valid`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -160,6 +191,17 @@ func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 	})
 }
 
+func (s *DebugLogSuite) TestTimeWindowDurations(c *gc.C) {
+	now := time.Date(2016, 11, 30, 12, 0, 0, 0, time.UTC)
+	command := &debugLogCommand{clock: testclock.NewClock(now)}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	err := cmdtesting.InitCommand(modelcmd.Wrap(command), []string{"--since", "2h", "--until", "30m"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(command.params.StartTime, gc.Equals, now.Add(-2*time.Hour))
+	c.Check(command.params.EndTime, gc.Equals, now.Add(-30*time.Minute))
+	c.Check(command.params.Replay, jc.IsTrue)
+}
+
 func (s *DebugLogSuite) TestLogOutput(c *gc.C) {
 	// test timezone is 6 hours east of UTC
 	tz := time.FixedZone("test", 6*60*60)
@@ -245,6 +287,39 @@ func (s *DebugLogSuite) TestLogOutputWithLogs(c *gc.C) {
 		"machine-0: 14:15:23 INFO test.module somefile.go:123 http,foo this is the log output\n")
 }
 
+func (s *DebugLogSuite) TestLogOutputJSON(c *gc.C) {
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		return &fakeDebugLogAPI{log: []common.LogMessage{
+			{
+				Entity:    "machine-0",
+				Timestamp: time.Date(2016, 10, 9, 8, 15, 23, 345000000, time.UTC),
+				Severity:  "INFO",
+				Module:    "test.module",
+				Location:  "somefile.go:123",
+				Message:   "this is the <log> output",
+				Labels:    []string{"http"},
+			}, {
+				Entity:    "unit-foo-0",
+				Timestamp: time.Date(2016, 10, 9, 8, 15, 24, 0, time.UTC),
+				Severity:  "ERROR",
+				Module:    "test.module",
+				Location:  "otherfile.go:42",
+				Message:   "whoops",
+			},
+		}}, nil
+	})
+	ctx, err := cmdtesting.RunCommand(c, newDebugLogCommand(jujuclienttesting.MinimalStore()), "--format", "json", "--utc", "--ms")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		`{"entity":"machine-0","timestamp":"2016-10-09T08:15:23.345Z","severity":"INFO","module":"test.module","location":"somefile.go:123","labels":["http"],"message":"this is the <log> output"}`+"\n"+
+		`{"entity":"unit-foo-0","timestamp":"2016-10-09T08:15:24Z","severity":"ERROR","module":"test.module","location":"otherfile.go:42","message":"whoops"}`+"\n")
+}
+
+func (s *DebugLogSuite) TestInvalidFormat(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, newDebugLogCommand(jujuclienttesting.MinimalStore()), "--format", "yaml")
+	c.Assert(err, gc.ErrorMatches, `format value "yaml" is not one of "text", "json"`)
+}
+
 type fakeDebugLogAPI struct {
 	log    []common.LogMessage
 	params common.DebugLogParams
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/caas"
 	"github.com/juju/juju/cmd/juju/charmhub"
+	"github.com/juju/juju/cmd/juju/clientstore"
 	"github.com/juju/juju/cmd/juju/cloud"
 	"github.com/juju/juju/cmd/juju/controller"
 	"github.com/juju/juju/cmd/juju/crossmodel"
@@ -212,8 +213,11 @@ func (m jujuMain) Run(args []string) int {
 	if flagIdx != -1 && (cmdIdx > flagIdx || cmdIdx == -1) {
 		cmdArgs[flagIdx] = "version"
 	}
+	ctx, endTracing := startTracing(ctx, args)
 	jcmd := NewJujuCommand(ctx, jujuMsg)
-	return cmd.Main(jcmd, ctx, cmdArgs)
+	exitCode := cmd.Main(jcmd, ctx, cmdArgs)
+	endTracing(exitCode)
+	return exitCode
 }
 
 func installProxy() error {
@@ -394,10 +398,13 @@ func registerCommands(r commandRegistry) {
 	r.Register(newDebugLogCommand(nil))
 	r.Register(ssh.NewDebugHooksCommand(nil, ssh.DefaultSSHRetryStrategy, ssh.DefaultSSHPublicKeyRetryStrategy))
 	r.Register(ssh.NewDebugCodeCommand(nil, ssh.DefaultSSHRetryStrategy, ssh.DefaultSSHPublicKeyRetryStrategy))
+	r.Register(newReplayHookCommand())
 
 	// Configuration commands.
 	r.Register(model.NewModelGetConstraintsCommand())
 	r.Register(model.NewModelSetConstraintsCommand())
+	r.Register(model.NewModelGetQuotaCommand())
+	r.Register(model.NewModelSetQuotaCommand())
 	r.Register(newSyncAgentBinaryCommand())
 	r.Register(newUpgradeJujuCommand())
 	r.Register(newUpgradeControllerCommand())
@@ -411,6 +418,7 @@ func registerCommands(r commandRegistry) {
 	// Manage backups.
 	r.Register(backups.NewCreateCommand())
 	r.Register(backups.NewDownloadCommand())
+	r.Register(backups.NewRestoreCommand())
 
 	// Manage authorized ssh keys.
 	r.Register(NewAddKeysCommand())
@@ -429,6 +437,14 @@ func registerCommands(r commandRegistry) {
 	r.Register(user.NewLogoutCommand())
 	r.Register(user.NewRemoveCommand())
 	r.Register(user.NewWhoAmICommand())
+	r.Register(user.NewAddGroupCommand())
+	r.Register(user.NewRemoveGroupCommand())
+	r.Register(user.NewListGroupsCommand())
+	r.Register(user.NewAddUserToGroupCommand())
+	r.Register(user.NewRemoveUserFromGroupCommand())
+	r.Register(user.NewAddRoleCommand())
+	r.Register(user.NewRemoveRoleCommand())
+	r.Register(user.NewListRolesCommand())
 
 	// Manage machines
 	r.Register(machine.NewAddCommand())
@@ -444,6 +460,7 @@ func registerCommands(r commandRegistry) {
 	r.Register(model.NewDestroyCommand())
 	r.Register(model.NewGrantCommand())
 	r.Register(model.NewRevokeCommand())
+	r.Register(model.NewGrantRoleCommand())
 	r.Register(model.NewShowCommand())
 	r.Register(model.NewModelCredentialCommand())
 	if featureflag.Enabled(feature.Branches) || featureflag.Enabled(feature.Generations) {
@@ -459,6 +476,8 @@ func registerCommands(r commandRegistry) {
 
 	r.Register(newMigrateCommand())
 	r.Register(model.NewExportBundleCommand())
+	r.Register(model.NewExportModelCommand())
+	r.Register(controller.NewImportModelCommand())
 
 	if featureflag.Enabled(feature.DeveloperMode) {
 		r.Register(model.NewDumpCommand())
@@ -479,6 +498,7 @@ func registerCommands(r commandRegistry) {
 
 	// Manage and control applications
 	r.Register(application.NewAddUnitCommand())
+	r.Register(application.NewApplyCommand())
 	r.Register(application.NewConfigCommand())
 	r.Register(application.NewDeployCommand())
 	r.Register(application.NewExposeCommand())
@@ -506,6 +526,11 @@ func registerCommands(r commandRegistry) {
 	r.Register(storage.NewDetachStorageCommandWithAPI())
 	r.Register(storage.NewAttachStorageCommandWithAPI())
 	r.Register(storage.NewImportFilesystemCommand(storage.NewStorageImporter, nil))
+	r.Register(storage.NewSnapshotStorageCommand())
+	r.Register(storage.NewListStorageSnapshotsCommand())
+	r.Register(storage.NewRestoreStorageCommand())
+	r.Register(storage.NewRemoveStorageSnapshotCommand())
+	r.Register(storage.NewResizeStorageCommand())
 
 	// Manage spaces
 	r.Register(space.NewAddCommand())
@@ -529,7 +554,9 @@ func registerCommands(r commandRegistry) {
 	r.Register(controller.NewUnregisterCommand(jujuclient.NewFileClientStore()))
 	r.Register(controller.NewEnableDestroyControllerCommand())
 	r.Register(controller.NewShowControllerCommand())
+	r.Register(controller.NewSetJumpHostsCommand())
 	r.Register(controller.NewConfigCommand())
+	r.Register(controller.NewAuditLogCommand())
 
 	// Debug Metrics
 	r.Register(metricsdebug.New())
@@ -554,6 +581,7 @@ func registerCommands(r commandRegistry) {
 	r.Register(cloud.NewShowCredentialCommand())
 	r.Register(model.NewGrantCloudCommand())
 	r.Register(model.NewRevokeCloudCommand())
+	r.Register(clientstore.NewClientStoreCommand())
 
 	// CAAS commands
 	r.Register(caas.NewAddCAASCommand(&cloudToCommandAdapter{}))
@@ -580,6 +608,7 @@ func registerCommands(r commandRegistry) {
 	// Secrets.
 	r.Register(secrets.NewListSecretsCommand())
 	r.Register(secrets.NewShowSecretsCommand())
+	r.Register(secrets.NewSecretDiffCommand())
 	r.Register(secrets.NewAddSecretCommand())
 	r.Register(secrets.NewUpdateSecretCommand())
 	r.Register(secrets.NewRemoveSecretCommand())
@@ -311,6 +311,8 @@ var commandNames = []string{
 	"add-k8s",
 	"add-machine",
 	"add-model",
+	"add-group",
+	"add-role",
 	"add-secret-backend",
 	"add-space",
 	"add-ssh-key",
@@ -318,16 +320,20 @@ var commandNames = []string{
 	"add-storage",
 	"add-unit",
 	"add-user",
+	"add-user-to-group",
 	"agree",
 	"agreements",
+	"apply",
 	"attach-resource",
 	"attach-storage",
+	"audit-log",
 	"autoload-credentials",
 	"bind",
 	"bootstrap",
 	"cancel-task",
 	"change-user-password",
 	"charm-resources",
+	"client-store",
 	"clouds",
 	"collect-metrics",
 	"config",
@@ -361,6 +367,7 @@ var commandNames = []string{
 	"enable-user",
 	"exec",
 	"export-bundle",
+	"export-model",
 	"expose",
 	"find",
 	"find-offers",
@@ -370,9 +377,12 @@ var commandNames = []string{
 	"grant",
 	"grant-secret",
 	"grant-cloud",
+	"grant-role",
+	"groups",
 	"help",
 	"help-tool",
 	"import-filesystem",
+	"import-model",
 	"import-ssh-key",
 	"info",
 	"integrate",
@@ -385,6 +395,8 @@ var commandNames = []string{
 	"list-credentials",
 	"list-disabled-commands",
 	"list-firewall-rules",
+	"list-groups",
+	"list-roles",
 	"list-machines",
 	"list-models",
 	"list-offers",
@@ -398,6 +410,7 @@ var commandNames = []string{
 	"list-ssh-keys",
 	"list-storage",
 	"list-storage-pools",
+	"list-storage-snapshots",
 	"list-subnets",
 	"list-users",
 	"login",
@@ -408,6 +421,7 @@ var commandNames = []string{
 	"model-config",
 	"model-default",
 	"model-defaults",
+	"model-quota",
 	"models",
 	"move-to-space",
 	"offer",
@@ -422,10 +436,12 @@ var commandNames = []string{
 	"remove-application",
 	"remove-cloud",
 	"remove-credential",
+	"remove-group",
 	"remove-k8s",
 	"remove-machine",
 	"remove-offer",
 	"remove-relation",
+	"remove-role",
 	"remove-saas",
 	"remove-secret-backend",
 	"remove-secret",
@@ -433,30 +449,40 @@ var commandNames = []string{
 	"remove-ssh-key",
 	"remove-storage",
 	"remove-storage-pool",
+	"remove-storage-snapshot",
 	"remove-unit",
 	"remove-user",
+	"remove-user-from-group",
 	"rename-space",
+	"replay-hook",
+	"resize-storage",
 	"resolved",
 	"resolve",
 	"resources",
+	"restore-backup",
+	"restore-storage",
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
 	"revoke-cloud",
 	"revoke-secret",
+	"roles",
 	"run",
 	"scale-application",
 	"scp",
 	"secrets",
 	"secret-backends",
+	"secret-diff",
 	"set-application-base",
 	"set-credential",
 	"set-constraints",
 	"set-default-credentials",
 	"set-default-region",
 	"set-firewall-rule",
+	"set-jump-hosts",
 	"set-meter-status",
 	"set-model-constraints",
+	"set-model-quota",
 	"show-action",
 	"show-application",
 	"show-cloud",
@@ -475,12 +501,14 @@ var commandNames = []string{
 	"show-task",
 	"show-unit",
 	"show-user",
+	"snapshot-storage",
 	"spaces",
 	"ssh",
 	"ssh-keys",
 	"status",
 	"storage",
 	"storage-pools",
+	"storage-snapshots",
 	"subnets",
 	"suspend-relation",
 	"switch",
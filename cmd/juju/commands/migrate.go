@@ -4,12 +4,14 @@
 package commands
 
 import (
+	"io"
 	"strings"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/httpbakery"
 	"github.com/juju/cmd/v3"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/names/v4"
 	"gopkg.in/macaroon.v2"
 
@@ -19,6 +21,8 @@ import (
 	"github.com/juju/juju/api/controller/controller"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/rpc/params"
 )
@@ -35,6 +39,7 @@ func newMigrateCommand() modelcmd.ModelCommand {
 type migrateCommand struct {
 	modelcmd.ModelCommandBase
 	targetController string
+	dryRun           bool
 
 	// Overridden by tests
 	newAPIRoot func(jujuclient.ClientStore, string, string) (api.Connection, error)
@@ -45,6 +50,7 @@ type migrateCommand struct {
 
 type migrateAPI interface {
 	InitiateMigration(spec controller.MigrationSpec) (string, error)
+	PrecheckMigration(spec controller.MigrationSpec) (controller.MigrationPrecheckResult, error)
 	IdentityProviderURL() (string, error)
 	Close() error
 }
@@ -82,15 +88,28 @@ This command only starts a model migration - it does not wait for its
 completion. The progress of a migration can be tracked using the
 "status" command and by consulting the logs.
 
+With --dry-run, no migration is started. Instead the checks which
+would be run before a migration are run against the target controller,
+and every machine, unit, relation or other problem which would prevent
+the migration is reported. The model is also trial exported and
+imported into a temporary model on the target controller, which is
+removed afterwards.
+
+`
+
+const migrateExamples = `
+    juju migrate mymodel prod-controller
+    juju migrate --dry-run mymodel prod-controller
 `
 
 // Info implements cmd.Command.
 func (c *migrateCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
-		Name:    "migrate",
-		Args:    "<model-name> <target-controller-name>",
-		Purpose: "Migrate a workload model to another controller.",
-		Doc:     migrateDoc,
+		Name:     "migrate",
+		Args:     "<model-name> <target-controller-name>",
+		Purpose:  "Migrate a workload model to another controller.",
+		Doc:      migrateDoc,
+		Examples: migrateExamples,
 		SeeAlso: []string{
 			"login",
 			"controllers",
@@ -99,6 +118,12 @@ func (c *migrateCommand) Info() *cmd.Info {
 	})
 }
 
+// SetFlags implements cmd.Command.
+func (c *migrateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Check whether the migration would succeed, without starting it")
+}
+
 // Init implements cmd.Command.
 func (c *migrateCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -134,6 +159,9 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	spec.ModelUUID = uuids[0]
+	if c.dryRun {
+		return c.precheckMigration(ctx, modelName, spec)
+	}
 	if err := c.checkMigrationFeasibility(spec); err != nil {
 		return errors.Trace(err)
 	}
@@ -154,6 +182,64 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// precheckMigration runs the migration prechecks without starting a
+// migration, reporting every problem found.
+func (c *migrateCommand) precheckMigration(ctx *cmd.Context, modelName string, spec *controller.MigrationSpec) error {
+	var blockers []coremigration.Blocker
+	if err := c.checkMigrationFeasibility(spec); err != nil {
+		blockers = append(blockers, coremigration.Blocker{
+			Check:   "users",
+			Entity:  "model",
+			Message: err.Error(),
+		})
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return err
+	}
+	api, err := c.getMigrationAPI(controllerName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = api.Close() }()
+	result, err := api.PrecheckMigration(*spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	blockers = append(blockers, result.Blockers...)
+
+	for _, warning := range result.Warnings {
+		ctx.Warningf("%s", warning)
+	}
+	if len(blockers) == 0 {
+		ctx.Infof("Migration of model %q to controller %q would be attempted; no problems found", modelName, c.targetController)
+		return nil
+	}
+	if err := formatMigrationBlockers(ctx.Stdout, blockers); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Errorf("migration of model %q to controller %q would fail: %d problem(s) found",
+		modelName, c.targetController, len(blockers))
+}
+
+// formatMigrationBlockers writes a tabular report of the problems
+// which would prevent a migration.
+func formatMigrationBlockers(writer io.Writer, blockers []coremigration.Blocker) error {
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Check", "Entity", "Problem")
+	for _, blocker := range blockers {
+		check := blocker.Check
+		if check == "" {
+			check = "-"
+		}
+		// Some messages span several lines, which would break
+		// the table layout.
+		w.Println(check, blocker.Entity, strings.Join(strings.Fields(blocker.Message), " "))
+	}
+	return tw.Flush()
+}
+
 func (c *migrateCommand) getMigrationSpec() (*controller.MigrationSpec, error) {
 	store := c.ClientStore()
 
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/api/controller/controller"
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/cmd/modelcmd"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/rpc/params"
@@ -416,6 +417,64 @@ func (s *MigrateSuite) TestControllerDoesNotExist(c *gc.C) {
 	c.Check(s.api.specSeen, gc.IsNil) // API shouldn't have been called
 }
 
+func (s *MigrateSuite) TestDryRunNoProblems(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals,
+		"Migration of model \"model\" to controller \"target\" would be attempted; no problems found\n")
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(s.api.specSeen, gc.IsNil) // No migration should have been started.
+	c.Check(s.api.precheckSeen, jc.DeepEquals, &controller.MigrationSpec{
+		ModelUUID:             modelUUID,
+		TargetControllerUUID:  targetControllerUUID,
+		TargetControllerAlias: "target",
+		TargetAddrs:           []string{"1.2.3.4:5"},
+		TargetCACert:          "cert",
+		TargetUser:            "targetuser",
+		TargetPassword:        "secret",
+	})
+}
+
+func (s *MigrateSuite) TestDryRunReportsProblems(c *gc.C) {
+	s.api.precheckResult = controller.MigrationPrecheckResult{
+		Blockers: []coremigration.Blocker{{
+			Check:   "source",
+			Entity:  "machine 0",
+			Message: "machine 0 not running (stopped)",
+		}, {
+			Check:   "source",
+			Entity:  "unit foo/0",
+			Message: "unit foo/0 not idle or executing (failed)",
+		}, {
+			Check:   "import",
+			Entity:  "model",
+			Message: "trial import failed: boom",
+		}},
+		Warnings: []string{"target controller does not support trial imports"},
+	}
+	ctx, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Assert(err, gc.ErrorMatches,
+		`migration of model "model" to controller "target" would fail: 3 problem\(s\) found`)
+
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Check   Entity      Problem
+source  machine 0   machine 0 not running (stopped)
+source  unit foo/0  unit foo/0 not idle or executing (failed)
+import  model       trial import failed: boom
+`[1:])
+	c.Check(c.GetTestLog(), gc.Matches, "(?s).*WARNING cmd target controller does not support trial imports.*")
+	c.Check(s.api.specSeen, gc.IsNil)
+}
+
+func (s *MigrateSuite) TestDryRunReportsUserProblems(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "--dry-run", "model-with-extra-local-users", "target")
+	c.Assert(err, gc.ErrorMatches, `.* would fail: 1 problem\(s\) found`)
+
+	c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s)Check .*\nusers +model +cannot initiate migration as the users granted access .* - foo\n`)
+	c.Check(s.api.specSeen, gc.IsNil)
+}
+
 func (s *MigrateSuite) makeAndRun(c *gc.C, args ...string) (*cmd.Context, error) {
 	return cmdtesting.RunCommand(c, s.makeCommand(), args...)
 }
@@ -439,8 +498,10 @@ func (s *MigrateSuite) makeCommand() modelcmd.ModelCommand {
 }
 
 type fakeMigrateAPI struct {
-	specSeen    *controller.MigrationSpec
-	identityURL string
+	specSeen       *controller.MigrationSpec
+	precheckSeen   *controller.MigrationSpec
+	identityURL    string
+	precheckResult controller.MigrationPrecheckResult
 }
 
 func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (string, error) {
@@ -448,6 +509,11 @@ func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (strin
 	return "uuid:0", nil
 }
 
+func (a *fakeMigrateAPI) PrecheckMigration(spec controller.MigrationSpec) (controller.MigrationPrecheckResult, error) {
+	a.precheckSeen = &spec
+	return a.precheckResult, nil
+}
+
 func (a *fakeMigrateAPI) IdentityProviderURL() (string, error) {
 	return a.identityURL, nil
 }
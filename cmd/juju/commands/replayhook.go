@@ -0,0 +1,139 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"os"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/worker/uniter/runner/capture"
+)
+
+const replayHookDoc = `
+Replays a hook captured on a unit against a local copy of the charm, so
+that hook failures can be reproduced and debugged outside of a running
+model.
+
+Hooks are captured by a unit agent when a file named "capture-hooks"
+exists in the agent's directory. If the file names any hooks, separated
+by whitespace, only those hooks are captured. Each captured hook is
+written to a bundle in the agent's "hook-captures" directory, holding
+the hook's environment, the charm config, relation data and secret
+metadata visible to the hook, and every call the hook made to a hook
+tool, with the tool's response. The 20 most recent bundles are kept.
+
+The hook is replayed with the recorded environment, and with each hook
+tool replaced by a stub which responds as the real tool did when the
+hook was captured. The output of credential-get and secret-get, and the
+arguments and input of secret-add and secret-set, are not recorded. If
+the charm calls a tool with different arguments than were recorded, a
+warning is written to stderr; a call beyond those recorded fails.
+
+Bundles hold relation data and charm config, so are only readable by
+root on the unit, and should be treated as sensitive.
+
+The command exits with the exit code of the hook.
+`
+
+const replayHookExamples = `
+Capture the config-changed hook of mysql/0:
+
+    juju exec --unit mysql/0 -- 'echo config-changed > /var/lib/juju/agents/unit-mysql-0/capture-hooks'
+
+List and fetch the captured hooks:
+
+    juju exec --unit mysql/0 -- ls /var/lib/juju/agents/unit-mysql-0/hook-captures
+    juju exec --unit mysql/0 -- cat /var/lib/juju/agents/unit-mysql-0/hook-captures/20230601-123000.000000-config-changed.yaml > config-changed.yaml
+
+Replay the hook against the charm in the current directory:
+
+    juju replay-hook config-changed.yaml
+
+Replay the hook against a charm checkout elsewhere:
+
+    juju replay-hook config-changed.yaml ~/src/mysql-operator
+
+Stop capturing hooks:
+
+    juju exec --unit mysql/0 -- rm /var/lib/juju/agents/unit-mysql-0/capture-hooks
+`
+
+func newReplayHookCommand() cmd.Command {
+	return &replayHookCommand{}
+}
+
+// replayHookCommand replays a captured hook against a local charm.
+type replayHookCommand struct {
+	cmd.CommandBase
+	bundlePath string
+	charmDir   string
+}
+
+func (c *replayHookCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "replay-hook",
+		Args:     "<bundle> [<charm directory>]",
+		Purpose:  "Replays a captured hook against a local charm.",
+		Doc:      replayHookDoc,
+		Examples: replayHookExamples,
+		SeeAlso: []string{
+			"debug-hooks",
+			"debug-code",
+			"exec",
+		},
+	})
+}
+
+func (c *replayHookCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no hook bundle specified")
+	}
+	c.bundlePath, args = args[0], args[1:]
+	c.charmDir = "."
+	if len(args) > 0 {
+		c.charmDir, args = args[0], args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *replayHookCommand) Run(ctx *cmd.Context) error {
+	bundle, err := capture.ReadBundle(ctx.AbsPath(c.bundlePath))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	workDir, err := os.MkdirTemp("", "juju-replay-hook-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	ctx.Infof("replaying %s hook of %s, captured at %s",
+		bundle.Hook, bundle.Unit, bundle.Started.Format("2006-01-02 15:04:05Z"))
+	result, err := capture.Replay(capture.ReplayParams{
+		Bundle:   bundle,
+		CharmDir: ctx.AbsPath(c.charmDir),
+		WorkDir:  workDir,
+		Env:      os.Environ(),
+		Stdout:   ctx.Stdout,
+		Stderr:   ctx.Stderr,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	ctx.Infof("replayed %d of %d recorded hook tool calls", result.Replayed, result.Recorded)
+	if result.Unrecorded > 0 {
+		ctx.Infof("%d hook tool calls were not recorded", result.Unrecorded)
+	}
+	if bundle.Error != "" {
+		ctx.Infof("captured hook failed: %s", bundle.Error)
+	}
+	if result.Code != 0 {
+		return cmd.NewRcPassthroughError(result.Code)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/capture"
+)
+
+type ReplayHookSuite struct {
+	testing.IsolationSuite
+
+	charmDir   string
+	bundlePath string
+}
+
+var _ = gc.Suite(&ReplayHookSuite{})
+
+func (s *ReplayHookSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.PatchEnvironment("PATH", "/usr/bin:/bin")
+
+	s.charmDir = c.MkDir()
+	err := os.WriteFile(filepath.Join(s.charmDir, "metadata.yaml"), []byte("name: wordpress\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.MkdirAll(filepath.Join(s.charmDir, "hooks"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	hook := "#!/bin/sh\necho \"port $(config-get port)\"\nexit $(config-get code)\n"
+	err = os.WriteFile(filepath.Join(s.charmDir, "hooks", "config-changed"), []byte(hook), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.bundlePath, err = capture.WriteBundle(c.MkDir(), capture.Bundle{
+		Version: 1,
+		Unit:    "wordpress/0",
+		Hook:    "config-changed",
+		Started: time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC),
+		ToolCalls: []capture.ToolCall{{
+			Command: "config-get",
+			Args:    []string{"port"},
+			Stdout:  "8080\n",
+		}, {
+			Command: "config-get",
+			Args:    []string{"code"},
+			Stdout:  "3\n",
+		}},
+		Error: "exit status 3",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ReplayHookSuite) TestInitNoBundle(c *gc.C) {
+	err := cmdtesting.InitCommand(newReplayHookCommand(), nil)
+	c.Assert(err, gc.ErrorMatches, "no hook bundle specified")
+}
+
+func (s *ReplayHookSuite) TestInitTooManyArgs(c *gc.C) {
+	err := cmdtesting.InitCommand(newReplayHookCommand(), []string{"bundle.yaml", "charm", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *ReplayHookSuite) TestInitDefaultCharmDir(c *gc.C) {
+	command := &replayHookCommand{}
+	err := cmdtesting.InitCommand(command, []string{"bundle.yaml"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command.bundlePath, gc.Equals, "bundle.yaml")
+	c.Assert(command.charmDir, gc.Equals, ".")
+}
+
+func (s *ReplayHookSuite) TestRun(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, newReplayHookCommand(), s.bundlePath, s.charmDir)
+	c.Assert(err, gc.FitsTypeOf, &cmd.RcPassthroughError{})
+	c.Assert(err.(*cmd.RcPassthroughError).Code, gc.Equals, 3)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "port 8080\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"replaying config-changed hook of wordpress/0, captured at 2023-06-01 12:30:00Z\n"+
+		"replayed 2 of 2 recorded hook tool calls\n"+
+		"captured hook failed: exit status 3\n")
+}
+
+func (s *ReplayHookSuite) TestRunMissingBundle(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, newReplayHookCommand(), filepath.Join(c.MkDir(), "missing.yaml"), s.charmDir)
+	c.Assert(err, gc.ErrorMatches, ".*no such file or directory")
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/trace"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/observability/tracing"
+	"github.com/juju/juju/observability/tracing/otlp"
+)
+
+// startTracing records the command as the root span of a trace, if a
+// tracing endpoint is set in the environment. The returned context
+// carries the span, so that API calls made by the command are part of
+// its trace. The returned func ends the span and waits for it to be
+// exported; it must be called before the process exits.
+func startTracing(ctx *cmd.Context, args []string) (*cmd.Context, func(exitCode int)) {
+	noop := func(int) {}
+	endpoint := os.Getenv(osenv.JujuTracingEndpointEnvKey)
+	if endpoint == "" {
+		return ctx, noop
+	}
+
+	tracer, err := newCommandTracer(endpoint)
+	if err != nil {
+		logger.Warningf("tracing disabled: %v", err)
+		return ctx, noop
+	}
+
+	spanCtx, span := tracer.Start(ctx, commandSpanName(args),
+		trace.WithAttributes(trace.StringAttr("juju.args", strings.Join(args[1:], " "))),
+	)
+	return ctx.With(spanCtx), func(exitCode int) {
+		span.End(trace.IntAttr("juju.exit-code", exitCode))
+		tracer.Kill()
+		if err := tracer.Wait(); err != nil {
+			logger.Debugf("stopping tracer: %v", err)
+		}
+	}
+}
+
+func newCommandTracer(endpoint string) (*tracing.Tracer, error) {
+	var insecure bool
+	if value := os.Getenv(osenv.JujuTracingInsecureEnvKey); value != "" {
+		var err error
+		if insecure, err = strconv.ParseBool(value); err != nil {
+			return nil, errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuTracingInsecureEnvKey)
+		}
+	}
+	exporter, err := otlp.NewExporter(otlp.Config{
+		Endpoint: endpoint,
+		Protocol: os.Getenv(osenv.JujuTracingProtocolEnvKey),
+		Insecure: insecure,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Tracing a command is opted into explicitly, so every command
+	// is recorded.
+	tracer, err := tracing.NewTracer(tracing.Config{
+		SampleRatio: 1,
+		Exporter:    exporter,
+		ServiceName: "juju",
+		Clock:       clock.WallClock,
+		Logger:      logger,
+	})
+	if err != nil {
+		_ = exporter.Shutdown(context.Background())
+		return nil, errors.Trace(err)
+	}
+	return tracer, nil
+}
+
+// commandSpanName returns the name of the span recording the command,
+// made up of the command name and the first argument which isn't a
+// flag, for example "juju deploy".
+func commandSpanName(args []string) string {
+	for _, arg := range args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			return "juju " + arg
+		}
+	}
+	return "juju"
+}
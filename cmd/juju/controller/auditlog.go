@@ -0,0 +1,246 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/client/auditlog"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/rpc/params"
+)
+
+const defaultAuditLogLimit = 100
+
+var usageAuditLogSummary = `
+Shows the API requests recorded in the controller's audit log.`[1:]
+
+var usageAuditLogDetails = `
+When auditing is enabled (the "auditing-enabled" controller config
+setting) the controller records the API requests made by users, along
+with the command that made them and any errors returned. The records are
+kept in the controller database for "audit-log-max-age", and may also be
+forwarded to a syslog sink with the other controller logs by setting
+"audit-log-forwarding".
+
+The records can be filtered by the user who made the requests, the model
+they were made against, the API method called (either <facade>.<method>,
+or just <facade> for all of its methods) and how long ago they were
+made. --since accepts either a duration, such as "2h", or a time, such
+as "2023-05-01" or "2023-05-01T10:00:00Z".
+
+The most recent records are shown, oldest first, up to --limit (0 shows
+all of them). Times are shown in UTC.
+
+Only controller superusers may read the audit log.
+
+`[1:]
+
+const usageAuditLogExamples = `
+    juju audit-log
+    juju audit-log --user bob --since 24h
+    juju audit-log --model default --method Application.Deploy
+    juju audit-log --method Client --limit 0 --format yaml
+`
+
+// AuditLogAPI defines the API methods used by the audit-log command.
+type AuditLogAPI interface {
+	Records(params.AuditLogFilter) ([]params.AuditLogRecord, error)
+	Close() error
+}
+
+// NewAuditLogCommand returns a command to show the audit log.
+func NewAuditLogCommand() cmd.Command {
+	return modelcmd.WrapController(&auditLogCommand{clock: clock.WallClock})
+}
+
+// auditLogCommand shows the records in the controller's audit log.
+type auditLogCommand struct {
+	modelcmd.ControllerCommandBase
+	out   cmd.Output
+	api   AuditLogAPI
+	clock clock.Clock
+
+	user   string
+	model  string
+	method string
+	since  string
+	limit  int
+}
+
+// Info implements Command.Info.
+func (c *auditLogCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "audit-log",
+		Purpose:  usageAuditLogSummary,
+		Doc:      usageAuditLogDetails,
+		Examples: usageAuditLogExamples,
+		SeeAlso: []string{
+			"controller-config",
+			"debug-log",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *auditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.user, "user", "", "Only show requests made by this user")
+	f.StringVar(&c.model, "model", "", "Only show requests made against this model")
+	f.StringVar(&c.method, "method", "", "Only show requests to this <facade>.<method>, or <facade>")
+	f.StringVar(&c.since, "since", "", "Only show requests made since this duration ago, or time")
+	f.IntVar(&c.limit, "limit", defaultAuditLogLimit, "The maximum number of records to show (0 for all)")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAuditLogTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *auditLogCommand) Init(args []string) error {
+	if c.limit < 0 {
+		return errors.NotValidf("negative limit")
+	}
+	if c.since != "" {
+		if _, err := c.parseSince(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+var sinceLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func (c *auditLogCommand) parseSince() (time.Time, error) {
+	if d, err := time.ParseDuration(c.since); err == nil {
+		if d < 0 {
+			return time.Time{}, errors.NotValidf("negative --since duration")
+		}
+		return c.clock.Now().Add(-d).UTC(), nil
+	}
+	for _, layout := range sinceLayouts {
+		if t, err := time.Parse(layout, c.since); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.NotValidf("--since %q: expected a duration or time", c.since)
+}
+
+func (c *auditLogCommand) getAPI() (AuditLogAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return auditlog.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *auditLogCommand) Run(ctx *cmd.Context) error {
+	filter := params.AuditLogFilter{
+		User:   c.user,
+		Method: c.method,
+		Limit:  c.limit,
+	}
+	if c.since != "" {
+		since, err := c.parseSince()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		filter.Since = &since
+	}
+	if c.model != "" {
+		uuids, err := c.ModelUUIDs([]string{c.model})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		filter.ModelUUID = uuids[0]
+	}
+
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	records, err := api.Records(filter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(records) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No audit log records found.")
+		return nil
+	}
+	result := make([]auditLogRecord, len(records))
+	for i, r := range records {
+		result[i] = auditLogRecord{
+			When:           r.When.UTC().Format(time.RFC3339),
+			User:           r.Who,
+			Model:          r.ModelName,
+			ModelUUID:      r.ModelUUID,
+			Command:        r.What,
+			Method:         fmt.Sprintf("%s.%s", r.Facade, r.Method),
+			Version:        r.Version,
+			Args:           r.Args,
+			ConversationID: r.ConversationID,
+			RequestID:      r.RequestID,
+		}
+		for _, e := range r.Errors {
+			result[i].Errors = append(result[i].Errors, e.Message)
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+// auditLogRecord defines the serialization behaviour of an audit log
+// record.
+type auditLogRecord struct {
+	When           string   `yaml:"when" json:"when"`
+	User           string   `yaml:"user" json:"user"`
+	Model          string   `yaml:"model,omitempty" json:"model,omitempty"`
+	ModelUUID      string   `yaml:"model-uuid,omitempty" json:"model-uuid,omitempty"`
+	Command        string   `yaml:"command,omitempty" json:"command,omitempty"`
+	Method         string   `yaml:"method" json:"method"`
+	Version        int      `yaml:"version" json:"version"`
+	Args           string   `yaml:"args,omitempty" json:"args,omitempty"`
+	Errors         []string `yaml:"errors,omitempty" json:"errors,omitempty"`
+	ConversationID string   `yaml:"conversation-id" json:"conversation-id"`
+	RequestID      uint64   `yaml:"request-id" json:"request-id"`
+}
+
+func formatAuditLogTabular(writer io.Writer, value interface{}) error {
+	records, valueConverted := value.([]auditLogRecord)
+	if !valueConverted {
+		return errors.Errorf("expected value of type %T, got %T", records, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("Time", "User", "Model", "Command", "Method", "Result")
+	for _, r := range records {
+		result := "ok"
+		if len(r.Errors) > 0 {
+			result = r.Errors[0]
+		}
+		w.Println(r.When, r.User, r.Model, r.Command, r.Method, result)
+	}
+	tw.Flush()
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/cmd/juju/controller"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/rpc/params"
+)
+
+const auditModelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+type auditLogSuite struct {
+	baseControllerSuite
+	api   *fakeAuditLogAPI
+	clock *testclock.Clock
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+
+	s.clock = testclock.NewClock(time.Date(2023, 5, 2, 10, 0, 0, 0, time.UTC))
+	s.api = &fakeAuditLogAPI{}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["fake"] = jujuclient.AccountDetails{User: "admin"}
+	s.store.Models["fake"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/default": {ModelUUID: auditModelUUID, ModelType: coremodel.IAAS},
+		},
+	}
+}
+
+func (s *auditLogSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := controller.NewAuditLogCommandForTest(s.api, s.clock, s.store)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *auditLogSuite) records() []params.AuditLogRecord {
+	when := time.Date(2023, 5, 2, 9, 30, 0, 0, time.UTC)
+	return []params.AuditLogRecord{{
+		ConversationID: "0123456789abcdef",
+		ConnectionID:   "2AF",
+		Who:            "bob",
+		What:           "juju status",
+		ModelName:      "admin/default",
+		ModelUUID:      auditModelUUID,
+		RequestID:      1,
+		When:           when,
+		Facade:         "Client",
+		Method:         "FullStatus",
+		Version:        6,
+	}, {
+		ConversationID: "0123456789abcdef",
+		ConnectionID:   "2AF",
+		Who:            "bob",
+		What:           "juju status",
+		ModelName:      "admin/default",
+		ModelUUID:      auditModelUUID,
+		RequestID:      2,
+		When:           when.Add(time.Second),
+		Facade:         "Application",
+		Method:         "Get",
+		Version:        19,
+		Errors:         []params.AuditLogError{{Message: `application "foo" not found`, Code: "not found"}},
+	}}
+}
+
+func (s *auditLogSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		args:     []string{"extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}, {
+		args:     []string{"--limit", "-1"},
+		errMatch: "negative limit not valid",
+	}, {
+		args:     []string{"--since", "yesterday"},
+		errMatch: `--since "yesterday": expected a duration or time not valid`,
+	}, {
+		args:     []string{"--since", "-1h"},
+		errMatch: "negative --since duration not valid",
+	}} {
+		c.Logf("test %d: %q", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+	s.api.CheckNoCalls(c)
+}
+
+func (s *auditLogSuite) TestDefaults(c *gc.C) {
+	s.api.records = s.records()
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []testing.StubCall{
+		{FuncName: "Records", Args: []interface{}{params.AuditLogFilter{Limit: 100}}},
+		{FuncName: "Close"},
+	})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Time                  User  Model          Command      Method             Result
+2023-05-02T09:30:00Z  bob   admin/default  juju status  Client.FullStatus  ok
+2023-05-02T09:30:01Z  bob   admin/default  juju status  Application.Get    application "foo" not found
+`[1:])
+}
+
+func (s *auditLogSuite) TestFilters(c *gc.C) {
+	_, err := s.run(c,
+		"--user", "bob",
+		"--model", "default",
+		"--method", "Client.FullStatus",
+		"--since", "2h",
+		"--limit", "0",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	since := time.Date(2023, 5, 2, 8, 0, 0, 0, time.UTC)
+	s.api.CheckCall(c, 0, "Records", params.AuditLogFilter{
+		User:      "bob",
+		ModelUUID: auditModelUUID,
+		Method:    "Client.FullStatus",
+		Since:     &since,
+	})
+}
+
+func (s *auditLogSuite) TestSinceTime(c *gc.C) {
+	_, err := s.run(c, "--since", "2023-05-01")
+	c.Assert(err, jc.ErrorIsNil)
+	since := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	s.api.CheckCall(c, 0, "Records", params.AuditLogFilter{
+		Since: &since,
+		Limit: 100,
+	})
+}
+
+func (s *auditLogSuite) TestYAML(c *gc.C) {
+	s.api.records = s.records()[1:]
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- when: "2023-05-02T09:30:01Z"
+  user: bob
+  model: admin/default
+  model-uuid: deadbeef-0bad-400d-8000-4b1d0d06f00d
+  command: juju status
+  method: Application.Get
+  version: 19
+  errors:
+  - application "foo" not found
+  conversation-id: 0123456789abcdef
+  request-id: 2
+`[1:])
+}
+
+func (s *auditLogSuite) TestNoRecords(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No audit log records found.\n")
+}
+
+func (s *auditLogSuite) TestPermissionDenied(c *gc.C) {
+	s.api.SetErrors(apiservererrors.ErrPerm)
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type fakeAuditLogAPI struct {
+	testing.Stub
+	records []params.AuditLogRecord
+}
+
+func (f *fakeAuditLogAPI) Records(filter params.AuditLogFilter) ([]params.AuditLogRecord, error) {
+	f.MethodCall(f, "Records", filter)
+	return f.records, f.NextErr()
+}
+
+func (f *fakeAuditLogAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
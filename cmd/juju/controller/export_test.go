@@ -183,3 +183,34 @@ func NewData(api destroyControllerAPI, ctrUUID string) (environmentStatus, error
 var (
 	NoModelsMessage = noModelsMessage
 )
+
+// NewImportModelCommandForTest returns an importModelCommand with the api
+// provided as specified.
+func NewImportModelCommandForTest(api ImportModelAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &importModelCommand{
+		newAPIFunc: func() (ImportModelAPI, error) {
+			return api, nil
+		},
+	}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewAuditLogCommandForTest returns an auditLogCommand with the api
+// and clock provided as specified.
+func NewAuditLogCommandForTest(api AuditLogAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	c := &auditLogCommand{
+		api:   api,
+		clock: clock,
+	}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewSetJumpHostsCommandForTest returns a setJumpHostsCommand with the
+// client store provided.
+func NewSetJumpHostsCommandForTest(store jujuclient.ClientStore) cmd.Command {
+	c := &setJumpHostsCommand{}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
@@ -0,0 +1,310 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+	"github.com/juju/version/v2"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/controller/migrationtarget"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/resources"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/migration/modelarchive"
+	"github.com/juju/juju/tools"
+)
+
+// NewImportModelCommand returns a command to import a model archive
+// written by export-model into a controller.
+func NewImportModelCommand() cmd.Command {
+	command := &importModelCommand{}
+	command.newAPIFunc = func() (ImportModelAPI, error) {
+		root, err := command.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &importModelClient{
+			Client: migrationtarget.NewClient(root),
+			root:   root,
+		}, nil
+	}
+	return modelcmd.WrapController(command)
+}
+
+type importModelCommand struct {
+	modelcmd.ControllerCommandBase
+
+	newAPIFunc func() (ImportModelAPI, error)
+
+	filename   string
+	trustedKey string
+	skipVerify bool
+}
+
+const importModelHelpDoc = `
+Imports a model archive written by the export-model command into the
+controller. The model is created with the same name, owner and UUID it
+had on the controller it was exported from, and the charms, resources and
+agent binaries held in the archive are uploaded to this controller.
+
+The archive must have been signed with the public key named by
+--trusted-key. An archive carries the key it was signed with, so its
+signature alone proves nothing about who wrote it. To import an archive
+without checking who signed it, pass --skip-verify instead; the signing
+key's fingerprint is then reported so it can be checked by hand.
+
+Importing a model does not move the machines or units in it. Their agents
+still point at the controller the model was exported from, and need to
+be reconfigured before this controller can manage them. The original
+model is left in place on its controller.
+`
+
+const importModelHelpExamples = `
+    juju import-model --trusted-key ~/.ssh/id_ed25519.pub prod.jmodel
+    juju import-model -c target --skip-verify prod.jmodel
+`
+
+// Info implements Command.
+func (c *importModelCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "import-model",
+		Args:     "<file>",
+		Purpose:  "Imports a model archive into a controller.",
+		Doc:      importModelHelpDoc,
+		Examples: importModelHelpExamples,
+		SeeAlso: []string{
+			"export-model",
+			"migrate",
+		},
+	})
+}
+
+// SetFlags implements Command.
+func (c *importModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.trustedKey, "trusted-key", "", "SSH public key file the archive must be signed with")
+	f.BoolVar(&c.skipVerify, "skip-verify", false, "Import the archive without checking who signed it")
+}
+
+// Init implements Command.
+func (c *importModelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no archive file specified")
+	}
+	c.filename, args = args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	switch {
+	case c.trustedKey == "" && !c.skipVerify:
+		return errors.New("--trusted-key is required to verify the archive's signer, or pass --skip-verify")
+	case c.trustedKey != "" && c.skipVerify:
+		return errors.New("cannot specify both --trusted-key and --skip-verify")
+	}
+	return nil
+}
+
+// ImportModelAPI specifies the migration target API used to import a
+// model archive.
+type ImportModelAPI interface {
+	Close() error
+	Prechecks(coremigration.ModelInfo) error
+	Import([]byte) error
+	UploadCharm(modelUUID string, curl *charm.URL, content io.ReadSeeker) (*charm.URL, error)
+	UploadTools(modelUUID string, r io.ReadSeeker, vers version.Binary) (tools.List, error)
+	UploadResource(modelUUID string, res resources.Resource, r io.ReadSeeker) error
+	SetPlaceholderResource(modelUUID string, res resources.Resource) error
+	SetUnitResource(modelUUID, unit string, res resources.Resource) error
+	Activate(modelUUID string, sourceInfo coremigration.SourceControllerInfo, relatedModels []string) error
+	Abort(modelUUID string) error
+}
+
+type importModelClient struct {
+	*migrationtarget.Client
+	root api.Connection
+}
+
+// Close implements ImportModelAPI.
+func (c *importModelClient) Close() error {
+	return c.root.Close()
+}
+
+// Run implements Command.
+func (c *importModelCommand) Run(ctx *cmd.Context) error {
+	trusted, err := c.readTrustedKey()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	archiveFile, err := os.Open(c.filename)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archiveFile.Close()
+	archive, err := modelarchive.Open(archiveFile, trusted)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	manifest := archive.Manifest()
+	if c.skipVerify {
+		ctx.Warningf("archive signer not verified; it was signed by %s",
+			ssh.FingerprintSHA256(archive.Signer()))
+	}
+	serialized, err := archive.SerializedModel()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	modelInfo, err := manifestModelInfo(manifest)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.Prechecks(modelInfo); err != nil {
+		return errors.Annotate(err, "target controller cannot accept model")
+	}
+	ctx.Infof("Importing model %q", modelInfo.Name)
+	if err := client.Import(serialized.Bytes); err != nil {
+		return errors.Annotate(err, "importing model")
+	}
+	if err := c.transferModel(client, archive, serialized, modelInfo.UUID); err != nil {
+		if abortErr := client.Abort(modelInfo.UUID); abortErr != nil {
+			logger.Errorf("aborting import of model %q: %v", modelInfo.UUID, abortErr)
+		}
+		return errors.Trace(err)
+	}
+
+	store := c.ClientStore()
+	qualifiedName := jujuclient.JoinOwnerModelName(modelInfo.Owner, modelInfo.Name)
+	err = store.UpdateModel(controllerName, qualifiedName, jujuclient.ModelDetails{
+		ModelUUID: modelInfo.UUID,
+		ModelType: model.ModelType(manifest.ModelType),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	fmt.Fprintf(ctx.Stdout, "Imported model %q into controller %q\n", qualifiedName, controllerName)
+	return nil
+}
+
+func (c *importModelCommand) transferModel(
+	client ImportModelAPI,
+	archive *modelarchive.Archive,
+	serialized coremigration.SerializedModel,
+	modelUUID string,
+) error {
+	uploader := &uploadWrapper{client: client, modelUUID: modelUUID}
+	err := migration.UploadBinaries(migration.UploadBinariesConfig{
+		Charms:             serialized.Charms,
+		CharmDownloader:    archive,
+		CharmUploader:      uploader,
+		Tools:              serialized.Tools,
+		ToolsDownloader:    archive,
+		ToolsUploader:      uploader,
+		Resources:          serialized.Resources,
+		ResourceDownloader: archive,
+		ResourceUploader:   uploader,
+	})
+	if err != nil {
+		return errors.Annotate(err, "uploading model binaries")
+	}
+	// There is no source controller to record: the model was carried
+	// across by hand, so nothing can have been related to it.
+	if err := client.Activate(modelUUID, coremigration.SourceControllerInfo{}, nil); err != nil {
+		return errors.Annotate(err, "activating model")
+	}
+	return nil
+}
+
+func (c *importModelCommand) readTrustedKey() (ssh.PublicKey, error) {
+	if c.trustedKey == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(c.trustedKey)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading trusted key")
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "parsing trusted key %q", c.trustedKey)
+	}
+	return key, nil
+}
+
+func manifestModelInfo(manifest modelarchive.Manifest) (coremigration.ModelInfo, error) {
+	if !names.IsValidUser(manifest.ModelOwner) {
+		return coremigration.ModelInfo{}, errors.NotValidf("model owner %q", manifest.ModelOwner)
+	}
+	agentVersion, err := version.Parse(manifest.AgentVersion)
+	if err != nil {
+		return coremigration.ModelInfo{}, errors.Annotate(err, "parsing model agent version")
+	}
+	return coremigration.ModelInfo{
+		UUID:         manifest.ModelUUID,
+		Owner:        names.NewUserTag(manifest.ModelOwner),
+		Name:         manifest.ModelName,
+		AgentVersion: agentVersion,
+		// The source controller's version isn't recorded, but it
+		// can't have been older than the model it hosted.
+		ControllerAgentVersion: agentVersion,
+	}, nil
+}
+
+// uploadWrapper adds the model UUID to the upload calls made by
+// migration.UploadBinaries.
+type uploadWrapper struct {
+	client    ImportModelAPI
+	modelUUID string
+}
+
+// UploadTools implements migration.ToolsUploader.
+func (w *uploadWrapper) UploadTools(r io.ReadSeeker, vers version.Binary) (tools.List, error) {
+	return w.client.UploadTools(w.modelUUID, r, vers)
+}
+
+// UploadCharm implements migration.CharmUploader.
+func (w *uploadWrapper) UploadCharm(curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	return w.client.UploadCharm(w.modelUUID, curl, content)
+}
+
+// UploadResource implements migration.ResourceUploader.
+func (w *uploadWrapper) UploadResource(res resources.Resource, content io.ReadSeeker) error {
+	return w.client.UploadResource(w.modelUUID, res, content)
+}
+
+// SetPlaceholderResource implements migration.ResourceUploader.
+func (w *uploadWrapper) SetPlaceholderResource(res resources.Resource) error {
+	return w.client.SetPlaceholderResource(w.modelUUID, res)
+}
+
+// SetUnitResource implements migration.ResourceUploader.
+func (w *uploadWrapper) SetUnitResource(unitName string, res resources.Resource) error {
+	return w.client.SetUnitResource(w.modelUUID, unitName, res)
+}
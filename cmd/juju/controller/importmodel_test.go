@@ -0,0 +1,253 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/description/v4"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/controller"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/resources"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/migration/modelarchive"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+)
+
+type importModelSuite struct {
+	baseControllerSuite
+	api      *fakeImportModelAPI
+	store    *jujuclient.MemStore
+	signer   ssh.Signer
+	filename string
+}
+
+var _ = gc.Suite(&importModelSuite{})
+
+const importModelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+func (s *importModelSuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+
+	s.api = &fakeImportModelAPI{}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	s.signer, err = ssh.NewSignerFromKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+
+	descModel := description.NewModel(description.ModelArgs{
+		Type:  string(model.IAAS),
+		Owner: names.NewUserTag("bob"),
+		Config: map[string]interface{}{
+			"name":          "prod",
+			"uuid":          importModelUUID,
+			"agent-version": "2.9.42",
+		},
+	})
+	descModel.SetStatus(description.StatusArgs{Value: "available"})
+	modelBytes, err := description.Serialize(descModel)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var buf bytes.Buffer
+	err = modelarchive.Write(&buf, modelarchive.WriteConfig{
+		Model: coremigration.SerializedModel{
+			Bytes:  modelBytes,
+			Charms: []string{"ch:foo-1"},
+			Tools: map[version.Binary]string{
+				version.MustParseBinary("2.9.42-ubuntu-amd64"): "/tools/2.9.42-ubuntu-amd64",
+			},
+		},
+		SourceControllerUUID: coretesting.ControllerTag.Id(),
+		CharmDownloader:      fakeBinaries{},
+		ToolsDownloader:      fakeBinaries{},
+		ResourceDownloader:   fakeBinaries{},
+		Signer:               s.signer,
+		Clock:                testclock.NewClock(coretesting.ZeroTime()),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.filename = filepath.Join(c.MkDir(), "prod.jmodel")
+	err = os.WriteFile(s.filename, buf.Bytes(), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *importModelSuite) newCommand() cmd.Command {
+	return controller.NewImportModelCommandForTest(s.api, s.store)
+}
+
+func (s *importModelSuite) writeKey(c *gc.C, key ssh.PublicKey) string {
+	keyFile := filepath.Join(c.MkDir(), "key.pub")
+	err := os.WriteFile(keyFile, ssh.MarshalAuthorizedKey(key), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return keyFile
+}
+
+func (s *importModelSuite) TestInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, gc.ErrorMatches, "no archive file specified")
+	_, err = cmdtesting.RunCommand(c, s.newCommand(), "a", "b")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+	_, err = cmdtesting.RunCommand(c, s.newCommand(), "a")
+	c.Assert(err, gc.ErrorMatches, "--trusted-key is required to verify the archive's signer, or pass --skip-verify")
+	_, err = cmdtesting.RunCommand(c, s.newCommand(), "--trusted-key", "key.pub", "--skip-verify", "a")
+	c.Assert(err, gc.ErrorMatches, "cannot specify both --trusted-key and --skip-verify")
+}
+
+func (s *importModelSuite) TestImport(c *gc.C) {
+	keyFile := s.writeKey(c, s.signer.PublicKey())
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--trusted-key", keyFile, s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `Imported model "bob/prod" into controller "fake"`+"\n")
+	c.Check(c.GetTestLog(), gc.Not(jc.Contains), "archive signer not verified")
+
+	s.api.CheckCallNames(c, "Prechecks", "Import", "UploadCharm", "UploadTools", "Activate", "Close")
+	s.api.CheckCall(c, 0, "Prechecks", coremigration.ModelInfo{
+		UUID:                   importModelUUID,
+		Owner:                  names.NewUserTag("bob"),
+		Name:                   "prod",
+		AgentVersion:           version.MustParse("2.9.42"),
+		ControllerAgentVersion: version.MustParse("2.9.42"),
+	})
+	s.api.CheckCall(c, 2, "UploadCharm", importModelUUID, "ch:foo-1", "charm ch:foo-1")
+	s.api.CheckCall(c, 3, "UploadTools", importModelUUID, "2.9.42-ubuntu-amd64", "tools /tools/2.9.42-ubuntu-amd64")
+	s.api.CheckCall(c, 4, "Activate", importModelUUID)
+
+	details, err := s.store.ModelByName("fake", "bob/prod")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details, jc.DeepEquals, &jujuclient.ModelDetails{
+		ModelUUID: importModelUUID,
+		ModelType: model.IAAS,
+	})
+}
+
+func (s *importModelSuite) TestImportSkipVerifyWarns(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "--skip-verify", s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(c.GetTestLog(), jc.Contains,
+		"archive signer not verified; it was signed by "+ssh.FingerprintSHA256(s.signer.PublicKey()))
+}
+
+func (s *importModelSuite) TestImportUntrustedKey(c *gc.C) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := ssh.NewSignerFromKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	keyFile := s.writeKey(c, other.PublicKey())
+
+	_, err = cmdtesting.RunCommand(c, s.newCommand(), "--trusted-key", keyFile, s.filename)
+	c.Assert(err, gc.ErrorMatches, "model archive signed by untrusted key SHA256:.*")
+	s.api.CheckNoCalls(c)
+}
+
+func (s *importModelSuite) TestImportPrecheckFails(c *gc.C) {
+	s.api.SetErrors(errors.New("model already exists"))
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "--skip-verify", s.filename)
+	c.Assert(err, gc.ErrorMatches, "target controller cannot accept model: model already exists")
+	s.api.CheckCallNames(c, "Prechecks", "Close")
+}
+
+func (s *importModelSuite) TestImportUploadFailsAborts(c *gc.C) {
+	s.api.SetErrors(nil, nil, errors.New("boom"))
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "--skip-verify", s.filename)
+	c.Assert(err, gc.ErrorMatches, "uploading model binaries: cannot upload charms: cannot upload charm: boom")
+	s.api.CheckCallNames(c, "Prechecks", "Import", "UploadCharm", "Abort", "Close")
+	s.api.CheckCall(c, 3, "Abort", importModelUUID)
+
+	_, err = s.store.ModelByName("fake", "bob/prod")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+type fakeImportModelAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeImportModelAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeImportModelAPI) Prechecks(info coremigration.ModelInfo) error {
+	f.MethodCall(f, "Prechecks", info)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelAPI) Import(bytes []byte) error {
+	f.MethodCall(f, "Import")
+	return f.NextErr()
+}
+
+func (f *fakeImportModelAPI) UploadCharm(modelUUID string, curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	data, _ := io.ReadAll(content)
+	f.MethodCall(f, "UploadCharm", modelUUID, curl.String(), string(data))
+	return curl, f.NextErr()
+}
+
+func (f *fakeImportModelAPI) UploadTools(modelUUID string, r io.ReadSeeker, vers version.Binary) (tools.List, error) {
+	data, _ := io.ReadAll(r)
+	f.MethodCall(f, "UploadTools", modelUUID, vers.String(), string(data))
+	return tools.List{&tools.Tools{Version: vers}}, f.NextErr()
+}
+
+func (f *fakeImportModelAPI) UploadResource(modelUUID string, res resources.Resource, r io.ReadSeeker) error {
+	f.MethodCall(f, "UploadResource", modelUUID, res.Name)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelAPI) SetPlaceholderResource(modelUUID string, res resources.Resource) error {
+	f.MethodCall(f, "SetPlaceholderResource", modelUUID, res.Name)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelAPI) SetUnitResource(modelUUID, unit string, res resources.Resource) error {
+	f.MethodCall(f, "SetUnitResource", modelUUID, unit, res.Name)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelAPI) Activate(modelUUID string, _ coremigration.SourceControllerInfo, _ []string) error {
+	f.MethodCall(f, "Activate", modelUUID)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelAPI) Abort(modelUUID string) error {
+	f.MethodCall(f, "Abort", modelUUID)
+	return f.NextErr()
+}
+
+type fakeBinaries struct{}
+
+func (fakeBinaries) OpenCharm(curl string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("charm " + curl)), nil
+}
+
+func (fakeBinaries) OpenURI(uri string, _ url.Values) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("tools " + uri)), nil
+}
+
+func (fakeBinaries) OpenResource(application, name string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("resource " + name)), nil
+}
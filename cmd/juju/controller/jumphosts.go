@@ -0,0 +1,172 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/v3"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	sshproxy "github.com/juju/juju/proxy/ssh"
+)
+
+var usageSetJumpHostsSummary = `
+Sets the SSH jump hosts through which a controller is reached.`[1:]
+
+var usageSetJumpHostsDetails = `
+Controllers in private networks may only be reachable through one or more
+SSH jump hosts, also known as bastions. This command records the jump hosts
+for a controller in the local client store, so that every juju command
+connects to the controller's API through them, and "juju ssh" and "juju scp"
+connect to machines through them, without any ProxyCommand configuration.
+
+Jump hosts are given in the order in which they are to be traversed, each in
+the form [user@]host[:port]. The last jump host must be able to reach the
+controller's API addresses.
+
+Jump hosts are authenticated with the key in the --identity-file, or else
+with the keys held by the SSH agent. Their host keys must be present in the
+--known-hosts-file, which defaults to ~/.ssh/known_hosts.
+
+Use --reset to connect to the controller directly once more.
+`[1:]
+
+const usageSetJumpHostsExamples = `
+    juju set-jump-hosts ubuntu@bastion.example.com
+    juju set-jump-hosts -c prod ubuntu@bastion.example.com admin@10.0.0.5 --identity-file ~/.ssh/bastion
+    juju set-jump-hosts -c prod --reset
+`
+
+// NewSetJumpHostsCommand returns a command to set the SSH jump hosts
+// for a controller.
+func NewSetJumpHostsCommand() cmd.Command {
+	return modelcmd.WrapController(&setJumpHostsCommand{})
+}
+
+// setJumpHostsCommand records SSH jump hosts for a controller in the
+// local client store.
+type setJumpHostsCommand struct {
+	modelcmd.ControllerCommandBase
+
+	jumpHosts      []string
+	identityFile   string
+	knownHostsFile string
+	reset          bool
+}
+
+// Info implements Command.Info.
+func (c *setJumpHostsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set-jump-hosts",
+		Args:     "[<[user@]host[:port]> ...]",
+		Purpose:  usageSetJumpHostsSummary,
+		Doc:      usageSetJumpHostsDetails,
+		Examples: usageSetJumpHostsExamples,
+		SeeAlso: []string{
+			"show-controller",
+			"ssh",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setJumpHostsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.identityFile, "identity-file", "", "Path to the private key used to authenticate with the jump hosts")
+	f.StringVar(&c.knownHostsFile, "known-hosts-file", "", "Path to the known_hosts file used to verify the jump hosts")
+	f.BoolVar(&c.reset, "reset", false, "Connect to the controller directly, without jump hosts")
+}
+
+// Init implements Command.Init.
+func (c *setJumpHostsCommand) Init(args []string) error {
+	if c.reset {
+		if len(args) > 0 || c.identityFile != "" || c.knownHostsFile != "" {
+			return errors.New("cannot specify jump hosts with --reset")
+		}
+		return nil
+	}
+	if len(args) == 0 {
+		return errors.New("no jump hosts specified")
+	}
+	c.jumpHosts = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setJumpHostsCommand) Run(ctx *cmd.Context) error {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store := c.ClientStore()
+	details, err := store.ControllerByName(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if details.Proxy != nil && details.Proxy.Proxier.Type() != sshproxy.ProxierTypeKey {
+		return errors.Errorf(
+			"controller %q is reached through a %q proxy, which cannot be replaced",
+			controllerName, details.Proxy.Proxier.Type(),
+		)
+	}
+
+	if c.reset {
+		if details.Proxy == nil {
+			return nil
+		}
+		details.Proxy = nil
+		if err := store.UpdateController(controllerName, *details); err != nil {
+			return errors.Trace(err)
+		}
+		ctx.Infof("controller %q is no longer reached through jump hosts", controllerName)
+		return nil
+	}
+
+	config := sshproxy.ProxierConfig{JumpHosts: c.jumpHosts}
+	if config.IdentityFile, err = absFilePath(c.identityFile); err != nil {
+		return errors.Annotate(err, "identity file")
+	}
+	if config.KnownHostsFile, err = absFilePath(c.knownHostsFile); err != nil {
+		return errors.Annotate(err, "known hosts file")
+	}
+	if err := config.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	details.Proxy = &jujuclient.ProxyConfWrapper{Proxier: sshproxy.NewProxier(config)}
+	if err := store.UpdateController(controllerName, *details); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("controller %q is reached through jump hosts %q", controllerName, c.jumpHosts)
+	return nil
+}
+
+// absFilePath returns the absolute form of the given path, checking
+// that it refers to an existing file. Empty paths are left empty.
+func absFilePath(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	path, err := utils.NormalizePath(path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return "", errors.Trace(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if info.IsDir() {
+		return "", errors.Errorf("%q is a directory", path)
+	}
+	return path, nil
+}
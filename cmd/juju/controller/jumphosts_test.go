@@ -0,0 +1,103 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jt "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/caas/kubernetes/provider/proxy"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/jujuclient"
+	sshproxy "github.com/juju/juju/proxy/ssh"
+)
+
+type SetJumpHostsSuite struct {
+	jt.IsolationSuite
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&SetJumpHostsSuite{})
+
+func (s *SetJumpHostsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "prod"
+	s.store.Controllers["prod"] = jujuclient.ControllerDetails{
+		ControllerUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		APIEndpoints:   []string{"10.0.0.10:17070"},
+	}
+}
+
+func (s *SetJumpHostsSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, controller.NewSetJumpHostsCommandForTest(s.store), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stderr(ctx), nil
+}
+
+func (s *SetJumpHostsSuite) TestSetJumpHosts(c *gc.C) {
+	identityFile := filepath.Join(c.MkDir(), "bastion")
+	err := os.WriteFile(identityFile, []byte("key"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stderr, err := s.run(c, "ubuntu@bastion", "admin@10.0.0.5:2222", "--identity-file", identityFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, `controller "prod" is reached through jump hosts ["ubuntu@bastion" "admin@10.0.0.5:2222"]`+"\n")
+
+	details := s.store.Controllers["prod"]
+	c.Assert(details.Proxy, gc.NotNil)
+	proxier, ok := details.Proxy.Proxier.(*sshproxy.Proxier)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(proxier.Config(), jc.DeepEquals, sshproxy.ProxierConfig{
+		JumpHosts:    []string{"ubuntu@bastion", "admin@10.0.0.5:2222"},
+		IdentityFile: identityFile,
+	})
+}
+
+func (s *SetJumpHostsSuite) TestSetJumpHostsMissingIdentityFile(c *gc.C) {
+	_, err := s.run(c, "ubuntu@bastion", "--identity-file", filepath.Join(c.MkDir(), "missing"))
+	c.Assert(err, gc.ErrorMatches, "identity file: stat .*: no such file or directory")
+	c.Assert(s.store.Controllers["prod"].Proxy, gc.IsNil)
+}
+
+func (s *SetJumpHostsSuite) TestSetJumpHostsInvalid(c *gc.C) {
+	_, err := s.run(c, "ubuntu@bastion:ssh")
+	c.Assert(err, gc.ErrorMatches, `jump host "ubuntu@bastion:ssh" port not valid`)
+}
+
+func (s *SetJumpHostsSuite) TestReset(c *gc.C) {
+	details := s.store.Controllers["prod"]
+	details.Proxy = &jujuclient.ProxyConfWrapper{Proxier: sshproxy.NewProxier(sshproxy.ProxierConfig{
+		JumpHosts: []string{"ubuntu@bastion"},
+	})}
+	s.store.Controllers["prod"] = details
+
+	stderr, err := s.run(c, "--reset")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, `controller "prod" is no longer reached through jump hosts`+"\n")
+	c.Assert(s.store.Controllers["prod"].Proxy, gc.IsNil)
+}
+
+func (s *SetJumpHostsSuite) TestOtherProxyNotReplaced(c *gc.C) {
+	details := s.store.Controllers["prod"]
+	details.Proxy = &jujuclient.ProxyConfWrapper{Proxier: proxy.NewProxier(proxy.ProxierConfig{})}
+	s.store.Controllers["prod"] = details
+
+	_, err := s.run(c, "ubuntu@bastion")
+	c.Assert(err, gc.ErrorMatches, `controller "prod" is reached through a "kubernetes-port-forward" proxy, which cannot be replaced`)
+}
+
+func (s *SetJumpHostsSuite) TestInitErrors(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no jump hosts specified")
+	_, err = s.run(c, "--reset", "ubuntu@bastion")
+	c.Assert(err, gc.ErrorMatches, "cannot specify jump hosts with --reset")
+}
@@ -12,11 +12,15 @@ import (
 
 func NewListRulesCommandForTest(
 	api ListFirewallRulesAPI,
+	egressAPI ListEgressRulesAPI,
 ) cmd.Command {
 	aCmd := &listFirewallRulesCommand{
 		newAPIFunc: func() (ListFirewallRulesAPI, error) {
 			return api, nil
 		},
+		newEgressAPIFunc: func() (ListEgressRulesAPI, error) {
+			return egressAPI, nil
+		},
 	}
 	aCmd.SetClientStore(jujuclienttesting.MinimalStore())
 	return modelcmd.Wrap(aCmd)
@@ -24,11 +28,15 @@ func NewListRulesCommandForTest(
 
 func NewSetRulesCommandForTest(
 	api SetFirewallRuleAPI,
+	egressAPI SetEgressRuleAPI,
 ) cmd.Command {
 	aCmd := &setFirewallRuleCommand{
 		newAPIFunc: func() (SetFirewallRuleAPI, error) {
 			return api, nil
 		},
+		newEgressAPIFunc: func() (SetEgressRuleAPI, error) {
+			return egressAPI, nil
+		},
 	}
 	aCmd.SetClientStore(jujuclienttesting.MinimalStore())
 	return modelcmd.Wrap(aCmd)
@@ -15,8 +15,10 @@ import (
 )
 
 type firewallRule struct {
-	KnownService   firewall.WellKnownServiceType `yaml:"known-service" json:"known-service"`
+	KnownService   firewall.WellKnownServiceType `yaml:"known-service,omitempty" json:"known-service,omitempty"`
 	WhitelistCIDRS []string                      `yaml:"allowlist-subnets,omitempty" json:"allowlist-subnets,omitempty"`
+	Application    string                        `yaml:"application,omitempty" json:"application,omitempty"`
+	Egress         []string                      `yaml:"egress,omitempty" json:"egress,omitempty"`
 }
 
 type firewallRules []firewallRule
@@ -24,7 +26,10 @@ type firewallRules []firewallRule
 func (o firewallRules) Len() int      { return len(o) }
 func (o firewallRules) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
 func (o firewallRules) Less(i, j int) bool {
-	return o[i].KnownService < o[j].KnownService
+	if o[i].KnownService != o[j].KnownService {
+		return o[i].KnownService < o[j].KnownService
+	}
+	return o[i].Application < o[j].Application
 }
 
 func formatListTabular(writer io.Writer, value interface{}) error {
@@ -43,9 +48,24 @@ func formatFirewallRulesTabular(writer io.Writer, rules firewallRules) {
 
 	sort.Sort(rules)
 
+	var egress firewallRules
 	w.Println("Service", "Allowlist subnets")
 	for _, rule := range rules {
+		if rule.Application != "" {
+			egress = append(egress, rule)
+			continue
+		}
 		w.Println(rule.KnownService, strings.Join(rule.WhitelistCIDRS, ","))
 	}
 	tw.Flush()
+
+	if len(egress) == 0 {
+		return
+	}
+	w.Println()
+	w.Println("Application", "Egress")
+	for _, rule := range egress {
+		w.Println(rule.Application, strings.Join(rule.Egress, ","))
+	}
+	tw.Flush()
 }
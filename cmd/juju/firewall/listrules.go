@@ -5,24 +5,34 @@ package firewall
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/juju/cmd/v3"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/loggo"
 
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/client/application"
+	apiclient "github.com/juju/juju/api/client/client"
 	"github.com/juju/juju/api/client/modelconfig"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/rpc/params"
 )
 
+var logger = loggo.GetLogger("juju.cmd.juju.firewall")
+
 var listRulesHelpSummary = `
 Prints the firewall rules.`[1:]
 
 var listRulesHelpDetails = `
 Lists the firewall rules which control ingress to well known services
-within a Juju model.
+within a Juju model, and the egress rules of any application whose
+outbound traffic is restricted.
 
 DEPRECATION WARNING: %v
 
@@ -44,6 +54,13 @@ func NewListFirewallRulesCommand() cmd.Command {
 		return modelconfig.NewClient(root), nil
 
 	}
+	cmd.newEgressAPIFunc = func() (ListEgressRulesAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return newEgressRulesClient(root), nil
+	}
 	return modelcmd.Wrap(cmd)
 }
 
@@ -52,7 +69,8 @@ type listFirewallRulesCommand struct {
 	modelcmd.IAASOnlyCommand
 	out cmd.Output
 
-	newAPIFunc func() (ListFirewallRulesAPI, error)
+	newAPIFunc       func() (ListFirewallRulesAPI, error)
+	newEgressAPIFunc func() (ListEgressRulesAPI, error)
 }
 
 // Info implements cmd.Command.
@@ -89,6 +107,33 @@ type ListFirewallRulesAPI interface {
 	ModelGet() (map[string]interface{}, error)
 }
 
+// ListEgressRulesAPI defines the API methods that the list firewall rules
+// command uses to read application egress rules.
+type ListEgressRulesAPI interface {
+	Close() error
+	Status(args *apiclient.StatusArgs) (*params.FullStatus, error)
+	Get(branchName, application string) (*params.ApplicationGetResults, error)
+}
+
+// egressRulesClient combines the client and application facades
+// needed to read application egress rules.
+type egressRulesClient struct {
+	*apiclient.Client
+	application *application.Client
+}
+
+func newEgressRulesClient(root api.Connection) *egressRulesClient {
+	return &egressRulesClient{
+		Client:      apiclient.NewClient(root, logger),
+		application: application.NewClient(root),
+	}
+}
+
+// Get implements ListEgressRulesAPI.
+func (c *egressRulesClient) Get(branchName, application string) (*params.ApplicationGetResults, error) {
+	return c.application.Get(branchName, application)
+}
+
 // Run implements cmd.Command.
 func (c *listFirewallRulesCommand) Run(ctx *cmd.Context) error {
 	ctx.Warningf(deprecationWarning + "\n")
@@ -114,5 +159,63 @@ func (c *listFirewallRulesCommand) Run(ctx *cmd.Context) error {
 		KnownService:   firewall.JujuApplicationOfferRule,
 		WhitelistCIDRS: cfg.SAASIngressAllow(),
 	}}
+
+	egressRules, err := c.applicationEgressRules()
+	if err != nil {
+		return err
+	}
+	rules = append(rules, egressRules...)
 	return c.out.Write(ctx, rules)
 }
+
+// applicationEgressRules returns a rule for each application in the
+// model which has an egress allow-list.
+func (c *listFirewallRulesCommand) applicationEgressRules() ([]firewallRule, error) {
+	client, err := c.newEgressAPIFunc()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	status, err := client.Status(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	appNames := make([]string, 0, len(status.Applications))
+	for name := range status.Applications {
+		appNames = append(appNames, name)
+	}
+	sort.Strings(appNames)
+
+	var rules []firewallRule
+	for _, name := range appNames {
+		results, err := client.Get(model.GenerationMaster, name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		egress, err := egressRulesFromConfig(results.ApplicationConfig)
+		if err != nil {
+			return nil, errors.Annotatef(err, "application %q", name)
+		}
+		if len(egress) == 0 {
+			continue
+		}
+		rule := firewallRule{Application: name}
+		for _, r := range egress {
+			rule.Egress = append(rule.Egress, r.String())
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// egressRulesFromConfig extracts the egress allow-list from the
+// described application config returned by the application facade.
+func egressRulesFromConfig(appConfig map[string]interface{}) (firewall.EgressRules, error) {
+	info, ok := appConfig[egressAllowKey].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	value, _ := info["value"].(string)
+	return firewall.ParseEgressRules(value)
+}
@@ -12,15 +12,18 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	apiclient "github.com/juju/juju/api/client/client"
 	"github.com/juju/juju/cmd/juju/firewall"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/testing"
 )
 
 type ListSuite struct {
 	testing.BaseSuite
 
-	mockAPI *mockListAPI
+	mockAPI       *mockListAPI
+	mockEgressAPI *mockListEgressAPI
 }
 
 var _ = gc.Suite(&ListSuite{})
@@ -29,6 +32,11 @@ func (s *ListSuite) SetUpTest(c *gc.C) {
 	s.mockAPI = &mockListAPI{
 		rules: "192.168.1.0/16,10.0.0.0/8",
 	}
+	s.mockEgressAPI = &mockListEgressAPI{
+		egress: map[string]string{
+			"mysql": "",
+		},
+	}
 }
 
 func (s *ListSuite) TestListError(c *gc.C) {
@@ -82,8 +90,52 @@ ssh
 
 }
 
+func (s *ListSuite) TestListEgressTabular(c *gc.C) {
+	s.mockEgressAPI.egress["mediawiki"] = "10.0.0.0/8:3306/tcp,0.0.0.0/0:443/tcp"
+	s.assertValidList(
+		c,
+		[]string{"--format", "tabular"},
+		`
+Service                 Allowlist subnets
+juju-application-offer  0.0.0.0/0
+ssh                     192.168.1.0/16,10.0.0.0/8
+
+Application  Egress
+mediawiki    0.0.0.0/0:443/tcp,10.0.0.0/8:3306/tcp
+`[1:],
+		"",
+	)
+}
+
+func (s *ListSuite) TestListEgressYAML(c *gc.C) {
+	s.mockEgressAPI.egress["mediawiki"] = "10.0.0.0/8:3306/tcp"
+	s.assertValidList(
+		c,
+		[]string{"--format", "yaml"},
+		`
+- known-service: ssh
+  allowlist-subnets:
+  - 192.168.1.0/16
+  - 10.0.0.0/8
+- known-service: juju-application-offer
+  allowlist-subnets:
+  - 0.0.0.0/0
+- application: mediawiki
+  egress:
+  - 10.0.0.0/8:3306/tcp
+`[1:],
+		"",
+	)
+}
+
+func (s *ListSuite) TestListEgressError(c *gc.C) {
+	s.mockEgressAPI.err = errors.New("boom")
+	_, err := s.runList(c, nil)
+	c.Assert(err, gc.ErrorMatches, ".*boom.*")
+}
+
 func (s *ListSuite) runList(c *gc.C, args []string) (*cmd.Context, error) {
-	return cmdtesting.RunCommand(c, firewall.NewListRulesCommandForTest(s.mockAPI), args...)
+	return cmdtesting.RunCommand(c, firewall.NewListRulesCommandForTest(s.mockAPI, s.mockEgressAPI), args...)
 }
 
 func (s *ListSuite) assertValidList(c *gc.C, args []string, expectedValid, expectedErr string) {
@@ -115,3 +167,35 @@ func (s *mockListAPI) ModelGet() (map[string]interface{}, error) {
 		config.SAASIngressAllowKey: "0.0.0.0/0",
 	}), nil
 }
+
+type mockListEgressAPI struct {
+	egress map[string]string
+	err    error
+}
+
+func (s *mockListEgressAPI) Close() error {
+	return nil
+}
+
+func (s *mockListEgressAPI) Status(*apiclient.StatusArgs) (*params.FullStatus, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	apps := make(map[string]params.ApplicationStatus)
+	for name := range s.egress {
+		apps[name] = params.ApplicationStatus{}
+	}
+	return &params.FullStatus{Applications: apps}, nil
+}
+
+func (s *mockListEgressAPI) Get(branchName, application string) (*params.ApplicationGetResults, error) {
+	return &params.ApplicationGetResults{
+		Application: application,
+		ApplicationConfig: map[string]interface{}{
+			"egress-allow": map[string]interface{}{
+				"value":  s.egress[application],
+				"source": "user",
+			},
+		},
+	}, nil
+}
@@ -11,11 +11,14 @@ import (
 	"github.com/juju/cmd/v3"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
 
+	"github.com/juju/juju/api/client/application"
 	"github.com/juju/juju/api/client/modelconfig"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/environs/config"
 )
@@ -31,11 +34,22 @@ The currently supported services are:
 - ssh
 - juju-application-offer
 
-DEPRECATION WARNING: %v
+Egress from the machines hosting an application can be restricted
+with --application and --egress. Each egress rule consists of a
+destination CIDR and a port range, for example 10.0.0.0/8:5432/tcp.
+Once every application on a machine has egress rules, the machine may
+only open connections to the union of those destinations (and to the
+controller). An empty --egress value removes the restriction.
+Egress rules are only enforced by providers which support them, and
+only when the model uses the "instance" firewall mode.
+
+DEPRECATION WARNING (well known services): %v
 `
 
 const setRuleHelpExamples = `
     juju set-firewall-rule ssh --allowlist 192.168.1.0/16
+    juju set-firewall-rule --application mediawiki --egress 10.0.0.0/8:3306/tcp,0.0.0.0/0:443/tcp
+    juju set-firewall-rule --application mediawiki --egress ""
 `
 
 // NewSetFirewallRuleCommand returns a command to set firewall rules.
@@ -49,6 +63,13 @@ func NewSetFirewallRuleCommand() cmd.Command {
 		return modelconfig.NewClient(root), nil
 
 	}
+	cmd.newEgressAPIFunc = func() (SetEgressRuleAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return application.NewClient(root), nil
+	}
 	return modelcmd.Wrap(cmd)
 }
 
@@ -59,14 +80,37 @@ type setFirewallRuleCommand struct {
 	allowlist string
 	whitelist string
 
-	newAPIFunc func() (SetFirewallRuleAPI, error)
+	application string
+	egress      egressFlag
+	egressRules firewall.EgressRules
+
+	newAPIFunc       func() (SetFirewallRuleAPI, error)
+	newEgressAPIFunc func() (SetEgressRuleAPI, error)
+}
+
+// egressFlag records whether --egress was supplied, so that an
+// explicitly empty value can be used to remove all egress rules.
+type egressFlag struct {
+	value string
+	set   bool
+}
+
+// Set implements gnuflag.Value.
+func (f *egressFlag) Set(s string) error {
+	f.value, f.set = s, true
+	return nil
+}
+
+// String implements gnuflag.Value.
+func (f *egressFlag) String() string {
+	return f.value
 }
 
 // Info implements cmd.Command.
 func (c *setFirewallRuleCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:     "set-firewall-rule",
-		Args:     "<service-name>, --allowlist <cidr>[,<cidr>...]",
+		Args:     "<service-name>, --allowlist <cidr>[,<cidr>...] | --application <name> --egress <cidr>:<port-range>[,...]",
 		Purpose:  setRuleHelpSummary,
 		Doc:      fmt.Sprintf(setRuleHelpDetails, deprecationWarning),
 		Examples: setRuleHelpExamples,
@@ -80,10 +124,15 @@ func (c *setFirewallRuleCommand) Info() *cmd.Info {
 func (c *setFirewallRuleCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.allowlist, "allowlist", "", "list of subnets to allowlist")
 	f.StringVar(&c.whitelist, "whitelist", "", "")
+	f.StringVar(&c.application, "application", "", "application whose egress rules are set")
+	f.Var(&c.egress, "egress", "list of allowed egress destinations as <cidr>:<port-range>")
 }
 
 // Init implements cmd.Command.
 func (c *setFirewallRuleCommand) Init(args []string) (err error) {
+	if c.application != "" || c.egress.set {
+		return c.initEgress(args)
+	}
 	if len(args) == 1 {
 		c.service = firewall.WellKnownServiceType(args[0])
 		if c.allowlist == "" && c.whitelist == "" {
@@ -105,6 +154,30 @@ func (c *setFirewallRuleCommand) Init(args []string) (err error) {
 	return cmd.CheckEmpty(args[1:])
 }
 
+func (c *setFirewallRuleCommand) initEgress(args []string) error {
+	if c.application == "" {
+		return errors.New("--egress requires --application")
+	}
+	if !names.IsValidApplication(c.application) {
+		return errors.NotValidf("application name %q", c.application)
+	}
+	if !c.egress.set {
+		return errors.New("no egress rules specified")
+	}
+	if c.allowlist != "" || c.whitelist != "" {
+		return errors.New("cannot specify both egress rules and an allowlist")
+	}
+	if len(args) > 0 {
+		return errors.New("cannot specify a service name with --application")
+	}
+	rules, err := firewall.ParseEgressRules(c.egress.value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.egressRules = rules
+	return nil
+}
+
 func (c *setFirewallRuleCommand) validateCIDRS(value string) error {
 	rawValues := strings.Split(value, ",")
 	for _, cidrStr := range rawValues {
@@ -122,6 +195,17 @@ type SetFirewallRuleAPI interface {
 	ModelSet(config map[string]interface{}) error
 }
 
+// SetEgressRuleAPI defines the API methods that the set firewall rules
+// command uses to manage application egress rules.
+type SetEgressRuleAPI interface {
+	Close() error
+	SetConfig(branchName, application, configYAML string, config map[string]string) error
+}
+
+// egressAllowKey is the application config key holding the egress
+// allow-list of an application.
+const egressAllowKey = "egress-allow"
+
 var deprecationWarning = `
 Firewall rules have been moved to model-config settings "ssh-allow" and
 "saas-ingress-allow". This command is deprecated in favour of
@@ -129,6 +213,9 @@ reading/writing directly to these settings.
 `[1:]
 
 func (c *setFirewallRuleCommand) Run(ctx *cmd.Context) error {
+	if c.application != "" {
+		return c.runEgress()
+	}
 	if c.whitelist != "" {
 		c.allowlist = c.whitelist
 		ctx.Warningf("--whitelist is deprecated in favour of --allowlist")
@@ -151,3 +238,17 @@ func (c *setFirewallRuleCommand) Run(ctx *cmd.Context) error {
 	}
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
+
+func (c *setFirewallRuleCommand) runEgress() error {
+	client, err := c.newEgressAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	c.egressRules.Sort()
+	err = client.SetConfig(model.GenerationMaster, c.application, "", map[string]string{
+		egressAllowKey: c.egressRules.String(),
+	})
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
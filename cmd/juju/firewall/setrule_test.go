@@ -18,13 +18,15 @@ import (
 type SetRuleSuite struct {
 	testing.BaseSuite
 
-	mockAPI *mockSetRuleAPI
+	mockAPI       *mockSetRuleAPI
+	mockEgressAPI *mockSetEgressAPI
 }
 
 var _ = gc.Suite(&SetRuleSuite{})
 
 func (s *SetRuleSuite) SetUpTest(c *gc.C) {
 	s.mockAPI = &mockSetRuleAPI{}
+	s.mockEgressAPI = &mockSetEgressAPI{}
 }
 
 func (s *SetRuleSuite) TestInitMissingService(c *gc.C) {
@@ -60,8 +62,53 @@ func (s *SetRuleSuite) TestSetError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, ".*fail.*")
 }
 
+func (s *SetRuleSuite) TestSetEgressRules(c *gc.C) {
+	_, err := s.runSetRule(c, "--application", "mediawiki", "--egress", "10.0.0.0/8:3306/tcp, 0.0.0.0/0:443/tcp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockEgressAPI.application, gc.Equals, "mediawiki")
+	c.Assert(s.mockEgressAPI.branchName, gc.Equals, "master")
+	c.Assert(s.mockEgressAPI.config, jc.DeepEquals, map[string]string{
+		"egress-allow": "0.0.0.0/0:443/tcp,10.0.0.0/8:3306/tcp",
+	})
+	c.Assert(s.mockAPI.sshRule, gc.Equals, "")
+}
+
+func (s *SetRuleSuite) TestClearEgressRules(c *gc.C) {
+	_, err := s.runSetRule(c, "--application", "mediawiki", "--egress", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockEgressAPI.config, jc.DeepEquals, map[string]string{
+		"egress-allow": "",
+	})
+}
+
+func (s *SetRuleSuite) TestInitEgressInvalid(c *gc.C) {
+	_, err := s.runSetRule(c, "--application", "mediawiki", "--egress", "10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, `egress rule "10.0.0.0/8", expected <cidr>:<port-range> not valid`)
+}
+
+func (s *SetRuleSuite) TestInitEgressMissingRules(c *gc.C) {
+	_, err := s.runSetRule(c, "--application", "mediawiki")
+	c.Assert(err, gc.ErrorMatches, "no egress rules specified")
+}
+
+func (s *SetRuleSuite) TestInitEgressMissingApplication(c *gc.C) {
+	_, err := s.runSetRule(c, "--egress", "10.0.0.0/8:443/tcp")
+	c.Assert(err, gc.ErrorMatches, "--egress requires --application")
+}
+
+func (s *SetRuleSuite) TestInitEgressWithService(c *gc.C) {
+	_, err := s.runSetRule(c, "ssh", "--application", "mediawiki", "--egress", "10.0.0.0/8:443/tcp")
+	c.Assert(err, gc.ErrorMatches, "cannot specify a service name with --application")
+}
+
+func (s *SetRuleSuite) TestSetEgressError(c *gc.C) {
+	s.mockEgressAPI.err = errors.New("fail")
+	_, err := s.runSetRule(c, "--application", "mediawiki", "--egress", "10.0.0.0/8:443/tcp")
+	c.Assert(err, gc.ErrorMatches, ".*fail.*")
+}
+
 func (s *SetRuleSuite) runSetRule(c *gc.C, args ...string) (*cmd.Context, error) {
-	return cmdtesting.RunCommand(c, firewall.NewSetRulesCommandForTest(s.mockAPI), args...)
+	return cmdtesting.RunCommand(c, firewall.NewSetRulesCommandForTest(s.mockAPI, s.mockEgressAPI), args...)
 }
 
 type mockSetRuleAPI struct {
@@ -89,3 +136,24 @@ func (s *mockSetRuleAPI) ModelSet(cfg map[string]interface{}) error {
 
 	return nil
 }
+
+type mockSetEgressAPI struct {
+	branchName  string
+	application string
+	config      map[string]string
+	err         error
+}
+
+func (s *mockSetEgressAPI) Close() error {
+	return nil
+}
+
+func (s *mockSetEgressAPI) SetConfig(branchName, application, configYAML string, config map[string]string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.branchName = branchName
+	s.application = application
+	s.config = config
+	return nil
+}
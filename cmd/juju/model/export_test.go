@@ -152,6 +152,34 @@ func NewRevokeCloudCommandForTest(cloudsApi RevokeCloudAPI, store jujuclient.Cli
 	return modelcmd.WrapController(cmd), &RevokeCloudCommand{cmd}
 }
 
+// NewGrantGroupCommandsForTest returns grant and grant-cloud commands
+// with the user group api provided as specified.
+func NewGrantGroupCommandsForTest(groupsApi UserGroupAccessAPI, store jujuclient.ClientStore) (cmd.Command, cmd.Command) {
+	grant := &grantCommand{accessCommand: accessCommand{groupsApi: groupsApi}}
+	grant.SetClientStore(store)
+	grantCloud := &grantCloudCommand{accessCloudCommand: accessCloudCommand{groupsApi: groupsApi}}
+	grantCloud.SetClientStore(store)
+	return modelcmd.WrapController(grant), modelcmd.WrapController(grantCloud)
+}
+
+// NewRevokeGroupCommandsForTest returns revoke and revoke-cloud commands
+// with the user group api provided as specified.
+func NewRevokeGroupCommandsForTest(groupsApi UserGroupAccessAPI, store jujuclient.ClientStore) (cmd.Command, cmd.Command) {
+	revoke := &revokeCommand{accessCommand: accessCommand{groupsApi: groupsApi}}
+	revoke.SetClientStore(store)
+	revokeCloud := &revokeCloudCommand{accessCloudCommand: accessCloudCommand{groupsApi: groupsApi}}
+	revokeCloud.SetClientStore(store)
+	return modelcmd.WrapController(revoke), modelcmd.WrapController(revokeCloud)
+}
+
+// NewGrantRoleCommandForTest returns a grant-role command with the api
+// provided as specified.
+func NewGrantRoleCommandForTest(api RoleGrantAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &grantRoleCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
 func NewModelSetConstraintsCommandForTest() cmd.Command {
 	cmd := &modelSetConstraintsCommand{}
 	cmd.SetClientStore(jujuclienttesting.MinimalStore())
@@ -242,3 +270,31 @@ func NewShowCommitCommandForTest(api ShowCommitCommandAPI, store jujuclient.Clie
 	cmd.SetClientStore(store)
 	return modelcmd.Wrap(cmd)
 }
+
+// NewExportModelCommandForTest returns an ExportModelCommand with the apis provided as specified.
+func NewExportModelCommandForTest(exportAPI ExportModelAPI, binariesAPI ModelBinariesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &exportModelCommand{
+		newExportAPI: func() (ExportModelAPI, error) {
+			return exportAPI, nil
+		},
+		newBinariesAPI: func() (ModelBinariesAPI, error) {
+			return binariesAPI, nil
+		},
+	}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewModelGetQuotaCommandForTest returns a modelGetQuotaCommand with the api provided as specified.
+func NewModelGetQuotaCommandForTest(api QuotaAPI) cmd.Command {
+	cmd := &modelGetQuotaCommand{api: api}
+	cmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(cmd)
+}
+
+// NewModelSetQuotaCommandForTest returns a modelSetQuotaCommand with the api provided as specified.
+func NewModelSetQuotaCommandForTest(api QuotaAPI) cmd.Command {
+	cmd := &modelSetQuotaCommand{api: api}
+	cmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(cmd)
+}
@@ -0,0 +1,240 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	jujussh "github.com/juju/utils/v3/ssh"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/client/charms"
+	"github.com/juju/juju/api/client/resources"
+	"github.com/juju/juju/api/controller/controller"
+	apihttp "github.com/juju/juju/api/http"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/migration/modelarchive"
+)
+
+// NewExportModelCommand returns a fully constructed export-model command.
+func NewExportModelCommand() cmd.Command {
+	command := &exportModelCommand{}
+	command.newExportAPI = func() (ExportModelAPI, error) {
+		root, err := command.NewControllerAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return controller.NewClient(root), nil
+	}
+	command.newBinariesAPI = func() (ModelBinariesAPI, error) {
+		root, err := command.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return newModelBinariesClient(root)
+	}
+	return modelcmd.Wrap(command)
+}
+
+type exportModelCommand struct {
+	modelcmd.ModelCommandBase
+
+	newExportAPI   func() (ExportModelAPI, error)
+	newBinariesAPI func() (ModelBinariesAPI, error)
+
+	filename   string
+	signingKey string
+}
+
+const exportModelHelpDoc = `
+Writes the model, together with the charms, resources and agent binaries
+it uses, to a signed archive file. The archive can be carried to another
+controller and imported there with the import-model command, without the
+two controllers ever needing to reach each other.
+
+The archive is signed with an SSH private key. By default the key Juju
+generated for this client is used; --signing-key selects another.
+Whoever imports the archive can then check it against the matching
+public key.
+
+The model is left untouched on this controller. Exporting a model does
+not migrate it: once imported elsewhere, only one copy of the model
+should be kept running.
+`
+
+const exportModelHelpExamples = `
+    juju export-model prod.jmodel
+    juju export-model -m prod --signing-key ~/.ssh/id_ed25519 prod.jmodel
+`
+
+// Info implements Command.
+func (c *exportModelCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "export-model",
+		Args:     "<file>",
+		Purpose:  "Exports a model and its binaries to a signed archive.",
+		Doc:      exportModelHelpDoc,
+		Examples: exportModelHelpExamples,
+		SeeAlso: []string{
+			"import-model",
+			"migrate",
+		},
+	})
+}
+
+// SetFlags implements Command.
+func (c *exportModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.signingKey, "signing-key", "", "SSH private key file to sign the archive with")
+}
+
+// Init implements Command.
+func (c *exportModelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no archive file specified")
+	}
+	c.filename, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+// ExportModelAPI specifies the controller API used to export a model.
+type ExportModelAPI interface {
+	Close() error
+	ExportModel(modelUUID string) (coremigration.SerializedModel, error)
+}
+
+// ModelBinariesAPI specifies the API used to download the binaries
+// used by a model.
+type ModelBinariesAPI interface {
+	Close() error
+	modelarchive.CharmDownloader
+	modelarchive.ToolsDownloader
+	modelarchive.ResourceDownloader
+}
+
+type modelBinariesClient struct {
+	charms.CharmOpener
+	apihttp.URIOpener
+	*resources.Client
+
+	root api.Connection
+}
+
+// Close implements ModelBinariesAPI.
+func (c *modelBinariesClient) Close() error {
+	return c.root.Close()
+}
+
+func newModelBinariesClient(root api.Connection) (*modelBinariesClient, error) {
+	charmOpener, err := charms.NewCharmOpener(root)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	uriOpener, err := apihttp.NewURIOpener(root)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resourcesClient, err := resources.NewClient(root)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &modelBinariesClient{
+		CharmOpener: charmOpener,
+		URIOpener:   uriOpener,
+		Client:      resourcesClient,
+		root:        root,
+	}, nil
+}
+
+// Run implements Command.
+func (c *exportModelCommand) Run(ctx *cmd.Context) error {
+	signer, err := c.readSigner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	controllerDetails, err := c.ClientStore().ControllerByName(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	modelName, modelDetails, err := c.ModelDetails()
+	if err != nil {
+		return errors.Annotate(err, "getting model details")
+	}
+
+	exportClient, err := c.newExportAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer exportClient.Close()
+
+	ctx.Infof("Exporting model %q", modelName)
+	serialized, err := exportClient.ExportModel(modelDetails.ModelUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	binariesClient, err := c.newBinariesAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer binariesClient.Close()
+
+	file, err := c.Filesystem().OpenFile(c.filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Annotate(err, "creating archive file")
+	}
+	err = modelarchive.Write(file, modelarchive.WriteConfig{
+		Model:                serialized,
+		SourceControllerUUID: controllerDetails.ControllerUUID,
+		CharmDownloader:      binariesClient,
+		ToolsDownloader:      binariesClient,
+		ResourceDownloader:   binariesClient,
+		Signer:               signer,
+		Clock:                clock.WallClock,
+	})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = c.Filesystem().RemoveAll(c.filename)
+		return errors.Annotate(err, "writing model archive")
+	}
+
+	fmt.Fprintf(ctx.Stdout, "Model %q exported to %s\n", modelName, c.filename)
+	fmt.Fprintf(ctx.Stdout, "Archive signed by key %s\n", ssh.FingerprintSHA256(signer.PublicKey()))
+	return nil
+}
+
+func (c *exportModelCommand) readSigner() (ssh.Signer, error) {
+	keyFile := c.signingKey
+	if keyFile == "" {
+		keyFiles := jujussh.PrivateKeyFiles()
+		if len(keyFiles) == 0 {
+			return nil, errors.New("no SSH private key found, specify one with --signing-key")
+		}
+		keyFile = keyFiles[0]
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading signing key")
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "parsing signing key %q", keyFile)
+	}
+	return signer, nil
+}
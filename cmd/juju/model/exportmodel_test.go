@@ -0,0 +1,211 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/description/v4"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	coremigration "github.com/juju/juju/core/migration"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/migration/modelarchive"
+	"github.com/juju/juju/testing"
+)
+
+type ExportModelCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	exportAPI   *fakeExportModelAPI
+	binariesAPI *fakeModelBinariesAPI
+	store       *jujuclient.MemStore
+	signer      ssh.Signer
+	keyFile     string
+}
+
+var _ = gc.Suite(&ExportModelCommandSuite{})
+
+func (s *ExportModelCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{
+		ControllerUUID: testing.ControllerTag.Id(),
+	}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+
+	descModel := description.NewModel(description.ModelArgs{
+		Type:  string(coremodel.IAAS),
+		Owner: names.NewUserTag("admin"),
+		Config: map[string]interface{}{
+			"name":          "mymodel",
+			"uuid":          testing.ModelTag.Id(),
+			"agent-version": "2.9.42",
+		},
+	})
+	descModel.SetStatus(description.StatusArgs{Value: "available"})
+	bytes, err := description.Serialize(descModel)
+	c.Assert(err, jc.ErrorIsNil)
+	s.exportAPI = &fakeExportModelAPI{model: coremigration.SerializedModel{
+		Bytes:  bytes,
+		Charms: []string{"ch:foo-1"},
+		Tools: map[version.Binary]string{
+			version.MustParseBinary("2.9.42-ubuntu-amd64"): "/tools/2.9.42-ubuntu-amd64",
+		},
+	}}
+	s.binariesAPI = &fakeModelBinariesAPI{}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	s.signer, err = ssh.NewSignerFromKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	block, err := ssh.MarshalPrivateKey(key, "")
+	c.Assert(err, jc.ErrorIsNil)
+	s.keyFile = filepath.Join(c.MkDir(), "id_ed25519")
+	err = os.WriteFile(s.keyFile, pem.EncodeToMemory(block), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ExportModelCommandSuite) run(c *gc.C, args ...string) (string, error) {
+	command := model.NewExportModelCommandForTest(s.exportAPI, s.binariesAPI, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, args...)
+	return cmdtesting.Stdout(ctx), err
+}
+
+func (s *ExportModelCommandSuite) TestInit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no archive file specified")
+	_, err = s.run(c, "a", "b")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *ExportModelCommandSuite) TestExport(c *gc.C) {
+	filename := filepath.Join(c.MkDir(), "mymodel.jmodel")
+	out, err := s.run(c, "--signing-key", s.keyFile, filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(out, gc.Equals, `Model "admin/mymodel" exported to `+filename+"\n"+
+		"Archive signed by key "+ssh.FingerprintSHA256(s.signer.PublicKey())+"\n")
+
+	s.exportAPI.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ExportModel", []interface{}{testing.ModelTag.Id()}},
+		{"Close", nil},
+	})
+	s.binariesAPI.CheckCalls(c, []gitjujutesting.StubCall{
+		{"OpenCharm", []interface{}{"ch:foo-1"}},
+		{"OpenURI", []interface{}{"/tools/2.9.42-ubuntu-amd64"}},
+		{"Close", nil},
+	})
+
+	info, err := os.Stat(filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	f, err := os.Open(filename)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	archive, err := modelarchive.Open(f, s.signer.PublicKey())
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	manifest := archive.Manifest()
+	c.Check(manifest.ModelUUID, gc.Equals, testing.ModelTag.Id())
+	c.Check(manifest.SourceControllerUUID, gc.Equals, testing.ControllerTag.Id())
+	c.Check(manifest.Charms, gc.HasLen, 1)
+	c.Check(manifest.Tools, gc.HasLen, 1)
+}
+
+func (s *ExportModelCommandSuite) TestExportError(c *gc.C) {
+	s.exportAPI.SetErrors(errors.New("boom"))
+	filename := filepath.Join(c.MkDir(), "mymodel.jmodel")
+	_, err := s.run(c, "--signing-key", s.keyFile, filename)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	_, err = os.Stat(filename)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *ExportModelCommandSuite) TestDownloadErrorRemovesFile(c *gc.C) {
+	s.binariesAPI.SetErrors(errors.New("no charm"))
+	filename := filepath.Join(c.MkDir(), "mymodel.jmodel")
+	_, err := s.run(c, "--signing-key", s.keyFile, filename)
+	c.Assert(err, gc.ErrorMatches, `writing model archive: adding charms: opening charm "ch:foo-1": no charm`)
+	_, err = os.Stat(filename)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *ExportModelCommandSuite) TestBadSigningKey(c *gc.C) {
+	err := os.WriteFile(s.keyFile, []byte("not a key"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.run(c, "--signing-key", s.keyFile, filepath.Join(c.MkDir(), "mymodel.jmodel"))
+	c.Assert(err, gc.ErrorMatches, `parsing signing key ".*": .*`)
+	s.exportAPI.CheckNoCalls(c)
+}
+
+type fakeExportModelAPI struct {
+	gitjujutesting.Stub
+	model coremigration.SerializedModel
+}
+
+func (f *fakeExportModelAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeExportModelAPI) ExportModel(modelUUID string) (coremigration.SerializedModel, error) {
+	f.MethodCall(f, "ExportModel", modelUUID)
+	return f.model, f.NextErr()
+}
+
+type fakeModelBinariesAPI struct {
+	gitjujutesting.Stub
+}
+
+func (f *fakeModelBinariesAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeModelBinariesAPI) OpenCharm(curl string) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenCharm", curl)
+	return f.content("charm "+curl, f.NextErr())
+}
+
+func (f *fakeModelBinariesAPI) OpenURI(uri string, _ url.Values) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenURI", uri)
+	return f.content("tools "+uri, f.NextErr())
+}
+
+func (f *fakeModelBinariesAPI) OpenResource(application, name string) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenResource", application, name)
+	return f.content("resource "+name, f.NextErr())
+}
+
+func (f *fakeModelBinariesAPI) content(data string, err error) (io.ReadCloser, error) {
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
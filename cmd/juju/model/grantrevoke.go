@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/cmd/v3"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/api/client/applicationoffers"
@@ -45,6 +46,9 @@ Valid access levels for application offers are:
 var usageGrantDetails = `
 By default, the controller is the current controller.
 
+With --group, the access is granted to a user group, and so to every
+member of the group, rather than to a single user.
+
 Users with read access are limited in what they can do with models:
 ` + "`juju models`, `juju machines`, and `juju status`" + `.
 
@@ -75,6 +79,10 @@ Grant user 'sam' 'read' access to application offers 'fred/prod.hosted-mysql' an
 
     juju grant sam read fred/prod.hosted-mysql mary/test.hosted-mysql
 
+Grant user group 'developers' 'write' access to model 'mymodel':
+
+    juju grant --group developers write mymodel
+
 `
 
 var usageRevokeSummary = `
@@ -87,6 +95,10 @@ Revoking write access, from a user who has that permission, will leave
 that user with read access. Revoking read access, however, also revokes
 write access.
 
+With --group, the access is revoked from a user group rather than a
+single user. Members of the group keep any access that was granted to
+them directly.
+
 `[1:] + validAccessLevels
 
 const usageRevokeExamples = `
@@ -105,17 +117,31 @@ Revoke 'read' (and 'write') access from user 'joe' for application offer 'fred/p
 Revoke 'consume' access from user 'sam' for models 'fred/prod.hosted-mysql' and 'mary/test.hosted-mysql':
 
     juju revoke sam consume fred/prod.hosted-mysql mary/test.hosted-mysql
+
+Revoke 'write' access from user group 'developers' for model 'mymodel':
+
+    juju revoke --group developers write mymodel
 `
 
 type accessCommand struct {
 	modelcmd.ControllerCommandBase
+	groupsApi UserGroupAccessAPI
 
+	// User is the user, or with Group set the user group, whose
+	// access is changed.
 	User       string
+	Group      bool
 	ModelNames []string
 	OfferURLs  []*crossmodel.OfferURL
 	Access     string
 }
 
+// SetFlags implements cmd.Command.
+func (c *accessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.BoolVar(&c.Group, "group", false, "Change the access of the named user group rather than a user")
+}
+
 // Init implements cmd.Command.
 func (c *accessCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -189,7 +215,7 @@ type grantCommand struct {
 func (c *grantCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:     "grant",
-		Args:     "<user name> <permission> [<model name> ... | <offer url> ...]",
+		Args:     "<user or group name> <permission> [<model name> ... | <offer url> ...]",
 		Purpose:  usageGrantSummary,
 		Doc:      usageGrantDetails,
 		Examples: usageGrantExamples,
@@ -242,6 +268,12 @@ type GrantOfferAPI interface {
 
 // Run implements cmd.Command.
 func (c *grantCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		if err := setUnsetUsers(c, c.OfferURLs); err != nil {
+			return errors.Trace(err)
+		}
+		return c.runForGroup(true)
+	}
 	if len(c.ModelNames) > 0 {
 		return c.runForModel()
 	}
@@ -309,7 +341,7 @@ type revokeCommand struct {
 func (c *revokeCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:     "revoke",
-		Args:     "<user name> <permission> [<model name> ... | <offer url> ...]",
+		Args:     "<user or group name> <permission> [<model name> ... | <offer url> ...]",
 		Purpose:  usageRevokeSummary,
 		Doc:      usageRevokeDetails,
 		Examples: usageRevokeExamples,
@@ -361,6 +393,12 @@ type RevokeOfferAPI interface {
 
 // Run implements cmd.Command.
 func (c *revokeCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		if err := setUnsetUsers(c, c.OfferURLs); err != nil {
+			return errors.Trace(err)
+		}
+		return c.runForGroup(false)
+	}
 	if len(c.ModelNames) > 0 {
 		return c.runForModel()
 	}
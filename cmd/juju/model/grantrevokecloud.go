@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/cmd/v3"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/api/client/cloud"
@@ -24,12 +25,20 @@ Valid access levels are:
 var usageGrantCloudSummary = `
 Grants access level to a Juju user for a cloud.`[1:]
 
-var usageGrantCloudDetails = validCloudAccessLevels
+var usageGrantCloudDetails = `
+With --group, the access is granted to a user group, and so to every
+member of the group, rather than to a single user.
+
+`[1:] + validCloudAccessLevels
 
 const usageGrantCloudExamples = `
 Grant user 'joe' 'add-model' access to cloud 'fluffy':
 
     juju grant-cloud joe add-model fluffy
+
+Grant user group 'developers' 'add-model' access to cloud 'fluffy':
+
+    juju grant-cloud --group developers add-model fluffy
 `
 
 var usageRevokeCloudSummary = `
@@ -40,6 +49,9 @@ Revoking admin access, from a user who has that permission, will leave
 that user with add-model access. Revoking add-model access, however, also revokes
 admin access.
 
+With --group, the access is revoked from a user group rather than a
+single user.
+
 `[1:] + validCloudAccessLevels
 
 const usageRevokeCloudExamples = `
@@ -55,12 +67,22 @@ Revoke 'admin' access from user 'sam' for clouds 'fluffy' and 'rainy':
 
 type accessCloudCommand struct {
 	modelcmd.ControllerCommandBase
+	groupsApi UserGroupAccessAPI
 
+	// User is the user, or with Group set the user group, whose
+	// access is changed.
 	User   string
+	Group  bool
 	Clouds []string
 	Access string
 }
 
+// SetFlags implements cmd.Command.
+func (c *accessCloudCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.BoolVar(&c.Group, "group", false, "Change the access of the named user group rather than a user")
+}
+
 // Init implements cmd.Command.
 func (c *accessCloudCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -107,7 +129,7 @@ type grantCloudCommand struct {
 func (c *grantCloudCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:     "grant-cloud",
-		Args:     "<user name> <permission> <cloud name> ...",
+		Args:     "<user or group name> <permission> <cloud name> ...",
 		Purpose:  usageGrantCloudSummary,
 		Doc:      usageGrantCloudDetails,
 		Examples: usageGrantCloudExamples,
@@ -137,6 +159,9 @@ type GrantCloudAPI interface {
 
 // Run implements cmd.Command.
 func (c *grantCloudCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		return c.runForGroup(true)
+	}
 	client, err := c.getCloudsAPI()
 	if err != nil {
 		return err
@@ -161,7 +186,7 @@ type revokeCloudCommand struct {
 func (c *revokeCloudCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:     "revoke-cloud",
-		Args:     "<user name> <permission> <cloud name> ...",
+		Args:     "<user or group name> <permission> <cloud name> ...",
 		Purpose:  usageRevokeCloudSummary,
 		Doc:      usageRevokeCloudDetails,
 		Examples: usageRevokeCloudExamples,
@@ -190,6 +215,9 @@ type RevokeCloudAPI interface {
 
 // Run implements cmd.Command.
 func (c *revokeCloudCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		return c.runForGroup(false)
+	}
 	client, err := c.getCloudAPI()
 	if err != nil {
 		return err
@@ -0,0 +1,103 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/permission"
+)
+
+// UserGroupAccessAPI defines the API functions used by the grant and
+// revoke commands to change the access of user groups.
+type UserGroupAccessAPI interface {
+	Close() error
+	GrantUserGroup(group string, access permission.Access, target names.Tag) error
+	RevokeUserGroup(group string, access permission.Access, target names.Tag) error
+	GrantUserGroupOffer(group string, access permission.Access, offerURL string) error
+	RevokeUserGroupOffer(group string, access permission.Access, offerURL string) error
+}
+
+func getUserGroupAccessAPI(c *modelcmd.ControllerCommandBase, api UserGroupAccessAPI) (UserGroupAccessAPI, error) {
+	if api != nil {
+		return api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// runForGroup grants or revokes the access of a user group to the
+// models, offers or controller named on the command line.
+func (c *accessCommand) runForGroup(grant bool) error {
+	client, err := getUserGroupAccessAPI(&c.ControllerCommandBase, c.groupsApi)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	modify, modifyOffer := client.RevokeUserGroup, client.RevokeUserGroupOffer
+	if grant {
+		modify, modifyOffer = client.GrantUserGroup, client.GrantUserGroupOffer
+	}
+	access := permission.Access(c.Access)
+
+	var targets []names.Tag
+	switch {
+	case len(c.OfferURLs) > 0:
+		for _, url := range c.OfferURLs {
+			if err := modifyOffer(c.User, access, url.String()); err != nil {
+				return block.ProcessBlockedError(err, block.BlockChange)
+			}
+		}
+		return nil
+	case len(c.ModelNames) > 0:
+		models, err := c.ModelUUIDs(c.ModelNames)
+		if err != nil {
+			return err
+		}
+		for _, uuid := range models {
+			targets = append(targets, names.NewModelTag(uuid))
+		}
+	default:
+		controllerName, err := c.ControllerName()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		details, err := c.ClientStore().ControllerByName(controllerName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		targets = append(targets, names.NewControllerTag(details.ControllerUUID))
+	}
+	for _, target := range targets {
+		if err := modify(c.User, access, target); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+	return nil
+}
+
+// runForGroup grants or revokes the access of a user group to the
+// clouds named on the command line.
+func (c *accessCloudCommand) runForGroup(grant bool) error {
+	client, err := getUserGroupAccessAPI(&c.ControllerCommandBase, c.groupsApi)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	modify := client.RevokeUserGroup
+	if grant {
+		modify = client.GrantUserGroup
+	}
+	for _, cloud := range c.Clouds {
+		err := modify(c.User, permission.Access(c.Access), names.NewCloudTag(cloud))
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+	return nil
+}
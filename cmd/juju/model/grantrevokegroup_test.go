@@ -0,0 +1,147 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type grantRevokeGroupSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api   *fakeUserGroupAccessAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&grantRevokeGroupSuite{})
+
+func (s *grantRevokeGroupSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeUserGroupAccessAPI{}
+
+	controllerName := "test-master"
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = controllerName
+	s.store.Controllers[controllerName] = jujuclient.ControllerDetails{
+		ControllerUUID: testing.ControllerTag.Id(),
+	}
+	s.store.Accounts[controllerName] = jujuclient.AccountDetails{
+		User: "bob",
+	}
+	s.store.Models = map[string]*jujuclient.ControllerModels{
+		controllerName: {
+			Models: map[string]jujuclient.ModelDetails{
+				"bob/foo": {ModelUUID: fooModelUUID, ModelType: coremodel.IAAS},
+				"bob/bar": {ModelUUID: barModelUUID, ModelType: coremodel.IAAS},
+			},
+		},
+	}
+}
+
+func (s *grantRevokeGroupSuite) TestGrantModels(c *gc.C) {
+	grant, _ := model.NewGrantGroupCommandsForTest(s.api, s.store)
+	_, err := cmdtesting.RunCommand(c, grant, "--group", "developers", "write", "foo", "bar")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"GrantUserGroup", []interface{}{"developers", permission.WriteAccess, names.NewModelTag(fooModelUUID)}},
+		{"GrantUserGroup", []interface{}{"developers", permission.WriteAccess, names.NewModelTag(barModelUUID)}},
+		{"Close", nil},
+	})
+}
+
+func (s *grantRevokeGroupSuite) TestGrantController(c *gc.C) {
+	grant, _ := model.NewGrantGroupCommandsForTest(s.api, s.store)
+	_, err := cmdtesting.RunCommand(c, grant, "--group", "developers", "superuser")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"GrantUserGroup", []interface{}{"developers", permission.SuperuserAccess, testing.ControllerTag}},
+		{"Close", nil},
+	})
+}
+
+func (s *grantRevokeGroupSuite) TestGrantOfferWithDefaultUser(c *gc.C) {
+	grant, _ := model.NewGrantGroupCommandsForTest(s.api, s.store)
+	_, err := cmdtesting.RunCommand(c, grant, "--group", "developers", "consume", "foo.hosted-mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"GrantUserGroupOffer", []interface{}{"developers", permission.ConsumeAccess, "bob/foo.hosted-mysql"}},
+		{"Close", nil},
+	})
+}
+
+func (s *grantRevokeGroupSuite) TestGrantCloud(c *gc.C) {
+	_, grantCloud := model.NewGrantGroupCommandsForTest(s.api, s.store)
+	_, err := cmdtesting.RunCommand(c, grantCloud, "--group", "developers", "add-model", "fluffy")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"GrantUserGroup", []interface{}{"developers", permission.AddModelAccess, names.NewCloudTag("fluffy")}},
+		{"Close", nil},
+	})
+}
+
+func (s *grantRevokeGroupSuite) TestRevokeModel(c *gc.C) {
+	revoke, _ := model.NewRevokeGroupCommandsForTest(s.api, s.store)
+	_, err := cmdtesting.RunCommand(c, revoke, "--group", "developers", "read", "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"RevokeUserGroup", []interface{}{"developers", permission.ReadAccess, names.NewModelTag(fooModelUUID)}},
+		{"Close", nil},
+	})
+}
+
+func (s *grantRevokeGroupSuite) TestRevokeCloud(c *gc.C) {
+	_, revokeCloud := model.NewRevokeGroupCommandsForTest(s.api, s.store)
+	_, err := cmdtesting.RunCommand(c, revokeCloud, "--group", "developers", "admin", "fluffy")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"RevokeUserGroup", []interface{}{"developers", permission.AdminAccess, names.NewCloudTag("fluffy")}},
+		{"Close", nil},
+	})
+}
+
+func (s *grantRevokeGroupSuite) TestRevokeError(c *gc.C) {
+	s.api.SetErrors(errors.NotFoundf("user group %q", "developers"))
+	revoke, _ := model.NewRevokeGroupCommandsForTest(s.api, s.store)
+	_, err := cmdtesting.RunCommand(c, revoke, "--group", "developers", "read", "foo")
+	c.Assert(err, gc.ErrorMatches, `user group "developers" not found`)
+}
+
+type fakeUserGroupAccessAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeUserGroupAccessAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return nil
+}
+
+func (f *fakeUserGroupAccessAPI) GrantUserGroup(group string, access permission.Access, target names.Tag) error {
+	f.MethodCall(f, "GrantUserGroup", group, access, target)
+	return f.NextErr()
+}
+
+func (f *fakeUserGroupAccessAPI) RevokeUserGroup(group string, access permission.Access, target names.Tag) error {
+	f.MethodCall(f, "RevokeUserGroup", group, access, target)
+	return f.NextErr()
+}
+
+func (f *fakeUserGroupAccessAPI) GrantUserGroupOffer(group string, access permission.Access, offerURL string) error {
+	f.MethodCall(f, "GrantUserGroupOffer", group, access, offerURL)
+	return f.NextErr()
+}
+
+func (f *fakeUserGroupAccessAPI) RevokeUserGroupOffer(group string, access permission.Access, offerURL string) error {
+	f.MethodCall(f, "RevokeUserGroupOffer", group, access, offerURL)
+	return f.NextErr()
+}
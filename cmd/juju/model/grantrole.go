@@ -0,0 +1,112 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageGrantRoleSummary = `
+Grants a role to a user on models.`[1:]
+
+var usageGrantRoleDetails = `
+The user is given the access level of the role on each model, replacing
+the access and role they had, and may then only call the API methods
+that the role's capabilities allow. Users without access to a model are
+given it.
+
+The built-in roles "read", "write" and "admin" allow every method;
+granting one of them is the same as granting that level of access with
+"juju grant".
+
+Controller administrators may grant roles on any model; model
+administrators may grant roles on their models.
+
+`[1:]
+
+const usageGrantRoleExamples = `
+    juju grant-role bob operator mymodel
+    juju grant-role sam auditor mymodel othermodel
+`
+
+// RoleGrantAPI defines the API functions used by the grant-role
+// command.
+type RoleGrantAPI interface {
+	Close() error
+	GrantRole(user, role string, modelUUIDs ...string) error
+}
+
+// NewGrantRoleCommand returns a command to grant a role to a user on
+// models.
+func NewGrantRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&grantRoleCommand{})
+}
+
+// grantRoleCommand grants a role to a user on models.
+type grantRoleCommand struct {
+	modelcmd.ControllerCommandBase
+	api RoleGrantAPI
+
+	User       string
+	Role       string
+	ModelNames []string
+}
+
+// Info implements Command.Info.
+func (c *grantRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "grant-role",
+		Args:     "<user name> <role name> <model name> ...",
+		Purpose:  usageGrantRoleSummary,
+		Doc:      usageGrantRoleDetails,
+		Examples: usageGrantRoleExamples,
+		SeeAlso: []string{
+			"roles",
+			"add-role",
+			"grant",
+		},
+	})
+}
+
+// Init implements Command.Init.
+func (c *grantRoleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no user specified")
+	}
+	if len(args) == 1 {
+		return errors.New("no role specified")
+	}
+	if len(args) == 2 {
+		return errors.New("no model specified")
+	}
+	c.User, c.Role, c.ModelNames = args[0], args[1], args[2:]
+	return nil
+}
+
+func (c *grantRoleCommand) getAPI() (RoleGrantAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *grantRoleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	models, err := c.ModelUUIDs(c.ModelNames)
+	if err != nil {
+		return err
+	}
+	return block.ProcessBlockedError(client.GrantRole(c.User, c.Role, models...), block.BlockChange)
+}
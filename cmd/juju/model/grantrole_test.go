@@ -0,0 +1,100 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type grantRoleSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api   *fakeRoleGrantAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&grantRoleSuite{})
+
+func (s *grantRoleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeRoleGrantAPI{}
+
+	controllerName := "test-master"
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = controllerName
+	s.store.Controllers[controllerName] = jujuclient.ControllerDetails{}
+	s.store.Accounts[controllerName] = jujuclient.AccountDetails{
+		User: "bob",
+	}
+	s.store.Models = map[string]*jujuclient.ControllerModels{
+		controllerName: {
+			Models: map[string]jujuclient.ModelDetails{
+				"bob/foo": {ModelUUID: fooModelUUID, ModelType: coremodel.IAAS},
+				"bob/bar": {ModelUUID: barModelUUID, ModelType: coremodel.IAAS},
+			},
+		},
+	}
+}
+
+func (s *grantRoleSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no user specified",
+	}, {
+		args:     []string{"sam"},
+		errMatch: "no role specified",
+	}, {
+		args:     []string{"sam", "operator"},
+		errMatch: "no model specified",
+	}, {
+		args: []string{"sam", "operator", "foo", "bar"},
+	}} {
+		c.Logf("test %d, args %v", i, test.args)
+		err := cmdtesting.InitCommand(model.NewGrantRoleCommandForTest(s.api, s.store), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *grantRoleSuite) TestGrantRole(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewGrantRoleCommandForTest(s.api, s.store), "sam", "operator", "foo", "bar")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"GrantRole", []interface{}{"sam", "operator", []string{fooModelUUID, barModelUUID}}},
+		{"Close", nil},
+	})
+}
+
+func (s *grantRoleSuite) TestGrantRoleError(c *gc.C) {
+	s.api.SetErrors(errors.NotFoundf("role %q", "operator"))
+	_, err := cmdtesting.RunCommand(c, model.NewGrantRoleCommandForTest(s.api, s.store), "sam", "operator", "foo")
+	c.Assert(err, gc.ErrorMatches, `role "operator" not found`)
+}
+
+type fakeRoleGrantAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeRoleGrantAPI) GrantRole(user, role string, modelUUIDs ...string) error {
+	f.MethodCall(f, "GrantRole", user, role, modelUUIDs)
+	return f.NextErr()
+}
+
+func (f *fakeRoleGrantAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return nil
+}
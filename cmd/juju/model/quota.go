@@ -0,0 +1,238 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/client/modelconfig"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/quota"
+)
+
+const getQuotaDoc = "" +
+	"Shows the resource quotas that have been set on the model with\n" +
+	"`juju set-model-quota`, along with the resources the model currently\n" +
+	"consumes. By default, the model is the current model.\n" +
+	"Memory and storage are shown in MiB. A resource without a quota is\n" +
+	"not limited.\n"
+
+const getQuotaDocExamples = `
+    juju model-quota
+    juju model-quota -m mymodel --format yaml
+`
+
+const setQuotaDoc = "" +
+	"Sets resource quotas on the model that can be viewed with\n" +
+	"`juju model-quota`. By default, the model is the current model.\n" +
+	"Only controller administrators may set model quotas.\n" +
+	"\n" +
+	"The following quotas may be set:\n" +
+	"\n" +
+	"    machines  the number of machines, including containers\n" +
+	"    units     the number of principal units\n" +
+	"    cores     the number of CPU cores of all machines or pods\n" +
+	"    memory    the memory of all machines or pods\n" +
+	"    storage   the size of all volumes and filesystems\n" +
+	"\n" +
+	"Memory and storage take an optional M, G, T or P suffix, and are in\n" +
+	"MiB otherwise. Quotas are checked when machines and units are added to\n" +
+	"the model; cores and memory are counted from the hardware of machines\n" +
+	"that have been provisioned, and from constraints otherwise.\n" +
+	"\n" +
+	"Quotas not given as arguments are removed, and a quota of 0 means\n" +
+	"that the resource is not limited; use machines=0 to remove all quotas.\n"
+
+const setQuotaDocExamples = `
+    juju set-model-quota machines=10 cores=40 memory=160G
+    juju set-model-quota -m mymodel units=20 storage=1T
+`
+
+// QuotaAPI defines methods on the client API that
+// the model-quota and set-model-quota commands call.
+type QuotaAPI interface {
+	Close() error
+	GetModelQuotas() (quotas, usage quota.ModelResources, _ error)
+	SetModelQuotas(quota.ModelResources) error
+}
+
+// NewModelGetQuotaCommand returns a command to show model quotas.
+func NewModelGetQuotaCommand() cmd.Command {
+	return modelcmd.Wrap(&modelGetQuotaCommand{})
+}
+
+// modelGetQuotaCommand shows the resource quotas of a model.
+type modelGetQuotaCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+	api QuotaAPI
+}
+
+func (c *modelGetQuotaCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "model-quota",
+		Purpose:  "Displays the resource quotas of a model.",
+		Doc:      getQuotaDoc,
+		Examples: getQuotaDocExamples,
+		SeeAlso: []string{
+			"models",
+			"set-model-quota",
+		},
+	})
+}
+
+func (c *modelGetQuotaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": formatQuotaTabular,
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+	})
+}
+
+func (c *modelGetQuotaCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *modelGetQuotaCommand) getAPI() (QuotaAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelconfig.NewClient(root), nil
+}
+
+func (c *modelGetQuotaCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	quotas, usage, err := client.GetModelQuotas()
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatModelQuotas(quotas, usage))
+}
+
+// quotaDetails holds the quota and usage of a single resource.
+type quotaDetails struct {
+	Quota uint64 `yaml:"quota,omitempty" json:"quota,omitempty"`
+	Usage uint64 `yaml:"usage" json:"usage"`
+}
+
+// formattedQuotas holds the quota and usage of each resource,
+// keyed by the resource name.
+type formattedQuotas map[string]quotaDetails
+
+func formatModelQuotas(quotas, usage quota.ModelResources) formattedQuotas {
+	return formattedQuotas{
+		quota.MachinesQuota: {uint64(quotas.Machines), uint64(usage.Machines)},
+		quota.UnitsQuota:    {uint64(quotas.Units), uint64(usage.Units)},
+		quota.CoresQuota:    {quotas.Cores, usage.Cores},
+		quota.MemoryQuota:   {quotas.Memory, usage.Memory},
+		quota.StorageQuota:  {quotas.Storage, usage.Storage},
+	}
+}
+
+// formatQuotaTabular writes a tabular summary of model quotas.
+func formatQuotaTabular(writer io.Writer, value interface{}) error {
+	quotas, ok := value.(formattedQuotas)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", quotas, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{
+		TabWriter: tw,
+	}
+	w.Println("Resource", "Quota", "Usage")
+	for _, name := range []string{
+		quota.MachinesQuota,
+		quota.UnitsQuota,
+		quota.CoresQuota,
+		quota.MemoryQuota,
+		quota.StorageQuota,
+	} {
+		details := quotas[name]
+		suffix := ""
+		if name == quota.MemoryQuota || name == quota.StorageQuota {
+			suffix = "M"
+		}
+		limit := "-"
+		if details.Quota > 0 {
+			limit = fmt.Sprintf("%d%s", details.Quota, suffix)
+		}
+		w.Println(name, limit, fmt.Sprintf("%d%s", details.Usage, suffix))
+	}
+	return tw.Flush()
+}
+
+// NewModelSetQuotaCommand returns a command to set model quotas.
+func NewModelSetQuotaCommand() cmd.Command {
+	return modelcmd.Wrap(&modelSetQuotaCommand{})
+}
+
+// modelSetQuotaCommand sets the resource quotas of a model.
+type modelSetQuotaCommand struct {
+	modelcmd.ModelCommandBase
+	api    QuotaAPI
+	Quotas quota.ModelResources
+}
+
+func (c *modelSetQuotaCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set-model-quota",
+		Args:     "<quota>=<value> ...",
+		Purpose:  "Sets resource quotas on a model.",
+		Doc:      setQuotaDoc,
+		Examples: setQuotaDocExamples,
+		SeeAlso: []string{
+			"models",
+			"model-quota",
+		},
+	})
+}
+
+func (c *modelSetQuotaCommand) Init(args []string) (err error) {
+	if len(args) == 0 {
+		return errors.New("no quotas specified")
+	}
+	c.Quotas, err = quota.ParseModelResources(args...)
+	return err
+}
+
+func (c *modelSetQuotaCommand) getAPI() (QuotaAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelconfig.NewClient(root), nil
+}
+
+func (c *modelSetQuotaCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.SetModelQuotas(c.Quotas)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/core/quota"
+	"github.com/juju/juju/testing"
+)
+
+type ModelQuotaCommandsSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeQuotaAPI
+}
+
+var _ = gc.Suite(&ModelQuotaCommandsSuite{})
+
+func (s *ModelQuotaCommandsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeQuotaAPI{}
+}
+
+func (s *ModelQuotaCommandsSuite) TestSetInit(c *gc.C) {
+	for _, test := range []struct {
+		args []string
+		err  string
+	}{
+		{
+			args: []string{},
+			err:  "no quotas specified",
+		}, {
+			args: []string{"machines"},
+			err:  `quota "machines" not valid`,
+		}, {
+			args: []string{"pods=3"},
+			err:  `bad "pods" quota: unknown quota; valid quotas are cores, machines, memory, storage, units`,
+		}, {
+			args: []string{"machines=10", "memory=16G"},
+		},
+	} {
+		err := cmdtesting.InitCommand(model.NewModelSetQuotaCommandForTest(s.fake), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *ModelQuotaCommandsSuite) TestGetInit(c *gc.C) {
+	err := cmdtesting.InitCommand(model.NewModelGetQuotaCommandForTest(s.fake), []string{"machines"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["machines"\]`)
+}
+
+func (s *ModelQuotaCommandsSuite) TestSet(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewModelSetQuotaCommandForTest(s.fake), "machines=10", "memory=16G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.quotas, jc.DeepEquals, quota.ModelResources{Machines: 10, Memory: 16384})
+}
+
+func (s *ModelQuotaCommandsSuite) TestSetBlocked(c *gc.C) {
+	s.fake.err = apiservererrors.OperationBlockedError("TestSetBlocked")
+	_, err := cmdtesting.RunCommand(c, model.NewModelSetQuotaCommandForTest(s.fake), "machines=10")
+	testing.AssertOperationWasBlocked(c, err, ".*TestSetBlocked.*")
+}
+
+func (s *ModelQuotaCommandsSuite) TestGetTabular(c *gc.C) {
+	s.fake.quotas = quota.ModelResources{Machines: 10, Memory: 16384}
+	s.fake.usage = quota.ModelResources{Machines: 2, Units: 3, Cores: 4, Memory: 8192}
+	ctx, err := cmdtesting.RunCommand(c, model.NewModelGetQuotaCommandForTest(s.fake))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Resource  Quota   Usage
+machines  10      2
+units     -       3
+cores     -       4
+memory    16384M  8192M
+storage   -       0M
+`[1:])
+}
+
+func (s *ModelQuotaCommandsSuite) TestGetYAML(c *gc.C) {
+	s.fake.quotas = quota.ModelResources{Units: 5}
+	s.fake.usage = quota.ModelResources{Units: 3}
+	ctx, err := cmdtesting.RunCommand(c, model.NewModelGetQuotaCommandForTest(s.fake), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+cores:
+  usage: 0
+machines:
+  usage: 0
+memory:
+  usage: 0
+storage:
+  usage: 0
+units:
+  quota: 5
+  usage: 3
+`[1:])
+}
+
+type fakeQuotaAPI struct {
+	quotas quota.ModelResources
+	usage  quota.ModelResources
+	err    error
+}
+
+func (f *fakeQuotaAPI) Close() error {
+	return nil
+}
+
+func (f *fakeQuotaAPI) GetModelQuotas() (quota.ModelResources, quota.ModelResources, error) {
+	return f.quotas, f.usage, f.err
+}
+
+func (f *fakeQuotaAPI) SetModelQuotas(quotas quota.ModelResources) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.quotas = quotas
+	return nil
+}
@@ -49,12 +49,21 @@ Any key values override file content if both are specified.
 To rotate the backend access credential/token (if specified), use
 the "token-rotate" config and supply a duration.
 
+A "file" backend stores secret content encrypted with an AES-256 key
+in a directory on the controller. Only the controller uses the key and
+the directory; agents read and write secret content through the
+controller. The "token-rotate" config rotates the encryption key,
+re-encrypting the stored content. As the directory is local to the
+controller, a "file" backend cannot be used with a controller which
+has more than one API server, and HA cannot be enabled while one exists.
+
 `
 
 const addSecretBackendsExamples = `
     juju add-secret-backend myvault vault --config /path/to/cfg.yaml
     juju add-secret-backend myvault vault token-rotate=10m --config /path/to/cfg.yaml
     juju add-secret-backend myvault vault endpoint=https://vault.io:8200 token=s.1wshwhw
+    juju add-secret-backend myfile file path=/var/lib/juju/secrets key=$(head -c32 /dev/urandom | base64)
 `
 
 // AddSecretBackendsAPI is the secrets client API.
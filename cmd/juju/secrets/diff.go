@@ -0,0 +1,261 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	apisecrets "github.com/juju/juju/api/client/secrets"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	coresecrets "github.com/juju/juju/core/secrets"
+)
+
+type secretDiffCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+
+	listSecretsAPIFunc func() (ListSecretsAPI, error)
+	uri                *coresecrets.URI
+	name               string
+	revealSecrets      bool
+	from               int
+	to                 int
+}
+
+var secretDiffDoc = `
+Shows the keys which were added, removed or changed between two
+revisions of a secret.
+
+Values are masked unless the '--reveal' option is used. Comparing
+revisions requires reading the secret content, so only controller
+and model admins may run this command.
+
+If --to is not specified, the latest revision is used. If --from is
+not specified, the revision before --to is used.
+`
+
+const secretDiffExamples = `
+    juju secret-diff my-secret
+    juju secret-diff secret:9m4e2mr0ui3e8a215n4g --from 1 --to 3
+    juju secret-diff 9m4e2mr0ui3e8a215n4g --from 2 --reveal --format yaml
+`
+
+// NewSecretDiffCommand returns a command to compare secret revisions.
+func NewSecretDiffCommand() cmd.Command {
+	c := &secretDiffCommand{}
+	c.listSecretsAPIFunc = c.secretsAPI
+
+	return modelcmd.Wrap(c)
+}
+
+func (c *secretDiffCommand) secretsAPI() (ListSecretsAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apisecrets.NewClient(root), nil
+}
+
+// Info implements cmd.Info.
+func (c *secretDiffCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "secret-diff",
+		Args:     "<ID>|<name>",
+		Purpose:  "Shows the differences between two revisions of a secret.",
+		Doc:      secretDiffDoc,
+		Examples: secretDiffExamples,
+		SeeAlso: []string{
+			"show-secret",
+			"update-secret",
+		},
+	})
+}
+
+// SetFlags implements cmd.SetFlags.
+func (c *secretDiffCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.revealSecrets, "reveal", false, "Reveal the changed secret values")
+	f.IntVar(&c.from, "from", 0, "The revision to compare from (defaults to the revision before --to)")
+	f.IntVar(&c.to, "to", 0, "The revision to compare to (defaults to latest)")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSecretDiffTabular,
+	})
+}
+
+// Init implements cmd.Init.
+func (c *secretDiffCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.Errorf("secret ID is required")
+	}
+	uri, err := coresecrets.ParseURI(args[0])
+	if err != nil {
+		c.name = args[0]
+	}
+	c.uri = uri
+	if c.from < 0 || c.to < 0 {
+		return errors.New("revision must be a positive integer")
+	}
+	if c.to > 0 && c.from >= c.to {
+		return errors.New("--from revision must be before --to revision")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+const maskedValue = "*****"
+
+type secretKeyChange struct {
+	Key    string `json:"key" yaml:"key"`
+	Change string `json:"change" yaml:"change"`
+	From   string `json:"from,omitempty" yaml:"from,omitempty"`
+	To     string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+type secretDiff struct {
+	From    int               `json:"from" yaml:"from"`
+	To      int               `json:"to" yaml:"to"`
+	Changes []secretKeyChange `json:"changes" yaml:"changes"`
+}
+
+// Run implements cmd.Run.
+func (c *secretDiffCommand) Run(ctxt *cmd.Context) error {
+	api, err := c.listSecretsAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	to := c.to
+	if to == 0 {
+		md, err := c.secretRevision(api, nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		to = md.Metadata.LatestRevision
+	}
+	from := c.from
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 || from >= to {
+		return errors.Errorf("secret has no revision before revision %d to compare with", to)
+	}
+
+	fromDetails, err := c.secretRevision(api, &from)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	toDetails, err := c.secretRevision(api, &to)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fromValues, err := fromDetails.Value.Values()
+	if err != nil {
+		return errors.Annotatef(err, "decoding revision %d", from)
+	}
+	toValues, err := toDetails.Value.Values()
+	if err != nil {
+		return errors.Annotatef(err, "decoding revision %d", to)
+	}
+	return c.out.Write(ctxt, secretDiff{
+		From:    from,
+		To:      to,
+		Changes: diffSecretData(fromValues, toValues, c.revealSecrets),
+	})
+}
+
+// secretRevision returns the secret details and content of the
+// specified revision, or the metadata for the latest revision if
+// revision is nil.
+func (c *secretDiffCommand) secretRevision(api ListSecretsAPI, revision *int) (*apisecrets.SecretDetails, error) {
+	filter := coresecrets.Filter{
+		URI:      c.uri,
+		Revision: revision,
+	}
+	if c.name != "" {
+		filter.Label = &c.name
+	}
+	reveal := revision != nil
+	result, err := api.ListSecrets(reveal, filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(result) == 0 {
+		if c.uri != nil {
+			return nil, errors.NotFoundf("secret %q", c.uri.ID)
+		}
+		return nil, errors.NotFoundf("secret %q", c.name)
+	}
+	details := result[0]
+	if details.Error != "" {
+		return nil, errors.New(details.Error)
+	}
+	if reveal && details.Value == nil {
+		return nil, errors.NotFoundf("content for revision %d", *revision)
+	}
+	return &details, nil
+}
+
+func diffSecretData(from, to coresecrets.SecretData, reveal bool) []secretKeyChange {
+	mask := func(v string) string {
+		if reveal {
+			return v
+		}
+		return maskedValue
+	}
+	changes := []secretKeyChange{}
+	for k, v := range from {
+		toV, ok := to[k]
+		if !ok {
+			changes = append(changes, secretKeyChange{Key: k, Change: "removed", From: mask(v)})
+		} else if toV != v {
+			changes = append(changes, secretKeyChange{Key: k, Change: "changed", From: mask(v), To: mask(toV)})
+		}
+	}
+	for k, v := range to {
+		if _, ok := from[k]; !ok {
+			changes = append(changes, secretKeyChange{Key: k, Change: "added", To: mask(v)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// formatSecretDiffTabular writes a tabular summary of the changes
+// between secret revisions.
+func formatSecretDiffTabular(writer io.Writer, value interface{}) error {
+	diff, ok := value.(secretDiff)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", diff, value)
+	}
+	if len(diff.Changes) == 0 {
+		_, err := fmt.Fprintf(writer, "No changes between revisions %d and %d.\n", diff.From, diff.To)
+		return err
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Key", "Change", fmt.Sprintf("Revision %d", diff.From), fmt.Sprintf("Revision %d", diff.To))
+	for _, change := range diff.Changes {
+		from, to := change.From, change.To
+		if from == "" {
+			from = "-"
+		}
+		if to == "" {
+			to = "-"
+		}
+		w.Println(change.Key, change.Change, from, to)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	apisecrets "github.com/juju/juju/api/client/secrets"
+	"github.com/juju/juju/cmd/juju/secrets"
+	"github.com/juju/juju/cmd/juju/secrets/mocks"
+	coresecrets "github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/jujuclient"
+)
+
+type DiffSuite struct {
+	jujutesting.IsolationSuite
+	store      *jujuclient.MemStore
+	secretsAPI *mocks.MockListSecretsAPI
+}
+
+var _ = gc.Suite(&DiffSuite{})
+
+func (s *DiffSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	store := jujuclient.NewMemStore()
+	store.Controllers["mycontroller"] = jujuclient.ControllerDetails{}
+	store.CurrentControllerName = "mycontroller"
+	s.store = store
+}
+
+func (s *DiffSuite) setup(c *gc.C) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+
+	s.secretsAPI = mocks.NewMockListSecretsAPI(ctrl)
+
+	return ctrl
+}
+
+func (s *DiffSuite) TestInit(c *gc.C) {
+	uri := coresecrets.NewURI()
+	_, err := cmdtesting.RunCommand(c, secrets.NewSecretDiffCommandForTest(s.store, s.secretsAPI))
+	c.Assert(err, gc.ErrorMatches, "secret ID is required")
+	_, err = cmdtesting.RunCommand(c, secrets.NewSecretDiffCommandForTest(s.store, s.secretsAPI), uri.ID, "--from", "-1")
+	c.Assert(err, gc.ErrorMatches, "revision must be a positive integer")
+	_, err = cmdtesting.RunCommand(c, secrets.NewSecretDiffCommandForTest(s.store, s.secretsAPI), uri.ID, "--from", "3", "--to", "2")
+	c.Assert(err, gc.ErrorMatches, "--from revision must be before --to revision")
+}
+
+func (s *DiffSuite) expectRevision(uri *coresecrets.URI, revision int, data map[string]string) {
+	s.secretsAPI.EXPECT().ListSecrets(true, coresecrets.Filter{
+		URI:      uri,
+		Revision: ptr(revision),
+	}).Return([]apisecrets.SecretDetails{{
+		Metadata: coresecrets.SecretMetadata{URI: uri, LatestRevision: 3},
+		Value:    coresecrets.NewSecretValue(data),
+	}}, nil)
+}
+
+func (s *DiffSuite) TestDiffLatest(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	uri := coresecrets.NewURI()
+	s.secretsAPI.EXPECT().ListSecrets(false, coresecrets.Filter{URI: uri}).Return(
+		[]apisecrets.SecretDetails{{
+			Metadata: coresecrets.SecretMetadata{URI: uri, LatestRevision: 3},
+		}}, nil)
+	// Values are base64 encoded: bar, baz, qux.
+	s.expectRevision(uri, 2, map[string]string{"foo": "YmFy", "gone": "YmFy", "same": "YmFy"})
+	s.expectRevision(uri, 3, map[string]string{"foo": "YmF6", "new": "cXV4", "same": "YmFy"})
+	s.secretsAPI.EXPECT().Close().Return(nil)
+
+	ctx, err := cmdtesting.RunCommand(c, secrets.NewSecretDiffCommandForTest(s.store, s.secretsAPI), uri.String())
+	c.Assert(err, jc.ErrorIsNil)
+	out := cmdtesting.Stdout(ctx)
+	c.Assert(out, gc.Equals, `
+Key   Change   Revision 2  Revision 3
+foo   changed  *****       *****
+gone  removed  *****       -
+new   added    -           *****
+`[1:])
+}
+
+func (s *DiffSuite) TestDiffReveal(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	uri := coresecrets.NewURI()
+	s.expectRevision(uri, 1, map[string]string{"foo": "YmFy"})
+	s.expectRevision(uri, 3, map[string]string{"foo": "YmF6"})
+	s.secretsAPI.EXPECT().Close().Return(nil)
+
+	ctx, err := cmdtesting.RunCommand(c, secrets.NewSecretDiffCommandForTest(s.store, s.secretsAPI),
+		uri.String(), "--from", "1", "--to", "3", "--reveal", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	out := cmdtesting.Stdout(ctx)
+	c.Assert(out, gc.Equals, `
+from: 1
+to: 3
+changes:
+- key: foo
+  change: changed
+  from: bar
+  to: baz
+`[1:])
+}
+
+func (s *DiffSuite) TestDiffNoChanges(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	uri := coresecrets.NewURI()
+	s.expectRevision(uri, 1, map[string]string{"foo": "YmFy"})
+	s.expectRevision(uri, 2, map[string]string{"foo": "YmFy"})
+	s.secretsAPI.EXPECT().Close().Return(nil)
+
+	ctx, err := cmdtesting.RunCommand(c, secrets.NewSecretDiffCommandForTest(s.store, s.secretsAPI),
+		uri.String(), "--to", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "No changes between revisions 1 and 2.\n")
+}
@@ -96,6 +96,7 @@ type secretRevisionDetails struct {
 	CreateTime time.Time  `json:"created" yaml:"created"`
 	UpdateTime time.Time  `json:"updated" yaml:"updated"`
 	ExpireTime *time.Time `json:"expires,omitempty" yaml:"expires,omitempty"`
+	RollbackTo int        `json:"rollback-to,omitempty" yaml:"rollback-to,omitempty"`
 }
 
 type secretDetailsByID map[string]secretDisplayDetails
@@ -184,6 +185,7 @@ func gatherSecretInfo(secrets []apisecrets.SecretDetails, reveal, includeRevisio
 					CreateTime: r.CreateTime,
 					UpdateTime: r.UpdateTime,
 					ExpireTime: r.ExpireTime,
+					RollbackTo: r.RollbackTo,
 				}
 				if r.BackendName != nil {
 					rev.Backend = *r.BackendName
@@ -220,6 +220,20 @@ func (mr *MockUpdateSecretsAPIMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockUpdateSecretsAPI)(nil).Close))
 }
 
+// RollbackSecret mocks base method.
+func (m *MockUpdateSecretsAPI) RollbackSecret(arg0 *secrets0.URI, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackSecret", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackSecret indicates an expected call of RollbackSecret.
+func (mr *MockUpdateSecretsAPIMockRecorder) RollbackSecret(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackSecret", reflect.TypeOf((*MockUpdateSecretsAPI)(nil).RollbackSecret), arg0, arg1, arg2)
+}
+
 // UpdateSecret mocks base method.
 func (m *MockUpdateSecretsAPI) UpdateSecret(arg0 *secrets0.URI, arg1 string, arg2 *bool, arg3, arg4 string, arg5 map[string]string) error {
 	m.ctrl.T.Helper()
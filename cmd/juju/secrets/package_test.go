@@ -79,3 +79,12 @@ func NewShowCommandForTest(store jujuclient.ClientStore, listSecretsAPI ListSecr
 	c.SetClientStore(store)
 	return c
 }
+
+// NewSecretDiffCommandForTest returns a secret-diff command for testing.
+func NewSecretDiffCommandForTest(store jujuclient.ClientStore, listSecretsAPI ListSecretsAPI) *secretDiffCommand {
+	c := &secretDiffCommand{
+		listSecretsAPIFunc: func() (ListSecretsAPI, error) { return listSecretsAPI, nil },
+	}
+	c.SetClientStore(store)
+	return c
+}
@@ -24,8 +24,9 @@ type updateSecretCommand struct {
 	secretURI *secrets.URI
 	autoPrune common.AutoBoolValue
 
-	name    string
-	newName string
+	name       string
+	newName    string
+	rollbackTo int
 }
 
 // UpdateSecretsAPI is the secrets client API.
@@ -34,6 +35,7 @@ type UpdateSecretsAPI interface {
 		uri *secrets.URI, name string, autoPrune *bool,
 		newName, description string, data map[string]string,
 	) error
+	RollbackSecret(uri *secrets.URI, name string, revision int) error
 	Close() error
 }
 
@@ -62,6 +64,10 @@ The --auto-prune option is used to allow Juju to automatically remove revisions
 which are no longer being tracked by any observers (see Rotation and Expiry).
 This is configured per revision. This feature is opt-in because Juju 
 automatically removing secret content might result in data loss.
+The --rollback-to option creates a new revision with the content of the
+specified older revision. The older revision is kept, and the new
+revision records which revision it was rolled back to. Use
+'juju secret-diff' to compare revisions before rolling back.
 
 `
 	updateSecretExamples = `
@@ -76,6 +82,7 @@ automatically removing secret content might result in data loss.
     juju update-secret secret:9m4e2mr0ui3e8a215n4g --name db-password \
         --info "my database password" \
         --file=/path/to/file
+    juju update-secret secret:9m4e2mr0ui3e8a215n4g --rollback-to 2
 `
 )
 
@@ -99,13 +106,25 @@ func (c *updateSecretCommand) Init(args []string) error {
 	if c.secretURI, err = secrets.ParseURI(args[0]); err != nil {
 		c.name = args[0]
 	}
-	return c.SecretUpsertContentCommand.Init(args[1:])
+	if err := c.SecretUpsertContentCommand.Init(args[1:]); err != nil {
+		return errors.Trace(err)
+	}
+	if c.rollbackTo < 0 {
+		return errors.New("rollback revision must be a positive integer")
+	}
+	if c.rollbackTo > 0 {
+		if len(c.Data) > 0 || c.Description != "" || c.newName != "" || c.autoPrune.Get() != nil {
+			return errors.New("--rollback-to cannot be combined with other updates")
+		}
+	}
+	return nil
 }
 
 func (c *updateSecretCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.SecretUpsertContentCommand.SetFlags(f)
 	f.StringVar(&c.newName, "name", "", "the new secret name")
 	f.Var(&c.autoPrune, "auto-prune", "used to allow Juju to automatically remove revisions which are no longer being tracked by any observers")
+	f.IntVar(&c.rollbackTo, "rollback-to", 0, "create a new revision with the content of the specified revision")
 }
 
 // Run implements cmd.Command.
@@ -115,5 +134,8 @@ func (c *updateSecretCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	defer func() { _ = secretsAPI.Close() }()
+	if c.rollbackTo > 0 {
+		return secretsAPI.RollbackSecret(c.secretURI, c.name, c.rollbackTo)
+	}
 	return secretsAPI.UpdateSecret(c.secretURI, c.name, c.autoPrune.Get(), c.newName, c.Description, c.Data)
 }
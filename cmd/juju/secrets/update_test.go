@@ -122,3 +122,25 @@ foo: bar
 	)
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *updateSuite) TestUpdateRollback(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	uri := coresecrets.NewURI()
+	s.secretsAPI.EXPECT().RollbackSecret(uri, "", 2).Return(nil)
+	s.secretsAPI.EXPECT().Close().Return(nil)
+
+	_, err := cmdtesting.RunCommand(c, secrets.NewUpdateCommandForTest(
+		s.store, s.secretsAPI), uri.String(), "--rollback-to", "2",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *updateSuite) TestUpdateRollbackWithContent(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	_, err := cmdtesting.RunCommand(c, secrets.NewUpdateCommandForTest(
+		s.store, s.secretsAPI), coresecrets.NewURI().String(), "foo=bar", "--rollback-to", "2",
+	)
+	c.Assert(err, gc.ErrorMatches, `--rollback-to cannot be combined with other updates`)
+}
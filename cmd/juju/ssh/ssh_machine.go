@@ -48,6 +48,23 @@ type sshMachine struct {
 	hostChecker            jujussh.ReachableChecker
 	retryStrategy          retry.CallArgs
 	publicKeyRetryStrategy retry.CallArgs
+
+	// jumpHosts is set when the API connection is made through SSH
+	// jump hosts, in which case SSH connections are made through
+	// them too.
+	jumpHosts jumpHostProxier
+}
+
+// jumpHostProxier is implemented by API connection proxies that
+// connect through SSH jump hosts.
+type jumpHostProxier interface {
+	// RemoteAddress returns the API address that the proxy
+	// connects to from the last jump host.
+	RemoteAddress() string
+
+	// ProxyCommand returns an OpenSSH command that connects to
+	// the target address through the jump hosts.
+	ProxyCommand(target string) []string
 }
 
 type statusClient interface {
@@ -216,6 +233,10 @@ func (c *sshMachine) getSSHOptions(enablePty bool, targets ...*resolvedTarget) (
 		if err := c.setProxyCommand(&options, targets); err != nil {
 			return nil, err
 		}
+	} else if c.jumpHosts != nil {
+		// When proxying through the controller, the proxy command
+		// runs juju ssh, which itself uses the jump hosts.
+		options.SetProxyCommand(c.jumpHosts.ProxyCommand("%h:%p")...)
 	}
 
 	return &options, nil
@@ -417,6 +438,12 @@ func (c *sshMachine) ensureAPIClient(mc ModelCommand) error {
 
 	c.sshClient = sshclient.NewFacade(conn)
 	c.apiAddr = conn.Addr()
+	if p, ok := conn.Proxy().(jumpHostProxier); ok {
+		// The connection address is the local end of the
+		// tunnel, so use the controller's address instead.
+		c.jumpHosts = p
+		c.apiAddr = p.RemoteAddress()
+	}
 	c.statusClient = apiclient.NewClient(conn, logger)
 	return nil
 }
@@ -91,3 +91,43 @@ func NewDetachStorageCommandForTest(new NewEntityDetacherCloserFunc, store jujuc
 	cmd.newEntityDetacherCloser = new
 	return modelcmd.Wrap(cmd)
 }
+
+func NewSnapshotStorageCommandForTest(api StorageSnapshotAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &snapshotStorageCommand{newAPIFunc: func() (StorageSnapshotAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+func NewListStorageSnapshotsCommandForTest(api StorageSnapshotListAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &listStorageSnapshotsCommand{newAPIFunc: func() (StorageSnapshotListAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+func NewRestoreStorageCommandForTest(api StorageSnapshotRestoreAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &restoreStorageCommand{newAPIFunc: func() (StorageSnapshotRestoreAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+func NewRemoveStorageSnapshotCommandForTest(api StorageSnapshotRemoveAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &removeStorageSnapshotCommand{newAPIFunc: func() (StorageSnapshotRemoveAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+func NewResizeStorageCommandForTest(api StorageResizeAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &resizeStorageCommand{newAPIFunc: func() (StorageResizeAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
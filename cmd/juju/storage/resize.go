@@ -0,0 +1,111 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils/v3"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const resizeStorageCommandDoc = `
+Grows the volume backing a storage instance to the specified size.
+
+The size is a number optionally followed by one of the units M, G,
+T, P or E; a number with no unit is taken to be in mebibytes. Storage
+can only be grown, and only if its storage provider supports resizing
+volumes.
+
+The volume is resized asynchronously by the storage provider. Once it
+has been resized, any filesystem on the volume is grown to fill it, and
+the "storage-resized" hook is run in each unit that the storage is
+attached to. Use "juju show-storage" to follow the resize's progress.
+`
+
+const resizeStorageCommandExamples = `
+    juju resize-storage pgdata/0 20G
+    juju resize-storage pgdata/0 20480
+`
+
+// NewResizeStorageCommand returns a command that resizes a storage
+// instance.
+func NewResizeStorageCommand() cmd.Command {
+	cmd := &resizeStorageCommand{}
+	cmd.newAPIFunc = func() (StorageResizeAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// resizeStorageCommand resizes a storage instance.
+type resizeStorageCommand struct {
+	StorageCommandBase
+	storageId  string
+	size       uint64
+	newAPIFunc func() (StorageResizeAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *resizeStorageCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("must specify a storage ID and size")
+	case 1:
+		return errors.New("must specify a size")
+	}
+	c.storageId, args = args[0], args[1:]
+	if !names.IsValidStorage(c.storageId) {
+		return errors.Errorf("invalid storage ID %v", c.storageId)
+	}
+	size, err := utils.ParseSize(args[0])
+	if err != nil {
+		return errors.Annotate(err, "cannot parse size")
+	}
+	if size == 0 {
+		return errors.New("size must be greater than zero")
+	}
+	c.size = size
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Info implements Command.Info.
+func (c *resizeStorageCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "resize-storage",
+		Args:     "<storage ID> <size>",
+		Purpose:  "Grows a storage instance.",
+		Doc:      resizeStorageCommandDoc,
+		Examples: resizeStorageCommandExamples,
+		SeeAlso: []string{
+			"storage",
+			"show-storage",
+		},
+	})
+}
+
+// Run implements Command.Run.
+func (c *resizeStorageCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := api.ResizeStorage(c.storageId, c.size); err != nil {
+		return err
+	}
+	ctx.Infof("resizing storage %s to %dMiB", c.storageId, c.size)
+	return nil
+}
+
+// StorageResizeAPI defines the API methods that the resize-storage
+// command uses.
+type StorageResizeAPI interface {
+	Close() error
+	ResizeStorage(storageId string, size uint64) error
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/storage"
+)
+
+type ResizeSuite struct {
+	SubStorageSuite
+	api *mockResizeAPI
+}
+
+var _ = gc.Suite(&ResizeSuite{})
+
+func (s *ResizeSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.api = &mockResizeAPI{}
+}
+
+func (s *ResizeSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewResizeStorageCommandForTest(s.api, s.store), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stderr(ctx), nil
+}
+
+func (s *ResizeSuite) TestResizeStorage(c *gc.C) {
+	stderr, err := s.run(c, "pgdata/0", "20G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, "resizing storage pgdata/0 to 20480MiB\n")
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"ResizeStorage", []interface{}{"pgdata/0", uint64(20480)}},
+		{"Close", nil},
+	})
+}
+
+func (s *ResizeSuite) TestResizeStorageDefaultUnit(c *gc.C) {
+	_, err := s.run(c, "pgdata/0", "2048")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "ResizeStorage", "pgdata/0", uint64(2048))
+}
+
+func (s *ResizeSuite) TestResizeStorageError(c *gc.C) {
+	s.api.SetErrors(errors.New("storage pgdata/0 cannot be shrunk"))
+	_, err := s.run(c, "pgdata/0", "1G")
+	c.Assert(err, gc.ErrorMatches, "storage pgdata/0 cannot be shrunk")
+}
+
+func (s *ResizeSuite) TestInitErrors(c *gc.C) {
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "must specify a storage ID and size",
+	}, {
+		args: []string{"pgdata/0"},
+		err:  "must specify a size",
+	}, {
+		args: []string{"pgdata", "1G"},
+		err:  "invalid storage ID pgdata",
+	}, {
+		args: []string{"pgdata/0", "lots"},
+		err:  "cannot parse size: .*",
+	}, {
+		args: []string{"pgdata/0", "0"},
+		err:  "size must be greater than zero",
+	}, {
+		args: []string{"pgdata/0", "1G", "2G"},
+		err:  `unrecognized args: \["2G"\]`,
+	}} {
+		_, err := s.run(c, t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+	s.api.CheckNoCalls(c)
+}
+
+type mockResizeAPI struct {
+	jujutesting.Stub
+}
+
+func (m *mockResizeAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockResizeAPI) ResizeStorage(storageId string, size uint64) error {
+	m.MethodCall(m, "ResizeStorage", storageId, size)
+	return m.NextErr()
+}
@@ -0,0 +1,500 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/rpc/params"
+)
+
+// SnapshotInfo defines the serialization behaviour of volume snapshot
+// information.
+type SnapshotInfo struct {
+	Storage    string       `yaml:"storage,omitempty" json:"storage,omitempty"`
+	Volume     string       `yaml:"volume" json:"volume"`
+	Kind       string       `yaml:"kind" json:"kind"`
+	Pool       string       `yaml:"pool" json:"pool"`
+	ProviderId string       `yaml:"provider-id,omitempty" json:"provider-id,omitempty"`
+	Size       uint64       `yaml:"size,omitempty" json:"size,omitempty"`
+	Created    string       `yaml:"created" json:"created"`
+	Life       string       `yaml:"life,omitempty" json:"life,omitempty"`
+	Status     EntityStatus `yaml:"status" json:"status"`
+}
+
+// formatSnapshotDetails takes a set of VolumeSnapshotDetails and
+// creates a mapping from snapshot ID to snapshot information.
+func formatSnapshotDetails(snapshots []params.VolumeSnapshotDetails, isoTime bool) (map[string]SnapshotInfo, error) {
+	output := make(map[string]SnapshotInfo)
+	for _, details := range snapshots {
+		volumeTag, err := names.ParseVolumeTag(details.VolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var storageId string
+		if details.StorageTag != "" {
+			storageTag, err := names.ParseStorageTag(details.StorageTag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			storageId = storageTag.Id()
+		}
+		created := details.Created
+		output[details.Id] = SnapshotInfo{
+			Storage:    storageId,
+			Volume:     volumeTag.Id(),
+			Kind:       details.Kind.String(),
+			Pool:       details.Pool,
+			ProviderId: details.SnapshotId,
+			Size:       details.Size,
+			Created:    common.FormatTime(&created, isoTime),
+			Life:       string(details.Life),
+			Status: EntityStatus{
+				details.Status.Status,
+				details.Status.Info,
+				common.FormatTime(details.Status.Since, isoTime),
+			},
+		}
+	}
+	return output, nil
+}
+
+// collectSnapshotDetails separates the successful results from the
+// errors, combining the latter into a single error.
+func collectSnapshotDetails(results []params.VolumeSnapshotDetailsResult) ([]params.VolumeSnapshotDetails, error) {
+	var errs params.ErrorResults
+	var valid []params.VolumeSnapshotDetails
+	for _, result := range results {
+		if result.Error != nil {
+			errs.Results = append(errs.Results, params.ErrorResult{result.Error})
+			continue
+		}
+		valid = append(valid, *result.Result)
+	}
+	if len(errs.Results) > 0 {
+		return valid, errs.Combine()
+	}
+	return valid, nil
+}
+
+func validateStorageIds(ids []string) error {
+	for _, id := range ids {
+		if !names.IsValidStorage(id) {
+			return errors.Errorf("invalid storage ID %v", id)
+		}
+	}
+	return nil
+}
+
+// formatSnapshotListTabular returns a tabular summary of volume snapshots.
+func formatSnapshotListTabular(writer io.Writer, value interface{}) error {
+	snapshots, ok := value.(map[string]SnapshotInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", snapshots, value)
+	}
+	tw := output.TabWriter(writer)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+
+	print("Snapshot", "Storage", "Pool", "Size", "Status", "Created", "Message")
+
+	ids := make([]string, 0, len(snapshots))
+	for id := range snapshots {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		info := snapshots[id]
+		var size string
+		if info.Size > 0 {
+			size = humanize.IBytes(info.Size * humanize.MiByte)
+		}
+		print(
+			id, info.Storage, info.Pool, size,
+			string(info.Status.Current), info.Created, info.Status.Message,
+		)
+	}
+	return tw.Flush()
+}
+
+const snapshotStorageCommandDoc = `
+Takes a point-in-time snapshot of the volumes backing the specified
+storage instances.
+
+Snapshots are taken asynchronously by the storage provider; use
+"juju storage-snapshots" to follow their progress. Only storage whose
+provider supports snapshots, and which is backed by a volume, may be
+snapshotted. The kubernetes storage provider does not support
+snapshots, as the volumes of Kubernetes units are created by their
+application's statefulset, which cannot create them from snapshots.
+`
+
+const snapshotStorageCommandExamples = `
+    juju snapshot-storage pgdata/0
+    juju snapshot-storage pgdata/0 pgdata/1
+`
+
+// NewSnapshotStorageCommand returns a command that snapshots storage
+// instances.
+func NewSnapshotStorageCommand() cmd.Command {
+	cmd := &snapshotStorageCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// snapshotStorageCommand snapshots storage instances.
+type snapshotStorageCommand struct {
+	StorageCommandBase
+	ids        []string
+	newAPIFunc func() (StorageSnapshotAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *snapshotStorageCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("must specify storage ID(s)")
+	}
+	if err := validateStorageIds(args); err != nil {
+		return err
+	}
+	c.ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *snapshotStorageCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "snapshot-storage",
+		Args:     "<storage ID> [...]",
+		Purpose:  "Snapshots storage instances.",
+		Doc:      snapshotStorageCommandDoc,
+		Examples: snapshotStorageCommandExamples,
+		SeeAlso: []string{
+			"storage-snapshots",
+			"restore-storage",
+			"remove-storage-snapshot",
+		},
+	})
+}
+
+// Run implements Command.Run.
+func (c *snapshotStorageCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.SnapshotStorage(c.ids)
+	if err != nil {
+		return err
+	}
+	snapshots, err := collectSnapshotDetails(results)
+	for _, snapshot := range snapshots {
+		ctx.Infof("snapshotting %s as %s", snapshot.StorageName, snapshot.Id)
+	}
+	return err
+}
+
+// StorageSnapshotAPI defines the API methods that the snapshot-storage
+// command uses.
+type StorageSnapshotAPI interface {
+	Close() error
+	SnapshotStorage(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error)
+}
+
+const listStorageSnapshotsCommandDoc = `
+Lists the snapshots of the specified storage instances, or of all
+storage in the model if none are specified.
+`
+
+const listStorageSnapshotsCommandExamples = `
+    juju storage-snapshots
+    juju storage-snapshots pgdata/0 --format yaml
+`
+
+// NewListStorageSnapshotsCommand returns a command that lists volume
+// snapshots.
+func NewListStorageSnapshotsCommand() cmd.Command {
+	cmd := &listStorageSnapshotsCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotListAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// listStorageSnapshotsCommand lists volume snapshots.
+type listStorageSnapshotsCommand struct {
+	StorageCommandBase
+	ids        []string
+	out        cmd.Output
+	newAPIFunc func() (StorageSnapshotListAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *listStorageSnapshotsCommand) Init(args []string) error {
+	if err := validateStorageIds(args); err != nil {
+		return err
+	}
+	c.ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *listStorageSnapshotsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "storage-snapshots",
+		Args:     "[<storage ID> ...]",
+		Purpose:  "Lists storage snapshots.",
+		Doc:      listStorageSnapshotsCommandDoc,
+		Examples: listStorageSnapshotsCommandExamples,
+		Aliases:  []string{"list-storage-snapshots"},
+		SeeAlso: []string{
+			"snapshot-storage",
+			"restore-storage",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listStorageSnapshotsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSnapshotListTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *listStorageSnapshotsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.ListStorageSnapshots(c.ids)
+	if err != nil {
+		return err
+	}
+	snapshots, err := collectSnapshotDetails(results)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No storage snapshots to display.")
+		return nil
+	}
+	output, err := formatSnapshotDetails(snapshots, false)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, output)
+}
+
+// StorageSnapshotListAPI defines the API methods that the
+// storage-snapshots command uses.
+type StorageSnapshotListAPI interface {
+	Close() error
+	ListStorageSnapshots(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error)
+}
+
+const restoreStorageCommandDoc = `
+Creates a new storage instance from a storage snapshot.
+
+The new storage instance is not attached to any unit. It may be attached
+to an existing unit with "juju attach-storage", or to a new unit with
+"juju add-unit --attach-storage". When the storage is attached, the
+storage provider creates its volume from the snapshot.
+
+Snapshots taken by machine-local storage providers, such as lvm or zfs,
+are held on the machine of the snapshotted volume, so storage restored
+from them can only be attached to units on that machine.
+
+By default the new storage instance has the same storage name as the
+snapshotted storage. Use --name to restore it under a different name.
+`
+
+const restoreStorageCommandExamples = `
+    juju restore-storage pgdata-0@1
+    juju restore-storage pgdata-0@1 --name archive
+`
+
+// NewRestoreStorageCommand returns a command that restores storage
+// from a snapshot.
+func NewRestoreStorageCommand() cmd.Command {
+	cmd := &restoreStorageCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotRestoreAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// restoreStorageCommand restores storage from a snapshot.
+type restoreStorageCommand struct {
+	StorageCommandBase
+	snapshotId  string
+	storageName string
+	newAPIFunc  func() (StorageSnapshotRestoreAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *restoreStorageCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("must specify a snapshot ID")
+	}
+	c.snapshotId, args = args[0], args[1:]
+	if c.storageName != "" {
+		validStorageName, err := regexp.MatchString("^"+names.StorageNameSnippet+"$", c.storageName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !validStorageName {
+			return errors.Errorf("%q is not a valid storage name", c.storageName)
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Info implements Command.Info.
+func (c *restoreStorageCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "restore-storage",
+		Args:     "<snapshot ID>",
+		Purpose:  "Restores storage from a snapshot.",
+		Doc:      restoreStorageCommandDoc,
+		Examples: restoreStorageCommandExamples,
+		SeeAlso: []string{
+			"storage-snapshots",
+			"attach-storage",
+			"add-unit",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *restoreStorageCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	f.StringVar(&c.storageName, "name", "", "Storage name for the restored storage")
+}
+
+// Run implements Command.Run.
+func (c *restoreStorageCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	storageTag, err := api.RestoreStorageSnapshot(c.snapshotId, c.storageName)
+	if err != nil {
+		return err
+	}
+	ctx.Infof("restored snapshot %s as storage %s", c.snapshotId, storageTag.Id())
+	return nil
+}
+
+// StorageSnapshotRestoreAPI defines the API methods that the
+// restore-storage command uses.
+type StorageSnapshotRestoreAPI interface {
+	Close() error
+	RestoreStorageSnapshot(snapshotId, storageName string) (names.StorageTag, error)
+}
+
+const removeStorageSnapshotCommandDoc = `
+Removes storage snapshots from the model, and destroys them in the
+storage provider.
+`
+
+const removeStorageSnapshotCommandExamples = `
+    juju remove-storage-snapshot pgdata-0@1
+`
+
+// NewRemoveStorageSnapshotCommand returns a command that removes
+// storage snapshots.
+func NewRemoveStorageSnapshotCommand() cmd.Command {
+	cmd := &removeStorageSnapshotCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotRemoveAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// removeStorageSnapshotCommand removes storage snapshots.
+type removeStorageSnapshotCommand struct {
+	StorageCommandBase
+	ids        []string
+	newAPIFunc func() (StorageSnapshotRemoveAPI, error)
+}
+
+// Init implements Command.Init.
+func (c *removeStorageSnapshotCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("must specify snapshot ID(s)")
+	}
+	c.ids = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *removeStorageSnapshotCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove-storage-snapshot",
+		Args:     "<snapshot ID> [...]",
+		Purpose:  "Removes storage snapshots.",
+		Doc:      removeStorageSnapshotCommandDoc,
+		Examples: removeStorageSnapshotCommandExamples,
+		SeeAlso: []string{
+			"storage-snapshots",
+		},
+	})
+}
+
+// Run implements Command.Run.
+func (c *removeStorageSnapshotCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.RemoveStorageSnapshots(c.ids)
+	if err != nil {
+		return err
+	}
+	var errs params.ErrorResults
+	for i, result := range results {
+		if result.Error != nil {
+			errs.Results = append(errs.Results, result)
+			continue
+		}
+		ctx.Infof("removing snapshot %s", c.ids[i])
+	}
+	return errs.Combine()
+}
+
+// StorageSnapshotRemoveAPI defines the API methods that the
+// remove-storage-snapshot command uses.
+type StorageSnapshotRemoveAPI interface {
+	Close() error
+	RemoveStorageSnapshots(snapshotIds []string) ([]params.ErrorResult, error)
+}
@@ -0,0 +1,230 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/names/v4"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/rpc/params"
+)
+
+type SnapshotSuite struct {
+	SubStorageSuite
+	api *mockSnapshotAPI
+}
+
+var _ = gc.Suite(&SnapshotSuite{})
+
+func (s *SnapshotSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	created := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.api = &mockSnapshotAPI{
+		snapshots: []params.VolumeSnapshotDetails{{
+			Id:          "0@1",
+			VolumeTag:   "volume-0",
+			StorageTag:  "storage-pgdata-0",
+			StorageName: "pgdata",
+			Kind:        params.StorageKindBlock,
+			Pool:        "ebs",
+			SnapshotId:  "snap-0123",
+			Size:        1024,
+			Created:     created,
+			Life:        life.Alive,
+			Status: params.EntityStatus{
+				Status: "available",
+				Since:  &created,
+			},
+		}, {
+			Id:          "1@1",
+			VolumeTag:   "volume-1",
+			StorageTag:  "storage-pgdata-1",
+			StorageName: "pgdata",
+			Kind:        params.StorageKindBlock,
+			Pool:        "ebs",
+			Created:     created,
+			Life:        life.Alive,
+			Status: params.EntityStatus{
+				Status: "pending",
+				Since:  &created,
+			},
+		}},
+	}
+}
+
+func (s *SnapshotSuite) TestSnapshotStorage(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewSnapshotStorageCommandForTest(s.api, s.store), "pgdata/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "snapshotting pgdata as 0@1\n")
+	s.api.CheckCallNames(c, "SnapshotStorage", "Close")
+	s.api.CheckCall(c, 0, "SnapshotStorage", []string{"pgdata/0"})
+}
+
+func (s *SnapshotSuite) TestSnapshotStorageError(c *gc.C) {
+	s.api.resultErr = &params.Error{Message: "storage provider does not support snapshots"}
+	_, err := cmdtesting.RunCommand(c, storage.NewSnapshotStorageCommandForTest(s.api, s.store), "pgdata/0")
+	c.Assert(err, gc.ErrorMatches, "storage provider does not support snapshots")
+}
+
+func (s *SnapshotSuite) TestSnapshotStorageInvalidId(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewSnapshotStorageCommandForTest(s.api, s.store), "pgdata")
+	c.Assert(err, gc.ErrorMatches, "invalid storage ID pgdata")
+	s.api.CheckNoCalls(c)
+}
+
+func (s *SnapshotSuite) TestSnapshotStorageNoArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewSnapshotStorageCommandForTest(s.api, s.store))
+	c.Assert(err, gc.ErrorMatches, "must specify storage ID\\(s\\)")
+}
+
+func (s *SnapshotSuite) TestListStorageSnapshotsTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewListStorageSnapshotsCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Snapshot  Storage   Pool  Size     Status     Created                Message
+0@1       pgdata/0  ebs   1.0 GiB  available  01 May 2023 12:00:00Z  
+1@1       pgdata/1  ebs            pending    01 May 2023 12:00:00Z  
+`[1:])
+	s.api.CheckCall(c, 0, "ListStorageSnapshots", []string(nil))
+}
+
+func (s *SnapshotSuite) TestListStorageSnapshotsYAML(c *gc.C) {
+	s.api.snapshots = s.api.snapshots[:1]
+	ctx, err := cmdtesting.RunCommand(c,
+		storage.NewListStorageSnapshotsCommandForTest(s.api, s.store),
+		"pgdata/0", "--format", "yaml",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+0@1:
+  storage: pgdata/0
+  volume: "0"
+  kind: block
+  pool: ebs
+  provider-id: snap-0123
+  size: 1024
+  created: 01 May 2023 12:00:00Z
+  life: alive
+  status:
+    current: available
+    since: 01 May 2023 12:00:00Z
+`[1:])
+	s.api.CheckCall(c, 0, "ListStorageSnapshots", []string{"pgdata/0"})
+}
+
+func (s *SnapshotSuite) TestListStorageSnapshotsNone(c *gc.C) {
+	s.api.snapshots = nil
+	ctx, err := cmdtesting.RunCommand(c, storage.NewListStorageSnapshotsCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No storage snapshots to display.\n")
+}
+
+func (s *SnapshotSuite) TestRestoreStorage(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c,
+		storage.NewRestoreStorageCommandForTest(s.api, s.store),
+		"0@1", "--name", "archive",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "restored snapshot 0@1 as storage archive/2\n")
+	s.api.CheckCall(c, 0, "RestoreStorageSnapshot", "0@1", "archive")
+}
+
+func (s *SnapshotSuite) TestRestoreStorageInvalidName(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c,
+		storage.NewRestoreStorageCommandForTest(s.api, s.store),
+		"0@1", "--name", "Archive!",
+	)
+	c.Assert(err, gc.ErrorMatches, `"Archive!" is not a valid storage name`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *SnapshotSuite) TestRestoreStorageTooManyArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c,
+		storage.NewRestoreStorageCommandForTest(s.api, s.store),
+		"0@1", "1@1",
+	)
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["1@1"\]`)
+}
+
+func (s *SnapshotSuite) TestRemoveStorageSnapshot(c *gc.C) {
+	s.api.removeErrs = map[string]*params.Error{
+		"1@1": {Message: "snapshot 1@1 not found", Code: params.CodeNotFound},
+	}
+	ctx, err := cmdtesting.RunCommand(c,
+		storage.NewRemoveStorageSnapshotCommandForTest(s.api, s.store),
+		"0@1", "1@1",
+	)
+	c.Assert(err, gc.ErrorMatches, "snapshot 1@1 not found")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "removing snapshot 0@1\n")
+	s.api.CheckCall(c, 0, "RemoveStorageSnapshots", []string{"0@1", "1@1"})
+}
+
+type mockSnapshotAPI struct {
+	jujutesting.Stub
+	snapshots  []params.VolumeSnapshotDetails
+	resultErr  *params.Error
+	removeErrs map[string]*params.Error
+}
+
+func (m *mockSnapshotAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockSnapshotAPI) SnapshotStorage(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error) {
+	m.MethodCall(m, "SnapshotStorage", storageIds)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	results := make([]params.VolumeSnapshotDetailsResult, len(storageIds))
+	for i := range storageIds {
+		if m.resultErr != nil {
+			results[i].Error = m.resultErr
+			continue
+		}
+		snapshot := m.snapshots[i]
+		results[i].Result = &snapshot
+	}
+	return results, nil
+}
+
+func (m *mockSnapshotAPI) ListStorageSnapshots(storageIds []string) ([]params.VolumeSnapshotDetailsResult, error) {
+	m.MethodCall(m, "ListStorageSnapshots", storageIds)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	results := make([]params.VolumeSnapshotDetailsResult, len(m.snapshots))
+	for i := range m.snapshots {
+		results[i].Result = &m.snapshots[i]
+	}
+	return results, nil
+}
+
+func (m *mockSnapshotAPI) RestoreStorageSnapshot(snapshotId, storageName string) (names.StorageTag, error) {
+	m.MethodCall(m, "RestoreStorageSnapshot", snapshotId, storageName)
+	if err := m.NextErr(); err != nil {
+		return names.StorageTag{}, err
+	}
+	return names.NewStorageTag(storageName + "/2"), nil
+}
+
+func (m *mockSnapshotAPI) RemoveStorageSnapshots(snapshotIds []string) ([]params.ErrorResult, error) {
+	m.MethodCall(m, "RemoveStorageSnapshots", snapshotIds)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	results := make([]params.ErrorResult, len(snapshotIds))
+	for i, id := range snapshotIds {
+		results[i].Error = m.removeErrs[id]
+	}
+	return results, nil
+}
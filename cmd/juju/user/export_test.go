@@ -17,6 +17,7 @@ var (
 	APIOpen          = &apiOpen
 	ListModels       = &listModels
 	NewAPIConnection = &newAPIConnection
+	OIDCProvider     = &oidcProvider
 	LoginClientStore = &loginClientStore
 )
 
@@ -124,3 +125,72 @@ func NewWhoAmICommandForTest(store jujuclient.ClientStore) cmd.Command {
 	c := &whoAmICommand{store: store}
 	return c
 }
+
+// NewAddGroupCommandForTest returns an add-group command with the api
+// provided as specified.
+func NewAddGroupCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addGroupCommand{userGroupCommandBase: userGroupCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewRemoveGroupCommandForTest returns a remove-group command with the
+// api provided as specified.
+func NewRemoveGroupCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &removeGroupCommand{userGroupCommandBase: userGroupCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewListGroupsCommandForTest returns a groups command with the api
+// provided as specified.
+func NewListGroupsCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listGroupsCommand{userGroupCommandBase: userGroupCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewAddUserToGroupCommandForTest returns an add-user-to-group command
+// with the api provided as specified.
+func NewAddUserToGroupCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addUserToGroupCommand{groupMembersCommandBase{
+		userGroupCommandBase: userGroupCommandBase{api: api},
+		add:                  true,
+	}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewRemoveUserFromGroupCommandForTest returns a remove-user-from-group
+// command with the api provided as specified.
+func NewRemoveUserFromGroupCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &removeUserFromGroupCommand{groupMembersCommandBase{
+		userGroupCommandBase: userGroupCommandBase{api: api},
+	}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewAddRoleCommandForTest returns an add-role command with the api
+// provided as specified.
+func NewAddRoleCommandForTest(api RoleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addRoleCommand{roleCommandBase: roleCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewRemoveRoleCommandForTest returns a remove-role command with the
+// api provided as specified.
+func NewRemoveRoleCommandForTest(api RoleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &removeRoleCommand{roleCommandBase: roleCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewListRolesCommandForTest returns a roles command with the api
+// provided as specified.
+func NewListRolesCommandForTest(api RoleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listRolesCommand{roleCommandBase: roleCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
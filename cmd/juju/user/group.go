@@ -0,0 +1,426 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/rpc/params"
+)
+
+var usageAddGroupSummary = `
+Adds a user group to a controller.`[1:]
+
+var usageAddGroupDetails = `
+A user group is a named set of users on a controller. Access to models,
+clouds, offers and the controller granted to a group, with
+"juju grant --group", applies to every member of the group.
+
+Users are added to a group with "juju add-user-to-group". The members
+of an external group are instead those that the identity provider users
+log in with asserts are in the group; they are updated each time a user
+logs in. An external group must have the same name as the group that
+the provider asserts.
+
+`[1:]
+
+const usageAddGroupExamples = `
+    juju add-group developers
+    juju add-group --external platform-admins
+`
+
+var usageRemoveGroupSummary = `
+Removes a user group from a controller.`[1:]
+
+var usageRemoveGroupDetails = `
+Removing a group revokes all the access that was granted to it. The
+members of the group keep any access that was granted to them directly.
+
+`[1:]
+
+const usageRemoveGroupExamples = `
+    juju remove-group developers
+`
+
+var usageListGroupsSummary = `
+Lists the user groups of a controller.`[1:]
+
+var usageListGroupsDetails = `
+Controller administrators see every group; other users see the groups
+they are a member of.
+
+`[1:]
+
+const usageListGroupsExamples = `
+    juju groups
+    juju groups --format yaml
+`
+
+var usageAddUserToGroupSummary = `
+Adds users to a user group.`[1:]
+
+var usageAddUserToGroupDetails = `
+The users gain all the access that has been granted to the group. The
+members of an external group cannot be changed with this command.
+
+`[1:]
+
+const usageAddUserToGroupExamples = `
+    juju add-user-to-group developers bob mary
+`
+
+var usageRemoveUserFromGroupSummary = `
+Removes users from a user group.`[1:]
+
+var usageRemoveUserFromGroupDetails = `
+The users lose the access that was granted to the group, but keep any
+access that was granted to them directly.
+
+`[1:]
+
+const usageRemoveUserFromGroupExamples = `
+    juju remove-user-from-group developers bob
+`
+
+// UserGroupAPI defines the usermanager API methods that the user group
+// commands use.
+type UserGroupAPI interface {
+	AddUserGroup(name string, external bool) error
+	RemoveUserGroup(name string) error
+	UserGroupInfo(groups ...string) ([]params.UserGroupInfo, error)
+	AddUserGroupMembers(group string, users ...string) error
+	RemoveUserGroupMembers(group string, users ...string) error
+	Close() error
+}
+
+// userGroupCommandBase is the base of the user group commands.
+type userGroupCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api UserGroupAPI
+}
+
+func (c *userGroupCommandBase) getAPI() (UserGroupAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// NewAddGroupCommand returns a command to add a user group.
+func NewAddGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&addGroupCommand{})
+}
+
+// addGroupCommand adds a user group to a controller.
+type addGroupCommand struct {
+	userGroupCommandBase
+	Group    string
+	External bool
+}
+
+// Info implements Command.Info.
+func (c *addGroupCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "add-group",
+		Args:     "<group name>",
+		Purpose:  usageAddGroupSummary,
+		Doc:      usageAddGroupDetails,
+		Examples: usageAddGroupExamples,
+		SeeAlso: []string{
+			"groups",
+			"remove-group",
+			"add-user-to-group",
+			"grant",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addGroupCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.userGroupCommandBase.SetFlags(f)
+	f.BoolVar(&c.External, "external", false, "Take the group's members from the identity provider")
+}
+
+// Init implements Command.Init.
+func (c *addGroupCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no group name supplied")
+	}
+	c.Group = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *addGroupCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.AddUserGroup(c.Group, c.External); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Group %q added", c.Group)
+	return nil
+}
+
+// NewRemoveGroupCommand returns a command to remove a user group.
+func NewRemoveGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&removeGroupCommand{})
+}
+
+// removeGroupCommand removes a user group from a controller.
+type removeGroupCommand struct {
+	userGroupCommandBase
+	Group string
+}
+
+// Info implements Command.Info.
+func (c *removeGroupCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove-group",
+		Args:     "<group name>",
+		Purpose:  usageRemoveGroupSummary,
+		Doc:      usageRemoveGroupDetails,
+		Examples: usageRemoveGroupExamples,
+		SeeAlso: []string{
+			"groups",
+			"add-group",
+		},
+	})
+}
+
+// Init implements Command.Init.
+func (c *removeGroupCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no group name supplied")
+	}
+	c.Group = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *removeGroupCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RemoveUserGroup(c.Group); err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	ctx.Infof("Group %q removed", c.Group)
+	return nil
+}
+
+// NewListGroupsCommand returns a command to list user groups.
+func NewListGroupsCommand() cmd.Command {
+	return modelcmd.WrapController(&listGroupsCommand{})
+}
+
+// listGroupsCommand lists the user groups of a controller.
+type listGroupsCommand struct {
+	userGroupCommandBase
+	out cmd.Output
+}
+
+// GroupInfo defines the serialization behaviour of the user group
+// information.
+type GroupInfo struct {
+	Name        string   `yaml:"name" json:"name"`
+	External    bool     `yaml:"external,omitempty" json:"external,omitempty"`
+	Members     []string `yaml:"members" json:"members"`
+	CreatedBy   string   `yaml:"created-by" json:"created-by"`
+	DateCreated string   `yaml:"date-created" json:"date-created"`
+}
+
+// Info implements Command.Info.
+func (c *listGroupsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "groups",
+		Purpose:  usageListGroupsSummary,
+		Doc:      usageListGroupsDetails,
+		Aliases:  []string{"list-groups"},
+		Examples: usageListGroupsExamples,
+		SeeAlso: []string{
+			"add-group",
+			"add-user-to-group",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listGroupsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.userGroupCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatGroupsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listGroupsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listGroupsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	groups, err := api.UserGroupInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	output := make([]GroupInfo, len(groups))
+	for i, group := range groups {
+		output[i] = GroupInfo{
+			Name:        group.Name,
+			External:    group.External,
+			Members:     group.Members,
+			CreatedBy:   group.CreatedBy,
+			DateCreated: group.DateCreated.Format("2006-01-02"),
+		}
+		if output[i].Members == nil {
+			output[i].Members = []string{}
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+func formatGroupsTabular(writer io.Writer, value interface{}) error {
+	groups, valueConverted := value.([]GroupInfo)
+	if !valueConverted {
+		return errors.Errorf("expected value of type %T, got %T", groups, value)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("Name", "Members", "Created by", "Date created")
+	for _, group := range groups {
+		members := strings.Join(group.Members, ",")
+		if group.External {
+			members = "(external)"
+		}
+		w.Println(group.Name, members, group.CreatedBy, group.DateCreated)
+	}
+	tw.Flush()
+	return nil
+}
+
+// NewAddUserToGroupCommand returns a command to add users to a user group.
+func NewAddUserToGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&addUserToGroupCommand{
+		groupMembersCommandBase{add: true},
+	})
+}
+
+// NewRemoveUserFromGroupCommand returns a command to remove users from a
+// user group.
+func NewRemoveUserFromGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&removeUserFromGroupCommand{})
+}
+
+// groupMembersCommandBase is the base of the commands that change the
+// members of a user group.
+type groupMembersCommandBase struct {
+	userGroupCommandBase
+	add   bool
+	Group string
+	Users []string
+}
+
+// Init implements Command.Init.
+func (c *groupMembersCommandBase) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no group name supplied")
+	}
+	if len(args) == 1 {
+		return errors.New("no users supplied")
+	}
+	c.Group, c.Users = args[0], args[1:]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *groupMembersCommandBase) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if c.add {
+		err = api.AddUserGroupMembers(c.Group, c.Users...)
+	} else {
+		err = api.RemoveUserGroupMembers(c.Group, c.Users...)
+	}
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if c.add {
+		ctx.Infof("Added %s to group %q", strings.Join(c.Users, ", "), c.Group)
+	} else {
+		ctx.Infof("Removed %s from group %q", strings.Join(c.Users, ", "), c.Group)
+	}
+	return nil
+}
+
+// addUserToGroupCommand adds users to a user group.
+type addUserToGroupCommand struct {
+	groupMembersCommandBase
+}
+
+// Info implements Command.Info.
+func (c *addUserToGroupCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "add-user-to-group",
+		Args:     "<group name> <user name> ...",
+		Purpose:  usageAddUserToGroupSummary,
+		Doc:      usageAddUserToGroupDetails,
+		Examples: usageAddUserToGroupExamples,
+		SeeAlso: []string{
+			"groups",
+			"remove-user-from-group",
+		},
+	})
+}
+
+// removeUserFromGroupCommand removes users from a user group.
+type removeUserFromGroupCommand struct {
+	groupMembersCommandBase
+}
+
+// Info implements Command.Info.
+func (c *removeUserFromGroupCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove-user-from-group",
+		Args:     "<group name> <user name> ...",
+		Purpose:  usageRemoveUserFromGroupSummary,
+		Doc:      usageRemoveUserFromGroupDetails,
+		Examples: usageRemoveUserFromGroupExamples,
+		SeeAlso: []string{
+			"groups",
+			"add-user-to-group",
+		},
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/rpc/params"
+)
+
+type UserGroupSuite struct {
+	BaseSuite
+	mock *mockUserGroupAPI
+}
+
+var _ = gc.Suite(&UserGroupSuite{})
+
+func (s *UserGroupSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mock = &mockUserGroupAPI{}
+}
+
+func (s *UserGroupSuite) TestAddGroupInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no group name supplied",
+	}, {
+		args:     []string{"developers", "extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"developers"},
+	}, {
+		args: []string{"--external", "developers"},
+	}} {
+		c.Logf("test %d, args %v", i, test.args)
+		err := cmdtesting.InitCommand(user.NewAddGroupCommandForTest(s.mock, s.store), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *UserGroupSuite) TestAddGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mock, s.store), "--external", "developers")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mock.CheckCalls(c, []testing.StubCall{
+		{"AddUserGroup", []interface{}{"developers", true}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Group \"developers\" added\n")
+}
+
+func (s *UserGroupSuite) TestAddGroupError(c *gc.C) {
+	s.mock.SetErrors(errors.AlreadyExistsf("user group %q", "developers"))
+	_, err := cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mock, s.store), "developers")
+	c.Assert(err, gc.ErrorMatches, `user group "developers" already exists`)
+}
+
+func (s *UserGroupSuite) TestRemoveGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewRemoveGroupCommandForTest(s.mock, s.store), "developers")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mock.CheckCalls(c, []testing.StubCall{
+		{"RemoveUserGroup", []interface{}{"developers"}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Group \"developers\" removed\n")
+}
+
+func (s *UserGroupSuite) TestRemoveGroupInit(c *gc.C) {
+	err := cmdtesting.InitCommand(user.NewRemoveGroupCommandForTest(s.mock, s.store), nil)
+	c.Assert(err, gc.ErrorMatches, "no group name supplied")
+}
+
+func (s *UserGroupSuite) TestListGroupsTabular(c *gc.C) {
+	s.mock.groups = []params.UserGroupInfo{{
+		Name:        "developers",
+		Members:     []string{"bob", "mary"},
+		CreatedBy:   "admin",
+		DateCreated: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, {
+		Name:        "platform",
+		External:    true,
+		CreatedBy:   "admin",
+		DateCreated: time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListGroupsCommandForTest(s.mock, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Name        Members     Created by  Date created
+developers  bob,mary    admin       2023-03-01
+platform    (external)  admin       2023-03-02
+`[1:])
+}
+
+func (s *UserGroupSuite) TestListGroupsYAML(c *gc.C) {
+	s.mock.groups = []params.UserGroupInfo{{
+		Name:        "developers",
+		CreatedBy:   "admin",
+		DateCreated: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListGroupsCommandForTest(s.mock, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- name: developers
+  members: []
+  created-by: admin
+  date-created: "2023-03-01"
+`[1:])
+}
+
+func (s *UserGroupSuite) TestGroupMembersInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no group name supplied",
+	}, {
+		args:     []string{"developers"},
+		errMatch: "no users supplied",
+	}, {
+		args: []string{"developers", "bob", "mary"},
+	}} {
+		c.Logf("test %d, args %v", i, test.args)
+		err := cmdtesting.InitCommand(user.NewAddUserToGroupCommandForTest(s.mock, s.store), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *UserGroupSuite) TestAddUserToGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddUserToGroupCommandForTest(s.mock, s.store), "developers", "bob", "mary")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mock.CheckCalls(c, []testing.StubCall{
+		{"AddUserGroupMembers", []interface{}{"developers", []string{"bob", "mary"}}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Added bob, mary to group \"developers\"\n")
+}
+
+func (s *UserGroupSuite) TestRemoveUserFromGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewRemoveUserFromGroupCommandForTest(s.mock, s.store), "developers", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mock.CheckCalls(c, []testing.StubCall{
+		{"RemoveUserGroupMembers", []interface{}{"developers", []string{"bob"}}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Removed bob from group \"developers\"\n")
+}
+
+type mockUserGroupAPI struct {
+	testing.Stub
+	groups []params.UserGroupInfo
+}
+
+func (m *mockUserGroupAPI) AddUserGroup(name string, external bool) error {
+	m.MethodCall(m, "AddUserGroup", name, external)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) RemoveUserGroup(name string) error {
+	m.MethodCall(m, "RemoveUserGroup", name)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) UserGroupInfo(groups ...string) ([]params.UserGroupInfo, error) {
+	m.MethodCall(m, "UserGroupInfo", groups)
+	return m.groups, m.NextErr()
+}
+
+func (m *mockUserGroupAPI) AddUserGroupMembers(group string, users ...string) error {
+	m.MethodCall(m, "AddUserGroupMembers", group, users)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) RemoveUserGroupMembers(group string, users ...string) error {
+	m.MethodCall(m, "RemoveUserGroupMembers", group, users)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
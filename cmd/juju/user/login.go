@@ -5,6 +5,7 @@ package user
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -53,6 +54,13 @@ except verify that fact.
 If the -u option is provided, the juju login command will attempt to log
 into the controller as that user.
 
+If the --oidc option is provided, the user logs in with the OpenID Connect
+provider that the controller has been configured with (see the
+oidc-issuer-url controller config). The user is asked to visit a URL
+and enter a code there to approve the login. The ID token issued by the
+provider is then used to log into the controller, and is refreshed
+when it expires.
+
 After login, a token ("macaroon") will become active. It has an expiration
 time of 24 hours. Upon expiration, no further Juju commands can be issued
 and the user will be prompted to log in again.
@@ -70,12 +78,14 @@ const loginExamples = `
     juju login somepubliccontroller
     juju login jimm.jujucharms.com
     juju login -u bob
+    juju login --oidc
 `
 
 // Functions defined as variables so they can be overridden in tests.
 var (
 	apiOpen          = (*modelcmd.CommandBase).APIOpen
 	newAPIConnection = juju.NewAPIConnection
+	oidcProvider     = (*modelcmd.CommandBase).OIDCProvider
 	listModels       = func(c api.Connection, userName string) ([]apibase.UserModel, error) {
 		return modelmanager.NewClient(c).ListModels(userName)
 	}
@@ -100,6 +110,7 @@ type loginCommand struct {
 	noPrompt         bool
 	noPromptPassword string
 	trust            bool
+	oidc             bool
 	pollster         *interact.Pollster
 
 	// controllerName holds the name of the current controller.
@@ -139,6 +150,7 @@ func (c *loginCommand) SetFlags(fset *gnuflag.FlagSet) {
 	fset.StringVar(&c.username, "user", "", "")
 	fset.BoolVar(&c.noPrompt, "no-prompt", false, "don't prompt for password just read a line from stdin")
 	fset.BoolVar(&c.trust, "trust", false, "automatically trust controller CA certificate")
+	fset.BoolVar(&c.oidc, "oidc", false, "log in with the controller's OpenID Connect provider")
 }
 
 // Init implements Command.Init.
@@ -148,6 +160,9 @@ func (c *loginCommand) Init(args []string) error {
 		return errors.Trace(err)
 	}
 	c.domain = domain
+	if c.oidc && c.username != "" {
+		return errors.New("--oidc cannot be used with --user")
+	}
 	return nil
 }
 
@@ -216,6 +231,15 @@ func (c *loginCommand) run(ctx *cmd.Context) error {
 		oldAccountDetails = d
 	}
 	switch {
+	case c.oidc && controllerDetails == nil:
+		// The controller's OpenID Connect provider is found from
+		// the controller, so it must be known to the client.
+		return errors.Errorf(`--oidc requires a registered controller, run "juju register" first`)
+	case c.oidc:
+		conn, accountDetails, err = c.oidcLogin(ctx, store, c.controllerName, oldAccountDetails)
+		if err != nil {
+			return errors.Annotatef(err, "cannot log into controller %q", c.controllerName)
+		}
 	case c.domain != "":
 		// Check if user is trying to login to a registered controller
 		// by providing the IP of one of its endpoints as the domain.
@@ -435,9 +459,9 @@ Run "juju logout" first before attempting to log in as a different user.`,
 			accountDetails.User)
 	}
 
-	if accountDetails != nil && accountDetails.Password != "" {
+	if accountDetails != nil && (accountDetails.Password != "" || accountDetails.IDToken != "") {
 		// We've been provided some account details that
-		// contain a password, so try that first.
+		// contain a password or ID token, so try that first.
 		conn, err := dial(accountDetails)
 		if err == nil {
 			return conn, accountDetails, nil
@@ -495,6 +519,61 @@ Run "juju logout" first before attempting to log in as a different user.`,
 
 const badCred = "invalid entity name or password"
 
+// oidcLogin logs into the controller as a user of its OpenID Connect
+// provider. Existing tokens of the user are refreshed if possible;
+// otherwise the user is asked to approve the login with the provider.
+func (c *loginCommand) oidcLogin(
+	ctx *cmd.Context,
+	store jujuclient.ClientStore,
+	controllerName string,
+	currentAccountDetails *jujuclient.AccountDetails,
+) (api.Connection, *jujuclient.AccountDetails, error) {
+	provider, err := oidcProvider(&c.CommandBase, store, controllerName)
+	if errors.Is(err, errors.NotFound) {
+		return nil, nil, errors.Errorf("controller does not support OIDC login")
+	} else if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	stdctx := context.Background()
+	var token *authentication.OIDCToken
+	if currentAccountDetails != nil && currentAccountDetails.RefreshToken != "" {
+		token, err = provider.Refresh(stdctx, currentAccountDetails.RefreshToken)
+		if err != nil {
+			logger.Debugf("cannot refresh OIDC token: %v", err)
+		}
+	}
+	if token == nil {
+		token, err = provider.DeviceLogin(stdctx, func(verificationURL, userCode string) error {
+			fmt.Fprintf(ctx.Stderr, "Please visit %s and enter code %s to log in.\n", verificationURL, userCode)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+
+	accountDetails := &jujuclient.AccountDetails{
+		IDToken:      token.IDToken,
+		RefreshToken: token.RefreshToken,
+	}
+	args, err := c.NewAPIConnectionParams(store, controllerName, "", accountDetails)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	conn, err := newAPIConnection(args)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	user, ok := conn.AuthTag().(names.UserTag)
+	if !ok {
+		_ = conn.Close()
+		return nil, nil, errors.Errorf("logged in as %v, not a user", conn.AuthTag())
+	}
+	accountDetails.User = user.Id()
+	return conn, accountDetails, nil
+}
+
 const noModelsMessage = `
 There are no models available. You can add models with
 "juju add-model", or you can ask an administrator or owner
@@ -5,6 +5,7 @@ package user_test
 
 import (
 	"bytes"
+	"context"
 	"strings"
 
 	"github.com/juju/cmd/v3"
@@ -14,7 +15,9 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/authentication"
 	apibase "github.com/juju/juju/api/base"
+	apitesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cmd/juju/user"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/juju"
@@ -262,6 +265,76 @@ There are no models available(.|\n)*`[1:])
 	c.Assert(code, gc.Equals, 0)
 }
 
+func (s *LoginCommandSuite) patchOIDCProvider(c *gc.C) *apitesting.FakeOIDCIssuer {
+	issuer, err := apitesting.NewFakeOIDCIssuer("juju")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { issuer.Close() })
+	s.PatchValue(user.OIDCProvider, func(_ *modelcmd.CommandBase, _ jujuclient.ClientStore, controllerName string) (*authentication.OIDCProvider, error) {
+		c.Check(controllerName, gc.Equals, "testing")
+		return authentication.NewOIDCProvider(context.Background(), issuer.Client(), issuer.URL(), "juju")
+	})
+	return issuer
+}
+
+func (s *LoginCommandSuite) TestLoginOIDC(c *gc.C) {
+	err := s.store.RemoveAccount("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	issuer := s.patchOIDCProvider(c)
+	issuer.ApproveDevice(map[string]interface{}{"email": "user@external"}, 0)
+
+	stdout, stderr, code := runLogin(c, "", "--oidc")
+	c.Check(stdout, gc.Equals, "")
+	c.Check(stderr, gc.Matches, `
+Please visit https://.*/activate\?user_code=ABCD-EFGH and enter code ABCD-EFGH to log in.
+Welcome, user@external. You are now logged into "testing".
+(.|\n)*`[1:])
+	c.Assert(code, gc.Equals, 0)
+
+	idToken := s.apiConnectionParams.AccountDetails.IDToken
+	c.Assert(idToken, gc.Not(gc.Equals), "")
+	details, err := s.store.AccountDetails("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.User, gc.Equals, "user@external")
+	c.Assert(details.IDToken, gc.Equals, idToken)
+	c.Assert(details.RefreshToken, gc.Not(gc.Equals), "")
+	c.Assert(details.LastKnownAccess, gc.Equals, "superuser")
+}
+
+func (s *LoginCommandSuite) TestLoginOIDCRefreshesToken(c *gc.C) {
+	issuer := s.patchOIDCProvider(c)
+	issuer.ApproveDevice(map[string]interface{}{"email": "user@external"}, 0)
+	_, _, code := runLogin(c, "", "--oidc")
+	c.Assert(code, gc.Equals, 0)
+	details, err := s.store.AccountDetails("testing")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Logging in again uses the refresh token rather than the device
+	// login.
+	_, stderr, code := runLogin(c, "", "--oidc")
+	c.Assert(code, gc.Equals, 0)
+	c.Check(stderr, gc.Equals, "")
+	c.Assert(issuer.DevicePolls(), gc.Equals, 1)
+	refreshed, err := s.store.AccountDetails("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(refreshed.RefreshToken, gc.Not(gc.Equals), details.RefreshToken)
+}
+
+func (s *LoginCommandSuite) TestLoginOIDCNotSupported(c *gc.C) {
+	s.PatchValue(user.OIDCProvider, func(*modelcmd.CommandBase, jujuclient.ClientStore, string) (*authentication.OIDCProvider, error) {
+		return nil, errors.NotFoundf("OIDC login on controller")
+	})
+	_, stderr, code := runLogin(c, "", "--oidc")
+	c.Assert(code, gc.Equals, 1)
+	c.Check(stderr, gc.Equals, `ERROR cannot log into controller "testing": controller does not support OIDC login
+`)
+}
+
+func (s *LoginCommandSuite) TestLoginOIDCWithUser(c *gc.C) {
+	_, stderr, code := runLogin(c, "", "--oidc", "-u", "bob")
+	c.Assert(code, gc.Equals, 2)
+	c.Check(stderr, gc.Equals, "ERROR --oidc cannot be used with --user\n")
+}
+
 func (s *LoginCommandSuite) TestLoginWithCAVerification(c *gc.C) {
 	caCert := testing.CACertX509
 	fingerprint, _, err := pki.Fingerprint([]byte(testing.CACert))
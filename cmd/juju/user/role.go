@@ -0,0 +1,322 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+)
+
+var usageAddRoleSummary = `
+Adds a role to a controller.`[1:]
+
+var usageAddRoleDetails = `
+A role is a named set of capabilities that may be granted to users on
+models, with "juju grant-role". Users granted a role are given its model
+access level, and may then only call the API methods that its
+capabilities allow.
+
+Each capability names API methods in the form <facade>.<method>. Either
+part may be "*" to match anything, and a trailing "*" matches any name
+with that prefix.
+
+Requests to the controller's HTTP endpoints are checked against these
+capabilities too: "Charms.Upload", "Charms.Download", "Resources.Upload",
+"Resources.Download", "Tools.Upload", "Backups.Upload",
+"Backups.Download" and "DebugLog.Read", the last of which is needed for
+"juju debug-log".
+
+The built-in roles "read", "write" and "admin" allow every method, and
+are what users granted model access with "juju grant" have.
+
+`[1:]
+
+const usageAddRoleExamples = `
+    juju add-role --access write operator 'Action.*' 'Client.FullStatus'
+    juju add-role auditor '*.FullStatus' 'ModelConfig.Model*'
+`
+
+var usageRemoveRoleSummary = `
+Removes a role from a controller.`[1:]
+
+var usageRemoveRoleDetails = `
+A role cannot be removed while it is granted to any user. The built-in
+roles cannot be removed.
+
+`[1:]
+
+const usageRemoveRoleExamples = `
+    juju remove-role operator
+`
+
+var usageListRolesSummary = `
+Lists the roles of a controller.`[1:]
+
+var usageListRolesDetails = `
+The built-in roles are listed first, followed by the roles added with
+"juju add-role".
+
+`[1:]
+
+const usageListRolesExamples = `
+    juju roles
+    juju roles --format yaml
+`
+
+// RoleAPI defines the usermanager API methods that the role commands
+// use.
+type RoleAPI interface {
+	AddRole(name string, access permission.Access, capabilities ...permission.Capability) error
+	RemoveRole(name string) error
+	RoleInfo(roles ...string) ([]params.RoleInfo, error)
+	Close() error
+}
+
+// roleCommandBase is the base of the role commands.
+type roleCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api RoleAPI
+}
+
+func (c *roleCommandBase) getAPI() (RoleAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// NewAddRoleCommand returns a command to add a role.
+func NewAddRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&addRoleCommand{})
+}
+
+// addRoleCommand adds a role to a controller.
+type addRoleCommand struct {
+	roleCommandBase
+	Role         string
+	Access       string
+	Capabilities []permission.Capability
+}
+
+// Info implements Command.Info.
+func (c *addRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "add-role",
+		Args:     "<role name> <capability> ...",
+		Purpose:  usageAddRoleSummary,
+		Doc:      usageAddRoleDetails,
+		Examples: usageAddRoleExamples,
+		SeeAlso: []string{
+			"roles",
+			"remove-role",
+			"grant-role",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addRoleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.roleCommandBase.SetFlags(f)
+	f.StringVar(&c.Access, "access", "read", "The model access level given to users granted the role")
+}
+
+// Init implements Command.Init.
+func (c *addRoleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no role name supplied")
+	}
+	if len(args) == 1 {
+		return errors.New("no capabilities supplied")
+	}
+	if err := permission.ValidateModelAccess(permission.Access(c.Access)); err != nil {
+		return errors.Trace(err)
+	}
+	c.Role = args[0]
+	for _, arg := range args[1:] {
+		capability := permission.Capability(arg)
+		if err := capability.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		c.Capabilities = append(c.Capabilities, capability)
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *addRoleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.AddRole(c.Role, permission.Access(c.Access), c.Capabilities...); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Role %q added", c.Role)
+	return nil
+}
+
+// NewRemoveRoleCommand returns a command to remove a role.
+func NewRemoveRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&removeRoleCommand{})
+}
+
+// removeRoleCommand removes a role from a controller.
+type removeRoleCommand struct {
+	roleCommandBase
+	Role string
+}
+
+// Info implements Command.Info.
+func (c *removeRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove-role",
+		Args:     "<role name>",
+		Purpose:  usageRemoveRoleSummary,
+		Doc:      usageRemoveRoleDetails,
+		Examples: usageRemoveRoleExamples,
+		SeeAlso: []string{
+			"roles",
+			"add-role",
+		},
+	})
+}
+
+// Init implements Command.Init.
+func (c *removeRoleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no role name supplied")
+	}
+	c.Role = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *removeRoleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RemoveRole(c.Role); err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	ctx.Infof("Role %q removed", c.Role)
+	return nil
+}
+
+// NewListRolesCommand returns a command to list roles.
+func NewListRolesCommand() cmd.Command {
+	return modelcmd.WrapController(&listRolesCommand{})
+}
+
+// listRolesCommand lists the roles of a controller.
+type listRolesCommand struct {
+	roleCommandBase
+	out cmd.Output
+}
+
+// RoleInfo defines the serialization behaviour of the role information.
+type RoleInfo struct {
+	Name         string   `yaml:"name" json:"name"`
+	Access       string   `yaml:"access" json:"access"`
+	Capabilities []string `yaml:"capabilities" json:"capabilities"`
+	BuiltIn      bool     `yaml:"built-in,omitempty" json:"built-in,omitempty"`
+	CreatedBy    string   `yaml:"created-by,omitempty" json:"created-by,omitempty"`
+	DateCreated  string   `yaml:"date-created,omitempty" json:"date-created,omitempty"`
+}
+
+// Info implements Command.Info.
+func (c *listRolesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "roles",
+		Purpose:  usageListRolesSummary,
+		Doc:      usageListRolesDetails,
+		Aliases:  []string{"list-roles"},
+		Examples: usageListRolesExamples,
+		SeeAlso: []string{
+			"add-role",
+			"grant-role",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listRolesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.roleCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRolesTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listRolesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listRolesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	roles, err := api.RoleInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	output := make([]RoleInfo, len(roles))
+	for i, role := range roles {
+		output[i] = RoleInfo{
+			Name:         role.Name,
+			Access:       role.Access,
+			Capabilities: role.Capabilities,
+			BuiltIn:      role.BuiltIn,
+			CreatedBy:    role.CreatedBy,
+		}
+		if !role.BuiltIn {
+			output[i].DateCreated = role.DateCreated.Format("2006-01-02")
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+func formatRolesTabular(writer io.Writer, value interface{}) error {
+	roles, valueConverted := value.([]RoleInfo)
+	if !valueConverted {
+		return errors.Errorf("expected value of type %T, got %T", roles, value)
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("Name", "Access", "Created by", "Date created", "Capabilities")
+	for _, role := range roles {
+		createdBy := role.CreatedBy
+		if role.BuiltIn {
+			createdBy = "(built-in)"
+		}
+		w.Println(role.Name, role.Access, createdBy, role.DateCreated, strings.Join(role.Capabilities, ","))
+	}
+	tw.Flush()
+	return nil
+}
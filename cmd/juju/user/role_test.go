@@ -0,0 +1,159 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc/params"
+)
+
+type RoleSuite struct {
+	BaseSuite
+	mock *mockRoleAPI
+}
+
+var _ = gc.Suite(&RoleSuite{})
+
+func (s *RoleSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mock = &mockRoleAPI{}
+}
+
+func (s *RoleSuite) TestAddRoleInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no role name supplied",
+	}, {
+		args:     []string{"operator"},
+		errMatch: "no capabilities supplied",
+	}, {
+		args:     []string{"operator", "Action"},
+		errMatch: `capability "Action" not valid`,
+	}, {
+		args:     []string{"--access", "superuser", "operator", "*.*"},
+		errMatch: `"superuser" model access not valid`,
+	}, {
+		args: []string{"--access", "write", "operator", "Action.*", "Client.FullStatus"},
+	}} {
+		c.Logf("test %d, args %v", i, test.args)
+		err := cmdtesting.InitCommand(user.NewAddRoleCommandForTest(s.mock, s.store), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *RoleSuite) TestAddRole(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddRoleCommandForTest(s.mock, s.store),
+		"--access", "write", "operator", "Action.*", "Client.FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mock.CheckCalls(c, []testing.StubCall{
+		{"AddRole", []interface{}{
+			"operator", permission.WriteAccess, []permission.Capability{"Action.*", "Client.FullStatus"},
+		}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Role \"operator\" added\n")
+}
+
+func (s *RoleSuite) TestAddRoleError(c *gc.C) {
+	s.mock.SetErrors(errors.AlreadyExistsf("role %q", "operator"))
+	_, err := cmdtesting.RunCommand(c, user.NewAddRoleCommandForTest(s.mock, s.store), "operator", "*.*")
+	c.Assert(err, gc.ErrorMatches, `role "operator" already exists`)
+}
+
+func (s *RoleSuite) TestRemoveRole(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewRemoveRoleCommandForTest(s.mock, s.store), "operator")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mock.CheckCalls(c, []testing.StubCall{
+		{"RemoveRole", []interface{}{"operator"}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Role \"operator\" removed\n")
+}
+
+func (s *RoleSuite) TestRemoveRoleInit(c *gc.C) {
+	err := cmdtesting.InitCommand(user.NewRemoveRoleCommandForTest(s.mock, s.store), nil)
+	c.Assert(err, gc.ErrorMatches, "no role name supplied")
+}
+
+func (s *RoleSuite) TestListRolesTabular(c *gc.C) {
+	s.mock.roles = []params.RoleInfo{{
+		Name:         "read",
+		Access:       "read",
+		Capabilities: []string{"*.*"},
+		BuiltIn:      true,
+	}, {
+		Name:         "operator",
+		Access:       "write",
+		Capabilities: []string{"Action.*", "Client.FullStatus"},
+		CreatedBy:    "admin",
+		DateCreated:  time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListRolesCommandForTest(s.mock, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Name      Access  Created by  Date created  Capabilities
+read      read    (built-in)                *.*
+operator  write   admin       2023-04-01    Action.*,Client.FullStatus
+`[1:])
+}
+
+func (s *RoleSuite) TestListRolesYAML(c *gc.C) {
+	s.mock.roles = []params.RoleInfo{{
+		Name:         "operator",
+		Access:       "write",
+		Capabilities: []string{"Action.*"},
+		CreatedBy:    "admin",
+		DateCreated:  time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListRolesCommandForTest(s.mock, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- name: operator
+  access: write
+  capabilities:
+  - Action.*
+  created-by: admin
+  date-created: "2023-04-01"
+`[1:])
+}
+
+type mockRoleAPI struct {
+	testing.Stub
+	roles []params.RoleInfo
+}
+
+func (m *mockRoleAPI) AddRole(name string, access permission.Access, capabilities ...permission.Capability) error {
+	m.MethodCall(m, "AddRole", name, access, capabilities)
+	return m.NextErr()
+}
+
+func (m *mockRoleAPI) RemoveRole(name string) error {
+	m.MethodCall(m, "RemoveRole", name)
+	return m.NextErr()
+}
+
+func (m *mockRoleAPI) RoleInfo(roles ...string) ([]params.RoleInfo, error) {
+	m.MethodCall(m, "RoleInfo", roles)
+	return m.roles, m.NextErr()
+}
+
+func (m *mockRoleAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/waitfor/api"
+	"github.com/juju/juju/cmd/juju/waitfor/query"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/rpc/params"
+)
+
+func newAllCommand() cmd.Command {
+	cmd := &allCommand{}
+	cmd.newWatchAllAPIFunc = func() (api.WatchAllAPI, error) {
+		client, err := cmd.NewAPIClient()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return modelAllWatchShim{
+			Client: client,
+		}, nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const allCommandDoc = `
+The wait-for all command waits for every entity in the current model, or the
+model specified with -m, to reach a goal state. The goal state is defined by a
+single query that is evaluated against the full model, which allows conditions
+across applications, machines and units to be combined into one call.
+
+The query is evaluated each time the model changes. The applications, machines
+and units collections can be iterated over using the forEach, all and any
+built-in functions:
+
+    all(collection, x => expression)   true if every entity satisfies the
+                                       expression (forEach is an alias)
+    any(collection, x => expression)   true if at least one entity satisfies
+                                       the expression
+
+Both all and forEach are false for an empty collection.
+
+If the goal state is not reached before the timeout, the command exits with a
+non-zero exit code and reports which entities did not satisfy the query.
+`
+
+const allCommandExamples = `
+Waits for all the applications in the model to be active and for no unit to be
+executing a hook.
+
+    juju wait-for all --query='all(applications, app => app.status=="active") && all(units, unit => unit.agent-status!="executing")'
+
+Waits for at least one unit of the model to be on machine 0.
+
+    juju wait-for all -m staging --query='any(units, unit => unit.machine-id=="0")'
+`
+
+// allCommand defines a command for waiting for every entity in a model.
+type allCommand struct {
+	modelCommand
+
+	// failures records the entities that did not satisfy the query on the
+	// last evaluation.
+	failures []string
+}
+
+// Info implements Command.Info.
+func (c *allCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "all",
+		Purpose:  "Wait for all the entities in a model to reach a specified state.",
+		Doc:      allCommandDoc,
+		Examples: allCommandExamples,
+		SeeAlso: []string{
+			"wait-for model",
+			"wait-for application",
+			"wait-for machine",
+			"wait-for unit",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *allCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.waitForCommandBase.SetFlags(f)
+	f.StringVar(&c.query, "query", `all(applications, app => app.status=="active")`, "query the goal state")
+	f.DurationVar(&c.timeout, "timeout", time.Minute*10, "how long to wait, before timing out")
+	f.BoolVar(&c.summary, "summary", true, "output a summary of the query on exit")
+}
+
+// Init implements Command.Init.
+func (c *allCommand) Init(args []string) error {
+	if c.query == "" {
+		return errors.New("query must be supplied when waiting for all entities")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *allCommand) Run(ctx *cmd.Context) (err error) {
+	name, _, err := c.ModelDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if jujuclient.IsQualifiedModelName(name) {
+		if name, _, err = jujuclient.SplitModelName(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	c.name = name
+
+	scopedContext := MakeScopeContext()
+
+	defer func() {
+		if err != nil {
+			outputFailures(ctx.Stderr, c.failures)
+			return
+		}
+		if c.model == nil || !c.summary {
+			return
+		}
+		if c.model.Life == life.Dead {
+			ctx.Infof("model %q has been removed", c.name)
+			return
+		}
+		outputModelSummary(ctx.Stdout, scopedContext, c.model, c.applications, c.units, c.machines)
+	}()
+
+	strategy := &Strategy{
+		ClientFn: c.newWatchAllAPIFunc,
+		Timeout:  c.timeout,
+	}
+	strategy.Subscribe(func(event EventType) {
+		switch event {
+		case WatchAllStarted:
+			c.primeCache()
+		}
+	})
+	err = strategy.Run(ctx, c.name, c.query, c.waitFor(c.query, &scopedContext, ctx), func(err error, attempt int) {
+		if errors.Is(err, errors.NotFound) {
+			ctx.Infof("model %q not found, waiting...", c.name)
+		}
+	})
+	return errors.Trace(err)
+}
+
+// waitFor evaluates the query against a fresh scope context for every set
+// of deltas, so that the failures reflect the latest state of the model.
+// The last scope context used is written to scopedContext.
+func (c *allCommand) waitFor(input string, scopedContext *ScopeContext, logger Logger) func(string, []params.Delta, query.Query) (bool, error) {
+	return func(name string, deltas []params.Delta, q query.Query) (bool, error) {
+		*scopedContext = MakeScopeContext()
+		done, err := c.modelCommand.waitFor(input, *scopedContext, logger)(name, deltas, q)
+		c.failures = scopedContext.Failures()
+		return done, err
+	}
+}
+
+func outputFailures(writer io.Writer, failures []string) {
+	if len(failures) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(writer, "The following entities did not satisfy the query:")
+	for _, failure := range failures {
+		_, _ = fmt.Fprintf(writer, "  %s\n", failure)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"bytes"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/waitfor/query"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/params"
+)
+
+type allSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&allSuite{})
+
+func (s *allSuite) modelScope(ctx ScopeContext) ModelScope {
+	return MakeModelScope(ctx,
+		&params.ModelUpdate{Name: "default", Life: life.Alive},
+		map[string]*params.ApplicationInfo{
+			"mysql":     {Name: "mysql", Status: params.StatusInfo{Current: status.Active}},
+			"wordpress": {Name: "wordpress", Status: params.StatusInfo{Current: status.Waiting}},
+		},
+		map[string]*params.UnitInfo{
+			"mysql/0": {
+				Name:        "mysql/0",
+				Application: "mysql",
+				AgentStatus: params.StatusInfo{Current: status.Idle},
+			},
+			"wordpress/0": {
+				Name:        "wordpress/0",
+				Application: "wordpress",
+				AgentStatus: params.StatusInfo{Current: status.Executing},
+			},
+		},
+		map[string]*params.MachineInfo{},
+	)
+}
+
+func (s *allSuite) run(c *gc.C, input string) (bool, []string) {
+	ctx := MakeScopeContext()
+	q, err := query.Parse(input)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := q.BuiltinsRun(s.modelScope(ctx))
+	c.Assert(err, jc.ErrorIsNil)
+	return result, ctx.Failures()
+}
+
+func (s *allSuite) TestAllSuccess(c *gc.C) {
+	result, failures := s.run(c, `all(units, unit => unit.life!="dying")`)
+	c.Assert(result, jc.IsTrue)
+	c.Assert(failures, gc.HasLen, 0)
+}
+
+func (s *allSuite) TestAllReportsEveryFailure(c *gc.C) {
+	result, failures := s.run(c, `all(applications, app => app.status=="active") && all(units, unit => unit.agent-status!="executing")`)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(failures, jc.DeepEquals, []string{"application wordpress"})
+
+	// && short circuits, so the units are only reported once the
+	// applications are satisfied.
+	result, failures = s.run(c, `all(units, unit => unit.agent-status!="executing")`)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(failures, jc.DeepEquals, []string{"unit wordpress/0"})
+}
+
+func (s *allSuite) TestForEachIsAll(c *gc.C) {
+	result, failures := s.run(c, `forEach(applications, app => app.status=="active")`)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(failures, jc.DeepEquals, []string{"application wordpress"})
+}
+
+func (s *allSuite) TestAllEmpty(c *gc.C) {
+	result, failures := s.run(c, `all(machines, machine => machine.life=="alive")`)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(failures, gc.HasLen, 0)
+}
+
+func (s *allSuite) TestAny(c *gc.C) {
+	result, failures := s.run(c, `any(units, unit => unit.agent-status=="executing")`)
+	c.Assert(result, jc.IsTrue)
+	c.Assert(failures, gc.HasLen, 0)
+}
+
+func (s *allSuite) TestAnyFailure(c *gc.C) {
+	result, failures := s.run(c, `any(units, unit => unit.agent-status=="error")`)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(failures, jc.DeepEquals, []string{"unit mysql/0", "unit wordpress/0"})
+}
+
+func (s *allSuite) TestWaitForRecordsFailures(c *gc.C) {
+	cmd := &allCommand{}
+	cmd.primeCache()
+
+	input := `all(units, unit => unit.agent-status=="idle")`
+	q, err := query.Parse(input)
+	c.Assert(err, jc.ErrorIsNil)
+
+	scopedContext := MakeScopeContext()
+	fn := cmd.waitFor(input, &scopedContext, noopLogger{})
+
+	done, err := fn("default", []params.Delta{{
+		Entity: &params.ModelUpdate{Name: "default", Life: life.Alive},
+	}, {
+		Entity: &params.UnitInfo{Name: "mysql/0", AgentStatus: params.StatusInfo{Current: status.Executing}},
+	}}, q)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsFalse)
+	c.Assert(cmd.failures, jc.DeepEquals, []string{"unit mysql/0"})
+
+	done, err = fn("default", []params.Delta{{
+		Entity: &params.UnitInfo{Name: "mysql/0", AgentStatus: params.StatusInfo{Current: status.Idle}},
+	}}, q)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsTrue)
+	c.Assert(cmd.failures, gc.HasLen, 0)
+}
+
+func (s *allSuite) TestOutputFailures(c *gc.C) {
+	var buf bytes.Buffer
+	outputFailures(&buf, []string{"application wordpress", "unit wordpress/0"})
+	c.Assert(buf.String(), gc.Equals, `
+The following entities did not satisfy the query:
+  application wordpress
+  unit wordpress/0
+`[1:])
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Infof(string, ...any)    {}
+func (noopLogger) Verbosef(string, ...any) {}
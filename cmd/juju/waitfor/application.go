@@ -257,6 +257,11 @@ func (m ApplicationScope) GetIdents() []string {
 	return set.NewStrings("units", "machines").Union(idents).SortedValues()
 }
 
+// RecordResult records if the scope satisfied a query lambda.
+func (m ApplicationScope) RecordResult(passed bool) {
+	m.ctx.RecordResult(passed)
+}
+
 // GetIdentValue returns the value of the identifier in a given scope.
 func (m ApplicationScope) GetIdentValue(name string) (query.Box, error) {
 	m.ctx.RecordIdent(name)
@@ -197,6 +197,11 @@ func (m MachineScope) GetIdents() []string {
 	return getIdents(m.MachineInfo)
 }
 
+// RecordResult records if the scope satisfied a query lambda.
+func (m MachineScope) RecordResult(passed bool) {
+	m.ctx.RecordResult(passed)
+}
+
 // GetIdentValue returns the value of the identifier in a given scope.
 func (m MachineScope) GetIdentValue(name string) (query.Box, error) {
 	m.ctx.RecordIdent(name)
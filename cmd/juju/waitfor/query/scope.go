@@ -22,6 +22,15 @@ type GlobalFuncScope struct {
 
 // NewGlobalFuncScope creates a new scope for executing functions.
 func NewGlobalFuncScope(scope Scope) *GlobalFuncScope {
+	// all is exposed as both forEach and all, as forEach predates the
+	// any builtin.
+	all := func(values, expr any) (any, error) {
+		results, err := evalLambda(scope, values, expr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return allResults(results), nil
+	}
 	return &GlobalFuncScope{
 		scope: scope,
 		funcs: map[string]any{
@@ -46,52 +55,25 @@ func NewGlobalFuncScope(scope Scope) *GlobalFuncScope {
 				fmt.Printf("%+v\n", v)
 				return v, nil
 			},
-			"forEach": func(values, expr any) (any, error) {
-				scopes, ok := values.(Box)
-				if !ok {
-					return nil, RuntimeErrorf("unexpected lambda values %T", values)
-				}
-				lambda, ok := expr.(*BoxLambda)
-				if !ok {
-					return nil, RuntimeErrorf("unexpected lambda %T", expr)
-				}
-
-				var (
-					err    error
-					called bool
-					result = true
-				)
-				ForEach(scopes, func(value any) bool {
-					called = true
-
-					nestedScope, ok := value.(Scope)
-					if !ok {
-						err = RuntimeErrorf("unexpected scope type %T", value)
-						return false
-					}
-
-					namedScope := MakeNestedScope(scope)
-					namedScope.SetScope(lambda.ArgName(), nestedScope)
-
-					var results []Box
-					results, err = lambda.Call(namedScope)
-					if err != nil {
-						return false
-					}
-					var lambdaResult bool
-					for _, result := range results {
-						lambdaResult = !result.IsZero()
-					}
-					result = result && lambdaResult
-					return result
-				})
+			"forEach": all,
+			"all":     all,
+			"any": func(values, expr any) (any, error) {
+				results, err := evalLambda(scope, values, expr)
 				if err != nil {
 					return nil, errors.Trace(err)
 				}
-				if !called {
-					return false, nil
+				for _, result := range results {
+					if result.passed {
+						return true, nil
+					}
+				}
+				// Only blame the entities when none of them satisfied the
+				// lambda, otherwise an any that succeeded would still
+				// report the entities that didn't match.
+				for _, result := range results {
+					recordResult(result.scope, false)
 				}
-				return result, nil
+				return false, nil
 			},
 			"startsWith": func(v, prefix any) (bool, error) {
 				if _, ok := prefix.(string); !ok {
@@ -173,6 +155,86 @@ func (s *GlobalFuncScope) Call(ident *Identifier, params []Box) (any, error) {
 	return results[0].Interface(), nil
 }
 
+// ResultRecorder is implemented by scopes that want to know whether they
+// satisfied a lambda passed to one of the collection builtins (forEach, all
+// or any).
+type ResultRecorder interface {
+	// RecordResult records if the scope satisfied the lambda.
+	RecordResult(passed bool)
+}
+
+type lambdaResult struct {
+	scope  Scope
+	passed bool
+}
+
+// evalLambda calls the lambda with every scope found in values. Every value
+// is evaluated, so that the result of each one can be reported back.
+func evalLambda(scope Scope, values, expr any) ([]lambdaResult, error) {
+	scopes, ok := values.(Box)
+	if !ok {
+		return nil, RuntimeErrorf("unexpected lambda values %T", values)
+	}
+	lambda, ok := expr.(*BoxLambda)
+	if !ok {
+		return nil, RuntimeErrorf("unexpected lambda %T", expr)
+	}
+
+	var (
+		err     error
+		results []lambdaResult
+	)
+	ForEach(scopes, func(value any) bool {
+		nestedScope, ok := value.(Scope)
+		if !ok {
+			err = RuntimeErrorf("unexpected scope type %T", value)
+			return false
+		}
+
+		namedScope := MakeNestedScope(scope)
+		namedScope.SetScope(lambda.ArgName(), nestedScope)
+
+		var boxes []Box
+		boxes, err = lambda.Call(namedScope)
+		if err != nil {
+			return false
+		}
+		var passed bool
+		for _, box := range boxes {
+			passed = !box.IsZero()
+		}
+		results = append(results, lambdaResult{
+			scope:  nestedScope,
+			passed: passed,
+		})
+		return true
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// allResults returns true if every lambda result passed, recording the
+// result against each scope. An empty set of results is never satisfied.
+func allResults(results []lambdaResult) bool {
+	if len(results) == 0 {
+		return false
+	}
+	passed := true
+	for _, result := range results {
+		recordResult(result.scope, result.passed)
+		passed = passed && result.passed
+	}
+	return passed
+}
+
+func recordResult(scope Scope, passed bool) {
+	if recorder, ok := scope.(ResultRecorder); ok {
+		recorder.RecordResult(passed)
+	}
+}
+
 // NestedScope allows scopes to be nested together in a named manor.
 type NestedScope struct {
 	base   Scope
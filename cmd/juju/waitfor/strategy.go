@@ -5,6 +5,7 @@ package waitfor
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"strings"
 	"time"
@@ -206,11 +207,18 @@ func (m *GenericScope) Clone() query.Scope {
 type ScopeContext struct {
 	idents   set.Strings
 	children map[string]map[string]ScopeContext
+	result   *scopeResult
+}
+
+// scopeResult records if a scope failed to satisfy a lambda.
+type scopeResult struct {
+	failed bool
 }
 
 func MakeScopeContext() ScopeContext {
 	return ScopeContext{
 		idents: set.NewStrings(),
+		result: &scopeResult{},
 		children: map[string]map[string]ScopeContext{
 			"applications": make(map[string]ScopeContext),
 			"machines":     make(map[string]ScopeContext),
@@ -239,4 +247,33 @@ func (c ScopeContext) Child(entityName, name string) ScopeContext {
 	return ctx
 }
 
+// RecordResult records if the scope satisfied a lambda. Once a scope has
+// failed a lambda it remains failed for the lifetime of the context.
+func (c ScopeContext) RecordResult(passed bool) {
+	if c.result == nil {
+		return
+	}
+	c.result.failed = c.result.failed || !passed
+}
+
+// Failures returns the entities, within the children of the context, that
+// failed to satisfy a lambda. Each entity is described by its kind and
+// name, for example "unit mysql/0".
+func (c ScopeContext) Failures() []string {
+	failures := set.NewStrings()
+	c.collectFailures(failures)
+	return failures.SortedValues()
+}
+
+func (c ScopeContext) collectFailures(failures set.Strings) {
+	for entity, scopes := range c.children {
+		for name, child := range scopes {
+			if child.result != nil && child.result.failed {
+				failures.Add(fmt.Sprintf("%s %s", strings.TrimSuffix(entity, "s"), name))
+			}
+			child.collectFailures(failures)
+		}
+	}
+}
+
 func emptyNotify(error, int) {}
@@ -226,6 +226,11 @@ func (m UnitScope) GetIdents() []string {
 	return set.NewStrings("machines").Union(idents).SortedValues()
 }
 
+// RecordResult records if the scope satisfied a query lambda.
+func (m UnitScope) RecordResult(passed bool) {
+	m.ctx.RecordResult(passed)
+}
+
 // GetIdentValue returns the value of the identifier in a given scope.
 func (m UnitScope) GetIdentValue(name string) (query.Box, error) {
 	m.ctx.RecordIdent(name)
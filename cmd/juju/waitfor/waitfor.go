@@ -14,8 +14,8 @@ type Logger interface {
 }
 
 var waitForDoc = `
-The wait-for set of commands (model, application, machine and unit) defines 
-a way to wait for a goal state to be reached. The goal state can be defined
+The wait-for set of commands (all, model, application, machine and unit)
+defines a way to wait for a goal state to be reached. The goal state can be defined
 programmatically using the query DSL (domain specific language).
 
 The wait-for command is an optimized alternative to the status command for 
@@ -30,7 +30,8 @@ the command.
 
 Built-in functions are provided to help define the goal state. The built-in
 functions are defined in the query package. Examples of built-in functions
-include len, print, forEach (lambda), startsWith and endsWith.
+include len, print, forEach (lambda), all (lambda), any (lambda), startsWith
+and endsWith.
 
 Examples:
 
@@ -46,7 +47,13 @@ Waits for the model units to all start with ubuntu.
 
     juju wait-for model default --query='forEach(units, unit => startsWith(unit.name, "ubuntu"))'
 
+Waits for all the applications in the current model to be active and for no
+unit to be executing a hook.
+
+    juju wait-for all --query='all(applications, app => app.status=="active") && all(units, unit => unit.agent-status!="executing")'
+
 See also:
+    wait-for all
     wait-for model
     wait-for application
     wait-for machine
@@ -63,6 +70,7 @@ func NewWaitForCommand() cmd.Command {
 		Purpose:     "Wait for an entity to reach a specified state.",
 	})
 
+	waitFor.Register(newAllCommand())
 	waitFor.Register(newApplicationCommand())
 	waitFor.Register(newMachineCommand())
 	waitFor.Register(newModelCommand())
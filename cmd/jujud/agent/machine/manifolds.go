@@ -32,6 +32,7 @@ import (
 	"github.com/juju/juju/core/machinelock"
 	"github.com/juju/juju/core/paths"
 	"github.com/juju/juju/core/presence"
+	"github.com/juju/juju/observability/tracing/otlp"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/upgrades"
 	proxyconfig "github.com/juju/juju/utils/proxy"
@@ -95,6 +96,7 @@ import (
 	"github.com/juju/juju/worker/syslogger"
 	"github.com/juju/juju/worker/terminationworker"
 	"github.com/juju/juju/worker/toolsversionchecker"
+	"github.com/juju/juju/worker/tracer"
 	"github.com/juju/juju/worker/upgradedatabase"
 	"github.com/juju/juju/worker/upgrader"
 	"github.com/juju/juju/worker/upgradeseries"
@@ -655,6 +657,7 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			AuditConfigUpdaterName: auditConfigUpdaterName,
 			CharmhubHTTPClientName: charmhubHTTPClientName,
 			DBAccessorName:         dbAccessorName,
+			TracerName:             tracerName,
 
 			PrometheusRegisterer:              config.PrometheusRegisterer,
 			RegisterIntrospectionHTTPHandlers: config.RegisterIntrospectionHTTPHandlers,
@@ -676,6 +679,7 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			AuthorityName:  certificateWatcherName,
 			StateName:      stateName,
 			SyslogName:     syslogName,
+			TracerName:     tracerName,
 			Clock:          config.Clock,
 			MuxName:        httpServerArgsName,
 			NewWorker:      modelworkermanager.New,
@@ -731,9 +735,20 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 		auditConfigUpdaterName: ifController(auditconfigupdater.Manifold(auditconfigupdater.ManifoldConfig{
 			AgentName: agentName,
 			StateName: stateName,
+			Clock:     config.Clock,
 			NewWorker: auditconfigupdater.New,
 		})),
 
+		// The tracer worker records spans, when tracing is enabled
+		// in controller config, and exports them to an OTLP receiver.
+		tracerName: ifController(tracer.Manifold(tracer.ManifoldConfig{
+			AgentName:   agentName,
+			StateName:   stateName,
+			Clock:       config.Clock,
+			Logger:      loggo.GetLogger("juju.worker.tracer"),
+			NewExporter: otlp.NewExporter,
+		})),
+
 		// The lease expiry worker constantly deletes
 		// leases with an expiry time in the past.
 		leaseExpiryName: ifController(leaseexpiry.Manifold(leaseexpiry.ManifoldConfig{
@@ -1145,6 +1160,7 @@ const (
 	changeStreamName              = "change-stream"
 	certificateUpdaterName        = "certificate-updater"
 	auditConfigUpdaterName        = "audit-config-updater"
+	tracerName                    = "tracer"
 	leaseExpiryName               = "lease-expiry"
 	leaseManagerName              = "lease-manager"
 	stateConverterName            = "state-converter"
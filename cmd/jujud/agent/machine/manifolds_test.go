@@ -120,6 +120,7 @@ func (s *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"syslog",
 			"termination-signal-handler",
 			"tools-version-checker",
+			"tracer",
 			"upgrade-check-flag",
 			"upgrade-check-gate",
 			"upgrade-database-flag",
@@ -185,6 +186,7 @@ func (s *ManifoldsSuite) TestManifoldNamesCAAS(c *gc.C) {
 			"state-config-watcher",
 			"syslog",
 			"termination-signal-handler",
+			"tracer",
 			"upgrade-check-flag",
 			"upgrade-check-gate",
 			"upgrade-database-flag",
@@ -259,6 +261,7 @@ func (s *ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 		"state-config-watcher",
 		"syslog",
 		"termination-signal-handler",
+		"tracer",
 		"migration-fortress",
 		"migration-inactive-flag",
 		"migration-minion",
@@ -296,6 +299,7 @@ func (*ManifoldsSuite) TestSingularGuardsUsed(c *gc.C) {
 	controllerWorkers := set.NewStrings(
 		"certificate-watcher",
 		"audit-config-updater",
+		"tracer",
 		"is-primary-controller-flag",
 		"model-cache-initialized-flag",
 		"model-cache-initialized-gate",
@@ -487,6 +491,7 @@ var expectedMachineManifoldsWithDependenciesIAAS = map[string][]string{
 		"state",
 		"state-config-watcher",
 		"syslog",
+		"tracer",
 		"upgrade-steps-gate",
 		"upgrade-database-flag",
 		"upgrade-database-gate",
@@ -653,6 +658,7 @@ var expectedMachineManifoldsWithDependenciesIAAS = map[string][]string{
 		"state",
 		"state-config-watcher",
 		"syslog",
+		"tracer",
 		"upgrade-database-flag",
 		"upgrade-database-gate",
 		"upgrade-steps-gate",
@@ -855,6 +861,7 @@ var expectedMachineManifoldsWithDependenciesIAAS = map[string][]string{
 		"state",
 		"state-config-watcher",
 		"syslog",
+		"tracer",
 		"upgrade-check-flag",
 		"upgrade-check-gate",
 		"upgrade-steps-flag",
@@ -1007,6 +1014,13 @@ var expectedMachineManifoldsWithDependenciesIAAS = map[string][]string{
 		"upgrade-steps-gate",
 	},
 
+	"tracer": {
+		"agent",
+		"is-controller-flag",
+		"state",
+		"state-config-watcher",
+	},
+
 	"upgrade-check-flag": {"upgrade-check-gate"},
 
 	"upgrade-check-gate": {},
@@ -1110,6 +1124,7 @@ var expectedMachineManifoldsWithDependenciesCAAS = map[string][]string{
 		"state",
 		"state-config-watcher",
 		"syslog",
+		"tracer",
 		"upgrade-steps-gate",
 		"upgrade-database-flag",
 		"upgrade-database-gate",
@@ -1206,6 +1221,7 @@ var expectedMachineManifoldsWithDependenciesCAAS = map[string][]string{
 		"state",
 		"state-config-watcher",
 		"syslog",
+		"tracer",
 		"upgrade-database-flag",
 		"upgrade-database-gate",
 		"upgrade-steps-gate",
@@ -1332,6 +1348,7 @@ var expectedMachineManifoldsWithDependenciesCAAS = map[string][]string{
 		"state",
 		"state-config-watcher",
 		"syslog",
+		"tracer",
 		"upgrade-check-flag",
 		"upgrade-check-gate",
 		"upgrade-steps-flag",
@@ -1421,6 +1438,13 @@ var expectedMachineManifoldsWithDependenciesCAAS = map[string][]string{
 
 	"termination-signal-handler": {},
 
+	"tracer": {
+		"agent",
+		"is-controller-flag",
+		"state",
+		"state-config-watcher",
+	},
+
 	"upgrade-check-flag": {"upgrade-check-gate"},
 
 	"upgrade-check-gate": {},
@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/cmd/jujud/agent/engine"
 	"github.com/juju/juju/core/life"
 	corelogger "github.com/juju/juju/core/logger"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/pki"
 	"github.com/juju/juju/rpc/params"
@@ -103,6 +104,10 @@ type ManifoldsConfig struct {
 	// HTTP server mux for registering caas admission controllers
 	Mux *apiserverhttp.Mux
 
+	// Tracer records spans for calls made on behalf of the model,
+	// such as those to the cloud provider.
+	Tracer trace.Tracer
+
 	// RunFlagDuration defines for how long this controller will ask
 	// for model administration rights; most of the workers controlled
 	// by this agent will only be started when the run flag is known
@@ -314,9 +319,16 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 			Sinks: []logforwarder.LogSinkSpec{{
 				Name:   "juju-log-forward",
+				Config: logforwarder.SyslogConfig,
 				OpenFn: sinks.OpenSyslog,
+			}, {
+				Name:   "juju-log-forward-otlp",
+				Config: logforwarder.OTLPConfig,
+				OpenFn: sinks.OpenOTLP,
 			}},
-			Logger: config.LoggingContext.GetLogger("juju.worker.logforwarder"),
+			OpenDestination: sinks.Open,
+			Clock:           config.Clock,
+			Logger:          config.LoggingContext.GetLogger("juju.worker.logforwarder"),
 		})),
 		// The environ upgrader runs on all controller agents, and
 		// unlocks the gate when the environ is up-to-date. The
@@ -405,6 +417,7 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 			EnvironName:   environTrackerName,
 			Logger:        config.LoggingContext.GetLogger("juju.worker.provisioner"),
+			Tracer:        config.Tracer,
 
 			NewProvisionerFunc:           provisioner.NewEnvironProvisioner,
 			NewCredentialValidatorFacade: common.NewCredentialInvalidatorFacade,
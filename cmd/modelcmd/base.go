@@ -235,9 +235,18 @@ func (c *CommandBase) NewAPIRootWithDialOpts(
 	} else {
 		u := names.NewUserTag(accountDetails.User)
 		if !u.IsLocal() {
-			if len(accountDetails.Macaroons) == 0 {
+			switch {
+			case accountDetails.IDToken != "":
+				// Users of the controller's OpenID Connect
+				// provider log in with their ID token.
+				accountDetails = &jujuclient.AccountDetails{
+					User:         u.Id(),
+					IDToken:      accountDetails.IDToken,
+					RefreshToken: accountDetails.RefreshToken,
+				}
+			case len(accountDetails.Macaroons) == 0:
 				accountDetails = &jujuclient.AccountDetails{}
-			} else {
+			default:
 				// If the account has macaroon set, use those to login
 				// to avoid an unnecessary auth round trip.
 				// Used for embedded commands.
@@ -256,9 +265,22 @@ func (c *CommandBase) NewAPIRootWithDialOpts(
 		return nil, errors.Trace(err)
 	}
 	if dialOpts != nil {
+		traceContext := param.DialOpts.TraceContext
 		param.DialOpts = *dialOpts
+		if param.DialOpts.TraceContext == nil {
+			param.DialOpts.TraceContext = traceContext
+		}
 	}
 	conn, err := juju.NewAPIConnection(param)
+	if params.IsCodeUnauthorized(err) && accountDetails.RefreshToken != "" {
+		// The ID token has most likely expired, so log in again
+		// with a new one.
+		if refreshErr := c.refreshOIDCToken(store, controllerName, accountDetails); refreshErr != nil {
+			logger.Debugf("cannot refresh OIDC token: %v", refreshErr)
+			return nil, errors.Annotate(err, `OIDC login has expired, run "juju login --oidc" to log in again`)
+		}
+		conn, err = juju.NewAPIConnection(param)
+	}
 	if modelName != "" && params.ErrCode(err) == params.CodeModelNotFound {
 		return nil, c.missingModelError(store, controllerName, modelName)
 	}
@@ -337,7 +359,7 @@ func (c *CommandBase) NewAPIConnectionParams(
 		}
 	}
 
-	return newAPIConnectionParams(
+	param, err := newAPIConnectionParams(
 		store, controllerName, modelName,
 		accountDetails,
 		c.Embedded,
@@ -345,6 +367,14 @@ func (c *CommandBase) NewAPIConnectionParams(
 		c.apiOpen,
 		getPassword,
 	)
+	if err != nil {
+		return juju.NewAPIConnectionParams{}, errors.Trace(err)
+	}
+	if c.cmdContext != nil {
+		// Record the API calls in the command's trace.
+		param.DialOpts.TraceContext = c.cmdContext
+	}
+	return param, nil
 }
 
 // HTTPClient returns an http.Client that contains the loaded
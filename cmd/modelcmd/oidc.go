@@ -0,0 +1,81 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelcmd
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	jujuhttp "github.com/juju/http/v2"
+
+	"github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/rpc/params"
+)
+
+// OIDCProvider returns the OpenID Connect provider that the named
+// controller accepts user logins from. It returns a NotFound error if
+// the controller does not accept OpenID Connect logins.
+func (c *CommandBase) OIDCProvider(store jujuclient.ClientStore, controllerName string) (*authentication.OIDCProvider, error) {
+	controller, err := store.ControllerByName(controllerName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(controller.APIEndpoints) == 0 {
+		return nil, errors.Errorf("no API addresses for controller %q", controllerName)
+	}
+
+	// The controller's certificate is signed by its own CA, and is
+	// not issued for the addresses the controller is dialled at.
+	var opts []jujuhttp.Option
+	if controller.CACert != "" {
+		opts = append(opts,
+			jujuhttp.WithCACertificates(controller.CACert),
+			jujuhttp.WithSkipHostnameVerification(true),
+		)
+	}
+	controllerClient := jujuhttp.NewClient(opts...).Client()
+
+	ctx := context.Background()
+	var config params.OIDCLoginConfig
+	for _, addr := range controller.APIEndpoints {
+		config, err = authentication.FetchOIDCLoginConfig(ctx, controllerClient, addr)
+		if err == nil || errors.Is(err, errors.NotFound) {
+			break
+		}
+		logger.Debugf("cannot get OIDC login config from %q: %v", addr, err)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return authentication.NewOIDCProvider(ctx, jujuhttp.NewClient().Client(), config.IssuerURL, config.ClientID)
+}
+
+// refreshOIDCToken replaces the ID token in the account details with
+// a new one obtained with the account's refresh token, and records the
+// new tokens in the store.
+func (c *CommandBase) refreshOIDCToken(
+	store jujuclient.ClientStore,
+	controllerName string,
+	accountDetails *jujuclient.AccountDetails,
+) error {
+	provider, err := c.OIDCProvider(store, controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	token, err := provider.Refresh(context.Background(), accountDetails.RefreshToken)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	accountDetails.IDToken = token.IDToken
+	accountDetails.RefreshToken = token.RefreshToken
+
+	stored, err := store.AccountDetails(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	stored.IDToken = token.IDToken
+	stored.RefreshToken = token.RefreshToken
+	return errors.Trace(store.UpdateAccount(controllerName, *stored))
+}
@@ -0,0 +1,197 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
+)
+
+const (
+	aclsKey               = "security.acls"
+	aclsDefaultEgressKey  = "security.acls.default.egress.action"
+	aclsDefaultIngressKey = "security.acls.default.ingress.action"
+)
+
+// egressACLName returns the name of the network ACL holding the egress
+// rules for the container with the input name.
+func egressACLName(containerName string) string {
+	return containerName + "-egress"
+}
+
+// ContainerEgressRules returns the egress rules in the network ACL for the
+// container with the input name. No rules are returned if outbound traffic
+// from the container is unrestricted.
+func (s *Server) ContainerEgressRules(name string) (firewall.EgressRules, error) {
+	if !s.networkACLSupport {
+		return nil, errors.NotSupportedf("network ACLs on LXD server %q", s.name)
+	}
+	acl, _, err := s.GetNetworkACL(egressACLName(name))
+	if IsLXDNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var rules firewall.EgressRules
+	for _, aclRule := range acl.Egress {
+		if aclRule.Action != "allow" {
+			continue
+		}
+		portRange, err := aclRulePortRange(aclRule)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, cidr := range strings.Split(aclRule.Destination, ",") {
+			rules = append(rules, firewall.NewEgressRule(strings.TrimSpace(cidr), portRange))
+		}
+	}
+	return rules.Union(nil), nil
+}
+
+// SetContainerEgressRules restricts outbound traffic from the container
+// with the input name to the input rules, using a network ACL applied to
+// each of its NICs. Inbound traffic is unaffected. If there are no rules,
+// the ACL is removed and outbound traffic is unrestricted.
+func (s *Server) SetContainerEgressRules(name string, rules firewall.EgressRules) error {
+	if !s.networkACLSupport {
+		return errors.NotSupportedf("network ACLs on LXD server %q", s.name)
+	}
+	aclName := egressACLName(name)
+	if len(rules) == 0 {
+		if err := s.setContainerACL(name, aclName, false); err != nil {
+			return errors.Trace(err)
+		}
+		if err := s.DeleteNetworkACL(aclName); err != nil && !IsLXDNotFound(err) {
+			return errors.Trace(err)
+		}
+		return nil
+	}
+
+	aclRules := make([]api.NetworkACLRule, len(rules))
+	for i, rule := range rules {
+		aclRules[i] = egressACLRule(rule)
+	}
+	acl, eTag, err := s.GetNetworkACL(aclName)
+	if IsLXDNotFound(err) {
+		err = s.CreateNetworkACL(api.NetworkACLsPost{
+			NetworkACLPost: api.NetworkACLPost{Name: aclName},
+			NetworkACLPut: api.NetworkACLPut{
+				Description: fmt.Sprintf("Juju egress rules for %s", name),
+				Egress:      aclRules,
+			},
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	} else if err != nil {
+		return errors.Trace(err)
+	} else {
+		put := acl.Writable()
+		put.Egress = aclRules
+		if err := s.UpdateNetworkACL(aclName, put, eTag); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(s.setContainerACL(name, aclName, true))
+}
+
+// setContainerACL applies or removes the input ACL on each of the NICs of
+// the container with the input name. NICs inherited from profiles are
+// overridden by container devices so that the profiles are left alone.
+func (s *Server) setContainerACL(name, aclName string, apply bool) error {
+	container, eTag, err := s.GetInstance(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if container.Devices == nil {
+		container.Devices = make(map[string]map[string]string)
+	}
+	changed := false
+	for devName, expanded := range container.ExpandedDevices {
+		if expanded["type"] != "nic" {
+			continue
+		}
+		device, ok := container.Devices[devName]
+		if !ok {
+			if !apply {
+				continue
+			}
+			device = make(map[string]string, len(expanded))
+			for k, v := range expanded {
+				device[k] = v
+			}
+		}
+		if apply {
+			if device[aclsKey] == aclName {
+				continue
+			}
+			device[aclsKey] = aclName
+			device[aclsDefaultEgressKey] = "reject"
+			device[aclsDefaultIngressKey] = "allow"
+		} else {
+			if device[aclsKey] != aclName {
+				continue
+			}
+			delete(device, aclsKey)
+			delete(device, aclsDefaultEgressKey)
+			delete(device, aclsDefaultIngressKey)
+		}
+		container.Devices[devName] = device
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	resp, err := s.UpdateInstance(name, container.Writable(), eTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(resp.Wait())
+}
+
+// egressACLRule returns a network ACL rule allowing the input egress rule.
+func egressACLRule(rule firewall.EgressRule) api.NetworkACLRule {
+	aclRule := api.NetworkACLRule{
+		Action:      "allow",
+		State:       "enabled",
+		Destination: rule.DestinationCIDR,
+		Protocol:    rule.PortRange.Protocol,
+	}
+	if aclRule.Protocol == "icmp" {
+		// Rules are pre-validated, so the CIDR is either IPv4 or IPv6.
+		aclRule.Protocol = "icmp4"
+		if addrType, _ := network.CIDRAddressType(rule.DestinationCIDR); addrType == network.IPv6Address {
+			aclRule.Protocol = "icmp6"
+		}
+		return aclRule
+	}
+	aclRule.DestinationPort = fmt.Sprint(rule.PortRange.FromPort)
+	if rule.PortRange.FromPort != rule.PortRange.ToPort {
+		aclRule.DestinationPort = fmt.Sprintf("%d-%d", rule.PortRange.FromPort, rule.PortRange.ToPort)
+	}
+	return aclRule
+}
+
+// aclRulePortRange returns the port range matched by the input network
+// ACL rule.
+func aclRulePortRange(aclRule api.NetworkACLRule) (network.PortRange, error) {
+	switch aclRule.Protocol {
+	case "icmp4", "icmp6":
+		return network.PortRange{Protocol: "icmp", FromPort: -1, ToPort: -1}, nil
+	case "tcp", "udp":
+		if aclRule.DestinationPort == "" {
+			return network.PortRange{Protocol: aclRule.Protocol, FromPort: 1, ToPort: 65535}, nil
+		}
+		portRange, err := network.ParsePortRange(aclRule.DestinationPort + "/" + aclRule.Protocol)
+		return portRange, errors.Trace(err)
+	}
+	return network.PortRange{}, errors.NotValidf("network ACL rule protocol %q", aclRule.Protocol)
+}
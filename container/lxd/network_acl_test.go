@@ -0,0 +1,198 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
+)
+
+type networkACLSuite struct {
+	lxdtesting.BaseSuite
+}
+
+var _ = gc.Suite(&networkACLSuite{})
+
+func (s *networkACLSuite) TestContainerEgressRulesNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "network")
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuSvr.ContainerEgressRules("juju-lxd-1")
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+	err = jujuSvr.SetContainerEgressRules("juju-lxd-1", nil)
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+}
+
+func (s *networkACLSuite) TestContainerEgressRules(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "network", "network_acl")
+
+	acl := &api.NetworkACL{
+		NetworkACLPut: api.NetworkACLPut{
+			Egress: []api.NetworkACLRule{{
+				Action:          "allow",
+				Destination:     "10.0.0.0/8,192.168.0.0/16",
+				Protocol:        "tcp",
+				DestinationPort: "5432",
+			}, {
+				Action:      "allow",
+				Destination: "10.0.0.0/8",
+				Protocol:    "icmp4",
+			}, {
+				Action:      "drop",
+				Destination: "10.1.0.0/16",
+				Protocol:    "tcp",
+			}},
+		},
+	}
+	cSvr.EXPECT().GetNetworkACL("juju-lxd-1-egress").Return(acl, lxdtesting.ETag, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rules, err := jujuSvr.ContainerEgressRules("juju-lxd-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rules, jc.DeepEquals, firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("icmp")),
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+		firewall.NewEgressRule("192.168.0.0/16", network.MustParsePortRange("5432/tcp")),
+	})
+}
+
+func (s *networkACLSuite) TestContainerEgressRulesNoACL(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "network", "network_acl")
+
+	notFound := api.StatusErrorf(http.StatusNotFound, "Network ACL not found")
+	cSvr.EXPECT().GetNetworkACL("juju-lxd-1-egress").Return(nil, "", notFound)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rules, err := jujuSvr.ContainerEgressRules("juju-lxd-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rules, gc.HasLen, 0)
+}
+
+func (s *networkACLSuite) TestSetContainerEgressRules(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "network", "network_acl")
+
+	notFound := api.StatusErrorf(http.StatusNotFound, "Network ACL not found")
+	container := &api.Instance{
+		ExpandedDevices: map[string]map[string]string{
+			"eth0": {
+				"type":    "nic",
+				"network": "lxdbr0",
+			},
+			"root": {
+				"type": "disk",
+				"path": "/",
+			},
+		},
+	}
+	updateReq := api.InstancePut{
+		Devices: map[string]map[string]string{
+			"eth0": {
+				"type":                                 "nic",
+				"network":                              "lxdbr0",
+				"security.acls":                        "juju-lxd-1-egress",
+				"security.acls.default.egress.action":  "reject",
+				"security.acls.default.ingress.action": "allow",
+			},
+		},
+	}
+	op := lxdtesting.NewMockOperation(ctrl)
+	gomock.InOrder(
+		cSvr.EXPECT().GetNetworkACL("juju-lxd-1-egress").Return(nil, "", notFound),
+		cSvr.EXPECT().CreateNetworkACL(api.NetworkACLsPost{
+			NetworkACLPost: api.NetworkACLPost{Name: "juju-lxd-1-egress"},
+			NetworkACLPut: api.NetworkACLPut{
+				Description: "Juju egress rules for juju-lxd-1",
+				Egress: []api.NetworkACLRule{{
+					Action:          "allow",
+					State:           "enabled",
+					Destination:     "10.0.0.0/8",
+					Protocol:        "tcp",
+					DestinationPort: "5432-5433",
+				}, {
+					Action:      "allow",
+					State:       "enabled",
+					Destination: "2001:db8::/32",
+					Protocol:    "icmp6",
+				}},
+			},
+		}).Return(nil),
+		cSvr.EXPECT().GetInstance("juju-lxd-1").Return(container, lxdtesting.ETag, nil),
+		cSvr.EXPECT().UpdateInstance("juju-lxd-1", updateReq, lxdtesting.ETag).Return(op, nil),
+		op.EXPECT().Wait().Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.SetContainerEgressRules("juju-lxd-1", firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432-5433/tcp")),
+		firewall.NewEgressRule("2001:db8::/32", network.MustParsePortRange("icmp")),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *networkACLSuite) TestSetContainerEgressRulesClear(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "network", "network_acl")
+
+	device := map[string]string{
+		"type":                                 "nic",
+		"network":                              "lxdbr0",
+		"security.acls":                        "juju-lxd-1-egress",
+		"security.acls.default.egress.action":  "reject",
+		"security.acls.default.ingress.action": "allow",
+	}
+	container := &api.Instance{
+		InstancePut: api.InstancePut{
+			Devices: map[string]map[string]string{"eth0": device},
+		},
+		ExpandedDevices: map[string]map[string]string{"eth0": device},
+	}
+	updateReq := api.InstancePut{
+		Devices: map[string]map[string]string{
+			"eth0": {
+				"type":    "nic",
+				"network": "lxdbr0",
+			},
+		},
+	}
+	op := lxdtesting.NewMockOperation(ctrl)
+	gomock.InOrder(
+		cSvr.EXPECT().GetInstance("juju-lxd-1").Return(container, lxdtesting.ETag, nil),
+		cSvr.EXPECT().UpdateInstance("juju-lxd-1", updateReq, lxdtesting.ETag).Return(op, nil),
+		op.EXPECT().Wait().Return(nil),
+		cSvr.EXPECT().DeleteNetworkACL("juju-lxd-1-egress").Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.SetContainerEgressRules("juju-lxd-1", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -27,6 +27,7 @@ type Server struct {
 	serverVersion     string
 
 	networkAPISupport bool
+	networkACLSupport bool
 	clusterAPISupport bool
 	storageAPISupport bool
 
@@ -100,6 +101,7 @@ func NewServer(svr lxd.InstanceServer) (*Server, error) {
 		hostArch:          hostArch,
 		supportedArches:   supportedArches,
 		networkAPISupport: inSlice("network", apiExt),
+		networkACLSupport: inSlice("network_acl", apiExt),
 		clusterAPISupport: inSlice("clustering", apiExt),
 		storageAPISupport: inSlice("storage", apiExt),
 		serverVersion:     info.Environment.ServerVersion,
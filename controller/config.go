@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery"
@@ -18,6 +19,7 @@ import (
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/yaml.v2"
 
+	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/pki"
 )
 
@@ -92,6 +94,15 @@ const (
 	// interesting calls though.)
 	AuditLogExcludeMethods = "audit-log-exclude-methods"
 
+	// AuditLogMaxAge is the maximum age of audit log records stored in
+	// the controller database, eg "336h". Older records are pruned.
+	AuditLogMaxAge = "audit-log-max-age"
+
+	// AuditLogForwarding determines whether audit log records are also
+	// written to the controller model's logs, from where they can be
+	// forwarded to a syslog sink along with all other log messages.
+	AuditLogForwarding = "audit-log-forwarding"
+
 	// ReadOnlyMethodsWildcard is the special value that can be added
 	// to the exclude-methods list that represents all of the read
 	// only methods (see apiserver/observer/auditfilter.go). This
@@ -125,6 +136,29 @@ const (
 	// created locally on the controller.
 	IdentityPublicKey = "identity-public-key"
 
+	// OIDCIssuerURL sets the URL of an OpenID Connect provider whose ID
+	// tokens are accepted for user logins. Users authenticated by the
+	// provider are external users of the controller.
+	OIDCIssuerURL = "oidc-issuer-url"
+
+	// OIDCClientID sets the client ID registered for the controller
+	// with the OpenID Connect provider. ID tokens must be issued to
+	// this client to be accepted.
+	OIDCClientID = "oidc-client-id"
+
+	// OIDCUsernameClaim sets the ID token claim holding the name of
+	// the user. If the name has no domain, the host name of the
+	// issuer is used.
+	OIDCUsernameClaim = "oidc-username-claim"
+
+	// OIDCGroupsClaim sets the ID token claim holding the groups
+	// that the user is a member of.
+	OIDCGroupsClaim = "oidc-groups-claim"
+
+	// OIDCGroupAccess is a list of "group=access" entries granting
+	// controller access to the members of OpenID Connect groups.
+	OIDCGroupAccess = "oidc-group-access"
+
 	// SetNUMAControlPolicyKey (true/false) is deprecated.
 	// Use to configure whether mongo is started with NUMA
 	// controller policy turned on.
@@ -266,6 +300,26 @@ const (
 	// is enabled). The lower the threshold, the more queries will be output. A
 	// value of 0 means all queries will be output.
 	QueryTracingThreshold = "query-tracing-threshold"
+
+	// OpenTelemetryEnabled returns whether distributed tracing is enabled.
+	// If so, spans are exported to OpenTelemetryEndpoint.
+	OpenTelemetryEnabled = "open-telemetry-enabled"
+
+	// OpenTelemetryEndpoint is the address of the OTLP receiver that
+	// spans are exported to.
+	OpenTelemetryEndpoint = "open-telemetry-endpoint"
+
+	// OpenTelemetryProtocol is the OTLP protocol used to export spans,
+	// either "grpc" or "http/protobuf".
+	OpenTelemetryProtocol = "open-telemetry-protocol"
+
+	// OpenTelemetryInsecure disables TLS when connecting to the OTLP
+	// receiver.
+	OpenTelemetryInsecure = "open-telemetry-insecure"
+
+	// OpenTelemetrySampleRatio is the fraction of traces which are
+	// recorded, between 0 and 1.
+	OpenTelemetrySampleRatio = "open-telemetry-sample-ratio"
 )
 
 // Attribute Defaults
@@ -302,6 +356,14 @@ const (
 	// keep.
 	DefaultAuditLogMaxBackups = 10
 
+	// DefaultAuditLogMaxAge is the default maximum age of audit log
+	// records kept in the controller database.
+	DefaultAuditLogMaxAge = 14 * 24 * time.Hour
+
+	// DefaultAuditLogForwarding is the default for the
+	// AuditLogForwarding setting (which is not to forward them).
+	DefaultAuditLogForwarding = false
+
 	// DefaultNUMAControlPolicy should not be used by default.
 	// Only use numactl if user specifically requests it
 	DefaultNUMAControlPolicy = false
@@ -390,6 +452,30 @@ const (
 	// for query tracing. If a query takes longer than this to complete
 	// it will be logged if query tracing is enabled.
 	DefaultQueryTracingThreshold = time.Second
+
+	// DefaultOpenTelemetryEnabled is the default value for if distributed
+	// tracing is enabled.
+	DefaultOpenTelemetryEnabled = false
+
+	// DefaultOpenTelemetryProtocol is the default OTLP protocol used to
+	// export spans.
+	DefaultOpenTelemetryProtocol = "grpc"
+
+	// DefaultOpenTelemetryInsecure is the default value for if the
+	// connection to the OTLP receiver is made without TLS.
+	DefaultOpenTelemetryInsecure = false
+
+	// DefaultOpenTelemetrySampleRatio is the default fraction of traces
+	// which are recorded.
+	DefaultOpenTelemetrySampleRatio = 0.1
+
+	// DefaultOIDCUsernameClaim is the default ID token claim holding
+	// the name of an OpenID Connect user.
+	DefaultOIDCUsernameClaim = "email"
+
+	// DefaultOIDCGroupsClaim is the default ID token claim holding the
+	// groups an OpenID Connect user is a member of.
+	DefaultOIDCGroupsClaim = "groups"
 )
 
 var (
@@ -410,6 +496,11 @@ var (
 		LoginTokenRefreshURL,
 		IdentityPublicKey,
 		IdentityURL,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCUsernameClaim,
+		OIDCGroupsClaim,
+		OIDCGroupAccess,
 		SetNUMAControlPolicyKey,
 		StatePort,
 		MongoMemoryProfile,
@@ -433,6 +524,8 @@ var (
 		AuditLogMaxSize,
 		AuditLogMaxBackups,
 		AuditLogExcludeMethods,
+		AuditLogMaxAge,
+		AuditLogForwarding,
 		CAASOperatorImagePath,
 		CAASImageRepo,
 		Features,
@@ -444,6 +537,11 @@ var (
 		ControllerResourceDownloadLimit,
 		QueryTracingEnabled,
 		QueryTracingThreshold,
+		OpenTelemetryEnabled,
+		OpenTelemetryEndpoint,
+		OpenTelemetryProtocol,
+		OpenTelemetryInsecure,
+		OpenTelemetrySampleRatio,
 	}
 
 	// For backwards compatibility, we must include "anything", "juju-apiserver"
@@ -471,6 +569,8 @@ var (
 		AuditingEnabled,
 		AuditLogCaptureArgs,
 		AuditLogExcludeMethods,
+		AuditLogForwarding,
+		AuditLogMaxAge,
 		AuditLogMaxBackups,
 		AuditLogMaxSize,
 		CAASImageRepo,
@@ -492,6 +592,11 @@ var (
 		ModelLogfileMaxSize,
 		ModelLogsSize,
 		MongoMemoryProfile,
+		OpenTelemetryEnabled,
+		OpenTelemetryEndpoint,
+		OpenTelemetryInsecure,
+		OpenTelemetryProtocol,
+		OpenTelemetrySampleRatio,
 		PruneTxnQueryCount,
 		PruneTxnSleepTime,
 		PublicDNSAddress,
@@ -594,6 +699,16 @@ func (c Config) boolOrDefault(name string, defaultVal bool) bool {
 	return defaultVal
 }
 
+func (c Config) floatOrDefault(name string, defaultVal float64) float64 {
+	switch value := c[name].(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	}
+	return defaultVal
+}
+
 func (c Config) sizeMBOrDefault(name string, defaultVal int) int {
 	size := c.asString(name)
 	if size != "" {
@@ -747,6 +862,22 @@ func (c Config) AuditLogMaxBackups() int {
 	return c.intOrDefault(AuditLogMaxBackups, DefaultAuditLogMaxBackups)
 }
 
+// AuditLogMaxAge returns the maximum age of audit log records kept
+// in the controller database.
+func (c Config) AuditLogMaxAge() time.Duration {
+	return c.durationOrDefault(AuditLogMaxAge, DefaultAuditLogMaxAge)
+}
+
+// AuditLogForwarding returns whether audit log records should also be
+// written to the controller model's logs so that they can be
+// forwarded. The default is false.
+func (c Config) AuditLogForwarding() bool {
+	if v, ok := c[AuditLogForwarding]; ok {
+		return v.(bool)
+	}
+	return DefaultAuditLogForwarding
+}
+
 // AuditLogExcludeMethods returns the set of method names that are
 // considered uninteresting for audit logging. Conversations
 // containing only these will be excluded from the audit log.
@@ -830,6 +961,49 @@ func (c Config) IdentityPublicKey() *bakery.PublicKey {
 	return &pubKey
 }
 
+// OIDCIssuerURL returns the URL of the OpenID Connect provider trusted
+// for user logins, or "" if OpenID Connect logins are not enabled.
+func (c Config) OIDCIssuerURL() string {
+	return c.asString(OIDCIssuerURL)
+}
+
+// OIDCClientID returns the client ID registered for the controller
+// with the OpenID Connect provider.
+func (c Config) OIDCClientID() string {
+	return c.asString(OIDCClientID)
+}
+
+// OIDCUsernameClaim returns the ID token claim holding the name of
+// the user.
+func (c Config) OIDCUsernameClaim() string {
+	if v := c.asString(OIDCUsernameClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCUsernameClaim
+}
+
+// OIDCGroupsClaim returns the ID token claim holding the groups the
+// user is a member of.
+func (c Config) OIDCGroupsClaim() string {
+	if v := c.asString(OIDCGroupsClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCGroupsClaim
+}
+
+// OIDCGroupAccess returns the controller access granted to the members
+// of each OpenID Connect group.
+func (c Config) OIDCGroupAccess() map[string]string {
+	result := make(map[string]string)
+	if value, ok := c[OIDCGroupAccess]; ok {
+		for _, item := range value.([]interface{}) {
+			group, access, _ := strings.Cut(item.(string), "=")
+			result[group] = access
+		}
+	}
+	return result
+}
+
 // LoginTokenRefreshURL returns the URL of the login jwt well known endpoint.
 func (c Config) LoginTokenRefreshURL() string {
 	return c.asString(LoginTokenRefreshURL)
@@ -995,6 +1169,37 @@ func (c Config) QueryTracingThreshold() time.Duration {
 	return c.durationOrDefault(QueryTracingThreshold, DefaultQueryTracingThreshold)
 }
 
+// OpenTelemetryEnabled returns whether distributed tracing is enabled.
+func (c Config) OpenTelemetryEnabled() bool {
+	return c.boolOrDefault(OpenTelemetryEnabled, DefaultOpenTelemetryEnabled)
+}
+
+// OpenTelemetryEndpoint returns the address of the OTLP receiver that
+// spans are exported to.
+func (c Config) OpenTelemetryEndpoint() string {
+	return c.asString(OpenTelemetryEndpoint)
+}
+
+// OpenTelemetryProtocol returns the OTLP protocol used to export spans.
+func (c Config) OpenTelemetryProtocol() string {
+	if v := c.asString(OpenTelemetryProtocol); v != "" {
+		return v
+	}
+	return DefaultOpenTelemetryProtocol
+}
+
+// OpenTelemetryInsecure returns whether the connection to the OTLP
+// receiver is made without TLS.
+func (c Config) OpenTelemetryInsecure() bool {
+	return c.boolOrDefault(OpenTelemetryInsecure, DefaultOpenTelemetryInsecure)
+}
+
+// OpenTelemetrySampleRatio returns the fraction of traces which are
+// recorded.
+func (c Config) OpenTelemetrySampleRatio() float64 {
+	return c.floatOrDefault(OpenTelemetrySampleRatio, DefaultOpenTelemetrySampleRatio)
+}
+
 // Validate ensures that config is a valid configuration.
 func Validate(c Config) error {
 	if v, ok := c[IdentityPublicKey].(string); ok {
@@ -1028,6 +1233,10 @@ func Validate(c Config) error {
 		}
 	}
 
+	if err := validateOIDC(c); err != nil {
+		return errors.Trace(err)
+	}
+
 	caCert, caCertOK := c.CACert()
 	if !caCertOK {
 		return errors.Errorf("missing CA certificate")
@@ -1234,6 +1443,22 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[OpenTelemetryProtocol].(string); ok {
+		if v != "grpc" && v != "http/protobuf" {
+			return errors.Errorf("%s value %q must be one of grpc or http/protobuf", OpenTelemetryProtocol, v)
+		}
+	}
+
+	if v, ok := c[OpenTelemetrySampleRatio].(float64); ok {
+		if v < 0 || v > 1 {
+			return errors.Errorf("%s value %v must be between 0 and 1", OpenTelemetrySampleRatio, v)
+		}
+	}
+
+	if c.OpenTelemetryEnabled() && c.OpenTelemetryEndpoint() == "" {
+		return errors.Errorf("%s must be set when %s is true", OpenTelemetryEndpoint, OpenTelemetryEnabled)
+	}
+
 	return nil
 }
 
@@ -1253,6 +1478,42 @@ func (c Config) validateSpaceConfig(key, topic string) error {
 	return nil
 }
 
+// validateOIDC validates the OpenID Connect login config.
+func validateOIDC(c Config) error {
+	issuer := c.OIDCIssuerURL()
+	if issuer == "" {
+		if _, ok := c[OIDCGroupAccess]; ok {
+			return errors.Errorf("%s requires %s", OIDCGroupAccess, OIDCIssuerURL)
+		}
+		return nil
+	}
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return errors.Annotate(err, "invalid OIDC issuer URL")
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return errors.NotValidf("OIDC issuer URL %q, should be an https URL", issuer)
+	}
+	if c.OIDCClientID() == "" {
+		return errors.Errorf("%s requires %s", OIDCIssuerURL, OIDCClientID)
+	}
+	if v, ok := c[OIDCGroupAccess].([]interface{}); ok {
+		for i, item := range v {
+			group, access, _ := strings.Cut(item.(string), "=")
+			if group == "" {
+				return errors.Errorf(
+					`invalid OIDC group access: should be a list of "group=access" entries, got %q at position %d`,
+					item, i+1,
+				)
+			}
+			if err := permission.ValidateControllerAccess(permission.Access(access)); err != nil {
+				return errors.Annotatef(err, "invalid OIDC group access for group %q", group)
+			}
+		}
+	}
+	return nil
+}
+
 // AsSpaceConstraints checks to see whether config has spaces names populated
 // for management and/or HA (Mongo).
 // Non-empty values are merged with any input spaces and returned as a new
@@ -355,6 +355,40 @@ var newConfigTests = []struct {
 		controller.LoginTokenRefreshURL: `xxxx`,
 	},
 	expectError: `logic token refresh URL "xxxx" not valid`,
+}, {
+	about: "oidc login",
+	config: controller.Config{
+		controller.OIDCIssuerURL:   "https://login.example.com/realms/juju",
+		controller.OIDCClientID:    "juju",
+		controller.OIDCGroupAccess: []interface{}{"juju-admins=superuser", "devs=login"},
+	},
+}, {
+	about: "oidc issuer url not https",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "http://login.example.com",
+		controller.OIDCClientID:  "juju",
+	},
+	expectError: `OIDC issuer URL "http://login.example.com", should be an https URL not valid`,
+}, {
+	about: "oidc issuer url without client id",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://login.example.com",
+	},
+	expectError: `oidc-issuer-url requires oidc-client-id`,
+}, {
+	about: "oidc group access without issuer",
+	config: controller.Config{
+		controller.OIDCGroupAccess: []interface{}{"devs=login"},
+	},
+	expectError: `oidc-group-access requires oidc-issuer-url`,
+}, {
+	about: "invalid oidc group access",
+	config: controller.Config{
+		controller.OIDCIssuerURL:   "https://login.example.com",
+		controller.OIDCClientID:    "juju",
+		controller.OIDCGroupAccess: []interface{}{"devs=write"},
+	},
+	expectError: `invalid OIDC group access for group "devs": "write" controller access not valid`,
 }, {
 	about: "invalid query tracing value",
 	config: controller.Config{
@@ -529,6 +563,8 @@ func (s *ConfigSuite) TestAuditLogDefaults(c *gc.C) {
 	c.Assert(cfg.AuditLogCaptureArgs(), gc.Equals, false)
 	c.Assert(cfg.AuditLogMaxSizeMB(), gc.Equals, 300)
 	c.Assert(cfg.AuditLogMaxBackups(), gc.Equals, 10)
+	c.Assert(cfg.AuditLogMaxAge(), gc.Equals, 336*time.Hour)
+	c.Assert(cfg.AuditLogForwarding(), gc.Equals, false)
 	c.Assert(cfg.AuditLogExcludeMethods(), gc.DeepEquals,
 		set.NewStrings(controller.DefaultAuditLogExcludeMethods...))
 }
@@ -543,6 +579,8 @@ func (s *ConfigSuite) TestAuditLogValues(c *gc.C) {
 			"audit-log-max-size":        "100M",
 			"audit-log-max-backups":     10.0,
 			"audit-log-exclude-methods": []string{"Fleet.Foxes", "King.Gizzard", "ReadOnlyMethods"},
+			"audit-log-max-age":         "24h",
+			"audit-log-forwarding":      true,
 		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -550,6 +588,8 @@ func (s *ConfigSuite) TestAuditLogValues(c *gc.C) {
 	c.Assert(cfg.AuditLogCaptureArgs(), gc.Equals, true)
 	c.Assert(cfg.AuditLogMaxSizeMB(), gc.Equals, 100)
 	c.Assert(cfg.AuditLogMaxBackups(), gc.Equals, 10)
+	c.Assert(cfg.AuditLogMaxAge(), gc.Equals, 24*time.Hour)
+	c.Assert(cfg.AuditLogForwarding(), gc.Equals, true)
 	c.Assert(cfg.AuditLogExcludeMethods(), gc.DeepEquals, set.NewStrings(
 		"Fleet.Foxes",
 		"King.Gizzard",
@@ -911,3 +951,20 @@ func (s *ConfigSuite) TestQueryTraceThreshold(c *gc.C) {
 
 	c.Assert(cfg2.QueryTracingThreshold(), gc.Equals, time.Second*10)
 }
+
+func (s *ConfigSuite) TestOIDCConfig(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, map[string]interface{}{
+		controller.OIDCIssuerURL:   "https://login.example.com",
+		controller.OIDCClientID:    "juju",
+		controller.OIDCGroupAccess: []interface{}{"juju-admins=superuser", "devs=login"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.OIDCIssuerURL(), gc.Equals, "https://login.example.com")
+	c.Assert(cfg.OIDCClientID(), gc.Equals, "juju")
+	c.Assert(cfg.OIDCUsernameClaim(), gc.Equals, "email")
+	c.Assert(cfg.OIDCGroupsClaim(), gc.Equals, "groups")
+	c.Assert(cfg.OIDCGroupAccess(), jc.DeepEquals, map[string]string{
+		"juju-admins": "superuser",
+		"devs":        "login",
+	})
+}
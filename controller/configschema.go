@@ -5,6 +5,7 @@ package controller
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/juju/romulus"
 	"github.com/juju/schema"
@@ -19,6 +20,8 @@ var configChecker = schema.FieldMap(schema.Fields{
 	AuditLogMaxSize:                  schema.String(),
 	AuditLogMaxBackups:               schema.ForceInt(),
 	AuditLogExcludeMethods:           schema.List(schema.String()),
+	AuditLogMaxAge:                   schema.TimeDuration(),
+	AuditLogForwarding:               schema.Bool(),
 	APIPort:                          schema.ForceInt(),
 	APIPortOpenDelay:                 schema.TimeDuration(),
 	ControllerAPIPort:                schema.ForceInt(),
@@ -27,6 +30,11 @@ var configChecker = schema.FieldMap(schema.Fields{
 	LoginTokenRefreshURL:             schema.String(),
 	IdentityURL:                      schema.String(),
 	IdentityPublicKey:                schema.String(),
+	OIDCIssuerURL:                    schema.String(),
+	OIDCClientID:                     schema.String(),
+	OIDCUsernameClaim:                schema.String(),
+	OIDCGroupsClaim:                  schema.String(),
+	OIDCGroupAccess:                  schema.List(schema.String()),
 	SetNUMAControlPolicyKey:          schema.Bool(),
 	AutocertURLKey:                   schema.String(),
 	AutocertDNSNameKey:               schema.String(),
@@ -58,6 +66,11 @@ var configChecker = schema.FieldMap(schema.Fields{
 	ControllerResourceDownloadLimit:  schema.ForceInt(),
 	QueryTracingEnabled:              schema.Bool(),
 	QueryTracingThreshold:            schema.TimeDuration(),
+	OpenTelemetryEnabled:             schema.Bool(),
+	OpenTelemetryEndpoint:            schema.String(),
+	OpenTelemetryProtocol:            schema.String(),
+	OpenTelemetryInsecure:            schema.Bool(),
+	OpenTelemetrySampleRatio:         forceFloat{},
 }, schema.Defaults{
 	AgentRateLimitMax:                schema.Omit,
 	AgentRateLimitRate:               schema.Omit,
@@ -70,10 +83,17 @@ var configChecker = schema.FieldMap(schema.Fields{
 	AuditLogMaxSize:                  fmt.Sprintf("%vM", DefaultAuditLogMaxSizeMB),
 	AuditLogMaxBackups:               DefaultAuditLogMaxBackups,
 	AuditLogExcludeMethods:           DefaultAuditLogExcludeMethods,
+	AuditLogMaxAge:                   DefaultAuditLogMaxAge,
+	AuditLogForwarding:               DefaultAuditLogForwarding,
 	StatePort:                        DefaultStatePort,
 	LoginTokenRefreshURL:             schema.Omit,
 	IdentityURL:                      schema.Omit,
 	IdentityPublicKey:                schema.Omit,
+	OIDCIssuerURL:                    schema.Omit,
+	OIDCClientID:                     schema.Omit,
+	OIDCUsernameClaim:                schema.Omit,
+	OIDCGroupsClaim:                  schema.Omit,
+	OIDCGroupAccess:                  schema.Omit,
 	SetNUMAControlPolicyKey:          DefaultNUMAControlPolicy,
 	AutocertURLKey:                   schema.Omit,
 	AutocertDNSNameKey:               schema.Omit,
@@ -105,8 +125,37 @@ var configChecker = schema.FieldMap(schema.Fields{
 	ControllerResourceDownloadLimit:  schema.Omit,
 	QueryTracingEnabled:              DefaultQueryTracingEnabled,
 	QueryTracingThreshold:            DefaultQueryTracingThreshold,
+	OpenTelemetryEnabled:             DefaultOpenTelemetryEnabled,
+	OpenTelemetryEndpoint:            schema.Omit,
+	OpenTelemetryProtocol:            DefaultOpenTelemetryProtocol,
+	OpenTelemetryInsecure:            DefaultOpenTelemetryInsecure,
+	OpenTelemetrySampleRatio:         DefaultOpenTelemetrySampleRatio,
 })
 
+// forceFloat is a checker that accepts any number, or a string holding
+// a number, and coerces it to a float64. Values set from the command
+// line are strings.
+type forceFloat struct{}
+
+// Coerce implements schema.Checker.
+func (forceFloat) Coerce(v interface{}, path []string) (interface{}, error) {
+	if s, ok := v.(string); ok {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected number, got string(%q)", pathString(path), s)
+		}
+		return f, nil
+	}
+	return schema.Float().Coerce(v, path)
+}
+
+func pathString(path []string) string {
+	if len(path) == 0 {
+		return "value"
+	}
+	return path[len(path)-1]
+}
+
 // ConfigSchema holds information on all the fields defined by
 // the config package.
 var ConfigSchema = environschema.Fields{
@@ -146,6 +195,14 @@ var ConfigSchema = environschema.Fields{
 		Type:        environschema.Tlist,
 		Description: "The list of Facade.Method names that aren't interesting for audit logging purposes.",
 	},
+	AuditLogMaxAge: {
+		Type:        environschema.Tstring,
+		Description: "The maximum age of audit log records kept in the controller database",
+	},
+	AuditLogForwarding: {
+		Type:        environschema.Tbool,
+		Description: "Determines if audit log records are written to the controller model logs for forwarding",
+	},
 	APIPort: {
 		Type:        environschema.Tint,
 		Description: "The port used for api connections",
@@ -181,6 +238,26 @@ set, the api-port isn't opened until the controllers have started properly.`,
 		Type:        environschema.Tstring,
 		Description: `The public key of the identity manager`,
 	},
+	OIDCIssuerURL: {
+		Type:        environschema.Tstring,
+		Description: `The https URL of an OpenID Connect provider trusted for user logins`,
+	},
+	OIDCClientID: {
+		Type:        environschema.Tstring,
+		Description: `The client ID registered for the controller with the OpenID Connect provider`,
+	},
+	OIDCUsernameClaim: {
+		Type:        environschema.Tstring,
+		Description: `The ID token claim holding the user name of an OpenID Connect user (default "email")`,
+	},
+	OIDCGroupsClaim: {
+		Type:        environschema.Tstring,
+		Description: `The ID token claim holding the groups of an OpenID Connect user (default "groups")`,
+	},
+	OIDCGroupAccess: {
+		Type:        environschema.Tlist,
+		Description: `A list of "group=access" entries granting controller access to members of OpenID Connect groups`,
+	},
 	SetNUMAControlPolicyKey: {
 		Type:        environschema.Tbool,
 		Description: `Determines if the NUMA control policy is set`,
@@ -302,4 +379,24 @@ Use "caas-image-repo" instead.`,
 threshold, the more queries will be output. A value of 0 means all queries 
 will be output if tracing is enabled.`,
 	},
+	OpenTelemetryEnabled: {
+		Type:        environschema.Tbool,
+		Description: `Enable distributed tracing of the controller, exporting spans with OpenTelemetry`,
+	},
+	OpenTelemetryEndpoint: {
+		Type:        environschema.Tstring,
+		Description: `The address of the OTLP receiver that spans are exported to`,
+	},
+	OpenTelemetryProtocol: {
+		Type:        environschema.Tstring,
+		Description: `The OTLP protocol used to export spans, either "grpc" or "http/protobuf"`,
+	},
+	OpenTelemetryInsecure: {
+		Type:        environschema.Tbool,
+		Description: `Connect to the OTLP receiver without TLS`,
+	},
+	OpenTelemetrySampleRatio: {
+		Type:        environschema.Tstring,
+		Description: `The fraction of traces that are recorded, between 0 and 1`,
+	},
 }
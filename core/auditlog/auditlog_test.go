@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -141,6 +142,34 @@ func (s *AuditLogSuite) TestRecorder(c *gc.C) {
 	})
 }
 
+func (s *AuditLogSuite) TestTee(c *gc.C) {
+	var log1, log2 fakeLog
+	log1.stub.SetErrors(nil, errors.New("boom"))
+	tee := auditlog.NewTee(&log1, &log2)
+
+	conversation := auditlog.Conversation{Who: "deerhoof", ConversationID: "0123456789abcdef"}
+	err := tee.AddConversation(conversation)
+	c.Assert(err, jc.ErrorIsNil)
+	request := auditlog.Request{ConversationID: "0123456789abcdef", RequestID: 1}
+	err = tee.AddRequest(request)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	response := auditlog.ResponseErrors{ConversationID: "0123456789abcdef", RequestID: 1}
+	err = tee.AddResponse(response)
+	c.Assert(err, jc.ErrorIsNil)
+	err = tee.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The second log is still written to when the first one fails.
+	for _, log := range []*fakeLog{&log1, &log2} {
+		log.stub.CheckCalls(c, []testing.StubCall{
+			{FuncName: "AddConversation", Args: []interface{}{conversation}},
+			{FuncName: "AddRequest", Args: []interface{}{request}},
+			{FuncName: "AddResponse", Args: []interface{}{response}},
+			{FuncName: "Close"},
+		})
+	}
+}
+
 type fakeLog struct {
 	stub testing.Stub
 }
@@ -4,6 +4,8 @@
 package auditlog
 
 import (
+	"time"
+
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 )
@@ -29,6 +31,15 @@ type Config struct {
 	// consists of these method calls we won't log it.
 	ExcludeMethods set.Strings
 
+	// MaxAge is how long records are kept in the controller
+	// database before they are pruned.
+	MaxAge time.Duration
+
+	// Forward says whether records should also be written to the
+	// controller model's logs, so they can be forwarded to a log
+	// sink.
+	Forward bool
+
 	// Target is the AuditLog entries should be written to.
 	Target AuditLog
 }
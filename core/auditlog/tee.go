@@ -0,0 +1,55 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"github.com/juju/errors"
+)
+
+// NewTee returns an AuditLog that writes every entry to each of the
+// logs passed in, in order. All of the logs are written to even if
+// one of them fails; the first error is returned.
+func NewTee(logs ...AuditLog) AuditLog {
+	return teeLog(logs)
+}
+
+type teeLog []AuditLog
+
+// AddConversation implements AuditLog.
+func (t teeLog) AddConversation(c Conversation) error {
+	return t.each(func(log AuditLog) error {
+		return log.AddConversation(c)
+	})
+}
+
+// AddRequest implements AuditLog.
+func (t teeLog) AddRequest(r Request) error {
+	return t.each(func(log AuditLog) error {
+		return log.AddRequest(r)
+	})
+}
+
+// AddResponse implements AuditLog.
+func (t teeLog) AddResponse(r ResponseErrors) error {
+	return t.each(func(log AuditLog) error {
+		return log.AddResponse(r)
+	})
+}
+
+// Close implements AuditLog.
+func (t teeLog) Close() error {
+	return t.each(func(log AuditLog) error {
+		return log.Close()
+	})
+}
+
+func (t teeLog) each(f func(AuditLog) error) error {
+	var result error
+	for _, log := range t {
+		if err := f(log); err != nil && result == nil {
+			result = errors.Trace(err)
+		}
+	}
+	return result
+}
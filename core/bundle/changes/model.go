@@ -200,6 +200,7 @@ type Application struct {
 	Charm            string // The charm URL.
 	Scale            int
 	Options          map[string]interface{}
+	UserOptions      set.Strings // The options set by the user.
 	Annotations      map[string]string
 	Constraints      string // TODO: not updated yet.
 	Exposed          bool
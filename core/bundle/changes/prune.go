@@ -0,0 +1,344 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package bundlechanges
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/naturalsort"
+)
+
+// PruneKind describes the type of a prune change.
+type PruneKind string
+
+const (
+	// PruneRemoveRelation removes a relation that isn't in the bundle.
+	PruneRemoveRelation PruneKind = "remove-relation"
+
+	// PruneRemoveOffer removes an offer that isn't in the bundle.
+	PruneRemoveOffer PruneKind = "remove-offer"
+
+	// PruneResetOptions resets application options that are set in the
+	// model but not in the bundle.
+	PruneResetOptions PruneKind = "reset-options"
+
+	// PruneRemoveUnits removes the units of an application that exceed
+	// the number of units in the bundle.
+	PruneRemoveUnits PruneKind = "remove-units"
+
+	// PruneScaleApplication scales a kubernetes application down to the
+	// scale in the bundle.
+	PruneScaleApplication PruneKind = "scale-application"
+
+	// PruneRemoveApplication removes an application that isn't in the
+	// bundle.
+	PruneRemoveApplication PruneKind = "remove-application"
+
+	// PruneRemoveMachine removes a machine that isn't in the bundle and
+	// won't be hosting any units once the other changes are applied.
+	PruneRemoveMachine PruneKind = "remove-machine"
+)
+
+// PruneChange describes a single change required to remove something from
+// the model that isn't described by the bundle. Only the fields relevant
+// to the kind of change are set.
+type PruneChange struct {
+	Kind PruneKind
+
+	// Application is the application the change applies to.
+	Application string
+
+	// Endpoints holds the two "application:endpoint" pairs of a relation.
+	Endpoints []string
+
+	// Offer is the name of the offer to remove.
+	Offer string
+
+	// Options holds the names of the application options to reset.
+	Options []string
+
+	// Units holds the names of the units to remove.
+	Units []string
+
+	// Scale is the scale of a kubernetes application.
+	Scale int
+
+	// Machine is the ID of the machine to remove.
+	Machine string
+}
+
+// Description returns a human readable description of the change.
+func (c PruneChange) Description() string {
+	switch c.Kind {
+	case PruneRemoveRelation:
+		return fmt.Sprintf("remove relation %s", strings.Join(c.Endpoints, " "))
+	case PruneRemoveOffer:
+		return fmt.Sprintf("remove offer %s of application %s", c.Offer, c.Application)
+	case PruneResetOptions:
+		return fmt.Sprintf("reset options %s of application %s", strings.Join(c.Options, ", "), c.Application)
+	case PruneRemoveUnits:
+		return fmt.Sprintf("remove units %s", strings.Join(c.Units, ", "))
+	case PruneScaleApplication:
+		return fmt.Sprintf("scale %s down to %d", c.Application, c.Scale)
+	case PruneRemoveApplication:
+		return fmt.Sprintf("remove application %s", c.Application)
+	case PruneRemoveMachine:
+		return fmt.Sprintf("remove machine %s", c.Machine)
+	}
+	return string(c.Kind)
+}
+
+// BuildPruneChanges returns the changes needed to remove everything from
+// the model that isn't described by the bundle: applications, relations,
+// offers, application options, surplus units and machines. The changes
+// are returned in the order in which they should be applied.
+//
+// Additions and updates are not included, they are handled by the
+// changes returned from FromData.
+func BuildPruneChanges(config DiffConfig) ([]PruneChange, error) {
+	diff, err := BuildDiff(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pruner := &pruner{
+		config:       config,
+		diff:         diff,
+		removedApps:  set.NewStrings(),
+		removedUnits: set.NewStrings(),
+	}
+	return pruner.build(), nil
+}
+
+type pruner struct {
+	config DiffConfig
+	diff   *BundleDiff
+
+	removedApps  set.Strings
+	removedUnits set.Strings
+}
+
+func (p *pruner) build() []PruneChange {
+	for name, appDiff := range p.diff.Applications {
+		if appDiff.Missing == BundleSide {
+			p.removedApps.Add(name)
+		}
+	}
+
+	var changes []PruneChange
+	changes = append(changes, p.relations()...)
+	changes = append(changes, p.offers()...)
+	changes = append(changes, p.options()...)
+	changes = append(changes, p.units()...)
+	changes = append(changes, p.applications()...)
+	changes = append(changes, p.machines()...)
+	return changes
+}
+
+// relations returns the changes to remove the relations found in the model
+// but not in the bundle. Relations of removed applications are removed
+// along with the application.
+func (p *pruner) relations() []PruneChange {
+	if p.diff.Relations == nil {
+		return nil
+	}
+	var changes []PruneChange
+	for _, endpoints := range p.diff.Relations.ModelAdditions {
+		relation := relationFromEndpoints(endpoints)
+		if p.removedApps.Contains(relation.App1) || p.removedApps.Contains(relation.App2) {
+			continue
+		}
+		// A bundle relation without explicit endpoints can't be matched
+		// against the model, so it's not safe to remove.
+		if p.bundleHasImplicitRelation(relation.App1, relation.App2) {
+			continue
+		}
+		changes = append(changes, PruneChange{
+			Kind:      PruneRemoveRelation,
+			Endpoints: endpoints,
+		})
+	}
+	return changes
+}
+
+func (p *pruner) bundleHasImplicitRelation(app1, app2 string) bool {
+	for _, endpoints := range p.config.Bundle.Relations {
+		relation := relationFromEndpoints(endpoints)
+		if relation.Endpoint1 != "" && relation.Endpoint2 != "" {
+			continue
+		}
+		if (relation.App1 == app1 && relation.App2 == app2) ||
+			(relation.App1 == app2 && relation.App2 == app1) {
+			return true
+		}
+	}
+	return false
+}
+
+// offers returns the changes to remove the offers found in the model but
+// not in the bundle. The offers of removed applications are also removed,
+// as an application can't be removed while it is offered.
+func (p *pruner) offers() []PruneChange {
+	var changes []PruneChange
+	for _, name := range p.modelApplications() {
+		modelApp := p.config.Model.Applications[name]
+		var bundleOffers set.Strings
+		if bundleApp, ok := p.config.Bundle.Applications[name]; ok {
+			bundleOffers = set.NewStrings()
+			for offer := range bundleApp.Offers {
+				bundleOffers.Add(offer)
+			}
+		}
+		offers := append([]string(nil), modelApp.Offers...)
+		sort.Strings(offers)
+		for _, offer := range offers {
+			if bundleOffers.Contains(offer) {
+				continue
+			}
+			changes = append(changes, PruneChange{
+				Kind:        PruneRemoveOffer,
+				Application: name,
+				Offer:       offer,
+			})
+		}
+	}
+	return changes
+}
+
+// options returns the changes to reset the options that were set by the
+// user in the model, but which aren't set in the bundle.
+func (p *pruner) options() []PruneChange {
+	var changes []PruneChange
+	for _, name := range p.modelApplications() {
+		bundleApp, ok := p.config.Bundle.Applications[name]
+		if !ok {
+			continue
+		}
+		modelApp := p.config.Model.Applications[name]
+		var reset []string
+		for _, option := range modelApp.UserOptions.SortedValues() {
+			if _, ok := bundleApp.Options[option]; !ok {
+				reset = append(reset, option)
+			}
+		}
+		if len(reset) == 0 {
+			continue
+		}
+		changes = append(changes, PruneChange{
+			Kind:        PruneResetOptions,
+			Application: name,
+			Options:     reset,
+		})
+	}
+	return changes
+}
+
+// units returns the changes to remove the units which exceed the number
+// of units in the bundle. The most recently added units are removed first.
+func (p *pruner) units() []PruneChange {
+	var changes []PruneChange
+	for _, name := range p.modelApplications() {
+		appDiff, ok := p.diff.Applications[name]
+		if !ok || appDiff.Missing != None {
+			continue
+		}
+		if appDiff.Scale != nil && appDiff.Scale.Bundle < appDiff.Scale.Model {
+			changes = append(changes, PruneChange{
+				Kind:        PruneScaleApplication,
+				Application: name,
+				Scale:       appDiff.Scale.Bundle,
+			})
+			continue
+		}
+		if appDiff.NumUnits == nil || appDiff.NumUnits.Bundle >= appDiff.NumUnits.Model {
+			continue
+		}
+
+		modelApp := p.config.Model.Applications[name]
+		units := make([]string, len(modelApp.Units))
+		for i, unit := range modelApp.Units {
+			units[i] = unit.Name
+		}
+		naturalsort.Sort(units)
+		surplus := units[appDiff.NumUnits.Bundle:]
+		for _, unit := range surplus {
+			p.removedUnits.Add(unit)
+		}
+		changes = append(changes, PruneChange{
+			Kind:        PruneRemoveUnits,
+			Application: name,
+			Units:       surplus,
+		})
+	}
+	return changes
+}
+
+// applications returns the changes to remove the applications that are
+// not in the bundle.
+func (p *pruner) applications() []PruneChange {
+	var changes []PruneChange
+	for _, name := range p.removedApps.SortedValues() {
+		for _, unit := range p.config.Model.Applications[name].Units {
+			p.removedUnits.Add(unit.Name)
+		}
+		changes = append(changes, PruneChange{
+			Kind:        PruneRemoveApplication,
+			Application: name,
+		})
+	}
+	return changes
+}
+
+// machines returns the changes to remove the machines which are not in
+// the bundle and which won't host any units once the other changes have
+// been applied. Containers are removed before their hosts.
+func (p *pruner) machines() []PruneChange {
+	var candidates []string
+	for id, machineDiff := range p.diff.Machines {
+		if machineDiff.Missing == BundleSide && !p.hostsRemainingUnits(id) {
+			candidates = append(candidates, id)
+		}
+	}
+	naturalsort.Sort(candidates)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return strings.Count(candidates[i], "/") > strings.Count(candidates[j], "/")
+	})
+
+	var changes []PruneChange
+	for _, id := range candidates {
+		changes = append(changes, PruneChange{
+			Kind:    PruneRemoveMachine,
+			Machine: id,
+		})
+	}
+	return changes
+}
+
+// hostsRemainingUnits returns true if the machine, or any of its
+// containers, hosts a unit that isn't being removed.
+func (p *pruner) hostsRemainingUnits(id string) bool {
+	for _, app := range p.config.Model.Applications {
+		for _, unit := range app.Units {
+			if unit.Machine != id && !strings.HasPrefix(unit.Machine, id+"/") {
+				continue
+			}
+			if !p.removedUnits.Contains(unit.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *pruner) modelApplications() []string {
+	names := make([]string, 0, len(p.config.Model.Applications))
+	for name := range p.config.Model.Applications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
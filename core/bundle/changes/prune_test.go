@@ -0,0 +1,293 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package bundlechanges_test
+
+import (
+	"strings"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/collections/set"
+	"github.com/juju/loggo"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	corebase "github.com/juju/juju/core/base"
+	bundlechanges "github.com/juju/juju/core/bundle/changes"
+)
+
+type pruneSuite struct {
+	jujutesting.IsolationSuite
+	logger loggo.Logger
+}
+
+var _ = gc.Suite(&pruneSuite{})
+
+func (s *pruneSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.logger = loggo.GetLogger("prune_test")
+}
+
+const pruneBundle = `
+        applications:
+            mysql:
+                charm: ch:mysql
+                revision: 7
+                series: jammy
+                channel: stable
+                num_units: 1
+                to: [0]
+                options:
+                    flavour: percona
+                offers:
+                    db:
+                        endpoints: [db]
+            wordpress:
+                charm: ch:wordpress
+                revision: 3
+                series: jammy
+                channel: stable
+                num_units: 1
+                to: [1]
+        machines:
+            0:
+            1:
+        relations:
+            - ["wordpress:db", "mysql:db"]
+            `
+
+func (s *pruneSuite) model() *bundlechanges.Model {
+	base := corebase.MakeDefaultBase("ubuntu", "22.04")
+	return &bundlechanges.Model{
+		Applications: map[string]*bundlechanges.Application{
+			"mysql": {
+				Name:        "mysql",
+				Charm:       "ch:mysql",
+				Base:        base,
+				Channel:     "stable",
+				Revision:    7,
+				Options:     map[string]interface{}{"flavour": "percona"},
+				UserOptions: set.NewStrings("flavour"),
+				Offers:      []string{"db"},
+				Units: []bundlechanges.Unit{
+					{Name: "mysql/0", Machine: "0"},
+				},
+			},
+			"wordpress": {
+				Name:     "wordpress",
+				Charm:    "ch:wordpress",
+				Base:     base,
+				Channel:  "stable",
+				Revision: 3,
+				Units: []bundlechanges.Unit{
+					{Name: "wordpress/0", Machine: "1"},
+				},
+			},
+		},
+		Machines: map[string]*bundlechanges.Machine{
+			"0": {ID: "0"},
+			"1": {ID: "1"},
+		},
+		Relations: []bundlechanges.Relation{{
+			App1:      "wordpress",
+			Endpoint1: "db",
+			App2:      "mysql",
+			Endpoint2: "db",
+		}},
+		MachineMap: map[string]string{"0": "0", "1": "1"},
+	}
+}
+
+func (s *pruneSuite) TestNoChanges(c *gc.C) {
+	s.checkPrune(c, pruneBundle, s.model(), nil)
+}
+
+func (s *pruneSuite) TestRemoveApplication(c *gc.C) {
+	model := s.model()
+	model.Applications["memcached"] = &bundlechanges.Application{
+		Name:     "memcached",
+		Charm:    "ch:memcached",
+		Revision: 1,
+		Offers:   []string{"cache"},
+		Units: []bundlechanges.Unit{
+			{Name: "memcached/0", Machine: "2"},
+			{Name: "memcached/1", Machine: "1/lxd/0"},
+		},
+	}
+	model.Machines["2"] = &bundlechanges.Machine{ID: "2"}
+	model.Machines["1/lxd/0"] = &bundlechanges.Machine{ID: "1/lxd/0"}
+	model.Relations = append(model.Relations, bundlechanges.Relation{
+		App1:      "wordpress",
+		Endpoint1: "cache",
+		App2:      "memcached",
+		Endpoint2: "cache",
+	})
+	s.checkPrune(c, pruneBundle, model, []bundlechanges.PruneChange{{
+		Kind:        bundlechanges.PruneRemoveOffer,
+		Application: "memcached",
+		Offer:       "cache",
+	}, {
+		Kind:        bundlechanges.PruneRemoveApplication,
+		Application: "memcached",
+	}, {
+		Kind:    bundlechanges.PruneRemoveMachine,
+		Machine: "1/lxd/0",
+	}, {
+		Kind:    bundlechanges.PruneRemoveMachine,
+		Machine: "2",
+	}})
+}
+
+func (s *pruneSuite) TestRemoveRelation(c *gc.C) {
+	model := s.model()
+	model.Relations = append(model.Relations, bundlechanges.Relation{
+		App1:      "wordpress",
+		Endpoint1: "cache",
+		App2:      "mysql",
+		Endpoint2: "cache",
+	})
+	s.checkPrune(c, pruneBundle, model, []bundlechanges.PruneChange{{
+		Kind:      bundlechanges.PruneRemoveRelation,
+		Endpoints: []string{"mysql:cache", "wordpress:cache"},
+	}})
+}
+
+func (s *pruneSuite) TestRemoveRelationImplicitEndpoints(c *gc.C) {
+	bundle := strings.Replace(pruneBundle, `["wordpress:db", "mysql:db"]`, `["wordpress", "mysql"]`, 1)
+	s.checkPrune(c, bundle, s.model(), nil)
+}
+
+func (s *pruneSuite) TestRemoveOffer(c *gc.C) {
+	model := s.model()
+	model.Applications["mysql"].Offers = []string{"db", "admin"}
+	s.checkPrune(c, pruneBundle, model, []bundlechanges.PruneChange{{
+		Kind:        bundlechanges.PruneRemoveOffer,
+		Application: "mysql",
+		Offer:       "admin",
+	}})
+}
+
+func (s *pruneSuite) TestResetOptions(c *gc.C) {
+	model := s.model()
+	model.Applications["mysql"].Options["tuning"] = "fast"
+	model.Applications["mysql"].Options["port"] = 3306
+	model.Applications["mysql"].UserOptions.Add("tuning")
+	s.checkPrune(c, pruneBundle, model, []bundlechanges.PruneChange{{
+		Kind:        bundlechanges.PruneResetOptions,
+		Application: "mysql",
+		Options:     []string{"tuning"},
+	}})
+}
+
+func (s *pruneSuite) TestRemoveUnits(c *gc.C) {
+	model := s.model()
+	model.Applications["wordpress"].Units = []bundlechanges.Unit{
+		{Name: "wordpress/10", Machine: "3"},
+		{Name: "wordpress/0", Machine: "1"},
+		{Name: "wordpress/9", Machine: "1"},
+	}
+	model.Machines["3"] = &bundlechanges.Machine{ID: "3"}
+	s.checkPrune(c, pruneBundle, model, []bundlechanges.PruneChange{{
+		Kind:        bundlechanges.PruneRemoveUnits,
+		Application: "wordpress",
+		Units:       []string{"wordpress/9", "wordpress/10"},
+	}, {
+		Kind:    bundlechanges.PruneRemoveMachine,
+		Machine: "3",
+	}})
+}
+
+func (s *pruneSuite) TestKeepMachineWithUnits(c *gc.C) {
+	model := s.model()
+	model.Applications["mysql"].Units = append(model.Applications["mysql"].Units,
+		bundlechanges.Unit{Name: "mysql/1", Machine: "2/lxd/0"},
+	)
+	model.Applications["mysql"].Units[0].Machine = "2"
+	model.Machines["2"] = &bundlechanges.Machine{ID: "2"}
+	model.Machines["2/lxd/0"] = &bundlechanges.Machine{ID: "2/lxd/0"}
+	delete(model.MachineMap, "0")
+	model.MachineMap["0"] = "2"
+	delete(model.Machines, "0")
+
+	// mysql/1 is removed, so the container can be removed, but the
+	// host is still running mysql/0.
+	s.checkPrune(c, pruneBundle, model, []bundlechanges.PruneChange{{
+		Kind:        bundlechanges.PruneRemoveUnits,
+		Application: "mysql",
+		Units:       []string{"mysql/1"},
+	}, {
+		Kind:    bundlechanges.PruneRemoveMachine,
+		Machine: "2/lxd/0",
+	}})
+}
+
+func (s *pruneSuite) TestScaleDown(c *gc.C) {
+	bundleContent := `
+        bundle: kubernetes
+        applications:
+            mariadb:
+                charm: ch:mariadb-k8s
+                revision: 3
+                channel: stable
+                scale: 2
+            `
+	model := &bundlechanges.Model{
+		Applications: map[string]*bundlechanges.Application{
+			"mariadb": {
+				Name:     "mariadb",
+				Charm:    "ch:mariadb-k8s",
+				Channel:  "stable",
+				Revision: 3,
+				Scale:    3,
+			},
+		},
+	}
+	s.checkPrune(c, bundleContent, model, []bundlechanges.PruneChange{{
+		Kind:        bundlechanges.PruneScaleApplication,
+		Application: "mariadb",
+		Scale:       2,
+	}})
+}
+
+func (s *pruneSuite) TestDescription(c *gc.C) {
+	for _, t := range []struct {
+		change   bundlechanges.PruneChange
+		expected string
+	}{{
+		change:   bundlechanges.PruneChange{Kind: bundlechanges.PruneRemoveRelation, Endpoints: []string{"a:b", "c:d"}},
+		expected: "remove relation a:b c:d",
+	}, {
+		change:   bundlechanges.PruneChange{Kind: bundlechanges.PruneRemoveOffer, Application: "mysql", Offer: "db"},
+		expected: "remove offer db of application mysql",
+	}, {
+		change:   bundlechanges.PruneChange{Kind: bundlechanges.PruneResetOptions, Application: "mysql", Options: []string{"a", "b"}},
+		expected: "reset options a, b of application mysql",
+	}, {
+		change:   bundlechanges.PruneChange{Kind: bundlechanges.PruneRemoveUnits, Application: "mysql", Units: []string{"mysql/1", "mysql/2"}},
+		expected: "remove units mysql/1, mysql/2",
+	}, {
+		change:   bundlechanges.PruneChange{Kind: bundlechanges.PruneScaleApplication, Application: "mariadb", Scale: 2},
+		expected: "scale mariadb down to 2",
+	}, {
+		change:   bundlechanges.PruneChange{Kind: bundlechanges.PruneRemoveApplication, Application: "mysql"},
+		expected: "remove application mysql",
+	}, {
+		change:   bundlechanges.PruneChange{Kind: bundlechanges.PruneRemoveMachine, Machine: "0/lxd/1"},
+		expected: "remove machine 0/lxd/1",
+	}} {
+		c.Check(t.change.Description(), gc.Equals, t.expected)
+	}
+}
+
+func (s *pruneSuite) checkPrune(c *gc.C, bundleContent string, model *bundlechanges.Model, expected []bundlechanges.PruneChange) {
+	data, err := charm.ReadBundleData(strings.NewReader(bundleContent))
+	c.Assert(err, jc.ErrorIsNil)
+	changes, err := bundlechanges.BuildPruneChanges(bundlechanges.DiffConfig{
+		Bundle: data,
+		Model:  model,
+		Logger: s.logger,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, expected)
+}
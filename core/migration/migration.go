@@ -94,3 +94,19 @@ func (i *ModelInfo) Validate() error {
 	}
 	return nil
 }
+
+// Blocker describes a single problem which prevents a model from
+// being migrated.
+type Blocker struct {
+	// Check identifies which part of the migration found the
+	// problem, for example "source", "target" or "import". It is
+	// empty if the context makes it clear.
+	Check string
+
+	// Entity identifies what the problem relates to, for example
+	// "model", "machine 0", "unit mysql/0" or "controller machine 1".
+	Entity string
+
+	// Message describes the problem.
+	Message string
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/network"
+)
+
+// EgressRule represents a rule for allowing outbound traffic to reach a
+// particular port range on a destination CIDR.
+//
+// Unlike IngressRule, an egress rule holds a single CIDR so that rules
+// can be compared and used as map keys when working out which rules to
+// add to or remove from a firewall.
+type EgressRule struct {
+	// The destination CIDR for the outgoing traffic.
+	DestinationCIDR string
+
+	// The destination port range for the outgoing traffic.
+	PortRange network.PortRange
+}
+
+// NewEgressRule creates a new EgressRule for allowing outbound traffic
+// to portRange on destinationCIDR.
+func NewEgressRule(destinationCIDR string, portRange network.PortRange) EgressRule {
+	return EgressRule{
+		DestinationCIDR: destinationCIDR,
+		PortRange:       portRange,
+	}
+}
+
+// ParseEgressRule parses an egress rule of the form
+// <cidr>:<port-range>, for example "10.0.0.0/8:5432/tcp" or
+// "192.168.0.0/16:icmp".
+func ParseEgressRule(value string) (EgressRule, error) {
+	// Port ranges never contain a colon, so splitting on the last one
+	// also copes with IPv6 CIDRs.
+	sep := strings.LastIndex(value, ":")
+	if sep < 0 {
+		return EgressRule{}, errors.NotValidf("egress rule %q, expected <cidr>:<port-range>", value)
+	}
+	portRange, err := network.ParsePortRange(value[sep+1:])
+	if err != nil {
+		return EgressRule{}, errors.Annotatef(err, "parsing egress rule %q", value)
+	}
+	rule := NewEgressRule(value[:sep], portRange)
+	if err := rule.Validate(); err != nil {
+		return EgressRule{}, errors.Annotatef(err, "parsing egress rule %q", value)
+	}
+	return rule, nil
+}
+
+// Validate ensures that the egress rule contains a valid destination CIDR
+// and port range.
+func (r EgressRule) Validate() error {
+	if _, _, err := net.ParseCIDR(r.DestinationCIDR); err != nil {
+		return errors.Trace(err)
+	}
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.Annotatef(err, "invalid destination for egress rule")
+	}
+	return nil
+}
+
+// String is the string representation of EgressRule. It is the form
+// accepted by ParseEgressRule.
+func (r EgressRule) String() string {
+	return fmt.Sprintf("%s:%s", r.DestinationCIDR, r.PortRange)
+}
+
+// LessThan compares two EgressRule instances for sorting.
+func (r EgressRule) LessThan(other EgressRule) bool {
+	if r.DestinationCIDR != other.DestinationCIDR {
+		return r.DestinationCIDR < other.DestinationCIDR
+	}
+	return r.PortRange.LessThan(other.PortRange)
+}
+
+// EgressRules represents a collection of EgressRule instances.
+type EgressRules []EgressRule
+
+// ParseEgressRules parses a comma separated list of egress rules, as
+// accepted by ParseEgressRule. Duplicate rules are dropped and the
+// result is sorted.
+func ParseEgressRules(value string) (EgressRules, error) {
+	var rules EgressRules
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		rule, err := ParseEgressRule(item)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules.Union(nil), nil
+}
+
+// Sort the rule list by destination CIDR and then by port range.
+func (rules EgressRules) Sort() {
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].LessThan(rules[j])
+	})
+}
+
+// Validate the list of egress rules.
+func (rules EgressRules) Validate() error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns the rules as a comma separated list, as accepted by
+// ParseEgressRules.
+func (rules EgressRules) String() string {
+	values := make([]string, len(rules))
+	for i, rule := range rules {
+		values[i] = rule.String()
+	}
+	return strings.Join(values, ",")
+}
+
+// Union returns the sorted set of rules found in either this list or
+// the other one.
+func (rules EgressRules) Union(other EgressRules) EgressRules {
+	seen := make(map[EgressRule]bool, len(rules)+len(other))
+	var result EgressRules
+	for _, list := range []EgressRules{rules, other} {
+		for _, rule := range list {
+			if seen[rule] {
+				continue
+			}
+			seen[rule] = true
+			result = append(result, rule)
+		}
+	}
+	result.Sort()
+	return result
+}
+
+// EqualTo returns true if this rule list holds the same rules as the
+// provided one, regardless of order.
+func (rules EgressRules) EqualTo(other EgressRules) bool {
+	toAdd, toRemove := rules.Diff(other)
+	return len(toAdd) == 0 && len(toRemove) == 0
+}
+
+// Diff returns the rules to add and remove so that this set of egress
+// rules matches the target.
+func (rules EgressRules) Diff(target EgressRules) (toAdd, toRemove EgressRules) {
+	current := make(map[EgressRule]bool, len(rules))
+	for _, rule := range rules {
+		current[rule] = true
+	}
+	wanted := make(map[EgressRule]bool, len(target))
+	for _, rule := range target {
+		wanted[rule] = true
+	}
+	for rule := range wanted {
+		if !current[rule] {
+			toAdd = append(toAdd, rule)
+		}
+	}
+	for rule := range current {
+		if !wanted[rule] {
+			toRemove = append(toRemove, rule)
+		}
+	}
+	toAdd.Sort()
+	toRemove.Sort()
+	return toAdd, toRemove
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+)
+
+var _ = gc.Suite(&EgressRuleSuite{})
+
+type EgressRuleSuite struct {
+	testing.IsolationSuite
+}
+
+func (EgressRuleSuite) TestParseEgressRule(c *gc.C) {
+	for _, t := range []struct {
+		value    string
+		expected EgressRule
+	}{{
+		value:    "10.0.0.0/8:5432/tcp",
+		expected: NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+	}, {
+		value:    "192.168.0.0/16:8000-8100/udp",
+		expected: NewEgressRule("192.168.0.0/16", network.MustParsePortRange("8000-8100/udp")),
+	}, {
+		value:    "0.0.0.0/0:icmp",
+		expected: NewEgressRule("0.0.0.0/0", network.MustParsePortRange("icmp")),
+	}, {
+		value:    "2001:db8::/32:443/tcp",
+		expected: NewEgressRule("2001:db8::/32", network.MustParsePortRange("443/tcp")),
+	}} {
+		rule, err := ParseEgressRule(t.value)
+		c.Check(err, jc.ErrorIsNil, gc.Commentf(t.value))
+		c.Check(rule, gc.Equals, t.expected, gc.Commentf(t.value))
+		c.Check(rule.String(), gc.Equals, t.value)
+	}
+}
+
+func (EgressRuleSuite) TestParseEgressRuleErrors(c *gc.C) {
+	for _, t := range []struct {
+		value string
+		err   string
+	}{{
+		value: "10.0.0.0/8",
+		err:   `egress rule "10.0.0.0/8", expected <cidr>:<port-range> not valid`,
+	}, {
+		value: "5432/tcp",
+		err:   `egress rule "5432/tcp", expected <cidr>:<port-range> not valid`,
+	}, {
+		value: "10.0.0.0:5432/tcp",
+		err:   `parsing egress rule "10.0.0.0:5432/tcp": invalid CIDR address: 10.0.0.0`,
+	}, {
+		value: "10.0.0.0/8:70000/tcp",
+		err:   `parsing egress rule "10.0.0.0/8:70000/tcp": .*`,
+	}} {
+		_, err := ParseEgressRule(t.value)
+		c.Check(err, gc.ErrorMatches, t.err, gc.Commentf(t.value))
+	}
+}
+
+func (EgressRuleSuite) TestParseEgressRules(c *gc.C) {
+	rules, err := ParseEgressRules("10.0.0.0/8:5432/tcp, 0.0.0.0/0:53/udp,10.0.0.0/8:5432/tcp,")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, EgressRules{
+		NewEgressRule("0.0.0.0/0", network.MustParsePortRange("53/udp")),
+		NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+	})
+	c.Assert(rules.String(), gc.Equals, "0.0.0.0/0:53/udp,10.0.0.0/8:5432/tcp")
+
+	rules, err = ParseEgressRules("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
+func (EgressRuleSuite) TestDiff(c *gc.C) {
+	current, err := ParseEgressRules("10.0.0.0/8:5432/tcp,10.0.0.0/8:6379/tcp")
+	c.Assert(err, jc.ErrorIsNil)
+	target, err := ParseEgressRules("10.0.0.0/8:6379/tcp,172.16.0.0/12:443/tcp")
+	c.Assert(err, jc.ErrorIsNil)
+
+	toAdd, toRemove := current.Diff(target)
+	c.Assert(toAdd.String(), gc.Equals, "172.16.0.0/12:443/tcp")
+	c.Assert(toRemove.String(), gc.Equals, "10.0.0.0/8:5432/tcp")
+	c.Assert(current.EqualTo(target), jc.IsFalse)
+	c.Assert(target.EqualTo(EgressRules{target[1], target[0]}), jc.IsTrue)
+}
+
+func (EgressRuleSuite) TestUnion(c *gc.C) {
+	a, err := ParseEgressRules("10.0.0.0/8:5432/tcp")
+	c.Assert(err, jc.ErrorIsNil)
+	b, err := ParseEgressRules("10.0.0.0/8:5432/tcp,0.0.0.0/0:53/udp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.Union(b).String(), gc.Equals, "0.0.0.0/0:53/udp,10.0.0.0/8:5432/tcp")
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package permission
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Capability names the API methods that a role allows a user to call,
+// in the form "<facade>.<method>". Either part may be "*" to match any
+// facade or any method, and a method ending in "*" matches any method
+// with that prefix; so "Application.*", "Action.Enqueue*" and "*.*"
+// are all valid capabilities.
+type Capability string
+
+// AllCapabilities allows every API method.
+const AllCapabilities Capability = "*.*"
+
+var (
+	validCapabilityPart = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*\*?|\*)$`)
+	validRoleName       = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+)
+
+// Validate returns an error if the capability is not well formed.
+func (c Capability) Validate() error {
+	facadeName, methodName, ok := strings.Cut(string(c), ".")
+	if !ok || !validCapabilityPart.MatchString(facadeName) || !validCapabilityPart.MatchString(methodName) {
+		return errors.NotValidf("capability %q", c)
+	}
+	return nil
+}
+
+// Allows returns whether the capability allows calling the given method
+// of the given facade.
+func (c Capability) Allows(facadeName, methodName string) bool {
+	capFacade, capMethod, ok := strings.Cut(string(c), ".")
+	if !ok {
+		return false
+	}
+	return matchCapabilityPart(capFacade, facadeName) && matchCapabilityPart(capMethod, methodName)
+}
+
+func matchCapabilityPart(pattern, name string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return pattern == name
+}
+
+// Role is a named set of capabilities that may be granted to a user on
+// a model. The user is also given the role's access level, which the
+// API facades go on to check as usual; the capabilities then restrict
+// which of the methods allowed at that level the user may call.
+type Role struct {
+	Name         string
+	Access       Access
+	Capabilities []Capability
+}
+
+// Allows returns whether the role allows calling the given method of
+// the given facade.
+func (r Role) Allows(facadeName, methodName string) bool {
+	for _, capability := range r.Capabilities {
+		if capability.Allows(facadeName, methodName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error if the role is not valid.
+func (r Role) Validate() error {
+	if !IsValidRoleName(r.Name) {
+		return errors.NotValidf("role name %q", r.Name)
+	}
+	if err := ValidateModelAccess(r.Access); err != nil {
+		return errors.Annotatef(err, "role %q", r.Name)
+	}
+	if len(r.Capabilities) == 0 {
+		return errors.NotValidf("role %q without capabilities", r.Name)
+	}
+	for _, capability := range r.Capabilities {
+		if err := capability.Validate(); err != nil {
+			return errors.Annotatef(err, "role %q", r.Name)
+		}
+	}
+	return nil
+}
+
+// IsValidRoleName returns whether name is a valid role name.
+func IsValidRoleName(name string) bool {
+	return validRoleName.MatchString(name)
+}
+
+// BuiltinRoles returns the roles equivalent to the model access levels.
+// Each allows every API method, leaving the facades to check the access
+// level as they always have.
+func BuiltinRoles() []Role {
+	return []Role{
+		builtinRole(ReadAccess),
+		builtinRole(WriteAccess),
+		builtinRole(AdminAccess),
+	}
+}
+
+// BuiltinRole returns the built-in role with the given name, and
+// whether there is such a role.
+func BuiltinRole(name string) (Role, bool) {
+	access := Access(name)
+	if ValidateModelAccess(access) != nil {
+		return Role{}, false
+	}
+	return builtinRole(access), true
+}
+
+func builtinRole(access Access) Role {
+	return Role{
+		Name:         string(access),
+		Access:       access,
+		Capabilities: []Capability{AllCapabilities},
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package permission_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+)
+
+type roleSuite struct{}
+
+var _ = gc.Suite(&roleSuite{})
+
+func (*roleSuite) TestCapabilityValidate(c *gc.C) {
+	for _, capability := range []permission.Capability{
+		"*.*", "Application.*", "Action.Enqueue*", "*.FullStatus", "Client.FullStatus",
+	} {
+		c.Check(capability.Validate(), jc.ErrorIsNil, gc.Commentf("%q", capability))
+	}
+	for _, capability := range []permission.Capability{
+		"", "*", "Application", "Application.", ".Deploy", "Appli*cation.Deploy", "Application.Deploy.Now", "Application.**",
+	} {
+		c.Check(capability.Validate(), gc.ErrorMatches, `capability ".*" not valid`, gc.Commentf("%q", capability))
+	}
+}
+
+func (*roleSuite) TestCapabilityAllows(c *gc.C) {
+	for i, test := range []struct {
+		capability permission.Capability
+		facade     string
+		method     string
+		allows     bool
+	}{
+		{"*.*", "Application", "Deploy", true},
+		{"Application.*", "Application", "Deploy", true},
+		{"Application.*", "Action", "Enqueue", false},
+		{"Action.Enqueue*", "Action", "EnqueueOperation", true},
+		{"Action.Enqueue*", "Action", "Cancel", false},
+		{"*.FullStatus", "Client", "FullStatus", true},
+		{"Client.FullStatus", "Client", "FullStatusX", false},
+	} {
+		c.Logf("test %d: %q allows %s.%s", i, test.capability, test.facade, test.method)
+		c.Check(test.capability.Allows(test.facade, test.method), gc.Equals, test.allows)
+	}
+}
+
+func (*roleSuite) TestRoleValidate(c *gc.C) {
+	role := permission.Role{
+		Name:         "operator",
+		Access:       permission.WriteAccess,
+		Capabilities: []permission.Capability{"Action.*"},
+	}
+	c.Assert(role.Validate(), jc.ErrorIsNil)
+
+	role.Name = "Operator"
+	c.Assert(role.Validate(), gc.ErrorMatches, `role name "Operator" not valid`)
+
+	role.Name = "operator"
+	role.Access = permission.SuperuserAccess
+	c.Assert(role.Validate(), gc.ErrorMatches, `role "operator": "superuser" model access not valid`)
+
+	role.Access = permission.WriteAccess
+	role.Capabilities = nil
+	c.Assert(role.Validate(), gc.ErrorMatches, `role "operator" without capabilities not valid`)
+
+	role.Capabilities = []permission.Capability{"Action"}
+	c.Assert(role.Validate(), gc.ErrorMatches, `role "operator": capability "Action" not valid`)
+}
+
+func (*roleSuite) TestRoleAllows(c *gc.C) {
+	role := permission.Role{
+		Name:         "operator",
+		Access:       permission.WriteAccess,
+		Capabilities: []permission.Capability{"Action.*", "Client.FullStatus"},
+	}
+	c.Check(role.Allows("Action", "EnqueueOperation"), jc.IsTrue)
+	c.Check(role.Allows("Client", "FullStatus"), jc.IsTrue)
+	c.Check(role.Allows("Application", "SetConfigs"), jc.IsFalse)
+}
+
+func (*roleSuite) TestBuiltinRoles(c *gc.C) {
+	roles := permission.BuiltinRoles()
+	c.Assert(roles, gc.HasLen, 3)
+	for _, role := range roles {
+		c.Check(role.Validate(), jc.ErrorIsNil)
+		c.Check(role.Name, gc.Equals, string(role.Access))
+		c.Check(role.Allows("Application", "Deploy"), jc.IsTrue)
+	}
+
+	role, ok := permission.BuiltinRole("write")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(role.Access, gc.Equals, permission.WriteAccess)
+
+	_, ok = permission.BuiltinRole("consume")
+	c.Assert(ok, jc.IsFalse)
+}
@@ -0,0 +1,265 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package quota
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v3"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+)
+
+const (
+	// MachinesQuota is the name of the quota on the number of machines
+	// in a model, including containers.
+	MachinesQuota = "machines"
+
+	// UnitsQuota is the name of the quota on the number of principal
+	// units in a model.
+	UnitsQuota = "units"
+
+	// CoresQuota is the name of the quota on the number of CPU cores
+	// consumed by a model.
+	CoresQuota = "cores"
+
+	// MemoryQuota is the name of the quota on the memory consumed by
+	// a model.
+	MemoryQuota = "memory"
+
+	// StorageQuota is the name of the quota on the size of the volumes
+	// and filesystems in a model.
+	StorageQuota = "storage"
+)
+
+// ModelResources holds an amount of each of the resources whose
+// consumption by a model may be limited by a quota. Memory and storage
+// sizes are in MiB.
+//
+// When used to describe the quotas of a model, a zero value means that
+// the corresponding resource is not limited.
+type ModelResources struct {
+	Machines int
+	Units    int
+	Cores    uint64
+	Memory   uint64
+	Storage  uint64
+}
+
+// IsZero returns true if none of the resources have a non-zero amount.
+func (r ModelResources) IsZero() bool {
+	return r == ModelResources{}
+}
+
+// Add returns the sum of r and other.
+func (r ModelResources) Add(other ModelResources) ModelResources {
+	return ModelResources{
+		Machines: r.Machines + other.Machines,
+		Units:    r.Units + other.Units,
+		Cores:    r.Cores + other.Cores,
+		Memory:   r.Memory + other.Memory,
+		Storage:  r.Storage + other.Storage,
+	}
+}
+
+// Times returns r with each amount multiplied by n.
+func (r ModelResources) Times(n int) ModelResources {
+	return ModelResources{
+		Machines: r.Machines * n,
+		Units:    r.Units * n,
+		Cores:    r.Cores * uint64(n),
+		Memory:   r.Memory * uint64(n),
+		Storage:  r.Storage * uint64(n),
+	}
+}
+
+// Validate returns an error if any of the amounts are negative.
+func (r ModelResources) Validate() error {
+	if r.Machines < 0 {
+		return errors.NotValidf("negative %s quota", MachinesQuota)
+	}
+	if r.Units < 0 {
+		return errors.NotValidf("negative %s quota", UnitsQuota)
+	}
+	return nil
+}
+
+// String returns the non-zero amounts in the form accepted by
+// ParseModelResources.
+func (r ModelResources) String() string {
+	var parts []string
+	if r.Cores > 0 {
+		parts = append(parts, fmt.Sprintf("%s=%d", CoresQuota, r.Cores))
+	}
+	if r.Machines > 0 {
+		parts = append(parts, fmt.Sprintf("%s=%d", MachinesQuota, r.Machines))
+	}
+	if r.Memory > 0 {
+		parts = append(parts, fmt.Sprintf("%s=%dM", MemoryQuota, r.Memory))
+	}
+	if r.Storage > 0 {
+		parts = append(parts, fmt.Sprintf("%s=%dM", StorageQuota, r.Storage))
+	}
+	if r.Units > 0 {
+		parts = append(parts, fmt.Sprintf("%s=%d", UnitsQuota, r.Units))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseModelResources parses a list of name=value pairs, as produced by
+// ModelResources.String. Memory and storage sizes may carry a suffix of
+// M, G, T, P or E; sizes without a suffix are in MiB.
+func ParseModelResources(args ...string) (ModelResources, error) {
+	var r ModelResources
+	for _, arg := range args {
+		for _, item := range strings.Fields(arg) {
+			name, value, ok := strings.Cut(item, "=")
+			if !ok || name == "" || value == "" {
+				return ModelResources{}, errors.NotValidf("quota %q", item)
+			}
+			if err := r.set(name, value); err != nil {
+				return ModelResources{}, errors.Annotatef(err, "bad %q quota", name)
+			}
+		}
+	}
+	return r, nil
+}
+
+func (r *ModelResources) set(name, value string) error {
+	switch name {
+	case MachinesQuota, UnitsQuota:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return errors.Errorf("must be a non-negative integer, got %q", value)
+		}
+		if name == MachinesQuota {
+			r.Machines = n
+		} else {
+			r.Units = n
+		}
+	case CoresQuota:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return errors.Errorf("must be a non-negative integer, got %q", value)
+		}
+		r.Cores = n
+	case MemoryQuota, StorageQuota:
+		n, err := utils.ParseSize(value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if name == MemoryQuota {
+			r.Memory = n
+		} else {
+			r.Storage = n
+		}
+	default:
+		return errors.Errorf("unknown quota; valid quotas are %s", strings.Join(quotaNames(), ", "))
+	}
+	return nil
+}
+
+func quotaNames() []string {
+	names := []string{MachinesQuota, UnitsQuota, CoresQuota, MemoryQuota, StorageQuota}
+	sort.Strings(names)
+	return names
+}
+
+// ConstraintsResources returns the cores and memory that a machine, or
+// a container-based unit, with the given constraints will consume.
+// Resources that are not constrained are not counted.
+func ConstraintsResources(cons constraints.Value) ModelResources {
+	var r ModelResources
+	if cons.HasCpuCores() {
+		r.Cores = *cons.CpuCores
+	}
+	if cons.HasMem() {
+		r.Memory = *cons.Mem
+	}
+	return r
+}
+
+// HardwareResources returns the cores and memory of a machine with the
+// given hardware characteristics.
+func HardwareResources(hc instance.HardwareCharacteristics) ModelResources {
+	var r ModelResources
+	if hc.CpuCores != nil {
+		r.Cores = *hc.CpuCores
+	}
+	if hc.Mem != nil {
+		r.Memory = *hc.Mem
+	}
+	return r
+}
+
+var _ Checker = (*ModelQuotaChecker)(nil)
+
+// A ModelQuotaChecker can be used to verify that the resources requested
+// of a model, together with those the model already consumes, remain
+// within the model's quotas.
+type ModelQuotaChecker struct {
+	quotas  ModelResources
+	usage   ModelResources
+	lastErr error
+}
+
+// NewModelQuotaChecker returns a new ModelQuotaChecker instance for a
+// model with the given quotas, which currently consumes the resources
+// described by usage.
+func NewModelQuotaChecker(quotas, usage ModelResources) *ModelQuotaChecker {
+	return &ModelQuotaChecker{
+		quotas: quotas,
+		usage:  usage,
+	}
+}
+
+// Check adds the requested resources to the checker's running total and
+// verifies that the total remains within the model's quotas. Check expects
+// a ModelResources value as an argument; any other value will cause an
+// error to be returned when Outcome is called.
+func (c *ModelQuotaChecker) Check(v interface{}) {
+	if v == nil || c.lastErr != nil {
+		return
+	}
+
+	requested, ok := v.(ModelResources)
+	if !ok {
+		c.lastErr = errors.NotImplementedf("model quota check for %T values", v)
+		return
+	}
+
+	used := c.usage
+	c.usage = used.Add(requested)
+	for _, check := range []struct {
+		name                 string
+		unit                 string
+		limit, used, request uint64
+	}{
+		{MachinesQuota, "", uint64(c.quotas.Machines), uint64(used.Machines), uint64(requested.Machines)},
+		{UnitsQuota, "", uint64(c.quotas.Units), uint64(used.Units), uint64(requested.Units)},
+		{CoresQuota, "", c.quotas.Cores, used.Cores, requested.Cores},
+		{MemoryQuota, "MiB", c.quotas.Memory, used.Memory, requested.Memory},
+		{StorageQuota, "MiB", c.quotas.Storage, used.Storage, requested.Storage},
+	} {
+		if check.limit == 0 || check.request == 0 || check.used+check.request <= check.limit {
+			continue
+		}
+		c.lastErr = errors.QuotaLimitExceededf(
+			"model %s quota of %d%s exceeded (%d%s in use, %d%s requested)",
+			check.name, check.limit, check.unit, check.used, check.unit, check.request, check.unit,
+		)
+		return
+	}
+}
+
+// Outcome returns the check outcome or whether an error occurred within a call
+// to the Check method.
+func (c *ModelQuotaChecker) Outcome() error {
+	return c.lastErr
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package quota_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/quota"
+)
+
+var _ = gc.Suite(&ModelQuotaCheckerSuite{})
+
+type ModelQuotaCheckerSuite struct {
+}
+
+func (s *ModelQuotaCheckerSuite) TestNonModelResourcesValue(c *gc.C) {
+	chk := quota.NewModelQuotaChecker(quota.ModelResources{Machines: 1}, quota.ModelResources{})
+	chk.Check("not-resources")
+
+	err := chk.Outcome()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *ModelQuotaCheckerSuite) TestWithinQuotas(c *gc.C) {
+	chk := quota.NewModelQuotaChecker(
+		quota.ModelResources{Machines: 3, Cores: 8, Memory: 8192},
+		quota.ModelResources{Machines: 1, Units: 5, Cores: 4, Memory: 4096, Storage: 10240},
+	)
+	chk.Check(quota.ModelResources{Machines: 1, Units: 1, Cores: 2, Memory: 2048, Storage: 1024})
+	chk.Check(quota.ModelResources{Machines: 1, Units: 1, Cores: 2, Memory: 2048})
+
+	err := chk.Outcome()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ModelQuotaCheckerSuite) TestQuotaExceeded(c *gc.C) {
+	chk := quota.NewModelQuotaChecker(
+		quota.ModelResources{Machines: 3, Memory: 8192},
+		quota.ModelResources{Machines: 1, Memory: 4096},
+	)
+	chk.Check(quota.ModelResources{Machines: 1, Memory: 2048})
+	c.Assert(chk.Outcome(), jc.ErrorIsNil)
+	chk.Check(quota.ModelResources{Machines: 1, Memory: 4096})
+
+	err := chk.Outcome()
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `model memory quota of 8192MiB exceeded \(6144MiB in use, 4096MiB requested\)`)
+}
+
+func (s *ModelQuotaCheckerSuite) TestExistingExcessNotReported(c *gc.C) {
+	// Lowering a quota below the current usage must not prevent
+	// requests that do not consume the limited resource.
+	chk := quota.NewModelQuotaChecker(
+		quota.ModelResources{Cores: 4},
+		quota.ModelResources{Machines: 4, Cores: 16},
+	)
+	chk.Check(quota.ModelResources{Units: 1})
+	c.Assert(chk.Outcome(), jc.ErrorIsNil)
+
+	chk.Check(quota.ModelResources{Machines: 1, Cores: 1})
+	c.Assert(chk.Outcome(), gc.ErrorMatches, `model cores quota of 4 exceeded \(16 in use, 1 requested\)`)
+}
+
+var _ = gc.Suite(&ModelResourcesSuite{})
+
+type ModelResourcesSuite struct {
+}
+
+func (s *ModelResourcesSuite) TestParse(c *gc.C) {
+	r, err := quota.ParseModelResources("machines=10 units=20", "cores=32", "memory=64G", "storage=1T")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r, jc.DeepEquals, quota.ModelResources{
+		Machines: 10,
+		Units:    20,
+		Cores:    32,
+		Memory:   64 * 1024,
+		Storage:  1024 * 1024,
+	})
+	c.Assert(r.String(), gc.Equals, "cores=32 machines=10 memory=65536M storage=1048576M units=20")
+
+	roundTrip, err := quota.ParseModelResources(r.String())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(roundTrip, jc.DeepEquals, r)
+}
+
+func (s *ModelResourcesSuite) TestParseErrors(c *gc.C) {
+	for _, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"machines"},
+		err:  `quota "machines" not valid`,
+	}, {
+		args: []string{"=4"},
+		err:  `quota "=4" not valid`,
+	}, {
+		args: []string{"machines=-1"},
+		err:  `bad "machines" quota: must be a non-negative integer, got "-1"`,
+	}, {
+		args: []string{"cores=lots"},
+		err:  `bad "cores" quota: must be a non-negative integer, got "lots"`,
+	}, {
+		args: []string{"memory=lots"},
+		err:  `bad "memory" quota: .*`,
+	}, {
+		args: []string{"gpus=1"},
+		err:  `bad "gpus" quota: unknown quota; valid quotas are cores, machines, memory, storage, units`,
+	}} {
+		_, err := quota.ParseModelResources(test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ModelResourcesSuite) TestArithmetic(c *gc.C) {
+	r := quota.ModelResources{Machines: 1, Units: 2, Cores: 3, Memory: 4, Storage: 5}
+	c.Assert(r.Times(2), jc.DeepEquals, quota.ModelResources{Machines: 2, Units: 4, Cores: 6, Memory: 8, Storage: 10})
+	c.Assert(r.Add(r), jc.DeepEquals, r.Times(2))
+	c.Assert(r.IsZero(), jc.IsFalse)
+	c.Assert(quota.ModelResources{}.IsZero(), jc.IsTrue)
+}
+
+func (s *ModelResourcesSuite) TestConstraintsAndHardware(c *gc.C) {
+	cons := constraints.MustParse("cores=4 mem=8G root-disk=20G")
+	c.Assert(quota.ConstraintsResources(cons), jc.DeepEquals, quota.ModelResources{Cores: 4, Memory: 8192})
+	c.Assert(quota.ConstraintsResources(constraints.Value{}), jc.DeepEquals, quota.ModelResources{})
+
+	hc := instance.MustParseHardware("cores=2 mem=2G")
+	c.Assert(quota.HardwareResources(hc), jc.DeepEquals, quota.ModelResources{Cores: 2, Memory: 2048})
+}
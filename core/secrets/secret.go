@@ -187,6 +187,9 @@ type SecretRevisionMetadata struct {
 	CreateTime  time.Time
 	UpdateTime  time.Time
 	ExpireTime  *time.Time
+	// RollbackTo is set if the revision content was copied
+	// from this older revision.
+	RollbackTo int
 }
 
 // SecretOwnerMetadata holds a secret metadata and any backend references of revisions.
@@ -0,0 +1,36 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace
+
+// Attribute is a key value pair recorded on a span or event. The
+// value is one of string, int64, float64 or bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// StringAttr returns a string attribute.
+func StringAttr(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// IntAttr returns an integer attribute.
+func IntAttr(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// Int64Attr returns an integer attribute.
+func Int64Attr(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Float64Attr returns a floating point attribute.
+func Float64Attr(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// BoolAttr returns a boolean attribute.
+func BoolAttr(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
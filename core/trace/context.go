@@ -0,0 +1,96 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace
+
+import (
+	"context"
+	"runtime"
+	"strings"
+)
+
+type contextKey string
+
+const (
+	tracerContextKey contextKey = "tracer"
+	spanContextKey   contextKey = "span"
+	remoteContextKey contextKey = "remote-scope"
+)
+
+// WithTracer returns a new context with the given tracer.
+func WithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerContextKey, tracer)
+}
+
+// TracerFromContext returns the tracer from the context. If there is
+// no tracer, a NoopTracer is returned.
+func TracerFromContext(ctx context.Context) Tracer {
+	if tracer, ok := ctx.Value(tracerContextKey).(Tracer); ok && tracer != nil {
+		return tracer
+	}
+	return NoopTracer{}
+}
+
+// WithSpan returns a new context with the given span.
+func WithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanContextKey, span)
+}
+
+// SpanFromContext returns the current span from the context. If there
+// is no span, a NoopSpan is returned.
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanContextKey).(Span); ok && span != nil {
+		return span
+	}
+	return NoopSpan{}
+}
+
+// WithRemoteScope returns a new context with the scope of a span in
+// another process, typically received with a request. Spans started
+// with the context, which have no local parent, are part of the remote
+// span's trace.
+func WithRemoteScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, remoteContextKey, scope)
+}
+
+// RemoteScopeFromContext returns the remote scope from the context, or
+// an invalid scope if there isn't one.
+func RemoteScopeFromContext(ctx context.Context) Scope {
+	scope, _ := ctx.Value(remoteContextKey).(Scope)
+	return scope
+}
+
+// ParentScope returns the scope that a span started with the context
+// should use as its parent: either the current span, or the remote
+// scope. The scope is invalid if there is no parent.
+func ParentScope(ctx context.Context) Scope {
+	if scope := SpanFromContext(ctx).Scope(); scope.IsValid() {
+		return scope
+	}
+	return RemoteScopeFromContext(ctx)
+}
+
+// Start starts a span using the tracer from the context.
+func Start(ctx context.Context, name string, options ...Option) (context.Context, Span) {
+	return TracerFromContext(ctx).Start(ctx, name, options...)
+}
+
+// NameFromFunc returns the name of the calling function, for use as a
+// span name. The package path is removed, leaving the package name,
+// any receiver type and the function name, for example
+// "txn.(*TransactionRunner).Txn".
+func NameFromFunc() string {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
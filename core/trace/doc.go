@@ -0,0 +1,12 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package trace defines the interfaces used to record distributed
+// traces. The tracer is carried in a context.Context, along with the
+// current span, so that code only needs a context to add spans:
+//
+//	ctx, span := trace.Start(ctx, trace.NameFromFunc())
+//	defer span.End()
+//
+// If the context has no tracer, the spans are no-ops.
+package trace
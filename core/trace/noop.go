@@ -0,0 +1,44 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace
+
+import "context"
+
+// NoopTracer is a tracer that doesn't record anything. Spans it starts
+// keep the scope of their parent, so that trace context is still
+// propagated.
+type NoopTracer struct{}
+
+// Start implements Tracer.
+func (NoopTracer) Start(ctx context.Context, name string, options ...Option) (context.Context, Span) {
+	span := NoopSpan{scope: SpanFromContext(ctx).Scope()}
+	if !span.scope.IsValid() {
+		span.scope = RemoteScopeFromContext(ctx)
+	}
+	return WithSpan(ctx, span), span
+}
+
+// Enabled implements Tracer.
+func (NoopTracer) Enabled() bool {
+	return false
+}
+
+// NoopSpan is a span that doesn't record anything.
+type NoopSpan struct {
+	scope Scope
+}
+
+// Scope implements Span.
+func (s NoopSpan) Scope() Scope {
+	return s.scope
+}
+
+// AddEvent implements Span.
+func (NoopSpan) AddEvent(string, ...Attribute) {}
+
+// RecordError implements Span.
+func (NoopSpan) RecordError(error, ...Attribute) {}
+
+// End implements Span.
+func (NoopSpan) End(...Attribute) {}
@@ -0,0 +1,86 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace
+
+import (
+	"encoding/hex"
+
+	"github.com/juju/errors"
+)
+
+// FlagSampled is the trace flag set when a trace is being recorded.
+const FlagSampled = 0x01
+
+// TraceID identifies a trace. It's shared by all spans in the trace.
+type TraceID [16]byte
+
+// IsValid returns true if the trace ID is not all zeros.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String returns the hex encoding of the trace ID.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// IsValid returns true if the span ID is not all zeros.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// String returns the hex encoding of the span ID.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// Scope identifies a span, and whether its trace is sampled. It's the
+// information propagated between processes so that the spans they
+// create are part of the same trace.
+type Scope struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid returns true if both the trace and span IDs are valid.
+func (s Scope) IsValid() bool {
+	return s.TraceID.IsValid() && s.SpanID.IsValid()
+}
+
+// Flags returns the trace flags of the scope.
+func (s Scope) Flags() int {
+	if s.Sampled {
+		return FlagSampled
+	}
+	return 0
+}
+
+// ParseScope returns the scope with the given hex encoded trace and
+// span IDs and trace flags.
+func ParseScope(traceID, spanID string, flags int) (Scope, error) {
+	var scope Scope
+	if err := decodeID(scope.TraceID[:], traceID); err != nil {
+		return Scope{}, errors.NotValidf("trace ID %q", traceID)
+	}
+	if err := decodeID(scope.SpanID[:], spanID); err != nil {
+		return Scope{}, errors.NotValidf("span ID %q", spanID)
+	}
+	if !scope.IsValid() {
+		return Scope{}, errors.NotValidf("zero trace scope")
+	}
+	scope.Sampled = flags&FlagSampled != 0
+	return scope, nil
+}
+
+func decodeID(dst []byte, s string) error {
+	if hex.DecodedLen(len(s)) != len(dst) {
+		return errors.New("wrong length")
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace_test
+
+import (
+	"context"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/trace"
+)
+
+type traceSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&traceSuite{})
+
+func (s *traceSuite) TestParseScope(c *gc.C) {
+	scope, err := trace.ParseScope("0102030405060708090a0b0c0d0e0f10", "0102030405060708", trace.FlagSampled)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(scope.IsValid(), jc.IsTrue)
+	c.Check(scope.Sampled, jc.IsTrue)
+	c.Check(scope.Flags(), gc.Equals, trace.FlagSampled)
+	c.Check(scope.TraceID.String(), gc.Equals, "0102030405060708090a0b0c0d0e0f10")
+	c.Check(scope.SpanID.String(), gc.Equals, "0102030405060708")
+}
+
+func (s *traceSuite) TestParseScopeInvalid(c *gc.C) {
+	_, err := trace.ParseScope("0102", "0102030405060708", 0)
+	c.Check(err, gc.ErrorMatches, `trace ID "0102" not valid`)
+	_, err = trace.ParseScope("0102030405060708090a0b0c0d0e0f10", "xx02030405060708", 0)
+	c.Check(err, gc.ErrorMatches, `span ID "xx02030405060708" not valid`)
+	_, err = trace.ParseScope("00000000000000000000000000000000", "0000000000000000", 0)
+	c.Check(err, gc.ErrorMatches, `zero trace scope not valid`)
+}
+
+func (s *traceSuite) TestStartWithoutTracer(c *gc.C) {
+	ctx, span := trace.Start(context.Background(), "test")
+	c.Check(span, gc.Equals, trace.SpanFromContext(ctx))
+	c.Check(span.Scope().IsValid(), jc.IsFalse)
+	c.Check(trace.TracerFromContext(ctx).Enabled(), jc.IsFalse)
+}
+
+func (s *traceSuite) TestNoopTracerKeepsRemoteScope(c *gc.C) {
+	scope, err := trace.ParseScope("0102030405060708090a0b0c0d0e0f10", "0102030405060708", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := trace.WithRemoteScope(context.Background(), scope)
+	c.Check(trace.ParentScope(ctx), gc.Equals, scope)
+
+	ctx, span := trace.Start(ctx, "test")
+	c.Check(span.Scope(), gc.Equals, scope)
+	_, child := trace.Start(ctx, "child")
+	c.Check(child.Scope(), gc.Equals, scope)
+}
+
+func (s *traceSuite) TestNameFromFunc(c *gc.C) {
+	c.Check(trace.NameFromFunc(), gc.Equals, "trace_test.(*traceSuite).TestNameFromFunc")
+}
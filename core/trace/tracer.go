@@ -0,0 +1,111 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace
+
+import (
+	"context"
+	"time"
+)
+
+// Kind describes the relationship between a span and its parent and
+// children.
+type Kind int
+
+const (
+	// KindInternal is an operation internal to a process.
+	KindInternal Kind = iota
+
+	// KindServer is the server side handling of a remote request.
+	KindServer
+
+	// KindClient is the client side of a remote request.
+	KindClient
+)
+
+// String returns the name of the span kind.
+func (k Kind) String() string {
+	switch k {
+	case KindServer:
+		return "server"
+	case KindClient:
+		return "client"
+	}
+	return "internal"
+}
+
+// Tracer creates spans.
+type Tracer interface {
+	// Start creates a span and a context containing the newly created
+	// span. If the context already contains a span, the new span is a
+	// child of it.
+	Start(ctx context.Context, name string, options ...Option) (context.Context, Span)
+
+	// Enabled returns true if the tracer records spans.
+	Enabled() bool
+}
+
+// Span is a single operation within a trace.
+type Span interface {
+	// Scope returns the scope of the span, which identifies it within
+	// its trace.
+	Scope() Scope
+
+	// AddEvent records a point in time event on the span.
+	AddEvent(message string, attrs ...Attribute)
+
+	// RecordError records an error on the span and marks the span as
+	// failed. A nil error is ignored.
+	RecordError(err error, attrs ...Attribute)
+
+	// End completes the span. Any attributes are added to the span
+	// before it ends. Calling End more than once has no effect.
+	End(attrs ...Attribute)
+}
+
+// Option modifies the creation of a span.
+type Option func(*Options)
+
+// Options holds the values that can be set when creating a span.
+type Options struct {
+	// Kind is the kind of span.
+	Kind Kind
+
+	// Attributes are set on the span when it is created.
+	Attributes []Attribute
+
+	// StartTime overrides the start time of the span. If it is zero,
+	// the time the span is created is used.
+	StartTime time.Time
+}
+
+// NewOptions returns the options for creating a span.
+func NewOptions(options ...Option) Options {
+	var o Options
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
+
+// WithKind sets the kind of the span.
+func WithKind(kind Kind) Option {
+	return func(o *Options) {
+		o.Kind = kind
+	}
+}
+
+// WithAttributes sets the attributes of the span.
+func WithAttributes(attrs ...Attribute) Option {
+	return func(o *Options) {
+		o.Attributes = append(o.Attributes, attrs...)
+	}
+}
+
+// WithStartTime sets the start time of the span. It's used for
+// operations which are only known about once they've completed.
+func WithStartTime(t time.Time) Option {
+	return func(o *Options) {
+		o.StartTime = t
+	}
+}
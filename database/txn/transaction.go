@@ -12,6 +12,8 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/retry"
+
+	"github.com/juju/juju/core/trace"
 )
 
 // Logger describes methods for emitting log output.
@@ -104,7 +106,13 @@ func NewTransactionRunner(opts ...Option) *TransactionRunner {
 //
 // This should not be used directly, instead the TrackedDB should be used to
 // handle transactions.
-func (t *TransactionRunner) Txn(ctx context.Context, db *sql.DB, fn func(context.Context, *sql.Tx) error) error {
+func (t *TransactionRunner) Txn(ctx context.Context, db *sql.DB, fn func(context.Context, *sql.Tx) error) (err error) {
+	ctx, span := trace.Start(ctx, trace.NameFromFunc())
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	ctx, cancel := context.WithTimeout(ctx, t.timeout)
 	defer cancel()
 
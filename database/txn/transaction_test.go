@@ -12,6 +12,7 @@ import (
 	"github.com/mattn/go-sqlite3"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/database/testing"
 	"github.com/juju/juju/database/txn"
 )
@@ -105,6 +106,21 @@ func (s *transactionRunnerSuite) TestTxnRollback(c *gc.C) {
 	}
 }
 
+func (s *transactionRunnerSuite) TestTxnRecordsSpan(c *gc.C) {
+	runner := txn.NewTransactionRunner()
+
+	var tracer recordingTracer
+	ctx := trace.WithTracer(context.Background(), &tracer)
+	err := runner.Txn(ctx, s.DB(), func(ctx context.Context, tx *sql.Tx) error {
+		return errors.Errorf("fail")
+	})
+	c.Assert(err, gc.ErrorMatches, "fail")
+
+	c.Assert(tracer.names, jc.DeepEquals, []string{"txn.(*TransactionRunner).Txn"})
+	c.Assert(tracer.errs, gc.HasLen, 1)
+	c.Assert(tracer.errs[0], gc.ErrorMatches, "fail")
+}
+
 func (s *transactionRunnerSuite) TestRetryForNonRetryableError(c *gc.C) {
 	runner := txn.NewTransactionRunner()
 
@@ -149,3 +165,31 @@ func (s *transactionRunnerSuite) createTable(c *gc.C) {
 	_, err := s.DB().Exec("CREATE TEMP TABLE foo (id INT PRIMARY KEY, name VARCHAR(255))")
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+// recordingTracer records the names of the spans it starts, and the
+// errors recorded on them.
+type recordingTracer struct {
+	names []string
+	errs  []error
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, _ ...trace.Option) (context.Context, trace.Span) {
+	t.names = append(t.names, name)
+	span := recordingSpan{tracer: t}
+	return trace.WithSpan(ctx, span), span
+}
+
+func (t *recordingTracer) Enabled() bool {
+	return true
+}
+
+type recordingSpan struct {
+	trace.NoopSpan
+	tracer *recordingTracer
+}
+
+func (s recordingSpan) RecordError(err error, _ ...trace.Attribute) {
+	if err != nil {
+		s.tracer.errs = append(s.tracer.errs, err)
+	}
+}
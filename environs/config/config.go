@@ -32,6 +32,8 @@ import (
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/logfwd/destination"
+	"github.com/juju/juju/logfwd/otlp"
 	"github.com/juju/juju/logfwd/syslog"
 	jujuversion "github.com/juju/juju/version"
 )
@@ -207,6 +209,36 @@ const (
 	// forwarding.
 	LogFwdSyslogClientKey = "syslog-client-key"
 
+	// LogFwdOTLPEndpoint sets the address of the OTLP logs receiver.
+	LogFwdOTLPEndpoint = "logforward-otlp-endpoint"
+
+	// LogFwdOTLPProtocol sets the OTLP transport protocol, either
+	// "grpc" or "http/protobuf".
+	LogFwdOTLPProtocol = "logforward-otlp-protocol"
+
+	// LogFwdOTLPInsecure disables TLS when connecting to the OTLP
+	// logs receiver.
+	LogFwdOTLPInsecure = "logforward-otlp-insecure"
+
+	// LogFwdOTLPCACert sets the certificate of the CA that signed the
+	// OTLP receiver certificate.
+	LogFwdOTLPCACert = "logforward-otlp-ca-cert"
+
+	// LogFwdOTLPClientCert sets the client certificate for OTLP
+	// forwarding.
+	LogFwdOTLPClientCert = "logforward-otlp-client-cert"
+
+	// LogFwdOTLPClientKey sets the client key for OTLP forwarding.
+	LogFwdOTLPClientKey = "logforward-otlp-client-key"
+
+	// LogFwdOTLPHeaders sets the headers sent with each OTLP export
+	// request, as comma separated key=value pairs.
+	LogFwdOTLPHeaders = "logforward-otlp-headers"
+
+	// LogFwdDestinations sets the named log forwarding destinations,
+	// each with its own target and filters, as YAML.
+	LogFwdDestinations = "logforward-destinations"
+
 	// AutomaticallyRetryHooks determines whether the uniter will
 	// automatically retry a hook that has failed
 	AutomaticallyRetryHooks = "automatically-retry-hooks"
@@ -727,11 +759,23 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
-	if lfCfg, ok := cfg.LogFwdSyslog(); ok {
+	// Log forwarding may be enabled with only an OTLP receiver
+	// configured, in which case the syslog host isn't required.
+	otlpCfg, ok := cfg.LogFwdOTLP()
+	otlpConfigured := ok && otlpCfg.Endpoint != ""
+	if lfCfg, ok := cfg.LogFwdSyslog(); ok && (lfCfg.Host != "" || !otlpConfigured) {
 		if err := lfCfg.Validate(); err != nil {
 			return errors.Annotate(err, "invalid syslog forwarding config")
 		}
 	}
+	if otlpConfigured {
+		if err := otlpCfg.Validate(); err != nil {
+			return errors.Annotate(err, "invalid OTLP forwarding config")
+		}
+	}
+	if _, err := cfg.LogFwdDestinations(); err != nil {
+		return errors.Annotate(err, "invalid log forwarding destinations")
+	}
 
 	if uuid := cfg.UUID(); !utils.IsValidUUIDString(uuid) {
 		return errors.Errorf("uuid: expected UUID, got string(%q)", uuid)
@@ -1307,6 +1351,69 @@ func (c *Config) LogFwdSyslog() (*syslog.RawConfig, bool) {
 	return &lfCfg, true
 }
 
+// LogFwdOTLP returns the OTLP forwarding config.
+func (c *Config) LogFwdOTLP() (*otlp.RawConfig, bool) {
+	partial := false
+	var lfCfg otlp.RawConfig
+
+	if s, ok := c.defined[LogForwardEnabled]; ok {
+		partial = true
+		lfCfg.Enabled = s.(bool)
+	}
+
+	if s, ok := c.defined[LogFwdOTLPEndpoint]; ok && s != "" {
+		partial = true
+		lfCfg.Endpoint = s.(string)
+	}
+
+	if s, ok := c.defined[LogFwdOTLPProtocol]; ok && s != "" {
+		partial = true
+		lfCfg.Protocol = s.(string)
+	}
+
+	if s, ok := c.defined[LogFwdOTLPInsecure]; ok {
+		partial = true
+		lfCfg.Insecure = s.(bool)
+	}
+
+	if s, ok := c.defined[LogFwdOTLPCACert]; ok && s != "" {
+		partial = true
+		lfCfg.CACert = s.(string)
+	}
+
+	if s, ok := c.defined[LogFwdOTLPClientCert]; ok && s != "" {
+		partial = true
+		lfCfg.ClientCert = s.(string)
+	}
+
+	if s, ok := c.defined[LogFwdOTLPClientKey]; ok && s != "" {
+		partial = true
+		lfCfg.ClientKey = s.(string)
+	}
+
+	if s, ok := c.defined[LogFwdOTLPHeaders]; ok && s != "" {
+		partial = true
+		lfCfg.Headers = s.(string)
+	}
+
+	if !partial {
+		return nil, false
+	}
+	return &lfCfg, true
+}
+
+// LogFwdDestinations returns the named log forwarding destinations,
+// sorted by name.
+func (c *Config) LogFwdDestinations() ([]destination.Config, error) {
+	value, _ := c.defined[LogFwdDestinations].(string)
+	if value == "" {
+		return nil, nil
+	}
+	enabled, _ := c.defined[LogForwardEnabled].(bool)
+	dests, err := destination.Parse(value, enabled)
+	return dests, errors.Trace(err)
+}
+
 // FirewallMode returns whether the firewall should
 // manage ports per machine, globally, or not at all.
 // (FwInstance, FwGlobal, or FwNone).
@@ -1826,6 +1933,14 @@ var alwaysOptional = schema.Defaults{
 	LogFwdSyslogCACert:     schema.Omit,
 	LogFwdSyslogClientCert: schema.Omit,
 	LogFwdSyslogClientKey:  schema.Omit,
+	LogFwdOTLPEndpoint:     schema.Omit,
+	LogFwdOTLPProtocol:     schema.Omit,
+	LogFwdOTLPInsecure:     schema.Omit,
+	LogFwdOTLPCACert:       schema.Omit,
+	LogFwdOTLPClientCert:   schema.Omit,
+	LogFwdOTLPClientKey:    schema.Omit,
+	LogFwdOTLPHeaders:      schema.Omit,
+	LogFwdDestinations:     schema.Omit,
 	LoggingOutputKey:       schema.Omit,
 
 	// Storage related config.
@@ -2296,7 +2411,7 @@ global or per instance security groups.`,
 		Group:       environschema.EnvironGroup,
 	},
 	LogForwardEnabled: {
-		Description: `Whether syslog forwarding is enabled.`,
+		Description: `Whether log forwarding to syslog and OTLP receivers is enabled.`,
 		Type:        environschema.Tbool,
 		Group:       environschema.EnvironGroup,
 	},
@@ -2320,6 +2435,46 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	LogFwdOTLPEndpoint: {
+		Description: `The host:port (or, for http/protobuf, the URL) of the OTLP logs receiver.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdOTLPProtocol: {
+		Description: `The OTLP transport protocol, either "grpc" or "http/protobuf". (default "grpc")`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdOTLPInsecure: {
+		Description: `Whether to connect to the OTLP logs receiver without TLS.`,
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdOTLPCACert: {
+		Description: `The certificate of the CA that signed the OTLP receiver certificate, in PEM format.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdOTLPClientCert: {
+		Description: `The OTLP client certificate in PEM format.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdOTLPClientKey: {
+		Description: `The OTLP client key in PEM format.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdOTLPHeaders: {
+		Description: `Headers sent with each OTLP export request, as comma separated key=value pairs.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdDestinations: {
+		Description: `Named log forwarding destinations as YAML, each with a syslog or otlp target and optional entities, modules and level filters.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"ssl-hostname-verification": {
 		Description: "Whether SSL hostname verification is enabled (default true)",
 		Type:        environschema.Tbool,
@@ -585,6 +585,65 @@ var configTests = []configTest{
 			"syslog-client-key":  serverKey2,
 		}),
 		err: `invalid syslog forwarding config: validating TLS config: parsing client key pair: (crypto/)?tls: private key does not match public key`,
+	}, {
+		about:       "Valid OTLP config values",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":          true,
+			"logforward-otlp-endpoint":    "otel.example.com:4317",
+			"logforward-otlp-ca-cert":     testing.CACert,
+			"logforward-otlp-client-cert": testing.ServerCert,
+			"logforward-otlp-client-key":  testing.ServerKey,
+			"logforward-otlp-headers":     "authorization=Bearer xyz",
+		}),
+	}, {
+		about:       "Insecure OTLP over HTTP",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":       true,
+			"logforward-otlp-endpoint": "localhost:4318",
+			"logforward-otlp-protocol": "http/protobuf",
+			"logforward-otlp-insecure": true,
+		}),
+	}, {
+		about:       "Invalid OTLP protocol",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":       true,
+			"logforward-otlp-endpoint": "localhost:4318",
+			"logforward-otlp-protocol": "http/json",
+		}),
+		err: `invalid OTLP forwarding config: Protocol "http/json" not valid`,
+	}, {
+		about:       "Invalid OTLP headers",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":       true,
+			"logforward-otlp-endpoint": "localhost:4317",
+			"logforward-otlp-headers":  "authorization",
+		}),
+		err: `invalid OTLP forwarding config: header "authorization", expected key=value not valid`,
+	}, {
+		about:       "Log forwarding enabled without a target",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled": true,
+		}),
+		err: `invalid syslog forwarding config: Host "" not valid`,
+	}, {
+		about:       "Valid log forwarding destinations",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":      true,
+			"logforward-destinations": "siem:\n  otlp:\n    endpoint: siem.example.com:4317\n  level: ERROR\n",
+		}),
+	}, {
+		about:       "Invalid log forwarding destinations",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-destinations": "siem:\n  level: ERROR\n",
+		}),
+		err: `invalid log forwarding destinations: destination "siem" without a syslog or otlp target not valid`,
 	}, {
 		about:       "net-bond-reconfigure-delay value",
 		useDefaults: config.UseDefaults,
@@ -778,6 +837,32 @@ func (test configTest) check(c *gc.C) {
 		c.Check(lfCfg.ClientKey, gc.Equals, "")
 	}
 
+	dests, err := cfg.LogFwdDestinations()
+	c.Assert(err, jc.ErrorIsNil)
+	if v, _ := test.attrs["logforward-destinations"].(string); v != "" {
+		c.Check(dests, gc.Not(gc.HasLen), 0)
+	} else {
+		c.Check(dests, gc.HasLen, 0)
+	}
+
+	otlpCfg, hasOTLPCfg := cfg.LogFwdOTLP()
+	if v, ok := test.attrs["logforward-otlp-endpoint"].(string); ok {
+		c.Assert(hasOTLPCfg, jc.IsTrue)
+		c.Check(otlpCfg.Endpoint, gc.Equals, v)
+	}
+	if v, ok := test.attrs["logforward-otlp-protocol"].(string); ok {
+		c.Assert(hasOTLPCfg, jc.IsTrue)
+		c.Check(otlpCfg.Protocol, gc.Equals, v)
+	}
+	if v, ok := test.attrs["logforward-otlp-insecure"].(bool); ok {
+		c.Assert(hasOTLPCfg, jc.IsTrue)
+		c.Check(otlpCfg.Insecure, gc.Equals, v)
+	}
+	if v, ok := test.attrs["logforward-otlp-headers"].(string); ok {
+		c.Assert(hasOTLPCfg, jc.IsTrue)
+		c.Check(otlpCfg.Headers, gc.Equals, v)
+	}
+
 	if v, ok := test.attrs["ssl-hostname-verification"]; ok {
 		c.Assert(cfg.SSLHostnameVerification(), gc.Equals, v)
 	}
@@ -0,0 +1,23 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"github.com/juju/juju/core/trace"
+)
+
+// StartSpan starts a client span for a call to the cloud substrate,
+// using the tracer carried by the supplied context. The returned
+// context carries the new span, and invalidates credentials in the
+// same way as the original.
+func StartSpan(ctx ProviderCallContext, name string, attrs ...trace.Attribute) (ProviderCallContext, trace.Span) {
+	spanCtx, span := trace.Start(ctx, name,
+		trace.WithKind(trace.KindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return &CloudCallContext{
+		Context:                  spanCtx,
+		InvalidateCredentialFunc: ctx.InvalidateCredential,
+	}, span
+}
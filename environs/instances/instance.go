@@ -41,3 +41,20 @@ type InstanceFirewaller interface {
 	// address rules for that port range.
 	IngressRules(ctx context.ProviderCallContext, machineId string) (firewall.IngressRules, error)
 }
+
+// InstanceEgressFirewaller provides instance-level control of outbound
+// traffic. It is implemented by instances whose provider firewall can
+// restrict egress.
+type InstanceEgressFirewaller interface {
+	// SetEgressRules replaces the egress rules on the instance, which
+	// should have been started with the given machine id. Outbound
+	// traffic is restricted to the given rules; when there are none,
+	// outbound traffic is not restricted, as is the case for newly
+	// started instances.
+	SetEgressRules(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error
+
+	// EgressRules returns the egress rules set on the instance, which
+	// should have been started with the given machine id. No rules are
+	// returned when outbound traffic is not restricted.
+	EgressRules(ctx context.ProviderCallContext, machineId string) (firewall.EgressRules, error)
+}
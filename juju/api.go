@@ -219,8 +219,11 @@ func connectionInfo(args NewAPIConnectionParams) (*api.Info, *jujuclient.Control
 		// authenticate using macaroons.
 		apiInfo.Password = account.Password
 	} else {
-		// Optionally the account may have macaroons to use.
+		// Optionally the account may have macaroons, or an ID
+		// token issued by the controller's OpenID Connect
+		// provider, to use.
 		apiInfo.Macaroons = account.Macaroons
+		apiInfo.IDToken = account.IDToken
 	}
 	return apiInfo, controller, nil
 }
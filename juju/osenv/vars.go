@@ -54,6 +54,22 @@ const (
 	// timestamps to be written in RFC3339 format.
 	JujuStatusIsoTimeEnvKey = "JUJU_STATUS_ISO_TIME"
 
+	// JujuTracingEndpointEnvKey, if set, is the address of an OTLP
+	// receiver the client sends the spans of each command to.
+	JujuTracingEndpointEnvKey = "JUJU_TRACING_ENDPOINT"
+
+	// JujuTracingProtocolEnvKey is the OTLP protocol used to send spans,
+	// either "grpc" (the default) or "http/protobuf".
+	JujuTracingProtocolEnvKey = "JUJU_TRACING_PROTOCOL"
+
+	// JujuTracingInsecureEnvKey is the env var which if true, will cause
+	// spans to be sent to the OTLP receiver without TLS.
+	JujuTracingInsecureEnvKey = "JUJU_TRACING_INSECURE"
+
+	// JujuClientStorePassphraseEnvKey is the env var holding the
+	// passphrase for an encrypted client store.
+	JujuClientStorePassphraseEnvKey = "JUJU_CLIENT_STORE_PASSPHRASE"
+
 	// XDGDataHome is a path where data for the running user
 	// should be stored according to the xdg standard.
 	XDGDataHome = "XDG_DATA_HOME"
@@ -24,24 +24,55 @@ func JujuAccountsPath() string {
 // ReadAccountsFile loads all accounts defined in a given file.
 // If the file is not found, it is not an error.
 func ReadAccountsFile(file string) (map[string]AccountDetails, error) {
+	c, err := storeCipher()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	accounts, plaintext, err := readAccountsFile(file, c)
+	if err != nil || accounts == nil {
+		return nil, err
+	}
+	// Passwords saved before the store was encrypted are encrypted
+	// as soon as they're seen.
+	if migrateLocalAccountUsers(accounts) || (c != nil && plaintext) {
+		if err := writeAccountsFile(accounts, c); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// readAccountsFile reads the accounts file, decrypting any encrypted
+// passwords, and reports whether any passwords were held in plain text.
+func readAccountsFile(file string, c SecretCipher) (map[string]AccountDetails, bool, error) {
 	data, err := os.ReadFile(file)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil
+			return nil, false, nil
 		}
-		return nil, err
+		return nil, false, err
 	}
 	accounts, err := ParseAccounts(data)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	if err := migrateLocalAccountUsers(accounts); err != nil {
-		return nil, err
+	t := &secretTransformer{cipher: c}
+	for name, account := range accounts {
+		if account.Password, err = t.decrypt(account.Password); err != nil {
+			return nil, false, errors.Annotatef(err, "account password for controller %q", name)
+		}
+		if account.IDToken, err = t.decrypt(account.IDToken); err != nil {
+			return nil, false, errors.Annotatef(err, "account ID token for controller %q", name)
+		}
+		if account.RefreshToken, err = t.decrypt(account.RefreshToken); err != nil {
+			return nil, false, errors.Annotatef(err, "account refresh token for controller %q", name)
+		}
+		accounts[name] = account
 	}
-	return accounts, nil
+	return accounts, t.plaintext, nil
 }
 
-func migrateLocalAccountUsers(accounts map[string]AccountDetails) error {
+func migrateLocalAccountUsers(accounts map[string]AccountDetails) bool {
 	changes := false
 	for user, account := range accounts {
 		if !strings.HasSuffix(account.User, "@local") {
@@ -53,16 +84,39 @@ func migrateLocalAccountUsers(accounts map[string]AccountDetails) error {
 		accounts[user] = updated
 		changes = true
 	}
-	if changes {
-		return WriteAccountsFile(accounts)
-	}
-	return nil
+	return changes
 }
 
 // WriteAccountsFile marshals to YAML details of the given accounts
 // and writes it to the accounts file.
 func WriteAccountsFile(controllerAccounts map[string]AccountDetails) error {
-	data, err := yaml.Marshal(accountsCollection{controllerAccounts})
+	c, err := storeCipher()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return writeAccountsFile(controllerAccounts, c)
+}
+
+// writeAccountsFile writes the accounts to the accounts file,
+// encrypting the passwords if c is not nil.
+func writeAccountsFile(controllerAccounts map[string]AccountDetails, c SecretCipher) error {
+	t := &secretTransformer{cipher: c}
+	encrypted := make(map[string]AccountDetails, len(controllerAccounts))
+	for name, account := range controllerAccounts {
+		password, err := t.encrypt(account.Password)
+		if err != nil {
+			return errors.Annotatef(err, "account password for controller %q", name)
+		}
+		account.Password = password
+		if account.IDToken, err = t.encrypt(account.IDToken); err != nil {
+			return errors.Annotatef(err, "account ID token for controller %q", name)
+		}
+		if account.RefreshToken, err = t.encrypt(account.RefreshToken); err != nil {
+			return errors.Annotatef(err, "account refresh token for controller %q", name)
+		}
+		encrypted[name] = account
+	}
+	data, err := yaml.Marshal(accountsCollection{encrypted})
 	if err != nil {
 		return errors.Annotate(err, "cannot marshal accounts")
 	}
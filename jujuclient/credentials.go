@@ -23,18 +23,49 @@ func JujuCredentialsPath() string {
 // ReadCredentialsFile loads all credentials defined in a given file.
 // If the file is not found, it is not an error.
 func ReadCredentialsFile(file string) (*cloud.CredentialCollection, error) {
+	credentials, _, err := readCredentialsFile(file)
+	return credentials, err
+}
+
+// readCredentialsFile loads all credentials defined in a given file,
+// and reports whether any of them need to be written back encrypted
+// because they were held in plain text in an encrypted store.
+func readCredentialsFile(file string) (*cloud.CredentialCollection, bool, error) {
+	c, err := storeCipher()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	data, plaintext, err := readCredentialsData(file, c)
+	if err != nil {
+		return nil, false, err
+	}
+	if data == nil {
+		return &cloud.CredentialCollection{}, false, nil
+	}
+	credentials, err := cloud.ParseCredentialCollection(data)
+	if err != nil {
+		return nil, false, err
+	}
+	return credentials, c != nil && plaintext, nil
+}
+
+// readCredentialsData reads the credentials file, decrypting any
+// encrypted values, and reports whether any values were held in plain
+// text. It returns nil data if the file doesn't exist.
+func readCredentialsData(file string, c SecretCipher) ([]byte, bool, error) {
 	data, err := os.ReadFile(file)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &cloud.CredentialCollection{}, nil
+			return nil, false, nil
 		}
-		return nil, err
+		return nil, false, err
 	}
-	credentials, err := cloud.ParseCredentialCollection(data)
+	t := &secretTransformer{cipher: c}
+	data, err = transformCredentials(data, t.decrypt)
 	if err != nil {
-		return nil, err
+		return nil, false, errors.Trace(err)
 	}
-	return credentials, nil
+	return data, t.plaintext, nil
 }
 
 // WriteCredentialsFile marshals to YAML details of the given credentials
@@ -44,5 +75,22 @@ func WriteCredentialsFile(credentials *cloud.CredentialCollection) error {
 	if err != nil {
 		return errors.Annotate(err, "cannot marshal yaml credentials")
 	}
+	c, err := storeCipher()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return writeCredentialsData(data, c)
+}
+
+// writeCredentialsData writes the YAML credentials data to the
+// credentials file, encrypting the attribute values if c is not nil.
+func writeCredentialsData(data []byte, c SecretCipher) error {
+	if c != nil {
+		t := &secretTransformer{cipher: c}
+		var err error
+		if data, err = transformCredentials(data, t.encrypt); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return utils.AtomicWriteFile(JujuCredentialsPath(), data, os.FileMode(0600))
 }
@@ -0,0 +1,490 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v3"
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+const (
+	// encryptedPrefix marks a value in the client store files which
+	// has been encrypted, and the version of the scheme used.
+	encryptedPrefix = "juju-encrypted:v1:"
+
+	// checkPlaintext is encrypted and recorded alongside the
+	// encryption config, so that a wrong passphrase can be told
+	// apart from corrupt files.
+	checkPlaintext = "juju-client-store"
+
+	saltSize = 16
+	keySize  = 32
+)
+
+// JujuClientStoreConfigPath is the location of the file recording how
+// the client store is encrypted.
+func JujuClientStoreConfigPath() string {
+	return osenv.JujuXDGDataHomePath("client-store.yaml")
+}
+
+// SecretCipher encrypts and decrypts the secret values held in the
+// client store: account passwords and tokens, and cloud credential
+// attributes.
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// PassphraseSource supplies the passphrase from which the client store
+// encryption key is derived.
+type PassphraseSource interface {
+	Passphrase() (string, error)
+}
+
+// EnvPassphrase reads the passphrase from $JUJU_CLIENT_STORE_PASSPHRASE.
+type EnvPassphrase struct{}
+
+// Passphrase implements PassphraseSource.
+func (EnvPassphrase) Passphrase() (string, error) {
+	passphrase := os.Getenv(osenv.JujuClientStorePassphraseEnvKey)
+	if passphrase == "" {
+		return "", errors.Errorf("client store is encrypted: set %s to read it", osenv.JujuClientStorePassphraseEnvKey)
+	}
+	return passphrase, nil
+}
+
+// HelperPassphrase runs an external command to get the passphrase, in
+// the same way git runs its credential helpers. The command is run with
+// the extra argument "get", and must print the passphrase on stdout.
+type HelperPassphrase string
+
+// Passphrase implements PassphraseSource.
+func (h HelperPassphrase) Passphrase() (string, error) {
+	args := strings.Fields(string(h))
+	if len(args) == 0 {
+		return "", errors.NotValidf("empty client store helper")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(args[0], append(args[1:], "get")...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.Errorf("%v: %s", err, msg)
+		}
+		return "", errors.Annotatef(err, "running client store helper %q", string(h))
+	}
+	passphrase := strings.TrimRight(string(out), "\r\n")
+	if passphrase == "" {
+		return "", errors.Errorf("client store helper %q returned an empty passphrase", string(h))
+	}
+	return passphrase, nil
+}
+
+// EncryptionConfig records how the secrets in the client store are
+// encrypted. It holds nothing secret itself.
+type EncryptionConfig struct {
+	// Helper, if set, is the command run to get the passphrase.
+	// Otherwise the passphrase is read from the environment.
+	Helper string `yaml:"helper,omitempty"`
+
+	// Salt is the base64 encoded salt the key is derived with.
+	Salt string `yaml:"salt"`
+
+	// Check is a known value encrypted with the key.
+	Check string `yaml:"check"`
+}
+
+// PassphraseSource returns where the passphrase for the config comes from.
+func (cfg EncryptionConfig) PassphraseSource() PassphraseSource {
+	if cfg.Helper != "" {
+		return HelperPassphrase(cfg.Helper)
+	}
+	return EnvPassphrase{}
+}
+
+type clientStoreConfig struct {
+	Encryption *EncryptionConfig `yaml:"encryption,omitempty"`
+}
+
+// ReadEncryptionConfig returns how the client store is encrypted, or
+// nil if it isn't.
+func ReadEncryptionConfig() (*EncryptionConfig, error) {
+	data, err := os.ReadFile(JujuClientStoreConfigPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var config clientStoreConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal client store config")
+	}
+	return config.Encryption, nil
+}
+
+func writeEncryptionConfig(cfg *EncryptionConfig) error {
+	if cfg == nil {
+		err := os.Remove(JujuClientStoreConfigPath())
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	data, err := yaml.Marshal(clientStoreConfig{Encryption: cfg})
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal client store config")
+	}
+	return utils.AtomicWriteFile(JujuClientStoreConfigPath(), data, os.FileMode(0600))
+}
+
+// EnableEncryption encrypts the account passwords and cloud credentials
+// in the client store with a key derived from passphrase. If helper is
+// not empty, later reads get the passphrase by running it; otherwise
+// they read it from $JUJU_CLIENT_STORE_PASSPHRASE.
+func EnableEncryption(passphrase, helper string) error {
+	if passphrase == "" {
+		return errors.NotValidf("empty passphrase")
+	}
+	releaser, err := (&store{lockName: generateStoreLockName()}).acquireLock()
+	if err != nil {
+		return errors.Annotate(err, "cannot acquire lock file to encrypt the client store")
+	}
+	defer releaser.Release()
+
+	existing, err := ReadEncryptionConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if existing != nil {
+		return errors.AlreadyExistsf("client store encryption")
+	}
+	accounts, _, err := readAccountsFile(JujuAccountsPath(), nil)
+	if err != nil {
+		return errors.Annotate(err, "cannot read accounts")
+	}
+	credentials, _, err := readCredentialsData(JujuCredentialsPath(), nil)
+	if err != nil {
+		return errors.Annotate(err, "cannot read credentials")
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return errors.Trace(err)
+	}
+	cfg := &EncryptionConfig{
+		Helper: helper,
+		Salt:   base64.StdEncoding.EncodeToString(salt),
+	}
+	c, err := newSecretCipher(passphrase, salt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cfg.Check, err = c.Encrypt(checkPlaintext); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Record the config before rewriting the secrets: any left in
+	// plain text if this fails part way are encrypted when next read.
+	if err := writeEncryptionConfig(cfg); err != nil {
+		return errors.Trace(err)
+	}
+	if accounts != nil {
+		if err := writeAccountsFile(accounts, c); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if credentials != nil {
+		if err := writeCredentialsData(credentials, c); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// DisableEncryption decrypts the account passwords and cloud credentials
+// in the client store, writing them back in plain text.
+func DisableEncryption(passphrase string) error {
+	releaser, err := (&store{lockName: generateStoreLockName()}).acquireLock()
+	if err != nil {
+		return errors.Annotate(err, "cannot acquire lock file to decrypt the client store")
+	}
+	defer releaser.Release()
+
+	cfg, err := ReadEncryptionConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cfg == nil {
+		return errors.NotFoundf("client store encryption")
+	}
+	c, err := cfg.cipher(passphrase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	accounts, _, err := readAccountsFile(JujuAccountsPath(), c)
+	if err != nil {
+		return errors.Annotate(err, "cannot read accounts")
+	}
+	credentials, _, err := readCredentialsData(JujuCredentialsPath(), c)
+	if err != nil {
+		return errors.Annotate(err, "cannot read credentials")
+	}
+
+	// Remove the config last: while it remains, any secrets still
+	// encrypted if this fails part way can be read.
+	if accounts != nil {
+		if err := writeAccountsFile(accounts, nil); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if credentials != nil {
+		if err := writeCredentialsData(credentials, nil); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(writeEncryptionConfig(nil))
+}
+
+// cipher returns the cipher for the config, checking that passphrase
+// is the one it was created with.
+func (cfg EncryptionConfig) cipher(passphrase string) (SecretCipher, error) {
+	salt, err := base64.StdEncoding.DecodeString(cfg.Salt)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid client store salt")
+	}
+	c, err := newSecretCipher(passphrase, salt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if check, err := c.Decrypt(cfg.Check); err != nil || check != checkPlaintext {
+		return nil, errors.New("incorrect client store passphrase")
+	}
+	return c, nil
+}
+
+// lazyCipher defers getting the passphrase until a secret actually
+// needs to be encrypted or decrypted, so that stores holding no secrets
+// can be used without one.
+type lazyCipher struct {
+	cfg EncryptionConfig
+
+	mu     sync.Mutex
+	cipher SecretCipher
+}
+
+func (l *lazyCipher) get() (SecretCipher, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cipher != nil {
+		return l.cipher, nil
+	}
+	passphrase, err := l.cfg.PassphraseSource().Passphrase()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c, err := l.cfg.cipher(passphrase)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	l.cipher = c
+	return c, nil
+}
+
+// Encrypt implements SecretCipher.
+func (l *lazyCipher) Encrypt(plaintext string) (string, error) {
+	c, err := l.get()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return c.Encrypt(plaintext)
+}
+
+// Decrypt implements SecretCipher.
+func (l *lazyCipher) Decrypt(ciphertext string) (string, error) {
+	c, err := l.get()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return c.Decrypt(ciphertext)
+}
+
+var (
+	storeCiphersMu sync.Mutex
+	storeCiphers   = make(map[EncryptionConfig]*lazyCipher)
+)
+
+// storeCipher returns the cipher for the client store, or nil if the
+// store isn't encrypted. Ciphers are kept for the life of the process,
+// so a passphrase helper is run at most once per command.
+func storeCipher() (SecretCipher, error) {
+	cfg, err := ReadEncryptionConfig()
+	if err != nil || cfg == nil {
+		return nil, errors.Trace(err)
+	}
+	storeCiphersMu.Lock()
+	defer storeCiphersMu.Unlock()
+	c, ok := storeCiphers[*cfg]
+	if !ok {
+		c = &lazyCipher{cfg: *cfg}
+		storeCiphers[*cfg] = c
+	}
+	return c, nil
+}
+
+var (
+	keyCacheMu sync.Mutex
+	keyCache   = make(map[[sha256.Size]byte][]byte)
+)
+
+// deriveKey derives the encryption key from the passphrase. Deriving
+// is deliberately slow, so keys are cached for the life of the process.
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	cacheKey := sha256.Sum256(append(append([]byte{}, salt...), passphrase...))
+	keyCacheMu.Lock()
+	defer keyCacheMu.Unlock()
+	if key, ok := keyCache[cacheKey]; ok {
+		return key, nil
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keyCache[cacheKey] = key
+	return key, nil
+}
+
+type aesCipher struct {
+	aead cipher.AEAD
+}
+
+func newSecretCipher(passphrase string, salt []byte) (SecretCipher, error) {
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &aesCipher{aead: aead}, nil
+}
+
+// Encrypt implements SecretCipher.
+func (c *aesCipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Trace(err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt implements SecretCipher.
+func (c *aesCipher) Decrypt(ciphertext string) (string, error) {
+	if !isEncrypted(ciphertext) {
+		return "", errors.NotValidf("encrypted value")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, encryptedPrefix))
+	if err != nil {
+		return "", errors.Annotate(err, "invalid encrypted value")
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.NotValidf("encrypted value")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot decrypt value")
+	}
+	return string(plaintext), nil
+}
+
+func isEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// secretTransformer applies a cipher to the secret values read from, or
+// written to, the client store files.
+type secretTransformer struct {
+	cipher SecretCipher
+
+	// plaintext records whether any secret was read unencrypted.
+	plaintext bool
+}
+
+func (t *secretTransformer) encrypt(value string) (string, error) {
+	if t.cipher == nil || value == "" || isEncrypted(value) {
+		return value, nil
+	}
+	return t.cipher.Encrypt(value)
+}
+
+func (t *secretTransformer) decrypt(value string) (string, error) {
+	if !isEncrypted(value) {
+		if value != "" {
+			t.plaintext = true
+		}
+		return value, nil
+	}
+	if t.cipher == nil {
+		return "", errors.Errorf("client store holds encrypted values but %s is missing", JujuClientStoreConfigPath())
+	}
+	return t.cipher.Decrypt(value)
+}
+
+// transformCredentials applies fn to every credential attribute value
+// in the YAML credentials data.
+func transformCredentials(data []byte, fn func(string) (string, error)) ([]byte, error) {
+	var collection struct {
+		Credentials map[string]map[string]interface{} `yaml:"credentials"`
+	}
+	if err := yaml.Unmarshal(data, &collection); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal yaml credentials")
+	}
+	for _, cloudCredentials := range collection.Credentials {
+		for name, value := range cloudCredentials {
+			switch name {
+			case "default-region", "default-credential":
+				continue
+			}
+			attrs, ok := value.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			for key, attr := range attrs {
+				s, ok := attr.(string)
+				if !ok || key == "auth-type" {
+					continue
+				}
+				transformed, err := fn(s)
+				if err != nil {
+					return nil, errors.Annotatef(err, "credential %q attribute %v", name, key)
+				}
+				attrs[key] = transformed
+			}
+		}
+	}
+	return yaml.Marshal(collection)
+}
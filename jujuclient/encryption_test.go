@@ -0,0 +1,217 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type EncryptionSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	credentials map[string]cloud.CloudCredential
+}
+
+var _ = gc.Suite(&EncryptionSuite{})
+
+func (s *EncryptionSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	writeTestAccountsFile(c)
+	s.credentials = writeTestCredentialsFile(c)
+}
+
+func readFile(c *gc.C, path string) string {
+	data, err := os.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *EncryptionSuite) assertEncrypted(c *gc.C) {
+	accounts := readFile(c, jujuclient.JujuAccountsPath())
+	c.Check(accounts, gc.Not(jc.Contains), "hunter2")
+	c.Check(accounts, jc.Contains, "password: juju-encrypted:v1:")
+	c.Check(accounts, jc.Contains, "user: admin")
+
+	credentials := readFile(c, jujuclient.JujuCredentialsPath())
+	c.Check(credentials, gc.Not(jc.Contains), "paul-secret")
+	c.Check(credentials, jc.Contains, "secret-key: juju-encrypted:v1:")
+	c.Check(credentials, jc.Contains, "auth-type: access-key")
+	c.Check(credentials, jc.Contains, "default-region: us-west-2")
+}
+
+func (s *EncryptionSuite) assertReadable(c *gc.C) {
+	accounts, err := jujuclient.ReadAccountsFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(accounts, jc.DeepEquals, testControllerAccounts)
+
+	credentials, err := jujuclient.NewFileCredentialStore().AllCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(credentials, jc.DeepEquals, s.credentials)
+}
+
+func (s *EncryptionSuite) TestEnableEncryption(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEncrypted(c)
+
+	cfg, err := jujuclient.ReadEncryptionConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg, gc.NotNil)
+	c.Check(cfg.Helper, gc.Equals, "")
+
+	s.PatchEnvironment(osenv.JujuClientStorePassphraseEnvKey, "sekrit")
+	s.assertReadable(c)
+}
+
+func (s *EncryptionSuite) TestEnableEncryptionTwice(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *EncryptionSuite) TestReadWithoutPassphrase(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuclient.ReadAccountsFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, gc.ErrorMatches, `account password for controller "ctrl": client store is encrypted: set JUJU_CLIENT_STORE_PASSPHRASE to read it`)
+}
+
+func (s *EncryptionSuite) TestReadWithWrongPassphrase(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.PatchEnvironment(osenv.JujuClientStorePassphraseEnvKey, "guess")
+	_, err = jujuclient.NewFileCredentialStore().AllCredentials()
+	c.Assert(err, gc.ErrorMatches, `credential "(paul|peter|fbi)" attribute .*: incorrect client store passphrase`)
+}
+
+func (s *EncryptionSuite) TestAccountsWithoutPasswordsNeedNoPassphrase(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(jujuclient.JujuAccountsPath(), []byte("controllers:\n  kontroll:\n    user: bob@remote\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	details, err := jujuclient.NewFileClientStore().AccountDetails("kontroll")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*details, jc.DeepEquals, kontrollBobRemoteAccountDetails)
+}
+
+func (s *EncryptionSuite) TestPlaintextReencryptedOnRead(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+	// Simulate files left in plain text, for example restored from
+	// a backup taken before the store was encrypted.
+	err = os.WriteFile(jujuclient.JujuAccountsPath(), []byte(testAccountsYAML), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(jujuclient.JujuCredentialsPath(), []byte(testCredentialsYAML), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.PatchEnvironment(osenv.JujuClientStorePassphraseEnvKey, "sekrit")
+	s.assertReadable(c)
+	s.assertEncrypted(c)
+}
+
+func (s *EncryptionSuite) TestWritesEncrypted(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.PatchEnvironment(osenv.JujuClientStorePassphraseEnvKey, "sekrit")
+	store := jujuclient.NewFileClientStore()
+	err = store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "admin", Password: "n3w-pa55"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(readFile(c, jujuclient.JujuAccountsPath()), gc.Not(jc.Contains), "n3w-pa55")
+
+	details, err := store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.Password, gc.Equals, "n3w-pa55")
+}
+
+func (s *EncryptionSuite) TestWritesOIDCTokensEncrypted(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.PatchEnvironment(osenv.JujuClientStorePassphraseEnvKey, "sekrit")
+	store := jujuclient.NewFileClientStore()
+	account := jujuclient.AccountDetails{
+		User:         "alice@example.com",
+		IDToken:      "id-t0ken",
+		RefreshToken: "refresh-t0ken",
+	}
+	err = store.UpdateAccount("ctrl", account)
+	c.Assert(err, jc.ErrorIsNil)
+	accounts := readFile(c, jujuclient.JujuAccountsPath())
+	c.Check(accounts, gc.Not(jc.Contains), "id-t0ken")
+	c.Check(accounts, gc.Not(jc.Contains), "refresh-t0ken")
+	c.Check(accounts, jc.Contains, "id-token: juju-encrypted:v1:")
+
+	details, err := store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.IDToken, gc.Equals, "id-t0ken")
+	c.Check(details.RefreshToken, gc.Equals, "refresh-t0ken")
+}
+
+func (s *EncryptionSuite) TestDisableEncryption(c *gc.C) {
+	err := jujuclient.EnableEncryption("sekrit", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuclient.DisableEncryption("guess")
+	c.Assert(err, gc.ErrorMatches, "incorrect client store passphrase")
+
+	err = jujuclient.DisableEncryption("sekrit")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(readFile(c, jujuclient.JujuAccountsPath()), jc.Contains, "password: hunter2")
+	c.Check(readFile(c, jujuclient.JujuCredentialsPath()), jc.Contains, "secret-key: paul-secret")
+
+	cfg, err := jujuclient.ReadEncryptionConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg, gc.IsNil)
+	s.assertReadable(c)
+}
+
+func (s *EncryptionSuite) TestDisableEncryptionNotEnabled(c *gc.C) {
+	err := jujuclient.DisableEncryption("sekrit")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EncryptionSuite) TestHelperPassphrase(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("helper script is a shell script")
+	}
+	helper := filepath.Join(c.MkDir(), "helper")
+	err := os.WriteFile(helper, []byte("#!/bin/sh\n[ \"$1\" = get ] && echo sekrit\n"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+
+	passphrase, err := jujuclient.HelperPassphrase(helper).Passphrase()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(passphrase, gc.Equals, "sekrit")
+
+	err = jujuclient.EnableEncryption(passphrase, helper)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEncrypted(c)
+	s.assertReadable(c)
+}
+
+func (s *EncryptionSuite) TestHelperPassphraseFails(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("helper script is a shell script")
+	}
+	helper := filepath.Join(c.MkDir(), "helper")
+	err := os.WriteFile(helper, []byte("#!/bin/sh\necho no keyring >&2\nexit 1\n"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuclient.HelperPassphrase(helper).Passphrase()
+	c.Assert(err, gc.ErrorMatches, `running client store helper ".*": exit status 1: no keyring`)
+}
@@ -786,7 +786,7 @@ func (s *store) UpdateCredential(cloudName string, details cloud.CloudCredential
 	}
 	defer releaser.Release()
 
-	credentials, err := ReadCredentialsFile(JujuCredentialsPath())
+	credentials, err := s.readCredentials()
 	if err != nil {
 		return errors.Annotate(err, "cannot get credentials")
 	}
@@ -795,9 +795,33 @@ func (s *store) UpdateCredential(cloudName string, details cloud.CloudCredential
 	return WriteCredentialsFile(credentials)
 }
 
+// readCredentials reads the store's credentials file. Credentials
+// added before the store was encrypted are encrypted as soon as
+// they're seen, so the caller must hold the store lock.
+func (s *store) readCredentials() (*cloud.CredentialCollection, error) {
+	credentials, plaintext, err := readCredentialsFile(JujuCredentialsPath())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if plaintext {
+		if err := WriteCredentialsFile(credentials); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return credentials, nil
+}
+
 // CredentialForCloud implements CredentialGetter.
 func (s *store) CredentialForCloud(cloudName string) (*cloud.CloudCredential, error) {
-	credentialCollection, err := ReadCredentialsFile(JujuCredentialsPath())
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return nil, errors.Annotatef(err,
+			"cannot acquire lock file for reading credentials for %s", cloudName,
+		)
+	}
+	defer releaser.Release()
+
+	credentialCollection, err := s.readCredentials()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -810,7 +834,15 @@ func (s *store) CredentialForCloud(cloudName string) (*cloud.CloudCredential, er
 
 // AllCredentials implements CredentialGetter.
 func (s *store) AllCredentials() (map[string]cloud.CloudCredential, error) {
-	credentialCollection, err := ReadCredentialsFile(JujuCredentialsPath())
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return nil, errors.Annotate(err,
+			"cannot acquire lock file to read all the credentials",
+		)
+	}
+	defer releaser.Release()
+
+	credentialCollection, err := s.readCredentials()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
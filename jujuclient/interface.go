@@ -97,6 +97,16 @@ type AccountDetails struct {
 	// LastKnownAccess is the last known access level for the account.
 	LastKnownAccess string `yaml:"last-known-access,omitempty"`
 
+	// IDToken, if set, is an ID token issued to the user by the
+	// controller's OpenID Connect provider, and is used for the
+	// account login.
+	IDToken string `yaml:"id-token,omitempty"`
+
+	// RefreshToken, if set, is used to obtain a new ID token from
+	// the controller's OpenID Connect provider when the ID token
+	// expires.
+	RefreshToken string `yaml:"refresh-token,omitempty"`
+
 	// Macaroons, if set, are used for the account login.
 	// They are only set when using the MemStore implementation,
 	// and are used by embedded commands. The are not written to disk.
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/caas/kubernetes/provider/proxy"
 	"github.com/juju/juju/jujuclient"
+	sshproxy "github.com/juju/juju/proxy/ssh"
 )
 
 type proxyWrapperSuite struct {
@@ -87,3 +88,30 @@ config:
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(rCfg, gc.DeepEquals, rawConfig)
 }
+
+func (p *proxyWrapperSuite) TestSSHJumpHostRoundTrip(c *gc.C) {
+	config := sshproxy.ProxierConfig{
+		JumpHosts:      []string{"ubuntu@bastion", "admin@10.0.0.1:2222"},
+		IdentityFile:   "/home/ubuntu/.ssh/bastion",
+		KnownHostsFile: "/home/ubuntu/.ssh/known_hosts",
+	}
+	wrapper := &jujuclient.ProxyConfWrapper{sshproxy.NewProxier(config)}
+	data, err := yaml.Marshal(wrapper)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, `
+type: ssh-jump-host
+config:
+    jump-hosts:
+        - ubuntu@bastion
+        - admin@10.0.0.1:2222
+    identity-file: /home/ubuntu/.ssh/bastion
+    known-hosts-file: /home/ubuntu/.ssh/known_hosts
+`[1:])
+
+	var unmarshalled jujuclient.ProxyConfWrapper
+	err = yaml.Unmarshal(data, &unmarshalled)
+	c.Assert(err, jc.ErrorIsNil)
+	sshProxier, ok := unmarshalled.Proxier.(*sshproxy.Proxier)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(sshProxier.Config(), jc.DeepEquals, config)
+}
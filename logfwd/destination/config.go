@@ -0,0 +1,186 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package destination
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/logfwd/otlp"
+	"github.com/juju/juju/logfwd/syslog"
+)
+
+// sinkPrefix is prepended to the destination name to give the name of
+// the log sink, which the controller uses to record the last record
+// sent to the destination.
+const sinkPrefix = "juju-log-forward-destination-"
+
+var validName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Config is the config for a single named log forwarding destination.
+type Config struct {
+	// Name identifies the destination.
+	Name string
+
+	// Filter selects the records forwarded to the destination.
+	Filter Filter
+
+	// Syslog is the config for the syslog host the records are
+	// forwarded to. Exactly one of Syslog and OTLP is set.
+	Syslog *syslog.RawConfig
+
+	// OTLP is the config for the OTLP receiver the records are
+	// forwarded to.
+	OTLP *otlp.RawConfig
+}
+
+// Filter selects the log records forwarded to a destination. The
+// zero value selects every record.
+type Filter struct {
+	// Entities lists the tags of the entities whose records are
+	// forwarded. Tags may include "*" wildcards.
+	Entities []string
+
+	// Modules lists the logging modules whose records are forwarded,
+	// including their submodules.
+	Modules []string
+
+	// Level is the minimum level of the records forwarded.
+	Level loggo.Level
+}
+
+// SinkName returns the name of the log sink for the destination.
+func (cfg Config) SinkName() string {
+	return sinkPrefix + cfg.Name
+}
+
+// Validate ensures that the config is valid.
+func (cfg Config) Validate() error {
+	if !validName.MatchString(cfg.Name) {
+		return errors.NotValidf("destination name %q", cfg.Name)
+	}
+	switch {
+	case cfg.Syslog != nil && cfg.OTLP != nil:
+		return errors.NotValidf("destination %q with both syslog and otlp targets", cfg.Name)
+	case cfg.Syslog != nil:
+		if cfg.Syslog.Host == "" {
+			return errors.NotValidf("destination %q with empty syslog host", cfg.Name)
+		}
+		if err := cfg.Syslog.Validate(); err != nil {
+			return errors.Annotatef(err, "destination %q", cfg.Name)
+		}
+	case cfg.OTLP != nil:
+		if cfg.OTLP.Endpoint == "" {
+			return errors.NotValidf("destination %q with empty otlp endpoint", cfg.Name)
+		}
+		if err := cfg.OTLP.Validate(); err != nil {
+			return errors.Annotatef(err, "destination %q", cfg.Name)
+		}
+	default:
+		return errors.NotValidf("destination %q without a syslog or otlp target", cfg.Name)
+	}
+	return nil
+}
+
+// rawConfig is the YAML serialisation of a destination.
+type rawConfig struct {
+	Syslog   *rawSyslog `yaml:"syslog,omitempty"`
+	OTLP     *rawOTLP   `yaml:"otlp,omitempty"`
+	Entities []string   `yaml:"entities,omitempty"`
+	Modules  []string   `yaml:"modules,omitempty"`
+	Level    string     `yaml:"level,omitempty"`
+}
+
+type rawSyslog struct {
+	Host       string `yaml:"host"`
+	CACert     string `yaml:"ca-cert,omitempty"`
+	ClientCert string `yaml:"client-cert,omitempty"`
+	ClientKey  string `yaml:"client-key,omitempty"`
+}
+
+type rawOTLP struct {
+	Endpoint   string `yaml:"endpoint"`
+	Protocol   string `yaml:"protocol,omitempty"`
+	Insecure   bool   `yaml:"insecure,omitempty"`
+	CACert     string `yaml:"ca-cert,omitempty"`
+	ClientCert string `yaml:"client-cert,omitempty"`
+	ClientKey  string `yaml:"client-key,omitempty"`
+	Headers    string `yaml:"headers,omitempty"`
+}
+
+// Parse parses destinations from their YAML serialisation, which is a
+// map of destination name to destination, for example:
+//
+//	siem:
+//	  syslog:
+//	    host: siem.example.com:6514
+//	    ca-cert: |
+//	      -----BEGIN CERTIFICATE-----
+//	      ...
+//	  entities: [machine-0, machine-1, machine-2]
+//	  level: ERROR
+//	mysql:
+//	  otlp:
+//	    endpoint: otel.example.com:4317
+//	  entities: [unit-mysql-*]
+//
+// The targets of the returned destinations are enabled if enabled is
+// true. The destinations are sorted by name, and each one is validated.
+func Parse(value string, enabled bool) ([]Config, error) {
+	var raw map[string]rawConfig
+	if err := yaml.UnmarshalStrict([]byte(value), &raw); err != nil {
+		return nil, errors.Annotate(err, "parsing log forwarding destinations")
+	}
+
+	var dests []Config
+	for name, rawDest := range raw {
+		dest := Config{
+			Name: name,
+			Filter: Filter{
+				Entities: rawDest.Entities,
+				Modules:  rawDest.Modules,
+			},
+		}
+		if rawDest.Level != "" {
+			level, ok := loggo.ParseLevel(rawDest.Level)
+			if !ok || level == loggo.UNSPECIFIED {
+				return nil, errors.NotValidf("destination %q level %q", name, rawDest.Level)
+			}
+			dest.Filter.Level = level
+		}
+		if s := rawDest.Syslog; s != nil {
+			dest.Syslog = &syslog.RawConfig{
+				Enabled:    enabled,
+				Host:       s.Host,
+				CACert:     s.CACert,
+				ClientCert: s.ClientCert,
+				ClientKey:  s.ClientKey,
+			}
+		}
+		if o := rawDest.OTLP; o != nil {
+			dest.OTLP = &otlp.RawConfig{
+				Enabled:    enabled,
+				Endpoint:   o.Endpoint,
+				Protocol:   o.Protocol,
+				Insecure:   o.Insecure,
+				CACert:     o.CACert,
+				ClientCert: o.ClientCert,
+				ClientKey:  o.ClientKey,
+				Headers:    o.Headers,
+			}
+		}
+		if err := dest.Validate(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		dests = append(dests, dest)
+	}
+	sort.Slice(dests, func(i, j int) bool {
+		return dests[i].Name < dests[j].Name
+	})
+	return dests, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package destination_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/destination"
+	"github.com/juju/juju/logfwd/otlp"
+	"github.com/juju/juju/logfwd/syslog"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func indent(s string) string {
+	return "      " + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n      ")
+}
+
+func (s *ConfigSuite) TestParse(c *gc.C) {
+	value := fmt.Sprintf(`
+siem:
+  syslog:
+    host: siem.example.com:6514
+    ca-cert: |
+%s
+    client-cert: |
+%s
+    client-key: |
+%s
+  entities: [machine-0, machine-1]
+  level: ERROR
+mysql:
+  otlp:
+    endpoint: otel.example.com:4318
+    protocol: http/protobuf
+    insecure: true
+    headers: x-tenant=mysql
+  entities: [unit-mysql-*]
+  modules: [juju.worker.uniter]
+`[1:], indent(coretesting.CACert), indent(coretesting.ServerCert), indent(coretesting.ServerKey))
+
+	dests, err := destination.Parse(value, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dests, gc.HasLen, 2)
+
+	c.Check(dests[0], jc.DeepEquals, destination.Config{
+		Name: "mysql",
+		Filter: destination.Filter{
+			Entities: []string{"unit-mysql-*"},
+			Modules:  []string{"juju.worker.uniter"},
+		},
+		OTLP: &otlp.RawConfig{
+			Enabled:  true,
+			Endpoint: "otel.example.com:4318",
+			Protocol: otlp.ProtocolHTTPProtobuf,
+			Insecure: true,
+			Headers:  "x-tenant=mysql",
+		},
+	})
+	c.Check(dests[0].SinkName(), gc.Equals, "juju-log-forward-destination-mysql")
+
+	c.Check(dests[1].Name, gc.Equals, "siem")
+	c.Check(dests[1].Filter, jc.DeepEquals, destination.Filter{
+		Entities: []string{"machine-0", "machine-1"},
+		Level:    loggo.ERROR,
+	})
+	c.Check(dests[1].Syslog, jc.DeepEquals, &syslog.RawConfig{
+		Enabled:    true,
+		Host:       "siem.example.com:6514",
+		CACert:     coretesting.CACert,
+		ClientCert: coretesting.ServerCert,
+		ClientKey:  coretesting.ServerKey,
+	})
+	c.Check(dests[1].OTLP, gc.IsNil)
+}
+
+func (s *ConfigSuite) TestParseEmpty(c *gc.C) {
+	dests, err := destination.Parse("", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(dests, gc.HasLen, 0)
+}
+
+func (s *ConfigSuite) TestParseDisabled(c *gc.C) {
+	dests, err := destination.Parse(`
+app:
+  otlp:
+    endpoint: localhost:4317
+`, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dests, gc.HasLen, 1)
+	c.Check(dests[0].OTLP.Enabled, jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestParseErrors(c *gc.C) {
+	for i, test := range []struct {
+		about string
+		value string
+		err   string
+	}{{
+		about: "not a map",
+		value: "[a, b]",
+		err:   `(?s)parsing log forwarding destinations: .*`,
+	}, {
+		about: "unknown field",
+		value: "a:\n  otlp:\n    endpoint: localhost:4317\n  levle: ERROR\n",
+		err:   `(?s)parsing log forwarding destinations: .*field levle not found.*`,
+	}, {
+		about: "bad name",
+		value: "Bad_Name:\n  otlp:\n    endpoint: localhost:4317\n",
+		err:   `destination name "Bad_Name" not valid`,
+	}, {
+		about: "no target",
+		value: "a:\n  level: ERROR\n",
+		err:   `destination "a" without a syslog or otlp target not valid`,
+	}, {
+		about: "two targets",
+		value: "a:\n  otlp:\n    endpoint: localhost:4317\n  syslog:\n    host: localhost:514\n",
+		err:   `destination "a" with both syslog and otlp targets not valid`,
+	}, {
+		about: "empty syslog host",
+		value: "a:\n  syslog:\n    host: \"\"\n",
+		err:   `destination "a" with empty syslog host not valid`,
+	}, {
+		about: "empty otlp endpoint",
+		value: "a:\n  otlp:\n    protocol: grpc\n",
+		err:   `destination "a" with empty otlp endpoint not valid`,
+	}, {
+		about: "bad otlp config",
+		value: "a:\n  otlp:\n    endpoint: localhost\n",
+		err:   `destination "a": gRPC Endpoint "localhost" not valid`,
+	}, {
+		about: "bad level",
+		value: "a:\n  otlp:\n    endpoint: localhost:4317\n  level: LOUD\n",
+		err:   `destination "a" level "LOUD" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := destination.Parse(test.value, true)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package destination holds the config for named log forwarding
+// destinations. Each destination forwards the records matching its
+// filter to a single syslog host or OTLP receiver.
+package destination
@@ -0,0 +1,217 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/juju/juju/logfwd"
+)
+
+var logger = loggo.GetLogger("juju.logfwd.otlp")
+
+// exportTimeout is how long a single export request may take.
+const exportTimeout = 30 * time.Second
+
+// Exporter sends OTLP export requests to a receiver.
+type Exporter interface {
+	io.Closer
+
+	// Export sends the request to the receiver.
+	Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error)
+}
+
+// Client is the wrapper around a connection to an OTLP logs receiver.
+type Client struct {
+	// Exporter is the exporter this client wraps.
+	Exporter Exporter
+}
+
+// Open connects to an OTLP logs receiver and wraps that connection
+// in a new client.
+func Open(cfg RawConfig) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var (
+		exporter Exporter
+		err      error
+	)
+	switch cfg.protocol() {
+	case ProtocolGRPC:
+		exporter, err = openGRPC(cfg)
+	case ProtocolHTTPProtobuf:
+		exporter, err = openHTTP(cfg)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening OTLP %s exporter", cfg.protocol())
+	}
+	return &Client{Exporter: exporter}, nil
+}
+
+// Close closes the client's connection.
+func (client Client) Close() error {
+	err := client.Exporter.Close()
+	return errors.Trace(err)
+}
+
+// Send sends the records to the OTLP receiver in a single export
+// request.
+func (client Client) Send(records []logfwd.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	resp, err := client.Exporter.Export(ctx, requestFromRecords(records))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Rejected records won't be accepted if they're sent again, so
+	// there's no point in failing the send.
+	if partial := resp.GetPartialSuccess(); partial.GetRejectedLogRecords() > 0 {
+		logger.Warningf("OTLP receiver rejected %d of %d log records: %s",
+			partial.GetRejectedLogRecords(), len(records), partial.GetErrorMessage())
+	}
+	return nil
+}
+
+type grpcExporter struct {
+	conn    *grpc.ClientConn
+	client  collogspb.LogsServiceClient
+	headers metadata.MD
+}
+
+func openGRPC(cfg RawConfig) (*grpcExporter, error) {
+	tlsCfg, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	creds := insecure.NewCredentials()
+	if tlsCfg != nil {
+		creds = credentials.NewTLS(tlsCfg)
+	}
+	headers, err := cfg.headers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Dialling doesn't block; the connection is made when the
+	// first request is sent.
+	conn, err := grpc.Dial(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &grpcExporter{
+		conn:    conn,
+		client:  collogspb.NewLogsServiceClient(conn),
+		headers: metadata.New(headers),
+	}, nil
+}
+
+// Export implements Exporter.
+func (e *grpcExporter) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	if len(e.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, e.headers)
+	}
+	resp, err := e.client.Export(ctx, req)
+	return resp, errors.Trace(err)
+}
+
+// Close implements Exporter.
+func (e *grpcExporter) Close() error {
+	return errors.Trace(e.conn.Close())
+}
+
+type httpExporter struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+}
+
+func openHTTP(cfg RawConfig) (*httpExporter, error) {
+	url, err := cfg.url()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsCfg, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	headers, err := cfg.headers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+	return &httpExporter{
+		url:     url,
+		client:  &http.Client{Transport: transport},
+		headers: headers,
+	}, nil
+}
+
+// Export implements Exporter.
+func (e *httpExporter) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	for key, value := range e.headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	httpResp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return nil, errors.Errorf("OTLP receiver returned %s", httpStatus(httpResp, respBody))
+	}
+	var resp collogspb.ExportLogsServiceResponse
+	if err := proto.Unmarshal(respBody, &resp); err != nil {
+		return nil, errors.Annotate(err, "decoding OTLP response")
+	}
+	return &resp, nil
+}
+
+// Close implements Exporter.
+func (e *httpExporter) Close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+func httpStatus(resp *http.Response, body []byte) string {
+	if len(body) == 0 || resp.Header.Get("Content-Type") == "application/x-protobuf" {
+		return resp.Status
+	}
+	return fmt.Sprintf("%s: %s", resp.Status, bytes.TrimSpace(body))
+}
@@ -0,0 +1,221 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package otlp_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/otlp"
+	"github.com/juju/juju/logfwd/otlp/otlptest"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ClientSuite struct {
+	testing.IsolationSuite
+
+	receiver *otlptest.Receiver
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	receiver, err := otlptest.NewReceiver()
+	c.Assert(err, jc.ErrorIsNil)
+	s.receiver = receiver
+	s.AddCleanup(func(*gc.C) { receiver.Close() })
+}
+
+func (s *ClientSuite) config(protocol string) otlp.RawConfig {
+	endpoint := s.receiver.GRPCEndpoint()
+	if protocol == otlp.ProtocolHTTPProtobuf {
+		endpoint = s.receiver.HTTPEndpoint()
+	}
+	return otlp.RawConfig{
+		Enabled:  true,
+		Endpoint: endpoint,
+		Protocol: protocol,
+		Insecure: true,
+		Headers:  "x-juju-token=abc",
+	}
+}
+
+func (s *ClientSuite) records() []logfwd.Record {
+	origin := logfwd.OriginForMachineAgent(
+		names.NewMachineTag("99"),
+		"9f484882-2f18-4fd2-967d-db9663db7bea",
+		"deadbeef-2f18-4fd2-967d-db9663db7bea",
+		version.MustParse("3.3.1"),
+	)
+	origin.Hostname = "juju-host"
+	return []logfwd.Record{{
+		ID:        10,
+		Origin:    origin,
+		Timestamp: time.Unix(12345, 0).UTC(),
+		Level:     loggo.ERROR,
+		Location: logfwd.SourceLocation{
+			Module:   "juju.x.y",
+			Filename: "x.go",
+			Line:     42,
+		},
+		Message: "send to 10.0.0.1 failed",
+	}, {
+		ID:        11,
+		Origin:    origin,
+		Timestamp: time.Unix(12346, 0).UTC(),
+		Level:     loggo.DEBUG,
+		Location: logfwd.SourceLocation{
+			Module: "juju.x.z",
+			Line:   -1,
+		},
+		Message: "all good",
+	}}
+}
+
+func (s *ClientSuite) TestSendGRPC(c *gc.C) {
+	s.assertSend(c, otlp.ProtocolGRPC)
+}
+
+func (s *ClientSuite) TestSendHTTP(c *gc.C) {
+	s.assertSend(c, otlp.ProtocolHTTPProtobuf)
+}
+
+func (s *ClientSuite) assertSend(c *gc.C, protocol string) {
+	client, err := otlp.Open(s.config(protocol))
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+
+	err = client.Send(s.records())
+	c.Assert(err, jc.ErrorIsNil)
+
+	export := s.nextExport(c)
+	c.Check(export.Protocol, gc.Equals, protocol)
+	c.Check(export.Headers["x-juju-token"], gc.Equals, "abc")
+
+	resourceLogs := export.Request.GetResourceLogs()
+	c.Assert(resourceLogs, gc.HasLen, 1)
+	c.Check(attributes(resourceLogs[0].GetResource().GetAttributes()), jc.DeepEquals, map[string]interface{}{
+		"service.name":         "jujud-machine-agent",
+		"service.version":      "3.3.1",
+		"host.name":            "juju-host",
+		"juju.controller.uuid": "9f484882-2f18-4fd2-967d-db9663db7bea",
+		"juju.model.uuid":      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+		"juju.origin.type":     "machine",
+		"juju.origin.name":     "99",
+	})
+
+	scopeLogs := resourceLogs[0].GetScopeLogs()
+	c.Assert(scopeLogs, gc.HasLen, 1)
+	c.Check(scopeLogs[0].GetScope().GetName(), gc.Equals, "juju")
+	logRecords := scopeLogs[0].GetLogRecords()
+	c.Assert(logRecords, gc.HasLen, 2)
+
+	c.Check(logRecords[0].GetTimeUnixNano(), gc.Equals, uint64(12345*time.Second))
+	c.Check(logRecords[0].GetSeverityNumber(), gc.Equals, logspb.SeverityNumber_SEVERITY_NUMBER_ERROR)
+	c.Check(logRecords[0].GetSeverityText(), gc.Equals, "ERROR")
+	c.Check(logRecords[0].GetBody().GetStringValue(), gc.Equals, "send to 10.0.0.1 failed")
+	c.Check(attributes(logRecords[0].GetAttributes()), jc.DeepEquals, map[string]interface{}{
+		"juju.record.id": int64(10),
+		"code.namespace": "juju.x.y",
+		"code.filepath":  "x.go",
+		"code.lineno":    int64(42),
+	})
+
+	c.Check(logRecords[1].GetSeverityNumber(), gc.Equals, logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG)
+	c.Check(logRecords[1].GetSeverityText(), gc.Equals, "DEBUG")
+	c.Check(attributes(logRecords[1].GetAttributes()), jc.DeepEquals, map[string]interface{}{
+		"juju.record.id": int64(11),
+		"code.namespace": "juju.x.z",
+	})
+}
+
+func (s *ClientSuite) TestSendGroupsByOrigin(c *gc.C) {
+	client, err := otlp.Open(s.config(otlp.ProtocolGRPC))
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+
+	records := s.records()
+	records[1].Origin = logfwd.OriginForUnitAgent(
+		names.NewUnitTag("mysql/0"),
+		records[0].Origin.ControllerUUID,
+		records[0].Origin.ModelUUID,
+		version.MustParse("3.3.1"),
+	)
+	err = client.Send(records)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resourceLogs := s.nextExport(c).Request.GetResourceLogs()
+	c.Assert(resourceLogs, gc.HasLen, 2)
+	c.Check(attributes(resourceLogs[0].GetResource().GetAttributes())["juju.origin.name"], gc.Equals, "99")
+	c.Check(attributes(resourceLogs[1].GetResource().GetAttributes())["juju.origin.name"], gc.Equals, "mysql/0")
+}
+
+func (s *ClientSuite) TestSendRejected(c *gc.C) {
+	client, err := otlp.Open(s.config(otlp.ProtocolHTTPProtobuf))
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+
+	// Partially rejected exports aren't retried, so they don't fail.
+	s.receiver.SetRejected(1)
+	err = client.Send(s.records())
+	c.Assert(err, jc.ErrorIsNil)
+	s.nextExport(c)
+}
+
+func (s *ClientSuite) TestSendFailure(c *gc.C) {
+	for _, protocol := range []string{otlp.ProtocolGRPC, otlp.ProtocolHTTPProtobuf} {
+		c.Logf("protocol %s", protocol)
+		client, err := otlp.Open(s.config(protocol))
+		c.Assert(err, jc.ErrorIsNil)
+
+		s.receiver.SetFailure(errors.New("receiver unavailable"))
+		err = client.Send(s.records())
+		c.Check(err, gc.ErrorMatches, `.*receiver unavailable.*`)
+		c.Check(client.Close(), jc.ErrorIsNil)
+	}
+}
+
+func (s *ClientSuite) TestOpenInvalidConfig(c *gc.C) {
+	_, err := otlp.Open(otlp.RawConfig{
+		Enabled:  true,
+		Endpoint: "localhost:4317",
+		Protocol: "udp",
+	})
+	c.Check(err, gc.ErrorMatches, `Protocol "udp" not valid`)
+}
+
+func (s *ClientSuite) nextExport(c *gc.C) otlptest.Export {
+	select {
+	case export := <-s.receiver.Exports():
+		return export
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for export")
+	}
+	panic("unreachable")
+}
+
+func attributes(kvs []*commonpb.KeyValue) map[string]interface{} {
+	attrs := make(map[string]interface{})
+	for _, kv := range kvs {
+		switch v := kv.GetValue().GetValue().(type) {
+		case *commonpb.AnyValue_StringValue:
+			attrs[kv.GetKey()] = v.StringValue
+		case *commonpb.AnyValue_IntValue:
+			attrs[kv.GetKey()] = v.IntValue
+		}
+	}
+	return attrs
+}
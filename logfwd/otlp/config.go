@@ -0,0 +1,178 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package otlp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v3/cert"
+)
+
+// The supported OTLP transport protocols. The names match those used
+// by the OTEL_EXPORTER_OTLP_PROTOCOL environment variable.
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// defaultLogsPath is the URL path OTLP/HTTP receivers accept logs on.
+const defaultLogsPath = "/v1/logs"
+
+// RawConfig holds the raw configuration data for a connection to an
+// OTLP logs receiver.
+type RawConfig struct {
+	// Enabled is true if the log forwarding feature is enabled.
+	Enabled bool
+
+	// Endpoint is the address of the OTLP receiver. For gRPC it has
+	// the format:
+	//
+	//   [domain-or-ip-addr]:[port]
+	//
+	// For HTTP it may also be a full URL. If no path is given,
+	// "/v1/logs" is used.
+	Endpoint string
+
+	// Protocol is the OTLP transport protocol, one of "grpc" or
+	// "http/protobuf". If empty, "grpc" is used.
+	Protocol string
+
+	// Insecure means the connection to the receiver is made without
+	// TLS. It is intended for receivers running on the same host.
+	Insecure bool
+
+	// CACert is the TLS CA certificate (x.509, PEM-encoded) to use
+	// for validating the server certificate when connecting. If not
+	// set, the system's trusted CAs are used.
+	CACert string
+
+	// ClientCert is the TLS certificate (x.509, PEM-encoded) to use
+	// when connecting. It is only needed if the receiver requires
+	// client authentication.
+	ClientCert string
+
+	// ClientKey is the TLS private key (x.509, PEM-encoded) to use
+	// when connecting.
+	ClientKey string
+
+	// Headers are sent with every export request, typically to
+	// authenticate with the receiver. The format is:
+	//
+	//   key1=value1,key2=value2
+	Headers string
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg RawConfig) Validate() error {
+	if cfg.Endpoint == "" {
+		if cfg.Enabled {
+			return errors.NotValidf("Endpoint %q", cfg.Endpoint)
+		}
+		return nil
+	}
+	switch cfg.protocol() {
+	case ProtocolGRPC:
+		if _, _, err := net.SplitHostPort(cfg.Endpoint); err != nil {
+			return errors.NotValidf("gRPC Endpoint %q", cfg.Endpoint)
+		}
+	case ProtocolHTTPProtobuf:
+		if _, err := cfg.url(); err != nil {
+			return errors.Trace(err)
+		}
+	default:
+		return errors.NotValidf("Protocol %q", cfg.Protocol)
+	}
+	if _, err := cfg.headers(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := cfg.tlsConfig(); err != nil {
+		return errors.Annotate(err, "validating TLS config")
+	}
+	return nil
+}
+
+func (cfg RawConfig) protocol() string {
+	if cfg.Protocol == "" {
+		return ProtocolGRPC
+	}
+	return cfg.Protocol
+}
+
+// url returns the URL to post OTLP/HTTP export requests to.
+func (cfg RawConfig) url() (string, error) {
+	endpoint := cfg.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		scheme := "https"
+		if cfg.Insecure {
+			scheme = "http"
+		}
+		endpoint = scheme + "://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", errors.NotValidf("HTTP Endpoint %q", cfg.Endpoint)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.NotValidf("HTTP Endpoint scheme %q", u.Scheme)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = defaultLogsPath
+	}
+	return u.String(), nil
+}
+
+// headers parses the configured headers.
+func (cfg RawConfig) headers() (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(cfg.Headers, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, errors.NotValidf("header %q, expected key=value", pair)
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// tlsConfig returns the TLS config to use when connecting, or nil if
+// the connection is insecure.
+func (cfg RawConfig) tlsConfig() (*tls.Config, error) {
+	if cfg.Insecure {
+		if cfg.CACert != "" || cfg.ClientCert != "" || cfg.ClientKey != "" {
+			return nil, errors.NotValidf("TLS certificates with an insecure connection")
+		}
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if cfg.CACert != "" {
+		caCert, err := cert.ParseCert(cfg.CACert)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing CA certificate")
+		}
+		rootCAs := x509.NewCertPool()
+		rootCAs.AddCert(caCert)
+		tlsCfg.RootCAs = rootCAs
+	}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		clientCert, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey))
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing client key pair")
+		}
+		tlsCfg.Certificates = []tls.Certificate{clientCert}
+	}
+	return tlsCfg, nil
+}
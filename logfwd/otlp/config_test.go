@@ -0,0 +1,136 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package otlp_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/otlp"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestRawValidateZeroValue(c *gc.C) {
+	var cfg otlp.RawConfig
+	err := cfg.Validate()
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidate(c *gc.C) {
+	for i, test := range []struct {
+		about string
+		cfg   otlp.RawConfig
+		err   string
+	}{{
+		about: "gRPC with TLS",
+		cfg: otlp.RawConfig{
+			Enabled:    true,
+			Endpoint:   "otel.example.com:4317",
+			CACert:     coretesting.CACert,
+			ClientCert: coretesting.ServerCert,
+			ClientKey:  coretesting.ServerKey,
+			Headers:    "authorization=Bearer xyz, x-tenant = juju",
+		},
+	}, {
+		about: "gRPC with system CAs",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Protocol: otlp.ProtocolGRPC,
+			Endpoint: "otel.example.com:4317",
+		},
+	}, {
+		about: "HTTP host and port",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Protocol: otlp.ProtocolHTTPProtobuf,
+			Endpoint: "otel.example.com:4318",
+			Insecure: true,
+		},
+	}, {
+		about: "HTTP URL",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Protocol: otlp.ProtocolHTTPProtobuf,
+			Endpoint: "https://otel.example.com/otlp/v1/logs",
+		},
+	}, {
+		about: "missing endpoint",
+		cfg: otlp.RawConfig{
+			Enabled: true,
+		},
+		err: `Endpoint "" not valid`,
+	}, {
+		about: "gRPC endpoint without port",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Endpoint: "otel.example.com",
+		},
+		err: `gRPC Endpoint "otel.example.com" not valid`,
+	}, {
+		about: "bad HTTP scheme",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Protocol: otlp.ProtocolHTTPProtobuf,
+			Endpoint: "ftp://otel.example.com",
+		},
+		err: `HTTP Endpoint scheme "ftp" not valid`,
+	}, {
+		about: "unknown protocol",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Protocol: "http/json",
+			Endpoint: "otel.example.com:4318",
+		},
+		err: `Protocol "http/json" not valid`,
+	}, {
+		about: "bad headers",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Endpoint: "otel.example.com:4317",
+			Headers:  "authorization",
+		},
+		err: `header "authorization", expected key=value not valid`,
+	}, {
+		about: "bad CA cert",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Endpoint: "otel.example.com:4317",
+			CACert:   "abc",
+		},
+		err: `validating TLS config: parsing CA certificate: no certificates found`,
+	}, {
+		about: "mismatched client cert and key",
+		cfg: otlp.RawConfig{
+			Enabled:    true,
+			Endpoint:   "otel.example.com:4317",
+			ClientCert: coretesting.ServerCert,
+			ClientKey:  coretesting.CAKey,
+		},
+		err: `validating TLS config: parsing client key pair: .*`,
+	}, {
+		about: "certificates with insecure",
+		cfg: otlp.RawConfig{
+			Enabled:  true,
+			Endpoint: "otel.example.com:4317",
+			Insecure: true,
+			CACert:   coretesting.CACert,
+		},
+		err: `validating TLS config: TLS certificates with an insecure connection not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		err := test.cfg.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
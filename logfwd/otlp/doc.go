@@ -0,0 +1,7 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package otlp holds the tools needed to perform log forwarding
+// from Juju to an OpenTelemetry (OTLP) logs receiver, over either
+// gRPC or HTTP/protobuf.
+package otlp
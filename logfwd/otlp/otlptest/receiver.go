@@ -0,0 +1,187 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package otlptest provides a local OTLP logs receiver for testing
+// log forwarding.
+package otlptest
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// Export is a single export request received by a Receiver.
+type Export struct {
+	// Protocol is the protocol the request was received over, either
+	// "grpc" or "http/protobuf".
+	Protocol string
+
+	// Headers holds the request headers (or gRPC metadata), with
+	// lower-cased keys.
+	Headers map[string]string
+
+	// Request is the export request.
+	Request *collogspb.ExportLogsServiceRequest
+}
+
+// Receiver is an OTLP logs receiver listening on the loopback
+// interface. It accepts export requests over both gRPC and
+// HTTP/protobuf, without TLS, and records them.
+type Receiver struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	grpcServer   *grpc.Server
+	grpcListener net.Listener
+	httpServer   *httptest.Server
+
+	exports chan Export
+
+	mu      sync.Mutex
+	reject  int64
+	failure error
+}
+
+// NewReceiver starts a new receiver. The caller is responsible for
+// closing it.
+func NewReceiver() (*Receiver, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	r := &Receiver{
+		grpcServer:   grpc.NewServer(),
+		grpcListener: listener,
+		exports:      make(chan Export, 100),
+	}
+	collogspb.RegisterLogsServiceServer(r.grpcServer, r)
+	go func() { _ = r.grpcServer.Serve(listener) }()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", r.serveHTTP)
+	r.httpServer = httptest.NewServer(mux)
+	return r, nil
+}
+
+// GRPCEndpoint returns the host:port of the receiver's gRPC listener.
+func (r *Receiver) GRPCEndpoint() string {
+	return r.grpcListener.Addr().String()
+}
+
+// HTTPEndpoint returns the URL of the receiver's HTTP logs endpoint.
+func (r *Receiver) HTTPEndpoint() string {
+	return r.httpServer.URL + "/v1/logs"
+}
+
+// Exports returns a channel on which each received export request is
+// delivered.
+func (r *Receiver) Exports() <-chan Export {
+	return r.exports
+}
+
+// SetRejected has the receiver report the given number of records as
+// rejected in each successful response.
+func (r *Receiver) SetRejected(count int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reject = count
+}
+
+// SetFailure has the receiver fail each export request with err, or
+// succeed again if err is nil.
+func (r *Receiver) SetFailure(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failure = err
+}
+
+// Close stops the receiver.
+func (r *Receiver) Close() {
+	r.grpcServer.Stop()
+	r.httpServer.Close()
+}
+
+// Export implements collogspb.LogsServiceServer.
+func (r *Receiver) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	headers := make(map[string]string)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		headers[key] = strings.Join(values, ",")
+	}
+	return r.record(Export{
+		Protocol: "grpc",
+		Headers:  headers,
+		Request:  req,
+	})
+}
+
+func (r *Receiver) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/x-protobuf" {
+		http.Error(w, "unsupported content type "+ct, http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var exportReq collogspb.ExportLogsServiceRequest
+	if err := proto.Unmarshal(body, &exportReq); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	headers := make(map[string]string)
+	for key := range req.Header {
+		headers[strings.ToLower(key)] = req.Header.Get(key)
+	}
+
+	resp, err := r.record(Export{
+		Protocol: "http/protobuf",
+		Headers:  headers,
+		Request:  &exportReq,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	respBody, err := proto.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(respBody)
+}
+
+func (r *Receiver) record(export Export) (*collogspb.ExportLogsServiceResponse, error) {
+	r.mu.Lock()
+	reject, failure := r.reject, r.failure
+	r.mu.Unlock()
+	if failure != nil {
+		return nil, failure
+	}
+
+	r.exports <- export
+	resp := &collogspb.ExportLogsServiceResponse{}
+	if reject > 0 {
+		resp.PartialSuccess = &collogspb.ExportLogsPartialSuccess{
+			RejectedLogRecords: reject,
+			ErrorMessage:       "rejected by test receiver",
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package otlp
+
+import (
+	"github.com/juju/loggo"
+	"github.com/juju/version/v2"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"github.com/juju/juju/logfwd"
+)
+
+// scopeName is the OTLP instrumentation scope of forwarded records.
+const scopeName = "juju"
+
+// The attribute keys used on forwarded records. Where there's an
+// OpenTelemetry semantic convention for the value, it's used.
+const (
+	attrServiceName    = "service.name"
+	attrServiceVersion = "service.version"
+	attrHostName       = "host.name"
+	attrControllerUUID = "juju.controller.uuid"
+	attrModelUUID      = "juju.model.uuid"
+	attrOriginType     = "juju.origin.type"
+	attrOriginName     = "juju.origin.name"
+
+	attrCodeNamespace = "code.namespace"
+	attrCodeFilepath  = "code.filepath"
+	attrCodeLineno    = "code.lineno"
+	attrRecordID      = "juju.record.id"
+)
+
+// requestFromRecords converts the records into an OTLP export
+// request. Records sharing an origin are grouped under a single
+// resource, in the order their origins were first seen.
+func requestFromRecords(records []logfwd.Record) *collogspb.ExportLogsServiceRequest {
+	var (
+		resourceLogs []*logspb.ResourceLogs
+		byOrigin     = make(map[logfwd.Origin]*logspb.ScopeLogs)
+	)
+	for _, rec := range records {
+		scopeLogs, ok := byOrigin[rec.Origin]
+		if !ok {
+			scopeLogs = &logspb.ScopeLogs{
+				Scope: &commonpb.InstrumentationScope{Name: scopeName},
+			}
+			byOrigin[rec.Origin] = scopeLogs
+			resourceLogs = append(resourceLogs, &logspb.ResourceLogs{
+				Resource:  resourceFromOrigin(rec.Origin),
+				ScopeLogs: []*logspb.ScopeLogs{scopeLogs},
+			})
+		}
+		scopeLogs.LogRecords = append(scopeLogs.LogRecords, logRecordFromRecord(rec))
+	}
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: resourceLogs,
+	}
+}
+
+func resourceFromOrigin(origin logfwd.Origin) *resourcepb.Resource {
+	var attrs []*commonpb.KeyValue
+	addString := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, stringAttr(key, value))
+		}
+	}
+	addString(attrServiceName, origin.Software.Name)
+	if origin.Software.Version != version.Zero {
+		addString(attrServiceVersion, origin.Software.Version.String())
+	}
+	addString(attrHostName, origin.Hostname)
+	addString(attrControllerUUID, origin.ControllerUUID)
+	addString(attrModelUUID, origin.ModelUUID)
+	if origin.Type != logfwd.OriginTypeUnknown {
+		addString(attrOriginType, origin.Type.String())
+	}
+	addString(attrOriginName, origin.Name)
+	return &resourcepb.Resource{Attributes: attrs}
+}
+
+func logRecordFromRecord(rec logfwd.Record) *logspb.LogRecord {
+	severity, text := severityFromLevel(rec.Level)
+	attrs := []*commonpb.KeyValue{
+		intAttr(attrRecordID, rec.ID),
+	}
+	if rec.Location.Module != "" {
+		attrs = append(attrs, stringAttr(attrCodeNamespace, rec.Location.Module))
+	}
+	if rec.Location.Filename != "" {
+		attrs = append(attrs, stringAttr(attrCodeFilepath, rec.Location.Filename))
+		if rec.Location.Line >= 0 {
+			attrs = append(attrs, intAttr(attrCodeLineno, int64(rec.Location.Line)))
+		}
+	}
+	return &logspb.LogRecord{
+		TimeUnixNano:   uint64(rec.Timestamp.UnixNano()),
+		SeverityNumber: severity,
+		SeverityText:   text,
+		Body: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_StringValue{StringValue: rec.Message},
+		},
+		Attributes: attrs,
+	}
+}
+
+// severityFromLevel maps a loggo level onto the OTLP severity number
+// and text for it.
+func severityFromLevel(level loggo.Level) (logspb.SeverityNumber, string) {
+	switch level {
+	case loggo.TRACE:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_TRACE, level.String()
+	case loggo.DEBUG:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG, level.String()
+	case loggo.INFO:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO, level.String()
+	case loggo.WARNING:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN, level.String()
+	case loggo.ERROR:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, level.String()
+	case loggo.CRITICAL:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL, level.String()
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED, ""
+	}
+}
+
+func stringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func intAttr(key string, value int64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value}},
+	}
+}
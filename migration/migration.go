@@ -14,6 +14,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/naturalsort"
+	"github.com/juju/utils/v3"
 	"github.com/juju/version/v2"
 
 	"github.com/juju/juju/core/leadership"
@@ -93,6 +94,35 @@ func ImportModel(importer StateImporter, getClaimer ClaimerFunc, bytes []byte) (
 	return dbModel, dbState, nil
 }
 
+// TrialImportModel deserializes a model description from the bytes
+// and imports it as a throwaway model with a new UUID and name, to
+// check that a real import would succeed. Leadership is not claimed.
+// The UUID of the throwaway model is returned, even if the import
+// fails part way through, so that the caller can remove anything left
+// behind.
+func TrialImportModel(importer StateImporter, bytes []byte) (string, error) {
+	model, err := description.Deserialize(bytes)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	trialUUID := uuid.String()
+	model.UpdateConfig(map[string]interface{}{
+		"uuid": trialUUID,
+		"name": "migration-dry-run-" + trialUUID[:8],
+	})
+
+	_, st, err := importer.Import(model)
+	if err != nil {
+		return trialUUID, errors.Trace(err)
+	}
+	return trialUUID, errors.Trace(st.Close())
+}
+
 // CharmDownloader defines a single method that is used to download a
 // charm from the source controller in a migration.
 type CharmDownloader interface {
@@ -109,6 +109,35 @@ func (s *ImportSuite) TestImportsLeadership(c *gc.C) {
 	claimer.stub.CheckCall(c, 0, "ClaimLeadership", "wordpress", "wordpress/1", time.Minute)
 }
 
+func (s *ImportSuite) TestTrialImportModel(c *gc.C) {
+	s.makeApplicationWithUnits(c, "wordpress", 2)
+	model, err := s.State.Export(map[string]string{})
+	c.Assert(err, jc.ErrorIsNil)
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+
+	controller := state.NewController(s.StatePool)
+	uuid, err := migration.TrialImportModel(controller, bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuid, gc.Not(gc.Equals), s.State.ModelUUID())
+
+	st, err := s.StatePool.Get(uuid)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Release()
+	trialModel, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(trialModel.Name(), gc.Equals, "migration-dry-run-"+uuid[:8])
+	c.Check(trialModel.MigrationMode(), gc.Equals, state.MigrationModeImporting)
+	c.Assert(st.RemoveImportingModelDocs(), jc.ErrorIsNil)
+}
+
+func (s *ImportSuite) TestTrialImportModelBadBytes(c *gc.C) {
+	controller := state.NewController(s.StatePool)
+	uuid, err := migration.TrialImportModel(controller, []byte("not a model"))
+	c.Check(uuid, gc.Equals, "")
+	c.Assert(err, gc.ErrorMatches, "yaml: unmarshal errors:\n.*")
+}
+
 func (s *ImportSuite) makeApplicationWithUnits(c *gc.C, applicationname string, count int) {
 	units := make([]*state.Unit, count)
 	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
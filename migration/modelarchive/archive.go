@@ -0,0 +1,629 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelarchive reads and writes portable model archives. An
+// archive holds everything needed to recreate a model on another
+// controller: the serialized model description, along with the charms,
+// agent binaries and resources the model uses. This allows a model to
+// be moved between controllers which can never reach each other.
+//
+// An archive is a gzipped tar file. Its manifest records a checksum
+// for every other file in the archive and is signed with an SSH key,
+// so that the archive can be checked before it is imported.
+package modelarchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/clock"
+	"github.com/juju/description/v4"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/naturalsort"
+	"github.com/juju/version/v2"
+	"golang.org/x/crypto/ssh"
+
+	coremigration "github.com/juju/juju/core/migration"
+)
+
+var logger = loggo.GetLogger("juju.migration.modelarchive")
+
+const (
+	// FormatVersion is the version of the archive layout written by
+	// this package. Archives with a newer format version are rejected.
+	FormatVersion = 1
+
+	manifestFile  = "manifest.json"
+	signatureFile = "manifest.sig"
+	signerFile    = "signer.pub"
+	modelFile     = "model.yaml"
+	charmsDir     = "charms"
+	toolsDir      = "tools"
+	resourcesDir  = "resources"
+)
+
+// CharmDownloader opens the archive for a charm used by the model.
+type CharmDownloader interface {
+	OpenCharm(string) (io.ReadCloser, error)
+}
+
+// ToolsDownloader opens the agent binaries found at a URI.
+type ToolsDownloader interface {
+	OpenURI(string, url.Values) (io.ReadCloser, error)
+}
+
+// ResourceDownloader opens the content of an application resource.
+type ResourceDownloader interface {
+	OpenResource(string, string) (io.ReadCloser, error)
+}
+
+// WriteConfig holds what is needed to write a model archive.
+type WriteConfig struct {
+	// Model is the exported model, along with the binaries it uses.
+	Model coremigration.SerializedModel
+
+	// SourceControllerUUID identifies the controller the model was
+	// exported from.
+	SourceControllerUUID string
+
+	CharmDownloader    CharmDownloader
+	ToolsDownloader    ToolsDownloader
+	ResourceDownloader ResourceDownloader
+
+	// Signer is used to sign the archive manifest.
+	Signer ssh.Signer
+
+	Clock clock.Clock
+}
+
+// Validate checks that the config is complete.
+func (c WriteConfig) Validate() error {
+	if len(c.Model.Bytes) == 0 {
+		return errors.NotValidf("empty Model")
+	}
+	if c.CharmDownloader == nil {
+		return errors.NotValidf("nil CharmDownloader")
+	}
+	if c.ToolsDownloader == nil {
+		return errors.NotValidf("nil ToolsDownloader")
+	}
+	if c.ResourceDownloader == nil {
+		return errors.NotValidf("nil ResourceDownloader")
+	}
+	if c.Signer == nil {
+		return errors.NotValidf("nil Signer")
+	}
+	if c.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// Write downloads the binaries used by the model, and writes them to w
+// along with the model description as a signed model archive.
+func Write(w io.Writer, config WriteConfig) error {
+	if err := config.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	model, err := description.Deserialize(config.Model.Bytes)
+	if err != nil {
+		return errors.Annotate(err, "reading model description")
+	}
+
+	dir, err := os.MkdirTemp("", "juju-model-archive")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	st := &stager{dir: dir, files: make(map[string]string)}
+	manifest := Manifest{
+		FormatVersion:        FormatVersion,
+		ModelUUID:            model.Tag().Id(),
+		ModelOwner:           model.Owner().Id(),
+		ModelType:            model.Type(),
+		SourceControllerUUID: config.SourceControllerUUID,
+		Created:              config.Clock.Now().UTC(),
+	}
+	if name, ok := model.Config()["name"].(string); ok {
+		manifest.ModelName = name
+	}
+	if agentVersion, ok := model.Config()["agent-version"].(string); ok {
+		manifest.AgentVersion = agentVersion
+	}
+
+	if err := st.add(modelFile, bytes.NewReader(config.Model.Bytes)); err != nil {
+		return errors.Annotate(err, "adding model description")
+	}
+	if manifest.Charms, err = addCharms(st, config); err != nil {
+		return errors.Annotate(err, "adding charms")
+	}
+	if manifest.Tools, err = addTools(st, config); err != nil {
+		return errors.Annotate(err, "adding agent binaries")
+	}
+	if manifest.Resources, err = addResources(st, config); err != nil {
+		return errors.Annotate(err, "adding resources")
+	}
+	manifest.Files = st.files
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	signature, err := config.Signer.Sign(rand.Reader, manifestBytes)
+	if err != nil {
+		return errors.Annotate(err, "signing manifest")
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	header := func(name string, size int64) *tar.Header {
+		return &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     size,
+			Mode:     0600,
+			ModTime:  manifest.Created,
+		}
+	}
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{
+		{manifestFile, manifestBytes},
+		{signatureFile, ssh.Marshal(signature)},
+		{signerFile, ssh.MarshalAuthorizedKey(config.Signer.PublicKey())},
+	} {
+		if err := tw.WriteHeader(header(entry.name, int64(len(entry.data)))); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := tw.Write(entry.data); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, name := range st.paths() {
+		if err := st.copyTo(tw, name, header); err != nil {
+			return errors.Annotatef(err, "writing %q", name)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(gzw.Close())
+}
+
+func addCharms(st *stager, config WriteConfig) ([]Charm, error) {
+	// Charms are recorded in the same order they must be uploaded
+	// in, so that charm revisions are preserved on import.
+	curls := append([]string(nil), config.Model.Charms...)
+	naturalsort.Sort(curls)
+
+	var charms []Charm
+	for i, curl := range curls {
+		logger.Debugf("adding charm %s to archive", curl)
+		name := path.Join(charmsDir, fmt.Sprintf("%d.charm", i))
+		reader, err := config.CharmDownloader.OpenCharm(curl)
+		if err != nil {
+			return nil, errors.Annotatef(err, "opening charm %q", curl)
+		}
+		err = st.add(name, reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, errors.Annotatef(err, "adding charm %q", curl)
+		}
+		charms = append(charms, Charm{URL: curl, Path: name})
+	}
+	return charms, nil
+}
+
+func addTools(st *stager, config WriteConfig) ([]Tools, error) {
+	var versions []version.Binary
+	for v := range config.Model.Tools {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].String() < versions[j].String()
+	})
+
+	var tools []Tools
+	for _, v := range versions {
+		logger.Debugf("adding agent binaries %s to archive", v)
+		name := path.Join(toolsDir, v.String()+".tgz")
+		reader, err := config.ToolsDownloader.OpenURI(config.Model.Tools[v], nil)
+		if err != nil {
+			return nil, errors.Annotatef(err, "opening agent binaries %q", v)
+		}
+		err = st.add(name, reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, errors.Annotatef(err, "adding agent binaries %q", v)
+		}
+		tools = append(tools, Tools{Version: v.String(), Path: name})
+	}
+	return tools, nil
+}
+
+func addResources(st *stager, config WriteConfig) ([]Resource, error) {
+	var out []Resource
+	for _, res := range config.Model.Resources {
+		manifestRes := resourceToManifest(res)
+		if !res.ApplicationRevision.IsPlaceholder() {
+			app, name := manifestRes.Application, manifestRes.Name
+			logger.Debugf("adding resource %s of %s to archive", name, app)
+			manifestRes.Path = path.Join(resourcesDir, app, name)
+			reader, err := config.ResourceDownloader.OpenResource(app, name)
+			if err != nil {
+				return nil, errors.Annotatef(err, "opening resource %q of application %q", name, app)
+			}
+			err = st.add(manifestRes.Path, reader)
+			_ = reader.Close()
+			if err != nil {
+				return nil, errors.Annotatef(err, "adding resource %q of application %q", name, app)
+			}
+		}
+		out = append(out, manifestRes)
+	}
+	return out, nil
+}
+
+// stager holds the files to be written to an archive in a local
+// directory, recording their checksums as they are added.
+type stager struct {
+	dir   string
+	files map[string]string
+}
+
+func (s *stager) add(name string, r io.Reader) error {
+	localPath := filepath.Join(s.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(localPath), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	f, err := os.OpenFile(localPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(f, io.TeeReader(r, hasher)); err != nil {
+		_ = f.Close()
+		return errors.Trace(err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	s.files[name] = hex.EncodeToString(hasher.Sum(nil))
+	return nil
+}
+
+func (s *stager) paths() []string {
+	var names []string
+	for name := range s.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *stager) copyTo(tw *tar.Writer, name string, header func(string, int64) *tar.Header) error {
+	f, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(name)))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := tw.WriteHeader(header(name, info.Size())); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = io.Copy(tw, f)
+	return errors.Trace(err)
+}
+
+// Archive is a model archive which has been unpacked and checked. It
+// can be used to download the binaries used by the model when they
+// are uploaded to the controller the model is imported into.
+type Archive struct {
+	dir      string
+	manifest Manifest
+	signer   ssh.PublicKey
+}
+
+// Open unpacks the model archive read from r into a temporary
+// directory, and checks its signature and the checksums of its
+// contents. If trusted is not nil, the archive must have been signed
+// with that key. The returned archive must be closed when it is no
+// longer needed.
+func Open(r io.Reader, trusted ssh.PublicKey) (_ *Archive, err error) {
+	dir, err := os.MkdirTemp("", "juju-model-archive")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(dir)
+		}
+	}()
+
+	if err := unpack(r, dir); err != nil {
+		return nil, errors.Annotate(err, "unpacking model archive")
+	}
+	readFile := func(name string) ([]byte, error) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			return nil, errors.NotValidf("model archive without %s", name)
+		}
+		return data, errors.Trace(err)
+	}
+	manifestBytes, err := readFile(manifestFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	signatureBytes, err := readFile(signatureFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	signerBytes, err := readFile(signerFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	signer, _, _, _, err := ssh.ParseAuthorizedKey(signerBytes)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading archive signing key")
+	}
+	if trusted != nil && string(trusted.Marshal()) != string(signer.Marshal()) {
+		return nil, errors.Errorf("model archive signed by untrusted key %s", ssh.FingerprintSHA256(signer))
+	}
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(signatureBytes, &signature); err != nil {
+		return nil, errors.Annotate(err, "reading archive signature")
+	}
+	if err := signer.Verify(manifestBytes, &signature); err != nil {
+		return nil, errors.Annotate(err, "verifying archive signature")
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, errors.Annotate(err, "reading archive manifest")
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, errors.NotSupportedf("model archive format version %d", manifest.FormatVersion)
+	}
+	if err := checkFiles(dir, manifest.Files); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := checkReferences(manifest); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Archive{
+		dir:      dir,
+		manifest: manifest,
+		signer:   signer,
+	}, nil
+}
+
+func unpack(r io.Reader, dir string) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = gzr.Close() }()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return errors.NotValidf("archive entry %q of type %q", header.Name, header.Typeflag)
+		}
+		name := path.Clean(header.Name)
+		if name != header.Name || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.NotValidf("archive entry %q", header.Name)
+		}
+		localPath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(localPath), 0700); err != nil {
+			return errors.Trace(err)
+		}
+		f, err := os.OpenFile(localPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// checkFiles ensures that the unpacked archive holds exactly the files
+// listed in the manifest, with the expected content.
+func checkFiles(dir string, files map[string]string) error {
+	if _, ok := files[modelFile]; !ok {
+		return errors.NotValidf("model archive without %s", modelFile)
+	}
+	found := make(map[string]bool)
+	err := filepath.Walk(dir, func(localPath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, localPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		name := filepath.ToSlash(rel)
+		switch name {
+		case manifestFile, signatureFile, signerFile:
+			return nil
+		}
+		expected, ok := files[name]
+		if !ok {
+			return errors.NotValidf("unexpected file %q in model archive", name)
+		}
+		actual, err := fileChecksum(localPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if actual != expected {
+			return errors.NotValidf("checksum of %q in model archive", name)
+		}
+		found[name] = true
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for name := range files {
+		if !found[name] {
+			return errors.NotValidf("model archive without %s", name)
+		}
+	}
+	return nil
+}
+
+// checkReferences ensures that every binary described by the manifest
+// is held in the archive.
+func checkReferences(manifest Manifest) error {
+	var paths []string
+	for _, charm := range manifest.Charms {
+		paths = append(paths, charm.Path)
+	}
+	for _, tools := range manifest.Tools {
+		paths = append(paths, tools.Path)
+	}
+	for _, res := range manifest.Resources {
+		if res.Path != "" {
+			paths = append(paths, res.Path)
+		}
+	}
+	for _, name := range paths {
+		if _, ok := manifest.Files[name]; !ok {
+			return errors.NotValidf("model archive without %s", name)
+		}
+	}
+	return nil
+}
+
+func fileChecksum(localPath string) (string, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() { _ = f.Close() }()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", errors.Trace(err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Manifest returns the archive's manifest.
+func (a *Archive) Manifest() Manifest {
+	return a.manifest
+}
+
+// Signer returns the public key the archive was signed with.
+func (a *Archive) Signer() ssh.PublicKey {
+	return a.signer
+}
+
+// SerializedModel returns the model held in the archive, in the form
+// used when migrating a model. The agent binary URIs it holds can be
+// passed to OpenURI.
+func (a *Archive) SerializedModel() (coremigration.SerializedModel, error) {
+	data, err := os.ReadFile(a.localPath(modelFile))
+	if err != nil {
+		return coremigration.SerializedModel{}, errors.Trace(err)
+	}
+	serialized := coremigration.SerializedModel{
+		Bytes: data,
+		Tools: make(map[version.Binary]string),
+	}
+	for _, charm := range a.manifest.Charms {
+		serialized.Charms = append(serialized.Charms, charm.URL)
+	}
+	for _, tools := range a.manifest.Tools {
+		v, err := version.ParseBinary(tools.Version)
+		if err != nil {
+			return coremigration.SerializedModel{}, errors.Annotate(err, "parsing agent binary version")
+		}
+		serialized.Tools[v] = tools.Path
+	}
+	for _, res := range a.manifest.Resources {
+		out, err := resourceFromManifest(res)
+		if err != nil {
+			return coremigration.SerializedModel{}, errors.Annotatef(err, "resource %q of application %q", res.Name, res.Application)
+		}
+		serialized.Resources = append(serialized.Resources, out)
+	}
+	return serialized, nil
+}
+
+// OpenCharm opens the archive for the specified charm.
+func (a *Archive) OpenCharm(curl string) (io.ReadCloser, error) {
+	for _, charm := range a.manifest.Charms {
+		if charm.URL == curl {
+			return a.open(charm.Path)
+		}
+	}
+	return nil, errors.NotFoundf("charm %q in model archive", curl)
+}
+
+// OpenURI opens the agent binaries found at a URI returned by
+// SerializedModel.
+func (a *Archive) OpenURI(uri string, _ url.Values) (io.ReadCloser, error) {
+	for _, tools := range a.manifest.Tools {
+		if tools.Path == uri {
+			return a.open(tools.Path)
+		}
+	}
+	return nil, errors.NotFoundf("agent binaries %q in model archive", uri)
+}
+
+// OpenResource opens the content of the specified application resource.
+func (a *Archive) OpenResource(application, name string) (io.ReadCloser, error) {
+	for _, res := range a.manifest.Resources {
+		if res.Application == application && res.Name == name && res.Path != "" {
+			return a.open(res.Path)
+		}
+	}
+	return nil, errors.NotFoundf("resource %q of application %q in model archive", name, application)
+}
+
+// Close removes the unpacked archive.
+func (a *Archive) Close() error {
+	return errors.Trace(os.RemoveAll(a.dir))
+}
+
+func (a *Archive) open(name string) (io.ReadCloser, error) {
+	f, err := os.Open(a.localPath(name))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f, nil
+}
+
+func (a *Archive) localPath(name string) string {
+	return filepath.Join(a.dir, filepath.FromSlash(name))
+}
@@ -0,0 +1,386 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelarchive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	charmresource "github.com/juju/charm/v12/resource"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/description/v4"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/core/resources"
+	"github.com/juju/juju/migration/modelarchive"
+	"github.com/juju/juju/testing"
+)
+
+type archiveSuite struct {
+	testing.BaseSuite
+
+	signer ssh.Signer
+	clock  *testclock.Clock
+	model  coremigration.SerializedModel
+	blobs  *fakeDownloader
+}
+
+var _ = gc.Suite(&archiveSuite{})
+
+const modelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+func (s *archiveSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.signer = newSigner(c)
+	s.clock = testclock.NewClock(time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	model := description.NewModel(description.ModelArgs{
+		Type:  "iaas",
+		Owner: names.NewUserTag("bob"),
+		Config: map[string]interface{}{
+			"name":          "prod",
+			"uuid":          modelUUID,
+			"agent-version": "2.9.42",
+		},
+	})
+	model.SetStatus(description.StatusArgs{Value: "available"})
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+
+	tools := version.MustParseBinary("2.9.42-ubuntu-amd64")
+	s.model = coremigration.SerializedModel{
+		Bytes:  bytes,
+		Charms: []string{"ch:foo-10", "ch:foo-2"},
+		Tools: map[version.Binary]string{
+			tools: "/tools/2.9.42-ubuntu-amd64",
+		},
+		Resources: []coremigration.SerializedModelResource{{
+			ApplicationRevision: newResource(c, "foo", "config", 3, "config data"),
+			UnitRevisions: map[string]resources.Resource{
+				"foo/0": newResource(c, "foo", "config", 2, "old config data"),
+			},
+		}, {
+			ApplicationRevision: resources.Resource{
+				Resource: charmresource.Resource{
+					Meta: charmresource.Meta{
+						Name: "placeholder",
+						Type: charmresource.TypeFile,
+						Path: "placeholder.txt",
+					},
+					Origin: charmresource.OriginUpload,
+				},
+				ApplicationID: "foo",
+			},
+		}},
+	}
+	s.blobs = &fakeDownloader{blobs: map[string]string{
+		"charm:ch:foo-2":                   "foo-2 charm",
+		"charm:ch:foo-10":                  "foo-10 charm",
+		"tools:/tools/2.9.42-ubuntu-amd64": "agent binaries",
+		"resource:foo/config":              "config data",
+	}}
+}
+
+func (s *archiveSuite) writeConfig() modelarchive.WriteConfig {
+	return modelarchive.WriteConfig{
+		Model:                s.model,
+		SourceControllerUUID: "controller-uuid",
+		CharmDownloader:      s.blobs,
+		ToolsDownloader:      s.blobs,
+		ResourceDownloader:   s.blobs,
+		Signer:               s.signer,
+		Clock:                s.clock,
+	}
+}
+
+func (s *archiveSuite) write(c *gc.C) []byte {
+	var buf bytes.Buffer
+	err := modelarchive.Write(&buf, s.writeConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *archiveSuite) TestRoundTrip(c *gc.C) {
+	data := s.write(c)
+
+	archive, err := modelarchive.Open(bytes.NewReader(data), s.signer.PublicKey())
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { _ = archive.Close() }()
+
+	manifest := archive.Manifest()
+	c.Check(manifest.FormatVersion, gc.Equals, modelarchive.FormatVersion)
+	c.Check(manifest.ModelUUID, gc.Equals, modelUUID)
+	c.Check(manifest.ModelName, gc.Equals, "prod")
+	c.Check(manifest.ModelOwner, gc.Equals, "bob")
+	c.Check(manifest.ModelType, gc.Equals, "iaas")
+	c.Check(manifest.AgentVersion, gc.Equals, "2.9.42")
+	c.Check(manifest.SourceControllerUUID, gc.Equals, "controller-uuid")
+	c.Check(manifest.Created, gc.Equals, s.clock.Now())
+	c.Check(ssh.FingerprintSHA256(archive.Signer()), gc.Equals, ssh.FingerprintSHA256(s.signer.PublicKey()))
+
+	serialized, err := archive.SerializedModel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(serialized.Bytes, jc.DeepEquals, s.model.Bytes)
+	// Charms are held in the order they must be uploaded.
+	c.Check(serialized.Charms, jc.DeepEquals, []string{"ch:foo-2", "ch:foo-10"})
+	c.Check(serialized.Resources, jc.DeepEquals, []coremigration.SerializedModelResource{{
+		ApplicationRevision: s.model.Resources[0].ApplicationRevision,
+		UnitRevisions:       s.model.Resources[0].UnitRevisions,
+	}, {
+		ApplicationRevision: s.model.Resources[1].ApplicationRevision,
+		UnitRevisions:       map[string]resources.Resource{},
+	}})
+
+	c.Assert(serialized.Tools, gc.HasLen, 1)
+	for v, uri := range serialized.Tools {
+		c.Check(v, gc.Equals, version.MustParseBinary("2.9.42-ubuntu-amd64"))
+		checkContent(c, func() (io.ReadCloser, error) { return archive.OpenURI(uri, nil) }, "agent binaries")
+	}
+	checkContent(c, func() (io.ReadCloser, error) { return archive.OpenCharm("ch:foo-2") }, "foo-2 charm")
+	checkContent(c, func() (io.ReadCloser, error) { return archive.OpenCharm("ch:foo-10") }, "foo-10 charm")
+	checkContent(c, func() (io.ReadCloser, error) { return archive.OpenResource("foo", "config") }, "config data")
+
+	_, err = archive.OpenResource("foo", "placeholder")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	_, err = archive.OpenCharm("ch:bar-1")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	_, err = archive.OpenURI("/etc/passwd", nil)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *archiveSuite) TestOpenWithoutTrustedKey(c *gc.C) {
+	archive, err := modelarchive.Open(bytes.NewReader(s.write(c)), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(archive.Manifest().ModelUUID, gc.Equals, modelUUID)
+	c.Assert(archive.Close(), jc.ErrorIsNil)
+}
+
+func (s *archiveSuite) TestOpenUntrustedKey(c *gc.C) {
+	other := newSigner(c)
+	_, err := modelarchive.Open(bytes.NewReader(s.write(c)), other.PublicKey())
+	c.Assert(err, gc.ErrorMatches, "model archive signed by untrusted key SHA256:.*")
+}
+
+func (s *archiveSuite) TestOpenTamperedFile(c *gc.C) {
+	data := rewrite(c, s.write(c), func(name string, content []byte) []byte {
+		if name == "charms/0.charm" {
+			return []byte("evil charm")
+		}
+		return content
+	})
+	_, err := modelarchive.Open(bytes.NewReader(data), nil)
+	c.Assert(err, gc.ErrorMatches, `checksum of "charms/0.charm" in model archive not valid`)
+}
+
+func (s *archiveSuite) TestOpenTamperedManifest(c *gc.C) {
+	data := rewrite(c, s.write(c), func(name string, content []byte) []byte {
+		if name == "manifest.json" {
+			return bytes.Replace(content, []byte(`"prod"`), []byte(`"test"`), 1)
+		}
+		return content
+	})
+	_, err := modelarchive.Open(bytes.NewReader(data), nil)
+	c.Assert(err, gc.ErrorMatches, "verifying archive signature: .*")
+}
+
+func (s *archiveSuite) TestOpenNewerFormat(c *gc.C) {
+	data := s.resign(c, s.write(c), func(manifest *modelarchive.Manifest) {
+		manifest.FormatVersion = modelarchive.FormatVersion + 1
+	})
+	_, err := modelarchive.Open(bytes.NewReader(data), nil)
+	c.Assert(err, gc.ErrorMatches, "model archive format version 2 not supported")
+}
+
+func (s *archiveSuite) TestOpenMissingReference(c *gc.C) {
+	data := s.resign(c, s.write(c), func(manifest *modelarchive.Manifest) {
+		manifest.Charms[0].Path = "../../etc/passwd"
+	})
+	_, err := modelarchive.Open(bytes.NewReader(data), nil)
+	c.Assert(err, gc.ErrorMatches, "model archive without ../../etc/passwd not valid")
+}
+
+func (s *archiveSuite) TestOpenUnexpectedFile(c *gc.C) {
+	data := appendEntry(c, s.write(c), "extra", []byte("surprise"))
+	_, err := modelarchive.Open(bytes.NewReader(data), nil)
+	c.Assert(err, gc.ErrorMatches, `unexpected file "extra" in model archive not valid`)
+}
+
+func (s *archiveSuite) TestOpenBadEntryName(c *gc.C) {
+	data := appendEntry(c, s.write(c), "../escape", []byte("surprise"))
+	_, err := modelarchive.Open(bytes.NewReader(data), nil)
+	c.Assert(err, gc.ErrorMatches, `unpacking model archive: archive entry "../escape" not valid`)
+}
+
+func (s *archiveSuite) TestWriteDownloadError(c *gc.C) {
+	delete(s.blobs.blobs, "resource:foo/config")
+	var buf bytes.Buffer
+	err := modelarchive.Write(&buf, s.writeConfig())
+	c.Assert(err, gc.ErrorMatches, `adding resources: opening resource "config" of application "foo": resource:foo/config not found`)
+}
+
+func (s *archiveSuite) TestWriteValidates(c *gc.C) {
+	config := s.writeConfig()
+	config.Signer = nil
+	err := modelarchive.Write(io.Discard, config)
+	c.Assert(err, gc.ErrorMatches, "nil Signer not valid")
+}
+
+// resign rewrites the manifest of the archive, signing it again with
+// the suite's key.
+func (s *archiveSuite) resign(c *gc.C, data []byte, modify func(*modelarchive.Manifest)) []byte {
+	var manifestBytes []byte
+	return rewrite(c, data, func(name string, content []byte) []byte {
+		switch name {
+		case "manifest.json":
+			var manifest modelarchive.Manifest
+			c.Assert(json.Unmarshal(content, &manifest), jc.ErrorIsNil)
+			modify(&manifest)
+			var err error
+			manifestBytes, err = json.Marshal(manifest)
+			c.Assert(err, jc.ErrorIsNil)
+			return manifestBytes
+		case "manifest.sig":
+			sig, err := s.signer.Sign(rand.Reader, manifestBytes)
+			c.Assert(err, jc.ErrorIsNil)
+			return ssh.Marshal(sig)
+		}
+		return content
+	})
+}
+
+func rewrite(c *gc.C, data []byte, modify func(name string, content []byte) []byte) []byte {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	tr := tar.NewReader(gzr)
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		content, err := io.ReadAll(tr)
+		c.Assert(err, jc.ErrorIsNil)
+		content = modify(header.Name, content)
+		header.Size = int64(len(content))
+		c.Assert(tw.WriteHeader(header), jc.ErrorIsNil)
+		_, err = tw.Write(content)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func appendEntry(c *gc.C, data []byte, name string, content []byte) []byte {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	tr := tar.NewReader(gzr)
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(tw.WriteHeader(header), jc.ErrorIsNil)
+		_, err = io.Copy(tw, tr)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(len(content)),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = tw.Write(content)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func checkContent(c *gc.C, open func() (io.ReadCloser, error), expected string) {
+	reader, err := open()
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { _ = reader.Close() }()
+	content, err := io.ReadAll(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(content), gc.Equals, expected)
+}
+
+func newSigner(c *gc.C) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	signer, err := ssh.NewSignerFromKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	return signer
+}
+
+func newResource(c *gc.C, app, name string, revision int, content string) resources.Resource {
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader(content))
+	c.Assert(err, jc.ErrorIsNil)
+	return resources.Resource{
+		Resource: charmresource.Resource{
+			Meta: charmresource.Meta{
+				Name:        name,
+				Type:        charmresource.TypeFile,
+				Path:        name + ".txt",
+				Description: "some config",
+			},
+			Origin:      charmresource.OriginUpload,
+			Revision:    revision,
+			Size:        int64(len(content)),
+			Fingerprint: fp,
+		},
+		ApplicationID: app,
+		Username:      "bob",
+		Timestamp:     time.Date(2023, 5, revision, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+type fakeDownloader struct {
+	blobs map[string]string
+}
+
+func (d *fakeDownloader) open(key string) (io.ReadCloser, error) {
+	blob, ok := d.blobs[key]
+	if !ok {
+		return nil, errors.NotFoundf(key)
+	}
+	return io.NopCloser(strings.NewReader(blob)), nil
+}
+
+func (d *fakeDownloader) OpenCharm(curl string) (io.ReadCloser, error) {
+	return d.open("charm:" + curl)
+}
+
+func (d *fakeDownloader) OpenURI(uri string, _ url.Values) (io.ReadCloser, error) {
+	return d.open("tools:" + uri)
+}
+
+func (d *fakeDownloader) OpenResource(application, name string) (io.ReadCloser, error) {
+	return d.open("resource:" + application + "/" + name)
+}
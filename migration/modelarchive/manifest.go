@@ -0,0 +1,186 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelarchive
+
+import (
+	"time"
+
+	charmresource "github.com/juju/charm/v12/resource"
+	"github.com/juju/errors"
+
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/core/resources"
+)
+
+// Manifest describes the contents of a model archive. It is the part
+// of the archive which is signed; every other file in the archive is
+// covered by the checksums it holds.
+type Manifest struct {
+	// FormatVersion is the version of the archive layout.
+	FormatVersion int `json:"format-version"`
+
+	// ModelUUID, ModelName and ModelOwner identify the exported model.
+	ModelUUID  string `json:"model-uuid"`
+	ModelName  string `json:"model-name"`
+	ModelOwner string `json:"model-owner"`
+
+	// ModelType is the type of the exported model, for example "iaas".
+	ModelType string `json:"model-type"`
+
+	// AgentVersion is the agent version of the exported model.
+	AgentVersion string `json:"agent-version"`
+
+	// SourceControllerUUID identifies the controller the model was
+	// exported from.
+	SourceControllerUUID string `json:"source-controller-uuid"`
+
+	// Created records when the archive was written.
+	Created time.Time `json:"created"`
+
+	// Charms, Tools and Resources describe the binaries held in the
+	// archive, and where in the archive they are.
+	Charms    []Charm    `json:"charms,omitempty"`
+	Tools     []Tools    `json:"tools,omitempty"`
+	Resources []Resource `json:"resources,omitempty"`
+
+	// Files maps the path of every file in the archive, other than
+	// the manifest and its signature, to the hex-encoded SHA-256
+	// checksum of its content.
+	Files map[string]string `json:"files"`
+}
+
+// Charm records where a charm used by the model is held in the archive.
+type Charm struct {
+	URL  string `json:"url"`
+	Path string `json:"path"`
+}
+
+// Tools records where the agent binaries for a version used by the
+// model are held in the archive.
+type Tools struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+}
+
+// Resource records the revisions of an application resource used by
+// the model, and where its content is held in the archive. Path is
+// empty for placeholder resources, which have no content.
+type Resource struct {
+	Application         string                      `json:"application"`
+	Name                string                      `json:"name"`
+	Path                string                      `json:"path,omitempty"`
+	ApplicationRevision ResourceRevision            `json:"application-revision"`
+	CharmStoreRevision  ResourceRevision            `json:"charmstore-revision"`
+	UnitRevisions       map[string]ResourceRevision `json:"unit-revisions,omitempty"`
+}
+
+// ResourceRevision describes a single revision of a resource.
+type ResourceRevision struct {
+	Revision       int       `json:"revision"`
+	Type           string    `json:"type"`
+	Path           string    `json:"path"`
+	Description    string    `json:"description"`
+	Origin         string    `json:"origin"`
+	FingerprintHex string    `json:"fingerprint"`
+	Size           int64     `json:"size"`
+	Timestamp      time.Time `json:"timestamp"`
+	Username       string    `json:"username,omitempty"`
+}
+
+func resourceToManifest(res coremigration.SerializedModelResource) Resource {
+	out := Resource{
+		Application:         res.ApplicationRevision.ApplicationID,
+		Name:                res.ApplicationRevision.Name,
+		ApplicationRevision: revisionToManifest(res.ApplicationRevision),
+		CharmStoreRevision:  revisionToManifest(res.CharmStoreRevision),
+	}
+	if len(res.UnitRevisions) > 0 {
+		out.UnitRevisions = make(map[string]ResourceRevision, len(res.UnitRevisions))
+		for unitName, rev := range res.UnitRevisions {
+			out.UnitRevisions[unitName] = revisionToManifest(rev)
+		}
+	}
+	return out
+}
+
+func revisionToManifest(res resources.Resource) ResourceRevision {
+	var fingerprint string
+	if !res.Fingerprint.IsZero() {
+		fingerprint = res.Fingerprint.Hex()
+	}
+	return ResourceRevision{
+		Revision:       res.Revision,
+		Type:           res.Type.String(),
+		Path:           res.Path,
+		Description:    res.Description,
+		Origin:         res.Origin.String(),
+		FingerprintHex: fingerprint,
+		Size:           res.Size,
+		Timestamp:      res.Timestamp,
+		Username:       res.Username,
+	}
+}
+
+func resourceFromManifest(res Resource) (coremigration.SerializedModelResource, error) {
+	appRev, err := revisionFromManifest(res.Application, res.Name, res.ApplicationRevision)
+	if err != nil {
+		return coremigration.SerializedModelResource{}, errors.Annotate(err, "application revision")
+	}
+	csRev, err := revisionFromManifest(res.Application, res.Name, res.CharmStoreRevision)
+	if err != nil {
+		return coremigration.SerializedModelResource{}, errors.Annotate(err, "charmstore revision")
+	}
+	unitRevs := make(map[string]resources.Resource, len(res.UnitRevisions))
+	for unitName, rev := range res.UnitRevisions {
+		unitRev, err := revisionFromManifest(res.Application, res.Name, rev)
+		if err != nil {
+			return coremigration.SerializedModelResource{}, errors.Annotate(err, "unit revision")
+		}
+		unitRevs[unitName] = unitRev
+	}
+	return coremigration.SerializedModelResource{
+		ApplicationRevision: appRev,
+		CharmStoreRevision:  csRev,
+		UnitRevisions:       unitRevs,
+	}, nil
+}
+
+func revisionFromManifest(app, name string, rev ResourceRevision) (resources.Resource, error) {
+	if rev == (ResourceRevision{}) {
+		// The revision wasn't recorded, for example a resource
+		// which never came from the charm store.
+		return resources.Resource{}, nil
+	}
+	resType, err := charmresource.ParseType(rev.Type)
+	if err != nil {
+		return resources.Resource{}, errors.Trace(err)
+	}
+	origin, err := charmresource.ParseOrigin(rev.Origin)
+	if err != nil {
+		return resources.Resource{}, errors.Trace(err)
+	}
+	var fp charmresource.Fingerprint
+	if rev.FingerprintHex != "" {
+		if fp, err = charmresource.ParseFingerprint(rev.FingerprintHex); err != nil {
+			return resources.Resource{}, errors.Annotate(err, "invalid fingerprint")
+		}
+	}
+	return resources.Resource{
+		Resource: charmresource.Resource{
+			Meta: charmresource.Meta{
+				Name:        name,
+				Type:        resType,
+				Path:        rev.Path,
+				Description: rev.Description,
+			},
+			Origin:      origin,
+			Revision:    rev.Revision,
+			Size:        rev.Size,
+			Fingerprint: fp,
+		},
+		ApplicationID: app,
+		Username:      rev.Username,
+		Timestamp:     rev.Timestamp,
+	}, nil
+}
@@ -26,7 +26,31 @@ func SourcePrecheck(
 	modelPresence ModelPresence, controllerPresence ModelPresence,
 	environscloudspecGetter environsCloudSpecGetter,
 ) error {
-	ctx := newPrecheckSource(backend, modelPresence, environscloudspecGetter)
+	return sourcePrecheck(backend, modelPresence, controllerPresence, environscloudspecGetter, nil)
+}
+
+// SourcePrecheckReport runs the same checks as SourcePrecheck but,
+// rather than stopping at the first problem, it returns every blocker
+// found. An error is only returned if the checks could not be run.
+func SourcePrecheckReport(
+	backend PrecheckBackend,
+	modelPresence ModelPresence, controllerPresence ModelPresence,
+	environscloudspecGetter environsCloudSpecGetter,
+) ([]coremigration.Blocker, error) {
+	found := &blockerCollector{}
+	if err := sourcePrecheck(backend, modelPresence, controllerPresence, environscloudspecGetter, found); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return found.blockers, nil
+}
+
+func sourcePrecheck(
+	backend PrecheckBackend,
+	modelPresence ModelPresence, controllerPresence ModelPresence,
+	environscloudspecGetter environsCloudSpecGetter,
+	found *blockerCollector,
+) error {
+	ctx := newPrecheckSource(backend, modelPresence, environscloudspecGetter, found)
 	if err := ctx.checkModel(); err != nil {
 		return errors.Trace(err)
 	}
@@ -47,7 +71,9 @@ func SourcePrecheck(
 	if cleanupNeeded, err := backend.NeedsCleanup(); err != nil {
 		return errors.Annotate(err, "checking cleanups")
 	} else if cleanupNeeded {
-		return errors.New("cleanup needed")
+		if err := found.add("model", errors.New("cleanup needed")); err != nil {
+			return err
+		}
 	}
 
 	// Check the source controller.
@@ -55,7 +81,7 @@ func SourcePrecheck(
 	if err != nil {
 		return errors.Trace(err)
 	}
-	controllerCtx := newPrecheckTarget(controllerBackend, controllerPresence, environscloudspecGetter)
+	controllerCtx := newPrecheckTarget(controllerBackend, controllerPresence, environscloudspecGetter, found.withPrefix("controller "))
 	if err := controllerCtx.checkController(); err != nil {
 		return errors.Annotate(err, "controller")
 	}
@@ -66,6 +92,25 @@ func SourcePrecheck(
 // sure that the preconditions for model migration are met. The
 // backend provided must be for the target controller.
 func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo, presence ModelPresence) error {
+	return targetPrecheck(backend, pool, modelInfo, presence, nil)
+}
+
+// TargetPrecheckReport runs the same checks as TargetPrecheck but,
+// rather than stopping at the first problem, it returns every blocker
+// found. An error is only returned if the checks could not be run.
+func TargetPrecheckReport(backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo, presence ModelPresence) ([]coremigration.Blocker, error) {
+	found := &blockerCollector{}
+	if err := targetPrecheck(backend, pool, modelInfo, presence, found); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return found.blockers, nil
+}
+
+func targetPrecheck(
+	backend PrecheckBackend, pool Pool,
+	modelInfo coremigration.ModelInfo, presence ModelPresence,
+	found *blockerCollector,
+) error {
 	if err := modelInfo.Validate(); err != nil {
 		return errors.Trace(err)
 	}
@@ -80,7 +125,9 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 	if migrating, err := backend.IsMigrationActive(modelInfo.UUID); err != nil {
 		return errors.Annotate(err, "checking for active migration")
 	} else if migrating {
-		return errors.New("model is being migrated out of target controller")
+		if err := found.add("model", errors.New("model is being migrated out of target controller")); err != nil {
+			return err
+		}
 	}
 
 	controllerVersion, err := backend.AgentVersion()
@@ -89,13 +136,17 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 	}
 
 	if controllerVersion.Compare(modelInfo.AgentVersion) < 0 {
-		return errors.Errorf("model has higher version than target controller (%s > %s)",
-			modelInfo.AgentVersion, controllerVersion)
+		if err := found.add("agent version", errors.Errorf("model has higher version than target controller (%s > %s)",
+			modelInfo.AgentVersion, controllerVersion)); err != nil {
+			return err
+		}
 	}
 
 	if !controllerVersionCompatible(modelInfo.ControllerAgentVersion, controllerVersion) {
-		return errors.Errorf("source controller has higher version than target controller (%s > %s)",
-			modelInfo.ControllerAgentVersion, controllerVersion)
+		if err := found.add("agent version", errors.Errorf("source controller has higher version than target controller (%s > %s)",
+			modelInfo.ControllerAgentVersion, controllerVersion)); err != nil {
+			return err
+		}
 	}
 
 	// The MigrateToAllowed check is the same as validating if a model can be
@@ -105,10 +156,12 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 		return errors.Maskf(err, "unknown target controller version %v", controllerVersion)
 	}
 	if !allowed {
-		return errors.Errorf("model must be upgraded to at least version %s before being migrated to a controller with version %s", minVer, controllerVersion)
+		if err := found.add("agent version", errors.Errorf("model must be upgraded to at least version %s before being migrated to a controller with version %s", minVer, controllerVersion)); err != nil {
+			return err
+		}
 	}
 
-	controllerCtx := newPrecheckTarget(backend, presence, nil)
+	controllerCtx := newPrecheckTarget(backend, presence, nil, found.withPrefix("controller "))
 	if err := controllerCtx.checkController(); err != nil {
 		return errors.Trace(err)
 	}
@@ -129,26 +182,69 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 		// from a previous migration attempt. It will be removed
 		// before the next import.
 		if model.UUID() == modelInfo.UUID && model.MigrationMode() != state.MigrationModeImporting {
-			return errors.Errorf("model with same UUID already exists (%s)", modelInfo.UUID)
+			if err := found.add("model", errors.Errorf("model with same UUID already exists (%s)", modelInfo.UUID)); err != nil {
+				return err
+			}
 		}
 		if model.Name() == modelInfo.Name && model.Owner() == modelInfo.Owner {
-			return errors.Errorf("model named %q already exists", model.Name())
+			if err := found.add("model", errors.Errorf("model named %q already exists", model.Name())); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// blockerCollector gathers the problems found by the prechecks. A nil
+// collector causes the first problem found to be returned as an
+// error, which stops the checks.
+type blockerCollector struct {
+	parent   *blockerCollector
+	prefix   string
+	blockers []coremigration.Blocker
+}
+
+// add records a problem with the given entity. It returns nil if the
+// problem was recorded and the checks should continue, or the
+// problem itself if they should stop.
+func (c *blockerCollector) add(entity string, err error) error {
+	if c == nil {
+		return err
+	}
+	if c.parent != nil {
+		return c.parent.add(c.prefix+entity, err)
+	}
+	c.blockers = append(c.blockers, coremigration.Blocker{
+		Entity:  entity,
+		Message: err.Error(),
+	})
+	return nil
+}
+
+// withPrefix returns a collector which records problems with c,
+// prefixing the entity of each.
+func (c *blockerCollector) withPrefix(prefix string) *blockerCollector {
+	if c == nil {
+		return nil
+	}
+	return &blockerCollector{parent: c, prefix: prefix}
+}
+
 type precheckTarget struct {
 	precheckContext
 }
 
-func newPrecheckTarget(backend PrecheckBackend, presence ModelPresence, environscloudspecGetter environsCloudSpecGetter) *precheckTarget {
+func newPrecheckTarget(
+	backend PrecheckBackend, presence ModelPresence,
+	environscloudspecGetter environsCloudSpecGetter, found *blockerCollector,
+) *precheckTarget {
 	return &precheckTarget{
 		precheckContext: precheckContext{
 			backend:                 backend,
 			presence:                presence,
 			environscloudspecGetter: environscloudspecGetter,
+			found:                   found,
 		},
 	}
 }
@@ -157,6 +253,7 @@ type precheckContext struct {
 	backend                 PrecheckBackend
 	presence                ModelPresence
 	environscloudspecGetter environsCloudSpecGetter
+	found                   *blockerCollector
 }
 
 func (ctx *precheckContext) checkController() error {
@@ -165,13 +262,17 @@ func (ctx *precheckContext) checkController() error {
 		return errors.Annotate(err, "retrieving model")
 	}
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		if err := ctx.found.add("model", errors.Errorf("model is %s", model.Life())); err != nil {
+			return err
+		}
 	}
 
 	if upgrading, err := ctx.backend.IsUpgrading(); err != nil {
 		return errors.Annotate(err, "checking for upgrades")
 	} else if upgrading {
-		return errors.New("upgrade in progress")
+		if err := ctx.found.add("model", errors.New("upgrade in progress")); err != nil {
+			return err
+		}
 	}
 
 	return errors.Trace(ctx.checkMachines())
@@ -187,36 +288,42 @@ func (ctx *precheckContext) checkMachines() error {
 	if err != nil {
 		return errors.Annotate(err, "retrieving machines")
 	}
-	modelPresenceContext := common.ModelPresenceContext{Presence: ctx.presence}
 	for _, machine := range machines {
-		if machine.Life() != state.Alive {
-			return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+		if err := ctx.checkMachine(machine, modelVersion); err != nil {
+			if err := ctx.found.add("machine "+machine.Id(), err); err != nil {
+				return errors.Trace(err)
+			}
 		}
+	}
+	return nil
+}
 
-		if statusInfo, err := machine.InstanceStatus(); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
-		} else if statusInfo.Status != status.Running {
-			return newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
-		}
+func (ctx *precheckContext) checkMachine(machine PrecheckMachine, modelVersion version.Number) error {
+	if machine.Life() != state.Alive {
+		return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+	}
 
-		if statusInfo, err := modelPresenceContext.MachineStatus(machine); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
-		} else if statusInfo.Status != status.Started {
-			return newStatusError("machine %s agent not functioning at this time",
-				machine.Id(), statusInfo.Status)
-		}
+	if statusInfo, err := machine.InstanceStatus(); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
+	} else if statusInfo.Status != status.Running {
+		return newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
+	}
 
-		if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
-		} else if rebootAction != state.ShouldDoNothing {
-			return errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
-		}
+	modelPresenceContext := common.ModelPresenceContext{Presence: ctx.presence}
+	if statusInfo, err := modelPresenceContext.MachineStatus(machine); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
+	} else if statusInfo.Status != status.Started {
+		return newStatusError("machine %s agent not functioning at this time",
+			machine.Id(), statusInfo.Status)
+	}
 
-		if err := checkAgentTools(modelVersion, machine, "machine "+machine.Id()); err != nil {
-			return errors.Trace(err)
-		}
+	if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
+	} else if rebootAction != state.ShouldDoNothing {
+		return errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
 	}
-	return nil
+
+	return errors.Trace(checkAgentTools(modelVersion, machine, "machine "+machine.Id()))
 }
 
 func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, error) {
@@ -236,7 +343,11 @@ func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, erro
 	appUnits := make(map[string][]PrecheckUnit, len(apps))
 	for _, app := range apps {
 		if app.Life() != state.Alive {
-			return nil, errors.Errorf("application %s is %s", app.Name(), app.Life())
+			err := errors.Errorf("application %s is %s", app.Name(), app.Life())
+			if err := ctx.found.add("application "+app.Name(), err); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		units, err := app.AllUnits()
 		if err != nil {
@@ -252,35 +363,48 @@ func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, erro
 }
 
 func (ctx *precheckContext) checkUnits(app PrecheckApplication, units []PrecheckUnit, modelVersion version.Number, modelType state.ModelType) error {
+	appEntity := "application " + app.Name()
 	if len(units) < app.MinUnits() {
-		return errors.Errorf("application %s is below its minimum units threshold", app.Name())
+		err := errors.Errorf("application %s is below its minimum units threshold", app.Name())
+		if err := ctx.found.add(appEntity, err); err != nil {
+			return err
+		}
 	}
 
 	appCharmURL, _ := app.CharmURL()
 	if appCharmURL == nil {
-		return errors.Errorf("application charm url is nil")
+		return ctx.found.add(appEntity, errors.Errorf("application charm url is nil"))
 	}
 
 	for _, unit := range units {
-		if unit.Life() != state.Alive {
-			return errors.Errorf("unit %s is %s", unit.Name(), unit.Life())
-		}
-
-		if err := ctx.checkUnitAgentStatus(unit); err != nil {
-			return errors.Trace(err)
-		}
-
-		if modelType == state.ModelTypeIAAS {
-			if err := checkAgentTools(modelVersion, unit, "unit "+unit.Name()); err != nil {
+		if err := ctx.checkUnit(unit, *appCharmURL, modelVersion, modelType); err != nil {
+			if err := ctx.found.add("unit "+unit.Name(), err); err != nil {
 				return errors.Trace(err)
 			}
 		}
+	}
+	return nil
+}
+
+func (ctx *precheckContext) checkUnit(unit PrecheckUnit, appCharmURL string, modelVersion version.Number, modelType state.ModelType) error {
+	if unit.Life() != state.Alive {
+		return errors.Errorf("unit %s is %s", unit.Name(), unit.Life())
+	}
 
-		unitCharmURL := unit.CharmURL()
-		if unitCharmURL == nil || *appCharmURL != *unitCharmURL {
-			return errors.Errorf("unit %s is upgrading", unit.Name())
+	if err := ctx.checkUnitAgentStatus(unit); err != nil {
+		return errors.Trace(err)
+	}
+
+	if modelType == state.ModelTypeIAAS {
+		if err := checkAgentTools(modelVersion, unit, "unit "+unit.Name()); err != nil {
+			return errors.Trace(err)
 		}
 	}
+
+	unitCharmURL := unit.CharmURL()
+	if unitCharmURL == nil || appCharmURL != *unitCharmURL {
+		return errors.Errorf("unit %s is upgrading", unit.Name())
+	}
 	return nil
 }
 
@@ -306,51 +430,60 @@ func (ctx *precheckContext) checkRelations(appUnits map[string][]PrecheckUnit) e
 		return errors.Annotate(err, "retrieving model relations")
 	}
 	for _, rel := range relations {
-		remoteAppName, crossModel, err := rel.RemoteApplication()
-		if err != nil && !errors.IsNotFound(err) {
-			return errors.Annotatef(err, "checking whether relation %s is cross-model", rel)
-		}
-
-		checkRelationUnit := func(ru PrecheckRelationUnit) error {
-			valid, err := ru.Valid()
-			if err != nil {
+		if err := ctx.checkRelation(rel, appUnits); err != nil {
+			if err := ctx.found.add("relation "+rel.String(), err); err != nil {
 				return errors.Trace(err)
 			}
-			if !valid {
-				return nil
-			}
-			inScope, err := ru.InScope()
+		}
+	}
+	return nil
+}
+
+func (ctx *precheckContext) checkRelation(rel PrecheckRelation, appUnits map[string][]PrecheckUnit) error {
+	remoteAppName, crossModel, err := rel.RemoteApplication()
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Annotatef(err, "checking whether relation %s is cross-model", rel)
+	}
+
+	checkRelationUnit := func(ru PrecheckRelationUnit) error {
+		valid, err := ru.Valid()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !valid {
+			return nil
+		}
+		inScope, err := ru.InScope()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !inScope {
+			return errors.Errorf("unit %s hasn't joined relation %q yet", ru.UnitName(), rel)
+		}
+		return nil
+	}
+
+	for _, ep := range rel.Endpoints() {
+		// The endpoint app is either local or cross model.
+		// Handle each one as appropriate.
+		if crossModel && ep.ApplicationName == remoteAppName {
+			remoteUnits, err := rel.AllRemoteUnits(remoteAppName)
 			if err != nil {
 				return errors.Trace(err)
 			}
-			if !inScope {
-				return errors.Errorf("unit %s hasn't joined relation %q yet", ru.UnitName(), rel)
+			for _, ru := range remoteUnits {
+				if err := checkRelationUnit(ru); err != nil {
+					return errors.Trace(err)
+				}
 			}
-			return nil
-		}
-
-		for _, ep := range rel.Endpoints() {
-			// The endpoint app is either local or cross model.
-			// Handle each one as appropriate.
-			if crossModel && ep.ApplicationName == remoteAppName {
-				remoteUnits, err := rel.AllRemoteUnits(remoteAppName)
+		} else {
+			for _, unit := range appUnits[ep.ApplicationName] {
+				ru, err := rel.Unit(unit)
 				if err != nil {
 					return errors.Trace(err)
 				}
-				for _, ru := range remoteUnits {
-					if err := checkRelationUnit(ru); err != nil {
-						return errors.Trace(err)
-					}
-				}
-			} else {
-				for _, unit := range appUnits[ep.ApplicationName] {
-					ru, err := rel.Unit(unit)
-					if err != nil {
-						return errors.Trace(err)
-					}
-					if err := checkRelationUnit(ru); err != nil {
-						return errors.Trace(err)
-					}
+				if err := checkRelationUnit(ru); err != nil {
+					return errors.Trace(err)
 				}
 			}
 		}
@@ -362,12 +495,16 @@ type precheckSource struct {
 	precheckContext
 }
 
-func newPrecheckSource(backend PrecheckBackend, presence ModelPresence, environscloudspecGetter environsCloudSpecGetter) *precheckSource {
+func newPrecheckSource(
+	backend PrecheckBackend, presence ModelPresence,
+	environscloudspecGetter environsCloudSpecGetter, found *blockerCollector,
+) *precheckSource {
 	return &precheckSource{
 		precheckContext: precheckContext{
 			backend:                 backend,
 			presence:                presence,
 			environscloudspecGetter: environscloudspecGetter,
+			found:                   found,
 		},
 	}
 }
@@ -378,10 +515,15 @@ func (ctx *precheckSource) checkModel() error {
 		return errors.Annotate(err, "retrieving model")
 	}
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		if err := ctx.found.add("model", errors.Errorf("model is %s", model.Life())); err != nil {
+			return err
+		}
 	}
 	if model.MigrationMode() == state.MigrationModeImporting {
-		return errors.New("model is being imported as part of another migration")
+		err := errors.New("model is being imported as part of another migration")
+		if err := ctx.found.add("model", err); err != nil {
+			return err
+		}
 	}
 	if credTag, found := model.CloudCredentialTag(); found {
 		creds, err := ctx.backend.CloudCredential(credTag)
@@ -389,7 +531,9 @@ func (ctx *precheckSource) checkModel() error {
 			return errors.Trace(err)
 		}
 		if creds.Revoked {
-			return errors.New("model has revoked credentials")
+			if err := ctx.found.add("model", errors.New("model has revoked credentials")); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -406,7 +550,7 @@ func (ctx *precheckSource) checkModel() error {
 	if blockers == nil {
 		return nil
 	}
-	return errors.NewNotSupported(nil, fmt.Sprintf("cannot migrate to controller due to issues:\n%s", blockers))
+	return ctx.found.add("model", errors.NewNotSupported(nil, fmt.Sprintf("cannot migrate to controller due to issues:\n%s", blockers)))
 }
 
 type agentToolsGetter interface {
@@ -473,6 +473,78 @@ func (s *SourcePrecheckSuite) TestCrossModelUnitsNotYetInScope(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unit remote-mysql/0 hasn't joined relation "foo:db remote-mysql:db" yet`)
 }
 
+func (s *SourcePrecheckSuite) TestReportCollectsAllBlockers(c *gc.C) {
+	backend := newHappyBackend()
+	backend.machines = []migration.PrecheckMachine{
+		&fakeMachine{id: "0", life: state.Dying},
+		&fakeMachine{id: "1", version: version.MustParseBinary("1.3.1-ubuntu-amd64")},
+	}
+	backend.apps = append(backend.apps, &fakeApp{
+		name:     "spanner",
+		charmURL: "ch:spanner-3",
+		units: []migration.PrecheckUnit{
+			&fakeUnit{name: "spanner/0", charmURL: "ch:spanner-3"},
+			&fakeUnit{name: "spanner/1", charmURL: "ch:spanner-2"},
+		},
+	})
+	backend.relations = []migration.PrecheckRelation{&fakeRelation{
+		key: "foo:db remote-mysql:db",
+		endpoints: []state.Endpoint{
+			{ApplicationName: "foo"},
+			{ApplicationName: "remote-mysql"},
+		},
+		relUnits: map[string]*fakeRelationUnit{
+			"foo/0": {unitName: "foo/0", valid: true, inScope: true},
+		},
+		remoteAppName: "remote-mysql",
+		remoteRelUnits: map[string][]*fakeRelationUnit{
+			"remote-mysql": {{unitName: "remote-mysql/0", valid: true, inScope: false}},
+		},
+	}}
+	backend.cleanupNeeded = true
+	backend.controllerBackend = newBackendWithRebootingMachine()
+
+	blockers, err := migration.SourcePrecheckReport(
+		backend, allAlivePresence(), allAlivePresence(),
+		func(names.ModelTag) (environscloudspec.CloudSpec, error) {
+			return environscloudspec.CloudSpec{Type: "foo"}, nil
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, jc.DeepEquals, []coremigration.Blocker{{
+		Entity:  "machine 0",
+		Message: "machine 0 is dying",
+	}, {
+		Entity:  "machine 1",
+		Message: "machine 1 agent binaries don't match model (1.3.1 != 1.2.3)",
+	}, {
+		Entity:  "unit spanner/1",
+		Message: "unit spanner/1 is upgrading",
+	}, {
+		Entity:  "relation foo:db remote-mysql:db",
+		Message: `unit remote-mysql/0 hasn't joined relation "foo:db remote-mysql:db" yet`,
+	}, {
+		Entity:  "model",
+		Message: "cleanup needed",
+	}, {
+		Entity:  "controller machine 0",
+		Message: "machine 0 is scheduled to reboot",
+	}})
+}
+
+func (s *SourcePrecheckSuite) TestReportError(c *gc.C) {
+	backend := newHappyBackend()
+	backend.cleanupErr = errors.New("boom")
+
+	_, err := migration.SourcePrecheckReport(
+		backend, allAlivePresence(), allAlivePresence(),
+		func(names.ModelTag) (environscloudspec.CloudSpec, error) {
+			return environscloudspec.CloudSpec{Type: "foo"}, nil
+		},
+	)
+	c.Assert(err, gc.ErrorMatches, "checking cleanups: boom")
+}
+
 type TargetPrecheckSuite struct {
 	precheckBaseSuite
 	modelInfo coremigration.ModelInfo
@@ -722,6 +794,42 @@ func (s *TargetPrecheckSuite) TestUUIDAlreadyExistsButImporting(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *TargetPrecheckSuite) TestReportCollectsAllBlockers(c *gc.C) {
+	pool := &fakePool{
+		models: []migration.PrecheckModel{
+			&fakeModel{
+				uuid:      "uuid",
+				name:      modelName,
+				modelType: state.ModelTypeIAAS,
+				owner:     modelOwner,
+			},
+		},
+	}
+	backend := newBackendWithDyingMachine()
+	backend.models = pool.uuids()
+	backend.isUpgrading = true
+
+	sourceVersion := backendVersion
+	sourceVersion.Patch++
+	s.modelInfo.AgentVersion = sourceVersion
+
+	blockers, err := migration.TargetPrecheckReport(backend, pool, s.modelInfo, allAlivePresence())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, jc.DeepEquals, []coremigration.Blocker{{
+		Entity:  "agent version",
+		Message: "model has higher version than target controller (1.2.4 > 1.2.3)",
+	}, {
+		Entity:  "controller model",
+		Message: "upgrade in progress",
+	}, {
+		Entity:  "controller machine 0",
+		Message: "machine 0 is dying",
+	}, {
+		Entity:  "model",
+		Message: `model named "model-name" already exists`,
+	}})
+}
+
 type precheckRunner func(migration.PrecheckBackend) error
 
 type precheckBaseSuite struct {
@@ -0,0 +1,9 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package tracing provides the tracer used to record the spans of
+// distributed traces. It adapts the OpenTelemetry SDK to the
+// core/trace interfaces; finished spans are batched by the SDK and
+// handed to an exporter, such as the OTLP exporter in the otlp
+// sub-package.
+package tracing
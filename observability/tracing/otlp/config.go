@@ -0,0 +1,107 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package otlp
+
+import (
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// The supported OTLP transport protocols. The names match those used
+// by the OTEL_EXPORTER_OTLP_PROTOCOL environment variable.
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// defaultTracesPath is the URL path OTLP/HTTP receivers accept traces
+// on.
+const defaultTracesPath = "/v1/traces"
+
+// Config holds the configuration for a connection to an OTLP traces
+// receiver.
+type Config struct {
+	// Endpoint is the address of the OTLP receiver. For gRPC it has
+	// the format:
+	//
+	//   [domain-or-ip-addr]:[port]
+	//
+	// For HTTP it may also be a full URL. If no path is given,
+	// "/v1/traces" is used.
+	Endpoint string
+
+	// Protocol is the OTLP transport protocol, one of "grpc" or
+	// "http/protobuf". If empty, "grpc" is used.
+	Protocol string
+
+	// Insecure means the connection to the receiver is made without
+	// TLS. It is intended for receivers running on the same host.
+	Insecure bool
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errors.NotValidf("empty Endpoint")
+	}
+	switch cfg.protocol() {
+	case ProtocolGRPC:
+		if _, _, err := net.SplitHostPort(cfg.Endpoint); err != nil {
+			return errors.NotValidf("gRPC Endpoint %q", cfg.Endpoint)
+		}
+	case ProtocolHTTPProtobuf:
+		if _, err := cfg.url(); err != nil {
+			return errors.Trace(err)
+		}
+	default:
+		return errors.NotValidf("Protocol %q", cfg.Protocol)
+	}
+	return nil
+}
+
+func (cfg Config) protocol() string {
+	if cfg.Protocol == "" {
+		return ProtocolGRPC
+	}
+	return cfg.Protocol
+}
+
+// url returns the URL to post OTLP/HTTP export requests to.
+func (cfg Config) url() (*url.URL, error) {
+	endpoint := cfg.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		scheme := "https"
+		if cfg.Insecure {
+			scheme = "http"
+		}
+		endpoint = scheme + "://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, errors.NotValidf("HTTP Endpoint %q", cfg.Endpoint)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.NotValidf("HTTP Endpoint scheme %q", u.Scheme)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = defaultTracesPath
+	}
+	return u, nil
+}
+
+// tlsConfig returns the TLS config to use when connecting, or nil if
+// the connection is insecure. The system's trusted CAs are used to
+// validate the receiver's certificate.
+func (cfg Config) tlsConfig() *tls.Config {
+	if cfg.Insecure {
+		return nil
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+}
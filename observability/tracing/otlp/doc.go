@@ -0,0 +1,7 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package otlp exports the spans recorded by a tracing.Tracer to an
+// OpenTelemetry (OTLP) traces receiver, over either gRPC or
+// HTTP/protobuf.
+package otlp
@@ -0,0 +1,80 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package otlp
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/juju/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+// NewExporter returns an exporter which sends spans to the configured
+// OTLP traces receiver.
+//
+// Spans are exported in the background, so failed exports aren't
+// retried; the spans are dropped rather than holding up the spans
+// which follow them.
+func NewExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	// Neither exporter connects until the first spans are sent.
+	switch cfg.protocol() {
+	case ProtocolGRPC:
+		exporter, err = newGRPCExporter(cfg)
+	case ProtocolHTTPProtobuf:
+		exporter, err = newHTTPExporter(cfg)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening OTLP %s exporter", cfg.protocol())
+	}
+	return exporter, nil
+}
+
+func newGRPCExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(cfg.Endpoint),
+		otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{Enabled: false}),
+	}
+	if tlsCfg := cfg.tlsConfig(); tlsCfg != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+	} else {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	return exporter, errors.Trace(err)
+}
+
+func newHTTPExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	u, err := cfg.url()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(u.Path),
+		otlptracehttp.WithRetry(otlptracehttp.RetryConfig{Enabled: false}),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	} else {
+		// The system's trusted CAs are used to validate the
+		// receiver's certificate.
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(&tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	return exporter, errors.Trace(err)
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package otlp_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/observability/tracing/otlp"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type exporterSuite struct {
+	testing.IsolationSuite
+
+	requests chan *coltracepb.ExportTraceServiceRequest
+}
+
+var _ = gc.Suite(&exporterSuite{})
+
+func (s *exporterSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.requests = make(chan *coltracepb.ExportTraceServiceRequest, 10)
+}
+
+func (s *exporterSuite) spans(c *gc.C) []sdktrace.ReadOnlySpan {
+	start := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	return tracetest.SpanStubs{{
+		Name: "Client.FullStatus",
+		SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+			TraceID:    oteltrace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			SpanID:     oteltrace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+			TraceFlags: oteltrace.FlagsSampled,
+		}),
+		Parent: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+			TraceID: oteltrace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			SpanID:  oteltrace.SpanID{8, 7, 6, 5, 4, 3, 2, 1},
+		}),
+		SpanKind:   oteltrace.SpanKindServer,
+		StartTime:  start,
+		EndTime:    start.Add(time.Second),
+		Attributes: []attribute.KeyValue{attribute.String("rpc.method", "FullStatus"), attribute.Int("rpc.version", 7)},
+		Events: []sdktrace.Event{{
+			Time: start.Add(time.Millisecond),
+			Name: "exception",
+		}},
+		Status: sdktrace.Status{Code: codes.Error, Description: "boom"},
+		Resource: resource.NewSchemaless(
+			attribute.String("service.name", "jujud"),
+			attribute.String("service.instance.id", "machine-0"),
+		),
+	}}.Snapshots()
+}
+
+func (s *exporterSuite) checkRequest(c *gc.C) {
+	var req *coltracepb.ExportTraceServiceRequest
+	select {
+	case req = <-s.requests:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for export")
+	}
+	c.Assert(req.ResourceSpans, gc.HasLen, 1)
+	attrs := make(map[string]string)
+	for _, attr := range req.ResourceSpans[0].Resource.Attributes {
+		attrs[attr.Key] = attr.Value.GetStringValue()
+	}
+	c.Check(attrs["service.name"], gc.Equals, "jujud")
+	c.Check(attrs["service.instance.id"], gc.Equals, "machine-0")
+
+	c.Assert(req.ResourceSpans[0].ScopeSpans, gc.HasLen, 1)
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	c.Assert(spans, gc.HasLen, 1)
+	span := spans[0]
+	c.Check(span.Name, gc.Equals, "Client.FullStatus")
+	c.Check(span.Kind, gc.Equals, tracepb.Span_SPAN_KIND_SERVER)
+	c.Check(span.TraceId, jc.DeepEquals, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	c.Check(span.SpanId, jc.DeepEquals, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	c.Check(span.ParentSpanId, jc.DeepEquals, []byte{8, 7, 6, 5, 4, 3, 2, 1})
+	c.Check(span.EndTimeUnixNano-span.StartTimeUnixNano, gc.Equals, uint64(time.Second))
+	c.Assert(span.Attributes, gc.HasLen, 2)
+	c.Check(span.Attributes[0].Value.GetStringValue(), gc.Equals, "FullStatus")
+	c.Check(span.Attributes[1].Value.GetIntValue(), gc.Equals, int64(7))
+	c.Assert(span.Events, gc.HasLen, 1)
+	c.Check(span.Events[0].Name, gc.Equals, "exception")
+	c.Check(span.Status.Code, gc.Equals, tracepb.Status_STATUS_CODE_ERROR)
+	c.Check(span.Status.Message, gc.Equals, "boom")
+}
+
+func (s *exporterSuite) TestValidate(c *gc.C) {
+	for _, t := range []struct {
+		cfg otlp.Config
+		err string
+	}{{
+		cfg: otlp.Config{},
+		err: "empty Endpoint not valid",
+	}, {
+		cfg: otlp.Config{Endpoint: "localhost"},
+		err: `gRPC Endpoint "localhost" not valid`,
+	}, {
+		cfg: otlp.Config{Endpoint: "ftp://localhost", Protocol: otlp.ProtocolHTTPProtobuf},
+		err: `HTTP Endpoint scheme "ftp" not valid`,
+	}, {
+		cfg: otlp.Config{Endpoint: "localhost:4317", Protocol: "thrift"},
+		err: `Protocol "thrift" not valid`,
+	}} {
+		c.Check(t.cfg.Validate(), gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *exporterSuite) TestExportGRPC(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, &traceServer{requests: s.requests})
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	exporter, err := otlp.NewExporter(otlp.Config{
+		Endpoint: listener.Addr().String(),
+		Insecure: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { _ = exporter.Shutdown(context.Background()) }()
+
+	err = exporter.ExportSpans(context.Background(), s.spans(c))
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkRequest(c)
+}
+
+func (s *exporterSuite) TestExportHTTP(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, gc.Equals, "/v1/traces")
+		c.Check(r.Header.Get("Content-Type"), gc.Equals, "application/x-protobuf")
+		body, err := io.ReadAll(r.Body)
+		c.Check(err, jc.ErrorIsNil)
+		var req coltracepb.ExportTraceServiceRequest
+		c.Check(proto.Unmarshal(body, &req), jc.ErrorIsNil)
+		s.requests <- &req
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer server.Close()
+
+	exporter, err := otlp.NewExporter(otlp.Config{
+		Endpoint: server.Listener.Addr().String(),
+		Protocol: otlp.ProtocolHTTPProtobuf,
+		Insecure: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { _ = exporter.Shutdown(context.Background()) }()
+
+	err = exporter.ExportSpans(context.Background(), s.spans(c))
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkRequest(c)
+}
+
+func (s *exporterSuite) TestExportHTTPError(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	exporter, err := otlp.NewExporter(otlp.Config{
+		Endpoint: server.URL,
+		Protocol: otlp.ProtocolHTTPProtobuf,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { _ = exporter.Shutdown(context.Background()) }()
+
+	err = exporter.ExportSpans(context.Background(), s.spans(c))
+	c.Assert(err, gc.ErrorMatches, "traces export: .*")
+}
+
+type traceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	requests chan *coltracepb.ExportTraceServiceRequest
+}
+
+func (s *traceServer) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	s.requests <- req
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
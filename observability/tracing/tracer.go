@@ -0,0 +1,326 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"gopkg.in/tomb.v2"
+
+	"github.com/juju/juju/core/trace"
+	jujuversion "github.com/juju/juju/version"
+)
+
+const (
+	// defaultBatchSize is the maximum number of spans sent in a single
+	// export.
+	defaultBatchSize = 512
+
+	// defaultFlushInterval is the longest a finished span waits before
+	// it's exported.
+	defaultFlushInterval = 5 * time.Second
+
+	// queueSize is the number of finished spans buffered for export.
+	// Spans that end while the queue is full are dropped rather than
+	// slowing down the operation being traced.
+	queueSize = 2048
+
+	// exportTimeout is how long a single export may take.
+	exportTimeout = 30 * time.Second
+
+	// scopeName is the instrumentation scope of the recorded spans.
+	scopeName = "juju"
+)
+
+// Logger is the logging interface used by the tracer.
+type Logger interface {
+	Warningf(string, ...interface{})
+	Debugf(string, ...interface{})
+}
+
+// Config holds the configuration of a Tracer.
+type Config struct {
+	// SampleRatio is the fraction of traces, started by this tracer,
+	// that are recorded. Spans that are part of a trace started by
+	// another process follow that process's sampling decision.
+	SampleRatio float64
+
+	// Exporter sends the recorded spans to the tracing backend. It
+	// is shut down when the tracer stops.
+	Exporter sdktrace.SpanExporter
+
+	// ServiceName identifies the process recording the spans, for
+	// example "jujud" or "juju".
+	ServiceName string
+
+	// ServiceInstanceID identifies the instance of the service, for
+	// example the tag of the agent.
+	ServiceInstanceID string
+
+	// Clock is used for span and event timestamps.
+	Clock clock.Clock
+
+	// Logger is used to report export failures.
+	Logger Logger
+
+	// BatchSize is the maximum number of spans exported together.
+	// If it is zero, 512 is used.
+	BatchSize int
+
+	// FlushInterval is the longest a finished span waits to be
+	// exported. If it is zero, 5 seconds is used.
+	FlushInterval time.Duration
+}
+
+// Validate ensures that the config is valid.
+func (c Config) Validate() error {
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.NotValidf("SampleRatio %v", c.SampleRatio)
+	}
+	if c.Exporter == nil {
+		return errors.NotValidf("nil Exporter")
+	}
+	if c.ServiceName == "" {
+		return errors.NotValidf("empty ServiceName")
+	}
+	if c.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if c.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if c.BatchSize < 0 {
+		return errors.NotValidf("negative BatchSize")
+	}
+	if c.FlushInterval < 0 {
+		return errors.NotValidf("negative FlushInterval")
+	}
+	return nil
+}
+
+// Tracer is a worker which records spans with the OpenTelemetry SDK,
+// which exports them in batches. Stopping the worker exports any
+// spans which are still waiting.
+type Tracer struct {
+	tomb     tomb.Tomb
+	config   Config
+	provider *sdktrace.TracerProvider
+	tracer   oteltrace.Tracer
+}
+
+var _ trace.Tracer = (*Tracer)(nil)
+
+// NewTracer returns a new tracer, which exports spans until it's
+// killed.
+func NewTracer(config Config) (*Tracer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.FlushInterval == 0 {
+		config.FlushInterval = defaultFlushInterval
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(config.ServiceName),
+		semconv.ServiceVersion(jujuversion.Current.String()),
+	}
+	if config.ServiceInstanceID != "" {
+		attrs = append(attrs, semconv.ServiceInstanceID(config.ServiceInstanceID))
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithBatcher(
+			loggingExporter{SpanExporter: config.Exporter, logger: config.Logger},
+			sdktrace.WithMaxQueueSize(queueSize),
+			sdktrace.WithMaxExportBatchSize(config.BatchSize),
+			sdktrace.WithBatchTimeout(config.FlushInterval),
+			sdktrace.WithExportTimeout(exportTimeout),
+		),
+	)
+	t := &Tracer{
+		config:   config,
+		provider: provider,
+		tracer: provider.Tracer(scopeName,
+			oteltrace.WithInstrumentationVersion(jujuversion.Current.String()),
+		),
+	}
+	t.tomb.Go(t.loop)
+	return t, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (t *Tracer) Kill() {
+	t.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (t *Tracer) Wait() error {
+	return t.tomb.Wait()
+}
+
+func (t *Tracer) loop() error {
+	<-t.tomb.Dying()
+
+	// Export whatever has already been recorded, so that the spans
+	// of short lived processes aren't lost.
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		t.config.Logger.Warningf("stopping tracer: %v", err)
+	}
+	return tomb.ErrDying
+}
+
+// Enabled implements trace.Tracer.
+func (t *Tracer) Enabled() bool {
+	return true
+}
+
+// Start implements trace.Tracer.
+func (t *Tracer) Start(ctx context.Context, name string, options ...trace.Option) (context.Context, trace.Span) {
+	opts := trace.NewOptions(options...)
+
+	start := opts.StartTime
+	if start.IsZero() {
+		start = t.config.Clock.Now()
+	}
+	ctx, otelSpan := t.tracer.Start(parentContext(ctx), name,
+		oteltrace.WithSpanKind(spanKind(opts.Kind)),
+		oteltrace.WithAttributes(attributes(opts.Attributes)...),
+		oteltrace.WithTimestamp(start),
+	)
+	s := &span{span: otelSpan, clock: t.config.Clock}
+	return trace.WithSpan(ctx, s), s
+}
+
+// parentContext returns the context with the span's parent set as the
+// SDK expects it. The parent is taken from the core/trace context, so
+// spans started by another tracer, or received from a remote process,
+// are parents too.
+func parentContext(ctx context.Context) context.Context {
+	local := trace.SpanFromContext(ctx).Scope()
+	parent := local
+	if !parent.IsValid() {
+		parent = trace.RemoteScopeFromContext(ctx)
+	}
+	if !parent.IsValid() {
+		return oteltrace.ContextWithSpanContext(ctx, oteltrace.SpanContext{})
+	}
+	var flags oteltrace.TraceFlags
+	if parent.Sampled {
+		flags = oteltrace.FlagsSampled
+	}
+	return oteltrace.ContextWithSpanContext(ctx, oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID(parent.TraceID),
+		SpanID:     oteltrace.SpanID(parent.SpanID),
+		TraceFlags: flags,
+		Remote:     !local.IsValid(),
+	}))
+}
+
+func spanKind(kind trace.Kind) oteltrace.SpanKind {
+	switch kind {
+	case trace.KindServer:
+		return oteltrace.SpanKindServer
+	case trace.KindClient:
+		return oteltrace.SpanKindClient
+	}
+	return oteltrace.SpanKindInternal
+}
+
+func attributes(attrs []trace.Attribute) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			result[i] = attribute.String(attr.Key, v)
+		case int64:
+			result[i] = attribute.Int64(attr.Key, v)
+		case float64:
+			result[i] = attribute.Float64(attr.Key, v)
+		case bool:
+			result[i] = attribute.Bool(attr.Key, v)
+		default:
+			result[i] = attribute.String(attr.Key, fmt.Sprint(v))
+		}
+	}
+	return result
+}
+
+// span adapts an OpenTelemetry span to trace.Span.
+type span struct {
+	span  oteltrace.Span
+	clock clock.Clock
+}
+
+// Scope implements trace.Span.
+func (s *span) Scope() trace.Scope {
+	sc := s.span.SpanContext()
+	return trace.Scope{
+		TraceID: trace.TraceID(sc.TraceID()),
+		SpanID:  trace.SpanID(sc.SpanID()),
+		Sampled: sc.IsSampled(),
+	}
+}
+
+// AddEvent implements trace.Span.
+func (s *span) AddEvent(message string, attrs ...trace.Attribute) {
+	s.span.AddEvent(message,
+		oteltrace.WithAttributes(attributes(attrs)...),
+		oteltrace.WithTimestamp(s.clock.Now()),
+	)
+}
+
+// RecordError implements trace.Span.
+func (s *span) RecordError(err error, attrs ...trace.Attribute) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err,
+		oteltrace.WithAttributes(attributes(attrs)...),
+		oteltrace.WithTimestamp(s.clock.Now()),
+	)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End implements trace.Span.
+func (s *span) End(attrs ...trace.Attribute) {
+	if !s.span.IsRecording() {
+		return
+	}
+	s.span.SetAttributes(attributes(attrs)...)
+	s.span.End(oteltrace.WithTimestamp(s.clock.Now()))
+}
+
+// loggingExporter logs the outcome of each export. Failures are
+// logged rather than returned, as the SDK would otherwise report them
+// to the process-wide OpenTelemetry error handler.
+type loggingExporter struct {
+	sdktrace.SpanExporter
+	logger Logger
+}
+
+// ExportSpans implements sdktrace.SpanExporter.
+func (e loggingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := e.SpanExporter.ExportSpans(ctx, spans); err != nil {
+		e.logger.Warningf("exporting %d spans: %v", len(spans), err)
+		return nil
+	}
+	e.logger.Debugf("exported %d spans", len(spans))
+	return nil
+}
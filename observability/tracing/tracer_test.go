@@ -0,0 +1,240 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v3/workertest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/trace"
+	"github.com/juju/juju/observability/tracing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type tracerSuite struct {
+	testing.IsolationSuite
+
+	clock    *testclock.Clock
+	exporter *fakeExporter
+}
+
+var _ = gc.Suite(&tracerSuite{})
+
+func (s *tracerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC))
+	s.exporter = &fakeExporter{exports: make(chan []sdktrace.ReadOnlySpan, 10)}
+}
+
+func (s *tracerSuite) newTracer(c *gc.C, ratio float64, flushInterval time.Duration) *tracing.Tracer {
+	tracer, err := tracing.NewTracer(tracing.Config{
+		SampleRatio:       ratio,
+		Exporter:          s.exporter,
+		ServiceName:       "jujud",
+		ServiceInstanceID: "machine-0",
+		Clock:             s.clock,
+		Logger:            loggo.GetLogger("tracing_test"),
+		BatchSize:         2,
+		FlushInterval:     flushInterval,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, tracer) })
+	return tracer
+}
+
+func (s *tracerSuite) nextExport(c *gc.C) []sdktrace.ReadOnlySpan {
+	select {
+	case spans := <-s.exporter.exports:
+		return spans
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for export")
+	}
+	return nil
+}
+
+func (s *tracerSuite) TestValidate(c *gc.C) {
+	_, err := tracing.NewTracer(tracing.Config{
+		SampleRatio: 2,
+		Exporter:    s.exporter,
+		ServiceName: "jujud",
+		Clock:       s.clock,
+		Logger:      loggo.GetLogger("tracing_test"),
+	})
+	c.Assert(err, gc.ErrorMatches, "SampleRatio 2 not valid")
+	_, err = tracing.NewTracer(tracing.Config{
+		SampleRatio: 1,
+		ServiceName: "jujud",
+		Clock:       s.clock,
+		Logger:      loggo.GetLogger("tracing_test"),
+	})
+	c.Assert(err, gc.ErrorMatches, "nil Exporter not valid")
+	_, err = tracing.NewTracer(tracing.Config{
+		SampleRatio: 1,
+		Exporter:    s.exporter,
+		Clock:       s.clock,
+		Logger:      loggo.GetLogger("tracing_test"),
+	})
+	c.Assert(err, gc.ErrorMatches, "empty ServiceName not valid")
+}
+
+func (s *tracerSuite) TestSpansExportedInBatches(c *gc.C) {
+	tracer := s.newTracer(c, 1, time.Hour)
+	ctx := trace.WithTracer(context.Background(), tracer)
+
+	ctx, parent := trace.Start(ctx, "parent", trace.WithKind(trace.KindServer))
+	_, child := trace.Start(ctx, "child", trace.WithAttributes(trace.StringAttr("key", "value")))
+	child.AddEvent("happened")
+	child.RecordError(errors.New("boom"))
+	child.End(trace.IntAttr("count", 2))
+	parent.End()
+
+	spans := s.nextExport(c)
+	c.Assert(spans, gc.HasLen, 2)
+	childData, parentData := spans[0], spans[1]
+	c.Check(childData.Name(), gc.Equals, "child")
+	c.Check(childData.SpanContext().TraceID(), gc.Equals, parentData.SpanContext().TraceID())
+	c.Check(childData.Parent().SpanID(), gc.Equals, parentData.SpanContext().SpanID())
+	c.Check(childData.SpanContext().SpanID(), gc.Not(gc.Equals), parentData.SpanContext().SpanID())
+	c.Check(childData.Attributes(), jc.DeepEquals, []attribute.KeyValue{
+		attribute.String("key", "value"),
+		attribute.Int64("count", 2),
+	})
+	c.Check(childData.Status(), gc.Equals, sdktrace.Status{Code: codes.Error, Description: "boom"})
+	c.Assert(childData.Events(), gc.HasLen, 2)
+	c.Check(childData.Events()[0].Name, gc.Equals, "happened")
+	c.Check(childData.Events()[1].Name, gc.Equals, "exception")
+	c.Check(parentData.SpanKind(), gc.Equals, oteltrace.SpanKindServer)
+	c.Check(parentData.Parent().IsValid(), jc.IsFalse)
+	c.Check(parentData.StartTime(), gc.Equals, s.clock.Now())
+
+	attrs := make(map[attribute.Key]string)
+	for _, attr := range parentData.Resource().Attributes() {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	c.Check(attrs["service.name"], gc.Equals, "jujud")
+	c.Check(attrs["service.instance.id"], gc.Equals, "machine-0")
+}
+
+func (s *tracerSuite) TestSpansFlushedOnInterval(c *gc.C) {
+	tracer := s.newTracer(c, 1, 10*time.Millisecond)
+	_, span := tracer.Start(context.Background(), "lonely")
+	span.End()
+	span.End()
+
+	spans := s.nextExport(c)
+	c.Assert(spans, gc.HasLen, 1)
+	c.Check(spans[0].Name(), gc.Equals, "lonely")
+}
+
+func (s *tracerSuite) TestSpansFlushedOnStop(c *gc.C) {
+	tracer := s.newTracer(c, 1, time.Hour)
+	_, span := tracer.Start(context.Background(), "last")
+	span.End()
+
+	workertest.CleanKill(c, tracer)
+	spans := s.nextExport(c)
+	c.Assert(spans, gc.HasLen, 1)
+	c.Check(s.exporter.isShutdown(), jc.IsTrue)
+}
+
+func (s *tracerSuite) TestStartTime(c *gc.C) {
+	tracer := s.newTracer(c, 1, time.Hour)
+	start := s.clock.Now().Add(-time.Second)
+	_, span := tracer.Start(context.Background(), "earlier", trace.WithStartTime(start))
+	span.End()
+	workertest.CleanKill(c, tracer)
+	spans := s.nextExport(c)
+	c.Assert(spans, gc.HasLen, 1)
+	c.Check(spans[0].StartTime(), gc.Equals, start)
+	c.Check(spans[0].EndTime(), gc.Equals, s.clock.Now())
+}
+
+func (s *tracerSuite) TestNotSampled(c *gc.C) {
+	tracer := s.newTracer(c, 0, time.Hour)
+	ctx, span := tracer.Start(context.Background(), "ignored")
+	c.Check(span.Scope().IsValid(), jc.IsTrue)
+	c.Check(span.Scope().Sampled, jc.IsFalse)
+
+	// Children follow the parent's sampling decision.
+	_, child := tracer.Start(ctx, "child")
+	c.Check(child.Scope().TraceID, gc.Equals, span.Scope().TraceID)
+	c.Check(child.Scope().Sampled, jc.IsFalse)
+	child.End()
+	span.End()
+
+	workertest.CleanKill(c, tracer)
+	c.Check(s.exporter.exports, gc.HasLen, 0)
+}
+
+func (s *tracerSuite) TestRemoteScopeSampled(c *gc.C) {
+	tracer := s.newTracer(c, 0, time.Hour)
+	remote, err := trace.ParseScope("0102030405060708090a0b0c0d0e0f10", "0102030405060708", trace.FlagSampled)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := trace.WithRemoteScope(context.Background(), remote)
+	_, span := tracer.Start(ctx, "remote child")
+	span.End()
+	workertest.CleanKill(c, tracer)
+
+	spans := s.nextExport(c)
+	c.Assert(spans, gc.HasLen, 1)
+	c.Check(trace.TraceID(spans[0].SpanContext().TraceID()), gc.Equals, remote.TraceID)
+	c.Check(trace.SpanID(spans[0].Parent().SpanID()), gc.Equals, remote.SpanID)
+	c.Check(spans[0].Parent().IsRemote(), jc.IsTrue)
+}
+
+func (s *tracerSuite) TestParentFromOtherTracer(c *gc.C) {
+	tracer := s.newTracer(c, 1, time.Hour)
+	other := s.newTracer(c, 1, time.Hour)
+
+	// Spans started by another tracer, such as one replaced after a
+	// config change, are still parents.
+	ctx, parent := other.Start(context.Background(), "parent")
+	_, span := tracer.Start(ctx, "child")
+	span.End()
+	workertest.CleanKill(c, tracer)
+
+	spans := s.nextExport(c)
+	c.Assert(spans, gc.HasLen, 1)
+	c.Check(trace.SpanID(spans[0].Parent().SpanID()), gc.Equals, parent.Scope().SpanID)
+	c.Check(spans[0].Parent().IsRemote(), jc.IsFalse)
+}
+
+type fakeExporter struct {
+	exports chan []sdktrace.ReadOnlySpan
+
+	mu       sync.Mutex
+	shutdown bool
+}
+
+func (e *fakeExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.exports <- spans
+	return nil
+}
+
+func (e *fakeExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shutdown = true
+	return nil
+}
+
+func (e *fakeExporter) isShutdown() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.shutdown
+}
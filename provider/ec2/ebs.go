@@ -87,6 +87,7 @@ const (
 	deviceInUse        = "InvalidDevice.InUse"
 	attachmentNotFound = "InvalidAttachment.NotFound"
 	volumeNotFound     = "InvalidVolume.NotFound"
+	snapshotNotFound   = "InvalidSnapshot.NotFound"
 	incorrectState     = "IncorrectState"
 )
 
@@ -445,6 +446,9 @@ func (v *ebsVolumeSource) createVolume(ctx context.ProviderCallContext, p storag
 		return nil, nil, errors.Trace(maybeConvertCredentialError(err, ctx))
 	}
 	vol, _ := parseVolumeOptions(p.Size, p.Attributes)
+	if p.SnapshotId != "" {
+		vol.SnapshotId = aws.String(p.SnapshotId)
+	}
 	if inst.Placement != nil {
 		vol.AvailabilityZone = inst.Placement.AvailabilityZone
 	}
@@ -0,0 +1,55 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+var _ storage.VolumeResizer = (*ebsVolumeSource)(nil)
+
+// ResizeVolumes is specified on the storage.VolumeResizer interface.
+//
+// EBS volumes are resized with Elastic Volumes, which grows the volume
+// while it remains attached and in use. The new size is rounded up to
+// the nearest GiB. AWS limits how often a volume may be modified, so
+// a resize may fail if the volume was modified recently.
+func (v *ebsVolumeSource) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	results := make([]storage.ResizeVolumesResult, len(params))
+	for i, p := range params {
+		info, err := v.resizeVolume(ctx, p)
+		if err != nil {
+			results[i].Error = errors.Trace(maybeConvertCredentialError(err, ctx))
+			continue
+		}
+		results[i].Info = info
+	}
+	return results, nil
+}
+
+func (v *ebsVolumeSource) resizeVolume(ctx context.ProviderCallContext, p storage.VolumeResizeParams) (*storage.VolumeInfo, error) {
+	sizeInGib := mibToGib(p.Size)
+	logger.Debugf("resizing volume %q to %dGiB", p.VolumeId, sizeInGib)
+	resp, err := v.env.ec2Client.ModifyVolume(ctx, &ec2.ModifyVolumeInput{
+		VolumeId: aws.String(p.VolumeId),
+		Size:     aws.Int32(int32(sizeInGib)),
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "resizing %q", p.VolumeId)
+	}
+	size := sizeInGib
+	if resp.VolumeModification != nil && resp.VolumeModification.TargetSize != nil {
+		size = uint64(aws.ToInt32(resp.VolumeModification.TargetSize))
+	}
+	return &storage.VolumeInfo{
+		VolumeId:   p.VolumeId,
+		Size:       gibToMib(size),
+		Persistent: true,
+	}, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsec2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/storage"
+)
+
+func (s *ebsSuite) TestResizeVolumes(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	c.Assert(vs, gc.Implements, new(storage.VolumeResizer))
+	volumeId := s.createSnapshotSourceVolume(c)
+
+	results, err := vs.(storage.VolumeResizer).ResizeVolumes(s.cloudCallCtx, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: volumeId,
+		Size:     4000,
+	}, {
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "vol-missing",
+		Size:     4096,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Info, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId:   volumeId,
+		Size:       4096,
+		Persistent: true,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `resizing "vol-missing": .*not found`)
+
+	volumes, err := s.srv.ec2srv.DescribeVolumes(s.cloudCallCtx, &awsec2.DescribeVolumesInput{
+		VolumeIds: []string{volumeId},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes.Volumes, gc.HasLen, 1)
+	c.Assert(aws.ToInt32(volumes.Volumes[0].Size), gc.Equals, int32(4))
+}
+
+func (s *ebsSuite) TestResizeVolumesCredentialError(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	volumeId := s.createSnapshotSourceVolume(c)
+	s.srv.ec2srv.SetAPIError("ModifyVolume", &smithy.GenericAPIError{Code: "Blocked"})
+
+	results, err := vs.(storage.VolumeResizer).ResizeVolumes(s.cloudCallCtx, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: volumeId,
+		Size:     4096,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(errors.Is(results[0].Error, common.ErrorCredentialNotValid), jc.IsTrue)
+}
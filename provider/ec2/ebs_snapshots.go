@@ -0,0 +1,78 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+var _ storage.VolumeSnapshotter = (*ebsVolumeSource)(nil)
+
+// CreateVolumeSnapshots is specified on the storage.VolumeSnapshotter interface.
+//
+// EBS snapshots are taken asynchronously; a snapshot may be recorded
+// before it has completed, in which case volumes created from it will
+// be lazily loaded by AWS.
+func (v *ebsVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	for i, p := range params {
+		snapshot, err := v.createVolumeSnapshot(ctx, p)
+		if err != nil {
+			results[i].Error = errors.Trace(maybeConvertCredentialError(err, ctx))
+			continue
+		}
+		results[i].Snapshot = snapshot
+	}
+	return results, nil
+}
+
+func (v *ebsVolumeSource) createVolumeSnapshot(ctx context.ProviderCallContext, p storage.VolumeSnapshotParams) (*storage.VolumeSnapshot, error) {
+	resourceTags := make(map[string]string)
+	for k, v := range p.ResourceTags {
+		resourceTags[k] = v
+	}
+	resourceTags[tagName] = fmt.Sprintf("juju-%s-snapshot-%s", v.envName, strings.Replace(p.Id, "/", "-", -1))
+	resp, err := v.env.ec2Client.CreateSnapshot(ctx, &ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(p.VolumeId),
+		Description: aws.String(fmt.Sprintf("juju volume snapshot %s", p.Id)),
+		TagSpecifications: []types.TagSpecification{
+			CreateTagSpecification(types.ResourceTypeSnapshot, resourceTags),
+		},
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "snapshotting %q", p.VolumeId)
+	}
+	return &storage.VolumeSnapshot{
+		Id: p.Id,
+		VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+			SnapshotId: aws.ToString(resp.SnapshotId),
+			Size:       gibToMib(uint64(aws.ToInt32(resp.VolumeSize))),
+			Created:    aws.ToTime(resp.StartTime),
+		},
+	}, nil
+}
+
+// DestroyVolumeSnapshots is specified on the storage.VolumeSnapshotter interface.
+func (v *ebsVolumeSource) DestroyVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		logger.Debugf("destroying snapshot %q", snapshotId)
+		_, err := v.env.ec2Client.DeleteSnapshot(ctx, &ec2.DeleteSnapshotInput{
+			SnapshotId: aws.String(snapshotId),
+		})
+		if err != nil && ec2ErrCode(err) != snapshotNotFound {
+			results[i] = errors.Annotatef(maybeConvertCredentialError(err, ctx), "destroying %q", snapshotId)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsec2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/ec2"
+	ec2test "github.com/juju/juju/provider/ec2/internal/testing"
+	"github.com/juju/juju/storage"
+)
+
+func (s *ebsSuite) createSnapshotSourceVolume(c *gc.C) string {
+	resp, err := s.srv.ec2srv.CreateVolume(s.cloudCallCtx, &awsec2.CreateVolumeInput{
+		Size:             aws.Int32(2),
+		VolumeType:       "gp2",
+		AvailabilityZone: aws.String("us-east-1a"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return aws.ToString(resp.VolumeId)
+}
+
+func (s *ebsSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	c.Assert(vs, gc.Implements, new(storage.VolumeSnapshotter))
+	volumeId := s.createSnapshotSourceVolume(c)
+
+	results, err := vs.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.cloudCallCtx, []storage.VolumeSnapshotParams{{
+		Id:           "0@1",
+		Volume:       names.NewVolumeTag("0"),
+		VolumeId:     volumeId,
+		ResourceTags: map[string]string{"foo": "bar"},
+	}, {
+		Id:       "1@2",
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "vol-missing",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Snapshot.Id, gc.Equals, "0@1")
+	c.Assert(results[0].Snapshot.SnapshotId, gc.Equals, "snap-0")
+	c.Assert(results[0].Snapshot.Size, gc.Equals, uint64(2048))
+	c.Assert(results[0].Snapshot.Created.IsZero(), jc.IsFalse)
+	c.Assert(results[1].Error, gc.ErrorMatches, `snapshotting "vol-missing": .*not found`)
+
+	snapshots := s.srv.ec2srv.Snapshots()
+	c.Assert(snapshots, gc.HasLen, 1)
+	c.Assert(aws.ToString(snapshots[0].VolumeId), gc.Equals, volumeId)
+	compareTags(c, snapshots[0].Tags, []tagInfo{
+		{"foo", "bar"},
+		{"Name", "juju-testmodel-snapshot-0@1"},
+	})
+}
+
+func (s *ebsSuite) TestCreateVolumeSnapshotsCredentialError(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	volumeId := s.createSnapshotSourceVolume(c)
+	s.srv.ec2srv.SetAPIError("CreateSnapshot", &smithy.GenericAPIError{Code: "Blocked"})
+
+	results, err := vs.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.cloudCallCtx, []storage.VolumeSnapshotParams{{
+		Id:       "0@1",
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: volumeId,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(errors.Is(results[0].Error, common.ErrorCredentialNotValid), jc.IsTrue)
+}
+
+func (s *ebsSuite) TestDestroyVolumeSnapshots(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	volumeId := s.createSnapshotSourceVolume(c)
+	snapshotter := vs.(storage.VolumeSnapshotter)
+	_, err := snapshotter.CreateVolumeSnapshots(s.cloudCallCtx, []storage.VolumeSnapshotParams{{
+		Id:       "0@1",
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: volumeId,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	errs, err := snapshotter.DestroyVolumeSnapshots(s.cloudCallCtx, []string{"snap-0", "snap-42"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil})
+	c.Assert(s.srv.ec2srv.Snapshots(), gc.HasLen, 0)
+}
+
+func (s *ebsSuite) TestCreateVolumeFromSnapshot(c *gc.C) {
+	vs := s.volumeSource(c, nil)
+	volumeId := s.createSnapshotSourceVolume(c)
+	_, err := vs.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.cloudCallCtx, []storage.VolumeSnapshotParams{{
+		Id:       "0@1",
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: volumeId,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	inst, err := s.srv.ec2srv.NewInstances(1, "m1.medium", imageId, ec2test.Running, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := vs.CreateVolumes(s.cloudCallCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       2048,
+		Provider:   ec2.EBS_ProviderType,
+		SnapshotId: "snap-0",
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				InstanceId: instance.Id(inst[0]),
+			},
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.Size, gc.Equals, uint64(2048))
+
+	volumes, err := s.srv.ec2srv.DescribeVolumes(s.cloudCallCtx, &awsec2.DescribeVolumesInput{
+		VolumeIds: []string{results[0].Volume.VolumeId},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes.Volumes, gc.HasLen, 1)
+	c.Assert(aws.ToString(volumes.Volumes[0].SnapshotId), gc.Equals, "snap-0")
+}
@@ -2022,6 +2022,114 @@ func (e *environ) closePortsInGroup(ctx context.ProviderCallContext, name string
 	return nil
 }
 
+// allowAllEgressPerm returns the permission EC2 adds to new security
+// groups, allowing all outbound traffic.
+func allowAllEgressPerm() types.IpPermission {
+	return types.IpPermission{
+		IpProtocol: aws.String("-1"),
+		IpRanges:   []types.IpRange{{CidrIp: aws.String(defaultRouteIpv4CIDRBlock)}},
+	}
+}
+
+func egressRulesToIPPerms(rules firewall.EgressRules) []types.IpPermission {
+	ipPerms := make([]types.IpPermission, len(rules))
+	for i, r := range rules {
+		ipPerms[i] = types.IpPermission{
+			IpProtocol: aws.String(r.PortRange.Protocol),
+			FromPort:   aws.Int32(int32(r.PortRange.FromPort)),
+			ToPort:     aws.Int32(int32(r.PortRange.ToPort)),
+		}
+		description := aws.String(fmt.Sprintf("juju egress to %s on %s", r.DestinationCIDR, r.PortRange))
+		// Rules are pre-validated, so the CIDR is either IPv4 or IPv6.
+		if addrType, _ := network.CIDRAddressType(r.DestinationCIDR); addrType == network.IPv6Address {
+			ipPerms[i].Ipv6Ranges = []types.Ipv6Range{{CidrIpv6: aws.String(r.DestinationCIDR), Description: description}}
+		} else {
+			ipPerms[i].IpRanges = []types.IpRange{{CidrIp: aws.String(r.DestinationCIDR), Description: description}}
+		}
+	}
+	return ipPerms
+}
+
+// egressRulesFromIPPerms converts security group egress permissions to
+// egress rules. It also reports whether the permissions include the
+// default rule allowing all outbound traffic.
+func egressRulesFromIPPerms(perms []types.IpPermission) (rules firewall.EgressRules, unrestricted bool) {
+	for _, p := range perms {
+		protocol := aws.ToString(p.IpProtocol)
+		if protocol == "-1" {
+			for _, r := range p.IpRanges {
+				if aws.ToString(r.CidrIp) == defaultRouteIpv4CIDRBlock {
+					unrestricted = true
+				}
+			}
+			continue
+		}
+		if protocol == "icmpv6" {
+			// As with ingress rules, icmpv6 isn't represented well
+			// in the juju model.
+			continue
+		}
+		portRange := network.PortRange{
+			Protocol: protocol,
+			FromPort: int(aws.ToInt32(p.FromPort)),
+			ToPort:   int(aws.ToInt32(p.ToPort)),
+		}
+		for _, r := range p.IpRanges {
+			rules = append(rules, firewall.NewEgressRule(aws.ToString(r.CidrIp), portRange))
+		}
+		for _, r := range p.Ipv6Ranges {
+			rules = append(rules, firewall.NewEgressRule(aws.ToString(r.CidrIpv6), portRange))
+		}
+	}
+	return rules.Union(nil), unrestricted
+}
+
+// setEgressRulesInGroup restricts outbound traffic from the named group
+// to the given rules. When there are no rules, the default rule allowing
+// all outbound traffic is restored.
+func (e *environ) setEgressRulesInGroup(ctx context.ProviderCallContext, name string, rules firewall.EgressRules) error {
+	g, err := e.groupByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	current, unrestricted := egressRulesFromIPPerms(g.IpPermissionsEgress)
+	var toAuthorize, toRevoke []types.IpPermission
+	if len(rules) == 0 {
+		toRevoke = egressRulesToIPPerms(current)
+		if !unrestricted {
+			toAuthorize = []types.IpPermission{allowAllEgressPerm()}
+		}
+	} else {
+		toAdd, toRemove := current.Diff(rules)
+		toAuthorize = egressRulesToIPPerms(toAdd)
+		toRevoke = egressRulesToIPPerms(toRemove)
+		if unrestricted {
+			toRevoke = append(toRevoke, allowAllEgressPerm())
+		}
+	}
+	// Authorize before revoking so that traffic which is allowed both
+	// before and after the change is never blocked.
+	if len(toAuthorize) > 0 {
+		_, err = e.ec2Client.AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+			GroupId:       g.GroupId,
+			IpPermissions: toAuthorize,
+		})
+		if err != nil && ec2ErrCode(err) != "InvalidPermission.Duplicate" {
+			return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot authorize egress rules")
+		}
+	}
+	if len(toRevoke) > 0 {
+		_, err = e.ec2Client.RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{
+			GroupId:       g.GroupId,
+			IpPermissions: toRevoke,
+		})
+		if err != nil {
+			return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot revoke egress rules")
+		}
+	}
+	return nil
+}
+
 func (e *environ) ingressRulesInGroup(ctx context.ProviderCallContext, name string) (rules firewall.IngressRules, err error) {
 	group, err := e.groupByName(ctx, name)
 	if err != nil {
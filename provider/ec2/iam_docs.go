@@ -32,11 +32,14 @@ const (
       "Action": [
         "ec2:AssociateIamInstanceProfile",
         "ec2:AttachVolume",
+        "ec2:AuthorizeSecurityGroupEgress",
         "ec2:AuthorizeSecurityGroupIngress",
         "ec2:CreateSecurityGroup",
+        "ec2:CreateSnapshot",
         "ec2:CreateTags",
         "ec2:CreateVolume",
         "ec2:DeleteSecurityGroup",
+        "ec2:DeleteSnapshot",
         "ec2:DeleteVolume",
         "ec2:DescribeAccountAttributes",
         "ec2:DescribeAvailabilityZones",
@@ -52,6 +55,8 @@ const (
         "ec2:DescribeVolumes",
         "ec2:DescribeVpcs",
         "ec2:DetachVolume",
+        "ec2:ModifyVolume",
+        "ec2:RevokeSecurityGroupEgress",
         "ec2:RevokeSecurityGroupIngress",
         "ec2:RunInstances",
         "ec2:TerminateInstances"
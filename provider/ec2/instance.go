@@ -147,6 +147,38 @@ func (inst *sdkInstance) IngressRules(ctx context.ProviderCallContext, machineId
 	return ranges, nil
 }
 
+// SetEgressRules implements instances.InstanceEgressFirewaller.
+func (inst *sdkInstance) SetEgressRules(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return fmt.Errorf("invalid firewall mode %q for setting egress rules on instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	if err := inst.e.setEgressRulesInGroup(ctx, name, rules); err != nil {
+		return err
+	}
+	logger.Infof("set egress rules in security group %s: %v", name, rules)
+	return nil
+}
+
+// EgressRules implements instances.InstanceEgressFirewaller.
+func (inst *sdkInstance) EgressRules(ctx context.ProviderCallContext, machineId string) (firewall.EgressRules, error) {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving egress rules from instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	group, err := inst.e.groupByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	rules, unrestricted := egressRulesFromIPPerms(group.IpPermissionsEgress)
+	if unrestricted {
+		return nil, nil
+	}
+	return rules, nil
+}
+
 // FetchInstanceClient describes the funcs needed from the EC2 client for
 // fetching instance types in a region. It's assumed that the ec2 client
 // conforming to this interface is scoped to the region that instances are being
@@ -35,6 +35,8 @@ type Server struct {
 	volumeAttachments   map[string]*volumeAttachment // id -> volumeAttachment
 	volumeMutatingCalls counter
 
+	snapshots map[string]*types.Snapshot // id -> snapshot
+
 	tagsMutatingCalls counter
 
 	maxId                       counter
@@ -48,6 +50,7 @@ type Server struct {
 	dhcpOptsId                  counter
 	subnetId                    counter
 	volumeId                    counter
+	snapshotId                  counter
 	ifaceId                     counter
 	attachId                    counter
 	initialInstanceState        types.InstanceState
@@ -90,6 +93,7 @@ func (srv *Server) Reset(withoutZonesOrGroups bool) {
 	srv.dhcpOptsId.reset()
 	srv.subnetId.reset()
 	srv.volumeId.reset()
+	srv.snapshotId.reset()
 	srv.ifaceId.reset()
 	srv.attachId.reset()
 
@@ -111,6 +115,7 @@ func (srv *Server) Reset(withoutZonesOrGroups bool) {
 	srv.ifaces = make(map[string]*iface)
 	srv.volumes = make(map[string]*volume)
 	srv.volumeAttachments = make(map[string]*volumeAttachment)
+	srv.snapshots = make(map[string]*types.Snapshot)
 	srv.reservations = make(map[string]*reservation)
 
 	srv.instanceProfileAssociations = make(map[string]types.IamInstanceProfileAssociation)
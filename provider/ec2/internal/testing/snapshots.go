@@ -0,0 +1,84 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// CreateSnapshot implements ec2.Client.
+func (srv *Server) CreateSnapshot(ctx context.Context, in *ec2.CreateSnapshotInput, opts ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+	srv.volumeMutatingCalls.next()
+
+	if err, ok := srv.apiCallErrors["CreateSnapshot"]; ok {
+		return nil, err
+	}
+
+	v, err := srv.volume(aws.ToString(in.VolumeId))
+	if err != nil {
+		return nil, err
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	snapshot := &types.Snapshot{
+		SnapshotId:  aws.String(fmt.Sprintf("snap-%d", srv.snapshotId.next())),
+		VolumeId:    v.VolumeId,
+		VolumeSize:  v.Size,
+		Description: in.Description,
+		Encrypted:   v.Encrypted,
+		StartTime:   aws.Time(time.Now()),
+		State:       types.SnapshotStateCompleted,
+		Tags:        tagSpecForType(types.ResourceTypeSnapshot, in.TagSpecifications).Tags,
+	}
+	srv.snapshots[aws.ToString(snapshot.SnapshotId)] = snapshot
+
+	return &ec2.CreateSnapshotOutput{
+		SnapshotId:  snapshot.SnapshotId,
+		VolumeId:    snapshot.VolumeId,
+		VolumeSize:  snapshot.VolumeSize,
+		Description: snapshot.Description,
+		Encrypted:   snapshot.Encrypted,
+		StartTime:   snapshot.StartTime,
+		State:       snapshot.State,
+		Tags:        snapshot.Tags,
+	}, nil
+}
+
+// DeleteSnapshot implements ec2.Client.
+func (srv *Server) DeleteSnapshot(ctx context.Context, in *ec2.DeleteSnapshotInput, opts ...func(*ec2.Options)) (*ec2.DeleteSnapshotOutput, error) {
+	srv.volumeMutatingCalls.next()
+
+	if err, ok := srv.apiCallErrors["DeleteSnapshot"]; ok {
+		return nil, err
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	id := aws.ToString(in.SnapshotId)
+	if _, ok := srv.snapshots[id]; !ok {
+		return nil, apiError("InvalidSnapshot.NotFound", "Snapshot %s not found", id)
+	}
+	delete(srv.snapshots, id)
+	return &ec2.DeleteSnapshotOutput{}, nil
+}
+
+// Snapshots returns the snapshots known to the server.
+func (srv *Server) Snapshots() []types.Snapshot {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	snapshots := make([]types.Snapshot, 0, len(srv.snapshots))
+	for _, snapshot := range srv.snapshots {
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots
+}
@@ -26,7 +26,18 @@ func (srv *Server) CreateVolume(ctx context.Context, in *ec2.CreateVolumeInput,
 
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
+	var snapshot *types.Snapshot
+	if in.SnapshotId != nil {
+		var ok bool
+		if snapshot, ok = srv.snapshots[aws.ToString(in.SnapshotId)]; !ok {
+			return nil, apiError("InvalidSnapshot.NotFound", "Snapshot %s not found", aws.ToString(in.SnapshotId))
+		}
+	}
 	volume := srv.newVolume("magnetic", 1, in.TagSpecifications)
+	if snapshot != nil {
+		volume.SnapshotId = snapshot.SnapshotId
+		volume.Size = snapshot.VolumeSize
+	}
 	volume.AvailabilityZone = in.AvailabilityZone
 	if in.VolumeType != "" {
 		volume.VolumeType = in.VolumeType
@@ -49,6 +60,7 @@ func (srv *Server) CreateVolume(ctx context.Context, in *ec2.CreateVolumeInput,
 		Tags:             volume.Tags,
 		VolumeId:         volume.VolumeId,
 		VolumeType:       volume.VolumeType,
+		SnapshotId:       volume.SnapshotId,
 		KmsKeyId:         volume.KmsKeyId,
 		Throughput:       volume.Throughput,
 	}, nil
@@ -116,6 +128,43 @@ func (srv *Server) DescribeVolumes(ctx context.Context, in *ec2.DescribeVolumesI
 	return result, nil
 }
 
+// ModifyVolume implements ec2.Client.
+func (srv *Server) ModifyVolume(ctx context.Context, in *ec2.ModifyVolumeInput, opts ...func(*ec2.Options)) (*ec2.ModifyVolumeOutput, error) {
+	srv.volumeMutatingCalls.next()
+
+	if err, ok := srv.apiCallErrors["ModifyVolume"]; ok {
+		return nil, err
+	}
+
+	v, err := srv.volume(aws.ToString(in.VolumeId))
+	if err != nil {
+		return nil, err
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	modification := &types.VolumeModification{
+		VolumeId:           v.VolumeId,
+		ModificationState:  types.VolumeModificationStateCompleted,
+		OriginalSize:       v.Size,
+		OriginalVolumeType: v.VolumeType,
+		TargetSize:         v.Size,
+		TargetVolumeType:   v.VolumeType,
+	}
+	if in.Size != nil {
+		if aws.ToInt32(in.Size) < aws.ToInt32(v.Size) {
+			return nil, apiError("InvalidParameterValue", "New size cannot be smaller than existing size")
+		}
+		v.Size = in.Size
+		modification.TargetSize = in.Size
+	}
+	if in.VolumeType != "" {
+		v.VolumeType = in.VolumeType
+		modification.TargetVolumeType = in.VolumeType
+	}
+	return &ec2.ModifyVolumeOutput{VolumeModification: modification}, nil
+}
+
 // SetCreateRootDisks records whether or not the server should create
 // root disks for each instance created. It defaults to false.
 func (srv *Server) SetCreateRootDisks(create bool) {
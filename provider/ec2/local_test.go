@@ -2901,6 +2901,61 @@ func (t *localServerSuite) TestPorts(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid firewall mode "instance" for retrieving ingress rules from model`)
 }
 
+func (t *localServerSuite) TestEgressRules(c *gc.C) {
+	t.prepareAndBootstrap(c)
+
+	inst1, _ := testing.AssertStartInstance(c, t.Env, t.ProviderCallContext, t.ControllerUUID, "1")
+	c.Assert(inst1, gc.NotNil)
+	defer func() { _ = t.Env.StopInstances(t.ProviderCallContext, inst1.Id()) }()
+	fwInst1, ok := inst1.(instances.InstanceEgressFirewaller)
+	c.Assert(ok, jc.IsTrue)
+
+	// New instances can reach anything.
+	rules, err := fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	restricted := firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+		firewall.NewEgressRule("10.1.2.3/32", network.MustParsePortRange("17070/tcp")),
+	}
+	err = fwInst1.SetEgressRules(t.ProviderCallContext, "1", restricted)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, restricted)
+
+	// Changing the rules only keeps the new ones.
+	changed := firewall.EgressRules{
+		firewall.NewEgressRule("10.1.2.3/32", network.MustParsePortRange("17070/tcp")),
+		firewall.NewEgressRule("192.168.0.0/16", network.MustParsePortRange("53/udp")),
+	}
+	err = fwInst1.SetEgressRules(t.ProviderCallContext, "1", changed)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, changed)
+
+	// Clearing the rules restores unrestricted egress.
+	err = fwInst1.SetEgressRules(t.ProviderCallContext, "1", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	// The default rule allowing all outbound traffic is back in place.
+	groupsResp, err := t.client.DescribeSecurityGroups(t.callCtx, &awsec2.DescribeSecurityGroupsInput{
+		GroupNames: []string{ec2.MachineGroupName(t.Env, "1")},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groupsResp.SecurityGroups, gc.HasLen, 1)
+	egress := groupsResp.SecurityGroups[0].IpPermissionsEgress
+	c.Assert(egress, gc.HasLen, 1)
+	c.Check(aws.ToString(egress[0].IpProtocol), gc.Equals, "-1")
+	c.Check(egress[0].IpRanges, gc.HasLen, 1)
+	c.Check(aws.ToString(egress[0].IpRanges[0].CidrIp), gc.Equals, "0.0.0.0/0")
+}
+
 func (t *localServerSuite) TestGlobalPorts(c *gc.C) {
 	t.prepareAndBootstrap(c)
 
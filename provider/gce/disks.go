@@ -226,6 +226,7 @@ func (v *volumeSource) createOneVolume(ctx context.ProviderCallContext, p storag
 		Name:               volumeName,
 		PersistentDiskType: persistentType,
 		Labels:             resourceTagsToDiskLabels(p.ResourceTags),
+		SourceSnapshot:     p.SnapshotId,
 	}
 
 	gceDisks, err := v.gce.CreateDisks(zone, []google.DiskSpec{disk})
@@ -287,6 +288,96 @@ func (v *volumeSource) foreachVolume(ctx context.ProviderCallContext, volNames [
 	return results
 }
 
+var _ storage.VolumeSnapshotter = (*volumeSource)(nil)
+
+// CreateVolumeSnapshots is specified on the storage.VolumeSnapshotter interface.
+func (v *volumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, params []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(params))
+	var wg sync.WaitGroup
+	wg.Add(len(params))
+	for i, p := range params {
+		go func(i int, p storage.VolumeSnapshotParams) {
+			defer wg.Done()
+			results[i].Snapshot, results[i].Error = v.createOneVolumeSnapshot(ctx, p)
+		}(i, p)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (v *volumeSource) createOneVolumeSnapshot(ctx context.ProviderCallContext, p storage.VolumeSnapshotParams) (*storage.VolumeSnapshot, error) {
+	zone, _, err := parseVolumeId(p.VolumeId)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid volume id %q", p.VolumeId)
+	}
+	snapshotUUID, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot generate uuid to name the snapshot")
+	}
+	// Snapshot names, like disk names, must comply with RFC1035
+	// and so cannot start with a digit.
+	spec := google.SnapshotSpec{
+		Name:        "juju-" + snapshotUUID.String(),
+		Description: fmt.Sprintf("juju volume snapshot %s", p.Id),
+		Labels:      resourceTagsToDiskLabels(p.ResourceTags),
+	}
+	snapshot, err := v.gce.CreateSnapshot(zone, p.VolumeId, spec)
+	if err != nil {
+		return nil, google.HandleCredentialError(errors.Annotate(err, "cannot create snapshot"), ctx)
+	}
+	return &storage.VolumeSnapshot{
+		Id: p.Id,
+		VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+			SnapshotId: snapshot.Name,
+			Size:       snapshot.Size,
+			Created:    snapshot.Created,
+		},
+	}, nil
+}
+
+// DestroyVolumeSnapshots is specified on the storage.VolumeSnapshotter interface.
+func (v *volumeSource) DestroyVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	return v.foreachVolume(ctx, snapshotIds, func(ctx context.ProviderCallContext, snapshotId string) error {
+		return google.HandleCredentialError(v.gce.RemoveSnapshot(snapshotId), ctx)
+	}), nil
+}
+
+var _ storage.VolumeResizer = (*volumeSource)(nil)
+
+// ResizeVolumes is specified on the storage.VolumeResizer interface.
+//
+// Persistent disks may be grown while attached to a running instance.
+// The new size is rounded up to the nearest GiB.
+func (v *volumeSource) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	results := make([]storage.ResizeVolumesResult, len(params))
+	var wg sync.WaitGroup
+	wg.Add(len(params))
+	for i, p := range params {
+		go func(i int, p storage.VolumeResizeParams) {
+			defer wg.Done()
+			results[i].Info, results[i].Error = v.resizeOneVolume(ctx, p)
+		}(i, p)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (v *volumeSource) resizeOneVolume(ctx context.ProviderCallContext, p storage.VolumeResizeParams) (*storage.VolumeInfo, error) {
+	zone, _, err := parseVolumeId(p.VolumeId)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid volume id %q", p.VolumeId)
+	}
+	disk, err := v.gce.ResizeDisk(zone, p.VolumeId, mibToGib(p.Size))
+	if err != nil {
+		return nil, google.HandleCredentialError(errors.Annotate(err, "cannot resize disk"), ctx)
+	}
+	return &storage.VolumeInfo{
+		VolumeId:   disk.Name,
+		Size:       disk.Size,
+		Persistent: true,
+	}, nil
+}
+
 func parseVolumeId(volName string) (string, string, error) {
 	idRest := strings.SplitN(volName, "--", 2)
 	if len(idRest) != 2 {
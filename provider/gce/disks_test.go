@@ -4,6 +4,8 @@
 package gce_test
 
 import (
+	"time"
+
 	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -195,6 +197,96 @@ func (s *volumeSourceSuite) TestDestroyVolumes(c *gc.C) {
 	c.Assert(call[0].ID, gc.Equals, "a--volume-name")
 }
 
+func (s *volumeSourceSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	created := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.FakeConn.Snapshot = &google.Snapshot{
+		Name:    "juju-snapshot",
+		Size:    10240,
+		Created: created,
+	}
+	results, err := s.source.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.CallCtx, []storage.VolumeSnapshotParams{{
+		Id:           "0@1",
+		Volume:       names.NewVolumeTag("0"),
+		VolumeId:     "home-zone--volume-name",
+		ResourceTags: map[string]string{"juju-model-uuid": "foo", "other": "bar"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Snapshot, jc.DeepEquals, &storage.VolumeSnapshot{
+		Id: "0@1",
+		VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+			SnapshotId: "juju-snapshot",
+			Size:       10240,
+			Created:    created,
+		},
+	})
+
+	called, calls := s.FakeConn.WasCalled("CreateSnapshot")
+	c.Assert(called, jc.IsTrue)
+	c.Assert(calls, gc.HasLen, 1)
+	c.Assert(calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Assert(calls[0].VolumeName, gc.Equals, "home-zone--volume-name")
+	c.Assert(calls[0].Snapshot.Name, gc.Matches, "juju-[0-9a-f-]{36}")
+	c.Assert(calls[0].Snapshot.Labels, jc.DeepEquals, map[string]string{"juju-model-uuid": "foo"})
+}
+
+func (s *volumeSourceSuite) TestCreateVolumeSnapshotsInvalidVolumeId(c *gc.C) {
+	results, err := s.source.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.CallCtx, []storage.VolumeSnapshotParams{{
+		Id:       "0@1",
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "volume-name",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `invalid volume id "volume-name": malformed volume id "volume-name"`)
+	called, _ := s.FakeConn.WasCalled("CreateSnapshot")
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *volumeSourceSuite) TestDestroyVolumeSnapshots(c *gc.C) {
+	errs, err := s.source.(storage.VolumeSnapshotter).DestroyVolumeSnapshots(s.CallCtx, []string{"juju-snapshot"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+
+	called, calls := s.FakeConn.WasCalled("RemoveSnapshot")
+	c.Assert(called, jc.IsTrue)
+	c.Assert(calls, gc.HasLen, 1)
+	c.Assert(calls[0].ID, gc.Equals, "juju-snapshot")
+}
+
+func (s *volumeSourceSuite) TestResizeVolumes(c *gc.C) {
+	s.FakeConn.GoogleDisk = &google.Disk{
+		Name: "home-zone--volume-name",
+		Size: 4096,
+	}
+	results, err := s.source.(storage.VolumeResizer).ResizeVolumes(s.CallCtx, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "home-zone--volume-name",
+		Size:     4000,
+	}, {
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "volume-name",
+		Size:     4096,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Info, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId:   "home-zone--volume-name",
+		Size:       4096,
+		Persistent: true,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `invalid volume id "volume-name": malformed volume id "volume-name"`)
+
+	called, calls := s.FakeConn.WasCalled("ResizeDisk")
+	c.Assert(called, jc.IsTrue)
+	c.Assert(calls, gc.HasLen, 1)
+	c.Assert(calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Assert(calls[0].ID, gc.Equals, "home-zone--volume-name")
+	c.Assert(calls[0].SizeGb, gc.Equals, uint64(4))
+}
+
 func (s *volumeSourceSuite) TestReleaseVolumesInvalidCredentialError(c *gc.C) {
 	s.FakeConn.Err = gce.InvalidCredentialError
 	c.Assert(s.InvalidatedCredentials, jc.IsFalse)
@@ -43,6 +43,9 @@ type gceConnection interface {
 	OpenPorts(fwname string, rules firewall.IngressRules) error
 	ClosePorts(fwname string, rules firewall.IngressRules) error
 
+	EgressRules(fwname string) (firewall.EgressRules, error)
+	SetEgressRules(fwname string, rules firewall.EgressRules) error
+
 	AvailabilityZones(region string) ([]google.AvailabilityZone, error)
 	// Subnetworks returns the subnetworks that machines can be
 	// assigned to in the given region.
@@ -66,6 +69,9 @@ type gceConnection interface {
 	// SetDiskLabels sets the labels on a disk, ensuring that the disk's
 	// label fingerprint matches the one supplied.
 	SetDiskLabels(zone, id, labelFingerprint string, labels map[string]string) error
+	// ResizeDisk will grow the disk identified by <id> in <zone> to
+	// <sizeGb> GiB, and return a Disk representing the resized disk.
+	ResizeDisk(zone, id string, sizeGb uint64) (*google.Disk, error)
 	// AttachDisk will attach the volume identified by <volumeName> into the instance
 	// <instanceId> and return an AttachedDisk representing it or error.
 	AttachDisk(zone, volumeName, instanceId string, mode google.DiskMode) (*google.AttachedDisk, error)
@@ -74,6 +80,11 @@ type gceConnection interface {
 	DetachDisk(zone, instanceId, volumeName string) error
 	// InstanceDisks returns a list of the disks attached to the passed instance.
 	InstanceDisks(zone, instanceId string) ([]*google.AttachedDisk, error)
+	// CreateSnapshot takes a snapshot of the disk identified by
+	// <diskName> in <zone>, and returns a Snapshot representing it.
+	CreateSnapshot(zone, diskName string, spec google.SnapshotSpec) (*google.Snapshot, error)
+	// RemoveSnapshot will destroy the snapshot identified by <name>.
+	RemoveSnapshot(name string) error
 	// ListMachineTypes returns a list of machines available in the project and zone provided.
 	ListMachineTypes(zone string) ([]google.MachineType, error)
 }
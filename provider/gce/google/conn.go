@@ -98,6 +98,10 @@ type service interface {
 	// label fingerprint matches the one supplied.
 	SetDiskLabels(project, zone, id, labelFingerprint string, labels map[string]string) error
 
+	// ResizeDisk grows the disk correspondent to the passed id
+	// to the specified size in GiB.
+	ResizeDisk(project, zone, id string, sizeGb int64) error
+
 	// AttachDisk will attach the disk described in attachedDisks (if it exists) into
 	// the instance with id instanceId.
 	AttachDisk(project, zone, instanceId string, attachedDisk *compute.AttachedDisk) error
@@ -110,6 +114,16 @@ type service interface {
 	// by instanceId
 	InstanceDisks(project, zone, instanceId string) ([]*compute.AttachedDisk, error)
 
+	// CreateSnapshot will take a snapshot of the disk identified by
+	// disk, as described by spec.
+	CreateSnapshot(project, zone, disk string, spec *compute.Snapshot) error
+
+	// GetSnapshot will return the snapshot with the specified name.
+	GetSnapshot(project, name string) (*compute.Snapshot, error)
+
+	// RemoveSnapshot will delete the snapshot with the specified name.
+	RemoveSnapshot(project, name string) error
+
 	// ListMachineTypes returns a list of machines available in the project and zone provided.
 	ListMachineTypes(projectID, zone string) (*compute.MachineTypeList, error)
 
@@ -65,6 +65,43 @@ func (gce *Connection) SetDiskLabels(zone, name, labelFingerprint string, labels
 	return errors.Annotatef(err, "cannot update labels for disk %q in zone %q", name, zone)
 }
 
+// ResizeDisk implements storage section of gceConnection.
+// The disk is grown to <sizeGb> GiB, and the resized disk returned.
+func (gce *Connection) ResizeDisk(zone, name string, sizeGb uint64) (*Disk, error) {
+	err := gce.service.ResizeDisk(gce.projectID, zone, name, int64(sizeGb))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot resize disk %q in zone %q", name, zone)
+	}
+	return gce.Disk(zone, name)
+}
+
+// CreateSnapshot implements storage section of gceConnection.
+func (gce *Connection) CreateSnapshot(zone, diskName string, spec SnapshotSpec) (*Snapshot, error) {
+	err := gce.service.CreateSnapshot(gce.projectID, zone, diskName, &compute.Snapshot{
+		Name:        spec.Name,
+		Description: spec.Description,
+		Labels:      spec.Labels,
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot snapshot disk %q", diskName)
+	}
+	snapshot, err := gce.service.GetSnapshot(gce.projectID, spec.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewSnapshot(snapshot), nil
+}
+
+// RemoveSnapshot implements storage section of gceConnection.
+// Removing a snapshot that does not exist is not an error.
+func (gce *Connection) RemoveSnapshot(name string) error {
+	err := gce.service.RemoveSnapshot(gce.projectID, name)
+	if IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
 // deviceName will generate a device name from the passed
 // <zone> and <diskId>, the device name must not be confused
 // with the volume name, as it is used mainly to name the
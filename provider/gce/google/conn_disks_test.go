@@ -4,8 +4,12 @@
 package google_test
 
 import (
+	"net/http"
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/provider/gce/google"
@@ -164,3 +168,67 @@ func (s *connSuite) TestConnectionInstanceDisks(c *gc.C) {
 	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
 	c.Check(s.FakeConn.Calls[0].InstanceId, gc.Equals, "a-fake-instance")
 }
+
+func (s *connSuite) TestConnectionCreateSnapshot(c *gc.C) {
+	s.FakeConn.Snapshot = &compute.Snapshot{
+		Name:              "juju-snap",
+		SourceDisk:        "https://bogus/url/project/aproject/zone/azone/disk/" + fakeVolName,
+		DiskSizeGb:        10,
+		CreationTimestamp: "2023-04-01T12:00:00Z",
+	}
+
+	snapshot, err := s.Conn.CreateSnapshot("home-zone", fakeVolName, google.SnapshotSpec{
+		Name:   "juju-snap",
+		Labels: map[string]string{"foo": "bar"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot, jc.DeepEquals, &google.Snapshot{
+		Name:       "juju-snap",
+		SourceDisk: fakeVolName,
+		Size:       10240,
+		Created:    time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "CreateSnapshot")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, fakeVolName)
+	c.Check(s.FakeConn.Calls[0].Snapshot, jc.DeepEquals, &compute.Snapshot{
+		Name:   "juju-snap",
+		Labels: map[string]string{"foo": "bar"},
+	})
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetSnapshot")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "juju-snap")
+}
+
+func (s *connSuite) TestConnectionRemoveSnapshotNotFound(c *gc.C) {
+	s.FakeConn.Err = &googleapi.Error{Code: http.StatusNotFound}
+
+	err := s.Conn.RemoveSnapshot("juju-snap")
+	c.Check(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveSnapshot")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Equals, "juju-snap")
+}
+
+func (s *connSuite) TestConnectionResizeDisk(c *gc.C) {
+	s.FakeConn.Disk = &compute.Disk{
+		Name:   fakeVolName,
+		SizeGb: 4,
+		Zone:   "home-zone",
+		Status: "READY",
+	}
+
+	disk, err := s.Conn.ResizeDisk("home-zone", fakeVolName, 4)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(disk.Name, gc.Equals, fakeVolName)
+	c.Check(disk.Size, gc.Equals, uint64(4096))
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ResizeDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, fakeVolName)
+	c.Check(s.FakeConn.Calls[0].SizeGb, gc.Equals, int64(4))
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetDisk")
+}
@@ -4,14 +4,17 @@
 package google
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"math/rand"
 	"sort"
+	"strings"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"google.golang.org/api/compute/v1"
 
+	corenetwork "github.com/juju/juju/core/network"
 	corefirewall "github.com/juju/juju/core/network/firewall"
 )
 
@@ -216,6 +219,192 @@ func (gce Connection) ClosePorts(target string, rules corefirewall.IngressRules)
 	return nil
 }
 
+const (
+	egressDirection = "EGRESS"
+
+	// egressDenyPriority is the priority of the firewall denying outbound
+	// traffic that isn't explicitly allowed. It sits just above the
+	// implied rule allowing all egress, which has the lowest priority.
+	egressDenyPriority = 65534
+)
+
+// egressFirewallName returns the name of the firewall allowing traffic
+// from target to the given destination CIDR.
+func egressFirewallName(target, cidr string) string {
+	sum := sha256.Sum256([]byte(cidr))
+	return fmt.Sprintf("%s-egress-%x", target, sum[:4])
+}
+
+// egressDenyFirewallName returns the name of the firewall denying all
+// other outbound traffic from target.
+func egressDenyFirewallName(target string) string {
+	return target + "-egress-deny"
+}
+
+// egressFirewalls returns the egress firewalls for the given target,
+// keyed by name.
+func (gce Connection) egressFirewalls(target string) (map[string]*compute.Firewall, error) {
+	firewalls, err := gce.service.GetFirewalls(gce.projectID, target)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "while getting firewall rules from GCE")
+	}
+	result := make(map[string]*compute.Firewall)
+	for _, fw := range firewalls {
+		if fw.Direction == egressDirection && strings.HasPrefix(fw.Name, target+"-egress-") {
+			result[fw.Name] = fw
+		}
+	}
+	return result, nil
+}
+
+// EgressRules returns the egress rules for the given firewall target. If
+// outbound traffic is unrestricted, no rules are returned.
+func (gce Connection) EgressRules(target string) (corefirewall.EgressRules, error) {
+	firewalls, err := gce.egressFirewalls(target)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, ok := firewalls[egressDenyFirewallName(target)]; !ok {
+		return nil, nil
+	}
+	var rules corefirewall.EgressRules
+	for _, fw := range firewalls {
+		for _, allowed := range fw.Allowed {
+			var ranges []corenetwork.PortRange
+			switch {
+			case allowed.IPProtocol == "icmp":
+				ranges = append(ranges, corenetwork.PortRange{Protocol: "icmp", FromPort: -1, ToPort: -1})
+			case len(allowed.Ports) == 0:
+				ranges = append(ranges, corenetwork.PortRange{Protocol: allowed.IPProtocol, FromPort: 1, ToPort: 65535})
+			}
+			for _, rangeStr := range allowed.Ports {
+				portRange, err := corenetwork.ParsePortRange(rangeStr)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				portRange.Protocol = allowed.IPProtocol
+				ranges = append(ranges, portRange)
+			}
+			for _, cidr := range fw.DestinationRanges {
+				for _, portRange := range ranges {
+					rules = append(rules, corefirewall.NewEgressRule(cidr, portRange))
+				}
+			}
+		}
+	}
+	return rules.Union(nil), nil
+}
+
+// SetEgressRules restricts outbound traffic from the given firewall
+// target to the destinations in the rules. A firewall is maintained for
+// each destination CIDR, along with one denying all other traffic. If
+// there are no rules, all of the egress firewalls are removed so that
+// outbound traffic is unrestricted.
+func (gce Connection) SetEgressRules(target string, rules corefirewall.EgressRules) error {
+	current, err := gce.egressFirewalls(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	wanted := make(map[string]*compute.Firewall)
+	if len(rules) > 0 {
+		portsByCIDR := make(map[string]protocolPorts)
+		for _, rule := range rules {
+			ports, ok := portsByCIDR[rule.DestinationCIDR]
+			if !ok {
+				ports = make(protocolPorts)
+				portsByCIDR[rule.DestinationCIDR] = ports
+			}
+			ports[rule.PortRange.Protocol] = append(ports[rule.PortRange.Protocol], rule.PortRange)
+		}
+		for cidr, ports := range portsByCIDR {
+			for protocol, ranges := range ports {
+				ports[protocol] = corenetwork.CombinePortRanges(ranges...)
+			}
+			name := egressFirewallName(target, cidr)
+			spec := firewallSpec(name, target, nil, ports)
+			spec.SourceRanges = nil
+			spec.Direction = egressDirection
+			spec.DestinationRanges = []string{cidr}
+			wanted[name] = spec
+		}
+	}
+
+	var sortedNames []string
+	for name := range wanted {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	// Allow the wanted traffic before denying everything else, so that
+	// it isn't interrupted.
+	for _, name := range sortedNames {
+		spec := wanted[name]
+		existing, ok := current[name]
+		if !ok {
+			if err := gce.service.AddFirewall(gce.projectID, spec); err != nil {
+				return errors.Annotatef(err, "adding egress firewall %q", name)
+			}
+			continue
+		}
+		if egressAllowedString(existing) == egressAllowedString(spec) {
+			continue
+		}
+		if err := gce.service.UpdateFirewall(gce.projectID, name, spec); err != nil {
+			return errors.Annotatef(err, "updating egress firewall %q", name)
+		}
+	}
+	denyName := egressDenyFirewallName(target)
+	if _, ok := current[denyName]; !ok && len(wanted) > 0 {
+		// GCE firewalls can't mix address families, and juju only
+		// manages IPv4 for GCE instances.
+		spec := &compute.Firewall{
+			Name:              denyName,
+			Direction:         egressDirection,
+			Priority:          egressDenyPriority,
+			TargetTags:        []string{target},
+			DestinationRanges: []string{corefirewall.AllNetworksIPV4CIDR},
+			Denied:            []*compute.FirewallDenied{{IPProtocol: "all"}},
+		}
+		if err := gce.service.AddFirewall(gce.projectID, spec); err != nil {
+			return errors.Annotatef(err, "adding egress firewall %q", denyName)
+		}
+	}
+
+	var toRemove []string
+	for name := range current {
+		if _, ok := wanted[name]; ok || name == denyName {
+			continue
+		}
+		toRemove = append(toRemove, name)
+	}
+	sort.Strings(toRemove)
+	if _, ok := current[denyName]; ok && len(wanted) == 0 {
+		// Lift the restriction before removing what it allowed.
+		toRemove = append([]string{denyName}, toRemove...)
+	}
+	for _, name := range toRemove {
+		if err := gce.service.RemoveFirewall(gce.projectID, name); err != nil {
+			return errors.Annotatef(err, "removing egress firewall %q", name)
+		}
+	}
+	return nil
+}
+
+// egressAllowedString returns a comparable representation of what an
+// egress firewall allows.
+func egressAllowedString(fw *compute.Firewall) string {
+	var parts []string
+	for _, allowed := range fw.Allowed {
+		parts = append(parts, allowed.IPProtocol+":"+strings.Join(allowed.Ports, ","))
+	}
+	sort.Strings(parts)
+	return strings.Join(fw.DestinationRanges, ",") + " " + strings.Join(parts, ";")
+}
+
 // Subnetworks returns the subnets available in this region.
 func (gce Connection) Subnetworks(region string) ([]*compute.Subnetwork, error) {
 	results, err := gce.service.ListSubnetworks(gce.projectID, region)
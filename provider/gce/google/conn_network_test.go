@@ -447,6 +447,159 @@ func (s *connSuite) TestConnectionCloseMoMatches(c *gc.C) {
 	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewalls")
 }
 
+func (s *connSuite) TestConnectionEgressRules(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80"},
+		}},
+	}, {
+		Name:              google.EgressFirewallName("spam", "10.0.0.0/8"),
+		Direction:         "EGRESS",
+		TargetTags:        []string{"spam"},
+		DestinationRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"5432", "6379"},
+		}, {
+			IPProtocol: "icmp",
+		}},
+	}, {
+		Name:              "spam-egress-deny",
+		Direction:         "EGRESS",
+		Priority:          65534,
+		TargetTags:        []string{"spam"},
+		DestinationRanges: []string{"0.0.0.0/0"},
+		Denied:            []*compute.FirewallDenied{{IPProtocol: "all"}},
+	}}
+
+	rules, err := s.Conn.EgressRules("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rules, jc.DeepEquals, corefirewall.EgressRules{
+		corefirewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("icmp")),
+		corefirewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+		corefirewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("6379/tcp")),
+	})
+
+	// Egress firewalls aren't reported as ingress rules.
+	ingress, err := s.Conn.IngressRules("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ingress, jc.DeepEquals, corefirewall.IngressRules{
+		corefirewall.NewIngressRule(network.MustParsePortRange("80/tcp"), "0.0.0.0/0"),
+	})
+}
+
+func (s *connSuite) TestConnectionEgressRulesUnrestricted(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80"},
+		}},
+	}}
+
+	rules, err := s.Conn.EgressRules("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rules, gc.HasLen, 0)
+}
+
+func (s *connSuite) TestConnectionSetEgressRules(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+	rules := corefirewall.EgressRules{
+		corefirewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+		corefirewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5433/tcp")),
+	}
+	err := s.Conn.SetEgressRules("spam", rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewalls")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:              google.EgressFirewallName("spam", "10.0.0.0/8"),
+		Direction:         "EGRESS",
+		TargetTags:        []string{"spam"},
+		DestinationRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"5432-5433"},
+		}},
+	})
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[2].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:              "spam-egress-deny",
+		Direction:         "EGRESS",
+		Priority:          65534,
+		TargetTags:        []string{"spam"},
+		DestinationRanges: []string{"0.0.0.0/0"},
+		Denied:            []*compute.FirewallDenied{{IPProtocol: "all"}},
+	})
+}
+
+func (s *connSuite) TestConnectionSetEgressRulesChanged(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:              google.EgressFirewallName("spam", "10.0.0.0/8"),
+		Direction:         "EGRESS",
+		TargetTags:        []string{"spam"},
+		DestinationRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"5432"},
+		}},
+	}, {
+		Name:              "spam-egress-deny",
+		Direction:         "EGRESS",
+		TargetTags:        []string{"spam"},
+		DestinationRanges: []string{"0.0.0.0/0"},
+		Denied:            []*compute.FirewallDenied{{IPProtocol: "all"}},
+	}}
+
+	rules := corefirewall.EgressRules{
+		corefirewall.NewEgressRule("192.168.0.0/16", network.MustParsePortRange("53/udp")),
+	}
+	err := s.Conn.SetEgressRules("spam", rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[1].Firewall.Name, gc.Equals, google.EgressFirewallName("spam", "192.168.0.0/16"))
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[2].Name, gc.Equals, google.EgressFirewallName("spam", "10.0.0.0/8"))
+}
+
+func (s *connSuite) TestConnectionSetEgressRulesClear(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:              google.EgressFirewallName("spam", "10.0.0.0/8"),
+		Direction:         "EGRESS",
+		TargetTags:        []string{"spam"},
+		DestinationRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"5432"},
+		}},
+	}, {
+		Name:              "spam-egress-deny",
+		Direction:         "EGRESS",
+		TargetTags:        []string{"spam"},
+		DestinationRanges: []string{"0.0.0.0/0"},
+		Denied:            []*compute.FirewallDenied{{IPProtocol: "all"}},
+	}}
+
+	err := s.Conn.SetEgressRules("spam", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "spam-egress-deny")
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[2].Name, gc.Equals, google.EgressFirewallName("spam", "10.0.0.0/8"))
+}
+
 func (s *connSuite) TestNetworks(c *gc.C) {
 	s.FakeConn.Networks = []*compute.Network{{
 		Name: "kamar-taj",
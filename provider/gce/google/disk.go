@@ -5,6 +5,7 @@ package google
 
 import (
 	"path"
+	"time"
 
 	"github.com/juju/errors"
 	"google.golang.org/api/compute/v1"
@@ -88,6 +89,9 @@ type DiskSpec struct {
 	// Labels holds labels/metadata for the disk. Labels are used for
 	// storing volume resource tags.
 	Labels map[string]string
+	// SourceSnapshot is the name of the snapshot from which the disk
+	// should be created, if any. (detached only)
+	SourceSnapshot string
 }
 
 // TooSmall checks the spec's size hint and indicates whether or not
@@ -149,13 +153,17 @@ func (ds *DiskSpec) newDetached() (*compute.Disk, error) {
 	if ds.PersistentDiskType == DiskLocalSSD {
 		return nil, errors.New("cannot create local ssd disks detached")
 	}
-	return &compute.Disk{
+	disk := &compute.Disk{
 		Name:        ds.Name,
 		SizeGb:      int64(ds.SizeGB()),
 		SourceImage: ds.ImageURL,
 		Type:        string(ds.PersistentDiskType),
 		Labels:      ds.Labels,
-	}, nil
+	}
+	if ds.SourceSnapshot != "" {
+		disk.SourceSnapshot = "global/snapshots/" + ds.SourceSnapshot
+	}
+	return disk, nil
 }
 
 // AttachedDisk represents a disk that is attached to an instance.
@@ -232,3 +240,41 @@ func NewDisk(cd *compute.Disk) *Disk {
 	}
 	return d
 }
+
+// SnapshotSpec holds all the data needed to request a new disk
+// snapshot on GCE.
+type SnapshotSpec struct {
+	// Name is the name of the snapshot. It must comply with RFC1035,
+	// in the same way as disk names.
+	Name string
+	// Description is an optional description of the snapshot.
+	Description string
+	// Labels holds labels/metadata for the snapshot.
+	Labels map[string]string
+}
+
+// Snapshot represents a gce disk snapshot.
+type Snapshot struct {
+	// Name is the unique name of the snapshot.
+	Name string
+	// SourceDisk is the name of the disk the snapshot was taken from.
+	SourceDisk string
+	// Size is the size of the source disk, in mebibytes.
+	Size uint64
+	// Created is the time at which the snapshot was created.
+	Created time.Time
+	// Labels holds labels/metadata for the snapshot.
+	Labels map[string]string
+}
+
+// NewSnapshot returns a Snapshot corresponding to the given compute.Snapshot.
+func NewSnapshot(cs *compute.Snapshot) *Snapshot {
+	created, _ := time.Parse(time.RFC3339, cs.CreationTimestamp)
+	return &Snapshot{
+		Name:       cs.Name,
+		SourceDisk: path.Base(cs.SourceDisk),
+		Size:       gibToMib(cs.DiskSizeGb),
+		Created:    created,
+		Labels:     cs.Labels,
+	}
+}
@@ -19,6 +19,8 @@ var (
 	ExtractAddresses    = extractAddresses
 	NewRuleSetFromRules = newRuleSetFromRules
 	MatchesPrefix       = matchesPrefix
+
+	EgressFirewallName = egressFirewallName
 )
 
 func SetRawConn(conn *Connection, svc service) {
@@ -293,6 +293,44 @@ func (rc *rawConn) SetDiskLabels(project, zone, id, labelFingerprint string, lab
 	return errors.Trace(err)
 }
 
+func (rc *rawConn) ResizeDisk(project, zone, id string, sizeGb int64) error {
+	call := rc.Disks.Resize(project, zone, id, &compute.DisksResizeRequest{
+		SizeGb: sizeGb,
+	})
+	op, err := call.Do()
+	if err != nil {
+		return errors.Annotatef(err, "could not resize disk %q", id)
+	}
+	return errors.Trace(rc.waitOperation(project, op, longRetryStrategy, logOperationErrors))
+}
+
+func (rc *rawConn) CreateSnapshot(project, zone, disk string, spec *compute.Snapshot) error {
+	call := rc.Disks.CreateSnapshot(project, zone, disk, spec)
+	op, err := call.Do()
+	if err != nil {
+		return errors.Annotatef(err, "could not snapshot disk %q", disk)
+	}
+	return errors.Trace(rc.waitOperation(project, op, longRetryStrategy, logOperationErrors))
+}
+
+func (rc *rawConn) GetSnapshot(project, name string) (*compute.Snapshot, error) {
+	call := rc.Snapshots.Get(project, name)
+	snapshot, err := call.Do()
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get snapshot %q in project %q", name, project)
+	}
+	return snapshot, nil
+}
+
+func (rc *rawConn) RemoveSnapshot(project, name string) error {
+	call := rc.Snapshots.Delete(project, name)
+	op, err := call.Do()
+	if err != nil {
+		return errors.Annotatef(err, "could not delete snapshot %q", name)
+	}
+	return errors.Trace(rc.waitOperation(project, op, longRetryStrategy, returnNotFoundOperationErrors))
+}
+
 func (rc *rawConn) AttachDisk(project, zone, instanceId string, disk *compute.AttachedDisk) error {
 	call := rc.Instances.AttachDisk(project, zone, instanceId, disk)
 	_, err := call.Do() // Perhaps return something from the Op
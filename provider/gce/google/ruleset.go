@@ -60,6 +60,10 @@ func newRuleSetFromFirewalls(firewalls ...*compute.Firewall) (ruleSet, error) {
 }
 
 func (rs ruleSet) addFirewall(fw *compute.Firewall) error {
+	if fw.Direction == egressDirection {
+		// Egress firewalls are managed separately; see SetEgressRules.
+		return nil
+	}
 	if len(fw.TargetTags) != 1 {
 		return errors.Errorf(
 			"firewall rule %q has %d targets (expected 1): %#v",
@@ -104,3 +104,27 @@ func (inst *environInstance) IngressRules(ctx context.ProviderCallContext, machi
 	ports, err := inst.env.gce.IngressRules(name)
 	return ports, google.HandleCredentialError(errors.Trace(err), ctx)
 }
+
+// SetEgressRules restricts outbound traffic from the instance, which
+// should have been started with the given machine id, to the given
+// rules. If there are no rules, outbound traffic is unrestricted.
+func (inst *environInstance) SetEgressRules(ctx context.ProviderCallContext, machineID string, rules firewall.EgressRules) error {
+	name, err := inst.env.namespace.Hostname(machineID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = inst.env.gce.SetEgressRules(name, rules)
+	return google.HandleCredentialError(errors.Trace(err), ctx)
+}
+
+// EgressRules returns the egress rules applicable to the instance, which
+// should have been started with the given machine id. No rules are
+// returned if outbound traffic is unrestricted.
+func (inst *environInstance) EgressRules(ctx context.ProviderCallContext, machineID string) (firewall.EgressRules, error) {
+	name, err := inst.env.namespace.Hostname(machineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rules, err := inst.env.gce.EgressRules(name)
+	return rules, google.HandleCredentialError(errors.Trace(err), ctx)
+}
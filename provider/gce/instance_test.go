@@ -8,6 +8,8 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/provider/gce"
 	"github.com/juju/juju/provider/gce/google"
 )
@@ -77,6 +79,33 @@ func (s *instanceSuite) TestPorts(c *gc.C) {
 	c.Check(ports, jc.DeepEquals, s.Rules)
 }
 
+func (s *instanceSuite) TestSetEgressRulesAPI(c *gc.C) {
+	rules := firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+	}
+	err := s.Instance.SetEgressRules(s.CallCtx, "42", rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "SetEgressRules")
+	c.Check(s.FakeConn.Calls[0].FirewallName, gc.Equals, s.InstName)
+	c.Check(s.FakeConn.Calls[0].EgressRules, jc.DeepEquals, rules)
+}
+
+func (s *instanceSuite) TestEgressRules(c *gc.C) {
+	s.FakeConn.Egress = firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+	}
+
+	rules, err := s.Instance.EgressRules(s.CallCtx, "42")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(rules, jc.DeepEquals, s.FakeConn.Egress)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "EgressRules")
+	c.Check(s.FakeConn.Calls[0].FirewallName, gc.Equals, s.InstName)
+}
+
 func (s *instanceSuite) TestPortsAPI(c *gc.C) {
 	_, err := s.Instance.IngressRules(s.CallCtx, "42")
 	c.Assert(err, jc.ErrorIsNil)
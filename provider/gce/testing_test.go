@@ -490,6 +490,7 @@ type fakeConnCall struct {
 	InstanceSpec     google.InstanceSpec
 	FirewallName     string
 	Rules            firewall.IngressRules
+	EgressRules      firewall.EgressRules
 	Region           string
 	Disks            []google.DiskSpec
 	VolumeName       string
@@ -499,6 +500,8 @@ type fakeConnCall struct {
 	Value            string
 	LabelFingerprint string
 	Labels           map[string]string
+	Snapshot         google.SnapshotSpec
+	SizeGb           uint64
 }
 
 type fakeConn struct {
@@ -507,6 +510,7 @@ type fakeConn struct {
 	Inst      *google.Instance
 	Insts     []google.Instance
 	Rules     firewall.IngressRules
+	Egress    firewall.EgressRules
 	Zones     []google.AvailabilityZone
 	Subnets   []*compute.Subnetwork
 	Networks_ []*compute.Network
@@ -515,6 +519,7 @@ type fakeConn struct {
 	GoogleDisk    *google.Disk
 	AttachedDisk  *google.AttachedDisk
 	AttachedDisks []*google.AttachedDisk
+	Snapshot      *google.Snapshot
 
 	Err        error
 	FailOnCall int
@@ -605,6 +610,23 @@ func (fc *fakeConn) ClosePorts(fwname string, rules firewall.IngressRules) error
 	return fc.err()
 }
 
+func (fc *fakeConn) EgressRules(fwname string) (firewall.EgressRules, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "EgressRules",
+		FirewallName: fwname,
+	})
+	return fc.Egress, fc.err()
+}
+
+func (fc *fakeConn) SetEgressRules(fwname string, rules firewall.EgressRules) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "SetEgressRules",
+		FirewallName: fwname,
+		EgressRules:  rules,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) AvailabilityZones(region string) ([]google.AvailabilityZone, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName: "AvailabilityZones",
@@ -673,6 +695,16 @@ func (fc *fakeConn) SetDiskLabels(zone, id, labelFingerprint string, labels map[
 	return fc.err()
 }
 
+func (fc *fakeConn) ResizeDisk(zone, id string, sizeGb uint64) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "ResizeDisk",
+		ZoneName: zone,
+		ID:       id,
+		SizeGb:   sizeGb,
+	})
+	return fc.GoogleDisk, fc.err()
+}
+
 func (fc *fakeConn) AttachDisk(zone, volumeName, instanceId string, mode google.DiskMode) (*google.AttachedDisk, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName:   "AttachDisk",
@@ -703,6 +735,24 @@ func (fc *fakeConn) InstanceDisks(zone, instanceId string) ([]*google.AttachedDi
 	return fc.AttachedDisks, fc.err()
 }
 
+func (fc *fakeConn) CreateSnapshot(zone, diskName string, spec google.SnapshotSpec) (*google.Snapshot, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:   "CreateSnapshot",
+		ZoneName:   zone,
+		VolumeName: diskName,
+		Snapshot:   spec,
+	})
+	return fc.Snapshot, fc.err()
+}
+
+func (fc *fakeConn) RemoveSnapshot(name string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "RemoveSnapshot",
+		ID:       name,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) WasCalled(funcName string) (bool, []fakeConnCall) {
 	var calls []fakeConnCall
 	called := false
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/environs/instances"
@@ -60,3 +61,16 @@ func (i *environInstance) Addresses(_ context.ProviderCallContext) (network.Prov
 	addrs, err := i.env.server().ContainerAddresses(i.container.Name)
 	return addrs, errors.Trace(err)
 }
+
+// SetEgressRules implements instances.InstanceEgressFirewaller, using an
+// LXD network ACL to restrict outbound traffic from the container.
+func (i *environInstance) SetEgressRules(_ context.ProviderCallContext, _ string, rules firewall.EgressRules) error {
+	err := i.env.server().SetContainerEgressRules(i.container.Name, rules)
+	return errors.Trace(err)
+}
+
+// EgressRules implements instances.InstanceEgressFirewaller.
+func (i *environInstance) EgressRules(_ context.ProviderCallContext, _ string) (firewall.EgressRules, error) {
+	rules, err := i.env.server().ContainerEgressRules(i.container.Name)
+	return rules, errors.Trace(err)
+}
@@ -8,6 +8,8 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/provider/lxd"
 )
@@ -46,3 +48,25 @@ func (s *instanceSuite) TestAddresses(c *gc.C) {
 
 	c.Check(addresses, jc.DeepEquals, s.Addresses)
 }
+
+func (s *instanceSuite) TestSetEgressRules(c *gc.C) {
+	rules := firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+	}
+	err := s.Instance.SetEgressRules(context.NewEmptyCloudCallContext(), "42", rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCall(c, 0, "SetContainerEgressRules", "spam", rules)
+}
+
+func (s *instanceSuite) TestEgressRules(c *gc.C) {
+	s.Client.EgressRules = firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+	}
+
+	rules, err := s.Instance.EgressRules(context.NewEmptyCloudCallContext(), "42")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(rules, jc.DeepEquals, s.Client.EgressRules)
+	s.Stub.CheckCall(c, 0, "ContainerEgressRules", "spam")
+}
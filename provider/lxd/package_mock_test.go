@@ -12,6 +12,7 @@ import (
 	lxd0 "github.com/juju/juju/container/lxd"
 	base "github.com/juju/juju/core/base"
 	network "github.com/juju/juju/core/network"
+	firewall "github.com/juju/juju/core/network/firewall"
 	environs "github.com/juju/juju/environs"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -69,6 +70,21 @@ func (mr *MockServerMockRecorder) ContainerAddresses(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerAddresses", reflect.TypeOf((*MockServer)(nil).ContainerAddresses), arg0)
 }
 
+// ContainerEgressRules mocks base method.
+func (m *MockServer) ContainerEgressRules(arg0 string) (firewall.EgressRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainerEgressRules", arg0)
+	ret0, _ := ret[0].(firewall.EgressRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerEgressRules indicates an expected call of ContainerEgressRules.
+func (mr *MockServerMockRecorder) ContainerEgressRules(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerEgressRules", reflect.TypeOf((*MockServer)(nil).ContainerEgressRules), arg0)
+}
+
 // CreateCertificate mocks base method.
 func (m *MockServer) CreateCertificate(arg0 api.CertificatesPost) error {
 	m.ctrl.T.Helper()
@@ -660,6 +676,20 @@ func (mr *MockServerMockRecorder) ServerVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerVersion", reflect.TypeOf((*MockServer)(nil).ServerVersion))
 }
 
+// SetContainerEgressRules mocks base method.
+func (m *MockServer) SetContainerEgressRules(arg0 string, arg1 firewall.EgressRules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetContainerEgressRules", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetContainerEgressRules indicates an expected call of SetContainerEgressRules.
+func (mr *MockServerMockRecorder) SetContainerEgressRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContainerEgressRules", reflect.TypeOf((*MockServer)(nil).SetContainerEgressRules), arg0, arg1)
+}
+
 // StorageSupported mocks base method.
 func (m *MockServer) StorageSupported() bool {
 	m.ctrl.T.Helper()
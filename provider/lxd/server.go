@@ -27,6 +27,7 @@ import (
 	corebase "github.com/juju/juju/core/base"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/environs"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
 	"github.com/juju/juju/utils/proxy"
@@ -86,6 +87,8 @@ type Server interface {
 	GetNetworkState(name string) (*lxdapi.NetworkState, error)
 	GetInstance(name string) (*lxdapi.Instance, string, error)
 	GetInstanceState(name string) (*lxdapi.InstanceState, string, error)
+	ContainerEgressRules(name string) (firewall.EgressRules, error)
+	SetContainerEgressRules(name string, rules firewall.EgressRules) error
 
 	// UseProject ensures that this server will use the input project.
 	// See: https://documentation.ubuntu.com/lxd/en/latest/projects.
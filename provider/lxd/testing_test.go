@@ -400,6 +400,7 @@ type StubClient struct {
 	ServerVer          string
 	NetworkNames       []string
 	NetworkState       map[string]api.NetworkState
+	EgressRules        firewall.EgressRules
 }
 
 func (conn *StubClient) FilterContainers(prefix string, statuses ...string) ([]lxd.Container, error) {
@@ -621,6 +622,20 @@ func (conn *StubClient) ContainerAddresses(name string) ([]network.ProviderAddre
 	}, nil
 }
 
+func (conn *StubClient) ContainerEgressRules(name string) (firewall.EgressRules, error) {
+	conn.AddCall("ContainerEgressRules", name)
+	if err := conn.NextErr(); err != nil {
+		return nil, err
+	}
+
+	return conn.EgressRules, nil
+}
+
+func (conn *StubClient) SetContainerEgressRules(name string, rules firewall.EgressRules) error {
+	conn.AddCall("SetContainerEgressRules", name, rules)
+	return conn.NextErr()
+}
+
 func (conn *StubClient) RemoveContainer(name string) error {
 	conn.AddCall("RemoveContainer", name)
 	return conn.NextErr()
@@ -4,9 +4,14 @@
 package openstack
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math"
+	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,10 +40,18 @@ const (
 	// you'd like Cinder to automatically assign a mount point.
 	autoAssignedMountPoint = ""
 
-	volumeStatusAvailable = "available"
-	volumeStatusDeleting  = "deleting"
-	volumeStatusError     = "error"
-	volumeStatusInUse     = "in-use"
+	volumeStatusAvailable      = "available"
+	volumeStatusDeleting       = "deleting"
+	volumeStatusError          = "error"
+	volumeStatusErrorExtending = "error_extending"
+	volumeStatusInUse          = "in-use"
+
+	snapshotStatusAvailable = "available"
+	snapshotStatusError     = "error"
+
+	// cinderTimestampLayout is the layout of the timestamps
+	// that Cinder reports, which carry no time zone.
+	cinderTimestampLayout = "2006-01-02T15:04:05.000000"
 )
 
 var cinderConfigFields = schema.Fields{
@@ -122,6 +135,7 @@ var newOpenstackStorage = func(env *Environ) (OpenstackStorage, error) {
 	// TODO (stickupkid): Move this to the ClientFactory.
 	// We shouldn't have another wrapper around an existing client.
 	cinderCl := cinderClient{cinder.Basic(env.volumeURL, client.TenantId(), client.Token)}
+	handleRequest := cinder.SetAuthHeaderFn(client.Token, http.DefaultClient.Do)
 
 	cloudSpec := env.cloudUnlocked
 	if len(cloudSpec.CACertificates) > 0 {
@@ -131,11 +145,15 @@ var newOpenstackStorage = func(env *Environ) (OpenstackStorage, error) {
 			client.Token,
 			tlsConfig(cloudSpec.CACertificates)),
 		}
+		handleRequest = cinder.AuthHeaderTSLConfigDoRequestFn(
+			client.Token, tlsConfig(cloudSpec.CACertificates),
+		)
 	}
 
 	return &openstackStorageAdapter{
 		cinderCl,
 		novaClient{env.novaUnlocked},
+		newCinderVolumeActions(env.volumeURL, handleRequest),
 	}, nil
 }
 
@@ -269,6 +287,7 @@ func (s *cinderVolumeSource) createVolume(
 		VolumeType:       cinderConfig.volumeType,
 		AvailabilityZone: az,
 		Metadata:         metadata,
+		SnapshotId:       arg.SnapshotId,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -652,6 +671,155 @@ func detachVolumes(ctx context.ProviderCallContext, storageAdapter OpenstackStor
 	return results
 }
 
+var _ storage.VolumeSnapshotter = (*cinderVolumeSource)(nil)
+
+// CreateVolumeSnapshots implements storage.VolumeSnapshotter.
+func (s *cinderVolumeSource) CreateVolumeSnapshots(
+	ctx context.ProviderCallContext, args []storage.VolumeSnapshotParams,
+) ([]storage.CreateVolumeSnapshotsResult, error) {
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshot, err := s.createVolumeSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Trace(err)
+			if denied := common.MaybeHandleCredentialError(IsAuthorisationFailure, err, ctx); denied {
+				break
+			}
+			continue
+		}
+		results[i].Snapshot = snapshot
+	}
+	return results, nil
+}
+
+func (s *cinderVolumeSource) createVolumeSnapshot(arg storage.VolumeSnapshotParams) (*storage.VolumeSnapshot, error) {
+	cinderSnapshot, err := s.storageAdapter.CreateSnapshot(cinder.CreateSnapshotSnapshotParams{
+		// The volume will usually be attached, so
+		// the snapshot must be forced.
+		Force:       true,
+		Name:        resourceName(s.namespace, s.envName, "snapshot-"+strings.Replace(arg.Id, "/", "-", -1)),
+		Description: fmt.Sprintf("juju volume snapshot %s", arg.Id),
+		VolumeId:    arg.VolumeId,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Wait for the snapshot to become available, so we
+	// know that it can be used to create new volumes.
+	snapshotId := cinderSnapshot.ID
+	cinderSnapshot, err = waitSnapshot(s.storageAdapter, snapshotId, func(v *cinder.Snapshot) (bool, error) {
+		switch v.Status {
+		case snapshotStatusAvailable:
+			return true, nil
+		case snapshotStatusError:
+			return false, errors.New("snapshot is in error state")
+		}
+		return false, nil
+	})
+	if err != nil {
+		if err := s.storageAdapter.DeleteSnapshot(snapshotId); err != nil {
+			logger.Warningf("destroying snapshot %s: %s", snapshotId, err)
+		}
+		return nil, errors.Errorf("waiting for snapshot to become available: %s", err)
+	}
+	logger.Debugf("created snapshot: %+v", cinderSnapshot)
+	created, _ := time.Parse(cinderTimestampLayout, cinderSnapshot.CreatedAt)
+	return &storage.VolumeSnapshot{
+		Id: arg.Id,
+		VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+			SnapshotId: snapshotId,
+			Size:       uint64(cinderSnapshot.Size * 1024),
+			Created:    created,
+		},
+	}, nil
+}
+
+func waitSnapshot(
+	storageAdapter OpenstackStorage,
+	snapshotId string,
+	pred func(*cinder.Snapshot) (bool, error),
+) (*cinder.Snapshot, error) {
+	for a := cinderAttempt.Start(); a.Next(); {
+		snapshot, err := storageAdapter.GetSnapshot(snapshotId)
+		if err != nil {
+			return nil, errors.Annotate(err, "getting snapshot")
+		}
+		ok, err := pred(snapshot)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ok {
+			return snapshot, nil
+		}
+	}
+	return nil, errors.New("timed out")
+}
+
+// DestroyVolumeSnapshots implements storage.VolumeSnapshotter.
+func (s *cinderVolumeSource) DestroyVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	return foreachVolume(ctx, s.storageAdapter, snapshotIds, destroyVolumeSnapshot), nil
+}
+
+func destroyVolumeSnapshot(ctx context.ProviderCallContext, storageAdapter OpenstackStorage, snapshotId string) error {
+	logger.Debugf("destroying snapshot %q", snapshotId)
+	if err := storageAdapter.DeleteSnapshot(snapshotId); err != nil && !errors.IsNotFound(err) {
+		handleCredentialError(err, ctx)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+var _ storage.VolumeResizer = (*cinderVolumeSource)(nil)
+
+// ResizeVolumes implements storage.VolumeResizer.
+//
+// Cinder volume sizes are whole GiB, so the new size is rounded up.
+// Extending in-use volumes requires Cinder API microversion 3.42 or
+// later, and a volume driver that supports it.
+func (s *cinderVolumeSource) ResizeVolumes(
+	ctx context.ProviderCallContext, args []storage.VolumeResizeParams,
+) ([]storage.ResizeVolumesResult, error) {
+	results := make([]storage.ResizeVolumesResult, len(args))
+	for i, arg := range args {
+		info, err := s.resizeVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Trace(err)
+			if denied := common.MaybeHandleCredentialError(IsAuthorisationFailure, err, ctx); denied {
+				break
+			}
+			continue
+		}
+		results[i].Info = info
+	}
+	return results, nil
+}
+
+func (s *cinderVolumeSource) resizeVolume(arg storage.VolumeResizeParams) (*storage.VolumeInfo, error) {
+	newSize := int(math.Ceil(float64(arg.Size) / 1024))
+	logger.Debugf("extending volume %q to %dGiB", arg.VolumeId, newSize)
+	if err := s.storageAdapter.ExtendVolume(arg.VolumeId, newSize); err != nil {
+		return nil, errors.Annotatef(err, "extending volume %q", arg.VolumeId)
+	}
+
+	// Wait for the volume to finish extending, which
+	// Cinder reports by restoring the previous status.
+	volume, err := waitVolume(s.storageAdapter, arg.VolumeId, func(v *cinder.Volume) (bool, error) {
+		switch v.Status {
+		case volumeStatusAvailable, volumeStatusInUse:
+			return v.Size >= newSize, nil
+		case volumeStatusError, volumeStatusErrorExtending:
+			return false, errors.Errorf("volume is in %q state", v.Status)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "waiting for volume to be extended")
+	}
+	info := cinderToJujuVolumeInfo(volume)
+	return &info, nil
+}
+
 func cinderToJujuVolumeInfos(volumes []cinder.Volume) []storage.VolumeInfo {
 	out := make([]storage.VolumeInfo, len(volumes))
 	for i, v := range volumes {
@@ -701,6 +869,10 @@ type OpenstackStorage interface {
 	ListVolumeAttachments(serverId string) ([]nova.VolumeAttachment, error)
 	SetVolumeMetadata(volumeId string, metadata map[string]string) (map[string]string, error)
 	ListVolumeAvailabilityZones() ([]cinder.AvailabilityZone, error)
+	CreateSnapshot(cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error)
+	GetSnapshot(snapshotId string) (*cinder.Snapshot, error)
+	DeleteSnapshot(snapshotId string) error
+	ExtendVolume(volumeId string, newSizeGiB int) error
 }
 
 type endpointResolver interface {
@@ -740,6 +912,7 @@ func getVolumeEndpointURL(client endpointResolver, region string) (*url.URL, err
 type openstackStorageAdapter struct {
 	cinderClient
 	novaClient
+	volumeActions *cinderVolumeActions
 }
 
 type cinderClient struct {
@@ -810,3 +983,94 @@ func (ga *openstackStorageAdapter) DetachVolume(serverId, attachmentId string) e
 	}
 	return nil
 }
+
+// CreateSnapshot is part of the OpenstackStorage interface.
+func (ga *openstackStorageAdapter) CreateSnapshot(args cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error) {
+	resp, err := ga.cinderClient.CreateSnapshot(args)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Snapshot, nil
+}
+
+// GetSnapshot is part of the OpenstackStorage interface.
+func (ga *openstackStorageAdapter) GetSnapshot(snapshotId string) (*cinder.Snapshot, error) {
+	resp, err := ga.cinderClient.GetSnapshot(snapshotId)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, errors.NotFoundf("snapshot %q", snapshotId)
+		}
+		return nil, err
+	}
+	return &resp.Snapshot, nil
+}
+
+// DeleteSnapshot is part of the OpenstackStorage interface.
+func (ga *openstackStorageAdapter) DeleteSnapshot(snapshotId string) error {
+	if err := ga.cinderClient.DeleteSnapshot(snapshotId); err != nil {
+		if IsNotFoundError(err) {
+			return errors.NotFoundf("snapshot %q", snapshotId)
+		}
+		return err
+	}
+	return nil
+}
+
+// ExtendVolume is part of the OpenstackStorage interface.
+func (ga *openstackStorageAdapter) ExtendVolume(volumeId string, newSizeGiB int) error {
+	return ga.volumeActions.extendVolume(volumeId, newSizeGiB)
+}
+
+// extendVolumeMicroversion is the Cinder API microversion that
+// first supports extending volumes that are attached to servers.
+const extendVolumeMicroversion = "volume 3.42"
+
+// cinderVolumeActions performs Cinder volume actions that the goose
+// client does not support.
+type cinderVolumeActions struct {
+	endpoint      *url.URL
+	handleRequest cinder.RequestHandlerFn
+}
+
+func newCinderVolumeActions(endpoint *url.URL, handleRequest cinder.RequestHandlerFn) *cinderVolumeActions {
+	// Ensure the endpoint has a trailing slash on the path, as the
+	// goose client does, so that relative references resolve to it.
+	if endpoint != nil && !strings.HasSuffix(endpoint.Path, "/") {
+		changedEndpoint := *endpoint
+		changedEndpoint.Path += "/"
+		endpoint = &changedEndpoint
+	}
+	return &cinderVolumeActions{endpoint: endpoint, handleRequest: handleRequest}
+}
+
+// extendVolume requests that the volume with the specified ID be
+// extended to the specified size in GiB.
+func (a *cinderVolumeActions) extendVolume(volumeId string, newSizeGiB int) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"os-extend": map[string]int{"new_size": newSizeGiB},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	urlPath := url.URL{Path: fmt.Sprintf("volumes/%s/action", volumeId)}
+	req, err := http.NewRequest("POST", a.endpoint.ResolveReference(&urlPath).String(), bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("OpenStack-API-Version", extendVolumeMicroversion)
+
+	resp, err := a.handleRequest(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return nil
+	case http.StatusNotFound:
+		return errors.NotFoundf("volume %q", volumeId)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	return errors.Errorf("invalid status (%d): %s", resp.StatusCode, respBody)
+}
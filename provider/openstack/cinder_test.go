@@ -5,6 +5,7 @@ package openstack_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-goose/goose/v5/cinder"
 	gooseerrors "github.com/go-goose/goose/v5/errors"
@@ -563,6 +564,144 @@ func (s *cinderVolumeSourceSuite) TestDestroyVolumesAttached(c *gc.C) {
 	}})
 }
 
+func (s *cinderVolumeSourceSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	s.PatchValue(openstack.CinderAttempt, utils.AttemptStrategy{Min: 3})
+
+	statuses := []string{"creating", "available"}
+	mockAdapter := &mockAdapter{
+		createSnapshot: func(args cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error) {
+			c.Assert(args, jc.DeepEquals, cinder.CreateSnapshotSnapshotParams{
+				Force:       true,
+				Name:        "juju-testmodel-snapshot-123@4",
+				Description: "juju volume snapshot 123@4",
+				VolumeId:    mockVolId,
+			})
+			return &cinder.Snapshot{ID: "snap-1"}, nil
+		},
+		getSnapshot: func(snapshotId string) (*cinder.Snapshot, error) {
+			status := statuses[0]
+			statuses = statuses[1:]
+			return &cinder.Snapshot{
+				ID:        snapshotId,
+				Size:      3,
+				Status:    status,
+				CreatedAt: "2023-04-01T12:00:00.000000",
+			}, nil
+		},
+	}
+
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	results, err := volSource.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Id:       "123@4",
+		Volume:   mockVolumeTag,
+		VolumeId: mockVolId,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Snapshot, jc.DeepEquals, &storage.VolumeSnapshot{
+		Id: "123@4",
+		VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+			SnapshotId: "snap-1",
+			Size:       3 * 1024,
+			Created:    time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC),
+		},
+	})
+	c.Assert(statuses, gc.HasLen, 0)
+}
+
+func (s *cinderVolumeSourceSuite) TestCreateVolumeSnapshotsError(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		createSnapshot: func(args cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error) {
+			return &cinder.Snapshot{ID: "snap-1"}, nil
+		},
+		getSnapshot: func(snapshotId string) (*cinder.Snapshot, error) {
+			return &cinder.Snapshot{ID: snapshotId, Status: "error"}, nil
+		},
+	}
+
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	results, err := volSource.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Id:       "123@4",
+		Volume:   mockVolumeTag,
+		VolumeId: mockVolId,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, "waiting for snapshot to become available: snapshot is in error state")
+	mockAdapter.CheckCallNames(c, "CreateSnapshot", "GetSnapshot", "DeleteSnapshot")
+}
+
+func (s *cinderVolumeSourceSuite) TestDestroyVolumeSnapshots(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		deleteSnapshot: func(snapshotId string) error {
+			if snapshotId == "snap-2" {
+				return errors.NotFoundf("snapshot %q", snapshotId)
+			}
+			return nil
+		},
+	}
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	errs, err := volSource.(storage.VolumeSnapshotter).DestroyVolumeSnapshots(s.callCtx, []string{"snap-1", "snap-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil})
+	mockAdapter.CheckCallNames(c, "DeleteSnapshot", "DeleteSnapshot")
+}
+
+func (s *cinderVolumeSourceSuite) TestResizeVolumes(c *gc.C) {
+	s.PatchValue(openstack.CinderAttempt, utils.AttemptStrategy{Min: 3})
+
+	statuses := []string{"extending", "in-use"}
+	mockAdapter := &mockAdapter{
+		getVolume: func(volumeId string) (*cinder.Volume, error) {
+			status := statuses[0]
+			statuses = statuses[1:]
+			return &cinder.Volume{ID: volumeId, Size: 3, Status: status}, nil
+		},
+	}
+
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	results, err := volSource.(storage.VolumeResizer).ResizeVolumes(s.callCtx, []storage.VolumeResizeParams{{
+		Tag:      mockVolumeTag,
+		VolumeId: mockVolId,
+		Size:     2*1024 + 1,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Info, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId:   mockVolId,
+		Size:       3 * 1024,
+		Persistent: true,
+	})
+	c.Assert(statuses, gc.HasLen, 0)
+	mockAdapter.CheckCalls(c, []gitjujutesting.StubCall{{
+		"ExtendVolume", []interface{}{mockVolId, 3},
+	}, {
+		"GetVolume", []interface{}{mockVolId},
+	}, {
+		"GetVolume", []interface{}{mockVolId},
+	}})
+}
+
+func (s *cinderVolumeSourceSuite) TestResizeVolumesError(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		getVolume: func(volumeId string) (*cinder.Volume, error) {
+			return &cinder.Volume{ID: volumeId, Size: 1, Status: "error_extending"}, nil
+		},
+	}
+
+	volSource := openstack.NewCinderVolumeSource(mockAdapter, s.env)
+	results, err := volSource.(storage.VolumeResizer).ResizeVolumes(s.callCtx, []storage.VolumeResizeParams{{
+		Tag:      mockVolumeTag,
+		VolumeId: mockVolId,
+		Size:     2 * 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `waiting for volume to be extended: volume is in "error_extending" state`)
+}
+
 func (s *cinderVolumeSourceSuite) TestDestroyVolumesInvalidCredential(c *gc.C) {
 	c.Assert(s.invalidCredential, jc.IsFalse)
 	mockAdapter := &mockAdapter{
@@ -850,6 +989,10 @@ type mockAdapter struct {
 	listVolumeAttachments func(string) ([]nova.VolumeAttachment, error)
 	setVolumeMetadata     func(string, map[string]string) (map[string]string, error)
 	listAvailabilityZones func() ([]cinder.AvailabilityZone, error)
+	createSnapshot        func(cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error)
+	getSnapshot           func(string) (*cinder.Snapshot, error)
+	deleteSnapshot        func(string) error
+	extendVolume          func(string, int) error
 }
 
 func (ma *mockAdapter) GetVolume(volumeId string) (*cinder.Volume, error) {
@@ -927,6 +1070,41 @@ func (ma *mockAdapter) ListVolumeAvailabilityZones() ([]cinder.AvailabilityZone,
 	return nil, gooseerrors.NewNotImplementedf(nil, nil, "ListAvailabilityZones")
 }
 
+func (ma *mockAdapter) CreateSnapshot(args cinder.CreateSnapshotSnapshotParams) (*cinder.Snapshot, error) {
+	ma.MethodCall(ma, "CreateSnapshot", args)
+	if ma.createSnapshot != nil {
+		return ma.createSnapshot(args)
+	}
+	return nil, errors.NotImplementedf("CreateSnapshot")
+}
+
+func (ma *mockAdapter) GetSnapshot(snapshotId string) (*cinder.Snapshot, error) {
+	ma.MethodCall(ma, "GetSnapshot", snapshotId)
+	if ma.getSnapshot != nil {
+		return ma.getSnapshot(snapshotId)
+	}
+	return &cinder.Snapshot{
+		ID:     snapshotId,
+		Status: "available",
+	}, nil
+}
+
+func (ma *mockAdapter) DeleteSnapshot(snapshotId string) error {
+	ma.MethodCall(ma, "DeleteSnapshot", snapshotId)
+	if ma.deleteSnapshot != nil {
+		return ma.deleteSnapshot(snapshotId)
+	}
+	return nil
+}
+
+func (ma *mockAdapter) ExtendVolume(volumeId string, newSizeGiB int) error {
+	ma.MethodCall(ma, "ExtendVolume", volumeId, newSizeGiB)
+	if ma.extendVolume != nil {
+		return ma.extendVolume(volumeId, newSizeGiB)
+	}
+	return nil
+}
+
 type testEndpointResolver struct {
 	authenticated   bool
 	regionEndpoints map[string]identity.ServiceURLs
@@ -106,6 +106,14 @@ type Firewaller interface {
 
 	// InstanceIngressRules returns the ingress rules applied to the specified  instance.
 	InstanceIngressRules(ctx context.ProviderCallContext, inst instances.Instance, machineID string) (firewall.IngressRules, error)
+
+	// SetInstanceEgressRules restricts outbound traffic from the specified
+	// instance to the given rules, or lifts the restriction if there are none.
+	SetInstanceEgressRules(ctx context.ProviderCallContext, inst instances.Instance, machineID string, rules firewall.EgressRules) error
+
+	// InstanceEgressRules returns the egress rules applied to the specified
+	// instance. No rules are returned when outbound traffic is unrestricted.
+	InstanceEgressRules(ctx context.ProviderCallContext, inst instances.Instance, machineID string) (firewall.EgressRules, error)
 }
 
 type firewallerFactory struct{}
@@ -652,6 +660,55 @@ func (c *neutronFirewaller) InstanceIngressRules(ctx context.ProviderCallContext
 	return rules, err
 }
 
+// SetInstanceEgressRules implements Firewaller interface.
+func (c *neutronFirewaller) SetInstanceEgressRules(ctx context.ProviderCallContext, inst instances.Instance, machineID string, rules firewall.EgressRules) error {
+	if c.environ.Config().FirewallMode() != config.FwInstance {
+		return errors.Errorf("invalid firewall mode %q for setting egress rules on instance",
+			c.environ.Config().FirewallMode())
+	}
+	// For bug 1680787
+	// No security groups exist if the network used to boot the instance has
+	// PortSecurityEnabled set to false, so there's nothing to restrict.
+	if securityGroups := inst.(*openstackInstance).getServerDetail().Groups; securityGroups == nil {
+		return nil
+	}
+	nameRegexp := c.machineGroupRegexp(machineID)
+	if err := c.setEgressRulesInGroup(ctx, nameRegexp, rules); err != nil {
+		handleCredentialError(err, ctx)
+		return errors.Trace(err)
+	}
+	logger.Infof("set egress rules in security group %s-%s: %v", c.environ.Config().UUID(), machineID, rules)
+	return nil
+}
+
+// InstanceEgressRules implements Firewaller interface.
+func (c *neutronFirewaller) InstanceEgressRules(ctx context.ProviderCallContext, inst instances.Instance, machineID string) (firewall.EgressRules, error) {
+	if c.environ.Config().FirewallMode() != config.FwInstance {
+		return nil, errors.Errorf("invalid firewall mode %q for retrieving egress rules from instance",
+			c.environ.Config().FirewallMode())
+	}
+	// For bug 1680787
+	// No security groups exist if the network used to boot the instance has
+	// PortSecurityEnabled set to false.
+	if securityGroups := inst.(*openstackInstance).getServerDetail().Groups; securityGroups == nil {
+		return nil, nil
+	}
+	group, err := c.matchingGroup(ctx, c.machineGroupRegexp(machineID))
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return nil, errors.Trace(err)
+	}
+	current, allowAll := egressRulesInGroup(group)
+	if len(allowAll) > 0 {
+		return nil, nil
+	}
+	var rules firewall.EgressRules
+	for rule := range current {
+		rules = append(rules, rule)
+	}
+	return rules.Union(nil), nil
+}
+
 // Matching a security group by name only works if each name is unqiue.  Neutron
 // security groups are not required to have unique names.  Juju constructs unique
 // names, but there are frequently multiple matches to 'default'
@@ -831,6 +888,132 @@ func (c *neutronFirewaller) ingressRulesInGroup(ctx context.ProviderCallContext,
 	return rules, nil
 }
 
+// egressRulesInGroup returns the egress rules in the group, mapped to the
+// ids of the security group rules that implement them, along with the ids
+// of any rules allowing all outbound traffic, such as the ones Neutron
+// adds to new groups.
+func egressRulesInGroup(group neutron.SecurityGroupV2) (map[firewall.EgressRule][]string, []string) {
+	rules := make(map[firewall.EgressRule][]string)
+	var allowAll []string
+	for _, p := range group.Rules {
+		if p.Direction != "egress" || p.RemoteGroupID != "" {
+			continue
+		}
+		remotePrefix := p.RemoteIPPrefix
+		if remotePrefix == "" {
+			remotePrefix = firewall.AllNetworksIPV4CIDR
+			if p.EthernetType == "IPv6" {
+				remotePrefix = firewall.AllNetworksIPV6CIDR
+			}
+		}
+		if p.IPProtocol == nil {
+			if remotePrefix == firewall.AllNetworksIPV4CIDR || remotePrefix == firewall.AllNetworksIPV6CIDR {
+				allowAll = append(allowAll, p.Id)
+			}
+			continue
+		}
+		portRange := corenetwork.PortRange{
+			Protocol: *p.IPProtocol,
+		}
+		if portRange.Protocol == "ipv6-icmp" {
+			portRange.Protocol = "icmp"
+		}
+		// NOTE: Juju firewall rule validation expects that icmp rules have port
+		// values set to -1
+		if p.PortRangeMin != nil {
+			portRange.FromPort = *p.PortRangeMin
+		} else if portRange.Protocol == "icmp" {
+			portRange.FromPort = -1
+		}
+		if p.PortRangeMax != nil {
+			portRange.ToPort = *p.PortRangeMax
+		} else if portRange.Protocol == "icmp" {
+			portRange.ToPort = -1
+		}
+		rule := firewall.NewEgressRule(remotePrefix, portRange)
+		rules[rule] = append(rules[rule], p.Id)
+	}
+	return rules, allowAll
+}
+
+// setEgressRulesInGroup restricts outbound traffic from the matching group
+// to the given rules. When there are no rules, rules allowing all outbound
+// traffic are put back in place.
+func (c *neutronFirewaller) setEgressRulesInGroup(ctx context.ProviderCallContext, nameRegExp string, rules firewall.EgressRules) error {
+	group, err := c.matchingGroup(ctx, nameRegExp)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, allowAll := egressRulesInGroup(group)
+
+	var toCreate []neutron.RuleInfoV2
+	var toDelete []string
+	if len(rules) == 0 {
+		if len(allowAll) == 0 {
+			for _, ethernetType := range []string{"IPv4", "IPv6"} {
+				toCreate = append(toCreate, neutron.RuleInfoV2{
+					Direction:     "egress",
+					ParentGroupId: group.Id,
+					EthernetType:  ethernetType,
+				})
+			}
+		}
+		for _, ids := range current {
+			toDelete = append(toDelete, ids...)
+		}
+	} else {
+		wanted := make(map[firewall.EgressRule]bool)
+		for _, rule := range rules {
+			wanted[rule] = true
+			if _, ok := current[rule]; !ok {
+				toCreate = append(toCreate, egressRuleToRuleInfo(group.Id, rule))
+			}
+		}
+		for rule, ids := range current {
+			if !wanted[rule] {
+				toDelete = append(toDelete, ids...)
+			}
+		}
+		toDelete = append(toDelete, allowAll...)
+	}
+
+	// Create rules before deleting any so that traffic which is allowed
+	// both before and after the change is never blocked.
+	neutronClient := c.environ.neutron()
+	for _, rule := range toCreate {
+		_, err := neutronClient.CreateSecurityGroupRuleV2(rule)
+		if err != nil && !gooseerrors.IsDuplicateValue(err) {
+			return fmt.Errorf("creating egress security group rule for parent group id %q using proto %q: %w", rule.ParentGroupId, rule.IPProtocol, err)
+		}
+	}
+	for _, id := range toDelete {
+		if err := neutronClient.DeleteSecurityGroupRuleV2(id); err != nil && !gooseerrors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// egressRuleToRuleInfo maps an egress rule to a neutron rule.
+func egressRuleToRuleInfo(groupId string, rule firewall.EgressRule) neutron.RuleInfoV2 {
+	ruleInfo := neutron.RuleInfoV2{
+		Direction:      "egress",
+		ParentGroupId:  groupId,
+		IPProtocol:     rule.PortRange.Protocol,
+		EthernetType:   "IPv4",
+		RemoteIPPrefix: rule.DestinationCIDR,
+	}
+	if ruleInfo.IPProtocol != "icmp" {
+		ruleInfo.PortRangeMin = rule.PortRange.FromPort
+		ruleInfo.PortRangeMax = rule.PortRange.ToPort
+	}
+	// Rules are pre-validated, so the CIDR is either IPv4 or IPv6.
+	if addrType, _ := corenetwork.CIDRAddressType(rule.DestinationCIDR); addrType == corenetwork.IPv6Address {
+		ruleInfo.EthernetType = "IPv6"
+	}
+	return ruleInfo
+}
+
 func replaceControllerUUID(oldName, controllerUUID string) (string, error) {
 	if !extractControllerRe.MatchString(oldName) {
 		return "", errors.Errorf("unexpected security group name format for %q", oldName)
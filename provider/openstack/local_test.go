@@ -3148,6 +3148,47 @@ func (s *localServerSuite) TestIPv6RuleCreationForEmptyCIDR(c *gc.C) {
 	c.Assert(rules[0].SourceCIDRs.Contains("::/0"), jc.IsTrue)
 }
 
+func (s *localServerSuite) TestInstanceEgressRules(c *gc.C) {
+	err := bootstrapEnv(c, s.env)
+	c.Assert(err, jc.ErrorIsNil)
+
+	inst, _ := testing.AssertStartInstance(c, s.env, s.callCtx, s.ControllerUUID, "100")
+	fwInst, ok := inst.(instances.InstanceEgressFirewaller)
+	c.Assert(ok, jc.IsTrue)
+
+	// Neutron allows all outbound traffic from new groups.
+	rules, err := fwInst.EgressRules(s.callCtx, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	restricted := firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("5432/tcp")),
+		firewall.NewEgressRule("2001:db8::/32", network.MustParsePortRange("443/tcp")),
+		firewall.NewEgressRule("192.168.0.0/16", network.MustParsePortRange("icmp")),
+	}
+	err = fwInst.SetEgressRules(s.callCtx, "100", restricted)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst.EgressRules(s.callCtx, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, restricted.Union(nil))
+
+	changed := firewall.EgressRules{
+		firewall.NewEgressRule("10.0.0.0/8", network.MustParsePortRange("6379/tcp")),
+	}
+	err = fwInst.SetEgressRules(s.callCtx, "100", changed)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst.EgressRules(s.callCtx, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, changed)
+
+	// Removing the restriction puts back the rules allowing everything.
+	err = fwInst.SetEgressRules(s.callCtx, "100", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst.EgressRules(s.callCtx, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
 func (s *localServerSuite) ensureAMDImages(c *gc.C) environs.Environ {
 	// Ensure amd64 tools are available, to ensure an amd64 image.
 	amd64Version := version.Binary{
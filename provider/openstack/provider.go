@@ -540,6 +540,16 @@ func (inst *openstackInstance) IngressRules(ctx context.ProviderCallContext, mac
 	return inst.e.firewaller.InstanceIngressRules(ctx, inst, machineId)
 }
 
+// SetEgressRules implements instances.InstanceEgressFirewaller.
+func (inst *openstackInstance) SetEgressRules(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error {
+	return inst.e.firewaller.SetInstanceEgressRules(ctx, inst, machineId, rules)
+}
+
+// EgressRules implements instances.InstanceEgressFirewaller.
+func (inst *openstackInstance) EgressRules(ctx context.ProviderCallContext, machineId string) (firewall.EgressRules, error) {
+	return inst.e.firewaller.InstanceEgressRules(ctx, inst, machineId)
+}
+
 func (e *Environ) ecfg() *environConfig {
 	e.ecfgMutex.Lock()
 	ecfg := e.ecfgUnlocked
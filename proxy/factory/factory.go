@@ -9,6 +9,7 @@ import (
 
 	k8sproxy "github.com/juju/juju/caas/kubernetes/provider/proxy"
 	"github.com/juju/juju/proxy"
+	sshproxy "github.com/juju/juju/proxy/ssh"
 )
 
 // Factory provides a mechanism for building various type of proxy based on
@@ -72,6 +73,15 @@ func NewDefaultFactory() (*Factory, error) {
 		return factory, err
 	}
 
+	if err := factory.Register(
+		sshproxy.ProxierTypeKey,
+		FactoryRegister{
+			ConfigFn: func() interface{} { return sshproxy.NewProxierConfig() },
+			MakerFn:  func(c interface{}) (proxy.Proxier, error) { return sshproxy.NewProxierFromRawConfig(c) },
+		}); err != nil {
+		return factory, err
+	}
+
 	return factory, nil
 }
 
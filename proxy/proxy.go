@@ -44,3 +44,15 @@ type TunnelProxier interface {
 	// Port returns the host port to connect to for tunneling connections.
 	Port() string
 }
+
+// ForwardingProxier describes a tunnel proxy that forwards connections
+// to remote addresses supplied by the client, rather than to addresses
+// held in its own configuration.
+type ForwardingProxier interface {
+	TunnelProxier
+
+	// SetRemoteAddresses sets the candidate addresses that
+	// connections are to be forwarded to. It must be called before
+	// Start.
+	SetRemoteAddresses(addrs []string)
+}
@@ -0,0 +1,408 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ssh
+
+import (
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/v3"
+	"github.com/mitchellh/mapstructure"
+	cryptossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	proxyerrors "github.com/juju/juju/proxy/errors"
+)
+
+var logger = loggo.GetLogger("juju.proxy.ssh")
+
+const (
+	// ProxierTypeKey is the unique key identifying SSH jump host
+	// proxies in configuration.
+	ProxierTypeKey = "ssh-jump-host"
+
+	defaultSSHPort = 22
+)
+
+// ProxierConfig holds the configuration for proxying connections
+// through a chain of SSH jump hosts.
+type ProxierConfig struct {
+	// JumpHosts holds the jump hosts to connect through, in the
+	// order in which they are to be traversed. Each jump host is
+	// of the form [user@]host[:port].
+	JumpHosts []string `yaml:"jump-hosts" mapstructure:"jump-hosts"`
+
+	// IdentityFile is the path to the private key used to
+	// authenticate with the jump hosts. If empty, keys are taken
+	// from the SSH agent.
+	IdentityFile string `yaml:"identity-file,omitempty" mapstructure:"identity-file,omitempty"`
+
+	// KnownHostsFile is the path to the known_hosts file used to
+	// verify the host keys of the jump hosts. If empty,
+	// ~/.ssh/known_hosts is used.
+	KnownHostsFile string `yaml:"known-hosts-file,omitempty" mapstructure:"known-hosts-file,omitempty"`
+}
+
+// Validate checks that the configuration is usable.
+func (c ProxierConfig) Validate() error {
+	if len(c.JumpHosts) == 0 {
+		return errors.NotValidf("empty jump hosts")
+	}
+	for _, jumpHost := range c.JumpHosts {
+		if _, _, err := parseJumpHost(jumpHost); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Proxier proxies connections to a remote address through a chain of
+// SSH jump hosts. Connections are accepted on a local port, and each
+// is forwarded from the last jump host in the chain to the remote
+// address.
+type Proxier struct {
+	config      ProxierConfig
+	remoteAddrs []string
+
+	clients    []*cryptossh.Client
+	listener   net.Listener
+	remoteAddr string
+	wg         sync.WaitGroup
+}
+
+// NewProxier returns a new SSH jump host proxier for the given
+// configuration.
+func NewProxier(config ProxierConfig) *Proxier {
+	return &Proxier{config: config}
+}
+
+// NewProxierConfig returns an empty configuration, for use with the
+// proxy factory.
+func NewProxierConfig() *ProxierConfig {
+	return &ProxierConfig{}
+}
+
+// NewProxierFromRawConfig returns a new SSH jump host proxier for the
+// configuration made by NewProxierConfig.
+func NewProxierFromRawConfig(rawConf interface{}) (*Proxier, error) {
+	conf, valid := rawConf.(*ProxierConfig)
+	if !valid {
+		return nil, errors.NewNotValid(nil, "config is not of type *ProxierConfig")
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewProxier(*conf), nil
+}
+
+// Config returns the proxier's configuration.
+func (p *Proxier) Config() ProxierConfig {
+	return p.config
+}
+
+// SetRemoteAddresses implements proxy.ForwardingProxier. The first
+// address reachable from the last jump host is used.
+func (p *Proxier) SetRemoteAddresses(addrs []string) {
+	p.remoteAddrs = append([]string(nil), addrs...)
+}
+
+// RemoteAddress returns the address that connections are being
+// forwarded to, once the proxy has been started.
+func (p *Proxier) RemoteAddress() string {
+	return p.remoteAddr
+}
+
+// Insecure implements proxy.Proxier. The identity and known hosts
+// files are local to this client, so they are removed, leaving
+// whoever uses the configuration to authenticate with their own SSH
+// agent and to verify host keys against their own known_hosts file.
+func (p *Proxier) Insecure() {
+	p.config.IdentityFile = ""
+	p.config.KnownHostsFile = ""
+}
+
+// RawConfig implements proxy.Proxier.
+func (p *Proxier) RawConfig() (map[string]interface{}, error) {
+	rval := map[string]interface{}{}
+	err := mapstructure.Decode(&p.config, &rval)
+	return rval, errors.Trace(err)
+}
+
+// MarshalYAML implements the yaml Marshaler interface.
+func (p *Proxier) MarshalYAML() (interface{}, error) {
+	return &p.config, nil
+}
+
+// Type implements proxy.Proxier.
+func (p *Proxier) Type() string {
+	return ProxierTypeKey
+}
+
+// Host implements proxy.TunnelProxier.
+func (p *Proxier) Host() string {
+	return "localhost"
+}
+
+// Port implements proxy.TunnelProxier.
+func (p *Proxier) Port() string {
+	if p.listener == nil {
+		return ""
+	}
+	return strconv.Itoa(p.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Start implements proxy.Proxier. It connects through each of the
+// jump hosts in turn, checks that one of the remote addresses is
+// reachable from the last of them, and then starts accepting
+// connections on a local port.
+func (p *Proxier) Start() (err error) {
+	if err := p.config.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if len(p.remoteAddrs) == 0 {
+		return errors.NotValidf("empty remote addresses")
+	}
+	defer func() {
+		if err != nil {
+			p.Stop()
+		}
+	}()
+
+	clientConfig, err := p.clientConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.connect(clientConfig); err != nil {
+		return errors.Trace(err)
+	}
+
+	client := p.clients[len(p.clients)-1]
+	for _, addr := range p.remoteAddrs {
+		conn, err := client.Dial("tcp", addr)
+		if err != nil {
+			logger.Debugf("cannot reach %q through jump hosts: %v", addr, err)
+			continue
+		}
+		_ = conn.Close()
+		p.remoteAddr = addr
+		break
+	}
+	if p.remoteAddr == "" {
+		return errors.Errorf("cannot reach any of %q through jump hosts", p.remoteAddrs)
+	}
+
+	p.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Annotate(err, "listening for proxy connections")
+	}
+	p.wg.Add(1)
+	go p.accept(client, p.listener)
+	return nil
+}
+
+// connect establishes SSH connections to each of the jump hosts,
+// connecting to each through the one before it.
+func (p *Proxier) connect(config cryptossh.ClientConfig) error {
+	for i, jumpHost := range p.config.JumpHosts {
+		hopUser, addr, _ := parseJumpHost(jumpHost)
+		hopConfig := config
+		hopConfig.User = hopUser
+
+		var client *cryptossh.Client
+		if i == 0 {
+			var err error
+			client, err = cryptossh.Dial("tcp", addr, &hopConfig)
+			if _, ok := errors.Cause(err).(*net.OpError); ok {
+				return proxyerrors.NewProxyConnectError(
+					errors.Annotatef(err, "connecting to jump host %q", jumpHost), p.Type(),
+				)
+			} else if err != nil {
+				return errors.Annotatef(err, "connecting to jump host %q", jumpHost)
+			}
+		} else {
+			conn, err := p.clients[i-1].Dial("tcp", addr)
+			if err != nil {
+				return errors.Annotatef(err, "reaching jump host %q", jumpHost)
+			}
+			c, chans, reqs, err := cryptossh.NewClientConn(conn, addr, &hopConfig)
+			if err != nil {
+				_ = conn.Close()
+				return errors.Annotatef(err, "connecting to jump host %q", jumpHost)
+			}
+			client = cryptossh.NewClient(c, chans, reqs)
+		}
+		p.clients = append(p.clients, client)
+	}
+	return nil
+}
+
+// clientConfig returns the SSH client configuration common to all of
+// the jump hosts.
+func (p *Proxier) clientConfig() (cryptossh.ClientConfig, error) {
+	var auth []cryptossh.AuthMethod
+	if p.config.IdentityFile != "" {
+		keyData, err := os.ReadFile(expandPath(p.config.IdentityFile))
+		if err != nil {
+			return cryptossh.ClientConfig{}, errors.Annotate(err, "reading identity file")
+		}
+		signer, err := cryptossh.ParsePrivateKey(keyData)
+		if err != nil {
+			return cryptossh.ClientConfig{}, errors.Annotatef(err, "parsing identity file %q", p.config.IdentityFile)
+		}
+		auth = append(auth, cryptossh.PublicKeys(signer))
+	}
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			auth = append(auth, cryptossh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		} else {
+			logger.Debugf("cannot connect to SSH agent: %v", err)
+		}
+	}
+	if len(auth) == 0 {
+		return cryptossh.ClientConfig{}, errors.New("no identity file specified, and no SSH agent available")
+	}
+
+	knownHostsFile := p.config.KnownHostsFile
+	if knownHostsFile == "" {
+		knownHostsFile = filepath.Join(utils.Home(), ".ssh", "known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(expandPath(knownHostsFile))
+	if err != nil {
+		return cryptossh.ClientConfig{}, errors.Annotate(err, "reading known hosts")
+	}
+	return cryptossh.ClientConfig{
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}, nil
+}
+
+// accept accepts connections on the local listener until it is
+// closed, forwarding each to the remote address.
+func (p *Proxier) accept(client *cryptossh.Client, listener net.Listener) {
+	defer p.wg.Done()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go p.forward(client, conn)
+	}
+}
+
+func (p *Proxier) forward(client *cryptossh.Client, local net.Conn) {
+	defer func() { _ = local.Close() }()
+	remote, err := client.Dial("tcp", p.remoteAddr)
+	if err != nil {
+		logger.Errorf("cannot forward connection to %q: %v", p.remoteAddr, err)
+		return
+	}
+	defer func() { _ = remote.Close() }()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// Stop implements proxy.Proxier.
+func (p *Proxier) Stop() {
+	if p.listener != nil {
+		_ = p.listener.Close()
+		p.wg.Wait()
+		p.listener = nil
+	}
+	for i := len(p.clients) - 1; i >= 0; i-- {
+		_ = p.clients[i].Close()
+	}
+	p.clients = nil
+}
+
+// ProxyCommand returns an OpenSSH command that connects its standard
+// input and output to the target address, of the form host:port,
+// through the jump hosts. The target may use the "%h" and "%p"
+// tokens of the ProxyCommand option.
+//
+// Rather than OpenSSH's ProxyJump option, each jump host is reached
+// through a nested ProxyCommand, so that the identity and known
+// hosts files are used for every jump host.
+func (p *Proxier) ProxyCommand(target string) []string {
+	return p.proxyCommand(p.config.JumpHosts, target)
+}
+
+func (p *Proxier) proxyCommand(jumpHosts []string, target string) []string {
+	last := jumpHosts[len(jumpHosts)-1]
+	hopUser, addr, _ := parseJumpHost(last)
+	host, port, _ := net.SplitHostPort(addr)
+
+	args := []string{"ssh"}
+	if p.config.IdentityFile != "" {
+		args = append(args, "-i", expandPath(p.config.IdentityFile))
+	}
+	if p.config.KnownHostsFile != "" {
+		args = append(args,
+			"-o", "UserKnownHostsFile "+expandPath(p.config.KnownHostsFile),
+			"-o", "StrictHostKeyChecking yes",
+		)
+	}
+	if len(jumpHosts) > 1 {
+		args = append(args, "-o", "ProxyCommand "+utils.CommandString(
+			p.proxyCommand(jumpHosts[:len(jumpHosts)-1], addr)...,
+		))
+	}
+	return append(args, "-p", port, "-W", target, hopUser+"@"+host)
+}
+
+// parseJumpHost parses a jump host of the form [user@]host[:port],
+// returning the user and the address to connect to. If the user is
+// not specified, the current user's name is used.
+func parseJumpHost(jumpHost string) (string, string, error) {
+	hostPort := jumpHost
+	var hopUser string
+	if i := strings.LastIndex(jumpHost, "@"); i >= 0 {
+		hopUser, hostPort = jumpHost[:i], jumpHost[i+1:]
+		if hopUser == "" {
+			return "", "", errors.NotValidf("jump host %q", jumpHost)
+		}
+	}
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host, port = strings.Trim(hostPort, "[]"), strconv.Itoa(defaultSSHPort)
+	}
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		return "", "", errors.NotValidf("jump host %q", jumpHost)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", "", errors.NotValidf("jump host %q port", jumpHost)
+	}
+	if hopUser == "" {
+		if u, err := user.Current(); err == nil {
+			hopUser = u.Username
+		}
+	}
+	return hopUser, net.JoinHostPort(host, port), nil
+}
+
+// expandPath expands a leading ~ in the given path to the user's home
+// directory.
+func expandPath(path string) string {
+	if expanded, err := utils.NormalizePath(path); err == nil {
+		return expanded
+	}
+	return path
+}
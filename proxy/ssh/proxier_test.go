@@ -0,0 +1,289 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ssh_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	cryptossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v3"
+
+	sshproxy "github.com/juju/juju/proxy/ssh"
+	"github.com/juju/juju/testing"
+)
+
+type proxierSuite struct {
+	testing.BaseSuite
+
+	identityFile   string
+	knownHostsFile string
+	serverAddr     string
+	echoAddr       string
+}
+
+var _ = gc.Suite(&proxierSuite{})
+
+func (s *proxierSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchEnvironment("SSH_AUTH_SOCK", "")
+	dir := c.MkDir()
+
+	_, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	block, err := cryptossh.MarshalPrivateKey(clientKey, "")
+	c.Assert(err, jc.ErrorIsNil)
+	s.identityFile = filepath.Join(dir, "id_ed25519")
+	err = os.WriteFile(s.identityFile, pem.EncodeToMemory(block), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	clientSigner, err := cryptossh.NewSignerFromKey(clientKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	hostSigner, err := cryptossh.NewSignerFromKey(hostKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.serverAddr = s.startSSHServer(c, hostSigner, clientSigner.PublicKey())
+	s.echoAddr = s.startEchoServer(c)
+
+	s.knownHostsFile = filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{s.serverAddr}, hostSigner.PublicKey())
+	err = os.WriteFile(s.knownHostsFile, []byte(line+"\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// startSSHServer starts an SSH server that accepts the given client
+// key, and forwards direct-tcpip channels as a jump host would.
+func (s *proxierSuite) startSSHServer(c *gc.C, hostSigner cryptossh.Signer, clientKey cryptossh.PublicKey) string {
+	config := &cryptossh.ServerConfig{
+		PublicKeyCallback: func(_ cryptossh.ConnMetadata, key cryptossh.PublicKey) (*cryptossh.Permissions, error) {
+			if string(key.Marshal()) != string(clientKey.Marshal()) {
+				return nil, io.EOF
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveSSH(conn net.Conn, config *cryptossh.ServerConfig) {
+	_, chans, reqs, err := cryptossh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go cryptossh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(cryptossh.UnknownChannelType, "unsupported")
+			continue
+		}
+		var target struct {
+			Host     string
+			Port     uint32
+			OrigHost string
+			OrigPort uint32
+		}
+		if err := cryptossh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+			_ = newChannel.Reject(cryptossh.ConnectionFailed, err.Error())
+			continue
+		}
+		remote, err := net.Dial("tcp", net.JoinHostPort(target.Host, fmt.Sprint(target.Port)))
+		if err != nil {
+			_ = newChannel.Reject(cryptossh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			_ = remote.Close()
+			continue
+		}
+		go cryptossh.DiscardRequests(requests)
+		go func() {
+			defer func() { _ = channel.Close() }()
+			defer func() { _ = remote.Close() }()
+			go func() { _, _ = io.Copy(remote, channel) }()
+			_, _ = io.Copy(channel, remote)
+		}()
+	}
+}
+
+func (s *proxierSuite) startEchoServer(c *gc.C) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (s *proxierSuite) assertEcho(c *gc.C, proxier *sshproxy.Proxier) {
+	conn, err := net.Dial("tcp", net.JoinHostPort(proxier.Host(), proxier.Port()))
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Write([]byte("hello"))
+	c.Assert(err, jc.ErrorIsNil)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(buf), gc.Equals, "hello")
+}
+
+func (s *proxierSuite) TestStartJumpHostChain(c *gc.C) {
+	proxier := sshproxy.NewProxier(sshproxy.ProxierConfig{
+		// The same server is used as both jump hosts; the second
+		// is reached by forwarding through the first.
+		JumpHosts:      []string{"ubuntu@" + s.serverAddr, "ubuntu@" + s.serverAddr},
+		IdentityFile:   s.identityFile,
+		KnownHostsFile: s.knownHostsFile,
+	})
+	proxier.SetRemoteAddresses([]string{"127.0.0.1:1", s.echoAddr})
+	err := proxier.Start()
+	c.Assert(err, jc.ErrorIsNil)
+	defer proxier.Stop()
+
+	c.Assert(proxier.RemoteAddress(), gc.Equals, s.echoAddr)
+	s.assertEcho(c, proxier)
+}
+
+func (s *proxierSuite) TestStartUnknownHostKey(c *gc.C) {
+	err := os.WriteFile(s.knownHostsFile, nil, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	proxier := sshproxy.NewProxier(sshproxy.ProxierConfig{
+		JumpHosts:      []string{"ubuntu@" + s.serverAddr},
+		IdentityFile:   s.identityFile,
+		KnownHostsFile: s.knownHostsFile,
+	})
+	proxier.SetRemoteAddresses([]string{s.echoAddr})
+	err = proxier.Start()
+	c.Assert(err, gc.ErrorMatches, `connecting to jump host .*: ssh: handshake failed: knownhosts: key is unknown`)
+	c.Assert(proxier.Port(), gc.Equals, "")
+}
+
+func (s *proxierSuite) TestStartNoReachableRemoteAddress(c *gc.C) {
+	proxier := sshproxy.NewProxier(sshproxy.ProxierConfig{
+		JumpHosts:      []string{"ubuntu@" + s.serverAddr},
+		IdentityFile:   s.identityFile,
+		KnownHostsFile: s.knownHostsFile,
+	})
+	proxier.SetRemoteAddresses([]string{"127.0.0.1:1"})
+	err := proxier.Start()
+	c.Assert(err, gc.ErrorMatches, `cannot reach any of \["127.0.0.1:1"\] through jump hosts`)
+}
+
+func (s *proxierSuite) TestStartNoRemoteAddresses(c *gc.C) {
+	proxier := sshproxy.NewProxier(sshproxy.ProxierConfig{
+		JumpHosts: []string{"ubuntu@" + s.serverAddr},
+	})
+	err := proxier.Start()
+	c.Assert(err, gc.ErrorMatches, "empty remote addresses not valid")
+}
+
+func (s *proxierSuite) TestValidate(c *gc.C) {
+	for _, t := range []struct {
+		jumpHosts []string
+		err       string
+	}{{
+		err: "empty jump hosts not valid",
+	}, {
+		jumpHosts: []string{"@bastion"},
+		err:       `jump host "@bastion" not valid`,
+	}, {
+		jumpHosts: []string{"ubuntu@bastion:ssh"},
+		err:       `jump host "ubuntu@bastion:ssh" port not valid`,
+	}, {
+		jumpHosts: []string{"ubuntu@bastion", "10.0.0.1:2222", "ubuntu@[fd00::1]:22"},
+	}} {
+		err := sshproxy.ProxierConfig{JumpHosts: t.jumpHosts}.Validate()
+		if t.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.err)
+		}
+	}
+}
+
+func (s *proxierSuite) TestProxyCommand(c *gc.C) {
+	proxier := sshproxy.NewProxier(sshproxy.ProxierConfig{
+		JumpHosts:      []string{"ubuntu@bastion", "admin@10.0.0.1:2222"},
+		IdentityFile:   "/home/ubuntu/.ssh/bastion",
+		KnownHostsFile: "/home/ubuntu/.ssh/bastion_known_hosts",
+	})
+	c.Assert(proxier.ProxyCommand("%h:%p"), jc.DeepEquals, []string{
+		"ssh", "-i", "/home/ubuntu/.ssh/bastion",
+		"-o", "UserKnownHostsFile /home/ubuntu/.ssh/bastion_known_hosts",
+		"-o", "StrictHostKeyChecking yes",
+		"-o", "ProxyCommand ssh -i /home/ubuntu/.ssh/bastion" +
+			` -o "UserKnownHostsFile /home/ubuntu/.ssh/bastion_known_hosts"` +
+			` -o "StrictHostKeyChecking yes" -p 22 -W 10.0.0.1:2222 ubuntu@bastion`,
+		"-p", "2222", "-W", "%h:%p", "admin@10.0.0.1",
+	})
+}
+
+func (s *proxierSuite) TestMarshalling(c *gc.C) {
+	config := sshproxy.ProxierConfig{
+		JumpHosts:      []string{"ubuntu@bastion"},
+		IdentityFile:   "/home/ubuntu/.ssh/bastion",
+		KnownHostsFile: "/home/ubuntu/.ssh/known_hosts",
+	}
+	yamlConf, err := yaml.Marshal(sshproxy.NewProxier(config))
+	c.Assert(err, jc.ErrorIsNil)
+
+	var unmarshalled sshproxy.ProxierConfig
+	c.Assert(yaml.Unmarshal(yamlConf, &unmarshalled), jc.ErrorIsNil)
+	c.Assert(unmarshalled, jc.DeepEquals, config)
+
+	raw, err := sshproxy.NewProxier(config).RawConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(raw, jc.DeepEquals, map[string]interface{}{
+		"jump-hosts":       []string{"ubuntu@bastion"},
+		"identity-file":    "/home/ubuntu/.ssh/bastion",
+		"known-hosts-file": "/home/ubuntu/.ssh/known_hosts",
+	})
+}
+
+func (s *proxierSuite) TestInsecure(c *gc.C) {
+	proxier := sshproxy.NewProxier(sshproxy.ProxierConfig{
+		JumpHosts:      []string{"ubuntu@bastion"},
+		IdentityFile:   "/home/ubuntu/.ssh/bastion",
+		KnownHostsFile: "/home/ubuntu/.ssh/known_hosts",
+	})
+	proxier.Insecure()
+	c.Assert(proxier.Config(), jc.DeepEquals, sshproxy.ProxierConfig{
+		JumpHosts: []string{"ubuntu@bastion"},
+	})
+}
@@ -4,11 +4,14 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"strings"
 
 	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/trace"
 )
 
 var ErrShutdown = errors.New("connection is shut down")
@@ -24,6 +27,9 @@ type Call struct {
 	Response interface{}
 	Error    error
 	Done     chan *Call
+
+	// Scope holds the trace scope sent with the request.
+	Scope trace.Scope
 }
 
 // RequestError represents an error returned from an RPC request.
@@ -95,6 +101,11 @@ func (conn *Conn) send(call *Call) {
 		Request:   call.Request,
 		Version:   1,
 	}
+	if call.Scope.IsValid() {
+		hdr.TraceID = call.Scope.TraceID.String()
+		hdr.SpanID = call.Scope.SpanID.String()
+		hdr.TraceFlags = call.Scope.Flags()
+	}
 	params := call.Params
 	if params == nil {
 		params = struct{}{}
@@ -167,11 +178,32 @@ func (call *Call) done() {
 // The params value may be nil if no parameters are provided; the response value
 // may be nil to indicate that any result should be discarded.
 func (conn *Conn) Call(req Request, params, response interface{}) error {
+	return conn.CallContext(context.Background(), req, params, response)
+}
+
+// CallContext is like Call, but the request is recorded as a span of
+// the trace in the context, and the trace scope is sent with the request
+// so that the server's spans join the same trace.
+func (conn *Conn) CallContext(ctx context.Context, req Request, params, response interface{}) (err error) {
+	ctx, span := trace.Start(ctx, req.Type+"."+req.Action,
+		trace.WithKind(trace.KindClient),
+		trace.WithAttributes(
+			trace.StringAttr("rpc.facade", req.Type),
+			trace.IntAttr("rpc.version", req.Version),
+			trace.StringAttr("rpc.method", req.Action),
+		),
+	)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	call := &Call{
 		Request:  req,
 		Params:   params,
 		Response: response,
 		Done:     make(chan *Call, 1),
+		Scope:    trace.ParentScope(ctx),
 	}
 	conn.send(call)
 	result := <-call.Done
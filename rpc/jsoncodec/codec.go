@@ -61,16 +61,19 @@ type inMsgV0 struct {
 }
 
 type inMsgV1 struct {
-	RequestId uint64                 `json:"request-id"`
-	Type      string                 `json:"type"`
-	Version   int                    `json:"version"`
-	Id        string                 `json:"id"`
-	Request   string                 `json:"request"`
-	Params    json.RawMessage        `json:"params"`
-	Error     string                 `json:"error"`
-	ErrorCode string                 `json:"error-code"`
-	ErrorInfo map[string]interface{} `json:"error-info"`
-	Response  json.RawMessage        `json:"response"`
+	RequestId  uint64                 `json:"request-id"`
+	Type       string                 `json:"type"`
+	Version    int                    `json:"version"`
+	Id         string                 `json:"id"`
+	Request    string                 `json:"request"`
+	Params     json.RawMessage        `json:"params"`
+	Error      string                 `json:"error"`
+	ErrorCode  string                 `json:"error-code"`
+	ErrorInfo  map[string]interface{} `json:"error-info"`
+	Response   json.RawMessage        `json:"response"`
+	TraceID    string                 `json:"trace-id"`
+	SpanID     string                 `json:"span-id"`
+	TraceFlags int                    `json:"trace-flags"`
 }
 
 // outMsg holds an outgoing message.
@@ -87,16 +90,19 @@ type outMsgV0 struct {
 }
 
 type outMsgV1 struct {
-	RequestId uint64                 `json:"request-id,omitempty"`
-	Type      string                 `json:"type,omitempty"`
-	Version   int                    `json:"version,omitempty"`
-	Id        string                 `json:"id,omitempty"`
-	Request   string                 `json:"request,omitempty"`
-	Params    interface{}            `json:"params,omitempty"`
-	Error     string                 `json:"error,omitempty"`
-	ErrorCode string                 `json:"error-code,omitempty"`
-	ErrorInfo map[string]interface{} `json:"error-info,omitempty"`
-	Response  interface{}            `json:"response,omitempty"`
+	RequestId  uint64                 `json:"request-id,omitempty"`
+	Type       string                 `json:"type,omitempty"`
+	Version    int                    `json:"version,omitempty"`
+	Id         string                 `json:"id,omitempty"`
+	Request    string                 `json:"request,omitempty"`
+	Params     interface{}            `json:"params,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	ErrorCode  string                 `json:"error-code,omitempty"`
+	ErrorInfo  map[string]interface{} `json:"error-info,omitempty"`
+	Response   interface{}            `json:"response,omitempty"`
+	TraceID    string                 `json:"trace-id,omitempty"`
+	SpanID     string                 `json:"span-id,omitempty"`
+	TraceFlags int                    `json:"trace-flags,omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -141,6 +147,9 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	hdr.ErrorCode = c.msg.ErrorCode
 	hdr.ErrorInfo = c.msg.ErrorInfo
 	hdr.Version = version
+	hdr.TraceID = c.msg.TraceID
+	hdr.SpanID = c.msg.SpanID
+	hdr.TraceFlags = c.msg.TraceFlags
 	return nil
 }
 
@@ -266,14 +275,17 @@ func newOutMsgV0(hdr *rpc.Header, body interface{}) outMsgV0 {
 // reflect, but no.
 func newOutMsgV1(hdr *rpc.Header, body interface{}) outMsgV1 {
 	result := outMsgV1{
-		RequestId: hdr.RequestId,
-		Type:      hdr.Request.Type,
-		Version:   hdr.Request.Version,
-		Id:        hdr.Request.Id,
-		Request:   hdr.Request.Action,
-		Error:     hdr.Error,
-		ErrorCode: hdr.ErrorCode,
-		ErrorInfo: hdr.ErrorInfo,
+		RequestId:  hdr.RequestId,
+		Type:       hdr.Request.Type,
+		Version:    hdr.Request.Version,
+		Id:         hdr.Request.Id,
+		Request:    hdr.Request.Action,
+		Error:      hdr.Error,
+		ErrorCode:  hdr.ErrorCode,
+		ErrorInfo:  hdr.ErrorInfo,
+		TraceID:    hdr.TraceID,
+		SpanID:     hdr.SpanID,
+		TraceFlags: hdr.TraceFlags,
 	}
 	if hdr.IsRequest() {
 		result.Params = body
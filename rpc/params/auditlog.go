@@ -0,0 +1,56 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// AuditLogFilter selects the audit log records to return. Empty
+// fields match all records.
+type AuditLogFilter struct {
+	// User is the name of the user who made the requests.
+	User string `json:"user,omitempty"`
+
+	// ModelUUID is the model the requests were made against.
+	ModelUUID string `json:"model-uuid,omitempty"`
+
+	// Method is a "Facade.Method" name, or a facade name matching
+	// all of its methods.
+	Method string `json:"method,omitempty"`
+
+	// Since excludes requests made before this time.
+	Since *time.Time `json:"since,omitempty"`
+
+	// Limit is the maximum number of (most recent) records to return.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditLogRecord describes an API request recorded in the audit log.
+type AuditLogRecord struct {
+	ConversationID string          `json:"conversation-id"`
+	ConnectionID   string          `json:"connection-id"`
+	Who            string          `json:"who"`
+	What           string          `json:"what"`
+	ModelName      string          `json:"model-name,omitempty"`
+	ModelUUID      string          `json:"model-uuid,omitempty"`
+	RequestID      uint64          `json:"request-id"`
+	When           time.Time       `json:"when"`
+	Facade         string          `json:"facade"`
+	Method         string          `json:"method"`
+	Version        int             `json:"version"`
+	Args           string          `json:"args,omitempty"`
+	Errors         []AuditLogError `json:"errors,omitempty"`
+}
+
+// AuditLogError holds an error returned in response to an audited
+// API request.
+type AuditLogError struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// AuditLogRecords holds the records returned from an audit log query,
+// oldest first.
+type AuditLogRecords struct {
+	Records []AuditLogRecord `json:"records"`
+}
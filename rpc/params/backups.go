@@ -20,6 +20,19 @@ type BackupsDownloadArgs struct {
 	ID string `json:"id"`
 }
 
+// BackupsUploadResult holds the result of uploading a backup archive
+// to the controller.
+type BackupsUploadResult struct {
+	// ID is the filename of the uploaded archive on the controller.
+	ID string `json:"id"`
+}
+
+// RestoreArgs holds the args for the API Restore method.
+type RestoreArgs struct {
+	// FileName is the backup archive on the controller to restore.
+	FileName string `json:"filename"`
+}
+
 // BackupsMetadataResult holds the metadata for a backup as returned by
 // an API backups method (such as Create).
 type BackupsMetadataResult struct {
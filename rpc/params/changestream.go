@@ -0,0 +1,49 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// ChangeStreamConfig holds all the information necessary to open a
+// streaming connection to the API endpoint for reading change events.
+//
+// The field tags relate to the following 2 libraries:
+//
+//	github.com/google/go-querystring/query (encoding)
+//	github.com/gorilla/schema (decoding)
+type ChangeStreamConfig struct {
+	// Namespaces lists the namespaces whose changes are streamed. If
+	// empty, changes in all of the permitted namespaces are streamed.
+	Namespaces []string `schema:"namespace" url:"namespace,omitempty"`
+
+	// Types lists the types of change ("create", "update" or
+	// "delete") that are streamed. If empty, all changes are streamed.
+	Types []string `schema:"type" url:"type,omitempty"`
+
+	// After is the position from which the stream is resumed; only
+	// changes with a greater ID are streamed. If nil, only changes
+	// made after the stream is opened are streamed.
+	After *int64 `schema:"after" url:"after,omitempty"`
+}
+
+// ChangeStreamEvents is a batch of change events sent over the
+// change stream.
+type ChangeStreamEvents struct {
+	// Events holds the changes in the batch, in ID order.
+	Events []ChangeStreamEvent `json:"events"`
+
+	// Position is the position of the stream after the batch. Passing
+	// it as ChangeStreamConfig.After resumes the stream from here.
+	Position int64 `json:"position"`
+}
+
+// ChangeStreamEvent describes a single change to an entity in the
+// controller database.
+type ChangeStreamEvent struct {
+	ID          int64     `json:"id"`
+	Type        string    `json:"type"`
+	Namespace   string    `json:"namespace"`
+	ChangedUUID string    `json:"changed-uuid"`
+	Timestamp   time.Time `json:"timestamp"`
+}
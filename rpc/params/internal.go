@@ -906,6 +906,23 @@ type LogMessage struct {
 	Labels    []string  `json:"lab"`
 }
 
+// StructuredLogMessage is the long-form JSON representation of a log
+// record, with self-describing field names. It is what
+// `juju debug-log --format=json` prints, and what the logstream
+// endpoint sends when asked for the structured format.
+type StructuredLogMessage struct {
+	ID        int64     `json:"id,omitempty"`
+	ModelUUID string    `json:"model-uuid,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Entity    string    `json:"entity"`
+	Timestamp time.Time `json:"timestamp"`
+	Severity  string    `json:"severity"`
+	Module    string    `json:"module"`
+	Location  string    `json:"location"`
+	Labels    []string  `json:"labels,omitempty"`
+	Message   string    `json:"message"`
+}
+
 // ResourceUploadResult is used to return some details about an
 // uploaded resource.
 type ResourceUploadResult struct {
@@ -49,4 +49,36 @@ type LogStreamConfig struct {
 
 	// MaxLookbackRecords is the maximum number of log records to stream from the past.
 	MaxLookbackRecords int `schema:"maxlookbackrecords" url:"maxlookbackrecords,omitempty"`
+
+	// Format is the shape of the records sent over the stream. If empty
+	// or LogStreamFormatCompact, each message is a LogStreamRecords
+	// batch. If LogStreamFormatStructured, each message is a single
+	// StructuredLogMessage.
+	Format string `schema:"format" url:"format,omitempty"`
+
+	// IncludeEntity lists the entity tags whose records are streamed.
+	// Tags may include "*" wildcards. If empty, records from all
+	// entities are streamed.
+	IncludeEntity []string `schema:"includeEntity" url:"includeEntity,omitempty"`
+
+	// IncludeModule lists the logging modules whose records are
+	// streamed, including their submodules. If empty, records from all
+	// modules are streamed.
+	IncludeModule []string `schema:"includeModule" url:"includeModule,omitempty"`
+
+	// Level is the minimum severity of the records streamed, one of
+	// TRACE, DEBUG, INFO, WARNING, ERROR or CRITICAL. If empty,
+	// records of all severities are streamed.
+	Level string `schema:"level" url:"level,omitempty"`
 }
+
+const (
+	// LogStreamFormatCompact is the default logstream format, sending
+	// batches of LogStreamRecord.
+	LogStreamFormatCompact = "compact"
+
+	// LogStreamFormatStructured has the logstream endpoint send one
+	// StructuredLogMessage per record, matching the output of
+	// `juju debug-log --format=json`.
+	LogStreamFormatStructured = "structured"
+)
@@ -63,6 +63,31 @@ type InitiateMigrationResult struct {
 	MigrationId string `json:"migration-id"`
 }
 
+// MigrationBlocker describes a single problem which would prevent a
+// model from being migrated.
+type MigrationBlocker struct {
+	// Check identifies which part of the migration found the
+	// problem, for example "source", "target" or "import".
+	Check   string `json:"check,omitempty"`
+	Entity  string `json:"entity"`
+	Message string `json:"message"`
+}
+
+// MigrationPrecheckResults is used to return the results of checking
+// whether one or more models could be migrated.
+type MigrationPrecheckResults struct {
+	Results []MigrationPrecheckResult `json:"results"`
+}
+
+// MigrationPrecheckResult holds every problem found when checking
+// whether a model could be migrated.
+type MigrationPrecheckResult struct {
+	ModelTag string             `json:"model-tag"`
+	Blockers []MigrationBlocker `json:"blockers,omitempty"`
+	Warnings []string           `json:"warnings,omitempty"`
+	Error    *Error             `json:"error,omitempty"`
+}
+
 // SetMigrationPhaseArgs provides a migration phase to the
 // migrationmaster.SetPhase API method.
 type SetMigrationPhaseArgs struct {
@@ -90,6 +115,18 @@ type SerializedModel struct {
 	Resources []SerializedModelResource `json:"resources"`
 }
 
+// SerializedModelResults holds the results of exporting one or more
+// models.
+type SerializedModelResults struct {
+	Results []SerializedModelResult `json:"results"`
+}
+
+// SerializedModelResult holds the result of exporting a single model.
+type SerializedModelResult struct {
+	Result *SerializedModel `json:"result,omitempty"`
+	Error  *Error           `json:"error,omitempty"`
+}
+
 // SerializedModelTools holds the version and URI for a given tools
 // version.
 type SerializedModelTools struct {
@@ -60,6 +60,31 @@ type ModelSequencesResult struct {
 	Sequences map[string]int `json:"sequences"`
 }
 
+// ModelResources holds an amount of each of the resources limited by
+// model quotas. Memory and storage sizes are in MiB.
+type ModelResources struct {
+	Machines int    `json:"machines,omitempty"`
+	Units    int    `json:"units,omitempty"`
+	Cores    uint64 `json:"cores,omitempty"`
+	Memory   uint64 `json:"memory,omitempty"`
+	Storage  uint64 `json:"storage,omitempty"`
+}
+
+// ModelQuotasResult holds the result of a GetModelQuotas call.
+type ModelQuotasResult struct {
+	// Quotas holds the model's quotas, where a zero
+	// amount means that the resource is not limited.
+	Quotas ModelResources `json:"quotas"`
+
+	// Usage holds the resources currently consumed by the model.
+	Usage ModelResources `json:"usage"`
+}
+
+// SetModelQuotas holds the arguments for a SetModelQuotas call.
+type SetModelQuotas struct {
+	Quotas ModelResources `json:"quotas"`
+}
+
 // ModelDefaults holds the settings for a given ModelDefaultsResult config
 // attribute.
 type ModelDefaults struct {
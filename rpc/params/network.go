@@ -912,6 +912,24 @@ type IngressRule struct {
 	SourceCIDRs []string  `json:"source-cidrs"`
 }
 
+// EgressRule is the wire form of a firewall.EgressRule.
+type EgressRule struct {
+	DestinationCIDR string    `json:"destination-cidr"`
+	PortRange       PortRange `json:"port-range"`
+}
+
+// EgressRulesResult holds the egress rules configured for an
+// application, or an error.
+type EgressRulesResult struct {
+	Rules []EgressRule `json:"rules"`
+	Error *Error       `json:"error,omitempty"`
+}
+
+// EgressRulesResults holds the results of a GetEgressRules call.
+type EgressRulesResults struct {
+	Results []EgressRulesResult `json:"results"`
+}
+
 // APIHostPortsResult holds the result of an APIHostPorts
 // call. Each element in the top level slice holds
 // the addresses for one API server.
@@ -599,11 +599,19 @@ type LoginRequest struct {
 	Macaroons     []macaroon.Slice `json:"macaroons"`
 	BakeryVersion bakery.Version   `json:"bakery-version,omitempty"`
 	Token         string           `json:"token,omitempty"`
+	IDToken       string           `json:"id-token,omitempty"`
 	CLIArgs       string           `json:"cli-args,omitempty"`
 	UserData      string           `json:"user-data"`
 	ClientVersion string           `json:"client-version,omitempty"`
 }
 
+// OIDCLoginConfig holds the details of the OpenID Connect provider that
+// users may log in to the controller with.
+type OIDCLoginConfig struct {
+	IssuerURL string `json:"issuer-url"`
+	ClientID  string `json:"client-id"`
+}
+
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
 // or earlier (v0 or even pre-facade).
 type LoginRequestCompat struct {
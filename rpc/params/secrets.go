@@ -131,13 +131,25 @@ type UpdateUserSecretArg struct {
 
 	// AutoPrune indicates whether the staled secret revisions should be pruned automatically.
 	AutoPrune *bool `json:"auto-prune,omitempty"`
+
+	// RollbackTo, if set, creates a new revision with the content
+	// of the specified older revision.
+	RollbackTo *int `json:"rollback-to,omitempty"`
 }
 
 // Validate validates the UpdateUserSecretArg.
 func (arg UpdateUserSecretArg) Validate() error {
-	if arg.AutoPrune == nil && arg.Description == nil && arg.Label == nil && len(arg.Content.Data) == 0 {
+	if arg.AutoPrune == nil && arg.Description == nil && arg.Label == nil && len(arg.Content.Data) == 0 && arg.RollbackTo == nil {
 		return errors.New("at least one attribute to update must be specified")
 	}
+	if arg.RollbackTo != nil {
+		if len(arg.Content.Data) > 0 {
+			return errors.New("must specify either content or a revision to roll back to but not both")
+		}
+		if *arg.RollbackTo < 1 {
+			return errors.NotValidf("rollback revision %d", *arg.RollbackTo)
+		}
+	}
 	if arg.URI == "" && arg.ExistingLabel == "" {
 		return errors.New("must specify either URI or label")
 	}
@@ -266,6 +278,7 @@ type SecretRevision struct {
 	CreateTime  time.Time       `json:"create-time,omitempty"`
 	UpdateTime  time.Time       `json:"update-time,omitempty"`
 	ExpireTime  *time.Time      `json:"expire-time,omitempty"`
+	RollbackTo  int             `json:"rollback-to,omitempty"`
 }
 
 // ListSecretResult is the result of getting secret metadata.
@@ -99,6 +99,10 @@ type StorageAttachment struct {
 	Kind     StorageKind `json:"kind"`
 	Location string      `json:"location"`
 	Life     life.Value  `json:"life"`
+
+	// Resized is true if the storage has been resized since the
+	// unit last ran its storage-resized hook.
+	Resized bool `json:"resized,omitempty"`
 }
 
 // StorageAttachmentId identifies a storage attachment by the tags of the
@@ -259,6 +263,10 @@ type VolumeParams struct {
 	Attributes map[string]interface{}  `json:"attributes,omitempty"`
 	Tags       map[string]string       `json:"tags,omitempty"`
 	Attachment *VolumeAttachmentParams `json:"attachment,omitempty"`
+
+	// SnapshotId, if non-empty, is the provider ID of the volume
+	// snapshot from which the volume should be created.
+	SnapshotId string `json:"snapshot-id,omitempty"`
 }
 
 // RemoveVolumeParams holds the parameters for destroying or releasing a
@@ -951,3 +959,239 @@ type AddStorageDetails struct {
 	// of the added storage instances.
 	StorageTags []string `json:"storage-tags"`
 }
+
+// VolumeSnapshotIds holds a set of volume snapshot IDs.
+type VolumeSnapshotIds struct {
+	Ids []string `json:"ids"`
+}
+
+// VolumeSnapshotParams holds the parameters for taking or destroying
+// a volume snapshot.
+type VolumeSnapshotParams struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string `json:"id"`
+
+	// Life is the lifecycle state of the snapshot.
+	Life life.Value `json:"life"`
+
+	// VolumeTag is the tag of the volume that the snapshot is,
+	// or was, taken from.
+	VolumeTag string `json:"volume-tag"`
+
+	// VolumeId is the storage provider's unique ID for the volume.
+	// This will be empty if the volume no longer exists.
+	VolumeId string `json:"volume-id,omitempty"`
+
+	// SnapshotId is the storage provider's unique ID for the
+	// snapshot. This will be empty if the snapshot has not yet
+	// been taken.
+	SnapshotId string `json:"snapshot-id,omitempty"`
+
+	// Provider is the storage provider that manages the snapshot.
+	Provider string `json:"provider"`
+
+	// Attributes is the configuration of the storage pool that
+	// the snapshotted volume belongs to.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// Tags is the set of tags to apply to the snapshot.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// VolumeSnapshotParamsResult holds provisioning parameters for
+// a volume snapshot.
+type VolumeSnapshotParamsResult struct {
+	Result VolumeSnapshotParams `json:"result"`
+	Error  *Error               `json:"error,omitempty"`
+}
+
+// VolumeSnapshotParamsResults holds provisioning parameters for
+// multiple volume snapshots.
+type VolumeSnapshotParamsResults struct {
+	Results []VolumeSnapshotParamsResult `json:"results,omitempty"`
+}
+
+// VolumeSnapshotInfo describes a volume snapshot taken by
+// a storage provider.
+type VolumeSnapshotInfo struct {
+	SnapshotId string `json:"snapshot-id"`
+	Size       uint64 `json:"size"`
+}
+
+// VolumeSnapshot identifies and describes a volume snapshot.
+type VolumeSnapshot struct {
+	Id   string             `json:"id"`
+	Info VolumeSnapshotInfo `json:"info"`
+}
+
+// VolumeSnapshots holds a set of volume snapshots.
+type VolumeSnapshots struct {
+	Snapshots []VolumeSnapshot `json:"snapshots"`
+}
+
+// VolumeSnapshotStatusArg holds the status to set on a volume snapshot.
+type VolumeSnapshotStatusArg struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+	Info   string `json:"info"`
+}
+
+// SetVolumeSnapshotStatus holds the arguments for setting the
+// status of volume snapshots.
+type SetVolumeSnapshotStatus struct {
+	Args []VolumeSnapshotStatusArg `json:"args"`
+}
+
+// VolumeSnapshotDetails describes a volume snapshot.
+type VolumeSnapshotDetails struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string `json:"id"`
+
+	// VolumeTag is the tag of the volume that the snapshot
+	// was taken from.
+	VolumeTag string `json:"volume-tag"`
+
+	// StorageTag is the tag of the storage instance that the
+	// snapshotted volume was assigned to, if any.
+	StorageTag string `json:"storage-tag,omitempty"`
+
+	// StorageName is the name of the storage that the snapshotted
+	// volume was assigned to.
+	StorageName string `json:"storage-name"`
+
+	// Kind is the kind of storage that the snapshot restores to.
+	Kind StorageKind `json:"kind"`
+
+	// Pool is the name of the storage pool of the snapshot.
+	Pool string `json:"pool"`
+
+	// SnapshotId is the storage provider's unique ID for the
+	// snapshot, if it has been taken.
+	SnapshotId string `json:"snapshot-id,omitempty"`
+
+	// Size is the size of the snapshotted volume in MiB, if the
+	// snapshot has been taken.
+	Size uint64 `json:"size,omitempty"`
+
+	// Created is the time at which the snapshot was requested.
+	Created time.Time `json:"created"`
+
+	// Life is the lifecycle state of the snapshot.
+	Life life.Value `json:"life"`
+
+	// Status is the status of the snapshot.
+	Status EntityStatus `json:"status"`
+}
+
+// VolumeSnapshotDetailsResult holds the details of a volume snapshot,
+// or an error.
+type VolumeSnapshotDetailsResult struct {
+	Result *VolumeSnapshotDetails `json:"result,omitempty"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// VolumeSnapshotDetailsResults holds the results of a snapshot
+// creation or listing request.
+type VolumeSnapshotDetailsResults struct {
+	Results []VolumeSnapshotDetailsResult `json:"results"`
+}
+
+// StorageSnapshotFilter holds the criteria for listing volume
+// snapshots. If StorageTags is empty, all snapshots are listed.
+type StorageSnapshotFilter struct {
+	StorageTags []string `json:"storage-tags,omitempty"`
+}
+
+// RestoreStorageSnapshotArg holds the arguments for restoring a volume
+// snapshot into a new storage instance.
+type RestoreStorageSnapshotArg struct {
+	// Id is the ID of the snapshot to restore.
+	Id string `json:"id"`
+
+	// StorageName is the name of the storage to assign to the new
+	// storage instance. If empty, the storage name of the
+	// snapshotted storage is used.
+	StorageName string `json:"storage-name,omitempty"`
+}
+
+// RestoreStorageSnapshotArgs holds the arguments for restoring
+// volume snapshots.
+type RestoreStorageSnapshotArgs struct {
+	Args []RestoreStorageSnapshotArg `json:"args"`
+}
+
+// ResizeStorageArg holds the arguments for resizing a storage instance.
+type ResizeStorageArg struct {
+	// StorageTag is the tag of the storage instance to resize.
+	StorageTag string `json:"storage-tag"`
+
+	// Size is the new size of the storage instance in MiB.
+	Size uint64 `json:"size"`
+}
+
+// ResizeStorageArgs holds the arguments for resizing storage instances.
+type ResizeStorageArgs struct {
+	Args []ResizeStorageArg `json:"args"`
+}
+
+// VolumeResizeParams holds the parameters for resizing a volume.
+type VolumeResizeParams struct {
+	// VolumeTag is the tag of the volume to resize.
+	VolumeTag string `json:"volume-tag"`
+
+	// VolumeId is the storage provider's unique ID for the volume.
+	VolumeId string `json:"volume-id"`
+
+	// Provider is the storage provider that manages the volume.
+	Provider string `json:"provider"`
+
+	// Attributes is the configuration of the storage pool that
+	// the volume belongs to.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// Size is the size in MiB that the volume is to be resized to.
+	Size uint64 `json:"size"`
+}
+
+// VolumeResizeParamsResult holds the parameters for resizing a volume,
+// or an error.
+type VolumeResizeParamsResult struct {
+	Result VolumeResizeParams `json:"result"`
+	Error  *Error             `json:"error,omitempty"`
+}
+
+// VolumeResizeParamsResults holds the parameters for resizing
+// multiple volumes.
+type VolumeResizeParamsResults struct {
+	Results []VolumeResizeParamsResult `json:"results,omitempty"`
+}
+
+// FilesystemResizeParams holds the parameters for growing a filesystem
+// whose backing volume has been resized.
+type FilesystemResizeParams struct {
+	// FilesystemTag is the tag of the filesystem to grow.
+	FilesystemTag string `json:"filesystem-tag"`
+
+	// VolumeTag is the tag of the volume backing the filesystem.
+	VolumeTag string `json:"volume-tag"`
+
+	// MountPoint is the location at which the filesystem is
+	// mounted on the machine.
+	MountPoint string `json:"mount-point"`
+
+	// Size is the size in MiB that the filesystem is to be grown to.
+	Size uint64 `json:"size"`
+}
+
+// FilesystemResizeParamsResult holds the parameters for growing
+// a filesystem, or an error.
+type FilesystemResizeParamsResult struct {
+	Result FilesystemResizeParams `json:"result"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// FilesystemResizeParamsResults holds the parameters for growing
+// multiple filesystems.
+type FilesystemResizeParamsResults struct {
+	Results []FilesystemResizeParamsResult `json:"results,omitempty"`
+}
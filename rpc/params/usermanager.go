@@ -67,3 +67,147 @@ type AddUserResult struct {
 	SecretKey []byte `json:"secret-key,omitempty"`
 	Error     *Error `json:"error,omitempty"`
 }
+
+// AddUserGroups holds the parameters for adding new user groups.
+type AddUserGroups struct {
+	Groups []AddUserGroup `json:"groups"`
+}
+
+// AddUserGroup stores the parameters to add one user group.
+type AddUserGroup struct {
+	Name string `json:"name"`
+
+	// External is true if the members of the group are asserted by
+	// the identity provider that users log in with, rather than
+	// added and removed through Juju.
+	External bool `json:"external,omitempty"`
+}
+
+// UserGroupNames holds the names of user groups.
+type UserGroupNames struct {
+	Names []string `json:"names"`
+}
+
+// ModifyUserGroupMembers holds the parameters for adding users to,
+// or removing users from, user groups.
+type ModifyUserGroupMembers struct {
+	Changes []UserGroupMembers `json:"changes"`
+}
+
+// UserGroupMembers holds the users to add to, or remove from, one
+// user group.
+type UserGroupMembers struct {
+	Group    string   `json:"group"`
+	UserTags []string `json:"user-tags"`
+}
+
+// UserGroupInfo holds information on a user group.
+type UserGroupInfo struct {
+	Name        string    `json:"name"`
+	External    bool      `json:"external,omitempty"`
+	Members     []string  `json:"members"`
+	CreatedBy   string    `json:"created-by"`
+	DateCreated time.Time `json:"date-created"`
+}
+
+// UserGroupInfoResult holds the result of a UserGroupInfo call.
+type UserGroupInfoResult struct {
+	Result *UserGroupInfo `json:"result,omitempty"`
+	Error  *Error         `json:"error,omitempty"`
+}
+
+// UserGroupInfoResults holds the result of a bulk UserGroupInfo API
+// call.
+type UserGroupInfoResults struct {
+	Results []UserGroupInfoResult `json:"results"`
+}
+
+// ModifyUserGroupAccessRequest holds the parameters for granting
+// user groups access to, and revoking their access from, models,
+// clouds, offers and the controller.
+type ModifyUserGroupAccessRequest struct {
+	Changes []ModifyUserGroupAccess `json:"changes"`
+}
+
+// ModifyUserGroupAccess defines an operation to modify the access a
+// user group has to a model, cloud, offer or the controller.
+type ModifyUserGroupAccess struct {
+	Group  string          `json:"group"`
+	Action UserGroupAction `json:"action"`
+	Access string          `json:"access"`
+
+	// TargetTag is the tag of the model, cloud or controller that
+	// access is modified on. It is empty when OfferURL is set.
+	TargetTag string `json:"target-tag,omitempty"`
+
+	// OfferURL is the URL of the offer that access is modified on.
+	OfferURL string `json:"offer-url,omitempty"`
+}
+
+// UserGroupAction is an action that can be performed on the access of
+// a user group.
+type UserGroupAction string
+
+// Actions that can be performed on the access of a user group.
+const (
+	GrantUserGroupAccess  UserGroupAction = "grant"
+	RevokeUserGroupAccess UserGroupAction = "revoke"
+)
+
+// AddRoles holds the parameters for adding new roles.
+type AddRoles struct {
+	Roles []AddRole `json:"roles"`
+}
+
+// AddRole stores the parameters to add one role.
+type AddRole struct {
+	Name string `json:"name"`
+
+	// Access is the model access level given to users granted the
+	// role.
+	Access string `json:"access"`
+
+	// Capabilities are the API methods, in the form
+	// "<facade>.<method>", that the role allows.
+	Capabilities []string `json:"capabilities"`
+}
+
+// RoleNames holds the names of roles.
+type RoleNames struct {
+	Names []string `json:"names"`
+}
+
+// RoleInfo holds information on a role.
+type RoleInfo struct {
+	Name         string    `json:"name"`
+	Access       string    `json:"access"`
+	Capabilities []string  `json:"capabilities"`
+	BuiltIn      bool      `json:"built-in,omitempty"`
+	CreatedBy    string    `json:"created-by,omitempty"`
+	DateCreated  time.Time `json:"date-created,omitempty"`
+}
+
+// RoleInfoResult holds the result of a RoleInfo call.
+type RoleInfoResult struct {
+	Result *RoleInfo `json:"result,omitempty"`
+	Error  *Error    `json:"error,omitempty"`
+}
+
+// RoleInfoResults holds the result of a bulk RoleInfo API call.
+type RoleInfoResults struct {
+	Results []RoleInfoResult `json:"results"`
+}
+
+// GrantRoles holds the parameters for granting roles to users on
+// models.
+type GrantRoles struct {
+	Grants []GrantRole `json:"grants"`
+}
+
+// GrantRole grants a role to a user on a model. Users without access
+// to the model are given it.
+type GrantRole struct {
+	UserTag  string `json:"user-tag"`
+	ModelTag string `json:"model-tag"`
+	Role     string `json:"role"`
+}
@@ -19,6 +19,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/rpc/params"
@@ -1173,6 +1174,24 @@ func (*rpcSuite) TestRequestContext(c *gc.C) {
 	c.Assert(arg, gc.Equals, stringVal{"foo"})
 }
 
+func (*rpcSuite) TestRequestTraceScope(c *gc.C) {
+	root := &Root{}
+	root.contextInst = &ContextMethods{root: root}
+
+	client, _, srvDone, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	scope, err := trace.ParseScope("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", trace.FlagSampled)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := trace.WithRemoteScope(context.Background(), scope)
+	err = client.CallContext(ctx, rpc.Request{"ContextMethods", 0, "", "Call0"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The server's span is part of the client's trace.
+	c.Assert(root.contextInst.callContext, gc.NotNil)
+	c.Assert(trace.ParentScope(root.contextInst.callContext), gc.Equals, scope)
+}
+
 func (*rpcSuite) TestConnectionContextCloseClient(c *gc.C) {
 	root := &Root{}
 	root.contextInst = &ContextMethods{
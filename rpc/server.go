@@ -14,6 +14,8 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/rpcreflect"
+
+	"github.com/juju/juju/core/trace"
 )
 
 const codeNotImplemented = "not implemented"
@@ -70,6 +72,13 @@ type Header struct {
 
 	// Version defines the wire format of the request and response structure.
 	Version int
+
+	// TraceID, SpanID and TraceFlags hold the trace scope of the
+	// caller, if any, so that the spans recorded while serving the
+	// request join the caller's trace.
+	TraceID    string
+	SpanID     string
+	TraceFlags int
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -544,6 +553,27 @@ func (conn *Conn) bindRequest(hdr *Header) (boundRequest, error) {
 	}, nil
 }
 
+// startRequestSpan starts the span recording a request. If the caller
+// sent its trace scope, the span is part of the caller's trace.
+func (conn *Conn) startRequestSpan(ctx context.Context, hdr *Header) (context.Context, trace.Span) {
+	if hdr.TraceID != "" {
+		scope, err := trace.ParseScope(hdr.TraceID, hdr.SpanID, hdr.TraceFlags)
+		if err != nil {
+			logger.Debugf("ignoring trace scope of request %d: %v", hdr.RequestId, err)
+		} else {
+			ctx = trace.WithRemoteScope(ctx, scope)
+		}
+	}
+	return trace.Start(ctx, hdr.Request.Type+"."+hdr.Request.Action,
+		trace.WithKind(trace.KindServer),
+		trace.WithAttributes(
+			trace.StringAttr("rpc.facade", hdr.Request.Type),
+			trace.IntAttr("rpc.version", hdr.Request.Version),
+			trace.StringAttr("rpc.method", hdr.Request.Action),
+		),
+	)
+}
+
 // runRequest runs the given request and sends the reply.
 func (conn *Conn) runRequest(
 	req boundRequest,
@@ -568,7 +598,10 @@ func (conn *Conn) runRequest(
 	ctx, cancel := context.WithCancel(conn.context)
 	defer cancel()
 
+	ctx, span := conn.startRequestSpan(ctx, &req.hdr)
 	rv, err := req.Call(ctx, req.hdr.Request.Id, arg)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), recorder)
 	} else {
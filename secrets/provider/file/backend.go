@@ -0,0 +1,111 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/secrets"
+)
+
+type fileBackend struct {
+	root      string
+	modelUUID string
+	store     ObjectStore
+	keys      *keyring
+}
+
+func (k fileBackend) objectName(revisionId string) string {
+	return path.Join(k.modelUUID, revisionId)
+}
+
+// GetContent implements SecretsBackend.
+func (k fileBackend) GetContent(ctx context.Context, revisionId string) (secrets.SecretValue, error) {
+	name := k.objectName(revisionId)
+	data, err := k.store.Get(ctx, name)
+	if errors.Is(err, errors.NotFound) {
+		return nil, errors.NotFoundf("secret revision %q", revisionId)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting secret %q", revisionId)
+	}
+	plaintext, err := k.keys.decrypt(name, data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var val map[string]string
+	if err := json.Unmarshal(plaintext, &val); err != nil {
+		return nil, errors.Annotatef(err, "decoding secret %q", revisionId)
+	}
+	return secrets.NewSecretValue(val), nil
+}
+
+// DeleteContent implements SecretsBackend.
+func (k fileBackend) DeleteContent(ctx context.Context, revisionId string) error {
+	err := k.store.Delete(ctx, k.objectName(revisionId))
+	if errors.Is(err, errors.NotFound) {
+		return errors.NotFoundf("secret revision %q", revisionId)
+	}
+	return errors.Trace(err)
+}
+
+// SaveContent implements SecretsBackend.
+func (k fileBackend) SaveContent(ctx context.Context, uri *secrets.URI, revision int, value secrets.SecretValue) (string, error) {
+	revisionId := uri.Name(revision)
+	name := k.objectName(revisionId)
+	plaintext, err := json.Marshal(value.EncodedValues())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	data, err := k.keys.encrypt(name, plaintext)
+	if err != nil {
+		return "", errors.Annotatef(err, "encrypting secret content for %q", revisionId)
+	}
+	if err := k.store.Put(ctx, name, data); err != nil {
+		return "", errors.Annotatef(err, "saving secret content for %q", revisionId)
+	}
+	return revisionId, nil
+}
+
+// Ping implements SecretsBackend.
+func (k fileBackend) Ping() error {
+	info, err := os.Stat(k.root)
+	if err != nil {
+		return errors.Annotate(err, "backend not reachable")
+	}
+	if !info.IsDir() {
+		return errors.Errorf("backend path %q is not a directory", k.root)
+	}
+	return nil
+}
+
+// controllerBackend is the backend used by clients other than the
+// controller, which pass secret content through the secrets API
+// instead of accessing the backend. It returns NotFound or NotSupported
+// as the internal backend does.
+type controllerBackend struct{}
+
+// GetContent implements SecretsBackend.
+func (controllerBackend) GetContent(_ context.Context, revisionId string) (secrets.SecretValue, error) {
+	return nil, errors.NotFoundf("secret revision %q", revisionId)
+}
+
+// DeleteContent implements SecretsBackend.
+func (controllerBackend) DeleteContent(_ context.Context, revisionId string) error {
+	return errors.NotFoundf("secret revision %q", revisionId)
+}
+
+// SaveContent implements SecretsBackend.
+func (controllerBackend) SaveContent(context.Context, *secrets.URI, int, secrets.SecretValue) (string, error) {
+	return "", errors.NotSupportedf("saving content to the file backend outside the controller")
+}
+
+// Ping implements SecretsBackend.
+func (controllerBackend) Ping() error {
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
+
+	coreconfig "github.com/juju/juju/core/config"
+	"github.com/juju/juju/secrets/provider"
+)
+
+const (
+	PathKey         = "path"
+	KeyKey          = "key"
+	PreviousKeysKey = "previous-keys"
+)
+
+var configSchema = environschema.Fields{
+	PathKey: {
+		Description: "The directory on the controller in which to store encrypted secret content.",
+		Type:        environschema.Tstring,
+		Immutable:   true,
+		Mandatory:   true,
+	},
+	KeyKey: {
+		Description: "The base64 encoded 256 bit AES key used to encrypt secret content.",
+		Type:        environschema.Tstring,
+		Mandatory:   true,
+		Secret:      true,
+	},
+	PreviousKeysKey: {
+		Description: "Comma separated base64 encoded keys used to decrypt content encrypted before the key was rotated.",
+		Type:        environschema.Tstring,
+		Secret:      true,
+	},
+}
+
+var configDefaults = schema.Defaults{}
+
+type backendConfig struct {
+	validAttrs map[string]interface{}
+}
+
+func (c *backendConfig) path() string {
+	return c.validAttrs[PathKey].(string)
+}
+
+func (c *backendConfig) key() string {
+	return c.validAttrs[KeyKey].(string)
+}
+
+func (c *backendConfig) previousKeys() []string {
+	v, _ := c.validAttrs[PreviousKeysKey].(string)
+	var keys []string
+	for _, key := range strings.Split(v, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// keyring returns the keys used to encrypt and decrypt content.
+func (c *backendConfig) keyring() (*keyring, error) {
+	return newKeyring(c.key(), c.previousKeys()...)
+}
+
+// ConfigSchema implements SecretBackendProvider.
+func (p fileProvider) ConfigSchema() environschema.Fields {
+	return configSchema
+}
+
+// ConfigDefaults implements SecretBackendProvider.
+func (p fileProvider) ConfigDefaults() schema.Defaults {
+	return schema.Defaults{}
+}
+
+// ValidateConfig implements SecretBackendProvider.
+func (p fileProvider) ValidateConfig(oldCfg, newCfg provider.ConfigAttrs) error {
+	newValidCfg, err := newConfig(newCfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !filepath.IsAbs(newValidCfg.path()) {
+		return errors.NotValidf("relative path %q", newValidCfg.path())
+	}
+	if _, err := newValidCfg.keyring(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if oldCfg == nil {
+		return nil
+	}
+	oldValidCfg, err := newConfig(oldCfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for n, field := range configSchema {
+		if !field.Immutable {
+			continue
+		}
+		oldV := oldValidCfg.validAttrs[n]
+		newV := newValidCfg.validAttrs[n]
+		if oldV != newV {
+			return errors.Errorf("cannot change immutable field %q", n)
+		}
+	}
+	return nil
+}
+
+func newConfig(attrs map[string]interface{}) (*backendConfig, error) {
+	cfg, err := coreconfig.NewConfig(attrs, configSchema, configDefaults)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &backendConfig{cfg.Attributes()}, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/secrets/provider"
+	_ "github.com/juju/juju/secrets/provider/all"
+	"github.com/juju/juju/secrets/provider/file"
+)
+
+type configSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&configSuite{})
+
+const (
+	testKey  = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+	otherKey = "Hx4dHBsaGRgXFhUUExIREA8ODQwLCgkIBwYFBAMCAQA="
+)
+
+func (s *configSuite) TestValidateConfig(c *gc.C) {
+	p, err := provider.Provider(file.BackendType)
+	c.Assert(err, jc.ErrorIsNil)
+	configValidator, ok := p.(provider.ProviderConfig)
+	c.Assert(ok, jc.IsTrue)
+	for i, t := range []struct {
+		cfg    map[string]interface{}
+		oldCfg map[string]interface{}
+		err    string
+	}{{
+		cfg: map[string]interface{}{"path": "/var/lib/juju/secrets", "key": testKey},
+	}, {
+		cfg: map[string]interface{}{"path": "/var/lib/juju/secrets", "key": testKey, "previous-keys": otherKey + "," + testKey},
+	}, {
+		cfg: map[string]interface{}{"key": testKey},
+		err: "path: expected string, got nothing",
+	}, {
+		cfg: map[string]interface{}{"path": "secrets", "key": testKey},
+		err: `relative path "secrets" not valid`,
+	}, {
+		cfg: map[string]interface{}{"path": "/var/lib/juju/secrets", "key": "AAEC"},
+		err: `parsing key: key of 3 bytes, expected 32 not valid`,
+	}, {
+		cfg: map[string]interface{}{"path": "/var/lib/juju/secrets", "key": "!!"},
+		err: `parsing key: key encoding not valid`,
+	}, {
+		cfg: map[string]interface{}{"path": "/var/lib/juju/secrets", "key": testKey, "previous-keys": "AAEC"},
+		err: `parsing previous key 0: key of 3 bytes, expected 32 not valid`,
+	}, {
+		cfg:    map[string]interface{}{"path": "/var/lib/juju/secrets", "key": testKey},
+		oldCfg: map[string]interface{}{"path": "/srv/secrets", "key": testKey},
+		err:    `cannot change immutable field "path"`,
+	}, {
+		cfg:    map[string]interface{}{"path": "/var/lib/juju/secrets", "key": otherKey},
+		oldCfg: map[string]interface{}{"path": "/var/lib/juju/secrets", "key": testKey},
+	}} {
+		c.Logf("test %d", i)
+		err = configValidator.ValidateConfig(t.oldCfg, t.cfg)
+		if t.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.err)
+		}
+	}
+}
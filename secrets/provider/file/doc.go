@@ -0,0 +1,18 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package file provides a secrets backend which stores secret content
+// as AES-GCM encrypted objects in a directory on the controller, so
+// the content is kept out of the controller database without needing
+// an external secrets store like vault.
+//
+// The key and the directory are only used by the controller. Agents
+// are given neither; they pass secret content through the secrets API,
+// as they do for the internal backend, and the controller encrypts and
+// saves it, or reads and decrypts it for the entities allowed to read
+// each secret.
+//
+// The directory is not replicated, so the backend may only be used on
+// a controller with a single API server; the controller refuses to add
+// a file backend when HA is enabled, and to enable HA while one exists.
+package file
@@ -0,0 +1,134 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// keySize is the size in bytes of the AES-256 keys.
+	keySize = 32
+
+	// keyIDSize is the size in bytes of the key ID stored with each
+	// encrypted object, identifying the key needed to decrypt it.
+	keyIDSize = 8
+
+	// formatVersion is the first byte of each encrypted object.
+	formatVersion = 1
+)
+
+// NewKey returns a new random key, base64 encoded as expected by the
+// key config attribute.
+func NewKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", errors.Trace(err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+type aead struct {
+	id []byte
+	cipher.AEAD
+}
+
+// keyring holds the key used to encrypt content and the keys which
+// may be used to decrypt it.
+type keyring struct {
+	current  aead
+	previous []aead
+}
+
+func newKeyring(key string, previous ...string) (*keyring, error) {
+	current, err := parseKey(key)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing key")
+	}
+	kr := &keyring{current: current}
+	for i, key := range previous {
+		k, err := parseKey(key)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing previous key %d", i)
+		}
+		kr.previous = append(kr.previous, k)
+	}
+	return kr, nil
+}
+
+func parseKey(value string) (aead, error) {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return aead{}, errors.NotValidf("key encoding")
+	}
+	if len(key) != keySize {
+		return aead{}, errors.NotValidf("key of %d bytes, expected %d", len(key), keySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return aead{}, errors.Trace(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return aead{}, errors.Trace(err)
+	}
+	sum := sha256.Sum256(key)
+	return aead{id: sum[:keyIDSize], AEAD: gcm}, nil
+}
+
+// encrypt seals the plaintext with the current key. The name of the
+// object is authenticated with the content, so an object can't be
+// swapped for another one.
+//
+// The format is: version (1 byte) | key ID | nonce | ciphertext.
+func (kr *keyring) encrypt(name string, plaintext []byte) ([]byte, error) {
+	k := kr.current
+	nonce := make([]byte, k.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Trace(err)
+	}
+	out := make([]byte, 0, 1+keyIDSize+len(nonce)+len(plaintext)+k.Overhead())
+	out = append(out, formatVersion)
+	out = append(out, k.id...)
+	out = append(out, nonce...)
+	return k.Seal(out, nonce, plaintext, []byte(name)), nil
+}
+
+// decrypt opens content sealed with any key in the keyring.
+func (kr *keyring) decrypt(name string, data []byte) ([]byte, error) {
+	if len(data) < 1+keyIDSize || data[0] != formatVersion {
+		return nil, errors.NotValidf("encrypted content for %q", name)
+	}
+	id := data[1 : 1+keyIDSize]
+	for _, k := range append([]aead{kr.current}, kr.previous...) {
+		if !bytes.Equal(id, k.id) {
+			continue
+		}
+		rest := data[1+keyIDSize:]
+		if len(rest) < k.NonceSize() {
+			return nil, errors.NotValidf("encrypted content for %q", name)
+		}
+		nonce, ciphertext := rest[:k.NonceSize()], rest[k.NonceSize():]
+		plaintext, err := k.Open(nil, nonce, ciphertext, []byte(name))
+		if err != nil {
+			return nil, errors.Annotatef(err, "decrypting content for %q", name)
+		}
+		return plaintext, nil
+	}
+	return nil, errors.NotFoundf("key to decrypt content for %q", name)
+}
+
+// isCurrent returns true if the content was encrypted with the current
+// key.
+func (kr *keyring) isCurrent(data []byte) bool {
+	return len(data) >= 1+keyIDSize && bytes.Equal(data[1:1+keyIDSize], kr.current.id)
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file
+
+import (
+	"context"
+	"os"
+	"path"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/secrets/provider"
+)
+
+var logger = loggo.GetLogger("juju.secrets.file")
+
+const (
+	// BackendType is the type of the encrypted file secrets backend.
+	BackendType = "file"
+)
+
+// NewProvider returns an encrypted file secrets provider.
+func NewProvider() provider.SecretBackendProvider {
+	return fileProvider{}
+}
+
+type fileProvider struct {
+}
+
+func (p fileProvider) Type() string {
+	return BackendType
+}
+
+// ControllerContent implements SupportControllerContent.
+// The key and the encrypted content never leave the controller.
+func (p fileProvider) ControllerContent() {}
+
+// Initialise creates the directory holding the encrypted content.
+func (p fileProvider) Initialise(cfg *provider.ModelBackendConfig) error {
+	validCfg, err := newConfig(cfg.Config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.MkdirAll(validCfg.path(), 0700))
+}
+
+// CleanupModel deletes all secret content belonging to the model.
+func (p fileProvider) CleanupModel(cfg *provider.ModelBackendConfig) error {
+	validCfg, err := newConfig(cfg.Config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store := NewObjectStore(validCfg.path())
+	return errors.Trace(store.RemoveAll(context.Background(), cfg.ModelUUID+"/"))
+}
+
+// CleanupSecrets deletes the content of the removed secret revisions.
+// There are no access policies to clean up; only the controller
+// accesses the content.
+func (p fileProvider) CleanupSecrets(cfg *provider.ModelBackendConfig, _ names.Tag, removed provider.SecretRevisions) error {
+	validCfg, err := newConfig(cfg.Config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store := NewObjectStore(validCfg.path())
+	ctx := context.Background()
+	for _, revisionIds := range removed {
+		for _, revisionId := range revisionIds.SortedValues() {
+			err := store.Delete(ctx, path.Join(cfg.ModelUUID, revisionId))
+			if err != nil && !errors.Is(err, errors.NotFound) {
+				return errors.Annotatef(err, "deleting secret %q", revisionId)
+			}
+		}
+	}
+	return nil
+}
+
+// RestrictedConfig returns the config needed to create a secrets
+// backend client. Only the controller reads and writes the content, so
+// the config has neither the key nor the path: the client passes
+// content through the secrets API, which checks the entity's access to
+// each secret.
+func (p fileProvider) RestrictedConfig(
+	_ *provider.ModelBackendConfig, _ bool, _ names.Tag, _ provider.SecretRevisions, _ provider.SecretRevisions,
+) (*provider.BackendConfig, error) {
+	return &provider.BackendConfig{
+		BackendType: BackendType,
+	}, nil
+}
+
+// NewBackend returns a secrets backend client.
+// Clients given the restricted config get a backend which leaves the
+// content to the controller.
+func (p fileProvider) NewBackend(cfg *provider.ModelBackendConfig) (provider.SecretsBackend, error) {
+	if _, ok := cfg.Config[KeyKey]; !ok {
+		return controllerBackend{}, nil
+	}
+	validCfg, err := newConfig(cfg.Config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys, err := validCfg.keyring()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &fileBackend{
+		root:      validCfg.path(),
+		modelUUID: cfg.ModelUUID,
+		store:     NewObjectStore(validCfg.path()),
+		keys:      keys,
+	}, nil
+}
+
+// RefreshAuth implements SupportAuthRefresh.
+// It rotates the encryption key, re-encrypting all the stored content
+// with a new key. The old key is kept in the previous keys so content
+// being written concurrently by controllers still using it can be read.
+func (p fileProvider) RefreshAuth(adminCfg *provider.ModelBackendConfig, _ time.Duration) (*provider.BackendConfig, error) {
+	validCfg, err := newConfig(adminCfg.Config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	oldKeys, err := validCfg.keyring()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	newKey, err := NewKey()
+	if err != nil {
+		return nil, errors.Annotate(err, "generating new key")
+	}
+	newKeys, err := newKeyring(newKey, append([]string{validCfg.key()}, validCfg.previousKeys()...)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := reencrypt(NewObjectStore(validCfg.path()), oldKeys, newKeys); err != nil {
+		return nil, errors.Annotate(err, "re-encrypting secret content")
+	}
+
+	cfgCopy := provider.BackendConfig{
+		BackendType: adminCfg.BackendType,
+		Config:      make(provider.ConfigAttrs),
+	}
+	for k, v := range adminCfg.Config {
+		cfgCopy.Config[k] = v
+	}
+	cfgCopy.Config[KeyKey] = newKey
+	// Only the key being replaced is kept; content encrypted with any
+	// older keys has now been re-encrypted.
+	cfgCopy.Config[PreviousKeysKey] = validCfg.key()
+	return &cfgCopy, nil
+}
+
+func reencrypt(store ObjectStore, oldKeys, newKeys *keyring) error {
+	ctx := context.Background()
+	names, err := store.List(ctx, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range names {
+		data, err := store.Get(ctx, name)
+		if errors.Is(err, errors.NotFound) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if newKeys.isCurrent(data) {
+			continue
+		}
+		plaintext, err := oldKeys.decrypt(name, data)
+		if err != nil {
+			logger.Warningf("cannot re-encrypt %q: %v", name, err)
+			continue
+		}
+		if data, err = newKeys.encrypt(name, plaintext); err != nil {
+			return errors.Trace(err)
+		}
+		if err := store.Put(ctx, name, data); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
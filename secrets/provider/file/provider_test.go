@@ -0,0 +1,235 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/secrets/provider"
+	_ "github.com/juju/juju/secrets/provider/all"
+	"github.com/juju/juju/secrets/provider/file"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type providerSuite struct {
+	testing.IsolationSuite
+
+	root string
+	p    provider.SecretBackendProvider
+}
+
+var _ = gc.Suite(&providerSuite{})
+
+func (s *providerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.root = filepath.Join(c.MkDir(), "secrets")
+	var err error
+	s.p, err = provider.Provider(file.BackendType)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *providerSuite) adminConfig() *provider.ModelBackendConfig {
+	return &provider.ModelBackendConfig{
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		ModelUUID:      coretesting.ModelTag.Id(),
+		ModelName:      "fred",
+		BackendConfig: provider.BackendConfig{
+			BackendType: file.BackendType,
+			Config: map[string]interface{}{
+				"path": s.root,
+				"key":  testKey,
+			},
+		},
+	}
+}
+
+func (s *providerSuite) newBackend(c *gc.C, cfg *provider.ModelBackendConfig) provider.SecretsBackend {
+	err := s.p.Initialise(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	b, err := s.p.NewBackend(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return b
+}
+
+func (s *providerSuite) TestSaveGetDeleteContent(c *gc.C) {
+	b := s.newBackend(c, s.adminConfig())
+	c.Assert(b.Ping(), jc.ErrorIsNil)
+
+	uri := secrets.NewURI()
+	ctx := context.Background()
+	revisionId, err := b.SaveContent(ctx, uri, 1, secrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revisionId, gc.Equals, uri.ID+"-1")
+
+	// The content is encrypted at rest.
+	data, err := os.ReadFile(filepath.Join(s.root, coretesting.ModelTag.Id(), revisionId))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Not(jc.Contains), "YmFy")
+
+	val, err := b.GetContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(val.EncodedValues(), jc.DeepEquals, map[string]string{"foo": "YmFy"})
+
+	err = b.DeleteContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = b.GetContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+	err = b.DeleteContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+}
+
+func (s *providerSuite) TestContentSharedBetweenBackends(c *gc.C) {
+	cfg := s.adminConfig()
+	writer := s.newBackend(c, cfg)
+	reader, err := s.p.NewBackend(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	uri := secrets.NewURI()
+	ctx := context.Background()
+	revisionId, err := writer.SaveContent(ctx, uri, 1, secrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	val, err := reader.GetContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(val.EncodedValues(), jc.DeepEquals, map[string]string{"foo": "YmFy"})
+
+	err = reader.DeleteContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = writer.GetContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+}
+
+func (s *providerSuite) TestGetContentWrongKey(c *gc.C) {
+	cfg := s.adminConfig()
+	b := s.newBackend(c, cfg)
+	uri := secrets.NewURI()
+	revisionId, err := b.SaveContent(context.Background(), uri, 1, secrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg.Config["key"] = otherKey
+	b = s.newBackend(c, cfg)
+	_, err = b.GetContent(context.Background(), revisionId)
+	c.Assert(err, gc.ErrorMatches, `key to decrypt content for ".*" not found`)
+}
+
+func (s *providerSuite) TestRestrictedConfig(c *gc.C) {
+	adminCfg := s.adminConfig()
+	cfg, err := s.p.RestrictedConfig(adminCfg, false, names.NewUnitTag("ubuntu/0"), nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BackendType, gc.Equals, file.BackendType)
+	// Neither the key nor the path are given out.
+	c.Assert(cfg.Config, gc.HasLen, 0)
+}
+
+func (s *providerSuite) TestRestrictedBackend(c *gc.C) {
+	c.Assert(provider.HasControllerContent(s.p), jc.IsTrue)
+	adminCfg := s.adminConfig()
+	b := s.newBackend(c, adminCfg)
+	uri := secrets.NewURI()
+	ctx := context.Background()
+	revisionId, err := b.SaveContent(ctx, uri, 1, secrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := s.p.RestrictedConfig(adminCfg, false, names.NewUnitTag("ubuntu/0"), nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	restricted, err := s.p.NewBackend(&provider.ModelBackendConfig{
+		ControllerUUID: adminCfg.ControllerUUID,
+		ModelUUID:      adminCfg.ModelUUID,
+		ModelName:      adminCfg.ModelName,
+		BackendConfig:  *cfg,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted.Ping(), jc.ErrorIsNil)
+
+	// The content is only accessed by the controller.
+	_, err = restricted.SaveContent(ctx, uri, 2, secrets.NewSecretValue(map[string]string{"foo": "YmF6"}))
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+	_, err = restricted.GetContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+	err = restricted.DeleteContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+	_, err = b.GetContent(ctx, revisionId)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *providerSuite) TestCleanupSecrets(c *gc.C) {
+	cfg := s.adminConfig()
+	b := s.newBackend(c, cfg)
+	uri := secrets.NewURI()
+	ctx := context.Background()
+	rev1, err := b.SaveContent(ctx, uri, 1, secrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+	rev2, err := b.SaveContent(ctx, uri, 2, secrets.NewSecretValue(map[string]string{"foo": "YmF6"}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.p.CleanupSecrets(cfg, names.NewUnitTag("ubuntu/0"), provider.SecretRevisions{
+		uri.ID: set.NewStrings(rev1, uri.ID+"-3"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = b.GetContent(ctx, rev1)
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+	_, err = b.GetContent(ctx, rev2)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *providerSuite) TestCleanupModel(c *gc.C) {
+	cfg := s.adminConfig()
+	b := s.newBackend(c, cfg)
+	otherCfg := s.adminConfig()
+	otherCfg.ModelUUID = "c1c7c33a-9e6b-4f8a-8d0e-3b6e5c8c1a7d"
+	other := s.newBackend(c, otherCfg)
+
+	uri := secrets.NewURI()
+	ctx := context.Background()
+	rev, err := b.SaveContent(ctx, uri, 1, secrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+	otherRev, err := other.SaveContent(ctx, uri, 1, secrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.p.CleanupModel(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = b.GetContent(ctx, rev)
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+	_, err = other.GetContent(ctx, otherRev)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *providerSuite) TestRefreshAuth(c *gc.C) {
+	cfg := s.adminConfig()
+	b := s.newBackend(c, cfg)
+	uri := secrets.NewURI()
+	ctx := context.Background()
+	rev, err := b.SaveContent(ctx, uri, 1, secrets.NewSecretValue(map[string]string{"foo": "YmFy"}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	refresher, ok := s.p.(provider.SupportAuthRefresh)
+	c.Assert(ok, jc.IsTrue)
+	newCfg, err := refresher.RefreshAuth(cfg, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newCfg.Config["key"], gc.Not(gc.Equals), testKey)
+	c.Assert(newCfg.Config["previous-keys"], gc.Equals, testKey)
+	c.Assert(cfg.Config["key"], gc.Equals, testKey)
+
+	// The content is readable with only the new key.
+	rotatedCfg := s.adminConfig()
+	rotatedCfg.Config = map[string]interface{}{
+		"path": s.root,
+		"key":  newCfg.Config["key"],
+	}
+	b = s.newBackend(c, rotatedCfg)
+	val, err := b.GetContent(ctx, rev)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(val.EncodedValues(), jc.DeepEquals, map[string]string{"foo": "YmFy"})
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v3"
+)
+
+// ObjectStore stores the encrypted secret content. Object names are
+// slash separated paths.
+type ObjectStore interface {
+	// Put stores the object, replacing any existing object with the
+	// same name.
+	Put(ctx context.Context, name string, data []byte) error
+
+	// Get returns the object, or a NotFound error if it doesn't
+	// exist.
+	Get(ctx context.Context, name string) ([]byte, error)
+
+	// Delete removes the object, returning a NotFound error if it
+	// doesn't exist.
+	Delete(ctx context.Context, name string) error
+
+	// List returns the names of the objects with the given prefix,
+	// sorted by name.
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// RemoveAll removes all the objects with the given prefix.
+	RemoveAll(ctx context.Context, prefix string) error
+}
+
+// NewObjectStore is patched for testing.
+var NewObjectStore = func(root string) ObjectStore {
+	return dirStore{root: root}
+}
+
+// dirStore is an ObjectStore which keeps each object in a file below
+// its root directory.
+type dirStore struct {
+	root string
+}
+
+func (s dirStore) path(name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if name == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", errors.NotValidf("object name %q", name)
+	}
+	return filepath.Join(s.root, clean), nil
+}
+
+// Put implements ObjectStore.
+func (s dirStore) Put(_ context.Context, name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	// Write atomically so readers never see partial content.
+	return errors.Trace(utils.AtomicWriteFile(path, data, 0600))
+}
+
+// Get implements ObjectStore.
+func (s dirStore) Get(_ context.Context, name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("object %q", name)
+	}
+	return data, errors.Trace(err)
+}
+
+// Delete implements ObjectStore.
+func (s dirStore) Delete(_ context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return errors.NotFoundf("object %q", name)
+	}
+	return errors.Trace(err)
+}
+
+// List implements ObjectStore.
+func (s dirStore) List(_ context.Context, prefix string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		if name := filepath.ToSlash(rel); strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RemoveAll implements ObjectStore.
+func (s dirStore) RemoveAll(ctx context.Context, prefix string) error {
+	names, err := s.List(ctx, prefix)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range names {
+		if err := s.Delete(ctx, name); err != nil && !errors.Is(err, errors.NotFound) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -100,3 +100,20 @@ func HasAuthRefresh(p SecretBackendProvider) bool {
 	_, ok := p.(SupportAuthRefresh)
 	return ok
 }
+
+// SupportControllerContent is implemented by providers whose secret
+// content is only read and written by the controller. Agents are not
+// given access to the backend; they pass secret content through the
+// secrets API, as they do for the internal backend, and the controller
+// saves it to and reads it from the backend.
+type SupportControllerContent interface {
+	// ControllerContent is a marker method.
+	ControllerContent()
+}
+
+// HasControllerContent returns true if the provider's secret content
+// is only accessed by the controller.
+func HasControllerContent(p SecretBackendProvider) bool {
+	_, ok := p.(SupportControllerContent)
+	return ok
+}
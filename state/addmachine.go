@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/storage"
 )
@@ -111,21 +112,26 @@ type HostFilesystemParams struct {
 // of the given type inside another new machine. The two given templates
 // specify the form of the child and parent respectively.
 func (st *State) AddMachineInsideNewMachine(template, parentTemplate MachineTemplate, containerType instance.ContainerType) (*Machine, error) {
-	mdoc, ops, err := st.addMachineInsideNewMachineOps(template, parentTemplate, containerType)
-	if err != nil {
-		return nil, errors.Annotate(err, "cannot add a new machine")
-	}
-	return st.addMachine(mdoc, ops)
+	return st.addMachine(func() (*machineDoc, []txn.Op, quota.ModelResources, error) {
+		mdoc, ops, err := st.addMachineInsideNewMachineOps(template, parentTemplate, containerType)
+		if err != nil {
+			return nil, nil, quota.ModelResources{}, errors.Trace(err)
+		}
+		requested, err := st.machineQuotaResources(parentTemplate)
+		if err != nil {
+			return nil, nil, quota.ModelResources{}, errors.Trace(err)
+		}
+		return mdoc, ops, requested.Add(containerQuotaResources(template)), nil
+	})
 }
 
 // AddMachineInsideMachine adds a machine inside a container of the
 // given type on the existing machine with id=parentId.
 func (st *State) AddMachineInsideMachine(template MachineTemplate, parentId string, containerType instance.ContainerType) (*Machine, error) {
-	mdoc, ops, err := st.addMachineInsideMachineOps(template, parentId, containerType)
-	if err != nil {
-		return nil, errors.Annotate(err, "cannot add a new machine")
-	}
-	return st.addMachine(mdoc, ops)
+	return st.addMachine(func() (*machineDoc, []txn.Op, quota.ModelResources, error) {
+		mdoc, ops, err := st.addMachineInsideMachineOps(template, parentId, containerType)
+		return mdoc, ops, containerQuotaResources(template), errors.Trace(err)
+	})
 }
 
 // AddMachine adds a machine with the given series and jobs.
@@ -156,44 +162,77 @@ func (st *State) AddOneMachine(template MachineTemplate) (*Machine, error) {
 func (st *State) AddMachines(templates ...MachineTemplate) (_ []*Machine, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add a new machine")
 	var ms []*Machine
-	var ops []txn.Op
-	var controllerIds []string
-	for _, template := range templates {
-		mdoc, addOps, err := st.addMachineOps(template)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := checkModelActive(st); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ms = nil
+		var ops []txn.Op
+		var controllerIds []string
+		var requested quota.ModelResources
+		for _, template := range templates {
+			mdoc, addOps, err := st.addMachineOps(template)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if isController(mdoc) {
+				controllerIds = append(controllerIds, mdoc.Id)
+			}
+			ms = append(ms, newMachine(st, mdoc))
+			ops = append(ops, addOps...)
+
+			machineResources, err := st.machineQuotaResources(template)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			requested = requested.Add(machineResources)
+		}
+		ssOps, err := st.maintainControllersOps(controllerIds, true)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		if isController(mdoc) {
-			controllerIds = append(controllerIds, mdoc.Id)
+		ops = append(ops, ssOps...)
+		quotaOps, err := st.modelQuotaOps(requested)
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
-		ms = append(ms, newMachine(st, mdoc))
-		ops = append(ops, addOps...)
+		ops = append(ops, quotaOps...)
+		return append(ops, assertModelActiveOp(st.ModelUUID())), nil
 	}
-	ssOps, err := st.maintainControllersOps(controllerIds, true)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	ops = append(ops, ssOps...)
-	ops = append(ops, assertModelActiveOp(st.ModelUUID()))
-	if err := st.db().RunTransaction(ops); err != nil {
-		if errors.Cause(err) == txn.ErrAborted {
-			if err := checkModelActive(st); err != nil {
-				return nil, errors.Trace(err)
-			}
-		}
+	if err := st.db().Run(buildTxn); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return ms, nil
 }
 
-func (st *State) addMachine(mdoc *machineDoc, ops []txn.Op) (*Machine, error) {
-	ops = append([]txn.Op{assertModelActiveOp(st.ModelUUID())}, ops...)
-	if err := st.db().RunTransaction(ops); err != nil {
-		if errors.Cause(err) == txn.ErrAborted {
+// addMachine adds the machine whose document and operations are
+// returned by machineOps, along with the resources it consumes.
+func (st *State) addMachine(machineOps func() (*machineDoc, []txn.Op, quota.ModelResources, error)) (*Machine, error) {
+	var mdoc *machineDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
 			if err := checkModelActive(st); err != nil {
 				return nil, errors.Trace(err)
 			}
 		}
+		var (
+			ops       []txn.Op
+			requested quota.ModelResources
+			err       error
+		)
+		if mdoc, ops, requested, err = machineOps(); err != nil {
+			return nil, errors.Annotate(err, "cannot add a new machine")
+		}
+		quotaOps, err := st.modelQuotaOps(requested)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot add a new machine")
+		}
+		ops = append([]txn.Op{assertModelActiveOp(st.ModelUUID())}, ops...)
+		return append(ops, quotaOps...), nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return newMachine(st, mdoc), nil
@@ -120,6 +120,41 @@ func allCollections() CollectionSchema {
 			global: true,
 		},
 
+		// This collection holds groups of users, which may be granted
+		// access to models, clouds, offers and the controller.
+		userGroupsC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"members"},
+			}},
+		},
+
+		// This collection holds the custom roles, each a named set of
+		// API capabilities, that may be granted to users on models.
+		rolesC: {global: true},
+
+		// These collections hold the audit log of API conversations
+		// and the requests made in them. Old records are pruned by
+		// the audit config updater.
+		auditConversationsC: {
+			global:    true,
+			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"when"},
+			}},
+		},
+		auditLogC: {
+			global:    true,
+			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"when"},
+			}, {
+				Key: []string{"who", "when"},
+			}, {
+				Key: []string{"model-uuid", "when"},
+			}},
+		},
+
 		// This collection holds the last time the user connected to the API server.
 		userLastLoginC: {
 			global:    true,
@@ -360,6 +395,11 @@ func allCollections() CollectionSchema {
 			}},
 		},
 		volumeAttachmentPlanC: {},
+		volumeSnapshotsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "storageid"},
+			}},
+		},
 
 		// -----
 
@@ -465,6 +505,9 @@ func allCollections() CollectionSchema {
 		},
 		storageConstraintsC: {},
 		deviceConstraintsC:  {},
+
+		// This collection holds the resource quotas of each model.
+		modelQuotasC: {},
 		statusesC: {
 			indexes: []mgo.Index{{
 				Key: []string{"model-uuid", "_id"},
@@ -601,6 +644,8 @@ const (
 	annotationsC               = "annotations"
 	autocertCacheC             = "autocertCache"
 	assignUnitC                = "assignUnits"
+	auditConversationsC        = "auditconversations"
+	auditLogC                  = "auditlog"
 	bakeryStorageItemsC        = "bakeryStorageItems"
 	blockDevicesC              = "blockdevices"
 	blocksC                    = "blocks"
@@ -638,6 +683,7 @@ const (
 	modelUsersC                = "modelusers"
 	modelsC                    = "models"
 	modelEntityRefsC           = "modelEntityRefs"
+	modelQuotasC               = "modelquotas"
 	openedPortsC               = "openedPorts"
 	operationsC                = "operations"
 	payloadsC                  = "payloads"
@@ -647,6 +693,7 @@ const (
 	rebootC                    = "reboot"
 	relationScopesC            = "relationscopes"
 	relationsC                 = "relations"
+	rolesC                     = "roles"
 	sequenceC                  = "sequence"
 	applicationsC              = "applications"
 	endpointBindingsC          = "endpointbindings"
@@ -670,12 +717,14 @@ const (
 	unitsC                     = "units"
 	unitStatesC                = "unitstates"
 	upgradeInfoC               = "upgradeInfo"
+	userGroupsC                = "usergroups"
 	userLastLoginC             = "userLastLogin"
 	usermodelnameC             = "usermodelname"
 	usersC                     = "users"
 	volumeAttachmentsC         = "volumeattachments"
 	volumeAttachmentPlanC      = "volumeattachmentplan"
 	volumesC                   = "volumes"
+	volumeSnapshotsC           = "volumesnapshots"
 
 	// Cross model relations
 	applicationOffersC   = "applicationOffers"
@@ -8,9 +8,11 @@ import (
 	"strings"
 
 	"github.com/juju/charm/v12"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/mgo/v3"
 	"github.com/juju/mgo/v3/bson"
+	"github.com/juju/names/v4"
 
 	corebase "github.com/juju/juju/core/base"
 	"github.com/juju/juju/core/constraints"
@@ -105,6 +107,11 @@ func makeAllWatcherCollectionInfo(collNames []string) map[string]allWatcherState
 			// Permissions are attached to the Model that they are for.
 			collection.docType = reflect.TypeOf(backingPermission{})
 			collection.subsidiary = true
+		case userGroupsC:
+			// User groups are only watched to update the permissions
+			// of the models that they have been granted access to.
+			collection.docType = reflect.TypeOf(backingUserGroup{})
+			collection.subsidiary = true
 		case podSpecsC:
 			collection.docType = reflect.TypeOf(backingPodSpec{})
 			collection.subsidiary = true
@@ -232,7 +239,10 @@ func (e *backingModel) mongoID() string {
 
 type backingPermission permissionDoc
 
-func (e *backingPermission) modelAndUser(id string) (string, string, bool) {
+// modelAndSubject returns the model UUID, and the subject global key,
+// of a model permission document ID. The subject is either a user or
+// a user group.
+func modelAndSubject(id string) (string, string, bool) {
 	parts := strings.Split(id, "#")
 
 	if len(parts) < 4 {
@@ -240,72 +250,156 @@ func (e *backingPermission) modelAndUser(id string) (string, string, bool) {
 		return "", "", false
 	}
 
-	// At this stage, we are only dealing with model user permissions.
-	if parts[0] != modelGlobalKey || parts[2] != userGlobalKeyPrefix {
+	// At this stage, we are only dealing with model permissions.
+	if parts[0] != modelGlobalKey {
 		return "", "", false
 	}
-	return parts[1], parts[3], true
+	switch parts[2] {
+	case userGlobalKeyPrefix, userGroupGlobalKeyPrefix:
+		return parts[1], strings.Join(parts[2:], "#"), true
+	}
+	return "", "", false
 }
 
 func (e *backingPermission) updated(ctx *allWatcherContext) error {
 	allWatcherLogger.Tracef(`permission "%s" updated`, ctx.id)
+	return errors.Trace(e.updateModel(ctx))
+}
+
+func (e *backingPermission) removed(ctx *allWatcherContext) error {
+	allWatcherLogger.Tracef(`permission "%s" removed`, ctx.id)
+	return errors.Trace(e.updateModel(ctx))
+}
 
-	modelUUID, user, ok := e.modelAndUser(ctx.id)
+// updateModel updates the permissions, in the store, of the users
+// affected by the change to the permission.
+func (e *backingPermission) updateModel(ctx *allWatcherContext) error {
+	modelUUID, subject, ok := modelAndSubject(ctx.id)
 	if !ok {
 		// Not valid for as far as we care about.
 		return nil
 	}
-
-	info := e.getModelInfo(ctx, modelUUID)
-	if info == nil {
-		return nil
+	if !strings.HasPrefix(subject, userGroupGlobalKeyPrefix+"#") {
+		return errors.Trace(ctx.updateModelUserPermissions(modelUUID, userIDFromGlobalKey(subject)))
 	}
+	members, err := ctx.userGroupMembers([]string{subject})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ctx.updateModelGroupPermissions(modelUUID, members[subject]))
+}
 
-	// Set the access for the user in the permission map of the model.
-	info.UserPermissions[user] = permission.Access(e.Access)
+func (e *backingPermission) mongoID() string {
+	allWatcherLogger.Criticalf("programming error: attempting to get mongoID from permissions document")
+	return ""
+}
 
-	ctx.store.Update(info)
+// backingUserGroup is only watched so that changes to the members of
+// a group are reflected in the permissions of the models that the
+// group has been granted access to.
+type backingUserGroup userGroupDoc
+
+func (e *backingUserGroup) updated(ctx *allWatcherContext) error {
+	allWatcherLogger.Tracef(`user group "%s" updated`, ctx.id)
+
+	col, closer := ctx.state.db().GetCollection(permissionsC)
+	defer closer()
+
+	var docs []permissionDoc
+	if err := col.Find(bson.D{
+		{"subject-global-key", userGroupGlobalKey(ctx.id)},
+		{"object-global-key", bson.D{{"$regex", "^" + modelGlobalKey + "#"}}},
+	}).All(&docs); err != nil {
+		return errors.Annotatef(err, "cannot read permissions of user group %q", ctx.id)
+	}
+	for _, doc := range docs {
+		modelUUID, _, ok := modelAndSubject(doc.ID)
+		if !ok {
+			continue
+		}
+		if err := ctx.updateModelGroupPermissions(modelUUID, e.Members); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return nil
 }
 
-func (e *backingPermission) removed(ctx *allWatcherContext) error {
-	allWatcherLogger.Tracef(`permission "%s" removed`, ctx.id)
+func (e *backingUserGroup) removed(ctx *allWatcherContext) error {
+	allWatcherLogger.Tracef(`user group "%s" removed`, ctx.id)
+	// The group's permissions are removed along with it, and the
+	// models are updated as each of those is removed.
+	return nil
+}
 
-	modelUUID, user, ok := e.modelAndUser(ctx.id)
-	if !ok {
-		// Not valid for as far as we care about.
+func (e *backingUserGroup) mongoID() string {
+	allWatcherLogger.Criticalf("programming error: attempting to get mongoID from user group document")
+	return ""
+}
+
+// updateModelGroupPermissions updates the permissions, in the store,
+// of the users affected by a change to a group's access to the model,
+// or to the group's members. Those are the group's current members,
+// and any users who were members before, who can only be found among
+// the users who already have access.
+func (ctx *allWatcherContext) updateModelGroupPermissions(modelUUID string, members []string) error {
+	info := ctx.modelInfo(modelUUID)
+	if info == nil {
 		return nil
 	}
+	users := set.NewStrings(members...)
+	for user := range info.UserPermissions {
+		users.Add(user)
+	}
+	return errors.Trace(ctx.updateModelUserPermissions(modelUUID, users.SortedValues()...))
+}
 
-	info := e.getModelInfo(ctx, modelUUID)
+// updateModelUserPermissions updates the permissions of the users, in
+// the store, to the access that the users have to the model directly
+// or through the groups they're members of.
+func (ctx *allWatcherContext) updateModelUserPermissions(modelUUID string, users ...string) error {
+	info := ctx.modelInfo(modelUUID)
 	if info == nil {
 		return nil
 	}
-
-	delete(info.UserPermissions, user)
+	if info.UserPermissions == nil {
+		info.UserPermissions = make(map[string]permission.Access)
+	}
+	modelTag := names.NewModelTag(modelUUID)
+	for _, user := range users {
+		var access permission.Access
+		perm, err := ctx.state.userPermission(modelKey(modelUUID), userGlobalKey(user))
+		if err == nil {
+			access = perm.access()
+		} else if !errors.Is(err, errors.NotFound) {
+			return errors.Trace(err)
+		}
+		groupAccess, err := ctx.state.userGroupsPermission(names.NewUserTag(user), modelTag)
+		if err == nil {
+			access = greaterAccess(names.ModelTagKind, access, groupAccess)
+		} else if !errors.Is(err, errors.NotFound) {
+			return errors.Trace(err)
+		}
+		if access == permission.NoAccess {
+			delete(info.UserPermissions, user)
+		} else {
+			info.UserPermissions[user] = access
+		}
+	}
 
 	ctx.store.Update(info)
 	return nil
 }
 
-func (e *backingPermission) getModelInfo(ctx *allWatcherContext, modelUUID string) *multiwatcher.ModelInfo {
+// modelInfo returns the model with the given UUID from the store, or
+// nil if the model isn't there.
+func (ctx *allWatcherContext) modelInfo(modelUUID string) *multiwatcher.ModelInfo {
 	// NOTE: we can't use the modelUUID from the ctx here because it is the
 	// modelUUID of the system state.
 	storeKey := &multiwatcher.ModelInfo{
 		ModelUUID: modelUUID,
 	}
-	info0 := ctx.store.Get(storeKey.EntityID())
-	switch info := info0.(type) {
-	case *multiwatcher.ModelInfo:
-		return info
-	}
-	// In all other cases, which really should be never, return nil.
-	return nil
-}
-
-func (e *backingPermission) mongoID() string {
-	allWatcherLogger.Criticalf("programming error: attempting to get mongoID from permissions document")
-	return ""
+	info, _ := ctx.store.Get(storeKey.EntityID()).(*multiwatcher.ModelInfo)
+	return info
 }
 
 type backingMachine machineDoc
@@ -1654,6 +1748,7 @@ func NewAllWatcherBacking(pool *StatePool) (AllWatcherBacking, error) {
 		remoteApplicationsC,
 		statusesC,
 		settingsC,
+		userGroupsC,
 		// And for CAAS we need to watch these...
 		podSpecsC,
 	}
@@ -1784,8 +1879,9 @@ func (b *allWatcherBacking) idForChange(change watcher.Change) (string, string,
 	if change.C == modelsC {
 		modelUUID := change.Id.(string)
 		return modelUUID, modelUUID, nil
-	} else if change.C == permissionsC {
-		// All permissions can just load using the system state.
+	} else if change.C == permissionsC || change.C == userGroupsC {
+		// All permissions and user groups can just load using the
+		// system state.
 		systemState, err := b.stPool.SystemState()
 		if err != nil {
 			return "", "", errors.Trace(err)
@@ -2027,25 +2123,15 @@ func (ctx *allWatcherContext) loadPermissions() error {
 	col, closer := ctx.state.db().GetCollection(permissionsC)
 	defer closer()
 
-	var docs []backingPermission
+	var docs []permissionDoc
 	if err := col.Find(nil).All(&docs); err != nil {
 		return errors.Annotate(err, "cannot read all permissions")
 	}
-
-	ctx.userAccess = make(map[string]map[string]permission.Access)
-	for _, doc := range docs {
-		modelUUID, user, ok := doc.modelAndUser(doc.ID)
-		if !ok {
-			continue
-		}
-		modelPermissions := ctx.userAccess[modelUUID]
-		if modelPermissions == nil {
-			modelPermissions = make(map[string]permission.Access)
-			ctx.userAccess[modelUUID] = modelPermissions
-		}
-		modelPermissions[user] = permission.Access(doc.Access)
+	groupMembers, err := ctx.userGroupMembers(nil)
+	if err != nil {
+		return errors.Trace(err)
 	}
-
+	ctx.userAccess = modelUserAccess(docs, groupMembers)
 	return nil
 }
 
@@ -2174,18 +2260,89 @@ func (ctx *allWatcherContext) permissionsForModel(uuid string) (map[string]permi
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	result := make(map[string]permission.Access)
-	for _, perm := range permissions {
-		user := userIDFromGlobalKey(perm.doc.SubjectGlobalKey)
-		if user == perm.doc.SubjectGlobalKey {
-			// Not a user subject
-			continue
+	docs := make([]permissionDoc, len(permissions))
+	var groupKeys []string
+	for i, perm := range permissions {
+		docs[i] = perm.doc
+		if strings.HasPrefix(perm.doc.SubjectGlobalKey, userGroupGlobalKeyPrefix+"#") {
+			groupKeys = append(groupKeys, perm.doc.SubjectGlobalKey)
+		}
+	}
+	var groupMembers map[string][]string
+	if len(groupKeys) > 0 {
+		groupMembers, err = ctx.userGroupMembers(groupKeys)
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
-		result[user] = perm.access()
+	}
+	result := modelUserAccess(docs, groupMembers)[uuid]
+	if result == nil {
+		result = make(map[string]permission.Access)
 	}
 	return result, nil
 }
 
+// userGroupMembers returns the members of the groups with the given
+// subject global keys, or of all groups if keys is nil, keyed by the
+// groups' subject global keys.
+func (ctx *allWatcherContext) userGroupMembers(keys []string) (map[string][]string, error) {
+	col, closer := ctx.state.db().GetCollection(userGroupsC)
+	defer closer()
+
+	var query bson.D
+	if keys != nil {
+		ids := make([]string, len(keys))
+		for i, key := range keys {
+			ids[i] = strings.TrimPrefix(key, userGroupGlobalKeyPrefix+"#")
+		}
+		query = bson.D{{"_id", bson.D{{"$in", ids}}}}
+	}
+	var docs []userGroupDoc
+	if err := col.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read user groups")
+	}
+	result := make(map[string][]string)
+	for _, doc := range docs {
+		result[userGroupGlobalKey(doc.DocID)] = doc.Members
+	}
+	return result, nil
+}
+
+// modelUserAccess returns the access that users have to the models
+// the permissions are on, keyed by model UUID and then user id. Access
+// granted to a group applies to each of the group's members, which
+// are keyed by the group's subject global key; users that have been
+// granted access both directly and through groups get the greatest.
+func modelUserAccess(docs []permissionDoc, groupMembers map[string][]string) map[string]map[string]permission.Access {
+	result := make(map[string]map[string]permission.Access)
+	for _, doc := range docs {
+		modelUUID, subject, ok := modelAndSubject(doc.ID)
+		if !ok {
+			continue
+		}
+		var users []string
+		if strings.HasPrefix(subject, userGroupGlobalKeyPrefix+"#") {
+			users = groupMembers[subject]
+		} else {
+			users = []string{userIDFromGlobalKey(subject)}
+		}
+		if len(users) == 0 {
+			continue
+		}
+		modelPermissions := result[modelUUID]
+		if modelPermissions == nil {
+			modelPermissions = make(map[string]permission.Access)
+			result[modelUUID] = modelPermissions
+		}
+		for _, user := range users {
+			modelPermissions[user] = greaterAccess(
+				names.ModelTagKind, modelPermissions[user], stringToAccess(doc.Access),
+			)
+		}
+	}
+	return result
+}
+
 func (ctx *allWatcherContext) getUnitPortRangesByEndpoint(unit *Unit) (network.GroupedPortRanges, error) {
 	if unit.ShouldBeAssigned() {
 		machineID, err := unit.AssignedMachineId()
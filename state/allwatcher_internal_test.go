@@ -1671,10 +1671,123 @@ func testChangePermissions(c *gc.C, runChangeTests func(*gc.C, []changeTestFunc)
 					},
 				}}}
 		},
+
+		func(c *gc.C, st *State) changeTestCase {
+			model, err := st.Model()
+			c.Assert(err, jc.ErrorIsNil)
+			carol := ensureUserGroupMember(c, st, "ops", "carol")
+			err = st.SetUserGroupAccess("ops", model.ModelTag(), permission.WriteAccess)
+			c.Assert(err, jc.ErrorIsNil)
+
+			return changeTestCase{
+				about: "granting a group access updates its members' permissions",
+				initialContents: []multiwatcher.EntityInfo{&multiwatcher.ModelInfo{
+					ModelUUID: st.ModelUUID(),
+					Name:      model.Name(),
+					UserPermissions: map[string]permission.Access{
+						model.Owner().Id(): permission.AdminAccess,
+					},
+				}},
+				change: watcher.Change{
+					C:  permissionsC,
+					Id: permissionID(modelKey(st.ModelUUID()), userGroupGlobalKey("ops")),
+				},
+				expectContents: []multiwatcher.EntityInfo{&multiwatcher.ModelInfo{
+					ModelUUID: st.ModelUUID(),
+					Name:      model.Name(),
+					UserPermissions: map[string]permission.Access{
+						model.Owner().Id(): permission.AdminAccess,
+						carol.Id():         permission.WriteAccess,
+					},
+				}}}
+		},
+
+		func(c *gc.C, st *State) changeTestCase {
+			model, err := st.Model()
+			c.Assert(err, jc.ErrorIsNil)
+			carol := ensureUserGroupMember(c, st, "ops", "carol")
+			err = st.SetUserGroupAccess("ops", model.ModelTag(), permission.AdminAccess)
+			c.Assert(err, jc.ErrorIsNil)
+
+			return changeTestCase{
+				about: "adding a member to a group with access updates the member's permission",
+				initialContents: []multiwatcher.EntityInfo{&multiwatcher.ModelInfo{
+					ModelUUID: st.ModelUUID(),
+					Name:      model.Name(),
+					UserPermissions: map[string]permission.Access{
+						model.Owner().Id(): permission.AdminAccess,
+					},
+				}},
+				change: watcher.Change{
+					C:  userGroupsC,
+					Id: "ops",
+				},
+				expectContents: []multiwatcher.EntityInfo{&multiwatcher.ModelInfo{
+					ModelUUID: st.ModelUUID(),
+					Name:      model.Name(),
+					UserPermissions: map[string]permission.Access{
+						model.Owner().Id(): permission.AdminAccess,
+						carol.Id():         permission.AdminAccess,
+					},
+				}}}
+		},
+
+		func(c *gc.C, st *State) changeTestCase {
+			model, err := st.Model()
+			c.Assert(err, jc.ErrorIsNil)
+			carol := ensureUserGroupMember(c, st, "ops", "carol")
+			err = st.SetUserGroupAccess("ops", model.ModelTag(), permission.WriteAccess)
+			c.Assert(err, jc.ErrorIsNil)
+			group, err := st.UserGroup("ops")
+			c.Assert(err, jc.ErrorIsNil)
+			err = group.RemoveMembers(carol)
+			c.Assert(err, jc.ErrorIsNil)
+
+			return changeTestCase{
+				about: "removing a member from a group removes the member's permission",
+				initialContents: []multiwatcher.EntityInfo{&multiwatcher.ModelInfo{
+					ModelUUID: st.ModelUUID(),
+					Name:      model.Name(),
+					UserPermissions: map[string]permission.Access{
+						model.Owner().Id(): permission.AdminAccess,
+						carol.Id():         permission.WriteAccess,
+					},
+				}},
+				change: watcher.Change{
+					C:  userGroupsC,
+					Id: "ops",
+				},
+				expectContents: []multiwatcher.EntityInfo{&multiwatcher.ModelInfo{
+					ModelUUID: st.ModelUUID(),
+					Name:      model.Name(),
+					UserPermissions: map[string]permission.Access{
+						model.Owner().Id(): permission.AdminAccess,
+					},
+				}}}
+		},
 	}
 	runChangeTests(c, changeTestFuncs)
 }
 
+// ensureUserGroupMember ensures that the named local user exists and
+// is a member of the named group. As change test functions may be
+// called more than once in the same test, either may already exist.
+func ensureUserGroupMember(c *gc.C, st *State, groupName, userName string) names.UserTag {
+	user, err := st.User(names.NewUserTag(userName))
+	if errors.IsNotFound(err) {
+		user, err = st.AddUser(userName, "", "pwd", "admin")
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	group, err := st.UserGroup(groupName)
+	if errors.IsNotFound(err) {
+		group, err = st.AddUserGroup(groupName, false, "admin")
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	return user.UserTag()
+}
+
 func testChangeAnnotations(c *gc.C, runChangeTests func(*gc.C, []changeTestFunc)) {
 	changeTestFuncs := []changeTestFunc{
 		func(c *gc.C, st *State) changeTestCase {
@@ -2231,6 +2231,11 @@ func (a *Application) ChangeScale(scaleChange int) (int, error) {
 			},
 			Update: bson.D{{"$set", bson.D{{"scale", newScale}}}},
 		}}
+		quotaOps, err := a.unitQuotaOps(newScale, a.doc.UnitCount)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, quotaOps...)
 
 		cloudSvcDoc := cloudServiceDoc{
 			DocID:                 a.globalKey(),
@@ -2287,14 +2292,23 @@ func (a *Application) SetScale(scale int, generation int64, force bool) error {
 				return nil, applicationNotAliveErr
 			}
 		}
+		quotaOps, err := a.unitQuotaOps(scale, a.doc.UnitCount)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		asserts := bson.D{
+			{"life", Alive},
+			{"charmurl", a.doc.CharmURL},
+			{"unitcount", a.doc.UnitCount},
+		}
+		if len(quotaOps) > 0 {
+			// The quotas are checked against the current scale.
+			asserts = append(asserts, bson.DocElem{"scale", a.doc.DesiredScale})
+		}
 		ops := []txn.Op{{
-			C:  applicationsC,
-			Id: a.doc.DocID,
-			Assert: bson.D{
-				{"life", Alive},
-				{"charmurl", a.doc.CharmURL},
-				{"unitcount", a.doc.UnitCount},
-			},
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: asserts,
 			Update: bson.D{{"$set", bson.D{{"scale", scale}}}},
 		}}
 		cloudSvcDoc := cloudServiceDoc{
@@ -2312,7 +2326,7 @@ func (a *Application) SetScale(scale int, generation int64, force bool) error {
 			return nil, errors.Trace(err)
 		}
 		ops = append(ops, cloudSvcOp...)
-		return ops, nil
+		return append(ops, quotaOps...), nil
 	}
 	if err := a.st.db().Run(buildTxn); err != nil {
 		return errors.Errorf("cannot set scale for application %q to %v: %v", a, scale, onAbort(err, applicationNotAliveErr))
@@ -2771,19 +2785,38 @@ type AddUnitParams struct {
 // AddUnit adds a new principal unit to the application.
 func (a *Application) AddUnit(args AddUnitParams) (unit *Unit, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add unit to application %q", a)
-	name, ops, err := a.addUnitOps("", args, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := a.st.db().RunTransaction(ops); err == txn.ErrAborted {
-		if alive, err := isAlive(a.st, applicationsC, a.doc.DocID); err != nil {
+	var name string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if alive, err := isAlive(a.st, applicationsC, a.doc.DocID); err != nil {
+				return nil, err
+			} else if !alive {
+				return nil, applicationNotAliveErr
+			}
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		quotaOps, err := a.unitQuotaOps(a.doc.DesiredScale, a.doc.UnitCount+1)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var asserts bson.D
+		if len(quotaOps) > 0 {
+			// The quotas are checked against the current scale
+			// and number of units.
+			asserts = bson.D{
+				{"unitcount", a.doc.UnitCount},
+				{"scale", a.doc.DesiredScale},
+			}
+		}
+		var ops []txn.Op
+		if name, ops, err = a.addUnitOps("", args, asserts); err != nil {
 			return nil, err
-		} else if !alive {
-			return nil, applicationNotAliveErr
 		}
-		return nil, errors.New("inconsistent state")
-	} else if err != nil {
+		return append(ops, quotaOps...), nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
 		return nil, err
 	}
 	return a.st.Unit(name)
@@ -3415,13 +3448,39 @@ func (a *Application) SetConstraints(cons constraints.Value) (err error) {
 		return applicationNotAliveErr
 	}
 
-	ops := []txn.Op{{
-		C:      applicationsC,
-		Id:     a.doc.DocID,
-		Assert: isAliveDoc,
-	}}
-	ops = append(ops, setConstraintsOp(a.globalKey(), cons))
-	return onAbort(a.st.db().RunTransaction(ops), applicationNotAliveErr)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); errors.IsNotFound(err) {
+				return nil, applicationNotAliveErr
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if a.doc.Life != Alive {
+				return nil, applicationNotAliveErr
+			}
+		}
+		quotaOps, err := a.constraintsQuotaOps(cons)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		asserts := isAliveDoc
+		if len(quotaOps) > 0 {
+			// The quotas are checked against the current scale
+			// and number of units.
+			asserts = append(bson.D{
+				{"unitcount", a.doc.UnitCount},
+				{"scale", a.doc.DesiredScale},
+			}, isAliveDoc...)
+		}
+		ops := []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: asserts,
+		}}
+		ops = append(ops, setConstraintsOp(a.globalKey(), cons))
+		return append(ops, quotaOps...), nil
+	}
+	return onAbort(a.st.db().Run(buildTxn), applicationNotAliveErr)
 }
 
 func assertApplicationAliveOp(docID string) txn.Op {
@@ -30,7 +30,12 @@ func (st *State) GetOfferUsers(offerUUID string) (map[string]permission.Access,
 	}
 	result := make(map[string]permission.Access)
 	for _, p := range perms {
-		result[userIDFromGlobalKey(p.doc.SubjectGlobalKey)] = p.access()
+		user := userIDFromGlobalKey(p.doc.SubjectGlobalKey)
+		if user == p.doc.SubjectGlobalKey {
+			// Not a user subject
+			continue
+		}
+		result[user] = p.access()
 	}
 	return result, nil
 }
@@ -0,0 +1,439 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/mgo/v3"
+	"github.com/juju/mgo/v3/bson"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/auditlog"
+	corelogger "github.com/juju/juju/core/logger"
+)
+
+const (
+	// auditLogModule is the module used for audit records written
+	// to the controller model's logs.
+	auditLogModule = "juju.apiserver.auditlog"
+
+	// maxCachedAuditConversations is the number of conversations the
+	// audit log store remembers so that requests can be recorded
+	// without looking the conversation up in the database.
+	maxCachedAuditConversations = 1024
+)
+
+// auditConversationDoc records a conversation (an API connection
+// from a client) in the audit log.
+type auditConversationDoc struct {
+	DocID        string    `bson:"_id"`
+	ConnectionID string    `bson:"connection-id"`
+	Who          string    `bson:"who"`
+	What         string    `bson:"what"`
+	When         time.Time `bson:"when"`
+	ModelName    string    `bson:"model-name"`
+	ModelUUID    string    `bson:"model-uuid"`
+}
+
+// auditRequestDoc records an API request made as part of a
+// conversation. The conversation details are copied into every
+// request so that the audit log can be queried without joins.
+type auditRequestDoc struct {
+	DocID          string          `bson:"_id"`
+	ConversationID string          `bson:"conversation-id"`
+	ConnectionID   string          `bson:"connection-id"`
+	Who            string          `bson:"who"`
+	What           string          `bson:"what"`
+	ModelName      string          `bson:"model-name"`
+	ModelUUID      string          `bson:"model-uuid"`
+	RequestID      int64           `bson:"request-id"`
+	When           time.Time       `bson:"when"`
+	Facade         string          `bson:"facade"`
+	Method         string          `bson:"method"`
+	Version        int             `bson:"version"`
+	Args           string          `bson:"args,omitempty"`
+	Errors         []auditErrorDoc `bson:"errors,omitempty"`
+}
+
+type auditErrorDoc struct {
+	Message string `bson:"message"`
+	Code    string `bson:"code,omitempty"`
+}
+
+func auditRequestDocID(conversationID string, requestID uint64) string {
+	return fmt.Sprintf("%s:%d", conversationID, requestID)
+}
+
+// AuditLogStore stores audit records in the controller database, so
+// that they can be queried with AuditLogRecords. Records are written
+// in batches by the audit log worker, rather than as API requests are
+// served, so that the requests don't wait for the database.
+type AuditLogStore struct {
+	st       *State
+	dbLogger *DbLogger
+
+	mu            sync.Mutex
+	conversations map[string]auditConversationDoc
+	order         []string
+}
+
+// NewAuditLogStore returns a store which writes audit records to the
+// controller database. The store must be closed when it's no longer
+// needed.
+func NewAuditLogStore(st *State) *AuditLogStore {
+	return &AuditLogStore{
+		st:            st,
+		dbLogger:      NewDbLogger(st),
+		conversations: make(map[string]auditConversationDoc),
+	}
+}
+
+// AddRecords stores the audit records. Conversations must be added
+// before, or in the same batch as, their requests; responses must be
+// added after their requests. Only responses which carry errors are
+// stored, against the request they answer.
+//
+// Every record is written even if some fail; the first error is
+// returned.
+func (s *AuditLogStore) AddRecords(records []auditlog.Record) error {
+	var (
+		conversations []interface{}
+		requests      []interface{}
+		responses     []auditlog.ResponseErrors
+		result        error
+	)
+	fail := func(err error) {
+		if result == nil {
+			result = err
+		}
+	}
+	for _, record := range records {
+		switch {
+		case record.Conversation != nil:
+			doc, err := conversationDoc(*record.Conversation)
+			if err != nil {
+				fail(errors.Trace(err))
+				continue
+			}
+			s.remember(doc)
+			conversations = append(conversations, doc)
+		case record.Request != nil:
+			doc, err := s.requestDoc(*record.Request)
+			if err != nil {
+				fail(errors.Trace(err))
+				continue
+			}
+			requests = append(requests, doc)
+		case record.Errors != nil:
+			responses = append(responses, *record.Errors)
+		}
+	}
+
+	if len(conversations) > 0 {
+		coll, closer := s.st.db().GetRawCollection(auditConversationsC)
+		err := coll.Insert(conversations...)
+		closer()
+		if err != nil {
+			fail(errors.Annotate(err, "cannot add audit conversations"))
+		}
+	}
+	if len(requests) > 0 {
+		coll, closer := s.st.db().GetRawCollection(auditLogC)
+		err := coll.Insert(requests...)
+		closer()
+		if err != nil {
+			fail(errors.Annotate(err, "cannot add audit requests"))
+		}
+	}
+	for _, r := range responses {
+		if err := s.addResponse(r); err != nil {
+			fail(errors.Trace(err))
+		}
+	}
+	return result
+}
+
+func conversationDoc(c auditlog.Conversation) (auditConversationDoc, error) {
+	when, err := time.Parse(time.RFC3339, c.When)
+	if err != nil {
+		return auditConversationDoc{}, errors.Annotate(err, "parsing conversation time")
+	}
+	return auditConversationDoc{
+		DocID:        c.ConversationID,
+		ConnectionID: c.ConnectionID,
+		Who:          c.Who,
+		What:         c.What,
+		When:         when.UTC(),
+		ModelName:    c.ModelName,
+		ModelUUID:    c.ModelUUID,
+	}, nil
+}
+
+func (s *AuditLogStore) requestDoc(r auditlog.Request) (auditRequestDoc, error) {
+	when, err := time.Parse(time.RFC3339, r.When)
+	if err != nil {
+		return auditRequestDoc{}, errors.Annotate(err, "parsing request time")
+	}
+	conversation, err := s.conversation(r.ConversationID)
+	if err != nil {
+		return auditRequestDoc{}, errors.Trace(err)
+	}
+	return auditRequestDoc{
+		DocID:          auditRequestDocID(r.ConversationID, r.RequestID),
+		ConversationID: r.ConversationID,
+		ConnectionID:   r.ConnectionID,
+		Who:            conversation.Who,
+		What:           conversation.What,
+		ModelName:      conversation.ModelName,
+		ModelUUID:      conversation.ModelUUID,
+		RequestID:      int64(r.RequestID),
+		When:           when.UTC(),
+		Facade:         r.Facade,
+		Method:         r.Method,
+		Version:        r.Version,
+		Args:           r.Args,
+	}, nil
+}
+
+func (s *AuditLogStore) addResponse(r auditlog.ResponseErrors) error {
+	var errorDocs []auditErrorDoc
+	for _, e := range r.Errors {
+		if e == nil {
+			continue
+		}
+		errorDocs = append(errorDocs, auditErrorDoc{
+			Message: e.Message,
+			Code:    e.Code,
+		})
+	}
+	if len(errorDocs) == 0 {
+		return nil
+	}
+	coll, closer := s.st.db().GetRawCollection(auditLogC)
+	defer closer()
+	err := coll.UpdateId(
+		auditRequestDocID(r.ConversationID, r.RequestID),
+		bson.D{{"$set", bson.D{{"errors", errorDocs}}}},
+	)
+	if err == mgo.ErrNotFound {
+		// The request was recorded before the store was (re)created,
+		// so there is nothing to attach the errors to.
+		logger.Debugf("audit request %s:%d not found for response", r.ConversationID, r.RequestID)
+	} else if err != nil {
+		return errors.Annotate(err, "cannot add audit response")
+	}
+	return nil
+}
+
+// ForwardRecords writes the audit records to the controller model's
+// logs, from where they are sent on to any configured log sink by the
+// log forwarder. The records' conversations must already have been
+// added to the store.
+func (s *AuditLogStore) ForwardRecords(records []auditlog.Record) error {
+	logRecords := make([]corelogger.LogRecord, 0, len(records))
+	for _, record := range records {
+		var conversationID string
+		switch {
+		case record.Conversation != nil:
+			conversationID = record.Conversation.ConversationID
+		case record.Request != nil:
+			conversationID = record.Request.ConversationID
+		case record.Errors != nil:
+			if len(record.Errors.Errors) == 0 {
+				continue
+			}
+			conversationID = record.Errors.ConversationID
+		default:
+			continue
+		}
+		conversation, err := s.conversation(conversationID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		message, err := json.Marshal(record)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		entity := "controller"
+		if names.IsValidUser(conversation.Who) {
+			entity = names.NewUserTag(conversation.Who).String()
+		}
+		logRecords = append(logRecords, corelogger.LogRecord{
+			Time:    s.st.clock().Now(),
+			Entity:  entity,
+			Level:   loggo.INFO,
+			Module:  auditLogModule,
+			Message: string(message),
+			Labels:  []string{"audit"},
+		})
+	}
+	if len(logRecords) == 0 {
+		return nil
+	}
+	return errors.Annotate(s.dbLogger.Log(logRecords), "cannot forward audit records")
+}
+
+// Close releases the store's resources.
+func (s *AuditLogStore) Close() error {
+	return errors.Trace(s.dbLogger.Close())
+}
+
+func (s *AuditLogStore) remember(doc auditConversationDoc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.conversations[doc.DocID]; ok {
+		return
+	}
+	if len(s.order) >= maxCachedAuditConversations {
+		delete(s.conversations, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.conversations[doc.DocID] = doc
+	s.order = append(s.order, doc.DocID)
+}
+
+func (s *AuditLogStore) conversation(id string) (auditConversationDoc, error) {
+	s.mu.Lock()
+	doc, ok := s.conversations[id]
+	s.mu.Unlock()
+	if ok {
+		return doc, nil
+	}
+	coll, closer := s.st.db().GetRawCollection(auditConversationsC)
+	defer closer()
+	if err := coll.FindId(id).One(&doc); err == mgo.ErrNotFound {
+		return doc, errors.NotFoundf("audit conversation %q", id)
+	} else if err != nil {
+		return doc, errors.Annotatef(err, "cannot get audit conversation %q", id)
+	}
+	s.remember(doc)
+	return doc, nil
+}
+
+// AuditLogFilter selects the audit log records returned by
+// AuditLogRecords. Zero valued fields match all records.
+type AuditLogFilter struct {
+	// User is the id of the user who made the requests.
+	User string
+
+	// ModelUUID is the model the requests were made against.
+	ModelUUID string
+
+	// Method is either a "Facade.Method" name or a bare facade name,
+	// matching all methods of that facade.
+	Method string
+
+	// Since excludes requests made before this time.
+	Since time.Time
+
+	// Limit is the maximum number of records returned; the most
+	// recent records are kept.
+	Limit int
+}
+
+// AuditLogRecord describes an API request recorded in the audit log.
+type AuditLogRecord struct {
+	ConversationID string
+	ConnectionID   string
+	Who            string
+	What           string
+	ModelName      string
+	ModelUUID      string
+	RequestID      uint64
+	When           time.Time
+	Facade         string
+	Method         string
+	Version        int
+	Args           string
+	Errors         []auditlog.Error
+}
+
+// AuditLogRecords returns the API requests recorded in the audit log
+// that match the filter, oldest first.
+func (st *State) AuditLogRecords(filter AuditLogFilter) ([]AuditLogRecord, error) {
+	query := bson.D{}
+	if filter.User != "" {
+		query = append(query, bson.DocElem{"who", filter.User})
+	}
+	if filter.ModelUUID != "" {
+		query = append(query, bson.DocElem{"model-uuid", filter.ModelUUID})
+	}
+	if filter.Method != "" {
+		facade, method, ok := strings.Cut(filter.Method, ".")
+		query = append(query, bson.DocElem{"facade", facade})
+		if ok {
+			query = append(query, bson.DocElem{"method", method})
+		}
+	}
+	if !filter.Since.IsZero() {
+		query = append(query, bson.DocElem{"when", bson.D{{"$gte", filter.Since.UTC()}}})
+	}
+
+	coll, closer := st.db().GetRawCollection(auditLogC)
+	defer closer()
+	q := coll.Find(query).Sort("-when", "-request-id")
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var docs []auditRequestDoc
+	if err := q.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit log records")
+	}
+
+	result := make([]AuditLogRecord, len(docs))
+	for i, doc := range docs {
+		record := AuditLogRecord{
+			ConversationID: doc.ConversationID,
+			ConnectionID:   doc.ConnectionID,
+			Who:            doc.Who,
+			What:           doc.What,
+			ModelName:      doc.ModelName,
+			ModelUUID:      doc.ModelUUID,
+			RequestID:      uint64(doc.RequestID),
+			When:           doc.When.UTC(),
+			Facade:         doc.Facade,
+			Method:         doc.Method,
+			Version:        doc.Version,
+			Args:           doc.Args,
+		}
+		for _, e := range doc.Errors {
+			record.Errors = append(record.Errors, auditlog.Error{
+				Message: e.Message,
+				Code:    e.Code,
+			})
+		}
+		// The query returns the newest records first so that the
+		// limit keeps the most recent ones.
+		result[len(docs)-1-i] = record
+	}
+	return result, nil
+}
+
+// PruneAuditLog removes audit log conversations and requests
+// recorded more than maxAge ago.
+func (st *State) PruneAuditLog(maxAge time.Duration) error {
+	if maxAge <= 0 {
+		return nil
+	}
+	cutoff := st.clock().Now().Add(-maxAge).UTC()
+	for _, name := range []string{auditLogC, auditConversationsC} {
+		coll, closer := st.db().GetRawCollection(name)
+		info, err := coll.RemoveAll(bson.D{{"when", bson.D{{"$lt", cutoff}}}})
+		closer()
+		if err != nil {
+			return errors.Annotatef(err, "pruning %s", name)
+		}
+		if info.Removed > 0 {
+			logger.Debugf("pruned %d %s documents older than %s", info.Removed, name, cutoff)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/mgo/v3/bson"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/state"
+)
+
+type AuditLogSuite struct {
+	ConnSuite
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC))
+	err := s.State.SetClockForTesting(s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AuditLogSuite) newStore(c *gc.C) *state.AuditLogStore {
+	store := state.NewAuditLogStore(s.State)
+	s.AddCleanup(func(c *gc.C) { c.Check(store.Close(), jc.ErrorIsNil) })
+	return store
+}
+
+func conversationRecord(id, who, modelUUID string, when time.Time) auditlog.Record {
+	return auditlog.Record{Conversation: &auditlog.Conversation{
+		ConversationID: id,
+		ConnectionID:   "2AF",
+		Who:            who,
+		What:           "juju status",
+		When:           when.Format(time.RFC3339),
+		ModelName:      "admin/default",
+		ModelUUID:      modelUUID,
+	}}
+}
+
+func requestRecord(id string, requestID uint64, method string, when time.Time) auditlog.Record {
+	return auditlog.Record{Request: &auditlog.Request{
+		ConversationID: id,
+		ConnectionID:   "2AF",
+		RequestID:      requestID,
+		When:           when.Format(time.RFC3339),
+		Facade:         "Client",
+		Method:         method,
+		Version:        6,
+	}}
+}
+
+func (s *AuditLogSuite) addRecords(c *gc.C, store *state.AuditLogStore, records ...auditlog.Record) {
+	err := store.AddRecords(records)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AuditLogSuite) TestAddAndQuery(c *gc.C) {
+	now := s.clock.Now()
+	store := s.newStore(c)
+	s.addRecords(c, store,
+		conversationRecord("0123456789abcdef", "admin", s.State.ModelUUID(), now),
+		requestRecord("0123456789abcdef", 1, "FullStatus", now),
+	)
+	s.addRecords(c, store, auditlog.Record{Errors: &auditlog.ResponseErrors{
+		ConversationID: "0123456789abcdef",
+		ConnectionID:   "2AF",
+		RequestID:      1,
+		When:           now.Format(time.RFC3339),
+		Errors:         []*auditlog.Error{{Message: "boom", Code: "bad"}},
+	}})
+
+	records, err := s.State.AuditLogRecords(state.AuditLogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, jc.DeepEquals, []state.AuditLogRecord{{
+		ConversationID: "0123456789abcdef",
+		ConnectionID:   "2AF",
+		Who:            "admin",
+		What:           "juju status",
+		ModelName:      "admin/default",
+		ModelUUID:      s.State.ModelUUID(),
+		RequestID:      1,
+		When:           now,
+		Facade:         "Client",
+		Method:         "FullStatus",
+		Version:        6,
+		Errors:         []auditlog.Error{{Message: "boom", Code: "bad"}},
+	}})
+}
+
+func (s *AuditLogSuite) TestAddRequestUnknownConversation(c *gc.C) {
+	now := s.clock.Now()
+	store := s.newStore(c)
+	err := store.AddRecords([]auditlog.Record{
+		requestRecord("0123456789abcdef", 1, "FullStatus", now),
+		conversationRecord("fedcba9876543210", "admin", "", now),
+		requestRecord("fedcba9876543210", 1, "FullStatus", now),
+	})
+	c.Assert(err, gc.ErrorMatches, `audit conversation "0123456789abcdef" not found`)
+
+	// The rest of the batch is still stored.
+	records, err := s.State.AuditLogRecords(state.AuditLogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Assert(records[0].ConversationID, gc.Equals, "fedcba9876543210")
+}
+
+func (s *AuditLogSuite) TestConversationLoadedFromDatabase(c *gc.C) {
+	now := s.clock.Now()
+	s.addRecords(c, s.newStore(c), conversationRecord("0123456789abcdef", "bob", "", now))
+
+	// A new store (as created when the controller agent restarts)
+	// can still record requests for existing conversations.
+	s.addRecords(c, s.newStore(c), requestRecord("0123456789abcdef", 1, "FullStatus", now))
+	records, err := s.State.AuditLogRecords(state.AuditLogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Assert(records[0].Who, gc.Equals, "bob")
+}
+
+func (s *AuditLogSuite) TestFilter(c *gc.C) {
+	now := s.clock.Now()
+	s.addRecords(c, s.newStore(c),
+		conversationRecord("aaaa", "admin", "model-1", now),
+		requestRecord("aaaa", 1, "FullStatus", now),
+		requestRecord("aaaa", 2, "AddMachines", now.Add(time.Second)),
+		conversationRecord("bbbb", "bob", "model-2", now.Add(time.Minute)),
+		requestRecord("bbbb", 1, "FullStatus", now.Add(time.Minute)),
+	)
+
+	check := func(filter state.AuditLogFilter, expected ...string) {
+		records, err := s.State.AuditLogRecords(filter)
+		c.Assert(err, jc.ErrorIsNil)
+		var got []string
+		for _, r := range records {
+			got = append(got, r.ConversationID+"."+r.Method)
+		}
+		c.Check(got, jc.DeepEquals, expected, gc.Commentf("filter %+v", filter))
+	}
+	check(state.AuditLogFilter{}, "aaaa.FullStatus", "aaaa.AddMachines", "bbbb.FullStatus")
+	check(state.AuditLogFilter{User: "bob"}, "bbbb.FullStatus")
+	check(state.AuditLogFilter{ModelUUID: "model-1"}, "aaaa.FullStatus", "aaaa.AddMachines")
+	check(state.AuditLogFilter{Method: "Client.FullStatus"}, "aaaa.FullStatus", "bbbb.FullStatus")
+	check(state.AuditLogFilter{Method: "Client"}, "aaaa.FullStatus", "aaaa.AddMachines", "bbbb.FullStatus")
+	check(state.AuditLogFilter{Method: "Application"})
+	check(state.AuditLogFilter{Since: now.Add(time.Second)}, "aaaa.AddMachines", "bbbb.FullStatus")
+	check(state.AuditLogFilter{Limit: 2}, "aaaa.AddMachines", "bbbb.FullStatus")
+}
+
+func (s *AuditLogSuite) TestPrune(c *gc.C) {
+	now := s.clock.Now()
+	s.addRecords(c, s.newStore(c),
+		conversationRecord("aaaa", "admin", "", now.Add(-48*time.Hour)),
+		requestRecord("aaaa", 1, "FullStatus", now.Add(-48*time.Hour)),
+		conversationRecord("bbbb", "admin", "", now.Add(-time.Hour)),
+		requestRecord("bbbb", 1, "FullStatus", now.Add(-time.Hour)),
+	)
+
+	err := s.State.PruneAuditLog(24 * time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	records, err := s.State.AuditLogRecords(state.AuditLogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Assert(records[0].ConversationID, gc.Equals, "bbbb")
+
+	// The pruned conversation can't be added to any more.
+	err = s.newStore(c).AddRecords([]auditlog.Record{
+		requestRecord("aaaa", 2, "FullStatus", now),
+	})
+	c.Assert(err, gc.ErrorMatches, `audit conversation "aaaa" not found`)
+}
+
+func (s *AuditLogSuite) TestForward(c *gc.C) {
+	now := s.clock.Now()
+	store := s.newStore(c)
+	records := []auditlog.Record{
+		conversationRecord("0123456789abcdef", "admin", "", now),
+		requestRecord("0123456789abcdef", 1, "FullStatus", now),
+	}
+	s.addRecords(c, store, records...)
+	err := store.ForwardRecords(records)
+	c.Assert(err, jc.ErrorIsNil)
+
+	logsColl := s.State.MongoSession().DB("logs").C("logs." + s.State.ModelUUID())
+	var docs []bson.M
+	err = logsColl.Find(bson.M{"m": "juju.apiserver.auditlog"}).Sort("t", "_id").All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 2)
+	c.Assert(docs[0]["n"], gc.Equals, "user-admin")
+	c.Assert(docs[0]["x"], jc.Contains, `"conversation":{"who":"admin"`)
+	c.Assert(docs[1]["x"], jc.Contains, `"request":{"conversation-id":"0123456789abcdef"`)
+}
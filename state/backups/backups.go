@@ -24,6 +24,10 @@ const (
 
 	// FilenameTemplate is used with time.Time.Format to generate a filename.
 	FilenameTemplate = FilenamePrefix + "20060102-150405.tar.gz"
+
+	// uploadedFilenameTemplate is used with time.Time.Format to generate
+	// a filename for an archive uploaded to the controller.
+	uploadedFilenameTemplate = FilenamePrefix + "uploaded-20060102-150405.tar.gz"
 )
 
 var logger = loggo.GetLogger("juju.state.backups")
@@ -52,6 +56,14 @@ type Backups interface {
 
 	// Get returns the metadata and specified archive file.
 	Get(fileName string) (*Metadata, io.ReadCloser, error)
+
+	// Add stores the provided archive in the backup dir and returns
+	// its filename, ready to be restored.
+	Add(archive io.Reader) (string, error)
+
+	// Restore replaces the controller's state with the contents
+	// of the specified archive file.
+	Restore(fileName string, dbInfo *DBInfo, args RestoreArgs) error
 }
 
 type backups struct {
@@ -231,3 +243,29 @@ func (b *backups) Get(fileName string) (_ *Metadata, _ io.ReadCloser, err error)
 
 	return meta, readCloser, nil
 }
+
+// Add stores the provided archive in the backup dir, under a new
+// backup filename, and returns that filename.
+func (b *backups) Add(archive io.Reader) (_ string, err error) {
+	destinationDir := b.paths.BackupDir
+	if !filepath.IsAbs(destinationDir) {
+		return "", errors.Errorf("cannot use relative backup destination directory %q", destinationDir)
+	}
+
+	fileName := filepath.Join(destinationDir, time.Now().Format(uploadedFilenameTemplate))
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", errors.Annotate(err, "while creating archive file")
+	}
+	defer func() {
+		_ = file.Close()
+		if err != nil {
+			_ = os.Remove(fileName)
+		}
+	}()
+
+	if _, err := io.Copy(file, archive); err != nil {
+		return "", errors.Annotate(err, "while storing archive file")
+	}
+	return fileName, nil
+}
@@ -23,6 +23,9 @@ var (
 	AvailableDisk        = &availableDisk
 	TotalDisk            = &totalDisk
 	DirSize              = &dirSize
+	GetDBRestorer        = &getDBRestorer
+	FindAgents           = &findAgents
+	RestartAgent         = &restartAgent
 )
 
 // ExposeCreateResult extracts the values in a create() result.
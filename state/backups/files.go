@@ -93,6 +93,17 @@ func GetFilesToBackUp(rootDir string, paths *Paths) ([]string, error) {
 	backupFiles = append(backupFiles, agentConfs...)
 	backupFiles = append(backupFiles, serviceConfs...)
 
+	// Handle the dqlite node data (might not exist on older controllers).
+	dqlite := filepath.Join(rootDir, paths.DataDir, dqliteDir)
+	if _, err := os.Stat(dqlite); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, errors.Trace(err)
+		}
+		logger.Errorf("skipping missing dir %q", dqlite)
+	} else {
+		backupFiles = append(backupFiles, dqlite)
+	}
+
 	// Handle nonce.txt (might not exist).
 	nonce := filepath.Join(rootDir, paths.DataDir, nonceFile)
 	if _, err := os.Stat(nonce); err != nil {
@@ -191,3 +191,30 @@ func (s *filesSuite) TestGetFilesToBackUpSnap(c *gc.C) {
 	c.Check(files, jc.SameContents, expected)
 	s.checkSameStrings(c, files, expected)
 }
+
+func (s *filesSuite) TestGetFilesToBackUpDqlite(c *gc.C) {
+	paths := backups.Paths{
+		DataDir: "/var/lib/juju",
+		LogsDir: "/var/log/juju",
+	}
+	s.createFiles(c, paths, s.root, "0", false)
+	err := os.MkdirAll(filepath.Join(s.root, "/var/lib/juju/dqlite"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+
+	files, err := backups.GetFilesToBackUp(s.root, &paths)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := []string{
+		filepath.Join(s.root, "/home/ubuntu/.ssh/authorized_keys"),
+		filepath.Join(s.root, "/var/lib/juju/agents/machine-0.conf"),
+		filepath.Join(s.root, "/var/lib/juju/nonce.txt"),
+		filepath.Join(s.root, "/var/lib/juju/server.pem"),
+		filepath.Join(s.root, "/var/lib/juju/shared-secret"),
+		filepath.Join(s.root, "/var/lib/juju/system-identity"),
+		filepath.Join(s.root, "/var/lib/juju/tools"),
+		filepath.Join(s.root, "/var/lib/juju/init/juju-db"),
+		filepath.Join(s.root, "/var/lib/juju/dqlite"),
+	}
+	c.Check(files, jc.SameContents, expected)
+	s.checkSameStrings(c, files, expected)
+}
@@ -0,0 +1,284 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/version/v2"
+
+	"github.com/juju/juju/service"
+)
+
+const (
+	restoreName = "mongorestore"
+
+	// dqliteDir is the directory, relative to the agent data dir,
+	// holding the controller's dqlite node data.
+	dqliteDir = "dqlite"
+)
+
+var (
+	getDBRestorer       = NewDBRestorer
+	getMongorestorePath = func() (string, error) {
+		return getMongoToolPath(restoreName, os.Stat, exec.LookPath)
+	}
+	findAgents   = service.FindAgents
+	restartAgent = func(serviceName string) error {
+		// The machine agent serving the restore request is one of the
+		// agents being restarted, so don't wait for the job to finish.
+		return runCommandFn("systemctl", "restart", "--no-block", serviceName)
+	}
+)
+
+// RestoreArgs holds the arguments needed to restore a backup archive
+// onto a controller machine.
+type RestoreArgs struct {
+	// ControllerVersion is the version of the controller the
+	// backup is being restored onto.
+	ControllerVersion version.Number
+
+	// HANodes is the number of nodes in the running controller's
+	// HA configuration.
+	HANodes int64
+
+	// RootDir is the directory the archived files are restored
+	// relative to. If empty, the files are restored relative to "/".
+	RootDir string
+}
+
+// Validate checks that the restore arguments are usable.
+func (args RestoreArgs) Validate() error {
+	if args.ControllerVersion == version.Zero {
+		return errors.NotValidf("missing controller version")
+	}
+	if args.HANodes > 1 {
+		return errors.NotSupportedf("restoring onto a controller with %d nodes in HA", args.HANodes)
+	}
+	return nil
+}
+
+// ValidateRestoreVersion checks that a backup taken with backupVersion
+// can be restored onto a controller running controllerVersion. Both
+// must share the same major and minor version, and the controller
+// must not be older than the backup.
+func ValidateRestoreVersion(backupVersion, controllerVersion version.Number) error {
+	if backupVersion == UnknownVersion || backupVersion == version.Zero {
+		return errors.NotValidf("backup with unknown juju version")
+	}
+	if backupVersion.Major != controllerVersion.Major || backupVersion.Minor != controllerVersion.Minor {
+		return errors.NotSupportedf("restoring a juju %s backup onto a juju %s controller", backupVersion, controllerVersion)
+	}
+	if backupVersion.Compare(controllerVersion) > 0 {
+		return errors.NotSupportedf("restoring a juju %s backup onto an older juju %s controller", backupVersion, controllerVersion)
+	}
+	return nil
+}
+
+// Restore replaces the controller's state with the contents of the
+// named backup archive. The mongo dump is loaded back into the database,
+// the agent config, certificates and dqlite data are put back on disk,
+// and the agents on the machine are restarted, machine agent last.
+func (b *backups) Restore(fileName string, dbInfo *DBInfo, args RestoreArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	valid, err := isValidFilepath(b.paths.BackupDir, fileName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !valid {
+		return errors.NotValidf("backup file %q", fileName)
+	}
+
+	restorer, err := getDBRestorer(dbInfo)
+	if err != nil {
+		return errors.Annotate(err, "while preparing for DB restore")
+	}
+
+	archive, err := os.Open(fileName)
+	if err != nil {
+		return errors.Annotate(err, "while opening archive file for restore")
+	}
+	defer func() { _ = archive.Close() }()
+
+	// As with creating a backup, the juju-db snap can only see the
+	// host's temp dir through its private tmp.
+	stagingDir := b.paths.BackupDir
+	if restorer.IsSnap() && stagingDir == os.TempDir() {
+		stagingDir = filepath.Join(snapTmpDir, stagingDir)
+	}
+	ws, err := newArchiveWorkspaceReader(stagingDir, archive)
+	if ws != nil {
+		defer func() {
+			if err := ws.Close(); err != nil {
+				logger.Errorf("error removing restore workspace: %v", err)
+			}
+		}()
+	}
+	if err != nil {
+		return errors.Annotate(err, "while unpacking archive")
+	}
+
+	meta, err := ws.Metadata()
+	if err != nil {
+		return errors.Annotate(err, "while reading archive metadata")
+	}
+	if err := ValidateRestoreVersion(meta.Origin.Version, args.ControllerVersion); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("restoring backup of controller %q taken with juju %s",
+		meta.Controller.UUID, meta.Origin.Version)
+
+	if err := restorer.Restore(ws.DBDumpDir); err != nil {
+		return errors.Annotate(err, "while restoring database")
+	}
+
+	rootDir := args.RootDir
+	if rootDir == "" {
+		rootDir = string(os.PathSeparator)
+	}
+	if err := restoreFiles(ws, rootDir, b.paths.DataDir); err != nil {
+		return errors.Annotate(err, "while restoring files")
+	}
+
+	err = restartAgents(filepath.Join(rootDir, b.paths.DataDir))
+	return errors.Annotate(err, "while restarting agents")
+}
+
+// restoreFiles unpacks the archived files bundle over rootDir. If the
+// bundle holds dqlite data, the existing dqlite node data is removed
+// first so that files from the node being replaced don't linger.
+func restoreFiles(ws *ArchiveWorkspace, rootDir, dataDir string) error {
+	found, err := bundleContains(ws, path.Join(strings.TrimPrefix(filepath.ToSlash(dataDir), "/"), dqliteDir))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if found {
+		dqlitePath := filepath.Join(rootDir, dataDir, dqliteDir)
+		logger.Debugf("removing existing dqlite data in %q", dqlitePath)
+		if err := os.RemoveAll(dqlitePath); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(ws.UnpackFilesBundle(rootDir))
+}
+
+// bundleContains reports whether the archived files bundle holds the
+// named file or directory.
+func bundleContains(ws *ArchiveWorkspace, name string) (bool, error) {
+	bundle, err := os.Open(ws.FilesBundle)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	defer func() { _ = bundle.Close() }()
+
+	tr := tar.NewReader(bundle)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		entry := strings.TrimSuffix(path.Clean(hdr.Name), "/")
+		if entry == name || strings.HasPrefix(entry, name+"/") {
+			return true, nil
+		}
+	}
+}
+
+// restartAgents restarts any unit agents found in the data dir,
+// followed by the machine agent. The machine agent goes last as it
+// runs the API server handling the restore.
+func restartAgents(dataDir string) error {
+	machineAgent, unitAgents, _, err := findAgents(dataDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if machineAgent == "" {
+		return errors.NotFoundf("machine agent in %q", dataDir)
+	}
+	for _, agentName := range append(unitAgents, machineAgent) {
+		logger.Infof("restarting agent %q", agentName)
+		if err := restartAgent(fmt.Sprintf("jujud-%s", agentName)); err != nil {
+			return errors.Annotatef(err, "restarting agent %q", agentName)
+		}
+	}
+	return nil
+}
+
+// DBRestorer is any type that loads a dump from a dump dir.
+type DBRestorer interface {
+	// Restore loads the dump in dumpDir.
+	Restore(dumpDir string) error
+
+	// IsSnap returns true if we are using the juju-db snap.
+	IsSnap() bool
+}
+
+type mongoRestorer struct {
+	*DBInfo
+	// binPath is the path to the restore executable.
+	binPath string
+}
+
+// NewDBRestorer returns a new value with a Restore method for loading
+// a juju state database dump.
+func NewDBRestorer(info *DBInfo) (DBRestorer, error) {
+	mongorestorePath, err := getMongorestorePath()
+	if err != nil {
+		return nil, errors.Annotate(err, "mongorestore not available")
+	}
+
+	restorer := mongoRestorer{
+		DBInfo:  info,
+		binPath: mongorestorePath,
+	}
+	return &restorer, nil
+}
+
+func (md *mongoRestorer) options(dumpDir string) []string {
+	options := []string{
+		"--ssl",
+		"--tlsInsecure",
+		"--authenticationDatabase", "admin",
+		"--host", md.Address,
+		"--username", md.Username,
+		"--password", md.Password,
+		"--drop",
+		"--oplogReplay",
+		"--preserveUUID",
+		dumpDir,
+	}
+	return options
+}
+
+// IsSnap returns true if we are using the juju-db snap.
+func (md *mongoRestorer) IsSnap() bool {
+	return filepath.Base(md.binPath) == snapToolPrefix+restoreName
+}
+
+// Restore loads the dumped juju state databases, dropping each
+// collection before it is restored.
+func (md *mongoRestorer) Restore(dumpDir string) error {
+	logger.Tracef("restoring Mongo database from %q", dumpDir)
+	dumpDirArg := dumpDir
+	if md.IsSnap() && strings.HasPrefix(dumpDirArg, snapTmpDir) {
+		dumpDirArg = strings.TrimPrefix(dumpDirArg, snapTmpDir)
+	}
+	if err := runCommandFn(md.binPath, md.options(dumpDirArg)...); err != nil {
+		return errors.Annotate(err, "error restoring databases")
+	}
+	return nil
+}
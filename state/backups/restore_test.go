@@ -0,0 +1,190 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+)
+
+type restoreSuite struct {
+	backupstesting.BaseSuite
+
+	paths   *backups.Paths
+	api     backups.Backups
+	rootDir string
+
+	restorer  *fakeRestorer
+	restarted []string
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+type fakeRestorer struct {
+	dumpDir string
+	err     error
+}
+
+func (r *fakeRestorer) Restore(dumpDir string) error {
+	r.dumpDir = dumpDir
+	return r.err
+}
+
+func (*fakeRestorer) IsSnap() bool {
+	return false
+}
+
+func (s *restoreSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.rootDir = c.MkDir()
+	s.paths = &backups.Paths{
+		BackupDir: c.MkDir(),
+		DataDir:   "/var/lib/juju",
+	}
+	s.api = backups.NewBackups(s.paths)
+
+	s.restorer = &fakeRestorer{}
+	s.PatchValue(backups.GetDBRestorer, func(*backups.DBInfo) (backups.DBRestorer, error) {
+		return s.restorer, nil
+	})
+	s.restarted = nil
+	s.PatchValue(backups.FindAgents, func(dataDir string) (string, []string, []string, error) {
+		c.Check(dataDir, gc.Equals, filepath.Join(s.rootDir, "/var/lib/juju"))
+		return "machine-0", []string{"unit-dashboard-0"}, nil, nil
+	})
+	s.PatchValue(backups.RestartAgent, func(name string) error {
+		s.restarted = append(s.restarted, name)
+		return nil
+	})
+}
+
+func (s *restoreSuite) addArchive(c *gc.C, vers version.Number) string {
+	meta := backupstesting.NewMetadata()
+	meta.Origin.Version = vers
+	files := []backupstesting.File{{
+		Name:    "var/lib/juju/agents/machine-0/agent.conf",
+		Content: "<restored agent config>",
+	}, {
+		Name:    "var/lib/juju/dqlite/cluster.yaml",
+		Content: "<restored cluster>",
+	}}
+	dump := []backupstesting.File{{
+		Name:    "oplog.bson",
+		Content: "<BSON data goes here>",
+	}}
+	archive, err := backupstesting.NewArchive(meta, files, dump)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fileName, err := s.api.Add(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	return fileName
+}
+
+func (s *restoreSuite) restoreArgs() backups.RestoreArgs {
+	return backups.RestoreArgs{
+		ControllerVersion: version.MustParse("3.3.1"),
+		HANodes:           1,
+		RootDir:           s.rootDir,
+	}
+}
+
+func (s *restoreSuite) TestAdd(c *gc.C) {
+	fileName := s.addArchive(c, version.MustParse("3.3.1"))
+	c.Check(filepath.Dir(fileName), gc.Equals, s.paths.BackupDir)
+	c.Check(filepath.Base(fileName), gc.Matches, `juju-backup-uploaded-\d{8}-\d{6}\.tar\.gz`)
+}
+
+func (s *restoreSuite) TestRestore(c *gc.C) {
+	fileName := s.addArchive(c, version.MustParse("3.3.0"))
+
+	stale := filepath.Join(s.rootDir, "/var/lib/juju/dqlite/stale.db")
+	err := os.MkdirAll(filepath.Dir(stale), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(stale, []byte("stale"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.api.Restore(fileName, &backups.DBInfo{}, s.restoreArgs())
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(filepath.Base(s.restorer.dumpDir), gc.Equals, "dump")
+	data, err := os.ReadFile(filepath.Join(s.rootDir, "/var/lib/juju/agents/machine-0/agent.conf"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<restored agent config>")
+	_, err = os.Stat(filepath.Join(s.rootDir, "/var/lib/juju/dqlite/cluster.yaml"))
+	c.Check(err, jc.ErrorIsNil)
+	_, err = os.Stat(stale)
+	c.Check(err, jc.Satisfies, os.IsNotExist)
+	c.Check(s.restarted, jc.DeepEquals, []string{"jujud-unit-dashboard-0", "jujud-machine-0"})
+}
+
+func (s *restoreSuite) TestRestoreVersionMismatch(c *gc.C) {
+	fileName := s.addArchive(c, version.MustParse("3.2.4"))
+
+	err := s.api.Restore(fileName, &backups.DBInfo{}, s.restoreArgs())
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+	c.Check(err, gc.ErrorMatches, `restoring a juju 3.2.4 backup onto a juju 3.3.1 controller not supported`)
+	c.Check(s.restorer.dumpDir, gc.Equals, "")
+	c.Check(s.restarted, gc.HasLen, 0)
+}
+
+func (s *restoreSuite) TestRestoreHA(c *gc.C) {
+	fileName := s.addArchive(c, version.MustParse("3.3.1"))
+
+	args := s.restoreArgs()
+	args.HANodes = 3
+	err := s.api.Restore(fileName, &backups.DBInfo{}, args)
+	c.Assert(err, gc.ErrorMatches, `restoring onto a controller with 3 nodes in HA not supported`)
+}
+
+func (s *restoreSuite) TestRestoreInvalidFile(c *gc.C) {
+	err := s.api.Restore("/etc/passwd", &backups.DBInfo{}, s.restoreArgs())
+	c.Assert(err, jc.ErrorIs, errors.NotValid)
+}
+
+func (s *restoreSuite) TestRestoreDBFailure(c *gc.C) {
+	fileName := s.addArchive(c, version.MustParse("3.3.1"))
+	s.restorer.err = errors.New("boom")
+
+	err := s.api.Restore(fileName, &backups.DBInfo{}, s.restoreArgs())
+	c.Assert(err, gc.ErrorMatches, `while restoring database: boom`)
+	c.Check(s.restarted, gc.HasLen, 0)
+}
+
+func (s *restoreSuite) TestValidateRestoreVersion(c *gc.C) {
+	controller := version.MustParse("3.3.1")
+	for i, test := range []struct {
+		backup string
+		err    string
+	}{{
+		backup: "3.3.1",
+	}, {
+		backup: "3.3.0",
+	}, {
+		backup: "3.3.2",
+		err:    `restoring a juju 3.3.2 backup onto an older juju 3.3.1 controller not supported`,
+	}, {
+		backup: "3.2.1",
+		err:    `restoring a juju 3.2.1 backup onto a juju 3.3.1 controller not supported`,
+	}, {
+		backup: "9999.9999.9999",
+		err:    `backup with unknown juju version not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.backup)
+		err := backups.ValidateRestoreVersion(version.MustParse(test.backup), controller)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
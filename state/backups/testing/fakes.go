@@ -41,6 +41,8 @@ type FakeBackups struct {
 	InstanceId instance.Id
 	// ArchiveArg holds the backup archive that was passed in.
 	ArchiveArg io.Reader
+	// RestoreArgs holds the restore args that were passed in.
+	RestoreArgs backups.RestoreArgs
 }
 
 var _ backups.Backups = (*FakeBackups)(nil)
@@ -69,3 +71,19 @@ func (b *FakeBackups) Get(id string) (*backups.Metadata, io.ReadCloser, error) {
 	b.IDArg = id
 	return b.Meta, b.Archive, b.Error
 }
+
+// Add stores the archive and returns its filename.
+func (b *FakeBackups) Add(archive io.Reader) (string, error) {
+	b.Calls = append(b.Calls, "Add")
+	b.ArchiveArg = archive
+	return b.Filename, b.Error
+}
+
+// Restore restores the archive with the given filename.
+func (b *FakeBackups) Restore(fileName string, dbInfo *backups.DBInfo, args backups.RestoreArgs) error {
+	b.Calls = append(b.Calls, "Restore")
+	b.IDArg = fileName
+	b.DBInfoArg = dbInfo
+	b.RestoreArgs = args
+	return b.Error
+}
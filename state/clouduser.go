@@ -60,7 +60,12 @@ func (st *State) GetCloudUsers(cloud string) (map[string]permission.Access, erro
 	}
 	result := make(map[string]permission.Access)
 	for _, p := range perms {
-		result[userIDFromGlobalKey(p.doc.SubjectGlobalKey)] = p.access()
+		user := userIDFromGlobalKey(p.doc.SubjectGlobalKey)
+		if user == p.doc.SubjectGlobalKey {
+			// Not a user subject
+			continue
+		}
+		result[user] = p.access()
 	}
 	return result, nil
 }
@@ -171,6 +176,15 @@ func (st *State) cloudNamesForUser(user names.UserTag) ([]string, error) {
 	if err := iter.Close(); err != nil {
 		return nil, errors.Trace(err)
 	}
+
+	// Include the clouds that any of the user's groups have access to.
+	groupPermissions, err := st.userGroupPermissionDocs(user, cloudGlobalKey(""))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, doc := range groupPermissions {
+		cloudNames = append(cloudNames, strings.TrimPrefix(doc.ObjectGlobalKey, "cloud#"))
+	}
 	return cloudNames, nil
 }
 
@@ -206,5 +220,26 @@ func (st *State) fillInCloudUserAccess(user names.UserTag, cloudInfo []CloudInfo
 			details.Access = access
 		}
 	}
-	return iter.Close()
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+
+	// The user may also have access through the groups they are a
+	// member of.
+	groupPermissions, err := st.userGroupPermissionDocs(user, cloudGlobalKey(""))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, doc := range groupPermissions {
+		cloudIdx, ok := indexByName[strings.TrimPrefix(doc.ObjectGlobalKey, "cloud#")]
+		if !ok {
+			continue
+		}
+		details := &cloudInfo[cloudIdx]
+		access := permission.Access(doc.Access)
+		if err := access.Validate(); err == nil {
+			details.Access = greaterAccess(names.CloudTagKind, details.Access, access)
+		}
+	}
+	return nil
 }
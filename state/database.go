@@ -4,6 +4,7 @@
 package state
 
 import (
+	"context"
 	"runtime/debug"
 	"sync"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/kr/pretty"
 
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/mongo"
 )
@@ -264,6 +266,10 @@ type database struct {
 	// many attempts a txn should have.
 	maxTxnAttempts int
 
+	// ctx holds the span, if any, that the transactions run through
+	// the database are recorded under.
+	ctx context.Context
+
 	mu           sync.RWMutex
 	queryTracker *queryTracker
 }
@@ -282,9 +288,29 @@ func (db *database) copySession(modelUUID string) (*database, SessionCloser) {
 		ownSession:     true,
 		clock:          db.clock,
 		maxTxnAttempts: db.maxTxnAttempts,
+		ctx:            db.ctx,
 	}, session.Close
 }
 
+// withContext returns a copy of the database, sharing its session,
+// whose transactions are recorded as spans under the span in ctx.
+func (db *database) withContext(ctx context.Context) *database {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return &database{
+		raw:                    db.raw,
+		schema:                 db.schema,
+		modelUUID:              db.modelUUID,
+		runner:                 db.runner,
+		ownSession:             db.ownSession,
+		runTransactionObserver: db.runTransactionObserver,
+		clock:                  db.clock,
+		maxTxnAttempts:         db.maxTxnAttempts,
+		ctx:                    ctx,
+		queryTracker:           db.queryTracker,
+	}
+}
+
 func (db *database) setTracker(tracker *queryTracker) {
 	db.mu.Lock()
 	db.queryTracker = tracker
@@ -371,13 +397,7 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 				txnLogger.Tracef("ran transaction in %.3fs (retries: %d) %# v\nerr: %v",
 					t.Duration.Seconds(), t.Attempt, pretty.Formatter(t.Ops), t.Error)
 			}
-		}
-		if db.runTransactionObserver != nil {
-			observer = func(t jujutxn.Transaction) {
-				if txnLogger.IsTraceEnabled() {
-					txnLogger.Tracef("ran transaction in %.3fs (retries: %d) %# v\nerr: %v",
-						t.Duration.Seconds(), t.Attempt, pretty.Formatter(t.Ops), t.Error)
-				}
+			if db.runTransactionObserver != nil {
 				db.runTransactionObserver(
 					db.raw.Name, db.modelUUID,
 					t.Attempt,
@@ -385,6 +405,7 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 					t.Ops, t.Error,
 				)
 			}
+			db.traceTransaction(t)
 		}
 		params := jujutxn.RunnerParams{
 			Database:                  raw,
@@ -404,6 +425,28 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 	}, closer
 }
 
+// traceTransaction records the transaction as a span under the
+// database's span. The span is started retrospectively, as the
+// transaction has already run. Transactions run without a span, for
+// example by workers, aren't recorded; they would only be unrelated
+// root spans.
+func (db *database) traceTransaction(t jujutxn.Transaction) {
+	if db.ctx == nil || !trace.SpanFromContext(db.ctx).Scope().Sampled {
+		return
+	}
+	_, span := trace.Start(db.ctx, "state.RunTransaction",
+		trace.WithStartTime(db.clock.Now().Add(-t.Duration)),
+		trace.WithAttributes(
+			trace.StringAttr("db.name", db.raw.Name),
+			trace.StringAttr("juju.model-uuid", db.modelUUID),
+			trace.IntAttr("txn.attempt", t.Attempt),
+			trace.IntAttr("txn.ops", len(t.Ops)),
+		),
+	)
+	span.RecordError(t.Error)
+	span.End()
+}
+
 // RunTransaction is part of the Database interface.
 func (db *database) RunTransaction(ops []txn.Op) error {
 	runner, closer := db.TransactionRunner()
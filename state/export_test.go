@@ -1099,7 +1099,7 @@ func ModelBackendFromStorageBackend(sb *StorageBackend) modelBackend {
 }
 
 func (st *State) ModelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.Query, SessionCloser, error) {
-	return st.modelQueryForUser(user, isSuperuser)
+	return st.modelQueryForUser(user, isSuperuser, true)
 }
 
 func UnitsHaveChanged(m *Machine, unitNames []string) (bool, error) {
@@ -67,6 +67,11 @@ type Filesystem interface {
 	// Releasing reports whether or not the filesystem is to be released
 	// from the model when it is Dying/Dead.
 	Releasing() bool
+
+	// PendingSize returns the size in MiB that the filesystem is to
+	// be grown to, and true, if its backing volume has been resized
+	// and the filesystem has not yet been grown to match.
+	PendingSize() (uint64, bool)
 }
 
 // FilesystemAttachment describes an attachment of a filesystem to a machine.
@@ -121,6 +126,10 @@ type filesystemDoc struct {
 	// the filesystem as being non-detachable, and to determine
 	// which filesystems must be removed along with said machine.
 	HostId string `bson:"hostid,omitempty"`
+
+	// ResizeTo is the size in MiB that the filesystem is to be grown
+	// to by the machine storage provisioner, if non-zero.
+	ResizeTo uint64 `bson:"resizeto,omitempty"`
 }
 
 // filesystemAttachmentDoc records information about a filesystem attachment.
@@ -153,6 +162,10 @@ type FilesystemParams struct {
 	// filesystem entity for an existing volume backed filesystem.
 	volumeInfo *VolumeInfo
 
+	// snapshot, if non-empty, is the ID of the volume snapshot from
+	// which the filesystem's backing volume should be created.
+	snapshot string
+
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
 }
@@ -252,6 +265,11 @@ func (f *filesystem) Releasing() bool {
 	return f.doc.Releasing
 }
 
+// PendingSize is required to implement Filesystem.
+func (f *filesystem) PendingSize() (uint64, bool) {
+	return f.doc.ResizeTo, f.doc.ResizeTo != 0
+}
+
 // Status is required to implement StatusGetter.
 func (f *filesystem) Status() (status.StatusInfo, error) {
 	return getStatus(f.mb.db(), filesystemGlobalKey(f.FilesystemTag().Id()), "filesystem")
@@ -1101,10 +1119,11 @@ func (sb *storageBackend) addFilesystemOps(params FilesystemParams, hostId strin
 			params.filesystemId = filesystemTag.String()
 		}
 		volumeParams := VolumeParams{
-			params.storage,
-			params.volumeInfo,
-			params.Pool,
-			params.Size,
+			storage:    params.storage,
+			volumeInfo: params.volumeInfo,
+			Pool:       params.Pool,
+			Size:       params.Size,
+			Snapshot:   params.snapshot,
 		}
 		volumeOps, volumeTag, err = sb.addVolumeOps(volumeParams, hostId)
 		if err != nil {
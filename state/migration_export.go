@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/payloads"
+	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/core/resources"
 	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/feature"
@@ -351,6 +352,7 @@ func (e *exporter) modelUsers() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	annotations := e.model.Annotations()
 	for _, user := range users {
 		lastConn := lastConnections[strings.ToLower(user.UserName)]
 		arg := description.UserArgs{
@@ -362,7 +364,21 @@ func (e *exporter) modelUsers() error {
 			Access:         string(user.Access),
 		}
 		e.model.AddUser(arg)
+
+		// Custom roles are exported by name; they must exist on the
+		// target controller.
+		role, err := e.st.ModelUserRole(user.UserTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := permission.BuiltinRole(role); !ok && role != "" {
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[modelUserRoleAnnotationPrefix+user.UserTag.Id()] = role
+		}
 	}
+	e.model.SetAnnotations(annotations)
 	return nil
 }
 
@@ -2891,7 +2907,12 @@ func (e *exporter) addStorage(instance *storageInstance, attachments []names.Uni
 	if !ok {
 		owner = nil
 	}
-	cons := description.StorageInstanceConstraints(instance.doc.Constraints)
+	// Volume snapshots are not migrated, so the snapshot
+	// constraint is dropped.
+	cons := description.StorageInstanceConstraints{
+		Pool: instance.doc.Constraints.Pool,
+		Size: instance.doc.Constraints.Size,
+	}
 	args := description.StorageArgs{
 		Tag:         instance.StorageTag(),
 		Kind:        instance.Kind().String(),
@@ -251,6 +251,29 @@ func (s *MigrationExportSuite) TestModelUsers(c *gc.C) {
 	c.Assert(exportedBob.Access(), gc.Equals, "read")
 }
 
+func (s *MigrationExportSuite) TestModelUserRoles(c *gc.C) {
+	_, err := s.State.AddRole(operatorRole, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	bobTag := names.NewUserTag("bob@external")
+	_, err = s.Model.AddUser(state.UserAccessSpec{
+		User:      bobTag,
+		CreatedBy: s.Owner,
+		Access:    permission.ReadAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelUserRole(bobTag, "operator")
+	c.Assert(err, jc.ErrorIsNil)
+
+	model, err := s.State.Export(map[string]string{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only custom roles are exported; the owner has the built-in role
+	// of their access.
+	c.Assert(model.Annotations(), jc.DeepEquals, map[string]string{
+		"juju-model-user-role/bob@external": "operator",
+	})
+}
+
 func (s *MigrationExportSuite) TestSLAs(c *gc.C) {
 	err := s.State.SetSLA("essential", "bob", []byte("creds"))
 	c.Assert(err, jc.ErrorIsNil)
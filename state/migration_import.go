@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/juju/charm/v12"
@@ -343,7 +344,14 @@ func (i *importer) modelExtras() error {
 		}
 	}
 
-	if annotations := i.model.Annotations(); len(annotations) > 0 {
+	annotations := make(map[string]string)
+	for key, value := range i.model.Annotations() {
+		// The roles of the model users are imported with them.
+		if !strings.HasPrefix(key, modelUserRoleAnnotationPrefix) {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) > 0 {
 		if err := i.dbModel.SetAnnotations(i.dbModel, annotations); err != nil {
 			return errors.Trace(err)
 		}
@@ -416,6 +424,10 @@ func (i *importer) modelUsers() error {
 	}
 
 	users := i.model.Users()
+	roles, err := i.modelUserRoles(users)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	modelUUID := i.dbModel.UUID()
 	var ops []txn.Op
 	for _, user := range users {
@@ -431,6 +443,11 @@ func (i *importer) modelUsers() error {
 	if err := i.st.db().RunTransaction(ops); err != nil {
 		return errors.Trace(err)
 	}
+	for user, role := range roles {
+		if err := i.st.SetModelUserRole(user, role); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	// Now set their last connection times.
 	for _, user := range users {
 		i.logger.Debugf("user %s", user.Name())
@@ -446,6 +463,28 @@ func (i *importer) modelUsers() error {
 	return nil
 }
 
+// modelUserRoles returns the custom roles granted to the model users
+// being imported. It returns an error if any of the roles is not
+// defined on this controller, rather than importing the users without
+// the restrictions of their roles.
+func (i *importer) modelUserRoles(users []description.User) (map[names.UserTag]string, error) {
+	annotations := i.model.Annotations()
+	roles := make(map[names.UserTag]string)
+	for _, user := range users {
+		role, ok := annotations[modelUserRoleAnnotationPrefix+user.Name().Id()]
+		if !ok {
+			continue
+		}
+		if _, err := i.st.Role(role); errors.Is(err, errors.NotFound) {
+			return nil, errors.NotFoundf("role %q, granted to model user %q,", role, user.Name().Id())
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		roles[user.Name()] = role
+	}
+	return roles, nil
+}
+
 func (i *importer) machines() error {
 	i.logger.Debugf("importing machines")
 	for _, m := range i.model.Machines() {
@@ -2405,7 +2444,10 @@ func (i *importer) addStorageInstance(storage description.Storage) error {
 
 func (i *importer) storageInstanceConstraints(storage description.Storage) storageInstanceConstraints {
 	if cons, ok := storage.Constraints(); ok {
-		return storageInstanceConstraints(cons)
+		return storageInstanceConstraints{
+			Pool: cons.Pool,
+			Size: cons.Size,
+		}
 	}
 	// Older versions of Juju did not record storage constraints on the
 	// storage instance, so we must do what we do during upgrade steps:
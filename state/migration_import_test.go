@@ -279,6 +279,46 @@ func (s *MigrationImportSuite) TestModelUsers(c *gc.C) {
 	c.Assert(allUsers, gc.HasLen, 3)
 }
 
+func (s *MigrationImportSuite) TestModelUserRoles(c *gc.C) {
+	_, err := s.State.AddRole(operatorRole, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	lastConnection := state.NowToTheSecond(s.State)
+	bravo := s.newModelUser(c, "bravo@external", false, lastConnection)
+	err = s.State.SetModelUserRole(bravo.UserTag, "operator")
+	c.Assert(err, jc.ErrorIsNil)
+
+	newModel, newSt := s.importModel(c, s.State)
+
+	role, err := newSt.ModelUserRole(bravo.UserTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role, gc.Equals, "operator")
+	newUser, err := newSt.UserAccess(bravo.UserTag, newModel.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newUser.Access, gc.Equals, permission.WriteAccess)
+
+	// The roles are not imported as model annotations.
+	annotations, err := newModel.Annotations(newModel)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 0)
+}
+
+func (s *MigrationImportSuite) TestModelUserRoleMissing(c *gc.C) {
+	lastConnection := state.NowToTheSecond(s.State)
+	s.newModelUser(c, "bravo@external", false, lastConnection)
+
+	out, err := s.State.Export(map[string]string{})
+	c.Assert(err, jc.ErrorIsNil)
+	out.SetAnnotations(map[string]string{
+		"juju-model-user-role/bravo@external": "operator",
+	})
+
+	uuid := utils.MustNewUUID().String()
+	in := newModel(out, uuid, "new")
+	_, _, err = s.Controller.Import(in)
+	c.Assert(err, gc.ErrorMatches, `.*role "operator", granted to model user "bravo@external", not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *MigrationImportSuite) TestSLA(c *gc.C) {
 	err := s.State.SetSLA("essential", "bob", []byte("creds"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -121,6 +121,15 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Controller users contain extra data about users therefore
 		// are not migrated either.
 		controllerUsersC,
+		// User groups are controller wide, and are not migrated.
+		userGroupsC,
+		// Roles are controller wide, and are not migrated; the
+		// custom roles granted to model users are exported by name,
+		// and must exist on the target controller.
+		rolesC,
+		// The audit log is controller wide, and is not migrated.
+		auditConversationsC,
+		auditLogC,
 		// userenvnameC is just to provide a unique key constraint.
 		usermodelnameC,
 		// Metrics aren't migrated.
@@ -214,6 +223,14 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Secret backends are per controller.
 		secretBackendsC,
 		secretBackendsRotateC,
+
+		// Volume snapshots are not yet migrated. The snapshots
+		// remain in the cloud, and can be imported by hand.
+		volumeSnapshotsC,
+
+		// Model quotas are set by the administrators of the
+		// source controller, and do not apply to the target.
+		modelQuotasC,
 	)
 
 	// THIS SET WILL BE REMOVED WHEN MIGRATIONS ARE COMPLETE
@@ -774,6 +791,7 @@ func (s *MigrationSuite) TestVolumeDocFields(c *gc.C) {
 		"Life",
 		"HostId",    // recreated from pool properties
 		"Releasing", // only when dying; can't migrate dying storage
+		"ResizeTo",  // in-flight resizes are not migrated
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -786,8 +804,10 @@ func (s *MigrationSuite) TestVolumeDocFields(c *gc.C) {
 	// The info and params fields ar structs.
 	s.AssertExportedFields(c, VolumeInfo{}, set.NewStrings(
 		"HardwareId", "WWN", "Size", "Pool", "VolumeId", "Persistent"))
+	// Snapshot is only set until the volume is provisioned, and
+	// volume snapshots are not migrated.
 	s.AssertExportedFields(c, VolumeParams{}, set.NewStrings(
-		"Size", "Pool"))
+		"Size", "Pool", "Snapshot"))
 }
 
 func (s *MigrationSuite) TestVolumeAttachmentDocFields(c *gc.C) {
@@ -817,6 +837,7 @@ func (s *MigrationSuite) TestFilesystemDocFields(c *gc.C) {
 		"Life",
 		"HostId",    // recreated from pool properties
 		"Releasing", // only when dying; can't migrate dying storage
+		"ResizeTo",  // in-flight resizes are not migrated
 	)
 	migrated := set.NewStrings(
 		"FilesystemId",
@@ -877,6 +898,7 @@ func (s *MigrationSuite) TestStorageAttachmentDocFields(c *gc.C) {
 		"ModelUUID",
 		"DocID",
 		"Life",
+		"Resized", // pending storage-resized hooks are not migrated
 	)
 	migrated := set.NewStrings(
 		"Unit",
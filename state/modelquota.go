@@ -0,0 +1,456 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/mgo/v3"
+	"github.com/juju/mgo/v3/bson"
+	"github.com/juju/mgo/v3/txn"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/quota"
+)
+
+// modelQuotasDoc records the resource quotas of a model. A zero
+// amount means that the resource is not limited.
+type modelQuotasDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Machines  int    `bson:"machines"`
+	Units     int    `bson:"units"`
+	Cores     uint64 `bson:"cores"`
+	Memory    uint64 `bson:"memory"`
+	Storage   uint64 `bson:"storage"`
+
+	// UsageChanges counts the transactions which have been checked
+	// against the quotas. Each such transaction asserts the revision
+	// of the document and increments it, so that they are serialised.
+	UsageChanges int64 `bson:"usage-changes"`
+	TxnRevno     int64 `bson:"txn-revno,omitempty"`
+}
+
+func (doc modelQuotasDoc) resources() quota.ModelResources {
+	return quota.ModelResources{
+		Machines: doc.Machines,
+		Units:    doc.Units,
+		Cores:    doc.Cores,
+		Memory:   doc.Memory,
+		Storage:  doc.Storage,
+	}
+}
+
+// ModelQuotas returns the resource quotas of the model. A zero amount
+// means that the resource is not limited; a model on which no quotas
+// have been set returns zero for every resource.
+func (st *State) ModelQuotas() (quota.ModelResources, error) {
+	coll, closer := st.db().GetCollection(modelQuotasC)
+	defer closer()
+
+	var doc modelQuotasDoc
+	if err := coll.FindId(modelGlobalKey).One(&doc); err == mgo.ErrNotFound {
+		return quota.ModelResources{}, nil
+	} else if err != nil {
+		return quota.ModelResources{}, errors.Annotate(err, "cannot get model quotas")
+	}
+	return doc.resources(), nil
+}
+
+// SetModelQuotas replaces the resource quotas of the model. A zero
+// amount means that the resource is not limited.
+//
+// Quotas are enforced when machines and units are added to the model,
+// and when applications are scaled or constrained; lowering a quota
+// below the resources the model already consumes does not remove
+// anything from the model.
+func (st *State) SetModelQuotas(quotas quota.ModelResources) error {
+	if err := quotas.Validate(); err != nil {
+		return errors.Annotate(err, "cannot set model quotas")
+	}
+	fields := bson.D{
+		{"machines", quotas.Machines},
+		{"units", quotas.Units},
+		{"cores", quotas.Cores},
+		{"memory", quotas.Memory},
+		{"storage", quotas.Storage},
+	}
+	coll, closer := st.db().GetCollection(modelQuotasC)
+	defer closer()
+
+	buildTxn := func(int) ([]txn.Op, error) {
+		model, err := st.Model()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if model.Life() != Alive {
+			return nil, errors.Errorf("model %q is no longer alive", model.Name())
+		}
+		n, err := coll.FindId(modelGlobalKey).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		op := txn.Op{
+			C:  modelQuotasC,
+			Id: modelGlobalKey,
+		}
+		if n == 0 {
+			op.Assert = txn.DocMissing
+			op.Insert = &modelQuotasDoc{
+				Machines: quotas.Machines,
+				Units:    quotas.Units,
+				Cores:    quotas.Cores,
+				Memory:   quotas.Memory,
+				Storage:  quotas.Storage,
+			}
+		} else {
+			op.Assert = txn.DocExists
+			op.Update = bson.D{{"$set", fields}}
+		}
+		return []txn.Op{assertModelActiveOp(st.ModelUUID()), op}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return errors.Annotate(err, "cannot set model quotas")
+	}
+	return nil
+}
+
+// modelQuotaOps returns operations which check a transaction that
+// consumes the requested resources against the model's quotas. The
+// model's usage is read when the operations are built, and they assert
+// that the quotas document is unchanged since and increment its usage
+// count, so that concurrent transactions which each fit within the
+// quotas can't together exceed them; each is checked against the usage
+// of those committed before it. No operations are returned if the
+// model has no quotas: a change committed as quotas are set is no
+// different to one committed just before.
+func (st *State) modelQuotaOps(requested quota.ModelResources) ([]txn.Op, error) {
+	if requested.IsZero() {
+		return nil, nil
+	}
+	coll, closer := st.db().GetCollection(modelQuotasC)
+	defer closer()
+
+	var doc modelQuotasDoc
+	if err := coll.FindId(modelGlobalKey).One(&doc); err == mgo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get model quotas")
+	}
+	quotas := doc.resources()
+	if quotas.IsZero() {
+		return nil, nil
+	}
+	usage, err := st.ModelResourceUsage()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	checker := quota.NewModelQuotaChecker(quotas, usage)
+	checker.Check(requested)
+	if err := checker.Outcome(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      modelQuotasC,
+		Id:     modelGlobalKey,
+		Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+		Update: bson.D{{"$inc", bson.D{{"usage-changes", 1}}}},
+	}}, nil
+}
+
+// machineQuotaResources returns the resources consumed by a new top
+// level machine created from the given template, including the storage
+// created with it.
+func (st *State) machineQuotaResources(template MachineTemplate) (quota.ModelResources, error) {
+	requested := containerQuotaResources(template)
+	if template.InstanceId != "" {
+		return requested.Add(quota.HardwareResources(template.HardwareCharacteristics)), nil
+	}
+	cons := template.Constraints
+	if template.Placement == "" {
+		var err error
+		if cons, err = st.resolveMachineConstraints(cons); err != nil {
+			return quota.ModelResources{}, errors.Trace(err)
+		}
+	}
+	return requested.Add(quota.ConstraintsResources(cons)), nil
+}
+
+// containerQuotaResources returns the resources consumed by a new
+// container created from the given template, including the storage
+// created with it. Containers consume the cores and memory of their
+// host. A filesystem backed by a volume is counted once, as the volume
+// has the size of the filesystem.
+func containerQuotaResources(template MachineTemplate) quota.ModelResources {
+	requested := quota.ModelResources{Machines: 1}
+	for _, v := range template.Volumes {
+		requested.Storage += v.Volume.Size
+	}
+	for _, f := range template.Filesystems {
+		requested.Storage += f.Filesystem.Size
+	}
+	return requested
+}
+
+// unitQuotaResources returns the resources consumed by numUnits new
+// units of an application with the given constraints. Subordinate
+// units don't count towards the units quota. In IAAS models, the cores
+// and memory used by units are counted when their machines are added.
+func (st *State) unitQuotaResources(
+	modelType ModelType, subordinate bool, cons constraints.Value, numUnits int,
+) (quota.ModelResources, error) {
+	var requested quota.ModelResources
+	if !subordinate {
+		requested.Units = numUnits
+	}
+	if modelType != ModelTypeCAAS {
+		return requested, nil
+	}
+	modelCons, err := st.ModelConstraints()
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return requested, errors.Trace(err)
+	}
+	return requested.Add(caasUnitResources(cons, modelCons).Times(numUnits)), nil
+}
+
+// unitQuotaOps returns operations which check a change to the
+// application's scale or number of units against the model's quotas.
+// Container-based applications count the greater of their scale and
+// their number of units.
+func (a *Application) unitQuotaOps(scale, unitCount int) ([]txn.Op, error) {
+	model, err := a.st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	numUnits := unitCount - a.doc.UnitCount
+	if model.Type() == ModelTypeCAAS {
+		numUnits = max(scale, unitCount) - max(a.doc.DesiredScale, a.doc.UnitCount)
+	}
+	if numUnits <= 0 {
+		return nil, nil
+	}
+	cons, err := readConstraints(a.st, a.globalKey())
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return nil, errors.Trace(err)
+	}
+	requested, err := a.st.unitQuotaResources(model.Type(), a.doc.Subordinate, cons, numUnits)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return a.st.modelQuotaOps(requested)
+}
+
+// constraintsQuotaOps returns operations which check a change to the
+// constraints of the application against the model's quotas. Only
+// container-based applications are checked, as each of their units
+// consumes the cores and memory of the application constraints; in
+// IAAS models, the constraints apply to machines added later, which
+// are checked when they are added.
+func (a *Application) constraintsQuotaOps(cons constraints.Value) ([]txn.Op, error) {
+	model, err := a.st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	numUnits := max(a.doc.DesiredScale, a.doc.UnitCount)
+	if model.Type() != ModelTypeCAAS || numUnits == 0 {
+		return nil, nil
+	}
+	current, err := readConstraints(a.st, a.globalKey())
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return nil, errors.Trace(err)
+	}
+	modelCons, err := a.st.ModelConstraints()
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return nil, errors.Trace(err)
+	}
+	from, to := caasUnitResources(current, modelCons), caasUnitResources(cons, modelCons)
+	var requested quota.ModelResources
+	if to.Cores > from.Cores {
+		requested.Cores = to.Cores - from.Cores
+	}
+	if to.Memory > from.Memory {
+		requested.Memory = to.Memory - from.Memory
+	}
+	return a.st.modelQuotaOps(requested.Times(numUnits))
+}
+
+// caasUnitResources returns the cores and memory consumed by a unit of
+// a container-based application with the given constraints, falling
+// back to the model constraints for those not set.
+func caasUnitResources(cons, modelCons constraints.Value) quota.ModelResources {
+	if !cons.HasCpuCores() {
+		cons.CpuCores = modelCons.CpuCores
+	}
+	if !cons.HasMem() {
+		cons.Mem = modelCons.Mem
+	}
+	return quota.ConstraintsResources(cons)
+}
+
+// ModelResourceUsage returns the resources currently consumed by the
+// model, for comparison with the model's quotas.
+//
+// Every machine, including containers, counts towards the machines
+// quota. The cores and memory of top-level machines are counted from
+// their hardware characteristics once provisioned, and from their
+// constraints until then. Only principal units count towards the
+// units quota. Container-based applications count the greater of
+// their scale and their number of units, and each such unit counts
+// the cores and memory of the application's constraints. Storage
+// counts the size of every volume, and of every filesystem not backed
+// by a volume.
+func (st *State) ModelResourceUsage() (quota.ModelResources, error) {
+	var usage quota.ModelResources
+	model, err := st.Model()
+	if err != nil {
+		return usage, errors.Trace(err)
+	}
+
+	var computeUsage quota.ModelResources
+	if model.Type() == ModelTypeCAAS {
+		computeUsage, err = st.caasComputeUsage()
+	} else {
+		units, closer := st.db().GetCollection(unitsC)
+		defer closer()
+		if usage.Units, err = units.Find(bson.D{{"principal", ""}}).Count(); err != nil {
+			return usage, errors.Annotate(err, "cannot count units")
+		}
+		computeUsage, err = st.machineComputeUsage()
+	}
+	if err != nil {
+		return usage, errors.Trace(err)
+	}
+	usage = usage.Add(computeUsage)
+
+	storageUsage, err := st.storageUsage()
+	if err != nil {
+		return usage, errors.Trace(err)
+	}
+	return usage.Add(storageUsage), nil
+}
+
+// machineComputeUsage returns the machines, cores and memory consumed
+// by the machines in the model.
+func (st *State) machineComputeUsage() (quota.ModelResources, error) {
+	var usage quota.ModelResources
+
+	machines, closer := st.db().GetCollection(machinesC)
+	defer closer()
+	var machineDocs []machineDoc
+	if err := machines.Find(nil).Select(bson.D{
+		{"machineid", 1}, {"containertype", 1},
+	}).All(&machineDocs); err != nil {
+		return usage, errors.Annotate(err, "cannot get machines")
+	}
+
+	instances, closer := st.db().GetCollection(instanceDataC)
+	defer closer()
+	var instanceDocs []instanceData
+	if err := instances.Find(nil).All(&instanceDocs); err != nil {
+		return usage, errors.Annotate(err, "cannot get instance data")
+	}
+	hardware := make(map[string]instanceData, len(instanceDocs))
+	for _, doc := range instanceDocs {
+		hardware[doc.MachineId] = doc
+	}
+
+	for _, doc := range machineDocs {
+		usage.Machines++
+		if doc.ContainerType != "" {
+			// Containers consume the resources of their host.
+			continue
+		}
+		if inst, ok := hardware[doc.Id]; ok {
+			usage = usage.Add(quota.HardwareResources(*hardwareCharacteristics(inst)))
+			continue
+		}
+		cons, err := readConstraints(st, machineGlobalKey(doc.Id))
+		if errors.Is(err, errors.NotFound) {
+			continue
+		} else if err != nil {
+			return usage, errors.Trace(err)
+		}
+		usage = usage.Add(quota.ConstraintsResources(cons))
+	}
+	return usage, nil
+}
+
+// caasComputeUsage returns the units, cores and memory consumed by
+// the container-based applications in the model. An application which
+// is scaling up counts the units it is scaling to.
+func (st *State) caasComputeUsage() (quota.ModelResources, error) {
+	var usage quota.ModelResources
+
+	modelCons, err := st.ModelConstraints()
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return usage, errors.Trace(err)
+	}
+
+	applications, closer := st.db().GetCollection(applicationsC)
+	defer closer()
+	var appDocs []applicationDoc
+	if err := applications.Find(nil).Select(bson.D{
+		{"name", 1}, {"unitcount", 1}, {"scale", 1}, {"subordinate", 1},
+	}).All(&appDocs); err != nil {
+		return usage, errors.Annotate(err, "cannot get applications")
+	}
+	for _, doc := range appDocs {
+		units := doc.UnitCount
+		if doc.DesiredScale > units {
+			units = doc.DesiredScale
+		}
+		if units == 0 {
+			continue
+		}
+		if !doc.Subordinate {
+			usage.Units += units
+		}
+		cons, err := readConstraints(st, applicationGlobalKey(doc.Name))
+		if err != nil && !errors.Is(err, errors.NotFound) {
+			return usage, errors.Trace(err)
+		}
+		usage = usage.Add(caasUnitResources(cons, modelCons).Times(units))
+	}
+	return usage, nil
+}
+
+// storageUsage returns the storage consumed by the volumes and
+// filesystems in the model.
+func (st *State) storageUsage() (quota.ModelResources, error) {
+	var usage quota.ModelResources
+
+	volumes, closer := st.db().GetCollection(volumesC)
+	defer closer()
+	var volumeDocs []volumeDoc
+	if err := volumes.Find(nil).Select(bson.D{
+		{"info", 1}, {"params", 1},
+	}).All(&volumeDocs); err != nil {
+		return usage, errors.Annotate(err, "cannot get volumes")
+	}
+	for _, doc := range volumeDocs {
+		switch {
+		case doc.Info != nil:
+			usage.Storage += doc.Info.Size
+		case doc.Params != nil:
+			usage.Storage += doc.Params.Size
+		}
+	}
+
+	filesystems, closer := st.db().GetCollection(filesystemsC)
+	defer closer()
+	var filesystemDocs []filesystemDoc
+	if err := filesystems.Find(bson.D{{"volumeid", bson.D{{"$exists", false}}}}).Select(bson.D{
+		{"info", 1}, {"params", 1},
+	}).All(&filesystemDocs); err != nil {
+		return usage, errors.Annotate(err, "cannot get filesystems")
+	}
+	for _, doc := range filesystemDocs {
+		switch {
+		case doc.Info != nil:
+			usage.Storage += doc.Info.Size
+		case doc.Params != nil:
+			usage.Storage += doc.Params.Size
+		}
+	}
+	return usage, nil
+}
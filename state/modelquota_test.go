@@ -0,0 +1,262 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/quota"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ModelQuotasSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ModelQuotasSuite{})
+
+func (s *ModelQuotasSuite) TestModelQuotasNotSet(c *gc.C) {
+	quotas, err := s.State.ModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas.IsZero(), jc.IsTrue)
+}
+
+func (s *ModelQuotasSuite) TestSetModelQuotas(c *gc.C) {
+	err := s.State.SetModelQuotas(quota.ModelResources{Machines: 10, Cores: 40})
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err := s.State.ModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, jc.DeepEquals, quota.ModelResources{Machines: 10, Cores: 40})
+
+	// Setting the quotas again replaces them.
+	err = s.State.SetModelQuotas(quota.ModelResources{Units: 5, Memory: 4096})
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err = s.State.ModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, jc.DeepEquals, quota.ModelResources{Units: 5, Memory: 4096})
+}
+
+func (s *ModelQuotasSuite) TestSetModelQuotasInvalid(c *gc.C) {
+	err := s.State.SetModelQuotas(quota.ModelResources{Machines: -1})
+	c.Assert(err, gc.ErrorMatches, "cannot set model quotas: negative machines quota not valid")
+}
+
+func (s *ModelQuotasSuite) TestModelQuotasPerModel(c *gc.C) {
+	otherSt := s.Factory.MakeModel(c, nil)
+	defer otherSt.Close()
+
+	err := s.State.SetModelQuotas(quota.ModelResources{Machines: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	quotas, err := otherSt.ModelQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas.IsZero(), jc.IsTrue)
+}
+
+func (s *ModelQuotasSuite) TestModelResourceUsage(c *gc.C) {
+	// A provisioned machine counts its hardware.
+	hc := instance.MustParseHardware("cores=4 mem=8G")
+	host := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Characteristics: &hc,
+		Volumes: []state.HostVolumeParams{{
+			Volume: state.VolumeParams{Pool: "loop", Size: 1024},
+		}},
+	})
+	// An unprovisioned machine counts its constraints.
+	_, err := s.State.AddOneMachine(state.MachineTemplate{
+		Base:        state.UbuntuBase("12.10"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("cores=2 mem=2G"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// A container counts only as a machine.
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Base: state.UbuntuBase("12.10"),
+		Jobs: []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeUnit(c, &factory.UnitParams{Machine: host})
+
+	usage, err := s.State.ModelResourceUsage()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, quota.ModelResources{
+		Machines: 3,
+		Units:    1,
+		Cores:    6,
+		Memory:   10240,
+		Storage:  1024,
+	})
+}
+
+func (s *ModelQuotasSuite) TestModelResourceUsageCAAS(c *gc.C) {
+	st := s.Factory.MakeCAASModel(c, nil)
+	defer st.Close()
+	f := factory.NewFactory(st, s.StatePool)
+	ch := f.MakeCharm(c, &factory.CharmParams{Name: "gitlab", Series: "kubernetes"})
+	app := f.MakeApplication(c, &factory.ApplicationParams{
+		Charm:       ch,
+		Constraints: constraints.MustParse("cores=2 mem=1G"),
+	})
+	f.MakeUnit(c, &factory.UnitParams{Application: app})
+
+	// An application scaling up counts the units it is scaling to.
+	err := app.SetScale(3, 0, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := st.ModelResourceUsage()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, quota.ModelResources{
+		Units:  3,
+		Cores:  6,
+		Memory: 3072,
+	})
+}
+
+func (s *ModelQuotasSuite) TestAddMachineQuotaExceeded(c *gc.C) {
+	err := s.State.SetModelQuotas(quota.ModelResources{Machines: 2, Cores: 4})
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Base:        state.UbuntuBase("12.10"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("cores=2"),
+	}
+	_, err = s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddMachines(template, template)
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `cannot add a new machine: model machines quota of 2 exceeded \(1 in use, 2 requested\)`)
+
+	host, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Base: state.UbuntuBase("12.10"),
+		Jobs: []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXD)
+	c.Assert(err, gc.ErrorMatches, `cannot add a new machine: model machines quota of 2 exceeded \(2 in use, 1 requested\)`)
+}
+
+func (s *ModelQuotasSuite) TestAddMachineQuotaConcurrentChange(c *gc.C) {
+	err := s.State.SetModelQuotas(quota.ModelResources{Machines: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Base: state.UbuntuBase("12.10"),
+		Jobs: []state.MachineJob{state.JobHostUnits},
+	}
+
+	// Another machine is added after the first is checked against the
+	// quotas, but before it's committed.
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := s.State.AddOneMachine(template)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = s.State.AddOneMachine(template)
+	c.Assert(err, gc.ErrorMatches, `cannot add a new machine: model machines quota of 1 exceeded \(1 in use, 1 requested\)`)
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+}
+
+func (s *ModelQuotasSuite) TestAssignToNewMachineQuotaExceeded(c *gc.C) {
+	err := s.State.SetModelQuotas(quota.ModelResources{Cores: 4})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Constraints: constraints.MustParse("cores=3"),
+	})
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit .* to new machine: model cores quota of 4 exceeded \(3 in use, 3 requested\)`)
+}
+
+func (s *ModelQuotasSuite) TestAddUnitQuotaExceeded(c *gc.C) {
+	err := s.State.SetModelQuotas(quota.ModelResources{Units: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.Factory.MakeApplication(c, nil)
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to application "mysql": model units quota of 1 exceeded \(1 in use, 1 requested\)`)
+	units, err := app.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
+
+func (s *ModelQuotasSuite) TestAddUnitQuotaConcurrentChange(c *gc.C) {
+	err := s.State.SetModelQuotas(quota.ModelResources{Units: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.Factory.MakeApplication(c, nil)
+	otherApp, err := s.State.Application(app.Name())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Another unit is added after the first is checked against the
+	// quotas, but before it's committed.
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := otherApp.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to application "mysql": model units quota of 1 exceeded \(1 in use, 1 requested\)`)
+	units, err := app.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
+
+func (s *ModelQuotasSuite) TestSetScaleQuotaExceeded(c *gc.C) {
+	st := s.Factory.MakeCAASModel(c, nil)
+	defer st.Close()
+	err := st.SetModelQuotas(quota.ModelResources{Units: 3, Memory: 2048})
+	c.Assert(err, jc.ErrorIsNil)
+	f := factory.NewFactory(st, s.StatePool)
+	ch := f.MakeCharm(c, &factory.CharmParams{Name: "gitlab", Series: "kubernetes"})
+	app := f.MakeApplication(c, &factory.ApplicationParams{
+		Charm:       ch,
+		Constraints: constraints.MustParse("mem=1G"),
+	})
+
+	err = app.SetScale(2, 0, true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.SetScale(3, 0, true)
+	c.Assert(err, gc.ErrorMatches, `cannot set scale for application "gitlab" to 3: model memory quota of 2048MiB exceeded \(2048MiB in use, 1024MiB requested\)`)
+	_, err = app.ChangeScale(1)
+	c.Assert(err, gc.ErrorMatches, `cannot set scale for application "gitlab" to 3: model memory quota of 2048MiB exceeded \(2048MiB in use, 1024MiB requested\)`)
+
+	// Scaling down is not checked.
+	err = app.SetScale(1, 0, true)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ModelQuotasSuite) TestSetConstraintsQuotaExceeded(c *gc.C) {
+	st := s.Factory.MakeCAASModel(c, nil)
+	defer st.Close()
+	err := st.SetModelQuotas(quota.ModelResources{Cores: 4})
+	c.Assert(err, jc.ErrorIsNil)
+	f := factory.NewFactory(st, s.StatePool)
+	ch := f.MakeCharm(c, &factory.CharmParams{Name: "gitlab", Series: "kubernetes"})
+	app := f.MakeApplication(c, &factory.ApplicationParams{
+		Charm:       ch,
+		Constraints: constraints.MustParse("cores=1"),
+	})
+	err = app.SetScale(2, 0, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.SetConstraints(constraints.MustParse("cores=2"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.SetConstraints(constraints.MustParse("cores=3"))
+	c.Assert(err, gc.ErrorMatches, `cannot set constraints: model cores quota of 4 exceeded \(4 in use, 2 requested\)`)
+}
@@ -222,11 +222,7 @@ func (p *modelSummaryProcessor) fillInPermissions(permissionIds []string) error
 			// TODO(jam) 2017-11-27, probably should be treated at least as a logged warning
 			continue
 		}
-		details := &p.summaries[modelIdx]
-		access := permission.Access(doc.Access)
-		if err := access.Validate(); err == nil {
-			details.Access = access
-		}
+		p.fillInAccess(modelIdx, permission.Access(doc.Access))
 	}
 	if err := iter.Close(); err != nil {
 		return errors.Trace(err)
@@ -234,6 +230,16 @@ func (p *modelSummaryProcessor) fillInPermissions(permissionIds []string) error
 	return nil
 }
 
+// fillInAccess records the access on the model at the index, unless
+// greater access has already been recorded.
+func (p *modelSummaryProcessor) fillInAccess(modelIdx int, access permission.Access) {
+	if err := access.Validate(); err != nil {
+		return
+	}
+	details := &p.summaries[modelIdx]
+	details.Access = greaterAccess(names.ModelTagKind, details.Access, access)
+}
+
 func (p *modelSummaryProcessor) fillInMachineSummary() error {
 	machines, closer := p.st.db().GetRawCollection(machinesC)
 	defer closer()
@@ -415,6 +421,18 @@ func (p *modelSummaryProcessor) fillInJustUser() error {
 	if err := p.fillInPermissions(permissionIds); err != nil {
 		return errors.Trace(err)
 	}
+
+	// The user may also have access through the groups they are a
+	// member of.
+	groupPermissions, err := p.st.userGroupPermissionDocs(p.user, modelKey(""))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, doc := range groupPermissions {
+		if modelIdx, ok := p.indexByUUID[strings.TrimPrefix(doc.ObjectGlobalKey, modelKey(""))]; ok {
+			p.fillInAccess(modelIdx, permission.Access(doc.Access))
+		}
+	}
 	return nil
 }
 
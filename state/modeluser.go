@@ -35,7 +35,7 @@ func (st *State) setModelAccess(access permission.Access, userGlobalKey, modelUU
 	if err := permission.ValidateModelAccess(access); err != nil {
 		return errors.Trace(err)
 	}
-	op := updatePermissionOp(modelKey(modelUUID), userGlobalKey, access)
+	op := setModelPermissionOp(modelUUID, userGlobalKey, access, accessToString(access))
 	err := st.db().RunTransactionFor(modelUUID, []txn.Op{op})
 	if err == txn.ErrAborted {
 		return errors.NotFoundf("existing permissions")
@@ -120,7 +120,7 @@ func createModelUserOps(modelUUID string, user, createdBy names.UserTag, display
 	}
 
 	ops := []txn.Op{
-		createPermissionOp(modelKey(modelUUID), userGlobalKey(userAccessID(user)), access),
+		createModelPermissionOp(modelUUID, userGlobalKey(userAccessID(user)), access),
 		{
 			C:      modelUsersC,
 			Id:     userAccessID(user),
@@ -167,7 +167,7 @@ func (st *State) removeModelUser(user names.UserTag) error {
 }
 
 func (st *State) ModelSummariesForUser(user names.UserTag, isSuperuser bool) ([]ModelSummary, error) {
-	modelQuery, closer, err := st.modelQueryForUser(user, isSuperuser)
+	modelQuery, closer, err := st.modelQueryForUser(user, isSuperuser, true)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -205,9 +205,10 @@ func (st *State) ModelSummariesForUser(user names.UserTag, isSuperuser bool) ([]
 	return p.summaries, nil
 }
 
-// modelsForUser gives you the information about all models that a user has access to.
-// This includes the name and UUID, as well as the last time the user connected to that model.
-func (st *State) modelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.Query, SessionCloser, error) {
+// modelQueryForUser returns a query for the models that the user has
+// access to. If withGroups is true, the models that the groups the user
+// is a member of have access to are included.
+func (st *State) modelQueryForUser(user names.UserTag, isSuperuser, withGroups bool) (mongo.Query, SessionCloser, error) {
 	var modelQuery mongo.Query
 	models, closer := st.db().GetCollection(modelsC)
 	if isSuperuser {
@@ -233,6 +234,14 @@ func (st *State) modelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.
 			closer()
 			return nil, nil, errors.Trace(err)
 		}
+		if withGroups {
+			groupModelUUIDs, err := st.userGroupModelUUIDs(user)
+			if err != nil {
+				closer()
+				return nil, nil, errors.Trace(err)
+			}
+			modelUUIDs = append(modelUUIDs, groupModelUUIDs...)
+		}
 		modelQuery = models.Find(bson.M{
 			"_id":            bson.M{"$in": modelUUIDs},
 			"migration-mode": bson.M{"$ne": MigrationModeImporting},
@@ -253,7 +262,7 @@ type ModelAccessInfo struct {
 // ModelBasicInfoForUser gives you the information about all models that a user has access to.
 // This includes the name and UUID, as well as the last time the user connected to that model.
 func (st *State) ModelBasicInfoForUser(user names.UserTag, isSuperuser bool) ([]ModelAccessInfo, error) {
-	modelQuery, closer1, err := st.modelQueryForUser(user, isSuperuser)
+	modelQuery, closer1, err := st.modelQueryForUser(user, isSuperuser, true)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -311,9 +320,9 @@ func (st *State) ModelUUIDsForUser(user names.UserTag) ([]string, error) {
 			return nil, errors.Trace(err)
 		}
 	} else {
-		// Since there are no groups at this stage, the simplest way to get all
-		// the models that a particular user can see is to look through the
-		// model user collection. A raw collection is required to support
+		// The models that a particular user can see are those in which
+		// they are a model user, and those that any of their groups have
+		// been granted access to. A raw collection is required to support
 		// queries across multiple models.
 		modelUsers, userCloser := st.db().GetRawCollection(modelUsersC)
 		defer userCloser()
@@ -326,6 +335,11 @@ func (st *State) ModelUUIDsForUser(user names.UserTag) ([]string, error) {
 		for _, doc := range userSlice {
 			modelUUIDs = append(modelUUIDs, doc.ObjectUUID)
 		}
+		groupModelUUIDs, err := st.userGroupModelUUIDs(user)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		modelUUIDs = append(modelUUIDs, groupModelUUIDs...)
 	}
 
 	modelsColl, close := st.db().GetCollection(modelsC)
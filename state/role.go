@@ -0,0 +1,293 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/mgo/v3"
+	"github.com/juju/mgo/v3/bson"
+	"github.com/juju/mgo/v3/txn"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/permission"
+)
+
+// roleDoc represents a custom, controller-wide, role. The built-in
+// roles, equivalent to the model access levels, are not stored.
+type roleDoc struct {
+	Name         string    `bson:"_id"`
+	Access       string    `bson:"access"`
+	Capabilities []string  `bson:"capabilities"`
+	CreatedBy    string    `bson:"createdby"`
+	DateCreated  time.Time `bson:"datecreated"`
+}
+
+// modelUserRoleAnnotationPrefix prefixes the model annotations, keyed
+// by user name, which carry the custom roles granted to model users
+// when the model is migrated. The migration format has no place for
+// them on the users themselves.
+const modelUserRoleAnnotationPrefix = "juju-model-user-role/"
+
+// Role represents a named set of capabilities that may be granted to
+// users on models.
+type Role struct {
+	doc     roleDoc
+	builtin bool
+}
+
+func newBuiltinRole(role permission.Role) *Role {
+	capabilities := make([]string, len(role.Capabilities))
+	for i, capability := range role.Capabilities {
+		capabilities[i] = string(capability)
+	}
+	return &Role{
+		doc: roleDoc{
+			Name:         role.Name,
+			Access:       string(role.Access),
+			Capabilities: capabilities,
+		},
+		builtin: true,
+	}
+}
+
+// Name returns the name of the role.
+func (r *Role) Name() string {
+	return r.doc.Name
+}
+
+// Access returns the model access level that users granted the role
+// are given.
+func (r *Role) Access() permission.Access {
+	return stringToAccess(r.doc.Access)
+}
+
+// Capabilities returns the API methods that the role allows.
+func (r *Role) Capabilities() []permission.Capability {
+	capabilities := make([]permission.Capability, len(r.doc.Capabilities))
+	for i, capability := range r.doc.Capabilities {
+		capabilities[i] = permission.Capability(capability)
+	}
+	return capabilities
+}
+
+// BuiltIn returns whether the role is one of the built-in roles
+// equivalent to the model access levels.
+func (r *Role) BuiltIn() bool {
+	return r.builtin
+}
+
+// CreatedBy returns the name of the user that created the role. It is
+// empty for built-in roles.
+func (r *Role) CreatedBy() string {
+	return r.doc.CreatedBy
+}
+
+// DateCreated returns when the role was created, in UTC. It is zero for
+// built-in roles.
+func (r *Role) DateCreated() time.Time {
+	return r.doc.DateCreated.UTC()
+}
+
+// PermissionRole returns the role as a permission.Role.
+func (r *Role) PermissionRole() permission.Role {
+	return permission.Role{
+		Name:         r.doc.Name,
+		Access:       r.Access(),
+		Capabilities: r.Capabilities(),
+	}
+}
+
+// AddRole adds a new custom role to the controller.
+func (st *State) AddRole(role permission.Role, creator string) (*Role, error) {
+	if err := role.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, ok := permission.BuiltinRole(role.Name); ok {
+		return nil, errors.AlreadyExistsf("built-in role %q", role.Name)
+	}
+	doc := roleDoc{
+		Name:         role.Name,
+		Access:       accessToString(role.Access),
+		Capabilities: make([]string, len(role.Capabilities)),
+		CreatedBy:    creator,
+		DateCreated:  st.nowToTheSecond(),
+	}
+	for i, capability := range role.Capabilities {
+		doc.Capabilities[i] = string(capability)
+	}
+	ops := []txn.Op{{
+		C:      rolesC,
+		Id:     doc.Name,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("role %q", role.Name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot add role %q", role.Name)
+	}
+	return &Role{doc: doc}, nil
+}
+
+// Role returns the named role, which may be built in.
+func (st *State) Role(name string) (*Role, error) {
+	if role, ok := permission.BuiltinRole(name); ok {
+		return newBuiltinRole(role), nil
+	}
+	roles, closer := st.db().GetCollection(rolesC)
+	defer closer()
+
+	var doc roleDoc
+	err := roles.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("role %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get role %q", name)
+	}
+	return &Role{doc: doc}, nil
+}
+
+// AllRoles returns the built-in roles followed by the custom roles,
+// sorted by name.
+func (st *State) AllRoles() ([]*Role, error) {
+	roles, closer := st.db().GetCollection(rolesC)
+	defer closer()
+
+	var docs []roleDoc
+	if err := roles.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get roles")
+	}
+	var result []*Role
+	for _, role := range permission.BuiltinRoles() {
+		result = append(result, newBuiltinRole(role))
+	}
+	for _, doc := range docs {
+		result = append(result, &Role{doc: doc})
+	}
+	return result, nil
+}
+
+// RemoveRole removes the named custom role. A role cannot be removed
+// while it is granted to any user.
+func (st *State) RemoveRole(name string) error {
+	if _, ok := permission.BuiltinRole(name); ok {
+		return errors.NotSupportedf("removing built-in role %q", name)
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.Role(name); err != nil {
+			return nil, errors.Trace(err)
+		}
+		permissions, closer := st.db().GetCollection(permissionsC)
+		defer closer()
+
+		granted, err := permissions.Find(bson.D{{"role", name}}).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if granted > 0 {
+			return nil, errors.Errorf("role %q is still granted to %d user(s)", name, granted)
+		}
+		return []txn.Op{{
+			C:      rolesC,
+			Id:     name,
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// SetModelUserRole grants the named role to a user of the state's
+// model, replacing the role and access level they had. The user must
+// already have access to the model.
+func (st *State) SetModelUserRole(user names.UserTag, name string) error {
+	role, err := st.Role(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	modelUUID := st.ModelUUID()
+	subjectKey := userGlobalKey(userAccessID(user))
+	ops := []txn.Op{setModelPermissionOp(modelUUID, subjectKey, role.Access(), role.Name())}
+	if !role.BuiltIn() {
+		ops = append(ops, txn.Op{
+			C:      rolesC,
+			Id:     role.Name(),
+			Assert: txn.DocExists,
+		})
+	}
+	err = st.db().RunTransactionFor(modelUUID, ops)
+	if err == txn.ErrAborted {
+		if _, err := st.Role(name); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.NotFoundf("model user %q", user.Id())
+	}
+	return errors.Annotatef(err, "cannot grant role %q to %q", name, user.Id())
+}
+
+// ModelUserRole returns the name of the role that the user has been
+// granted on the state's model.
+func (st *State) ModelUserRole(user names.UserTag) (string, error) {
+	perm, err := st.userPermission(modelKey(st.ModelUUID()), userGlobalKey(userAccessID(user)))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return perm.role(), nil
+}
+
+// ModelUserRoles returns the roles that restrict the API methods the
+// user may call on the state's model: the role granted to the user, if
+// any. A user may call any API method allowed by at least one of the
+// roles.
+//
+// Access granted to the groups the user is a member of may raise the
+// user's access level, which the facades check as usual, but doesn't
+// lift the restrictions of the user's own role; the groups' built-in
+// roles would otherwise allow every method.
+func (st *State) ModelUserRoles(user names.UserTag) ([]permission.Role, error) {
+	roleName, err := st.ModelUserRole(user)
+	if errors.Is(err, errors.NotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	role, err := st.Role(roleName)
+	if errors.Is(err, errors.NotFound) {
+		// The role was removed while it was granted. Without
+		// capabilities it allows nothing.
+		return []permission.Role{{Name: roleName}}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []permission.Role{role.PermissionRole()}, nil
+}
+
+// createModelPermissionOp returns the operation that gives a user
+// access to a model, with the built-in role of the same access level.
+func createModelPermissionOp(modelUUID, subjectGlobalKey string, access permission.Access) txn.Op {
+	doc := makePermissionDoc(modelKey(modelUUID), subjectGlobalKey, access)
+	doc.Role = accessToString(access)
+	return txn.Op{
+		C:      permissionsC,
+		Id:     doc.ID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}
+}
+
+// setModelPermissionOp returns the operation that changes the access
+// level and role of a user on a model.
+func setModelPermissionOp(modelUUID, subjectGlobalKey string, access permission.Access, role string) txn.Op {
+	return txn.Op{
+		C:      permissionsC,
+		Id:     permissionID(modelKey(modelUUID), subjectGlobalKey),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"access", accessToString(access)},
+			{"role", role},
+		}}},
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/testing/factory"
+)
+
+type RoleSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&RoleSuite{})
+
+var operatorRole = permission.Role{
+	Name:         "operator",
+	Access:       permission.WriteAccess,
+	Capabilities: []permission.Capability{"Action.*", "Client.FullStatus"},
+}
+
+func (s *RoleSuite) TestAddRole(c *gc.C) {
+	role, err := s.State.AddRole(operatorRole, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.Name(), gc.Equals, "operator")
+	c.Assert(role.Access(), gc.Equals, permission.WriteAccess)
+	c.Assert(role.BuiltIn(), jc.IsFalse)
+	c.Assert(role.CreatedBy(), gc.Equals, "admin")
+	c.Assert(role.PermissionRole(), jc.DeepEquals, operatorRole)
+
+	role, err = s.State.Role("operator")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.Capabilities(), jc.DeepEquals, operatorRole.Capabilities)
+
+	_, err = s.State.AddRole(operatorRole, "admin")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *RoleSuite) TestAddRoleBuiltin(c *gc.C) {
+	_, err := s.State.AddRole(permission.Role{
+		Name:         "write",
+		Access:       permission.WriteAccess,
+		Capabilities: []permission.Capability{"*.*"},
+	}, "admin")
+	c.Assert(err, gc.ErrorMatches, `built-in role "write" already exists`)
+}
+
+func (s *RoleSuite) TestAddRoleInvalid(c *gc.C) {
+	role := operatorRole
+	role.Capabilities = []permission.Capability{"Action"}
+	_, err := s.State.AddRole(role, "admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *RoleSuite) TestBuiltinRole(c *gc.C) {
+	role, err := s.State.Role("read")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.BuiltIn(), jc.IsTrue)
+	c.Assert(role.Access(), gc.Equals, permission.ReadAccess)
+	c.Assert(role.Capabilities(), jc.DeepEquals, []permission.Capability{permission.AllCapabilities})
+
+	_, err = s.State.Role("operator")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RoleSuite) TestAllRoles(c *gc.C) {
+	_, err := s.State.AddRole(operatorRole, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	auditor := permission.Role{
+		Name:         "auditor",
+		Access:       permission.ReadAccess,
+		Capabilities: []permission.Capability{"Client.FullStatus"},
+	}
+	_, err = s.State.AddRole(auditor, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	roles, err := s.State.AllRoles()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, role := range roles {
+		names = append(names, role.Name())
+	}
+	c.Assert(names, jc.DeepEquals, []string{"read", "write", "admin", "auditor", "operator"})
+}
+
+func (s *RoleSuite) TestRemoveRole(c *gc.C) {
+	_, err := s.State.AddRole(operatorRole, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Access: permission.WriteAccess}).UserTag()
+	err = s.State.SetModelUserRole(bob, "operator")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveRole("operator")
+	c.Assert(err, gc.ErrorMatches, `role "operator" is still granted to 1 user\(s\)`)
+
+	err = s.State.SetModelUserRole(bob, "read")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveRole("operator")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Role("operator")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveRole("operator")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RoleSuite) TestRemoveBuiltinRole(c *gc.C) {
+	err := s.State.RemoveRole("admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *RoleSuite) TestSetModelUserRole(c *gc.C) {
+	_, err := s.State.AddRole(operatorRole, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Access: permission.ReadAccess}).UserTag()
+
+	role, err := s.State.ModelUserRole(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role, gc.Equals, "read")
+
+	err = s.State.SetModelUserRole(bob, "operator")
+	c.Assert(err, jc.ErrorIsNil)
+	role, err = s.State.ModelUserRole(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role, gc.Equals, "operator")
+
+	// The user is given the access level of the role.
+	access, err := s.State.UserPermission(bob, s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	// Changing the access level resets the role.
+	_, err = s.State.SetUserAccess(bob, s.Model.ModelTag(), permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	role, err = s.State.ModelUserRole(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role, gc.Equals, "admin")
+}
+
+func (s *RoleSuite) TestSetModelUserRoleNotFound(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true}).UserTag()
+	err := s.State.SetModelUserRole(bob, "operator")
+	c.Assert(err, gc.ErrorMatches, `role "operator" not found`)
+
+	err = s.State.SetModelUserRole(bob, "read")
+	c.Assert(err, gc.ErrorMatches, `model user "bob" not found`)
+}
+
+func (s *RoleSuite) TestModelUserRoles(c *gc.C) {
+	_, err := s.State.AddRole(operatorRole, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Access: permission.WriteAccess}).UserTag()
+	err = s.State.SetModelUserRole(bob, "operator")
+	c.Assert(err, jc.ErrorIsNil)
+
+	group, err := s.State.AddUserGroup("auditors", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("auditors", s.Model.ModelTag(), permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The group's built-in role doesn't lift the restrictions of the
+	// user's own role.
+	roles, err := s.State.ModelUserRoles(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(roles, jc.DeepEquals, []permission.Role{operatorRole})
+
+	roles, err = s.State.ModelUserRoles(names.NewUserTag("mary"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(roles, gc.HasLen, 0)
+}
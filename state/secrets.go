@@ -48,6 +48,10 @@ type UpdateSecretParams struct {
 	Data           secrets.SecretData
 	ValueRef       *secrets.ValueRef
 	AutoPrune      *bool
+
+	// RollbackTo, if set, records that the new revision content
+	// is a copy of the content of the specified older revision.
+	RollbackTo *int
 }
 
 func (u *UpdateSecretParams) hasUpdate() bool {
@@ -143,6 +147,10 @@ type secretRevisionDoc struct {
 	Data       secretsDataMap `bson:"data"`
 	ValueRef   *valueRefDoc   `bson:"value-reference,omitempty"`
 
+	// RollbackTo is the older revision whose content was copied
+	// to create this revision, if it was created by a rollback.
+	RollbackTo int `bson:"rollback-to,omitempty"`
+
 	// PendingDelete is true if the revision is to be deleted.
 	// It will not be drained to a new active backend.
 	PendingDelete bool `bson:"pending-delete"`
@@ -434,6 +442,17 @@ func (s *secretsStore) UpdateSecret(uri *secrets.URI, p UpdateSecretParams) (*se
 				return nil, errors.AlreadyExistsf("secret value with revision %d for %q", metadataDoc.LatestRevision, uri.String())
 			}
 			revisionDoc := s.secretRevisionDoc(uri, metadataDoc.OwnerTag, metadataDoc.LatestRevision, newExpireTime, p.Data, p.ValueRef)
+			if p.RollbackTo != nil {
+				if *p.RollbackTo < 1 || *p.RollbackTo >= metadataDoc.LatestRevision {
+					return nil, errors.NotValidf("rollback to revision %d for %q", *p.RollbackTo, uri.String())
+				}
+				revisionDoc.RollbackTo = *p.RollbackTo
+				ops = append(ops, txn.Op{
+					C:      secretRevisionsC,
+					Id:     secretRevisionKey(uri, *p.RollbackTo),
+					Assert: txn.DocExists,
+				})
+			}
 			ops = append(ops, txn.Op{
 				C:      secretRevisionsC,
 				Id:     revisionDoc.DocID,
@@ -474,6 +493,8 @@ func (s *secretsStore) UpdateSecret(uri *secrets.URI, p UpdateSecretParams) (*se
 			}
 			ops = append(ops, obsoleteOps...)
 
+		} else if p.RollbackTo != nil {
+			return nil, errors.NotValidf("rollback without secret content")
 		} else if haveExpireTime {
 			if !revisionExists {
 				return nil, errors.NotFoundf("reversion %d for secret %q", metadataDoc.LatestRevision, uri.String())
@@ -1162,6 +1183,7 @@ func (s *secretsStore) listSecretRevisions(uri *secrets.URI, revision *int) ([]*
 			CreateTime:  doc.CreateTime,
 			UpdateTime:  doc.UpdateTime,
 			ExpireTime:  doc.ExpireTime,
+			RollbackTo:  doc.RollbackTo,
 		}
 	}
 	return result, nil
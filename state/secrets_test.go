@@ -875,6 +875,69 @@ func (s *SecretsSuite) TestUpdateDataSetsLatestConsumerRevision(c *gc.C) {
 	})
 }
 
+func (s *SecretsSuite) TestUpdateRollback(c *gc.C) {
+	uri := secrets.NewURI()
+	cp := state.CreateSecretParams{
+		Version: 1,
+		Owner:   s.owner.Tag(),
+		UpdateSecretParams: state.UpdateSecretParams{
+			LeaderToken: &fakeToken{},
+			Data:        map[string]string{"foo": "bar"},
+		},
+	}
+	_, err := s.store.CreateSecret(uri, cp)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.UpdateSecret(uri, state.UpdateSecretParams{
+		LeaderToken: &fakeToken{},
+		Data:        map[string]string{"foo": "baz"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	md, err := s.store.UpdateSecret(uri, state.UpdateSecretParams{
+		LeaderToken: &fakeToken{},
+		Data:        map[string]string{"foo": "bar"},
+		RollbackTo:  ptr(1),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(md.LatestRevision, gc.Equals, 3)
+
+	val, _, err := s.store.GetSecretValue(uri, 3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(val.EncodedValues(), jc.DeepEquals, map[string]string{"foo": "bar"})
+	revs, err := s.store.ListSecretRevisions(uri)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revs, gc.HasLen, 3)
+	c.Assert(revs[1].RollbackTo, gc.Equals, 0)
+	c.Assert(revs[2].RollbackTo, gc.Equals, 1)
+}
+
+func (s *SecretsSuite) TestUpdateRollbackInvalid(c *gc.C) {
+	uri := secrets.NewURI()
+	cp := state.CreateSecretParams{
+		Version: 1,
+		Owner:   s.owner.Tag(),
+		UpdateSecretParams: state.UpdateSecretParams{
+			LeaderToken: &fakeToken{},
+			Data:        map[string]string{"foo": "bar"},
+		},
+	}
+	_, err := s.store.CreateSecret(uri, cp)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.store.UpdateSecret(uri, state.UpdateSecretParams{
+		LeaderToken: &fakeToken{},
+		Data:        map[string]string{"foo": "bar"},
+		RollbackTo:  ptr(2),
+	})
+	c.Assert(err, jc.ErrorIs, errors.NotValid)
+	_, err = s.store.UpdateSecret(uri, state.UpdateSecretParams{
+		LeaderToken: &fakeToken{},
+		Description: ptr("rollback"),
+		RollbackTo:  ptr(1),
+	})
+	c.Assert(err, gc.ErrorMatches, `rollback without secret content not valid`)
+}
+
 func (s *SecretsSuite) TestUpdateOwnerLabel(c *gc.C) {
 	uri := secrets.NewURI()
 	now := s.Clock.Now().Round(time.Second).UTC()
@@ -4,6 +4,7 @@
 package state
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"sort"
@@ -523,6 +524,20 @@ func (st *State) newDB() (Database, func()) {
 	return st.database.Copy()
 }
 
+// WithContext returns a view of the State whose transactions are
+// recorded as spans under the span in ctx, such as that of the API
+// request being served. The view shares the State's session and
+// workers, so it must not be closed.
+func (st *State) WithContext(ctx context.Context) *State {
+	db, ok := st.database.(*database)
+	if !ok {
+		return st
+	}
+	view := *st
+	view.database = db.withContext(ctx)
+	return &view
+}
+
 // db returns the Database instance used by the State. It is part of
 // the modelBackend interface.
 func (st *State) db() Database {
@@ -1436,7 +1451,15 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 				ops = append(ops, assignUnitOps(unitName, placement)...)
 			}
 		}
-		return ops, nil
+		requested, err := st.unitQuotaResources(model.Type(), subordinate, args.Constraints, args.NumUnits)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		quotaOps, err := st.modelQuotaOps(requested)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, quotaOps...), nil
 	}
 	// At the last moment before inserting the application, prime status history.
 	_, _ = probablyUpdateStatusHistory(st.db(), app.globalKey(), statusDoc)
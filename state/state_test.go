@@ -4,6 +4,7 @@
 package state_test
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"sort"
@@ -42,6 +43,7 @@ import (
 	coreos "github.com/juju/juju/core/os"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/mongo/mongotest"
@@ -5224,3 +5226,62 @@ func (s *StateSuite) TestPeerRelationCreatesApplicationSettings(c *gc.C) {
 	_, err = settings.ReadSettings(key)
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *StateSuite) TestWithContextTracesTransactions(c *gc.C) {
+	tracer := &recordingTracer{}
+	ctx, span := tracer.Start(trace.WithTracer(context.Background(), tracer), "request")
+
+	err := s.State.WithContext(ctx).SetModelConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	span.End()
+	c.Assert(tracer.spans, jc.DeepEquals, []recordedSpan{
+		{name: "request"},
+		{name: "state.RunTransaction", parent: "request"},
+	})
+
+	// Transactions run without a span aren't recorded.
+	err = s.State.WithContext(trace.WithTracer(context.Background(), tracer)).SetModelConstraints(constraints.MustParse("mem=8G"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetModelConstraints(constraints.MustParse("mem=16G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tracer.spans, gc.HasLen, 2)
+}
+
+type recordedSpan struct {
+	name   string
+	parent string
+}
+
+// recordingTracer records the names of the spans it starts, and the
+// names of their parents.
+type recordingTracer struct {
+	spans []recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, _ ...trace.Option) (context.Context, trace.Span) {
+	span := &namedSpan{name: name}
+	span.scope.TraceID[0] = 1
+	span.scope.SpanID[0] = byte(len(t.spans) + 1)
+	span.scope.Sampled = true
+	parent, _ := trace.SpanFromContext(ctx).(*namedSpan)
+	recorded := recordedSpan{name: name}
+	if parent != nil {
+		recorded.parent = parent.name
+	}
+	t.spans = append(t.spans, recorded)
+	return trace.WithSpan(ctx, span), span
+}
+
+func (t *recordingTracer) Enabled() bool {
+	return true
+}
+
+type namedSpan struct {
+	trace.NoopSpan
+	name  string
+	scope trace.Scope
+}
+
+func (s *namedSpan) Scope() trace.Scope {
+	return s.scope
+}
@@ -73,6 +73,10 @@ type StorageAttachment interface {
 
 	// Life reports whether the storage attachment is Alive, Dying or Dead.
 	Life() Life
+
+	// Resized reports whether the storage instance has been resized
+	// since the unit last ran its storage-resized hook.
+	Resized() bool
 }
 
 // StorageKind defines the type of a store: whether it is a block device
@@ -225,6 +229,10 @@ type storageInstanceDoc struct {
 type storageInstanceConstraints struct {
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// Snapshot, if non-empty, is the ID of the volume snapshot
+	// from which the storage instance's volume will be created.
+	Snapshot string `bson:"snapshot,omitempty"`
 }
 
 type storageAttachment struct {
@@ -243,6 +251,10 @@ func (s *storageAttachment) Life() Life {
 	return s.doc.Life
 }
 
+func (s *storageAttachment) Resized() bool {
+	return s.doc.Resized
+}
+
 // storageAttachmentDoc describes a unit's attachment to a charm storage
 // instance.
 type storageAttachmentDoc struct {
@@ -252,6 +264,10 @@ type storageAttachmentDoc struct {
 	Unit            string `bson:"unitid"`
 	StorageInstance string `bson:"storageid"`
 	Life            Life   `bson:"life"`
+
+	// Resized is set when the storage instance has been resized,
+	// and cleared once the unit has run its storage-resized hook.
+	Resized bool `bson:"resized,omitempty"`
 }
 
 // newStorageInstanceId returns a unique storage instance name. The name
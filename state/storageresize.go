@@ -0,0 +1,216 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/mgo/v3/bson"
+	"github.com/juju/mgo/v3/txn"
+	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn/v3"
+)
+
+// ResizeStorageInstance records a request to grow the volume assigned to
+// the specified storage instance to the given size, in MiB. For filesystem
+// storage, the volume backing the filesystem is resized; filesystems that
+// are not backed by volumes cannot be resized.
+//
+// The volume is resized asynchronously by the storage provisioner, which
+// records completion with FinishVolumeResize. Requesting a resize while
+// another is in progress replaces the pending request, which allows a
+// failed resize to be retried.
+func (sb *storageBackend) ResizeStorageInstance(tag names.StorageTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize storage %s", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := sb.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		v, err := sb.storageInstanceVolume(tag)
+		if errors.IsNotFound(err) {
+			return nil, errors.NotSupportedf("resizing storage not backed by a volume")
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errors.Errorf("%s is not alive", names.ReadableString(v.VolumeTag()))
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Annotatef(err, "%s", names.ReadableString(v.VolumeTag()))
+		}
+		if size <= info.Size {
+			return nil, errors.NotValidf(
+				"new size %dMiB for %s of size %dMiB; volumes can only be grown",
+				size, names.ReadableString(v.VolumeTag()), info.Size,
+			)
+		}
+		if pending, ok := v.PendingSize(); ok && pending == size {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{
+			{
+				C:      storageInstancesC,
+				Id:     s.doc.Id,
+				Assert: isAliveDoc,
+			},
+			{
+				C:  volumesC,
+				Id: v.doc.Name,
+				Assert: append(isAliveDoc,
+					bson.DocElem{"info.size", info.Size},
+					resizeToAssert(v.doc.ResizeTo),
+				),
+				Update: bson.D{{"$set", bson.D{{"resizeto", size}}}},
+			},
+		}, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// FinishVolumeResize records that the specified volume has been resized
+// by the storage provider to the given size, in MiB.
+//
+// If the volume backs a filesystem, the filesystem is marked to be grown
+// by the machine storage provisioner. Otherwise, the units the volume's
+// storage instance is attached to are notified that it has been resized.
+func (sb *storageBackend) FinishVolumeResize(tag names.VolumeTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot finish resizing volume %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := getVolumeByTag(sb.mb, tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		pending, ok := v.PendingSize()
+		if !ok {
+			return nil, errors.New("volume is not being resized")
+		}
+		if size < pending {
+			return nil, errors.Errorf("resized to %dMiB, expected at least %dMiB", size, pending)
+		}
+		ops := []txn.Op{{
+			C:      volumesC,
+			Id:     v.doc.Name,
+			Assert: bson.D{{"resizeto", pending}, {"info", bson.D{{"$exists", true}}}},
+			Update: bson.D{
+				{"$set", bson.D{{"info.size", size}}},
+				{"$unset", bson.D{{"resizeto", nil}}},
+			},
+		}}
+		if v.doc.StorageId == "" {
+			return ops, nil
+		}
+		f, err := sb.volumeFilesystem(tag)
+		if err == nil {
+			if f.Life() != Alive {
+				return ops, nil
+			}
+			return append(ops, txn.Op{
+				C:      filesystemsC,
+				Id:     f.doc.FilesystemId,
+				Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", true}}}),
+				Update: bson.D{{"$set", bson.D{{"resizeto", size}}}},
+			}), nil
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		resizedOps, err := sb.setStorageResizedOps(names.NewStorageTag(v.doc.StorageId))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, resizedOps...), nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// FinishFilesystemResize records that the specified filesystem has been
+// grown to the given size, in MiB, and notifies the units its storage
+// instance is attached to that it has been resized.
+func (sb *storageBackend) FinishFilesystemResize(tag names.FilesystemTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot finish resizing filesystem %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		f, err := getFilesystemByTag(sb.mb, tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		pending, ok := f.PendingSize()
+		if !ok {
+			return nil, errors.New("filesystem is not being resized")
+		}
+		ops := []txn.Op{{
+			C:      filesystemsC,
+			Id:     f.doc.FilesystemId,
+			Assert: bson.D{{"resizeto", pending}, {"info", bson.D{{"$exists", true}}}},
+			Update: bson.D{
+				{"$set", bson.D{{"info.size", size}}},
+				{"$unset", bson.D{{"resizeto", nil}}},
+			},
+		}}
+		if f.doc.StorageId == "" {
+			return ops, nil
+		}
+		resizedOps, err := sb.setStorageResizedOps(names.NewStorageTag(f.doc.StorageId))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, resizedOps...), nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// resizeToAssert returns an assertion that a volume or filesystem
+// document's pending size is unchanged.
+func resizeToAssert(resizeTo uint64) bson.DocElem {
+	if resizeTo == 0 {
+		return bson.DocElem{"resizeto", bson.D{{"$exists", false}}}
+	}
+	return bson.DocElem{"resizeto", resizeTo}
+}
+
+// setStorageResizedOps returns the txn.Ops that flag each alive
+// attachment of the specified storage instance as resized.
+func (sb *storageBackend) setStorageResizedOps(tag names.StorageTag) ([]txn.Op, error) {
+	attachments, err := sb.StorageAttachments(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var ops []txn.Op
+	for _, a := range attachments {
+		if a.Life() != Alive {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      storageAttachmentsC,
+			Id:     storageAttachmentId(a.Unit().Id(), tag.Id()),
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"resized", true}}}},
+		})
+	}
+	return ops, nil
+}
+
+// ClearStorageAttachmentResized clears the resized flag of the specified
+// storage attachment, once the unit has run its storage-resized hook.
+func (sb *storageBackend) ClearStorageAttachmentResized(storage names.StorageTag, unit names.UnitTag) (err error) {
+	defer errors.DeferredAnnotatef(&err,
+		"cannot clear resized flag of %s for %s",
+		names.ReadableString(storage),
+		names.ReadableString(unit),
+	)
+	ops := []txn.Op{{
+		C:      storageAttachmentsC,
+		Id:     storageAttachmentId(unit.Id(), storage.Id()),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{{"resized", nil}}}},
+	}}
+	if err := sb.mb.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("storage attachment %s:%s", storage.Id(), unit.Id())
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
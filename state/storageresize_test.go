@@ -0,0 +1,135 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type StorageResizeSuite struct {
+	FilesystemStateSuite
+}
+
+var _ = gc.Suite(&StorageResizeSuite{})
+
+func (s *StorageResizeSuite) TestResizeBlockStorage(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	s.provisionStorageVolume(c, u, storageTag)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err := s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	volume = s.volume(c, volume.VolumeTag())
+	size, ok := volume.PendingSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	err = s.storageBackend.FinishVolumeResize(volume.VolumeTag(), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	volume = s.volume(c, volume.VolumeTag())
+	_, ok = volume.PendingSize()
+	c.Assert(ok, jc.IsFalse)
+	info, err := volume.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size, gc.Equals, uint64(2048))
+
+	sa, err := s.storageBackend.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sa.Resized(), jc.IsTrue)
+
+	err = s.storageBackend.ClearStorageAttachmentResized(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	sa, err = s.storageBackend.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sa.Resized(), jc.IsFalse)
+}
+
+func (s *StorageResizeSuite) TestResizeFilesystemStorage(c *gc.C) {
+	filesystem, _, storageAttachment := s.addUnitWithFilesystem(c, "modelscoped-block", true)
+	storageTag := storageAttachment.StorageInstance()
+	volume := s.filesystemVolume(c, filesystem.FilesystemTag())
+
+	err := s.storageBackend.ResizeStorageInstance(storageTag, 4096)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.FinishVolumeResize(volume.VolumeTag(), 4096)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The filesystem must be grown before the unit is notified.
+	filesystem = s.filesystem(c, filesystem.FilesystemTag())
+	size, ok := filesystem.PendingSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(4096))
+	sa, err := s.storageBackend.StorageAttachment(storageTag, storageAttachment.Unit())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sa.Resized(), jc.IsFalse)
+
+	err = s.storageBackend.FinishFilesystemResize(filesystem.FilesystemTag(), 4000)
+	c.Assert(err, jc.ErrorIsNil)
+	filesystem = s.filesystem(c, filesystem.FilesystemTag())
+	_, ok = filesystem.PendingSize()
+	c.Assert(ok, jc.IsFalse)
+	info, err := filesystem.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size, gc.Equals, uint64(4000))
+	sa, err = s.storageBackend.StorageAttachment(storageTag, storageAttachment.Unit())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sa.Resized(), jc.IsTrue)
+}
+
+func (s *StorageResizeSuite) TestResizeStorageShrink(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	s.provisionStorageVolume(c, u, storageTag)
+	err := s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	volume := s.storageInstanceVolume(c, storageTag)
+	err = s.storageBackend.FinishVolumeResize(volume.VolumeTag(), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 1024)
+	c.Assert(err, gc.ErrorMatches, `cannot resize storage data/0: new size 1024MiB for volume 0/0 of size 2048MiB; volumes can only be grown not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *StorageResizeSuite) TestResizeStorageReplacesPending(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	s.provisionStorageVolume(c, u, storageTag)
+	err := s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 3072)
+	c.Assert(err, jc.ErrorIsNil)
+
+	volume := s.storageInstanceVolume(c, storageTag)
+	size, ok := volume.PendingSize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(3072))
+
+	err = s.storageBackend.FinishVolumeResize(volume.VolumeTag(), 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot finish resizing volume "0/0": resized to 2048MiB, expected at least 3072MiB`)
+}
+
+func (s *StorageResizeSuite) TestResizeStorageUnprovisioned(c *gc.C) {
+	_, _, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize storage data/0: volume 0/0: volume "0/0" not provisioned`)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *StorageResizeSuite) TestFinishVolumeResizeNotPending(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	s.provisionStorageVolume(c, u, storageTag)
+	volume := s.storageInstanceVolume(c, storageTag)
+	err := s.storageBackend.FinishVolumeResize(volume.VolumeTag(), 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot finish resizing volume "0/0": volume is not being resized`)
+}
+
+func (s *StorageResizeSuite) TestClearStorageAttachmentResizedNotFound(c *gc.C) {
+	_, u, _ := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.storageBackend.ClearStorageAttachmentResized(names.NewStorageTag("data/1"), u.UnitTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/core/status"
 	mgoutils "github.com/juju/juju/mongo/utils"
 	stateerrors "github.com/juju/juju/state/errors"
@@ -1980,13 +1981,17 @@ func (u *Unit) assignToNewMachineOps(
 	template.Dirty = true
 
 	var (
-		mdoc *machineDoc
-		ops  []txn.Op
-		err  error
+		mdoc      *machineDoc
+		ops       []txn.Op
+		requested quota.ModelResources
+		err       error
 	)
 	switch {
 	case parentId == "" && containerType == "":
 		mdoc, ops, err = u.st.addMachineOps(template)
+		if err == nil {
+			requested, err = u.st.machineQuotaResources(template)
+		}
 	case parentId == "":
 		if containerType == "" {
 			return nil, nil, errors.New("assignToNewMachine called without container type (should never happen)")
@@ -1996,12 +2001,22 @@ func (u *Unit) assignToNewMachineOps(
 		parentParams := template
 		parentParams.Jobs = []MachineJob{JobHostUnits}
 		mdoc, ops, err = u.st.addMachineInsideNewMachineOps(template, parentParams, containerType)
+		if err == nil {
+			requested, err = u.st.machineQuotaResources(parentParams)
+			requested = requested.Add(containerQuotaResources(template))
+		}
 	default:
 		mdoc, ops, err = u.st.addMachineInsideMachineOps(template, parentId, containerType)
+		requested = containerQuotaResources(template)
 	}
 	if err != nil {
 		return nil, nil, err
 	}
+	quotaOps, err := u.st.modelQuotaOps(requested)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	ops = append(ops, quotaOps...)
 
 	// Ensure the host machine is really clean.
 	if parentId != "" {
@@ -2364,9 +2379,10 @@ func storageParamsForStorageInstance(
 			}
 		} else if errors.IsNotFound(err) {
 			filesystemParams := FilesystemParams{
-				storage: storage.StorageTag(),
-				Pool:    storage.doc.Constraints.Pool,
-				Size:    storage.doc.Constraints.Size,
+				storage:  storage.StorageTag(),
+				snapshot: storage.doc.Constraints.Snapshot,
+				Pool:     storage.doc.Constraints.Pool,
+				Size:     storage.doc.Constraints.Size,
 			}
 			filesystems = append(filesystems, HostFilesystemParams{
 				filesystemParams, filesystemAttachmentParams,
@@ -2409,9 +2425,10 @@ func storageParamsForStorageInstance(
 			volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
 		} else if errors.IsNotFound(err) {
 			volumeParams := VolumeParams{
-				storage: storage.StorageTag(),
-				Pool:    storage.doc.Constraints.Pool,
-				Size:    storage.doc.Constraints.Size,
+				storage:  storage.StorageTag(),
+				Pool:     storage.doc.Constraints.Pool,
+				Size:     storage.doc.Constraints.Size,
+				Snapshot: storage.doc.Constraints.Snapshot,
 			}
 			volumes = append(volumes, HostVolumeParams{
 				volumeParams, volumeAttachmentParams,
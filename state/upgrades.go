@@ -94,3 +94,39 @@ func applyToAllModelSettings(st *State, change func(*settingsDoc) (bool, error))
 	}
 	return nil
 }
+
+// AssignBuiltinModelRoles records, on the model permissions of users
+// that predate roles, the built-in role matching their access level.
+func AssignBuiltinModelRoles(pool *StatePool) error {
+	st, err := pool.SystemState()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	coll, closer := st.db().GetRawCollection(permissionsC)
+	defer closer()
+
+	iter := coll.Find(bson.D{
+		{"object-global-key", bson.D{{"$regex", "^" + modelKey("")}}},
+		{"subject-global-key", bson.D{{"$regex", "^" + userGlobalKey("")}}},
+		{"role", bson.D{{"$exists", false}}},
+	}).Iter()
+	defer iter.Close()
+
+	var ops []txn.Op
+	var doc permissionDoc
+	for iter.Next(&doc) {
+		ops = append(ops, txn.Op{
+			C:      permissionsC,
+			Id:     doc.ID,
+			Assert: bson.D{{"role", bson.D{{"$exists", false}}}},
+			Update: bson.D{{"$set", bson.D{{"role", doc.Access}}}},
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
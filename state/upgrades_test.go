@@ -81,3 +81,53 @@ func (s *upgradesSuite) makeModel(c *gc.C, name string, attr coretesting.Attrs)
 	c.Assert(err, jc.ErrorIsNil)
 	return st
 }
+
+func (s *upgradesSuite) TestAssignBuiltinModelRoles(c *gc.C) {
+	coll, closer := s.state.db().GetRawCollection(permissionsC)
+	defer closer()
+
+	docs := []bson.M{{
+		"_id":                "cloud#fluffy#us#bob",
+		"object-global-key":  "cloud#fluffy",
+		"subject-global-key": "us#bob",
+		"access":             "admin",
+	}, {
+		"_id":                "e#deadbeef#gr#devs",
+		"object-global-key":  "e#deadbeef",
+		"subject-global-key": "gr#devs",
+		"access":             "read",
+	}, {
+		"_id":                "e#deadbeef#us#bob",
+		"object-global-key":  "e#deadbeef",
+		"subject-global-key": "us#bob",
+		"access":             "write",
+	}, {
+		"_id":                "e#deadbeef#us#mary",
+		"object-global-key":  "e#deadbeef",
+		"subject-global-key": "us#mary",
+		"access":             "write",
+		"role":               "operator",
+	}}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		err := coll.Insert(doc)
+		c.Assert(err, jc.ErrorIsNil)
+		ids[i] = doc["_id"].(string)
+	}
+
+	expected := []bson.M{
+		docs[0],
+		docs[1],
+		{
+			"_id":                "e#deadbeef#us#bob",
+			"object-global-key":  "e#deadbeef",
+			"subject-global-key": "us#bob",
+			"access":             "write",
+			"role":               "write",
+		},
+		docs[3],
+	}
+	upgraded := upgradedData(coll, expected)
+	upgraded.filter = bson.D{{"_id", bson.D{{"$in", ids}}}}
+	s.assertUpgradedData(c, AssignBuiltinModelRoles, upgraded)
+}
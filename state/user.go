@@ -182,7 +182,7 @@ func (st *State) recreateExistingUser(u *User, name, displayName, password, crea
 
 		// ensure models that were migrating at the time of the RemoveUser call are
 		// processed now.
-		modelQuery, closer, err := st.modelQueryForUser(u.UserTag(), false)
+		modelQuery, closer, err := st.modelQueryForUser(u.UserTag(), false, false)
 		defer closer()
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -258,7 +258,7 @@ func (st *State) RemoveUser(tag names.UserTag) error {
 
 		// remove the access to all the models and the current controller
 		// first query all the models for this user
-		modelQuery, closer, err := st.modelQueryForUser(tag, false)
+		modelQuery, closer, err := st.modelQueryForUser(tag, false, false)
 		defer closer()
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -276,6 +276,13 @@ func (st *State) RemoveUser(tag names.UserTag) error {
 		// remove the user from the controller
 		ops = append(ops, removeControllerUserOps(st.ControllerUUID(), tag)...)
 
+		// and from any groups they are a member of
+		groupOps, err := st.removeUserFromGroupsOps(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, groupOps...)
+
 		// new entry in the removal log
 		newRemovalLogEntry := userRemovedLogEntry{
 			RemovedBy:   u.doc.CreatedBy,
@@ -141,11 +141,29 @@ func NewControllerUserAccess(st *State, userDoc userAccessDoc) (permission.UserA
 }
 
 // UserPermission returns the access permission for the passed subject and target.
+// The access granted to any group the subject is a member of is taken
+// into account, and the greatest access is returned.
 func (st *State) UserPermission(subject names.UserTag, target names.Tag) (permission.Access, error) {
 	if err := st.userMayHaveAccess(subject); err != nil {
 		return "", errors.Trace(err)
 	}
 
+	access, err := st.userPermissionOnTarget(subject, target)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return "", errors.Trace(err)
+	}
+	groupAccess, groupErr := st.userGroupsPermission(subject, target)
+	if errors.Is(groupErr, errors.NotFound) {
+		return access, errors.Trace(err)
+	} else if groupErr != nil {
+		return "", errors.Trace(groupErr)
+	}
+	return greaterAccess(target.Kind(), access, groupAccess), nil
+}
+
+// userPermissionOnTarget returns the access permission granted directly
+// to the passed subject on the target.
+func (st *State) userPermissionOnTarget(subject names.UserTag, target names.Tag) (permission.Access, error) {
 	switch target.Kind() {
 	case names.ModelTagKind, names.ControllerTagKind:
 		access, err := st.UserAccess(subject, target)
@@ -0,0 +1,526 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/mgo/v3"
+	"github.com/juju/mgo/v3/bson"
+	"github.com/juju/mgo/v3/txn"
+	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn/v3"
+
+	"github.com/juju/juju/core/permission"
+)
+
+const userGroupGlobalKeyPrefix = "gr"
+
+// validUserGroupName matches the names of user groups. Group names are
+// more permissive than user names, so that the groups asserted by an
+// identity provider can be used as they are.
+var validUserGroupName = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?$`)
+
+// IsValidUserGroupName returns whether name is a valid user group name.
+func IsValidUserGroupName(name string) bool {
+	return validUserGroupName.MatchString(name)
+}
+
+// userGroupGlobalKey returns the subject global key of the named group,
+// which is used to grant the group's members access to objects.
+func userGroupGlobalKey(name string) string {
+	return fmt.Sprintf("%s#%s", userGroupGlobalKeyPrefix, strings.ToLower(name))
+}
+
+// userGroupDoc represents a controller-wide group of users.
+type userGroupDoc struct {
+	DocID string `bson:"_id"`
+	Name  string `bson:"name"`
+	// External is true if the membership of the group is asserted by
+	// the identity provider that users log in with, rather than
+	// managed through Juju.
+	External bool `bson:"external,omitempty"`
+	// Members holds the lower-cased ids of the users in the group.
+	Members     []string  `bson:"members"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+}
+
+// UserGroup represents a group of users on the controller. Access to
+// models, clouds, offers and the controller that is granted to a
+// group applies to every member of it.
+type UserGroup struct {
+	st  *State
+	doc userGroupDoc
+}
+
+// Name returns the name of the group.
+func (g *UserGroup) Name() string {
+	return g.doc.Name
+}
+
+// External returns whether the membership of the group is asserted by
+// an identity provider, rather than managed through Juju.
+func (g *UserGroup) External() bool {
+	return g.doc.External
+}
+
+// Members returns the users in the group, sorted by id.
+func (g *UserGroup) Members() []names.UserTag {
+	members := make([]names.UserTag, len(g.doc.Members))
+	for i, id := range g.doc.Members {
+		members[i] = names.NewUserTag(id)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Id() < members[j].Id()
+	})
+	return members
+}
+
+// CreatedBy returns the name of the user that created the group.
+func (g *UserGroup) CreatedBy() string {
+	return g.doc.CreatedBy
+}
+
+// DateCreated returns when the group was created, in UTC.
+func (g *UserGroup) DateCreated() time.Time {
+	return g.doc.DateCreated.UTC()
+}
+
+// Refresh refreshes the group's details from the database.
+func (g *UserGroup) Refresh() error {
+	var doc userGroupDoc
+	if err := g.st.getUserGroup(g.doc.Name, &doc); err != nil {
+		return errors.Trace(err)
+	}
+	g.doc = doc
+	return nil
+}
+
+// AddMembers adds the given users to the group. Local users must
+// exist. The members of external groups cannot be changed.
+func (g *UserGroup) AddMembers(users ...names.UserTag) error {
+	if g.doc.External {
+		return errors.NotSupportedf("changing the members of external group %q", g.doc.Name)
+	}
+	ids := make([]string, len(users))
+	for i, user := range users {
+		if user.IsLocal() {
+			if _, err := g.st.User(user); errors.Is(err, errors.NotFound) {
+				return errors.Annotatef(err, "user %q does not exist locally", user.Name())
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		ids[i] = userAccessID(user)
+	}
+	ops := []txn.Op{{
+		C:      userGroupsC,
+		Id:     g.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$addToSet", bson.D{{"members", bson.D{{"$each", ids}}}}}},
+	}}
+	if err := g.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("user group %q", g.doc.Name)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot add members to user group %q", g.doc.Name)
+	}
+	return errors.Trace(g.Refresh())
+}
+
+// RemoveMembers removes the given users from the group. Users that are
+// not members of the group are ignored. The members of external groups
+// cannot be changed.
+func (g *UserGroup) RemoveMembers(users ...names.UserTag) error {
+	if g.doc.External {
+		return errors.NotSupportedf("changing the members of external group %q", g.doc.Name)
+	}
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = userAccessID(user)
+	}
+	ops := []txn.Op{{
+		C:      userGroupsC,
+		Id:     g.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$pullAll", bson.D{{"members", ids}}}},
+	}}
+	if err := g.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("user group %q", g.doc.Name)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove members from user group %q", g.doc.Name)
+	}
+	return errors.Trace(g.Refresh())
+}
+
+// AddUserGroup adds a new, empty, group of users to the controller.
+// The members of an external group are asserted by the identity
+// provider that users log in with, and are updated with
+// SyncExternalUserGroups rather than AddMembers.
+func (st *State) AddUserGroup(name string, external bool, creator string) (*UserGroup, error) {
+	if !IsValidUserGroupName(name) {
+		return nil, errors.NotValidf("user group name %q", name)
+	}
+	doc := userGroupDoc{
+		DocID:       strings.ToLower(name),
+		Name:        name,
+		External:    external,
+		Members:     []string{},
+		CreatedBy:   creator,
+		DateCreated: st.nowToTheSecond(),
+	}
+	ops := []txn.Op{{
+		C:      userGroupsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("user group %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot add user group %q", name)
+	}
+	return &UserGroup{st: st, doc: doc}, nil
+}
+
+func (st *State) getUserGroup(name string, doc *userGroupDoc) error {
+	groups, closer := st.db().GetCollection(userGroupsC)
+	defer closer()
+
+	err := groups.FindId(strings.ToLower(name)).One(doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("user group %q", name)
+	}
+	doc.DateCreated = doc.DateCreated.UTC()
+	return errors.Trace(err)
+}
+
+// UserGroup returns the named group of users.
+func (st *State) UserGroup(name string) (*UserGroup, error) {
+	group := &UserGroup{st: st}
+	if err := st.getUserGroup(name, &group.doc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return group, nil
+}
+
+// AllUserGroups returns all the groups of users on the controller,
+// sorted by name.
+func (st *State) AllUserGroups() ([]*UserGroup, error) {
+	return st.findUserGroups(nil)
+}
+
+// UserGroupsForUser returns the groups that the given user is a member
+// of, sorted by name.
+func (st *State) UserGroupsForUser(user names.UserTag) ([]*UserGroup, error) {
+	return st.findUserGroups(bson.D{{"members", userAccessID(user)}})
+}
+
+func (st *State) findUserGroups(query bson.D) ([]*UserGroup, error) {
+	groups, closer := st.db().GetCollection(userGroupsC)
+	defer closer()
+
+	var docs []userGroupDoc
+	if err := groups.Find(query).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get user groups")
+	}
+	result := make([]*UserGroup, len(docs))
+	for i, doc := range docs {
+		doc.DateCreated = doc.DateCreated.UTC()
+		result[i] = &UserGroup{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveUserGroup removes the named group of users, along with all the
+// access that was granted to it.
+func (st *State) RemoveUserGroup(name string) error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.UserGroup(name); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops, err := st.removeInCollectionOps(permissionsC, bson.D{
+			{"subject-global-key", userGroupGlobalKey(name)},
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      userGroupsC,
+			Id:     strings.ToLower(name),
+			Assert: txn.DocExists,
+			Remove: true,
+		}), nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// SyncExternalUserGroups records that the given user is a member of
+// exactly the named groups, as asserted by the identity provider that
+// they logged in with. Only the membership of external groups is
+// changed; groups that do not exist on the controller are ignored.
+func (st *State) SyncExternalUserGroups(user names.UserTag, groups []string) error {
+	userID := userAccessID(user)
+	asserted := set.NewStrings()
+	for _, group := range groups {
+		asserted.Add(strings.ToLower(group))
+	}
+
+	buildTxn := func(int) ([]txn.Op, error) {
+		external, err := st.findUserGroups(bson.D{{"external", true}})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var ops []txn.Op
+		for _, group := range external {
+			isMember := set.NewStrings(group.doc.Members...).Contains(userID)
+			switch {
+			case asserted.Contains(group.doc.DocID) && !isMember:
+				ops = append(ops, txn.Op{
+					C:      userGroupsC,
+					Id:     group.doc.DocID,
+					Assert: bson.D{{"members", bson.D{{"$ne", userID}}}},
+					Update: bson.D{{"$addToSet", bson.D{{"members", userID}}}},
+				})
+			case !asserted.Contains(group.doc.DocID) && isMember:
+				ops = append(ops, txn.Op{
+					C:      userGroupsC,
+					Id:     group.doc.DocID,
+					Assert: bson.D{{"members", userID}},
+					Update: bson.D{{"$pull", bson.D{{"members", userID}}}},
+				})
+			}
+		}
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot sync user groups of %q", user.Id())
+}
+
+// userGroupObjectGlobalKey returns the global key of the object that a
+// group may be granted access to, and the function that validates the
+// access levels applicable to it.
+func (st *State) userGroupObjectGlobalKey(target names.Tag) (string, func(permission.Access) error, error) {
+	switch target.Kind() {
+	case names.ModelTagKind:
+		return modelKey(target.Id()), permission.ValidateModelAccess, nil
+	case names.ControllerTagKind:
+		return controllerKey(target.Id()), permission.ValidateControllerAccess, nil
+	case names.CloudTagKind:
+		return cloudGlobalKey(target.Id()), permission.ValidateCloudAccess, nil
+	case names.ApplicationOfferTagKind:
+		offerUUID, err := applicationOfferUUID(st, target.Id())
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		return applicationOfferKey(offerUUID), permission.ValidateOfferAccess, nil
+	default:
+		return "", nil, errors.NotValidf("%q as a target", target.Kind())
+	}
+}
+
+// UserGroupAccess returns the access that the named group has been
+// granted to the target.
+func (st *State) UserGroupAccess(group string, target names.Tag) (permission.Access, error) {
+	objectKey, _, err := st.userGroupObjectGlobalKey(target)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	perm, err := st.userPermission(objectKey, userGroupGlobalKey(group))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return perm.access(), nil
+}
+
+// SetUserGroupAccess grants the named group the given access to the
+// target, replacing any access it had already been granted.
+func (st *State) SetUserGroupAccess(group string, target names.Tag, access permission.Access) error {
+	objectKey, validate, err := st.userGroupObjectGlobalKey(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := validate(access); err != nil {
+		return errors.Trace(err)
+	}
+	subjectKey := userGroupGlobalKey(group)
+
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.UserGroup(group); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      userGroupsC,
+			Id:     strings.ToLower(group),
+			Assert: txn.DocExists,
+		}}
+		_, err := st.userPermission(objectKey, subjectKey)
+		if errors.Is(err, errors.NotFound) {
+			return append(ops, createPermissionOp(objectKey, subjectKey, access)), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, updatePermissionOp(objectKey, subjectKey, access)), nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot grant user group %q access", group)
+}
+
+// RemoveUserGroupAccess revokes the access that the named group has
+// been granted to the target.
+func (st *State) RemoveUserGroupAccess(group string, target names.Tag) error {
+	objectKey, _, err := st.userGroupObjectGlobalKey(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subjectKey := userGroupGlobalKey(group)
+
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.userPermission(objectKey, subjectKey); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{removePermissionOp(objectKey, subjectKey)}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// userGroupsPermission returns the greatest access to the target that
+// has been granted to any of the groups the user is a member of. It
+// returns a NotFound error if none of the groups have any access.
+func (st *State) userGroupsPermission(user names.UserTag, target names.Tag) (permission.Access, error) {
+	groupKeys, err := st.userGroupGlobalKeys(user)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(groupKeys) == 0 {
+		return "", errors.NotFoundf("user group permission for %q", user.Id())
+	}
+	objectKey, _, err := st.userGroupObjectGlobalKey(target)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	ids := make([]string, len(groupKeys))
+	for i, key := range groupKeys {
+		ids[i] = permissionID(objectKey, key)
+	}
+
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+
+	var docs []permissionDoc
+	if err := permissions.Find(bson.D{{"_id", bson.D{{"$in", ids}}}}).All(&docs); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(docs) == 0 {
+		return "", errors.NotFoundf("user group permission for %q on %q", user.Id(), objectKey)
+	}
+	var result permission.Access
+	for _, doc := range docs {
+		result = greaterAccess(target.Kind(), result, stringToAccess(doc.Access))
+	}
+	return result, nil
+}
+
+// userGroupPermissionDocs returns the permissions granted to the groups
+// that the user is a member of, on the objects whose global keys have
+// the given prefix.
+func (st *State) userGroupPermissionDocs(user names.UserTag, objectKeyPrefix string) ([]permissionDoc, error) {
+	groupKeys, err := st.userGroupGlobalKeys(user)
+	if err != nil || len(groupKeys) == 0 {
+		return nil, errors.Trace(err)
+	}
+
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+
+	var docs []permissionDoc
+	if err := permissions.Find(bson.D{
+		{"subject-global-key", bson.D{{"$in", groupKeys}}},
+		{"object-global-key", bson.D{{"$regex", "^" + regexp.QuoteMeta(objectKeyPrefix)}}},
+	}).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return docs, nil
+}
+
+// userGroupModelUUIDs returns the UUIDs of the models that the groups
+// the user is a member of have been granted access to.
+func (st *State) userGroupModelUUIDs(user names.UserTag) ([]string, error) {
+	docs, err := st.userGroupPermissionDocs(user, modelKey(""))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	uuids := make([]string, len(docs))
+	for i, doc := range docs {
+		uuids[i] = strings.TrimPrefix(doc.ObjectGlobalKey, modelKey(""))
+	}
+	return uuids, nil
+}
+
+// userGroupGlobalKeys returns the subject global keys of the groups
+// that the user is a member of.
+func (st *State) userGroupGlobalKeys(user names.UserTag) ([]string, error) {
+	groups, closer := st.db().GetCollection(userGroupsC)
+	defer closer()
+
+	var docs []userGroupDoc
+	if err := groups.Find(bson.D{{"members", userAccessID(user)}}).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get user groups")
+	}
+	keys := make([]string, len(docs))
+	for i, doc := range docs {
+		keys[i] = userGroupGlobalKey(doc.DocID)
+	}
+	return keys, nil
+}
+
+// greaterAccess returns the greater of the two access levels, as
+// ordered for the given kind of target.
+func greaterAccess(targetKind string, a, b permission.Access) permission.Access {
+	if a == permission.NoAccess {
+		return b
+	}
+	var greater bool
+	switch targetKind {
+	case names.ModelTagKind:
+		greater = b.GreaterModelAccessThan(a)
+	case names.ControllerTagKind:
+		greater = b.GreaterControllerAccessThan(a)
+	case names.CloudTagKind:
+		greater = b.EqualOrGreaterCloudAccessThan(a)
+	case names.ApplicationOfferTagKind:
+		greater = b.GreaterOfferAccessThan(a)
+	}
+	if greater {
+		return b
+	}
+	return a
+}
+
+// removeUserFromGroupsOps returns the operations that remove the user
+// from every group that they are a member of.
+func (st *State) removeUserFromGroupsOps(user names.UserTag) ([]txn.Op, error) {
+	groupKeys, err := st.userGroupGlobalKeys(user)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	userID := userAccessID(user)
+	ops := make([]txn.Op, len(groupKeys))
+	for i, key := range groupKeys {
+		ops[i] = txn.Op{
+			C:      userGroupsC,
+			Id:     strings.TrimPrefix(key, userGroupGlobalKeyPrefix+"#"),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$pull", bson.D{{"members", userID}}}},
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserGroupSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserGroupSuite{})
+
+func (s *UserGroupSuite) TestAddUserGroup(c *gc.C) {
+	group, err := s.State.AddUserGroup("Developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "Developers")
+	c.Assert(group.External(), jc.IsFalse)
+	c.Assert(group.Members(), gc.HasLen, 0)
+	c.Assert(group.CreatedBy(), gc.Equals, "admin")
+
+	group, err = s.State.UserGroup("developers")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "Developers")
+
+	_, err = s.State.AddUserGroup("developers", true, "admin")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *UserGroupSuite) TestAddUserGroupInvalidName(c *gc.C) {
+	_, err := s.State.AddUserGroup("-developers", false, "admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *UserGroupSuite) TestUserGroupNotFound(c *gc.C) {
+	_, err := s.State.UserGroup("developers")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserGroupSuite) TestMembers(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	mary := names.NewUserTag("mary@external")
+	group, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = group.AddMembers(mary, bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{bob, mary})
+
+	groups, err := s.State.UserGroupsForUser(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 1)
+	c.Assert(groups[0].Name(), gc.Equals, "developers")
+
+	err = group.RemoveMembers(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{mary})
+}
+
+func (s *UserGroupSuite) TestAddMembersUnknownLocalUser(c *gc.C) {
+	group, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(names.NewUserTag("nobody"))
+	c.Assert(err, gc.ErrorMatches, `user "nobody" does not exist locally: user "nobody" not found`)
+}
+
+func (s *UserGroupSuite) TestExternalGroupMembersNotChangeable(c *gc.C) {
+	group, err := s.State.AddUserGroup("platform", true, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(names.NewUserTag("mary@external"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *UserGroupSuite) TestSyncExternalUserGroups(c *gc.C) {
+	mary := names.NewUserTag("mary@external")
+	_, err := s.State.AddUserGroup("platform", true, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddUserGroup("testers", true, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	local, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = local.AddMembers(mary)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SyncExternalUserGroups(mary, []string{"Platform", "testers", "unknown"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertGroupsForUser(c, mary, "developers", "platform", "testers")
+
+	// Local groups are left alone.
+	err = s.State.SyncExternalUserGroups(mary, []string{"testers"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertGroupsForUser(c, mary, "developers", "testers")
+
+	// Syncing again with no change is fine.
+	err = s.State.SyncExternalUserGroups(mary, []string{"testers"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserGroupSuite) assertGroupsForUser(c *gc.C, user names.UserTag, expected ...string) {
+	groups, err := s.State.UserGroupsForUser(user)
+	c.Assert(err, jc.ErrorIsNil)
+	groupNames := make([]string, len(groups))
+	for i, group := range groups {
+		groupNames[i] = group.Name()
+	}
+	c.Assert(groupNames, jc.DeepEquals, expected)
+}
+
+func (s *UserGroupSuite) TestGroupModelAccess(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true}).UserTag()
+	modelTag := s.Model.ModelTag()
+	group, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(bob)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.UserPermission(bob, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetUserGroupAccess("developers", modelTag, permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserGroupAccess("developers", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	access, err = s.State.UserPermission(bob, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	uuids, err := s.State.ModelUUIDsForUser(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuids, jc.DeepEquals, []string{modelTag.Id()})
+
+	err = s.State.RemoveUserGroupAccess("developers", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserPermission(bob, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserGroupSuite) TestGroupAccessDoesNotLowerUserAccess(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", Access: permission.AdminAccess}).UserTag()
+	modelTag := s.Model.ModelTag()
+	group, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("developers", modelTag, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.UserPermission(bob, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *UserGroupSuite) TestGroupControllerAccess(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	controllerTag := s.State.ControllerTag()
+	group, err := s.State.AddUserGroup("admins", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(bob)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetUserGroupAccess("admins", controllerTag, permission.WriteAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	err = s.State.SetUserGroupAccess("admins", controllerTag, permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserPermission(bob, controllerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.SuperuserAccess)
+}
+
+func (s *UserGroupSuite) TestSetUserGroupAccessGroupNotFound(c *gc.C) {
+	err := s.State.SetUserGroupAccess("developers", s.Model.ModelTag(), permission.ReadAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserGroupSuite) TestRemoveUserGroup(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true}).UserTag()
+	modelTag := s.Model.ModelTag()
+	group, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("developers", modelTag, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveUserGroup("developers")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserGroup("developers")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.UserPermission(bob, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveUserGroup("developers")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserGroupSuite) TestRemoveUserRemovesGroupMembership(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	group, err := s.State.AddUserGroup("developers", false, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMembers(bob)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveUser(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Members(), gc.HasLen, 0)
+}
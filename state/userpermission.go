@@ -29,6 +29,10 @@ type permissionDoc struct {
 	SubjectGlobalKey string `bson:"subject-global-key"`
 	// Access is the permission level.
 	Access string `bson:"access"`
+	// Role is the name of the role granted to a user on a model. It is
+	// only set for the model permissions of users; if empty, the
+	// built-in role of the same name as Access applies.
+	Role string `bson:"role,omitempty"`
 }
 
 func stringToAccess(a string) permission.Access {
@@ -76,6 +80,13 @@ func (p *userPermission) access() permission.Access {
 	return stringToAccess(p.doc.Access)
 }
 
+func (p *userPermission) role() string {
+	if p.doc.Role == "" {
+		return p.doc.Access
+	}
+	return p.doc.Role
+}
+
 func permissionID(objectGlobalKey, subjectGlobalKey string) string {
 	// example: e#deadbeef#us#jim
 	// e: object global key
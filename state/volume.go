@@ -52,6 +52,11 @@ type Volume interface {
 	// Releasing reports whether or not the volume is to be released
 	// from the model when it is Dying/Dead.
 	Releasing() bool
+
+	// PendingSize returns the size in MiB that the volume is to be
+	// resized to, and true, if a resize has been requested and not
+	// yet completed.
+	PendingSize() (uint64, bool)
 }
 
 // VolumeAttachment describes an attachment of a volume to a machine.
@@ -132,6 +137,10 @@ type volumeDoc struct {
 	// the volume as being non-detachable, and to determine
 	// which volumes must be removed along with said machine.
 	HostId string `bson:"hostid,omitempty"`
+
+	// ResizeTo is the size in MiB that the volume is to be resized
+	// to by the storage provisioner, if non-zero.
+	ResizeTo uint64 `bson:"resizeto,omitempty"`
 }
 
 // volumeAttachmentDoc records information about a volume attachment.
@@ -176,6 +185,10 @@ type VolumeParams struct {
 
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// Snapshot, if non-empty, is the ID of the volume snapshot
+	// from which the volume should be created.
+	Snapshot string `bson:"snapshot,omitempty"`
 }
 
 // VolumeInfo describes information about a volume.
@@ -278,6 +291,11 @@ func (v *volume) Releasing() bool {
 	return v.doc.Releasing
 }
 
+// PendingSize is required to implement Volume.
+func (v *volume) PendingSize() (uint64, bool) {
+	return v.doc.ResizeTo, v.doc.ResizeTo != 0
+}
+
 // Status is required to implement StatusGetter.
 func (v *volume) Status() (status.StatusInfo, error) {
 	return getStatus(v.mb.db(), volumeGlobalKey(v.VolumeTag().Id()), "volume")
@@ -1134,6 +1152,11 @@ func (sb *storageBackend) validateVolumeParams(params VolumeParams, machineId st
 	if params.Size == 0 {
 		return "", errors.New("invalid size 0")
 	}
+	if params.Snapshot != "" {
+		if err := sb.validateVolumeSnapshotHost(params.Snapshot, machineId); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
 	return machineId, nil
 }
 
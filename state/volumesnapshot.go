@@ -0,0 +1,495 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/mgo/v3"
+	"github.com/juju/mgo/v3/bson"
+	"github.com/juju/mgo/v3/txn"
+	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn/v3"
+
+	"github.com/juju/juju/core/status"
+)
+
+// VolumeSnapshot describes a point-in-time snapshot of a volume.
+//
+// Snapshots outlive the volumes they were taken from, and may be
+// restored into new storage instances, which can then be attached
+// to units like any other detached storage.
+type VolumeSnapshot interface {
+	Lifer
+	status.StatusGetter
+	status.StatusSetter
+
+	// Id returns the unique ID of the snapshot. The ID is the
+	// ID of the snapshotted volume, followed by "@" and a number,
+	// e.g. "0/1@3". Snapshots of machine-scoped volumes are
+	// thereby scoped to the same machine.
+	Id() string
+
+	// Volume returns the tag of the volume that the snapshot was
+	// taken from. The volume may no longer exist.
+	Volume() names.VolumeTag
+
+	// StorageInstance returns the tag of the storage instance that
+	// the snapshotted volume was assigned to, if any.
+	StorageInstance() (names.StorageTag, bool)
+
+	// StorageName returns the name of the storage, as defined in the
+	// charm storage metadata, that the snapshotted volume was
+	// assigned to.
+	StorageName() string
+
+	// Kind returns the kind of the storage instance that the
+	// snapshotted volume was assigned to. Restoring the snapshot
+	// creates a storage instance of the same kind.
+	Kind() StorageKind
+
+	// Pool returns the name of the storage pool of the snapshotted
+	// volume. Snapshots are restored into the same pool.
+	Pool() string
+
+	// Created returns the time at which the snapshot was requested.
+	Created() time.Time
+
+	// Info returns the snapshot's VolumeSnapshotInfo, or a
+	// NotProvisioned error if the snapshot has not yet been taken.
+	Info() (VolumeSnapshotInfo, error)
+}
+
+// VolumeSnapshotInfo describes information about a volume snapshot.
+type VolumeSnapshotInfo struct {
+	// SnapshotId is the provider-allocated unique ID of the snapshot.
+	SnapshotId string `bson:"snapshotid"`
+
+	// Size is the size of the snapshotted volume, in MiB.
+	Size uint64 `bson:"size"`
+}
+
+type volumeSnapshot struct {
+	mb  modelBackend
+	doc volumeSnapshotDoc
+}
+
+// volumeSnapshotDoc records information about a volume snapshot.
+type volumeSnapshotDoc struct {
+	DocID       string              `bson:"_id"`
+	Id          string              `bson:"id"`
+	ModelUUID   string              `bson:"model-uuid"`
+	Life        Life                `bson:"life"`
+	Volume      string              `bson:"volumeid"`
+	StorageId   string              `bson:"storageid,omitempty"`
+	StorageName string              `bson:"storagename"`
+	Kind        StorageKind         `bson:"storagekind"`
+	Pool        string              `bson:"pool"`
+	Created     int64               `bson:"created"`
+	Info        *VolumeSnapshotInfo `bson:"info,omitempty"`
+}
+
+// Id is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) Id() string {
+	return s.doc.Id
+}
+
+// Volume is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) Volume() names.VolumeTag {
+	return names.NewVolumeTag(s.doc.Volume)
+}
+
+// StorageInstance is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) StorageInstance() (names.StorageTag, bool) {
+	if s.doc.StorageId == "" {
+		return names.StorageTag{}, false
+	}
+	return names.NewStorageTag(s.doc.StorageId), true
+}
+
+// StorageName is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) StorageName() string {
+	return s.doc.StorageName
+}
+
+// Kind is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) Kind() StorageKind {
+	return s.doc.Kind
+}
+
+// Pool is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) Pool() string {
+	return s.doc.Pool
+}
+
+// Created is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) Created() time.Time {
+	return time.Unix(0, s.doc.Created).UTC()
+}
+
+// Life is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) Life() Life {
+	return s.doc.Life
+}
+
+// Info is part of the VolumeSnapshot interface.
+func (s *volumeSnapshot) Info() (VolumeSnapshotInfo, error) {
+	if s.doc.Info == nil {
+		return VolumeSnapshotInfo{}, errors.NotProvisionedf("volume snapshot %q", s.doc.Id)
+	}
+	return *s.doc.Info, nil
+}
+
+// Status is required to implement StatusGetter.
+func (s *volumeSnapshot) Status() (status.StatusInfo, error) {
+	return getStatus(s.mb.db(), volumeSnapshotGlobalKey(s.doc.Id), "volume snapshot")
+}
+
+// SetStatus is required to implement StatusSetter.
+func (s *volumeSnapshot) SetStatus(snapshotStatus status.StatusInfo) error {
+	switch snapshotStatus.Status {
+	case status.Pending, status.Available, status.Destroying:
+	case status.Error:
+		if snapshotStatus.Message == "" {
+			return errors.Errorf("cannot set status %q without info", snapshotStatus.Status)
+		}
+	default:
+		return errors.Errorf("cannot set invalid status %q", snapshotStatus.Status)
+	}
+	return setStatus(s.mb.db(), setStatusParams{
+		badge:     "volume snapshot",
+		globalKey: volumeSnapshotGlobalKey(s.doc.Id),
+		status:    snapshotStatus.Status,
+		message:   snapshotStatus.Message,
+		rawData:   snapshotStatus.Data,
+		updated:   timeOrNow(snapshotStatus.Since, s.mb.clock()),
+	})
+}
+
+func volumeSnapshotGlobalKey(id string) string {
+	return "vs#" + id
+}
+
+// volumeSnapshotIdRE matches valid volume snapshot IDs.
+var volumeSnapshotIdRE = regexp.MustCompile(
+	fmt.Sprintf("^((%s/)?%s)@%s$", machineOrUnitSnippet, names.NumberSnippet, names.NumberSnippet),
+)
+
+// IsValidVolumeSnapshotId reports whether the specified string is a
+// valid volume snapshot ID.
+func IsValidVolumeSnapshotId(id string) bool {
+	return volumeSnapshotIdRE.MatchString(id)
+}
+
+// VolumeSnapshot returns the volume snapshot with the specified ID.
+func (sb *storageBackend) VolumeSnapshot(id string) (VolumeSnapshot, error) {
+	s, err := sb.volumeSnapshot(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s, nil
+}
+
+func (sb *storageBackend) volumeSnapshot(id string) (*volumeSnapshot, error) {
+	if !IsValidVolumeSnapshotId(id) {
+		return nil, errors.NotValidf("volume snapshot ID %q", id)
+	}
+	coll, cleanup := sb.mb.db().GetCollection(volumeSnapshotsC)
+	defer cleanup()
+
+	s := volumeSnapshot{mb: sb.mb}
+	if err := coll.FindId(id).One(&s.doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("volume snapshot %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get volume snapshot %q", id)
+	}
+	return &s, nil
+}
+
+// AllVolumeSnapshots returns all volume snapshots in the model.
+func (sb *storageBackend) AllVolumeSnapshots() ([]VolumeSnapshot, error) {
+	coll, cleanup := sb.mb.db().GetCollection(volumeSnapshotsC)
+	defer cleanup()
+
+	var docs []volumeSnapshotDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get volume snapshots")
+	}
+	snapshots := make([]VolumeSnapshot, len(docs))
+	for i, doc := range docs {
+		snapshots[i] = &volumeSnapshot{mb: sb.mb, doc: doc}
+	}
+	return snapshots, nil
+}
+
+// CreateVolumeSnapshot records a request to snapshot the volume assigned
+// to the specified storage instance. For filesystem storage, the volume
+// backing the filesystem is snapshotted; filesystems that are not backed
+// by volumes cannot be snapshotted.
+//
+// The snapshot is taken asynchronously by the storage provisioner, which
+// records the provider's snapshot ID with SetVolumeSnapshotInfo.
+func (sb *storageBackend) CreateVolumeSnapshot(tag names.StorageTag) (_ VolumeSnapshot, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot snapshot storage %s", tag.Id())
+
+	var doc volumeSnapshotDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := sb.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		v, err := sb.storageInstanceVolume(tag)
+		if errors.IsNotFound(err) {
+			return nil, errors.NotSupportedf("snapshotting storage not backed by a volume")
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errors.Errorf("%s is not alive", names.ReadableString(v.VolumeTag()))
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Annotatef(err, "%s", names.ReadableString(v.VolumeTag()))
+		}
+
+		// Only allocate the snapshot ID once, so that retries
+		// do not burn through the sequence.
+		if doc.Id == "" {
+			seq, err := sequence(sb.mb, "volumesnapshot")
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			doc.Id = fmt.Sprintf("%s@%d", v.VolumeTag().Id(), seq)
+		}
+		doc.Volume = v.VolumeTag().Id()
+		doc.StorageId = s.doc.Id
+		doc.StorageName = s.doc.StorageName
+		doc.Kind = s.doc.Kind
+		doc.Pool = info.Pool
+		doc.Created = sb.mb.clock().Now().UnixNano()
+
+		statusDoc := statusDoc{
+			Status:  status.Pending,
+			Updated: doc.Created,
+		}
+		return []txn.Op{
+			{
+				C:      storageInstancesC,
+				Id:     s.doc.Id,
+				Assert: isAliveDoc,
+			},
+			{
+				C:      volumesC,
+				Id:     v.doc.Name,
+				Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", true}}}),
+			},
+			createStatusOp(sb.mb, volumeSnapshotGlobalKey(doc.Id), statusDoc),
+			{
+				C:      volumeSnapshotsC,
+				Id:     doc.Id,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			},
+		}, nil
+	}
+	if err := sb.mb.db().Run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return sb.VolumeSnapshot(doc.Id)
+}
+
+// SetVolumeSnapshotInfo records the provider-allocated information
+// for a snapshot once it has been taken, and marks it available.
+func (sb *storageBackend) SetVolumeSnapshotInfo(id string, info VolumeSnapshotInfo) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set info for volume snapshot %q", id)
+	if info.SnapshotId == "" {
+		return errors.New("snapshot ID not set")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := sb.volumeSnapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("volume snapshot is not alive")
+		}
+		if s.doc.Info != nil {
+			if s.doc.Info.SnapshotId != info.SnapshotId {
+				return nil, errors.Errorf(
+					"snapshot ID mismatch: %q != %q",
+					info.SnapshotId, s.doc.Info.SnapshotId,
+				)
+			}
+			return nil, jujutxn.ErrNoOperations
+		}
+		statusOps, err := statusSetOps(sb.mb.db(), statusDoc{
+			Status:  status.Available,
+			Updated: sb.mb.clock().Now().UnixNano(),
+		}, volumeSnapshotGlobalKey(id))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append([]txn.Op{{
+			C:  volumeSnapshotsC,
+			Id: id,
+			Assert: append(isAliveDoc,
+				bson.DocElem{"info", bson.D{{"$exists", false}}},
+			),
+			Update: bson.D{{"$set", bson.D{{"info", &info}}}},
+		}}, statusOps...), nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// DestroyVolumeSnapshot ensures that the volume snapshot will be
+// destroyed by the storage provisioner, and then removed from state.
+// Storage instances already restored from the snapshot are unaffected,
+// but those not yet provisioned will fail to provision.
+func (sb *storageBackend) DestroyVolumeSnapshot(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "destroying volume snapshot %q", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := sb.volumeSnapshot(id)
+		if errors.IsNotFound(err) && attempt > 0 {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		statusOps, err := statusSetOps(sb.mb.db(), statusDoc{
+			Status:  status.Destroying,
+			Updated: sb.mb.clock().Now().UnixNano(),
+		}, volumeSnapshotGlobalKey(id))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append([]txn.Op{{
+			C:      volumeSnapshotsC,
+			Id:     id,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+		}}, statusOps...), nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// RemoveVolumeSnapshot removes the volume snapshot from state. It is
+// called by the storage provisioner once a dying snapshot has been
+// destroyed in the provider, and will fail if the snapshot is alive.
+func (sb *storageBackend) RemoveVolumeSnapshot(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "removing volume snapshot %q", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := sb.volumeSnapshot(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life == Alive {
+			return nil, errors.New("volume snapshot is alive")
+		}
+		return []txn.Op{
+			{
+				C:      volumeSnapshotsC,
+				Id:     id,
+				Assert: bson.D{{"life", bson.D{{"$ne", Alive}}}},
+				Remove: true,
+			},
+			removeStatusOp(sb.mb, volumeSnapshotGlobalKey(id)),
+		}, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// RestoreVolumeSnapshot creates a new, detached storage instance with
+// the specified storage name, whose volume will be created from the
+// snapshot when the storage is attached to a unit. Snapshots of
+// machine-scoped volumes can only be restored on the machine that
+// holds the snapshot.
+func (sb *storageBackend) RestoreVolumeSnapshot(id, storageName string) (_ names.StorageTag, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot restore volume snapshot %q", id)
+	if storageName != "" && !storageNameRE.MatchString(storageName) {
+		return names.StorageTag{}, errors.NotValidf("storage name %q", storageName)
+	}
+	var storageId string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := sb.volumeSnapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("volume snapshot is not alive")
+		}
+		info, err := s.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		name := storageName
+		if name == "" {
+			name = s.doc.StorageName
+		}
+		if storageId == "" {
+			storageId, err = newStorageInstanceId(sb.mb, name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		} else if !strings.HasPrefix(storageId, name+"/") {
+			return nil, errors.New("storage name changed")
+		}
+		return []txn.Op{
+			{
+				C:      volumeSnapshotsC,
+				Id:     id,
+				Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", true}}}),
+			},
+			{
+				C:      storageInstancesC,
+				Id:     storageId,
+				Assert: txn.DocMissing,
+				Insert: &storageInstanceDoc{
+					Id:          storageId,
+					Kind:        s.doc.Kind,
+					StorageName: name,
+					Constraints: storageInstanceConstraints{
+						Pool:     s.doc.Pool,
+						Size:     info.Size,
+						Snapshot: id,
+					},
+				},
+			},
+		}, nil
+	}
+	if err := sb.mb.db().Run(buildTxn); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	return names.NewStorageTag(storageId), nil
+}
+
+// validateVolumeSnapshotHost returns an error if the volume snapshot
+// with the specified ID cannot be restored into a volume scoped to the
+// specified machine. Snapshots of machine-scoped volumes, such as lvm
+// or zfs snapshots, are held by the machine, so they can only be
+// restored into volumes on that machine.
+func (sb *storageBackend) validateVolumeSnapshotHost(id, machineId string) error {
+	s, err := sb.volumeSnapshot(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	snapshotMachine, ok := names.VolumeMachine(s.Volume())
+	if !ok || snapshotMachine.Id() == machineId {
+		return nil
+	}
+	return errors.Errorf(
+		"volume snapshot %q is held by machine %s, and can only be restored there",
+		id, snapshotMachine.Id(),
+	)
+}
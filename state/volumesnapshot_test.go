@@ -0,0 +1,63 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type VolumeSnapshotStateSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&VolumeSnapshotStateSuite{})
+
+func (s *VolumeSnapshotStateSuite) TestRestoreMachineScopedSnapshot(c *gc.C) {
+	app, u, storageTag := s.setupSingleStorageDetachable(c, "block", "loop-pool")
+	err := s.st.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := unitMachine(c, s.st, u)
+	volume := s.storageInstanceVolume(c, storageTag)
+	err = s.storageBackend.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "vol-123",
+		Pool:     "loop-pool",
+		Size:     1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshot, err := s.storageBackend.CreateVolumeSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeSnapshotInfo(snapshot.Id(), state.VolumeSnapshotInfo{
+		SnapshotId: "snap-123",
+		Size:       1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	restored, err := s.storageBackend.RestoreVolumeSnapshot(snapshot.Id(), "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The snapshot is held by the first unit's machine, so the
+	// restored storage cannot be attached to a unit on another.
+	other, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.st.AssignUnit(other, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unitMachine(c, s.st, other).Id(), gc.Not(gc.Equals), machine.Id())
+	err = s.storageBackend.AttachStorage(restored, other.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `.*volume snapshot "`+snapshot.Id()+`" is held by machine `+machine.Id()+`, and can only be restored there`)
+
+	err = s.storageBackend.AttachStorage(restored, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	volume = s.storageInstanceVolume(c, restored)
+	volumeMachine, ok := names.VolumeMachine(volume.VolumeTag())
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(volumeMachine, gc.Equals, machine.MachineTag())
+	params, ok := volume.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(params.Snapshot, gc.Equals, snapshot.Id())
+}
+
@@ -286,6 +286,87 @@ func (sb *storageBackend) watchHostStorage(host names.Tag, collection string) St
 	return newLifecycleWatcher(mb, collection, members, filter, nil)
 }
 
+// WatchModelVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all snapshots of model-scoped volumes.
+func (sb *storageBackend) WatchModelVolumeSnapshots() StringsWatcher {
+	mb := sb.mb
+	pattern := fmt.Sprintf("^%s@%s$", mb.docID(names.NumberSnippet), names.NumberSnippet)
+	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
+	filter := func(id interface{}) bool {
+		k, err := mb.strictLocalID(id.(string))
+		if err != nil {
+			return false
+		}
+		return !strings.Contains(k, "/")
+	}
+	return newLifecycleWatcher(mb, volumeSnapshotsC, members, filter, nil)
+}
+
+// WatchMachineVolumeSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all snapshots of volumes scoped to the
+// specified machine.
+func (sb *storageBackend) WatchMachineVolumeSnapshots(m names.MachineTag) StringsWatcher {
+	mb := sb.mb
+	pattern := fmt.Sprintf("^%s/%s@%s$", mb.docID(m.Id()), names.NumberSnippet, names.NumberSnippet)
+	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
+	prefix := m.Id() + "/"
+	filter := func(id interface{}) bool {
+		k, err := mb.strictLocalID(id.(string))
+		if err != nil {
+			return false
+		}
+		return strings.HasPrefix(k, prefix) && !strings.Contains(k[len(prefix):], "/")
+	}
+	return newLifecycleWatcher(mb, volumeSnapshotsC, members, filter, nil)
+}
+
+// WatchModelVolumeResizes returns a StringsWatcher that notifies of
+// changes to model-scoped volumes, so that pending resizes may be
+// carried out. The watcher reports all changes to the volumes, not
+// only those that request a resize.
+func (sb *storageBackend) WatchModelVolumeResizes() StringsWatcher {
+	mb := sb.mb
+	return newCollectionWatcher(mb, colWCfg{
+		col: volumesC,
+		filter: func(id interface{}) bool {
+			k, err := mb.strictLocalID(id.(string))
+			if err != nil {
+				return false
+			}
+			return !strings.Contains(k, "/")
+		},
+	})
+}
+
+// WatchMachineVolumeResizes returns a StringsWatcher that notifies of
+// changes to volumes scoped to the specified machine, so that pending
+// resizes may be carried out.
+func (sb *storageBackend) WatchMachineVolumeResizes(m names.MachineTag) StringsWatcher {
+	return sb.watchMachineStorageResizes(m, volumesC)
+}
+
+// WatchMachineFilesystemResizes returns a StringsWatcher that notifies
+// of changes to filesystems scoped to the specified machine, so that
+// filesystems whose backing volumes have been resized may be grown.
+func (sb *storageBackend) WatchMachineFilesystemResizes(m names.MachineTag) StringsWatcher {
+	return sb.watchMachineStorageResizes(m, filesystemsC)
+}
+
+func (sb *storageBackend) watchMachineStorageResizes(m names.MachineTag, collection string) StringsWatcher {
+	mb := sb.mb
+	prefix := m.Id() + "/"
+	return newCollectionWatcher(mb, colWCfg{
+		col: collection,
+		filter: func(id interface{}) bool {
+			k, err := mb.strictLocalID(id.(string))
+			if err != nil {
+				return false
+			}
+			return strings.HasPrefix(k, prefix) && !strings.Contains(k[len(prefix):], "/")
+		},
+	})
+}
+
 // WatchMachineAttachmentsPlans returns a StringsWatcher that notifies machine agents
 // that a volume has been attached to their instance by the environment provider.
 // This allows machine agents to do extra initialization to the volume, in cases
@@ -2095,6 +2176,12 @@ func (u *Unit) WatchApplicationConfigSettings() (NotifyWatcher, error) {
 	return newEntityWatcher(u.st, settingsC, u.st.docID(applicationConfigKey)), nil
 }
 
+// WatchApplicationConfig returns a watcher for observing changes to the
+// application's own config settings, rather than its charm config.
+func (a *Application) WatchApplicationConfig() NotifyWatcher {
+	return newEntityWatcher(a.st, settingsC, a.st.docID(a.applicationConfigKey()))
+}
+
 // WatchConfigSettingsHash returns a watcher that yields a hash of the
 // unit's charm config settings whenever they are changed. The
 // returned watcher will be valid only while the application's charm
@@ -243,6 +243,12 @@ type VolumeParams struct {
 	// storage provider supports tags.
 	ResourceTags map[string]string
 
+	// SnapshotId, if non-empty, is the provider ID of the volume
+	// snapshot from which the volume should be created. Only storage
+	// providers whose volume sources implement VolumeSnapshotter
+	// will be presented with a snapshot ID.
+	SnapshotId string
+
 	// Attachment identifies the machine that the volume should be attached
 	// to initially, or nil if the volume should not be attached to any
 	// machine. Some providers, such as MAAS, do not support dynamic
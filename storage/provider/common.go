@@ -16,6 +16,8 @@ var (
 		LoopProviderType:   &loopProvider{logAndExec},
 		RootfsProviderType: &rootfsProvider{logAndExec},
 		TmpfsProviderType:  &tmpfsProvider{logAndExec},
+		LVMProviderType:    &lvmProvider{logAndExec},
+		ZFSProviderType:    &zfsProvider{logAndExec},
 	}
 )
 
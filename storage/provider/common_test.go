@@ -35,6 +35,8 @@ func (s *providerCommonSuite) TestCommonProvidersExported(c *gc.C) {
 		provider.LoopProviderType,
 		provider.RootfsProviderType,
 		provider.TmpfsProviderType,
+		provider.LVMProviderType,
+		provider.ZFSProviderType,
 	})
 }
 
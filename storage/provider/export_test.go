@@ -143,3 +143,11 @@ func TmpfsFilesystemSource(etcDir, storageDir string, run func(string, ...string
 func TmpfsProvider(run func(string, ...string) (string, error)) storage.Provider {
 	return &tmpfsProvider{run}
 }
+
+func LVMProvider(run func(string, ...string) (string, error)) storage.Provider {
+	return &lvmProvider{run}
+}
+
+func ZFSProvider(run func(string, ...string) (string, error)) storage.Provider {
+	return &zfsProvider{run}
+}
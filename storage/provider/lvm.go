@@ -0,0 +1,509 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// LVMProviderType is the storage provider type for logical
+	// volumes carved from an LVM volume group on the machine.
+	LVMProviderType = storage.ProviderType("lvm")
+
+	// LVMVolumeGroup is the name of the pool attribute holding the
+	// LVM volume group that volumes are created in.
+	LVMVolumeGroup = "volume-group"
+
+	// LVMThinPool is the name of the pool attribute holding the
+	// optional thin pool that volumes are created in. If it is not
+	// specified, volumes are fully allocated in the volume group.
+	LVMThinPool = "thin-pool"
+
+	// lvmVolumePrefix is prepended to the names of the logical
+	// volumes created by Juju, to distinguish them from volumes
+	// created by other means.
+	lvmVolumePrefix = "juju-"
+
+	// lvmSnapshotPrefix is prepended to the names of the snapshot
+	// logical volumes created by Juju.
+	lvmSnapshotPrefix = "juju-snapshot-"
+)
+
+// lvmNameRE matches valid LVM volume group and logical volume names.
+var lvmNameRE = regexp.MustCompile(`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`)
+
+// lvmProvider creates volume sources which use LVM logical volumes.
+type lvmProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var _ storage.Provider = (*lvmProvider)(nil)
+
+func (*lvmProvider) ValidateForK8s(map[string]any) error {
+	return errors.NotValidf("storage provider type %q", LVMProviderType)
+}
+
+// ValidateConfig is defined on the Provider interface.
+func (*lvmProvider) ValidateConfig(cfg *storage.Config) error {
+	vg, ok := cfg.ValueString(LVMVolumeGroup)
+	if !ok || vg == "" {
+		return errors.Errorf("%s must be specified", LVMVolumeGroup)
+	}
+	if !lvmNameRE.MatchString(vg) {
+		return errors.NotValidf("%s %q", LVMVolumeGroup, vg)
+	}
+	if pool, ok := cfg.ValueString(LVMThinPool); ok && pool != "" && !lvmNameRE.MatchString(pool) {
+		return errors.NotValidf("%s %q", LVMThinPool, pool)
+	}
+	return nil
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *lvmProvider) VolumeSource(sourceConfig *storage.Config) (storage.VolumeSource, error) {
+	if err := p.ValidateConfig(sourceConfig); err != nil {
+		return nil, err
+	}
+	vg, _ := sourceConfig.ValueString(LVMVolumeGroup)
+	pool, _ := sourceConfig.ValueString(LVMThinPool)
+	return &lvmVolumeSource{
+		run:         p.run,
+		volumeGroup: vg,
+		thinPool:    pool,
+	}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (*lvmProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*lvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*lvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*lvmProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is defined on the Provider interface.
+func (*lvmProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is defined on the Provider interface.
+func (*lvmProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// lvmVolumeSource creates and manages logical volumes in a single
+// LVM volume group, optionally backed by a thin pool.
+type lvmVolumeSource struct {
+	run         runCommandFunc
+	volumeGroup string
+	thinPool    string
+}
+
+var _ storage.VolumeSource = (*lvmVolumeSource)(nil)
+
+// lvmVolumeName returns the name of the logical volume for the
+// volume with the specified tag.
+func lvmVolumeName(tag names.VolumeTag) string {
+	return lvmVolumePrefix + tag.String()
+}
+
+// lvPath returns the volume group qualified name of the logical
+// volume, as accepted by the LVM commands.
+func (s *lvmVolumeSource) lvPath(volumeId string) string {
+	return s.volumeGroup + "/" + volumeId
+}
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) CreateVolumes(ctx context.ProviderCallContext, args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	existing, err := s.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.CreateVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := s.createVolume(arg, existing)
+		if err != nil {
+			results[i].Error = errors.Annotate(err, "creating volume")
+			continue
+		}
+		results[i].Volume = volume
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) createVolume(arg storage.VolumeParams, existing map[string]uint64) (*storage.Volume, error) {
+	name := lvmVolumeName(arg.Tag)
+	size, ok := existing[name]
+	if !ok {
+		var err error
+		if arg.SnapshotId != "" {
+			size, err = s.restoreVolume(name, arg.Size, arg.SnapshotId)
+		} else {
+			err = s.createLogicalVolume(name, arg.Size)
+			size = arg.Size
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &storage.Volume{
+		arg.Tag,
+		storage.VolumeInfo{
+			VolumeId: name,
+			Size:     size,
+		},
+	}, nil
+}
+
+// createLogicalVolume creates an empty logical volume with the
+// specified name and size in MiB.
+func (s *lvmVolumeSource) createLogicalVolume(name string, size uint64) error {
+	args := []string{"--yes", "--name", name}
+	if s.thinPool != "" {
+		args = append(args,
+			"--virtualsize", fmt.Sprintf("%dm", size),
+			"--thin", s.lvPath(s.thinPool),
+		)
+	} else {
+		args = append(args, "--size", fmt.Sprintf("%dm", size), s.volumeGroup)
+	}
+	if _, err := s.run("lvcreate", args...); err != nil {
+		return errors.Annotatef(err, "creating logical volume %q", s.lvPath(name))
+	}
+	return nil
+}
+
+// restoreVolume creates a logical volume with the specified name
+// from a snapshot, returning its size in MiB. Thin snapshots are
+// restored by taking a writable snapshot of the snapshot; otherwise
+// the snapshot's contents are copied into a new logical volume.
+func (s *lvmVolumeSource) restoreVolume(name string, size uint64, snapshotId string) (uint64, error) {
+	snapshots, err := s.listLogicalVolumes(lvmSnapshotPrefix)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	snapshotSize, ok := snapshots[snapshotId]
+	if !ok {
+		return 0, errors.NotFoundf("snapshot %q", s.lvPath(snapshotId))
+	}
+	if s.thinPool != "" {
+		if _, err := s.run(
+			"lvcreate", "--yes", "--snapshot", "--setactivationskip", "n",
+			"--name", name, s.lvPath(snapshotId),
+		); err != nil {
+			return 0, errors.Annotatef(err, "restoring logical volume %q", s.lvPath(name))
+		}
+		return snapshotSize, nil
+	}
+	if snapshotSize > size {
+		size = snapshotSize
+	}
+	if err := s.createLogicalVolume(name, size); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if _, err := s.run(
+		"dd", "if=/dev/"+s.lvPath(snapshotId), "of=/dev/"+s.lvPath(name),
+		"bs=4M", "conv=fsync",
+	); err != nil {
+		return 0, errors.Annotatef(err, "restoring logical volume %q", s.lvPath(name))
+	}
+	return size, nil
+}
+
+// logicalVolumes returns the sizes, in MiB, of the logical volumes
+// created by Juju in the volume group, keyed by name. Snapshots are
+// not included.
+func (s *lvmVolumeSource) logicalVolumes() (map[string]uint64, error) {
+	volumes, err := s.listLogicalVolumes(lvmVolumePrefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name := range volumes {
+		if strings.HasPrefix(name, lvmSnapshotPrefix) {
+			delete(volumes, name)
+		}
+	}
+	return volumes, nil
+}
+
+// listLogicalVolumes returns the sizes, in MiB, of the logical
+// volumes in the volume group whose names have the specified
+// prefix, keyed by name.
+func (s *lvmVolumeSource) listLogicalVolumes(prefix string) (map[string]uint64, error) {
+	output, err := s.run(
+		"lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"--options", "lv_name,lv_size", s.volumeGroup,
+	)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing logical volumes in %q", s.volumeGroup)
+	}
+	volumes := make(map[string]uint64)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, errors.Errorf("unexpected lvs output %q", line)
+		}
+		if !strings.HasPrefix(fields[0], prefix) {
+			continue
+		}
+		size, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing size of %q", fields[0])
+		}
+		volumes[fields[0]] = uint64(size)
+	}
+	return volumes, nil
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
+	volumes, err := s.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeIds := make([]string, 0, len(volumes))
+	for name := range volumes {
+		volumeIds = append(volumeIds, name)
+	}
+	return volumeIds, nil
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DescribeVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	volumes, err := s.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		size, ok := volumes[volumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("logical volume %q", s.lvPath(volumeId))
+			continue
+		}
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DestroyVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	volumes, err := s.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		if !strings.HasPrefix(volumeId, lvmVolumePrefix) || !lvmNameRE.MatchString(volumeId) {
+			results[i] = errors.Errorf("invalid lvm volume ID %q", volumeId)
+			continue
+		}
+		if _, ok := volumes[volumeId]; !ok {
+			// Already removed.
+			continue
+		}
+		if _, err := s.run("lvremove", "--yes", s.lvPath(volumeId)); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results, nil
+}
+
+// ReleaseVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) ReleaseVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	return make([]error, len(volumeIds)), nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	// ValidateVolumeParams may be called on a machine other than the
+	// machine where the volume will be created, so we cannot check
+	// the free space in the volume group until we get to CreateVolumes.
+	return nil
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) AttachVolumes(ctx context.ProviderCallContext, args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (*storage.VolumeAttachment, error) {
+	// Activating an active volume is a no-op, so this is idempotent.
+	lvPath := s.lvPath(arg.VolumeId)
+	if _, err := s.run("lvchange", "--activate", "y", lvPath); err != nil {
+		return nil, errors.Annotatef(err, "activating logical volume %q", lvPath)
+	}
+	if arg.ReadOnly {
+		_, err := s.run("lvchange", "--permission", "r", lvPath)
+		if err != nil && !strings.Contains(err.Error(), "already read only") {
+			return nil, errors.Annotatef(err, "making logical volume %q read only", lvPath)
+		}
+	}
+	return &storage.VolumeAttachment{
+		arg.Volume,
+		arg.Machine,
+		storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/" + lvPath,
+			ReadOnly:   arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DetachVolumes(ctx context.ProviderCallContext, args []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		lvPath := s.lvPath(arg.VolumeId)
+		if _, err := s.run("lvchange", "--activate", "n", lvPath); err != nil {
+			results[i] = errors.Annotatef(err, "detaching volume %s", arg.Volume.Id())
+		}
+	}
+	return results, nil
+}
+
+var _ storage.VolumeSnapshotter = (*lvmVolumeSource)(nil)
+
+// lvmSnapshotName returns the name of the logical volume for the
+// volume snapshot with the specified ID.
+func lvmSnapshotName(id string) string {
+	return lvmSnapshotPrefix + strings.NewReplacer("/", "-", "@", ".").Replace(id)
+}
+
+// CreateVolumeSnapshots is defined on the VolumeSnapshotter interface.
+//
+// Snapshots of volumes in a thin pool are themselves thin volumes,
+// and are independent of the volume they were taken from. Otherwise,
+// the snapshot is allocated enough space to hold a full copy of the
+// volume, and is removed along with it.
+func (s *lvmVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	volumes, err := s.listLogicalVolumes(lvmVolumePrefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		size, ok := volumes[arg.VolumeId]
+		if !ok || strings.HasPrefix(arg.VolumeId, lvmSnapshotPrefix) {
+			results[i].Error = errors.NotFoundf("logical volume %q", s.lvPath(arg.VolumeId))
+			continue
+		}
+		name := lvmSnapshotName(arg.Id)
+		if _, ok := volumes[name]; !ok {
+			args := []string{"--yes", "--snapshot", "--name", name}
+			if s.thinPool != "" {
+				args = append(args, "--setactivationskip", "n")
+			} else {
+				args = append(args, "--size", fmt.Sprintf("%dm", size))
+			}
+			args = append(args, s.lvPath(arg.VolumeId))
+			if _, err := s.run("lvcreate", args...); err != nil {
+				results[i].Error = errors.Annotatef(err, "creating snapshot of %q", s.lvPath(arg.VolumeId))
+				continue
+			}
+		}
+		results[i].Snapshot = &storage.VolumeSnapshot{
+			Id: arg.Id,
+			VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+				SnapshotId: name,
+				Size:       size,
+			},
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumeSnapshots is defined on the VolumeSnapshotter interface.
+func (s *lvmVolumeSource) DestroyVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	snapshots, err := s.listLogicalVolumes(lvmSnapshotPrefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		if !strings.HasPrefix(snapshotId, lvmSnapshotPrefix) || !lvmNameRE.MatchString(snapshotId) {
+			results[i] = errors.Errorf("invalid lvm snapshot ID %q", snapshotId)
+			continue
+		}
+		if _, ok := snapshots[snapshotId]; !ok {
+			// Already removed, possibly along with its volume.
+			continue
+		}
+		if _, err := s.run("lvremove", "--yes", s.lvPath(snapshotId)); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", snapshotId)
+		}
+	}
+	return results, nil
+}
+
+var _ storage.VolumeResizer = (*lvmVolumeSource)(nil)
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+//
+// Logical volumes are only ever grown; a volume that is already at
+// least the requested size is left as it is.
+func (s *lvmVolumeSource) ResizeVolumes(ctx context.ProviderCallContext, args []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	volumes, err := s.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.ResizeVolumesResult, len(args))
+	for i, arg := range args {
+		size, ok := volumes[arg.VolumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("logical volume %q", s.lvPath(arg.VolumeId))
+			continue
+		}
+		if size < arg.Size {
+			if _, err := s.run(
+				"lvextend", "--size", fmt.Sprintf("%dm", arg.Size), s.lvPath(arg.VolumeId),
+			); err != nil {
+				results[i].Error = errors.Annotatef(err, "extending logical volume %q", s.lvPath(arg.VolumeId))
+				continue
+			}
+			size = arg.Size
+		}
+		results[i].Info = &storage.VolumeInfo{
+			VolumeId: arg.VolumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
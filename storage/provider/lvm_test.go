@@ -0,0 +1,366 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"errors"
+
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&lvmSuite{})
+
+type lvmSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+
+	callCtx context.ProviderCallContext
+}
+
+func (s *lvmSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.commands = &mockRunCommand{c: c}
+	s.callCtx = context.NewEmptyCloudCallContext()
+}
+
+func (s *lvmSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *lvmSuite) volumeSource(c *gc.C, attrs map[string]interface{}) storage.VolumeSource {
+	p := provider.LVMProvider(s.commands.run)
+	cfg, err := storage.NewConfig("name", provider.LVMProviderType, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+func (s *lvmSuite) TestValidateConfig(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	for _, t := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{},
+		err:   "volume-group must be specified",
+	}, {
+		attrs: map[string]interface{}{"volume-group": "-vg"},
+		err:   `volume-group "-vg" not valid`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "thin-pool": "a/b"},
+		err:   `thin-pool "a/b" not valid`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg0", "thin-pool": "pool0"},
+	}} {
+		cfg, err := storage.NewConfig("name", provider.LVMProviderType, t.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if t.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.err)
+		}
+	}
+}
+
+func (s *lvmSuite) TestSupports(c *gc.C) {
+	p := provider.LVMProvider(s.commands.run)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *lvmSuite) TestCreateVolumesThin(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{
+		"volume-group": "vg0",
+		"thin-pool":    "pool0",
+	})
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"--options", "lv_name,lv_size", "vg0").respond(`
+  juju-volume-1 1024.00
+  pool0         8192.00
+`, nil)
+	s.commands.expect("lvcreate", "--yes", "--name", "juju-volume-0",
+		"--virtualsize", "2048m", "--thin", "vg0/pool0")
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 2048,
+	}, {
+		Tag:  names.NewVolumeTag("1"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumesResult{{
+		Volume: &storage.Volume{
+			names.NewVolumeTag("0"),
+			storage.VolumeInfo{VolumeId: "juju-volume-0", Size: 2048},
+		},
+	}, {
+		Volume: &storage.Volume{
+			names.NewVolumeTag("1"),
+			storage.VolumeInfo{VolumeId: "juju-volume-1", Size: 1024},
+		},
+	}})
+}
+
+func (s *lvmSuite) TestCreateVolumesThick(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"--options", "lv_name,lv_size", "vg0")
+	s.commands.expect("lvcreate", "--yes", "--name", "juju-volume-0-1",
+		"--size", "512m", "vg0").respond("", errors.New("insufficient free space"))
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/1"),
+		Size: 512,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		`creating volume: creating logical volume "vg0/juju-volume-0-1": insufficient free space`)
+}
+
+func (s *lvmSuite) TestDescribeVolumes(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"--options", "lv_name,lv_size", "vg0").respond("  juju-volume-0 100.00\n  root 2000.00\n", nil)
+
+	results, err := source.DescribeVolumes(s.callCtx, []string{"juju-volume-0", "juju-volume-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{VolumeId: "juju-volume-0", Size: 100})
+	c.Assert(results[1].Error, gc.ErrorMatches, `logical volume "vg0/juju-volume-1" not found`)
+}
+
+func (s *lvmSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"--options", "lv_name,lv_size", "vg0").respond("  juju-volume-0 100.00\n", nil)
+	s.commands.expect("lvremove", "--yes", "vg0/juju-volume-0")
+
+	errs, err := source.DestroyVolumes(s.callCtx, []string{"juju-volume-0", "juju-volume-1", "root"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `invalid lvm volume ID "root"`)
+}
+
+func (s *lvmSuite) TestAttachVolumes(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.commands.expect("lvchange", "--activate", "y", "vg0/juju-volume-0")
+	s.commands.expect("lvchange", "--activate", "y", "vg0/juju-volume-1")
+	s.commands.expect("lvchange", "--permission", "r", "vg0/juju-volume-1").respond(
+		"", errors.New("Logical volume vg0/juju-volume-1 is already read only."),
+	)
+
+	results, err := source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "juju-volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}, {
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "juju-volume-1",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachVolumesResult{{
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("0"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{DeviceLink: "/dev/vg0/juju-volume-0"},
+		},
+	}, {
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("1"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{DeviceLink: "/dev/vg0/juju-volume-1", ReadOnly: true},
+		},
+	}})
+}
+
+func (s *lvmSuite) TestDetachVolumes(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.commands.expect("lvchange", "--activate", "n", "vg0/juju-volume-0").respond("", errors.New("in use"))
+
+	errs, err := source.DetachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "juju-volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], gc.ErrorMatches, "detaching volume 0: in use")
+}
+
+func (s *lvmSuite) expectLVs(output string) {
+	s.commands.expect("lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"--options", "lv_name,lv_size", "vg0").respond(output, nil)
+}
+
+func (s *lvmSuite) TestCreateVolumeSnapshotsThin(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{
+		"volume-group": "vg0",
+		"thin-pool":    "pool0",
+	})
+	s.expectLVs("  juju-volume-0-1 1024.00\n  pool0 8192.00\n")
+	s.commands.expect("lvcreate", "--yes", "--snapshot", "--name", "juju-snapshot-0-1.2",
+		"--setactivationskip", "n", "vg0/juju-volume-0-1")
+
+	results, err := source.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Id:       "0/1@2",
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "juju-volume-0-1",
+	}, {
+		Id:       "0/2@1",
+		Volume:   names.NewVolumeTag("0/2"),
+		VolumeId: "juju-volume-0-2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Snapshot, jc.DeepEquals, &storage.VolumeSnapshot{
+		Id: "0/1@2",
+		VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+			SnapshotId: "juju-snapshot-0-1.2",
+			Size:       1024,
+		},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `logical volume "vg0/juju-volume-0-2" not found`)
+}
+
+func (s *lvmSuite) TestCreateVolumeSnapshotsThick(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.expectLVs("  juju-volume-0-1 1024.00\n")
+	s.commands.expect("lvcreate", "--yes", "--snapshot", "--name", "juju-snapshot-0-1.2",
+		"--size", "1024m", "vg0/juju-volume-0-1")
+
+	results, err := source.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Id:       "0/1@2",
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "juju-volume-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+}
+
+func (s *lvmSuite) TestCreateVolumesFromSnapshotThin(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{
+		"volume-group": "vg0",
+		"thin-pool":    "pool0",
+	})
+	s.expectLVs("  juju-snapshot-0-1.2 1024.00\n")
+	s.expectLVs("  juju-snapshot-0-1.2 1024.00\n")
+	s.commands.expect("lvcreate", "--yes", "--snapshot", "--setactivationskip", "n",
+		"--name", "juju-volume-3", "vg0/juju-snapshot-0-1.2")
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("3"),
+		Size:       512,
+		SnapshotId: "juju-snapshot-0-1.2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumesResult{{
+		Volume: &storage.Volume{
+			Tag:        names.NewVolumeTag("3"),
+			VolumeInfo: storage.VolumeInfo{VolumeId: "juju-volume-3", Size: 1024},
+		},
+	}})
+}
+
+func (s *lvmSuite) TestCreateVolumesFromSnapshotThick(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.expectLVs("  juju-snapshot-0-1.2 1024.00\n")
+	s.expectLVs("  juju-snapshot-0-1.2 1024.00\n")
+	s.commands.expect("lvcreate", "--yes", "--name", "juju-volume-3", "--size", "2048m", "vg0")
+	s.commands.expect("dd", "if=/dev/vg0/juju-snapshot-0-1.2", "of=/dev/vg0/juju-volume-3", "bs=4M", "conv=fsync")
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("3"),
+		Size:       2048,
+		SnapshotId: "juju-snapshot-0-1.2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumesResult{{
+		Volume: &storage.Volume{
+			Tag:        names.NewVolumeTag("3"),
+			VolumeInfo: storage.VolumeInfo{VolumeId: "juju-volume-3", Size: 2048},
+		},
+	}})
+}
+
+func (s *lvmSuite) TestCreateVolumesFromSnapshotNotFound(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.expectLVs("")
+	s.expectLVs("")
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("3"),
+		Size:       2048,
+		SnapshotId: "juju-snapshot-0-1.2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `creating volume: snapshot "vg0/juju-snapshot-0-1.2" not found`)
+}
+
+func (s *lvmSuite) TestDestroyVolumeSnapshots(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.expectLVs("  juju-volume-0 100.00\n  juju-snapshot-0.1 100.00\n")
+	s.commands.expect("lvremove", "--yes", "vg0/juju-snapshot-0.1")
+
+	errs, err := source.(storage.VolumeSnapshotter).DestroyVolumeSnapshots(s.callCtx, []string{
+		"juju-snapshot-0.1", "juju-snapshot-0.2", "juju-volume-0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `invalid lvm snapshot ID "juju-volume-0"`)
+}
+
+func (s *lvmSuite) TestResizeVolumes(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"volume-group": "vg0"})
+	s.expectLVs("  juju-volume-0-1 1024.00\n  juju-volume-0-2 4096.00\n")
+	s.commands.expect("lvextend", "--size", "2048m", "vg0/juju-volume-0-1")
+
+	results, err := source.(storage.VolumeResizer).ResizeVolumes(s.callCtx, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0/1"),
+		VolumeId: "juju-volume-0-1",
+		Size:     2048,
+	}, {
+		Tag:      names.NewVolumeTag("0/2"),
+		VolumeId: "juju-volume-0-2",
+		Size:     2048,
+	}, {
+		Tag:      names.NewVolumeTag("0/3"),
+		VolumeId: "juju-volume-0-3",
+		Size:     2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0], jc.DeepEquals, storage.ResizeVolumesResult{
+		Info: &storage.VolumeInfo{VolumeId: "juju-volume-0-1", Size: 2048},
+	})
+	c.Assert(results[1], jc.DeepEquals, storage.ResizeVolumesResult{
+		Info: &storage.VolumeInfo{VolumeId: "juju-volume-0-2", Size: 4096},
+	})
+	c.Assert(results[2].Error, gc.ErrorMatches, `logical volume "vg0/juju-volume-0-3" not found`)
+}
@@ -93,17 +93,25 @@ func (s *managedFilesystemSource) createFilesystem(arg storage.FilesystemParams)
 		return nil, errors.Trace(err)
 	}
 	devicePath := devicePath(blockDevice)
-	if isDiskDevice(devicePath) {
-		if err := destroyPartitions(s.run, devicePath); err != nil {
-			return nil, errors.Trace(err)
+	if blockDevice.FilesystemType != "" {
+		// The volume already has a filesystem, e.g. because it was
+		// restored from a snapshot; preserve its contents.
+		logger.Infof("%s already has a %s filesystem, not formatting", devicePath, blockDevice.FilesystemType)
+	} else if isDiskDevice(devicePath) && hasFilesystem(s.run, partitionDevicePath(devicePath)) {
+		logger.Infof("%s already has a filesystem, not formatting", partitionDevicePath(devicePath))
+	} else {
+		if isDiskDevice(devicePath) {
+			if err := destroyPartitions(s.run, devicePath); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err := createPartition(s.run, devicePath); err != nil {
+				return nil, errors.Trace(err)
+			}
+			devicePath = partitionDevicePath(devicePath)
 		}
-		if err := createPartition(s.run, devicePath); err != nil {
+		if err := createFilesystem(s.run, devicePath); err != nil {
 			return nil, errors.Trace(err)
 		}
-		devicePath = partitionDevicePath(devicePath)
-	}
-	if err := createFilesystem(s.run, devicePath); err != nil {
-		return nil, errors.Trace(err)
 	}
 	return &storage.Filesystem{
 		arg.Tag,
@@ -197,6 +205,13 @@ func createPartition(run runCommandFunc, devicePath string) error {
 	return nil
 }
 
+// hasFilesystem reports whether the specified device exists and
+// contains a filesystem.
+func hasFilesystem(run runCommandFunc, devicePath string) bool {
+	output, err := run("blkid", "-p", "-o", "value", "-s", "TYPE", devicePath)
+	return err == nil && strings.TrimSpace(output) != ""
+}
+
 func createFilesystem(run runCommandFunc, devicePath string) error {
 	logger.Debugf("attempting to create filesystem on %q", devicePath)
 	mkfscmd := "mkfs." + defaultFilesystemType
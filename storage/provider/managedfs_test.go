@@ -4,6 +4,7 @@
 package provider_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -63,6 +64,7 @@ func (s *managedfsSuite) TestCreateFilesystems(c *gc.C) {
 	source := s.initSource(c)
 	// sda is (re)partitioned and the filesystem created
 	// on the partition.
+	s.commands.expect("blkid", "-p", "-o", "value", "-s", "TYPE", "/dev/sda1").respond("", errors.New("exit status 2"))
 	s.commands.expect("sgdisk", "--zap-all", "/dev/sda")
 	s.commands.expect("sgdisk", "-n", "1:0:-1", "/dev/sda")
 	s.commands.expect("mkfs.ext4", "/dev/sda1")
@@ -111,6 +113,37 @@ func (s *managedfsSuite) TestCreateFilesystems(c *gc.C) {
 	}})
 }
 
+func (s *managedfsSuite) TestCreateFilesystemsExisting(c *gc.C) {
+	source := s.initSource(c)
+	// sda has a partition with a filesystem, and xvdf1 has a
+	// filesystem, e.g. because they were restored from snapshots.
+	// Neither is formatted.
+	s.commands.expect("blkid", "-p", "-o", "value", "-s", "TYPE", "/dev/sda1").respond("ext4\n", nil)
+
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       2,
+	}
+	s.blockDevices[names.NewVolumeTag("1")] = storage.BlockDevice{
+		DeviceName:     "xvdf1",
+		Size:           3,
+		FilesystemType: "ext4",
+	}
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+		Size:   2,
+	}, {
+		Tag:    names.NewFilesystemTag("0/1"),
+		Volume: names.NewVolumeTag("1"),
+		Size:   3,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[1].Error, jc.ErrorIsNil)
+}
+
 func (s *managedfsSuite) TestCreateFilesystemsNoBlockDevice(c *gc.C) {
 	source := s.initSource(c)
 	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
@@ -0,0 +1,590 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// ZFSProviderType is the storage provider type for ZFS datasets
+	// and volumes created in a ZFS pool on the machine.
+	ZFSProviderType = storage.ProviderType("zfs")
+
+	// ZFSPool is the name of the pool attribute holding the ZFS pool,
+	// or parent dataset, that datasets and volumes are created in.
+	ZFSPool = "zfs-pool"
+
+	// zfsDatasetPrefix is prepended to the names of the datasets
+	// created by Juju, to distinguish them from datasets created
+	// by other means.
+	zfsDatasetPrefix = "juju-"
+
+	// zfsSnapshotPrefix is prepended to the names of the snapshots
+	// created by Juju.
+	zfsSnapshotPrefix = "juju-snapshot-"
+
+	zfsTypeFilesystem = "filesystem"
+	zfsTypeVolume     = "volume"
+)
+
+// zfsNameRE matches valid ZFS dataset names.
+var zfsNameRE = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.:-]*(/[a-zA-Z0-9_.:-]+)*$`)
+
+// zfsProvider creates volume and filesystem sources which use ZFS
+// volumes and datasets respectively.
+type zfsProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var _ storage.Provider = (*zfsProvider)(nil)
+
+func (*zfsProvider) ValidateForK8s(map[string]any) error {
+	return errors.NotValidf("storage provider type %q", ZFSProviderType)
+}
+
+// ValidateConfig is defined on the Provider interface.
+func (*zfsProvider) ValidateConfig(cfg *storage.Config) error {
+	pool, ok := cfg.ValueString(ZFSPool)
+	if !ok || pool == "" {
+		return errors.Errorf("%s must be specified", ZFSPool)
+	}
+	if !zfsNameRE.MatchString(pool) {
+		return errors.NotValidf("%s %q", ZFSPool, pool)
+	}
+	return nil
+}
+
+func (p *zfsProvider) newZFS(sourceConfig *storage.Config) (*zfsDatasets, error) {
+	if err := p.ValidateConfig(sourceConfig); err != nil {
+		return nil, err
+	}
+	pool, _ := sourceConfig.ValueString(ZFSPool)
+	return &zfsDatasets{run: p.run, pool: pool}, nil
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *zfsProvider) VolumeSource(sourceConfig *storage.Config) (storage.VolumeSource, error) {
+	datasets, err := p.newZFS(sourceConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &zfsVolumeSource{datasets}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *zfsProvider) FilesystemSource(sourceConfig *storage.Config) (storage.FilesystemSource, error) {
+	datasets, err := p.newZFS(sourceConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &zfsFilesystemSource{datasets}, nil
+}
+
+// Supports is defined on the Provider interface.
+func (*zfsProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock || k == storage.StorageKindFilesystem
+}
+
+// Scope is defined on the Provider interface.
+func (*zfsProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*zfsProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is defined on the Provider interface.
+func (*zfsProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is defined on the Provider interface.
+func (*zfsProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// zfsDatasets wraps the zfs commands used to manage the datasets
+// Juju creates directly beneath a ZFS pool.
+type zfsDatasets struct {
+	run  runCommandFunc
+	pool string
+}
+
+// path returns the pool qualified name of the dataset with the
+// specified ID.
+func (z *zfsDatasets) path(id string) string {
+	return z.pool + "/" + id
+}
+
+// validateId checks that the given dataset ID is one that was
+// created by Juju, and does not reach outside of the pool.
+func (z *zfsDatasets) validateId(id string) error {
+	if !strings.HasPrefix(id, zfsDatasetPrefix) || strings.Contains(id, "/") || !zfsNameRE.MatchString(id) {
+		return errors.Errorf("invalid zfs dataset ID %q", id)
+	}
+	return nil
+}
+
+// list returns the sizes, in MiB, of the datasets of the given type
+// that were created by Juju in the pool, keyed by ID. The size of a
+// volume is its volsize, and the size of a filesystem is its quota.
+func (z *zfsDatasets) list(datasetType string) (map[string]uint64, error) {
+	property := "quota"
+	if datasetType == zfsTypeVolume {
+		property = "volsize"
+	}
+	output, err := z.run(
+		"zfs", "list", "-H", "-p", "-d", "1",
+		"-t", datasetType, "-o", "name,"+property, z.pool,
+	)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing zfs datasets in %q", z.pool)
+	}
+	datasets := make(map[string]uint64)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, errors.Errorf("unexpected zfs output %q", line)
+		}
+		id := strings.TrimPrefix(fields[0], z.pool+"/")
+		if id == fields[0] || !strings.HasPrefix(id, zfsDatasetPrefix) {
+			continue
+		}
+		size, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing size of %q", fields[0])
+		}
+		datasets[id] = size / (1024 * 1024)
+	}
+	return datasets, nil
+}
+
+// destroy destroys the datasets with the specified IDs, ignoring
+// any which no longer exist.
+func (z *zfsDatasets) destroy(datasetType string, ids []string) ([]error, error) {
+	existing, err := z.list(datasetType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]error, len(ids))
+	for i, id := range ids {
+		if err := z.validateId(id); err != nil {
+			results[i] = err
+			continue
+		}
+		if _, ok := existing[id]; !ok {
+			// Already destroyed.
+			continue
+		}
+		if _, err := z.run("zfs", "destroy", z.path(id)); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", id)
+		}
+	}
+	return results, nil
+}
+
+// setReadOnly sets the readonly property of the specified dataset.
+func (z *zfsDatasets) setReadOnly(id string, readOnly bool) error {
+	value := "off"
+	if readOnly {
+		value = "on"
+	}
+	if _, err := z.run("zfs", "set", "readonly="+value, z.path(id)); err != nil {
+		return errors.Annotatef(err, "setting readonly on %q", z.path(id))
+	}
+	return nil
+}
+
+// zfsVolumeSource creates ZFS volumes (zvols), which are exposed
+// to the machine as block devices.
+type zfsVolumeSource struct {
+	*zfsDatasets
+}
+
+var _ storage.VolumeSource = (*zfsVolumeSource)(nil)
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (s *zfsVolumeSource) CreateVolumes(ctx context.ProviderCallContext, args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	existing, err := s.list(zfsTypeVolume)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.CreateVolumesResult, len(args))
+	for i, arg := range args {
+		id := zfsDatasetPrefix + arg.Tag.String()
+		size, ok := existing[id]
+		if !ok && arg.SnapshotId != "" {
+			// Restored volumes are clones of the snapshot,
+			// and so have the size of the snapshotted volume.
+			origin, err := s.snapshotOrigin(arg.SnapshotId)
+			if err != nil {
+				results[i].Error = errors.Trace(err)
+				continue
+			}
+			if _, err := s.run("zfs", "clone", s.path(arg.SnapshotId), s.path(id)); err != nil {
+				results[i].Error = errors.Annotatef(err, "restoring zfs volume %q", s.path(id))
+				continue
+			}
+			size = existing[origin]
+		} else if !ok {
+			if _, err := s.run("zfs", "create", "-V", fmt.Sprintf("%dM", arg.Size), s.path(id)); err != nil {
+				results[i].Error = errors.Annotatef(err, "creating zfs volume %q", s.path(id))
+				continue
+			}
+			size = arg.Size
+		}
+		results[i].Volume = &storage.Volume{
+			arg.Tag,
+			storage.VolumeInfo{
+				VolumeId: id,
+				Size:     size,
+			},
+		}
+	}
+	return results, nil
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (s *zfsVolumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
+	volumes, err := s.list(zfsTypeVolume)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeIds := make([]string, 0, len(volumes))
+	for id := range volumes {
+		volumeIds = append(volumeIds, id)
+	}
+	return volumeIds, nil
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (s *zfsVolumeSource) DescribeVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	volumes, err := s.list(zfsTypeVolume)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		size, ok := volumes[volumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("zfs volume %q", s.path(volumeId))
+			continue
+		}
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+func (s *zfsVolumeSource) DestroyVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	return s.destroy(zfsTypeVolume, volumeIds)
+}
+
+// ReleaseVolumes is defined on the VolumeSource interface.
+func (s *zfsVolumeSource) ReleaseVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	return make([]error, len(volumeIds)), nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (s *zfsVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	// ValidateVolumeParams may be called on a machine other than the
+	// machine where the volume will be created, so we cannot check
+	// the free space in the pool until we get to CreateVolumes.
+	return nil
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+func (s *zfsVolumeSource) AttachVolumes(ctx context.ProviderCallContext, args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		// ZFS volumes are always available as block devices
+		// on the machine; there is nothing to attach.
+		if err := s.setReadOnly(arg.VolumeId, arg.ReadOnly); err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = &storage.VolumeAttachment{
+			arg.Volume,
+			arg.Machine,
+			storage.VolumeAttachmentInfo{
+				DeviceLink: "/dev/zvol/" + s.path(arg.VolumeId),
+				ReadOnly:   arg.ReadOnly,
+			},
+		}
+	}
+	return results, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (s *zfsVolumeSource) DetachVolumes(ctx context.ProviderCallContext, args []storage.VolumeAttachmentParams) ([]error, error) {
+	// ZFS volumes cannot be detached from the machine hosting the
+	// pool; they are removed from the machine when destroyed.
+	return make([]error, len(args)), nil
+}
+
+var _ storage.VolumeSnapshotter = (*zfsVolumeSource)(nil)
+
+// snapshotOrigin returns the ID of the volume that the snapshot with
+// the specified ID was taken from, checking that both were created
+// by Juju.
+func (s *zfsVolumeSource) snapshotOrigin(snapshotId string) (string, error) {
+	parts := strings.SplitN(snapshotId, "@", 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], zfsSnapshotPrefix) || !zfsNameRE.MatchString(parts[1]) {
+		return "", errors.Errorf("invalid zfs snapshot ID %q", snapshotId)
+	}
+	if err := s.validateId(parts[0]); err != nil {
+		return "", errors.Trace(err)
+	}
+	return parts[0], nil
+}
+
+// snapshots returns the IDs of the snapshots in the pool.
+func (s *zfsVolumeSource) snapshots() (map[string]bool, error) {
+	output, err := s.run("zfs", "list", "-H", "-r", "-t", "snapshot", "-o", "name", s.pool)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing zfs snapshots in %q", s.pool)
+	}
+	snapshots := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		if id := strings.TrimPrefix(strings.TrimSpace(line), s.pool+"/"); id != "" {
+			snapshots[id] = true
+		}
+	}
+	return snapshots, nil
+}
+
+// CreateVolumeSnapshots is defined on the VolumeSnapshotter interface.
+//
+// ZFS snapshots are stored within the volume they were taken from,
+// so a volume cannot be destroyed until its snapshots, and any
+// volumes restored from them, have been removed.
+func (s *zfsVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	volumes, err := s.list(zfsTypeVolume)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		size, ok := volumes[arg.VolumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("zfs volume %q", s.path(arg.VolumeId))
+			continue
+		}
+		snapshotId := arg.VolumeId + "@" + zfsSnapshotPrefix + strings.NewReplacer("/", "-", "@", ".").Replace(arg.Id)
+		if _, err := s.run("zfs", "snapshot", s.path(snapshotId)); err != nil && !strings.Contains(err.Error(), "dataset already exists") {
+			results[i].Error = errors.Annotatef(err, "creating snapshot of %q", s.path(arg.VolumeId))
+			continue
+		}
+		results[i].Snapshot = &storage.VolumeSnapshot{
+			Id: arg.Id,
+			VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+				SnapshotId: snapshotId,
+				Size:       size,
+			},
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumeSnapshots is defined on the VolumeSnapshotter interface.
+//
+// Snapshots from which volumes have been restored are marked for
+// deferred destruction, and destroyed by ZFS when those volumes are.
+func (s *zfsVolumeSource) DestroyVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	existing, err := s.snapshots()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		if _, err := s.snapshotOrigin(snapshotId); err != nil {
+			results[i] = err
+			continue
+		}
+		if !existing[snapshotId] {
+			// Already destroyed.
+			continue
+		}
+		if _, err := s.run("zfs", "destroy", "-d", s.path(snapshotId)); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", snapshotId)
+		}
+	}
+	return results, nil
+}
+
+var _ storage.VolumeResizer = (*zfsVolumeSource)(nil)
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+//
+// ZFS volumes are only ever grown; a volume that is already at
+// least the requested size is left as it is.
+func (s *zfsVolumeSource) ResizeVolumes(ctx context.ProviderCallContext, args []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	volumes, err := s.list(zfsTypeVolume)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.ResizeVolumesResult, len(args))
+	for i, arg := range args {
+		if err := s.validateId(arg.VolumeId); err != nil {
+			results[i].Error = err
+			continue
+		}
+		size, ok := volumes[arg.VolumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("zfs volume %q", s.path(arg.VolumeId))
+			continue
+		}
+		if size < arg.Size {
+			if _, err := s.run(
+				"zfs", "set", fmt.Sprintf("volsize=%dM", arg.Size), s.path(arg.VolumeId),
+			); err != nil {
+				results[i].Error = errors.Annotatef(err, "resizing zfs volume %q", s.path(arg.VolumeId))
+				continue
+			}
+			size = arg.Size
+		}
+		results[i].Info = &storage.VolumeInfo{
+			VolumeId: arg.VolumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
+
+// zfsFilesystemSource creates ZFS datasets, whose size is limited
+// by a quota, and mounts them at the requested location.
+type zfsFilesystemSource struct {
+	*zfsDatasets
+}
+
+var _ storage.FilesystemSource = (*zfsFilesystemSource)(nil)
+
+// ValidateFilesystemParams is defined on the FilesystemSource interface.
+func (s *zfsFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
+	// ValidateFilesystemParams may be called on a machine other than
+	// the machine where the filesystem will be created, so we cannot
+	// check the free space in the pool until we get to CreateFilesystems.
+	return nil
+}
+
+// CreateFilesystems is defined on the FilesystemSource interface.
+func (s *zfsFilesystemSource) CreateFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemParams) ([]storage.CreateFilesystemsResult, error) {
+	existing, err := s.list(zfsTypeFilesystem)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.CreateFilesystemsResult, len(args))
+	for i, arg := range args {
+		id := zfsDatasetPrefix + arg.Tag.String()
+		size, ok := existing[id]
+		if !ok {
+			// The dataset is not mounted until it is attached.
+			if _, err := s.run(
+				"zfs", "create",
+				"-o", "mountpoint=none",
+				"-o", fmt.Sprintf("quota=%dM", arg.Size),
+				s.path(id),
+			); err != nil {
+				results[i].Error = errors.Annotatef(err, "creating zfs dataset %q", s.path(id))
+				continue
+			}
+			size = arg.Size
+		}
+		results[i].Filesystem = &storage.Filesystem{
+			arg.Tag,
+			arg.Volume,
+			storage.FilesystemInfo{
+				FilesystemId: id,
+				Size:         size,
+			},
+		}
+	}
+	return results, nil
+}
+
+// DestroyFilesystems is defined on the FilesystemSource interface.
+func (s *zfsFilesystemSource) DestroyFilesystems(ctx context.ProviderCallContext, filesystemIds []string) ([]error, error) {
+	return s.destroy(zfsTypeFilesystem, filesystemIds)
+}
+
+// ReleaseFilesystems is defined on the FilesystemSource interface.
+func (s *zfsFilesystemSource) ReleaseFilesystems(ctx context.ProviderCallContext, filesystemIds []string) ([]error, error) {
+	return make([]error, len(filesystemIds)), nil
+}
+
+// AttachFilesystems is defined on the FilesystemSource interface.
+func (s *zfsFilesystemSource) AttachFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemAttachmentParams) ([]storage.AttachFilesystemsResult, error) {
+	results := make([]storage.AttachFilesystemsResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachFilesystem(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching filesystem %v", arg.Filesystem.Id())
+			continue
+		}
+		results[i].FilesystemAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *zfsFilesystemSource) attachFilesystem(arg storage.FilesystemAttachmentParams) (*storage.FilesystemAttachment, error) {
+	if arg.Path == "" {
+		return nil, errNoMountPoint
+	}
+	if err := s.validateId(arg.FilesystemId); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := s.setReadOnly(arg.FilesystemId, arg.ReadOnly); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Setting the mountpoint mounts the dataset, creating the mount
+	// point directory if necessary. Setting it to its current value
+	// is a no-op, so this is idempotent.
+	dataset := s.path(arg.FilesystemId)
+	if _, err := s.run("zfs", "set", "mountpoint="+arg.Path, dataset); err != nil {
+		return nil, errors.Annotatef(err, "mounting %q at %q", dataset, arg.Path)
+	}
+	return &storage.FilesystemAttachment{
+		arg.Filesystem,
+		arg.Machine,
+		storage.FilesystemAttachmentInfo{
+			Path:     arg.Path,
+			ReadOnly: arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachFilesystems is defined on the FilesystemSource interface.
+func (s *zfsFilesystemSource) DetachFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if err := s.validateId(arg.FilesystemId); err != nil {
+			results[i] = err
+			continue
+		}
+		// Clearing the mountpoint unmounts the dataset.
+		dataset := s.path(arg.FilesystemId)
+		if _, err := s.run("zfs", "set", "mountpoint=none", dataset); err != nil {
+			results[i] = errors.Annotatef(err, "unmounting %q", dataset)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&zfsSuite{})
+
+type zfsSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+
+	callCtx context.ProviderCallContext
+}
+
+func (s *zfsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.commands = &mockRunCommand{c: c}
+	s.callCtx = context.NewEmptyCloudCallContext()
+}
+
+func (s *zfsSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *zfsSuite) config(c *gc.C) *storage.Config {
+	cfg, err := storage.NewConfig("name", provider.ZFSProviderType, map[string]interface{}{
+		"zfs-pool": "tank/juju",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func (s *zfsSuite) volumeSource(c *gc.C) storage.VolumeSource {
+	source, err := provider.ZFSProvider(s.commands.run).VolumeSource(s.config(c))
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+func (s *zfsSuite) filesystemSource(c *gc.C) storage.FilesystemSource {
+	source, err := provider.ZFSProvider(s.commands.run).FilesystemSource(s.config(c))
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+func (s *zfsSuite) expectList(datasetType, property, output string) {
+	s.commands.expect("zfs", "list", "-H", "-p", "-d", "1",
+		"-t", datasetType, "-o", "name,"+property, "tank/juju").respond(output, nil)
+}
+
+func (s *zfsSuite) TestValidateConfig(c *gc.C) {
+	p := provider.ZFSProvider(s.commands.run)
+	cfg, err := storage.NewConfig("name", provider.ZFSProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, "zfs-pool must be specified")
+
+	cfg, err = storage.NewConfig("name", provider.ZFSProviderType, map[string]interface{}{
+		"zfs-pool": "tank/juju",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("name", provider.ZFSProviderType, map[string]interface{}{
+		"zfs-pool": "/tank",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, `zfs-pool "/tank" not valid`)
+}
+
+func (s *zfsSuite) TestSupports(c *gc.C) {
+	p := provider.ZFSProvider(s.commands.run)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsTrue)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+}
+
+func (s *zfsSuite) TestCreateVolumes(c *gc.C) {
+	source := s.volumeSource(c)
+	s.expectList("volume", "volsize", "tank/juju\t-\ntank/juju/juju-volume-1\t1073741824\n")
+	s.commands.expect("zfs", "create", "-V", "2048M", "tank/juju/juju-volume-0")
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 2048,
+	}, {
+		Tag:  names.NewVolumeTag("1"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateVolumesResult{{
+		Volume: &storage.Volume{
+			names.NewVolumeTag("0"),
+			storage.VolumeInfo{VolumeId: "juju-volume-0", Size: 2048},
+		},
+	}, {
+		Volume: &storage.Volume{
+			names.NewVolumeTag("1"),
+			storage.VolumeInfo{VolumeId: "juju-volume-1", Size: 1024},
+		},
+	}})
+}
+
+func (s *zfsSuite) TestAttachVolumes(c *gc.C) {
+	source := s.volumeSource(c)
+	s.commands.expect("zfs", "set", "readonly=on", "tank/juju/juju-volume-0")
+
+	results, err := source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "juju-volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachVolumesResult{{
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("0"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{
+				DeviceLink: "/dev/zvol/tank/juju/juju-volume-0",
+				ReadOnly:   true,
+			},
+		},
+	}})
+}
+
+func (s *zfsSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.volumeSource(c)
+	s.expectList("volume", "volsize", "tank/juju/juju-volume-0\t1073741824\n")
+	s.commands.expect("zfs", "destroy", "tank/juju/juju-volume-0")
+
+	errs, err := source.DestroyVolumes(s.callCtx, []string{"juju-volume-0", "juju-volume-1", "juju-x/../y"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `invalid zfs dataset ID "juju-x/../y"`)
+}
+
+func (s *zfsSuite) TestCreateFilesystems(c *gc.C) {
+	source := s.filesystemSource(c)
+	s.expectList("filesystem", "quota", "tank/juju\t0\n")
+	s.commands.expect("zfs", "create", "-o", "mountpoint=none", "-o", "quota=1024M",
+		"tank/juju/juju-filesystem-0-1")
+
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:  names.NewFilesystemTag("0/1"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateFilesystemsResult{{
+		Filesystem: &storage.Filesystem{
+			Tag: names.NewFilesystemTag("0/1"),
+			FilesystemInfo: storage.FilesystemInfo{
+				FilesystemId: "juju-filesystem-0-1",
+				Size:         1024,
+			},
+		},
+	}})
+}
+
+func (s *zfsSuite) TestAttachFilesystems(c *gc.C) {
+	source := s.filesystemSource(c)
+	s.commands.expect("zfs", "set", "readonly=off", "tank/juju/juju-filesystem-0-1")
+	s.commands.expect("zfs", "set", "mountpoint=/srv/data", "tank/juju/juju-filesystem-0-1")
+
+	results, err := source.AttachFilesystems(s.callCtx, []storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		FilesystemId: "juju-filesystem-0-1",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+		Path: "/srv/data",
+	}, {
+		Filesystem:   names.NewFilesystemTag("0/2"),
+		FilesystemId: "juju-filesystem-0-2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].FilesystemAttachment, jc.DeepEquals, &storage.FilesystemAttachment{
+		names.NewFilesystemTag("0/1"),
+		names.NewMachineTag("0"),
+		storage.FilesystemAttachmentInfo{Path: "/srv/data"},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, "attaching filesystem 0/2: filesystem mount point not specified")
+}
+
+func (s *zfsSuite) TestDetachFilesystems(c *gc.C) {
+	source := s.filesystemSource(c)
+	s.commands.expect("zfs", "set", "mountpoint=none", "tank/juju/juju-filesystem-0-1")
+
+	errs, err := source.DetachFilesystems(s.callCtx, []storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		FilesystemId: "juju-filesystem-0-1",
+		Path:         "/srv/data",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], jc.ErrorIsNil)
+}
+
+func (s *zfsSuite) TestDestroyFilesystems(c *gc.C) {
+	source := s.filesystemSource(c)
+	s.expectList("filesystem", "quota", "tank/juju/juju-filesystem-0-1\t1073741824\n")
+	s.commands.expect("zfs", "destroy", "tank/juju/juju-filesystem-0-1")
+
+	errs, err := source.DestroyFilesystems(s.callCtx, []string{"juju-filesystem-0-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *zfsSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	source := s.volumeSource(c)
+	s.expectList("volume", "volsize", "tank/juju/juju-volume-0-1\t1073741824\n")
+	s.commands.expect("zfs", "snapshot", "tank/juju/juju-volume-0-1@juju-snapshot-0-1.2")
+
+	results, err := source.(storage.VolumeSnapshotter).CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Id:       "0/1@2",
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "juju-volume-0-1",
+	}, {
+		Id:       "0/2@1",
+		Volume:   names.NewVolumeTag("0/2"),
+		VolumeId: "juju-volume-0-2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Snapshot, jc.DeepEquals, &storage.VolumeSnapshot{
+		Id: "0/1@2",
+		VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+			SnapshotId: "juju-volume-0-1@juju-snapshot-0-1.2",
+			Size:       1024,
+		},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `zfs volume "tank/juju/juju-volume-0-2" not found`)
+}
+
+func (s *zfsSuite) TestCreateVolumesFromSnapshot(c *gc.C) {
+	source := s.volumeSource(c)
+	s.expectList("volume", "volsize", "tank/juju/juju-volume-0-1\t1073741824\n")
+	s.commands.expect("zfs", "clone", "tank/juju/juju-volume-0-1@juju-snapshot-0-1.2", "tank/juju/juju-volume-3")
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("3"),
+		Size:       512,
+		SnapshotId: "juju-volume-0-1@juju-snapshot-0-1.2",
+	}, {
+		Tag:        names.NewVolumeTag("4"),
+		Size:       512,
+		SnapshotId: "../other@juju-snapshot-0-1.2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.DeepEquals, storage.CreateVolumesResult{
+		Volume: &storage.Volume{
+			Tag:        names.NewVolumeTag("3"),
+			VolumeInfo: storage.VolumeInfo{VolumeId: "juju-volume-3", Size: 1024},
+		},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `invalid zfs dataset ID "../other"`)
+}
+
+func (s *zfsSuite) TestDestroyVolumeSnapshots(c *gc.C) {
+	source := s.volumeSource(c)
+	s.commands.expect("zfs", "list", "-H", "-r", "-t", "snapshot", "-o", "name", "tank/juju").
+		respond("tank/juju/juju-volume-0@juju-snapshot-0.1\n", nil)
+	s.commands.expect("zfs", "destroy", "-d", "tank/juju/juju-volume-0@juju-snapshot-0.1")
+
+	errs, err := source.(storage.VolumeSnapshotter).DestroyVolumeSnapshots(s.callCtx, []string{
+		"juju-volume-0@juju-snapshot-0.1", "juju-volume-0@juju-snapshot-0.2", "juju-volume-0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `invalid zfs snapshot ID "juju-volume-0"`)
+}
+
+func (s *zfsSuite) TestResizeVolumes(c *gc.C) {
+	source := s.volumeSource(c)
+	s.expectList("volume", "volsize", "tank/juju/juju-volume-0-1\t1073741824\n")
+	s.commands.expect("zfs", "set", "volsize=2048M", "tank/juju/juju-volume-0-1")
+
+	results, err := source.(storage.VolumeResizer).ResizeVolumes(s.callCtx, []storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0/1"),
+		VolumeId: "juju-volume-0-1",
+		Size:     2048,
+	}, {
+		Tag:      names.NewVolumeTag("0/2"),
+		VolumeId: "../juju-volume-0-2",
+		Size:     2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.DeepEquals, storage.ResizeVolumesResult{
+		Info: &storage.VolumeInfo{VolumeId: "juju-volume-0-1", Size: 2048},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `invalid zfs dataset ID "../juju-volume-0-2"`)
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/environs/context"
+)
+
+// VolumeResizer is an optional interface that may be implemented by a
+// VolumeSource, if the storage provider supports growing volumes after
+// they have been created. Volumes may only be grown, never shrunk.
+//
+// Resizing a volume does not resize any filesystem on the volume; the
+// machine agent is responsible for growing the filesystem once the
+// volume has been resized.
+type VolumeResizer interface {
+	// ResizeVolumes grows the volumes with the specified parameters.
+	// Implementations should resize volumes while they are attached,
+	// if the storage provider supports it.
+	ResizeVolumes(ctx context.ProviderCallContext, params []VolumeResizeParams) ([]ResizeVolumesResult, error)
+}
+
+// VolumeResizeParams is a set of parameters for resizing a volume.
+type VolumeResizeParams struct {
+	// Tag is the tag of the volume to resize.
+	Tag names.VolumeTag
+
+	// VolumeId is the provider ID of the volume to resize.
+	VolumeId string
+
+	// Size is the minimum size of the resized volume in MiB.
+	Size uint64
+}
+
+// ResizeVolumesResult contains the result of a VolumeResizer.ResizeVolumes
+// call for one volume. Info should only be used if Error is nil.
+type ResizeVolumesResult struct {
+	// Info is the updated information for the resized volume.
+	Info *VolumeInfo
+
+	Error error
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/environs/context"
+)
+
+// VolumeSnapshotter is an optional interface that may be implemented by
+// a VolumeSource, if the storage provider supports taking point-in-time
+// snapshots of volumes. Volume sources implementing VolumeSnapshotter
+// must also support creating volumes from snapshots, by honouring the
+// SnapshotId field of VolumeParams.
+type VolumeSnapshotter interface {
+	// CreateVolumeSnapshots takes snapshots of the volumes with the
+	// specified parameters.
+	CreateVolumeSnapshots(ctx context.ProviderCallContext, params []VolumeSnapshotParams) ([]CreateVolumeSnapshotsResult, error)
+
+	// DestroyVolumeSnapshots destroys the volume snapshots with the
+	// specified provider snapshot IDs. Destroying a snapshot that
+	// does not exist is not an error.
+	DestroyVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error)
+}
+
+// VolumeSnapshotParams is a set of parameters for taking a snapshot
+// of a volume.
+type VolumeSnapshotParams struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string
+
+	// Volume is the tag of the volume to snapshot.
+	Volume names.VolumeTag
+
+	// VolumeId is the provider ID of the volume to snapshot.
+	VolumeId string
+
+	// ResourceTags is a set of tags to set on the created snapshot,
+	// if the storage provider supports tags.
+	ResourceTags map[string]string
+}
+
+// VolumeSnapshot describes a snapshot of a volume.
+type VolumeSnapshot struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string
+
+	VolumeSnapshotInfo
+}
+
+// VolumeSnapshotInfo describes a snapshot of a volume.
+type VolumeSnapshotInfo struct {
+	// SnapshotId is a unique provider-supplied ID for the snapshot.
+	SnapshotId string
+
+	// Size is the size of the volume from which the snapshot was
+	// taken, in MiB. Volumes created from the snapshot will be at
+	// least this size.
+	Size uint64
+
+	// Created is the time at which the snapshot was taken.
+	Created time.Time
+}
+
+// CreateVolumeSnapshotsResult contains the result of a
+// VolumeSnapshotter.CreateVolumeSnapshots call for one snapshot.
+// Snapshot should only be used if Error is nil.
+type CreateVolumeSnapshotsResult struct {
+	Snapshot *VolumeSnapshot
+	Error    error
+}
@@ -11,6 +11,7 @@ import (
 
 // StateBackend provides an interface for upgrading the global state database.
 type StateBackend interface {
+	AssignBuiltinModelRoles() error
 }
 
 // Model is an interface providing access to the details of a model within the
@@ -28,3 +29,7 @@ func NewStateBackend(pool *state.StatePool) StateBackend {
 type stateBackend struct {
 	pool *state.StatePool
 }
+
+func (s stateBackend) AssignBuiltinModelRoles() error {
+	return state.AssignBuiltinModelRoles(s.pool)
+}
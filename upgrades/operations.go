@@ -19,6 +19,7 @@ import (
 // (below).
 var stateUpgradeOperations = func() []Operation {
 	steps := []Operation{
+		upgradeToVersion{version.MustParse("3.4.0"), stateStepsFor34()},
 		// Fill in when we have upgrade steps.
 		upgradeToVersion{version.MustParse("6.6.6"), []Step(nil)},
 	}
@@ -0,0 +1,17 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+// stateStepsFor34 returns upgrade steps for Juju 3.4.0 that manipulate state directly.
+func stateStepsFor34() []Step {
+	return []Step{
+		&upgradeStep{
+			description: "assign built-in roles to existing model users",
+			targets:     []Target{DatabaseMaster},
+			run: func(context Context) error {
+				return context.State().AssignBuiltinModelRoles()
+			},
+		},
+	}
+}
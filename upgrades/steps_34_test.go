@@ -0,0 +1,31 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+var v340 = version.MustParse("3.4.0")
+
+type steps34Suite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&steps34Suite{})
+
+func (s *steps34Suite) TestAssignBuiltinModelRoles(c *gc.C) {
+	step := findStateStep(c, v340, "assign built-in roles to existing model users")
+	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
+
+	state := &mockStateBackend{}
+	err := step.Run(&mockContext{state: state})
+	c.Assert(err, jc.ErrorIsNil)
+	state.CheckCallNames(c, "AssignBuiltinModelRoles")
+}
@@ -31,9 +31,6 @@ func TestPackage(t *stdtesting.T) {
 	gc.TestingT(t)
 }
 
-// Untl we add upgrade steps for 3.0, keep static analysis happy.
-var _ = findStateStep
-
 func findStateStep(c *gc.C, ver version.Number, description string) upgrades.Step {
 	for _, op := range (*upgrades.StateUpgradeOperations)() {
 		if op.TargetVersion() == ver {
@@ -201,6 +198,11 @@ func (mock *mockStateBackend) ControllerUUID() (string, error) {
 	return "a-b-c-d", mock.Stub.NextErr()
 }
 
+func (mock *mockStateBackend) AssignBuiltinModelRoles() error {
+	mock.MethodCall(mock, "AssignBuiltinModelRoles")
+	return mock.Stub.NextErr()
+}
+
 func stateUpgradeOperations() []upgrades.Operation {
 	steps := []upgrades.Operation{
 		&mockUpgradeOperation{
@@ -596,7 +598,7 @@ func (s *upgradeSuite) TestUpgradeOperationsOrdered(c *gc.C) {
 
 func (s *upgradeSuite) TestStateUpgradeOperationsVersions(c *gc.C) {
 	versions := extractUpgradeVersions(c, (*upgrades.StateUpgradeOperations)())
-	c.Assert(versions, gc.DeepEquals, []string{"6.6.6"})
+	c.Assert(versions, gc.DeepEquals, []string{"3.4.0", "6.6.6"})
 }
 
 func (s *upgradeSuite) TestUpgradeOperationsVersions(c *gc.C) {
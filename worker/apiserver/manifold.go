@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/multiwatcher"
 	"github.com/juju/juju/core/presence"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/worker/common"
 	"github.com/juju/juju/worker/gate"
@@ -49,6 +50,7 @@ type ManifoldConfig struct {
 	SyslogName             string
 	CharmhubHTTPClientName string
 	DBAccessorName         string
+	TracerName             string
 
 	PrometheusRegisterer              prometheus.Registerer
 	RegisterIntrospectionHTTPHandlers func(func(path string, _ http.Handler))
@@ -106,6 +108,9 @@ func (config ManifoldConfig) Validate() error {
 	if config.DBAccessorName == "" {
 		return errors.NotValidf("empty DBAccessorName")
 	}
+	if config.TracerName == "" {
+		return errors.NotValidf("empty TracerName")
+	}
 	if config.Hub == nil {
 		return errors.NotValidf("nil Hub")
 	}
@@ -140,6 +145,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			config.SyslogName,
 			config.CharmhubHTTPClientName,
 			config.DBAccessorName,
+			config.TracerName,
 		},
 		Start: config.start,
 	}
@@ -216,6 +222,11 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		return nil, errors.Trace(err)
 	}
 
+	var tracer trace.Tracer
+	if err := context.Get(config.TracerName, &tracer); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Register the metrics collector against the prometheus register.
 	metricsCollector := config.NewMetricsCollector()
 	if err := config.PrometheusRegisterer.Register(metricsCollector); err != nil {
@@ -254,6 +265,7 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		SysLogger:                         sysLogger,
 		CharmhubHTTPClient:                charmhubHTTPClient,
 		DBGetter:                          dbGetter,
+		Tracer:                            tracer,
 	})
 	if err != nil {
 		// Ensure we clean up the resources we've registered with. This includes
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/juju/clock"
 	"github.com/juju/errors"
@@ -18,13 +19,17 @@ import (
 	"github.com/juju/juju/apiserver/apiserverhttp"
 	"github.com/juju/juju/apiserver/authentication/jwt"
 	"github.com/juju/juju/apiserver/authentication/macaroon"
+	"github.com/juju/juju/apiserver/authentication/oidc"
+	"github.com/juju/juju/apiserver/stateauthenticator"
 	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/core/cache"
 	coredatabase "github.com/juju/juju/core/database"
 	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/multiwatcher"
+	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/core/presence"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/syslogger"
 )
@@ -51,6 +56,8 @@ type Config struct {
 	CharmhubHTTPClient                HTTPClient
 	// DBGetter supplies sql.DB references on request, for named databases.
 	DBGetter coredatabase.DBGetter
+	// Tracer records the spans of API requests.
+	Tracer trace.Tracer
 }
 
 type HTTPClient interface {
@@ -114,6 +121,9 @@ func (config Config) Validate() error {
 	if config.DBGetter == nil {
 		return errors.NotValidf("nil DBGetter")
 	}
+	if config.Tracer == nil {
+		return errors.NotValidf("nil Tracer")
+	}
 	return nil
 }
 
@@ -152,6 +162,10 @@ func NewWorker(config Config) (worker.Worker, error) {
 	if err != nil {
 		return nil, fmt.Errorf("gathering authenticators for apiserver: %w", err)
 	}
+	oidcAuthenticator, err := gatherOIDCAuthenticator(controllerConfig, systemState, config.Clock)
+	if err != nil {
+		return nil, fmt.Errorf("gathering authenticators for apiserver: %w", err)
+	}
 
 	serverConfig := apiserver.ServerConfig{
 		StatePool:                     config.StatePool,
@@ -166,6 +180,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		Mux:                           config.Mux,
 		LocalMacaroonAuthenticator:    config.LocalMacaroonAuthenticator,
 		JWTAuthenticator:              jwtAuthenticator,
+		OIDCAuthenticator:             oidcAuthenticator,
 		UpgradeComplete:               config.UpgradeComplete,
 		PublicDNSName:                 controllerConfig.AutocertDNSName(),
 		AllowModelAccess:              controllerConfig.AllowModelAccess(),
@@ -179,6 +194,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		SysLogger:                     config.SysLogger,
 		CharmhubHTTPClient:            config.CharmhubHTTPClient,
 		DBGetter:                      config.DBGetter,
+		Tracer:                        config.Tracer,
 	}
 	return config.NewServer(serverConfig)
 }
@@ -197,6 +213,33 @@ func gatherJWTAuthenticator(controllerConfig jujucontroller.Config) (jwt.Authent
 	return jwtAuthenticator, nil
 }
 
+// gatherOIDCAuthenticator is responsible for building up the OpenID Connect
+// authenticator if this controller has been configured with an OIDC
+// provider.
+func gatherOIDCAuthenticator(
+	controllerConfig jujucontroller.Config, st *state.State, clock clock.Clock,
+) (oidc.Authenticator, error) {
+	issuerURL := controllerConfig.OIDCIssuerURL()
+	if issuerURL == "" {
+		return nil, nil
+	}
+	groupAccess := make(map[string]permission.Access)
+	for group, access := range controllerConfig.OIDCGroupAccess() {
+		groupAccess[group] = permission.Access(access)
+	}
+	return oidc.NewAuthenticator(oidc.Config{
+		IssuerURL:     issuerURL,
+		ClientID:      controllerConfig.OIDCClientID(),
+		UsernameClaim: controllerConfig.OIDCUsernameClaim(),
+		GroupsClaim:   controllerConfig.OIDCGroupsClaim(),
+		GroupAccess:   groupAccess,
+		Delegator:     &stateauthenticator.PermissionDelegator{State: st},
+		GroupSyncer:   st,
+		HTTPClient:    &http.Client{Timeout: 30 * time.Second},
+		Clock:         clock,
+	})
+}
+
 func newServerShim(config apiserver.ServerConfig) (worker.Worker, error) {
 	return apiserver.NewServer(config)
 }
@@ -0,0 +1,190 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditconfigupdater
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v3/catacomb"
+
+	"github.com/juju/juju/core/auditlog"
+)
+
+const (
+	// queueSize is the number of audit records waiting to be
+	// written. Records that are logged while the queue is full are
+	// dropped, rather than holding up the API requests they record.
+	queueSize = 4096
+
+	// batchSize is the maximum number of records written together.
+	batchSize = 256
+
+	// flushInterval is the longest a record waits to be written.
+	flushInterval = time.Second
+)
+
+// RecordStore stores batches of audit records. (Primary
+// implementation is state.AuditLogStore.)
+type RecordStore interface {
+	// AddRecords stores the records.
+	AddRecords([]auditlog.Record) error
+
+	// ForwardRecords writes the records, which have already been
+	// stored, to the controller model's logs.
+	ForwardRecords([]auditlog.Record) error
+}
+
+// BufferedLog is a worker which writes audit records to a RecordStore
+// in batches. API requests only queue their records, so they neither
+// wait for, nor fail because of, the database. The records are always
+// written to the audit log file as well, so that any dropped by the
+// store can still be found there.
+type BufferedLog struct {
+	catacomb catacomb.Catacomb
+	store    RecordStore
+	clock    clock.Clock
+	records  chan queuedRecord
+	dropped  int64
+}
+
+type queuedRecord struct {
+	record  auditlog.Record
+	forward bool
+}
+
+// NewBufferedLog returns a worker which writes the records queued by
+// its logs to the store until it's killed, when it writes whatever is
+// still queued.
+func NewBufferedLog(store RecordStore, clock clock.Clock) (*BufferedLog, error) {
+	b := &BufferedLog{
+		store:   store,
+		clock:   clock,
+		records: make(chan queuedRecord, queueSize),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &b.catacomb,
+		Work: b.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return b, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (b *BufferedLog) Kill() {
+	b.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (b *BufferedLog) Wait() error {
+	return b.catacomb.Wait()
+}
+
+// Log returns an audit log which queues its records to be written by
+// the worker. If forward is true, the records are also written to the
+// controller model's logs.
+func (b *BufferedLog) Log(forward bool) auditlog.AuditLog {
+	return &queueLog{buffer: b, forward: forward}
+}
+
+func (b *BufferedLog) queue(record auditlog.Record, forward bool) {
+	select {
+	case b.records <- queuedRecord{record: record, forward: forward}:
+	default:
+		atomic.AddInt64(&b.dropped, 1)
+	}
+}
+
+func (b *BufferedLog) loop() error {
+	var (
+		batch []queuedRecord
+		flush <-chan time.Time
+	)
+	for {
+		select {
+		case <-b.catacomb.Dying():
+			// Write whatever is already queued, so that the records
+			// of the last requests aren't lost.
+			for len(b.records) > 0 {
+				batch = append(batch, <-b.records)
+			}
+			b.write(batch)
+			return b.catacomb.ErrDying()
+		case r := <-b.records:
+			batch = append(batch, r)
+			if len(batch) >= batchSize {
+				b.write(batch)
+				batch, flush = nil, nil
+			} else if flush == nil {
+				flush = b.clock.After(flushInterval)
+			}
+		case <-flush:
+			b.write(batch)
+			batch, flush = nil, nil
+		}
+	}
+}
+
+// write stores the batch of records. Failures are logged rather than
+// stopping the worker, as the records are also in the audit log file.
+func (b *BufferedLog) write(batch []queuedRecord) {
+	if dropped := atomic.SwapInt64(&b.dropped, 0); dropped > 0 {
+		logger.Warningf("dropped %d audit records while the queue was full", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+	records := make([]auditlog.Record, len(batch))
+	var forward []auditlog.Record
+	for i, r := range batch {
+		records[i] = r.record
+		if r.forward {
+			forward = append(forward, r.record)
+		}
+	}
+	if err := b.store.AddRecords(records); err != nil {
+		logger.Warningf("storing %d audit records: %v", len(records), err)
+	}
+	if len(forward) == 0 {
+		return
+	}
+	if err := b.store.ForwardRecords(forward); err != nil {
+		logger.Warningf("forwarding %d audit records: %v", len(forward), err)
+	}
+}
+
+// queueLog is an auditlog.AuditLog which queues its records with a
+// BufferedLog.
+type queueLog struct {
+	buffer  *BufferedLog
+	forward bool
+}
+
+// AddConversation is part of auditlog.AuditLog.
+func (l *queueLog) AddConversation(c auditlog.Conversation) error {
+	l.buffer.queue(auditlog.Record{Conversation: &c}, l.forward)
+	return nil
+}
+
+// AddRequest is part of auditlog.AuditLog.
+func (l *queueLog) AddRequest(r auditlog.Request) error {
+	l.buffer.queue(auditlog.Record{Request: &r}, l.forward)
+	return nil
+}
+
+// AddResponse is part of auditlog.AuditLog.
+func (l *queueLog) AddResponse(r auditlog.ResponseErrors) error {
+	l.buffer.queue(auditlog.Record{Errors: &r}, l.forward)
+	return nil
+}
+
+// Close is part of auditlog.AuditLog. The records are written by the
+// BufferedLog, which outlives its logs, so there's nothing to close.
+func (l *queueLog) Close() error {
+	return nil
+}
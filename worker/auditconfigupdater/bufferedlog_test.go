@@ -0,0 +1,141 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditconfigupdater_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v3/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+	jujutesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/auditconfigupdater"
+)
+
+type bufferedLogSuite struct {
+	jujutesting.BaseSuite
+
+	clock *testclock.Clock
+	store *recordStore
+}
+
+var _ = gc.Suite(&bufferedLogSuite{})
+
+func (s *bufferedLogSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.store = &recordStore{written: make(chan struct{}, 10)}
+}
+
+func (s *bufferedLogSuite) TestWritesBatchAfterFlushInterval(c *gc.C) {
+	b, err := auditconfigupdater.NewBufferedLog(s.store, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+
+	log := b.Log(false)
+	err = log.AddConversation(auditlog.Conversation{ConversationID: "abc"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = log.AddRequest(auditlog.Request{ConversationID: "abc", RequestID: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	err = log.AddResponse(auditlog.ResponseErrors{ConversationID: "abc", RequestID: 1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Nothing is written until the flush interval has passed.
+	err = s.clock.WaitAdvance(time.Second, jujutesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForWrite(c)
+	s.store.CheckCallNames(c, "AddRecords")
+	s.store.CheckCall(c, 0, "AddRecords", []auditlog.Record{
+		{Conversation: &auditlog.Conversation{ConversationID: "abc"}},
+		{Request: &auditlog.Request{ConversationID: "abc", RequestID: 1}},
+		{Errors: &auditlog.ResponseErrors{ConversationID: "abc", RequestID: 1}},
+	})
+}
+
+func (s *bufferedLogSuite) TestForwardsOnlyForwardedRecords(c *gc.C) {
+	b, err := auditconfigupdater.NewBufferedLog(s.store, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+
+	err = b.Log(false).AddConversation(auditlog.Conversation{ConversationID: "abc"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = b.Log(true).AddConversation(auditlog.Conversation{ConversationID: "def"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.clock.WaitAdvance(time.Second, jujutesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForWrite(c)
+	s.waitForWrite(c)
+	s.store.CheckCallNames(c, "AddRecords", "ForwardRecords")
+	s.store.CheckCall(c, 1, "ForwardRecords", []auditlog.Record{
+		{Conversation: &auditlog.Conversation{ConversationID: "def"}},
+	})
+}
+
+func (s *bufferedLogSuite) TestWritesFullBatch(c *gc.C) {
+	b, err := auditconfigupdater.NewBufferedLog(s.store, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, b)
+
+	log := b.Log(false)
+	for i := 0; i < 256; i++ {
+		err := log.AddRequest(auditlog.Request{ConversationID: "abc", RequestID: uint64(i)})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// A full batch is written without waiting for the flush interval.
+	s.waitForWrite(c)
+	s.store.CheckCallNames(c, "AddRecords")
+	c.Assert(s.store.Calls()[0].Args[0], gc.HasLen, 256)
+}
+
+func (s *bufferedLogSuite) TestWritesQueuedRecordsWhenStopped(c *gc.C) {
+	s.store.SetErrors(errors.New("boom"))
+	b, err := auditconfigupdater.NewBufferedLog(s.store, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = b.Log(false).AddConversation(auditlog.Conversation{ConversationID: "abc"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Failing to store the records doesn't stop the worker.
+	workertest.CleanKill(c, b)
+	s.store.CheckCallNames(c, "AddRecords")
+}
+
+func (s *bufferedLogSuite) waitForWrite(c *gc.C) {
+	select {
+	case <-s.store.written:
+	case <-time.After(jujutesting.LongWait):
+		c.Fatalf("timed out waiting for records to be written")
+	}
+}
+
+type recordStore struct {
+	mu sync.Mutex
+	testing.Stub
+	written chan struct{}
+}
+
+func (s *recordStore) AddRecords(records []auditlog.Record) error {
+	return s.record("AddRecords", records)
+}
+
+func (s *recordStore) ForwardRecords(records []auditlog.Record) error {
+	return s.record("ForwardRecords", records)
+}
+
+func (s *recordStore) record(name string, records []auditlog.Record) error {
+	s.mu.Lock()
+	s.MethodCall(s, name, records)
+	err := s.NextErr()
+	s.mu.Unlock()
+	s.written <- struct{}{}
+	return err
+}
@@ -4,12 +4,14 @@
 package auditconfigupdater
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/worker/v3"
 	"github.com/juju/worker/v3/dependency"
 
 	jujuagent "github.com/juju/juju/agent"
 	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/common"
 	workerstate "github.com/juju/juju/worker/state"
 )
@@ -19,7 +21,8 @@ import (
 type ManifoldConfig struct {
 	AgentName string
 	StateName string
-	NewWorker func(ConfigSource, auditlog.Config, AuditLogFactory) (worker.Worker, error)
+	Clock     clock.Clock
+	NewWorker func(ConfigSource, auditlog.Config, AuditLogFactory, clock.Clock) (worker.Worker, error)
 }
 
 // Validate validates the manifold configuration.
@@ -30,6 +33,9 @@ func (config ManifoldConfig) Validate() error {
 	if config.StateName == "" {
 		return errors.NotValidf("empty StateName")
 	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
 	if config.NewWorker == nil {
 		return errors.NotValidf("nil NewWorker")
 	}
@@ -80,22 +86,47 @@ func (config ManifoldConfig) start(context dependency.Context) (_ worker.Worker,
 		return nil, errors.Trace(err)
 	}
 
-	logFactory := func(cfg auditlog.Config) auditlog.AuditLog {
-		return auditlog.NewLogFile(logDir, cfg.MaxSizeMB, cfg.MaxBackups)
-	}
 	auditConfig, err := initialConfig(st)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+
+	// Records are written to the database in batches by the buffered
+	// log, so that API requests don't wait for the database.
+	store := state.NewAuditLogStore(st)
+	buffered, err := NewBufferedLog(store, config.Clock)
+	if err != nil {
+		_ = store.Close()
+		return nil, errors.Trace(err)
+	}
+	stopBuffered := func() {
+		_ = worker.Stop(buffered)
+		_ = store.Close()
+	}
+
+	// The log file is only ever created once, so that changing the
+	// audit config doesn't leak file handles; only the forwarding of
+	// the buffered records changes.
+	var logFile auditlog.AuditLog
+	logFactory := func(cfg auditlog.Config) auditlog.AuditLog {
+		if logFile == nil {
+			logFile = auditlog.NewLogFile(logDir, cfg.MaxSizeMB, cfg.MaxBackups)
+		}
+		return auditlog.NewTee(logFile, buffered.Log(cfg.Forward))
+	}
 	if auditConfig.Enabled {
 		auditConfig.Target = logFactory(auditConfig)
 	}
 
-	w, err := config.NewWorker(st, auditConfig, logFactory)
+	w, err := config.NewWorker(st, auditConfig, logFactory, config.Clock)
 	if err != nil {
+		stopBuffered()
 		return nil, errors.Trace(err)
 	}
-	return common.NewCleanupWorker(w, func() { _ = stTracker.Done() }), nil
+	return common.NewCleanupWorker(w, func() {
+		stopBuffered()
+		_ = stTracker.Done()
+	}), nil
 }
 
 type withCurrentConfig interface {
@@ -129,6 +160,8 @@ func initialConfig(source ConfigSource) (auditlog.Config, error) {
 		MaxSizeMB:      cfg.AuditLogMaxSizeMB(),
 		MaxBackups:     cfg.AuditLogMaxBackups(),
 		ExcludeMethods: cfg.AuditLogExcludeMethods(),
+		MaxAge:         cfg.AuditLogMaxAge(),
+		Forward:        cfg.AuditLogForwarding(),
 	}
 	return result, nil
 }
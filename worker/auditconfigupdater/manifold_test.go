@@ -4,6 +4,9 @@
 package auditconfigupdater_test
 
 import (
+	"time"
+
+	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -57,6 +60,7 @@ func (s *manifoldSuite) SetUpTest(c *gc.C) {
 	s.manifold = auditconfigupdater.Manifold(auditconfigupdater.ManifoldConfig{
 		AgentName: "agent",
 		StateName: "state",
+		Clock:     clock.WallClock,
 		NewWorker: s.newWorker,
 	})
 }
@@ -76,6 +80,7 @@ func (s *manifoldSuite) newWorker(
 	source auditconfigupdater.ConfigSource,
 	initial auditlog.Config,
 	factory auditconfigupdater.AuditLogFactory,
+	_ clock.Clock,
 ) (worker.Worker, error) {
 	s.stub.MethodCall(s, "NewWorker", source, initial, factory)
 	err := s.stub.NextErr()
@@ -126,6 +131,7 @@ func (s *manifoldSuite) TestStart(c *gc.C) {
 		ExcludeMethods: set.NewStrings("This.Method"),
 		MaxSizeMB:      10,
 		MaxBackups:     10,
+		MaxAge:         336 * time.Hour,
 	})
 
 	c.Assert(args[2], gc.NotNil)
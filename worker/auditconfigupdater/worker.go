@@ -5,8 +5,11 @@ package auditconfigupdater
 
 import (
 	"sync"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/worker/v3"
 	"github.com/juju/worker/v3/catacomb"
 
@@ -15,12 +18,20 @@ import (
 	"github.com/juju/juju/state"
 )
 
+var logger = loggo.GetLogger("juju.worker.auditconfigupdater")
+
+// pruneInterval is how often audit log records older than the
+// configured maximum age are removed from the database.
+const pruneInterval = time.Hour
+
 // ConfigSource lets us get notifications of changes to controller
-// configuration, and then get the changed config. (Primary
-// implementation is State.)
+// configuration, and then get the changed config. It also prunes old
+// audit log records from the database. (Primary implementation is
+// State.)
 type ConfigSource interface {
 	WatchControllerConfig() state.NotifyWatcher
 	ControllerConfig() (controller.Config, error)
+	PruneAuditLog(maxAge time.Duration) error
 }
 
 // AuditLogFactory is a function that will return an audit log given
@@ -28,11 +39,12 @@ type ConfigSource interface {
 type AuditLogFactory func(auditlog.Config) auditlog.AuditLog
 
 // New returns a worker that will keep an up-to-date audit log config.
-func New(source ConfigSource, initial auditlog.Config, logFactory AuditLogFactory) (worker.Worker, error) {
+func New(source ConfigSource, initial auditlog.Config, logFactory AuditLogFactory, clock clock.Clock) (worker.Worker, error) {
 	u := &updater{
 		source:     source,
 		current:    initial,
 		logFactory: logFactory,
+		clock:      clock,
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &u.catacomb,
@@ -50,6 +62,7 @@ type updater struct {
 	source     ConfigSource
 	current    auditlog.Config
 	logFactory AuditLogFactory
+	clock      clock.Clock
 }
 
 // Kill is part of the worker.Worker interface.
@@ -67,10 +80,18 @@ func (u *updater) loop() error {
 	if err := u.catacomb.Add(watcher); err != nil {
 		return errors.Trace(err)
 	}
+	prune := u.clock.After(0)
 	for {
 		select {
 		case <-u.catacomb.Dying():
 			return u.catacomb.ErrDying()
+		case <-prune:
+			// Failing to prune isn't fatal, we'll try again next
+			// time around.
+			if err := u.source.PruneAuditLog(u.CurrentConfig().MaxAge); err != nil {
+				logger.Warningf("pruning audit log: %v", err)
+			}
+			prune = u.clock.After(pruneInterval)
 		case _, ok := <-watcher.Changes():
 			if !ok {
 				return errors.Errorf("watcher channel closed")
@@ -95,14 +116,19 @@ func (u *updater) newConfig() (auditlog.Config, error) {
 		MaxSizeMB:      cfg.AuditLogMaxSizeMB(),
 		MaxBackups:     cfg.AuditLogMaxBackups(),
 		ExcludeMethods: cfg.AuditLogExcludeMethods(),
+		MaxAge:         cfg.AuditLogMaxAge(),
+		Forward:        cfg.AuditLogForwarding(),
 	}
-	if result.Enabled && u.current.Target == nil {
+	current := u.CurrentConfig()
+	if result.Enabled && (current.Target == nil || result.Forward != current.Forward) {
+		// The factory reuses the log file, so a new target can be
+		// created when forwarding is switched on or off.
 		result.Target = u.logFactory(result)
 	} else {
 		// Keep the existing target to avoid file handle leaks from
 		// disabling and enabling auditing - we'll still stop logging
 		// because enabled is false.
-		result.Target = u.current.Target
+		result.Target = current.Target
 	}
 	return result, nil
 }
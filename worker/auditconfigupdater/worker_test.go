@@ -6,8 +6,11 @@ package auditconfigupdater_test
 import (
 	"reflect"
 	"sync"
+	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/collections/set"
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/worker/v3"
@@ -25,10 +28,17 @@ import (
 
 type updaterSuite struct {
 	jujutesting.BaseSuite
+
+	clock *testclock.Clock
 }
 
 var _ = gc.Suite(&updaterSuite{})
 
+func (s *updaterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+}
+
 var ding = struct{}{}
 
 func (s *updaterSuite) TestWorker(c *gc.C) {
@@ -48,7 +58,7 @@ func (s *updaterSuite) TestWorker(c *gc.C) {
 		return &fakeTarget
 	}
 
-	w, err := auditconfigupdater.New(&source, initial, factory)
+	w, err := auditconfigupdater.New(&source, initial, factory, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 	defer workertest.CleanKill(c, w)
 
@@ -90,7 +100,7 @@ func (s *updaterSuite) TestKeepsLogFileWhenAuditingDisabled(c *gc.C) {
 
 	// Passing a nil factory means we can be sure it didn't try to
 	// create a new logfile.
-	w, err := auditconfigupdater.New(&source, initial, nil)
+	w, err := auditconfigupdater.New(&source, initial, nil, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 	defer workertest.CleanKill(c, w)
 
@@ -118,7 +128,7 @@ func (s *updaterSuite) TestKeepsLogFileWhenEnabled(c *gc.C) {
 
 	// Passing a nil factory means we can be sure it didn't try to
 	// create a new logfile.
-	w, err := auditconfigupdater.New(&source, initial, nil)
+	w, err := auditconfigupdater.New(&source, initial, nil, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 	defer workertest.CleanKill(c, w)
 
@@ -145,7 +155,7 @@ func (s *updaterSuite) TestChangingExcludeMethod(c *gc.C) {
 		cfg:     makeControllerConfig(true, false, "Pink.Floyd"),
 	}
 
-	w, err := auditconfigupdater.New(&source, initial, nil)
+	w, err := auditconfigupdater.New(&source, initial, nil, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 	defer workertest.CleanKill(c, w)
 
@@ -176,7 +186,7 @@ func (s *updaterSuite) TestChangingCaptureArgs(c *gc.C) {
 		cfg:     makeControllerConfig(true, false, "Pink.Floyd"),
 	}
 
-	w, err := auditconfigupdater.New(&source, initial, nil)
+	w, err := auditconfigupdater.New(&source, initial, nil, s.clock)
 	c.Assert(err, jc.ErrorIsNil)
 	defer workertest.CleanKill(c, w)
 
@@ -188,6 +198,76 @@ func (s *updaterSuite) TestChangingCaptureArgs(c *gc.C) {
 	})
 }
 
+func (s *updaterSuite) TestChangingForwardingRecreatesTarget(c *gc.C) {
+	configChanged := make(chan struct{}, 1)
+	initial := auditlog.Config{
+		Enabled: true,
+		Target:  &apitesting.FakeAuditLog{},
+	}
+	source := configSource{
+		watcher: watchertest.NewNotifyWatcher(configChanged),
+		cfg:     makeControllerConfig(true, false),
+	}
+
+	fakeTarget := apitesting.FakeAuditLog{}
+	var calls []auditlog.Config
+	factory := func(cfg auditlog.Config) auditlog.AuditLog {
+		calls = append(calls, cfg)
+		return &fakeTarget
+	}
+
+	w, err := auditconfigupdater.New(&source, initial, factory, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	cfg := makeControllerConfig(true, false)
+	cfg["audit-log-forwarding"] = true
+	source.setConfig(cfg)
+	configChanged <- ding
+
+	newConfig := waitForConfig(c, w, func(cfg auditlog.Config) bool {
+		return cfg.Forward
+	})
+	c.Assert(newConfig.Target, gc.Equals, auditlog.AuditLog(&fakeTarget))
+	c.Assert(calls, gc.HasLen, 1)
+	c.Assert(calls[0].Forward, jc.IsTrue)
+}
+
+func (s *updaterSuite) TestPrunesAuditLog(c *gc.C) {
+	initial := auditlog.Config{
+		Enabled: true,
+		MaxAge:  time.Hour,
+		Target:  &apitesting.FakeAuditLog{},
+	}
+	source := configSource{
+		watcher: watchertest.NewNotifyWatcher(make(chan struct{})),
+		cfg:     makeControllerConfig(true, false),
+		pruned:  make(chan time.Duration, 1),
+	}
+	source.stub.SetErrors(errors.New("boom"))
+
+	w, err := auditconfigupdater.New(&source, initial, nil, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	waitForPrune := func() {
+		select {
+		case maxAge := <-source.pruned:
+			c.Assert(maxAge, gc.Equals, time.Hour)
+		case <-time.After(jujutesting.LongWait):
+			c.Fatalf("timed out waiting for prune")
+		}
+	}
+	waitForPrune()
+	// Pruning errors don't stop the worker.
+	workertest.CheckAlive(c, w)
+
+	// The audit log is pruned again after the prune interval.
+	err = s.clock.WaitAdvance(time.Hour, jujutesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	waitForPrune()
+}
+
 func makeControllerConfig(auditEnabled bool, captureArgs bool, methods ...interface{}) controller.Config {
 	result := map[string]interface{}{
 		"other-setting":             "something",
@@ -213,6 +293,7 @@ type configSource struct {
 	stub    testing.Stub
 	watcher *watchertest.NotifyWatcher
 	cfg     controller.Config
+	pruned  chan time.Duration
 }
 
 func (s *configSource) WatchControllerConfig() state.NotifyWatcher {
@@ -230,6 +311,16 @@ func (s *configSource) ControllerConfig() (controller.Config, error) {
 	return s.cfg, nil
 }
 
+func (s *configSource) PruneAuditLog(maxAge time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stub.AddCall("PruneAuditLog", maxAge)
+	if s.pruned != nil {
+		s.pruned <- maxAge
+	}
+	return s.stub.NextErr()
+}
+
 func (s *configSource) setConfig(cfg controller.Config) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
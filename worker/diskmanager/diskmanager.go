@@ -41,6 +41,19 @@ type ListBlockDevicesFunc func() ([]storage.BlockDevice, error)
 // devices for the operating system of the local host.
 var DefaultListBlockDevices ListBlockDevicesFunc
 
+// GrowFilesystemFunc is the type of a function that grows the filesystem
+// mounted at the specified location to fill its block device, which must
+// be at least the specified size in MiB. It returns the new size of the
+// filesystem in MiB.
+//
+// If the block device is smaller than the specified size, an error
+// satisfying errors.IsNotYetAvailable is returned.
+type GrowFilesystemFunc func(mountPoint string, size uint64) (uint64, error)
+
+// DefaultGrowFilesystem is the default function for growing filesystems
+// for the operating system of the local host.
+var DefaultGrowFilesystem GrowFilesystemFunc
+
 // NewWorker returns a worker that lists block devices
 // attached to the machine, and records them in state.
 var NewWorker = func(l ListBlockDevicesFunc, b BlockDeviceSetter) worker.Worker {
@@ -8,6 +8,8 @@ package diskmanager
 import (
 	"runtime"
 
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/storage"
 )
 
@@ -20,10 +22,15 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 	return nil, nil
 }
 
+func growFilesystem(mountPoint string, size uint64) (uint64, error) {
+	return 0, errors.NotSupportedf("growing filesystems on %s", runtime.GOOS)
+}
+
 func init() {
 	logger.Infof(
 		"block device support has not been implemented for %s",
 		runtime.GOOS,
 	)
 	DefaultListBlockDevices = listBlockDevices
+	DefaultGrowFilesystem = growFilesystem
 }
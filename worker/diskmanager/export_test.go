@@ -5,6 +5,7 @@ package diskmanager
 
 var (
 	ListBlockDevices = listBlockDevices
+	GrowFilesystem   = growFilesystem
 	BlockDeviceInUse = &blockDeviceInUse
 	DoWork           = doWork
 	NewWorkerFunc    = newWorker
@@ -0,0 +1,124 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+//go:build linux
+
+package diskmanager
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/juju/errors"
+)
+
+func init() {
+	DefaultGrowFilesystem = growFilesystem
+}
+
+// growFilesystem grows the filesystem mounted at the specified location
+// to fill its block device, which must be at least the specified size
+// in MiB. The new size of the filesystem in MiB is returned.
+func growFilesystem(mountPoint string, size uint64) (uint64, error) {
+	source, fstype, err := findMount(mountPoint)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	deviceSize, err := blockDeviceSize(source)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if deviceSize < size {
+		// The storage provider may take some time to resize the
+		// volume, and the kernel may not yet have noticed it.
+		return 0, errors.NotYetAvailablef(
+			"block device %q not yet resized (%dMiB of %dMiB)",
+			source, deviceSize, size,
+		)
+	}
+
+	var args []string
+	switch fstype {
+	case "ext2", "ext3", "ext4":
+		args = []string{"resize2fs", source}
+	case "xfs":
+		args = []string{"xfs_growfs", mountPoint}
+	case "btrfs":
+		args = []string{"btrfs", "filesystem", "resize", "max", mountPoint}
+	default:
+		return 0, errors.NotSupportedf("growing %q filesystem", fstype)
+	}
+	logger.Debugf("growing %s filesystem on %q: %v", fstype, source, args)
+	if err := run(args[0], args[1:]...); err != nil {
+		return 0, errors.Annotatef(err, "growing %s filesystem on %q", fstype, source)
+	}
+
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(mountPoint, &st); err != nil {
+		return 0, errors.Annotatef(err, "getting size of filesystem mounted at %q", mountPoint)
+	}
+	return st.Blocks * uint64(st.Bsize) / bytesInMiB, nil
+}
+
+// findMount returns the source device and filesystem type of the
+// filesystem mounted at the specified location.
+func findMount(mountPoint string) (source, fstype string, _ error) {
+	output, err := exec.Command(
+		"findmnt",
+		"-n", // no headings
+		"-P", // output fields as key=value pairs
+		"-o", "SOURCE,FSTYPE",
+		"--mountpoint", mountPoint,
+	).Output()
+	if err != nil {
+		return "", "", errors.Annotatef(err, "cannot find filesystem mounted at %q: findmnt failed", mountPoint)
+	}
+	for _, pair := range pairsRE.FindAllStringSubmatch(string(output), -1) {
+		switch pair[1] {
+		case "SOURCE":
+			source = pair[2]
+		case "FSTYPE":
+			fstype = pair[2]
+		}
+	}
+	if source == "" {
+		return "", "", errors.NotFoundf("filesystem mounted at %q", mountPoint)
+	}
+	return source, fstype, nil
+}
+
+// blockDeviceSize returns the size of the specified block device in MiB.
+func blockDeviceSize(devicePath string) (uint64, error) {
+	output, err := exec.Command(
+		"lsblk",
+		"-b", // output size in bytes
+		"-d", // do not list holders or slaves
+		"-n", // no headings
+		"-o", "SIZE",
+		devicePath,
+	).Output()
+	if err != nil {
+		return 0, errors.Annotatef(err, "cannot get size of %q: lsblk failed", devicePath)
+	}
+	size, err := strconv.ParseUint(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return 0, errors.Annotatef(err, "invalid size %q from lsblk", output)
+	}
+	return size / bytesInMiB, nil
+}
+
+func run(command string, args ...string) error {
+	output, err := exec.Command(command, args...).CombinedOutput()
+	if err != nil {
+		msg := fmt.Sprintf("%s failed", command)
+		if output := bytes.TrimSpace(output); len(output) > 0 {
+			msg += fmt.Sprintf(" (%s)", output)
+		}
+		return errors.Annotate(err, msg)
+	}
+	return nil
+}
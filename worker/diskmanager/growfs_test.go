@@ -0,0 +1,106 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+//go:build linux
+
+package diskmanager_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/diskmanager"
+)
+
+var _ = gc.Suite(&GrowFilesystemSuite{})
+
+type GrowFilesystemSuite struct {
+	coretesting.BaseSuite
+	mountPoint string
+}
+
+func (s *GrowFilesystemSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mountPoint = c.MkDir()
+	testing.PatchExecutable(c, s, "lsblk", `#!/bin/bash --norc
+echo 2147483648`)
+}
+
+func (s *GrowFilesystemSuite) patchFindmnt(c *gc.C, fstype string) {
+	testing.PatchExecutable(c, s, "findmnt", `#!/bin/bash --norc
+echo 'SOURCE="/dev/sdb" FSTYPE="`+fstype+`"'`)
+}
+
+func (s *GrowFilesystemSuite) patchGrowExecutable(c *gc.C, name string) string {
+	argsFile := filepath.Join(c.MkDir(), "args")
+	testing.PatchExecutable(c, s, name, `#!/bin/bash --norc
+echo "$@" > `+argsFile)
+	return argsFile
+}
+
+func (s *GrowFilesystemSuite) assertArgs(c *gc.C, argsFile, expect string) {
+	data, err := os.ReadFile(argsFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, expect+"\n")
+}
+
+func (s *GrowFilesystemSuite) TestGrowExt4(c *gc.C) {
+	s.patchFindmnt(c, "ext4")
+	argsFile := s.patchGrowExecutable(c, "resize2fs")
+	size, err := diskmanager.GrowFilesystem(s.mountPoint, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Not(gc.Equals), uint64(0))
+	s.assertArgs(c, argsFile, "/dev/sdb")
+}
+
+func (s *GrowFilesystemSuite) TestGrowXFS(c *gc.C) {
+	s.patchFindmnt(c, "xfs")
+	argsFile := s.patchGrowExecutable(c, "xfs_growfs")
+	_, err := diskmanager.GrowFilesystem(s.mountPoint, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertArgs(c, argsFile, s.mountPoint)
+}
+
+func (s *GrowFilesystemSuite) TestGrowBtrfs(c *gc.C) {
+	s.patchFindmnt(c, "btrfs")
+	argsFile := s.patchGrowExecutable(c, "btrfs")
+	_, err := diskmanager.GrowFilesystem(s.mountPoint, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertArgs(c, argsFile, "filesystem resize max "+s.mountPoint)
+}
+
+func (s *GrowFilesystemSuite) TestGrowUnsupportedFilesystem(c *gc.C) {
+	s.patchFindmnt(c, "vfat")
+	_, err := diskmanager.GrowFilesystem(s.mountPoint, 2048)
+	c.Assert(err, gc.ErrorMatches, `growing "vfat" filesystem not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *GrowFilesystemSuite) TestGrowDeviceNotResized(c *gc.C) {
+	s.patchFindmnt(c, "ext4")
+	_, err := diskmanager.GrowFilesystem(s.mountPoint, 4096)
+	c.Assert(err, gc.ErrorMatches, `block device "/dev/sdb" not yet resized \(2048MiB of 4096MiB\)`)
+	c.Assert(err, jc.Satisfies, errors.IsNotYetAvailable)
+}
+
+func (s *GrowFilesystemSuite) TestGrowNotMounted(c *gc.C) {
+	testing.PatchExecutable(c, s, "findmnt", `#!/bin/bash --norc
+exit 1`)
+	_, err := diskmanager.GrowFilesystem(s.mountPoint, 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot find filesystem mounted at ".*": findmnt failed: exit status 1`)
+}
+
+func (s *GrowFilesystemSuite) TestGrowFailure(c *gc.C) {
+	s.patchFindmnt(c, "ext4")
+	testing.PatchExecutable(c, s, "resize2fs", `#!/bin/bash --norc
+echo "Bad magic number" >&2
+exit 1`)
+	_, err := diskmanager.GrowFilesystem(s.mountPoint, 2048)
+	c.Assert(err, gc.ErrorMatches, `growing ext4 filesystem on "/dev/sdb": resize2fs failed \(Bad magic number\): exit status 1`)
+}
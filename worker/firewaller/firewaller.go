@@ -6,6 +6,7 @@ package firewaller
 import (
 	stdcontext "context"
 	"io"
+	"net"
 	"sort"
 	"time"
 
@@ -91,6 +92,10 @@ type EnvironInstances interface {
 
 type newCrossModelFacadeFunc func(*api.Info) (CrossModelFirewallerFacadeCloser, error)
 
+// egressRetryDelay is how long to wait before trying again to set egress
+// rules on machines that have not yet been provisioned.
+const egressRetryDelay = 10 * time.Second
+
 // Config defines the operation of a Worker.
 type Config struct {
 	ModelUUID              string
@@ -168,6 +173,8 @@ type Firewaller struct {
 	unitds               map[names.UnitTag]*unitData
 	applicationids       map[names.ApplicationTag]*applicationData
 	exposedChange        chan *exposedChange
+	egressChange         chan *egressChange
+	pendingEgress        map[names.MachineTag]bool
 	spaceInfos           network.SpaceInfos
 	globalMode           bool
 	globalIngressRuleRef map[string]int // map of rule names to count of occurrences
@@ -216,6 +223,8 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 		unitds:                     make(map[names.UnitTag]*unitData),
 		applicationids:             make(map[names.ApplicationTag]*applicationData),
 		exposedChange:              make(chan *exposedChange),
+		egressChange:               make(chan *egressChange),
+		pendingEgress:              make(map[names.MachineTag]bool),
 		relationIngress:            make(map[names.RelationTag]*remoteRelationData),
 		localRelationsChange:       make(chan *remoteRelationNetworkChange),
 		clk:                        clk,
@@ -320,6 +329,7 @@ func (fw *Firewaller) loop() error {
 
 	var modelFirewallChanges watcher.NotifyChannel
 	var ensureModelFirewalls <-chan time.Time
+	var retryEgress <-chan time.Time
 	if fw.modelFirewallWatcher != nil {
 		modelFirewallChanges = fw.modelFirewallWatcher.Changes()
 	}
@@ -420,6 +430,28 @@ func (fw *Firewaller) loop() error {
 			if err := fw.flushUnits(unitds); err != nil {
 				return errors.Annotate(err, "cannot change firewall ports")
 			}
+		case change := <-fw.egressChange:
+			change.applicationd.egressRules = change.rules
+			var unitds []*unitData
+			for _, unitd := range change.applicationd.unitds {
+				unitds = append(unitds, unitd)
+			}
+			if err := fw.flushUnits(unitds); err != nil {
+				return errors.Annotate(err, "cannot change firewall egress rules")
+			}
+		case <-retryEgress:
+			retryEgress = nil
+			for tag := range fw.pendingEgress {
+				delete(fw.pendingEgress, tag)
+				if machined, ok := fw.machineds[tag]; ok {
+					if err := fw.flushInstanceEgress(machined); err != nil {
+						return errors.Annotate(err, "cannot change firewall egress rules")
+					}
+				}
+			}
+		}
+		if retryEgress == nil && len(fw.pendingEgress) > 0 {
+			retryEgress = fw.clk.After(egressRetryDelay)
 		}
 	}
 }
@@ -606,11 +638,18 @@ func (fw *Firewaller) startApplication(app *firewaller.Application) error {
 	if err != nil {
 		return err
 	}
+	egressRules, err := app.EgressRules()
+	if errors.Is(err, errors.NotSupported) {
+		fw.logger.Debugf("controller does not support egress rules for %q", app.Tag())
+	} else if err != nil {
+		return err
+	}
 	applicationd := &applicationData{
 		fw:               fw,
 		application:      app,
 		exposed:          exposed,
 		exposedEndpoints: exposedEndpoints,
+		egressRules:      egressRules,
 		unitds:           make(map[names.UnitTag]*unitData),
 	}
 	fw.applicationids[app.Tag()] = applicationd
@@ -856,9 +895,159 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 	toOpen, toClose := machined.ingressRules.Diff(want)
 	machined.ingressRules = want
 	if fw.globalMode {
+		for _, unitd := range machined.unitds {
+			if len(unitd.applicationd.egressRules) > 0 {
+				fw.logger.Warningf("egress rules for %q are not enforced in global firewall mode",
+					unitd.applicationd.application.Tag())
+			}
+		}
 		return fw.flushGlobalPorts(toOpen, toClose)
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	if err := fw.flushInstancePorts(machined, toOpen, toClose); err != nil {
+		return errors.Trace(err)
+	}
+	return fw.flushInstanceEgress(machined)
+}
+
+// gatherEgressRules returns the egress rules for the specified machine.
+// Outbound traffic is only restricted when every application with units
+// on the machine has an egress allow-list, in which case the machine may
+// reach the destinations allowed for any of them, as well as the
+// controller.
+func (fw *Firewaller) gatherEgressRules(machined *machineData) (firewall.EgressRules, error) {
+	var want firewall.EgressRules
+	for _, unitd := range machined.unitds {
+		if len(unitd.applicationd.egressRules) == 0 {
+			return nil, nil
+		}
+		want = want.Union(unitd.applicationd.egressRules)
+	}
+	if len(want) == 0 {
+		return nil, nil
+	}
+	controllerRules, err := fw.controllerEgressRules()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return want.Union(controllerRules), nil
+}
+
+// controllerEgressRules returns the egress rules needed for agents to
+// reach the controller's API servers.
+func (fw *Firewaller) controllerEgressRules() (firewall.EgressRules, error) {
+	info, err := fw.firewallerApi.ControllerAPIInfoForModel(fw.modelUUID)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting controller API addresses")
+	}
+	var rules firewall.EgressRules
+	for _, addr := range info.Addrs {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			// Provider firewalls only deal in addresses, so
+			// there's nothing to be done for host names.
+			fw.logger.Debugf("not adding egress rule for controller address %q", addr)
+			continue
+		}
+		cidr := ip.String() + "/32"
+		if ip.To4() == nil {
+			cidr = ip.String() + "/128"
+		}
+		portRange, err := network.ParsePortRange(port + "/tcp")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, firewall.NewEgressRule(cidr, portRange))
+	}
+	return rules.Union(nil), nil
+}
+
+// flushInstanceEgress sets the egress rules for the passed machine's
+// instance, if they have changed. Machines that are not yet provisioned
+// are retried later.
+func (fw *Firewaller) flushInstanceEgress(machined *machineData) (err error) {
+	defer func() {
+		if params.IsCodeNotFound(err) {
+			err = nil
+		}
+	}()
+
+	want, err := fw.gatherEgressRules(machined)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if machined.egressFlushed && machined.egressRules.EqualTo(want) {
+		return nil
+	}
+	m, err := machined.machine()
+	if err != nil {
+		return err
+	}
+	instanceId, err := m.InstanceId()
+	if errors.IsNotProvisioned(err) {
+		if len(want) > 0 {
+			fw.pendingEgress[machined.tag] = true
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ctx := fw.cloudCallContextFunc(stdcontext.Background())
+	envInstances, err := fw.environInstances.Instances(ctx, []instance.Id{instanceId})
+	if err == environs.ErrNoInstances {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fwInstance, ok := envInstances[0].(instances.InstanceEgressFirewaller)
+	if !ok {
+		return fw.egressNotSupported(machined, want,
+			errors.NotSupportedf("egress rules for instances of type %T", envInstances[0]))
+	}
+
+	machineId := machined.tag.Id()
+	if !machined.egressFlushed {
+		// Check what's on the instance before changing anything;
+		// there's usually nothing to do when the worker restarts.
+		current, err := fwInstance.EgressRules(ctx, machineId)
+		if errors.Is(err, errors.NotSupported) {
+			return fw.egressNotSupported(machined, want, err)
+		} else if err != nil {
+			return err
+		}
+		machined.egressRules, machined.egressFlushed = current, true
+		if current.EqualTo(want) {
+			return nil
+		}
+	}
+	err = fwInstance.SetEgressRules(ctx, machineId, want)
+	if errors.Is(err, errors.NotSupported) {
+		return fw.egressNotSupported(machined, want, err)
+	} else if err != nil {
+		return err
+	}
+	machined.egressRules = want
+	if len(want) == 0 {
+		fw.logger.Infof("removed egress restrictions on %q", machined.tag)
+	} else {
+		fw.logger.Infof("restricted egress on %q to %v", machined.tag, want)
+	}
+	return nil
+}
+
+// egressNotSupported records that the egress rules for the passed machine
+// can't be enforced, so that it isn't retried until they change.
+func (fw *Firewaller) egressNotSupported(machined *machineData, want firewall.EgressRules, err error) error {
+	if len(want) > 0 {
+		fw.logger.Warningf("cannot restrict egress for %q: %v", machined.tag, err)
+	}
+	machined.egressRules, machined.egressFlushed = want, true
+	return nil
 }
 
 // gatherIngressRules returns the ingress rules to open and close
@@ -1218,6 +1407,10 @@ func (fw *Firewaller) forgetMachine(machined *machineData) error {
 	for _, unitd := range machined.unitds {
 		fw.forgetUnit(unitd)
 	}
+	// The instance is going away, so there's no need to lift any
+	// egress restrictions on it.
+	machined.egressRules, machined.egressFlushed = nil, true
+	delete(fw.pendingEgress, machined.tag)
 	if err := fw.flushMachine(machined); err != nil {
 		return errors.Trace(err)
 	}
@@ -1284,6 +1477,10 @@ type machineData struct {
 	tag          names.MachineTag
 	unitds       map[names.UnitTag]*unitData
 	ingressRules firewall.IngressRules
+	// egressRules holds the egress rules last set on the instance;
+	// they are only known once egressFlushed is true.
+	egressRules   firewall.EgressRules
+	egressFlushed bool
 	// ports defined by units on this machine
 	openedPortRangesByEndpoint map[names.UnitTag]network.GroupedPortRanges
 }
@@ -1340,17 +1537,27 @@ type exposedChange struct {
 	exposedEndpoints map[string]params.ExposedEndpoint
 }
 
-// applicationData holds application details and watches exposure changes.
+// egressChange contains the changed egress rules for one specific
+// application.
+type egressChange struct {
+	applicationd *applicationData
+	rules        firewall.EgressRules
+}
+
+// applicationData holds application details and watches exposure and
+// egress rule changes.
 type applicationData struct {
 	catacomb         catacomb.Catacomb
 	fw               *Firewaller
 	application      *firewaller.Application
 	exposed          bool
 	exposedEndpoints map[string]params.ExposedEndpoint
+	egressRules      firewall.EgressRules
 	unitds           map[names.UnitTag]*unitData
 }
 
-// watchLoop watches the application's exposed flag for changes.
+// watchLoop watches the application's exposed flag and egress rules for
+// changes.
 func (ad *applicationData) watchLoop(curExposed bool, curExposedEndpoints map[string]params.ExposedEndpoint) error {
 	appWatcher, err := ad.application.Watch()
 	if err != nil {
@@ -1362,10 +1569,43 @@ func (ad *applicationData) watchLoop(curExposed bool, curExposedEndpoints map[st
 	if err := ad.catacomb.Add(appWatcher); err != nil {
 		return errors.Trace(err)
 	}
+	var egressChanges watcher.NotifyChannel
+	egressWatcher, err := ad.application.WatchEgressRules()
+	if params.IsCodeNotFound(err) {
+		return nil
+	} else if err != nil && !errors.Is(err, errors.NotSupported) {
+		return errors.Trace(err)
+	} else if err == nil {
+		if err := ad.catacomb.Add(egressWatcher); err != nil {
+			return errors.Trace(err)
+		}
+		egressChanges = egressWatcher.Changes()
+	}
+	curEgressRules := ad.egressRules
 	for {
 		select {
 		case <-ad.catacomb.Dying():
 			return ad.catacomb.ErrDying()
+		case _, ok := <-egressChanges:
+			if !ok {
+				return errors.New("application egress rules watcher closed")
+			}
+			newEgressRules, err := ad.application.EgressRules()
+			if errors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			if curEgressRules.EqualTo(newEgressRules) {
+				continue
+			}
+			ad.fw.logger.Tracef("application(%q) egress rules changed: %v", ad.application.Name(), newEgressRules)
+			curEgressRules = newEgressRules
+			select {
+			case <-ad.catacomb.Dying():
+				return ad.catacomb.ErrDying()
+			case ad.fw.egressChange <- &egressChange{ad, newEgressRules}:
+			}
 		case _, ok := <-appWatcher.Changes():
 			if !ok {
 				return errors.New("application watcher closed")
@@ -6,13 +6,18 @@ package logforwarder
 import (
 	"io"
 	"sync"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/worker/v3/catacomb"
 	"gopkg.in/tomb.v2"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/destination"
 	"github.com/juju/juju/rpc/params"
 )
 
@@ -43,9 +48,6 @@ type sender interface {
 	Send([]logfwd.Record) error
 }
 
-// TODO(ericsnow) It is likely that eventually we will want to support
-// multiplexing to multiple senders, each in its own goroutine (or worker).
-
 // LogForwarder is a worker that forwards log records from a source
 // to a sender.
 type LogForwarder struct {
@@ -70,6 +72,11 @@ type OpenLogForwarderArgs struct {
 	// Name is the name given to the log sink.
 	Name string
 
+	// SinkConfig is the function that extracts the sink's config
+	// from the log forwarding config. If nil, the syslog config is
+	// used.
+	SinkConfig SinkConfigFn
+
 	// OpenSink is the function that opens the underlying log sink that
 	// will be wrapped.
 	OpenSink LogSinkFn
@@ -78,10 +85,22 @@ type OpenLogForwarderArgs struct {
 	// log stream.
 	OpenLogStream LogStreamFn
 
+	// Filter selects the records streamed to the sink. The zero value
+	// selects every record.
+	Filter destination.Filter
+
+	// Clock is used to wait before retrying a failed send.
+	Clock clock.Clock
+
+	// SendBackoff returns how long to wait before the given attempt
+	// to resend records after a failure, given the previous delay.
+	// If nil, a failed send stops the forwarder.
+	SendBackoff func(delay time.Duration, attempt int) time.Duration
+
 	Logger Logger
 }
 
-// processNewConfig acts on a new syslog forward config change.
+// processNewConfig acts on a new log forward config change.
 func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser, error) {
 	lf.mu.Lock()
 	defer lf.mu.Unlock()
@@ -96,13 +115,13 @@ func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser,
 	}
 
 	// Get the new config and set up log forwarding if enabled.
-	cfg, ok, err := lf.args.LogForwardConfig.LogForwardConfig()
+	cfg, enabled, err := lf.args.SinkConfig(lf.args.LogForwardConfig)
 	if err != nil {
 		_ = closeExisting()
 		return nil, errors.Trace(err)
 	}
-	if !ok || !cfg.Enabled {
-		lf.args.Logger.Infof("config change - log forwarding not enabled")
+	if !enabled {
+		lf.args.Logger.Infof("config change - log forwarding to %s not enabled", lf.args.Name)
 		return nil, closeExisting()
 	}
 	// If the config is not valid, we don't want to exit with an error
@@ -127,7 +146,12 @@ func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	lf.enabledCh <- true
+	// If the stream hasn't yet picked up an earlier change, there's
+	// nothing more to tell it.
+	select {
+	case lf.enabledCh <- true:
+	default:
+	}
 	return sink, nil
 }
 
@@ -150,7 +174,7 @@ func (lf *LogForwarder) waitForEnabled() (bool, error) {
 	defer lf.mu.Unlock()
 
 	if !lf.enabled && enabled {
-		lf.args.Logger.Infof("log forward enabled, starting to stream logs to syslog sink")
+		lf.args.Logger.Infof("log forward enabled, starting to stream logs to %s sink", lf.args.Name)
 	}
 	lf.enabled = enabled
 	return enabled, nil
@@ -159,6 +183,12 @@ func (lf *LogForwarder) waitForEnabled() (bool, error) {
 // NewLogForwarder returns a worker that forwards logs received from
 // the stream to the sender.
 func NewLogForwarder(args OpenLogForwarderArgs) (*LogForwarder, error) {
+	if args.SinkConfig == nil {
+		args.SinkConfig = SyslogConfig
+	}
+	if args.SendBackoff != nil && args.Clock == nil {
+		return nil, errors.NotValidf("nil Clock with a SendBackoff")
+	}
 	lf := &LogForwarder{
 		args:      args,
 		enabledCh: make(chan bool, 1),
@@ -201,6 +231,11 @@ func (lf *LogForwarder) loop() error {
 					Sink: lf.args.Name,
 					// TODO(wallyworld) - this should be configurable via lf.args.LogForwardConfig
 					MaxLookbackRecords: 100,
+					IncludeEntity:      lf.args.Filter.Entities,
+					IncludeModule:      lf.args.Filter.Modules,
+				}
+				if lf.args.Filter.Level != loggo.UNSPECIFIED {
+					streamCfg.Level = lf.args.Filter.Level.String()
 				}
 				stream, err = lf.args.OpenLogStream(lf.args.Caller, streamCfg, lf.args.ControllerUUID)
 				if err != nil {
@@ -235,7 +270,7 @@ func (lf *LogForwarder) loop() error {
 			return lf.catacomb.ErrDying()
 		case _, ok := <-configWatcher.Changes():
 			if !ok {
-				return errors.New("syslog configuration watcher closed")
+				return errors.New("log forward configuration watcher closed")
 			}
 			if sender, err = lf.processNewConfig(sender); err != nil {
 				return errors.Trace(err)
@@ -244,13 +279,51 @@ func (lf *LogForwarder) loop() error {
 			if sender == nil {
 				continue
 			}
-			if err := sender.Send(rec); err != nil {
+			if sender, err = lf.send(sender, rec, configWatcher.Changes()); err != nil {
 				return errors.Trace(err)
 			}
 		}
 	}
 }
 
+// send sends the records to the sink. If the send fails and a backoff
+// is configured, the send is retried until it succeeds, so a failing
+// sink only holds up its own log stream. A config change while waiting
+// to retry is applied before the next attempt; if forwarding is then
+// disabled, the records are dropped. The sender to use for subsequent
+// records is returned.
+func (lf *LogForwarder) send(sender SendCloser, records []logfwd.Record, configChanges watcher.NotifyChannel) (SendCloser, error) {
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		err := sender.Send(records)
+		if err == nil {
+			return sender, nil
+		}
+		if lf.args.SendBackoff == nil {
+			return sender, errors.Trace(err)
+		}
+		delay = lf.args.SendBackoff(delay, attempt)
+		lf.args.Logger.Errorf("cannot send log records to %s sink (attempt %d), retrying in %v: %v",
+			lf.args.Name, attempt, delay, err)
+
+		select {
+		case <-lf.catacomb.Dying():
+			return sender, lf.catacomb.ErrDying()
+		case <-lf.args.Clock.After(delay):
+		case _, ok := <-configChanges:
+			if !ok {
+				return sender, errors.New("log forward configuration watcher closed")
+			}
+			if sender, err = lf.processNewConfig(sender); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if sender == nil {
+				return nil, nil
+			}
+		}
+	}
+}
+
 // Kill implements Worker.Kill()
 func (lf *LogForwarder) Kill() {
 	lf.catacomb.Kill(nil)
@@ -6,6 +6,7 @@ package logforwarder_test
 import (
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/testing"
@@ -16,6 +17,8 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/destination"
+	"github.com/juju/juju/logfwd/otlp"
 	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/rpc/params"
 	coretesting "github.com/juju/juju/testing"
@@ -85,8 +88,15 @@ func (s *LogForwarderSuite) newLogForwarderArgsWithAPI(
 		Caller:           &mockCaller{},
 		LogForwardConfig: configAPI,
 		ControllerUUID:   "feebdaed-2f18-4fd2-967d-db9663db7bea",
-		OpenSink: func(cfg *syslog.RawConfig) (*logforwarder.LogSink, error) {
-			sender.host = cfg.Host
+		OpenSink: func(cfg logforwarder.SinkConfig) (*logforwarder.LogSink, error) {
+			switch cfg := cfg.(type) {
+			case *syslog.RawConfig:
+				sender.host = cfg.Host
+			case *otlp.RawConfig:
+				sender.host = cfg.Endpoint
+			default:
+				c.Fatalf("unexpected sink config %T", cfg)
+			}
 			sink := &logforwarder.LogSink{
 				sender,
 			}
@@ -166,6 +176,46 @@ func (s *LogForwarderSuite) TestNotEnabled(c *gc.C) {
 	s.sender.stub.CheckCallNames(c)
 }
 
+func (s *LogForwarderSuite) TestOTLPSink(c *gc.C) {
+	rec := s.rec
+	api := &mockLogForwardConfig{
+		enabled:  true,
+		endpoint: "10.0.0.3:4317",
+	}
+	args := s.newLogForwarderArgsWithAPI(c, api, s.stream, s.sender)
+	args.SinkConfig = logforwarder.OTLPConfig
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	s.stream.addRecords(c, rec)
+	s.sender.waitForSend(c)
+	workertest.CleanKill(c, lf)
+
+	rec.Message = "send to 10.0.0.3:4317"
+	s.sender.stub.CheckCalls(c, []testing.StubCall{
+		{"Send", []interface{}{[]logfwd.Record{rec}}},
+		{"Close", nil},
+	})
+}
+
+func (s *LogForwarderSuite) TestSyslogSinkWithoutHost(c *gc.C) {
+	// Only an OTLP receiver is configured, so the syslog sink
+	// isn't enabled.
+	api := &mockLogForwardConfig{
+		enabled:  true,
+		endpoint: "10.0.0.3:4317",
+	}
+	lf, err := logforwarder.NewLogForwarder(s.newLogForwarderArgsWithAPI(c, api, s.stream, s.sender))
+	c.Assert(err, jc.ErrorIsNil)
+
+	time.Sleep(coretesting.ShortWait)
+	workertest.CleanKill(c, lf)
+
+	s.stream.stub.CheckCallNames(c)
+	s.sender.stub.CheckCallNames(c)
+}
+
 func (s *LogForwarderSuite) TestStreamError(c *gc.C) {
 	failure := errors.New("<failure>")
 	s.stream.stub.SetErrors(nil, failure)
@@ -207,10 +257,74 @@ func (s *LogForwarderSuite) TestSenderError(c *gc.C) {
 	})
 }
 
+func (s *LogForwarderSuite) TestSenderErrorRetried(c *gc.C) {
+	failure := errors.New("<failure>")
+	s.sender.stub.SetErrors(failure)
+	s.stream.addRecords(c, s.rec)
+
+	clock := testclock.NewClock(time.Now())
+	var attempts []int
+	args := s.newLogForwarderArgs(c, s.stream, s.sender)
+	args.Clock = clock
+	args.SendBackoff = func(_ time.Duration, attempt int) time.Duration {
+		attempts = append(attempts, attempt)
+		return time.Minute
+	}
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	s.sender.waitForSend(c)
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.sender.waitForSend(c)
+	workertest.CleanKill(c, lf)
+
+	c.Check(attempts, jc.DeepEquals, []int{1})
+	s.sender.stub.CheckCalls(c, []testing.StubCall{
+		{"Send", []interface{}{[]logfwd.Record{s.rec}}},
+		{"Send", []interface{}{[]logfwd.Record{s.rec}}},
+		{"Close", nil},
+	})
+}
+
+func (s *LogForwarderSuite) TestStreamFilter(c *gc.C) {
+	streamCfgs := make(chan params.LogStreamConfig, 1)
+	args := s.newLogForwarderArgs(c, s.stream, s.sender)
+	args.Filter = destination.Filter{
+		Entities: []string{"unit-mysql-*"},
+		Modules:  []string{"juju.worker.uniter"},
+		Level:    loggo.WARNING,
+	}
+	args.OpenLogStream = func(_ base.APICaller, cfg params.LogStreamConfig, _ string) (logforwarder.LogStream, error) {
+		streamCfgs <- cfg
+		return s.stream, nil
+	}
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	select {
+	case cfg := <-streamCfgs:
+		c.Check(cfg, jc.DeepEquals, params.LogStreamConfig{
+			MaxLookbackRecords: 100,
+			IncludeEntity:      []string{"unit-mysql-*"},
+			IncludeModule:      []string{"juju.worker.uniter"},
+			Level:              "WARNING",
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log stream")
+	}
+	s.stream.addRecords(c, s.rec)
+	s.sender.waitForSend(c)
+	workertest.CleanKill(c, lf)
+}
+
 type mockLogForwardConfig struct {
-	enabled bool
-	host    string
-	changes chan struct{}
+	enabled  bool
+	host     string
+	endpoint string
+	changes  chan struct{}
 }
 
 type mockWatcher struct {
@@ -259,6 +373,18 @@ func (c *mockLogForwardConfig) LogForwardConfig() (*syslog.RawConfig, bool, erro
 	}, true, nil
 }
 
+func (c *mockLogForwardConfig) LogForwardDestinations() ([]destination.Config, error) {
+	return nil, nil
+}
+
+func (c *mockLogForwardConfig) OTLPForwardConfig() (*otlp.RawConfig, bool, error) {
+	return &otlp.RawConfig{
+		Enabled:  c.enabled,
+		Endpoint: c.endpoint,
+		Insecure: true,
+	}, true, nil
+}
+
 type stubStream struct {
 	stub     *testing.Stub
 	nextRecs chan logfwd.Record
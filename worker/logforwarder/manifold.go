@@ -4,6 +4,7 @@
 package logforwarder
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/worker/v3"
 	"github.com/juju/worker/v3/dependency"
@@ -30,6 +31,10 @@ type ManifoldConfig struct {
 	// to which log records will be forwarded.
 	Sinks []LogSinkSpec
 
+	// OpenDestination is the function that opens the log sink for
+	// each named log forwarding destination in the model config.
+	OpenDestination LogSinkFn
+
 	// OpenLogStream is the function that will be used to for the
 	// log stream.
 	OpenLogStream LogStreamFn
@@ -37,6 +42,9 @@ type ManifoldConfig struct {
 	// OpenLogForwarder opens each log forwarder that will be used.
 	OpenLogForwarder func(OpenLogForwarderArgs) (*LogForwarder, error)
 
+	// Clock is used for restart delays and send backoff.
+	Clock clock.Clock
+
 	Logger Logger
 }
 
@@ -79,8 +87,10 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				LogForwardConfig: agentFacade,
 				Caller:           apiCaller,
 				Sinks:            config.Sinks,
+				OpenDestination:  config.OpenDestination,
 				OpenLogStream:    openLogStream,
 				OpenLogForwarder: openForwarder,
+				Clock:            config.Clock,
 				Logger:           config.Logger,
 			})
 			return orchestrator, errors.Annotate(err, "creating log forwarding orchestrator")
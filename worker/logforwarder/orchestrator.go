@@ -4,13 +4,42 @@
 package logforwarder
 
 import (
+	"reflect"
+	"time"
+
+	"github.com/juju/clock"
 	"github.com/juju/errors"
+	"github.com/juju/retry"
+	"github.com/juju/worker/v3"
+	"github.com/juju/worker/v3/catacomb"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/logfwd/destination"
 )
 
+const (
+	// restartDelay is how long to wait before restarting a log
+	// forwarder that has stopped with an error.
+	restartDelay = 10 * time.Second
+
+	// minSendDelay and maxSendDelay bound the backoff between
+	// attempts to resend records to a failing sink.
+	minSendDelay = time.Second
+	maxSendDelay = 5 * time.Minute
+)
+
+// orchestrator runs a log forwarder for each log sink and for each
+// configured log forwarding destination. Each forwarder has its own
+// log stream, checkpoint and backoff, so a failing sink doesn't hold
+// up the others.
 type orchestrator struct {
-	*LogForwarder // For now its just a single forwarder.
+	catacomb catacomb.Catacomb
+	args     OrchestratorArgs
+	runner   *worker.Runner
+
+	// destinations holds the config of the destinations that have a
+	// forwarder running, keyed by name.
+	destinations map[string]destination.Config
 }
 
 // OrchestratorArgs holds the info needed to open a log forwarding
@@ -29,6 +58,10 @@ type OrchestratorArgs struct {
 	// to which log records will be forwarded.
 	Sinks []LogSinkSpec
 
+	// OpenDestination is the function that opens the log sink for a
+	// log forwarding destination. If nil, destinations are ignored.
+	OpenDestination LogSinkFn
+
 	// OpenLogStream is the function that will be used to for the
 	// log stream.
 	OpenLogStream LogStreamFn
@@ -36,26 +69,139 @@ type OrchestratorArgs struct {
 	// OpenLogForwarder opens each log forwarder that will be used.
 	OpenLogForwarder func(OpenLogForwarderArgs) (*LogForwarder, error)
 
+	// Clock is used for restart delays and send backoff.
+	Clock clock.Clock
+
 	Logger Logger
 }
 
 func newOrchestratorForController(args OrchestratorArgs) (*orchestrator, error) {
-	// For now we work with only 1 forwarder. Later we can have a proper
-	// orchestrator that spawns a sub-worker for each log sink.
-	if len(args.Sinks) == 0 {
-		return nil, nil
-	}
-	if len(args.Sinks) > 1 {
-		return nil, errors.Errorf("multiple log forwarding targets not supported (yet)")
-	}
-	lf, err := args.OpenLogForwarder(OpenLogForwarderArgs{
-		ControllerUUID:   args.ControllerUUID,
-		LogForwardConfig: args.LogForwardConfig,
-		Caller:           args.Caller,
-		Name:             args.Sinks[0].Name,
-		OpenSink:         args.Sinks[0].OpenFn,
-		OpenLogStream:    args.OpenLogStream,
-		Logger:           args.Logger,
+	if args.Clock == nil {
+		return nil, errors.NotValidf("nil Clock")
+	}
+	o := &orchestrator{
+		args: args,
+		runner: worker.NewRunner(worker.RunnerParams{
+			IsFatal:      func(error) bool { return false },
+			RestartDelay: restartDelay,
+			Clock:        args.Clock,
+		}),
+		destinations: make(map[string]destination.Config),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &o.catacomb,
+		Work: o.loop,
+		Init: []worker.Worker{o.runner},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return o, nil
+}
+
+func (o *orchestrator) loop() error {
+	for _, spec := range o.args.Sinks {
+		if err := o.startForwarder(spec.Name, spec.Config, spec.OpenFn, destination.Filter{}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if o.args.OpenDestination == nil {
+		<-o.catacomb.Dying()
+		return o.catacomb.ErrDying()
+	}
+
+	configWatcher, err := o.args.LogForwardConfig.WatchForLogForwardConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := o.catacomb.Add(configWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-o.catacomb.Dying():
+			return o.catacomb.ErrDying()
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return errors.New("log forward configuration watcher closed")
+			}
+			if err := o.updateDestinations(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// updateDestinations starts a forwarder for each new destination and
+// stops the forwarders of removed destinations. The forwarder of a
+// changed destination is restarted, since its filters are applied when
+// the log stream is opened.
+func (o *orchestrator) updateDestinations() error {
+	dests, err := o.args.LogForwardConfig.LogForwardDestinations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current := make(map[string]destination.Config)
+	for _, dest := range dests {
+		current[dest.Name] = dest
+	}
+
+	for name, old := range o.destinations {
+		if dest, ok := current[name]; ok && reflect.DeepEqual(dest, old) {
+			continue
+		}
+		o.args.Logger.Infof("stopping log forwarding to destination %q", name)
+		err := o.runner.StopAndRemoveWorker(old.SinkName(), o.catacomb.Dying())
+		if errors.Is(err, worker.ErrAborted) || errors.Is(err, worker.ErrDead) {
+			return errors.Trace(err)
+		}
+		delete(o.destinations, name)
+	}
+
+	for name, dest := range current {
+		if _, ok := o.destinations[name]; ok {
+			continue
+		}
+		o.args.Logger.Infof("starting log forwarding to destination %q", name)
+		err := o.startForwarder(dest.SinkName(), destinationConfig(dest), o.args.OpenDestination, dest.Filter)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		o.destinations[name] = dest
+	}
+	return nil
+}
+
+func (o *orchestrator) startForwarder(name string, sinkConfig SinkConfigFn, openSink LogSinkFn, filter destination.Filter) error {
+	args := OpenLogForwarderArgs{
+		ControllerUUID:   o.args.ControllerUUID,
+		LogForwardConfig: o.args.LogForwardConfig,
+		Caller:           o.args.Caller,
+		Name:             name,
+		SinkConfig:       sinkConfig,
+		OpenSink:         openSink,
+		OpenLogStream:    o.args.OpenLogStream,
+		Filter:           filter,
+		Clock:            o.args.Clock,
+		SendBackoff:      retry.ExpBackoff(minSendDelay, maxSendDelay, 2, true),
+		Logger:           o.args.Logger,
+	}
+	err := o.runner.StartWorker(name, func() (worker.Worker, error) {
+		lf, err := o.args.OpenLogForwarder(args)
+		if err != nil {
+			return nil, errors.Annotatef(err, "opening log forwarder for %q", name)
+		}
+		return lf, nil
 	})
-	return &orchestrator{lf}, errors.Annotate(err, "opening log forwarder")
+	return errors.Trace(err)
+}
+
+// Kill implements Worker.Kill()
+func (o *orchestrator) Kill() {
+	o.catacomb.Kill(nil)
+}
+
+// Wait implements Worker.Wait()
+func (o *orchestrator) Wait() error {
+	return o.catacomb.Wait()
 }
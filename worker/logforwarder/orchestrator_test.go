@@ -0,0 +1,197 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v3"
+	"github.com/juju/worker/v3/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/logfwd/destination"
+	"github.com/juju/juju/logfwd/otlp"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type OrchestratorSuite struct {
+	testing.IsolationSuite
+
+	api     *mockDestinationsConfig
+	started chan logforwarder.OpenLogForwarderArgs
+}
+
+var _ = gc.Suite(&OrchestratorSuite{})
+
+func (s *OrchestratorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.api = &mockDestinationsConfig{}
+	s.started = make(chan logforwarder.OpenLogForwarderArgs, 10)
+}
+
+func (s *OrchestratorSuite) newOrchestrator(c *gc.C) worker.Worker {
+	openSink := func(logforwarder.SinkConfig) (*logforwarder.LogSink, error) {
+		return &logforwarder.LogSink{newStubSender()}, nil
+	}
+	o, err := logforwarder.NewOrchestratorForController(logforwarder.OrchestratorArgs{
+		ControllerUUID:   "feebdaed-2f18-4fd2-967d-db9663db7bea",
+		LogForwardConfig: s.api,
+		Caller:           &mockCaller{},
+		Sinks: []logforwarder.LogSinkSpec{{
+			Name:   "juju-log-forward",
+			OpenFn: openSink,
+		}},
+		OpenDestination: openSink,
+		OpenLogStream: func(base.APICaller, params.LogStreamConfig, string) (logforwarder.LogStream, error) {
+			return newStubStream(), nil
+		},
+		OpenLogForwarder: func(args logforwarder.OpenLogForwarderArgs) (*logforwarder.LogForwarder, error) {
+			s.started <- args
+			return logforwarder.NewLogForwarder(args)
+		},
+		Clock:  testclock.NewClock(time.Now()),
+		Logger: loggo.GetLogger("test"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return o
+}
+
+func (s *OrchestratorSuite) waitForStarted(c *gc.C, count int) []logforwarder.OpenLogForwarderArgs {
+	var started []logforwarder.OpenLogForwarderArgs
+	for len(started) < count {
+		select {
+		case args := <-s.started:
+			started = append(started, args)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for log forwarders to start")
+		}
+	}
+	sort.Slice(started, func(i, j int) bool {
+		return started[i].Name < started[j].Name
+	})
+	return started
+}
+
+func (s *OrchestratorSuite) assertNoneStarted(c *gc.C) {
+	select {
+	case args := <-s.started:
+		c.Fatalf("unexpected log forwarder %q started", args.Name)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func destinationConfig(name, endpoint string, filter destination.Filter) destination.Config {
+	return destination.Config{
+		Name:   name,
+		Filter: filter,
+		OTLP: &otlp.RawConfig{
+			Endpoint: endpoint,
+			Insecure: true,
+		},
+	}
+}
+
+func (s *OrchestratorSuite) TestStartsForwarderPerDestination(c *gc.C) {
+	siem := destinationConfig("siem", "siem.example.com:4317", destination.Filter{
+		Entities: []string{"machine-0"},
+		Level:    loggo.ERROR,
+	})
+	mysql := destinationConfig("mysql", "otel.example.com:4317", destination.Filter{
+		Entities: []string{"unit-mysql-*"},
+	})
+	s.api.setDestinations(siem, mysql)
+
+	o := s.newOrchestrator(c)
+	defer workertest.CleanKill(c, o)
+
+	started := s.waitForStarted(c, 3)
+	c.Check(started[0].Name, gc.Equals, "juju-log-forward")
+	c.Check(started[0].Filter, jc.DeepEquals, destination.Filter{})
+	c.Check(started[1].Name, gc.Equals, "juju-log-forward-destination-mysql")
+	c.Check(started[1].Filter, jc.DeepEquals, mysql.Filter)
+	c.Check(started[2].Name, gc.Equals, "juju-log-forward-destination-siem")
+	c.Check(started[2].Filter, jc.DeepEquals, siem.Filter)
+	for _, args := range started {
+		c.Check(args.SendBackoff, gc.NotNil)
+	}
+
+	cfg, enabled, err := started[2].SinkConfig(s.api)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(enabled, jc.IsFalse)
+	c.Check(cfg, gc.Equals, siem.OTLP)
+}
+
+func (s *OrchestratorSuite) TestDestinationsChange(c *gc.C) {
+	siem := destinationConfig("siem", "siem.example.com:4317", destination.Filter{})
+	mysql := destinationConfig("mysql", "otel.example.com:4317", destination.Filter{})
+	s.api.setDestinations(siem, mysql)
+
+	o := s.newOrchestrator(c)
+	defer workertest.CleanKill(c, o)
+	s.waitForStarted(c, 3)
+
+	// An unchanged config doesn't restart anything.
+	s.api.setDestinations(siem, mysql)
+	s.assertNoneStarted(c)
+
+	// Changing the filter of one destination and removing the other
+	// only restarts the changed one.
+	siem.Filter.Level = loggo.WARNING
+	s.api.setDestinations(siem)
+	started := s.waitForStarted(c, 1)
+	c.Check(started[0].Name, gc.Equals, "juju-log-forward-destination-siem")
+	c.Check(started[0].Filter.Level, gc.Equals, loggo.WARNING)
+	s.assertNoneStarted(c)
+
+	// Adding it back starts it again.
+	s.api.setDestinations(siem, mysql)
+	started = s.waitForStarted(c, 1)
+	c.Check(started[0].Name, gc.Equals, "juju-log-forward-destination-mysql")
+}
+
+type mockDestinationsConfig struct {
+	mockLogForwardConfig
+
+	mu       sync.Mutex
+	dests    []destination.Config
+	watchers []chan struct{}
+}
+
+func (c *mockDestinationsConfig) WatchForLogForwardConfigChanges() (watcher.NotifyWatcher, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changes := make(chan struct{}, 1)
+	changes <- struct{}{}
+	c.watchers = append(c.watchers, changes)
+	return &mockWatcher{changes: changes}, nil
+}
+
+func (c *mockDestinationsConfig) LogForwardDestinations() ([]destination.Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dests, nil
+}
+
+func (c *mockDestinationsConfig) setDestinations(dests ...destination.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dests = dests
+	for _, changes := range c.watchers {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -4,7 +4,11 @@
 package logforwarder
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/logfwd/destination"
+	"github.com/juju/juju/logfwd/otlp"
 	"github.com/juju/juju/logfwd/syslog"
 )
 
@@ -14,20 +18,83 @@ type LogForwardConfig interface {
 	// log forward configuration to change.
 	WatchForLogForwardConfigChanges() (watcher.NotifyWatcher, error)
 
-	// LogForwardConfig returns the current log forward configuration.
+	// LogForwardConfig returns the current syslog forward configuration.
 	LogForwardConfig() (*syslog.RawConfig, bool, error)
+
+	// OTLPForwardConfig returns the current OTLP forward configuration.
+	OTLPForwardConfig() (*otlp.RawConfig, bool, error)
+
+	// LogForwardDestinations returns the current named log forwarding
+	// destinations.
+	LogForwardDestinations() ([]destination.Config, error)
 }
 
 type LogSinkSpec struct {
 	// Name is the name of the log sink.
 	Name string
 
+	// Config is a function that extracts the sink's config from the
+	// log forwarding config. If nil, the syslog config is used.
+	Config SinkConfigFn
+
 	// OpenFn is a function that opens a log sink.
 	OpenFn LogSinkFn
 }
 
+// SinkConfig is the config used to open a single log sink.
+type SinkConfig interface {
+	// Validate ensures that the config is valid.
+	Validate() error
+}
+
+// SinkConfigFn is a function that returns the config for a log sink,
+// and whether forwarding to that sink is enabled.
+type SinkConfigFn func(LogForwardConfig) (SinkConfig, bool, error)
+
+// SyslogConfig returns the syslog sink config. Forwarding to syslog is
+// enabled if log forwarding is enabled and a syslog host is set.
+func SyslogConfig(api LogForwardConfig) (SinkConfig, bool, error) {
+	cfg, ok, err := api.LogForwardConfig()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if !ok || !cfg.Enabled || cfg.Host == "" {
+		return nil, false, nil
+	}
+	return cfg, true, nil
+}
+
+// OTLPConfig returns the OTLP sink config. Forwarding to an OTLP
+// receiver is enabled if log forwarding is enabled and an OTLP
+// endpoint is set.
+func OTLPConfig(api LogForwardConfig) (SinkConfig, bool, error) {
+	cfg, ok, err := api.OTLPForwardConfig()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if !ok || !cfg.Enabled || cfg.Endpoint == "" {
+		return nil, false, nil
+	}
+	return cfg, true, nil
+}
+
+// destinationConfig returns a SinkConfigFn for the target of the
+// destination. Forwarding to the destination is enabled if log
+// forwarding is enabled.
+func destinationConfig(dest destination.Config) SinkConfigFn {
+	return func(LogForwardConfig) (SinkConfig, bool, error) {
+		if dest.Syslog != nil {
+			return dest.Syslog, dest.Syslog.Enabled, nil
+		}
+		if dest.OTLP != nil {
+			return dest.OTLP, dest.OTLP.Enabled, nil
+		}
+		return nil, false, errors.NotValidf("destination %q without a target", dest.Name)
+	}
+}
+
 // LogSinkFn is a function that opens a log sink.
-type LogSinkFn func(cfg *syslog.RawConfig) (*LogSink, error)
+type LogSinkFn func(cfg SinkConfig) (*LogSink, error)
 
 // LogSink is a single log sink, to which log records may be sent.
 type LogSink struct {
@@ -0,0 +1,30 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd/otlp"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+// OpenOTLP returns a sink used to forward log messages to an OTLP
+// logs receiver.
+func OpenOTLP(cfg logforwarder.SinkConfig) (*logforwarder.LogSink, error) {
+	otlpCfg, ok := cfg.(*otlp.RawConfig)
+	if !ok {
+		return nil, errors.Errorf("expected OTLP config, got %T", cfg)
+	}
+	if !otlpCfg.Enabled {
+		return nil, errors.New("log forwarding not enabled")
+	}
+	client, err := otlp.Open(*otlpCfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &logforwarder.LogSink{
+		SendCloser: client,
+	}, nil
+}
@@ -7,16 +7,35 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/otlp"
 	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/worker/logforwarder"
 )
 
+// Open returns a sink for the given config, which may be either a
+// syslog or an OTLP config. It is used to open the sinks of named log
+// forwarding destinations.
+func Open(cfg logforwarder.SinkConfig) (*logforwarder.LogSink, error) {
+	switch cfg.(type) {
+	case *syslog.RawConfig:
+		return OpenSyslog(cfg)
+	case *otlp.RawConfig:
+		return OpenOTLP(cfg)
+	default:
+		return nil, errors.NotSupportedf("log sink config %T", cfg)
+	}
+}
+
 // OpenSyslog returns a sink used to receive log messages to be forwarded.
-func OpenSyslog(cfg *syslog.RawConfig) (*logforwarder.LogSink, error) {
-	if !cfg.Enabled {
+func OpenSyslog(cfg logforwarder.SinkConfig) (*logforwarder.LogSink, error) {
+	syslogCfg, ok := cfg.(*syslog.RawConfig)
+	if !ok {
+		return nil, errors.Errorf("expected syslog config, got %T", cfg)
+	}
+	if !syslogCfg.Enabled {
 		return nil, errors.New("log forwarding not enabled")
 	}
-	client, err := syslog.Open(*cfg)
+	client, err := syslog.Open(*syslogCfg)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
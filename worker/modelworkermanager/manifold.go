@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver/apiserverhttp"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/pki"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/common"
@@ -34,6 +35,7 @@ type ManifoldConfig struct {
 	StateName      string
 	MuxName        string
 	SyslogName     string
+	TracerName     string
 	Clock          clock.Clock
 	NewWorker      func(Config) (worker.Worker, error)
 	NewModelWorker NewModelWorkerFunc
@@ -58,6 +60,9 @@ func (config ManifoldConfig) Validate() error {
 	if config.SyslogName == "" {
 		return errors.NotValidf("empty SyslogName")
 	}
+	if config.TracerName == "" {
+		return errors.NotValidf("empty TracerName")
+	}
 	if config.NewWorker == nil {
 		return errors.NotValidf("nil NewWorker")
 	}
@@ -82,6 +87,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			config.MuxName,
 			config.StateName,
 			config.SyslogName,
+			config.TracerName,
 		},
 		Start: config.start,
 	}
@@ -112,6 +118,11 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		return nil, errors.Trace(err)
 	}
 
+	var tracer trace.Tracer
+	if err := context.Get(config.TracerName, &tracer); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	var stTracker workerstate.StateTracker
 	if err := context.Get(config.StateName, &stTracker); err != nil {
 		return nil, errors.Trace(err)
@@ -135,6 +146,7 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		ModelWatcher: systemState,
 		ModelMetrics: config.ModelMetrics,
 		Mux:          mux,
+		Tracer:       tracer,
 		Controller: StatePoolController{
 			StatePool: statePool,
 			SysLogger: sysLogger,
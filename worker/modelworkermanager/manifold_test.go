@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver/apiserverhttp"
 	corelogger "github.com/juju/juju/core/logger"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/pki"
 	pkitest "github.com/juju/juju/pki/test"
 	"github.com/juju/juju/state"
@@ -59,6 +60,7 @@ func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 		StateName:      "state",
 		MuxName:        "mux",
 		SyslogName:     "syslog",
+		TracerName:     "tracer",
 		NewWorker:      s.newWorker,
 		NewModelWorker: s.newModelWorker,
 		ModelMetrics:   dummyModelMetrics{},
@@ -75,6 +77,7 @@ func (s *ManifoldSuite) newContext(overlay map[string]interface{}) dependency.Co
 		"mux":       mux,
 		"state":     &s.stateTracker,
 		"syslog":    s.sysLogger,
+		"tracer":    trace.NoopTracer{},
 	}
 	for k, v := range overlay {
 		resources[k] = v
@@ -98,7 +101,7 @@ func (s *ManifoldSuite) newModelWorker(config modelworkermanager.NewModelConfig)
 	return worker.NewRunner(worker.RunnerParams{}), nil
 }
 
-var expectedInputs = []string{"agent", "authority", "mux", "state", "syslog"}
+var expectedInputs = []string{"agent", "authority", "mux", "state", "syslog", "tracer"}
 
 func (s *ManifoldSuite) TestInputs(c *gc.C) {
 	c.Assert(s.manifold.Inputs, jc.SameContents, expectedInputs)
@@ -144,6 +147,7 @@ func (s *ManifoldSuite) TestStart(c *gc.C) {
 		ModelWatcher: s.State,
 		ModelMetrics: dummyModelMetrics{},
 		Mux:          mux,
+		Tracer:       trace.NoopTracer{},
 		Controller: modelworkermanager.StatePoolController{
 			StatePool: s.StatePool,
 			SysLogger: s.sysLogger,
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/cmd/jujud/agent/engine"
 	"github.com/juju/juju/controller"
 	corelogger "github.com/juju/juju/core/logger"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/pki"
 	"github.com/juju/juju/state"
 )
@@ -76,6 +77,7 @@ type NewModelConfig struct {
 	ModelMetrics     engine.MetricSink
 	Mux              *apiserverhttp.Mux
 	ControllerConfig controller.Config
+	Tracer           trace.Tracer
 }
 
 // NewModelWorkerFunc should return a worker responsible for running
@@ -93,6 +95,7 @@ type Config struct {
 	ModelWatcher   ModelWatcher
 	ModelMetrics   ModelMetrics
 	Mux            *apiserverhttp.Mux
+	Tracer         trace.Tracer
 	Controller     Controller
 	NewModelWorker NewModelWorkerFunc
 	ErrorDelay     time.Duration
@@ -119,6 +122,9 @@ func (config Config) Validate() error {
 	if config.ModelMetrics == nil {
 		return errors.NotValidf("nil ModelMetrics")
 	}
+	if config.Tracer == nil {
+		return errors.NotValidf("nil Tracer")
+	}
 	if config.Controller == nil {
 		return errors.NotValidf("nil Controller")
 	}
@@ -218,6 +224,7 @@ func (m *modelWorkerManager) loop() error {
 			ModelMetrics:     m.config.ModelMetrics.ForModel(names.NewModelTag(modelUUID)),
 			Mux:              m.config.Mux,
 			ControllerConfig: controllerConfig,
+			Tracer:           m.config.Tracer,
 		}
 		return errors.Trace(m.ensure(cfg))
 	}
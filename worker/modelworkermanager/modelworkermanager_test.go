@@ -20,6 +20,7 @@ import (
 
 	"github.com/juju/juju/cmd/jujud/agent/engine"
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/pki"
 	pkitest "github.com/juju/juju/pki/test"
 	"github.com/juju/juju/state"
@@ -194,6 +195,7 @@ func (s *suite) runKillTest(c *gc.C, kill killFunc, test testFunc) {
 		Logger:         loggo.GetLogger("test"),
 		MachineID:      "1",
 		ModelWatcher:   watcher,
+		Tracer:         trace.NoopTracer{},
 		Controller:     controller,
 		NewModelWorker: s.startModelWorker,
 		ModelMetrics:   dummyModelMetrics{},
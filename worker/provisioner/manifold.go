@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/agent"
 	apiprovisioner "github.com/juju/juju/api/agent/provisioner"
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/worker/common"
 )
@@ -35,7 +36,11 @@ type ManifoldConfig struct {
 	EnvironName   string
 	Logger        Logger
 
-	NewProvisionerFunc           func(*apiprovisioner.State, agent.Config, Logger, environs.Environ, common.CredentialAPI) (Provisioner, error)
+	// Tracer is used to record spans around calls to the
+	// environ. If nil, no spans are recorded.
+	Tracer trace.Tracer
+
+	NewProvisionerFunc           func(*apiprovisioner.State, agent.Config, Logger, environs.Environ, common.CredentialAPI, trace.Tracer) (Provisioner, error)
 	NewCredentialValidatorFacade func(base.APICaller) (common.CredentialAPI, error)
 }
 
@@ -72,7 +77,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				return nil, errors.Trace(err)
 			}
 
-			w, err := config.NewProvisionerFunc(api, agentConfig, config.Logger, environ, credentialAPI)
+			w, err := config.NewProvisionerFunc(api, agentConfig, config.Logger, environ, credentialAPI, config.Tracer)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
@@ -16,6 +16,7 @@ import (
 	apiprovisioner "github.com/juju/juju/api/agent/provisioner"
 	"github.com/juju/juju/api/base"
 	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/worker/common"
 	"github.com/juju/juju/worker/provisioner"
@@ -35,6 +36,7 @@ func (s *ManifoldSuite) makeManifold() dependency.Manifold {
 		provisioner.Logger,
 		environs.Environ,
 		common.CredentialAPI,
+		trace.Tracer,
 	) (provisioner.Provisioner, error) {
 		s.stub.AddCall("NewProvisionerFunc")
 		return struct{ provisioner.Provisioner }{}, nil
@@ -4,6 +4,7 @@
 package provisioner
 
 import (
+	stdcontext "context"
 	"sync"
 	"time"
 
@@ -16,9 +17,11 @@ import (
 	apiprovisioner "github.com/juju/juju/api/agent/provisioner"
 	"github.com/juju/juju/controller/authentication"
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/worker/common"
 )
 
@@ -194,10 +197,15 @@ func NewEnvironProvisioner(
 	logger Logger,
 	environ environs.Environ,
 	credentialAPI common.CredentialAPI,
+	tracer trace.Tracer,
 ) (Provisioner, error) {
 	if logger == nil {
 		return nil, errors.NotValidf("missing logger")
 	}
+	if tracer == nil {
+		tracer = trace.NoopTracer{}
+	}
+	callContextFunc := common.NewCloudCallContextFunc(credentialAPI)
 	p := &environProvisioner{
 		provisioner: provisioner{
 			st:                      st,
@@ -205,7 +213,9 @@ func NewEnvironProvisioner(
 			logger:                  logger,
 			toolsFinder:             getToolsFinder(st),
 			distributionGroupFinder: getDistributionGroupFinder(st),
-			callContextFunc:         common.NewCloudCallContextFunc(credentialAPI),
+			callContextFunc: func(ctx stdcontext.Context) context.ProviderCallContext {
+				return callContextFunc(trace.WithTracer(ctx, tracer))
+			},
 		},
 		environ: environ,
 	}
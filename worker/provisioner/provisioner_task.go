@@ -32,6 +32,7 @@ import (
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/core/workerpool"
 	"github.com/juju/juju/environs"
@@ -375,7 +376,10 @@ func instanceIds(instances []instances.Instance) []string {
 // populateMachineMaps updates task.instances. Also updates task.machines map
 // if a list of IDs is given.
 func (task *provisionerTask) populateMachineMaps(ctx context.ProviderCallContext, ids []string) error {
-	allInstances, err := task.broker.AllRunningInstances(ctx)
+	spanCtx, span := context.StartSpan(ctx, "broker.AllRunningInstances")
+	allInstances, err := task.broker.AllRunningInstances(spanCtx)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		return errors.Annotate(err, "getting all instances from broker")
 	}
@@ -753,7 +757,13 @@ func (task *provisionerTask) doStopInstances(ctx context.ProviderCallContext, in
 	for i, inst := range instances {
 		ids[i] = inst.Id()
 	}
-	if err := task.broker.StopInstances(ctx, ids...); err != nil {
+	spanCtx, span := context.StartSpan(ctx, "broker.StopInstances",
+		trace.IntAttr("instances", len(ids)),
+	)
+	err := task.broker.StopInstances(spanCtx, ids...)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
 		return errors.Annotate(err, "stopping instances")
 	}
 	return nil
@@ -851,6 +861,7 @@ func (task *provisionerTask) constructStartInstanceParams(
 			Provider:     storage.ProviderType(v.Provider),
 			Attributes:   v.Attributes,
 			ResourceTags: v.Tags,
+			SnapshotId:   v.SnapshotId,
 			Attachment: &storage.VolumeAttachmentParams{
 				AttachmentParams: storage.AttachmentParams{
 					Machine:  machineTag,
@@ -1375,7 +1386,13 @@ func (task *provisionerTask) doStartMachine(
 				machine, startInstanceParams.AvailabilityZone)
 		}
 
-		attemptResult, err := task.broker.StartInstance(ctx, startInstanceParams)
+		spanCtx, span := context.StartSpan(ctx, "broker.StartInstance",
+			trace.StringAttr("machine", machine.Id()),
+			trace.StringAttr("availability-zone", startInstanceParams.AvailabilityZone),
+		)
+		attemptResult, err := task.broker.StartInstance(spanCtx, startInstanceParams)
+		span.RecordError(err)
+		span.End()
 		if err == nil {
 			result = attemptResult
 			break
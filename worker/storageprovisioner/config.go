@@ -28,6 +28,8 @@ type Config struct {
 	Applications         ApplicationWatcher
 	Volumes              VolumeAccessor
 	Filesystems          FilesystemAccessor
+	Snapshots            VolumeSnapshotAccessor
+	Resizes              VolumeResizeAccessor
 	Life                 LifecycleManager
 	Registry             storage.ProviderRegistry
 	Machines             MachineAccessor
@@ -35,6 +37,13 @@ type Config struct {
 	Clock                clock.Clock
 	Logger               Logger
 	CloudCallContextFunc common.CloudCallContextFunc
+
+	// GrowFilesystem grows the filesystem mounted at the specified
+	// path to fill its resized block device, returning the new size
+	// of the filesystem in MiB. It is only used by machine-scoped
+	// storage provisioners, and may be nil if growing filesystems
+	// is not supported.
+	GrowFilesystem func(mountPoint string, size uint64) (uint64, error)
 }
 
 // Validate returns an error if the config cannot be relied upon to start a worker.
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/cmd/jujud/agent/engine"
 	"github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/worker/common"
+	"github.com/juju/juju/worker/diskmanager"
 )
 
 // MachineManifoldConfig defines a storage provisioner's configuration and dependencies.
@@ -58,6 +59,8 @@ func (config MachineManifoldConfig) newWorker(a agent.Agent, apiCaller base.APIC
 		StorageDir:           storageDir,
 		Volumes:              api,
 		Filesystems:          api,
+		Snapshots:            api,
+		Resizes:              api,
 		Life:                 api,
 		Registry:             provider.CommonStorageProviders(),
 		Machines:             api,
@@ -65,6 +68,7 @@ func (config MachineManifoldConfig) newWorker(a agent.Agent, apiCaller base.APIC
 		Clock:                config.Clock,
 		Logger:               config.Logger,
 		CloudCallContextFunc: common.NewCloudCallContextFunc(credentialAPI),
+		GrowFilesystem:       diskmanager.DefaultGrowFilesystem,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
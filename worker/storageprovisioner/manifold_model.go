@@ -60,6 +60,8 @@ func ModelManifold(config ModelManifoldConfig) dependency.Manifold {
 				Applications:         api,
 				Volumes:              api,
 				Filesystems:          api,
+				Snapshots:            api,
+				Resizes:              api,
 				Life:                 api,
 				Registry:             registry,
 				Machines:             api,
@@ -0,0 +1,262 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	stdcontext "context"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeResizesChanged is called when the volumes with the provided
+// IDs have been seen to have changed, so that any pending resizes may
+// be carried out.
+//
+// Volumes are resized in the storage provider, and the new sizes are
+// then recorded in state. Resizes are not retried; any failure is
+// recorded in the volume's status, and the resize may be requested
+// again.
+func volumeResizesChanged(ctx *context, ids []string) error {
+	ctx.config.Logger.Debugf("volume resizes changed: %v", ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	tags := make([]names.VolumeTag, len(ids))
+	for i, id := range ids {
+		tags[i] = names.NewVolumeTag(id)
+	}
+	results, err := ctx.config.Resizes.VolumeResizeParams(tags)
+	if err != nil {
+		return errors.Annotate(err, "getting volume resize params")
+	}
+
+	var statuses []params.EntityStatusArgs
+	setError := func(tag string, err error) {
+		statuses = append(statuses, params.EntityStatusArgs{
+			Tag:    tag,
+			Status: status.Error.String(),
+			Info:   err.Error(),
+		})
+	}
+	var resized []params.Volume
+	for i, result := range results {
+		if result.Error != nil {
+			if isStorageEntityGone(result.Error) {
+				continue
+			}
+			return errors.Annotatef(result.Error, "getting resize params for %s", names.ReadableString(tags[i]))
+		}
+		p := result.Result
+		if p.Size == 0 || p.VolumeId == "" {
+			// No resize pending, or the volume has not yet
+			// been provisioned.
+			continue
+		}
+		volume, err := resizeVolume(ctx, p)
+		if errors.Cause(err) == errNonDynamic {
+			// Non-dynamic volumes are not managed by the
+			// storage provisioner.
+			continue
+		} else if err != nil {
+			setError(p.VolumeTag, err)
+			continue
+		}
+		resized = append(resized, volume)
+	}
+	setStatus(ctx, statuses)
+	if len(resized) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Resizes.FinishVolumeResizes(resized)
+	if err != nil {
+		return errors.Annotate(err, "publishing resized volumes to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "publishing resized volume %q to state", resized[i].VolumeTag)
+		}
+	}
+	return nil
+}
+
+// resizeVolume resizes a single volume in the storage provider,
+// returning the volume's new details.
+//
+// The volume source is configured with the attributes of the
+// volume's storage pool, as the pool may determine where the
+// volume lives; for example, the LVM volume group.
+func resizeVolume(ctx *context, p params.VolumeResizeParams) (params.Volume, error) {
+	tag, err := names.ParseVolumeTag(p.VolumeTag)
+	if err != nil {
+		return params.Volume{}, errors.Trace(err)
+	}
+	providerType := storage.ProviderType(p.Provider)
+	provider, err := ctx.config.Registry.StorageProvider(providerType)
+	if err != nil {
+		return params.Volume{}, errors.Annotate(err, "getting provider")
+	}
+	if !provider.Dynamic() {
+		return params.Volume{}, errNonDynamic
+	}
+	attrs := make(map[string]interface{})
+	for k, v := range p.Attributes {
+		attrs[k] = v
+	}
+	if ctx.config.StorageDir != "" {
+		attrs[storage.ConfigStorageDir] = filepath.Join(ctx.config.StorageDir, p.Provider)
+	}
+	sourceConfig, err := storage.NewConfig(p.Provider, providerType, attrs)
+	if err != nil {
+		return params.Volume{}, errors.Annotate(err, "getting config")
+	}
+	source, err := provider.VolumeSource(sourceConfig)
+	if err != nil {
+		return params.Volume{}, errors.Annotatef(err, "getting storage source %q", p.Provider)
+	}
+	resizer, ok := source.(storage.VolumeResizer)
+	if !ok {
+		return params.Volume{}, errors.NotSupportedf("resizing volumes with storage provider %q", p.Provider)
+	}
+
+	ctx.config.Logger.Debugf("resizing volume %s to %dMiB with %q", tag.Id(), p.Size, p.Provider)
+	results, err := resizer.ResizeVolumes(
+		ctx.config.CloudCallContextFunc(stdcontext.Background()),
+		[]storage.VolumeResizeParams{{
+			Tag:      tag,
+			VolumeId: p.VolumeId,
+			Size:     p.Size,
+		}},
+	)
+	if err != nil {
+		return params.Volume{}, errors.Annotate(err, "resizing volume")
+	}
+	if len(results) != 1 {
+		return params.Volume{}, errors.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Error != nil {
+		return params.Volume{}, errors.Annotate(results[0].Error, "resizing volume")
+	}
+	info := results[0].Info
+	return params.Volume{
+		VolumeTag: tag.String(),
+		Info: params.VolumeInfo{
+			VolumeId:   info.VolumeId,
+			HardwareId: info.HardwareId,
+			WWN:        info.WWN,
+			Size:       info.Size,
+			Persistent: info.Persistent,
+		},
+	}, nil
+}
+
+// filesystemResizesChanged is called when the filesystems with the
+// provided IDs have been seen to have changed, so that filesystems
+// whose backing volumes have been resized may be grown to fill them.
+//
+// If the backing volume's block device has not yet grown on the
+// machine, the filesystem is recorded as pending and retried when
+// the machine's block devices next change.
+func filesystemResizesChanged(ctx *context, ids []string) error {
+	ctx.config.Logger.Debugf("filesystem resizes changed: %v", ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	machineTag, ok := ctx.config.Scope.(names.MachineTag)
+	if !ok {
+		return errors.NotSupportedf("growing filesystems for %s", names.ReadableString(ctx.config.Scope))
+	}
+	storageIds := make([]params.MachineStorageId, len(ids))
+	for i, id := range ids {
+		storageIds[i] = params.MachineStorageId{
+			MachineTag:    machineTag.String(),
+			AttachmentTag: names.NewFilesystemTag(id).String(),
+		}
+	}
+	results, err := ctx.config.Resizes.FilesystemResizeParams(storageIds)
+	if err != nil {
+		return errors.Annotate(err, "getting filesystem resize params")
+	}
+
+	var statuses []params.EntityStatusArgs
+	var grown []params.Filesystem
+	for i, result := range results {
+		tag := names.NewFilesystemTag(ids[i])
+		if result.Error != nil {
+			if isStorageEntityGone(result.Error) {
+				ctx.pendingFilesystemResizes.Remove(tag)
+				continue
+			}
+			return errors.Annotatef(result.Error, "getting resize params for %s", names.ReadableString(tag))
+		}
+		p := result.Result
+		if p.Size == 0 || p.MountPoint == "" {
+			// No resize pending, or the filesystem is
+			// not yet mounted.
+			ctx.pendingFilesystemResizes.Remove(tag)
+			continue
+		}
+		ctx.config.Logger.Debugf("growing filesystem %s at %q to %dMiB", tag.Id(), p.MountPoint, p.Size)
+		size, err := ctx.config.GrowFilesystem(p.MountPoint, p.Size)
+		if errors.IsNotYetAvailable(err) {
+			ctx.config.Logger.Debugf("filesystem %s not grown: %v", tag.Id(), err)
+			ctx.pendingFilesystemResizes.Add(tag)
+			continue
+		}
+		ctx.pendingFilesystemResizes.Remove(tag)
+		if err != nil {
+			statuses = append(statuses, params.EntityStatusArgs{
+				Tag:    p.FilesystemTag,
+				Status: status.Error.String(),
+				Info:   errors.Annotate(err, "growing filesystem").Error(),
+			})
+			continue
+		}
+		grown = append(grown, params.Filesystem{
+			FilesystemTag: p.FilesystemTag,
+			VolumeTag:     p.VolumeTag,
+			Info:          params.FilesystemInfo{Size: size},
+		})
+	}
+	setStatus(ctx, statuses)
+	if len(grown) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Resizes.FinishFilesystemResizes(grown)
+	if err != nil {
+		return errors.Annotate(err, "publishing grown filesystems to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "publishing grown filesystem %q to state", grown[i].FilesystemTag)
+		}
+	}
+	return nil
+}
+
+// processPendingFilesystemResizes is called when the machine's block
+// devices change, to retry growing filesystems whose backing volumes'
+// block devices had not previously grown.
+func processPendingFilesystemResizes(ctx *context) error {
+	if len(ctx.pendingFilesystemResizes) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(ctx.pendingFilesystemResizes))
+	for _, tag := range ctx.pendingFilesystemResizes.SortedValues() {
+		ids = append(ids, tag.Id())
+	}
+	return filesystemResizesChanged(ctx, ids)
+}
+
+// isStorageEntityGone reports whether the given error indicates that
+// a storage entity has been removed, or is no longer the concern of
+// this storage provisioner.
+func isStorageEntityGone(err *params.Error) bool {
+	return params.IsCodeNotFound(err) || params.IsCodeUnauthorized(err) || params.IsCodeNotProvisioned(err)
+}
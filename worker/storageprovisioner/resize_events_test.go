@@ -0,0 +1,276 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/storage"
+)
+
+type mockVolumeResizeAccessor struct {
+	volumesWatcher     *mockStringsWatcher
+	filesystemsWatcher *mockStringsWatcher
+	volumes            map[string]params.VolumeResizeParams
+	filesystems        map[string]params.FilesystemResizeParams
+
+	finishVolumeResizes     func([]params.Volume) ([]params.ErrorResult, error)
+	finishFilesystemResizes func([]params.Filesystem) ([]params.ErrorResult, error)
+}
+
+func newMockVolumeResizeAccessor() *mockVolumeResizeAccessor {
+	return &mockVolumeResizeAccessor{
+		volumesWatcher:     newMockStringsWatcher(),
+		filesystemsWatcher: newMockStringsWatcher(),
+		volumes:            make(map[string]params.VolumeResizeParams),
+		filesystems:        make(map[string]params.FilesystemResizeParams),
+	}
+}
+
+func (m *mockVolumeResizeAccessor) WatchVolumeResizes(names.Tag) (watcher.StringsWatcher, error) {
+	return m.volumesWatcher, nil
+}
+
+func (m *mockVolumeResizeAccessor) WatchFilesystemResizes(names.MachineTag) (watcher.StringsWatcher, error) {
+	return m.filesystemsWatcher, nil
+}
+
+func (m *mockVolumeResizeAccessor) VolumeResizeParams(tags []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	results := make([]params.VolumeResizeParamsResult, len(tags))
+	for i, tag := range tags {
+		p, ok := m.volumes[tag.String()]
+		if !ok {
+			results[i].Error = &params.Error{Code: params.CodeNotFound, Message: "not found"}
+			continue
+		}
+		results[i].Result = p
+	}
+	return results, nil
+}
+
+func (m *mockVolumeResizeAccessor) FinishVolumeResizes(volumes []params.Volume) ([]params.ErrorResult, error) {
+	if m.finishVolumeResizes != nil {
+		return m.finishVolumeResizes(volumes)
+	}
+	return make([]params.ErrorResult, len(volumes)), nil
+}
+
+func (m *mockVolumeResizeAccessor) FilesystemResizeParams(ids []params.MachineStorageId) ([]params.FilesystemResizeParamsResult, error) {
+	results := make([]params.FilesystemResizeParamsResult, len(ids))
+	for i, id := range ids {
+		p, ok := m.filesystems[id.AttachmentTag]
+		if !ok {
+			results[i].Error = &params.Error{Code: params.CodeNotFound, Message: "not found"}
+			continue
+		}
+		results[i].Result = p
+	}
+	return results, nil
+}
+
+func (m *mockVolumeResizeAccessor) FinishFilesystemResizes(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
+	if m.finishFilesystemResizes != nil {
+		return m.finishFilesystemResizes(filesystems)
+	}
+	return make([]params.ErrorResult, len(filesystems)), nil
+}
+
+type dummyVolumeResizer struct {
+	dummyVolumeSource
+	config  *storage.Config
+	resized [][]storage.VolumeResizeParams
+}
+
+func (s *dummyVolumeResizer) ResizeVolumes(ctx context.ProviderCallContext, args []storage.VolumeResizeParams) ([]storage.ResizeVolumesResult, error) {
+	s.resized = append(s.resized, args)
+	results := make([]storage.ResizeVolumesResult, len(args))
+	for i, arg := range args {
+		results[i].Info = &storage.VolumeInfo{
+			VolumeId: arg.VolumeId,
+			Size:     arg.Size,
+		}
+	}
+	return results, nil
+}
+
+func (s *storageProvisionerSuite) resizerRegistry() (*dummyVolumeResizer, storage.ProviderRegistry) {
+	resizer := &dummyVolumeResizer{}
+	provider := &dummyProvider{
+		dynamic: true,
+		volumeSourceFunc: func(cfg *storage.Config) (storage.VolumeSource, error) {
+			resizer.config = cfg
+			return resizer, nil
+		},
+	}
+	resizer.provider = provider
+	return resizer, storage.StaticProviderRegistry{
+		Providers: map[storage.ProviderType]storage.Provider{"dummy": provider},
+	}
+}
+
+func (s *storageProvisionerSuite) TestVolumeResized(c *gc.C) {
+	resizer, registry := s.resizerRegistry()
+	resizes := newMockVolumeResizeAccessor()
+	resizes.volumes["volume-1"] = params.VolumeResizeParams{
+		VolumeTag:  "volume-1",
+		VolumeId:   "vol-1",
+		Provider:   "dummy",
+		Attributes: map[string]interface{}{"foo": "bar"},
+		Size:       2048,
+	}
+	resizes.volumes["volume-2"] = params.VolumeResizeParams{
+		VolumeTag: "volume-2",
+		VolumeId:  "vol-2",
+		Provider:  "dummy",
+	}
+	finished := make(chan interface{})
+	resizes.finishVolumeResizes = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		defer close(finished)
+		c.Assert(volumes, jc.DeepEquals, []params.Volume{{
+			VolumeTag: "volume-1",
+			Info:      params.VolumeInfo{VolumeId: "vol-1", Size: 2048},
+		}})
+		return make([]params.ErrorResult, len(volumes)), nil
+	}
+
+	args := &workerArgs{resizes: resizes, registry: registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	resizes.volumesWatcher.changes <- []string{"1", "2", "3"}
+	waitChannel(c, finished, "waiting for volume resizes to be finished")
+	c.Assert(resizer.resized, jc.DeepEquals, [][]storage.VolumeResizeParams{{{
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "vol-1",
+		Size:     2048,
+	}}})
+	c.Assert(resizer.config.Attrs(), jc.DeepEquals, storage.Attrs{"foo": "bar"})
+}
+
+func (s *storageProvisionerSuite) TestVolumeResizeNotSupported(c *gc.C) {
+	resizes := newMockVolumeResizeAccessor()
+	resizes.volumes["volume-1"] = params.VolumeResizeParams{
+		VolumeTag: "volume-1",
+		VolumeId:  "vol-1",
+		Provider:  "dummy",
+		Size:      2048,
+	}
+	resizes.finishVolumeResizes = func([]params.Volume) ([]params.ErrorResult, error) {
+		c.Fatalf("unexpected call to FinishVolumeResizes")
+		return nil, nil
+	}
+	statusSet := make(chan interface{})
+	statusSetter := &mockStatusSetter{
+		setStatus: func(args []params.EntityStatusArgs) error {
+			defer close(statusSet)
+			c.Assert(args, jc.DeepEquals, []params.EntityStatusArgs{{
+				Tag:    "volume-1",
+				Status: status.Error.String(),
+				Info:   `resizing volumes with storage provider "dummy" not supported`,
+			}})
+			return nil
+		},
+	}
+
+	args := &workerArgs{resizes: resizes, registry: s.registry, statusSetter: statusSetter}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	resizes.volumesWatcher.changes <- []string{"1"}
+	waitChannel(c, statusSet, "waiting for volume status to be set")
+}
+
+func (s *storageProvisionerSuite) TestFilesystemGrown(c *gc.C) {
+	resizes := newMockVolumeResizeAccessor()
+	resizes.filesystems["filesystem-0-1"] = params.FilesystemResizeParams{
+		FilesystemTag: "filesystem-0-1",
+		VolumeTag:     "volume-0-1",
+		MountPoint:    "/srv",
+		Size:          2048,
+	}
+	finished := make(chan interface{})
+	resizes.finishFilesystemResizes = func(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
+		defer close(finished)
+		c.Assert(filesystems, jc.DeepEquals, []params.Filesystem{{
+			FilesystemTag: "filesystem-0-1",
+			VolumeTag:     "volume-0-1",
+			Info:          params.FilesystemInfo{Size: 2000},
+		}})
+		return make([]params.ErrorResult, len(filesystems)), nil
+	}
+	var grown []string
+	growFS := func(mountPoint string, size uint64) (uint64, error) {
+		c.Check(size, gc.Equals, uint64(2048))
+		grown = append(grown, mountPoint)
+		return 2000, nil
+	}
+
+	args := &workerArgs{
+		scope:    names.NewMachineTag("0"),
+		resizes:  resizes,
+		growFS:   growFS,
+		registry: s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	resizes.filesystemsWatcher.changes <- []string{"0/1"}
+	waitChannel(c, finished, "waiting for filesystem resizes to be finished")
+	c.Assert(grown, jc.DeepEquals, []string{"/srv"})
+}
+
+func (s *storageProvisionerSuite) TestFilesystemGrowRetriedOnBlockDevicesChange(c *gc.C) {
+	resizes := newMockVolumeResizeAccessor()
+	resizes.filesystems["filesystem-0-1"] = params.FilesystemResizeParams{
+		FilesystemTag: "filesystem-0-1",
+		VolumeTag:     "volume-0-1",
+		MountPoint:    "/srv",
+		Size:          2048,
+	}
+	finished := make(chan interface{})
+	resizes.finishFilesystemResizes = func(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
+		defer close(finished)
+		c.Assert(filesystems, gc.HasLen, 1)
+		c.Assert(filesystems[0].Info.Size, gc.Equals, uint64(2000))
+		return make([]params.ErrorResult, len(filesystems)), nil
+	}
+	attempted := make(chan interface{}, 1)
+	var attempts int
+	growFS := func(mountPoint string, size uint64) (uint64, error) {
+		attempts++
+		attempted <- nil
+		if attempts == 1 {
+			return 0, errors.NotYetAvailablef("block device")
+		}
+		return 2000, nil
+	}
+
+	args := &workerArgs{
+		scope:    names.NewMachineTag("0"),
+		resizes:  resizes,
+		growFS:   growFS,
+		registry: s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	resizes.filesystemsWatcher.changes <- []string{"0/1"}
+	waitChannel(c, attempted, "waiting for filesystem grow to be attempted")
+
+	args.volumes.blockDevicesWatcher.changes <- struct{}{}
+	waitChannel(c, attempted, "waiting for filesystem grow to be retried")
+	waitChannel(c, finished, "waiting for filesystem resizes to be finished")
+	c.Assert(attempts, gc.Equals, 2)
+}
@@ -0,0 +1,229 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	stdcontext "context"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeSnapshotsChanged is called when the lifecycle states of the volume
+// snapshots with the provided IDs have been seen to have changed.
+//
+// Alive snapshots that have not yet been taken are taken, and snapshots
+// that are Dying or Dead are destroyed in the storage provider and then
+// removed from state. Snapshots are not retried; any failure is recorded
+// in the snapshot's status.
+func volumeSnapshotsChanged(ctx *context, ids []string) error {
+	ctx.config.Logger.Debugf("volume snapshots changed: %v", ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	results, err := ctx.config.Snapshots.VolumeSnapshotParams(ids)
+	if err != nil {
+		return errors.Annotate(err, "getting volume snapshot params")
+	}
+	var create, destroy []params.VolumeSnapshotParams
+	var remove []string
+	for i, result := range results {
+		if result.Error != nil {
+			if params.IsCodeNotFound(result.Error) || params.IsCodeUnauthorized(result.Error) {
+				// The snapshot has already been removed.
+				continue
+			}
+			return errors.Annotatef(result.Error, "getting params for volume snapshot %q", ids[i])
+		}
+		p := result.Result
+		switch {
+		case p.Life == life.Alive && p.SnapshotId == "":
+			create = append(create, p)
+		case p.Life == life.Alive:
+			// Already taken; nothing to do.
+		case p.SnapshotId == "":
+			// Never taken, so there's nothing to destroy.
+			remove = append(remove, p.Id)
+		default:
+			destroy = append(destroy, p)
+		}
+	}
+
+	var statuses []params.VolumeSnapshotStatusArg
+	setError := func(id string, err error) {
+		statuses = append(statuses, params.VolumeSnapshotStatusArg{
+			Id:     id,
+			Status: status.Error.String(),
+			Info:   err.Error(),
+		})
+	}
+	if len(create) > 0 {
+		if err := createVolumeSnapshots(ctx, create, setError); err != nil {
+			return errors.Annotate(err, "creating volume snapshots")
+		}
+	}
+	if len(destroy) > 0 {
+		destroyed, err := destroyVolumeSnapshots(ctx, destroy, setError)
+		if err != nil {
+			return errors.Annotate(err, "destroying volume snapshots")
+		}
+		remove = append(remove, destroyed...)
+	}
+	if len(statuses) > 0 {
+		if err := ctx.config.Snapshots.SetVolumeSnapshotStatus(statuses); err != nil {
+			ctx.config.Logger.Errorf("failed to set volume snapshot status: %v", err)
+		}
+	}
+	return removeVolumeSnapshots(ctx, remove)
+}
+
+// volumeSnapshotter returns the storage.VolumeSnapshotter for the
+// specified storage provider, or an error satisfying errors.NotSupported
+// if the provider cannot snapshot volumes.
+func volumeSnapshotter(ctx *context, providerType string, snapshotters map[string]storage.VolumeSnapshotter) (storage.VolumeSnapshotter, error) {
+	if snapshotter, ok := snapshotters[providerType]; ok {
+		return snapshotter, nil
+	}
+	source, err := volumeSource(
+		ctx.config.StorageDir, providerType, storage.ProviderType(providerType), ctx.config.Registry,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return nil, errors.NotSupportedf("snapshotting volumes with storage provider %q", providerType)
+	}
+	snapshotters[providerType] = snapshotter
+	return snapshotter, nil
+}
+
+// createVolumeSnapshots takes the specified volume snapshots, and records
+// their details in state.
+func createVolumeSnapshots(ctx *context, snapshots []params.VolumeSnapshotParams, setError func(string, error)) error {
+	snapshotters := make(map[string]storage.VolumeSnapshotter)
+	paramsByProvider := make(map[string][]storage.VolumeSnapshotParams)
+	for _, p := range snapshots {
+		if p.VolumeId == "" {
+			setError(p.Id, errors.Errorf("volume %s is not provisioned", p.VolumeTag))
+			continue
+		}
+		volumeTag, err := names.ParseVolumeTag(p.VolumeTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := volumeSnapshotter(ctx, p.Provider, snapshotters); errors.Cause(err) == errNonDynamic {
+			// Snapshots of non-dynamic volumes are not managed
+			// by the storage provisioner.
+			continue
+		} else if err != nil {
+			setError(p.Id, err)
+			continue
+		}
+		paramsByProvider[p.Provider] = append(paramsByProvider[p.Provider], storage.VolumeSnapshotParams{
+			Id:           p.Id,
+			Volume:       volumeTag,
+			VolumeId:     p.VolumeId,
+			ResourceTags: p.Tags,
+		})
+	}
+
+	var taken []params.VolumeSnapshot
+	for providerType, args := range paramsByProvider {
+		ctx.config.Logger.Debugf("creating volume snapshots with %q: %v", providerType, args)
+		results, err := snapshotters[providerType].CreateVolumeSnapshots(
+			ctx.config.CloudCallContextFunc(stdcontext.Background()), args,
+		)
+		if err != nil {
+			return errors.Annotatef(err, "creating volume snapshots with %q", providerType)
+		}
+		for i, result := range results {
+			if result.Error != nil {
+				setError(args[i].Id, errors.Annotate(result.Error, "creating volume snapshot"))
+				continue
+			}
+			taken = append(taken, params.VolumeSnapshot{
+				Id: args[i].Id,
+				Info: params.VolumeSnapshotInfo{
+					SnapshotId: result.Snapshot.SnapshotId,
+					Size:       result.Snapshot.Size,
+				},
+			})
+		}
+	}
+	if len(taken) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Snapshots.SetVolumeSnapshotInfo(taken)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume snapshots to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "publishing volume snapshot %q to state", taken[i].Id)
+		}
+	}
+	return nil
+}
+
+// destroyVolumeSnapshots destroys the specified volume snapshots in the
+// storage provider, returning the IDs of those that were destroyed.
+func destroyVolumeSnapshots(ctx *context, snapshots []params.VolumeSnapshotParams, setError func(string, error)) ([]string, error) {
+	snapshotters := make(map[string]storage.VolumeSnapshotter)
+	idsByProvider := make(map[string][]string)
+	snapshotIdsByProvider := make(map[string][]string)
+	for _, p := range snapshots {
+		if _, err := volumeSnapshotter(ctx, p.Provider, snapshotters); errors.Cause(err) == errNonDynamic {
+			continue
+		} else if err != nil {
+			setError(p.Id, err)
+			continue
+		}
+		idsByProvider[p.Provider] = append(idsByProvider[p.Provider], p.Id)
+		snapshotIdsByProvider[p.Provider] = append(snapshotIdsByProvider[p.Provider], p.SnapshotId)
+	}
+
+	var destroyed []string
+	for providerType, snapshotIds := range snapshotIdsByProvider {
+		ctx.config.Logger.Debugf("destroying volume snapshots with %q: %v", providerType, snapshotIds)
+		errs, err := snapshotters[providerType].DestroyVolumeSnapshots(
+			ctx.config.CloudCallContextFunc(stdcontext.Background()), snapshotIds,
+		)
+		if err != nil {
+			return nil, errors.Annotatef(err, "destroying volume snapshots with %q", providerType)
+		}
+		ids := idsByProvider[providerType]
+		for i, err := range errs {
+			if err != nil {
+				setError(ids[i], errors.Annotate(err, "destroying volume snapshot"))
+				continue
+			}
+			destroyed = append(destroyed, ids[i])
+		}
+	}
+	return destroyed, nil
+}
+
+// removeVolumeSnapshots removes each specified volume snapshot from state.
+func removeVolumeSnapshots(ctx *context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	ctx.config.Logger.Debugf("removing volume snapshots: %v", ids)
+	errorResults, err := ctx.config.Snapshots.RemoveVolumeSnapshots(ids)
+	if err != nil {
+		return errors.Annotate(err, "removing volume snapshots")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil && !params.IsCodeNotFound(result.Error) {
+			return errors.Annotatef(result.Error, "removing volume snapshot %q from state", ids[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/storage"
+)
+
+type mockVolumeSnapshotAccessor struct {
+	snapshotsWatcher *mockStringsWatcher
+	snapshots        map[string]params.VolumeSnapshotParams
+
+	setVolumeSnapshotInfo   func([]params.VolumeSnapshot) ([]params.ErrorResult, error)
+	setVolumeSnapshotStatus func([]params.VolumeSnapshotStatusArg) error
+	removeVolumeSnapshots   func([]string) ([]params.ErrorResult, error)
+}
+
+func newMockVolumeSnapshotAccessor() *mockVolumeSnapshotAccessor {
+	return &mockVolumeSnapshotAccessor{
+		snapshotsWatcher: newMockStringsWatcher(),
+		snapshots:        make(map[string]params.VolumeSnapshotParams),
+	}
+}
+
+func (m *mockVolumeSnapshotAccessor) WatchVolumeSnapshots(names.Tag) (watcher.StringsWatcher, error) {
+	return m.snapshotsWatcher, nil
+}
+
+func (m *mockVolumeSnapshotAccessor) VolumeSnapshotParams(ids []string) ([]params.VolumeSnapshotParamsResult, error) {
+	results := make([]params.VolumeSnapshotParamsResult, len(ids))
+	for i, id := range ids {
+		p, ok := m.snapshots[id]
+		if !ok {
+			results[i].Error = &params.Error{Code: params.CodeNotFound, Message: "not found"}
+			continue
+		}
+		results[i].Result = p
+	}
+	return results, nil
+}
+
+func (m *mockVolumeSnapshotAccessor) SetVolumeSnapshotInfo(snapshots []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+	if m.setVolumeSnapshotInfo != nil {
+		return m.setVolumeSnapshotInfo(snapshots)
+	}
+	return make([]params.ErrorResult, len(snapshots)), nil
+}
+
+func (m *mockVolumeSnapshotAccessor) SetVolumeSnapshotStatus(args []params.VolumeSnapshotStatusArg) error {
+	if m.setVolumeSnapshotStatus != nil {
+		return m.setVolumeSnapshotStatus(args)
+	}
+	return nil
+}
+
+func (m *mockVolumeSnapshotAccessor) RemoveVolumeSnapshots(ids []string) ([]params.ErrorResult, error) {
+	if m.removeVolumeSnapshots != nil {
+		return m.removeVolumeSnapshots(ids)
+	}
+	return make([]params.ErrorResult, len(ids)), nil
+}
+
+type dummyVolumeSnapshotter struct {
+	dummyVolumeSource
+	created   [][]storage.VolumeSnapshotParams
+	destroyed [][]string
+	createErr error
+}
+
+func (s *dummyVolumeSnapshotter) CreateVolumeSnapshots(ctx context.ProviderCallContext, args []storage.VolumeSnapshotParams) ([]storage.CreateVolumeSnapshotsResult, error) {
+	s.created = append(s.created, args)
+	results := make([]storage.CreateVolumeSnapshotsResult, len(args))
+	for i, arg := range args {
+		if s.createErr != nil {
+			results[i].Error = s.createErr
+			continue
+		}
+		results[i].Snapshot = &storage.VolumeSnapshot{
+			Id: arg.Id,
+			VolumeSnapshotInfo: storage.VolumeSnapshotInfo{
+				SnapshotId: "snap-" + arg.VolumeId,
+				Size:       1024,
+			},
+		}
+	}
+	return results, nil
+}
+
+func (s *dummyVolumeSnapshotter) DestroyVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	s.destroyed = append(s.destroyed, snapshotIds)
+	return make([]error, len(snapshotIds)), nil
+}
+
+func (s *storageProvisionerSuite) snapshotterRegistry() (*dummyVolumeSnapshotter, storage.ProviderRegistry) {
+	snapshotter := &dummyVolumeSnapshotter{}
+	provider := &dummyProvider{
+		dynamic: true,
+		volumeSourceFunc: func(*storage.Config) (storage.VolumeSource, error) {
+			return snapshotter, nil
+		},
+	}
+	snapshotter.provider = provider
+	return snapshotter, storage.StaticProviderRegistry{
+		Providers: map[storage.ProviderType]storage.Provider{"dummy": provider},
+	}
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotCreated(c *gc.C) {
+	snapshotter, registry := s.snapshotterRegistry()
+	snapshots := newMockVolumeSnapshotAccessor()
+	snapshots.snapshots["1@2"] = params.VolumeSnapshotParams{
+		Id:        "1@2",
+		Life:      life.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "vol-1",
+		Provider:  "dummy",
+		Tags:      map[string]string{"juju-model-uuid": "deadbeef"},
+	}
+	infoSet := make(chan interface{})
+	snapshots.setVolumeSnapshotInfo = func(taken []params.VolumeSnapshot) ([]params.ErrorResult, error) {
+		defer close(infoSet)
+		c.Assert(taken, jc.DeepEquals, []params.VolumeSnapshot{{
+			Id:   "1@2",
+			Info: params.VolumeSnapshotInfo{SnapshotId: "snap-vol-1", Size: 1024},
+		}})
+		return make([]params.ErrorResult, len(taken)), nil
+	}
+
+	args := &workerArgs{snapshots: snapshots, registry: registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	snapshots.snapshotsWatcher.changes <- []string{"1@2"}
+	waitChannel(c, infoSet, "waiting for volume snapshot info to be set")
+	c.Assert(snapshotter.created, jc.DeepEquals, [][]storage.VolumeSnapshotParams{{{
+		Id:           "1@2",
+		Volume:       names.NewVolumeTag("1"),
+		VolumeId:     "vol-1",
+		ResourceTags: map[string]string{"juju-model-uuid": "deadbeef"},
+	}}})
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotCreateError(c *gc.C) {
+	snapshotter, registry := s.snapshotterRegistry()
+	snapshotter.createErr = errors.New("quota exceeded")
+	snapshots := newMockVolumeSnapshotAccessor()
+	snapshots.snapshots["1@2"] = params.VolumeSnapshotParams{
+		Id:        "1@2",
+		Life:      life.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "vol-1",
+		Provider:  "dummy",
+	}
+	snapshots.setVolumeSnapshotInfo = func([]params.VolumeSnapshot) ([]params.ErrorResult, error) {
+		c.Fatalf("unexpected call to SetVolumeSnapshotInfo")
+		return nil, nil
+	}
+	statusSet := make(chan interface{})
+	snapshots.setVolumeSnapshotStatus = func(args []params.VolumeSnapshotStatusArg) error {
+		defer close(statusSet)
+		c.Assert(args, jc.DeepEquals, []params.VolumeSnapshotStatusArg{{
+			Id:     "1@2",
+			Status: status.Error.String(),
+			Info:   "creating volume snapshot: quota exceeded",
+		}})
+		return nil
+	}
+
+	args := &workerArgs{snapshots: snapshots, registry: registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	snapshots.snapshotsWatcher.changes <- []string{"1@2"}
+	waitChannel(c, statusSet, "waiting for volume snapshot status to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotNotSupported(c *gc.C) {
+	snapshots := newMockVolumeSnapshotAccessor()
+	snapshots.snapshots["1@2"] = params.VolumeSnapshotParams{
+		Id:        "1@2",
+		Life:      life.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "vol-1",
+		Provider:  "dummy",
+	}
+	statusSet := make(chan interface{})
+	snapshots.setVolumeSnapshotStatus = func(args []params.VolumeSnapshotStatusArg) error {
+		defer close(statusSet)
+		c.Assert(args, jc.DeepEquals, []params.VolumeSnapshotStatusArg{{
+			Id:     "1@2",
+			Status: status.Error.String(),
+			Info:   `snapshotting volumes with storage provider "dummy" not supported`,
+		}})
+		return nil
+	}
+
+	args := &workerArgs{snapshots: snapshots, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	snapshots.snapshotsWatcher.changes <- []string{"1@2"}
+	waitChannel(c, statusSet, "waiting for volume snapshot status to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeSnapshotDestroyed(c *gc.C) {
+	snapshotter, registry := s.snapshotterRegistry()
+	snapshots := newMockVolumeSnapshotAccessor()
+	snapshots.snapshots["1@2"] = params.VolumeSnapshotParams{
+		Id:         "1@2",
+		Life:       life.Dying,
+		VolumeTag:  "volume-1",
+		SnapshotId: "snap-vol-1",
+		Provider:   "dummy",
+	}
+	snapshots.snapshots["1@3"] = params.VolumeSnapshotParams{
+		Id:        "1@3",
+		Life:      life.Dying,
+		VolumeTag: "volume-1",
+		Provider:  "dummy",
+	}
+	removed := make(chan interface{})
+	snapshots.removeVolumeSnapshots = func(ids []string) ([]params.ErrorResult, error) {
+		defer close(removed)
+		c.Assert(ids, jc.SameContents, []string{"1@2", "1@3"})
+		return make([]params.ErrorResult, len(ids)), nil
+	}
+
+	args := &workerArgs{snapshots: snapshots, registry: registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	snapshots.snapshotsWatcher.changes <- []string{"1@2", "1@3", "1@4"}
+	waitChannel(c, removed, "waiting for volume snapshots to be removed")
+	c.Assert(snapshotter.destroyed, jc.DeepEquals, [][]string{{"snap-vol-1"}})
+}
@@ -128,6 +128,57 @@ type FilesystemAccessor interface {
 	SetFilesystemAttachmentInfo([]params.FilesystemAttachment) ([]params.ErrorResult, error)
 }
 
+// VolumeSnapshotAccessor defines an interface used to allow a storage
+// provisioner worker to take and destroy volume snapshots.
+type VolumeSnapshotAccessor interface {
+	// WatchVolumeSnapshots watches for changes to snapshots of volumes
+	// that this storage provisioner is responsible for.
+	WatchVolumeSnapshots(scope names.Tag) (watcher.StringsWatcher, error)
+
+	// VolumeSnapshotParams returns the parameters for taking or
+	// destroying the volume snapshots with the specified IDs.
+	VolumeSnapshotParams([]string) ([]params.VolumeSnapshotParamsResult, error)
+
+	// SetVolumeSnapshotInfo records the details of newly taken
+	// volume snapshots.
+	SetVolumeSnapshotInfo([]params.VolumeSnapshot) ([]params.ErrorResult, error)
+
+	// SetVolumeSnapshotStatus sets the status of volume snapshots.
+	SetVolumeSnapshotStatus([]params.VolumeSnapshotStatusArg) error
+
+	// RemoveVolumeSnapshots removes the specified volume snapshots
+	// from state.
+	RemoveVolumeSnapshots([]string) ([]params.ErrorResult, error)
+}
+
+// VolumeResizeAccessor defines an interface used to allow a storage
+// provisioner worker to resize volumes, and grow the filesystems on them.
+type VolumeResizeAccessor interface {
+	// WatchVolumeResizes watches for changes to volumes that this
+	// storage provisioner is responsible for, so that pending resizes
+	// may be carried out.
+	WatchVolumeResizes(scope names.Tag) (watcher.StringsWatcher, error)
+
+	// WatchFilesystemResizes watches for changes to filesystems attached
+	// to the specified machine, so that filesystems whose backing volumes
+	// have been resized may be grown.
+	WatchFilesystemResizes(scope names.MachineTag) (watcher.StringsWatcher, error)
+
+	// VolumeResizeParams returns the parameters for resizing the
+	// volumes with the specified tags.
+	VolumeResizeParams([]names.VolumeTag) ([]params.VolumeResizeParamsResult, error)
+
+	// FinishVolumeResizes records the details of resized volumes.
+	FinishVolumeResizes([]params.Volume) ([]params.ErrorResult, error)
+
+	// FilesystemResizeParams returns the parameters for growing the
+	// filesystems attached to machines with the specified IDs.
+	FilesystemResizeParams([]params.MachineStorageId) ([]params.FilesystemResizeParamsResult, error)
+
+	// FinishFilesystemResizes records the details of grown filesystems.
+	FinishFilesystemResizes([]params.Filesystem) ([]params.ErrorResult, error)
+}
+
 // MachineAccessor defines an interface used to allow a storage provisioner
 // worker to perform machine related operations.
 type MachineAccessor interface {
@@ -210,6 +261,9 @@ func (w *storageProvisioner) loop() error {
 		volumeAttachmentsChanges     watcher.MachineStorageIdsChannel
 		volumeAttachmentPlansChanges watcher.MachineStorageIdsChannel
 		filesystemAttachmentsChanges watcher.MachineStorageIdsChannel
+		volumeSnapshotsChanges       watcher.StringsChannel
+		volumeResizesChanges         watcher.StringsChannel
+		filesystemResizesChanges     watcher.StringsChannel
 		machineBlockDevicesChanges   <-chan struct{}
 	)
 	machineChanges := make(chan names.MachineTag)
@@ -254,6 +308,7 @@ func (w *storageProvisioner) loop() error {
 		incompleteFilesystemParams:           make(map[names.FilesystemTag]storage.FilesystemParams),
 		incompleteFilesystemAttachmentParams: make(map[params.MachineStorageId]storage.FilesystemAttachmentParams),
 		pendingVolumeBlockDevices:            names.NewSet(),
+		pendingFilesystemResizes:             names.NewSet(),
 	}
 	ctx.managedFilesystemSource = newManagedFilesystemSource(
 		ctx.volumeBlockDevices, ctx.filesystems,
@@ -276,6 +331,52 @@ func (w *storageProvisioner) loop() error {
 		volumesChanges = volumesWatcher.Changes()
 	}
 
+	// Volume snapshots are optional; they are not supported for
+	// application-scoped storage, nor by older controllers.
+	if w.config.Snapshots != nil && !ctx.isApplicationKind() {
+		volumeSnapshotsWatcher, err := w.config.Snapshots.WatchVolumeSnapshots(w.config.Scope)
+		if errors.IsNotSupported(err) {
+			w.config.Logger.Debugf("not watching volume snapshots: %v", err)
+		} else if err != nil {
+			return errors.Annotate(err, "watching volume snapshots")
+		} else {
+			if err := w.catacomb.Add(volumeSnapshotsWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			volumeSnapshotsChanges = volumeSnapshotsWatcher.Changes()
+		}
+	}
+
+	// Volume resizes are optional in the same way. Filesystems are
+	// only grown by machine-scoped provisioners, as the filesystem
+	// must be grown on the machine to which its volume is attached.
+	if w.config.Resizes != nil && !ctx.isApplicationKind() {
+		volumeResizesWatcher, err := w.config.Resizes.WatchVolumeResizes(w.config.Scope)
+		if errors.IsNotSupported(err) {
+			w.config.Logger.Debugf("not watching volume resizes: %v", err)
+		} else if err != nil {
+			return errors.Annotate(err, "watching volume resizes")
+		} else {
+			if err := w.catacomb.Add(volumeResizesWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			volumeResizesChanges = volumeResizesWatcher.Changes()
+		}
+		if machineTag, ok := w.config.Scope.(names.MachineTag); ok && w.config.GrowFilesystem != nil {
+			filesystemResizesWatcher, err := w.config.Resizes.WatchFilesystemResizes(machineTag)
+			if errors.IsNotSupported(err) {
+				w.config.Logger.Debugf("not watching filesystem resizes: %v", err)
+			} else if err != nil {
+				return errors.Annotate(err, "watching filesystem resizes")
+			} else {
+				if err := w.catacomb.Add(filesystemResizesWatcher); err != nil {
+					return errors.Trace(err)
+				}
+				filesystemResizesChanges = filesystemResizesWatcher.Changes()
+			}
+		}
+	}
+
 	filesystemsWatcher, err := w.config.Filesystems.WatchFilesystems(w.config.Scope)
 	if err != nil {
 		return errors.Annotate(err, "watching filesystems")
@@ -364,6 +465,27 @@ func (w *storageProvisioner) loop() error {
 			if err := filesystemAttachmentsChanged(&ctx, changes); err != nil {
 				return errors.Trace(err)
 			}
+		case changes, ok := <-volumeSnapshotsChanges:
+			if !ok {
+				return errors.New("volume snapshots watcher closed")
+			}
+			if err := volumeSnapshotsChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-volumeResizesChanges:
+			if !ok {
+				return errors.New("volume resizes watcher closed")
+			}
+			if err := volumeResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-filesystemResizesChanges:
+			if !ok {
+				return errors.New("filesystem resizes watcher closed")
+			}
+			if err := filesystemResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
 		case _, ok := <-machineBlockDevicesChanges:
 			if !ok {
 				return errors.New("machine block devices watcher closed")
@@ -371,6 +493,11 @@ func (w *storageProvisioner) loop() error {
 			if err := machineBlockDevicesChanged(&ctx); err != nil {
 				return errors.Trace(err)
 			}
+			// A volume's block device may have grown, so retry
+			// growing any filesystems that were waiting on it.
+			if err := processPendingFilesystemResizes(&ctx); err != nil {
+				return errors.Trace(err)
+			}
 		case machineTag := <-machineChanges:
 			if err := refreshMachine(&ctx, machineTag); err != nil {
 				return errors.Trace(err)
@@ -554,6 +681,11 @@ type context struct {
 	// block devices we wish to enquire.
 	pendingVolumeBlockDevices names.Set
 
+	// pendingFilesystemResizes contains the tags of filesystems that
+	// are waiting for their backing volumes' block devices to grow
+	// before they can themselves be grown.
+	pendingFilesystemResizes names.Set
+
 	// managedFilesystemSource is a storage.FilesystemSource that
 	// manages filesystems backed by volumes attached to the host
 	// machine.
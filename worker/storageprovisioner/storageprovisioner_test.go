@@ -2319,7 +2319,7 @@ func newStorageProvisioner(c *gc.C, args *workerArgs) worker.Worker {
 	if args.statusSetter == nil {
 		args.statusSetter = &mockStatusSetter{}
 	}
-	worker, err := storageprovisioner.NewStorageProvisioner(storageprovisioner.Config{
+	config := storageprovisioner.Config{
 		Scope:       args.scope,
 		StorageDir:  storageDir,
 		Volumes:     args.volumes,
@@ -2333,7 +2333,15 @@ func newStorageProvisioner(c *gc.C, args *workerArgs) worker.Worker {
 		CloudCallContextFunc: func(_ stdcontext.Context) context.ProviderCallContext {
 			return context.NewEmptyCloudCallContext()
 		},
-	})
+	}
+	if args.snapshots != nil {
+		config.Snapshots = args.snapshots
+	}
+	if args.resizes != nil {
+		config.Resizes = args.resizes
+	}
+	config.GrowFilesystem = args.growFS
+	worker, err := storageprovisioner.NewStorageProvisioner(config)
 	c.Assert(err, jc.ErrorIsNil)
 	return worker
 }
@@ -2342,6 +2350,9 @@ type workerArgs struct {
 	scope        names.Tag
 	volumes      *mockVolumeAccessor
 	filesystems  *mockFilesystemAccessor
+	snapshots    *mockVolumeSnapshotAccessor
+	resizes      *mockVolumeResizeAccessor
+	growFS       func(string, uint64) (uint64, error)
 	life         *mockLifecycleManager
 	registry     storage.ProviderRegistry
 	machines     *mockMachineAccessor
@@ -0,0 +1,119 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracer
+
+import (
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v3"
+	"github.com/juju/worker/v3/dependency"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	jujuagent "github.com/juju/juju/agent"
+	"github.com/juju/juju/core/trace"
+	"github.com/juju/juju/observability/tracing/otlp"
+	"github.com/juju/juju/worker/common"
+	workerstate "github.com/juju/juju/worker/state"
+)
+
+// ManifoldConfig holds the information needed to run a tracer worker
+// in a dependency.Engine.
+type ManifoldConfig struct {
+	AgentName   string
+	StateName   string
+	Clock       clock.Clock
+	Logger      Logger
+	NewExporter func(otlp.Config) (sdktrace.SpanExporter, error)
+}
+
+// Validate validates the manifold configuration.
+func (config ManifoldConfig) Validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.StateName == "" {
+		return errors.NotValidf("empty StateName")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.NewExporter == nil {
+		return errors.NotValidf("nil NewExporter")
+	}
+	return nil
+}
+
+// Manifold returns a dependency.Manifold which runs a tracer worker.
+// The manifold outputs a trace.Tracer.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.StateName,
+		},
+		Start:  config.start,
+		Output: output,
+	}
+}
+
+func (config ManifoldConfig) start(context dependency.Context) (_ worker.Worker, err error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var agent jujuagent.Agent
+	if err := context.Get(config.AgentName, &agent); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var stTracker workerstate.StateTracker
+	if err := context.Get(config.StateName, &stTracker); err != nil {
+		return nil, errors.Trace(err)
+	}
+	statePool, err := stTracker.Use()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = stTracker.Done()
+		}
+	}()
+
+	st, err := statePool.SystemState()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	w, err := NewWorker(Config{
+		Source:      st,
+		Tag:         agent.CurrentConfig().Tag(),
+		Clock:       config.Clock,
+		Logger:      config.Logger,
+		NewExporter: config.NewExporter,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewCleanupWorker(w, func() { _ = stTracker.Done() }), nil
+}
+
+func output(in worker.Worker, out interface{}) error {
+	if w, ok := in.(*common.CleanupWorker); ok {
+		in = w.Worker
+	}
+	w, ok := in.(*Worker)
+	if !ok {
+		return errors.Errorf("expected *tracer.Worker, got %T", in)
+	}
+	target, ok := out.(*trace.Tracer)
+	if !ok {
+		return errors.Errorf("expected *trace.Tracer, got %T", out)
+	}
+	*target = w
+	return nil
+}
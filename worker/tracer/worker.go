@@ -0,0 +1,257 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/worker/v3"
+	"github.com/juju/worker/v3/catacomb"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/trace"
+	"github.com/juju/juju/observability/tracing"
+	"github.com/juju/juju/observability/tracing/otlp"
+	"github.com/juju/juju/state"
+)
+
+// Logger represents the methods used by the worker to log details.
+type Logger interface {
+	Warningf(string, ...interface{})
+	Infof(string, ...interface{})
+	Debugf(string, ...interface{})
+}
+
+// ConfigSource lets us get notifications of changes to controller
+// configuration, and then get the changed config. (Primary
+// implementation is State.)
+type ConfigSource interface {
+	WatchControllerConfig() state.NotifyWatcher
+	ControllerConfig() (controller.Config, error)
+}
+
+// Config holds the configuration of the tracer worker.
+type Config struct {
+	// Source supplies the controller config which enables tracing.
+	Source ConfigSource
+
+	// Tag is the tag of the agent running the worker, which identifies
+	// the exported spans.
+	Tag names.Tag
+
+	Clock  clock.Clock
+	Logger Logger
+
+	// NewExporter returns the exporter that sends spans to the OTLP
+	// receiver.
+	NewExporter func(otlp.Config) (sdktrace.SpanExporter, error)
+}
+
+// Validate ensures that the config is valid.
+func (config Config) Validate() error {
+	if config.Source == nil {
+		return errors.NotValidf("nil Source")
+	}
+	if config.Tag == nil {
+		return errors.NotValidf("nil Tag")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.NewExporter == nil {
+		return errors.NotValidf("nil NewExporter")
+	}
+	return nil
+}
+
+// settings holds the controller config values used to trace.
+type settings struct {
+	enabled     bool
+	endpoint    string
+	protocol    string
+	insecure    bool
+	sampleRatio float64
+}
+
+func settingsFromConfig(cfg controller.Config) settings {
+	if !cfg.OpenTelemetryEnabled() {
+		return settings{}
+	}
+	return settings{
+		enabled:     true,
+		endpoint:    cfg.OpenTelemetryEndpoint(),
+		protocol:    cfg.OpenTelemetryProtocol(),
+		insecure:    cfg.OpenTelemetryInsecure(),
+		sampleRatio: cfg.OpenTelemetrySampleRatio(),
+	}
+}
+
+// Worker is a trace.Tracer which records spans according to the
+// current controller config. When tracing is disabled, spans are
+// no-ops; when the config changes, the underlying tracer is replaced,
+// so the worker doesn't need to be restarted, nor do the workers which
+// use it.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	mu       sync.Mutex
+	settings settings
+	tracer   trace.Tracer
+	stop     func()
+}
+
+var (
+	_ trace.Tracer  = (*Worker)(nil)
+	_ worker.Worker = (*Worker)(nil)
+)
+
+// NewWorker returns a tracer worker which tracks the controller config.
+func NewWorker(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config: config,
+		tracer: trace.NoopTracer{},
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+// Start implements trace.Tracer.
+func (w *Worker) Start(ctx context.Context, name string, options ...trace.Option) (context.Context, trace.Span) {
+	return w.current().Start(ctx, name, options...)
+}
+
+// Enabled implements trace.Tracer.
+func (w *Worker) Enabled() bool {
+	return w.current().Enabled()
+}
+
+func (w *Worker) current() trace.Tracer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.tracer
+}
+
+func (w *Worker) loop() error {
+	defer w.replace(settings{}, trace.NoopTracer{}, nil)
+
+	watcher := w.config.Source.WatchControllerConfig()
+	if err := w.catacomb.Add(watcher); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return errors.Errorf("controller config watcher closed")
+			}
+			cfg, err := w.config.Source.ControllerConfig()
+			if err != nil {
+				return errors.Annotate(err, "getting controller config")
+			}
+			if err := w.update(settingsFromConfig(cfg)); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// update replaces the tracer if the settings have changed.
+func (w *Worker) update(newSettings settings) error {
+	w.mu.Lock()
+	unchanged := newSettings == w.settings
+	w.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	if !newSettings.enabled {
+		w.config.Logger.Infof("tracing disabled")
+		w.replace(newSettings, trace.NoopTracer{}, nil)
+		return nil
+	}
+
+	tracer, err := w.newTracer(newSettings)
+	if err != nil {
+		// A bad endpoint shouldn't stop the controller, so tracing
+		// is disabled until the config is fixed.
+		w.config.Logger.Warningf("tracing disabled: %v", err)
+		w.replace(newSettings, trace.NoopTracer{}, nil)
+		return nil
+	}
+	if err := w.catacomb.Add(tracer); err != nil {
+		return errors.Trace(err)
+	}
+	w.config.Logger.Infof("tracing to %s (%s), sampling %v of traces",
+		newSettings.endpoint, newSettings.protocol, newSettings.sampleRatio)
+	w.replace(newSettings, tracer, tracer.Kill)
+	return nil
+}
+
+func (w *Worker) newTracer(s settings) (*tracing.Tracer, error) {
+	exporter, err := w.config.NewExporter(otlp.Config{
+		Endpoint: s.endpoint,
+		Protocol: s.protocol,
+		Insecure: s.insecure,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tracer, err := tracing.NewTracer(tracing.Config{
+		SampleRatio:       s.sampleRatio,
+		Exporter:          exporter,
+		ServiceName:       "jujud",
+		ServiceInstanceID: w.config.Tag.String(),
+		Clock:             w.config.Clock,
+		Logger:            w.config.Logger,
+	})
+	if err != nil {
+		_ = exporter.Shutdown(context.Background())
+		return nil, errors.Trace(err)
+	}
+	return tracer, nil
+}
+
+// replace swaps in the new tracer, and stops the old one. The old
+// tracer exports the spans it has recorded as it stops.
+func (w *Worker) replace(s settings, tracer trace.Tracer, stop func()) {
+	w.mu.Lock()
+	oldStop := w.stop
+	w.settings = s
+	w.tracer = tracer
+	w.stop = stop
+	w.mu.Unlock()
+
+	if oldStop != nil {
+		oldStop()
+	}
+}
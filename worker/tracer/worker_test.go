@@ -0,0 +1,240 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracer_test
+
+import (
+	"context"
+	"sync"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v3/workertest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/observability/tracing/otlp"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher/watchertest"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/tracer"
+)
+
+type workerSuite struct {
+	configChanged chan struct{}
+	source        *configSource
+	exporters     *exporterFactory
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.configChanged = make(chan struct{}, 1)
+	s.source = &configSource{
+		watcher: watchertest.NewNotifyWatcher(s.configChanged),
+		cfg:     controller.Config{},
+	}
+	s.exporters = &exporterFactory{}
+}
+
+func (s *workerSuite) newWorker(c *gc.C) *tracer.Worker {
+	w, err := tracer.NewWorker(tracer.Config{
+		Source:      s.source,
+		Tag:         names.NewMachineTag("0"),
+		Clock:       clock.WallClock,
+		Logger:      loggo.GetLogger("test"),
+		NewExporter: s.exporters.newExporter,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *workerSuite) setConfig(cfg controller.Config) {
+	s.source.setConfig(cfg)
+	s.configChanged <- struct{}{}
+}
+
+func enabledConfig() controller.Config {
+	return controller.Config{
+		controller.OpenTelemetryEnabled:     true,
+		controller.OpenTelemetryEndpoint:    "localhost:4317",
+		controller.OpenTelemetryInsecure:    true,
+		controller.OpenTelemetrySampleRatio: 1.0,
+	}
+}
+
+func waitForEnabled(c *gc.C, w *tracer.Worker, enabled bool) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if w.Enabled() == enabled {
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for tracer enabled to be %v", enabled)
+}
+
+func (s *workerSuite) TestValidate(c *gc.C) {
+	_, err := tracer.NewWorker(tracer.Config{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *workerSuite) TestDisabled(c *gc.C) {
+	w := s.newWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.setConfig(controller.Config{})
+	waitForEnabled(c, w, false)
+
+	_, span := w.Start(context.Background(), "test")
+	span.End()
+	c.Assert(s.exporters.configs(), gc.HasLen, 0)
+}
+
+func (s *workerSuite) TestEnabled(c *gc.C) {
+	w := s.newWorker(c)
+
+	s.setConfig(enabledConfig())
+	waitForEnabled(c, w, true)
+
+	c.Assert(s.exporters.configs(), jc.DeepEquals, []otlp.Config{{
+		Endpoint: "localhost:4317",
+		Protocol: "grpc",
+		Insecure: true,
+	}})
+
+	_, span := w.Start(context.Background(), "test")
+	span.End()
+
+	// The recorded spans are exported when the worker stops.
+	workertest.CleanKill(c, w)
+	exporter := s.exporters.exporter(0)
+	c.Assert(exporter.spanNames(), jc.DeepEquals, []string{"test"})
+	c.Assert(exporter.isClosed(), jc.IsTrue)
+}
+
+func (s *workerSuite) TestDisabledAfterEnabled(c *gc.C) {
+	w := s.newWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.setConfig(enabledConfig())
+	waitForEnabled(c, w, true)
+
+	s.setConfig(controller.Config{})
+	waitForEnabled(c, w, false)
+
+	exporter := s.exporters.exporter(0)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if exporter.isClosed() {
+			return
+		}
+	}
+	c.Fatalf("exporter not closed")
+}
+
+func (s *workerSuite) TestExporterErrorDisables(c *gc.C) {
+	s.exporters.err = errors.New("boom")
+	w := s.newWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.setConfig(enabledConfig())
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.exporters.configs()) > 0 {
+			break
+		}
+	}
+	c.Assert(s.exporters.configs(), gc.HasLen, 1)
+	c.Assert(w.Enabled(), jc.IsFalse)
+	workertest.CheckAlive(c, w)
+}
+
+type configSource struct {
+	mu      sync.Mutex
+	watcher *watchertest.NotifyWatcher
+	cfg     controller.Config
+}
+
+func (s *configSource) WatchControllerConfig() state.NotifyWatcher {
+	return s.watcher
+}
+
+func (s *configSource) ControllerConfig() (controller.Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg, nil
+}
+
+func (s *configSource) setConfig(cfg controller.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
+
+type exporterFactory struct {
+	mu        sync.Mutex
+	err       error
+	cfgs      []otlp.Config
+	exporters []*fakeExporter
+}
+
+func (f *exporterFactory) newExporter(cfg otlp.Config) (sdktrace.SpanExporter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cfgs = append(f.cfgs, cfg)
+	if f.err != nil {
+		return nil, f.err
+	}
+	exporter := &fakeExporter{}
+	f.exporters = append(f.exporters, exporter)
+	return exporter, nil
+}
+
+func (f *exporterFactory) configs() []otlp.Config {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]otlp.Config(nil), f.cfgs...)
+}
+
+func (f *exporterFactory) exporter(i int) *fakeExporter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.exporters[i]
+}
+
+type fakeExporter struct {
+	mu     sync.Mutex
+	spans  []sdktrace.ReadOnlySpan
+	closed bool
+}
+
+func (e *fakeExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *fakeExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	return nil
+}
+
+func (e *fakeExporter) spanNames() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var names []string
+	for _, span := range e.spans {
+		names = append(names, span.Name())
+	}
+	return names
+}
+
+func (e *fakeExporter) isClosed() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.closed
+}
@@ -67,6 +67,18 @@ type Info struct {
 	SecretLabel string `yaml:"secret-label,omitempty"`
 }
 
+// StorageResized is the kind of hook run when a storage instance
+// attached to the unit has been resized. The charm library does not
+// define this hook, as it is not declared in charm metadata; charms
+// implement it as "<storage-name>-storage-resized".
+const StorageResized hooks.Kind = "storage-resized"
+
+// IsStorage returns whether the hook kind is a storage hook,
+// including the storage-resized hook.
+func IsStorage(kind hooks.Kind) bool {
+	return kind.IsStorage() || kind == StorageResized
+}
+
 // SecretHookRequiresRevision returns true if the hook context needs a secret revision.
 func SecretHookRequiresRevision(kind hooks.Kind) bool {
 	return kind == hooks.SecretRemove || kind == hooks.SecretExpired
@@ -116,7 +128,7 @@ func (hi Info) Validate() error {
 		return nil
 	case hooks.Action:
 		return errors.Errorf("hooks.Kind Action is deprecated")
-	case hooks.StorageAttached, hooks.StorageDetaching:
+	case hooks.StorageAttached, hooks.StorageDetaching, StorageResized:
 		if !names.IsValidStorage(hi.StorageId) {
 			return errors.Errorf("invalid storage ID %q", hi.StorageId)
 		}
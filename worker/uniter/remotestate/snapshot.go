@@ -160,4 +160,7 @@ type StorageSnapshot struct {
 	Life     life.Value
 	Attached bool
 	Location string
+	// Resized is true if the storage has been resized since the
+	// unit last ran its storage-resized hook.
+	Resized bool
 }
@@ -82,6 +82,7 @@ func getStorageSnapshot(
 		Kind:     attachment.Kind,
 		Attached: true,
 		Location: attachment.Location,
+		Resized:  attachment.Resized,
 	}
 	return snapshot, nil
 }
@@ -0,0 +1,310 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package capture records the execution of a unit's hooks in bundles,
+// which "juju replay-hook" replays against a local copy of the charm.
+package capture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/utils/v3/exec"
+	goyaml "gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+const (
+	// FlagFile is the name of the file, in a unit agent's directory,
+	// whose presence enables the capture of the unit's hooks. If the
+	// file names any hooks, separated by whitespace, only those hooks
+	// are captured.
+	FlagFile = "capture-hooks"
+
+	// BundleDir is the name of the directory, in a unit agent's
+	// directory, to which captured hooks are written.
+	BundleDir = "hook-captures"
+
+	// MaxBundles is the number of bundles kept in BundleDir; the
+	// oldest are removed as new hooks are captured.
+	MaxBundles = 20
+
+	bundleVersion = 1
+)
+
+// outputRedacted and inputRedacted hold the hook tools whose output,
+// or arguments and input, carry credentials or secret content, which
+// are not recorded.
+var (
+	outputRedacted = set.NewStrings("credential-get", "secret-get")
+	inputRedacted  = set.NewStrings("secret-add", "secret-set")
+)
+
+// Bundle holds everything recorded while running a single hook.
+type Bundle struct {
+	Version   int                       `yaml:"version"`
+	Unit      string                    `yaml:"unit"`
+	Hook      string                    `yaml:"hook"`
+	Started   time.Time                 `yaml:"started"`
+	Duration  time.Duration             `yaml:"duration"`
+	Env       map[string]string         `yaml:"env"`
+	Config    map[string]interface{}    `yaml:"config,omitempty"`
+	Relations []Relation                `yaml:"relations,omitempty"`
+	Secrets   map[string]SecretMetadata `yaml:"secrets,omitempty"`
+	ToolCalls []ToolCall                `yaml:"tool-calls,omitempty"`
+	Error     string                    `yaml:"error,omitempty"`
+}
+
+// Relation holds the data of a relation visible to the unit when the
+// hook started.
+type Relation struct {
+	Id                  string                       `yaml:"id"`
+	Endpoint            string                       `yaml:"endpoint"`
+	RemoteApplication   string                       `yaml:"remote-application,omitempty"`
+	LocalSettings       map[string]string            `yaml:"local-settings,omitempty"`
+	ApplicationSettings map[string]string            `yaml:"application-settings,omitempty"`
+	Units               map[string]map[string]string `yaml:"units,omitempty"`
+}
+
+// SecretMetadata holds the metadata of a secret owned by the charm.
+type SecretMetadata struct {
+	Owner          string `yaml:"owner,omitempty"`
+	Label          string `yaml:"label,omitempty"`
+	Description    string `yaml:"description,omitempty"`
+	RotatePolicy   string `yaml:"rotate-policy,omitempty"`
+	LatestRevision int    `yaml:"latest-revision"`
+	Revisions      []int  `yaml:"revisions,omitempty"`
+}
+
+// ToolCall holds a call the hook made to a hook tool, and the tool's
+// response.
+type ToolCall struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args,omitempty"`
+	Stdin   string   `yaml:"stdin,omitempty"`
+	Stdout  string   `yaml:"stdout,omitempty"`
+	Stderr  string   `yaml:"stderr,omitempty"`
+	Code    int      `yaml:"code"`
+
+	// Redacted is true if the arguments, input or output of the
+	// call carried secret content, and were not recorded.
+	Redacted bool `yaml:"redacted,omitempty"`
+}
+
+// Enabled reports whether the named hook should be captured for the
+// unit whose agent directory is baseDir.
+func Enabled(baseDir, hookName string) bool {
+	data, err := os.ReadFile(filepath.Join(baseDir, FlagFile))
+	if err != nil {
+		return false
+	}
+	hooks := strings.Fields(string(data))
+	return len(hooks) == 0 || set.NewStrings(hooks...).Contains(hookName)
+}
+
+// Recorder records the execution of a hook.
+type Recorder struct {
+	mu     sync.Mutex
+	bundle Bundle
+}
+
+// NewRecorder returns a Recorder for the named hook of the unit,
+// which started at the given time.
+func NewRecorder(unitName, hookName string, started time.Time) *Recorder {
+	return &Recorder{bundle: Bundle{
+		Version: bundleVersion,
+		Unit:    unitName,
+		Hook:    hookName,
+		Started: started.UTC(),
+		Env:     make(map[string]string),
+	}}
+}
+
+// SetEnv records the environment the hook is run with.
+func (r *Recorder) SetEnv(env []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			r.bundle.Env[k] = v
+		}
+	}
+}
+
+// SetContext records the charm config, relation data and secret
+// metadata available to the hook. Relation settings that cannot be
+// read, such as those of a unit that has departed, are left out.
+func (r *Recorder) SetContext(ctx jujuc.Context) error {
+	config, err := ctx.ConfigSettings()
+	if err != nil {
+		return errors.Annotate(err, "reading charm config")
+	}
+	relations, err := readRelations(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	secrets, err := ctx.SecretMetadata()
+	if err != nil {
+		return errors.Annotate(err, "reading secret metadata")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bundle.Config = config
+	r.bundle.Relations = relations
+	r.bundle.Secrets = make(map[string]SecretMetadata, len(secrets))
+	for id, md := range secrets {
+		owner := ""
+		if md.Owner != nil {
+			owner = md.Owner.String()
+		}
+		r.bundle.Secrets[id] = SecretMetadata{
+			Owner:          owner,
+			Label:          md.Label,
+			Description:    md.Description,
+			RotatePolicy:   string(md.RotatePolicy),
+			LatestRevision: md.LatestRevision,
+			Revisions:      md.Revisions,
+		}
+	}
+	return nil
+}
+
+func readRelations(ctx jujuc.Context) ([]Relation, error) {
+	ids, err := ctx.RelationIds()
+	if err != nil {
+		return nil, errors.Annotate(err, "reading relations")
+	}
+	sort.Ints(ids)
+	relations := make([]Relation, 0, len(ids))
+	for _, id := range ids {
+		rel, err := ctx.Relation(id)
+		if errors.Is(err, errors.NotFound) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "reading relation %d", id)
+		}
+		relation := Relation{
+			Id:                rel.FakeId(),
+			Endpoint:          rel.Name(),
+			RemoteApplication: rel.RemoteApplicationName(),
+			Units:             make(map[string]map[string]string),
+		}
+		if settings, err := rel.Settings(); err == nil {
+			relation.LocalSettings = settings.Map()
+		}
+		if relation.RemoteApplication != "" {
+			if settings, err := rel.ReadApplicationSettings(relation.RemoteApplication); err == nil {
+				relation.ApplicationSettings = settings
+			}
+		}
+		for _, unit := range rel.UnitNames() {
+			settings, err := rel.ReadSettings(unit)
+			if err != nil {
+				continue
+			}
+			relation.Units[unit] = settings
+		}
+		relations = append(relations, relation)
+	}
+	return relations, nil
+}
+
+// RecordToolCall records a call to a hook tool, and its response. It
+// has the signature of a jujuc.RecordFunc.
+func (r *Recorder) RecordToolCall(req jujuc.Request, resp exec.ExecResponse) {
+	call := ToolCall{
+		Command: req.CommandName,
+		Args:    req.Args,
+		Stdin:   string(req.Stdin),
+		Stdout:  string(resp.Stdout),
+		Stderr:  string(resp.Stderr),
+		Code:    resp.Code,
+	}
+	if inputRedacted.Contains(call.Command) {
+		call.Args, call.Stdin, call.Redacted = nil, "", true
+	}
+	if outputRedacted.Contains(call.Command) {
+		call.Stdout, call.Redacted = "", true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bundle.ToolCalls = append(r.bundle.ToolCalls, call)
+}
+
+// Finish records the outcome of the hook, which finished at the
+// given time, and returns the completed bundle.
+func (r *Recorder) Finish(finished time.Time, hookErr error) Bundle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bundle.Duration = finished.Sub(r.bundle.Started)
+	if hookErr != nil {
+		r.bundle.Error = hookErr.Error()
+	}
+	return r.bundle
+}
+
+// WriteBundle writes the bundle to a new file in dir, and returns the
+// path of the file. Bundles hold relation data and charm config, so
+// are only readable by the owner. The oldest bundles in dir are
+// removed, so that no more than MaxBundles are kept.
+func WriteBundle(dir string, bundle Bundle) (string, error) {
+	data, err := goyaml.Marshal(bundle)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", errors.Trace(err)
+	}
+	name := fmt.Sprintf("%s-%s.yaml", bundle.Started.Format("20060102-150405.000000"), bundle.Hook)
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", errors.Trace(err)
+	}
+	return path, errors.Trace(pruneBundles(dir))
+}
+
+func pruneBundles(dir string) error {
+	// Bundle names start with the time the hook started,
+	// so sort in the order the hooks were run.
+	names, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sort.Strings(names)
+	for len(names) > MaxBundles {
+		if err := os.Remove(names[0]); err != nil && !os.IsNotExist(err) {
+			return errors.Trace(err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// ReadBundle reads the bundle in the named file.
+func ReadBundle(path string) (Bundle, error) {
+	var bundle Bundle
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return bundle, errors.Trace(err)
+	}
+	if err := goyaml.Unmarshal(data, &bundle); err != nil {
+		return bundle, errors.Annotatef(err, "reading hook bundle %q", path)
+	}
+	if bundle.Version != bundleVersion {
+		return bundle, errors.NotSupportedf("hook bundle version %d", bundle.Version)
+	}
+	if bundle.Hook == "" {
+		return bundle, errors.NotValidf("hook bundle %q without hook name", path)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package capture_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/v3/exec"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/capture"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/runner/jujuc/jujuctesting"
+)
+
+type CaptureSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&CaptureSuite{})
+
+var started = time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC)
+
+func (s *CaptureSuite) TestEnabled(c *gc.C) {
+	dir := c.MkDir()
+	c.Check(capture.Enabled(dir, "install"), jc.IsFalse)
+
+	err := os.WriteFile(filepath.Join(dir, capture.FlagFile), nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(capture.Enabled(dir, "install"), jc.IsTrue)
+	c.Check(capture.Enabled(dir, "config-changed"), jc.IsTrue)
+
+	err = os.WriteFile(filepath.Join(dir, capture.FlagFile), []byte("install\nstart\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(capture.Enabled(dir, "install"), jc.IsTrue)
+	c.Check(capture.Enabled(dir, "start"), jc.IsTrue)
+	c.Check(capture.Enabled(dir, "config-changed"), jc.IsFalse)
+}
+
+func (s *CaptureSuite) TestRecorder(c *gc.C) {
+	info := &jujuctesting.ContextInfo{}
+	info.Unit.ConfigSettings = charm.Settings{"port": 8080}
+	rel := info.Relations.SetNewRelation(1, "db", &testing.Stub{})
+	rel.RemoteApplicationName = "mysql"
+	rel.UnitName = "wordpress/0"
+	rel.SetRelated("wordpress/0", jujuctesting.Settings{"host": "10.0.0.1"})
+	rel.SetRelated("mysql/0", jujuctesting.Settings{"password": "pass"})
+	rel.SetRemoteApplicationSettings(jujuctesting.Settings{"database": "wp"})
+	ctx := info.Context(&testing.Stub{})
+
+	rec := capture.NewRecorder("wordpress/0", "db-relation-changed", started)
+	rec.SetEnv([]string{"JUJU_UNIT_NAME=wordpress/0", "JUJU_RELATION_ID=db:1"})
+	err := rec.SetContext(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	rec.RecordToolCall(jujuc.Request{
+		CommandName: "relation-get",
+		Args:        []string{"-", "mysql/0"},
+		Token:       "not recorded",
+	}, exec.ExecResponse{Stdout: []byte("password: pass\n")})
+	rec.RecordToolCall(jujuc.Request{
+		CommandName: "secret-get",
+		Args:        []string{"secret:9m4e2mr0ui3e8a215n4g"},
+	}, exec.ExecResponse{Stdout: []byte("key: secret\n")})
+	rec.RecordToolCall(jujuc.Request{
+		CommandName: "secret-set",
+		Args:        []string{"secret:9m4e2mr0ui3e8a215n4g", "key=secret"},
+	}, exec.ExecResponse{Code: 1, Stderr: []byte("ERROR permission denied\n")})
+	bundle := rec.Finish(started.Add(3*time.Second), fmt.Errorf("exit status 1"))
+
+	c.Assert(bundle, jc.DeepEquals, capture.Bundle{
+		Version:  1,
+		Unit:     "wordpress/0",
+		Hook:     "db-relation-changed",
+		Started:  started,
+		Duration: 3 * time.Second,
+		Env: map[string]string{
+			"JUJU_UNIT_NAME":   "wordpress/0",
+			"JUJU_RELATION_ID": "db:1",
+		},
+		Config: map[string]interface{}{"port": 8080},
+		Relations: []capture.Relation{{
+			Id:                  "db:1",
+			Endpoint:            "db",
+			RemoteApplication:   "mysql",
+			LocalSettings:       map[string]string{"host": "10.0.0.1"},
+			ApplicationSettings: map[string]string{"database": "wp"},
+			Units: map[string]map[string]string{
+				"mysql/0":     {"password": "pass"},
+				"wordpress/0": {"host": "10.0.0.1"},
+			},
+		}},
+		Secrets: map[string]capture.SecretMetadata{
+			"9m4e2mr0ui3e8a215n4g": {
+				Owner:          "application-mariadb",
+				Label:          "label",
+				Description:    "description",
+				RotatePolicy:   "hourly",
+				LatestRevision: 666,
+			},
+		},
+		ToolCalls: []capture.ToolCall{{
+			Command: "relation-get",
+			Args:    []string{"-", "mysql/0"},
+			Stdout:  "password: pass\n",
+		}, {
+			Command:  "secret-get",
+			Args:     []string{"secret:9m4e2mr0ui3e8a215n4g"},
+			Redacted: true,
+		}, {
+			Command:  "secret-set",
+			Stderr:   "ERROR permission denied\n",
+			Code:     1,
+			Redacted: true,
+		}},
+		Error: "exit status 1",
+	})
+}
+
+func (s *CaptureSuite) TestWriteReadBundle(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), capture.BundleDir)
+	bundle := capture.Bundle{
+		Version: 1,
+		Unit:    "wordpress/0",
+		Hook:    "install",
+		Started: started,
+		Env:     map[string]string{"JUJU_UNIT_NAME": "wordpress/0"},
+		ToolCalls: []capture.ToolCall{{
+			Command: "config-get",
+			Args:    []string{"port"},
+			Stdout:  "8080\n",
+		}},
+	}
+	path, err := capture.WriteBundle(dir, bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(path, gc.Equals, filepath.Join(dir, "20230601-123000.000000-install.yaml"))
+
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	read, err := capture.ReadBundle(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, bundle)
+}
+
+func (s *CaptureSuite) TestWriteBundlePrunesOldest(c *gc.C) {
+	dir := c.MkDir()
+	for i := 0; i < capture.MaxBundles+2; i++ {
+		_, err := capture.WriteBundle(dir, capture.Bundle{
+			Version: 1,
+			Hook:    "update-status",
+			Started: started.Add(time.Duration(i) * time.Minute),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	names, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, capture.MaxBundles)
+	c.Assert(filepath.Base(names[0]), gc.Equals, "20230601-123200.000000-update-status.yaml")
+}
+
+func (s *CaptureSuite) TestReadBundleUnsupportedVersion(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := os.WriteFile(path, []byte("version: 2\nhook: install\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = capture.ReadBundle(path)
+	c.Assert(err, gc.ErrorMatches, "hook bundle version 2 not supported")
+}
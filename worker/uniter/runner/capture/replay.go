@@ -0,0 +1,243 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package capture
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/kballard/go-shellquote"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// ReplayParams holds the parameters for Replay.
+type ReplayParams struct {
+	// Bundle holds the hook to replay.
+	Bundle Bundle
+
+	// CharmDir is the directory holding the charm to run the hook
+	// against.
+	CharmDir string
+
+	// WorkDir is a scratch directory in which the hook tool stubs
+	// are written.
+	WorkDir string
+
+	// Env is the environment of the replay, to which the hook
+	// environment recorded in the bundle is added.
+	Env []string
+
+	// Stdout and Stderr receive the output of the hook.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ReplayResult holds the outcome of a replayed hook.
+type ReplayResult struct {
+	// Code is the exit code of the hook.
+	Code int
+
+	// Recorded is the number of hook tool calls in the bundle.
+	Recorded int
+
+	// Replayed is the number of recorded hook tool calls that
+	// the hook made when replayed.
+	Replayed int
+
+	// Unrecorded is the number of hook tool calls that the hook
+	// made when replayed, beyond those recorded.
+	Unrecorded int
+}
+
+// hostEnvVars holds the variables recorded in a bundle that describe
+// the machine the hook ran on, rather than the hook, and are not
+// replayed.
+var hostEnvVars = set.NewStrings(
+	"PATH",
+	"TERM",
+	"CHARM_DIR",
+	"JUJU_CHARM_DIR",
+	"JUJU_AGENT_SOCKET_ADDRESS",
+	"JUJU_AGENT_SOCKET_NETWORK",
+	"JUJU_AGENT_CA_CERT",
+)
+
+// Replay runs the hook recorded in a bundle against the charm in a
+// local directory. The hook tools are replaced with stubs which, when
+// called, respond as the real tools did when the hook was captured;
+// the nth call to a tool gets the nth response recorded for it. If
+// the arguments of a call differ from those recorded, the stub writes
+// a warning to stderr, and responds anyway.
+func Replay(p ReplayParams) (ReplayResult, error) {
+	result := ReplayResult{Recorded: len(p.Bundle.ToolCalls)}
+	hookScript, err := findHookScript(p.CharmDir, p.Bundle.Hook)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	binDir := filepath.Join(p.WorkDir, "bin")
+	callsDir := filepath.Join(p.WorkDir, "calls")
+	if err := writeStubs(binDir, callsDir, p.Bundle.ToolCalls); err != nil {
+		return result, errors.Annotate(err, "writing hook tool stubs")
+	}
+
+	cmd := exec.Command(hookScript)
+	cmd.Dir = p.CharmDir
+	cmd.Env = replayEnv(p.Env, p.Bundle.Env, p.CharmDir, binDir)
+	cmd.Stdout = p.Stdout
+	cmd.Stderr = p.Stderr
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.Code = exitErr.ExitCode()
+	} else if err != nil {
+		return result, errors.Annotatef(err, "running hook %q", p.Bundle.Hook)
+	}
+
+	recorded := make(map[string]int)
+	for _, call := range p.Bundle.ToolCalls {
+		recorded[call.Command]++
+	}
+	entries, err := os.ReadDir(callsDir)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(callsDir, entry.Name(), "count"))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return result, errors.Trace(err)
+		}
+		count, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+		if n := recorded[entry.Name()]; count > n {
+			result.Replayed += n
+			result.Unrecorded += count - n
+		} else {
+			result.Replayed += count
+		}
+	}
+	return result, nil
+}
+
+// findHookScript returns the script that handles the named hook in
+// the charm, preferring the charm's dispatch script.
+func findHookScript(charmDir, hookName string) (string, error) {
+	if _, err := os.Stat(filepath.Join(charmDir, "metadata.yaml")); err != nil {
+		return "", errors.Errorf("%q does not hold a charm", charmDir)
+	}
+	for _, name := range []string{"dispatch", filepath.Join("hooks", hookName)} {
+		path := filepath.Join(charmDir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return path, nil
+		}
+	}
+	return "", errors.NotFoundf("handler for hook %q in charm %q", hookName, charmDir)
+}
+
+// replayEnv returns the environment to replay a hook with: the given
+// base environment, with the hook environment recorded in the bundle
+// added, and the hook tool stubs first on the path.
+func replayEnv(base []string, recorded map[string]string, charmDir, binDir string) []string {
+	env := make(map[string]string)
+	for _, kv := range base {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	for k, v := range recorded {
+		if !hostEnvVars.Contains(k) {
+			env[k] = v
+		}
+	}
+	env["CHARM_DIR"] = charmDir
+	env["JUJU_CHARM_DIR"] = charmDir
+	if path := env["PATH"]; path != "" {
+		env["PATH"] = binDir + string(os.PathListSeparator) + path
+	} else {
+		env["PATH"] = binDir
+	}
+
+	result := make([]string, 0, len(env))
+	for k, v := range env {
+		result = append(result, k+"="+v)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// stubScript responds to a call to a hook tool with the next response
+// recorded for it.
+const stubScript = `#!/bin/sh
+# Hook tool stub written by "juju replay-hook".
+dir=%s
+n=$(cat "$dir/count" 2>/dev/null || echo 0)
+echo $((n + 1)) > "$dir/count"
+call="$dir/$n"
+if [ ! -d "$call" ]; then
+	echo "replay: no recorded response for call $((n + 1)) to %s" >&2
+	exit 1
+fi
+if [ -f "$call/args" ] && [ "$*" != "$(cat "$call/args")" ]; then
+	echo "replay: warning: %s called with \"$*\", recorded with \"$(cat "$call/args")\"" >&2
+fi
+cat "$call/stdout"
+cat "$call/stderr" >&2
+exit "$(cat "$call/code")"
+`
+
+// writeStubs writes a stub for every hook tool to binDir, and the
+// recorded responses of each tool to callsDir.
+func writeStubs(binDir, callsDir string, calls []ToolCall) error {
+	tools := set.NewStrings(jujuc.CommandNames()...)
+	counts := make(map[string]int)
+	for _, call := range calls {
+		if call.Command == "" || call.Command != filepath.Base(call.Command) || strings.HasPrefix(call.Command, ".") {
+			return errors.NotValidf("hook tool name %q", call.Command)
+		}
+		tools.Add(call.Command)
+		n := counts[call.Command]
+		counts[call.Command]++
+
+		dir := filepath.Join(callsDir, call.Command, strconv.Itoa(n))
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return errors.Trace(err)
+		}
+		files := map[string]string{
+			"stdout": call.Stdout,
+			"stderr": call.Stderr,
+			"code":   strconv.Itoa(call.Code),
+		}
+		if !call.Redacted {
+			files["args"] = strings.Join(call.Args, " ")
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(binDir, 0700); err != nil {
+		return errors.Trace(err)
+	}
+	for _, tool := range tools.SortedValues() {
+		dir := filepath.Join(callsDir, tool)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return errors.Trace(err)
+		}
+		script := fmt.Sprintf(stubScript, shellquote.Join(dir), tool, tool)
+		if err := os.WriteFile(filepath.Join(binDir, tool), []byte(script), 0700); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2023 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package capture_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/capture"
+)
+
+type ReplaySuite struct {
+	testing.IsolationSuite
+
+	charmDir string
+}
+
+var _ = gc.Suite(&ReplaySuite{})
+
+func (s *ReplaySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.charmDir = c.MkDir()
+	err := os.WriteFile(filepath.Join(s.charmDir, "metadata.yaml"), []byte("name: wordpress\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ReplaySuite) writeHook(c *gc.C, name, script string) {
+	err := os.MkdirAll(filepath.Join(s.charmDir, filepath.Dir(name)), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(filepath.Join(s.charmDir, name), []byte("#!/bin/sh\n"+script), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ReplaySuite) replay(c *gc.C, bundle capture.Bundle) (capture.ReplayResult, string, string) {
+	var stdout, stderr bytes.Buffer
+	result, err := capture.Replay(capture.ReplayParams{
+		Bundle:   bundle,
+		CharmDir: s.charmDir,
+		WorkDir:  c.MkDir(),
+		Env:      []string{"PATH=/usr/bin:/bin", "JUJU_UNIT_NAME=local"},
+		Stdout:   &stdout,
+		Stderr:   &stderr,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return result, stdout.String(), stderr.String()
+}
+
+func (s *ReplaySuite) TestReplay(c *gc.C) {
+	s.writeHook(c, "hooks/config-changed", `
+echo "unit $JUJU_UNIT_NAME in $JUJU_CHARM_DIR"
+echo "port $(config-get port)"
+config-get port >/dev/null
+status-set active "ready"
+`)
+	result, stdout, stderr := s.replay(c, capture.Bundle{
+		Hook: "config-changed",
+		Env: map[string]string{
+			"JUJU_UNIT_NAME": "wordpress/0",
+			"JUJU_CHARM_DIR": "/var/lib/juju/agents/unit-wordpress-0/charm",
+		},
+		ToolCalls: []capture.ToolCall{{
+			Command: "config-get",
+			Args:    []string{"port"},
+			Stdout:  "8080\n",
+		}, {
+			Command: "config-get",
+			Args:    []string{"port"},
+			Stdout:  "8080\n",
+		}, {
+			Command: "status-set",
+			Args:    []string{"active", "ready"},
+		}},
+	})
+	c.Assert(result, jc.DeepEquals, capture.ReplayResult{Recorded: 3, Replayed: 3})
+	c.Assert(stdout, gc.Equals, "unit wordpress/0 in "+s.charmDir+"\nport 8080\n")
+	c.Assert(stderr, gc.Equals, "")
+}
+
+func (s *ReplaySuite) TestReplayPrefersDispatch(c *gc.C) {
+	s.writeHook(c, "hooks/install", "echo hook\n")
+	s.writeHook(c, "dispatch", "echo dispatch $JUJU_DISPATCH_PATH\n")
+	_, stdout, _ := s.replay(c, capture.Bundle{
+		Hook: "install",
+		Env:  map[string]string{"JUJU_DISPATCH_PATH": "hooks/install"},
+	})
+	c.Assert(stdout, gc.Equals, "dispatch hooks/install\n")
+}
+
+func (s *ReplaySuite) TestReplayFailedToolCall(c *gc.C) {
+	s.writeHook(c, "hooks/start", "leader-set foo=bar || exit 3\n")
+	result, _, stderr := s.replay(c, capture.Bundle{
+		Hook: "start",
+		ToolCalls: []capture.ToolCall{{
+			Command: "leader-set",
+			Args:    []string{"foo=bar"},
+			Stderr:  "ERROR cannot write leadership settings\n",
+			Code:    1,
+		}},
+	})
+	c.Assert(result, jc.DeepEquals, capture.ReplayResult{Code: 3, Recorded: 1, Replayed: 1})
+	c.Assert(stderr, gc.Equals, "ERROR cannot write leadership settings\n")
+}
+
+func (s *ReplaySuite) TestReplayDivergence(c *gc.C) {
+	s.writeHook(c, "hooks/start", `
+config-get colour
+is-leader
+`)
+	result, _, stderr := s.replay(c, capture.Bundle{
+		Hook: "start",
+		ToolCalls: []capture.ToolCall{{
+			Command: "config-get",
+			Args:    []string{"port"},
+			Stdout:  "8080\n",
+		}},
+	})
+	c.Assert(result, jc.DeepEquals, capture.ReplayResult{Code: 1, Recorded: 1, Replayed: 1, Unrecorded: 1})
+	c.Assert(stderr, gc.Equals, ""+
+		"replay: warning: config-get called with \"colour\", recorded with \"port\"\n"+
+		"replay: no recorded response for call 1 to is-leader\n")
+}
+
+func (s *ReplaySuite) TestReplayMissingHook(c *gc.C) {
+	_, err := capture.Replay(capture.ReplayParams{
+		Bundle:   capture.Bundle{Hook: "install"},
+		CharmDir: s.charmDir,
+		WorkDir:  c.MkDir(),
+	})
+	c.Assert(err, gc.ErrorMatches, `handler for hook "install" in charm ".*" not found`)
+}
+
+func (s *ReplaySuite) TestReplayNotCharm(c *gc.C) {
+	_, err := capture.Replay(capture.ReplayParams{
+		Bundle:   capture.Bundle{Hook: "install"},
+		CharmDir: c.MkDir(),
+		WorkDir:  c.MkDir(),
+	})
+	c.Assert(err, gc.ErrorMatches, `".*" does not hold a charm`)
+}
@@ -171,11 +171,16 @@ type Request struct {
 // CmdGetter looks up a Command implementation connected to a particular Context.
 type CmdGetter func(contextId, cmdName string) (cmd.Command, error)
 
+// RecordFunc is called with each command request served, and the
+// response returned for it.
+type RecordFunc func(req Request, resp exec.ExecResponse)
+
 // Jujuc implements the jujuc command in the form required by net/rpc.
 type Jujuc struct {
 	mu     sync.Mutex
 	getCmd CmdGetter
 	token  string
+	record RecordFunc
 }
 
 // badReqErrorf returns an error indicating a bad Request.
@@ -228,6 +233,9 @@ func (j *Jujuc) Main(req Request, resp *exec.ExecResponse) error {
 	}
 	resp.Stdout = stdout.Bytes()
 	resp.Stderr = stderr.Bytes()
+	if j.record != nil {
+		j.record(req, *resp)
+	}
 	return nil
 }
 
@@ -246,8 +254,14 @@ type Server struct {
 // remote command invocations against an appropriate Context. It will not
 // actually do so until Run is called.
 func NewServer(getCmd CmdGetter, socket sockets.Socket, token string) (*Server, error) {
+	return NewRecordingServer(getCmd, socket, token, nil)
+}
+
+// NewRecordingServer creates a server as NewServer does, which passes
+// each command request it serves, and the response to it, to record.
+func NewRecordingServer(getCmd CmdGetter, socket sockets.Socket, token string, record RecordFunc) (*Server, error) {
 	server := rpc.NewServer()
-	if err := server.Register(&Jujuc{getCmd: getCmd, token: token}); err != nil {
+	if err := server.Register(&Jujuc{getCmd: getCmd, token: token, record: record}); err != nil {
 		return nil, err
 	}
 	listener, err := sockets.Listen(socket)
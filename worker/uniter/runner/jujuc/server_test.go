@@ -139,6 +139,38 @@ func (s *ServerSuite) TestHappyPath(c *gc.C) {
 	c.Assert(string(content), gc.Equals, "something")
 }
 
+func (s *ServerSuite) TestRecording(c *gc.C) {
+	var (
+		requests  []jujuc.Request
+		responses []exec.ExecResponse
+	)
+	record := func(req jujuc.Request, resp exec.ExecResponse) {
+		requests = append(requests, req)
+		responses = append(responses, resp)
+	}
+	socket := s.osDependentSockPath(c)
+	srv, err := jujuc.NewRecordingServer(factory, socket, "", record)
+	c.Assert(err, jc.ErrorIsNil)
+	go func() { _ = srv.Run() }()
+
+	client, err := sockets.Dial(socket)
+	c.Assert(err, jc.ErrorIsNil)
+	req := jujuc.Request{
+		ContextId:   "validCtx",
+		Dir:         c.MkDir(),
+		CommandName: "remote",
+		Args:        []string{"--value", "something"},
+	}
+	var resp exec.ExecResponse
+	err = client.Call("Jujuc.Main", req, &resp)
+	c.Assert(err, jc.ErrorIsNil)
+	_ = client.Close()
+	srv.Close()
+
+	c.Assert(requests, jc.DeepEquals, []jujuc.Request{req})
+	c.Assert(responses, jc.DeepEquals, []exec.ExecResponse{resp})
+}
+
 func (s *ServerSuite) TestNoStdin(c *gc.C) {
 	dir := c.MkDir()
 	_, err := s.Call(c, jujuc.Request{
@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/core/actions"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/worker/common/charmrunner"
+	"github.com/juju/juju/worker/uniter/runner/capture"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/debug"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -214,7 +215,7 @@ func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Durat
 			return nil, errors.Trace(err)
 		}
 	}
-	srv, err := runner.startJujucServer(token, rMode)
+	srv, err := runner.startJujucServer(token, rMode, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -376,15 +377,42 @@ func (runner *runner) RunAction(actionName string) (HookHandlerType, error) {
 		return InvalidHookHandler, errors.Trace(err)
 	}
 	runner.logger().Debugf("running action %q on %v", actionName, rMode)
-	return runner.runCharmHookWithLocation(actionName, "actions", rMode)
+	return runner.runCharmHookWithLocation(actionName, "actions", rMode, nil)
 }
 
 // RunHook exists to satisfy the Runner interface.
 func (runner *runner) RunHook(hookName string) (HookHandlerType, error) {
-	return runner.runCharmHookWithLocation(hookName, "hooks", runOnLocal)
+	if capture.Enabled(runner.paths.GetBaseDir(), hookName) {
+		return runner.runCapturedHook(hookName)
+	}
+	return runner.runCharmHookWithLocation(hookName, "hooks", runOnLocal, nil)
+}
+
+// runCapturedHook runs the named hook, and writes a bundle recording
+// its execution, which "juju replay-hook" can replay against a local
+// copy of the charm. Failure to capture the hook is logged, and does
+// not affect the outcome of the hook.
+func (runner *runner) runCapturedHook(hookName string) (HookHandlerType, error) {
+	logger := runner.logger()
+	recorder := capture.NewRecorder(runner.context.UnitName(), hookName, time.Now())
+	if err := recorder.SetContext(runner.context); err != nil {
+		logger.Warningf("cannot capture context of hook %q: %v", hookName, err)
+	}
+	hookHandlerType, err := runner.runCharmHookWithLocation(hookName, "hooks", runOnLocal, recorder)
+
+	bundle := recorder.Finish(time.Now(), err)
+	dir := filepath.Join(runner.paths.GetBaseDir(), capture.BundleDir)
+	if path, writeErr := capture.WriteBundle(dir, bundle); writeErr != nil {
+		logger.Warningf("cannot write capture of hook %q: %v", hookName, writeErr)
+	} else {
+		logger.Infof("captured hook %q in %s", hookName, path)
+	}
+	return hookHandlerType, err
 }
 
-func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, rMode runMode) (hookHandlerType HookHandlerType, err error) {
+func (runner *runner) runCharmHookWithLocation(
+	hookName, charmLocation string, rMode runMode, recorder *capture.Recorder,
+) (hookHandlerType HookHandlerType, err error) {
 	token := ""
 	if rMode == runOnRemote {
 		token, err = utils.RandomPassword()
@@ -392,7 +420,7 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, r
 			return InvalidHookHandler, errors.Trace(err)
 		}
 	}
-	srv, err := runner.startJujucServer(token, rMode)
+	srv, err := runner.startJujucServer(token, rMode, recorder)
 	if err != nil {
 		return InvalidHookHandler, errors.Trace(err)
 	}
@@ -435,6 +463,9 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, r
 		env = append(env, "JUJU_AGENT_TOKEN="+token)
 	}
 	env = append(env, "JUJU_DISPATCH_PATH="+charmLocation+"/"+hookName)
+	if recorder != nil {
+		recorder.SetEnv(env)
+	}
 
 	defer func() {
 		err = runner.context.Flush(hookName, err)
@@ -706,7 +737,9 @@ func (runner *runner) discoverHookHandler(hookName, charmDir, charmLocation stri
 	return InvalidHookHandler, hook, err
 }
 
-func (runner *runner) startJujucServer(token string, rMode runMode) (*jujuc.Server, error) {
+// startJujucServer starts the server for the hook tools. If recorder
+// is not nil, the hook tool calls served are recorded with it.
+func (runner *runner) startJujucServer(token string, rMode runMode, recorder *capture.Recorder) (*jujuc.Server, error) {
 	// Prepare server.
 	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
 		if ctxId != runner.context.Id() {
@@ -717,7 +750,11 @@ func (runner *runner) startJujucServer(token string, rMode runMode) (*jujuc.Serv
 
 	socket := runner.paths.GetJujucServerSocket(rMode == runOnRemote)
 	runner.logger().Debugf("starting jujuc server %s %v", token, socket)
-	srv, err := jujuc.NewServer(getCmd, socket, token)
+	var record jujuc.RecordFunc
+	if recorder != nil {
+		record = recorder.RecordToolCall
+	}
+	srv, err := jujuc.NewRecordingServer(getCmd, socket, token, record)
 	if err != nil {
 		return nil, errors.Annotate(err, "starting jujuc server")
 	}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/juju/charm/v12"
 	"github.com/juju/charm/v12/hooks"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -23,7 +24,9 @@ import (
 	"github.com/juju/juju/worker/common/charmrunner"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/capture"
 	"github.com/juju/juju/worker/uniter/runner/context"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
 	runnertesting "github.com/juju/juju/worker/uniter/runner/testing"
 )
 
@@ -240,6 +243,18 @@ func (ctx *MockContext) UnitName() string {
 	return "some-unit/999"
 }
 
+func (ctx *MockContext) ConfigSettings() (charm.Settings, error) {
+	return charm.Settings{"colour": "blue"}, nil
+}
+
+func (ctx *MockContext) RelationIds() ([]int, error) {
+	return nil, nil
+}
+
+func (ctx *MockContext) SecretMetadata() (map[string]jujuc.SecretMetadata, error) {
+	return nil, nil
+}
+
 func (ctx *MockContext) HookVars(
 	paths context.Paths,
 	_ bool,
@@ -344,6 +359,49 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookCaptured(c *gc.C) {
+	err := os.WriteFile(filepath.Join(s.paths.GetBaseDir(), capture.FlagFile), []byte("something-happened\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := &MockContext{
+		flushResult: errors.New("pew pew pew"),
+	}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	_, err = runner.NewRunner(ctx, s.paths, nil).RunHook("something-happened")
+	c.Assert(err, gc.ErrorMatches, "pew pew pew")
+
+	paths, err := filepath.Glob(filepath.Join(s.paths.GetBaseDir(), capture.BundleDir, "*.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paths, gc.HasLen, 1)
+	bundle, err := capture.ReadBundle(paths[0])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bundle.Unit, gc.Equals, "some-unit/999")
+	c.Check(bundle.Hook, gc.Equals, "something-happened")
+	c.Check(bundle.Env["VAR"], gc.Equals, "value")
+	c.Check(bundle.Env["JUJU_DISPATCH_PATH"], gc.Equals, "hooks/something-happened")
+	c.Check(bundle.Config, jc.DeepEquals, map[string]interface{}{"colour": "blue"})
+	c.Check(bundle.Error, gc.Equals, "pew pew pew")
+}
+
+func (s *RunMockContextSuite) TestRunHookNotCaptured(c *gc.C) {
+	err := os.WriteFile(filepath.Join(s.paths.GetBaseDir(), capture.FlagFile), []byte("install\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := &MockContext{}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	_, err = runner.NewRunner(ctx, s.paths, nil).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = os.Stat(filepath.Join(s.paths.GetBaseDir(), capture.BundleDir))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
 func (s *RunHookSuite) TestRunActionDispatchingHookHandler(c *gc.C) {
 	ctx := &MockContext{
 		actionData:    &context.ActionData{},
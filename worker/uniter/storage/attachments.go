@@ -32,6 +32,11 @@ type StorageAccessor interface {
 	// with the specified unit and storage tags. This method is only
 	// expected to succeed if the storage attachment is Dying.
 	RemoveStorageAttachment(names.StorageTag, names.UnitTag) error
+
+	// ClearStorageAttachmentResized clears the resized flag of the
+	// storage attachment with the specified unit and storage tags,
+	// once the storage-resized hook has been run.
+	ClearStorageAttachmentResized(names.StorageTag, names.UnitTag) error
 }
 
 // Attachments generates storage hooks in response to changes to
@@ -45,6 +50,11 @@ type Attachments struct {
 	// for which no hooks have been run.
 	pending names.Set
 
+	// resized is the set of tags for storage attachments whose
+	// storage-resized hook has been committed, but for which we
+	// have not yet observed the resized flag being cleared.
+	resized names.Set
+
 	stateOps *stateOps
 
 	// TODO: hml
@@ -65,6 +75,7 @@ func NewAttachments(
 		abort:    abort,
 		stateOps: NewStateOps(rw),
 		pending:  names.NewSet(),
+		resized:  names.NewSet(),
 	}
 	if err := a.init(); err != nil {
 		return nil, err
@@ -141,9 +152,12 @@ func (a *Attachments) ValidateHook(hi hook.Info) error {
 // CommitHook persists the State change encoded in the supplied storage
 // hook, or returns an error if the hook is invalid given current State.
 func (a *Attachments) CommitHook(hi hook.Info) error {
-	if !hi.Kind.IsStorage() {
+	if !hook.IsStorage(hi.Kind) {
 		return errors.Errorf("not a storage hook: %#v", hi)
 	}
+	if hi.Kind == hook.StorageResized {
+		return a.commitStorageResized(names.NewStorageTag(hi.StorageId))
+	}
 	if hi.Kind == hooks.StorageDetaching {
 		err := a.storageState.Detach(hi.StorageId)
 		if err != nil {
@@ -168,6 +182,17 @@ func (a *Attachments) CommitHook(hi hook.Info) error {
 	return nil
 }
 
+// commitStorageResized records that the storage-resized hook has been
+// run for the specified storage, so that it is not run again.
+func (a *Attachments) commitStorageResized(tag names.StorageTag) error {
+	err := a.st.ClearStorageAttachmentResized(tag, a.unitTag)
+	if err != nil && !params.IsCodeNotFound(err) && !errors.IsNotSupported(err) {
+		return errors.Annotate(err, "clearing storage attachment resized flag")
+	}
+	a.resized.Add(tag)
+	return nil
+}
+
 func (a *Attachments) removeStorageAttachment(tag names.StorageTag) error {
 	if err := a.st.RemoveStorageAttachment(tag, a.unitTag); err != nil {
 		return errors.Annotate(err, "removing storage attachment")
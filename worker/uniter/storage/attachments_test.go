@@ -347,6 +347,79 @@ func (s *attachmentsSuite) TestAttachmentsCommitHook(c *gc.C) {
 	c.Assert(removed, jc.IsTrue)
 }
 
+func (s *attachmentsSuite) TestAttachmentsStorageResized(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	unitTag := names.NewUnitTag("mysql/0")
+	abort := make(chan struct{})
+
+	var cleared int
+	storageTag := names.NewStorageTag("data/0")
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			return nil, nil
+		},
+		clearResized: func(s names.StorageTag, u names.UnitTag) error {
+			cleared++
+			c.Assert(s, gc.Equals, storageTag)
+			c.Assert(u, gc.Equals, unitTag)
+			return nil
+		},
+	}
+
+	att, err := storage.NewAttachments(st, unitTag, s.mockStateOps, abort)
+	c.Assert(err, jc.ErrorIsNil)
+	r := storage.NewResolver(loggo.GetLogger("test"), att, s.modelType)
+
+	s.storSt.Attach(storageTag.Id())
+	s.expectSetState(c, "")
+	err = att.CommitHook(hook.Info{
+		Kind:      hooks.StorageAttached,
+		StorageId: storageTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	nextOp := func(resized bool) (operation.Operation, error) {
+		localState := resolver.LocalState{State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+		}}
+		return r.NextOp(localState, remotestate.Snapshot{
+			Life: life.Alive,
+			Storage: map[names.StorageTag]remotestate.StorageSnapshot{
+				storageTag: {
+					Kind:     params.StorageKindFilesystem,
+					Life:     life.Alive,
+					Location: "/srv/data",
+					Attached: true,
+					Resized:  resized,
+				},
+			},
+		}, &mockOperations{})
+	}
+
+	op, err := nextOp(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-resized")
+
+	err = att.ValidateHook(hook.Info{Kind: hook.StorageResized, StorageId: storageTag.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	err = att.CommitHook(hook.Info{Kind: hook.StorageResized, StorageId: storageTag.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cleared, gc.Equals, 1)
+
+	// The hook is not run again until the resized flag
+	// has been seen to be cleared, and set again.
+	_, err = nextOp(true)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	_, err = nextOp(false)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	op, err = nextOp(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-resized")
+}
+
 func (s *attachmentsSuite) TestAttachmentsSetDying(c *gc.C) {
 	defer s.setupMocks(c).Finish()
 
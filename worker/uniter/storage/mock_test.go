@@ -19,6 +19,7 @@ type mockStorageAccessor struct {
 	unitStorageAttachments        func(names.UnitTag) ([]params.StorageAttachmentId, error)
 	destroyUnitStorageAttachments func(names.UnitTag) error
 	remove                        func(names.StorageTag, names.UnitTag) error
+	clearResized                  func(names.StorageTag, names.UnitTag) error
 }
 
 func (m *mockStorageAccessor) StorageAttachment(s names.StorageTag, u names.UnitTag) (params.StorageAttachment, error) {
@@ -37,6 +38,10 @@ func (m *mockStorageAccessor) RemoveStorageAttachment(s names.StorageTag, u name
 	return m.remove(s, u)
 }
 
+func (m *mockStorageAccessor) ClearStorageAttachmentResized(s names.StorageTag, u names.UnitTag) error {
+	return m.clearResized(s, u)
+}
+
 type mockOperations struct {
 	operation.Factory
 }
@@ -184,8 +184,18 @@ func (s *storageResolver) nextHookOp(
 		attached, ok := s.storage.storageState.Attached(tag.Id())
 		if ok && attached {
 			// Once the storage is attached, we only care about
-			// lifecycle State changes.
-			return nil, resolver.ErrNoOperation
+			// lifecycle State changes, and resizes.
+			if !snap.Resized {
+				s.storage.resized.Remove(tag)
+				return nil, resolver.ErrNoOperation
+			}
+			if s.storage.resized.Contains(tag) {
+				// The storage-resized hook has already been
+				// run; wait for the flag to be cleared.
+				return nil, resolver.ErrNoOperation
+			}
+			hookInfo.Kind = hook.StorageResized
+			break
 		}
 		// The storage-attached hook has not been committed, so add the
 		// storage to the pending set.
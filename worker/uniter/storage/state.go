@@ -58,7 +58,7 @@ func (s *State) ValidateHook(hi hook.Info) (err error) {
 		if attached {
 			return errors.New("storage already attached")
 		}
-	case hooks.StorageDetaching:
+	case hooks.StorageDetaching, hook.StorageResized:
 		if !attached {
 			return errors.New("storage not attached")
 		}
@@ -85,6 +85,12 @@ func (s *stateSuite) TestValidateHookStorageDetachingError(c *gc.C) {
 
 }
 
+func (s *stateSuite) TestValidateHookStorageResizedError(c *gc.C) {
+	hi := hook.Info{Kind: hook.StorageResized, StorageId: s.tag1.Id()}
+	err := s.st.ValidateHook(hi)
+	c.Assert(err, gc.ErrorMatches, `inappropriate "storage-resized" hook for storage "test/1": storage not attached`)
+}
+
 func (s *stateSuite) TestValidateHookStorageAttached(c *gc.C) {
 	hi := hook.Info{Kind: hooks.StorageAttached, StorageId: s.tag1.Id()}
 	err := s.st.ValidateHook(hi)